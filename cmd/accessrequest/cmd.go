@@ -0,0 +1,22 @@
+package accessrequest
+
+import "github.com/spf13/cobra"
+
+// NewCmdAccessRequest implements the access-request command to manage OCM access requests
+func NewCmdAccessRequest() *cobra.Command {
+	accessRequest := &cobra.Command{
+		Use:   "access-request",
+		Short: "Create and track access requests for clusters requiring customer approval",
+		Long: `Create and track access requests for clusters requiring customer approval
+
+  Clusters with access protection enabled require the customer to approve an access
+  request before SREs can access them. These commands wrap the OCM access transparency API.`,
+		Args: cobra.NoArgs,
+	}
+
+	accessRequest.AddCommand(newCmdCreate())
+	accessRequest.AddCommand(newCmdStatus())
+	accessRequest.AddCommand(newCmdExpire())
+
+	return accessRequest
+}
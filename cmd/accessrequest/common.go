@@ -0,0 +1,115 @@
+package accessrequest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	atv1 "github.com/openshift-online/ocm-sdk-go/accesstransparency/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultWaitTimeout  = 2 * time.Hour
+)
+
+// listAccessRequests returns the access requests of the cluster, the most recent one first
+func listAccessRequests(conn *sdk.Connection, clusterID string) ([]*atv1.AccessRequest, error) {
+	response, err := conn.AccessTransparency().V1().AccessRequests().List().
+		Search(fmt.Sprintf("cluster_id = '%s'", clusterID)).
+		Order("created_at desc").
+		Size(100).
+		Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access requests for cluster %s: %w", clusterID, err)
+	}
+	return response.Items().Slice(), nil
+}
+
+// getAccessRequest returns the access request with the given ID
+func getAccessRequest(conn *sdk.Connection, id string) (*atv1.AccessRequest, error) {
+	response, err := conn.AccessTransparency().V1().AccessRequests().AccessRequest(id).Get().Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access request %s: %w", id, err)
+	}
+	return response.Body(), nil
+}
+
+// findOpenAccessRequest returns the most recent pending or approved access request, or nil if there is none
+func findOpenAccessRequest(requests []*atv1.AccessRequest) *atv1.AccessRequest {
+	for _, request := range requests {
+		switch request.Status().State() {
+		case atv1.AccessRequestStatePending, atv1.AccessRequestStateApproved:
+			return request
+		}
+	}
+	return nil
+}
+
+// waitForDecision polls the access request until it is not pending anymore and returns it
+func waitForDecision(ctx context.Context, conn *sdk.Connection, id string, interval, timeout time.Duration) (*atv1.AccessRequest, error) {
+	var request *atv1.AccessRequest
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		request, err = getAccessRequest(conn, id)
+		if err != nil {
+			return false, err
+		}
+		if request.Status().State() == atv1.AccessRequestStatePending {
+			log.Printf("access request %s is still pending, waiting for the customer's decision", id)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for a decision on access request %s: %w", id, err)
+	}
+	return request, nil
+}
+
+// printAccessRequests prints a table of the access requests
+func printAccessRequests(w io.Writer, requests []*atv1.AccessRequest) error {
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"ID", "STATE", "REQUESTED BY", "CREATED AT", "EXPIRES AT", "JUSTIFICATION"})
+	for _, request := range requests {
+		table.AddRow([]string{
+			request.ID(),
+			string(request.Status().State()),
+			request.RequestedBy(),
+			formatTime(request.CreatedAt()),
+			formatTime(request.Status().ExpiresAt()),
+			request.Justification(),
+		})
+	}
+	return table.Flush()
+}
+
+// printAccessRequest prints the details of a single access request including its decisions
+func printAccessRequest(w io.Writer, request *atv1.AccessRequest) error {
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"ID:", request.ID()})
+	table.AddRow([]string{"Cluster ID:", request.ClusterId()})
+	table.AddRow([]string{"State:", string(request.Status().State())})
+	table.AddRow([]string{"Requested by:", request.RequestedBy()})
+	table.AddRow([]string{"Justification:", request.Justification()})
+	table.AddRow([]string{"Duration:", request.Duration()})
+	table.AddRow([]string{"Created at:", formatTime(request.CreatedAt())})
+	table.AddRow([]string{"Deadline at:", formatTime(request.DeadlineAt())})
+	table.AddRow([]string{"Expires at:", formatTime(request.Status().ExpiresAt())})
+	for _, decision := range request.Decisions() {
+		table.AddRow([]string{"Decision:", fmt.Sprintf("%s by %s at %s (%s)", decision.Decision(), decision.DecidedBy(), formatTime(decision.CreatedAt()), decision.Justification())})
+	}
+	return table.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
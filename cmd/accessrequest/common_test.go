@@ -0,0 +1,147 @@
+package accessrequest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	atv1 "github.com/openshift-online/ocm-sdk-go/accesstransparency/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tokenPath        = "/fake-path/token" // #nosec G101
+	accessRequestAPI = "/api/access_transparency/v1/access_requests"
+)
+
+// newTestConnection returns an OCM connection to a fake server serving the handler
+func newTestConnection(t *testing.T, handler http.HandlerFunc) *sdk.Connection {
+	token, err := jwt.New(jwt.SigningMethodHS256).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == tokenPath {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": token, "token_type": "Bearer", "expires_in": 3600})
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	conn, err := sdk.NewConnectionBuilder().
+		URL(server.URL).
+		TokenURL(server.URL+tokenPath).
+		Insecure(true).
+		Client("fake-id", "fake-secret").
+		Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func accessRequestJSON(id, state string) map[string]interface{} {
+	return map[string]interface{}{
+		"kind":          "AccessRequest",
+		"id":            id,
+		"cluster_id":    "cluster-123",
+		"justification": "Investigating OHSS-1234",
+		"status":        map[string]interface{}{"state": state},
+	}
+}
+
+func TestListAndFindOpenAccessRequest(t *testing.T) {
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, accessRequestAPI, r.URL.Path)
+		assert.Equal(t, "cluster_id = 'cluster-123'", r.URL.Query().Get("search"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"kind": "AccessRequestList",
+			"page": 1, "size": 3, "total": 3,
+			"items": []interface{}{
+				accessRequestJSON("ar-3", "Denied"),
+				accessRequestJSON("ar-2", "Approved"),
+				accessRequestJSON("ar-1", "Expired"),
+			},
+		})
+	})
+
+	requests, err := listAccessRequests(conn, "cluster-123")
+	require.NoError(t, err)
+	require.Len(t, requests, 3)
+
+	open := findOpenAccessRequest(requests)
+	require.NotNil(t, open)
+	assert.Equal(t, "ar-2", open.ID())
+
+	selected, err := selectAccessRequest(requests, "ar-1")
+	require.NoError(t, err)
+	assert.Equal(t, atv1.AccessRequestStateExpired, selected.Status().State())
+
+	_, err = selectAccessRequest(requests, "ar-404")
+	assert.Error(t, err)
+	_, err = selectAccessRequest(requests[:1], "")
+	assert.Error(t, err)
+}
+
+func TestWaitForDecision(t *testing.T) {
+	var calls int32
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, accessRequestAPI+"/ar-1", r.URL.Path)
+		state := "Pending"
+		if atomic.AddInt32(&calls, 1) > 2 {
+			state = "Approved"
+		}
+		_ = json.NewEncoder(w).Encode(accessRequestJSON("ar-1", state))
+	})
+
+	request, err := waitForDecision(context.Background(), conn, "ar-1", 10*time.Millisecond, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, atv1.AccessRequestStateApproved, request.Status().State())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestExpireAccessRequest(t *testing.T) {
+	var decision map[string]interface{}
+	conn := newTestConnection(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, accessRequestAPI+"/ar-1/decisions", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &decision))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	})
+
+	approved, err := atv1.NewAccessRequest().ID("ar-1").Status(atv1.NewAccessRequestStatus().State(atv1.AccessRequestStateApproved)).Build()
+	require.NoError(t, err)
+	require.NoError(t, expireAccessRequest(conn, approved, "Investigation completed"))
+	assert.Equal(t, "Expired", decision["decision"])
+	assert.Equal(t, "Investigation completed", decision["justification"])
+
+	denied, err := atv1.NewAccessRequest().ID("ar-2").Status(atv1.NewAccessRequestStatus().State(atv1.AccessRequestStateDenied)).Build()
+	require.NoError(t, err)
+	assert.EqualError(t, expireAccessRequest(conn, denied, "Investigation completed"), "access request ar-2 is Denied and cannot be expired")
+}
+
+func TestBuildRequest(t *testing.T) {
+	o := &createOptions{justification: "Investigating OHSS-1234", duration: "8h", deadline: "2h", internalSupportCase: "OHSS-1234"}
+	request, err := o.buildRequest("cluster-123", "sub-123")
+	require.NoError(t, err)
+	assert.Equal(t, "cluster-123", request.ClusterId())
+	assert.Equal(t, "sub-123", request.SubscriptionId())
+	assert.Equal(t, "2h", request.Deadline())
+	assert.Equal(t, "OHSS-1234", request.InternalSupportCaseId())
+	_, ok := request.GetSupportCaseId()
+	assert.False(t, ok)
+
+	o.duration = "a day"
+	_, err = o.buildRequest("cluster-123", "sub-123")
+	assert.ErrorContains(t, err, "invalid --duration")
+}
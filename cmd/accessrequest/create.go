@@ -0,0 +1,190 @@
+package accessrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	atv1 "github.com/openshift-online/ocm-sdk-go/accesstransparency/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type createOptions struct {
+	clusterID           string
+	justification       string
+	duration            string
+	deadline            string
+	supportCaseID       string
+	internalSupportCase string
+	wait                bool
+	pollInterval        time.Duration
+	timeout             time.Duration
+	command             []string
+
+	out io.Writer
+}
+
+func newCmdCreate() *cobra.Command {
+	opts := &createOptions{}
+
+	createCmd := &cobra.Command{
+		Use:   "create --cluster-id <cluster-identifier> --justification <justification> [-- <command>]",
+		Short: "Create an access request for a cluster and optionally wait for its approval",
+		Long: `Create an access request for a cluster and optionally wait for its approval
+
+  If the cluster already has a pending or approved access request, it is reused instead
+  of creating a new one. With --wait, the command polls the access request until the
+  customer made a decision and fails if it was not approved. A command given after "--"
+  implies --wait and is run once access has been granted.`,
+		Example: `
+  # Request access to a cluster for an incident
+  osdctl access-request create --cluster-id ${CLUSTER_ID} --justification "Investigating OHSS-1234" --internal-support-case OHSS-1234
+
+  # Request access, wait for the approval and log into the cluster once granted
+  osdctl access-request create --cluster-id ${CLUSTER_ID} --justification "Investigating OHSS-1234" -- ocm backplane login ${CLUSTER_ID}`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			command, err := commandAfterDash(args, cmd.ArgsLenAtDash())
+			if err != nil {
+				return err
+			}
+			opts.command = command
+			opts.out = cmd.OutOrStdout()
+			return opts.run(cmd.Context())
+		},
+	}
+
+	createCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID, external ID or name of the cluster to request access to")
+	createCmd.Flags().StringVar(&opts.justification, "justification", "", "Justification for the access request shown to the customer")
+	createCmd.Flags().StringVar(&opts.duration, "duration", "8h", "How long access is granted for once approved")
+	createCmd.Flags().StringVar(&opts.deadline, "deadline", "8h", "How long the access request waits for a customer decision before expiring")
+	createCmd.Flags().StringVar(&opts.supportCaseID, "support-case", "", "Customer support case ID related to the access request")
+	createCmd.Flags().StringVar(&opts.internalSupportCase, "internal-support-case", "", "Internal support case (e.g. OHSS Jira ticket) related to the access request")
+	createCmd.Flags().BoolVar(&opts.wait, "wait", false, "Wait until the customer made a decision, failing if access was not approved")
+	createCmd.Flags().DurationVar(&opts.pollInterval, "poll-interval", defaultPollInterval, "Interval between checks of the access request state while waiting")
+	createCmd.Flags().DurationVar(&opts.timeout, "timeout", defaultWaitTimeout, "Maximum time to wait for a decision")
+
+	_ = createCmd.MarkFlagRequired("cluster-id")
+	_ = createCmd.MarkFlagRequired("justification")
+
+	return createCmd
+}
+
+// commandAfterDash returns the command given after "--", refusing arguments given before it
+func commandAfterDash(args []string, argsLenAtDash int) ([]string, error) {
+	if argsLenAtDash == -1 {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("unexpected arguments %v, the command to run must be given after \"--\"", args)
+		}
+		return nil, nil
+	}
+	if argsLenAtDash > 0 {
+		return nil, fmt.Errorf("unexpected arguments %v before \"--\"", args[:argsLenAtDash])
+	}
+	return args, nil
+}
+
+func (o *createOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cluster, err := utils.GetCluster(conn, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	request, err := o.createOrReuse(conn, cluster.ID(), cluster.Subscription().ID())
+	if err != nil {
+		return err
+	}
+
+	if !o.wait && len(o.command) == 0 {
+		return nil
+	}
+
+	if request.Status().State() == atv1.AccessRequestStatePending {
+		request, err = waitForDecision(ctx, conn, request.ID(), o.pollInterval, o.timeout)
+		if err != nil {
+			return err
+		}
+	}
+	if state := request.Status().State(); state != atv1.AccessRequestStateApproved {
+		return fmt.Errorf("access request %s was not approved, its state is %s", request.ID(), state)
+	}
+	log.Printf("access request %s has been approved, access expires at %s", request.ID(), formatTime(request.Status().ExpiresAt()))
+
+	if len(o.command) == 0 {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, o.command[0], o.command[1:]...) // #nosec G204 -- the command is provided by the user
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// createOrReuse creates a new access request unless the cluster already has a pending or approved one
+func (o *createOptions) createOrReuse(conn *sdk.Connection, clusterID, subscriptionID string) (*atv1.AccessRequest, error) {
+	requests, err := listAccessRequests(conn, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if existing := findOpenAccessRequest(requests); existing != nil {
+		log.Printf("cluster %s already has a %s access request, reusing it", clusterID, existing.Status().State())
+		return existing, printAccessRequest(o.out, existing)
+	}
+
+	body, err := o.buildRequest(clusterID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := conn.AccessTransparency().V1().AccessRequests().Post().Body(body).Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access request: %w", err)
+	}
+
+	request := response.Body()
+	log.Printf("created access request %s, waiting for the customer to approve it", request.ID())
+	return request, printAccessRequest(o.out, request)
+}
+
+// buildRequest builds the body of the access request creation
+func (o *createOptions) buildRequest(clusterID, subscriptionID string) (*atv1.AccessRequestPostRequest, error) {
+	if o.justification == "" {
+		return nil, errors.New("a justification is required")
+	}
+	for name, value := range map[string]string{"duration": o.duration, "deadline": o.deadline} {
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid --%s %q: %w", name, value, err)
+		}
+	}
+
+	builder := atv1.NewAccessRequestPostRequest().
+		ClusterId(clusterID).
+		SubscriptionId(subscriptionID).
+		Justification(o.justification).
+		Duration(o.duration).
+		Deadline(o.deadline)
+	if o.supportCaseID != "" {
+		builder = builder.SupportCaseId(o.supportCaseID)
+	}
+	if o.internalSupportCase != "" {
+		builder = builder.InternalSupportCaseId(o.internalSupportCase)
+	}
+	return builder.Build()
+}
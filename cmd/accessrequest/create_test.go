@@ -0,0 +1,46 @@
+package accessrequest
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandAfterDash(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		argsLenAtDash int
+		want          []string
+		wantErr       bool
+	}{
+		{name: "no command", argsLenAtDash: -1},
+		{name: "command after dash", args: []string{"ocm", "backplane", "login"}, argsLenAtDash: 0, want: []string{"ocm", "backplane", "login"}},
+		{name: "command without dash", args: []string{"ocm", "backplane", "login"}, argsLenAtDash: -1, wantErr: true},
+		{name: "arguments before dash", args: []string{"typo", "ocm"}, argsLenAtDash: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := commandAfterDash(tt.args, tt.argsLenAtDash)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreateRefusesCommandWithoutDash(t *testing.T) {
+	cmd := newCmdCreate()
+	cmd.SetArgs([]string{"--cluster-id", "my-cluster", "--justification", "Investigating OHSS-1234", "ocm", "backplane", "login"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `must be given after "--"`)
+}
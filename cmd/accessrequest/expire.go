@@ -0,0 +1,109 @@
+package accessrequest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	atv1 "github.com/openshift-online/ocm-sdk-go/accesstransparency/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type expireOptions struct {
+	clusterID string
+	id        string
+	reason    string
+
+	out io.Writer
+}
+
+func newCmdExpire() *cobra.Command {
+	opts := &expireOptions{}
+
+	expireCmd := &cobra.Command{
+		Use:   "expire --cluster-id <cluster-identifier> --reason <reason>",
+		Short: "Expire an access request once access is not needed anymore",
+		Long: `Expire an access request once access is not needed anymore
+
+  Expires the given access request, or the most recent pending or approved access
+  request of the cluster when --id is not provided.`,
+		Example: `
+  # Expire the open access request of a cluster once the investigation is done
+  osdctl access-request expire --cluster-id ${CLUSTER_ID} --reason "Investigation of OHSS-1234 completed"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			return opts.run()
+		},
+	}
+
+	expireCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID, external ID or name of the cluster")
+	expireCmd.Flags().StringVar(&opts.id, "id", "", "ID of the access request to expire")
+	expireCmd.Flags().StringVar(&opts.reason, "reason", "", "Reason for expiring the access request")
+
+	_ = expireCmd.MarkFlagRequired("cluster-id")
+	_ = expireCmd.MarkFlagRequired("reason")
+
+	return expireCmd
+}
+
+func (o *expireOptions) run() error {
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cluster, err := utils.GetCluster(conn, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	requests, err := listAccessRequests(conn, cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	request, err := selectAccessRequest(requests, o.id)
+	if err != nil {
+		return err
+	}
+	if err := printAccessRequest(o.out, request); err != nil {
+		return err
+	}
+
+	if !prompt.Confirm(fmt.Sprintf("Expire access request %s?", request.ID()), false) {
+		return errors.New("aborted")
+	}
+
+	if err := expireAccessRequest(conn, request, o.reason); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(o.out, "Access request %s has been expired\n", request.ID())
+	return nil
+}
+
+// expireAccessRequest adds an Expired decision to the access request
+func expireAccessRequest(conn *sdk.Connection, request *atv1.AccessRequest, reason string) error {
+	switch request.Status().State() {
+	case atv1.AccessRequestStatePending, atv1.AccessRequestStateApproved:
+	default:
+		return fmt.Errorf("access request %s is %s and cannot be expired", request.ID(), request.Status().State())
+	}
+
+	decision, err := atv1.NewDecision().
+		Decision(atv1.DecisionDecisionExpired).
+		Justification(reason).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.AccessTransparency().V1().AccessRequests().AccessRequest(request.ID()).Decisions().Add().Body(decision).Send(); err != nil {
+		return fmt.Errorf("failed to expire access request %s: %w", request.ID(), err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package accessrequest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	atv1 "github.com/openshift-online/ocm-sdk-go/accesstransparency/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type statusOptions struct {
+	clusterID    string
+	id           string
+	wait         bool
+	pollInterval time.Duration
+	timeout      time.Duration
+
+	out io.Writer
+}
+
+func newCmdStatus() *cobra.Command {
+	opts := &statusOptions{}
+
+	statusCmd := &cobra.Command{
+		Use:   "status --cluster-id <cluster-identifier>",
+		Short: "Show the access requests of a cluster",
+		Long: `Show the access requests of a cluster
+
+  Without --id all access requests of the cluster are listed. With --id, or with --wait,
+  the details and decisions of a single access request are shown. --wait polls the
+  access request (by default the most recent pending or approved one) until the customer
+  made a decision.`,
+		Example: `
+  # List the access requests of a cluster
+  osdctl access-request status --cluster-id ${CLUSTER_ID}
+
+  # Wait for the customer to decide on the pending access request
+  osdctl access-request status --cluster-id ${CLUSTER_ID} --wait`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			return opts.run(cmd.Context())
+		},
+	}
+
+	statusCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID, external ID or name of the cluster")
+	statusCmd.Flags().StringVar(&opts.id, "id", "", "ID of a single access request to show")
+	statusCmd.Flags().BoolVar(&opts.wait, "wait", false, "Wait until the customer made a decision on the access request")
+	statusCmd.Flags().DurationVar(&opts.pollInterval, "poll-interval", defaultPollInterval, "Interval between checks of the access request state while waiting")
+	statusCmd.Flags().DurationVar(&opts.timeout, "timeout", defaultWaitTimeout, "Maximum time to wait for a decision")
+
+	_ = statusCmd.MarkFlagRequired("cluster-id")
+
+	return statusCmd
+}
+
+func (o *statusOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cluster, err := utils.GetCluster(conn, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	requests, err := listAccessRequests(conn, cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	if o.id == "" && !o.wait {
+		if len(requests) == 0 {
+			_, _ = fmt.Fprintf(o.out, "Cluster %s has no access requests\n", cluster.ID())
+			return nil
+		}
+		return printAccessRequests(o.out, requests)
+	}
+
+	request, err := selectAccessRequest(requests, o.id)
+	if err != nil {
+		return err
+	}
+
+	if o.wait && request.Status().State() == atv1.AccessRequestStatePending {
+		request, err = waitForDecision(ctx, conn, request.ID(), o.pollInterval, o.timeout)
+		if err != nil {
+			return err
+		}
+	}
+	return printAccessRequest(o.out, request)
+}
+
+// selectAccessRequest returns the access request with the given ID, or the most recent open one if id is empty
+func selectAccessRequest(requests []*atv1.AccessRequest, id string) (*atv1.AccessRequest, error) {
+	if id == "" {
+		if request := findOpenAccessRequest(requests); request != nil {
+			return request, nil
+		}
+		return nil, fmt.Errorf("the cluster has no pending or approved access request")
+	}
+
+	for _, request := range requests {
+		if request.ID() == id {
+			return request, nil
+		}
+	}
+	return nil, fmt.Errorf("access request %s not found for the cluster", id)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts/types"
 	"github.com/openshift/osdctl/pkg/osdCloud"
 	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
@@ -51,7 +52,7 @@ func (o *cliOptions) complete(cmd *cobra.Command) error {
 	}
 
 	if o.region == "" {
-		o.region = "us-east-1"
+		o.region = utils.GetDefaultAWSRegion()
 	}
 
 	return nil
@@ -7,6 +7,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/openshift/osdctl/cmd/account/get"
+	"github.com/openshift/osdctl/cmd/account/iam"
 	"github.com/openshift/osdctl/cmd/account/list"
 	"github.com/openshift/osdctl/cmd/account/mgmt"
 	"github.com/openshift/osdctl/cmd/account/servicequotas"
@@ -24,6 +25,7 @@ func NewCmdAccount(streams genericclioptions.IOStreams, client *k8s.LazyClient,
 	}
 
 	accountCmd.AddCommand(get.NewCmdGet(streams, client, globalOpts))
+	accountCmd.AddCommand(iam.NewCmdIam())
 	accountCmd.AddCommand(list.NewCmdList(streams, client, globalOpts))
 	accountCmd.AddCommand(servicequotas.NewCmdServiceQuotas(streams))
 	accountCmd.AddCommand(mgmt.NewCmdMgmt(streams, globalOpts))
@@ -2,23 +2,47 @@ package account
 
 import (
 	"fmt"
-	"net/url"
+	"os"
 
 	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+// defaultConsoleDuration is the default duration of a console session, in seconds
+const defaultConsoleDuration = 3600
+
 // newCmdConsole implements the Console command which Consoles the specified account cr
 func newCmdConsole() *cobra.Command {
 	ops := newConsoleOptions()
 	consoleCmd := &cobra.Command{
-		Use:               "console",
-		Short:             "Generate an AWS console URL on the fly",
+		Use:   "console",
+		Short: "Generate an AWS console URL on the fly",
+		Long: `Generate an AWS console URL on the fly, signed in as OrganizationAccountAccessRole of the account.
+
+The partition of the console (standard, GovCloud or China) is the partition of the AWS credentials in use,
+the region must be in that partition and defaults to the default region of the partition.
+
+The URL can land directly on a page of the console: a path relative to the console home with --destination,
+or the page of an EC2 instance with --instance-id. With --qr, the URL is also printed as a QR code to open it
+from a phone, which needs a terminal about 180 columns wide as the URLs are long.
+
+Sessions longer than 1 hour need credentials which don't come from an assumed role themselves,
+AWS limits role chaining to 1 hour.`,
+		Example: `  # Open the console of an account in the browser
+  osdctl account console -i ${AWS_ACCOUNT_ID} --launch
+
+  # Land on the page of an EC2 instance of a GovCloud account
+  osdctl account console -i ${AWS_ACCOUNT_ID} -r us-gov-east-1 --instance-id i-0123456789abcdef0
+
+  # Land on the CloudWatch alarms, with a QR code to open the URL from a phone
+  osdctl account console -i ${AWS_ACCOUNT_ID} -r eu-west-1 --destination 'cloudwatch/home#alarmsV2:' --qr`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -29,11 +53,14 @@ func newCmdConsole() *cobra.Command {
 
 	consoleCmd.Flags().BoolVarP(&ops.verbose, "verbose", "", false, "Verbose output")
 	consoleCmd.Flags().BoolVar(&ops.launch, "launch", false, "Launch web browser directly")
-	consoleCmd.Flags().Int32VarP(&ops.consoleDuration, "duration", "d", 3600, "The duration of the console session. "+
-		"Default value is 3600 seconds(1 hour)")
+	consoleCmd.Flags().Int32VarP(&ops.consoleDuration, "duration", "d", defaultConsoleDuration, fmt.Sprintf("The duration of the console session in seconds, between %d and %d. "+
+		"Default value is 3600 seconds(1 hour)", aws.MinConsoleSessionSeconds, aws.MaxConsoleSessionSeconds))
 	consoleCmd.Flags().StringVarP(&ops.awsAccountID, "accountId", "i", "", "AWS Account ID")
 	consoleCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
-	consoleCmd.Flags().StringVarP(&ops.region, "region", "r", "", "Region")
+	consoleCmd.Flags().StringVarP(&ops.region, "region", "r", "", "Region, defaults to the default region of the partition of the credentials")
+	consoleCmd.Flags().StringVar(&ops.destination, "destination", "", "Page of the console to land on, as a path relative to the console home, e.g. 'ec2/home#Instances:'")
+	consoleCmd.Flags().StringVar(&ops.instanceID, "instance-id", "", "Land on the page of this EC2 instance")
+	consoleCmd.Flags().BoolVar(&ops.qr, "qr", false, "Also print the URL as a QR code")
 
 	return consoleCmd
 }
@@ -42,16 +69,22 @@ func newCmdConsole() *cobra.Command {
 type consoleOptions struct {
 	verbose bool
 	launch  bool
+	qr      bool
 
 	awsAccountID string
 	awsProfile   string
 	region       string
+	// regionSet is whether the region was given, rather than defaulted
+	regionSet bool
+
+	destination string
+	instanceID  string
 
 	consoleDuration int32
 }
 
 func newConsoleOptions() *consoleOptions {
-	return &consoleOptions{}
+	return &consoleOptions{consoleDuration: defaultConsoleDuration}
 }
 
 func (o *consoleOptions) complete(cmd *cobra.Command) error {
@@ -60,8 +93,17 @@ func (o *consoleOptions) complete(cmd *cobra.Command) error {
 		return fmt.Errorf("please specify -i")
 	}
 
+	if o.consoleDuration < aws.MinConsoleSessionSeconds || o.consoleDuration > aws.MaxConsoleSessionSeconds {
+		return fmt.Errorf("--duration must be between %d and %d seconds", aws.MinConsoleSessionSeconds, aws.MaxConsoleSessionSeconds)
+	}
+
+	if o.destination != "" && o.instanceID != "" {
+		return fmt.Errorf("--destination and --instance-id can't be combined")
+	}
+
+	o.regionSet = o.region != ""
 	if o.region == "" {
-		o.region = "us-east-1"
+		o.region = utils.GetDefaultAWSRegion()
 	}
 
 	return nil
@@ -84,6 +126,10 @@ func (o *consoleOptions) run() error {
 		return err
 	}
 
+	if err := o.completeRegion(partition); err != nil {
+		return err
+	}
+
 	// Generate a session name using the SRE's kerberos ID
 	sessionName, err := osdCloud.GenerateRoleSessionName(awsClient)
 	if err != nil {
@@ -101,23 +147,32 @@ func (o *consoleOptions) run() error {
 
 	targetRoleArn.Partition = partition
 
-	consoleURL, err := aws.RequestSignInToken(
+	destination, err := aws.ConsoleDestination(partition, o.region, o.consolePage())
+	if err != nil {
+		return err
+	}
+
+	consoleURL, err := aws.RequestSignInURL(
 		awsClient,
 		&o.consoleDuration,
 		&sessionName,
 		awsSdk.String(targetRoleArn.String()),
+		destination,
 	)
 	if err != nil {
 		fmt.Printf("Generating console failed: %s\n", err)
 		return err
 	}
 
-	consoleURL, err = PrependRegionToURL(consoleURL, o.region)
-	if err != nil {
-		return fmt.Errorf("could not prepend region to console url: %w", err)
-	}
 	fmt.Printf("The AWS Console URL is:\n%s\n", consoleURL)
 
+	if o.qr {
+		fmt.Println()
+		if err := printer.PrintQRCode(os.Stdout, consoleURL); err != nil {
+			return err
+		}
+	}
+
 	if o.launch {
 		return browser.OpenURL(consoleURL)
 	}
@@ -125,33 +180,24 @@ func (o *consoleOptions) run() error {
 	return nil
 }
 
-func PrependRegionToURL(consoleURL, region string) (string, error) {
-	// Extract the url data
-	u, err := url.Parse(consoleURL)
-	if err != nil {
-		return "", fmt.Errorf("cannot parse consoleURL '%s' : %w", consoleURL, err)
+// completeRegion checks the region is in the partition of the credentials, a defaulted region is replaced by the
+// default region of the partition
+func (o *consoleOptions) completeRegion(partition string) error {
+	regionPartition := aws.GetPartitionForRegion(o.region)
+	if regionPartition == partition {
+		return nil
 	}
-	urlValues, err := url.ParseQuery(u.RawQuery)
-	if err != nil {
-		return "", fmt.Errorf("cannot parse the queries '%s' : %w", u.RawQuery, err)
+	if o.regionSet {
+		return fmt.Errorf("region %s is in the %s partition but the AWS credentials are for the %s partition", o.region, regionPartition, partition)
 	}
+	o.region = aws.GetDefaultRegion(partition)
+	return nil
+}
 
-	// Retrieve the Destination url for modification
-	rawDestinationUrl := urlValues.Get("Destination")
-	destinationURL, err := url.Parse(rawDestinationUrl)
-	if err != nil {
-		return "", fmt.Errorf("cannot parse rawDestinationUrl '%s' : %w", rawDestinationUrl, err)
+// consolePage returns the page of the console to land on, the console home when empty
+func (o *consoleOptions) consolePage() string {
+	if o.instanceID != "" {
+		return aws.EC2InstanceConsolePage(o.region, o.instanceID)
 	}
-	// Prepend the region to the url
-	destinationURL.Host = fmt.Sprintf("%s.%s", region, destinationURL.Host)
-	prependedDestinationURL := destinationURL.String()
-
-	// override the Destination after it was modified
-	urlValues.Set("Destination", prependedDestinationURL)
-
-	// Wrap up the values into the original url
-	u.RawQuery = urlValues.Encode()
-	consoleURL = u.String()
-
-	return consoleURL, nil
+	return o.destination
 }
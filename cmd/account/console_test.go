@@ -7,64 +7,59 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestPrependRegionToURL(t *testing.T) {
+func TestConsoleCompleteRegion(t *testing.T) {
 	tests := []struct {
-		name        string
-		consoleURL  string
-		region      string
-		expectedURL string
-		expectErr   bool
+		name           string
+		region         string
+		regionSet      bool
+		partition      string
+		expectedRegion string
+		expectErr      bool
 	}{
 		{
-			name:        "valid_url_with_destination",
-			consoleURL:  "https://example.com/login?Destination=https%3A%2F%2Fservice.com%2Fhome",
-			region:      "us-west-2",
-			expectedURL: "https://example.com/login?Destination=https%3A%2F%2Fus-west-2.service.com%2Fhome",
-			expectErr:   false,
-		},
-		{
-			name:        "invalid_console_url",
-			consoleURL:  "http://[::1]:namedport",
-			region:      "us-west-2",
-			expectedURL: "",
-			expectErr:   true,
-		},
-		{
-			name:        "missing_destination",
-			consoleURL:  "https://example.com/login",
-			region:      "us-west-2",
-			expectedURL: "https://example.com/login?Destination=%2F%2Fus-west-2.",
-			expectErr:   false,
+			name:           "region_in_partition",
+			region:         "us-gov-east-1",
+			regionSet:      true,
+			partition:      "aws-us-gov",
+			expectedRegion: "us-gov-east-1",
 		},
 		{
-			name:        "invalid_destination_url",
-			consoleURL:  "https://example.com/login?Destination=::badurl::",
-			region:      "us-west-2",
-			expectedURL: "",
-			expectErr:   true,
+			name:           "defaulted_region_of_another_partition",
+			region:         "us-east-1",
+			partition:      "aws-cn",
+			expectedRegion: "cn-north-1",
 		},
 		{
-			name:        "valid_destination_with_query",
-			consoleURL:  "https://example.com/login?Destination=https%3A%2F%2Fservice.com%2Fhome%3Ffoo%3Dbar",
-			region:      "ap-southeast-1",
-			expectedURL: "https://example.com/login?Destination=https%3A%2F%2Fap-southeast-1.service.com%2Fhome%3Ffoo%3Dbar",
-			expectErr:   false,
+			name:      "given_region_of_another_partition",
+			region:    "cn-north-1",
+			regionSet: true,
+			partition: "aws",
+			expectErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := PrependRegionToURL(tt.consoleURL, tt.region)
+			ops := &consoleOptions{region: tt.region, regionSet: tt.regionSet}
+			err := ops.completeRegion(tt.partition)
 			if tt.expectErr {
-				assert.Error(t, err)
+				assert.EqualError(t, err, "region cn-north-1 is in the aws-cn partition but the AWS credentials are for the aws partition")
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedURL, result)
+				assert.Equal(t, tt.expectedRegion, ops.region)
 			}
 		})
 	}
 }
 
+func TestConsolePage(t *testing.T) {
+	ops := &consoleOptions{region: "us-gov-west-1", destination: "cloudwatch/home"}
+	assert.Equal(t, "cloudwatch/home", ops.consolePage())
+
+	ops = &consoleOptions{region: "us-gov-west-1", instanceID: "i-0123456789abcdef0"}
+	assert.Equal(t, "ec2/home?region=us-gov-west-1#InstanceDetails:instanceId=i-0123456789abcdef0", ops.consolePage())
+}
+
 func TestNewCmdConsole(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -86,6 +81,23 @@ func TestNewCmdConsole(t *testing.T) {
 			expectedErr:    false,
 			expectedRegion: "ap-south-1",
 		},
+		{
+			name: "duration_too_long",
+			flags: map[string]string{
+				"accountId": "123456789012",
+				"duration":  "86400",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "destination_and_instance_id",
+			flags: map[string]string{
+				"accountId":   "123456789012",
+				"destination": "ec2/home",
+				"instance-id": "i-0123456789abcdef0",
+			},
+			expectedErr: true,
+		},
 		{
 			name: "valid_input_without_region",
 			flags: map[string]string{
@@ -104,6 +116,9 @@ func TestNewCmdConsole(t *testing.T) {
 			cmd := &cobra.Command{}
 			cmd.Flags().StringVarP(&ops.awsAccountID, "accountId", "i", "", "")
 			cmd.Flags().StringVarP(&ops.region, "region", "r", "", "")
+			cmd.Flags().Int32VarP(&ops.consoleDuration, "duration", "d", defaultConsoleDuration, "")
+			cmd.Flags().StringVar(&ops.destination, "destination", "", "")
+			cmd.Flags().StringVar(&ops.instanceID, "instance-id", "", "")
 
 			// Set flags
 			for k, v := range tt.flags {
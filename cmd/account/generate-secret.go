@@ -157,7 +157,7 @@ func (o *generateSecretOptions) run() error {
 	}
 
 	// Assume
-	roleArn := awsSdk.String(fmt.Sprintf("arn:%s:iam::%s:role/%s", callerIdentityArn.Partition, accountID, awsv1alpha1.AccountOperatorIAMRole))
+	roleArn := awsSdk.String(awsprovider.GenerateRoleARNForPartition(callerIdentityArn.Partition, accountID, awsv1alpha1.AccountOperatorIAMRole))
 	credentials, err := awsprovider.GetAssumeRoleCredentials(awsSetupClient, awsSdk.Int32(900),
 		callerIdentityOutput.UserId, roleArn)
 	if err != nil {
@@ -182,7 +182,7 @@ func (o *generateSecretOptions) run() error {
 
 	// if the specified user does not exist, create one
 	if !ok {
-		policyArn := awsSdk.String("arn:aws:iam::aws:policy/AdministratorAccess")
+		policyArn := awsSdk.String(awsprovider.GenerateManagedPolicyARN(callerIdentityArn.Partition, "AdministratorAccess"))
 		if err := awsprovider.CreateIAMUserAndAttachPolicy(awsClient,
 			username, policyArn); err != nil {
 			return err
@@ -243,6 +243,13 @@ func (o *generateSecretOptions) generateCcsSecret() error {
 		return err
 	}
 
+	// Use the partition of the caller so role ARNs are valid in GovCloud as well
+	callerIdentityArn, err := arn.Parse(*callerIdentityOutput.Arn)
+	if err != nil {
+		return err
+	}
+	region := awsprovider.GetDefaultRegion(callerIdentityArn.Partition)
+
 	accountIDSuffixLabel, ok := account.Labels["iamUserId"]
 	if !ok {
 		return fmt.Errorf("no label on Account CR for IAM User")
@@ -271,7 +278,7 @@ func (o *generateSecretOptions) generateCcsSecret() error {
 		AccessKeyID:     *srepRoleCredentials.AccessKeyId,
 		SecretAccessKey: *srepRoleCredentials.SecretAccessKey,
 		SessionToken:    *srepRoleCredentials.SessionToken,
-		Region:          "us-east-1",
+		Region:          region,
 	})
 	if err != nil {
 		return err
@@ -292,13 +299,13 @@ func (o *generateSecretOptions) generateCcsSecret() error {
 		AccessKeyID:     *jumpRoleCreds.AccessKeyId,
 		SecretAccessKey: *jumpRoleCreds.SecretAccessKey,
 		SessionToken:    *jumpRoleCreds.SessionToken,
-		Region:          "us-east-1",
+		Region:          region,
 	})
 	if err != nil {
 		return err
 	}
 	// Role chain to assume ManagedOpenShift-Support-{uid}
-	roleArn := awsSdk.String(fmt.Sprintf("arn:aws:iam::%s:role/%s", account.Spec.AwsAccountID, "ManagedOpenShift-Support-"+accountIDSuffixLabel))
+	roleArn := awsSdk.String(awsprovider.GenerateRoleARNForPartition(callerIdentityArn.Partition, account.Spec.AwsAccountID, "ManagedOpenShift-Support-"+accountIDSuffixLabel))
 	credentials, err := awsprovider.GetAssumeRoleCredentials(jumpRoleClient, o.awsAccountTimeout,
 		callerIdentityOutput.UserId, roleArn)
 	if err != nil {
@@ -310,7 +317,7 @@ func (o *generateSecretOptions) generateCcsSecret() error {
 		AccessKeyID:     *credentials.AccessKeyId,
 		SecretAccessKey: *credentials.SecretAccessKey,
 		SessionToken:    *credentials.SessionToken,
-		Region:          "us-east-1",
+		Region:          region,
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,22 @@
+package iam
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdIam implements commands related to the IAM roles of a cluster's AWS account
+func NewCmdIam() *cobra.Command {
+	baseCmd := &cobra.Command{
+		Use:               "iam",
+		Short:             "Inspect the IAM roles of a cluster's AWS account",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	baseCmd.AddCommand(newCmdSimulate())
+
+	return baseCmd
+}
@@ -0,0 +1,321 @@
+package iam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	roleInstaller    = "installer"
+	roleSupport      = "support"
+	roleControlPlane = "control-plane"
+	roleWorker       = "worker"
+	roleOperators    = "operators"
+
+	simulateExample = `  # Check whether the installer and operator roles of a cluster can still create instances
+  osdctl account iam simulate --cluster-id ${CLUSTER_ID} --action ec2:RunInstances --action ec2:CreateTags
+
+  # Check a single operator role against a specific resource
+  osdctl account iam simulate --cluster-id ${CLUSTER_ID} --role operator:openshift-image-registry/installer-cloud-credentials \
+    --action s3:PutObject --resource arn:aws:s3:::my-registry-bucket/*
+
+  # Check an arbitrary role of the cluster's account and print the results as JSON
+  osdctl account iam simulate --cluster-id ${CLUSTER_ID} --role arn:aws:iam::123456789012:role/custom --action iam:PassRole -o json`
+)
+
+// simulateOptions defines the struct for running the iam simulate command
+type simulateOptions struct {
+	clusterID  string
+	awsProfile string
+	roles      []string
+	actions    []string
+	resources  []string
+	output     string
+
+	out       io.Writer
+	awsClient awsprovider.Client
+	cluster   *cmv1.Cluster
+}
+
+// simulatedRole is a role of the cluster whose policies are simulated
+type simulatedRole struct {
+	Name string `json:"name"`
+	ARN  string `json:"arn"`
+}
+
+// simulationResult is the decision of IAM for a role performing an action on a resource
+type simulationResult struct {
+	Role                 string   `json:"role"`
+	RoleARN              string   `json:"roleArn"`
+	Action               string   `json:"action"`
+	Resource             string   `json:"resource"`
+	Decision             string   `json:"decision"`
+	MatchedPolicies      []string `json:"matchedPolicies,omitempty"`
+	MissingContextValues []string `json:"missingContextValues,omitempty"`
+}
+
+func (r simulationResult) allowed() bool {
+	return r.Decision == "allowed"
+}
+
+// newCmdSimulate implements iam simulate
+func newCmdSimulate() *cobra.Command {
+	ops := &simulateOptions{out: os.Stdout}
+	simulateCmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulate whether the IAM roles of an STS cluster can perform a list of actions",
+		Long: `Uses the IAM policy simulator to check whether the roles of an STS cluster can perform the given actions
+on the given resources, to quickly confirm whether an STS permissions regression is the cause of an install or
+upgrade failure.
+
+--role selects the roles to simulate, one of:
+  installer, support, control-plane, worker: the account roles of the cluster
+  operators: all operator roles of the cluster
+  operator:<namespace>/<name>: a single operator role of the cluster
+  <role ARN>: any role of the cluster's AWS account
+
+The simulation only evaluates the identity policies attached to the roles and the permissions boundaries, it
+doesn't take resource policies or service control policies into account.`,
+		Example:           simulateExample,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ops.complete(); err != nil {
+				return err
+			}
+			return ops.run()
+		},
+	}
+
+	simulateCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID whose roles should be simulated")
+	simulateCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
+	simulateCmd.Flags().StringSliceVar(&ops.roles, "role", []string{roleInstaller, roleOperators}, "Roles to simulate, see the command description for the accepted values")
+	simulateCmd.Flags().StringSliceVarP(&ops.actions, "action", "a", nil, "IAM actions to simulate, e.g. ec2:RunInstances (can be specified multiple times)")
+	simulateCmd.Flags().StringSliceVar(&ops.resources, "resource", []string{"*"}, "Resource ARNs the actions are simulated against")
+	simulateCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Output format: table or json")
+
+	_ = simulateCmd.MarkFlagRequired("cluster-id")
+	_ = simulateCmd.MarkFlagRequired("action")
+
+	return simulateCmd
+}
+
+func (o *simulateOptions) complete() error {
+	if len(o.actions) == 0 {
+		return errors.New("at least one --action is required")
+	}
+	for _, action := range o.actions {
+		if !strings.Contains(action, ":") {
+			return fmt.Errorf("invalid action %q, expected <service>:<action>, e.g. ec2:RunInstances", action)
+		}
+	}
+	if len(o.roles) == 0 {
+		return errors.New("at least one --role is required")
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	return nil
+}
+
+func (o *simulateOptions) run() error {
+	if o.cluster == nil {
+		ocmClient, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		o.cluster, err = utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+		ocmClient.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	roles, err := resolveRoles(o.cluster, o.roles)
+	if err != nil {
+		return err
+	}
+
+	if o.awsClient == nil {
+		o.awsClient, err = osdCloud.GenerateAWSClientForCluster(o.awsProfile, o.cluster.ID())
+		if err != nil {
+			return err
+		}
+	}
+
+	var results []simulationResult
+	for _, role := range roles {
+		roleResults, err := o.simulate(role)
+		if err != nil {
+			return fmt.Errorf("failed to simulate the policies of role %s: %w", role.ARN, err)
+		}
+		results = append(results, roleResults...)
+	}
+
+	if err := o.printResults(results); err != nil {
+		return err
+	}
+
+	denied := 0
+	for _, result := range results {
+		if !result.allowed() {
+			denied++
+		}
+	}
+	if denied > 0 {
+		return fmt.Errorf("%d of %d simulated actions are denied", denied, len(results))
+	}
+	return nil
+}
+
+// resolveRoles maps the requested roles to the roles of the cluster
+func resolveRoles(cluster *cmv1.Cluster, requested []string) ([]simulatedRole, error) {
+	sts := cluster.AWS().STS()
+	if sts.RoleARN() == "" {
+		return nil, fmt.Errorf("cluster %s is not an STS cluster", cluster.ID())
+	}
+
+	var roles []simulatedRole
+	add := func(name, roleARN string) error {
+		if roleARN == "" {
+			return fmt.Errorf("cluster %s has no %s role", cluster.ID(), name)
+		}
+		if !slices.ContainsFunc(roles, func(r simulatedRole) bool { return r.ARN == roleARN }) {
+			roles = append(roles, simulatedRole{Name: name, ARN: roleARN})
+		}
+		return nil
+	}
+
+	for _, role := range requested {
+		var err error
+		switch {
+		case role == roleInstaller:
+			err = add(roleInstaller, sts.RoleARN())
+		case role == roleSupport:
+			err = add(roleSupport, sts.SupportRoleARN())
+		case role == roleControlPlane:
+			err = add(roleControlPlane, sts.InstanceIAMRoles().MasterRoleARN())
+		case role == roleWorker:
+			err = add(roleWorker, sts.InstanceIAMRoles().WorkerRoleARN())
+		case role == roleOperators:
+			for _, operatorRole := range sts.OperatorIAMRoles() {
+				if err = add(operatorRoleName(operatorRole), operatorRole.RoleARN()); err != nil {
+					break
+				}
+			}
+		case strings.HasPrefix(role, "operator:"):
+			found := false
+			for _, operatorRole := range sts.OperatorIAMRoles() {
+				if operatorRoleName(operatorRole) == role {
+					found = true
+					err = add(role, operatorRole.RoleARN())
+				}
+			}
+			if !found {
+				err = fmt.Errorf("cluster %s has no %s role", cluster.ID(), role)
+			}
+		case arn.IsARN(role):
+			err = add(role, role)
+		default:
+			err = fmt.Errorf("invalid role %q", role)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return roles, nil
+}
+
+func operatorRoleName(role *cmv1.OperatorIAMRole) string {
+	return fmt.Sprintf("operator:%s/%s", role.Namespace(), role.Name())
+}
+
+func (o *simulateOptions) simulate(role simulatedRole) ([]simulationResult, error) {
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: awsSdk.String(role.ARN),
+		ActionNames:     o.actions,
+		ResourceArns:    o.resources,
+	}
+
+	var results []simulationResult
+	for {
+		output, err := o.awsClient.SimulatePrincipalPolicy(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, evaluation := range output.EvaluationResults {
+			result := simulationResult{
+				Role:                 role.Name,
+				RoleARN:              role.ARN,
+				Action:               awsSdk.ToString(evaluation.EvalActionName),
+				Resource:             awsSdk.ToString(evaluation.EvalResourceName),
+				Decision:             string(evaluation.EvalDecision),
+				MissingContextValues: evaluation.MissingContextValues,
+			}
+			for _, statement := range evaluation.MatchedStatements {
+				policy := awsSdk.ToString(statement.SourcePolicyId)
+				if policy != "" && !slices.Contains(result.MatchedPolicies, policy) {
+					result.MatchedPolicies = append(result.MatchedPolicies, policy)
+				}
+			}
+			results = append(results, result)
+		}
+
+		if !output.IsTruncated {
+			break
+		}
+		input.Marker = output.Marker
+	}
+	return results, nil
+}
+
+func (o *simulateOptions) printResults(results []simulationResult) error {
+	if o.output == "json" {
+		if results == nil {
+			results = []simulationResult{}
+		}
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	p.AddRow([]string{"ROLE", "ACTION", "RESOURCE", "DECISION", "MATCHED POLICIES"})
+	for _, result := range results {
+		decision := decisionLabel(result.Decision)
+		if len(result.MissingContextValues) > 0 {
+			decision += fmt.Sprintf(" (missing context: %s)", strings.Join(result.MissingContextValues, ", "))
+		}
+		p.AddRow([]string{result.Role, result.Action, result.Resource, decision, strings.Join(result.MatchedPolicies, ", ")})
+	}
+	return p.Flush()
+}
+
+// decisionLabel turns the camel cased decisions of the simulator (allowed, explicitDeny, implicitDeny) into table labels
+func decisionLabel(decision string) string {
+	var label strings.Builder
+	for i, r := range decision {
+		if i > 0 && unicode.IsUpper(r) {
+			label.WriteRune(' ')
+		}
+		label.WriteRune(unicode.ToUpper(r))
+	}
+	return label.String()
+}
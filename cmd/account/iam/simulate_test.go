@@ -0,0 +1,121 @@
+package iam
+
+import (
+	"bytes"
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	installerARN = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+	ingressARN   = "arn:aws:iam::123456789012:role/mycluster-openshift-ingress-operator-cloud-credentials"
+)
+
+func newSTSCluster(t *testing.T) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID("abc123").AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+		RoleARN(installerARN).
+		SupportRoleARN("arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role").
+		InstanceIAMRoles(cmv1.NewInstanceIAMRoles().WorkerRoleARN("arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role")).
+		OperatorIAMRoles(cmv1.NewOperatorIAMRole().Namespace("openshift-ingress-operator").Name("cloud-credentials").RoleARN(ingressARN)))).
+		Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func TestResolveRoles(t *testing.T) {
+	cluster := newSTSCluster(t)
+
+	roles, err := resolveRoles(cluster, []string{roleInstaller, roleOperators, "operator:openshift-ingress-operator/cloud-credentials"})
+	require.NoError(t, err)
+	assert.Equal(t, []simulatedRole{
+		{Name: roleInstaller, ARN: installerARN},
+		{Name: "operator:openshift-ingress-operator/cloud-credentials", ARN: ingressARN},
+	}, roles)
+
+	roles, err = resolveRoles(cluster, []string{roleWorker, "arn:aws:iam::123456789012:role/custom"})
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/custom", roles[1].ARN)
+
+	_, err = resolveRoles(cluster, []string{roleControlPlane})
+	assert.EqualError(t, err, "cluster abc123 has no control-plane role")
+
+	_, err = resolveRoles(cluster, []string{"operator:openshift-machine-api/aws-cloud-credentials"})
+	assert.EqualError(t, err, "cluster abc123 has no operator:openshift-machine-api/aws-cloud-credentials role")
+
+	_, err = resolveRoles(cluster, []string{"admin"})
+	assert.EqualError(t, err, `invalid role "admin"`)
+
+	nonSTS, err := cmv1.NewCluster().ID("def456").Build()
+	require.NoError(t, err)
+	_, err = resolveRoles(nonSTS, []string{roleInstaller})
+	assert.EqualError(t, err, "cluster def456 is not an STS cluster")
+}
+
+func TestSimulate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	awsClient := mock.NewMockClient(ctrl)
+
+	evaluation := func(action string, decision iamtypes.PolicyEvaluationDecisionType, policies ...string) iamtypes.EvaluationResult {
+		result := iamtypes.EvaluationResult{EvalActionName: awsSdk.String(action), EvalResourceName: awsSdk.String("*"), EvalDecision: decision}
+		for _, policy := range policies {
+			result.MatchedStatements = append(result.MatchedStatements, iamtypes.Statement{SourcePolicyId: awsSdk.String(policy)})
+		}
+		return result
+	}
+
+	awsClient.EXPECT().SimulatePrincipalPolicy(gomock.Any()).DoAndReturn(func(input *iam.SimulatePrincipalPolicyInput) (*iam.SimulatePrincipalPolicyOutput, error) {
+		assert.Equal(t, installerARN, *input.PolicySourceArn)
+		assert.Nil(t, input.Marker)
+		return &iam.SimulatePrincipalPolicyOutput{
+			EvaluationResults: []iamtypes.EvaluationResult{evaluation("ec2:RunInstances", iamtypes.PolicyEvaluationDecisionTypeAllowed, "ManagedOpenShift-Installer-Role-Policy")},
+			IsTruncated:       true,
+			Marker:            awsSdk.String("next"),
+		}, nil
+	})
+	awsClient.EXPECT().SimulatePrincipalPolicy(gomock.Any()).DoAndReturn(func(input *iam.SimulatePrincipalPolicyInput) (*iam.SimulatePrincipalPolicyOutput, error) {
+		assert.Equal(t, "next", *input.Marker)
+		return &iam.SimulatePrincipalPolicyOutput{
+			EvaluationResults: []iamtypes.EvaluationResult{evaluation("ec2:CreateTags", iamtypes.PolicyEvaluationDecisionTypeImplicitDeny)},
+		}, nil
+	})
+
+	out := &bytes.Buffer{}
+	opts := &simulateOptions{
+		roles:     []string{roleInstaller},
+		actions:   []string{"ec2:RunInstances", "ec2:CreateTags"},
+		resources: []string{"*"},
+		output:    "table",
+		out:       out,
+		awsClient: awsClient,
+		cluster:   newSTSCluster(t),
+	}
+	require.NoError(t, opts.complete())
+
+	err := opts.run()
+	assert.EqualError(t, err, "1 of 2 simulated actions are denied")
+	assert.Contains(t, out.String(), "ec2:RunInstances")
+	assert.Contains(t, out.String(), "ALLOWED")
+	assert.Contains(t, out.String(), "ManagedOpenShift-Installer-Role-Policy")
+	assert.Contains(t, out.String(), "IMPLICIT DENY")
+}
+
+func TestSimulateComplete(t *testing.T) {
+	opts := &simulateOptions{roles: []string{roleInstaller}, output: "table"}
+	assert.EqualError(t, opts.complete(), "at least one --action is required")
+
+	opts.actions = []string{"RunInstances"}
+	assert.EqualError(t, opts.complete(), `invalid action "RunInstances", expected <service>:<action>, e.g. ec2:RunInstances`)
+
+	opts.actions = []string{"ec2:RunInstances"}
+	opts.output = "yaml"
+	assert.EqualError(t, opts.complete(), `invalid output format "yaml", expected table or json`)
+}
@@ -3,7 +3,6 @@ package mgmt
 import (
 	"fmt"
 	"math/rand"
-	"os"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
@@ -33,6 +32,11 @@ type accountAssignOptions struct {
 	accountID    string
 	output       string
 	iamUser      bool
+	dryRun       bool
+	csvFile      string
+
+	// reserved are the accounts already picked for previous rows of the CSV file
+	reserved map[string]bool
 
 	printFlags *printer.PrintFlags
 	genericclioptions.IOStreams
@@ -60,8 +64,13 @@ func newAccountAssignOptions(streams genericclioptions.IOStreams, globalOpts *gl
 func newCmdAccountAssign(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *cobra.Command {
 	ops := newAccountAssignOptions(streams, globalOpts)
 	accountAssignCmd := &cobra.Command{
-		Use:               "assign",
-		Short:             "Assign account to user",
+		Use:   "assign",
+		Short: "Assign account to user",
+		Example: `  # Assign an account to a user
+  osdctl account mgmt assign -p osd-staging-2 -u jdoe
+
+  # Preview the accounts a CSV file of users would be assigned
+  osdctl account mgmt assign -p osd-staging-2 --from-csv users.csv --dry-run`,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(ops.complete(cmd, args))
@@ -73,12 +82,17 @@ func newCmdAccountAssign(streams genericclioptions.IOStreams, globalOpts *global
 	accountAssignCmd.Flags().StringVarP(&ops.username, "username", "u", "", "LDAP username")
 	accountAssignCmd.Flags().StringVarP(&ops.accountID, "account-id", "i", "", "(optional) Specific AWS account ID to assign")
 	accountAssignCmd.Flags().BoolVarP(&ops.iamUser, "iam-user", "I", false, "(optional) Create an AWS IAM user and Access Key")
+	accountAssignCmd.Flags().BoolVar(&ops.dryRun, "dry-run", false, "Print the account which would be assigned without changing anything")
+	accountAssignCmd.Flags().StringVar(&ops.csvFile, "from-csv", "", "Assign an account to the user of every row of a CSV file with the columns username and (optional) account-id, and print the result of every row")
+	accountAssignCmd.MarkFlagsMutuallyExclusive("from-csv", "username")
+	accountAssignCmd.MarkFlagsMutuallyExclusive("from-csv", "account-id")
+	accountAssignCmd.MarkFlagsMutuallyExclusive("from-csv", "iam-user")
 
 	return accountAssignCmd
 }
 
 func (o *accountAssignOptions) complete(cmd *cobra.Command, _ []string) error {
-	if o.username == "" {
+	if o.username == "" && o.csvFile == "" {
 		return cmdutil.UsageErrorf(cmd, "LDAP username was not provided")
 	}
 
@@ -88,21 +102,9 @@ func (o *accountAssignOptions) complete(cmd *cobra.Command, _ []string) error {
 }
 
 func (o *accountAssignOptions) run() error {
-
-	var (
-		accountAssignID string
-		destinationOU   string
-		rootID          string
-	)
-
-	if o.payerAccount == osdStaging1 || os.Getenv(envKeyAWSAccountName) == osdStaging1 {
-		rootID = OSDStaging1RootID
-		destinationOU = OSDStaging1OuID
-	} else if o.payerAccount == osdStaging2 || os.Getenv(envKeyAWSAccountName) == osdStaging2 {
-		rootID = OSDStaging2RootID
-		destinationOU = OSDStaging2OuID
-	} else {
-		return fmt.Errorf("invalid payer account provided")
+	rootID, destinationOU, err := payerAccountOUs(o.payerAccount)
+	if err != nil {
+		return err
 	}
 
 	//Instantiate aws client
@@ -112,24 +114,87 @@ func (o *accountAssignOptions) run() error {
 	}
 
 	o.awsClient = awsClient
+	if o.csvFile != "" {
+		rows, err := readBulkRows(o.csvFile)
+		if err != nil {
+			return err
+		}
+		return o.runBulk(rows, rootID, destinationOU)
+	}
+
+	accountAssignID, err := o.assign(rootID, destinationOU)
+	if err != nil {
+		return err
+	}
+
+	if o.dryRun {
+		if accountAssignID == "" {
+			_, _ = fmt.Fprintf(o.Out, "No untagged account is available, a new account would be created and assigned to %s\n", o.username)
+		} else {
+			_, _ = fmt.Fprintf(o.Out, "Account %s would be assigned to %s\n", accountAssignID, o.username)
+		}
+		return nil
+	}
+
+	resp := assignResponse{
+		Username: o.username,
+		Id:       accountAssignID,
+	}
+
+	err = outputflag.PrintResponse(o.output, resp)
+	if err != nil {
+		fmt.Println("Error while calling PrintResponse(): ", err.Error())
+	}
+
+	// Create an AWS IAM user if iamUser is true
+	if o.iamUser {
+		fmt.Printf("Creating AWS IAM user for account %s...\n\n", accountAssignID)
+		iamOptions := iamOptions{
+			awsAccountID: accountAssignID,
+			awsProfile:   o.payerAccount,
+			awsRegion:    "us-east-1",
+			kerberosUser: o.username,
+			rotate:       false,
+		}
+
+		err = iamOptions.run()
+		if err != nil {
+			return fmt.Errorf("error while creating AWS IAM user: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// assign assigns o.accountID, or an untagged account, or a new account to o.username and returns its ID. With
+// dryRun nothing is changed, and the ID is empty when a new account would be created.
+func (o *accountAssignOptions) assign(rootID string, destinationOU string) (string, error) {
+	var (
+		accountAssignID string
+		err             error
+	)
+
 	// We support passing in an aws account ID to be assigned, or retrieving one for the user.
 	if o.accountID != "" {
 		accountAssignID = o.accountID
+		if o.reserved[accountAssignID] {
+			return "", fmt.Errorf("account %s is already assigned by a previous row", accountAssignID)
+		}
 		// ensure that the account we're assigning is not already owned
 		isOwned, err := isOwned(accountAssignID, &o.awsClient)
 		if err != nil {
-			return err
+			return "", err
 		}
 		if isOwned {
-			return fmt.Errorf("the account you are attempting to assign is already owned, please use the 'unassign' command to unassign the account, or use 'assign' without a specific aws account id to be assigned one at random")
+			return "", fmt.Errorf("the account you are attempting to assign is already owned, please use the 'unassign' command to unassign the account, or use 'assign' without a specific aws account id to be assigned one at random")
 		}
 
 		isSuspended, err := isSuspended(accountAssignID, o.awsClient)
 		if err != nil {
-			return err
+			return "", err
 		}
 		if isSuspended {
-			return fmt.Errorf("the account you are attempting to assign is suspended, please use another account, or use 'assign' without a specific aws account id to be assigned one at random")
+			return "", fmt.Errorf("the account you are attempting to assign is suspended, please use another account, or use 'assign' without a specific aws account id to be assigned one at random")
 		}
 
 	} else {
@@ -139,55 +204,74 @@ func (o *accountAssignOptions) run() error {
 	if err != nil {
 		// If the error returned is not because of a lack of accounts, return the error
 		if err != ErrNoUntaggedAccounts {
-			return err
+			return "", err
+		}
+		if o.dryRun {
+			return "", nil
 		}
 		// otherwise, create a new account
 		seed := time.Now().UnixNano()
 		accountAssignID, err = o.buildAccount(seed)
 
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	err = o.tagAccount(accountAssignID)
-	if err != nil {
-		return err
+	if o.dryRun {
+		return accountAssignID, nil
 	}
 
-	err = o.moveAccount(accountAssignID, destinationOU, rootID)
+	err = o.tagAccount(accountAssignID)
 	if err != nil {
-		return err
-	}
-
-	resp := assignResponse{
-		Username: o.username,
-		Id:       accountAssignID,
+		return "", err
 	}
 
-	err = outputflag.PrintResponse(o.output, resp)
+	err = o.moveAccount(accountAssignID, destinationOU, rootID)
 	if err != nil {
-		fmt.Println("Error while calling PrintResponse(): ", err.Error())
+		return "", err
 	}
+	return accountAssignID, nil
+}
 
-	// Create an AWS IAM user if iamUser is true
-	if o.iamUser {
-		fmt.Printf("Creating AWS IAM user for account %s...\n\n", accountAssignID)
-		iamOptions := iamOptions{
-			awsAccountID: accountAssignID,
-			awsProfile:   o.payerAccount,
-			awsRegion:    "us-east-1",
-			kerberosUser: o.username,
-			rotate:       false,
+// runBulk assigns an account to the user of every row, going on after a failed row, and prints the result of
+// every row
+func (o *accountAssignOptions) runBulk(rows []bulkRow, rootID string, destinationOU string) error {
+	// The accounts picked by the previous rows, which aren't tagged yet in a dry run
+	o.reserved = map[string]bool{}
+	var results []bulkResult
+	for _, row := range rows {
+		result := bulkResult{username: row.username}
+		if row.username == "" {
+			result.accountIDs = []string{row.accountID}
+			result.result = "failed: the username is required"
+			results = append(results, result)
+			continue
 		}
 
-		err = iamOptions.run()
-		if err != nil {
-			return fmt.Errorf("error while creating AWS IAM user: %s", err)
+		o.username, o.accountID = row.username, row.accountID
+		accountAssignID, err := o.assign(rootID, destinationOU)
+		switch {
+		case err != nil:
+			result.result = fmt.Sprintf("failed: %v", err)
+		case o.dryRun && accountAssignID == "":
+			result.result = "would create a new account"
+		case o.dryRun:
+			result.result = "would assign"
+		default:
+			result.result = "assigned"
+		}
+		if accountAssignID != "" {
+			o.reserved[accountAssignID] = true
+			result.accountIDs = []string{accountAssignID}
+		} else if row.accountID != "" {
+			result.accountIDs = []string{row.accountID}
 		}
+		results = append(results, result)
 	}
 
-	return nil
+	printBulkResults(o.Out, results)
+	return bulkError(results, "assign")
 }
 
 var ErrNoUntaggedAccounts = fmt.Errorf("no untagged accounts available")
@@ -220,6 +304,10 @@ func (o *accountAssignOptions) findUntaggedAccount(rootOu string) (string, error
 			// Don't allow the payer account to be assigned to an individual user
 			continue
 		}
+		if o.reserved[*a.Id] {
+			// Already picked for a previous row of a dry run
+			continue
+		}
 
 		isOwned, err := isOwned(*a.Id, &o.awsClient)
 		if err != nil {
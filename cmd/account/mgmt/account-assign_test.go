@@ -1,8 +1,10 @@
 package mgmt
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 
 	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
@@ -329,3 +331,43 @@ func TestMoveAccount(t *testing.T) {
 		t.Errorf("failed to move account")
 	}
 }
+
+func TestRunBulkDryRun(t *testing.T) {
+	mocks := setupDefaultMocks(t)
+	mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+
+	payerAccount, firstAccount, secondAccount := "000000000000", "111111111111", "222222222222"
+	mockAWSClient.EXPECT().ListAccountsForParent(gomock.Any()).Return(&organizations.ListAccountsForParentOutput{
+		Accounts: []organizationTypes.Account{{Id: &payerAccount}, {Id: &firstAccount}, {Id: &secondAccount}},
+	}, nil).Times(2)
+	mockAWSClient.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Account: &payerAccount}, nil).Times(2)
+	// The first account is only checked once: it is reserved for the first row afterwards
+	mockAWSClient.EXPECT().ListTagsForResource(gomock.Any()).Return(&organizations.ListTagsForResourceOutput{}, nil).Times(2)
+	mockAWSClient.EXPECT().DescribeAccount(gomock.Any()).DoAndReturn(func(input *organizations.DescribeAccountInput) (*organizations.DescribeAccountOutput, error) {
+		return &organizations.DescribeAccountOutput{Account: &organizationTypes.Account{Id: input.AccountId, Status: "ACTIVE"}}, nil
+	}).Times(2)
+
+	out := &bytes.Buffer{}
+	o := &accountAssignOptions{awsClient: mockAWSClient, dryRun: true}
+	o.Out = out
+	err := o.runBulk([]bulkRow{{username: "jdoe"}, {username: "asmith"}, {accountID: "333333333333"}}, "root", "ou")
+	if err == nil || err.Error() != "failed to assign 1 of 3 row(s)" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 rows, got:\n%s", out.String())
+	}
+	for i, expected := range [][]string{
+		{"jdoe", firstAccount, "would assign"},
+		{"asmith", secondAccount, "would assign"},
+		{"333333333333", "failed: the username is required"},
+	} {
+		for _, field := range expected {
+			if !strings.Contains(lines[i+1], field) {
+				t.Errorf("expected %q in row %d, got %q", field, i+1, lines[i+1])
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package mgmt
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/printer"
+)
+
+// bulkRow is a row of the CSV file given to --from-csv: the owner of the accounts and optionally a specific
+// account ID, e.g.
+//
+//	username,account-id
+//	jdoe,
+//	asmith,111111111111
+type bulkRow struct {
+	username  string
+	accountID string
+}
+
+// bulkResult is the result of the assignment or unassignment of a row of the CSV file
+type bulkResult struct {
+	username   string
+	accountIDs []string
+	result     string
+}
+
+// readBulkRows reads the rows of the CSV file, skipping the optional header, blank lines and lines starting with #
+func readBulkRows(path string) ([]bulkRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseBulkRows(f)
+}
+
+func parseBulkRows(r io.Reader) ([]bulkRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []bulkRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "username") {
+			continue
+		}
+		if len(record) > 2 {
+			return nil, fmt.Errorf("line %d: expected the columns username and account-id, got %d columns", line, len(record))
+		}
+		row := bulkRow{username: strings.TrimSpace(record[0])}
+		if len(record) == 2 {
+			row.accountID = strings.TrimSpace(record[1])
+		}
+		if row.username == "" && row.accountID == "" {
+			return nil, fmt.Errorf("line %d: neither a username nor an account ID is set", line)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("the CSV file has no rows")
+	}
+	return rows, nil
+}
+
+func printBulkResults(w io.Writer, results []bulkResult) {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"USERNAME", "ACCOUNT ID", "RESULT"})
+	for _, r := range results {
+		accountIDs := strings.Join(r.accountIDs, ",")
+		if accountIDs == "" {
+			accountIDs = "-"
+		}
+		p.AddRow([]string{r.username, accountIDs, r.result})
+	}
+	if err := p.Flush(); err != nil {
+		_, _ = fmt.Fprintf(w, "failed to print the results: %v\n", err)
+	}
+}
+
+// bulkError summarizes the rows which failed
+func bulkError(results []bulkResult, action string) error {
+	var failures int
+	for _, r := range results {
+		if strings.HasPrefix(r.result, "failed") {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("failed to %s %d of %d row(s)", action, failures, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package mgmt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBulkRows(t *testing.T) {
+	rows, err := parseBulkRows(strings.NewReader("username,account-id\n# new hires\njdoe\nasmith, 111111111111\n\n,222222222222\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []bulkRow{
+		{username: "jdoe"},
+		{username: "asmith", accountID: "111111111111"},
+		{accountID: "222222222222"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %+v, got %+v", expected, rows)
+	}
+
+	for input, expectedErr := range map[string]string{
+		"":                      "the CSV file has no rows",
+		"username\n":            "the CSV file has no rows",
+		"jdoe\n,\n":             "line 2: neither a username nor an account ID is set",
+		"jdoe,111111111111,x\n": "line 1: expected the columns username and account-id, got 3 columns",
+	} {
+		if _, err := parseBulkRows(strings.NewReader(input)); err == nil || err.Error() != expectedErr {
+			t.Errorf("parseBulkRows(%q): expected error %q, got %v", input, expectedErr, err)
+		}
+	}
+}
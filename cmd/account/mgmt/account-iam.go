@@ -22,8 +22,6 @@ type iamOptions struct {
 	rotate       bool
 }
 
-var arnPolicy = "arn:aws:iam::aws:policy/AdministratorAccess"
-
 // accountIamCmd implements the accountIam command which creates an IAM user for a given account
 func newCmdAccountIAM(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *cobra.Command {
 	ops := &iamOptions{}
@@ -129,6 +127,7 @@ func (o *iamOptions) run() error {
 	iamUserExist, err := awsprovider.CheckIAMUserExists(impersonateAwsClient, &o.kerberosUser)
 	if !iamUserExist {
 		// Create IAM user
+		arnPolicy := awsprovider.GenerateManagedPolicyARN(partition, "AdministratorAccess")
 		err := awsprovider.CreateIAMUserAndAttachPolicy(impersonateAwsClient, &o.kerberosUser, &arnPolicy)
 		if err != nil {
 			return fmt.Errorf("error Creating the IAM user: %s", err)
@@ -13,6 +13,7 @@ import (
 	resourceGroupsTaggingApiTypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
 	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -22,8 +23,13 @@ import (
 func newCmdAccountUnassign(streams genericclioptions.IOStreams) *cobra.Command {
 	ops := newAccountUnassignOptions(streams)
 	accountUnassignCmd := &cobra.Command{
-		Use:               "unassign",
-		Short:             "Unassign account to user",
+		Use:   "unassign",
+		Short: "Unassign account to user",
+		Example: `  # Unassign the accounts of a user
+  osdctl account mgmt unassign -p osd-staging-2 -u jdoe
+
+  # Preview the accounts of a CSV file of users and accounts which would be unassigned
+  osdctl account mgmt unassign -p osd-staging-2 --from-csv offboarding.csv --dry-run`,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(ops.complete(cmd, args))
@@ -34,6 +40,8 @@ func newCmdAccountUnassign(streams genericclioptions.IOStreams) *cobra.Command {
 	accountUnassignCmd.Flags().StringVarP(&ops.payerAccount, "payer-account", "p", "", "Payer account type")
 	accountUnassignCmd.Flags().StringVarP(&ops.username, "username", "u", "", "LDAP username")
 	accountUnassignCmd.Flags().StringVarP(&ops.accountID, "account-id", "i", "", "Account ID")
+	accountUnassignCmd.Flags().BoolVar(&ops.dryRun, "dry-run", false, "Print the accounts which would be unassigned without changing anything")
+	accountUnassignCmd.Flags().StringVar(&ops.csvFile, "from-csv", "", "Unassign the accounts of every row of a CSV file with the columns username and account-id, one of them set per row, and print the result of every row")
 	return accountUnassignCmd
 }
 
@@ -42,6 +50,8 @@ type accountUnassignOptions struct {
 	username     string
 	payerAccount string
 	accountID    string
+	dryRun       bool
+	csvFile      string
 	printFlags   *printer.PrintFlags
 	genericclioptions.IOStreams
 }
@@ -53,6 +63,12 @@ func newAccountUnassignOptions(streams genericclioptions.IOStreams) *accountUnas
 	}
 }
 func (o *accountUnassignOptions) complete(cmd *cobra.Command, _ []string) error {
+	if o.csvFile != "" {
+		if o.username != "" || o.accountID != "" {
+			return cmdutil.UsageErrorf(cmd, "--from-csv can't be used with a username or an account ID")
+		}
+		return nil
+	}
 	if o.username == "" && o.accountID == "" {
 		return cmdutil.UsageErrorf(cmd, "Please provide either an username or account ID")
 	}
@@ -62,54 +78,33 @@ func (o *accountUnassignOptions) complete(cmd *cobra.Command, _ []string) error
 	return nil
 }
 func (o *accountUnassignOptions) run() error {
-	var (
-		accountUsername      string
-		accountIdList        []string
-		destinationOU        string
-		rootID               string
-		assumedRoleAwsClient awsprovider.Client
-		allUsers             []string
-	)
-
 	// Instantiate Aws client
 	awsClient, err := awsprovider.NewAwsClient(o.payerAccount, "us-east-1", "")
 	if err != nil {
 		return err
 	}
-	if o.payerAccount == osdStaging1 || os.Getenv(envKeyAWSAccountName) == osdStaging1 {
-		rootID = OSDStaging1RootID
-		destinationOU = OSDStaging1OuID
-	} else if o.payerAccount == osdStaging2 || os.Getenv(envKeyAWSAccountName) == osdStaging2 {
-		rootID = OSDStaging2RootID
-		destinationOU = OSDStaging2OuID
-	} else {
-		return fmt.Errorf("invalid payer account provided")
+	rootID, destinationOU, err := payerAccountOUs(o.payerAccount)
+	if err != nil {
+		return err
 	}
 
 	o.awsClient = awsClient
-
-	if o.accountID != "" {
-		// Check aws tag to see if it's a ccs acct, if it's not return name of owner
-		accountUsername, err = o.checkForHiveNameTag(o.accountID)
+	if o.csvFile != "" {
+		rows, err := readBulkRows(o.csvFile)
 		if err != nil {
 			return err
 		}
-
-		accountIdList = append(accountIdList, o.accountID)
+		return o.runBulk(rows, rootID, destinationOU)
 	}
 
-	if o.username != "" {
-		// Check that username doesn't belong to a ccs acct
-		if strings.HasPrefix(o.username, "hive") {
-			return ErrHiveNameProvided
-		}
-
-		accountUsername = o.username
+	accountUsername, accountIdList, err := o.resolveAccounts(o.username, o.accountID)
+	if err != nil {
+		return err
+	}
 
-		accountIdList, err = o.listAccountsFromUser(accountUsername)
-		if err != nil {
-			return err
-		}
+	if o.dryRun {
+		_, _ = fmt.Fprintf(o.Out, "Account(s) %v would be unassigned from %s\n", accountIdList, accountUsername)
+		return nil
 	}
 
 	fmt.Printf("Are you sure you want to unassign account(s) [%v] from %s? [y/n] ", accountIdList, accountUsername)
@@ -123,6 +118,56 @@ func (o *accountUnassignOptions) run() error {
 		os.Exit(0)
 	}
 
+	return o.unassignAccounts(accountIdList, rootID, destinationOU)
+}
+
+// resolveAccounts returns the owner and the accounts to unassign, either the account or the accounts of the user
+func (o *accountUnassignOptions) resolveAccounts(username string, accountID string) (string, []string, error) {
+	var (
+		accountUsername string
+		accountIdList   []string
+		err             error
+	)
+
+	if accountID != "" {
+		// Check aws tag to see if it's a ccs acct, if it's not return name of owner
+		accountUsername, err = o.checkForHiveNameTag(accountID)
+		if err != nil {
+			return "", nil, err
+		}
+
+		accountIdList = append(accountIdList, accountID)
+	}
+
+	if username != "" {
+		// Check that username doesn't belong to a ccs acct
+		if strings.HasPrefix(username, "hive") {
+			return "", nil, ErrHiveNameProvided
+		}
+
+		accountUsername = username
+
+		accountIdList, err = o.listAccountsFromUser(accountUsername)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return accountUsername, accountIdList, nil
+}
+
+// unassignAccounts untags the accounts, moves them back to the root OU and deletes the IAM roles, policies and
+// users created in them
+func (o *accountUnassignOptions) unassignAccounts(accountIdList []string, rootID string, destinationOU string) error {
+	var (
+		assumedRoleAwsClient awsprovider.Client
+		allUsers             []string
+		err                  error
+	)
+
+	// The clients of the accounts replace the client of the payer account while unassigning them
+	payerAwsClient := o.awsClient
+	defer func() { o.awsClient = payerAwsClient }()
+
 	// loop through accounts list and untag and move them back into root OU
 	for _, id := range accountIdList {
 
@@ -204,9 +249,69 @@ func (o *accountUnassignOptions) run() error {
 	return nil
 }
 
-func (o *accountUnassignOptions) assumeRoleForAccount(accountId string) (awsprovider.Client, error) {
+// runBulk unassigns the accounts of every row after a single confirmation, going on after a failed row, and prints
+// the result of every row
+func (o *accountUnassignOptions) runBulk(rows []bulkRow, rootID string, destinationOU string) error {
+	var results []bulkResult
+	var resolved [][]string
+	for _, row := range rows {
+		result := bulkResult{username: row.username}
+		if row.username != "" && row.accountID != "" {
+			result.accountIDs = []string{row.accountID}
+			result.result = "failed: set either the username or the account ID of the row, not both"
+			results, resolved = append(results, result), append(resolved, nil)
+			continue
+		}
+		accountUsername, accountIdList, err := o.resolveAccounts(row.username, row.accountID)
+		result.username, result.accountIDs = accountUsername, accountIdList
+		if err != nil {
+			if result.username == "" {
+				result.username = "-"
+			}
+			if row.accountID != "" {
+				result.accountIDs = []string{row.accountID}
+			}
+			result.result = fmt.Sprintf("failed: %v", err)
+			accountIdList = nil
+		} else if o.dryRun {
+			result.result = "would unassign"
+		} else {
+			result.result = "to unassign"
+		}
+		results, resolved = append(results, result), append(resolved, accountIdList)
+	}
 
-	roleArn := fmt.Sprintf("arn:aws:iam::%s:role/OrganizationAccountAccessRole", accountId)
+	printBulkResults(o.Out, results)
+	if o.dryRun {
+		return bulkError(results, "unassign")
+	}
+	if !prompt.ConfirmPrompt() {
+		_, _ = fmt.Fprintln(o.Out, "Nothing was unassigned.")
+		return nil
+	}
+
+	for i := range results {
+		if resolved[i] == nil {
+			continue
+		}
+		if err := o.unassignAccounts(resolved[i], rootID, destinationOU); err != nil {
+			results[i].result = fmt.Sprintf("failed: %v", err)
+		} else {
+			results[i].result = "unassigned"
+		}
+	}
+	_, _ = fmt.Fprintln(o.Out)
+	printBulkResults(o.Out, results)
+	return bulkError(results, "unassign")
+}
+
+func (o *accountUnassignOptions) assumeRoleForAccount(accountId string) (awsprovider.Client, error) {
+	// The member accounts are in the partition of the payer account
+	partition, err := awsprovider.GetAwsPartition(o.awsClient)
+	if err != nil {
+		return nil, err
+	}
+	roleArn := awsprovider.GenerateRoleARNForPartition(partition, accountId, "OrganizationAccountAccessRole")
 
 	input := &sts.AssumeRoleInput{
 		RoleArn:         &roleArn,
@@ -222,7 +327,7 @@ func (o *accountUnassignOptions) assumeRoleForAccount(accountId string) (awsprov
 		AccessKeyID:     *result.Credentials.AccessKeyId,
 		SecretAccessKey: *result.Credentials.SecretAccessKey,
 		SessionToken:    *result.Credentials.SessionToken,
-		Region:          "us-east-1",
+		Region:          awsprovider.GetDefaultRegion(partition),
 	}
 
 	newAWSClient, err := awsprovider.NewAwsClientWithInput(newAwsClientInput)
@@ -40,7 +40,13 @@ func TestAssumeRoleForAccount(t *testing.T) {
 		},
 	}
 
-	mockAWSClient.EXPECT().AssumeRole(gomock.Any()).Return(
+	mockAWSClient.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+		Arn: awsSdk.String("arn:aws-us-gov:iam::222222222222:user/payer"),
+	}, nil)
+	mockAWSClient.EXPECT().AssumeRole(&sts.AssumeRoleInput{
+		RoleArn:         awsSdk.String("arn:aws-us-gov:iam::111111111111:role/OrganizationAccountAccessRole"),
+		RoleSessionName: awsSdk.String("osdctl-account-unassignment"),
+	}).Return(
 		awsAssumeRoleOutput,
 		nil,
 	)
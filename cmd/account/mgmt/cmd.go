@@ -2,6 +2,7 @@ package mgmt
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/openshift/osdctl/internal/utils/globalflags"
 	"github.com/spf13/cobra"
@@ -31,6 +32,17 @@ func NewCmdMgmt(streams genericclioptions.IOStreams, globalOpts *globalflags.Glo
 	return mgmtCmd
 }
 
+// payerAccountOUs returns the root and the OU of the accounts assigned to users in the payer account
+func payerAccountOUs(payerAccount string) (rootID string, ouID string, err error) {
+	switch {
+	case payerAccount == osdStaging1 || os.Getenv(envKeyAWSAccountName) == osdStaging1:
+		return OSDStaging1RootID, OSDStaging1OuID, nil
+	case payerAccount == osdStaging2 || os.Getenv(envKeyAWSAccountName) == osdStaging2:
+		return OSDStaging2RootID, OSDStaging2OuID, nil
+	}
+	return "", "", fmt.Errorf("invalid payer account provided")
+}
+
 func help(cmd *cobra.Command, _ []string) {
 	err := cmd.Help()
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/openshift/osdctl/cmd/common"
 	"github.com/openshift/osdctl/pkg/k8s"
 	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -209,7 +210,7 @@ func (o *resetOptions) getAwsClientFromSecret(secretName string, namespace strin
 	awsClient, err := awsprovider.NewAwsClientWithInput(&awsprovider.ClientInput{
 		AccessKeyID:     string(accessKeyID),
 		SecretAccessKey: string(secretkeyID),
-		Region:          "us-east-1",
+		Region:          utils.GetDefaultAWSRegion(),
 	})
 	if err != nil {
 		fmt.Printf(" error occurred when calling NewAwsClientWithInput")
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 	ccov1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
@@ -137,7 +138,7 @@ func (o *rotateSecretOptions) run() error {
 		return fmt.Errorf("no label on Account CR for IAM User")
 	}
 
-	awsSetupClient, err := awsprovider.NewAwsClient(o.profile, "us-east-1", "")
+	awsSetupClient, err := awsprovider.NewAwsClient(o.profile, utils.GetDefaultAWSRegion(), "")
 	if err != nil {
 		return err
 	}
@@ -148,8 +149,14 @@ func (o *rotateSecretOptions) run() error {
 	}
 	roleSessionName := getSessionNameFromUserId(*callerIdentityOutput.UserId)
 
+	// Use the partition of the caller so role ARNs are valid in GovCloud as well
+	callerIdentityArn, err := arn.Parse(*callerIdentityOutput.Arn)
+	if err != nil {
+		return err
+	}
+
 	// Build the final AWS client via role chaining
-	awsClient, err := o.buildAssumedRoleClient(ctx, kubeCli, awsSetupClient, account, accountID, accountIDSuffixLabel, &roleSessionName)
+	awsClient, err := o.buildAssumedRoleClient(ctx, kubeCli, awsSetupClient, account, accountID, callerIdentityArn.Partition, accountIDSuffixLabel, &roleSessionName)
 	if err != nil {
 		return err
 	}
@@ -177,6 +184,7 @@ func (o *rotateSecretOptions) run() error {
 		UpdateCcsCreds:          o.updateCcsCreds,
 		DryRun:                  o.dryRun,
 		AwsClient:               awsClient,
+		Partition:               callerIdentityArn.Partition,
 		HiveKubeClient:          kubeCli,
 		ManagedClusterClient:    managedClient,
 		Out:                     os.Stdout,
@@ -191,10 +199,13 @@ func (o *rotateSecretOptions) buildAssumedRoleClient(
 	awsSetupClient awsprovider.Client,
 	account *awsv1alpha1.Account,
 	accountID string,
+	partition string,
 	accountIDSuffixLabel string,
 	roleSessionName *string,
 ) (awsprovider.Client, error) {
 
+	region := awsprovider.GetDefaultRegion(partition)
+
 	var credAccessKeyId, credSecretAccessKey, credSessionToken *string
 
 	if account.Spec.BYOC {
@@ -219,7 +230,7 @@ func (o *rotateSecretOptions) buildAssumedRoleClient(
 			AccessKeyID:     *srepRoleCredentials.AccessKeyId,
 			SecretAccessKey: *srepRoleCredentials.SecretAccessKey,
 			SessionToken:    *srepRoleCredentials.SessionToken,
-			Region:          "us-east-1",
+			Region:          region,
 		})
 		if err != nil {
 			return nil, err
@@ -239,13 +250,13 @@ func (o *rotateSecretOptions) buildAssumedRoleClient(
 			AccessKeyID:     *jumpRoleCreds.AccessKeyId,
 			SecretAccessKey: *jumpRoleCreds.SecretAccessKey,
 			SessionToken:    *jumpRoleCreds.SessionToken,
-			Region:          "us-east-1",
+			Region:          region,
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		roleArn := awsSdk.String(fmt.Sprintf("arn:aws:iam::%s:role/%s", accountID, "ManagedOpenShift-Support-"+accountIDSuffixLabel))
+		roleArn := awsSdk.String(awsprovider.GenerateRoleARNForPartition(partition, accountID, "ManagedOpenShift-Support-"+accountIDSuffixLabel))
 		credentials, err := awsprovider.GetAssumeRoleCredentials(jumpRoleClient, o.awsAccountTimeout, roleSessionName, roleArn)
 		if err != nil {
 			return nil, err
@@ -254,7 +265,7 @@ func (o *rotateSecretOptions) buildAssumedRoleClient(
 		credSecretAccessKey = credentials.SecretAccessKey
 		credSessionToken = credentials.SessionToken
 	} else {
-		roleArn := awsSdk.String(fmt.Sprintf("arn:aws:iam::%s:role/%s", accountID, awsv1alpha1.AccountOperatorIAMRole))
+		roleArn := awsSdk.String(awsprovider.GenerateRoleARNForPartition(partition, accountID, awsv1alpha1.AccountOperatorIAMRole))
 		credentials, err := awsprovider.GetAssumeRoleCredentials(awsSetupClient, o.awsAccountTimeout, roleSessionName, roleArn)
 		if err != nil {
 			return nil, err
@@ -268,7 +279,7 @@ func (o *rotateSecretOptions) buildAssumedRoleClient(
 		AccessKeyID:     *credAccessKeyId,
 		SecretAccessKey: *credSecretAccessKey,
 		SessionToken:    *credSessionToken,
-		Region:          "us-east-1",
+		Region:          region,
 	})
 }
 
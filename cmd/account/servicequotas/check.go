@@ -0,0 +1,296 @@
+package servicequotas
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// bootstrapVCPUs is the vCPU count of the temporary bootstrap instance used during install
+	bootstrapVCPUs = 4
+
+	checkExample = `  # Check whether the account backing an existing cluster can host a 9 node multi-AZ cluster
+  osdctl account servicequotas check --cluster-id ${CLUSTER_ID} --compute-nodes 9 --multi-az
+
+  # Check an account directly with an AWS profile before provisioning
+  osdctl account servicequotas check --profile my-profile --region us-east-1 --compute-nodes 4 --compute-instance-type m5.2xlarge`
+)
+
+// vcpuQuota is the "Running On-Demand instances" vCPU quota the instances of some families are counted against
+type vcpuQuota struct {
+	families []string
+	code     string
+	name     string
+}
+
+// standardVCPUQuota is the quota of the standard instance families, the bootstrap instance is counted against it
+var standardVCPUQuota = vcpuQuota{families: []string{"a", "c", "d", "h", "i", "m", "r", "t", "z"}, code: "L-1216C47A", name: "Running On-Demand Standard instances (vCPUs)"}
+
+// vcpuQuotas are the vCPU quotas of the instance families, the families being matched by prefix the quotas of the
+// families which start like a standard one (dl, hpc, inf, trn) come first
+var vcpuQuotas = []vcpuQuota{
+	{families: []string{"dl"}, code: "L-6E869C2A", name: "Running On-Demand DL instances (vCPUs)"},
+	{families: []string{"hpc"}, code: "L-F7808C92", name: "Running On-Demand HPC instances (vCPUs)"},
+	{families: []string{"inf"}, code: "L-1945791B", name: "Running On-Demand Inf instances (vCPUs)"},
+	{families: []string{"trn"}, code: "L-2C3B7624", name: "Running On-Demand Trn instances (vCPUs)"},
+	standardVCPUQuota,
+	{families: []string{"f"}, code: "L-74FC7D96", name: "Running On-Demand F instances (vCPUs)"},
+	{families: []string{"g", "vt"}, code: "L-DB2E81BA", name: "Running On-Demand G and VT instances (vCPUs)"},
+	{families: []string{"p"}, code: "L-417A185B", name: "Running On-Demand P instances (vCPUs)"},
+	{families: []string{"u"}, code: "L-43DA4232", name: "Running On-Demand High Memory instances (vCPUs)"},
+	{families: []string{"x"}, code: "L-7295265B", name: "Running On-Demand X instances (vCPUs)"},
+}
+
+// quotaRequirement describes the minimum value a service quota needs for the cluster to install
+type quotaRequirement struct {
+	ServiceCode string
+	QuotaCode   string
+	Name        string
+	Required    float64
+}
+
+// clusterSize describes the cluster that is going to be provisioned in the account
+type clusterSize struct {
+	computeNodes             int
+	computeInstanceType      string
+	controlPlaneInstanceType string
+	infraInstanceType        string
+	multiAZ                  bool
+}
+
+// checkOptions defines the struct for running the servicequotas check command
+type checkOptions struct {
+	clusterID  string
+	awsProfile string
+	region     string
+	size       clusterSize
+
+	out       io.Writer
+	awsClient awsprovider.Client
+}
+
+// newCmdCheck implements servicequotas check
+func newCmdCheck() *cobra.Command {
+	ops := &checkOptions{out: os.Stdout}
+	checkCmd := &cobra.Command{
+		Use:               "check",
+		Short:             "Check AWS service-quotas against the requirements of a cluster install",
+		Long:              "Compares the service quotas of the target AWS account against the requirements of the requested cluster size and prints the quota increases that must be requested before provisioning.",
+		Example:           checkExample,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ops.complete(); err != nil {
+				return err
+			}
+			return ops.run()
+		},
+	}
+
+	checkCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID whose AWS account should be checked")
+	checkCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
+	checkCmd.Flags().StringVarP(&ops.region, "region", "r", "", "AWS region to check. Required when --cluster-id is not provided")
+	checkCmd.Flags().IntVar(&ops.size.computeNodes, "compute-nodes", 2, "Number of compute nodes of the cluster")
+	checkCmd.Flags().StringVar(&ops.size.computeInstanceType, "compute-instance-type", "m5.xlarge", "Instance type of the compute nodes")
+	checkCmd.Flags().StringVar(&ops.size.controlPlaneInstanceType, "control-plane-instance-type", "m5.2xlarge", "Instance type of the control plane nodes")
+	checkCmd.Flags().StringVar(&ops.size.infraInstanceType, "infra-instance-type", "r5.xlarge", "Instance type of the infra nodes")
+	checkCmd.Flags().BoolVar(&ops.size.multiAZ, "multi-az", false, "Whether the cluster is deployed across multiple availability zones")
+
+	return checkCmd
+}
+
+func (o *checkOptions) complete() error {
+	if o.clusterID == "" && o.region == "" {
+		return errors.New("either --cluster-id or --region must be provided")
+	}
+	if o.size.computeNodes < 0 {
+		return errors.New("--compute-nodes cannot be negative")
+	}
+	return nil
+}
+
+func (o *checkOptions) run() error {
+	if o.awsClient == nil {
+		var err error
+		if o.clusterID != "" {
+			o.awsClient, err = osdCloud.GenerateAWSClientForCluster(o.awsProfile, o.clusterID)
+		} else {
+			o.awsClient, err = awsprovider.NewAwsClient(o.awsProfile, o.region, "")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	vcpus, err := o.getInstanceTypeVCPUs()
+	if err != nil {
+		return err
+	}
+
+	requirements, err := requiredQuotas(o.size, vcpus)
+	if err != nil {
+		return err
+	}
+
+	p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	p.AddRow([]string{"SERVICE", "QUOTA CODE", "NAME", "REQUIRED", "CURRENT", "STATUS"})
+
+	var increases []quotaRequirement
+	quotasByService := map[string]map[string]float64{}
+	for _, req := range requirements {
+		if _, ok := quotasByService[req.ServiceCode]; !ok {
+			quotasByService[req.ServiceCode], err = o.listQuotaValues(req.ServiceCode)
+			if err != nil {
+				return err
+			}
+		}
+
+		current, found := quotasByService[req.ServiceCode][req.QuotaCode]
+		status := "OK"
+		currentStr := fmt.Sprintf("%.0f", current)
+		if !found {
+			status = "UNKNOWN"
+			currentStr = "-"
+		} else if current < req.Required {
+			status = "INCREASE REQUIRED"
+			increases = append(increases, req)
+		}
+		p.AddRow([]string{req.ServiceCode, req.QuotaCode, req.Name, fmt.Sprintf("%.0f", req.Required), currentStr, status})
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	if len(increases) == 0 {
+		_, _ = fmt.Fprintln(o.out, "\nAll checked service quotas satisfy the requested cluster size.")
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(o.out, "\nThe following quota increases must be requested before provisioning:")
+	for _, req := range increases {
+		_, _ = fmt.Fprintf(o.out, "  - %s (%s/%s): at least %.0f\n", req.Name, req.ServiceCode, req.QuotaCode, req.Required)
+	}
+	return nil
+}
+
+// getInstanceTypeVCPUs returns the default vCPU count of every instance type used by the cluster
+func (o *checkOptions) getInstanceTypeVCPUs() (map[string]int32, error) {
+	var instanceTypes []ec2types.InstanceType
+	seen := map[string]bool{}
+	for _, t := range []string{o.size.computeInstanceType, o.size.controlPlaneInstanceType, o.size.infraInstanceType} {
+		if !seen[t] {
+			seen[t] = true
+			instanceTypes = append(instanceTypes, ec2types.InstanceType(t))
+		}
+	}
+
+	out, err := o.awsClient.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{InstanceTypes: instanceTypes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instance types: %w", err)
+	}
+
+	vcpus := map[string]int32{}
+	for _, info := range out.InstanceTypes {
+		if info.VCpuInfo != nil && info.VCpuInfo.DefaultVCpus != nil {
+			vcpus[string(info.InstanceType)] = *info.VCpuInfo.DefaultVCpus
+		}
+	}
+	return vcpus, nil
+}
+
+// listQuotaValues returns the applied quota values of a service keyed by quota code
+func (o *checkOptions) listQuotaValues(serviceCode string) (map[string]float64, error) {
+	values := map[string]float64{}
+	input := &servicequotas.ListServiceQuotasInput{ServiceCode: &serviceCode}
+	for {
+		result, err := o.awsClient.ListServiceQuotas(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, quota := range result.Quotas {
+			if quota.QuotaCode != nil && quota.Value != nil {
+				values[*quota.QuotaCode] = *quota.Value
+			}
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	return values, nil
+}
+
+// requiredQuotas computes the minimum service quotas needed to install a cluster of the given size
+func requiredQuotas(size clusterSize, vcpus map[string]int32) ([]quotaRequirement, error) {
+	azCount := 1
+	infraNodes := 2
+	if size.multiAZ {
+		azCount = 3
+		infraNodes = 3
+	}
+
+	nodes := []struct {
+		instanceType string
+		count        int
+	}{
+		{size.controlPlaneInstanceType, 3},
+		{size.infraInstanceType, infraNodes},
+		{size.computeInstanceType, size.computeNodes},
+	}
+
+	requiredVCPUs := map[string]int{standardVCPUQuota.code: bootstrapVCPUs}
+	for _, n := range nodes {
+		v, ok := vcpus[n.instanceType]
+		if !ok {
+			return nil, fmt.Errorf("unable to determine vCPU count for instance type %s", n.instanceType)
+		}
+		quota, ok := instanceTypeVCPUQuota(n.instanceType)
+		if !ok {
+			return nil, fmt.Errorf("instance type %s doesn't belong to an instance family with a known vCPU quota and is not supported by this check", n.instanceType)
+		}
+		requiredVCPUs[quota.code] += int(v) * n.count
+	}
+
+	var requirements []quotaRequirement
+	for _, quota := range vcpuQuotas {
+		if required, ok := requiredVCPUs[quota.code]; ok {
+			requirements = append(requirements, quotaRequirement{ServiceCode: "ec2", QuotaCode: quota.code, Name: quota.name, Required: float64(required)})
+		}
+	}
+	return append(requirements, []quotaRequirement{
+		{ServiceCode: "ec2", QuotaCode: "L-0263D0A3", Name: "EC2-VPC Elastic IPs", Required: float64(azCount)},
+		{ServiceCode: "vpc", QuotaCode: "L-F678F1CE", Name: "VPCs per Region", Required: 1},
+		{ServiceCode: "vpc", QuotaCode: "L-FE5A380F", Name: "NAT gateways per Availability Zone", Required: 1},
+		{ServiceCode: "vpc", QuotaCode: "L-A4707A72", Name: "Internet gateways per Region", Required: 1},
+		{ServiceCode: "elasticloadbalancing", QuotaCode: "L-E9E9831D", Name: "Classic Load Balancers per Region", Required: 1},
+		{ServiceCode: "elasticloadbalancing", QuotaCode: "L-69A177A2", Name: "Network Load Balancers per Region", Required: 2},
+	}...), nil
+}
+
+// instanceTypeVCPUQuota returns the vCPU quota the instance type is counted against
+func instanceTypeVCPUQuota(instanceType string) (vcpuQuota, bool) {
+	family := strings.ToLower(strings.SplitN(instanceType, ".", 2)[0])
+	// Mac instances run on dedicated hosts, they aren't counted against a vCPU quota
+	if family == "" || strings.HasPrefix(family, "mac") {
+		return vcpuQuota{}, false
+	}
+	for _, quota := range vcpuQuotas {
+		for _, f := range quota.families {
+			if strings.HasPrefix(family, f) {
+				return quota, true
+			}
+		}
+	}
+	return vcpuQuota{}, false
+}
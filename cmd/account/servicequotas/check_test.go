@@ -0,0 +1,142 @@
+package servicequotas
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	sqtypes "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequiredQuotas(t *testing.T) {
+	vcpus := map[string]int32{"m5.xlarge": 4, "m5.2xlarge": 8, "r5.xlarge": 4, "p3.2xlarge": 8, "inf1.2xlarge": 8, "mac1.metal": 12}
+
+	tests := []struct {
+		name          string
+		size          clusterSize
+		expectedVCPUs float64
+		expectedEIPs  float64
+		// expectedOther are the vCPUs required of the quotas of the non standard instance families, by quota code
+		expectedOther map[string]float64
+		expectErr     bool
+	}{
+		{
+			name:          "single AZ default size",
+			size:          clusterSize{computeNodes: 2, computeInstanceType: "m5.xlarge", controlPlaneInstanceType: "m5.2xlarge", infraInstanceType: "r5.xlarge"},
+			expectedVCPUs: 4 + 3*8 + 2*4 + 2*4,
+			expectedEIPs:  1,
+		},
+		{
+			name:          "multi AZ",
+			size:          clusterSize{computeNodes: 9, computeInstanceType: "m5.2xlarge", controlPlaneInstanceType: "m5.2xlarge", infraInstanceType: "r5.xlarge", multiAZ: true},
+			expectedVCPUs: 4 + 3*8 + 3*4 + 9*8,
+			expectedEIPs:  3,
+		},
+		{
+			name:      "unknown instance type",
+			size:      clusterSize{computeNodes: 2, computeInstanceType: "m5.unknown", controlPlaneInstanceType: "m5.2xlarge", infraInstanceType: "r5.xlarge"},
+			expectErr: true,
+		},
+		{
+			name:          "non standard instance type",
+			size:          clusterSize{computeNodes: 2, computeInstanceType: "p3.2xlarge", controlPlaneInstanceType: "m5.2xlarge", infraInstanceType: "r5.xlarge"},
+			expectedVCPUs: 4 + 3*8 + 2*4,
+			expectedEIPs:  1,
+			expectedOther: map[string]float64{"L-417A185B": 2 * 8},
+		},
+		{
+			name:          "non standard instance family starting like a standard one",
+			size:          clusterSize{computeNodes: 3, computeInstanceType: "inf1.2xlarge", controlPlaneInstanceType: "m5.2xlarge", infraInstanceType: "r5.xlarge"},
+			expectedVCPUs: 4 + 3*8 + 2*4,
+			expectedEIPs:  1,
+			expectedOther: map[string]float64{"L-1945791B": 3 * 8},
+		},
+		{
+			name:      "instance family without vCPU quota",
+			size:      clusterSize{computeNodes: 2, computeInstanceType: "mac1.metal", controlPlaneInstanceType: "m5.2xlarge", infraInstanceType: "r5.xlarge"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, err := requiredQuotas(tt.size, vcpus)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			other := map[string]float64{}
+			for _, r := range reqs {
+				switch r.QuotaCode {
+				case "L-1216C47A":
+					assert.Equal(t, tt.expectedVCPUs, r.Required)
+				case "L-0263D0A3":
+					assert.Equal(t, tt.expectedEIPs, r.Required)
+				default:
+					if strings.HasPrefix(r.Name, "Running On-Demand") {
+						other[r.QuotaCode] = r.Required
+					}
+				}
+			}
+			if tt.expectedOther == nil {
+				assert.Empty(t, other)
+			} else {
+				assert.Equal(t, tt.expectedOther, other)
+			}
+		})
+	}
+}
+
+func TestCheckRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAWSClient := mock.NewMockClient(ctrl)
+
+	mockAWSClient.EXPECT().DescribeInstanceTypes(gomock.Any()).Return(&ec2.DescribeInstanceTypesOutput{
+		InstanceTypes: []ec2types.InstanceTypeInfo{
+			{InstanceType: "m5.xlarge", VCpuInfo: &ec2types.VCpuInfo{DefaultVCpus: awsSdk.Int32(4)}},
+			{InstanceType: "m5.2xlarge", VCpuInfo: &ec2types.VCpuInfo{DefaultVCpus: awsSdk.Int32(8)}},
+			{InstanceType: "r5.xlarge", VCpuInfo: &ec2types.VCpuInfo{DefaultVCpus: awsSdk.Int32(4)}},
+		},
+	}, nil)
+
+	quota := func(code string, value float64) sqtypes.ServiceQuota {
+		return sqtypes.ServiceQuota{QuotaCode: awsSdk.String(code), Value: awsSdk.Float64(value)}
+	}
+	mockAWSClient.EXPECT().ListServiceQuotas(gomock.Any()).DoAndReturn(
+		func(input *servicequotas.ListServiceQuotasInput) (*servicequotas.ListServiceQuotasOutput, error) {
+			switch *input.ServiceCode {
+			case "ec2":
+				return &servicequotas.ListServiceQuotasOutput{Quotas: []sqtypes.ServiceQuota{quota("L-1216C47A", 32), quota("L-0263D0A3", 5)}}, nil
+			case "vpc":
+				return &servicequotas.ListServiceQuotasOutput{Quotas: []sqtypes.ServiceQuota{quota("L-F678F1CE", 5), quota("L-FE5A380F", 5), quota("L-A4707A72", 5)}}, nil
+			default:
+				return &servicequotas.ListServiceQuotasOutput{Quotas: []sqtypes.ServiceQuota{quota("L-E9E9831D", 20), quota("L-69A177A2", 50)}}, nil
+			}
+		}).Times(3)
+
+	out := &bytes.Buffer{}
+	o := &checkOptions{
+		region:    "us-east-1",
+		size:      clusterSize{computeNodes: 2, computeInstanceType: "m5.xlarge", controlPlaneInstanceType: "m5.2xlarge", infraInstanceType: "r5.xlarge"},
+		out:       out,
+		awsClient: mockAWSClient,
+	}
+
+	assert.NoError(t, o.complete())
+	assert.NoError(t, o.run())
+	assert.Contains(t, out.String(), "INCREASE REQUIRED")
+	assert.Contains(t, out.String(), "Running On-Demand Standard instances (vCPUs) (ec2/L-1216C47A): at least 44")
+}
+
+func TestCheckComplete(t *testing.T) {
+	assert.Error(t, (&checkOptions{}).complete())
+	assert.NoError(t, (&checkOptions{clusterID: "abc"}).complete())
+}
@@ -18,6 +18,7 @@ func NewCmdServiceQuotas(streams genericclioptions.IOStreams) *cobra.Command {
 	}
 
 	baseCmd.AddCommand(newCmdDescribe())
+	baseCmd.AddCommand(newCmdCheck())
 
 	return baseCmd
 }
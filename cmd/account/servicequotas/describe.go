@@ -29,7 +29,9 @@ func newCmdDescribe() *cobra.Command {
 
 	describeCmd.Flags().StringVarP(&ops.queryServiceCode, "service-code", "", "ec2", "Query for ServiceCode")
 	describeCmd.Flags().StringVarP(&ops.queryQuotaCode, "quota-code", "q", "L-1216C47A", "Query for QuotaCode")
-	describeCmd.Flags().StringVarP(&ops.clusterID, "clusterID", "C", "", "Cluster ID")
+	describeCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	describeCmd.Flags().StringVar(&ops.clusterID, "clusterID", "", "Cluster ID")
+	_ = describeCmd.Flags().MarkDeprecated("clusterID", "use --cluster-id instead")
 	describeCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
 	describeCmd.Flags().BoolVarP(&ops.verbose, "verbose", "", false, "Verbose output")
 
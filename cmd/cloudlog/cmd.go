@@ -0,0 +1,20 @@
+package cloudlog
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCloudlogCmd represents the GCP Cloud Audit Logs command
+func NewCloudlogCmd() *cobra.Command {
+	cloudlogCmd := &cobra.Command{
+		Use:   "cloudlog",
+		Short: "GCP Cloud Audit Logs related utilities",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cloudlogCmd.AddCommand(newCmdWriteEvents())
+
+	return cloudlogCmd
+}
@@ -0,0 +1,112 @@
+package cloudlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	logging "google.golang.org/api/logging/v2"
+)
+
+const (
+	activityLogID   = "cloudaudit.googleapis.com%2Factivity"
+	dataAccessLogID = "cloudaudit.googleapis.com%2Fdata_access"
+)
+
+// AuditEvent represents the relevant fields extracted from a GCP Cloud Audit Log entry.
+type AuditEvent struct {
+	InsertID     string
+	Time         time.Time
+	Principal    string
+	Method       string
+	Service      string
+	ResourceName string
+	ErrorCode    int64
+	ErrorMessage string
+	Raw          json.RawMessage
+}
+
+// auditLogPayload mirrors the subset of google.cloud.audit.AuditLog used by osdctl.
+type auditLogPayload struct {
+	ServiceName        string `json:"serviceName"`
+	MethodName         string `json:"methodName"`
+	ResourceName       string `json:"resourceName"`
+	AuthenticationInfo struct {
+		PrincipalEmail string `json:"principalEmail"`
+	} `json:"authenticationInfo"`
+	Status struct {
+		Code    int64  `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+// BuildFilter generates a Cloud Logging query filter for the audit logs of the given project.
+// When writeOnly is set only the Admin Activity log is queried, which holds every
+// call that modifies configuration or metadata of a resource.
+func BuildFilter(projectID string, startTime, endTime time.Time, writeOnly bool, principals []string) string {
+	var clauses []string
+
+	if writeOnly {
+		clauses = append(clauses, fmt.Sprintf(`logName="projects/%s/logs/%s"`, projectID, activityLogID))
+	} else {
+		clauses = append(clauses, fmt.Sprintf(`(logName="projects/%s/logs/%s" OR logName="projects/%s/logs/%s")`,
+			projectID, activityLogID, projectID, dataAccessLogID))
+	}
+
+	clauses = append(clauses,
+		fmt.Sprintf(`timestamp>="%s"`, startTime.UTC().Format(time.RFC3339)),
+		fmt.Sprintf(`timestamp<="%s"`, endTime.UTC().Format(time.RFC3339)),
+	)
+
+	if len(principals) > 0 {
+		quoted := make([]string, 0, len(principals))
+		for _, p := range principals {
+			quoted = append(quoted, fmt.Sprintf(`protoPayload.authenticationInfo.principalEmail="%s"`, p))
+		}
+		clauses = append(clauses, "("+strings.Join(quoted, " OR ")+")")
+	}
+
+	return strings.Join(clauses, " AND ")
+}
+
+// ParseEntry converts a Cloud Logging entry into an AuditEvent.
+func ParseEntry(entry *logging.LogEntry) (AuditEvent, error) {
+	event := AuditEvent{
+		InsertID: entry.InsertId,
+		Raw:      json.RawMessage(entry.ProtoPayload),
+	}
+
+	if entry.Timestamp != "" {
+		t, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil {
+			return event, fmt.Errorf("failed to parse timestamp %q: %w", entry.Timestamp, err)
+		}
+		event.Time = t
+	}
+
+	if len(entry.ProtoPayload) == 0 {
+		return event, nil
+	}
+
+	var payload auditLogPayload
+	if err := json.Unmarshal(entry.ProtoPayload, &payload); err != nil {
+		return event, fmt.Errorf("failed to parse audit log payload: %w", err)
+	}
+
+	event.Principal = payload.AuthenticationInfo.PrincipalEmail
+	event.Method = payload.MethodName
+	event.Service = payload.ServiceName
+	event.ResourceName = payload.ResourceName
+	event.ErrorCode = payload.Status.Code
+	event.ErrorMessage = payload.Status.Message
+
+	return event, nil
+}
+
+// generateLink generates a hyperlink to the log entry in the GCP Logs Explorer.
+func generateLink(projectID string, event AuditEvent) string {
+	query := url.PathEscape(fmt.Sprintf(`insertId="%s"`, event.InsertID))
+	return fmt.Sprintf("https://console.cloud.google.com/logs/query;query=%s?project=%s", query, projectID)
+}
@@ -0,0 +1,65 @@
+package cloudlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	logging "google.golang.org/api/logging/v2"
+)
+
+func TestBuildFilter(t *testing.T) {
+	start := time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 7, 15, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		writeOnly  bool
+		principals []string
+		expected   string
+	}{
+		{
+			name:      "write only",
+			writeOnly: true,
+			expected:  `logName="projects/my-project/logs/cloudaudit.googleapis.com%2Factivity" AND timestamp>="2025-07-15T09:00:00Z" AND timestamp<="2025-07-15T17:00:00Z"`,
+		},
+		{
+			name:      "include data access",
+			writeOnly: false,
+			expected:  `(logName="projects/my-project/logs/cloudaudit.googleapis.com%2Factivity" OR logName="projects/my-project/logs/cloudaudit.googleapis.com%2Fdata_access") AND timestamp>="2025-07-15T09:00:00Z" AND timestamp<="2025-07-15T17:00:00Z"`,
+		},
+		{
+			name:       "principals",
+			writeOnly:  true,
+			principals: []string{"a@example.com", "b@example.com"},
+			expected:   `logName="projects/my-project/logs/cloudaudit.googleapis.com%2Factivity" AND timestamp>="2025-07-15T09:00:00Z" AND timestamp<="2025-07-15T17:00:00Z" AND (protoPayload.authenticationInfo.principalEmail="a@example.com" OR protoPayload.authenticationInfo.principalEmail="b@example.com")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, BuildFilter("my-project", start, end, tt.writeOnly, tt.principals))
+		})
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	entry := &logging.LogEntry{
+		InsertId:     "abc123",
+		Timestamp:    "2025-07-15T10:00:00.123456Z",
+		ProtoPayload: []byte(`{"serviceName":"compute.googleapis.com","methodName":"v1.compute.instances.delete","resourceName":"projects/p/zones/z/instances/i","authenticationInfo":{"principalEmail":"a@example.com"},"status":{"code":7,"message":"PERMISSION_DENIED"}}`),
+	}
+
+	event, err := ParseEntry(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", event.InsertID)
+	assert.Equal(t, "a@example.com", event.Principal)
+	assert.Equal(t, "v1.compute.instances.delete", event.Method)
+	assert.Equal(t, "compute.googleapis.com", event.Service)
+	assert.Equal(t, "projects/p/zones/z/instances/i", event.ResourceName)
+	assert.Equal(t, int64(7), event.ErrorCode)
+	assert.Equal(t, 2025, event.Time.Year())
+
+	_, err = ParseEntry(&logging.LogEntry{Timestamp: "not-a-time"})
+	assert.Error(t, err)
+}
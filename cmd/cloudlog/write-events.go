@@ -0,0 +1,159 @@
+package cloudlog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/osdctl/cmd/cloudtrail"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	logging "google.golang.org/api/logging/v2"
+)
+
+const (
+	cloudlogWriteEventsExample = `
+    # Get all admin activity events for the last 2 hours
+    $ osdctl cloudlog write-events -C cluster-id --since 2h
+
+    # Time range, only events made by a given principal; print url
+    $ osdctl cloudlog write-events -C cluster-id --after 2025-07-15,09:00:00 --until 2025-07-15,17:00:00 \
+      --principal john.doe@example.com --url
+
+    # Include data access events as well as admin activity events; print raw-event
+    $ osdctl cloudlog write-events -C cluster-id --write-only=false --raw-event`
+
+	cloudlogWriteEventsDescription = `
+	Lists GCP Cloud Audit Log events for a specific OSD on GCP cluster, the GCP
+	equivalent of 'osdctl cloudtrail write-events'.
+
+	The command looks up the cluster's GCP project in OpenShift Cluster Manager (OCM)
+	and queries Cloud Logging using the Application Default Credentials of the
+	current user (see 'gcloud auth application-default login').
+
+	By default only Admin Activity audit logs are queried, which hold every call
+	that modifies the configuration or metadata of a resource.`
+)
+
+// writeEventsOptions struct for holding options for audit log lookup
+type writeEventsOptions struct {
+	ClusterID    string
+	GcpProjectID string
+	StartTime    string
+	EndTime      string
+	Duration     string
+	WriteOnly    bool
+	Principals   []string
+	PrintUrl     bool
+	PrintRaw     bool
+}
+
+func newCmdWriteEvents() *cobra.Command {
+	ops := &writeEventsOptions{}
+	writeEventsCmd := &cobra.Command{
+		Use:     "write-events",
+		Short:   "Prints GCP Cloud Audit Log write events to console",
+		Long:    cloudlogWriteEventsDescription,
+		Example: cloudlogWriteEventsExample,
+		Args:    cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error { return utils.IsValidClusterKey(ops.ClusterID) },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run()
+		},
+	}
+	writeEventsCmd.Flags().StringVarP(&ops.ClusterID, "cluster-id", "C", "", "Cluster ID")
+	writeEventsCmd.Flags().StringVar(&ops.GcpProjectID, "gcp-project-id", "", "Override the GCP project ID retrieved from OCM")
+	writeEventsCmd.Flags().StringVarP(&ops.StartTime, "after", "", "", "Specifies all events that occur after the specified time. Format \"YY-MM-DD,hh:mm:ss\".")
+	writeEventsCmd.Flags().StringVarP(&ops.EndTime, "until", "", "", "Specifies all events that occur before the specified time. Format \"YY-MM-DD,hh:mm:ss\".")
+	writeEventsCmd.Flags().StringVarP(&ops.Duration, "since", "", "1h", "Specifies that only events that occur within the specified time are returned. Defaults to 1h.Valid time units are \"ns\", \"us\" (or \"µs\"), \"ms\", \"s\", \"m\", \"h\".")
+	writeEventsCmd.Flags().BoolVar(&ops.WriteOnly, "write-only", true, "Only query Admin Activity audit logs. Set to false to include Data Access audit logs")
+	writeEventsCmd.Flags().StringSliceVarP(&ops.Principals, "principal", "P", nil, "Only show events made by the given principal email. Can be repeated")
+	writeEventsCmd.Flags().BoolVarP(&ops.PrintUrl, "url", "u", false, "Generates Url link to the GCP Logs Explorer entry")
+	writeEventsCmd.Flags().BoolVarP(&ops.PrintRaw, "raw-event", "r", false, "Prints the audit log payload to the console in raw json format")
+	_ = writeEventsCmd.MarkFlagRequired("cluster-id")
+	return writeEventsCmd
+}
+
+func (o *writeEventsOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("unable to create connection to ocm: %w", err)
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(connection, o.ClusterID)
+	if err != nil {
+		return err
+	}
+	if strings.ToUpper(cluster.CloudProvider().ID()) != "GCP" {
+		return fmt.Errorf("this command is only available for GCP clusters, use 'osdctl cloudtrail' for AWS clusters")
+	}
+
+	projectID := o.GcpProjectID
+	if projectID == "" {
+		projectID = cluster.GCP().ProjectID()
+	}
+	if projectID == "" {
+		return fmt.Errorf("could not get GCP project ID from OCM, specify manually with --gcp-project-id")
+	}
+
+	startTime, endTime, err := cloudtrail.ParseStartEndTime(o.StartTime, o.EndTime, o.Duration)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	svc, err := logging.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create GCP logging client: %w", err)
+	}
+
+	fmt.Printf("Checking audit log history for GCP project %v from %v until %v...\n", projectID, startTime, endTime)
+
+	req := &logging.ListLogEntriesRequest{
+		ResourceNames: []string{"projects/" + projectID},
+		Filter:        BuildFilter(projectID, startTime, endTime, o.WriteOnly, o.Principals),
+		OrderBy:       "timestamp desc",
+		PageSize:      1000,
+	}
+
+	var events []AuditEvent
+	err = svc.Entries.List(req).Pages(ctx, func(resp *logging.ListLogEntriesResponse) error {
+		for _, entry := range resp.Entries {
+			event, err := ParseEntry(entry)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+
+	printEvents(events, projectID, o.PrintUrl, o.PrintRaw)
+	return nil
+}
+
+// printEvents prints the audit events in a human-readable format.
+func printEvents(events []AuditEvent, projectID string, printUrl, printRaw bool) {
+	var sb strings.Builder
+	for _, event := range events {
+		sb.WriteString("\n")
+		_, _ = fmt.Fprintf(&sb, "%v | %v | Principal: %v", event.Method, event.Time.String(), event.Principal)
+		if event.ResourceName != "" {
+			_, _ = fmt.Fprintf(&sb, " | Resource Name: %v", event.ResourceName)
+		}
+		if event.ErrorCode != 0 {
+			_, _ = fmt.Fprintf(&sb, " | Error: %v %v", event.ErrorCode, event.ErrorMessage)
+		}
+		if printUrl {
+			_, _ = fmt.Fprintf(&sb, " | %v", generateLink(projectID, event))
+		}
+		if printRaw {
+			_, _ = fmt.Fprintf(&sb, "\n%s", string(event.Raw))
+		}
+	}
+	fmt.Println(sb.String())
+}
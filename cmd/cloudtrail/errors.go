@@ -1,15 +1,16 @@
 package cloudtrail
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
-	"github.com/openshift/osdctl/pkg/osdCloud"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -33,6 +34,8 @@ type errorsOptions struct {
 	PrintRaw   bool
 	JSONOutput bool
 	ErrorTypes []string
+
+	awsConfig *awsprovider.ClusterConfigFactory
 }
 
 type errorEventOutput struct {
@@ -46,7 +49,7 @@ type errorEventOutput struct {
 }
 
 func newCmdErrors() *cobra.Command {
-	opts := &errorsOptions{}
+	opts := &errorsOptions{awsConfig: awsprovider.NewClusterConfigFactory()}
 
 	errorsCmd := &cobra.Command{
 		Use:   "errors",
@@ -75,7 +78,9 @@ Use --error-types to filter for specific error patterns.`,
   # Include console links for each event
   osdctl cloudtrail errors -C ${CLUSTER_ID} --url`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return opts.run()
+			ctx, stop := utils.NotifyInterrupt(cmd.Context(), cmd.ErrOrStderr())
+			defer stop()
+			return opts.run(ctx)
 		},
 	}
 
@@ -85,12 +90,13 @@ Use --error-types to filter for specific error patterns.`,
 	errorsCmd.Flags().BoolVarP(&opts.PrintRaw, "raw-event", "r", false, "Print raw CloudTrail event JSON")
 	errorsCmd.Flags().BoolVar(&opts.JSONOutput, "json", false, "Output results as JSON")
 	errorsCmd.Flags().StringSliceVar(&opts.ErrorTypes, "error-types", nil, "Comma-separated list of error patterns to match (default: all common permission errors)")
+	opts.awsConfig.AddRegionFlag(errorsCmd.Flags())
 	_ = errorsCmd.MarkFlagRequired("cluster-id")
 
 	return errorsCmd
 }
 
-func (o *errorsOptions) run() error {
+func (o *errorsOptions) run(ctx context.Context) error {
 	err := utils.IsValidClusterKey(o.ClusterID)
 	if err != nil {
 		return err
@@ -107,11 +113,7 @@ func (o *errorsOptions) run() error {
 		return err
 	}
 
-	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
-		return fmt.Errorf("this command is only available for AWS clusters")
-	}
-
-	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	cfg, err := o.awsConfig.Config(connection, cluster)
 	if err != nil {
 		return err
 	}
@@ -140,7 +142,7 @@ func (o *errorsOptions) run() error {
 
 	awsAPI := NewEventAPI(cfg, false, cfg.Region)
 	requestTime := Period{StartTime: startTime, EndTime: time.Now().UTC()}
-	generator := awsAPI.GetEvents(o.ClusterID, requestTime)
+	generator := awsAPI.GetEvents(ctx, o.ClusterID, requestTime)
 
 	var allEvents []errorEventOutput
 	eventCount := 0
@@ -174,14 +176,14 @@ func (o *errorsOptions) run() error {
 	}
 
 	// Also check global region if different
-	if DEFAULT_REGION != cfg.Region {
-		defaultAwsAPI := NewEventAPI(cfg, true, DEFAULT_REGION)
+	if globalRegion := GetGlobalRegion(cfg.Region); globalRegion != cfg.Region && ctx.Err() == nil {
+		defaultAwsAPI := NewEventAPI(cfg, true, globalRegion)
 
 		if !o.JSONOutput && !o.PrintRaw {
-			fmt.Printf("[INFO] Fetching CloudTrail error events from %v region...\n", DEFAULT_REGION)
+			fmt.Printf("[INFO] Fetching CloudTrail error events from %v region...\n", globalRegion)
 		}
 
-		generator := defaultAwsAPI.GetEvents(o.ClusterID, requestTime)
+		generator := defaultAwsAPI.GetEvents(ctx, o.ClusterID, requestTime)
 
 		for page := range generator {
 			filteredEvents, err := ApplyFilters(page.AWSEvent,
@@ -195,7 +197,7 @@ func (o *errorsOptions) run() error {
 
 			if o.JSONOutput {
 				for _, event := range filteredEvents {
-					output := o.eventToOutput(event, DEFAULT_REGION)
+					output := o.eventToOutput(event, globalRegion)
 					allEvents = append(allEvents, output)
 				}
 			} else if o.PrintRaw {
@@ -205,12 +207,17 @@ func (o *errorsOptions) run() error {
 					}
 				}
 			} else if len(filteredEvents) > 0 {
-				o.printEvents(filteredEvents, DEFAULT_REGION)
+				o.printEvents(filteredEvents, globalRegion)
 			}
 			eventCount += len(filteredEvents)
 		}
 	}
 
+	interrupted := utils.IsInterrupted(ctx)
+	if interrupted {
+		fmt.Fprintln(os.Stderr, utils.PartialResultsBanner)
+	}
+
 	if o.JSONOutput {
 		output, err := json.MarshalIndent(allEvents, "", "  ")
 		if err != nil {
@@ -221,6 +228,9 @@ func (o *errorsOptions) run() error {
 		fmt.Printf("\n[INFO] Found %d error event(s)\n", eventCount)
 	}
 
+	if interrupted {
+		return fmt.Errorf("retrieving events interrupted: %w", ctx.Err())
+	}
 	return nil
 }
 
@@ -14,6 +14,8 @@ import (
 type RawEventDetails struct {
 	EventVersion string `json:"eventVersion"`
 	UserIdentity struct {
+		Type           string `json:"type"`
+		Arn            string `json:"arn"`
 		AccountId      string `json:"accountId"`
 		SessionContext struct {
 			SessionIssuer struct {
@@ -57,9 +59,9 @@ func NewEventAPI(cfg aws.Config, writeOnly bool, region string) *EventAPI {
 	}
 }
 
-func (a *EventAPI) GetEvents(_ string, missing Period) <-chan EventResult {
-	var alllookupEvents []types.Event
-
+// GetEvents pages through the events of the period in the background. The pages are sent on the returned
+// channel, which is closed once all pages are retrieved, on the first error or when the context is cancelled.
+func (a *EventAPI) GetEvents(ctx context.Context, _ string, missing Period) <-chan EventResult {
 	pageChan := make(chan EventResult)
 
 	input := cloudtrail.LookupEventsInput{
@@ -79,20 +81,22 @@ func (a *EventAPI) GetEvents(_ string, missing Period) <-chan EventResult {
 		defer close(pageChan)
 
 		for paginator.HasMorePages() {
-			lookupOutput, err := paginator.NextPage(context.Background())
+			var result EventResult
+			lookupOutput, err := paginator.NextPage(ctx)
 			if err != nil {
-				pageChan <- EventResult{
-					AWSEvent: nil,
-					errors:   err,
-				}
+				result.errors = err
+			} else {
+				result.AWSEvent = lookupOutput.Events
 			}
-			alllookupEvents = append(alllookupEvents, lookupOutput.Events...)
 
-			pageChan <- EventResult{
-				AWSEvent: lookupOutput.Events,
-				errors:   nil,
+			select {
+			case pageChan <- result:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
 			}
-
 		}
 	}()
 
@@ -0,0 +1,200 @@
+package cloudtrail
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	config "github.com/openshift/osdctl/pkg/envConfig"
+)
+
+// IdentityKind describes whether a CloudTrail event was caused by a person or by automation
+type IdentityKind string
+
+const (
+	IdentityKindHuman      IdentityKind = "human"
+	IdentityKindAutomation IdentityKind = "automation"
+	IdentityKindUnknown    IdentityKind = "unknown"
+
+	// sessionPlaceholder is replaced with the role session name (usually the SRE's username) in mapping names
+	sessionPlaceholder = "{session}"
+
+	rosaRoleTypeTag      = "rosa_role_type"
+	operatorNameTag      = "operator_name"
+	operatorNamespaceTag = "operator_namespace"
+)
+
+// defaultIdentityMappings maps well-known SRE and automation role ARNs to friendly names.
+// Mappings from the osdctl configuration file take precedence over these.
+var defaultIdentityMappings = []config.CloudTrailIdentityMapping{
+	{Pattern: `:role/(.+-)?Support-Role$`, Name: "SRE ({session})", Kind: string(IdentityKindHuman)},
+	{Pattern: `:role/ManagedOpenShift-Support-[a-z0-9]+$`, Name: "SRE ({session})", Kind: string(IdentityKindHuman)},
+	{Pattern: `:role/RH-Technical-Support-Access$`, Name: "Red Hat Technical Support ({session})", Kind: string(IdentityKindHuman)},
+	{Pattern: `:role/OrganizationAccountAccessRole$`, Name: "SRE ({session})", Kind: string(IdentityKindHuman)},
+	{Pattern: `openshift-machine-api-aws`, Name: "machine-api", Kind: string(IdentityKindAutomation)},
+	{Pattern: `openshift-ingress-operator`, Name: "ingress-operator", Kind: string(IdentityKindAutomation)},
+	{Pattern: `openshift-image-registry`, Name: "image-registry", Kind: string(IdentityKindAutomation)},
+	{Pattern: `openshift-cluster-csi-drivers-ebs`, Name: "aws-ebs-csi-driver", Kind: string(IdentityKindAutomation)},
+	{Pattern: `openshift-cloud-network-config-controller`, Name: "cloud-network-config-controller", Kind: string(IdentityKindAutomation)},
+	{Pattern: `openshift-cloud-credential-operator`, Name: "cloud-credential-operator", Kind: string(IdentityKindAutomation)},
+	{Pattern: `kube-controller-manager`, Name: "kube-controller-manager", Kind: string(IdentityKindAutomation)},
+	{Pattern: `capa-controller-manager`, Name: "cluster-api-provider-aws", Kind: string(IdentityKindAutomation)},
+	{Pattern: `:role/(.+-)?Installer-Role$`, Name: "installer", Kind: string(IdentityKindAutomation)},
+	{Pattern: `:role/(.+-)?ControlPlane-Role$`, Name: "control plane node", Kind: string(IdentityKindAutomation)},
+	{Pattern: `:role/(.+-)?Worker-Role$`, Name: "worker node", Kind: string(IdentityKindAutomation)},
+}
+
+// Identity is the resolved identity behind a CloudTrail event
+type Identity struct {
+	Name string
+	Kind IdentityKind
+}
+
+func (i Identity) String() string {
+	return fmt.Sprintf("%s [%s]", i.Name, i.Kind)
+}
+
+type identityMapping struct {
+	pattern *regexp.Regexp
+	name    string
+	kind    IdentityKind
+}
+
+// RoleTagLookup returns the IAM tags of a role
+type RoleTagLookup func(roleName string) (map[string]string, error)
+
+// IdentityResolver maps the principal of CloudTrail events to friendly identities
+type IdentityResolver struct {
+	mappings  []identityMapping
+	tagLookup RoleTagLookup
+	roleTags  map[string]map[string]string
+}
+
+// NewIdentityResolver creates an IdentityResolver from the given mappings, followed by the default ones.
+// tagLookup is optional and used to resolve roles which don't match any mapping through their IAM tags.
+func NewIdentityResolver(mappings []config.CloudTrailIdentityMapping, tagLookup RoleTagLookup) (*IdentityResolver, error) {
+	resolver := &IdentityResolver{
+		tagLookup: tagLookup,
+		roleTags:  map[string]map[string]string{},
+	}
+
+	for _, mapping := range append(append([]config.CloudTrailIdentityMapping{}, mappings...), defaultIdentityMappings...) {
+		pattern, err := regexp.Compile("(?i)" + mapping.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cloudtrail identity mapping pattern %q: %w", mapping.Pattern, err)
+		}
+
+		kind := IdentityKind(strings.ToLower(mapping.Kind))
+		switch kind {
+		case IdentityKindHuman, IdentityKindAutomation:
+		case "":
+			kind = IdentityKindUnknown
+		default:
+			return nil, fmt.Errorf("invalid cloudtrail identity mapping kind %q for pattern %q, expected %q or %q", mapping.Kind, mapping.Pattern, IdentityKindHuman, IdentityKindAutomation)
+		}
+
+		resolver.mappings = append(resolver.mappings, identityMapping{pattern: pattern, name: mapping.Name, kind: kind})
+	}
+
+	return resolver, nil
+}
+
+// NewIAMRoleTagLookup returns a RoleTagLookup using the IAM API
+func NewIAMRoleTagLookup(cfg aws.Config) RoleTagLookup {
+	client := iam.NewFromConfig(cfg)
+	return func(roleName string) (map[string]string, error) {
+		output, err := client.ListRoleTags(context.TODO(), &iam.ListRoleTagsInput{RoleName: aws.String(roleName)})
+		if err != nil {
+			return nil, err
+		}
+		tags := make(map[string]string, len(output.Tags))
+		for _, tag := range output.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		return tags, nil
+	}
+}
+
+// ResolveEvent resolves the identity behind a CloudTrail event
+func (r *IdentityResolver) ResolveEvent(event types.Event) (Identity, error) {
+	raw, err := ExtractUserDetails(event.CloudTrailEvent)
+	if err != nil {
+		return Identity{Name: aws.ToString(event.Username), Kind: IdentityKindUnknown}, err
+	}
+	return r.Resolve(raw, aws.ToString(event.Username)), nil
+}
+
+// Resolve maps the principal of the event to an identity, trying the configured and default mappings first
+// and the IAM tags of the role second. The session issuer's name is used for principals which cannot be resolved.
+func (r *IdentityResolver) Resolve(raw *RawEventDetails, username string) Identity {
+	issuer := raw.UserIdentity.SessionContext.SessionIssuer
+	principalArn := issuer.Arn
+	if principalArn == "" {
+		principalArn = raw.UserIdentity.Arn
+	}
+	session := sessionName(raw.UserIdentity.Arn, username)
+
+	for _, mapping := range r.mappings {
+		if principalArn != "" && mapping.pattern.MatchString(principalArn) {
+			return Identity{Name: strings.ReplaceAll(mapping.name, sessionPlaceholder, session), Kind: mapping.kind}
+		}
+	}
+
+	if identity, ok := r.resolveFromTags(issuer.Type, issuer.UserName, session); ok {
+		return identity
+	}
+
+	name := issuer.UserName
+	if name == "" {
+		name = username
+	}
+	return Identity{Name: name, Kind: IdentityKindUnknown}
+}
+
+// resolveFromTags resolves ROSA account and operator roles through the tags set on them by the ROSA CLI
+func (r *IdentityResolver) resolveFromTags(issuerType, roleName, session string) (Identity, bool) {
+	if r.tagLookup == nil || issuerType != "Role" || roleName == "" {
+		return Identity{}, false
+	}
+
+	tags, ok := r.roleTags[roleName]
+	if !ok {
+		var err error
+		tags, err = r.tagLookup(roleName)
+		if err != nil {
+			// Don't retry roles which can't be looked up, e.g. because of missing permissions
+			tags = map[string]string{}
+		}
+		r.roleTags[roleName] = tags
+	}
+
+	if operator := tags[operatorNameTag]; operator != "" {
+		if namespace := tags[operatorNamespaceTag]; namespace != "" {
+			operator = namespace + "/" + operator
+		}
+		return Identity{Name: operator, Kind: IdentityKindAutomation}, true
+	}
+	switch roleType := tags[rosaRoleTypeTag]; roleType {
+	case "":
+		return Identity{}, false
+	case "support":
+		return Identity{Name: fmt.Sprintf("SRE (%s)", session), Kind: IdentityKindHuman}, true
+	default:
+		return Identity{Name: "ROSA " + roleType + " role", Kind: IdentityKindAutomation}, true
+	}
+}
+
+// sessionName returns the role session name of an assumed-role ARN
+// (arn:aws:sts::<account>:assumed-role/<role>/<session>), falling back to the event's username
+func sessionName(assumedRoleArn, username string) string {
+	if strings.Contains(assumedRoleArn, ":assumed-role/") {
+		if i := strings.LastIndex(assumedRoleArn, "/"); i >= 0 && i < len(assumedRoleArn)-1 {
+			return assumedRoleArn[i+1:]
+		}
+	}
+	return username
+}
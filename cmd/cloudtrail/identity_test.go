@@ -0,0 +1,99 @@
+package cloudtrail
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	config "github.com/openshift/osdctl/pkg/envConfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assumedRoleEvent(roleName, session string) types.Event {
+	return types.Event{
+		Username: aws.String(session),
+		CloudTrailEvent: aws.String(`{"eventVersion": "1.08", "userIdentity": {"type": "AssumedRole",
+			"arn": "arn:aws:sts::123456789012:assumed-role/` + roleName + `/` + session + `",
+			"sessionContext": {"sessionIssuer": {"type": "Role", "userName": "` + roleName + `",
+			"arn": "arn:aws:iam::123456789012:role/` + roleName + `"}}}}`),
+	}
+}
+
+func TestIdentityResolver(t *testing.T) {
+	tags := map[string]map[string]string{
+		"custom-openshift-cluster-csi-drivers": {"operator_name": "ebs-cloud-credentials", "operator_namespace": "openshift-cluster-csi-drivers"},
+		"custom-account-role":                  {"rosa_role_type": "support"},
+	}
+	lookups := 0
+	lookup := func(roleName string) (map[string]string, error) {
+		lookups++
+		if t, ok := tags[roleName]; ok {
+			return t, nil
+		}
+		return nil, errors.New("AccessDenied")
+	}
+
+	resolver, err := NewIdentityResolver([]config.CloudTrailIdentityMapping{
+		{Pattern: `:role/my-automation$`, Name: "my-automation", Kind: "automation"},
+	}, lookup)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		event    types.Event
+		expected Identity
+	}{
+		{
+			name:     "rosa_support_role",
+			event:    assumedRoleEvent("ManagedOpenShift-Support-Role", "jdoe"),
+			expected: Identity{Name: "SRE (jdoe)", Kind: IdentityKindHuman},
+		},
+		{
+			name:     "machine_api_operator_role",
+			event:    assumedRoleEvent("mycluster-a1b2-openshift-machine-api-aws-cloud-credentials", "1700000000000000000"),
+			expected: Identity{Name: "machine-api", Kind: IdentityKindAutomation},
+		},
+		{
+			name:     "configured_mapping",
+			event:    assumedRoleEvent("my-automation", "run-42"),
+			expected: Identity{Name: "my-automation", Kind: IdentityKindAutomation},
+		},
+		{
+			name:     "operator_role_from_tags",
+			event:    assumedRoleEvent("custom-openshift-cluster-csi-drivers", "1700000000000000000"),
+			expected: Identity{Name: "openshift-cluster-csi-drivers/ebs-cloud-credentials", Kind: IdentityKindAutomation},
+		},
+		{
+			name:     "support_role_from_tags",
+			event:    assumedRoleEvent("custom-account-role", "jdoe"),
+			expected: Identity{Name: "SRE (jdoe)", Kind: IdentityKindHuman},
+		},
+		{
+			name:     "unknown_role",
+			event:    assumedRoleEvent("customer-role", "alice"),
+			expected: Identity{Name: "customer-role", Kind: IdentityKindUnknown},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity, err := resolver.ResolveEvent(test.event)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, identity)
+		})
+	}
+
+	// Tags of a role are only looked up once, even when the lookup fails
+	_, _ = resolver.ResolveEvent(assumedRoleEvent("customer-role", "bob"))
+	assert.Equal(t, 3, lookups)
+}
+
+func TestNewIdentityResolverInvalidMapping(t *testing.T) {
+	_, err := NewIdentityResolver([]config.CloudTrailIdentityMapping{{Pattern: "(", Name: "broken"}}, nil)
+	assert.ErrorContains(t, err, "invalid cloudtrail identity mapping pattern")
+
+	_, err = NewIdentityResolver([]config.CloudTrailIdentityMapping{{Pattern: "role", Name: "robot", Kind: "robot"}}, nil)
+	assert.ErrorContains(t, err, "invalid cloudtrail identity mapping kind")
+}
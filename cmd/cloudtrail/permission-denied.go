@@ -1,14 +1,14 @@
 package cloudtrail
 
 import (
+	"context"
 	"fmt"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
-	"github.com/openshift/osdctl/pkg/osdCloud"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -18,10 +18,12 @@ type permissionDeniedEventsOptions struct {
 	StartTime string
 	PrintUrl  bool
 	PrintRaw  bool
+
+	awsConfig *awsprovider.ClusterConfigFactory
 }
 
 func newCmdPermissionDenied() *cobra.Command {
-	opts := &permissionDeniedEventsOptions{}
+	opts := &permissionDeniedEventsOptions{awsConfig: awsprovider.NewClusterConfigFactory()}
 
 	permissionDeniedCmd := &cobra.Command{
 		Use:   "permission-denied-events",
@@ -32,13 +34,16 @@ func newCmdPermissionDenied() *cobra.Command {
   # Check for permission-denied events in the last hour with URLs
   osdctl cloudtrail permission-denied-events --cluster-id ${CLUSTER_ID} --since 1h --url`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return opts.run()
+			ctx, stop := utils.NotifyInterrupt(cmd.Context(), cmd.ErrOrStderr())
+			defer stop()
+			return opts.run(ctx)
 		},
 	}
 	permissionDeniedCmd.Flags().StringVarP(&opts.ClusterID, "cluster-id", "C", "", "Cluster ID")
 	permissionDeniedCmd.Flags().StringVarP(&opts.StartTime, "since", "", "5m", "Specifies that only events that occur within the specified time are returned.Defaults to 5m. Valid time units are \"ns\", \"us\" (or \"µs\"), \"ms\", \"s\", \"m\", \"h\".")
 	permissionDeniedCmd.Flags().BoolVarP(&opts.PrintUrl, "url", "u", false, "Generates Url link to cloud console cloudtrail event")
 	permissionDeniedCmd.Flags().BoolVarP(&opts.PrintRaw, "raw-event", "r", false, "Prints the cloudtrail events to the console in raw json format")
+	opts.awsConfig.AddRegionFlag(permissionDeniedCmd.Flags())
 	permissionDeniedCmd.MarkFlagRequired("cluster-id")
 	return permissionDeniedCmd
 }
@@ -61,7 +66,7 @@ func isforbiddenEvent(event types.Event) (bool, error) {
 
 	return false, nil
 }
-func (p *permissionDeniedEventsOptions) run() error {
+func (p *permissionDeniedEventsOptions) run(ctx context.Context) error {
 
 	err := utils.IsValidClusterKey(p.ClusterID)
 	if err != nil {
@@ -79,11 +84,7 @@ func (p *permissionDeniedEventsOptions) run() error {
 		return err
 	}
 
-	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
-		return fmt.Errorf("[ERROR] this command is only available for AWS clusters")
-	}
-
-	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	cfg, err := p.awsConfig.Config(connection, cluster)
 	if err != nil {
 		return err
 	}
@@ -101,7 +102,7 @@ func (p *permissionDeniedEventsOptions) run() error {
 	awsAPI := NewEventAPI(cfg, false, cfg.Region)
 	printer := NewPrinter(p.PrintUrl, p.PrintRaw)
 	requestTime := Period{StartTime: startTime, EndTime: time.Now().UTC()}
-	generator := awsAPI.GetEvents(p.ClusterID, requestTime)
+	generator := awsAPI.GetEvents(ctx, p.ClusterID, requestTime)
 
 	fmt.Printf("[INFO] Checking Permission Denied History since %v for AWS Account %v as %v \n", startTime, accountId, arn)
 	fmt.Printf("[INFO] Fetching %v Event History...", cfg.Region)
@@ -120,11 +121,11 @@ func (p *permissionDeniedEventsOptions) run() error {
 		}
 	}
 
-	if DEFAULT_REGION != cfg.Region {
-		defaultAwsAPI := NewEventAPI(cfg, true, DEFAULT_REGION)
+	if globalRegion := GetGlobalRegion(cfg.Region); globalRegion != cfg.Region && ctx.Err() == nil {
+		defaultAwsAPI := NewEventAPI(cfg, true, globalRegion)
 
-		fmt.Printf("[INFO] Fetching Cloudtrail Global Permission Denied Event History from %v Region...", DEFAULT_REGION)
-		generator := defaultAwsAPI.GetEvents(p.ClusterID, requestTime)
+		fmt.Printf("[INFO] Fetching Cloudtrail Global Permission Denied Event History from %v Region...", globalRegion)
+		generator := defaultAwsAPI.GetEvents(ctx, p.ClusterID, requestTime)
 
 		for page := range generator {
 			filteredEvents, err := ApplyFilters(page.AWSEvent,
@@ -141,6 +142,11 @@ func (p *permissionDeniedEventsOptions) run() error {
 		}
 	}
 
+	if utils.IsInterrupted(ctx) {
+		fmt.Println(utils.PartialResultsBanner)
+		return fmt.Errorf("retrieving events interrupted: %w", ctx.Err())
+	}
+
 	return err
 
 }
@@ -2,15 +2,21 @@ package cloudtrail
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 )
 
 // Printer struct handles the formatting and output of CloudTrail events.
 type Printer struct {
-	printUrl bool
-	printRaw bool
+	printUrl   bool
+	printRaw   bool
+	identities *IdentityResolver
+	// summarize collects the events for PrintSummary instead of printing them
+	summarize bool
+	collected []types.Event
 }
 
 // NewPrinter creates a new Printer instance with the specified output options.
@@ -24,10 +30,32 @@ func NewPrinter(printUrl, printRaw bool) *Printer {
 	}
 }
 
+// WithIdentityResolver makes the printer resolve the identity behind each event for the "identity" field.
+func (o *Printer) WithIdentityResolver(identities *IdentityResolver) *Printer {
+	o.identities = identities
+	return o
+}
+
+// WithSummary makes the printer collect the events instead of printing them, for PrintSummary to group them by resource.
+func (o *Printer) WithSummary() *Printer {
+	o.summarize = true
+	return o
+}
+
+// PrintSummary prints the events collected since WithSummary grouped by the resources they affect.
+func (o *Printer) PrintSummary(w io.Writer) error {
+	return PrintSummary(w, SummarizeByResource(o.collected, o.identities))
+}
+
 // PrintEvents prints the filtered CloudTrail events in a human-readable format.
 // Allows to print cloudtrail event url link or its raw JSON format.
 // Allows to print cloutrail event resource name & type.
 func (o *Printer) PrintEvents(filterEvents []types.Event, printFields []string) {
+	if o.summarize {
+		o.collected = append(o.collected, filterEvents...)
+		return
+	}
+
 	var eventStringBuilder = strings.Builder{}
 	tableFilter := map[string]struct{}{}
 
@@ -51,6 +79,9 @@ func (o *Printer) PrintEvents(filterEvents []types.Event, printFields []string)
 		if _, ok := tableFilter["username"]; ok && filterEvents[i].Username != nil {
 			_, _ = fmt.Fprintf(&eventStringBuilder, "Username: %v | ", *filterEvents[i].Username)
 		}
+		if _, ok := tableFilter["identity"]; ok && o.identities != nil && err == nil {
+			_, _ = fmt.Fprintf(&eventStringBuilder, "Identity: %v | ", o.identities.Resolve(rawEventDetails, aws.ToString(filterEvents[i].Username)))
+		}
 		if _, ok := tableFilter["arn"]; ok && sessionIssuer != "" {
 			_, _ = fmt.Fprintf(&eventStringBuilder, "ARN: %v | ", sessionIssuer)
 		}
@@ -102,11 +133,12 @@ func ValidateFormat(table []string) error {
 		"resource-type": {},
 		"arn":           {},
 		"time":          {},
+		"identity":      {},
 	}
 
 	for _, column := range table {
 		if _, ok := allowedKeys[strings.ToLower(column)]; !ok {
-			return fmt.Errorf("invalid table column: %s (allowed: username, event, resource-name, resource-type, arn, time, url, region)", column)
+			return fmt.Errorf("invalid table column: %s (allowed: username, event, resource-name, resource-type, arn, time, identity, url, region)", column)
 		}
 	}
 
@@ -0,0 +1,119 @@
+package cloudtrail
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/openshift/osdctl/pkg/printer"
+)
+
+// noResourceType groups the write events CloudTrail doesn't record an affected resource for
+const noResourceType = "(no resource)"
+
+// ResourceChange is one write event affecting a resource
+type ResourceChange struct {
+	Time  time.Time
+	Event string
+	Who   string
+}
+
+// ResourceSummary is the write events affecting one resource, oldest first
+type ResourceSummary struct {
+	Type    string
+	Name    string
+	Changes []ResourceChange
+}
+
+// SummarizeByResource groups the write events by the resources they affect, e.g. instances, security groups or
+// route tables. An event affecting several resources is listed under each of them, and events retrieved twice
+// (e.g. from the cache and from CloudTrail) are counted once. identities is optional and resolves who made each change.
+func SummarizeByResource(events []types.Event, identities *IdentityResolver) []ResourceSummary {
+	seen := map[string]bool{}
+	byResource := map[[2]string]*ResourceSummary{}
+
+	for _, event := range events {
+		if id := aws.ToString(event.EventId); id != "" {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+
+		change := ResourceChange{
+			Time:  aws.ToTime(event.EventTime),
+			Event: aws.ToString(event.EventName),
+			Who:   aws.ToString(event.Username),
+		}
+		if identities != nil {
+			if identity, err := identities.ResolveEvent(event); err == nil {
+				change.Who = identity.String()
+			}
+		}
+
+		resources := event.Resources
+		if len(resources) == 0 {
+			resources = []types.Resource{{ResourceType: aws.String(noResourceType)}}
+		}
+		for _, resource := range resources {
+			key := [2]string{aws.ToString(resource.ResourceType), aws.ToString(resource.ResourceName)}
+			summary, ok := byResource[key]
+			if !ok {
+				summary = &ResourceSummary{Type: key[0], Name: key[1]}
+				byResource[key] = summary
+			}
+			summary.Changes = append(summary.Changes, change)
+		}
+	}
+
+	summaries := make([]ResourceSummary, 0, len(byResource))
+	for _, summary := range byResource {
+		sort.SliceStable(summary.Changes, func(i, j int) bool {
+			return summary.Changes[i].Time.Before(summary.Changes[j].Time)
+		})
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		// The events without a resource come last
+		if (summaries[i].Type == noResourceType) != (summaries[j].Type == noResourceType) {
+			return summaries[j].Type == noResourceType
+		}
+		if summaries[i].Type != summaries[j].Type {
+			return summaries[i].Type < summaries[j].Type
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+// PrintSummary prints the changes of each resource, with who made them and when
+func PrintSummary(w io.Writer, summaries []ResourceSummary) error {
+	if len(summaries) == 0 {
+		_, err := fmt.Fprintln(w, "No write events found")
+		return err
+	}
+
+	for i, summary := range summaries {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		first, last := summary.Changes[0].Time, summary.Changes[len(summary.Changes)-1].Time
+		_, _ = fmt.Fprintf(w, "%s %s: %d change(s) from %s to %s\n", summary.Type, summary.Name, len(summary.Changes), formatEventTime(first), formatEventTime(last))
+
+		table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+		for _, change := range summary.Changes {
+			table.AddRow([]string{"  " + formatEventTime(change.Time), change.Event, change.Who})
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatEventTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
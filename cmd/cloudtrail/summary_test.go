@@ -0,0 +1,74 @@
+package cloudtrail
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeEvent(id, name, session string, at time.Time, resources ...types.Resource) types.Event {
+	event := assumedRoleEvent("ManagedOpenShift-Support-abcd", session)
+	event.EventId = aws.String(id)
+	event.EventName = aws.String(name)
+	event.EventTime = aws.Time(at)
+	event.Resources = resources
+	return event
+}
+
+func resource(resourceType, name string) types.Resource {
+	return types.Resource{ResourceType: aws.String(resourceType), ResourceName: aws.String(name)}
+}
+
+func TestSummarizeByResource(t *testing.T) {
+	start := time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC)
+	sg := resource("AWS::EC2::SecurityGroup", "sg-0123")
+	events := []types.Event{
+		writeEvent("3", "RevokeSecurityGroupIngress", "jdoe", start.Add(2*time.Hour), sg),
+		writeEvent("1", "AuthorizeSecurityGroupIngress", "jdoe", start, sg),
+		writeEvent("2", "CreateRoute", "alice", start.Add(time.Hour), resource("AWS::EC2::RouteTable", "rtb-0456"), resource("AWS::EC2::VPC", "vpc-0789")),
+		writeEvent("4", "PutBucketPolicy", "alice", start.Add(3*time.Hour)),
+		// Retrieved twice, from the cache and from CloudTrail
+		writeEvent("1", "AuthorizeSecurityGroupIngress", "jdoe", start, sg),
+	}
+
+	resolver, err := NewIdentityResolver(nil, nil)
+	require.NoError(t, err)
+	summaries := SummarizeByResource(events, resolver)
+
+	require.Len(t, summaries, 4)
+	assert.Equal(t, "AWS::EC2::RouteTable", summaries[0].Type)
+	assert.Equal(t, "AWS::EC2::SecurityGroup", summaries[1].Type)
+	assert.Equal(t, "AWS::EC2::VPC", summaries[2].Type)
+	assert.Equal(t, noResourceType, summaries[3].Type)
+
+	require.Len(t, summaries[1].Changes, 2)
+	assert.Equal(t, "AuthorizeSecurityGroupIngress", summaries[1].Changes[0].Event)
+	assert.Equal(t, "RevokeSecurityGroupIngress", summaries[1].Changes[1].Event)
+	assert.Equal(t, "SRE (jdoe) [human]", summaries[1].Changes[0].Who)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, PrintSummary(out, summaries))
+	assert.Contains(t, out.String(), "AWS::EC2::SecurityGroup sg-0123: 2 change(s) from 2025-07-15 09:00:00 UTC to 2025-07-15 11:00:00 UTC")
+	assert.Equal(t, 4, strings.Count(out.String(), "change(s)"))
+
+	out.Reset()
+	require.NoError(t, PrintSummary(out, nil))
+	assert.Equal(t, "No write events found\n", out.String())
+}
+
+func TestPrinterWithSummary(t *testing.T) {
+	p := NewPrinter(false, false).WithSummary()
+	p.PrintEvents([]types.Event{writeEvent("1", "RunInstances", "jdoe", time.Now(), resource("AWS::EC2::Instance", "i-0123"))}, defaultFields)
+	p.PrintEvents([]types.Event{writeEvent("2", "TerminateInstances", "jdoe", time.Now(), resource("AWS::EC2::Instance", "i-0123"))}, defaultFields)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, p.PrintSummary(out))
+	assert.Contains(t, out.String(), "AWS::EC2::Instance i-0123: 2 change(s)")
+	assert.Contains(t, out.String(), "TerminateInstances")
+}
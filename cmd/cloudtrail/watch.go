@@ -0,0 +1,153 @@
+package cloudtrail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	logrus "github.com/sirupsen/logrus"
+)
+
+// watchWindow is how far back every poll looks. CloudTrail delivers most events within 5 minutes
+// but can take up to 15, so the window overlaps the previous polls and new events are found by ID.
+const watchWindow = 15 * time.Minute
+
+// eventLookup is the subset of the CloudTrail API used to watch the events
+type eventLookup interface {
+	LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error)
+}
+
+// eventWatcher polls the write events of a sliding window and prints the ones it has not seen yet
+type eventWatcher struct {
+	lookups    []eventLookup
+	filters    WriteEventFilters
+	identities *IdentityResolver
+	window     time.Duration
+	interval   time.Duration
+	now        func() time.Time
+	out        io.Writer
+	log        *logrus.Logger
+
+	// seen holds the time of the events already printed by ID, pruned once they leave the window
+	seen map[string]time.Time
+}
+
+func newEventWatcher(lookups []eventLookup, filters WriteEventFilters, identities *IdentityResolver, interval time.Duration, out io.Writer, log *logrus.Logger) *eventWatcher {
+	return &eventWatcher{
+		lookups:    lookups,
+		filters:    filters,
+		identities: identities,
+		window:     watchWindow,
+		interval:   interval,
+		now:        time.Now,
+		out:        out,
+		log:        log,
+		seen:       map[string]time.Time{},
+	}
+}
+
+// run polls and prints the new events every interval until the context is cancelled
+func (w *eventWatcher) run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.log.Infof("Watching write events every %v, press Ctrl+C to stop...", w.interval)
+	for {
+		events, err := w.poll(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			// Keep watching through throttling and other transient errors, the next poll covers the same window
+			w.log.Warnf("Failed to poll the events: %v", err)
+		}
+		w.print(events)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll returns the events of the window which were not returned by a previous poll, oldest first
+func (w *eventWatcher) poll(ctx context.Context) ([]types.Event, error) {
+	end := w.now()
+	start := end.Add(-w.window)
+
+	var events []types.Event
+	for _, lookup := range w.lookups {
+		fetched, err := lookupWriteEvents(ctx, lookup, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range fetched {
+			id := aws.ToString(event.EventId)
+			if _, ok := w.seen[id]; ok {
+				continue
+			}
+			w.seen[id] = aws.ToTime(event.EventTime)
+			events = append(events, event)
+		}
+	}
+
+	for id, eventTime := range w.seen {
+		if eventTime.Before(start) {
+			delete(w.seen, id)
+		}
+	}
+
+	events = Filters(w.filters, events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.ToTime(events[i].EventTime).Before(aws.ToTime(events[j].EventTime))
+	})
+	return events, nil
+}
+
+// print writes a line per event with the principal behind it and the error code of failed calls
+func (w *eventWatcher) print(events []types.Event) {
+	for _, event := range events {
+		errorCode := "-"
+		principal := aws.ToString(event.Username)
+		if raw, err := ExtractUserDetails(event.CloudTrailEvent); err == nil {
+			if raw.ErrorCode != "" {
+				errorCode = raw.ErrorCode
+			}
+			if w.identities != nil {
+				principal = w.identities.Resolve(raw, aws.ToString(event.Username)).String()
+			}
+		}
+		_, _ = fmt.Fprintf(w.out, "%s | %s | %s | Error: %s\n",
+			aws.ToTime(event.EventTime).UTC().Format(time.RFC3339), aws.ToString(event.EventName), principal, errorCode)
+	}
+}
+
+// lookupWriteEvents returns all the write events between start and end
+func lookupWriteEvents(ctx context.Context, lookup eventLookup, start, end time.Time) ([]types.Event, error) {
+	input := &cloudtrail.LookupEventsInput{
+		StartTime: aws.Time(start),
+		EndTime:   aws.Time(end),
+		LookupAttributes: []types.LookupAttribute{
+			{AttributeKey: types.LookupAttributeKeyReadOnly, AttributeValue: aws.String("false")},
+		},
+	}
+
+	var events []types.Event
+	for {
+		output, err := lookup.LookupEvents(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, output.Events...)
+		if aws.ToString(output.NextToken) == "" {
+			return events, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
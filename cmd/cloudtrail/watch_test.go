@@ -0,0 +1,123 @@
+package cloudtrail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	logrus "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEventLookup returns its events two per page, ignoring the window
+type fakeEventLookup struct {
+	events []types.Event
+	err    error
+
+	mu     sync.Mutex
+	inputs []cloudtrail.LookupEventsInput
+}
+
+func (f *fakeEventLookup) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.inputs)
+}
+
+func (f *fakeEventLookup) LookupEvents(_ context.Context, params *cloudtrail.LookupEventsInput, _ ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	f.mu.Lock()
+	f.inputs = append(f.inputs, *params)
+	f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	start := 0
+	if params.NextToken != nil {
+		start = 2
+	}
+	end := min(start+2, len(f.events))
+	output := &cloudtrail.LookupEventsOutput{Events: f.events[start:end]}
+	if end < len(f.events) {
+		output.NextToken = aws.String("next")
+	}
+	return output, nil
+}
+
+func TestEventWatcherPoll(t *testing.T) {
+	now := time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC)
+	regional := &fakeEventLookup{events: []types.Event{
+		writeEvent("2", "RunInstances", "jdoe", now.Add(-time.Minute)),
+		writeEvent("1", "CreateSecurityGroup", "jdoe", now.Add(-2*time.Minute)),
+		writeEvent("3", "DeleteBucket", "system", now.Add(-3*time.Minute)),
+	}}
+	global := &fakeEventLookup{events: []types.Event{
+		writeEvent("4", "AttachRolePolicy", "jdoe", now.Add(-4*time.Minute)),
+	}}
+
+	watcher := newEventWatcher([]eventLookup{regional, global}, WriteEventFilters{Exclude: []string{"username=system"}}, nil, time.Second, &bytes.Buffer{}, logrus.New())
+	watcher.now = func() time.Time { return now }
+
+	events, err := watcher.poll(context.Background())
+	require.NoError(t, err)
+	var names []string
+	for _, event := range events {
+		names = append(names, aws.ToString(event.EventName))
+	}
+	assert.Equal(t, []string{"AttachRolePolicy", "CreateSecurityGroup", "RunInstances"}, names)
+	require.Len(t, regional.inputs, 2)
+	assert.Equal(t, now.Add(-watchWindow), aws.ToTime(regional.inputs[0].StartTime))
+	assert.Equal(t, types.LookupAttributeKeyReadOnly, regional.inputs[0].LookupAttributes[0].AttributeKey)
+
+	// Only the events delivered since the previous poll are returned
+	regional.events = append(regional.events, writeEvent("5", "TerminateInstances", "jdoe", now.Add(-5*time.Minute)))
+	events, err = watcher.poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "TerminateInstances", aws.ToString(events[0].EventName))
+
+	// The events which left the window are forgotten
+	watcher.now = func() time.Time { return now.Add(watchWindow - 90*time.Second) }
+	_, err = watcher.poll(context.Background())
+	require.NoError(t, err)
+	assert.NotContains(t, watcher.seen, "4")
+	assert.NotContains(t, watcher.seen, "5")
+	assert.Contains(t, watcher.seen, "2")
+}
+
+func TestEventWatcherPrint(t *testing.T) {
+	resolver, err := NewIdentityResolver(nil, nil)
+	require.NoError(t, err)
+
+	at := time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC)
+	denied := writeEvent("1", "RunInstances", "jdoe", at)
+	denied.CloudTrailEvent = aws.String(`{"eventVersion": "1.08", "errorCode": "Client.UnauthorizedOperation", "userIdentity": {"type": "AssumedRole",
+		"arn": "arn:aws:sts::123456789012:assumed-role/ManagedOpenShift-Support-abcd/jdoe",
+		"sessionContext": {"sessionIssuer": {"type": "Role", "userName": "ManagedOpenShift-Support-abcd",
+		"arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-abcd"}}}}`)
+
+	out := &bytes.Buffer{}
+	watcher := newEventWatcher(nil, WriteEventFilters{}, resolver, time.Second, out, logrus.New())
+	watcher.print([]types.Event{denied, writeEvent("2", "CreateTags", "jdoe", at.Add(time.Second))})
+
+	assert.Equal(t, "2025-07-15T09:00:00Z | RunInstances | SRE (jdoe) [human] | Error: Client.UnauthorizedOperation\n"+
+		"2025-07-15T09:00:01Z | CreateTags | SRE (jdoe) [human] | Error: -\n", out.String())
+}
+
+func TestEventWatcherRunStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lookup := &fakeEventLookup{err: errors.New("ThrottlingException")}
+	watcher := newEventWatcher([]eventLookup{lookup}, WriteEventFilters{}, nil, 10*time.Millisecond, &bytes.Buffer{}, logrus.New())
+
+	done := make(chan error)
+	go func() { done <- watcher.run(ctx) }()
+	assert.Eventually(t, func() bool { return lookup.calls() >= 2 }, time.Second, 5*time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -1,23 +1,34 @@
 package cloudtrail
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"sort"
-	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
-	"github.com/openshift/osdctl/pkg/osdCloud"
+	config "github.com/openshift/osdctl/pkg/envConfig"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/openshift/osdctl/pkg/utils"
 	logrus "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
-const DEFAULT_REGION = "us-east-1"
+// GetGlobalRegion returns the region global services (IAM, STS...) log their events to
+// for the partition the given region belongs to.
+func GetGlobalRegion(region string) string {
+	return awsprovider.GetDefaultRegion(awsprovider.GetPartitionForRegion(region))
+}
 
 var defaultFields = []string{"event", "time", "username", "arn"}
 
+// writeEventsDefaultFields additionally shows the resolved identity behind each event
+var writeEventsDefaultFields = []string{"event", "time", "username", "identity", "arn"}
+
 // LookupEventsOptions struct for holding options for event lookup
 type writeEventsOptions struct {
 	ClusterID   string
@@ -28,11 +39,17 @@ type writeEventsOptions struct {
 	PrintRaw    bool
 	PrintFields []string
 	Cache       bool
+	IAMTags     bool
+	Summarize   bool
+	Watch       bool
+
+	WatchInterval time.Duration
 
-	awsAPI   *EventAPI
-	printer  *Printer
-	log      *logrus.Logger
-	logLevel string
+	awsAPI    *EventAPI
+	awsConfig *awsprovider.ClusterConfigFactory
+	printer   *Printer
+	log       *logrus.Logger
+	logLevel  string
 
 	missingPeriod []Period
 }
@@ -47,7 +64,16 @@ const (
     $ osdctl cloudtrail write-events -C cluster-id --after 2025-07-15,15:00:00 --since 2h --url
 
     # Get all events until the specified time since the last 2 hours; print raw-event
-    $ osdctl cloudtrail write-events -C cluster-id --after 2025-07-15,15:00:00 --since 2h --raw-event`
+    $ osdctl cloudtrail write-events -C cluster-id --after 2025-07-15,15:00:00 --since 2h --raw-event
+
+    # Resolve roles which are not known to osdctl through the tags set on ROSA account and operator roles
+    $ osdctl cloudtrail write-events -C cluster-id --since 2h --iam-tags
+
+    # Summarize who changed which resource (instances, security groups, route tables...) over the last day
+    $ osdctl cloudtrail write-events -C cluster-id --since 24h --summarize
+
+    # Stream the write events as they arrive while reproducing an issue, polling every 30s
+    $ osdctl cloudtrail write-events -C cluster-id --watch`
 
 	cloudtrailWriteEventsDescription = `
 	Lists AWS CloudTrail write events for a specific OpenShift/ROSA cluster with advanced 
@@ -57,11 +83,28 @@ const (
 	the appropriate AWS role for the target cluster to access CloudTrail logs.
 
 	By default, the command filters out system and service account events using patterns 
-	from the osdctl configuration file. `
+	from the osdctl configuration file.
+
+	The identity field resolves assumed-role sessions to the person or automation behind
+	them, distinguishing SRE actions from operators like machine-api. Well-known roles are
+	resolved by default, additional ones can be mapped in the osdctl configuration file:
+
+	cloudtrail_cmd_lists:
+	  identity_mappings:
+	    - pattern: ":role/my-automation-role$"
+	      name: "my-automation"
+	      kind: automation
+
+	With --summarize, the events are grouped by the resource they affect instead, each with
+	the list of changes, who made them and when, for "who touched the VPC" investigations.
+
+	With --watch, the events of the last 15 minutes are polled repeatedly and each new
+	one is printed once with the identity behind it and its error code, until interrupted.
+	CloudTrail can take up to 15 minutes to deliver an event, so they may show up late.`
 )
 
 func newCmdWriteEvents() *cobra.Command {
-	ops := &writeEventsOptions{}
+	ops := &writeEventsOptions{awsConfig: awsprovider.NewClusterConfigFactory()}
 	fil := &WriteEventFilters{}
 	listEventsCmd := &cobra.Command{
 		Use:     "write-events",
@@ -71,7 +114,9 @@ func newCmdWriteEvents() *cobra.Command {
 		Args:    cobra.NoArgs,
 		PreRunE: func(cmd *cobra.Command, args []string) error { return ops.preRun(*fil) },
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return ops.run(*fil)
+			ctx, stop := utils.NotifyInterrupt(cmd.Context(), cmd.ErrOrStderr())
+			defer stop()
+			return ops.run(ctx, *fil)
 		},
 	}
 	listEventsCmd.Flags().StringVarP(&ops.ClusterID, "cluster-id", "C", "", "Cluster ID")
@@ -83,15 +128,25 @@ func newCmdWriteEvents() *cobra.Command {
 
 	listEventsCmd.Flags().BoolVarP(&ops.PrintUrl, "url", "u", false, "Generates Url link to cloud console cloudtrail event")
 	listEventsCmd.Flags().BoolVarP(&ops.PrintRaw, "raw-event", "r", false, "Prints the cloudtrail events to the console in raw json format")
-	listEventsCmd.Flags().StringSliceVarP(&ops.PrintFields, "print-fields", "", defaultFields, "Prints all cloudtrail write events in selected format. Can specify (username, time, event, arn, resource-name, resource-type, arn). i.e --print-format username,time,event")
+	listEventsCmd.Flags().StringSliceVarP(&ops.PrintFields, "print-fields", "", writeEventsDefaultFields, "Prints all cloudtrail write events in selected format. Can specify (username, time, event, identity, arn, resource-name, resource-type). i.e --print-format username,time,event")
+	listEventsCmd.Flags().BoolVar(&ops.Summarize, "summarize", false, "Group the events by affected resource, showing who changed what and when")
+	listEventsCmd.Flags().BoolVar(&ops.Watch, "watch", false, "Keep polling for new write events and print them as they arrive, until interrupted")
+	listEventsCmd.Flags().DurationVar(&ops.WatchInterval, "watch-interval", 30*time.Second, "Interval between the polls of --watch")
+	ops.awsConfig.AddRegionFlag(listEventsCmd.Flags())
+	listEventsCmd.Flags().BoolVar(&ops.IAMTags, "iam-tags", false, "Resolve the identity of roles not matching any mapping through their IAM tags (requires iam:ListRoleTags)")
 
 	listEventsCmd.Flags().StringSliceVarP(&fil.Include, "include", "I", nil, "Filter events by inclusion. (i.e. \"-I username=, -I event=, -I resource-name=, -I resource-type=, -I arn=\")")
 	listEventsCmd.Flags().StringSliceVarP(&fil.Exclude, "exclude", "E", nil, "Filter events by exclusion. (i.e. \"-E username=, -E event=, -E resource-name=, -E resource-type=, -E arn=\")")
 	listEventsCmd.MarkFlagRequired("cluster-id")
+	listEventsCmd.MarkFlagsMutuallyExclusive("summarize", "raw-event")
+	listEventsCmd.MarkFlagsMutuallyExclusive("summarize", "url")
+	for _, flag := range []string{"summarize", "raw-event", "url", "after", "until"} {
+		listEventsCmd.MarkFlagsMutuallyExclusive("watch", flag)
+	}
 	return listEventsCmd
 }
 
-func (o *writeEventsOptions) getPages(filters WriteEventFilters, region string, requestedPeriod Period) error {
+func (o *writeEventsOptions) getPages(ctx context.Context, filters WriteEventFilters, region string, requestedPeriod Period) error {
 	cache, err := NewCache(o.log, o.ClusterID)
 	if err != nil {
 		return err
@@ -145,17 +200,29 @@ func (o *writeEventsOptions) getPages(filters WriteEventFilters, region string,
 		}
 
 		var missingEvents []types.Event
-		generator := o.awsAPI.GetEvents(o.ClusterID, currentPeriod)
+		generator := o.awsAPI.GetEvents(ctx, o.ClusterID, currentPeriod)
 		for page := range generator {
 			o.log.Debug("\n Retrieving Pages \n")
 			if page.errors != nil {
-				o.log.Errorf("Error fetching events: %v", page.errors)
+				if ctx.Err() == nil {
+					o.log.Errorf("Error fetching events: %v", page.errors)
+				}
 				continue
 			}
 			missingEvents = append(missingEvents, page.AWSEvent...)
 		}
 
 		fetchedEvents := Filters(filters, missingEvents)
+		if ctx.Err() != nil {
+			// The period was only partially retrieved, so it isn't cached
+			fmt.Println(utils.PartialResultsBanner)
+			o.printer.PrintEvents(fetchedEvents, o.PrintFields)
+			o.missingPeriod = []Period{}
+			if err := cache.Save(newCacheData); err != nil {
+				o.log.Warnf("Failed to save the events retrieved before the interruption into the cache: %v", err)
+			}
+			return fmt.Errorf("retrieving events interrupted: %w", ctx.Err())
+		}
 		o.printer.PrintEvents(fetchedEvents, o.PrintFields)
 
 		// if startTimePeriod is out of range, create a period starting
@@ -202,6 +269,9 @@ func (o *writeEventsOptions) preRun(filters WriteEventFilters) error {
 	if err := ValidateFormat(o.PrintFields); err != nil {
 		return err
 	}
+	if o.Watch && o.WatchInterval < time.Second {
+		return fmt.Errorf("--watch-interval must be at least 1s")
+	}
 
 	log := logrus.New()
 	level, err := logrus.ParseLevel(o.logLevel)
@@ -221,7 +291,7 @@ func (o *writeEventsOptions) preRun(filters WriteEventFilters) error {
 
 }
 
-func (o *writeEventsOptions) run(filters WriteEventFilters) error {
+func (o *writeEventsOptions) run(ctx context.Context, filters WriteEventFilters) error {
 	connection, err := utils.CreateConnection()
 	if err != nil {
 		o.log.Error("unable to create connection to ocm: %w", err)
@@ -233,12 +303,7 @@ func (o *writeEventsOptions) run(filters WriteEventFilters) error {
 	if err != nil {
 		return err
 	}
-	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
-		o.log.Error("this command is only available for AWS clusters")
-		return err
-	}
-
-	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	cfg, err := o.awsConfig.Config(connection, cluster)
 	if err != nil {
 		return err
 	}
@@ -247,6 +312,9 @@ func (o *writeEventsOptions) run(filters WriteEventFilters) error {
 	if err != nil {
 		return err
 	}
+	if o.Watch {
+		return o.watch(ctx, cfg, filters, accountId, arn)
+	}
 	startTime, endTime, err := ParseStartEndTime(o.StartTime, o.EndTime, o.Duration)
 	if err != nil {
 		return err
@@ -257,25 +325,79 @@ func (o *writeEventsOptions) run(filters WriteEventFilters) error {
 	o.awsAPI = NewEventAPI(cfg, true, cfg.Region)
 	o.printer = NewPrinter(o.PrintUrl, o.PrintRaw)
 
+	identities, err := o.newIdentityResolver(cfg)
+	if err != nil {
+		return err
+	}
+	o.printer.WithIdentityResolver(identities)
+	if o.Summarize {
+		o.printer.WithSummary()
+	}
+
 	requestedPeriod := Period{StartTime: startTime, EndTime: endTime}
 
-	err = o.getPages(filters, cfg.Region, requestedPeriod)
+	err = o.getPages(ctx, filters, cfg.Region, requestedPeriod)
 	if err != nil {
-		return err
+		return o.printPartialSummary(err)
 	}
 
 	fmt.Println("")
-	if DEFAULT_REGION != cfg.Region {
+	if globalRegion := GetGlobalRegion(cfg.Region); globalRegion != cfg.Region {
 
-		o.log.Infof("Retrieving from %s...", DEFAULT_REGION)
-		defaultAwsAPI := NewEventAPI(cfg, true, DEFAULT_REGION)
+		o.log.Infof("Retrieving from %s...", globalRegion)
+		defaultAwsAPI := NewEventAPI(cfg, true, globalRegion)
 		o.awsAPI = defaultAwsAPI
 
-		err = o.getPages(filters, DEFAULT_REGION, requestedPeriod)
+		err = o.getPages(ctx, filters, globalRegion, requestedPeriod)
 		if err != nil {
-			return err
+			return o.printPartialSummary(err)
 		}
 	}
 
+	if o.Summarize {
+		return o.printer.PrintSummary(os.Stdout)
+	}
 	return nil
 }
+
+// watch prints the new write events of the regional and global CloudTrail until the context is cancelled
+func (o *writeEventsOptions) watch(ctx context.Context, cfg aws.Config, filters WriteEventFilters, accountId, arn string) error {
+	identities, err := o.newIdentityResolver(cfg)
+	if err != nil {
+		return err
+	}
+
+	lookups := []eventLookup{NewEventAPI(cfg, true, cfg.Region).client}
+	regions := cfg.Region
+	if globalRegion := GetGlobalRegion(cfg.Region); globalRegion != cfg.Region {
+		lookups = append(lookups, NewEventAPI(cfg, true, globalRegion).client)
+		regions += ", " + globalRegion
+	}
+
+	o.log.Infof("Watching write events for AWS Account %v as %v in %v", accountId, arn, regions)
+	return newEventWatcher(lookups, filters, identities, o.WatchInterval, os.Stdout, o.log).run(ctx)
+}
+
+// printPartialSummary prints the summary of the events retrieved before an interruption, and returns err
+func (o *writeEventsOptions) printPartialSummary(err error) error {
+	if o.Summarize && errors.Is(err, context.Canceled) {
+		if printErr := o.printer.PrintSummary(os.Stdout); printErr != nil {
+			o.log.Warnf("Failed to print the summary of the events retrieved before the interruption: %v", printErr)
+		}
+	}
+	return err
+}
+
+// newIdentityResolver creates the resolver for the identity field using the mappings of the osdctl configuration file
+func (o *writeEventsOptions) newIdentityResolver(cfg aws.Config) (*IdentityResolver, error) {
+	mappings, err := config.LoadCloudTrailIdentityMappings()
+	if err != nil {
+		o.log.Warnf("Failed to load cloudtrail identity mappings, only the default ones are used: %v", err)
+	}
+
+	var tagLookup RoleTagLookup
+	if o.IAMTags {
+		tagLookup = NewIAMRoleTagLookup(cfg)
+	}
+	return NewIdentityResolver(mappings, tagLookup)
+}
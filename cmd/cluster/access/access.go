@@ -12,6 +12,7 @@ import (
 	"time"
 
 	clustersmgmtv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/danger"
 	"github.com/openshift/osdctl/pkg/k8s"
 	osdctlutil "github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -72,7 +73,7 @@ func NewCmdAccess(streams genericclioptions.IOStreams, client *k8s.LazyClient) *
 	_ = accessCmd.MarkFlagRequired("reason")
 	_ = accessCmd.MarkFlagRequired("cluster-id")
 
-	return accessCmd
+	return danger.Annotate(accessCmd, danger.Medium)
 }
 
 // clusterAccessOptions contains the objects and information required to access a cluster
@@ -116,7 +117,11 @@ func (c *clusterAccessOptions) Readln() (string, error) {
 
 // accessCmdComplete verifies the command's invocation, returning an error if the usage is invalid
 func (c *clusterAccessOptions) accessCmdComplete() error {
-	if err := osdctlutil.IsValidClusterKey(c.clusterID); err != nil {
+	if err := osdctlutil.ValidateClusterKey("cluster-id", c.clusterID); err != nil {
+		return err
+	}
+
+	if err := osdctlutil.ValidateReason("reason", c.reason); err != nil {
 		return err
 	}
 
@@ -152,6 +157,9 @@ func (c *clusterAccessOptions) Run(ctx context.Context) error {
 		return err
 	}
 	c.Println(fmt.Sprintf("Internal Cluster ID: %s", cluster.ID()))
+	if ok, err := danger.Confirm(danger.Medium, cluster); err != nil || !ok {
+		return err
+	}
 	c.Println(fmt.Sprintf("Retrieving Kubeconfig for cluster '%s'", c.clusterID))
 
 	if c.hiveOcmUrl != "" {
@@ -439,12 +439,13 @@ func TestGetKubeConfigSecret(t *testing.T) {
 	}
 }
 
-// TestClusterAccessOptions_accessCmdComplete tests the early validation of cluster-id and hive-ocm-url
+// TestClusterAccessOptions_accessCmdComplete tests the early validation of cluster-id, reason and hive-ocm-url
 func TestClusterAccessOptions_accessCmdComplete(t *testing.T) {
 	tests := []struct {
 		name        string
 		clusterID   string
 		hiveOcmUrl  string
+		reason      string
 		expectErr   bool
 		errContains string
 	}{
@@ -490,15 +491,34 @@ func TestClusterAccessOptions_accessCmdComplete(t *testing.T) {
 			clusterID:   "",
 			hiveOcmUrl:  "",
 			expectErr:   true,
-			errContains: "isn't valid",
+			errContains: "--cluster-id is required",
+		},
+		{
+			name:        "Missing reason",
+			clusterID:   "test-cluster-123",
+			reason:      " ",
+			expectErr:   true,
+			errContains: "--reason is required",
+		},
+		{
+			name:        "Malformed ticket in reason",
+			clusterID:   "test-cluster-123",
+			reason:      "OHSS1234",
+			expectErr:   true,
+			errContains: "malformed ticket",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			reason := tt.reason
+			if reason == "" {
+				reason = "OHSS-1234"
+			}
 			c := &clusterAccessOptions{
 				clusterID:  tt.clusterID,
 				hiveOcmUrl: tt.hiveOcmUrl,
+				reason:     reason,
 			}
 
 			err := c.accessCmdComplete()
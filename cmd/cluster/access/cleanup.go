@@ -6,16 +6,22 @@ import (
 	"os"
 	fpath "path/filepath"
 	"strings"
+	"time"
 
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	clustersmgmtv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	certificatesv1alpha1 "github.com/openshift/hypershift/api/certificates/v1alpha1"
+	"github.com/openshift/osdctl/pkg/elevate"
 	"github.com/openshift/osdctl/pkg/k8s"
 	osdctlutil "github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -23,11 +29,18 @@ import (
 func newCmdCleanup(client *k8s.LazyClient, streams genericclioptions.IOStreams) *cobra.Command {
 	ops := newCleanupAccessOptions(client, streams)
 	cleanupCmd := &cobra.Command{
-		Use:   "cleanup --cluster-id <cluster-identifier>",
+		Use:   "cleanup (--cluster-id <cluster-identifier> | --mc <management-cluster-identifier>)",
 		Short: "Drop emergency access to a cluster",
-		Long:  "Relinquish emergency access from the given cluster. If the cluster is PrivateLink, it deletes\nall jump pods in the cluster's namespace (because of this, you must be logged into the hive shard\nwhen dropping access for PrivateLink clusters). For non-PrivateLink clusters, the $KUBECONFIG\nenvironment variable is unset, if applicable.",
+		Long: "Relinquish emergency access from the given cluster. If the cluster is PrivateLink, it deletes\nall jump pods in the cluster's namespace (because of this, you must be logged into the hive shard\nwhen dropping access for PrivateLink clusters). For non-PrivateLink clusters, the $KUBECONFIG\nenvironment variable is unset, if applicable." +
+			"\n\nWith --mc, the HCP namespaces of the management cluster are swept instead: the expired break-glass\ncredentials not revoked yet are revoked in OCM (with the active ones too with --include-active, OCM\nrevoking all the credentials of a cluster at once), and the certificate signing requests of break-glass\nclient certificates and their approvals older than --max-age are deleted. What was revoked is listed.",
 		Example: `  # Drop emergency access to a cluster
-  osdctl cluster break-glass cleanup --cluster-id ${CLUSTER_ID}`,
+  osdctl cluster break-glass cleanup --cluster-id ${CLUSTER_ID}
+
+  # List the expired and forgotten break-glass access of the hosted clusters of a management cluster
+  osdctl cluster break-glass cleanup --mc ${MC_ID} --reason "${REASON}" --dry-run
+
+  # Revoke them, including the break-glass credentials still valid
+  osdctl cluster break-glass cleanup --mc ${MC_ID} --reason "${REASON}" --include-active`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -36,19 +49,42 @@ func newCmdCleanup(client *k8s.LazyClient, streams genericclioptions.IOStreams)
 		},
 	}
 	cleanupCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "[Mandatory] Provide the Internal ID of the cluster")
-	cleanupCmd.Flags().StringVar(&ops.reason, "reason", "", "[Mandatory for PrivateLink clusters] The reason for this command, which requires elevation, to be run (usualy an OHSS or PD ticket)")
+	cleanupCmd.Flags().StringVar(&ops.reason, "reason", "", "[Mandatory for PrivateLink clusters and --mc] The reason for this command, which requires elevation, to be run (usualy an OHSS or PD ticket)")
+	cleanupCmd.Flags().StringVar(&ops.mcID, "mc", "", "Sweep the expired and forgotten break-glass access of all the hosted clusters of this management cluster")
+	cleanupCmd.Flags().DurationVar(&ops.maxAge, "max-age", jumpPodLifespan*time.Second, "With --mc, age after which the certificate signing requests of break-glass client certificates and their approvals are deleted")
+	cleanupCmd.Flags().BoolVar(&ops.includeActive, "include-active", false, "With --mc, also revoke the break-glass credentials which haven't expired yet")
+	cleanupCmd.Flags().BoolVar(&ops.dryRun, "dry-run", false, "With --mc, only list the break-glass access which would be revoked")
 
-	_ = cleanupCmd.MarkFlagRequired("cluster-id")
+	cleanupCmd.MarkFlagsOneRequired("cluster-id", "mc")
+	cleanupCmd.MarkFlagsMutuallyExclusive("cluster-id", "mc")
 
 	return cleanupCmd
 }
 
 func cleanupCmdComplete(cmd *cobra.Command) error {
 	clusterID, _ := cmd.Flags().GetString("cluster-id")
-	if clusterID == "" {
-		return cmdutil.UsageErrorf(cmd, "The cluster-id flag is required")
+	mcID, _ := cmd.Flags().GetString("mc")
+	if clusterID == "" && mcID == "" {
+		return cmdutil.UsageErrorf(cmd, "The cluster-id or mc flag is required")
+	}
+	if mcID != "" {
+		if err := osdctlutil.IsValidClusterKey(mcID); err != nil {
+			return err
+		}
+		reason, _ := cmd.Flags().GetString("reason")
+		if reason == "" {
+			return cmdutil.UsageErrorf(cmd, "The reason flag is required with --mc, revoking break-glass access requires elevation")
+		}
+		return osdctlutil.ValidateReason("reason", reason)
+	}
+	if err := osdctlutil.IsValidClusterKey(clusterID); err != nil {
+		return err
 	}
-	return osdctlutil.IsValidClusterKey(clusterID)
+	// The reason is only required for PrivateLink clusters, which is checked once the cluster is retrieved
+	if reason, _ := cmd.Flags().GetString("reason"); reason != "" {
+		return osdctlutil.ValidateReason("reason", reason)
+	}
+	return nil
 }
 
 // cleanupAccessOptions contains the objects and information required to drop access to a cluster
@@ -56,6 +92,12 @@ type cleanupAccessOptions struct {
 	reason    string
 	clusterID string
 
+	// The flags of the sweep of the hosted clusters of a management cluster
+	mcID          string
+	maxAge        time.Duration
+	includeActive bool
+	dryRun        bool
+
 	genericclioptions.IOStreams
 	kubeCli *k8s.LazyClient
 }
@@ -101,6 +143,10 @@ func (c *cleanupAccessOptions) Run(cmd *cobra.Command) error {
 		cmdutil.CheckErr(conn.Close())
 	}()
 
+	if c.mcID != "" {
+		return c.sweepManagementCluster(cmd.Context(), conn)
+	}
+
 	cluster, err := osdctlutil.GetCluster(conn, c.clusterID)
 	if err != nil {
 		return err
@@ -233,3 +279,47 @@ func (c *cleanupAccessOptions) dropLocalAccess(cluster *clustersmgmtv1.Cluster)
 	c.Println("Access has been dropped.")
 	return nil
 }
+
+// sweepManagementCluster revokes the expired and forgotten break-glass access of the hosted clusters of the
+// management cluster
+func (c *cleanupAccessOptions) sweepManagementCluster(ctx context.Context, conn *sdk.Connection) error {
+	mc, err := osdctlutil.GetCluster(conn, c.mcID)
+	if err != nil {
+		return err
+	}
+	isMC, err := osdctlutil.IsManagementCluster(mc.ID())
+	if err != nil {
+		return fmt.Errorf("failed to verify management cluster %s: %w", mc.ID(), err)
+	}
+	if !isMC {
+		return fmt.Errorf("cluster %s is not a management cluster", mc.ID())
+	}
+
+	sweepScheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(sweepScheme); err != nil {
+		return err
+	}
+	if err := certificatesv1alpha1.AddToScheme(sweepScheme); err != nil {
+		return err
+	}
+	kubeCli, err := elevate.NewClientWithConn(mc.ID(), kclient.Options{Scheme: sweepScheme}, conn, elevate.Reason{
+		Ticket:        c.reason,
+		Justification: "revoke the break-glass access of the hosted clusters",
+		Command:       "cluster break-glass cleanup",
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Println(fmt.Sprintf("Sweeping the break-glass access of the hosted clusters of management cluster '%s'", mc.Name()))
+	sweep := &sweepOptions{
+		maxAge:        c.maxAge,
+		includeActive: c.includeActive,
+		dryRun:        c.dryRun,
+		out:           c.Out,
+		kubeCli:       kubeCli,
+		credentials:   &ocmBreakGlassCredentials{conn: conn},
+		now:           time.Now,
+	}
+	return sweep.run(ctx)
+}
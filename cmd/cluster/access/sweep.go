@@ -0,0 +1,256 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	clustersmgmtv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	certificatesv1alpha1 "github.com/openshift/hypershift/api/certificates/v1alpha1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	sweepKindCredentials = "break-glass credentials"
+	sweepKindCSR         = "certificate signing request"
+	sweepKindCSRApproval = "certificate signing request approval"
+)
+
+// breakGlassSignerClasses are the classes of the signers of the break-glass client certificates of a hosted control
+// plane, see the CertificateRevocationRequest of hypershift
+var breakGlassSignerClasses = []string{"customer-break-glass", "sre-break-glass"}
+
+// breakGlassSignerName returns the name of the signer of a break-glass class of the hosted control plane namespace
+func breakGlassSignerName(namespace, signerClass string) string {
+	return fmt.Sprintf("hypershift.openshift.io/%s.%s", namespace, signerClass)
+}
+
+// breakGlassCredentials is the part of the OCM break-glass credentials API used by the sweep
+type breakGlassCredentials interface {
+	list(clusterID string) ([]*clustersmgmtv1.BreakGlassCredential, error)
+	// revoke revokes all the break-glass credentials of the cluster, OCM doesn't revoke them one by one
+	revoke(clusterID string) error
+}
+
+// sweepTarget is a break-glass credential or artifact the sweep revokes
+type sweepTarget struct {
+	clusterID string
+	namespace string
+	kind      string
+	name      string
+	reason    string
+	result    string
+}
+
+// sweepOptions finds and revokes the expired and forgotten break-glass access of the hosted clusters of a
+// management cluster
+type sweepOptions struct {
+	maxAge        time.Duration
+	includeActive bool
+	dryRun        bool
+
+	out         io.Writer
+	kubeCli     kclient.Client
+	credentials breakGlassCredentials
+	now         func() time.Time
+}
+
+// run sweeps the HCP namespaces of the management cluster
+func (s *sweepOptions) run(ctx context.Context) error {
+	nsList := corev1.NamespaceList{}
+	if err := s.kubeCli.List(ctx, &nsList, kclient.HasLabels{hiveNSLabelKey}); err != nil {
+		return fmt.Errorf("failed to list the namespaces of the hosted clusters: %w", err)
+	}
+
+	// The break-glass certificate signing requests are cluster scoped, they are matched to the namespaces by signer
+	csrs := certificatesv1.CertificateSigningRequestList{}
+	if err := s.kubeCli.List(ctx, &csrs); err != nil {
+		return fmt.Errorf("failed to list the certificate signing requests: %w", err)
+	}
+
+	var targets []*sweepTarget
+	credentialsChecked := map[string]bool{}
+	for _, ns := range nsList.Items {
+		clusterID := ns.Labels[hiveNSLabelKey]
+		if !credentialsChecked[clusterID] {
+			credentialsChecked[clusterID] = true
+			target, err := s.findCredentials(clusterID)
+			if err != nil {
+				_, _ = fmt.Fprintf(s.out, "Failed to list the break-glass credentials of cluster %s: %v\n", clusterID, err)
+			} else if target != nil {
+				target.namespace = ns.Name
+				targets = append(targets, target)
+			}
+		}
+
+		artifacts, err := s.findArtifacts(ctx, clusterID, ns.Name, csrs.Items)
+		if err != nil {
+			_, _ = fmt.Fprintf(s.out, "Failed to list the break-glass artifacts of namespace %s: %v\n", ns.Name, err)
+			continue
+		}
+		targets = append(targets, artifacts...)
+	}
+
+	if len(targets) == 0 {
+		_, _ = fmt.Fprintf(s.out, "No expired or forgotten break-glass access found in %d namespace(s).\n", len(nsList.Items))
+		return nil
+	}
+
+	if s.dryRun {
+		for _, target := range targets {
+			target.result = "would be revoked"
+		}
+		printSweepTargets(s.out, targets)
+		return nil
+	}
+
+	printSweepTargets(s.out, targets)
+	if !prompt.ConfirmPrompt() {
+		_, _ = fmt.Fprintln(s.out, "Nothing was revoked.")
+		return nil
+	}
+
+	var failures int
+	for _, target := range targets {
+		if err := s.revoke(ctx, target); err != nil {
+			target.result = fmt.Sprintf("failed: %v", err)
+			failures++
+		} else {
+			target.result = "revoked"
+		}
+	}
+	_, _ = fmt.Fprintln(s.out)
+	printSweepTargets(s.out, targets)
+	if failures > 0 {
+		return fmt.Errorf("failed to revoke %d of %d break-glass access(es)", failures, len(targets))
+	}
+	return nil
+}
+
+// findCredentials returns the break-glass credentials of the cluster to revoke: the expired ones not revoked yet,
+// and with includeActive the ones still valid
+func (s *sweepOptions) findCredentials(clusterID string) (*sweepTarget, error) {
+	credentials, err := s.credentials.list(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired, active int
+	for _, credential := range credentials {
+		switch credential.Status() {
+		case clustersmgmtv1.BreakGlassCredentialStatusExpired:
+			expired++
+		case clustersmgmtv1.BreakGlassCredentialStatusIssued, clustersmgmtv1.BreakGlassCredentialStatusCreated:
+			if expiration, ok := credential.GetExpirationTimestamp(); ok && expiration.Before(s.now()) {
+				expired++
+			} else {
+				active++
+			}
+		}
+	}
+
+	reason := ""
+	switch {
+	case expired > 0 && active > 0 && !s.includeActive:
+		// Revoking the expired credentials would revoke the active ones too
+		_, _ = fmt.Fprintf(s.out, "Cluster %s has %d expired and %d active break-glass credential(s), revoking them requires --include-active\n", clusterID, expired, active)
+		return nil, nil
+	case expired > 0 && active > 0:
+		reason = fmt.Sprintf("%d expired and %d active credential(s)", expired, active)
+	case expired > 0:
+		reason = fmt.Sprintf("%d expired credential(s) not revoked", expired)
+	case active > 0 && s.includeActive:
+		reason = fmt.Sprintf("%d active credential(s)", active)
+	default:
+		return nil, nil
+	}
+	return &sweepTarget{clusterID: clusterID, kind: sweepKindCredentials, reason: reason}, nil
+}
+
+// findArtifacts returns the certificate signing requests of break-glass client certificates of the hosted control
+// plane namespace, and their approvals, left for longer than maxAge. The issued certificates stay valid until their
+// credentials are revoked, the requests hold them.
+func (s *sweepOptions) findArtifacts(ctx context.Context, clusterID string, namespace string, csrs []certificatesv1.CertificateSigningRequest) ([]*sweepTarget, error) {
+	var targets []*sweepTarget
+
+	signers := make([]string, 0, len(breakGlassSignerClasses))
+	for _, class := range breakGlassSignerClasses {
+		signers = append(signers, breakGlassSignerName(namespace, class))
+	}
+	for _, csr := range csrs {
+		if !slices.Contains(signers, csr.Spec.SignerName) {
+			continue
+		}
+		if age := s.now().Sub(csr.CreationTimestamp.Time); age > s.maxAge {
+			targets = append(targets, &sweepTarget{clusterID: clusterID, namespace: namespace, kind: sweepKindCSR, name: csr.Name, reason: fmt.Sprintf("created %s ago", age.Round(time.Minute))})
+		}
+	}
+
+	// The approvals are only used for the break-glass signers of the hosted control plane
+	approvals := certificatesv1alpha1.CertificateSigningRequestApprovalList{}
+	if err := s.kubeCli.List(ctx, &approvals, kclient.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for _, approval := range approvals.Items {
+		if age := s.now().Sub(approval.CreationTimestamp.Time); age > s.maxAge {
+			targets = append(targets, &sweepTarget{clusterID: clusterID, namespace: namespace, kind: sweepKindCSRApproval, name: approval.Name, reason: fmt.Sprintf("created %s ago", age.Round(time.Minute))})
+		}
+	}
+	return targets, nil
+}
+
+func (s *sweepOptions) revoke(ctx context.Context, target *sweepTarget) error {
+	switch target.kind {
+	case sweepKindCredentials:
+		return s.credentials.revoke(target.clusterID)
+	case sweepKindCSR:
+		csr := &certificatesv1.CertificateSigningRequest{}
+		csr.Name = target.name
+		return kclient.IgnoreNotFound(s.kubeCli.Delete(ctx, csr))
+	case sweepKindCSRApproval:
+		approval := &certificatesv1alpha1.CertificateSigningRequestApproval{}
+		approval.Name, approval.Namespace = target.name, target.namespace
+		return kclient.IgnoreNotFound(s.kubeCli.Delete(ctx, approval))
+	}
+	return fmt.Errorf("unknown break-glass access kind %q", target.kind)
+}
+
+func printSweepTargets(w io.Writer, targets []*sweepTarget) {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"CLUSTER ID", "NAMESPACE", "KIND", "NAME", "REASON", "RESULT"})
+	for _, target := range targets {
+		result := target.result
+		if result == "" {
+			result = "to revoke"
+		}
+		p.AddRow([]string{target.clusterID, target.namespace, target.kind, target.name, target.reason, result})
+	}
+	if err := p.Flush(); err != nil {
+		_, _ = fmt.Fprintf(w, "failed to print the break-glass access: %v\n", err)
+	}
+}
+
+// ocmBreakGlassCredentials implements breakGlassCredentials with the OCM API
+type ocmBreakGlassCredentials struct {
+	conn *sdk.Connection
+}
+
+func (o *ocmBreakGlassCredentials) list(clusterID string) ([]*clustersmgmtv1.BreakGlassCredential, error) {
+	response, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).BreakGlassCredentials().List().Send()
+	if err != nil {
+		return nil, err
+	}
+	return response.Items().Slice(), nil
+}
+
+func (o *ocmBreakGlassCredentials) revoke(clusterID string) error {
+	_, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).BreakGlassCredentials().Delete().Send()
+	return err
+}
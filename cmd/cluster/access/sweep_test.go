@@ -0,0 +1,166 @@
+package access
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	clustersmgmtv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	certificatesv1alpha1 "github.com/openshift/hypershift/api/certificates/v1alpha1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeBreakGlassCredentials struct {
+	credentials map[string][]*clustersmgmtv1.BreakGlassCredential
+	revoked     []string
+}
+
+func (f *fakeBreakGlassCredentials) list(clusterID string) ([]*clustersmgmtv1.BreakGlassCredential, error) {
+	return f.credentials[clusterID], nil
+}
+
+func (f *fakeBreakGlassCredentials) revoke(clusterID string) error {
+	f.revoked = append(f.revoked, clusterID)
+	return nil
+}
+
+func newBreakGlassCredential(t *testing.T, status clustersmgmtv1.BreakGlassCredentialStatus, expiration time.Time) *clustersmgmtv1.BreakGlassCredential {
+	t.Helper()
+	credential, err := clustersmgmtv1.NewBreakGlassCredential().Status(status).ExpirationTimestamp(expiration).Build()
+	require.NoError(t, err)
+	return credential
+}
+
+func TestSweepOptionsRun(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	created := func(ago time.Duration) metav1.Time { return metav1.NewTime(now.Add(-ago)) }
+
+	objs := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ocm-production-a", Labels: map[string]string{hiveNSLabelKey: "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ocm-production-a-hcp", Labels: map[string]string{hiveNSLabelKey: "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ocm-production-b", Labels: map[string]string{hiveNSLabelKey: "b"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "hypershift"}},
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "old-customer", CreationTimestamp: created(10 * time.Hour)}, Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: breakGlassSignerName("ocm-production-a-hcp", "customer-break-glass")}},
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "new-customer", CreationTimestamp: created(time.Hour)}, Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: breakGlassSignerName("ocm-production-a-hcp", "customer-break-glass")}},
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "old-sre", CreationTimestamp: created(24 * time.Hour)}, Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: breakGlassSignerName("ocm-production-b", "sre-break-glass")}},
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "kubelet-serving", CreationTimestamp: created(100 * time.Hour)}, Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: certificatesv1.KubeletServingSignerName}},
+		&certificatesv1alpha1.CertificateSigningRequestApproval{ObjectMeta: metav1.ObjectMeta{Name: "old-customer", Namespace: "ocm-production-a-hcp", CreationTimestamp: created(10 * time.Hour)}},
+		&certificatesv1alpha1.CertificateSigningRequestApproval{ObjectMeta: metav1.ObjectMeta{Name: "new-customer", Namespace: "ocm-production-a-hcp", CreationTimestamp: created(time.Hour)}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "admin-kubeconfig", Namespace: "ocm-production-a-hcp", CreationTimestamp: created(100 * time.Hour)}},
+	}
+
+	tests := []struct {
+		name            string
+		dryRun          bool
+		includeActive   bool
+		input           string
+		expectRevoked   []string
+		expectRemaining []string
+	}{
+		{
+			name:            "revokes the expired and forgotten access",
+			input:           "y\n",
+			expectRevoked:   []string{"a"},
+			expectRemaining: []string{"csr/new-customer", "csr/kubelet-serving", "approval/new-customer", "secret/admin-kubeconfig"},
+		},
+		{
+			name:            "includes the active credentials",
+			includeActive:   true,
+			input:           "y\n",
+			expectRevoked:   []string{"a", "b"},
+			expectRemaining: []string{"csr/new-customer", "csr/kubelet-serving", "approval/new-customer", "secret/admin-kubeconfig"},
+		},
+		{
+			name:            "dry run",
+			dryRun:          true,
+			expectRemaining: []string{"csr/old-customer", "csr/new-customer", "csr/old-sre", "csr/kubelet-serving", "approval/old-customer", "approval/new-customer", "secret/admin-kubeconfig"},
+		},
+		{
+			name:            "not confirmed",
+			input:           "n\n",
+			expectRemaining: []string{"csr/old-customer", "csr/new-customer", "csr/old-sre", "csr/kubelet-serving", "approval/old-customer", "approval/new-customer", "secret/admin-kubeconfig"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer prompt.SetIO(strings.NewReader(tt.input), &bytes.Buffer{})()
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			require.NoError(t, certificatesv1.AddToScheme(scheme))
+			require.NoError(t, certificatesv1alpha1.AddToScheme(scheme))
+			kubeCli := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+			credentials := &fakeBreakGlassCredentials{credentials: map[string][]*clustersmgmtv1.BreakGlassCredential{
+				"a": {
+					newBreakGlassCredential(t, clustersmgmtv1.BreakGlassCredentialStatusExpired, now.Add(-time.Hour)),
+					newBreakGlassCredential(t, clustersmgmtv1.BreakGlassCredentialStatusRevoked, now.Add(-time.Hour)),
+				},
+				"b": {newBreakGlassCredential(t, clustersmgmtv1.BreakGlassCredentialStatusIssued, now.Add(time.Hour))},
+			}}
+			out := &bytes.Buffer{}
+			sweep := &sweepOptions{
+				maxAge:        8 * time.Hour,
+				includeActive: tt.includeActive,
+				dryRun:        tt.dryRun,
+				out:           out,
+				kubeCli:       kubeCli,
+				credentials:   credentials,
+				now:           func() time.Time { return now },
+			}
+
+			require.NoError(t, sweep.run(context.Background()))
+			assert.Equal(t, tt.expectRevoked, credentials.revoked)
+
+			var remaining []string
+			csrs := certificatesv1.CertificateSigningRequestList{}
+			require.NoError(t, kubeCli.List(context.Background(), &csrs))
+			for _, csr := range csrs.Items {
+				remaining = append(remaining, "csr/"+csr.Name)
+			}
+			approvals := certificatesv1alpha1.CertificateSigningRequestApprovalList{}
+			require.NoError(t, kubeCli.List(context.Background(), &approvals))
+			for _, approval := range approvals.Items {
+				remaining = append(remaining, "approval/"+approval.Name)
+			}
+			secrets := corev1.SecretList{}
+			require.NoError(t, kubeCli.List(context.Background(), &secrets))
+			for _, secret := range secrets.Items {
+				remaining = append(remaining, "secret/"+secret.Name)
+			}
+			assert.ElementsMatch(t, tt.expectRemaining, remaining)
+			assert.Contains(t, out.String(), "1 expired credential(s) not revoked")
+		})
+	}
+}
+
+func TestSweepOptionsFindCredentialsExpiredAndActive(t *testing.T) {
+	now := time.Now()
+	credentials := &fakeBreakGlassCredentials{credentials: map[string][]*clustersmgmtv1.BreakGlassCredential{
+		"a": {
+			newBreakGlassCredential(t, clustersmgmtv1.BreakGlassCredentialStatusIssued, now.Add(-time.Hour)),
+			newBreakGlassCredential(t, clustersmgmtv1.BreakGlassCredentialStatusIssued, now.Add(time.Hour)),
+		},
+	}}
+	out := &bytes.Buffer{}
+	sweep := &sweepOptions{out: out, credentials: credentials, now: func() time.Time { return now }}
+
+	target, err := sweep.findCredentials("a")
+	require.NoError(t, err)
+	assert.Nil(t, target)
+	assert.Contains(t, out.String(), "revoking them requires --include-active")
+
+	sweep.includeActive = true
+	target, err = sweep.findCredentials("a")
+	require.NoError(t, err)
+	require.NotNil(t, target)
+	assert.Equal(t, "1 expired and 1 active credential(s)", target.reason)
+}
@@ -1,6 +1,7 @@
 package cad
 
 import (
+	"github.com/openshift/osdctl/pkg/clients"
 	"github.com/spf13/cobra"
 )
 
@@ -12,6 +13,7 @@ func NewCmdCad() *cobra.Command {
 		DisableAutoGenTag: true,
 	}
 
-	cadCmd.AddCommand(newCmdRun())
+	cadCmd.AddCommand(newCmdRun(clients.NewFactoryWithURL("production")))
+	cadCmd.AddCommand(newCmdValidate(clients.NewFactory()))
 	return cadCmd
 }
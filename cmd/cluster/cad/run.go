@@ -2,12 +2,16 @@ package cad
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/openshift/osdctl/cmd/setup"
-	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,7 +27,8 @@ const (
 	cadNamespaceStage = "configuration-anomaly-detection-stage"
 )
 
-var validInvestigations = []string{
+// ValidInvestigations are the investigations CAD can run on demand
+var ValidInvestigations = []string{
 	"chgm",
 	"cmbb",
 	"can-not-retrieve-updates",
@@ -38,6 +43,12 @@ var validInvestigations = []string{
 	"describe-nodes",
 }
 
+var pipelineRunGVK = schema.GroupVersionKind{
+	Group:   "tekton.dev",
+	Version: "v1beta1",
+	Kind:    "PipelineRun",
+}
+
 var validEnvironments = []string{
 	"stage",
 	"production",
@@ -45,15 +56,30 @@ var validEnvironments = []string{
 
 type cadRunOptions struct {
 	clusterID       string
+	pdIncidentID    string
 	investigation   string
 	elevationReason string
 	environment     string
 	isDryRun        bool
 	params          []string
+	watch           bool
+
+	// factory creates the clients of the CAD clusters, which are always in production OCM
+	factory clients.Factory
+
+	pollInterval time.Duration
+	// findReport and addNote are replaced in tests
+	findReport func(clusterID string, since time.Time) (*cadReport, error)
+	addNote    func(incidentID, content string) error
 }
 
-func newCmdRun() *cobra.Command {
-	opts := &cadRunOptions{}
+func newCmdRun(factory clients.Factory) *cobra.Command {
+	opts := &cadRunOptions{
+		factory:      factory,
+		pollInterval: pipelineRunPollInterval,
+		findReport:   findReport,
+		addNote:      addIncidentNote,
+	}
 
 	runCmd := &cobra.Command{
 		Use:   "run",
@@ -78,7 +104,14 @@ Note:
 osdctl cluster reports list -C <cluster-id> -l 1
 ` + "```" + `
 
-  You must be connected to the target cluster's OCM environment to view its reports.`,
+  You must be connected to the target cluster's OCM environment to view its reports.
+
+  With --pd-incident, the investigation is scheduled for every cluster referenced by the alerts of the
+  PagerDuty incident, using the PagerDuty token configured for 'osdctl cluster context'.
+
+  With --watch, the command waits for the PipelineRuns to complete and prints the report of each investigation.
+  Combined with --pd-incident, the report summary and a link to the full report are added as a note of the
+  incident, so that the investigation results are visible to everyone working it.`,
 		Example: `  # Run a change management investigation on a production cluster
   osdctl cluster cad run --cluster-id ${CLUSTER_ID} --investigation chgm --environment production --reason "${REASON}"
 
@@ -86,7 +119,13 @@ osdctl cluster reports list -C <cluster-id> -l 1
   osdctl cluster cad run --cluster-id ${CLUSTER_ID} --investigation chgm --environment production --reason "${REASON}" --dry-run
 
   # Run describe-nodes with parameters
-  osdctl cluster cad run --cluster-id ${CLUSTER_ID} --investigation describe-nodes --environment production --reason "${REASON}" --params MASTER=true`,
+  osdctl cluster cad run --cluster-id ${CLUSTER_ID} --investigation describe-nodes --environment production --reason "${REASON}" --params MASTER=true
+
+  # Run an investigation for all clusters referenced by a PagerDuty incident
+  osdctl cluster cad run --pd-incident ${INCIDENT_ID} --investigation chgm --environment production --reason "${REASON}"
+
+  # Wait for the investigations and add their reports as notes of the PagerDuty incident
+  osdctl cluster cad run --pd-incident ${INCIDENT_ID} --investigation chgm --environment production --reason "${REASON}" --watch`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -95,20 +134,23 @@ osdctl cluster reports list -C <cluster-id> -l 1
 	}
 
 	runCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID (internal or external)")
+	runCmd.Flags().StringVar(&opts.pdIncidentID, "pd-incident", "", "PagerDuty incident ID, schedules the investigation for every cluster referenced by its alerts")
 	runCmd.Flags().StringVarP(&opts.investigation, "investigation", "i", "", "Investigation name")
 	runCmd.Flags().StringVarP(&opts.environment, "environment", "e", "", "Environment in which the target cluster runs. Allowed values: \"stage\" or \"production\"")
 	runCmd.Flags().BoolVarP(&opts.isDryRun, "dry-run", "d", false, "Dry-Run: Run the investigation with the dry-run flag. This will not create a report.")
 	runCmd.Flags().StringVar(&opts.elevationReason, "reason", "", "Provide a reason for running a manual investigation, used for backplane. Eg: 'OHSS-XXXX', or '#ITN-2024-XXXXX.")
 	runCmd.Flags().StringArrayVarP(&opts.params, "params", "p", nil,
 		"Investigation-specific parameters as KEY=VALUE (can be specified multiple times)")
+	runCmd.Flags().BoolVarP(&opts.watch, "watch", "w", false, "Wait for the investigation to complete and print its report. With --pd-incident, the report is added as a note of the incident")
 
-	_ = runCmd.MarkFlagRequired("cluster-id")
+	runCmd.MarkFlagsOneRequired("cluster-id", "pd-incident")
+	runCmd.MarkFlagsMutuallyExclusive("cluster-id", "pd-incident")
 	_ = runCmd.MarkFlagRequired("investigation")
 	_ = runCmd.MarkFlagRequired("environment")
 	_ = runCmd.MarkFlagRequired("reason")
 
 	_ = runCmd.RegisterFlagCompletionFunc("investigation", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return validInvestigations, cobra.ShellCompDirectiveNoFileComp
+		return ValidInvestigations, cobra.ShellCompDirectiveNoFileComp
 	})
 
 	_ = runCmd.RegisterFlagCompletionFunc("environment", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -122,38 +164,35 @@ func (o *cadRunOptions) run() error {
 	if err := o.validate(); err != nil {
 		return err
 	}
+	defer o.factory.Close()
 
-	grafanaURL := viper.GetString(setup.CADGrafanaURL)
-	awsAccountID := viper.GetString(setup.CADAWSAccountID)
-
-	cadClusterID, cadNamespace := o.getCADClusterConfig()
-
-	// CAD clusters are always in production OCM, so explicitly create a production connection
-	ocmConn, err := utils.CreateConnectionWithUrl("production")
-	if err != nil {
-		return fmt.Errorf("failed to create production OCM connection: %w", err)
+	if o.pdIncidentID != "" {
+		clusterIDs, err := o.getIncidentClusterIDs()
+		if err != nil {
+			return err
+		}
+		k8sClient, err := o.cadClient(clusterIDs)
+		if err != nil {
+			return err
+		}
+		_, cadNamespace := o.getCADClusterConfig()
+		runs, err := o.scheduleForIncident(k8sClient, cadNamespace, clusterIDs, viper.GetString(setup.CADGrafanaURL), viper.GetString(setup.CADAWSAccountID))
+		if o.watch && len(runs) > 0 {
+			err = errors.Join(err, o.watchRuns(k8sClient, cadNamespace, runs))
+		}
+		return err
 	}
-	defer ocmConn.Close()
 
-	k8sClient, err := k8s.NewAsBackplaneClusterAdminWithConn(cadClusterID, client.Options{}, ocmConn, o.elevationReason, "Need elevation for cad cluster in order to schedule a Tekton pipeline run")
+	k8sClient, err := o.cadClient([]string{o.clusterID})
 	if err != nil {
-		return fmt.Errorf("failed to create k8s client: %w", err)
+		return err
 	}
-
-	u := o.pipelineRunTemplate(cadNamespace)
-
-	err = k8sClient.Create(context.Background(), u)
+	_, cadNamespace := o.getCADClusterConfig()
+	run, err := o.scheduleWith(k8sClient, cadNamespace)
 	if err != nil {
-		return fmt.Errorf("failed to schedule task: %w", err)
-	}
-
-	// Get the generated name created by the API server
-	pipelineRunName := u.GetName()
-
-	var logsLink string
-	if grafanaURL != "" && awsAccountID != "" {
-		logsLink = fmt.Sprintf("%s/explore?schemaVersion=1&panes=%%7B%%22buh%%22:%%7B%%22datasource%%22:%%22P1A97A9592CB7F392%%22,%%22queries%%22:%%5B%%7B%%22id%%22:%%22%%22,%%22region%%22:%%22us-east-1%%22,%%22namespace%%22:%%22%%22,%%22refId%%22:%%22A%%22,%%22datasource%%22:%%7B%%22type%%22:%%22cloudwatch%%22,%%22uid%%22:%%22P1A97A9592CB7F392%%22%%7D,%%22queryMode%%22:%%22Logs%%22,%%22logGroups%%22:%%5B%%7B%%22arn%%22:%%22arn:aws:logs:us-east-1:%[2]s:log-group:cads01ue1.configuration-anomaly-detection-stage:%%2A%%22,%%22name%%22:%%22cads01ue1.configuration-anomaly-detection-stage%%22,%%22accountId%%22:%%22%[2]s%%22%%7D,%%7B%%22arn%%22:%%22arn:aws:logs:us-east-1:%[2]s:log-group:cadp01ue1.configuration-anomaly-detection-production:%%2A%%22,%%22name%%22:%%22cadp01ue1.configuration-anomaly-detection-production%%22,%%22accountId%%22:%%22%[2]s%%22%%7D%%5D,%%22expression%%22:%%22fields%%20message%%5Cn%%7C%%20filter%%20kubernetes.pod_name%%20like%%20%%5C%%22%s%%5C%%22%%22,%%22statsGroups%%22:%%5B%%5D%%7D%%5D,%%22range%%22:%%7B%%22from%%22:%%22now-1h%%22,%%22to%%22:%%22now%%22%%7D,%%22panelsState%%22:%%7B%%22logs%%22:%%7B%%22visualisationType%%22:%%22logs%%22%%7D%%7D%%7D%%7D&orgId=1", grafanaURL, awsAccountID, pipelineRunName)
+		return err
 	}
+	logsLink := run.logsLink
 
 	if !o.isDryRun {
 		reportCmd := fmt.Sprintf("'osdctl cluster reports list -C %s -l 1'", o.clusterID)
@@ -174,24 +213,181 @@ func (o *cadRunOptions) run() error {
 		}
 	}
 
+	if o.watch {
+		return o.watchRuns(k8sClient, cadNamespace, []scheduledRun{run})
+	}
 	return nil
 }
 
+// cadClient returns a client of the CAD cluster of the environment, elevated to schedule the investigation of the
+// clusters
+func (o *cadRunOptions) cadClient(clusterIDs []string) (client.Client, error) {
+	cadClusterID, _ := o.getCADClusterConfig()
+
+	k8sClient, err := o.factory.ElevatedKubeClient(cadClusterID, client.Options{}, elevate.Reason{
+		Ticket:        o.elevationReason,
+		Justification: fmt.Sprintf("schedule the %s investigation of cluster(s) %s", o.investigation, strings.Join(clusterIDs, ", ")),
+		Command:       "cluster cad run",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s client: %w", err)
+	}
+	return k8sClient, nil
+}
+
+// schedule creates the PipelineRun of the investigation of the cluster, and returns its name and the link to its logs
+func (o *cadRunOptions) schedule() (pipelineRunName string, logsLink string, err error) {
+	defer o.factory.Close()
+	k8sClient, err := o.cadClient([]string{o.clusterID})
+	if err != nil {
+		return "", "", err
+	}
+
+	_, cadNamespace := o.getCADClusterConfig()
+	run, err := o.scheduleWith(k8sClient, cadNamespace)
+	if err != nil {
+		return "", "", err
+	}
+	return run.pipelineRun, run.logsLink, nil
+}
+
+// scheduleWith creates the PipelineRun of the investigation of the cluster with the client of the CAD cluster
+func (o *cadRunOptions) scheduleWith(k8sClient client.Client, cadNamespace string) (scheduledRun, error) {
+	scheduledAt := time.Now()
+	u := o.pipelineRunTemplate(cadNamespace)
+	if err := k8sClient.Create(context.Background(), u); err != nil {
+		return scheduledRun{}, fmt.Errorf("failed to schedule task: %w", err)
+	}
+
+	// Get the generated name created by the API server
+	return scheduledRun{
+		clusterID:   o.clusterID,
+		pipelineRun: u.GetName(),
+		logsLink:    buildLogsLink(viper.GetString(setup.CADGrafanaURL), viper.GetString(setup.CADAWSAccountID), u.GetName()),
+		scheduledAt: scheduledAt,
+	}, nil
+}
+
+// RunRequest is a manual investigation of a cluster, as scheduled by "osdctl cluster cad run"
+type RunRequest struct {
+	ClusterID     string   `json:"cluster_id"`
+	Investigation string   `json:"investigation"`
+	Environment   string   `json:"environment"`
+	Reason        string   `json:"reason"`
+	DryRun        bool     `json:"dry_run,omitempty"`
+	Params        []string `json:"params,omitempty"`
+}
+
+// RunResult is the scheduled PipelineRun of an investigation
+type RunResult struct {
+	PipelineRun string `json:"pipeline_run"`
+	LogsLink    string `json:"logs_link,omitempty"`
+}
+
+// Run validates and schedules a manual investigation, for callers outside of the CLI such as "osdctl serve"
+func Run(req RunRequest) (*RunResult, error) {
+	o := &cadRunOptions{
+		clusterID:       req.ClusterID,
+		investigation:   req.Investigation,
+		environment:     req.Environment,
+		elevationReason: req.Reason,
+		isDryRun:        req.DryRun,
+		params:          req.Params,
+		factory:         clients.NewFactoryWithURL("production"),
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	pipelineRunName, logsLink, err := o.schedule()
+	if err != nil {
+		return nil, err
+	}
+	return &RunResult{PipelineRun: pipelineRunName, LogsLink: logsLink}, nil
+}
+
+// getIncidentClusterIDs resolves the clusters referenced by the alerts of the PagerDuty incident
+func (o *cadRunOptions) getIncidentClusterIDs() ([]string, error) {
+	pdProvider, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		Init()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterIDs, err := pdProvider.GetClusterIDsForIncident(o.pdIncidentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(clusterIDs) == 0 {
+		return nil, fmt.Errorf("no cluster ID found in the alerts of incident %s", o.pdIncidentID)
+	}
+	return clusterIDs, nil
+}
+
+// scheduleForIncident schedules the investigation for each cluster of the incident, printing one PipelineRun per cluster,
+// and returns the scheduled PipelineRuns. A failure to schedule the investigation for a cluster doesn't prevent
+// scheduling it for the others.
+func (o *cadRunOptions) scheduleForIncident(k8sClient client.Client, cadNamespace string, clusterIDs []string, grafanaURL, awsAccountID string) ([]scheduledRun, error) {
+	var errs []error
+	var runs []scheduledRun
+	for _, clusterID := range clusterIDs {
+		clusterOpts := *o
+		clusterOpts.clusterID = clusterID
+		scheduledAt := time.Now()
+		u := clusterOpts.pipelineRunTemplate(cadNamespace)
+		if err := k8sClient.Create(context.Background(), u); err != nil {
+			errs = append(errs, fmt.Errorf("failed to schedule task for cluster %s: %w", clusterID, err))
+			continue
+		}
+		fmt.Printf("%s: %s\n", clusterID, u.GetName())
+		logsLink := buildLogsLink(grafanaURL, awsAccountID, u.GetName())
+		if logsLink != "" {
+			fmt.Println("  TaskRun pod logs: " + logsLink)
+		}
+		runs = append(runs, scheduledRun{clusterID: clusterID, pipelineRun: u.GetName(), logsLink: logsLink, scheduledAt: scheduledAt})
+	}
+
+	if len(runs) > 0 && !o.isDryRun && !o.watch {
+		fmt.Println("It can take several minutes until the reports are available. " +
+			"Run 'osdctl cluster reports list -C <cluster-id> -l 1' for each cluster while being connected to the right OCM backplane environment.")
+	}
+	return runs, errors.Join(errs...)
+}
+
+// buildLogsLink returns the Grafana link to the logs of the PipelineRun, or an empty string when Grafana isn't configured
+func buildLogsLink(grafanaURL, awsAccountID, pipelineRunName string) string {
+	if grafanaURL == "" || awsAccountID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/explore?schemaVersion=1&panes=%%7B%%22buh%%22:%%7B%%22datasource%%22:%%22P1A97A9592CB7F392%%22,%%22queries%%22:%%5B%%7B%%22id%%22:%%22%%22,%%22region%%22:%%22us-east-1%%22,%%22namespace%%22:%%22%%22,%%22refId%%22:%%22A%%22,%%22datasource%%22:%%7B%%22type%%22:%%22cloudwatch%%22,%%22uid%%22:%%22P1A97A9592CB7F392%%22%%7D,%%22queryMode%%22:%%22Logs%%22,%%22logGroups%%22:%%5B%%7B%%22arn%%22:%%22arn:aws:logs:us-east-1:%[2]s:log-group:cads01ue1.configuration-anomaly-detection-stage:%%2A%%22,%%22name%%22:%%22cads01ue1.configuration-anomaly-detection-stage%%22,%%22accountId%%22:%%22%[2]s%%22%%7D,%%7B%%22arn%%22:%%22arn:aws:logs:us-east-1:%[2]s:log-group:cadp01ue1.configuration-anomaly-detection-production:%%2A%%22,%%22name%%22:%%22cadp01ue1.configuration-anomaly-detection-production%%22,%%22accountId%%22:%%22%[2]s%%22%%7D%%5D,%%22expression%%22:%%22fields%%20message%%5Cn%%7C%%20filter%%20kubernetes.pod_name%%20like%%20%%5C%%22%s%%5C%%22%%22,%%22statsGroups%%22:%%5B%%5D%%7D%%5D,%%22range%%22:%%7B%%22from%%22:%%22now-1h%%22,%%22to%%22:%%22now%%22%%7D,%%22panelsState%%22:%%7B%%22logs%%22:%%7B%%22visualisationType%%22:%%22logs%%22%%7D%%7D%%7D%%7D&orgId=1", grafanaURL, awsAccountID, pipelineRunName)
+}
+
 func (o *cadRunOptions) validate() error {
-	if o.clusterID == "" {
-		return fmt.Errorf("cluster-id is required")
+	if o.clusterID == "" && o.pdIncidentID == "" {
+		return fmt.Errorf("one of cluster-id or pd-incident is required")
+	}
+
+	if o.clusterID != "" && o.pdIncidentID != "" {
+		return fmt.Errorf("cluster-id and pd-incident are mutually exclusive")
 	}
 
-	if !slices.Contains(validInvestigations, o.investigation) {
-		return fmt.Errorf("invalid investigation %q, must be one of: %v", o.investigation, validInvestigations)
+	if o.clusterID != "" {
+		if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+			return err
+		}
+	}
+
+	if !slices.Contains(ValidInvestigations, o.investigation) {
+		return fmt.Errorf("invalid investigation %q, must be one of: %v", o.investigation, ValidInvestigations)
 	}
 
 	if !slices.Contains(validEnvironments, o.environment) {
 		return fmt.Errorf("invalid environment %q, must be one of: %v", o.environment, validEnvironments)
 	}
 
-	if o.elevationReason == "" {
-		return fmt.Errorf("elevation reason is required")
+	if err := utils.ValidateReason(elevate.ReasonFlag, o.elevationReason); err != nil {
+		return err
 	}
 
 	for _, p := range o.params {
@@ -250,11 +446,7 @@ func (o *cadRunOptions) pipelineRunTemplate(cadNamespace string) *unstructured.U
 		},
 	}
 
-	u.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "tekton.dev",
-		Version: "v1beta1",
-		Kind:    "PipelineRun",
-	})
+	u.SetGroupVersionKind(pipelineRunGVK)
 
 	return &u
 }
@@ -1,11 +1,18 @@
 package cad
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/openshift/osdctl/pkg/clients"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func TestValidateParams(t *testing.T) {
@@ -288,3 +295,76 @@ func TestLogsLinkURLConstruction(t *testing.T) {
 		t.Fatal("Expected config values to be set")
 	}
 }
+
+func TestValidateTarget(t *testing.T) {
+	base := cadRunOptions{
+		investigation:   "chgm",
+		environment:     "production",
+		elevationReason: "OHSS-12345",
+	}
+
+	opts := base
+	assert.EqualError(t, opts.validate(), "one of cluster-id or pd-incident is required")
+
+	opts.pdIncidentID = "Q1A2B3C4D5E6F7"
+	assert.NoError(t, opts.validate())
+
+	opts.clusterID = "test-cluster"
+	assert.EqualError(t, opts.validate(), "cluster-id and pd-incident are mutually exclusive")
+
+	opts.pdIncidentID = ""
+	opts.elevationReason = "OHSS12345"
+	assert.EqualError(t, opts.validate(), `--reason references the malformed ticket "OHSS12345", expected OHSS-<number> or ITN-<year>-<number>`)
+}
+
+func TestScheduleForIncident(t *testing.T) {
+	var clusterIDs []string
+	k8sClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			params := obj.(*unstructured.Unstructured).Object["spec"].(map[string]interface{})["params"].([]map[string]interface{})
+			clusterID := params[0]["value"].(string)
+			if clusterID == "cluster-b" {
+				return errors.New("forbidden")
+			}
+			clusterIDs = append(clusterIDs, clusterID)
+			obj.SetName("cad-manual-" + clusterID)
+			return nil
+		},
+	}).Build()
+	opts := &cadRunOptions{pdIncidentID: "Q1A2B3C4D5E6F7", investigation: "chgm", environment: "production", elevationReason: "OHSS-12345"}
+
+	runs, err := opts.scheduleForIncident(k8sClient, cadNamespaceProd, []string{"cluster-a", "cluster-b", "cluster-c"}, "", "")
+	assert.EqualError(t, err, "failed to schedule task for cluster cluster-b: forbidden")
+	assert.Equal(t, []string{"cluster-a", "cluster-c"}, clusterIDs)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, "cad-manual-cluster-c", runs[1].pipelineRun)
+	assert.Empty(t, opts.clusterID, "scheduling per cluster should not modify the options")
+}
+
+func TestRunWithFactory(t *testing.T) {
+	var created []string
+	k8sClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			created = append(created, obj.GetNamespace())
+			obj.SetName("cad-manual-abc12")
+			return nil
+		},
+	}).Build()
+	factory := &clients.FakeFactory{Client: k8sClient}
+	opts := &cadRunOptions{
+		clusterID:       "test-cluster",
+		investigation:   "chgm",
+		environment:     "stage",
+		elevationReason: "OHSS-12345",
+		factory:         factory,
+	}
+
+	assert.NoError(t, opts.run())
+	assert.Equal(t, []string{cadNamespaceStage}, created)
+	assert.Equal(t, []string{cadClusterIDStage}, factory.ElevatedClusterIDs)
+	assert.Equal(t, "schedule the chgm investigation of cluster(s) test-cluster", factory.Reasons[0].Justification)
+	assert.True(t, factory.Closed, "the OCM connection should be closed once done")
+
+	opts.factory = &clients.FakeFactory{Err: errors.New("not logged in")}
+	assert.EqualError(t, opts.run(), "failed to create k8s client: not logged in")
+}
@@ -0,0 +1,292 @@
+package cad
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	internalutils "github.com/openshift/osdctl/internal/utils"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	topologyClassic = "classic"
+	topologyHCP     = "hcp"
+
+	validateCheckPass = "PASS"
+	validateCheckWarn = "WARN"
+	validateCheckFail = "FAIL"
+)
+
+// builtinRules are the prerequisites of the investigations shipped with osdctl. CAD doesn't publish the
+// prerequisites of its investigations, the file is an approximation owned by osdctl: the approvers of the OWNERS file
+// keep it in sync with the investigations of CAD, which is checked when reviewing any change to it.
+//
+//go:embed investigation_rules.json
+var builtinRules []byte
+
+// investigationRules are the prerequisites of the investigations CAD can run on demand
+type investigationRules struct {
+	Investigations []investigationRule `json:"investigations"`
+}
+
+// investigationRule is what a cluster needs for CAD to run an investigation on it. An empty list allows any value.
+type investigationRule struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description,omitempty"`
+	CloudProviders []string `json:"cloudProviders,omitempty"`
+	Products       []string `json:"products,omitempty"`
+	Topologies     []string `json:"topologies,omitempty"`
+	States         []string `json:"states,omitempty"`
+	// Requires are the OCM data the investigation needs, see clusterData
+	Requires []string `json:"requires,omitempty"`
+}
+
+// clusterData returns the OCM data of the cluster the investigations can require, empty when missing
+var clusterData = map[string]func(cluster *cmv1.Cluster) string{
+	"infra_id":    func(c *cmv1.Cluster) string { return c.InfraID() },
+	"external_id": func(c *cmv1.Cluster) string { return c.ExternalID() },
+	"api_url":     func(c *cmv1.Cluster) string { return c.API().URL() },
+	"console_url": func(c *cmv1.Cluster) string { return c.Console().URL() },
+	"region":      func(c *cmv1.Cluster) string { return c.Region().ID() },
+}
+
+// validateCheck is the result of checking one prerequisite of the investigation
+type validateCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// validateResult is the result of checking all the prerequisites of the investigation on the cluster
+type validateResult struct {
+	ClusterID     string          `json:"clusterId"`
+	Investigation string          `json:"investigation"`
+	Valid         bool            `json:"valid"`
+	Checks        []validateCheck `json:"checks"`
+}
+
+type cadValidateOptions struct {
+	clusterID     string
+	investigation string
+	rules         string
+	output        string
+
+	out     io.Writer
+	factory clients.Factory
+}
+
+func newCmdValidate(factory clients.Factory) *cobra.Command {
+	opts := &cadValidateOptions{out: os.Stdout, factory: factory}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check a cluster satisfies the prerequisites of an investigation before running it",
+		Long: `Check a cluster satisfies the prerequisites of a manual investigation before scheduling it with 'osdctl cluster cad run'.
+
+The cloud provider, product, topology (classic or hcp) and state of the cluster, and the OCM data the investigation
+needs, are checked against the rules of the investigation, so that no PipelineRun is wasted on a cluster CAD can't
+investigate. The command exits with an error when any check fails.
+
+The rules shipped with osdctl are an approximation of the prerequisites of the CAD investigations, maintained in
+osdctl. --rules uses another rules file of the same format instead, from a file or URL.
+
+Prerequisites:
+  - Connected to the target cluster's OCM environment (production or stage)`,
+		Example: `  # Check a change management investigation can run on a cluster
+  osdctl cluster cad validate --cluster-id ${CLUSTER_ID} --investigation chgm
+
+  # Check against another rules file and print the results as JSON
+  osdctl cluster cad validate --cluster-id ${CLUSTER_ID} --investigation chgm --rules ${RULES_URL} -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run()
+		},
+	}
+
+	validateCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID (internal or external)")
+	validateCmd.Flags().StringVarP(&opts.investigation, "investigation", "i", "", "Investigation name")
+	validateCmd.Flags().StringVar(&opts.rules, "rules", "", "Investigation rules file or URL to use instead of the rules shipped with osdctl")
+	validateCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	_ = validateCmd.MarkFlagRequired("cluster-id")
+	_ = validateCmd.MarkFlagRequired("investigation")
+
+	_ = validateCmd.RegisterFlagCompletionFunc("investigation", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return ValidInvestigations, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return validateCmd
+}
+
+func (o *cadValidateOptions) run() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	rules, err := o.loadRules()
+	if err != nil {
+		return err
+	}
+	rule := rules.find(o.investigation)
+	if rule == nil {
+		return fmt.Errorf("no rules for investigation %q, must be one of: %v", o.investigation, rules.names())
+	}
+
+	defer o.factory.Close()
+	cluster, err := o.factory.Cluster(o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	result := validateResult{ClusterID: cluster.ID(), Investigation: rule.Name, Checks: rule.check(cluster)}
+	failed := 0
+	for _, c := range result.Checks {
+		if c.Status == validateCheckFail {
+			failed++
+		}
+	}
+	result.Valid = failed == 0
+
+	if o.output == "json" {
+		enc := json.NewEncoder(o.out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printValidateChecks(o.out, result.Checks)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("cluster %s doesn't satisfy the prerequisites of investigation %s: %d check(s) failed", cluster.ID(), rule.Name, failed)
+	}
+	if o.output == "table" {
+		_, _ = fmt.Fprintf(o.out, "\nCluster %s satisfies the prerequisites of investigation %s, schedule it with 'osdctl cluster cad run'\n", cluster.ID(), rule.Name)
+	}
+	return nil
+}
+
+// loadRules returns the rules of --rules, or the ones shipped with osdctl
+func (o *cadValidateOptions) loadRules() (*investigationRules, error) {
+	if o.rules == "" {
+		return parseRules(builtinRules)
+	}
+
+	var data []byte
+	var err error
+	if internalutils.IsValidUrl(o.rules) {
+		data, err = internalutils.CurlThis(o.rules)
+	} else {
+		data, err = os.ReadFile(filepath.Clean(o.rules)) //#nosec G304 -- the rules file is provided by the user
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the investigation rules: %w", err)
+	}
+	return parseRules(data)
+}
+
+// parseRules parses and validates investigation rules
+func parseRules(data []byte) (*investigationRules, error) {
+	var rules investigationRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse the investigation rules: %w", err)
+	}
+	if len(rules.Investigations) == 0 {
+		return nil, errors.New("invalid investigation rules: no investigations defined")
+	}
+	names := map[string]bool{}
+	for _, r := range rules.Investigations {
+		if r.Name == "" {
+			return nil, errors.New("invalid investigation rules: the name of the investigations is required")
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("invalid investigation rules: investigation %s is defined more than once", r.Name)
+		}
+		names[r.Name] = true
+	}
+	return &rules, nil
+}
+
+// find returns the rule of the investigation, or nil
+func (r *investigationRules) find(name string) *investigationRule {
+	for i := range r.Investigations {
+		if r.Investigations[i].Name == name {
+			return &r.Investigations[i]
+		}
+	}
+	return nil
+}
+
+// names returns the names of the investigations with rules
+func (r *investigationRules) names() []string {
+	names := make([]string, 0, len(r.Investigations))
+	for _, rule := range r.Investigations {
+		names = append(names, rule.Name)
+	}
+	return names
+}
+
+// check returns the result of checking each prerequisite of the investigation on the cluster
+func (r *investigationRule) check(cluster *cmv1.Cluster) []validateCheck {
+	topology := topologyClassic
+	if cluster.Hypershift().Enabled() {
+		topology = topologyHCP
+	}
+
+	checks := []validateCheck{
+		checkAllowed("Cloud provider", cluster.CloudProvider().ID(), r.CloudProviders),
+		checkAllowed("Product", cluster.Product().ID(), r.Products),
+		checkAllowed("Topology", topology, r.Topologies),
+		checkAllowed("State", string(cluster.State()), r.States),
+	}
+	for _, name := range r.Requires {
+		c := validateCheck{Name: "OCM data " + name}
+		get, ok := clusterData[name]
+		switch {
+		case !ok:
+			c.Status, c.Message = validateCheckWarn, fmt.Sprintf("%s is unknown to this version of osdctl, it can't be checked", name)
+		case get(cluster) == "":
+			c.Status, c.Message = validateCheckFail, fmt.Sprintf("the cluster has no %s in OCM", name)
+		default:
+			c.Status, c.Message = validateCheckPass, get(cluster)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// checkAllowed checks the value of the cluster is one of the allowed ones, any value being allowed when there are none
+func checkAllowed(name, value string, allowed []string) validateCheck {
+	c := validateCheck{Name: name, Status: validateCheckPass, Message: value}
+	if value == "" {
+		c.Message = "unknown"
+	}
+	if len(allowed) > 0 && !slices.Contains(allowed, value) {
+		c.Status = validateCheckFail
+		c.Message = fmt.Sprintf("%s, expected %s", c.Message, strings.Join(allowed, " or "))
+	}
+	return c
+}
+
+// printValidateChecks prints a row per check
+func printValidateChecks(w io.Writer, checks []validateCheck) {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, c := range checks {
+		p.AddRow([]string{c.Name, c.Status, c.Message})
+	}
+	_ = p.Flush()
+}
@@ -0,0 +1,133 @@
+package cad
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidateTestCluster(t *testing.T, provider string, hcp bool, state cmv1.ClusterState, infraID string) *cmv1.Cluster {
+	cluster, err := cmv1.NewCluster().ID("1a2b3c").ExternalID("abc-123").InfraID(infraID).
+		CloudProvider(cmv1.NewCloudProvider().ID(provider)).
+		Product(cmv1.NewProduct().ID("rosa")).
+		Hypershift(cmv1.NewHypershift().Enabled(hcp)).
+		Region(cmv1.NewCloudRegion().ID("us-east-1")).
+		API(cmv1.NewClusterAPI().URL("https://api.my-cluster.example.com:6443")).
+		State(state).Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func TestBuiltinRulesCoverValidInvestigations(t *testing.T) {
+	rules, err := parseRules(builtinRules)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, ValidInvestigations, rules.names())
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid", data: `{"investigations":[{"name":"chgm","cloudProviders":["aws"]}]}`},
+		{name: "invalid JSON", data: `{`, wantErr: "cannot parse the investigation rules"},
+		{name: "no investigations", data: `{"investigations":[]}`, wantErr: "no investigations defined"},
+		{name: "no name", data: `{"investigations":[{"cloudProviders":["aws"]}]}`, wantErr: "the name of the investigations is required"},
+		{name: "duplicate", data: `{"investigations":[{"name":"chgm"},{"name":"chgm"}]}`, wantErr: "investigation chgm is defined more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRules([]byte(tt.data))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		investigation string
+		cluster       *cmv1.Cluster
+		wantErr       string
+		contains      []string
+	}{
+		{
+			name:          "valid",
+			investigation: "chgm",
+			cluster:       newValidateTestCluster(t, "aws", false, cmv1.ClusterStateReady, "my-cluster-x7k2p"),
+			contains:      []string{"Topology", "classic", "my-cluster-x7k2p", "satisfies the prerequisites of investigation chgm"},
+		},
+		{
+			name:          "gcp hosted cluster",
+			investigation: "chgm",
+			cluster:       newValidateTestCluster(t, "gcp", true, cmv1.ClusterStateReady, "my-cluster-x7k2p"),
+			wantErr:       "cluster 1a2b3c doesn't satisfy the prerequisites of investigation chgm: 2 check(s) failed",
+			contains:      []string{"gcp, expected aws", "hcp, expected classic"},
+		},
+		{
+			name:          "missing infra ID",
+			investigation: "cpd",
+			cluster:       newValidateTestCluster(t, "aws", false, cmv1.ClusterStateInstalling, ""),
+			wantErr:       "1 check(s) failed",
+			contains:      []string{"the cluster has no infra_id in OCM"},
+		},
+		{
+			name:          "unknown investigation",
+			investigation: "unknown",
+			cluster:       newValidateTestCluster(t, "aws", false, cmv1.ClusterStateReady, "my-cluster-x7k2p"),
+			wantErr:       `no rules for investigation "unknown"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			factory := &clients.FakeFactory{Clusters: []*cmv1.Cluster{tt.cluster}}
+			o := &cadValidateOptions{clusterID: "1a2b3c", investigation: tt.investigation, output: "table", out: out, factory: factory}
+
+			err := o.run()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+			for _, s := range tt.contains {
+				assert.Contains(t, out.String(), s)
+			}
+		})
+	}
+}
+
+func TestValidateRunRulesFile(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`{"investigations":[{"name":"chgm","states":["ready"],"requires":["infra_id","network_type"]}]}`), 0600))
+
+	out := &bytes.Buffer{}
+	factory := &clients.FakeFactory{Clusters: []*cmv1.Cluster{newValidateTestCluster(t, "gcp", false, cmv1.ClusterStateReady, "my-cluster-x7k2p")}}
+	o := &cadValidateOptions{clusterID: "1a2b3c", investigation: "chgm", rules: rulesFile, output: "json", out: out, factory: factory}
+	require.NoError(t, o.run())
+
+	var result validateResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.True(t, result.Valid)
+	assert.Equal(t, validateCheck{
+		Name:    "OCM data network_type",
+		Status:  validateCheckWarn,
+		Message: "network_type is unknown to this version of osdctl, it can't be checked",
+	}, result.Checks[len(result.Checks)-1])
+	assert.True(t, factory.Closed)
+}
@@ -0,0 +1,188 @@
+package cad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/backplane"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	pipelineRunPollInterval = 15 * time.Second
+	// pipelineRunWatchTimeout leaves some margin over the timeout of the PipelineRun itself
+	pipelineRunWatchTimeout = 35 * time.Minute
+)
+
+// scheduledRun is a PipelineRun scheduled for the investigation of a cluster
+type scheduledRun struct {
+	clusterID   string
+	pipelineRun string
+	logsLink    string
+	scheduledAt time.Time
+}
+
+// cadReport is the backplane report written by an investigation
+type cadReport struct {
+	ID      string
+	Summary string
+	URL     string
+}
+
+// watchRuns waits for the PipelineRuns to complete and prints the report of each investigation. With --pd-incident,
+// the report summary and a link to the full report are added as a note of the incident.
+// A failure for a cluster doesn't prevent reporting the others.
+func (o *cadRunOptions) watchRuns(k8sClient client.Client, cadNamespace string, runs []scheduledRun) error {
+	var errs []error
+	for _, run := range runs {
+		fmt.Printf("Waiting for PipelineRun %s of cluster %s to complete...\n", run.pipelineRun, run.clusterID)
+		succeeded, message, err := waitForPipelineRun(k8sClient, cadNamespace, run.pipelineRun, o.pollInterval, pipelineRunWatchTimeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to watch PipelineRun %s of cluster %s: %w", run.pipelineRun, run.clusterID, err))
+			continue
+		}
+		if !succeeded {
+			errs = append(errs, fmt.Errorf("PipelineRun %s of cluster %s failed: %s", run.pipelineRun, run.clusterID, message))
+			if o.pdIncidentID != "" {
+				if err := o.addNote(o.pdIncidentID, o.failureNote(run, message)); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+		fmt.Printf("PipelineRun %s of cluster %s succeeded\n", run.pipelineRun, run.clusterID)
+		if o.isDryRun {
+			continue
+		}
+
+		report, err := o.findReport(run.clusterID, run.scheduledAt)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to find the report of cluster %s: %w", run.clusterID, err))
+			continue
+		}
+		fmt.Printf("Report %s: %s\n  %s\n", report.ID, report.Summary, report.URL)
+
+		if o.pdIncidentID != "" {
+			if err := o.addNote(o.pdIncidentID, o.reportNote(run, report)); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Printf("Added the report to incident %s\n", o.pdIncidentID)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reportNote is the PagerDuty note of a completed investigation
+func (o *cadRunOptions) reportNote(run scheduledRun, report *cadReport) string {
+	lines := []string{
+		fmt.Sprintf("CAD %s investigation of cluster %s completed: %s", o.investigation, run.clusterID, report.Summary),
+		"Full report: " + report.URL,
+		fmt.Sprintf("View it with: osdctl cluster reports get -C %s --report-id %s", run.clusterID, report.ID),
+	}
+	if run.logsLink != "" {
+		lines = append(lines, "TaskRun pod logs: "+run.logsLink)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// failureNote is the PagerDuty note of an investigation whose PipelineRun failed
+func (o *cadRunOptions) failureNote(run scheduledRun, message string) string {
+	lines := []string{
+		fmt.Sprintf("CAD %s investigation of cluster %s failed: %s", o.investigation, run.clusterID, message),
+	}
+	if run.logsLink != "" {
+		lines = append(lines, "TaskRun pod logs: "+run.logsLink)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// waitForPipelineRun polls the PipelineRun until its Succeeded condition is set, and returns whether it succeeded
+// and the message of the condition
+func waitForPipelineRun(k8sClient client.Client, namespace, name string, interval, timeout time.Duration) (succeeded bool, message string, err error) {
+	err = wait.PollUntilContextTimeout(context.Background(), interval, timeout, true, func(ctx context.Context) (bool, error) {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(pipelineRunGVK)
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, u); err != nil {
+			return false, err
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok || condition["type"] != "Succeeded" {
+				continue
+			}
+			message, _ = condition["message"].(string)
+			switch condition["status"] {
+			case "True":
+				succeeded = true
+				return true, nil
+			case "False":
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return succeeded, message, err
+}
+
+// findReport returns the latest report of the cluster created since the investigation was scheduled, using the
+// OCM environment of the target cluster
+func findReport(clusterID string, since time.Time) (*cadReport, error) {
+	ocmConn, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer ocmConn.Close()
+
+	internalClusterID, err := utils.GetInternalClusterID(ocmConn, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	backplaneClient, err := backplane.NewClient(internalClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backplane client: %w", err)
+	}
+
+	reports, err := backplaneClient.ListReports(context.Background(), 5)
+	if err != nil {
+		return nil, err
+	}
+	var latest *cadReport
+	var latestAt time.Time
+	for _, report := range reports.Reports {
+		if report.ReportId == nil || report.CreatedAt == nil || report.CreatedAt.Before(since) || report.CreatedAt.Before(latestAt) {
+			continue
+		}
+		latestAt = *report.CreatedAt
+		latest = &cadReport{ID: *report.ReportId, URL: backplaneClient.ReportURL(*report.ReportId)}
+		if report.Summary != nil {
+			latest.Summary = *report.Summary
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no report was created since %s", since.Format(time.RFC3339))
+	}
+	return latest, nil
+}
+
+// addIncidentNote adds a note to the PagerDuty incident, using the PagerDuty token configured for 'osdctl cluster context'
+func addIncidentNote(incidentID, content string) error {
+	pdProvider, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		Init()
+	if err != nil {
+		return err
+	}
+	return pdProvider.AddIncidentNote(incidentID, content)
+}
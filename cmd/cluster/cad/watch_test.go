@@ -0,0 +1,91 @@
+package cad
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func pipelineRun(name, status, message string) client.Object {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": cadNamespaceProd},
+	}}
+	u.SetGroupVersionKind(pipelineRunGVK)
+	if status != "" {
+		u.Object["status"] = map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Succeeded", "status": status, "message": message},
+			},
+		}
+	}
+	return u
+}
+
+func TestWaitForPipelineRun(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithObjects(
+		pipelineRun("succeeded", "True", "Tasks Completed: 1"),
+		pipelineRun("failed", "False", "Tasks Completed: 1 (Failed: 1)"),
+		pipelineRun("running", "Unknown", "Tasks Completed: 0"),
+	).Build()
+
+	succeeded, _, err := waitForPipelineRun(k8sClient, cadNamespaceProd, "succeeded", time.Millisecond, time.Second)
+	require.NoError(t, err)
+	assert.True(t, succeeded)
+
+	succeeded, message, err := waitForPipelineRun(k8sClient, cadNamespaceProd, "failed", time.Millisecond, time.Second)
+	require.NoError(t, err)
+	assert.False(t, succeeded)
+	assert.Equal(t, "Tasks Completed: 1 (Failed: 1)", message)
+
+	_, _, err = waitForPipelineRun(k8sClient, cadNamespaceProd, "running", time.Millisecond, 10*time.Millisecond)
+	assert.Error(t, err)
+
+	_, _, err = waitForPipelineRun(k8sClient, cadNamespaceProd, "missing", time.Millisecond, time.Second)
+	assert.Error(t, err)
+}
+
+func TestWatchRuns(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithObjects(
+		pipelineRun("cad-manual-a", "True", ""),
+		pipelineRun("cad-manual-b", "False", "Tasks Completed: 1 (Failed: 1)"),
+		pipelineRun("cad-manual-c", "True", ""),
+	).Build()
+
+	notes := map[string][]string{}
+	opts := &cadRunOptions{
+		pdIncidentID:  "Q1A2B3C4D5E6F7",
+		investigation: "chgm",
+		pollInterval:  time.Millisecond,
+		findReport: func(clusterID string, since time.Time) (*cadReport, error) {
+			if clusterID == "cluster-c" {
+				return nil, errors.New("no report was created")
+			}
+			return &cadReport{ID: "report-" + clusterID, Summary: "Network egress blocked", URL: "https://backplane.example.com/reports/report-" + clusterID}, nil
+		},
+		addNote: func(incidentID, content string) error {
+			notes[incidentID] = append(notes[incidentID], content)
+			return nil
+		},
+	}
+
+	err := opts.watchRuns(k8sClient, cadNamespaceProd, []scheduledRun{
+		{clusterID: "cluster-a", pipelineRun: "cad-manual-a", logsLink: "https://grafana.example.com/a"},
+		{clusterID: "cluster-b", pipelineRun: "cad-manual-b"},
+		{clusterID: "cluster-c", pipelineRun: "cad-manual-c"},
+	})
+	assert.EqualError(t, err, "PipelineRun cad-manual-b of cluster cluster-b failed: Tasks Completed: 1 (Failed: 1)\n"+
+		"failed to find the report of cluster cluster-c: no report was created")
+
+	require.Len(t, notes["Q1A2B3C4D5E6F7"], 2)
+	assert.Equal(t, "CAD chgm investigation of cluster cluster-a completed: Network egress blocked\n"+
+		"Full report: https://backplane.example.com/reports/report-cluster-a\n"+
+		"View it with: osdctl cluster reports get -C cluster-a --report-id report-cluster-a\n"+
+		"TaskRun pod logs: https://grafana.example.com/a", notes["Q1A2B3C4D5E6F7"][0])
+	assert.Equal(t, "CAD chgm investigation of cluster cluster-b failed: Tasks Completed: 1 (Failed: 1)", notes["Q1A2B3C4D5E6F7"][1])
+}
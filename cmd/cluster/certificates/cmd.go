@@ -0,0 +1,23 @@
+package certificates
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdCertificates implements the certificates command to inspect the certificates of a cluster
+// osdctl cluster certificates status --cluster-id <cluster-id> --reason <reason>
+func NewCmdCertificates() *cobra.Command {
+	certificatesCmd := &cobra.Command{
+		Use:               "certificates",
+		Short:             "Inspect the certificates of a cluster",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	certificatesCmd.AddCommand(newCmdStatus())
+
+	return certificatesCmd
+}
@@ -0,0 +1,350 @@
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	categoryAPI     = "api-serving"
+	categoryIngress = "ingress"
+	categoryCA      = "internal-ca"
+	categorySigning = "hcp-signing"
+
+	statusOK              = "OK"
+	statusExpiring        = "EXPIRING"
+	statusExpired         = "EXPIRED"
+	statusRotationOverdue = "ROTATION OVERDUE"
+
+	// rotationThreshold is the share of a CA's lifetime after which the cert rotation controllers
+	// should have replaced it, a CA past it indicates a stuck rotation
+	rotationThreshold = 0.8
+)
+
+// classicSources are the certificates of classic clusters which always exist, the named API certificates
+// and the default ingress certificate are discovered from the cluster configuration
+var classicSources = []certificateSource{
+	{category: categoryAPI, namespace: "openshift-kube-apiserver", name: "external-loadbalancer-serving-certkey"},
+	{category: categoryAPI, namespace: "openshift-kube-apiserver", name: "internal-loadbalancer-serving-certkey"},
+	{category: categoryAPI, namespace: "openshift-kube-apiserver", name: "service-network-serving-certkey"},
+	{category: categoryAPI, namespace: "openshift-kube-apiserver", name: "localhost-serving-cert-certkey"},
+	{category: categoryCA, namespace: "openshift-kube-apiserver-operator", name: "kube-apiserver-to-kubelet-signer", ca: true},
+	{category: categoryCA, namespace: "openshift-kube-apiserver-operator", name: "kube-control-plane-signer", ca: true},
+	{category: categoryCA, namespace: "openshift-kube-apiserver-operator", name: "loadbalancer-serving-signer", ca: true},
+	{category: categoryCA, namespace: "openshift-kube-apiserver-operator", name: "localhost-serving-signer", ca: true},
+	{category: categoryCA, namespace: "openshift-kube-apiserver-operator", name: "service-network-serving-signer", ca: true},
+	{category: categoryCA, namespace: "openshift-kube-apiserver-operator", name: "aggregator-client-signer", ca: true},
+	{category: categoryCA, namespace: "openshift-service-ca", name: "signing-key", ca: true},
+}
+
+// hcpManagementSecrets are the certificates of a hosted control plane in its namespace on the management cluster.
+// The signing CAs are stored by hypershift under ca.crt rather than tls.crt.
+var hcpManagementSecrets = []certificateSource{
+	{category: categoryAPI, name: "kas-server-crt"},
+	{category: categoryAPI, name: "kas-server-private-crt"},
+	{category: categorySigning, name: "root-ca", key: corev1.ServiceAccountRootCAKey, ca: true},
+	{category: categorySigning, name: "cluster-signer-ca", key: corev1.ServiceAccountRootCAKey, ca: true},
+}
+
+var (
+	apiServerGVK         = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "APIServer"}
+	ingressControllerGVK = schema.GroupVersionKind{Group: "operator.openshift.io", Version: "v1", Kind: "IngressController"}
+)
+
+// certificateSource is a secret holding a certificate to report on
+type certificateSource struct {
+	category  string
+	namespace string
+	name      string
+	// key is the key of the certificate in the secret, tls.crt if empty
+	key string
+	ca  bool
+}
+
+// certificateStatus is the expiry status of a certificate
+type certificateStatus struct {
+	Category  string    `json:"category"`
+	Cluster   string    `json:"cluster"`
+	Secret    string    `json:"secret"`
+	Subject   string    `json:"subject"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	Status    string    `json:"status"`
+}
+
+type statusOptions struct {
+	clusterID  string
+	reason     string
+	warnWithin time.Duration
+	output     string
+}
+
+func newCmdStatus() *cobra.Command {
+	opts := &statusOptions{}
+
+	statusCmd := &cobra.Command{
+		Use:   "status --cluster-id <cluster-id> --reason <reason>",
+		Short: "Report the expiry of the certificates of a cluster",
+		Long: `Report the expiry of the certificates of a cluster.
+
+The following certificates are reported, flagging the ones expiring within --warn-within:
+  - the API serving certificates, including custom named certificates
+  - the default ingress certificate
+  - the internal CAs of the control plane, flagged as ROTATION OVERDUE once past 80% of
+    their lifetime without being rotated
+  - for HCP clusters, the API serving and signing certificates of the hosted control plane
+    on the management cluster
+
+Reading the certificates requires backplane-cluster-admin elevation.`,
+		Example: `  # Report the certificates of a cluster
+  osdctl cluster certificates status --cluster-id ${CLUSTER_ID} --reason "OHSS-1234"
+
+  # Flag the certificates expiring within the next 90 days
+  osdctl cluster certificates status --cluster-id ${CLUSTER_ID} --reason "OHSS-1234" --warn-within 2160h`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.output != "table" && opts.output != "json" {
+				return fmt.Errorf("invalid output format %q, expected table or json", opts.output)
+			}
+			cmd.SilenceUsage = true
+			return opts.run(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	statusCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID (internal or external)")
+	statusCmd.Flags().StringVar(&opts.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	statusCmd.Flags().DurationVar(&opts.warnWithin, "warn-within", 30*24*time.Hour, "Flag the certificates expiring within this duration")
+	statusCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	_ = statusCmd.MarkFlagRequired("cluster-id")
+	_ = statusCmd.MarkFlagRequired("reason")
+
+	return statusCmd
+}
+
+func (o *statusOptions) run(ctx context.Context, out io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cluster, err := utils.GetCluster(conn, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	elevationReasons := []string{o.reason, "Reporting certificate expiry with osdctl cluster certificates status"}
+	kubeClient, err := k8s.NewAsBackplaneClusterAdmin(cluster.ID(), client.Options{}, elevationReasons...)
+	if err != nil {
+		return err
+	}
+
+	sources, err := discoverSources(ctx, kubeClient, cluster.Hypershift().Enabled())
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	certificates, err := collectCertificates(ctx, kubeClient, cluster.Name(), sources, now, o.warnWithin)
+	if err != nil {
+		return err
+	}
+
+	if cluster.Hypershift().Enabled() {
+		mc, err := utils.GetManagementCluster(cluster.ID())
+		if err != nil {
+			return err
+		}
+		namespace, err := utils.GetHCPNamespace(cluster.ID())
+		if err != nil {
+			return err
+		}
+		mcClient, err := k8s.NewAsBackplaneClusterAdmin(mc.ID(), client.Options{}, elevationReasons...)
+		if err != nil {
+			return err
+		}
+
+		mcSources := make([]certificateSource, 0, len(hcpManagementSecrets))
+		for _, source := range hcpManagementSecrets {
+			source.namespace = namespace
+			mcSources = append(mcSources, source)
+		}
+		mcCertificates, err := collectCertificates(ctx, mcClient, mc.Name(), mcSources, now, o.warnWithin)
+		if err != nil {
+			return err
+		}
+		certificates = append(certificates, mcCertificates...)
+	}
+
+	return printCertificates(out, certificates, o.output, now)
+}
+
+// discoverSources returns the certificates to report for the cluster. HCP clusters only run the ingress
+// on the cluster itself, their API serving and signing certificates are on the management cluster.
+func discoverSources(ctx context.Context, c client.Client, hcp bool) ([]certificateSource, error) {
+	var sources []certificateSource
+	if !hcp {
+		sources = append(sources, classicSources...)
+
+		apiServer := &unstructured.Unstructured{}
+		apiServer.SetGroupVersionKind(apiServerGVK)
+		if err := c.Get(ctx, client.ObjectKey{Name: "cluster"}, apiServer); err != nil {
+			return nil, fmt.Errorf("failed to get the apiserver configuration: %w", err)
+		}
+		namedCertificates, _, _ := unstructured.NestedSlice(apiServer.Object, "spec", "servingCerts", "namedCertificates")
+		for _, n := range namedCertificates {
+			namedCertificate, ok := n.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, found, _ := unstructured.NestedString(namedCertificate, "servingCertificate", "name"); found && name != "" {
+				sources = append(sources, certificateSource{category: categoryAPI, namespace: "openshift-config", name: name})
+			}
+		}
+	}
+
+	ingressController := &unstructured.Unstructured{}
+	ingressController.SetGroupVersionKind(ingressControllerGVK)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "openshift-ingress-operator", Name: "default"}, ingressController); err != nil {
+		return nil, fmt.Errorf("failed to get the default ingresscontroller: %w", err)
+	}
+	ingressSecret, _, _ := unstructured.NestedString(ingressController.Object, "spec", "defaultCertificate", "name")
+	if ingressSecret == "" {
+		ingressSecret = "router-certs-default"
+	}
+	sources = append(sources, certificateSource{category: categoryIngress, namespace: "openshift-ingress", name: ingressSecret})
+
+	return sources, nil
+}
+
+// collectCertificates reads the certificates of the sources, skipping secrets which don't exist on the cluster
+func collectCertificates(ctx context.Context, c client.Client, clusterName string, sources []certificateSource, now time.Time, warnWithin time.Duration) ([]certificateStatus, error) {
+	var certificates []certificateStatus
+	for _, source := range sources {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: source.namespace, Name: source.name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", source.namespace, source.name, err)
+		}
+
+		key := source.key
+		if key == "" {
+			key = corev1.TLSCertKey
+		}
+		cert, err := parseCertificate(secret.Data[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the certificate of secret %s/%s: %w", source.namespace, source.name, err)
+		}
+
+		certificates = append(certificates, certificateStatus{
+			Category:  source.category,
+			Cluster:   clusterName,
+			Secret:    source.namespace + "/" + source.name,
+			Subject:   cert.Subject.CommonName,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			Status:    evaluateCertificate(cert, source.ca, now, warnWithin),
+		})
+	}
+	return certificates, nil
+}
+
+// parseCertificate returns the first certificate of a PEM bundle, which is the leaf of a chain
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// evaluateCertificate returns the status of a certificate at the given time
+func evaluateCertificate(cert *x509.Certificate, ca bool, now time.Time, warnWithin time.Duration) string {
+	switch {
+	case !now.Before(cert.NotAfter):
+		return statusExpired
+	case cert.NotAfter.Sub(now) <= warnWithin:
+		return statusExpiring
+	}
+
+	if ca {
+		lifetime := cert.NotAfter.Sub(cert.NotBefore)
+		if lifetime > 0 && float64(now.Sub(cert.NotBefore)) > rotationThreshold*float64(lifetime) {
+			return statusRotationOverdue
+		}
+	}
+	return statusOK
+}
+
+func printCertificates(w io.Writer, certificates []certificateStatus, output string, now time.Time) error {
+	sort.SliceStable(certificates, func(i, j int) bool {
+		return certificates[i].NotAfter.Before(certificates[j].NotAfter)
+	})
+
+	if output == "json" {
+		if certificates == nil {
+			certificates = []certificateStatus{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(certificates)
+	}
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"STATUS", "CATEGORY", "CLUSTER", "SECRET", "SUBJECT", "EXPIRES", "REMAINING"})
+	flagged := 0
+	for _, cert := range certificates {
+		if cert.Status != statusOK {
+			flagged++
+		}
+		table.AddRow([]string{
+			cert.Status,
+			cert.Category,
+			cert.Cluster,
+			cert.Secret,
+			cert.Subject,
+			cert.NotAfter.UTC().Format(time.RFC3339),
+			formatRemaining(cert.NotAfter.Sub(now)),
+		})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d certificate(s) checked, %d need attention\n", len(certificates), flagged)
+	return err
+}
+
+// formatRemaining formats the remaining validity of a certificate in days
+func formatRemaining(remaining time.Duration) string {
+	if remaining <= 0 {
+		return "expired"
+	}
+	days := int(remaining.Hours() / 24)
+	if days == 0 {
+		return remaining.Truncate(time.Minute).String()
+	}
+	return fmt.Sprintf("%dd", days)
+}
@@ -0,0 +1,183 @@
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testNow = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestCertificate(t *testing.T, commonName string, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newTestSecret(namespace, name string, cert []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: cert},
+	}
+}
+
+func newTestObject(apiVersion, kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+	if namespace == "" {
+		unstructured.RemoveNestedField(obj.Object, "metadata", "namespace")
+	}
+	return obj
+}
+
+func TestEvaluateCertificate(t *testing.T) {
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		notAfter  time.Time
+		ca        bool
+		expected  string
+	}{
+		{name: "valid", notBefore: testNow.AddDate(0, -1, 0), notAfter: testNow.AddDate(1, 0, 0), expected: statusOK},
+		{name: "expiring", notBefore: testNow.AddDate(0, -1, 0), notAfter: testNow.AddDate(0, 0, 10), expected: statusExpiring},
+		{name: "expired", notBefore: testNow.AddDate(-1, 0, 0), notAfter: testNow.Add(-time.Hour), expected: statusExpired},
+		{name: "ca rotated", notBefore: testNow.AddDate(0, -1, 0), notAfter: testNow.AddDate(1, 0, 0), ca: true, expected: statusOK},
+		{name: "ca rotation overdue", notBefore: testNow.AddDate(-1, 0, 0), notAfter: testNow.AddDate(0, 2, 0), ca: true, expected: statusRotationOverdue},
+		{name: "serving certificate is not rotated by the CA controllers", notBefore: testNow.AddDate(-1, 0, 0), notAfter: testNow.AddDate(0, 2, 0), expected: statusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := parseCertificate(newTestCertificate(t, "test", tt.notBefore, tt.notAfter))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, evaluateCertificate(cert, tt.ca, testNow, 30*24*time.Hour))
+		})
+	}
+}
+
+func TestDiscoverAndCollectCertificates(t *testing.T) {
+	valid := newTestCertificate(t, "api.example.com", testNow.AddDate(0, -1, 0), testNow.AddDate(1, 0, 0))
+	expiring := newTestCertificate(t, "*.apps.example.com", testNow.AddDate(0, -2, 0), testNow.AddDate(0, 0, 5))
+	overdue := newTestCertificate(t, "kube-control-plane-signer", testNow.AddDate(-1, 0, 0), testNow.AddDate(0, 1, 15))
+
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		newTestObject("config.openshift.io/v1", "APIServer", "", "cluster", map[string]interface{}{
+			"servingCerts": map[string]interface{}{
+				"namedCertificates": []interface{}{
+					map[string]interface{}{"names": []interface{}{"api.example.com"}, "servingCertificate": map[string]interface{}{"name": "custom-api-cert"}},
+				},
+			},
+		}),
+		newTestObject("operator.openshift.io/v1", "IngressController", "openshift-ingress-operator", "default", map[string]interface{}{
+			"defaultCertificate": map[string]interface{}{"name": "custom-ingress-cert"},
+		}),
+		newTestSecret("openshift-config", "custom-api-cert", valid),
+		newTestSecret("openshift-ingress", "custom-ingress-cert", expiring),
+		newTestSecret("openshift-kube-apiserver-operator", "kube-control-plane-signer", overdue),
+	).Build()
+
+	ctx := context.Background()
+	sources, err := discoverSources(ctx, fakeClient, false)
+	require.NoError(t, err)
+	assert.Contains(t, sources, certificateSource{category: categoryAPI, namespace: "openshift-config", name: "custom-api-cert"})
+	assert.Contains(t, sources, certificateSource{category: categoryIngress, namespace: "openshift-ingress", name: "custom-ingress-cert"})
+
+	certificates, err := collectCertificates(ctx, fakeClient, "test-cluster", sources, testNow, 30*24*time.Hour)
+	require.NoError(t, err)
+
+	statuses := map[string]string{}
+	for _, cert := range certificates {
+		assert.Equal(t, "test-cluster", cert.Cluster)
+		statuses[cert.Secret] = cert.Status
+	}
+	assert.Equal(t, map[string]string{
+		"openshift-config/custom-api-cert":                            statusOK,
+		"openshift-ingress/custom-ingress-cert":                       statusExpiring,
+		"openshift-kube-apiserver-operator/kube-control-plane-signer": statusRotationOverdue,
+	}, statuses)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printCertificates(out, certificates, "table", testNow))
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 6)
+	assert.Contains(t, string(lines[1]), "EXPIRING")
+	assert.Contains(t, string(lines[1]), "5d")
+	assert.Equal(t, "3 certificate(s) checked, 2 need attention", string(lines[5]))
+}
+
+func TestDiscoverSourcesHCP(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		newTestObject("operator.openshift.io/v1", "IngressController", "openshift-ingress-operator", "default", map[string]interface{}{}),
+	).Build()
+
+	sources, err := discoverSources(context.Background(), fakeClient, true)
+	require.NoError(t, err)
+	assert.Equal(t, []certificateSource{{category: categoryIngress, namespace: "openshift-ingress", name: "router-certs-default"}}, sources)
+}
+
+func TestCollectCertificatesHCPManagementSecrets(t *testing.T) {
+	serving := newTestCertificate(t, "kube-apiserver", testNow.AddDate(0, -1, 0), testNow.AddDate(0, 11, 0))
+	rootCA := newTestCertificate(t, "root-ca", testNow.AddDate(-9, 0, 0), testNow.AddDate(1, 0, 0))
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		newTestSecret("ocm-production-abc-hcp", "kas-server-crt", serving),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ocm-production-abc-hcp", Name: "root-ca"},
+			Data:       map[string][]byte{corev1.ServiceAccountRootCAKey: rootCA, corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+	).Build()
+
+	var sources []certificateSource
+	for _, source := range hcpManagementSecrets {
+		source.namespace = "ocm-production-abc-hcp"
+		sources = append(sources, source)
+	}
+	certificates, err := collectCertificates(context.Background(), fakeClient, "mc", sources, testNow, 30*24*time.Hour)
+	require.NoError(t, err)
+
+	statuses := map[string]string{}
+	for _, cert := range certificates {
+		statuses[cert.Secret] = cert.Status
+	}
+	assert.Equal(t, map[string]string{
+		"ocm-production-abc-hcp/kas-server-crt": statusOK,
+		"ocm-production-abc-hcp/root-ca":        statusRotationOverdue,
+	}, statuses)
+}
+
+func TestCollectCertificatesInvalidSecret(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().WithObjects(newTestSecret("ns", "broken", []byte("not a certificate"))).Build()
+
+	_, err := collectCertificates(context.Background(), fakeClient, "test-cluster",
+		[]certificateSource{{category: categoryAPI, namespace: "ns", name: "broken"}}, testNow, time.Hour)
+	assert.EqualError(t, err, "failed to parse the certificate of secret ns/broken: no PEM encoded certificate found")
+}
@@ -0,0 +1,311 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultQuotaThreshold = 90
+
+// platformNamespaces are the namespaces of the platform which aren't prefixed like the others
+var platformNamespaces = []string{"default", "openshift", "kube-system", "kube-public", "kube-node-lease"}
+
+// platformNamespacePrefixes are the prefixes of the namespaces managed by the platform and Red Hat
+var platformNamespacePrefixes = []string{"openshift-", "kube-", "redhat-"}
+
+type checkQuotaOptions struct {
+	clusterID     string
+	namespace     string
+	allNamespaces bool
+	threshold     int
+
+	out     io.Writer
+	factory clients.Factory
+	client  client.Client
+}
+
+// quotaUsage is the usage of a resource of a resource quota
+type quotaUsage struct {
+	namespace string
+	quota     string
+	resource  corev1.ResourceName
+	used      resource.Quantity
+	hard      resource.Quantity
+	percent   int
+}
+
+func newCmdCheckQuota(factory clients.Factory) *cobra.Command {
+	opts := &checkQuotaOptions{factory: factory}
+	cmd := &cobra.Command{
+		Use:   "check-quota",
+		Short: "Audit the resource quotas and limit ranges of the customer namespaces of a cluster",
+		Long: `Audit the resource quotas and limit ranges of the customer namespaces of a cluster.
+
+Resource quotas and limit ranges set by customers frequently block installs and upgrades, e.g. when a quota
+prevents the pods of a namespace from being recreated on other nodes. The usage of every resource of the quotas
+is listed along with the limit ranges, and the namespaces using more than the threshold of a quota are highlighted.
+
+The namespaces of the platform (openshift-*, kube-*, redhat-*, default) are skipped unless --all-namespaces is set.
+
+Requires previous login to the api server via "ocm backplane login".`,
+		Example: `  # Audit the quotas of the customer namespaces of a cluster
+  osdctl cluster check-quota --cluster-id ${CLUSTER_ID}
+
+  # Audit the quotas of a namespace, highlighting the resources above 80% of their quota
+  osdctl cluster check-quota --cluster-id ${CLUSTER_ID} --namespace my-app --threshold 80`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			defer opts.factory.Close()
+			opts.out = cmd.OutOrStdout()
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "The internal ID of the cluster to audit")
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Only audit the given namespace")
+	cmd.Flags().BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false, "Also audit the namespaces of the platform")
+	cmd.Flags().IntVar(&opts.threshold, "threshold", defaultQuotaThreshold, "Percentage of a quota above which a namespace is highlighted")
+	_ = cmd.MarkFlagRequired("cluster-id")
+	cmd.MarkFlagsMutuallyExclusive("namespace", "all-namespaces")
+
+	return cmd
+}
+
+func (o *checkQuotaOptions) validate() error {
+	if err := utils.IsValidClusterKey(o.clusterID); err != nil {
+		return err
+	}
+	if o.threshold < 0 || o.threshold > 100 {
+		return fmt.Errorf("--threshold must be between 0 and 100, got %d", o.threshold)
+	}
+	return nil
+}
+
+func (o *checkQuotaOptions) complete() error {
+	cluster, err := o.factory.Cluster(o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	o.client, err = o.factory.KubeClient(cluster.ID(), client.Options{Scheme: scheme})
+	return err
+}
+
+func (o *checkQuotaOptions) run(ctx context.Context) error {
+	var listOpts []client.ListOption
+	if o.namespace != "" {
+		listOpts = append(listOpts, client.InNamespace(o.namespace))
+	}
+
+	quotas := &corev1.ResourceQuotaList{}
+	if err := o.client.List(ctx, quotas, listOpts...); err != nil {
+		return fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+	limitRanges := &corev1.LimitRangeList{}
+	if err := o.client.List(ctx, limitRanges, listOpts...); err != nil {
+		return fmt.Errorf("failed to list limit ranges: %w", err)
+	}
+
+	var usages []quotaUsage
+	for _, quota := range quotas.Items {
+		if o.skipNamespace(quota.Namespace) {
+			continue
+		}
+		usages = append(usages, resourceQuotaUsages(quota)...)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].namespace != usages[j].namespace {
+			return usages[i].namespace < usages[j].namespace
+		}
+		if usages[i].quota != usages[j].quota {
+			return usages[i].quota < usages[j].quota
+		}
+		return usages[i].resource < usages[j].resource
+	})
+
+	var ranges []corev1.LimitRange
+	for _, limitRange := range limitRanges.Items {
+		if !o.skipNamespace(limitRange.Namespace) {
+			ranges = append(ranges, limitRange)
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].Namespace != ranges[j].Namespace {
+			return ranges[i].Namespace < ranges[j].Namespace
+		}
+		return ranges[i].Name < ranges[j].Name
+	})
+
+	if err := o.printQuotas(usages); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.out)
+	if err := printLimitRanges(o.out, ranges); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.out)
+	o.printSummary(usages)
+	return nil
+}
+
+// skipNamespace returns whether the namespace is one of the platform, which isn't audited without --all-namespaces
+func (o *checkQuotaOptions) skipNamespace(namespace string) bool {
+	if o.allNamespaces || o.namespace != "" {
+		return false
+	}
+	return isPlatformNamespace(namespace)
+}
+
+func isPlatformNamespace(namespace string) bool {
+	for _, name := range platformNamespaces {
+		if namespace == name {
+			return true
+		}
+	}
+	for _, prefix := range platformNamespacePrefixes {
+		if strings.HasPrefix(namespace, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceQuotaUsages returns the usage of each resource of the quota, from the status maintained by the quota
+// controller
+func resourceQuotaUsages(quota corev1.ResourceQuota) []quotaUsage {
+	var usages []quotaUsage
+	for name, hard := range quota.Status.Hard {
+		used := quota.Status.Used[name]
+		usages = append(usages, quotaUsage{
+			namespace: quota.Namespace,
+			quota:     quota.Name,
+			resource:  name,
+			used:      used,
+			hard:      hard,
+			percent:   usagePercent(used, hard),
+		})
+	}
+	return usages
+}
+
+// usagePercent returns the percentage of hard which is used, a quota of zero is fully used
+func usagePercent(used, hard resource.Quantity) int {
+	if hard.IsZero() {
+		return 100
+	}
+	return int(float64(used.MilliValue()) * 100 / float64(hard.MilliValue()))
+}
+
+func (o *checkQuotaOptions) printQuotas(usages []quotaUsage) error {
+	if len(usages) == 0 {
+		fmt.Fprintln(o.out, "No resource quotas found")
+		return nil
+	}
+	p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	p.AddRow([]string{"NAMESPACE", "QUOTA", "RESOURCE", "USED", "HARD", "USAGE"})
+	for _, u := range usages {
+		usage := fmt.Sprintf("%d%%", u.percent)
+		if u.percent > o.threshold {
+			usage += " (!)"
+		}
+		p.AddRow([]string{u.namespace, u.quota, string(u.resource), u.used.String(), u.hard.String(), usage})
+	}
+	return p.Flush()
+}
+
+func printLimitRanges(w io.Writer, ranges []corev1.LimitRange) error {
+	if len(ranges) == 0 {
+		fmt.Fprintln(w, "No limit ranges found")
+		return nil
+	}
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"NAMESPACE", "LIMIT RANGE", "TYPE", "RESOURCE", "MIN", "MAX", "DEFAULT REQUEST", "DEFAULT LIMIT"})
+	for _, limitRange := range ranges {
+		for _, item := range limitRange.Spec.Limits {
+			for _, name := range limitRangeResources(item) {
+				p.AddRow([]string{
+					limitRange.Namespace,
+					limitRange.Name,
+					string(item.Type),
+					string(name),
+					quantityOrDash(item.Min, name),
+					quantityOrDash(item.Max, name),
+					quantityOrDash(item.DefaultRequest, name),
+					quantityOrDash(item.Default, name),
+				})
+			}
+		}
+	}
+	return p.Flush()
+}
+
+// limitRangeResources returns the sorted resources constrained by the item of a limit range
+func limitRangeResources(item corev1.LimitRangeItem) []corev1.ResourceName {
+	seen := map[corev1.ResourceName]bool{}
+	var names []corev1.ResourceName
+	for _, list := range []corev1.ResourceList{item.Min, item.Max, item.DefaultRequest, item.Default, item.MaxLimitRequestRatio} {
+		for name := range list {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func quantityOrDash(list corev1.ResourceList, name corev1.ResourceName) string {
+	if q, ok := list[name]; ok {
+		return q.String()
+	}
+	return "-"
+}
+
+// printSummary lists the namespaces using more than the threshold of a quota, with their most used resource
+func (o *checkQuotaOptions) printSummary(usages []quotaUsage) {
+	highest := map[string]quotaUsage{}
+	for _, u := range usages {
+		if current, ok := highest[u.namespace]; u.percent > o.threshold && (!ok || u.percent > current.percent) {
+			highest[u.namespace] = u
+		}
+	}
+	if len(highest) == 0 {
+		fmt.Fprintf(o.out, "No namespace uses more than %d%% of a quota\n", o.threshold)
+		return
+	}
+
+	namespaces := make([]string, 0, len(highest))
+	for namespace := range highest {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	fmt.Fprintf(o.out, "%d namespace(s) use more than %d%% of a quota, which can block installs and upgrades:\n", len(namespaces), o.threshold)
+	for _, namespace := range namespaces {
+		u := highest[namespace]
+		fmt.Fprintf(o.out, "  %s: %s of quota %s at %d%% (%s/%s)\n", namespace, u.resource, u.quota, u.percent, u.used.String(), u.hard.String())
+	}
+}
@@ -0,0 +1,113 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestResourceQuota(namespace, name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+		Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func TestCheckQuotaRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newTestResourceQuota("my-app", "compute",
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4"), corev1.ResourcePods: resource.MustParse("10")},
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("3900m"), corev1.ResourcePods: resource.MustParse("2")}),
+		newTestResourceQuota("other-app", "compute",
+			corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("8Gi")},
+			corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("4Gi")}),
+		newTestResourceQuota("openshift-monitoring", "platform",
+			corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+			corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}),
+		&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-app", Name: "limits"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:    corev1.LimitTypeContainer,
+				Max:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Default: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}}},
+		},
+	).Build()
+
+	tests := []struct {
+		name          string
+		namespace     string
+		allNamespaces bool
+		threshold     int
+		contains      []string
+		notContains   []string
+	}{
+		{
+			name:      "customer namespaces",
+			threshold: 90,
+			contains: []string{
+				"requests.cpu", "3900m", "97% (!)",
+				"pods", "20%",
+				"other-app", "50%",
+				"Container", "500m", "512Mi",
+				"1 namespace(s) use more than 90% of a quota",
+				"my-app: requests.cpu of quota compute at 97% (3900m/4)",
+			},
+			notContains: []string{"openshift-monitoring", "50% (!)"},
+		},
+		{
+			name:          "all namespaces",
+			allNamespaces: true,
+			threshold:     40,
+			contains:      []string{"openshift-monitoring", "3 namespace(s) use more than 40% of a quota", "other-app: limits.memory of quota compute at 50% (4Gi/8Gi)"},
+		},
+		{
+			name:        "single namespace",
+			namespace:   "other-app",
+			threshold:   90,
+			contains:    []string{"other-app", "No limit ranges found", "No namespace uses more than 90% of a quota"},
+			notContains: []string{"my-app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			opts := &checkQuotaOptions{namespace: tt.namespace, allNamespaces: tt.allNamespaces, threshold: tt.threshold, out: out, client: fakeClient}
+			require.NoError(t, opts.run(context.Background()))
+			for _, s := range tt.contains {
+				assert.Contains(t, out.String(), s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, out.String(), s)
+			}
+		})
+	}
+}
+
+func TestUsagePercent(t *testing.T) {
+	assert.Equal(t, 50, usagePercent(resource.MustParse("500m"), resource.MustParse("1")))
+	assert.Equal(t, 100, usagePercent(resource.MustParse("0"), resource.MustParse("0")))
+	assert.Equal(t, 150, usagePercent(resource.MustParse("3Gi"), resource.MustParse("2Gi")))
+}
+
+func TestIsPlatformNamespace(t *testing.T) {
+	for _, ns := range []string{"default", "openshift", "openshift-monitoring", "kube-system", "redhat-rhoam"} {
+		assert.True(t, isPlatformNamespace(ns), ns)
+	}
+	for _, ns := range []string{"my-app", "kubeflow", "openshift_custom"} {
+		assert.False(t, isPlatformNamespace(ns), ns)
+	}
+}
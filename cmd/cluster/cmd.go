@@ -3,12 +3,20 @@ package cluster
 import (
 	"github.com/openshift/osdctl/cmd/cluster/access"
 	"github.com/openshift/osdctl/cmd/cluster/cad"
+	"github.com/openshift/osdctl/cmd/cluster/certificates"
+	"github.com/openshift/osdctl/cmd/cluster/labels"
+	"github.com/openshift/osdctl/cmd/cluster/machinepool"
+	"github.com/openshift/osdctl/cmd/cluster/metrics"
+	"github.com/openshift/osdctl/cmd/cluster/node"
+	"github.com/openshift/osdctl/cmd/cluster/oidc"
 	"github.com/openshift/osdctl/cmd/cluster/reports"
 	"github.com/openshift/osdctl/cmd/cluster/resize"
 	"github.com/openshift/osdctl/cmd/cluster/sre_operators"
 	"github.com/openshift/osdctl/cmd/cluster/ssh"
 	"github.com/openshift/osdctl/cmd/cluster/support"
+	"github.com/openshift/osdctl/cmd/cluster/upgrade"
 	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/clients"
 	"github.com/openshift/osdctl/pkg/k8s"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -28,6 +36,7 @@ func NewCmdCluster(streams genericclioptions.IOStreams, client *k8s.LazyClient,
 	clusterCmd.AddCommand(newCmdOwner(streams, globalOpts))
 	clusterCmd.AddCommand(support.NewCmdSupport(streams, client, globalOpts))
 	clusterCmd.AddCommand(resize.NewCmdResize())
+	clusterCmd.AddCommand(resize.NewCmdValidateSize())
 	clusterCmd.AddCommand(newCmdResync())
 	clusterCmd.AddCommand(newCmdContext())
 	clusterCmd.AddCommand(newCmdTransferOwner(streams, globalOpts))
@@ -37,13 +46,16 @@ func NewCmdCluster(streams genericclioptions.IOStreams, client *k8s.LazyClient,
 	clusterCmd.AddCommand(newCmdValidatePullSecret())
 	clusterCmd.AddCommand(newCmdValidatePullSecretExt())
 	clusterCmd.AddCommand(newCmdEtcdHealthCheck())
+	clusterCmd.AddCommand(newCmdEtcdHealth())
 	clusterCmd.AddCommand(newCmdEtcdMemberReplacement())
+	clusterCmd.AddCommand(newCmdEtcd())
 	clusterCmd.AddCommand(newCmdFromInfraId(globalOpts))
 	clusterCmd.AddCommand(NewCmdHypershiftInfo(streams))
 	clusterCmd.AddCommand(newCmdOrgId())
 	clusterCmd.AddCommand(newCmdDetachStuckVolume())
 	clusterCmd.AddCommand(newCmdChangeVolumeType())
 	clusterCmd.AddCommand(NewCmdVerifyDNS(streams))
+	clusterCmd.AddCommand(newCmdDNS(streams))
 	clusterCmd.AddCommand(ssh.NewCmdSSH())
 	clusterCmd.AddCommand(sre_operators.NewCmdSREOperators(streams, client))
 	clusterCmd.AddCommand(newCmdGetEnvVars())
@@ -52,5 +64,20 @@ func NewCmdCluster(streams genericclioptions.IOStreams, client *k8s.LazyClient,
 	clusterCmd.AddCommand(newCmdSnapshot())
 	clusterCmd.AddCommand(newCmdDiff())
 	clusterCmd.AddCommand(newCmdIMDSv2())
+	clusterCmd.AddCommand(newCmdMachines())
+	clusterCmd.AddCommand(newCmdCheckQuota(clients.NewFactory()))
+	clusterCmd.AddCommand(newCmdHibernate())
+	clusterCmd.AddCommand(newCmdResume())
+	clusterCmd.AddCommand(newCmdKubeconfig(clients.NewFactory()))
+	clusterCmd.AddCommand(newCmdSearch())
+	clusterCmd.AddCommand(newCmdEvents())
+	clusterCmd.AddCommand(metrics.NewCmdMetrics())
+	clusterCmd.AddCommand(certificates.NewCmdCertificates())
+	clusterCmd.AddCommand(oidc.NewCmdOidc())
+	clusterCmd.AddCommand(node.NewCmdNode())
+	clusterCmd.AddCommand(node.NewCmdRebootNode(clients.NewFactory()))
+	clusterCmd.AddCommand(machinepool.NewCmdMachinePool())
+	clusterCmd.AddCommand(labels.NewCmdLabels())
+	clusterCmd.AddCommand(upgrade.NewCmdUpgrade())
 	return clusterCmd
 }
@@ -10,6 +10,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -46,8 +47,19 @@ const (
 	longOutputConfigValue         = "long"
 	jsonOutputConfigValue         = "json"
 	delimiter                     = ">> "
+
+	// The sections of the context --only selects
+	contextSectionPagerDuty      = "pd"
+	contextSectionServiceLogs    = "sl"
+	contextSectionJira           = "jira"
+	contextSectionLimitedSupport = "ls"
+
+	// compactMaxItems is the number of items listed per section in the compact output
+	compactMaxItems = 3
 )
 
+var contextSections = []string{contextSectionPagerDuty, contextSectionServiceLogs, contextSectionJira, contextSectionLimitedSupport}
+
 type contextOptions struct {
 	cluster *cmv1.Cluster
 
@@ -67,6 +79,12 @@ type contextOptions struct {
 	jiratoken         string
 	teamIds           []string
 	regionID          string
+	// only are the sections of the context to collect and print, all of them if empty
+	only    []string
+	compact bool
+
+	// tableFlags apply to the short output table
+	tableFlags printer.TableFlags
 }
 
 type contextData struct {
@@ -138,7 +156,16 @@ func newCmdContext() *cobra.Command {
   osdctl cluster context --cluster-id ${CLUSTER_ID}
 
   # Show cluster context with full checks
-  osdctl cluster context --cluster-id ${CLUSTER_ID} --full`,
+  osdctl cluster context --cluster-id ${CLUSTER_ID} --full
+
+  # Only show the version and support status in the short output
+  osdctl cluster context --cluster-id ${CLUSTER_ID} --output short --columns version,supported?
+
+  # Only show the PagerDuty alerts of the cluster
+  osdctl cluster context --cluster-id ${CLUSTER_ID} --only pd
+
+  # Show a one-screen summary of the support status and service logs, to paste into an incident channel
+  osdctl cluster context --cluster-id ${CLUSTER_ID} --compact --only ls,sl`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -164,6 +191,10 @@ func newCmdContext() *cobra.Command {
 	contextCmd.Flags().StringVar(&options.usertoken, "usertoken", "", fmt.Sprintf("Pass in PD usertoken directly. If not passed in, by default will read `pd_user_token` from ~/config/%s", osdctlConfig.ConfigFileName))
 	contextCmd.Flags().StringVar(&options.jiratoken, "jiratoken", "", fmt.Sprintf("Pass in the Jira access token directly. If not passed in, by default will read `jira_token` from ~/.config/%s.\nJira access tokens can be registered by visiting %s/%s", osdctlConfig.ConfigFileName, JiraBaseURL, JiraTokenRegistrationPath))
 	contextCmd.Flags().StringArrayVarP(&options.teamIds, "team-ids", "t", []string{}, fmt.Sprintf("Pass in PD team IDs directly to filter the PD Alerts by team. Can also be defined as `teamIds` in ~/.config/%s\nWill show all PD Alerts for all PD service IDs if none is defined", osdctlConfig.ConfigFileName))
+	contextCmd.Flags().StringSliceVar(&options.only, "only", nil, fmt.Sprintf("Only collect and print these sections of the context, to speed up targeted checks. Sections: %s (PagerDuty, service logs, Jira, limited support)", strings.Join(contextSections, ", ")))
+	contextCmd.Flags().BoolVar(&options.compact, "compact", false, "Print a one-screen summary, suitable for pasting into incident channels")
+	contextCmd.MarkFlagsMutuallyExclusive("compact", "output")
+	options.tableFlags.AddFlags(contextCmd)
 	return contextCmd
 }
 
@@ -171,6 +202,11 @@ func (o *contextOptions) setup() error {
 	if o.days < 1 {
 		return fmt.Errorf("cannot have a days value lower than 1")
 	}
+	for _, section := range o.only {
+		if !slices.Contains(contextSections, section) {
+			return fmt.Errorf("unknown section %q for --only, valid sections are: %s", section, strings.Join(contextSections, ", "))
+		}
+	}
 
 	// Create OCM client to talk to cluster API
 	defer utils.StartDelayTracker(o.verbose, "OCM Clusters").End()
@@ -229,6 +265,10 @@ func (o *contextOptions) run() error {
 		return fmt.Errorf("unknown Output Format: %s", o.output)
 	}
 
+	if o.compact {
+		printFunc = o.printCompactOutput
+	}
+
 	currentData, dataErrors := o.generateContextData()
 	if currentData == nil {
 		fmt.Fprintf(os.Stderr, "Failed to query cluster info: %+v", dataErrors)
@@ -247,32 +287,81 @@ func (o *contextOptions) run() error {
 	return nil
 }
 
+// GetClusterContext returns the context of a cluster as printed by "osdctl cluster context -o json", for callers
+// outside of the CLI such as "osdctl serve". The data points which couldn't be collected are left empty and
+// their errors returned along with the context, which is nil only when the cluster itself couldn't be queried.
+func GetClusterContext(clusterID string, days int, full bool) (json.RawMessage, []error) {
+	o := &contextOptions{clusterID: clusterID, days: days, full: full, pages: 40, output: jsonOutputConfigValue}
+	if err := o.setup(); err != nil {
+		return nil, []error{err}
+	}
+
+	data, dataErrors := o.generateContextData()
+	if data == nil {
+		return nil, dataErrors
+	}
+	jsonOut, err := json.Marshal(data)
+	if err != nil {
+		return nil, append(dataErrors, err)
+	}
+	return jsonOut, dataErrors
+}
+
+// wants returns whether the section of the context is selected by --only
+func (o *contextOptions) wants(section string) bool {
+	return len(o.only) == 0 || slices.Contains(o.only, section)
+}
+
+// wantsAll returns whether the whole context is collected, i.e. --only isn't set
+func (o *contextOptions) wantsAll() bool {
+	return len(o.only) == 0
+}
+
 func (o *contextOptions) printLongOutput(data *contextData, w io.Writer) {
 	data.printClusterHeader(w)
 
-	fmt.Fprintln(w, strings.TrimSpace(data.Description))
-	fmt.Println()
-	printNetworkInfo(data, w)
-	fmt.Println()
-	utils.PrintHandoverAnnouncements(data.HandoverAnnouncements)
-	fmt.Println()
-	utils.PrintLimitedSupportReasons(data.LimitedSupportReasons)
-	fmt.Println()
-	printJIRASupportExceptions(data.SupportExceptions, w)
-	fmt.Println()
-	utils.PrintServiceLogs(data.ServiceLogs, o.verbose, o.days)
-	fmt.Println()
-	utils.PrintJiraIssues(data.JiraIssues)
-	fmt.Println()
-	utils.PrintPDAlerts(data.PdAlerts, data.pdServiceID)
-	fmt.Println()
+	if o.wantsAll() {
+		fmt.Fprintln(w, strings.TrimSpace(data.Description))
+		fmt.Println()
+		printNetworkInfo(data, w)
+		fmt.Println()
+	}
+	if o.wants(contextSectionJira) {
+		utils.PrintHandoverAnnouncements(data.HandoverAnnouncements)
+		fmt.Println()
+	}
+	if o.wants(contextSectionLimitedSupport) {
+		utils.PrintLimitedSupportReasons(data.LimitedSupportReasons)
+		fmt.Println()
+	}
+	if o.wants(contextSectionJira) {
+		printJIRASupportExceptions(data.SupportExceptions, w)
+		fmt.Println()
+	}
+	if o.wants(contextSectionServiceLogs) {
+		utils.PrintServiceLogs(data.ServiceLogs, o.verbose, o.days)
+		fmt.Println()
+	}
+	if o.wants(contextSectionJira) {
+		utils.PrintJiraIssues(data.JiraIssues)
+		fmt.Println()
+	}
+	if o.wants(contextSectionPagerDuty) {
+		utils.PrintPDAlerts(data.PdAlerts, data.pdServiceID)
+		fmt.Println()
+	}
+	if o.full && o.wants(contextSectionPagerDuty) {
+		printHistoricalPDAlertSummary(data.HistoricalAlerts, data.pdServiceID, o.days, w)
+		fmt.Println()
+	}
+	if !o.wantsAll() {
+		return
+	}
+
 	utils.PrintClusterReports(data.clusterReports)
 	fmt.Println()
 
 	if o.full {
-		printHistoricalPDAlertSummary(data.HistoricalAlerts, data.pdServiceID, o.days, w)
-		fmt.Println()
-
 		printCloudTrailLogs(data.CloudtrailEvents, w)
 		fmt.Println()
 	}
@@ -324,29 +413,126 @@ func (o *contextOptions) printShortOutput(data *contextData, w io.Writer) {
 		}
 	}
 
-	table := printer.NewTablePrinter(w, 20, 1, 2, ' ')
-	table.AddRow([]string{
-		"Version",
-		"Supported?",
-		fmt.Sprintf("SLs (last %d d)", o.days),
-		"Jira Tickets",
-		"Current Alerts",
-		fmt.Sprintf("Historical Alerts (last %d d)", o.days),
-	})
-	table.AddRow([]string{
-		data.ClusterVersion,
-		fmt.Sprintf("%t", len(data.LimitedSupportReasons) == 0),
-		fmt.Sprintf("%d (%d internal)", len(data.ServiceLogs), numInternalServiceLogs),
-		fmt.Sprintf("%d", len(data.JiraIssues)),
-		fmt.Sprintf("H: %d | L: %d", highAlertCount, lowAlertCount),
-		historicalAlertsString,
-	})
+	header := []string{"Version"}
+	row := []string{data.ClusterVersion}
+	if o.wants(contextSectionLimitedSupport) {
+		header = append(header, "Supported?")
+		row = append(row, fmt.Sprintf("%t", len(data.LimitedSupportReasons) == 0))
+	}
+	if o.wants(contextSectionServiceLogs) {
+		header = append(header, fmt.Sprintf("SLs (last %d d)", o.days))
+		row = append(row, fmt.Sprintf("%d (%d internal)", len(data.ServiceLogs), numInternalServiceLogs))
+	}
+	if o.wants(contextSectionJira) {
+		header = append(header, "Jira Tickets")
+		row = append(row, fmt.Sprintf("%d", len(data.JiraIssues)))
+	}
+	if o.wants(contextSectionPagerDuty) {
+		header = append(header, "Current Alerts", fmt.Sprintf("Historical Alerts (last %d d)", o.days))
+		row = append(row, fmt.Sprintf("H: %d | L: %d", highAlertCount, lowAlertCount), historicalAlertsString)
+	}
+
+	table := o.tableFlags.Apply(printer.NewTablePrinter(w, 20, 1, 2, ' '))
+	table.AddRow(header)
+	table.AddRow(row)
 
 	if err := table.Flush(); err != nil {
 		fmt.Fprintf(w, "Error printing Short Output: %v\n", err)
 	}
 }
 
+// printCompactOutput prints a one-screen summary of the context, with a line per section
+func (o *contextOptions) printCompactOutput(data *contextData, w io.Writer) {
+	fmt.Fprintf(w, "%s (%s) | %s | %s\n", data.ClusterName, data.ClusterID, data.ClusterVersion, data.OCMEnv)
+
+	if o.wants(contextSectionLimitedSupport) {
+		if len(data.LimitedSupportReasons) == 0 {
+			fmt.Fprintln(w, "Support: fully supported")
+		} else {
+			summaries := make([]string, 0, len(data.LimitedSupportReasons))
+			for _, reason := range data.LimitedSupportReasons {
+				summaries = append(summaries, reason.Summary())
+			}
+			fmt.Fprintf(w, "Support: LIMITED SUPPORT: %s\n", compactList(summaries))
+		}
+	}
+
+	if o.wants(contextSectionServiceLogs) {
+		var internal int
+		var latest *v1.LogEntry
+		for _, serviceLog := range data.ServiceLogs {
+			if serviceLog.InternalOnly() {
+				internal++
+			}
+			if latest == nil || serviceLog.Timestamp().After(latest.Timestamp()) {
+				latest = serviceLog
+			}
+		}
+		line := fmt.Sprintf("Service logs (last %d d): %d (%d internal)", o.days, len(data.ServiceLogs), internal)
+		if latest != nil {
+			line += fmt.Sprintf(", latest: %q on %s", latest.Summary(), latest.Timestamp().Format("2006-01-02"))
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	if o.wants(contextSectionJira) {
+		issues := make([]string, 0, len(data.JiraIssues))
+		for _, issue := range data.JiraIssues {
+			status := ""
+			if issue.Fields != nil && issue.Fields.Status != nil {
+				status = " (" + issue.Fields.Status.Name + ")"
+			}
+			issues = append(issues, issue.Key+status)
+		}
+		line := fmt.Sprintf("Jira: %d open", len(issues))
+		if len(issues) > 0 {
+			line += ": " + compactList(issues)
+		}
+		if len(data.SupportExceptions) > 0 {
+			line += fmt.Sprintf(", %d support exception(s)", len(data.SupportExceptions))
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	if o.wants(contextSectionPagerDuty) {
+		var high, low int
+		var titles []string
+		for _, alerts := range data.PdAlerts {
+			for _, alert := range alerts {
+				if strings.ToLower(alert.Urgency) == "high" {
+					high++
+				} else {
+					low++
+				}
+				titles = append(titles, alert.Title)
+			}
+		}
+		sort.Strings(titles)
+		line := fmt.Sprintf("PagerDuty: %d high, %d low firing", high, low)
+		if len(titles) > 0 {
+			line += ": " + compactList(titles)
+		}
+		if data.HistoricalAlerts != nil {
+			var historical int
+			for _, alerts := range data.HistoricalAlerts {
+				for _, alert := range alerts {
+					historical += alert.Count
+				}
+			}
+			line += fmt.Sprintf(", %d in the last %d d", historical, o.days)
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// compactList joins the first items of the list, mentioning how many more there are
+func compactList(items []string) string {
+	if len(items) <= compactMaxItems {
+		return strings.Join(items, "; ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(items[:compactMaxItems], "; "), len(items)-compactMaxItems)
+}
+
 func (o *contextOptions) printJsonOutput(data *contextData, w io.Writer) {
 	jsonOut, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -380,7 +566,7 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 		WithBaseDomain(o.baseDomain).
 		WithTeamIdList(viper.GetStringSlice(pagerduty.PagerDutyTeamIDsKey)).
 		Init()
-	if err != nil {
+	if err != nil && o.wants(contextSectionPagerDuty) {
 		skipPagerDutyCollection = true
 		dataErrors = append(dataErrors, fmt.Errorf("skipping PagerDuty context collection: %v", err))
 	}
@@ -653,21 +839,29 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 
 	var retrievers []func()
 
-	retrievers = append(
-		retrievers,
-		GetLimitedSupport,
-		GetServiceLogs,
-		GetJiraIssues,
-		GetHandoverAnnouncements,
-		GetSupportExceptions,
-		GetPagerDutyAlerts,
-		GetDynatraceDetails,
-		GetBannedUser,
-		GetMigrationInfo,
-		GetClusterReports,
-	)
-
-	if o.output == longOutputConfigValue {
+	if o.wants(contextSectionLimitedSupport) {
+		retrievers = append(retrievers, GetLimitedSupport)
+	}
+	if o.wants(contextSectionServiceLogs) {
+		retrievers = append(retrievers, GetServiceLogs)
+	}
+	if o.wants(contextSectionJira) {
+		retrievers = append(retrievers, GetJiraIssues, GetHandoverAnnouncements, GetSupportExceptions)
+	}
+	if o.wants(contextSectionPagerDuty) {
+		retrievers = append(retrievers, GetPagerDutyAlerts)
+	}
+	if o.wantsAll() {
+		retrievers = append(
+			retrievers,
+			GetDynatraceDetails,
+			GetBannedUser,
+			GetMigrationInfo,
+			GetClusterReports,
+		)
+	}
+
+	if o.output == longOutputConfigValue && !o.compact && o.wantsAll() {
 
 		GetDescription := func() {
 			defer wg.Done()
@@ -716,11 +910,12 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 			}
 		}
 
-		retrievers = append(
-			retrievers,
-			GetHistoricalPagerDutyAlerts,
-			GetCloudTrailLogs,
-		)
+		if o.wants(contextSectionPagerDuty) {
+			retrievers = append(retrievers, GetHistoricalPagerDutyAlerts)
+		}
+		if o.wantsAll() {
+			retrievers = append(retrievers, GetCloudTrailLogs)
+		}
 	}
 
 	// Add to pdwg before launching goroutines so pdwg.Wait() in
@@ -627,3 +627,81 @@ func TestPrintSDNtoOVNMigrationStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintCompactOutput(t *testing.T) {
+	limitedSupportReason, _ := v1.NewLimitedSupportReason().Summary("Cluster is unreachable").Build()
+	older, _ := v2.NewLogEntry().Summary("Older").InternalOnly(true).Timestamp(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)).Build()
+	latest, _ := v2.NewLogEntry().Summary("Pull secret expiring").Timestamp(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)).Build()
+
+	data := &contextData{
+		ClusterName:           "compact-cluster",
+		ClusterID:             "cluster-123",
+		ClusterVersion:        "4.15.3",
+		OCMEnv:                "production",
+		LimitedSupportReasons: []*v1.LimitedSupportReason{limitedSupportReason},
+		ServiceLogs:           []*v2.LogEntry{older, latest},
+		JiraIssues: []jira.Issue{
+			{Key: "OHSS-1", Fields: &jira.IssueFields{Status: &jira.Status{Name: "New"}}},
+			{Key: "OHSS-2"}, {Key: "OHSS-3"}, {Key: "OHSS-4"},
+		},
+		PdAlerts: map[string][]pd.Incident{"service": {{Title: "ClusterOperatorDown", Urgency: "high"}, {Title: "KubePodNotReady", Urgency: "low"}}},
+	}
+
+	tests := []struct {
+		name        string
+		only        []string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "all sections",
+			contains: []string{
+				"compact-cluster (cluster-123) | 4.15.3 | production",
+				"Support: LIMITED SUPPORT: Cluster is unreachable",
+				`Service logs (last 30 d): 2 (1 internal), latest: "Pull secret expiring" on 2024-05-02`,
+				"Jira: 4 open: OHSS-1 (New); OHSS-2; OHSS-3 (+1 more)",
+				"PagerDuty: 1 high, 1 low firing: ClusterOperatorDown; KubePodNotReady",
+			},
+		},
+		{
+			name:        "only PagerDuty",
+			only:        []string{contextSectionPagerDuty},
+			contains:    []string{"compact-cluster (cluster-123)", "PagerDuty: 1 high, 1 low firing"},
+			notContains: []string{"Support:", "Service logs", "Jira:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &contextOptions{days: 30, only: tt.only}
+			var buf bytes.Buffer
+			opts.printCompactOutput(data, &buf)
+
+			for _, s := range tt.contains {
+				assert.Contains(t, buf.String(), s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, buf.String(), s)
+			}
+		})
+	}
+}
+
+func TestPrintShortOutputOnly(t *testing.T) {
+	opts := &contextOptions{days: 7, only: []string{contextSectionServiceLogs}}
+	data := &contextData{ClusterName: "short-cluster", ClusterVersion: "4.11"}
+
+	var buf bytes.Buffer
+	opts.printShortOutput(data, &buf)
+
+	assert.Contains(t, buf.String(), "SLs (last 7 d)")
+	assert.NotContains(t, buf.String(), "Supported?")
+	assert.NotContains(t, buf.String(), "Current Alerts")
+}
+
+func TestSetupUnknownSection(t *testing.T) {
+	opts := &contextOptions{days: 7, only: []string{"sl", "dt"}}
+
+	err := opts.setup()
+	assert.EqualError(t, err, `unknown section "dt" for --only, valid sections are: pd, sl, jira, ls`)
+}
@@ -3,6 +3,8 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	sdk "github.com/openshift-online/ocm-sdk-go"
@@ -13,6 +15,7 @@ import (
 	"github.com/openshift/osdctl/cmd/network"
 	"github.com/openshift/osdctl/pkg/osdCloud"
 	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/provider/gcp"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -21,17 +24,25 @@ import (
 type cpdOptions struct {
 	clusterID  string
 	awsProfile string
+
+	out io.Writer
+	// newGCPClient creates the client of the GCP checks, replaced in tests
+	newGCPClient func(ctx context.Context) (gcp.Client, error)
 }
 
 const (
 	cpdLongDescription = `
 Helps investigate OSD/ROSA cluster provisioning delays (CPD) or failures
 
-  This command only supports AWS at the moment and will:
+  This command supports AWS and GCP and will:
 	
   * Check the cluster's dnszone.hive.openshift.io custom resource
   * Check whether a known OCM error code and message has been shared with the customer already
-  * Check that the cluster's VPC and/or subnet route table(s) contain a route for 0.0.0.0/0 if it's BYOVPC
+  * AWS: check that the cluster's VPC and/or subnet route table(s) contain a route for 0.0.0.0/0 if it's BYOVPC
+  * GCP: check the cluster's project for firewall rules denying egress to the internet, subnets without Cloud NAT,
+    missing roles of the osd-ccs-admin service account and exhausted quotas
+
+  The GCP checks use the application default credentials, see "gcloud auth application-default login".
 `
 	cpdExample = `
   # Investigate a CPD for a cluster using an AWS profile named "rhcontrol"
@@ -41,7 +52,7 @@ Helps investigate OSD/ROSA cluster provisioning delays (CPD) or failures
 )
 
 func newCmdCpd() *cobra.Command {
-	ops := cpdOptions{}
+	ops := cpdOptions{out: os.Stdout, newGCPClient: gcp.NewGcpClient}
 	cpdCmd := &cobra.Command{
 		Use:               "cpd",
 		Short:             "Runs diagnostic for a Cluster Provisioning Delay (CPD)",
@@ -88,9 +99,8 @@ func (o *cpdOptions) run() error {
 	}
 
 	fmt.Println("Checking if cluster is GCP")
-	// If the cluster is GCP, give instructions on how to get console access
 	if cluster.CloudProvider().ID() == "gcp" {
-		return fmt.Errorf("this command doesn't support GCP yet. Needs manual investigation:\nocm backplane cloud console -b %s", o.clusterID)
+		return o.runGCP(context.Background(), cluster)
 	}
 
 	awsv2cfg, err := osdCloud.CreateAWSV2Config(ocmClient, cluster)
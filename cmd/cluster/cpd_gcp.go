@@ -0,0 +1,322 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/gcp"
+)
+
+const (
+	cpdCheckPass = "PASS"
+	cpdCheckWarn = "WARN"
+	cpdCheckFail = "FAIL"
+
+	// wifAuthenticationKind is the authentication kind of the clusters using Workload Identity Federation
+	wifAuthenticationKind = "WifConfig"
+)
+
+// ccsAdminRoles are the roles the osd-ccs-admin service account of CCS clusters needs to install the cluster
+var ccsAdminRoles = []string{
+	"roles/compute.admin",
+	"roles/dns.admin",
+	"roles/orgpolicy.policyViewer",
+	"roles/servicemanagement.admin",
+	"roles/serviceusage.serviceUsageAdmin",
+	"roles/storage.admin",
+	"roles/compute.loadBalancerAdmin",
+	"roles/viewer",
+	"roles/iam.roleAdmin",
+	"roles/iam.securityAdmin",
+	"roles/iam.serviceAccountKeyAdmin",
+	"roles/iam.serviceAccountAdmin",
+	"roles/iam.serviceAccountUser",
+}
+
+// gcpInstallQuotas are the regional quotas an installation needs available, on top of the current usage
+var gcpInstallQuotas = []struct {
+	metric   string
+	required float64
+}{
+	{metric: "CPUS", required: 28},
+	{metric: "SSD_TOTAL_GB", required: 896},
+	{metric: "IN_USE_ADDRESSES", required: 4},
+}
+
+// cpdCheck is the result of a provisioning check
+type cpdCheck struct {
+	Name    string
+	Status  string
+	Details string
+}
+
+// gcpCPD runs the provisioning checks of a GCP cluster against its project
+type gcpCPD struct {
+	cluster *cmv1.Cluster
+	client  gcp.Client
+}
+
+// runGCP checks the firewall rules, Cloud NAT, service account permissions and quotas of the cluster's project
+func (o *cpdOptions) runGCP(ctx context.Context, cluster *cmv1.Cluster) error {
+	if cluster.GCP().ProjectID() == "" {
+		return fmt.Errorf("could not get the GCP project of the cluster from OCM. Needs manual investigation:\nocm backplane cloud console -b %s", o.clusterID)
+	}
+
+	client, err := o.newGCPClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create the GCP client, make sure you're logged in with \"gcloud auth application-default login\": %w", err)
+	}
+	defer client.Close()
+
+	c := &gcpCPD{cluster: cluster, client: client}
+	checks := c.run(ctx)
+	printCPDChecks(o.out, checks)
+
+	for _, check := range checks {
+		if check.Status == cpdCheckFail {
+			return fmt.Errorf("the GCP project %s of cluster %s prevents the installation, see the failed checks above", cluster.GCP().ProjectID(), o.clusterID)
+		}
+	}
+	_, _ = fmt.Fprintln(o.out, "Next step: check the GCP resources manually, run ocm backplane cloud console")
+	return nil
+}
+
+func (c *gcpCPD) run(ctx context.Context) []cpdCheck {
+	var checks []cpdCheck
+	checks = append(checks, c.checkFirewalls(ctx)...)
+	checks = append(checks, c.checkCloudNAT(ctx)...)
+	checks = append(checks, c.checkServiceAccount(ctx))
+	checks = append(checks, c.checkQuotas(ctx)...)
+	return checks
+}
+
+// networkProject returns the project of the cluster's VPC, the host project of a shared VPC
+func (c *gcpCPD) networkProject() string {
+	if project := c.cluster.GCPNetwork().VPCProjectID(); project != "" {
+		return project
+	}
+	return c.cluster.GCP().ProjectID()
+}
+
+// networkName returns the name of the cluster's VPC, the one created by the installer unless it's BYOVPC
+func (c *gcpCPD) networkName() string {
+	if name := c.cluster.GCPNetwork().VPCName(); name != "" {
+		return name
+	}
+	return c.cluster.InfraID() + "-network"
+}
+
+func (c *gcpCPD) isBYOVPC() bool {
+	return c.cluster.GCPNetwork().VPCName() != ""
+}
+
+// checkFirewalls looks for egress deny rules blocking the access of the nodes to the internet
+func (c *gcpCPD) checkFirewalls(ctx context.Context) []cpdCheck {
+	name := "Firewall rules"
+	firewalls, err := c.client.ListFirewalls(ctx, c.networkProject())
+	if err != nil {
+		return []cpdCheck{{Name: name, Status: cpdCheckWarn, Details: fmt.Sprintf("failed to list the firewall rules: %v", err)}}
+	}
+
+	var denies, allows []*computepb.Firewall
+	for _, firewall := range firewalls {
+		if path.Base(firewall.GetNetwork()) != c.networkName() || firewall.GetDisabled() || firewall.GetDirection() != "EGRESS" {
+			continue
+		}
+		if !slices.Contains(firewall.GetDestinationRanges(), "0.0.0.0/0") {
+			continue
+		}
+		if len(firewall.GetDenied()) > 0 && coversHTTPS(firewall.GetDenied()) {
+			denies = append(denies, firewall)
+		}
+		if len(firewall.GetAllowed()) > 0 && coversHTTPS(allowedAsDenied(firewall.GetAllowed())) {
+			allows = append(allows, firewall)
+		}
+	}
+
+	var checks []cpdCheck
+	for _, deny := range denies {
+		// The rule with the lowest priority number wins, deny wins over allow at the same priority
+		overridden := slices.ContainsFunc(allows, func(allow *computepb.Firewall) bool {
+			return allow.GetPriority() < deny.GetPriority() && len(allow.GetTargetTags()) == 0 && len(allow.GetTargetServiceAccounts()) == 0
+		})
+		if overridden {
+			continue
+		}
+		checks = append(checks, cpdCheck{Name: name, Status: cpdCheckFail, Details: fmt.Sprintf(
+			"rule %s (priority %d) denies egress to the internet on network %s", deny.GetName(), deny.GetPriority(), c.networkName())})
+	}
+	if len(checks) == 0 {
+		checks = append(checks, cpdCheck{Name: name, Status: cpdCheckPass, Details: fmt.Sprintf("no rule denies egress to the internet on network %s", c.networkName())})
+	}
+	return checks
+}
+
+// coversHTTPS returns true if the rules match tcp/443, which the nodes need to reach the internet
+func coversHTTPS(rules []*computepb.Denied) bool {
+	for _, rule := range rules {
+		if protocol := rule.GetIPProtocol(); protocol != "all" && protocol != "tcp" {
+			continue
+		}
+		if len(rule.GetPorts()) == 0 {
+			return true
+		}
+		for _, ports := range rule.GetPorts() {
+			if portInRange(443, ports) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func allowedAsDenied(allowed []*computepb.Allowed) []*computepb.Denied {
+	rules := make([]*computepb.Denied, 0, len(allowed))
+	for _, allow := range allowed {
+		rules = append(rules, &computepb.Denied{IPProtocol: allow.IPProtocol, Ports: allow.GetPorts()})
+	}
+	return rules
+}
+
+// portInRange returns true if the port is ports, a port or a range of ports like 1-1024
+func portInRange(port int, ports string) bool {
+	var low, high int
+	if n, _ := fmt.Sscanf(ports, "%d-%d", &low, &high); n == 2 {
+		return low <= port && port <= high
+	}
+	return ports == fmt.Sprint(port)
+}
+
+// checkCloudNAT verifies that the subnets of the nodes are served by a Cloud NAT of the cluster's region
+func (c *gcpCPD) checkCloudNAT(ctx context.Context) []cpdCheck {
+	name := "Cloud NAT"
+	routers, err := c.client.ListRouters(ctx, c.networkProject(), c.cluster.Region().ID())
+	if err != nil {
+		return []cpdCheck{{Name: name, Status: cpdCheckWarn, Details: fmt.Sprintf("failed to list the Cloud Routers: %v", err)}}
+	}
+
+	var nats []*computepb.RouterNat
+	for _, router := range routers {
+		if path.Base(router.GetNetwork()) == c.networkName() {
+			nats = append(nats, router.GetNats()...)
+		}
+	}
+
+	if !c.isBYOVPC() {
+		if len(nats) == 0 {
+			return []cpdCheck{{Name: name, Status: cpdCheckWarn, Details: fmt.Sprintf("no Cloud NAT on network %s yet, the installer creates it", c.networkName())}}
+		}
+		return []cpdCheck{{Name: name, Status: cpdCheckPass, Details: fmt.Sprintf("%d Cloud NAT(s) on network %s", len(nats), c.networkName())}}
+	}
+
+	var checks []cpdCheck
+	for _, subnet := range []string{c.cluster.GCPNetwork().ControlPlaneSubnet(), c.cluster.GCPNetwork().ComputeSubnet()} {
+		if subnet == "" {
+			continue
+		}
+		if slices.ContainsFunc(nats, func(nat *computepb.RouterNat) bool { return natCoversSubnet(nat, subnet) }) {
+			checks = append(checks, cpdCheck{Name: name, Status: cpdCheckPass, Details: fmt.Sprintf("subnet %s is served by a Cloud NAT", subnet)})
+			continue
+		}
+		checks = append(checks, cpdCheck{Name: name, Status: cpdCheckFail, Details: fmt.Sprintf(
+			"subnet %s isn't served by a Cloud NAT of network %s in %s, its nodes can't reach the internet", subnet, c.networkName(), c.cluster.Region().ID())})
+	}
+	return checks
+}
+
+func natCoversSubnet(nat *computepb.RouterNat, subnet string) bool {
+	switch nat.GetSourceSubnetworkIpRangesToNat() {
+	case "ALL_SUBNETWORKS_ALL_IP_RANGES", "ALL_SUBNETWORKS_ALL_PRIMARY_IP_RANGES":
+		return true
+	}
+	return slices.ContainsFunc(nat.GetSubnetworks(), func(s *computepb.RouterNatSubnetworkToNat) bool {
+		return path.Base(s.GetName()) == subnet
+	})
+}
+
+// checkServiceAccount verifies the roles of the osd-ccs-admin service account in the project of CCS clusters
+func (c *gcpCPD) checkServiceAccount(ctx context.Context) cpdCheck {
+	name := "Service account"
+	switch {
+	case !c.cluster.CCS().Enabled():
+		return cpdCheck{Name: name, Status: cpdCheckPass, Details: "the project is managed by Red Hat"}
+	case c.cluster.GCP().Authentication().Kind() == wifAuthenticationKind:
+		return cpdCheck{Name: name, Status: cpdCheckWarn, Details: "the cluster uses Workload Identity Federation, verify it with \"ocm gcp verify wif-config\""}
+	}
+
+	email := c.cluster.GCP().ClientEmail()
+	if email == "" {
+		email = fmt.Sprintf("osd-ccs-admin@%s.iam.gserviceaccount.com", c.cluster.GCP().ProjectID())
+	}
+	policy, err := c.client.GetProjectIamPolicy(ctx, c.cluster.GCP().ProjectID())
+	if err != nil {
+		return cpdCheck{Name: name, Status: cpdCheckWarn, Details: fmt.Sprintf("failed to get the IAM policy of the project: %v", err)}
+	}
+
+	member := "serviceAccount:" + email
+	var roles []string
+	for _, binding := range policy.Bindings {
+		if slices.Contains(binding.Members, member) {
+			roles = append(roles, binding.Role)
+		}
+	}
+	if slices.Contains(roles, "roles/owner") {
+		return cpdCheck{Name: name, Status: cpdCheckPass, Details: fmt.Sprintf("%s is owner of the project", email)}
+	}
+
+	var missing []string
+	for _, role := range ccsAdminRoles {
+		if !slices.Contains(roles, role) {
+			missing = append(missing, role)
+		}
+	}
+	if len(missing) > 0 {
+		return cpdCheck{Name: name, Status: cpdCheckFail, Details: fmt.Sprintf("%s is missing the roles %s", email, strings.Join(missing, ", "))}
+	}
+	return cpdCheck{Name: name, Status: cpdCheckPass, Details: fmt.Sprintf("%s has the roles the installation needs", email)}
+}
+
+// checkQuotas compares the headroom of the regional quotas of the project, their limit minus their usage, to the
+// resources an installation needs
+func (c *gcpCPD) checkQuotas(ctx context.Context) []cpdCheck {
+	name := "Quotas"
+	region, err := c.client.GetRegion(ctx, c.cluster.GCP().ProjectID(), c.cluster.Region().ID())
+	if err != nil {
+		return []cpdCheck{{Name: name, Status: cpdCheckWarn, Details: fmt.Sprintf("failed to get the quotas of region %s: %v", c.cluster.Region().ID(), err)}}
+	}
+
+	var checks []cpdCheck
+	for _, quota := range gcpInstallQuotas {
+		i := slices.IndexFunc(region.GetQuotas(), func(q *computepb.Quota) bool { return q.GetMetric() == quota.metric })
+		if i < 0 {
+			continue
+		}
+		q := region.GetQuotas()[i]
+		details := fmt.Sprintf("%s: %g of %g used", quota.metric, q.GetUsage(), q.GetLimit())
+		switch {
+		case q.GetUsage() >= q.GetLimit():
+			checks = append(checks, cpdCheck{Name: name, Status: cpdCheckFail, Details: details + ", the quota is exhausted"})
+		case q.GetLimit()-q.GetUsage() < quota.required:
+			checks = append(checks, cpdCheck{Name: name, Status: cpdCheckWarn, Details: fmt.Sprintf("%s, %g left while an installation needs %g", details, q.GetLimit()-q.GetUsage(), quota.required)})
+		default:
+			checks = append(checks, cpdCheck{Name: name, Status: cpdCheckPass, Details: details})
+		}
+	}
+	return checks
+}
+
+func printCPDChecks(w io.Writer, checks []cpdCheck) {
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, check := range checks {
+		table.AddRow([]string{check.Name, check.Status, check.Details})
+	}
+	_ = table.Flush()
+}
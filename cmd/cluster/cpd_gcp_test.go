@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/provider/gcp"
+	"github.com/openshift/osdctl/pkg/provider/gcp/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"k8s.io/utils/ptr"
+)
+
+const testNetworkURL = "https://www.googleapis.com/compute/v1/projects/host-project/global/networks/customer-vpc"
+
+func newGCPCPDCluster(t *testing.T, ccs bool) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID("abc123").InfraID("abc-x7k2p").
+		Region(cmv1.NewCloudRegion().ID("us-east1")).
+		CCS(cmv1.NewCCS().Enabled(ccs)).
+		GCP(cmv1.NewGCP().ProjectID("customer-project")).
+		GCPNetwork(cmv1.NewGCPNetwork().VPCName("customer-vpc").VPCProjectID("host-project").
+			ControlPlaneSubnet("master-subnet").ComputeSubnet("worker-subnet")).
+		Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func egressFirewall(name string, priority int32, deny bool, ports ...string) *computepb.Firewall {
+	firewall := &computepb.Firewall{
+		Name:              ptr.To(name),
+		Network:           ptr.To(testNetworkURL),
+		Direction:         ptr.To("EGRESS"),
+		Priority:          ptr.To[int32](priority),
+		DestinationRanges: []string{"0.0.0.0/0"},
+	}
+	if deny {
+		firewall.Denied = []*computepb.Denied{{IPProtocol: ptr.To("tcp"), Ports: ports}}
+	} else {
+		firewall.Allowed = []*computepb.Allowed{{IPProtocol: ptr.To("tcp"), Ports: ports}}
+	}
+	return firewall
+}
+
+func quota(metric string, usage, limit float64) *computepb.Quota {
+	return &computepb.Quota{Metric: ptr.To(metric), Usage: ptr.To(usage), Limit: ptr.To(limit)}
+}
+
+func TestGCPCPDChecks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	client.EXPECT().ListFirewalls(gomock.Any(), "host-project").Return([]*computepb.Firewall{
+		egressFirewall("deny-all-egress", 1000, true),
+		egressFirewall("allow-https", 900, false, "443"),
+		egressFirewall("deny-web", 500, true, "80-443"),
+		egressFirewall("deny-ssh", 100, true, "22"),
+	}, nil)
+	client.EXPECT().ListRouters(gomock.Any(), "host-project", "us-east1").Return([]*computepb.Router{{
+		Network: ptr.To(testNetworkURL),
+		Nats: []*computepb.RouterNat{{
+			SourceSubnetworkIpRangesToNat: ptr.To("LIST_OF_SUBNETWORKS"),
+			Subnetworks:                   []*computepb.RouterNatSubnetworkToNat{{Name: ptr.To("projects/host-project/regions/us-east1/subnetworks/master-subnet")}},
+		}},
+	}}, nil)
+	client.EXPECT().GetProjectIamPolicy(gomock.Any(), "customer-project").Return(&cloudresourcemanager.Policy{Bindings: []*cloudresourcemanager.Binding{
+		{Role: "roles/compute.admin", Members: []string{"serviceAccount:osd-ccs-admin@customer-project.iam.gserviceaccount.com"}},
+	}}, nil)
+	client.EXPECT().GetRegion(gomock.Any(), "customer-project", "us-east1").Return(&computepb.Region{Quotas: []*computepb.Quota{
+		quota("CPUS", 24, 24),
+		quota("SSD_TOTAL_GB", 500, 1000),
+		quota("IN_USE_ADDRESSES", 1, 8),
+	}}, nil)
+	client.EXPECT().Close()
+
+	out := &bytes.Buffer{}
+	opts := &cpdOptions{clusterID: "abc123", out: out, newGCPClient: func(context.Context) (gcp.Client, error) { return client, nil }}
+	err := opts.runGCP(context.Background(), newGCPCPDCluster(t, true))
+	require.ErrorContains(t, err, "the GCP project customer-project of cluster abc123 prevents the installation")
+
+	output := out.String()
+	assert.Contains(t, output, "rule deny-web (priority 500) denies egress to the internet on network customer-vpc")
+	assert.NotContains(t, output, "deny-all-egress")
+	assert.NotContains(t, output, "deny-ssh")
+	assert.Contains(t, output, "subnet master-subnet is served by a Cloud NAT")
+	assert.Contains(t, output, "subnet worker-subnet isn't served by a Cloud NAT of network customer-vpc in us-east1")
+	assert.Contains(t, output, "osd-ccs-admin@customer-project.iam.gserviceaccount.com is missing the roles roles/dns.admin")
+	assert.Contains(t, output, "CPUS: 24 of 24 used, the quota is exhausted")
+	assert.Contains(t, output, "SSD_TOTAL_GB: 500 of 1000 used, 500 left while an installation needs 896")
+	assert.Contains(t, output, "IN_USE_ADDRESSES: 1 of 8 used")
+}
+
+func TestGCPCPDNonCCS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	client.EXPECT().ListFirewalls(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().ListRouters(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("permission denied"))
+	client.EXPECT().GetRegion(gomock.Any(), gomock.Any(), gomock.Any()).Return(&computepb.Region{}, nil)
+
+	checks := (&gcpCPD{cluster: newGCPCPDCluster(t, false), client: client}).run(context.Background())
+	assert.Equal(t, []cpdCheck{
+		{Name: "Firewall rules", Status: cpdCheckPass, Details: "no rule denies egress to the internet on network customer-vpc"},
+		{Name: "Cloud NAT", Status: cpdCheckWarn, Details: "failed to list the Cloud Routers: permission denied"},
+		{Name: "Service account", Status: cpdCheckPass, Details: "the project is managed by Red Hat"},
+	}, checks)
+}
+
+func TestPortInRange(t *testing.T) {
+	assert.True(t, portInRange(443, "443"))
+	assert.True(t, portInRange(443, "1-1024"))
+	assert.False(t, portInRange(443, "80"))
+	assert.False(t, portInRange(443, "8000-9000"))
+}
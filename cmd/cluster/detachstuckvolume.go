@@ -11,6 +11,7 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/danger"
 	"github.com/openshift/osdctl/pkg/osdCloud"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -50,7 +51,7 @@ func newCmdDetachStuckVolume() *cobra.Command {
 	_ = detachstuckvolumeCmd.MarkFlagRequired("cluster-id")
 	_ = detachstuckvolumeCmd.MarkFlagRequired("reason")
 
-	return detachstuckvolumeCmd
+	return danger.Annotate(detachstuckvolumeCmd, danger.High)
 }
 
 func (o *detachStuckVolumeOptions) detachVolume() error {
@@ -95,6 +96,9 @@ func (o *detachStuckVolumeOptions) detachVolume() error {
 	}
 
 	log.Printf("The volume id are %v\n", detachStuckVolumeInput.VolumeId)
+	if ok, err := danger.Confirm(danger.High, o.cluster); err != nil || !ok {
+		return err
+	}
 
 	// Aws fuction to detach volume of no running state pod's using it's volume id
 	cfg, err := osdCloud.CreateAWSV2Config(connection, o.cluster)
@@ -0,0 +1,443 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	dnsRecordStatusOK      = "OK"
+	dnsRecordStatusMissing = "MISSING"
+	dnsRecordStatusDrift   = "DRIFT"
+
+	dnsZonePublic  = "public"
+	dnsZonePrivate = "private"
+
+	// routerServiceName is the value of the kubernetes.io/service-name tag of the default ingress controller load balancer
+	routerServiceName = "openshift-ingress/router-default"
+	serviceNameTag    = "kubernetes.io/service-name"
+
+	// elbTagsBatchSize is the maximum number of load balancers DescribeTags accepts at once
+	elbTagsBatchSize = 20
+)
+
+// dnsRecordCheck is the result of validating a single DNS record of a cluster
+type dnsRecordCheck struct {
+	Zone       string `json:"zone"`
+	ZoneType   string `json:"zoneType"`
+	Record     string `json:"record"`
+	Expected   string `json:"expected"`
+	Actual     string `json:"actual"`
+	Status     string `json:"status"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// clusterLoadBalancers are the DNS names of the load balancers the cluster's records should point to
+type clusterLoadBalancers struct {
+	apiExternal string
+	apiInternal string
+	ingress     string
+}
+
+// clusterHostedZone is a Route53 hosted zone of the cluster along with its records
+type clusterHostedZone struct {
+	id      string
+	name    string
+	private bool
+	records []route53types.ResourceRecordSet
+}
+
+// dnsVerifyOptions defines the struct for running the dns verify command
+type dnsVerifyOptions struct {
+	clusterID  string
+	awsProfile string
+	output     string
+
+	genericclioptions.IOStreams
+}
+
+func newCmdDNS(streams genericclioptions.IOStreams) *cobra.Command {
+	dnsCmd := &cobra.Command{
+		Use:               "dns",
+		Short:             "DNS related utilities for a cluster",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	dnsCmd.AddCommand(newCmdDNSVerify(streams))
+	return dnsCmd
+}
+
+func newCmdDNSVerify(streams genericclioptions.IOStreams) *cobra.Command {
+	opts := &dnsVerifyOptions{IOStreams: streams}
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the API and ingress Route53 records of a cluster against its load balancers",
+		Long: `Verify the API and ingress Route53 records of a cluster against its load balancers
+
+  Checks the public and private Route53 hosted zones of a classic AWS cluster for the api, api-int and *.apps
+  records and compares their targets with the DNS names of the cluster's API and default router load balancers.
+  Records which are missing or point to a different load balancer (drift) are reported together with a suggested
+  corrective action.
+
+  HCP clusters are not supported, use "osdctl cluster verify-dns" for them instead.`,
+		Example: `  # Verify the DNS records of a cluster
+  osdctl cluster dns verify --cluster-id ${CLUSTER_ID}
+
+  # Verify the DNS records with JSON output
+  osdctl cluster dns verify --cluster-id ${CLUSTER_ID} --output json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			return opts.run()
+		},
+	}
+	verifyCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID (internal or external)")
+	verifyCmd.Flags().StringVarP(&opts.awsProfile, "profile", "p", "", "AWS profile used to access the cluster account")
+	verifyCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: 'table' or 'json'")
+	_ = verifyCmd.MarkFlagRequired("cluster-id")
+
+	return verifyCmd
+}
+
+func (o *dnsVerifyOptions) validate() error {
+	if err := utils.IsValidClusterKey(o.clusterID); err != nil {
+		return err
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format: %s (must be 'table' or 'json')", o.output)
+	}
+	return nil
+}
+
+func (o *dnsVerifyOptions) run() error {
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	cluster, err := utils.GetCluster(conn, o.clusterID)
+	conn.Close()
+	if err != nil {
+		return err
+	}
+
+	if cluster.Hypershift().Enabled() {
+		return fmt.Errorf("cluster %s is an HCP cluster, use 'osdctl cluster verify-dns' instead", cluster.ID())
+	}
+	if cluster.CloudProvider().ID() != "aws" {
+		return fmt.Errorf("cloud provider not supported: %s, only AWS is supported", cluster.CloudProvider().ID())
+	}
+
+	domain, err := clusterDomainFromAPIURL(cluster.API().URL())
+	if err != nil {
+		return err
+	}
+
+	awsClient, err := osdCloud.GenerateAWSClientForCluster(o.awsProfile, cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	zones, err := listClusterHostedZones(awsClient, domain)
+	if err != nil {
+		return fmt.Errorf("failed to list hosted zones: %w", err)
+	}
+	lbs, err := listClusterLoadBalancers(awsClient, cluster.InfraID())
+	if err != nil {
+		return fmt.Errorf("failed to list load balancers: %w", err)
+	}
+
+	checks := verifyClusterDNSRecords(domain, cluster.API().Listening() == cmv1.ListeningMethodInternal, zones, lbs)
+
+	if o.output == "json" {
+		return printDNSChecksJSON(o.Out, checks)
+	}
+	return printDNSChecksTable(o.Out, checks)
+}
+
+// clusterDomainFromAPIURL returns the cluster domain from its API URL,
+// e.g. https://api.mycluster.abcd.p1.openshiftapps.com:6443 -> mycluster.abcd.p1.openshiftapps.com
+func clusterDomainFromAPIURL(apiURL string) (string, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse API URL %q: %w", apiURL, err)
+	}
+	host := u.Hostname()
+	if !strings.HasPrefix(host, "api.") {
+		return "", fmt.Errorf("unexpected API URL %q, expected it to start with api.", apiURL)
+	}
+	return strings.TrimPrefix(host, "api."), nil
+}
+
+// normalizeDNSName lowercases a DNS name and strips the trailing dot, Route53's dualstack prefix
+// and its escaped wildcard so that record names and alias targets can be compared with load balancer DNS names
+func normalizeDNSName(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	name = strings.TrimPrefix(name, "dualstack.")
+	return strings.ReplaceAll(name, `\052`, "*")
+}
+
+// listClusterHostedZones returns the public and private hosted zones of the cluster domain with their records
+func listClusterHostedZones(client aws.Client, domain string) ([]clusterHostedZone, error) {
+	var zones []clusterHostedZone
+	var marker *string
+	for {
+		out, err := client.ListHostedZones(&route53.ListHostedZonesInput{Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		for _, hz := range out.HostedZones {
+			if hz.Name == nil || normalizeDNSName(*hz.Name) != domain {
+				continue
+			}
+			zone := clusterHostedZone{id: *hz.Id, name: normalizeDNSName(*hz.Name)}
+			if hz.Config != nil {
+				zone.private = hz.Config.PrivateZone
+			}
+			zones = append(zones, zone)
+		}
+		if !out.IsTruncated || out.NextMarker == nil {
+			break
+		}
+		marker = out.NextMarker
+	}
+
+	for i := range zones {
+		input := &route53.ListResourceRecordSetsInput{HostedZoneId: &zones[i].id}
+		for {
+			out, err := client.ListResourceRecordSets(input)
+			if err != nil {
+				return nil, err
+			}
+			zones[i].records = append(zones[i].records, out.ResourceRecordSets...)
+			if !out.IsTruncated {
+				break
+			}
+			input.StartRecordName = out.NextRecordName
+			input.StartRecordType = out.NextRecordType
+			input.StartRecordIdentifier = out.NextRecordIdentifier
+		}
+	}
+
+	return zones, nil
+}
+
+// listClusterLoadBalancers returns the API and default router load balancers owned by the cluster
+func listClusterLoadBalancers(client aws.Client, infraID string) (clusterLoadBalancers, error) {
+	var lbs clusterLoadBalancers
+	clusterTag := "kubernetes.io/cluster/" + infraID
+
+	v2, err := client.DescribeV2LoadBalancers(&elasticloadbalancingv2.DescribeLoadBalancersInput{})
+	if err != nil {
+		return lbs, err
+	}
+	dnsNameByArn := map[string]string{}
+	var arns []string
+	for _, lb := range v2.LoadBalancers {
+		if lb.LoadBalancerArn == nil || lb.DNSName == nil {
+			continue
+		}
+		dnsNameByArn[*lb.LoadBalancerArn] = normalizeDNSName(*lb.DNSName)
+		arns = append(arns, *lb.LoadBalancerArn)
+		// The API load balancers are named after the infra ID
+		switch awsSdk.ToString(lb.LoadBalancerName) {
+		case infraID + "-ext":
+			lbs.apiExternal = normalizeDNSName(*lb.DNSName)
+		case infraID + "-int":
+			lbs.apiInternal = normalizeDNSName(*lb.DNSName)
+		}
+	}
+	for i := 0; i < len(arns); i += elbTagsBatchSize {
+		tags, err := client.DescribeV2Tags(&elasticloadbalancingv2.DescribeTagsInput{ResourceArns: arns[i:min(i+elbTagsBatchSize, len(arns))]})
+		if err != nil {
+			return lbs, err
+		}
+		for _, desc := range tags.TagDescriptions {
+			tagMap := map[string]string{}
+			for _, tag := range desc.Tags {
+				tagMap[awsSdk.ToString(tag.Key)] = awsSdk.ToString(tag.Value)
+			}
+			if _, owned := tagMap[clusterTag]; owned && tagMap[serviceNameTag] == routerServiceName {
+				lbs.ingress = dnsNameByArn[awsSdk.ToString(desc.ResourceArn)]
+			}
+		}
+	}
+	if lbs.ingress != "" {
+		return lbs, nil
+	}
+
+	// Older clusters expose the default router through a classic load balancer
+	v1, err := client.DescribeLoadBalancers(&elasticloadbalancing.DescribeLoadBalancersInput{})
+	if err != nil {
+		return lbs, err
+	}
+	dnsNameByName := map[string]string{}
+	var names []string
+	for _, lb := range v1.LoadBalancerDescriptions {
+		if lb.LoadBalancerName == nil || lb.DNSName == nil {
+			continue
+		}
+		dnsNameByName[*lb.LoadBalancerName] = normalizeDNSName(*lb.DNSName)
+		names = append(names, *lb.LoadBalancerName)
+	}
+	for i := 0; i < len(names); i += elbTagsBatchSize {
+		tags, err := client.DescribeTags(&elasticloadbalancing.DescribeTagsInput{LoadBalancerNames: names[i:min(i+elbTagsBatchSize, len(names))]})
+		if err != nil {
+			return lbs, err
+		}
+		for _, desc := range tags.TagDescriptions {
+			tagMap := map[string]string{}
+			for _, tag := range desc.Tags {
+				tagMap[awsSdk.ToString(tag.Key)] = awsSdk.ToString(tag.Value)
+			}
+			if _, owned := tagMap[clusterTag]; owned && tagMap[serviceNameTag] == routerServiceName {
+				lbs.ingress = dnsNameByName[awsSdk.ToString(desc.LoadBalancerName)]
+			}
+		}
+	}
+
+	return lbs, nil
+}
+
+// verifyClusterDNSRecords compares the api, api-int and *.apps records of the cluster hosted zones with the
+// load balancers they are expected to point to
+func verifyClusterDNSRecords(domain string, privateAPI bool, zones []clusterHostedZone, lbs clusterLoadBalancers) []dnsRecordCheck {
+	var checks []dnsRecordCheck
+
+	hasZone := map[bool]bool{}
+	for _, zone := range zones {
+		hasZone[zone.private] = true
+	}
+	if !hasZone[false] {
+		checks = append(checks, dnsRecordCheck{
+			Zone: domain, ZoneType: dnsZonePublic, Record: "-", Expected: "-", Actual: "-", Status: dnsRecordStatusMissing,
+			Suggestion: fmt.Sprintf("no public hosted zone found for %s, check the cluster's DNS configuration and the dns operator", domain),
+		})
+	}
+	if !hasZone[true] {
+		checks = append(checks, dnsRecordCheck{
+			Zone: domain, ZoneType: dnsZonePrivate, Record: "-", Expected: "-", Actual: "-", Status: dnsRecordStatusMissing,
+			Suggestion: fmt.Sprintf("no private hosted zone found for %s, check the cluster's DNS configuration and the dns operator", domain),
+		})
+	}
+
+	for _, zone := range zones {
+		expected := map[string]string{
+			"*.apps." + domain: lbs.ingress,
+		}
+		zoneType := dnsZonePublic
+		if zone.private {
+			zoneType = dnsZonePrivate
+			expected["api."+domain] = lbs.apiInternal
+			expected["api-int."+domain] = lbs.apiInternal
+		} else if !privateAPI {
+			expected["api."+domain] = lbs.apiExternal
+		}
+
+		actual := map[string]string{}
+		for _, rrs := range zone.records {
+			if rrs.Name == nil {
+				continue
+			}
+			actual[normalizeDNSName(*rrs.Name)] = recordTarget(rrs)
+		}
+
+		names := make([]string, 0, len(expected))
+		for name := range expected {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			check := dnsRecordCheck{Zone: zone.name, ZoneType: zoneType, Record: name, Expected: valueOrDash(expected[name]), Actual: "-"}
+			target, found := actual[name]
+			switch {
+			case !found:
+				check.Status = dnsRecordStatusMissing
+				if expected[name] != "" {
+					check.Suggestion = fmt.Sprintf("create an alias A record %s in hosted zone %s pointing to %s", name, zone.id, expected[name])
+				} else {
+					check.Suggestion = fmt.Sprintf("create record %s in hosted zone %s once its load balancer exists", name, zone.id)
+				}
+			case expected[name] == "":
+				check.Actual = valueOrDash(target)
+				check.Status = dnsRecordStatusDrift
+				check.Suggestion = "no matching load balancer found for this record, check the corresponding service and the ingress/kube-apiserver operators"
+			case target != expected[name]:
+				check.Actual = valueOrDash(target)
+				check.Status = dnsRecordStatusDrift
+				check.Suggestion = fmt.Sprintf("update record %s in hosted zone %s to point to %s", name, zone.id, expected[name])
+			default:
+				check.Actual = target
+				check.Status = dnsRecordStatusOK
+			}
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
+
+// recordTarget returns the alias target or first value of a record
+func recordTarget(rrs route53types.ResourceRecordSet) string {
+	if rrs.AliasTarget != nil && rrs.AliasTarget.DNSName != nil {
+		return normalizeDNSName(*rrs.AliasTarget.DNSName)
+	}
+	if len(rrs.ResourceRecords) > 0 && rrs.ResourceRecords[0].Value != nil {
+		return normalizeDNSName(*rrs.ResourceRecords[0].Value)
+	}
+	return ""
+}
+
+func printDNSChecksTable(w io.Writer, checks []dnsRecordCheck) error {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"ZONE", "TYPE", "RECORD", "STATUS", "EXPECTED", "ACTUAL"})
+	var suggestions []string
+	for _, c := range checks {
+		p.AddRow([]string{c.Zone, c.ZoneType, c.Record, c.Status, c.Expected, c.Actual})
+		if c.Suggestion != "" {
+			suggestions = append(suggestions, fmt.Sprintf("[%s %s] %s", c.ZoneType, c.Record, c.Suggestion))
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Fprintln(w, "\nAll DNS records point to the expected load balancers")
+		return nil
+	}
+	fmt.Fprintln(w, "\nSuggested actions:")
+	for _, s := range suggestions {
+		fmt.Fprintf(w, "  - %s\n", s)
+	}
+	return nil
+}
+
+func printDNSChecksJSON(w io.Writer, checks []dnsRecordCheck) error {
+	out, err := json.MarshalIndent(checks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling DNS checks: %w", err)
+	}
+	fmt.Fprintln(w, string(out))
+	return nil
+}
@@ -0,0 +1,151 @@
+package cluster
+
+import (
+	"bytes"
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testDNSDomain    = "mycluster.abcd.p1.openshiftapps.com"
+	testDNSInfraID   = "mycluster-x7k2p"
+	testAPIExtLB     = "mycluster-x7k2p-ext-123.elb.us-east-1.amazonaws.com"
+	testAPIIntLB     = "mycluster-x7k2p-int-456.elb.us-east-1.amazonaws.com"
+	testIngressLB    = "a1b2c3-789.us-east-1.elb.amazonaws.com"
+	testStaleLBAlias = "old-router-000.us-east-1.elb.amazonaws.com"
+)
+
+func aliasRecord(name, target string) route53types.ResourceRecordSet {
+	return route53types.ResourceRecordSet{
+		Name:        awsSdk.String(name),
+		Type:        route53types.RRTypeA,
+		AliasTarget: &route53types.AliasTarget{DNSName: awsSdk.String(target)},
+	}
+}
+
+func TestClusterDomainFromAPIURL(t *testing.T) {
+	domain, err := clusterDomainFromAPIURL("https://api." + testDNSDomain + ":6443")
+	require.NoError(t, err)
+	assert.Equal(t, testDNSDomain, domain)
+
+	_, err = clusterDomainFromAPIURL("https://console." + testDNSDomain)
+	assert.Error(t, err)
+}
+
+func TestNormalizeDNSName(t *testing.T) {
+	assert.Equal(t, "*.apps."+testDNSDomain, normalizeDNSName(`\052.apps.`+testDNSDomain+"."))
+	assert.Equal(t, testIngressLB, normalizeDNSName("dualstack."+testIngressLB+"."))
+	assert.Equal(t, testAPIExtLB, normalizeDNSName("MyCluster-x7k2p-ext-123.elb.us-east-1.amazonaws.com"))
+}
+
+func TestVerifyClusterDNSRecords(t *testing.T) {
+	lbs := clusterLoadBalancers{apiExternal: testAPIExtLB, apiInternal: testAPIIntLB, ingress: testIngressLB}
+	publicZone := clusterHostedZone{id: "/hostedzone/PUBLIC", name: testDNSDomain, records: []route53types.ResourceRecordSet{
+		aliasRecord("api."+testDNSDomain+".", "dualstack."+testAPIExtLB+"."),
+		aliasRecord(`\052.apps.`+testDNSDomain+".", testStaleLBAlias+"."),
+	}}
+	privateZone := clusterHostedZone{id: "/hostedzone/PRIVATE", name: testDNSDomain, private: true, records: []route53types.ResourceRecordSet{
+		aliasRecord("api."+testDNSDomain+".", testAPIIntLB+"."),
+		aliasRecord(`\052.apps.`+testDNSDomain+".", testIngressLB+"."),
+	}}
+
+	checks := verifyClusterDNSRecords(testDNSDomain, false, []clusterHostedZone{publicZone, privateZone}, lbs)
+
+	statuses := map[string]string{}
+	for _, c := range checks {
+		statuses[c.ZoneType+" "+c.Record] = c.Status
+	}
+	assert.Equal(t, map[string]string{
+		"public api." + testDNSDomain:      dnsRecordStatusOK,
+		"public *.apps." + testDNSDomain:   dnsRecordStatusDrift,
+		"private api." + testDNSDomain:     dnsRecordStatusOK,
+		"private api-int." + testDNSDomain: dnsRecordStatusMissing,
+		"private *.apps." + testDNSDomain:  dnsRecordStatusOK,
+	}, statuses)
+
+	// Private API clusters don't need a public api record, but a missing private zone is reported
+	checks = verifyClusterDNSRecords(testDNSDomain, true, []clusterHostedZone{{id: "/hostedzone/PUBLIC", name: testDNSDomain}}, lbs)
+	require.Len(t, checks, 2)
+	assert.Equal(t, dnsZonePrivate, checks[0].ZoneType)
+	assert.Equal(t, dnsRecordStatusMissing, checks[0].Status)
+	assert.Equal(t, "*.apps."+testDNSDomain, checks[1].Record)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printDNSChecksTable(out, checks))
+	assert.Contains(t, out.String(), "Suggested actions:")
+	assert.Contains(t, out.String(), "create an alias A record *.apps."+testDNSDomain+" in hosted zone /hostedzone/PUBLIC pointing to "+testIngressLB)
+}
+
+func TestListClusterHostedZones(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(mockCtrl)
+
+	mockClient.EXPECT().ListHostedZones(gomock.Any()).Return(&route53.ListHostedZonesOutput{
+		HostedZones: []route53types.HostedZone{
+			{Id: awsSdk.String("/hostedzone/OTHER"), Name: awsSdk.String("other.abcd.p1.openshiftapps.com.")},
+			{Id: awsSdk.String("/hostedzone/PRIVATE"), Name: awsSdk.String(testDNSDomain + "."), Config: &route53types.HostedZoneConfig{PrivateZone: true}},
+		},
+	}, nil)
+	first := mockClient.EXPECT().ListResourceRecordSets(gomock.Any()).Return(&route53.ListResourceRecordSetsOutput{
+		ResourceRecordSets: []route53types.ResourceRecordSet{aliasRecord("api."+testDNSDomain+".", testAPIIntLB)},
+		IsTruncated:        true,
+		NextRecordName:     awsSdk.String("api-int." + testDNSDomain),
+	}, nil)
+	mockClient.EXPECT().ListResourceRecordSets(gomock.Any()).DoAndReturn(func(input *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+		assert.Equal(t, "api-int."+testDNSDomain, awsSdk.ToString(input.StartRecordName))
+		return &route53.ListResourceRecordSetsOutput{
+			ResourceRecordSets: []route53types.ResourceRecordSet{aliasRecord("api-int."+testDNSDomain+".", testAPIIntLB)},
+		}, nil
+	}).After(first)
+
+	zones, err := listClusterHostedZones(mockClient, testDNSDomain)
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+	assert.True(t, zones[0].private)
+	assert.Len(t, zones[0].records, 2)
+}
+
+func TestListClusterLoadBalancers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(mockCtrl)
+
+	mockClient.EXPECT().DescribeV2LoadBalancers(gomock.Any()).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+		LoadBalancers: []elbv2types.LoadBalancer{
+			{LoadBalancerArn: awsSdk.String("arn-ext"), LoadBalancerName: awsSdk.String(testDNSInfraID + "-ext"), DNSName: awsSdk.String(testAPIExtLB)},
+			{LoadBalancerArn: awsSdk.String("arn-int"), LoadBalancerName: awsSdk.String(testDNSInfraID + "-int"), DNSName: awsSdk.String(testAPIIntLB)},
+		},
+	}, nil)
+	mockClient.EXPECT().DescribeV2Tags(gomock.Any()).Return(&elasticloadbalancingv2.DescribeTagsOutput{}, nil)
+	mockClient.EXPECT().DescribeLoadBalancers(gomock.Any()).Return(&elasticloadbalancing.DescribeLoadBalancersOutput{
+		LoadBalancerDescriptions: []elbtypes.LoadBalancerDescription{
+			{LoadBalancerName: awsSdk.String("a1b2c3"), DNSName: awsSdk.String(testIngressLB)},
+			{LoadBalancerName: awsSdk.String("other"), DNSName: awsSdk.String(testStaleLBAlias)},
+		},
+	}, nil)
+	mockClient.EXPECT().DescribeTags(gomock.Any()).Return(&elasticloadbalancing.DescribeTagsOutput{
+		TagDescriptions: []elbtypes.TagDescription{
+			{LoadBalancerName: awsSdk.String("a1b2c3"), Tags: []elbtypes.Tag{
+				{Key: awsSdk.String("kubernetes.io/cluster/" + testDNSInfraID), Value: awsSdk.String("owned")},
+				{Key: awsSdk.String(serviceNameTag), Value: awsSdk.String(routerServiceName)},
+			}},
+			{LoadBalancerName: awsSdk.String("other"), Tags: []elbtypes.Tag{
+				{Key: awsSdk.String(serviceNameTag), Value: awsSdk.String(routerServiceName)},
+			}},
+		},
+	}, nil)
+
+	lbs, err := listClusterLoadBalancers(mockClient, testDNSInfraID)
+	require.NoError(t, err)
+	assert.Equal(t, clusterLoadBalancers{apiExternal: testAPIExtLB, apiInternal: testAPIIntLB, ingress: testIngressLB}, lbs)
+}
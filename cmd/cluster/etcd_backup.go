@@ -0,0 +1,344 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	hcpEtcdContainer = "etcd"
+	hcpEtcdPodLabel  = "app"
+	// hcpEtcdDataDir is the mount path of the persistent volume of the etcd members of a hosted control plane
+	hcpEtcdDataDir = "/var/lib/data"
+	// etcdBackupFreeSpaceFactor is how many times the size of the database must be free on the etcd volume before
+	// taking a snapshot, so that etcd keeps at least as much free space as the snapshot takes while it is on the volume
+	etcdBackupFreeSpaceFactor = 2
+)
+
+// hcpEtcdctl runs etcdctl with the client certificates mounted in the etcd container of a hosted control plane
+var hcpEtcdctl = []string{
+	"env", "ETCDCTL_API=3", "/usr/bin/etcdctl",
+	"--cacert", "/etc/etcd/tls/etcd-ca/ca.crt",
+	"--cert", "/etc/etcd/tls/client/etcd-client.crt",
+	"--key", "/etc/etcd/tls/client/etcd-client.key",
+	"--endpoints", "https://localhost:2379",
+}
+
+type etcdBackupOptions struct {
+	clusterID string
+	reason    string
+	outputDir string
+
+	// namespace is the hosted control plane namespace on the management cluster
+	namespace string
+	out       io.Writer
+	now       func() time.Time
+	client    client.Client
+	// exec runs a command in a container of a pod of the management cluster and streams its standard output to
+	// stdout, replaced in tests
+	exec func(ctx context.Context, namespace, pod, container string, command []string, stdout io.Writer) error
+}
+
+// etcdSnapshot is the status of an etcd snapshot as reported by 'etcdctl snapshot status -w json'
+type etcdSnapshot struct {
+	Hash      int64 `json:"hash"`
+	Revision  int64 `json:"revision"`
+	TotalKey  int64 `json:"totalKey"`
+	TotalSize int64 `json:"totalSize"`
+}
+
+// etcdEndpointStatus is the status of an etcd member as reported by 'etcdctl endpoint status -w json'
+type etcdEndpointStatus struct {
+	Status struct {
+		DBSize int64 `json:"dbSize"`
+	} `json:"Status"`
+}
+
+func newCmdEtcd() *cobra.Command {
+	etcdCmd := &cobra.Command{
+		Use:               "etcd",
+		Short:             "Manage the etcd of a cluster",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+
+	etcdCmd.AddCommand(newCmdEtcdBackup())
+
+	return etcdCmd
+}
+
+func newCmdEtcdBackup() *cobra.Command {
+	opts := &etcdBackupOptions{out: os.Stdout, now: time.Now}
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Take an etcd snapshot of an HCP cluster and download it",
+		Long: `Take an on-demand etcd snapshot of an HCP cluster, verify it and download it
+
+  The snapshot is taken from a ready etcd member of the hosted control plane on the management cluster, with
+  elevated permissions. It is only taken when the persistent volume of the member has at least twice the size of
+  the etcd database free, so that the snapshot can't run etcd out of space.
+
+  Once 'etcdctl snapshot save' completes, the snapshot is verified with 'etcdctl snapshot status', streamed off
+  the pod to --output-dir and its checksum compared with the one of the snapshot on the volume. The snapshot is
+  always removed from the volume of the member, and the downloaded file is readable by the current user only as
+  it holds the secrets of the cluster.
+
+  Scheduled backups of the hosted control plane to its backup bucket are taken with 'osdctl hcp backup' instead.`,
+		Example: `  # Take an etcd snapshot of an HCP cluster and download it to the current directory
+  osdctl cluster etcd backup --cluster-id ${CLUSTER_ID} --reason "${REASON}"
+
+  # Download the snapshot to another directory
+  osdctl cluster etcd backup --cluster-id ${CLUSTER_ID} --reason "${REASON}" --output-dir /tmp/backups`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "The internal ID, external ID or name of the HCP cluster")
+	cmd.Flags().StringVar(&opts.reason, "reason", "", "The reason for this command, which requires elevation on the management cluster, to be run (usually an OHSS or PD ticket)")
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", ".", "The directory to download the snapshot to")
+	_ = cmd.MarkFlagRequired("cluster-id")
+	_ = cmd.MarkFlagRequired("reason")
+
+	return cmd
+}
+
+// complete resolves the management cluster and the hosted control plane namespace of the cluster, and logs into
+// the management cluster
+func (o *etcdBackupOptions) complete() error {
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cluster, err := utils.GetCluster(conn, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if !cluster.Hypershift().Enabled() {
+		return fmt.Errorf("cluster %s is not an HCP cluster, use the etcd backup of the cluster itself", cluster.ID())
+	}
+	o.clusterID = cluster.ID()
+
+	hypershiftResp, err := conn.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).Hypershift().Get().Send()
+	if err != nil {
+		return fmt.Errorf("failed to get the hypershift info of cluster %s: %w", cluster.ID(), err)
+	}
+	mcName, ok := hypershiftResp.Body().GetManagementCluster()
+	if !ok {
+		return fmt.Errorf("no management cluster found for cluster %s", cluster.ID())
+	}
+	if o.namespace, ok = hypershiftResp.Body().GetHCPNamespace(); !ok {
+		return fmt.Errorf("no hcp namespace found for cluster %s", cluster.ID())
+	}
+	mc, err := utils.GetClusterAnyStatus(conn, mcName)
+	if err != nil {
+		return fmt.Errorf("failed to get management cluster %s: %w", mcName, err)
+	}
+
+	kubeCli, restCfg, clientset, err := common.GetKubeConfigAndClientWithConn(mc.ID(), conn, o.reason)
+	if err != nil {
+		return err
+	}
+	o.client = kubeCli
+	o.exec = func(ctx context.Context, namespace, pod, container string, command []string, stdout io.Writer) error {
+		return k8s.ExecInPod(ctx, restCfg, clientset, namespace, pod, container, command, stdout)
+	}
+	return nil
+}
+
+func (o *etcdBackupOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timestamp := o.now().UTC().Format("20060102-150405")
+	localPath := filepath.Join(o.outputDir, fmt.Sprintf("etcd-snapshot-%s-%s.db", o.clusterID, timestamp))
+	if _, err := os.Stat(localPath); err == nil {
+		return fmt.Errorf("%s already exists", localPath)
+	}
+
+	pod, err := o.readyEtcdPod(ctx)
+	if err != nil {
+		return err
+	}
+	if err := o.checkFreeSpace(ctx, pod); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("%s/snapshot-%s.db", hcpEtcdDataDir, timestamp)
+	// The snapshot must never be left on the etcd volume, etcdctl writes it to a .part file first
+	defer o.removeSnapshot(pod, path)
+
+	fmt.Fprintf(o.out, "Saving an etcd snapshot of cluster %s from %s/%s to %s...\n", o.clusterID, o.namespace, pod, path)
+	if _, err := o.execOutput(ctx, pod, append(append([]string{}, hcpEtcdctl...), "snapshot", "save", path)); err != nil {
+		return fmt.Errorf("failed to save the etcd snapshot: %w", err)
+	}
+
+	snapshot, err := o.verifySnapshot(ctx, pod, path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.out, "Downloading the snapshot to %s...\n", localPath)
+	if err := o.downloadSnapshot(ctx, pod, path, localPath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.out, "Snapshot verified and downloaded:\n")
+	fmt.Fprintf(o.out, "  File:     %s\n", localPath)
+	fmt.Fprintf(o.out, "  Size:     %s\n", formatBytes(float64(snapshot.TotalSize)))
+	fmt.Fprintf(o.out, "  Revision: %d\n", snapshot.Revision)
+	fmt.Fprintf(o.out, "  Keys:     %d\n", snapshot.TotalKey)
+	fmt.Fprintf(o.out, "  Hash:     %d\n", snapshot.Hash)
+	return nil
+}
+
+// execOutput runs a command in the etcd container of the pod and returns its standard output
+func (o *etcdBackupOptions) execOutput(ctx context.Context, pod string, command []string) (string, error) {
+	stdout := &bytes.Buffer{}
+	if err := o.exec(ctx, o.namespace, pod, hcpEtcdContainer, command, stdout); err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// readyEtcdPod returns the name of a running and ready etcd member of the hosted control plane
+func (o *etcdBackupOptions) readyEtcdPod(ctx context.Context) (string, error) {
+	pods := &corev1.PodList{}
+	if err := o.client.List(ctx, pods, client.InNamespace(o.namespace), client.MatchingLabels{hcpEtcdPodLabel: "etcd"}); err != nil {
+		return "", fmt.Errorf("failed to list the etcd pods in %s: %w", o.namespace, err)
+	}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return pod.Name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no ready etcd pod found in %s", o.namespace)
+}
+
+// checkFreeSpace refuses to take a snapshot when the etcd volume doesn't have etcdBackupFreeSpaceFactor times the
+// size of the database free, as filling the volume would raise the NOSPACE alarm of etcd
+func (o *etcdBackupOptions) checkFreeSpace(ctx context.Context, pod string) error {
+	output, err := o.execOutput(ctx, pod, append(append([]string{}, hcpEtcdctl...), "endpoint", "status", "-w", "json"))
+	if err != nil {
+		return fmt.Errorf("failed to get the size of the etcd database: %w", err)
+	}
+	var statuses []etcdEndpointStatus
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &statuses); err != nil || len(statuses) == 0 {
+		return fmt.Errorf("failed to parse the status of the etcd member: %q", strings.TrimSpace(output))
+	}
+	dbSize := statuses[0].Status.DBSize
+
+	// %a is the number of free blocks and %S their size
+	output, err = o.execOutput(ctx, pod, []string{"stat", "-f", "-c", "%a %S", hcpEtcdDataDir})
+	if err != nil {
+		return fmt.Errorf("failed to get the free space of the etcd volume: %w", err)
+	}
+	var blocks, blockSize int64
+	if _, err := fmt.Sscanf(strings.TrimSpace(output), "%d %d", &blocks, &blockSize); err != nil {
+		return fmt.Errorf("failed to parse the free space of the etcd volume: %q", strings.TrimSpace(output))
+	}
+	free := blocks * blockSize
+
+	if free < etcdBackupFreeSpaceFactor*dbSize {
+		return fmt.Errorf("the etcd volume has %s free for a database of %s, at least %s must be free to take a snapshot safely, use 'osdctl hcp backup' instead",
+			formatBytes(float64(free)), formatBytes(float64(dbSize)), formatBytes(float64(etcdBackupFreeSpaceFactor*dbSize)))
+	}
+	fmt.Fprintf(o.out, "The etcd volume has %s free for a database of %s\n", formatBytes(float64(free)), formatBytes(float64(dbSize)))
+	return nil
+}
+
+// verifySnapshot checks that the snapshot file exists on the etcd volume and is a valid snapshot
+func (o *etcdBackupOptions) verifySnapshot(ctx context.Context, pod, path string) (*etcdSnapshot, error) {
+	output, err := o.execOutput(ctx, pod, []string{"stat", "-c", "%s", path})
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s not found on the etcd volume: %w", path, err)
+	}
+	fileSize, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+	if err != nil || fileSize == 0 {
+		return nil, fmt.Errorf("snapshot %s is empty", path)
+	}
+
+	output, err = o.execOutput(ctx, pod, append(append([]string{}, hcpEtcdctl...), "snapshot", "status", path, "-w", "json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify snapshot %s: %w", path, err)
+	}
+	snapshot := &etcdSnapshot{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse the status of snapshot %s: %w", path, err)
+	}
+	if snapshot.Revision == 0 || snapshot.TotalKey == 0 {
+		return nil, fmt.Errorf("snapshot %s has no keys, it is not a valid backup", path)
+	}
+	return snapshot, nil
+}
+
+// downloadSnapshot streams the snapshot off the pod to localPath and checks its checksum against the one of the
+// snapshot on the volume, the local file is removed when the download fails
+func (o *etcdBackupOptions) downloadSnapshot(ctx context.Context, pod, path, localPath string) (err error) {
+	output, err := o.execOutput(ctx, pod, []string{"sha256sum", path})
+	if err != nil {
+		return fmt.Errorf("failed to get the checksum of snapshot %s: %w", path, err)
+	}
+	expected, _, _ := strings.Cut(strings.TrimSpace(output), " ")
+
+	file, err := os.OpenFile(filepath.Clean(localPath), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", localPath, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(localPath)
+		}
+	}()
+
+	hash := sha256.New()
+	if err := o.exec(ctx, o.namespace, pod, hcpEtcdContainer, []string{"cat", path}, io.MultiWriter(file, hash)); err != nil {
+		return fmt.Errorf("failed to download snapshot %s: %w", path, err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("the checksum of the downloaded snapshot %s doesn't match the one of %s (%s), the download is corrupted", actual, path, expected)
+	}
+	return nil
+}
+
+// removeSnapshot removes the snapshot and any partial snapshot from the etcd volume, even when the command was
+// interrupted
+func (o *etcdBackupOptions) removeSnapshot(pod, path string) {
+	if _, err := o.execOutput(context.Background(), pod, []string{"rm", "-f", path, path + ".part"}); err != nil {
+		fmt.Fprintf(o.out, "Warning: failed to remove the snapshot from the etcd volume, remove it with:\n  oc exec -c %s -n %s %s -- rm -f %s %s.part\n",
+			hcpEtcdContainer, o.namespace, pod, path, path)
+	}
+}
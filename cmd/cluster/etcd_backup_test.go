@@ -0,0 +1,191 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func etcdPod(name string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ocm-production-abc-hcp", Labels: map[string]string{hcpEtcdPodLabel: "etcd"}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+// snapshotData is the content of the snapshot streamed by 'cat' in the tests
+const snapshotData = "etcd snapshot"
+
+// fakeEtcdExec returns an exec of a ready etcd member answering the commands of the backup, recording them
+func fakeEtcdExec(t *testing.T, commands *[]string, outputs map[string]string) func(context.Context, string, string, string, []string, io.Writer) error {
+	return func(_ context.Context, namespace, pod, container string, command []string, stdout io.Writer) error {
+		assert.Equal(t, "ocm-production-abc-hcp", namespace)
+		assert.Equal(t, "etcd-1", pod)
+		assert.Equal(t, hcpEtcdContainer, container)
+		joined := strings.Join(command, " ")
+		*commands = append(*commands, joined)
+		for prefix, output := range outputs {
+			if strings.Contains(joined, prefix) {
+				_, err := io.WriteString(stdout, output)
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func newEtcdBackupTestOptions(t *testing.T, commands *[]string, outputs map[string]string) (*etcdBackupOptions, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &etcdBackupOptions{
+		clusterID: "abc",
+		outputDir: t.TempDir(),
+		namespace: "ocm-production-abc-hcp",
+		out:       out,
+		now:       func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) },
+		client:    fake.NewClientBuilder().WithObjects(etcdPod("etcd-0", false), etcdPod("etcd-1", true)).Build(),
+		exec:      fakeEtcdExec(t, commands, outputs),
+	}, out
+}
+
+func TestEtcdBackupRun(t *testing.T) {
+	sum := sha256.Sum256([]byte(snapshotData))
+	checksum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name      string
+		outputs   map[string]string
+		expectErr string
+		// removed is whether the snapshot is expected to be removed from the volume
+		removed bool
+	}{
+		{
+			name: "verified snapshot",
+			outputs: map[string]string{
+				"endpoint status": `[{"Endpoint":"https://localhost:2379","Status":{"dbSize":52428800}}]`,
+				"stat -f":         "2560000 4096\n",
+				"stat -c %s":      "52428800\n",
+				"snapshot status": `{"hash":123,"revision":45678,"totalKey":1500,"totalSize":52428800}`,
+				"sha256sum":       checksum + "  /var/lib/data/snapshot-20240501-120000.db\n",
+				"cat":             snapshotData,
+			},
+			removed: true,
+		},
+		{
+			name: "not enough free space",
+			outputs: map[string]string{
+				"endpoint status": `[{"Endpoint":"https://localhost:2379","Status":{"dbSize":52428800}}]`,
+				"stat -f":         "12800 4096\n",
+			},
+			expectErr: "the etcd volume has 50.0MiB free for a database of 50.0MiB, at least 100.0MiB must be free to take a snapshot safely, use 'osdctl hcp backup' instead",
+		},
+		{
+			name: "empty snapshot",
+			outputs: map[string]string{
+				"endpoint status": `[{"Endpoint":"https://localhost:2379","Status":{"dbSize":52428800}}]`,
+				"stat -f":         "2560000 4096\n",
+				"stat -c %s":      "0\n",
+			},
+			expectErr: "snapshot /var/lib/data/snapshot-20240501-120000.db is empty",
+			removed:   true,
+		},
+		{
+			name: "snapshot without keys",
+			outputs: map[string]string{
+				"endpoint status": `[{"Endpoint":"https://localhost:2379","Status":{"dbSize":52428800}}]`,
+				"stat -f":         "2560000 4096\n",
+				"stat -c %s":      "20480\n",
+				"snapshot status": `{"hash":0,"revision":0,"totalKey":0,"totalSize":20480}`,
+			},
+			expectErr: "snapshot /var/lib/data/snapshot-20240501-120000.db has no keys, it is not a valid backup",
+			removed:   true,
+		},
+		{
+			name: "corrupted download",
+			outputs: map[string]string{
+				"endpoint status": `[{"Endpoint":"https://localhost:2379","Status":{"dbSize":52428800}}]`,
+				"stat -f":         "2560000 4096\n",
+				"stat -c %s":      "52428800\n",
+				"snapshot status": `{"hash":123,"revision":45678,"totalKey":1500,"totalSize":52428800}`,
+				"sha256sum":       "0123  /var/lib/data/snapshot-20240501-120000.db\n",
+				"cat":             snapshotData,
+			},
+			expectErr: "the download is corrupted",
+			removed:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			opts, out := newEtcdBackupTestOptions(t, &commands, tt.outputs)
+			localPath := filepath.Join(opts.outputDir, "etcd-snapshot-abc-20240501-120000.db")
+
+			err := opts.run(context.Background())
+			assert.Equal(t, tt.removed, slices.Contains(commands, "rm -f /var/lib/data/snapshot-20240501-120000.db /var/lib/data/snapshot-20240501-120000.db.part"),
+				"unexpected commands %v", commands)
+			if tt.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr)
+				assert.NoFileExists(t, localPath)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, commands[2], "snapshot save /var/lib/data/snapshot-20240501-120000.db")
+			assert.Contains(t, out.String(), "Size:     50.0MiB")
+			assert.Contains(t, out.String(), "Revision: 45678")
+
+			data, err := os.ReadFile(localPath)
+			require.NoError(t, err)
+			assert.Equal(t, snapshotData, string(data))
+			info, err := os.Stat(localPath)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		})
+	}
+}
+
+func TestEtcdBackupExistingFile(t *testing.T) {
+	var commands []string
+	opts, _ := newEtcdBackupTestOptions(t, &commands, nil)
+	localPath := filepath.Join(opts.outputDir, "etcd-snapshot-abc-20240501-120000.db")
+	require.NoError(t, os.WriteFile(localPath, []byte("previous"), 0600))
+
+	assert.EqualError(t, opts.run(context.Background()), localPath+" already exists")
+	assert.Empty(t, commands)
+}
+
+func TestEtcdBackupNoReadyPod(t *testing.T) {
+	opts := &etcdBackupOptions{
+		namespace: "ocm-production-abc-hcp",
+		outputDir: t.TempDir(),
+		out:       &bytes.Buffer{},
+		now:       time.Now,
+		client:    fake.NewClientBuilder().WithObjects(etcdPod("etcd-0", false)).Build(),
+		exec: func(context.Context, string, string, string, []string, io.Writer) error {
+			return errors.New("unexpected exec")
+		},
+	}
+	assert.EqualError(t, opts.run(context.Background()), "no ready etcd pod found in ocm-production-abc-hcp")
+}
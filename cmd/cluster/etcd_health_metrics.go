@@ -0,0 +1,323 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/openshift/osdctl/cmd/cluster/metrics"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	etcdCheckPass = "PASS"
+	etcdCheckWarn = "WARN"
+	etcdCheckFail = "FAIL"
+
+	// etcdQuotaWarnRatio and etcdQuotaFailRatio are the database size to quota ratios of the warning and critical
+	// etcdDatabaseQuotaLowSpace alerts, which the CAD etcd-quota-low investigation is triggered by
+	etcdQuotaWarnRatio = 0.65
+	etcdQuotaFailRatio = 0.85
+	// etcdSlowFsyncSeconds is the WAL fsync duration over which a fsync is counted as slow, the highest bucket
+	// under the 1s threshold of the critical etcdHighFsyncDurations alert
+	etcdSlowFsyncSeconds = "0.512"
+	// etcdLeaderChangesFail is the number of leader changes per hour over which etcd is considered unstable
+	etcdLeaderChangesFail = 4
+)
+
+var (
+	etcdDBSizeQuery      = `etcd_mvcc_db_total_size_in_bytes{job="etcd"}`
+	etcdDBInUseQuery     = `etcd_mvcc_db_total_size_in_use_in_bytes{job="etcd"}`
+	etcdQuotaQuery       = `etcd_server_quota_backend_bytes{job="etcd"}`
+	etcdLeaderQuery      = `increase(etcd_server_leader_changes_seen_total{job="etcd"}[1h])`
+	etcdFsyncP99Query    = `histogram_quantile(0.99, rate(etcd_disk_wal_fsync_duration_seconds_bucket{job="etcd"}[5m]))`
+	etcdSlowFsyncQuery   = `increase(etcd_disk_wal_fsync_duration_seconds_count{job="etcd"}[1h]) - ignoring(le) increase(etcd_disk_wal_fsync_duration_seconds_bucket{job="etcd",le="` + etcdSlowFsyncSeconds + `"}[1h])`
+	etcdFiringAlertQuery = `ALERTS{alertstate="firing",alertname=~"etcd.*"}`
+)
+
+// etcdHealthOptions defines the struct for running the etcd-health command
+type etcdHealthOptions struct {
+	clusterID string
+	reason    string
+	output    string
+
+	exec metrics.PromtoolExecutor
+	out  io.Writer
+}
+
+// etcdCheck is the result of one check of the etcd health report
+type etcdCheck struct {
+	Name    string `json:"name"`
+	Member  string `json:"member,omitempty"`
+	Status  string `json:"status"`
+	Details string `json:"details"`
+}
+
+func newCmdEtcdHealth() *cobra.Command {
+	opts := &etcdHealthOptions{}
+	etcdHealthCmd := &cobra.Command{
+		Use:   "etcd-health --cluster-id <cluster-id> --reason <reason>",
+		Short: "Report the etcd database quota usage, leader changes, slow fsyncs and alarms of a cluster",
+		Long: `Report the etcd database quota usage, leader changes, slow fsyncs and alarms of a cluster
+
+  The metrics are read from the cluster's Prometheus through backplane, and nothing is changed on the cluster.
+  These are the checks of the CAD etcd-quota-low investigation, so that its findings can be confirmed manually:
+
+  - database size: the size of the database of each member against the backend quota, warning from
+    65% and failing from 85% (the etcdDatabaseQuotaLowSpace alert thresholds). The space a defragmentation
+    would reclaim is reported when the database is larger than the data in use.
+  - leader changes: the leader changes of the last hour, failing from 4.
+  - fsync: the 99th percentile of the WAL fsync duration and the number of fsyncs slower than 512ms of the last hour.
+  - alarms: the firing etcd alerts. A member whose database reached its quota raises the NOSPACE alarm and only
+    serves reads and deletes until it is defragmented and the alarm is disarmed.`,
+		Example: `  # Report the etcd health of a cluster
+  osdctl cluster etcd-health --cluster-id ${CLUSTER_ID} --reason "${REASON}"
+
+  # Report the etcd health as JSON
+  osdctl cluster etcd-health --cluster-id ${CLUSTER_ID} --reason "${REASON}" -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			config, err := elevate.NewRestConfig(opts.clusterID, elevate.Reason{
+				Ticket:        opts.reason,
+				Justification: "read the etcd metrics of cluster " + opts.clusterID,
+				Command:       "cluster etcd-health",
+			})
+			if err != nil {
+				return err
+			}
+			if opts.exec, err = metrics.NewPromtoolExecutorForConfig(config); err != nil {
+				return err
+			}
+			opts.out = cmd.OutOrStdout()
+			return opts.run(cmd.Context())
+		},
+	}
+
+	etcdHealthCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	etcdHealthCmd.Flags().StringVar(&opts.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	etcdHealthCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+
+	_ = etcdHealthCmd.MarkFlagRequired("cluster-id")
+	_ = etcdHealthCmd.MarkFlagRequired("reason")
+
+	return etcdHealthCmd
+}
+
+func (o *etcdHealthOptions) validate() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	return nil
+}
+
+func (o *etcdHealthOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	queries := map[string]string{
+		"size":       etcdDBSizeQuery,
+		"inUse":      etcdDBInUseQuery,
+		"quota":      etcdQuotaQuery,
+		"leader":     etcdLeaderQuery,
+		"fsyncP99":   etcdFsyncP99Query,
+		"slowFsyncs": etcdSlowFsyncQuery,
+	}
+	values := map[string]map[string]float64{}
+	for name, expression := range queries {
+		byMember, err := o.valuesByMember(ctx, expression)
+		if err != nil {
+			return fmt.Errorf("failed to query %s: %w", expression, err)
+		}
+		values[name] = byMember
+	}
+	alerts, err := metrics.InstantQuery(ctx, o.exec, etcdFiringAlertQuery)
+	if err != nil {
+		return fmt.Errorf("failed to query the firing etcd alerts: %w", err)
+	}
+
+	var checks []etcdCheck
+	members := sortedKeys(values["size"])
+	if len(members) == 0 {
+		checks = append(checks, etcdCheck{Name: "Database size", Status: etcdCheckFail, Details: "no etcd metrics found, check that the etcd targets of Prometheus are up"})
+	}
+	for _, member := range members {
+		checks = append(checks, checkEtcdQuota(member, values["size"][member], values["inUse"][member], values["quota"][member]))
+	}
+	checks = append(checks, checkEtcdLeaderChanges(values["leader"]))
+	for _, member := range sortedKeys(values["fsyncP99"]) {
+		checks = append(checks, checkEtcdFsync(member, values["fsyncP99"][member], values["slowFsyncs"][member]))
+	}
+	checks = append(checks, checkEtcdAlerts(alerts))
+
+	if err := printEtcdChecks(o.out, checks, o.output); err != nil {
+		return err
+	}
+	for _, c := range checks {
+		if c.Status == etcdCheckFail {
+			return fmt.Errorf("etcd of cluster %s is unhealthy", o.clusterID)
+		}
+	}
+	return nil
+}
+
+// valuesByMember runs the query and returns its value per etcd member, identified by its pod
+func (o *etcdHealthOptions) valuesByMember(ctx context.Context, expression string) (map[string]float64, error) {
+	series, err := metrics.InstantQuery(ctx, o.exec, expression)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]float64{}
+	for _, s := range series {
+		member := s.Labels["pod"]
+		if member == "" {
+			member = s.Labels["instance"]
+		}
+		if member != "" && !math.IsNaN(s.Value) {
+			values[member] = s.Value
+		}
+	}
+	return values, nil
+}
+
+func checkEtcdQuota(member string, size, inUse, quota float64) etcdCheck {
+	c := etcdCheck{Name: "Database size", Member: member}
+	if quota <= 0 {
+		c.Status, c.Details = etcdCheckWarn, fmt.Sprintf("%s, the backend quota is unknown", formatBytes(size))
+		return c
+	}
+
+	ratio := size / quota
+	c.Details = fmt.Sprintf("%s of %s quota (%.0f%%)", formatBytes(size), formatBytes(quota), ratio*100)
+	if inUse > 0 && size > inUse {
+		c.Details += fmt.Sprintf(", defragmentation would reclaim %s", formatBytes(size-inUse))
+	}
+	switch {
+	case ratio >= 1:
+		c.Status = etcdCheckFail
+		c.Details += ", the quota is reached: the NOSPACE alarm is raised and etcd only serves reads and deletes"
+	case ratio >= etcdQuotaFailRatio:
+		c.Status = etcdCheckFail
+	case ratio >= etcdQuotaWarnRatio:
+		c.Status = etcdCheckWarn
+	default:
+		c.Status = etcdCheckPass
+	}
+	return c
+}
+
+func checkEtcdLeaderChanges(changes map[string]float64) etcdCheck {
+	c := etcdCheck{Name: "Leader changes"}
+	highest := 0.0
+	for _, v := range changes {
+		highest = math.Max(highest, v)
+	}
+	c.Details = fmt.Sprintf("%.0f in the last hour", math.Round(highest))
+	switch {
+	case len(changes) == 0:
+		c.Status, c.Details = etcdCheckWarn, "no leader change metrics found"
+	case math.Round(highest) >= etcdLeaderChangesFail:
+		c.Status = etcdCheckFail
+	case math.Round(highest) > 0:
+		c.Status = etcdCheckWarn
+	default:
+		c.Status = etcdCheckPass
+	}
+	return c
+}
+
+func checkEtcdFsync(member string, p99, slowFsyncs float64) etcdCheck {
+	c := etcdCheck{Name: "WAL fsync", Member: member}
+	slow := math.Max(0, math.Round(slowFsyncs))
+	c.Details = fmt.Sprintf("p99 %.0fms, %.0f fsyncs slower than %sms in the last hour", p99*1000, slow, strings.TrimPrefix(etcdSlowFsyncSeconds, "0."))
+	switch {
+	case p99 >= 1:
+		c.Status = etcdCheckFail
+	case p99 >= 0.5 || slow > 0:
+		c.Status = etcdCheckWarn
+	default:
+		c.Status = etcdCheckPass
+	}
+	return c
+}
+
+func checkEtcdAlerts(alerts []metrics.Series) etcdCheck {
+	c := etcdCheck{Name: "Alarms", Status: etcdCheckPass, Details: "no etcd alert firing"}
+	var firing []string
+	for _, a := range alerts {
+		name := a.Labels["alertname"]
+		if severity := a.Labels["severity"]; severity != "" {
+			name += " (" + severity + ")"
+			if severity == "critical" {
+				c.Status = etcdCheckFail
+			}
+		}
+		if !slices.Contains(firing, name) {
+			firing = append(firing, name)
+		}
+	}
+	if len(firing) > 0 {
+		if c.Status != etcdCheckFail {
+			c.Status = etcdCheckWarn
+		}
+		sort.Strings(firing)
+		c.Details = "firing: " + strings.Join(firing, ", ")
+	}
+	return c
+}
+
+func printEtcdChecks(w io.Writer, checks []etcdCheck, output string) error {
+	if output == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(checks)
+	}
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "MEMBER", "STATUS", "DETAILS"})
+	for _, c := range checks {
+		member := c.Member
+		if member == "" {
+			member = "-"
+		}
+		table.AddRow([]string{c.Name, member, c.Status, c.Details})
+	}
+	return table.Flush()
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatBytes formats a size in bytes with a binary unit, e.g. 2.5GiB
+func formatBytes(size float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for size >= 1024 && i < len(units)-1 {
+		size /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f%s", size, units[i])
+}
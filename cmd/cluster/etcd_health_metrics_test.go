@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/cluster/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gib = 1024 * 1024 * 1024
+
+func fakeEtcdExecutor(results map[string]string) metrics.PromtoolExecutor {
+	return func(_ context.Context, command []string) (string, error) {
+		expression := command[len(command)-1]
+		for _, query := range []string{etcdFiringAlertQuery, etcdSlowFsyncQuery, etcdFsyncP99Query, etcdLeaderQuery, etcdQuotaQuery, etcdDBInUseQuery, etcdDBSizeQuery} {
+			if expression == query {
+				if result, ok := results[query]; ok {
+					return result, nil
+				}
+				return "[]", nil
+			}
+		}
+		return "", fmt.Errorf("unexpected query %s", expression)
+	}
+}
+
+func etcdSeries(value float64, pods ...string) string {
+	var series []string
+	for _, pod := range pods {
+		series = append(series, fmt.Sprintf(`{"metric":{"pod":"%s"},"value":[1700000000,"%g"]}`, pod, value))
+	}
+	return "[" + strings.Join(series, ",") + "]"
+}
+
+func TestEtcdHealthRun(t *testing.T) {
+	pods := []string{"etcd-master-0", "etcd-master-1", "etcd-master-2"}
+
+	t.Run("healthy", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		opts := &etcdHealthOptions{clusterID: "abc", output: "table", out: out, exec: fakeEtcdExecutor(map[string]string{
+			etcdDBSizeQuery:    etcdSeries(2*gib, pods...),
+			etcdDBInUseQuery:   etcdSeries(1*gib, pods...),
+			etcdQuotaQuery:     etcdSeries(8*gib, pods...),
+			etcdLeaderQuery:    etcdSeries(0, pods...),
+			etcdFsyncP99Query:  etcdSeries(0.008, pods...),
+			etcdSlowFsyncQuery: etcdSeries(0, pods...),
+		})}
+		require.NoError(t, opts.run(context.Background()))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 9)
+		assert.Contains(t, lines[0], "CHECK")
+		assert.Contains(t, lines[1], "2.0GiB of 8.0GiB quota (25%), defragmentation would reclaim 1.0GiB")
+		assert.Contains(t, lines[4], "0 in the last hour")
+		assert.Contains(t, lines[5], "p99 8ms, 0 fsyncs slower than 512ms")
+		assert.Contains(t, lines[8], "no etcd alert firing")
+		assert.NotContains(t, out.String(), etcdCheckWarn)
+	})
+
+	t.Run("quota low", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		opts := &etcdHealthOptions{clusterID: "abc", output: "json", out: out, exec: fakeEtcdExecutor(map[string]string{
+			etcdDBSizeQuery:      etcdSeries(7*gib, pods...),
+			etcdQuotaQuery:       etcdSeries(8*gib, pods...),
+			etcdLeaderQuery:      etcdSeries(1, pods...),
+			etcdFsyncP99Query:    etcdSeries(0.6, pods[0]),
+			etcdSlowFsyncQuery:   etcdSeries(12, pods[0]),
+			etcdFiringAlertQuery: `[{"metric":{"alertname":"etcdDatabaseQuotaLowSpace","severity":"critical"},"value":[1700000000,"1"]}]`,
+		})}
+		err := opts.run(context.Background())
+		require.EqualError(t, err, "etcd of cluster abc is unhealthy")
+
+		var checks []etcdCheck
+		require.NoError(t, json.Unmarshal(out.Bytes(), &checks))
+		require.Len(t, checks, 6)
+		assert.Equal(t, etcdCheck{Name: "Database size", Member: "etcd-master-0", Status: etcdCheckFail, Details: "7.0GiB of 8.0GiB quota (88%)"}, checks[0])
+		assert.Equal(t, etcdCheck{Name: "Leader changes", Status: etcdCheckWarn, Details: "1 in the last hour"}, checks[3])
+		assert.Equal(t, etcdCheck{Name: "WAL fsync", Member: "etcd-master-0", Status: etcdCheckWarn, Details: "p99 600ms, 12 fsyncs slower than 512ms in the last hour"}, checks[4])
+		assert.Equal(t, etcdCheck{Name: "Alarms", Status: etcdCheckFail, Details: "firing: etcdDatabaseQuotaLowSpace (critical)"}, checks[5])
+	})
+
+	t.Run("no metrics", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		opts := &etcdHealthOptions{clusterID: "abc", output: "table", out: out, exec: fakeEtcdExecutor(nil)}
+		require.Error(t, opts.run(context.Background()))
+		assert.Contains(t, out.String(), "no etcd metrics found")
+	})
+}
+
+func TestCheckEtcdQuota(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     float64
+		quota    float64
+		status   string
+		contains string
+	}{
+		{name: "below warning", size: 5 * gib, quota: 8 * gib, status: etcdCheckPass, contains: "(62%)"},
+		{name: "warning", size: 5.5 * gib, quota: 8 * gib, status: etcdCheckWarn, contains: "(69%)"},
+		{name: "critical", size: 7 * gib, quota: 8 * gib, status: etcdCheckFail, contains: "(88%)"},
+		{name: "quota reached", size: 8 * gib, quota: 8 * gib, status: etcdCheckFail, contains: "NOSPACE"},
+		{name: "unknown quota", size: 1 * gib, status: etcdCheckWarn, contains: "quota is unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := checkEtcdQuota("etcd-0", tt.size, 0, tt.quota)
+			assert.Equal(t, tt.status, c.Status)
+			assert.Contains(t, c.Details, tt.contains)
+		})
+	}
+}
+
+func TestEtcdHealthValidate(t *testing.T) {
+	assert.NoError(t, (&etcdHealthOptions{clusterID: "abc", output: "json"}).validate())
+	assert.Error(t, (&etcdHealthOptions{clusterID: "abc", output: "yaml"}).validate())
+}
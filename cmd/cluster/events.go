@@ -0,0 +1,449 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/backplane"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	eventSourceServiceLog     = "servicelog"
+	eventSourceLimitedSupport = "limited-support"
+	eventSourceUpgrade        = "upgrade"
+	eventSourceCAD            = "cad"
+	eventSourceCloudTrail     = "cloudtrail"
+)
+
+var validEventSources = []string{
+	eventSourceServiceLog,
+	eventSourceLimitedSupport,
+	eventSourceUpgrade,
+	eventSourceCAD,
+	eventSourceCloudTrail,
+}
+
+// timelineEvent is a single entry of the cluster events timeline, regardless of where it was collected from
+type timelineEvent struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Summary string    `json:"summary"`
+	Actor   string    `json:"actor,omitempty"`
+	Details string    `json:"details,omitempty"`
+}
+
+type eventsOptions struct {
+	clusterID  string
+	since      time.Duration
+	output     string
+	sources    []string
+	awsProfile string
+	pages      int
+	verbose    bool
+
+	cluster *cmv1.Cluster
+	out     io.Writer
+}
+
+// newCmdEvents implements the events command to show a unified timeline of what happened to a cluster
+func newCmdEvents() *cobra.Command {
+	opts := &eventsOptions{out: os.Stdout}
+	eventsCmd := &cobra.Command{
+		Use:   "events --cluster-id <cluster-identifier>",
+		Short: "Shows a unified timeline of the events of a cluster",
+		Long: `Shows a single chronologically sorted timeline of the events of a cluster, merged from:
+
+  servicelog       OCM service logs
+  limited-support  Limited support reasons placed on the cluster
+  upgrade          OCM upgrade policies and their state, and the completed upgrades of the cluster version history
+  cad              CAD investigation reports stored in backplane-api
+  cloudtrail       CloudTrail write events (AWS clusters only)
+
+Sources which cannot be collected are reported on stderr and skipped, so that the timeline
+can still be used for incident retrospectives when e.g. no AWS credentials are available.`,
+		Example: `  # Show the timeline of the last 7 days
+  osdctl cluster events --cluster-id ${CLUSTER_ID}
+
+  # Show the timeline of the last 3 days without CloudTrail events as JSON
+  osdctl cluster events --cluster-id ${CLUSTER_ID} --since 72h --sources servicelog,limited-support,upgrade,cad -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			return opts.run()
+		},
+	}
+
+	eventsCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Provide internal ID of the cluster")
+	eventsCmd.Flags().DurationVar(&opts.since, "since", 7*24*time.Hour, "Only show events more recent than this duration")
+	eventsCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Valid formats are ['table', 'json']")
+	eventsCmd.Flags().StringSliceVar(&opts.sources, "sources", validEventSources, fmt.Sprintf("Sources to collect events from. Valid sources are %v", validEventSources))
+	eventsCmd.Flags().StringVarP(&opts.awsProfile, "profile", "p", "", "AWS Profile used to collect CloudTrail events")
+	eventsCmd.Flags().IntVar(&opts.pages, "pages", 10, "Maximum number of CloudTrail pages to collect")
+	eventsCmd.Flags().BoolVar(&opts.verbose, "verbose", false, "Verbose output")
+	_ = eventsCmd.MarkFlagRequired("cluster-id")
+
+	return eventsCmd
+}
+
+func (o *eventsOptions) validate() error {
+	if err := utils.IsValidClusterKey(o.clusterID); err != nil {
+		return err
+	}
+	if o.since <= 0 {
+		return fmt.Errorf("--since must be a positive duration")
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("unknown output format: %s", o.output)
+	}
+	for _, source := range o.sources {
+		if !o.isValidSource(source) {
+			return fmt.Errorf("invalid source %q, must be one of %v", source, validEventSources)
+		}
+	}
+	return nil
+}
+
+func (o *eventsOptions) isValidSource(source string) bool {
+	for _, s := range validEventSources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *eventsOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	o.cluster, err = utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	o.clusterID = o.cluster.ID()
+
+	events, collectErrors := o.collectEvents(ocmClient)
+	if len(collectErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "Encountered errors during event collection. Displayed timeline may be incomplete:\n")
+		for _, collectErr := range collectErrors {
+			fmt.Fprintf(os.Stderr, "\t%v\n", collectErr)
+		}
+	}
+
+	events = buildTimeline(events, time.Now().Add(-o.since))
+
+	if o.output == "json" {
+		return printTimelineJSON(o.out, events)
+	}
+	return printTimelineTable(o.out, events)
+}
+
+// collectEvents collects the events of all requested sources concurrently
+func (o *eventsOptions) collectEvents(ocmClient *sdk.Connection) ([]timelineEvent, []error) {
+	var (
+		events         []timelineEvent
+		collectErrors  []error
+		mu             sync.Mutex
+		wg             sync.WaitGroup
+		sinceTimestamp = time.Now().Add(-o.since)
+	)
+
+	collectors := map[string]func() ([]timelineEvent, error){
+		eventSourceServiceLog: func() ([]timelineEvent, error) {
+			logs, err := servicelog.GetServiceLogsSince(o.clusterID, sinceTimestamp, true, false)
+			if err != nil {
+				return nil, err
+			}
+			return serviceLogEvents(logs), nil
+		},
+		eventSourceLimitedSupport: func() ([]timelineEvent, error) {
+			reasons, err := utils.GetClusterLimitedSupportReasons(ocmClient, o.clusterID)
+			if err != nil {
+				return nil, err
+			}
+			return limitedSupportEvents(reasons), nil
+		},
+		eventSourceUpgrade: func() ([]timelineEvent, error) {
+			events, err := o.upgradeEvents(ocmClient)
+			if err != nil {
+				return nil, err
+			}
+			history, err := o.versionHistoryEvents()
+			if err != nil {
+				// Keep the upgrade policies when the cluster cannot be reached
+				return events, fmt.Errorf("failed to get the cluster version history: %w", err)
+			}
+			return append(events, history...), nil
+		},
+		eventSourceCAD: func() ([]timelineEvent, error) {
+			backplaneClient, err := backplane.NewClient(o.clusterID)
+			if err != nil {
+				return nil, err
+			}
+			reports, err := backplaneClient.ListReports(context.Background(), 0)
+			if err != nil {
+				return nil, err
+			}
+			return cadReportEvents(o.clusterID, reports), nil
+		},
+		eventSourceCloudTrail: func() ([]timelineEvent, error) {
+			if o.cluster.CloudProvider().ID() != "aws" {
+				return nil, nil
+			}
+			ctEvents, err := GetCloudTrailLogsForCluster(o.awsProfile, o.clusterID, o.pages)
+			if err != nil {
+				return nil, err
+			}
+			return cloudTrailEvents(ctEvents), nil
+		},
+	}
+
+	for _, source := range o.sources {
+		collect := collectors[source]
+		wg.Add(1)
+		go func(source string) {
+			defer wg.Done()
+			defer utils.StartDelayTracker(o.verbose, source+" events").End()
+			sourceEvents, err := collect()
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				collectErrors = append(collectErrors, fmt.Errorf("error while collecting %s events: %w", source, err))
+			}
+			// A source may return the events it collected along with an error
+			events = append(events, sourceEvents...)
+		}(source)
+	}
+	wg.Wait()
+
+	return events, collectErrors
+}
+
+// upgradeEvents returns the upgrade policies of the cluster, using the control plane policies for HCP clusters
+func (o *eventsOptions) upgradeEvents(ocmClient *sdk.Connection) ([]timelineEvent, error) {
+	clusterResource := ocmClient.ClustersMgmt().V1().Clusters().Cluster(o.clusterID)
+
+	if o.cluster.Hypershift().Enabled() {
+		resp, err := clusterResource.ControlPlane().UpgradePolicies().List().Send()
+		if err != nil {
+			return nil, err
+		}
+		var events []timelineEvent
+		for _, policy := range resp.Items().Slice() {
+			events = append(events, upgradePolicyEvent(policy.Version(), string(policy.ScheduleType()), policy.State(), policy.NextRun()))
+		}
+		return events, nil
+	}
+
+	resp, err := clusterResource.UpgradePolicies().List().Send()
+	if err != nil {
+		return nil, err
+	}
+	var events []timelineEvent
+	for _, policy := range resp.Items().Slice() {
+		stateResp, err := clusterResource.UpgradePolicies().UpgradePolicy(policy.ID()).State().Get().Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get state of upgrade policy %s: %w", policy.ID(), err)
+		}
+		events = append(events, upgradePolicyEvent(policy.Version(), string(policy.ScheduleType()), stateResp.Body(), policy.NextRun()))
+	}
+	return events, nil
+}
+
+// versionHistoryEvents returns the completed updates of the ClusterVersion history of the cluster
+func (o *eventsOptions) versionHistoryEvents() ([]timelineEvent, error) {
+	scheme := runtime.NewScheme()
+	if err := configv1.Install(scheme); err != nil {
+		return nil, err
+	}
+	c, err := k8s.New(o.clusterID, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	clusterVersion := &configv1.ClusterVersion{}
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "version"}, clusterVersion); err != nil {
+		return nil, err
+	}
+	return clusterVersionHistoryEvents(clusterVersion.Status.History), nil
+}
+
+// clusterVersionHistoryEvents returns an event per completed update of the history, the oldest one being the installation
+func clusterVersionHistoryEvents(history []configv1.UpdateHistory) []timelineEvent {
+	var events []timelineEvent
+	for i, update := range history {
+		if update.State != configv1.CompletedUpdate || update.CompletionTime == nil {
+			continue
+		}
+		summary := fmt.Sprintf("Upgraded to %s", update.Version)
+		if i == len(history)-1 {
+			summary = fmt.Sprintf("Installed %s", update.Version)
+		}
+		events = append(events, timelineEvent{
+			Time:    update.CompletionTime.Time,
+			Source:  eventSourceUpgrade,
+			Summary: summary,
+			Details: fmt.Sprintf("Started at %s", update.StartedTime.UTC().Format(time.RFC3339)),
+		})
+	}
+	return events
+}
+
+func upgradePolicyEvent(version string, scheduleType string, state *cmv1.UpgradePolicyState, nextRun time.Time) timelineEvent {
+	event := timelineEvent{
+		Time:    nextRun,
+		Source:  eventSourceUpgrade,
+		Summary: fmt.Sprintf("Upgrade to %s (%s)", valueOrDash(version), valueOrDash(scheduleType)),
+	}
+	if state != nil {
+		event.Summary = fmt.Sprintf("%s: %s", event.Summary, state.Value())
+		event.Details = state.Description()
+	}
+	return event
+}
+
+func serviceLogEvents(logs []*v1.LogEntry) []timelineEvent {
+	events := make([]timelineEvent, 0, len(logs))
+	for _, log := range logs {
+		summary := log.Summary()
+		if log.InternalOnly() {
+			summary = "[internal] " + summary
+		}
+		events = append(events, timelineEvent{
+			Time:    log.CreatedAt(),
+			Source:  eventSourceServiceLog,
+			Summary: fmt.Sprintf("%s: %s", log.Severity(), summary),
+			Actor:   log.Username(),
+			Details: log.Description(),
+		})
+	}
+	return events
+}
+
+func limitedSupportEvents(reasons []*cmv1.LimitedSupportReason) []timelineEvent {
+	events := make([]timelineEvent, 0, len(reasons))
+	for _, reason := range reasons {
+		events = append(events, timelineEvent{
+			Time:    reason.CreationTimestamp(),
+			Source:  eventSourceLimitedSupport,
+			Summary: "Limited support reason added: " + reason.Summary(),
+			Details: reason.Details(),
+		})
+	}
+	return events
+}
+
+func cadReportEvents(clusterID string, reports *backplaneapi.ListReports) []timelineEvent {
+	if reports == nil {
+		return nil
+	}
+	events := make([]timelineEvent, 0, len(reports.Reports))
+	for _, report := range reports.Reports {
+		if report.CreatedAt == nil {
+			continue
+		}
+		event := timelineEvent{
+			Time:    *report.CreatedAt,
+			Source:  eventSourceCAD,
+			Summary: "CAD investigation report",
+		}
+		if report.Summary != nil {
+			event.Summary = fmt.Sprintf("%s: %s", event.Summary, *report.Summary)
+		}
+		if report.ReportId != nil {
+			event.Details = fmt.Sprintf("osdctl cluster reports get --cluster-id %s --report-id %s", clusterID, *report.ReportId)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func cloudTrailEvents(ctEvents []*types.Event) []timelineEvent {
+	events := make([]timelineEvent, 0, len(ctEvents))
+	for _, ctEvent := range ctEvents {
+		if ctEvent.EventTime == nil || ctEvent.EventName == nil {
+			continue
+		}
+		event := timelineEvent{
+			Time:    *ctEvent.EventTime,
+			Source:  eventSourceCloudTrail,
+			Summary: *ctEvent.EventName,
+		}
+		if ctEvent.Username != nil {
+			event.Actor = *ctEvent.Username
+		}
+		if ctEvent.EventId != nil {
+			event.Details = "EventId: " + *ctEvent.EventId
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// buildTimeline drops events older than since and sorts the remaining ones chronologically
+func buildTimeline(events []timelineEvent, since time.Time) []timelineEvent {
+	var timeline []timelineEvent
+	for _, event := range events {
+		if event.Time.Before(since) {
+			continue
+		}
+		timeline = append(timeline, event)
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.Before(timeline[j].Time)
+	})
+	return timeline
+}
+
+func printTimelineTable(w io.Writer, events []timelineEvent) error {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "No events found")
+		return nil
+	}
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"TIME", "SOURCE", "ACTOR", "SUMMARY"})
+	for _, event := range events {
+		table.AddRow([]string{
+			event.Time.UTC().Format(time.RFC3339),
+			event.Source,
+			valueOrDash(event.Actor),
+			strings.ReplaceAll(event.Summary, "\n", " "),
+		})
+	}
+	return table.Flush()
+}
+
+func printTimelineJSON(w io.Writer, events []timelineEvent) error {
+	if events == nil {
+		events = []timelineEvent{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(events)
+}
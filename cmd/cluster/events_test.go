@@ -0,0 +1,152 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTimelineEventConverters(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	log, err := v1.NewLogEntry().
+		CreatedAt(now).
+		Severity(v1.SeverityWarning).
+		Summary("Action required").
+		Username("sre-user").
+		InternalOnly(true).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, []timelineEvent{{
+		Time:    now,
+		Source:  eventSourceServiceLog,
+		Summary: "Warning: [internal] Action required",
+		Actor:   "sre-user",
+	}}, serviceLogEvents([]*v1.LogEntry{log}))
+
+	reason, err := cmv1.NewLimitedSupportReason().
+		CreationTimestamp(now).
+		Summary("Cluster is in Limited Support due to unsupported cloud provider configuration").
+		Details("details").
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, []timelineEvent{{
+		Time:    now,
+		Source:  eventSourceLimitedSupport,
+		Summary: "Limited support reason added: Cluster is in Limited Support due to unsupported cloud provider configuration",
+		Details: "details",
+	}}, limitedSupportEvents([]*cmv1.LimitedSupportReason{reason}))
+
+	reports := &backplaneapi.ListReports{}
+	require.NoError(t, json.Unmarshal([]byte(`{"reports":[{"created_at":"2024-05-01T12:00:00Z","report_id":"abc","summary":"chgm"},{"report_id":"no-time"}]}`), reports))
+	assert.Equal(t, []timelineEvent{{
+		Time:    now,
+		Source:  eventSourceCAD,
+		Summary: "CAD investigation report: chgm",
+		Details: "osdctl cluster reports get --cluster-id test-cluster --report-id abc",
+	}}, cadReportEvents("test-cluster", reports))
+	assert.Nil(t, cadReportEvents("test-cluster", nil))
+
+	assert.Equal(t, []timelineEvent{{
+		Time:    now,
+		Source:  eventSourceCloudTrail,
+		Summary: "TerminateInstances",
+		Actor:   "customer",
+		Details: "EventId: 123",
+	}}, cloudTrailEvents([]*types.Event{{
+		EventTime: &now,
+		EventName: awsSdk.String("TerminateInstances"),
+		Username:  awsSdk.String("customer"),
+		EventId:   awsSdk.String("123"),
+	}}))
+
+	state, err := cmv1.NewUpgradePolicyState().Value(cmv1.UpgradePolicyStateValueScheduled).Description("Upgrade scheduled").Build()
+	require.NoError(t, err)
+	assert.Equal(t, timelineEvent{
+		Time:    now,
+		Source:  eventSourceUpgrade,
+		Summary: "Upgrade to 4.15.3 (manual): scheduled",
+		Details: "Upgrade scheduled",
+	}, upgradePolicyEvent("4.15.3", "manual", state, now))
+}
+
+func TestClusterVersionHistoryEvents(t *testing.T) {
+	installed := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	upgraded := metav1.NewTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	events := clusterVersionHistoryEvents([]configv1.UpdateHistory{
+		{State: configv1.PartialUpdate, Version: "4.16.1", StartedTime: upgraded},
+		{State: configv1.CompletedUpdate, Version: "4.15.3", StartedTime: metav1.NewTime(upgraded.Add(-time.Hour)), CompletionTime: &upgraded},
+		{State: configv1.CompletedUpdate, Version: "4.14.8", StartedTime: metav1.NewTime(installed.Add(-time.Hour)), CompletionTime: &installed},
+	})
+
+	assert.Equal(t, []timelineEvent{
+		{Time: upgraded.Time, Source: eventSourceUpgrade, Summary: "Upgraded to 4.15.3", Details: "Started at 2024-03-01T11:00:00Z"},
+		{Time: installed.Time, Source: eventSourceUpgrade, Summary: "Installed 4.14.8", Details: "Started at 2024-01-01T11:00:00Z"},
+	}, events)
+}
+
+func TestBuildTimeline(t *testing.T) {
+	now := time.Now()
+	events := []timelineEvent{
+		{Time: now.Add(-1 * time.Hour), Source: eventSourceCloudTrail, Summary: "second"},
+		{Time: now.Add(-48 * time.Hour), Source: eventSourceServiceLog, Summary: "too old"},
+		{Time: now.Add(-2 * time.Hour), Source: eventSourceServiceLog, Summary: "first"},
+		{Time: now.Add(time.Hour), Source: eventSourceUpgrade, Summary: "scheduled"},
+	}
+
+	timeline := buildTimeline(events, now.Add(-24*time.Hour))
+	require.Len(t, timeline, 3)
+	assert.Equal(t, "first", timeline[0].Summary)
+	assert.Equal(t, "second", timeline[1].Summary)
+	assert.Equal(t, "scheduled", timeline[2].Summary)
+}
+
+func TestPrintTimeline(t *testing.T) {
+	events := []timelineEvent{{
+		Time:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Source:  eventSourceServiceLog,
+		Summary: "Info: multi\nline",
+	}}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printTimelineTable(out, events))
+	assert.Contains(t, out.String(), "2024-05-01T12:00:00Z")
+	assert.Contains(t, out.String(), "Info: multi line")
+
+	out.Reset()
+	require.NoError(t, printTimelineTable(out, nil))
+	assert.Equal(t, "No events found\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printTimelineJSON(out, nil))
+	assert.JSONEq(t, "[]", out.String())
+}
+
+func TestEventsValidate(t *testing.T) {
+	valid := eventsOptions{clusterID: "test-cluster-123", since: time.Hour, output: "table", sources: validEventSources}
+	assert.NoError(t, valid.validate())
+
+	invalidSource := valid
+	invalidSource.sources = []string{"pagerduty"}
+	assert.Error(t, invalidSource.validate())
+
+	invalidOutput := valid
+	invalidOutput.output = "yaml"
+	assert.Error(t, invalidOutput.validate())
+
+	invalidSince := valid
+	invalidSince.since = 0
+	assert.Error(t, invalidSince.validate())
+}
@@ -0,0 +1,469 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	hibernationActionHibernate = "hibernate"
+	hibernationActionResume    = "resume"
+
+	hibernationCheckPass = "PASS"
+	hibernationCheckWarn = "WARN"
+	hibernationCheckFail = "FAIL"
+
+	defaultHibernationCertWarnWithin = 30 * 24 * time.Hour
+	apiCertificateTimeout            = 10 * time.Second
+)
+
+// hibernationClient is the part of OCM the hibernate and resume commands use, replaced in tests
+type hibernationClient interface {
+	cluster(key string) (*cmv1.Cluster, error)
+	upgradePolicies(clusterID string) ([]upgradePolicy, error)
+	hibernate(clusterID string) error
+	resume(clusterID string) error
+}
+
+type hibernationOptions struct {
+	clusterID  string
+	action     string
+	warnWithin time.Duration
+	output     string
+
+	out    io.Writer
+	client hibernationClient
+	// apiCertificateExpiry returns when the serving certificate of the API of the cluster expires
+	apiCertificateExpiry func(ctx context.Context, apiURL string) (time.Time, error)
+	// internalCertificateExpiry returns the internal signer of the cluster which expires first and when
+	internalCertificateExpiry func(ctx context.Context, clusterID string) (string, time.Time, error)
+	now                       func() time.Time
+}
+
+// hibernationSigners are the internal signers of the cluster which aren't rotated while it is hibernated, the
+// cluster can't recover on its own when resumed after one of them expired
+var hibernationSigners = []types.NamespacedName{
+	{Namespace: "openshift-kube-apiserver-operator", Name: "kube-apiserver-to-kubelet-signer"},
+	{Namespace: "openshift-kube-apiserver-operator", Name: "kube-control-plane-signer"},
+	{Namespace: "openshift-kube-apiserver-operator", Name: "aggregator-client-signer"},
+}
+
+// upgradePolicy is an upgrade policy of a cluster with its state
+type upgradePolicy struct {
+	policy *cmv1.UpgradePolicy
+	state  *cmv1.UpgradePolicyState
+}
+
+// hibernationCheck is a safety check run before hibernating or resuming a cluster
+type hibernationCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// hibernationStatus is the outcome of a hibernate or resume command
+type hibernationStatus struct {
+	ClusterID string             `json:"clusterId"`
+	Name      string             `json:"name"`
+	Action    string             `json:"action"`
+	Performed bool               `json:"performed"`
+	State     string             `json:"state"`
+	Checks    []hibernationCheck `json:"checks"`
+}
+
+func newCmdHibernate() *cobra.Command {
+	opts := &hibernationOptions{action: hibernationActionHibernate}
+	cmd := &cobra.Command{
+		Use:   "hibernate",
+		Short: "Hibernate a cluster through OCM",
+		Long: `Hibernate a cluster through OCM
+
+  The instances of a hibernated cluster are stopped until the cluster is resumed with "osdctl cluster resume",
+  and the workloads of the customer are unavailable meanwhile.
+
+  The following is checked before hibernating the cluster:
+    - the cluster is ready
+    - no upgrade is in progress, a scheduled upgrade is reported as it won't start while the cluster is hibernated
+    - the cluster isn't in limited support
+    - the serving certificate of the API and the internal signers (e.g. kube-apiserver-to-kubelet-signer) don't
+      expire within --warn-within, as the certificates aren't rotated while the cluster is hibernated
+
+  Failed checks block the hibernation, warnings are reported and confirmed along with the hibernation.`,
+		Example: `  # Hibernate a cluster
+  osdctl cluster hibernate --cluster-id ${CLUSTER_ID}
+
+  # Hibernate a cluster and print the outcome as JSON
+  osdctl cluster hibernate --cluster-id ${CLUSTER_ID} -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return opts.runCommand(cmd)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "The internal ID, external ID or name of the cluster to hibernate")
+	cmd.Flags().DurationVar(&opts.warnWithin, "warn-within", defaultHibernationCertWarnWithin, "Warn when the serving certificate of the API or an internal signer expires within this duration")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	_ = cmd.MarkFlagRequired("cluster-id")
+
+	return danger.Annotate(cmd, danger.High)
+}
+
+func newCmdResume() *cobra.Command {
+	opts := &hibernationOptions{action: hibernationActionResume}
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a hibernated cluster through OCM",
+		Long: `Resume a hibernated cluster through OCM
+
+  The instances of the cluster are started again, the cluster is ready once its nodes have rejoined it and its
+  certificates have been approved. Only clusters in the hibernating state can be resumed.`,
+		Example: `  # Resume a hibernated cluster
+  osdctl cluster resume --cluster-id ${CLUSTER_ID}
+
+  # Resume a hibernated cluster and print the outcome as JSON
+  osdctl cluster resume --cluster-id ${CLUSTER_ID} -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return opts.runCommand(cmd)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "The internal ID, external ID or name of the cluster to resume")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	_ = cmd.MarkFlagRequired("cluster-id")
+
+	return danger.Annotate(cmd, danger.Medium)
+}
+
+func (o *hibernationOptions) runCommand(cmd *cobra.Command) error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	if err := utils.IsValidClusterKey(o.clusterID); err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	o.out = cmd.OutOrStdout()
+	o.client = &ocmHibernationClient{conn: conn}
+	o.apiCertificateExpiry = apiCertificateExpiry
+	o.internalCertificateExpiry = internalCertificateExpiry
+	o.now = time.Now
+	return o.run(cmd.Context(), danger.LevelOf(cmd))
+}
+
+func (o *hibernationOptions) run(ctx context.Context, level danger.Level) error {
+	cluster, err := o.client.cluster(o.clusterID)
+	if err != nil {
+		return err
+	}
+	status := hibernationStatus{
+		ClusterID: cluster.ID(),
+		Name:      cluster.Name(),
+		Action:    o.action,
+		State:     string(cluster.State()),
+	}
+
+	if o.action == hibernationActionHibernate {
+		status.Checks = o.hibernateChecks(ctx, cluster)
+	} else {
+		status.Checks = []hibernationCheck{checkClusterState(cluster, cmv1.ClusterStateHibernating)}
+	}
+
+	if o.output == "table" {
+		if err := printHibernationChecks(o.out, status.Checks); err != nil {
+			return err
+		}
+	}
+	if failed := failedHibernationChecks(status.Checks); failed > 0 {
+		if err := o.printStatus(status); err != nil {
+			return err
+		}
+		return fmt.Errorf("refusing to %s cluster %s: %d check(s) failed", o.action, cluster.Name(), failed)
+	}
+
+	if ok, err := danger.Confirm(level, cluster); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("aborting, cluster %s was not %sd", cluster.Name(), o.action)
+	}
+
+	if o.action == hibernationActionHibernate {
+		err = o.client.hibernate(cluster.ID())
+	} else {
+		err = o.client.resume(cluster.ID())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s cluster %s: %w", o.action, cluster.Name(), err)
+	}
+	status.Performed = true
+
+	// The state changes asynchronously, report the one of the cluster right after the request
+	if updated, err := o.client.cluster(cluster.ID()); err == nil {
+		status.State = string(updated.State())
+	}
+	return o.printStatus(status)
+}
+
+// hibernateChecks returns the safety checks of hibernating the cluster
+func (o *hibernationOptions) hibernateChecks(ctx context.Context, cluster *cmv1.Cluster) []hibernationCheck {
+	checks := []hibernationCheck{checkClusterState(cluster, cmv1.ClusterStateReady)}
+	if cluster.Hypershift().Enabled() {
+		checks = append(checks, hibernationCheck{Name: "Cluster type", Status: hibernationCheckFail, Message: "HCP clusters can't be hibernated"})
+	}
+	checks = append(checks, o.checkUpgrades(cluster), checkLimitedSupport(cluster), o.checkAPICertificate(ctx, cluster))
+	if !cluster.Hypershift().Enabled() {
+		checks = append(checks, o.checkInternalCertificates(ctx, cluster))
+	}
+	return checks
+}
+
+func checkClusterState(cluster *cmv1.Cluster, expected cmv1.ClusterState) hibernationCheck {
+	check := hibernationCheck{Name: "Cluster state", Status: hibernationCheckPass, Message: fmt.Sprintf("cluster is %s", cluster.State())}
+	if cluster.State() != expected {
+		check.Status = hibernationCheckFail
+		check.Message = fmt.Sprintf("cluster is %s, expected %s", cluster.State(), expected)
+	}
+	return check
+}
+
+// checkUpgrades fails when an upgrade of the cluster is in progress, and warns about the scheduled ones
+func (o *hibernationOptions) checkUpgrades(cluster *cmv1.Cluster) hibernationCheck {
+	check := hibernationCheck{Name: "Upgrades", Status: hibernationCheckPass, Message: "no upgrade in progress or scheduled"}
+	policies, err := o.client.upgradePolicies(cluster.ID())
+	if err != nil {
+		check.Status = hibernationCheckFail
+		check.Message = fmt.Sprintf("failed to get the upgrade policies: %v", err)
+		return check
+	}
+	for _, p := range policies {
+		policy := p.policy
+		switch p.state.Value() {
+		case cmv1.UpgradePolicyStateValueStarted, cmv1.UpgradePolicyStateValueDelayed:
+			check.Status = hibernationCheckFail
+			check.Message = fmt.Sprintf("upgrade to %s is %s", policy.Version(), p.state.Value())
+			return check
+		case cmv1.UpgradePolicyStateValueScheduled, cmv1.UpgradePolicyStateValuePending:
+			check.Status = hibernationCheckWarn
+			check.Message = fmt.Sprintf("upgrade to %s is scheduled at %s, it won't start while the cluster is hibernated", policy.Version(), policy.NextRun().UTC().Format(time.RFC3339))
+		}
+	}
+	return check
+}
+
+func checkLimitedSupport(cluster *cmv1.Cluster) hibernationCheck {
+	if count := cluster.Status().LimitedSupportReasonCount(); count > 0 {
+		return hibernationCheck{Name: "Limited support", Status: hibernationCheckFail, Message: fmt.Sprintf("cluster is in limited support for %d reason(s)", count)}
+	}
+	return hibernationCheck{Name: "Limited support", Status: hibernationCheckPass, Message: "cluster is fully supported"}
+}
+
+// checkAPICertificate warns when the serving certificate of the API expires soon, as it isn't rotated while the
+// cluster is hibernated
+func (o *hibernationOptions) checkAPICertificate(ctx context.Context, cluster *cmv1.Cluster) hibernationCheck {
+	check := hibernationCheck{Name: "API certificate", Status: hibernationCheckPass}
+	notAfter, err := o.apiCertificateExpiry(ctx, cluster.API().URL())
+	if err != nil {
+		check.Status = hibernationCheckWarn
+		check.Message = fmt.Sprintf("failed to check the serving certificate of the API: %v", err)
+		return check
+	}
+	check.Message = fmt.Sprintf("expires at %s", notAfter.UTC().Format(time.RFC3339))
+	if notAfter.Sub(o.now()) <= o.warnWithin {
+		check.Status = hibernationCheckWarn
+		check.Message += ", it isn't renewed while the cluster is hibernated"
+	}
+	return check
+}
+
+// apiCertificateExpiry returns the earliest expiry of the certificate chain served by the API. The chain isn't
+// verified, only its expiry is read.
+func apiCertificateExpiry(ctx context.Context, apiURL string) (time.Time, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if u.Host == "" {
+		return time.Time{}, errors.New("the cluster has no API URL")
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, apiCertificateTimeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12}} // #nosec G402 -- only the expiry of the certificates is read
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+
+	var notAfter time.Time
+	for _, cert := range conn.(*tls.Conn).ConnectionState().PeerCertificates {
+		if notAfter.IsZero() || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+		}
+	}
+	if notAfter.IsZero() {
+		return time.Time{}, errors.New("no certificate served")
+	}
+	return notAfter, nil
+}
+
+// checkInternalCertificates warns when an internal signer of the cluster expires soon, as it isn't rotated while the
+// cluster is hibernated
+func (o *hibernationOptions) checkInternalCertificates(ctx context.Context, cluster *cmv1.Cluster) hibernationCheck {
+	check := hibernationCheck{Name: "Internal certificates", Status: hibernationCheckPass}
+	signer, notAfter, err := o.internalCertificateExpiry(ctx, cluster.ID())
+	if err != nil {
+		check.Status = hibernationCheckWarn
+		check.Message = fmt.Sprintf("failed to check the internal signers: %v", err)
+		return check
+	}
+	check.Message = fmt.Sprintf("%s expires first at %s", signer, notAfter.UTC().Format(time.RFC3339))
+	if notAfter.Sub(o.now()) <= o.warnWithin {
+		check.Status = hibernationCheckWarn
+		check.Message += ", it isn't renewed while the cluster is hibernated"
+	}
+	return check
+}
+
+// internalCertificateExpiry returns the internal signer of the cluster which expires first and when, read through backplane
+func internalCertificateExpiry(ctx context.Context, clusterID string) (string, time.Time, error) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return "", time.Time{}, err
+	}
+	c, err := k8s.New(clusterID, client.Options{Scheme: scheme})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return earliestSignerExpiry(ctx, c)
+}
+
+// earliestSignerExpiry returns the signer of hibernationSigners which expires first and when
+func earliestSignerExpiry(ctx context.Context, c client.Client) (string, time.Time, error) {
+	var (
+		signer   string
+		notAfter time.Time
+	)
+	for _, key := range hibernationSigners {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, key, secret); err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to get secret %s: %w", key, err)
+		}
+		block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+		if block == nil || block.Type != "CERTIFICATE" {
+			return "", time.Time{}, fmt.Errorf("no PEM encoded certificate found in secret %s", key)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to parse the certificate of secret %s: %w", key, err)
+		}
+		if notAfter.IsZero() || cert.NotAfter.Before(notAfter) {
+			signer, notAfter = key.Name, cert.NotAfter
+		}
+	}
+	return signer, notAfter, nil
+}
+
+func failedHibernationChecks(checks []hibernationCheck) int {
+	failed := 0
+	for _, check := range checks {
+		if check.Status == hibernationCheckFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+func printHibernationChecks(w io.Writer, checks []hibernationCheck) error {
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "MESSAGE"})
+	for _, check := range checks {
+		table.AddRow([]string{check.Name, check.Status, check.Message})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func (o *hibernationOptions) printStatus(status hibernationStatus) error {
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(status)
+	}
+	if !status.Performed {
+		return nil
+	}
+	_, err := fmt.Fprintf(o.out, "Requested to %s cluster %s (%s), it is now %s\n", status.Action, status.Name, status.ClusterID, status.State)
+	return err
+}
+
+// ocmHibernationClient is the hibernationClient of an OCM connection
+type ocmHibernationClient struct {
+	conn *sdk.Connection
+}
+
+func (c *ocmHibernationClient) cluster(key string) (*cmv1.Cluster, error) {
+	return utils.GetCluster(c.conn, key)
+}
+
+func (c *ocmHibernationClient) upgradePolicies(clusterID string) ([]upgradePolicy, error) {
+	policiesClient := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).UpgradePolicies()
+	resp, err := policiesClient.List().Send()
+	if err != nil {
+		return nil, err
+	}
+	var policies []upgradePolicy
+	for _, policy := range resp.Items().Slice() {
+		stateResp, err := policiesClient.UpgradePolicy(policy.ID()).State().Get().Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get state of upgrade policy %s: %w", policy.ID(), err)
+		}
+		policies = append(policies, upgradePolicy{policy: policy, state: stateResp.Body()})
+	}
+	return policies, nil
+}
+
+func (c *ocmHibernationClient) hibernate(clusterID string) error {
+	_, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Hibernate().Send()
+	return err
+}
+
+func (c *ocmHibernationClient) resume(clusterID string) error {
+	_, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Resume().Send()
+	return err
+}
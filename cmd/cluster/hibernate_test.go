@@ -0,0 +1,284 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeHibernationClient is a hibernationClient whose cluster moves to the state of the last request
+type fakeHibernationClient struct {
+	clusterBuilder *cmv1.ClusterBuilder
+	policies       []upgradePolicy
+	policiesErr    error
+	requests       []string
+}
+
+func (c *fakeHibernationClient) cluster(string) (*cmv1.Cluster, error) {
+	return c.clusterBuilder.Build()
+}
+
+func (c *fakeHibernationClient) upgradePolicies(string) ([]upgradePolicy, error) {
+	return c.policies, c.policiesErr
+}
+
+func (c *fakeHibernationClient) hibernate(clusterID string) error {
+	c.requests = append(c.requests, "hibernate "+clusterID)
+	c.clusterBuilder.State(cmv1.ClusterStatePoweringDown)
+	return nil
+}
+
+func (c *fakeHibernationClient) resume(clusterID string) error {
+	c.requests = append(c.requests, "resume "+clusterID)
+	c.clusterBuilder.State(cmv1.ClusterStateResuming)
+	return nil
+}
+
+func newTestUpgradePolicy(t *testing.T, version string, state cmv1.UpgradePolicyStateValue, nextRun time.Time) upgradePolicy {
+	policy, err := cmv1.NewUpgradePolicy().Version(version).NextRun(nextRun).Build()
+	require.NoError(t, err)
+	policyState, err := cmv1.NewUpgradePolicyState().Value(state).Build()
+	require.NoError(t, err)
+	return upgradePolicy{policy: policy, state: policyState}
+}
+
+func TestHibernationRun(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	newCluster := func(state cmv1.ClusterState, limitedSupportReasons int) *cmv1.ClusterBuilder {
+		return cmv1.NewCluster().ID("1a2b3c").Name("my-cluster").State(state).
+			API(cmv1.NewClusterAPI().URL("https://api.my-cluster.example.com:6443")).
+			Status(cmv1.NewClusterStatus().LimitedSupportReasonCount(limitedSupportReasons))
+	}
+
+	tests := []struct {
+		name         string
+		action       string
+		cluster      *cmv1.ClusterBuilder
+		policies     []upgradePolicy
+		certExpiry   time.Time
+		certErr      error
+		signerExpiry time.Time
+		input        string
+		output       string
+		expectedErr  string
+		expectedReqs []string
+		contains     []string
+	}{
+		{
+			name:         "hibernate",
+			action:       hibernationActionHibernate,
+			cluster:      newCluster(cmv1.ClusterStateReady, 0),
+			policies:     []upgradePolicy{newTestUpgradePolicy(t, "4.16.1", cmv1.UpgradePolicyStateValueCompleted, now.Add(-time.Hour))},
+			certExpiry:   now.Add(90 * 24 * time.Hour),
+			input:        "my-cluster\n",
+			expectedReqs: []string{"hibernate 1a2b3c"},
+			contains:     []string{"Cluster state", "PASS", "expires at 2026-07-30T12:00:00Z", "Requested to hibernate cluster my-cluster (1a2b3c), it is now powering_down"},
+		},
+		{
+			name:         "hibernate with warnings",
+			action:       hibernationActionHibernate,
+			cluster:      newCluster(cmv1.ClusterStateReady, 0),
+			policies:     []upgradePolicy{newTestUpgradePolicy(t, "4.16.2", cmv1.UpgradePolicyStateValueScheduled, now.Add(48*time.Hour))},
+			certErr:      errors.New("connection refused"),
+			input:        "my-cluster\n",
+			expectedReqs: []string{"hibernate 1a2b3c"},
+			contains: []string{
+				"upgrade to 4.16.2 is scheduled at 2026-05-03T12:00:00Z, it won't start while the cluster is hibernated",
+				"failed to check the serving certificate of the API: connection refused",
+			},
+		},
+		{
+			name:         "expiring certificate",
+			action:       hibernationActionHibernate,
+			cluster:      newCluster(cmv1.ClusterStateReady, 0),
+			certExpiry:   now.Add(10 * 24 * time.Hour),
+			input:        "my-cluster\n",
+			expectedReqs: []string{"hibernate 1a2b3c"},
+			contains:     []string{"WARN", "it isn't renewed while the cluster is hibernated"},
+		},
+		{
+			name:         "expiring internal signer",
+			action:       hibernationActionHibernate,
+			cluster:      newCluster(cmv1.ClusterStateReady, 0),
+			certExpiry:   now.Add(90 * 24 * time.Hour),
+			signerExpiry: now.Add(20 * 24 * time.Hour),
+			input:        "my-cluster\n",
+			expectedReqs: []string{"hibernate 1a2b3c"},
+			contains:     []string{"Internal certificates", "WARN", "kube-apiserver-to-kubelet-signer expires first at 2026-05-21T12:00:00Z, it isn't renewed while the cluster is hibernated"},
+		},
+		{
+			name:        "upgrade in progress",
+			action:      hibernationActionHibernate,
+			cluster:     newCluster(cmv1.ClusterStateReady, 0),
+			policies:    []upgradePolicy{newTestUpgradePolicy(t, "4.16.2", cmv1.UpgradePolicyStateValueStarted, now.Add(-time.Hour))},
+			certExpiry:  now.Add(90 * 24 * time.Hour),
+			expectedErr: "refusing to hibernate cluster my-cluster: 1 check(s) failed",
+			contains:    []string{"FAIL", "upgrade to 4.16.2 is started"},
+		},
+		{
+			name:        "limited support and not ready",
+			action:      hibernationActionHibernate,
+			cluster:     newCluster(cmv1.ClusterStateInstalling, 2),
+			certExpiry:  now.Add(90 * 24 * time.Hour),
+			expectedErr: "refusing to hibernate cluster my-cluster: 2 check(s) failed",
+			contains:    []string{"cluster is installing, expected ready", "cluster is in limited support for 2 reason(s)"},
+		},
+		{
+			name:         "wrong cluster name",
+			action:       hibernationActionHibernate,
+			cluster:      newCluster(cmv1.ClusterStateReady, 0),
+			certExpiry:   now.Add(90 * 24 * time.Hour),
+			input:        "other-cluster\n",
+			expectedErr:  "aborting, cluster my-cluster was not hibernated",
+			expectedReqs: nil,
+		},
+		{
+			name:         "resume",
+			action:       hibernationActionResume,
+			cluster:      newCluster(cmv1.ClusterStateHibernating, 0),
+			input:        "y\n",
+			expectedReqs: []string{"resume 1a2b3c"},
+			contains:     []string{"cluster is hibernating", "Requested to resume cluster my-cluster (1a2b3c), it is now resuming"},
+		},
+		{
+			name:        "resume a ready cluster",
+			action:      hibernationActionResume,
+			cluster:     newCluster(cmv1.ClusterStateReady, 0),
+			expectedErr: "refusing to resume cluster my-cluster: 1 check(s) failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := prompt.SetIO(strings.NewReader(tt.input), &bytes.Buffer{})
+			defer restore()
+
+			client := &fakeHibernationClient{clusterBuilder: tt.cluster, policies: tt.policies}
+			out := &bytes.Buffer{}
+			opts := &hibernationOptions{
+				clusterID:  "my-cluster",
+				action:     tt.action,
+				warnWithin: defaultHibernationCertWarnWithin,
+				output:     "table",
+				out:        out,
+				client:     client,
+				apiCertificateExpiry: func(_ context.Context, apiURL string) (time.Time, error) {
+					assert.Equal(t, "https://api.my-cluster.example.com:6443", apiURL)
+					return tt.certExpiry, tt.certErr
+				},
+				internalCertificateExpiry: func(_ context.Context, clusterID string) (string, time.Time, error) {
+					assert.Equal(t, "1a2b3c", clusterID)
+					if tt.signerExpiry.IsZero() {
+						return "kube-apiserver-to-kubelet-signer", now.Add(300 * 24 * time.Hour), nil
+					}
+					return "kube-apiserver-to-kubelet-signer", tt.signerExpiry, nil
+				},
+				now: func() time.Time { return now },
+			}
+			level := danger.High
+			if tt.action == hibernationActionResume {
+				level = danger.Medium
+			}
+
+			err := opts.run(context.Background(), level)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedReqs, client.requests)
+			for _, s := range tt.contains {
+				assert.Contains(t, out.String(), s)
+			}
+		})
+	}
+}
+
+func TestHibernationJSONOutput(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader("y\n"), &bytes.Buffer{})
+	defer restore()
+
+	client := &fakeHibernationClient{clusterBuilder: cmv1.NewCluster().ID("1a2b3c").Name("my-cluster").State(cmv1.ClusterStateHibernating)}
+	out := &bytes.Buffer{}
+	opts := &hibernationOptions{clusterID: "1a2b3c", action: hibernationActionResume, output: "json", out: out, client: client, now: time.Now}
+	require.NoError(t, opts.run(context.Background(), danger.Medium))
+
+	var status hibernationStatus
+	require.NoError(t, json.Unmarshal(out.Bytes(), &status))
+	assert.Equal(t, hibernationStatus{
+		ClusterID: "1a2b3c",
+		Name:      "my-cluster",
+		Action:    hibernationActionResume,
+		Performed: true,
+		State:     "resuming",
+		Checks:    []hibernationCheck{{Name: "Cluster state", Status: hibernationCheckPass, Message: "cluster is hibernating"}},
+	}, status)
+}
+
+func TestCheckUpgradesError(t *testing.T) {
+	cluster, err := cmv1.NewCluster().ID("1a2b3c").Build()
+	require.NoError(t, err)
+	opts := &hibernationOptions{client: &fakeHibernationClient{policiesErr: errors.New("forbidden")}}
+	check := opts.checkUpgrades(cluster)
+	assert.Equal(t, hibernationCheckFail, check.Status)
+	assert.Equal(t, "failed to get the upgrade policies: forbidden", check.Message)
+}
+
+func newTestSignerSecret(t *testing.T, key types.NamespacedName, notAfter time.Time) *corev1.Secret {
+	t.Helper()
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: key.Name},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data:       map[string][]byte{corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+}
+
+func TestEarliestSignerExpiry(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	var objects []kclient.Object
+	for i, key := range hibernationSigners {
+		objects = append(objects, newTestSignerSecret(t, key, now.Add(time.Duration(100-i*10)*24*time.Hour)))
+	}
+
+	c := fake.NewClientBuilder().WithObjects(objects...).Build()
+	signer, notAfter, err := earliestSignerExpiry(context.Background(), c)
+	require.NoError(t, err)
+	assert.Equal(t, "aggregator-client-signer", signer)
+	assert.Equal(t, now.Add(80*24*time.Hour), notAfter)
+
+	c = fake.NewClientBuilder().WithObjects(objects[1:]...).Build()
+	_, _, err = earliestSignerExpiry(context.Background(), c)
+	assert.ErrorContains(t, err, "failed to get secret openshift-kube-apiserver-operator/kube-apiserver-to-kubelet-signer")
+}
@@ -40,12 +40,19 @@ func NewCmdHypershiftInfo(streams genericclioptions.IOStreams) *cobra.Command {
 		Use:   "hypershift-info",
 		Short: "Pull information about AWS objects from the cluster, the management cluster and the privatelink cluster",
 		Long: `This command aggregates AWS objects from the cluster, management cluster and privatelink for hypershift cluster.
-It attempts to render the relationships as graphviz if that output format is chosen or will simply print the output as tables.`,
+It attempts to render the relationships as graphviz if that output format is chosen or will simply print the output as tables.
+
+The json output format emits the management and service cluster linkage, the HCP namespace, the OIDC configuration and the
+AWS resources found in all accounts, to attach to escalations. --dot-file writes a graphviz (DOT) graph of the relationships
+between the cluster, its management and service clusters, the HCP namespace and the OIDC issuer, merged with the AWS resources.`,
 		Example: `  # Show hypershift cluster info as graphviz
   osdctl cluster hypershift-info --cluster-id ${CLUSTER_ID}
 
   # Show hypershift cluster info as table
-  osdctl cluster hypershift-info --cluster-id ${CLUSTER_ID} --output table`,
+  osdctl cluster hypershift-info --cluster-id ${CLUSTER_ID} --output table
+
+  # Show hypershift cluster info as json and write the dependency graph for the escalation doc
+  osdctl cluster hypershift-info --cluster-id ${CLUSTER_ID} --output json --dot-file /tmp/${CLUSTER_ID}.dot`,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.CheckErr(ops.complete(cmd))
@@ -56,7 +63,8 @@ It attempts to render the relationships as graphviz if that output format is cho
 	infoCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
 	infoCmd.Flags().StringVarP(&ops.awsRegion, "region", "r", "", "AWS Region")
 	infoCmd.Flags().StringVarP(&ops.privatelinkAccountId, "privatelinkaccount", "l", "", "Privatelink account ID")
-	infoCmd.Flags().StringVarP(&ops.output, "output", "o", "graphviz", "output format ['table', 'graphviz']")
+	infoCmd.Flags().StringVarP(&ops.output, "output", "o", "graphviz", "output format ['table', 'graphviz', 'json']")
+	infoCmd.Flags().StringVar(&ops.dotFile, "dot-file", "", "Write a graphviz (DOT) graph of the cluster relationships to this file")
 	infoCmd.Flags().BoolVarP(&ops.verbose, "verbose", "", false, "Verbose output")
 
 	// Mark cluster-id as required
@@ -72,6 +80,7 @@ type infoOptions struct {
 	awsRegion            string
 	privatelinkAccountId string
 	output               string
+	dotFile              string
 	verbose              bool
 	genericclioptions.IOStreams
 }
@@ -81,6 +90,9 @@ type infoOptions struct {
 type infoClusters struct {
 	managementCluster *v1.Cluster
 	customerCluster   *v1.Cluster
+	// serviceCluster is nil when it could not be retrieved
+	serviceCluster *v1.Cluster
+	hcpNamespace   string
 }
 
 // Store all clients required to access the 3 different AWS accounts
@@ -146,8 +158,8 @@ func (i *infoOptions) complete(cmd *cobra.Command) error {
 		errMsg += "missing argument -l."
 	}
 	if i.output != "" {
-		if i.output != "graphviz" && i.output != "table" {
-			errMsg += "output must be 'graphviz' or 'table'"
+		if i.output != "graphviz" && i.output != "table" && i.output != "json" {
+			errMsg += "output must be 'graphviz', 'table' or 'json'"
 		}
 	}
 	if errMsg != "" {
@@ -217,6 +229,7 @@ func (i *infoOptions) run() error {
 			break
 		}
 	}
+	info := newHypershiftInfo(clusters, &ai)
 	switch i.output {
 	case "table":
 		render(&ai)
@@ -224,9 +237,23 @@ func (i *infoOptions) run() error {
 		connections := createGraphViz(&ai)
 		verboseLog("Generating GraphViz Input - please run this: 'echo <output> | dot -Tpng -o/tmp/example.png'")
 		graphviz.RenderGraphViz(connections)
+	case "json":
+		encoder := json.NewEncoder(i.Out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(info); err != nil {
+			return err
+		}
 	default:
 		fmt.Println("No valid output format selected")
 	}
+
+	if i.dotFile != "" {
+		dot := graphviz.Render(createTopologyGraph(info, createGraphViz(&ai)))
+		if err := os.WriteFile(i.dotFile, []byte(dot+"\n"), 0600); err != nil {
+			return fmt.Errorf("failed to write the DOT graph: %w", err)
+		}
+		verboseLog(fmt.Sprintf("Wrote the DOT graph to %s - render it with 'dot -Tpng -o/tmp/example.png %s'", i.dotFile, i.dotFile))
+	}
 	return nil
 }
 
@@ -368,9 +395,17 @@ func (i *infoOptions) getClusters() (*infoClusters, error) {
 		return nil, errors.New(errMsg)
 	}
 	managementCluster := clusters[0]
+
+	serviceCluster, err := utils.GetServiceCluster(i.clusterID)
+	if err != nil {
+		verboseLog(fmt.Sprintf("Could not retrieve the service cluster: %s", err))
+		serviceCluster = nil
+	}
 	return &infoClusters{
 		managementCluster: managementCluster,
 		customerCluster:   customerCluster,
+		serviceCluster:    serviceCluster,
+		hcpNamespace:      hrsp.HcpNamespace,
 	}, nil
 }
 
@@ -953,3 +988,180 @@ func verboseLog(msg ...interface{}) {
 		fmt.Println(msg...)
 	}
 }
+
+// hypershiftInfo is the structured output of hypershift-info
+type hypershiftInfo struct {
+	Cluster           clusterSummary  `json:"cluster"`
+	ManagementCluster clusterSummary  `json:"managementCluster"`
+	ServiceCluster    *clusterSummary `json:"serviceCluster,omitempty"`
+	HCPNamespace      string          `json:"hcpNamespace"`
+	OIDC              *oidcSummary    `json:"oidc,omitempty"`
+	AWS               awsResources    `json:"aws"`
+}
+
+type clusterSummary struct {
+	ID         string `json:"id"`
+	ExternalID string `json:"externalId,omitempty"`
+	Name       string `json:"name"`
+	Region     string `json:"region,omitempty"`
+	APIURL     string `json:"apiUrl,omitempty"`
+}
+
+type oidcSummary struct {
+	ConfigID           string `json:"configId,omitempty"`
+	IssuerURL          string `json:"issuerUrl"`
+	Managed            bool   `json:"managed"`
+	S3Bucket           string `json:"s3Bucket,omitempty"`
+	InstallerRoleARN   string `json:"installerRoleArn,omitempty"`
+	OperatorRolePrefix string `json:"operatorRolePrefix,omitempty"`
+}
+
+// awsResources lists the IDs of the AWS resources found in the customer, management and privatelink accounts
+type awsResources struct {
+	Customer struct {
+		HostedZones []string `json:"hostedZones"`
+		Endpoints   []string `json:"vpcEndpoints"`
+		Subnets     []string `json:"subnets"`
+		RouteTables []string `json:"routeTables"`
+	} `json:"customer"`
+	Management struct {
+		EndpointServices    []string `json:"endpointServices"`
+		EndpointConnections []string `json:"endpointConnections"`
+		LoadBalancers       []string `json:"loadBalancers"`
+	} `json:"management"`
+	Privatelink struct {
+		HostedZones []string `json:"hostedZones"`
+		Endpoints   []string `json:"vpcEndpoints"`
+	} `json:"privatelink"`
+}
+
+func newClusterSummary(c *v1.Cluster) clusterSummary {
+	return clusterSummary{
+		ID:         c.ID(),
+		ExternalID: c.ExternalID(),
+		Name:       c.Name(),
+		Region:     c.Region().ID(),
+		APIURL:     c.API().URL(),
+	}
+}
+
+func newOidcSummary(c *v1.Cluster) *oidcSummary {
+	sts := c.AWS().STS()
+	issuerURL := utils.OIDCIssuerURL(c)
+	if issuerURL == "" {
+		return nil
+	}
+	return &oidcSummary{
+		ConfigID:           sts.OidcConfig().ID(),
+		IssuerURL:          issuerURL,
+		Managed:            sts.OidcConfig().Managed(),
+		S3Bucket:           utils.OIDCS3Bucket(issuerURL),
+		InstallerRoleARN:   sts.RoleARN(),
+		OperatorRolePrefix: sts.OperatorRolePrefix(),
+	}
+}
+
+func newHypershiftInfo(clusters *infoClusters, ai *aggregateClusterInfo) *hypershiftInfo {
+	info := &hypershiftInfo{
+		Cluster:           newClusterSummary(clusters.customerCluster),
+		ManagementCluster: newClusterSummary(clusters.managementCluster),
+		HCPNamespace:      clusters.hcpNamespace,
+		OIDC:              newOidcSummary(clusters.customerCluster),
+	}
+	if clusters.serviceCluster != nil {
+		sc := newClusterSummary(clusters.serviceCluster)
+		info.ServiceCluster = &sc
+	}
+
+	resources := &info.AWS
+	if ai.clusterInfo != nil {
+		for _, hz := range ai.clusterInfo.HostedZones {
+			resources.Customer.HostedZones = append(resources.Customer.HostedZones, safeDeref(hz.Id))
+		}
+		for _, ep := range ai.clusterInfo.Endpoints {
+			resources.Customer.Endpoints = append(resources.Customer.Endpoints, safeDeref(ep.VpcEndpointId))
+		}
+		for _, subnet := range ai.clusterInfo.Subnets {
+			resources.Customer.Subnets = append(resources.Customer.Subnets, safeDeref(subnet.SubnetId))
+		}
+		for _, rt := range ai.clusterInfo.SubnetRouteTables {
+			resources.Customer.RouteTables = append(resources.Customer.RouteTables, safeDeref(rt.RouteTableId))
+		}
+	}
+	if ai.managementClusterInfo != nil {
+		for _, svc := range ai.managementClusterInfo.EndpointServices {
+			resources.Management.EndpointServices = append(resources.Management.EndpointServices, safeDeref(svc.ServiceId))
+		}
+		for _, conn := range ai.managementClusterInfo.EndpointConnections {
+			resources.Management.EndpointConnections = append(resources.Management.EndpointConnections, safeDeref(conn.VpcEndpointConnectionId))
+		}
+		for _, lb := range ai.managementClusterInfo.LoadBalancers {
+			resources.Management.LoadBalancers = append(resources.Management.LoadBalancers, safeDeref(lb.LoadBalancerArn))
+		}
+	}
+	if ai.privatelinkInfo != nil {
+		for _, hz := range ai.privatelinkInfo.HostedZones {
+			resources.Privatelink.HostedZones = append(resources.Privatelink.HostedZones, safeDeref(hz.Id))
+		}
+		for _, ep := range ai.privatelinkInfo.Endpoints {
+			resources.Privatelink.Endpoints = append(resources.Privatelink.Endpoints, safeDeref(ep.VpcEndpointId))
+		}
+	}
+	return info
+}
+
+// createTopologyGraph adds the relationships between the cluster, its management and service clusters,
+// the HCP namespace and the OIDC issuer to the AWS resource connections
+func createTopologyGraph(info *hypershiftInfo, connections map[graphviz.Node][]graphviz.Node) map[graphviz.Node][]graphviz.Node {
+	if connections == nil {
+		connections = make(map[graphviz.Node][]graphviz.Node)
+	}
+	connect := func(from, to graphviz.Node) {
+		connections[from] = append(connections[from], to)
+		if _, ok := connections[to]; !ok {
+			connections[to] = make([]graphviz.Node, 0)
+		}
+	}
+
+	cluster := graphviz.Node{
+		Id:                    info.Cluster.ID,
+		AdditionalInformation: fmt.Sprintf("Hosted Cluster\\n%s", info.Cluster.Name),
+		Subgraph:              "customer",
+	}
+	mc := graphviz.Node{
+		Id:                    info.ManagementCluster.ID,
+		AdditionalInformation: fmt.Sprintf("Management Cluster\\n%s", info.ManagementCluster.Name),
+		Subgraph:              "management",
+	}
+	connect(cluster, mc)
+	if info.HCPNamespace != "" {
+		connect(mc, graphviz.Node{
+			Id:                    info.HCPNamespace,
+			AdditionalInformation: "HCP Namespace",
+			Subgraph:              "management",
+		})
+	}
+	if info.ServiceCluster != nil {
+		connect(mc, graphviz.Node{
+			Id:                    info.ServiceCluster.ID,
+			AdditionalInformation: fmt.Sprintf("Service Cluster\\n%s", info.ServiceCluster.Name),
+			Subgraph:              "service",
+		})
+	}
+	if info.OIDC != nil {
+		issuer := graphviz.Node{
+			Id:                    info.OIDC.IssuerURL,
+			AdditionalInformation: "OIDC Issuer",
+			Subgraph:              "customer",
+		}
+		connect(cluster, issuer)
+		if info.OIDC.S3Bucket != "" {
+			connect(issuer, graphviz.Node{
+				Id:                    info.OIDC.S3Bucket,
+				AdditionalInformation: "OIDC S3 Bucket",
+				Subgraph:              "customer",
+			})
+		}
+	}
+	return connections
+}
@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/graphviz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHypershiftInfo(t *testing.T) {
+	customer, err := v1.NewCluster().ID("hcp-id").Name("hcp").ExternalID("ext-id").
+		Region(v1.NewCloudRegion().ID("us-east-1")).
+		API(v1.NewClusterAPI().URL("https://api.hcp.example.com:443")).
+		AWS(v1.NewAWS().STS(v1.NewSTS().
+			RoleARN("arn:aws:iam::123456789012:role/installer").
+			OidcConfig(v1.NewOidcConfig().ID("oidc-id").IssuerUrl("https://my-oidc-bucket.s3.us-east-1.amazonaws.com")))).
+		Build()
+	require.NoError(t, err)
+	mc, err := v1.NewCluster().ID("mc-id").Name("hs-mc-1").Build()
+	require.NoError(t, err)
+	sc, err := v1.NewCluster().ID("sc-id").Name("hs-sc-1").Build()
+	require.NoError(t, err)
+
+	ai := &aggregateClusterInfo{
+		clusterInfo:           &clusterInfo{HostedZones: []route53types.HostedZone{{Id: awsv2.String("Z1"), Name: awsv2.String("hcp.example.com.")}}},
+		managementClusterInfo: &managementClusterInfo{},
+		privatelinkInfo:       &privatelinkInfo{},
+	}
+	info := newHypershiftInfo(&infoClusters{customerCluster: customer, managementCluster: mc, serviceCluster: sc, hcpNamespace: "ocm-production-hcp-id-hcp"}, ai)
+
+	out, err := json.Marshal(info)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, "ocm-production-hcp-id-hcp", decoded["hcpNamespace"])
+	assert.Equal(t, "sc-id", decoded["serviceCluster"].(map[string]any)["id"])
+	assert.Equal(t, "my-oidc-bucket", decoded["oidc"].(map[string]any)["s3Bucket"])
+	assert.Equal(t, []any{"Z1"}, decoded["aws"].(map[string]any)["customer"].(map[string]any)["hostedZones"])
+
+	dot := graphviz.Render(createTopologyGraph(info, nil))
+	assert.Contains(t, dot, `"Hosted Cluster\nhcp\nhcp-id" -- "Management Cluster\nhs-mc-1\nmc-id"`)
+	assert.Contains(t, dot, `"Management Cluster\nhs-mc-1\nmc-id" -- "HCP Namespace\nocm-production-hcp-id-hcp"`)
+	assert.Contains(t, dot, `"Management Cluster\nhs-mc-1\nmc-id" -- "Service Cluster\nhs-sc-1\nsc-id"`)
+	assert.Contains(t, dot, `"OIDC Issuer\nhttps://my-oidc-bucket.s3.us-east-1.amazonaws.com" -- "OIDC S3 Bucket\nmy-oidc-bucket"`)
+	assert.Contains(t, dot, "subgraph cluster_service {")
+	assert.Equal(t, dot, graphviz.Render(createTopologyGraph(info, nil)), "the graph should render deterministically")
+}
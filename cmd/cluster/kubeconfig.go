@@ -0,0 +1,182 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type kubeconfigOptions struct {
+	clusterID   string
+	reason      string
+	contextName string
+	namespace   string
+	outputFile  string
+	force       bool
+
+	out     io.Writer
+	errOut  io.Writer
+	factory clients.Factory
+	// restConfig and elevatedRestConfig return the backplane rest configs of the cluster, replaced in tests
+	restConfig         func(clusterID string) (*rest.Config, error)
+	elevatedRestConfig func(clusterID string, reason elevate.Reason) (*rest.Config, error)
+}
+
+func newCmdKubeconfig(factory clients.Factory) *cobra.Command {
+	opts := &kubeconfigOptions{
+		out:                os.Stdout,
+		errOut:             os.Stderr,
+		factory:            factory,
+		restConfig:         k8s.NewRestConfig,
+		elevatedRestConfig: elevate.NewRestConfig,
+	}
+
+	kubeconfigCmd := &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "Export a backplane kubeconfig of a cluster",
+		Long: `Export a kubeconfig accessing the cluster through backplane, for the tools which need a kubeconfig path rather than
+a backplane login, e.g. to run them against several clusters at the same time.
+
+With --reason, the kubeconfig is elevated as backplane-cluster-admin, the reason being recorded by backplane for
+every request made with it.
+
+The kubeconfig holds the OCM access token of the current OCM login, it must be kept private and stops working when
+the token expires. Export a new one then.`,
+		Example: `  # Print the kubeconfig of a cluster
+  osdctl cluster kubeconfig --cluster-id ${CLUSTER_ID}
+
+  # Write an elevated kubeconfig to a file and use it
+  osdctl cluster kubeconfig --cluster-id ${CLUSTER_ID} --reason OHSS-1234 -f /tmp/my-cluster.kubeconfig
+  KUBECONFIG=/tmp/my-cluster.kubeconfig oc get nodes`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run()
+		},
+	}
+
+	kubeconfigCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID (internal or external) or name")
+	kubeconfigCmd.Flags().StringVar(&opts.reason, elevate.ReasonFlag, "", "Elevate the kubeconfig as backplane-cluster-admin with this reason, e.g. 'OHSS-XXXX' or '#ITN-2024-XXXXX'")
+	kubeconfigCmd.Flags().StringVar(&opts.contextName, "context-name", "", "Name of the context, cluster and user of the kubeconfig, the name of the cluster by default")
+	kubeconfigCmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Default namespace of the context")
+	kubeconfigCmd.Flags().StringVarP(&opts.outputFile, "output-file", "f", "", "File to write the kubeconfig to instead of printing it")
+	kubeconfigCmd.Flags().BoolVar(&opts.force, "force", false, "Overwrite the output file if it exists")
+	_ = kubeconfigCmd.MarkFlagRequired("cluster-id")
+
+	return kubeconfigCmd
+}
+
+func (o *kubeconfigOptions) run() error {
+	if o.force && o.outputFile == "" {
+		return errors.New("--force requires --output-file")
+	}
+	defer o.factory.Close()
+
+	cluster, err := o.factory.Cluster(o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	var cfg *rest.Config
+	if o.reason != "" {
+		cfg, err = o.elevatedRestConfig(cluster.ID(), elevate.Reason{
+			Ticket:        o.reason,
+			Justification: "export a kubeconfig of the cluster",
+			Command:       "cluster kubeconfig",
+		})
+	} else {
+		cfg, err = o.restConfig(cluster.ID())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the backplane config of cluster %s: %w", cluster.ID(), err)
+	}
+
+	contextName := o.contextName
+	if contextName == "" {
+		contextName = cluster.Name()
+	}
+	kubeconfig, err := buildKubeconfig(cfg, contextName, o.namespace)
+	if err != nil {
+		return err
+	}
+	data, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the kubeconfig: %w", err)
+	}
+
+	if o.outputFile == "" {
+		_, err = o.out.Write(data)
+		return err
+	}
+	return o.writeFile(data, cluster.ID())
+}
+
+// writeFile writes the kubeconfig readable by the user only, refusing to overwrite an existing file without --force
+func (o *kubeconfigOptions) writeFile(data []byte, clusterID string) error {
+	path := filepath.Clean(o.outputFile)
+	if _, err := os.Stat(path); err == nil && !o.force {
+		return fmt.Errorf("%s already exists, overwrite it with --force", path)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write the kubeconfig: %w", err)
+	}
+	// WriteFile keeps the permissions of an existing file
+	if err := os.Chmod(path, 0600); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(o.errOut, "Wrote the kubeconfig of cluster %s to %s, use it with KUBECONFIG=%s\n", clusterID, path, path)
+	return nil
+}
+
+// buildKubeconfig returns a kubeconfig with a single context, named like its cluster and user, accessing the cluster
+// with the rest config
+func buildKubeconfig(cfg *rest.Config, contextName, namespace string) (*clientcmdapi.Config, error) {
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = cfg.Host
+	cluster.CertificateAuthorityData = cfg.CAData
+	cluster.CertificateAuthority = cfg.CAFile
+	cluster.InsecureSkipTLSVerify = cfg.Insecure
+	if cfg.Proxy != nil {
+		host, err := url.Parse(cfg.Host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backplane URL %q: %w", cfg.Host, err)
+		}
+		proxyURL, err := cfg.Proxy(&http.Request{URL: host})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the backplane proxy: %w", err)
+		}
+		if proxyURL != nil {
+			cluster.ProxyURL = proxyURL.String()
+		}
+	}
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.Token = cfg.BearerToken
+	authInfo.Impersonate = cfg.Impersonate.UserName
+	authInfo.ImpersonateGroups = cfg.Impersonate.Groups
+	authInfo.ImpersonateUserExtra = cfg.Impersonate.Extra
+
+	context := clientcmdapi.NewContext()
+	context.Cluster = contextName
+	context.AuthInfo = contextName
+	context.Namespace = namespace
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[contextName] = cluster
+	kubeconfig.AuthInfos[contextName] = authInfo
+	kubeconfig.Contexts[contextName] = context
+	kubeconfig.CurrentContext = contextName
+	return kubeconfig, nil
+}
@@ -0,0 +1,125 @@
+package cluster
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const testBackplaneHost = "https://api.backplane.example.com/backplane/cluster/1a2b3c/"
+
+func newKubeconfigTestOptions(t *testing.T) (*kubeconfigOptions, *bytes.Buffer, *bytes.Buffer) {
+	cluster, err := cmv1.NewCluster().ID("1a2b3c").Name("my-cluster").Build()
+	require.NoError(t, err)
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	return &kubeconfigOptions{
+		clusterID: "my-cluster",
+		out:       out,
+		errOut:    errOut,
+		factory:   &clients.FakeFactory{Clusters: []*cmv1.Cluster{cluster}},
+		restConfig: func(clusterID string) (*rest.Config, error) {
+			assert.Equal(t, "1a2b3c", clusterID)
+			return &rest.Config{
+				Host:        testBackplaneHost,
+				BearerToken: "ocm-token",
+				Proxy: func(*http.Request) (*url.URL, error) {
+					return url.Parse("http://squid.example.com:3128")
+				},
+			}, nil
+		},
+		elevatedRestConfig: func(clusterID string, reason elevate.Reason) (*rest.Config, error) {
+			if err := reason.Validate(); err != nil {
+				return nil, err
+			}
+			return &rest.Config{
+				Host:        testBackplaneHost,
+				BearerToken: "ocm-token",
+				Impersonate: rest.ImpersonationConfig{
+					UserName: "backplane-cluster-admin",
+					Extra:    map[string][]string{"reason": {reason.String()}},
+				},
+			}, nil
+		},
+	}, out, errOut
+}
+
+func TestKubeconfigRun(t *testing.T) {
+	opts, out, _ := newKubeconfigTestOptions(t)
+	opts.namespace = "openshift-monitoring"
+	require.NoError(t, opts.run())
+
+	kubeconfig, err := clientcmd.Load(out.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "my-cluster", kubeconfig.CurrentContext)
+	require.Contains(t, kubeconfig.Contexts, "my-cluster")
+	assert.Equal(t, "openshift-monitoring", kubeconfig.Contexts["my-cluster"].Namespace)
+	assert.Equal(t, testBackplaneHost, kubeconfig.Clusters["my-cluster"].Server)
+	assert.Equal(t, "http://squid.example.com:3128", kubeconfig.Clusters["my-cluster"].ProxyURL)
+	assert.Equal(t, "ocm-token", kubeconfig.AuthInfos["my-cluster"].Token)
+	assert.Empty(t, kubeconfig.AuthInfos["my-cluster"].Impersonate)
+	assert.True(t, opts.factory.(*clients.FakeFactory).Closed)
+}
+
+func TestKubeconfigRunElevated(t *testing.T) {
+	opts, out, _ := newKubeconfigTestOptions(t)
+	opts.reason = "OHSS-1234"
+	opts.contextName = "my-cluster-admin"
+	require.NoError(t, opts.run())
+
+	kubeconfig, err := clientcmd.Load(out.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "my-cluster-admin", kubeconfig.CurrentContext)
+	authInfo := kubeconfig.AuthInfos["my-cluster-admin"]
+	require.NotNil(t, authInfo)
+	assert.Equal(t, "backplane-cluster-admin", authInfo.Impersonate)
+	assert.Equal(t, map[string][]string{"reason": {"OHSS-1234 - export a kubeconfig of the cluster (osdctl cluster kubeconfig)"}}, authInfo.ImpersonateUserExtra)
+}
+
+func TestKubeconfigRunOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "my-cluster.kubeconfig")
+
+	opts, out, errOut := newKubeconfigTestOptions(t)
+	opts.outputFile = path
+	require.NoError(t, opts.run())
+	assert.Empty(t, out.String())
+	assert.Contains(t, errOut.String(), "use it with KUBECONFIG="+path)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	_, err = clientcmd.LoadFromFile(path)
+	require.NoError(t, err)
+
+	opts, _, _ = newKubeconfigTestOptions(t)
+	opts.outputFile = path
+	assert.EqualError(t, opts.run(), path+" already exists, overwrite it with --force")
+
+	opts.force = true
+	assert.NoError(t, opts.run())
+}
+
+func TestKubeconfigRunErrors(t *testing.T) {
+	opts, _, _ := newKubeconfigTestOptions(t)
+	opts.force = true
+	assert.EqualError(t, opts.run(), "--force requires --output-file")
+
+	opts, _, _ = newKubeconfigTestOptions(t)
+	opts.reason = " "
+	assert.Error(t, opts.run())
+
+	opts, _, _ = newKubeconfigTestOptions(t)
+	opts.clusterID = "unknown"
+	assert.Error(t, opts.run())
+}
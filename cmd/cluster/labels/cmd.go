@@ -0,0 +1,33 @@
+package labels
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdLabels implements the labels command to manage the OCM labels of a cluster and of its subscription
+// osdctl cluster labels list --cluster-id <cluster-id>
+func NewCmdLabels() *cobra.Command {
+	labelsCmd := &cobra.Command{
+		Use:     "labels",
+		Aliases: []string{"label"},
+		Short:   "Manage the OCM labels of a cluster and of its subscription",
+		Long: `Manage the OCM labels of a cluster and of its subscription.
+
+Labels have a scope:
+  subscription: labels of the subscription of the cluster (accounts_mgmt), e.g. the Dynatrace tenant of the cluster
+  cluster:      external configuration labels of the cluster (clusters_mgmt), which are applied to the cluster
+
+The values of the labels osdctl knows are validated, and their downstream effects are shown before changing them.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	labelsCmd.AddCommand(newCmdList())
+	labelsCmd.AddCommand(newCmdSet())
+	labelsCmd.AddCommand(newCmdDelete())
+
+	return labelsCmd
+}
@@ -0,0 +1,108 @@
+package labels
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type deleteOptions struct {
+	clusterID string
+	key       string
+	scope     string
+
+	// resolvedScope is the scope of the label to delete, resolved from the flags
+	resolvedScope scope
+
+	out     io.Writer
+	client  labelClient
+	cluster *cmv1.Cluster
+}
+
+// newCmdDelete implements labels delete
+func newCmdDelete() *cobra.Command {
+	ops := &deleteOptions{out: os.Stdout}
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a label of a cluster or of its subscription",
+		Long: `Delete a label of a cluster or of its subscription.
+
+The scope of a known label is the one it is read from, other labels are subscription labels unless --scope is set.
+The label and the downstream effects of a known label are shown for confirmation.`,
+		Example: `  # Delete a subscription label of a cluster
+  osdctl cluster labels delete --cluster-id ${CLUSTER_ID} --key example.openshift.io/feature`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete(cmd))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	deleteCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID of the label")
+	deleteCmd.Flags().StringVar(&ops.key, "key", "", "Key of the label")
+	deleteCmd.Flags().StringVar(&ops.scope, "scope", string(scopeSubscription), "Scope of the label: subscription or cluster, the scope of a known label by default")
+
+	_ = deleteCmd.MarkFlagRequired("cluster-id")
+	_ = deleteCmd.MarkFlagRequired("key")
+
+	return danger.Annotate(deleteCmd, danger.Medium)
+}
+
+func (o *deleteOptions) complete(cmd *cobra.Command) error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	s, err := resolveScope(o.key, o.scope, cmd.Flags().Changed("scope"))
+	if err != nil {
+		return err
+	}
+	if err := validateKey(s, o.key); err != nil {
+		return err
+	}
+	o.resolvedScope = s
+	return nil
+}
+
+func (o *deleteOptions) run() error {
+	if o.client == nil {
+		conn, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if o.client, o.cluster, err = newOCMLabelClient(conn, o.clusterID); err != nil {
+			return err
+		}
+	}
+
+	labels, err := o.client.listLabels()
+	if err != nil {
+		return err
+	}
+	l, ok := findLabel(labels, o.resolvedScope, o.key)
+	if !ok {
+		return fmt.Errorf("cluster %s has no %s label %s", o.cluster.ID(), o.resolvedScope, o.key)
+	}
+
+	fmt.Fprintf(o.out, "The %s label %s=%q of cluster %s (%s) will be deleted\n", l.Scope, l.Key, l.Value, o.cluster.Name(), o.cluster.ID())
+	printEffects(o.out, l.Key)
+
+	if ok, err := danger.Confirm(danger.Medium, o.cluster); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("aborting the label deletion")
+	}
+	if err := o.client.deleteLabel(l); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "The %s label %s is deleted\n", l.Scope, l.Key)
+	return nil
+}
@@ -0,0 +1,43 @@
+package labels
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRun(t *testing.T) {
+	t.Run("deletes a known label after confirmation", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("y\n"), io.Discard)()
+		client := newFakeLabelClient()
+		out := &bytes.Buffer{}
+		opts := &deleteOptions{key: "ext-hypershift.openshift.io/cluster-type", resolvedScope: scopeCluster, out: out, client: client, cluster: newTestCluster(t)}
+
+		require.NoError(t, opts.run())
+		require.Len(t, client.deletes, 1)
+		assert.Equal(t, "label-id", client.deletes[0].ID)
+		assert.Contains(t, out.String(), `The cluster label ext-hypershift.openshift.io/cluster-type="management-cluster" of cluster hs-mc-1 (cluster-id) will be deleted`)
+		assert.Contains(t, out.String(), "osdctl treats the cluster as a management cluster")
+	})
+
+	t.Run("not confirmed", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("n\n"), io.Discard)()
+		client := newFakeLabelClient()
+		opts := &deleteOptions{key: "example.openshift.io/feature", resolvedScope: scopeSubscription, out: io.Discard, client: client, cluster: newTestCluster(t)}
+
+		assert.EqualError(t, opts.run(), "aborting the label deletion")
+		assert.Empty(t, client.deletes)
+	})
+
+	t.Run("missing label", func(t *testing.T) {
+		client := newFakeLabelClient()
+		opts := &deleteOptions{key: "example.openshift.io/feature", resolvedScope: scopeCluster, out: io.Discard, client: client, cluster: newTestCluster(t)}
+
+		assert.EqualError(t, opts.run(), "cluster cluster-id has no cluster label example.openshift.io/feature")
+	})
+}
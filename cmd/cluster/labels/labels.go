@@ -0,0 +1,236 @@
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// scope is the OCM resource a label is set on
+type scope string
+
+const (
+	// scopeSubscription labels are set on the subscription of the cluster (accounts_mgmt)
+	scopeSubscription scope = "subscription"
+	// scopeCluster labels are the external configuration labels of the cluster (clusters_mgmt), which are
+	// applied to the cluster itself
+	scopeCluster scope = "cluster"
+)
+
+var scopes = []string{string(scopeSubscription), string(scopeCluster)}
+
+func parseScope(value string) (scope, error) {
+	if !slices.Contains(scopes, value) {
+		return "", fmt.Errorf("invalid scope %q, must be one of: %s", value, strings.Join(scopes, ", "))
+	}
+	return scope(value), nil
+}
+
+// label is a label of a cluster or of its subscription
+type label struct {
+	Scope scope
+	// ID identifies the external configuration labels, which aren't addressed by their key
+	ID    string
+	Key   string
+	Value string
+	// Internal subscription labels are hidden from the customer
+	Internal bool
+}
+
+// labelSchema describes a label osdctl or other tooling relies on
+type labelSchema struct {
+	Key   string
+	Scope scope
+	// Internal is whether the label is hidden from the customer, for subscription labels
+	Internal bool
+	// Values are the allowed values, or Pattern the format of the value
+	Values      []string
+	Pattern     *regexp.Regexp
+	Description string
+	// Effects are what the label changes downstream, shown before setting or deleting it
+	Effects []string
+}
+
+// knownLabels are the labels whose value is validated before setting them
+var knownLabels = []labelSchema{
+	{
+		Key:         utils.DynatraceTenantKeyLabel,
+		Scope:       scopeSubscription,
+		Internal:    true,
+		Pattern:     regexp.MustCompile(`^[a-z0-9]+$`),
+		Description: "the ID of the Dynatrace tenant of the cluster, e.g. abc12345",
+		Effects: []string{
+			"osdctl dynatrace commands query https://<value>.apps.dynatrace.com/ for the cluster, and for the HCP clusters it manages when it is a management cluster",
+		},
+	},
+	{
+		Key:         utils.HypershiftClusterTypeLabel,
+		Scope:       scopeCluster,
+		Values:      []string{"management-cluster", "service-cluster"},
+		Description: "the type of the cluster in the HCP infrastructure",
+		Effects: []string{
+			"osdctl treats the cluster as a management cluster of HCP clusters (dynatrace, rhobs, hcp, network verify-egress and cluster access cleanup commands)",
+		},
+	},
+}
+
+// findSchema returns the schema of a known label
+func findSchema(key string) (labelSchema, bool) {
+	for _, schema := range knownLabels {
+		if schema.Key == key {
+			return schema, true
+		}
+	}
+	return labelSchema{}, false
+}
+
+// validateKey checks the format of the key, and that a known label is set on its scope
+func validateKey(s scope, key string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, ", "))
+	}
+	if schema, ok := findSchema(key); ok && schema.Scope != s {
+		return fmt.Errorf("label %s is a %s label, not a %s label", key, schema.Scope, s)
+	}
+	return nil
+}
+
+// validateValue checks the value of a known label, the value of other labels must only be non-empty
+func validateValue(key, value string) error {
+	if value == "" {
+		return fmt.Errorf("the value of label %s can't be empty, delete the label instead", key)
+	}
+	schema, ok := findSchema(key)
+	if !ok {
+		return nil
+	}
+	if len(schema.Values) > 0 && !slices.Contains(schema.Values, value) {
+		return fmt.Errorf("invalid value %q for label %s, must be one of: %s", value, key, strings.Join(schema.Values, ", "))
+	}
+	if schema.Pattern != nil && !schema.Pattern.MatchString(value) {
+		return fmt.Errorf("invalid value %q for label %s, expected %s", value, key, schema.Description)
+	}
+	return nil
+}
+
+// findLabel returns the label of the scope with the key
+func findLabel(labels []label, s scope, key string) (label, bool) {
+	for _, l := range labels {
+		if l.Scope == s && l.Key == key {
+			return l, true
+		}
+	}
+	return label{}, false
+}
+
+// labelClient is the subset of the OCM API used to manage the labels of a cluster
+type labelClient interface {
+	listLabels() ([]label, error)
+	// setLabel creates the label, or updates it when existing is set
+	setLabel(l label, existing *label) error
+	deleteLabel(l label) error
+}
+
+// ocmLabelClient manages the labels of a cluster and of its subscription
+type ocmLabelClient struct {
+	conn    *sdk.Connection
+	cluster *cmv1.Cluster
+}
+
+// newOCMLabelClient returns the label client of the cluster
+func newOCMLabelClient(conn *sdk.Connection, clusterID string) (*ocmLabelClient, *cmv1.Cluster, error) {
+	cluster, err := utils.GetCluster(conn, clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ocmLabelClient{conn: conn, cluster: cluster}, cluster, nil
+}
+
+func (c *ocmLabelClient) subscriptionLabels() *amv1.GenericLabelsClient {
+	return c.conn.AccountsMgmt().V1().Subscriptions().Subscription(c.cluster.Subscription().ID()).Labels()
+}
+
+func (c *ocmLabelClient) clusterLabels() *cmv1.LabelsClient {
+	return c.conn.ClustersMgmt().V1().Clusters().Cluster(c.cluster.ID()).ExternalConfiguration().Labels()
+}
+
+func (c *ocmLabelClient) listLabels() ([]label, error) {
+	var labels []label
+
+	subscriptionResponse, err := c.subscriptionLabels().List().Size(100).Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the labels of subscription %s: %w", c.cluster.Subscription().ID(), err)
+	}
+	for _, l := range subscriptionResponse.Items().Slice() {
+		labels = append(labels, label{Scope: scopeSubscription, Key: l.Key(), Value: l.Value(), Internal: l.Internal()})
+	}
+
+	clusterResponse, err := c.clusterLabels().List().Size(100).Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the labels of cluster %s: %w", c.cluster.ID(), err)
+	}
+	for _, l := range clusterResponse.Items().Slice() {
+		labels = append(labels, label{Scope: scopeCluster, ID: l.ID(), Key: l.Key(), Value: l.Value()})
+	}
+
+	sort.SliceStable(labels, func(i, j int) bool {
+		if labels[i].Scope != labels[j].Scope {
+			return labels[i].Scope > labels[j].Scope
+		}
+		return labels[i].Key < labels[j].Key
+	})
+	return labels, nil
+}
+
+func (c *ocmLabelClient) setLabel(l label, existing *label) error {
+	if l.Scope == scopeSubscription {
+		body, err := amv1.NewLabel().Key(l.Key).Value(l.Value).Internal(l.Internal).Build()
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			_, err = c.subscriptionLabels().Label(l.Key).Update().Body(body).Send()
+		} else {
+			_, err = c.subscriptionLabels().Add().Body(body).Send()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set label %s of subscription %s: %w", l.Key, c.cluster.Subscription().ID(), err)
+		}
+		return nil
+	}
+
+	body, err := cmv1.NewLabel().Key(l.Key).Value(l.Value).Build()
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		_, err = c.clusterLabels().Label(existing.ID).Update().Body(body).Send()
+	} else {
+		_, err = c.clusterLabels().Add().Body(body).Send()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set label %s of cluster %s: %w", l.Key, c.cluster.ID(), err)
+	}
+	return nil
+}
+
+func (c *ocmLabelClient) deleteLabel(l label) error {
+	if l.Scope == scopeSubscription {
+		if _, err := c.subscriptionLabels().Label(l.Key).Delete().Send(); err != nil {
+			return fmt.Errorf("failed to delete label %s of subscription %s: %w", l.Key, c.cluster.Subscription().ID(), err)
+		}
+		return nil
+	}
+	if _, err := c.clusterLabels().Label(l.ID).Delete().Send(); err != nil {
+		return fmt.Errorf("failed to delete label %s of cluster %s: %w", l.Key, c.cluster.ID(), err)
+	}
+	return nil
+}
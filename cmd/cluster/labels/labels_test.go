@@ -0,0 +1,76 @@
+package labels
+
+import (
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLabelClient struct {
+	labels  []label
+	sets    []label
+	deletes []label
+}
+
+func (c *fakeLabelClient) listLabels() ([]label, error) {
+	return c.labels, nil
+}
+
+func (c *fakeLabelClient) setLabel(l label, _ *label) error {
+	c.sets = append(c.sets, l)
+	return nil
+}
+
+func (c *fakeLabelClient) deleteLabel(l label) error {
+	c.deletes = append(c.deletes, l)
+	return nil
+}
+
+func newFakeLabelClient() *fakeLabelClient {
+	return &fakeLabelClient{labels: []label{
+		{Scope: scopeSubscription, Key: "dynatrace.regional-tenant", Value: "abc12345", Internal: true},
+		{Scope: scopeSubscription, Key: "example.openshift.io/feature", Value: "enabled"},
+		{Scope: scopeCluster, ID: "label-id", Key: "ext-hypershift.openshift.io/cluster-type", Value: "management-cluster"},
+	}}
+}
+
+func newTestCluster(t *testing.T) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID("cluster-id").Name("hs-mc-1").Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func TestValidateKey(t *testing.T) {
+	assert.NoError(t, validateKey(scopeSubscription, "dynatrace.regional-tenant"))
+	assert.NoError(t, validateKey(scopeCluster, "ext-example.openshift.io/feature"))
+	assert.EqualError(t, validateKey(scopeCluster, "dynatrace.regional-tenant"),
+		"label dynatrace.regional-tenant is a subscription label, not a cluster label")
+	assert.ErrorContains(t, validateKey(scopeSubscription, "not a key"), `invalid label key "not a key"`)
+}
+
+func TestValidateValue(t *testing.T) {
+	assert.NoError(t, validateValue("dynatrace.regional-tenant", "abc12345"))
+	assert.EqualError(t, validateValue("dynatrace.regional-tenant", "https://abc12345.apps.dynatrace.com"),
+		`invalid value "https://abc12345.apps.dynatrace.com" for label dynatrace.regional-tenant, expected the ID of the Dynatrace tenant of the cluster, e.g. abc12345`)
+	assert.NoError(t, validateValue("ext-hypershift.openshift.io/cluster-type", "service-cluster"))
+	assert.EqualError(t, validateValue("ext-hypershift.openshift.io/cluster-type", "mc"),
+		`invalid value "mc" for label ext-hypershift.openshift.io/cluster-type, must be one of: management-cluster, service-cluster`)
+	assert.NoError(t, validateValue("example.openshift.io/feature", "anything goes"))
+	assert.ErrorContains(t, validateValue("example.openshift.io/feature", ""), "can't be empty")
+}
+
+func TestResolveScope(t *testing.T) {
+	s, err := resolveScope("ext-hypershift.openshift.io/cluster-type", "subscription", false)
+	require.NoError(t, err)
+	assert.Equal(t, scopeCluster, s, "a known label defaults to its scope")
+
+	s, err = resolveScope("example.openshift.io/feature", "cluster", true)
+	require.NoError(t, err)
+	assert.Equal(t, scopeCluster, s)
+
+	_, err = resolveScope("example.openshift.io/feature", "organization", true)
+	assert.EqualError(t, err, `invalid scope "organization", must be one of: subscription, cluster`)
+}
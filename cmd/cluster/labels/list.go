@@ -0,0 +1,92 @@
+package labels
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type listOptions struct {
+	clusterID string
+	scope     string
+
+	out    io.Writer
+	client labelClient
+}
+
+// newCmdList implements labels list
+func newCmdList() *cobra.Command {
+	ops := &listOptions{out: os.Stdout}
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the labels of a cluster and of its subscription",
+		Example: `  # List the labels of a cluster and of its subscription
+  osdctl cluster labels list --cluster-id ${CLUSTER_ID}
+
+  # Only list the labels of the subscription
+  osdctl cluster labels list --cluster-id ${CLUSTER_ID} --scope subscription`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	listCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID of the labels")
+	listCmd.Flags().StringVar(&ops.scope, "scope", "", "Only list the labels of this scope: subscription or cluster")
+
+	_ = listCmd.MarkFlagRequired("cluster-id")
+
+	return listCmd
+}
+
+func (o *listOptions) complete() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	if o.scope != "" {
+		if _, err := parseScope(o.scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *listOptions) run() error {
+	if o.client == nil {
+		conn, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if o.client, _, err = newOCMLabelClient(conn, o.clusterID); err != nil {
+			return err
+		}
+	}
+
+	labels, err := o.client.listLabels()
+	if err != nil {
+		return err
+	}
+
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"SCOPE", "KEY", "VALUE", "INTERNAL", "KNOWN"})
+	for _, l := range labels {
+		if o.scope != "" && string(l.Scope) != o.scope {
+			continue
+		}
+		internal := "-"
+		if l.Scope == scopeSubscription {
+			internal = strconv.FormatBool(l.Internal)
+		}
+		_, known := findSchema(l.Key)
+		table.AddRow([]string{string(l.Scope), l.Key, l.Value, internal, strconv.FormatBool(known)})
+	}
+	return table.Flush()
+}
@@ -0,0 +1,26 @@
+package labels
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	opts := &listOptions{clusterID: "cluster-id", out: out, client: newFakeLabelClient()}
+
+	require.NoError(t, opts.run())
+	assert.Regexp(t, `SCOPE +KEY +VALUE +INTERNAL +KNOWN`, out.String())
+	assert.Regexp(t, `subscription +dynatrace.regional-tenant +abc12345 +true +true`, out.String())
+	assert.Regexp(t, `subscription +example.openshift.io/feature +enabled +false +false`, out.String())
+	assert.Regexp(t, `cluster +ext-hypershift.openshift.io/cluster-type +management-cluster +- +true`, out.String())
+
+	out.Reset()
+	opts.scope = "cluster"
+	require.NoError(t, opts.run())
+	assert.NotContains(t, out.String(), "dynatrace.regional-tenant")
+	assert.Contains(t, out.String(), "ext-hypershift.openshift.io/cluster-type")
+}
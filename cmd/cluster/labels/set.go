@@ -0,0 +1,164 @@
+package labels
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type setOptions struct {
+	clusterID string
+	key       string
+	value     string
+	scope     string
+	internal  bool
+
+	// label is the label to set, resolved from the flags
+	label label
+
+	out     io.Writer
+	client  labelClient
+	cluster *cmv1.Cluster
+}
+
+// newCmdSet implements labels set
+func newCmdSet() *cobra.Command {
+	ops := &setOptions{out: os.Stdout}
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Create or update a label of a cluster or of its subscription",
+		Long: `Create or update a label of a cluster or of its subscription.
+
+The scope of a known label is the one it is read from, other labels are subscription labels unless --scope is set.
+The value of a known label is validated, and the change and its downstream effects are shown for confirmation.`,
+		Example: `  # Set the Dynatrace tenant of a cluster
+  osdctl cluster labels set --cluster-id ${CLUSTER_ID} --key dynatrace.regional-tenant --value abc12345
+
+  # Set an external configuration label of a cluster
+  osdctl cluster labels set --cluster-id ${CLUSTER_ID} --key ext-example.openshift.io/feature --value enabled --scope cluster`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete(cmd))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	setCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID of the label")
+	setCmd.Flags().StringVar(&ops.key, "key", "", "Key of the label")
+	setCmd.Flags().StringVar(&ops.value, "value", "", "Value of the label")
+	setCmd.Flags().StringVar(&ops.scope, "scope", string(scopeSubscription), "Scope of the label: subscription or cluster, the scope of a known label by default")
+	setCmd.Flags().BoolVar(&ops.internal, "internal", true, "Hide the subscription label from the customer, the visibility of a known label by default")
+
+	_ = setCmd.MarkFlagRequired("cluster-id")
+	_ = setCmd.MarkFlagRequired("key")
+	_ = setCmd.MarkFlagRequired("value")
+
+	return danger.Annotate(setCmd, danger.Medium)
+}
+
+func (o *setOptions) complete(cmd *cobra.Command) error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	s, err := resolveScope(o.key, o.scope, cmd.Flags().Changed("scope"))
+	if err != nil {
+		return err
+	}
+	if err := validateKey(s, o.key); err != nil {
+		return err
+	}
+	if err := validateValue(o.key, o.value); err != nil {
+		return err
+	}
+
+	o.label = label{Scope: s, Key: o.key, Value: o.value, Internal: o.internal && s == scopeSubscription}
+	if schema, ok := findSchema(o.key); ok && s == scopeSubscription {
+		if cmd.Flags().Changed("internal") && o.internal != schema.Internal {
+			return fmt.Errorf("label %s must have --internal=%t", o.key, schema.Internal)
+		}
+		o.label.Internal = schema.Internal
+	}
+	return nil
+}
+
+// resolveScope returns the scope of the flag, or the scope of a known label when the flag isn't set
+func resolveScope(key, flag string, flagSet bool) (scope, error) {
+	if schema, ok := findSchema(key); ok && !flagSet {
+		return schema.Scope, nil
+	}
+	return parseScope(flag)
+}
+
+func (o *setOptions) run() error {
+	if o.client == nil {
+		conn, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if o.client, o.cluster, err = newOCMLabelClient(conn, o.clusterID); err != nil {
+			return err
+		}
+	}
+
+	labels, err := o.client.listLabels()
+	if err != nil {
+		return err
+	}
+	var existing *label
+	if l, ok := findLabel(labels, o.label.Scope, o.label.Key); ok {
+		existing = &l
+		if l.Value == o.label.Value && l.Internal == o.label.Internal {
+			fmt.Fprintf(o.out, "The %s label %s of cluster %s is already set to %q, nothing to do\n", o.label.Scope, o.label.Key, o.cluster.ID(), l.Value)
+			return nil
+		}
+		fmt.Fprintf(o.out, "The %s label %s of cluster %s (%s) will be changed from %q to %q\n",
+			o.label.Scope, o.label.Key, o.cluster.Name(), o.cluster.ID(), l.Value, o.label.Value)
+	} else {
+		fmt.Fprintf(o.out, "The %s label %s of cluster %s (%s) will be created with value %q\n",
+			o.label.Scope, o.label.Key, o.cluster.Name(), o.cluster.ID(), o.label.Value)
+	}
+	if o.label.Scope == scopeSubscription && (existing == nil || existing.Internal != o.label.Internal) {
+		fmt.Fprintf(o.out, "The label will be %s\n", visibility(o.label.Internal))
+	}
+	printEffects(o.out, o.label.Key)
+
+	if ok, err := danger.Confirm(danger.Medium, o.cluster); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("aborting the label change")
+	}
+	if err := o.client.setLabel(o.label, existing); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "The %s label %s is now set to %q\n", o.label.Scope, o.label.Key, o.label.Value)
+	return nil
+}
+
+func visibility(internal bool) string {
+	if internal {
+		return "internal, hidden from the customer"
+	}
+	return "visible to the customer"
+}
+
+// printEffects describes what depends on the label, so the change can be weighed before confirming it
+func printEffects(out io.Writer, key string) {
+	schema, ok := findSchema(key)
+	if !ok {
+		fmt.Fprintf(out, "%s isn't a label known to osdctl, its value isn't validated and its downstream effects are unknown\n", key)
+		return
+	}
+	fmt.Fprintf(out, "%s is %s. Downstream effects:\n", key, schema.Description)
+	for _, effect := range schema.Effects {
+		fmt.Fprintf(out, "  - %s\n", effect)
+	}
+}
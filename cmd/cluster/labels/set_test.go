@@ -0,0 +1,80 @@
+package labels
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetComplete(t *testing.T) {
+	t.Run("known label takes its scope and visibility", func(t *testing.T) {
+		cmd := newCmdSet()
+		require.NoError(t, cmd.ParseFlags([]string{"-C", "cluster-id", "--key", "dynatrace.regional-tenant", "--value", "def67890", "--internal=true"}))
+		opts := &setOptions{clusterID: "cluster-id", key: "dynatrace.regional-tenant", value: "def67890", scope: "subscription", internal: true}
+
+		require.NoError(t, opts.complete(cmd))
+		assert.Equal(t, label{Scope: scopeSubscription, Key: "dynatrace.regional-tenant", Value: "def67890", Internal: true}, opts.label)
+	})
+
+	t.Run("known label visibility can't be changed", func(t *testing.T) {
+		cmd := newCmdSet()
+		require.NoError(t, cmd.ParseFlags([]string{"--internal=false"}))
+		opts := &setOptions{clusterID: "cluster-id", key: "dynatrace.regional-tenant", value: "def67890", scope: "subscription"}
+
+		assert.EqualError(t, opts.complete(cmd), "label dynatrace.regional-tenant must have --internal=true")
+	})
+
+	t.Run("invalid value of a known label", func(t *testing.T) {
+		opts := &setOptions{clusterID: "cluster-id", key: "ext-hypershift.openshift.io/cluster-type", value: "mc", scope: "subscription"}
+		assert.ErrorContains(t, opts.complete(newCmdSet()), "must be one of: management-cluster, service-cluster")
+	})
+}
+
+func TestSetRun(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader(""), io.Discard)
+	defer restore()
+	prompt.SetAssumeYes(true)
+	defer prompt.SetAssumeYes(false)
+
+	t.Run("updates a known label and shows its effects", func(t *testing.T) {
+		client := newFakeLabelClient()
+		out := &bytes.Buffer{}
+		opts := &setOptions{label: label{Scope: scopeSubscription, Key: "dynatrace.regional-tenant", Value: "def67890", Internal: true},
+			out: out, client: client, cluster: newTestCluster(t)}
+
+		require.NoError(t, opts.run())
+		assert.Equal(t, []label{opts.label}, client.sets)
+		assert.Contains(t, out.String(), `The subscription label dynatrace.regional-tenant of cluster hs-mc-1 (cluster-id) will be changed from "abc12345" to "def67890"`)
+		assert.Contains(t, out.String(), "dynatrace.regional-tenant is the ID of the Dynatrace tenant of the cluster, e.g. abc12345. Downstream effects:\n  - osdctl dynatrace commands query")
+		assert.NotContains(t, out.String(), "The label will be")
+	})
+
+	t.Run("creates an unknown label", func(t *testing.T) {
+		client := newFakeLabelClient()
+		out := &bytes.Buffer{}
+		opts := &setOptions{label: label{Scope: scopeSubscription, Key: "example.openshift.io/other", Value: "on", Internal: true},
+			out: out, client: client, cluster: newTestCluster(t)}
+
+		require.NoError(t, opts.run())
+		assert.Len(t, client.sets, 1)
+		assert.Contains(t, out.String(), `will be created with value "on"`)
+		assert.Contains(t, out.String(), "The label will be internal, hidden from the customer")
+		assert.Contains(t, out.String(), "isn't a label known to osdctl")
+	})
+
+	t.Run("nothing to do", func(t *testing.T) {
+		client := newFakeLabelClient()
+		out := &bytes.Buffer{}
+		opts := &setOptions{label: label{Scope: scopeSubscription, Key: "dynatrace.regional-tenant", Value: "abc12345", Internal: true},
+			out: out, client: client, cluster: newTestCluster(t)}
+
+		require.NoError(t, opts.run())
+		assert.Empty(t, client.sets)
+		assert.Contains(t, out.String(), "already set")
+	})
+}
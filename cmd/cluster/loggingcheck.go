@@ -1,14 +1,34 @@
 package cluster
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	onv "github.com/openshift/osd-network-verifier/pkg/verifier"
+	"github.com/openshift/osdctl/cmd/network"
 	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/prompt"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
 const loggingLabel string = "ext-managed.openshift.io/extended-logging-support"
@@ -19,6 +39,8 @@ type loggingCheckOptions struct {
 	output    string
 	verbose   bool
 	clusterID string
+	fix       bool
+	reason    string
 	genericclioptions.IOStreams
 	GlobalOptions *globalflags.GlobalOptions
 }
@@ -29,8 +51,22 @@ func newCmdLoggingCheck(streams genericclioptions.IOStreams, globalOpts *globalf
 	loggingCheckCmd := &cobra.Command{
 		Use:   "logging-check --cluster-id <cluster-identifier>",
 		Short: "Shows the logging support status of a specified cluster",
+		Long: `Shows the logging support status of a specified cluster
+
+  Besides the SREP support status, the ClusterLogForwarders of the cluster are inspected for
+  common misconfigurations: failing status conditions, unhealthy collector pods, outputs with
+  malformed URLs and pipelines referencing undefined outputs.
+
+  With --fix, guided remediations are offered for the issues found and each one is confirmed
+  before being applied as backplane-cluster-admin with the given --reason:
+    - restarting unhealthy collector pods
+    - patching known-bad fields of the ClusterLogForwarder
+    - verifying the output endpoints are reachable from the cluster using the egress verifier`,
 		Example: `  # Check logging support status for a cluster
-  osdctl cluster logging-check --cluster-id ${CLUSTER_ID}`,
+  osdctl cluster logging-check --cluster-id ${CLUSTER_ID}
+
+  # Check the log forwarding configuration and apply guided remediations
+  osdctl cluster logging-check --cluster-id ${CLUSTER_ID} --fix --reason "OHSS-1234"`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -40,6 +76,8 @@ func newCmdLoggingCheck(streams genericclioptions.IOStreams, globalOpts *globalf
 	}
 	loggingCheckCmd.Flags().BoolVarP(&ops.verbose, "verbose", "", false, "Verbose output")
 	loggingCheckCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "The internal ID of the cluster to check (required)")
+	loggingCheckCmd.Flags().BoolVar(&ops.fix, "fix", false, "Offer guided remediations for the log forwarding issues found")
+	loggingCheckCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for elevation to apply remediations (usually an OHSS or PD ticket), required with --fix")
 	cmdutil.CheckErr(loggingCheckCmd.MarkFlagRequired("cluster-id"))
 
 	return loggingCheckCmd
@@ -53,6 +91,10 @@ func newloggingCheckOptions(streams genericclioptions.IOStreams, globalOpts *glo
 }
 
 func (o *loggingCheckOptions) complete(cmd *cobra.Command, args []string) error {
+	if o.fix && o.reason == "" {
+		return fmt.Errorf("--reason is required with --fix")
+	}
+
 	// Create an OCM client to talk to the cluster API
 	// the user has to be logged in (e.g. 'ocm login')
 	ocmClient, err := utils.CreateConnection()
@@ -95,22 +137,474 @@ func (o *loggingCheckOptions) run() error {
 		os.Exit(1)
 	}
 
-	labels, ok := response.GetItems()
-	// If there are no labels, then logging is not SREP supported
-	if !ok {
+	if isLoggingSupported(response.Items().Slice()) {
+		fmt.Printf("Cluster logging SREP supported for the target cluster\n")
+	} else {
 		fmt.Printf("Cluster logging not SREP supported\n")
+	}
+
+	return o.checkForwarders(context.Background())
+}
+
+// isLoggingSupported returns whether the logging label is set, in which case it's an SREP supported logging stack
+func isLoggingSupported(labels []*cmv1.Label) bool {
+	for _, label := range labels {
+		if l, ok := label.GetKey(); ok && l == loggingLabel {
+			return true
+		}
+	}
+	return false
+}
+
+// checkForwarders inspects the ClusterLogForwarders of the cluster and, with --fix, offers remediations
+func (o *loggingCheckOptions) checkForwarders(ctx context.Context) error {
+	kubeClient, err := k8s.New(o.clusterID, client.Options{})
+	if err != nil {
+		return o.inspectionFailed(err)
+	}
+
+	forwarders, err := listClusterLogForwarders(ctx, kubeClient)
+	if err != nil {
+		return o.inspectionFailed(err)
+	}
+	if len(forwarders) == 0 {
+		fmt.Fprintln(o.Out, "No ClusterLogForwarders found")
 		return nil
 	}
 
-	for _, label := range labels.Slice() {
-		if l, ok := label.GetKey(); ok {
-			// If the label is found as the key, we know its an SREP supported logging stack
-			if l == loggingLabel {
-				fmt.Printf("Cluster logging SREP supported for the target cluster\n")
-				return nil
-			}
+	var issues []loggingIssue
+	var endpoints []logEndpoint
+	for i := range forwarders {
+		pods, err := listCollectorPods(ctx, kubeClient, &forwarders[i])
+		if err != nil {
+			return o.inspectionFailed(err)
 		}
+		issues = append(issues, inspectClusterLogForwarder(&forwarders[i], pods)...)
+		endpoints = append(endpoints, forwarderEndpoints(&forwarders[i])...)
+	}
+	printLoggingIssues(o.Out, forwarders, issues, o.verbose)
+
+	if !o.fix {
+		if slices.ContainsFunc(issues, func(issue loggingIssue) bool { return issue.remediation != nil }) {
+			fmt.Fprintln(o.Out, "Run with --fix --reason <reason> to apply the suggested remediations")
+		}
+		return nil
+	}
+
+	elevatedClient, err := k8s.NewAsBackplaneClusterAdmin(o.clusterID, client.Options{}, o.reason,
+		"Remediating log forwarding with osdctl cluster logging-check --fix")
+	if err != nil {
+		return err
+	}
+	if err := applyLoggingRemediations(ctx, elevatedClient, o.Out, issues); err != nil {
+		return err
+	}
+
+	if len(endpoints) > 0 && prompt.Confirm(fmt.Sprintf("Verify %d log forwarding endpoint(s) are reachable from the cluster with the egress verifier?", len(endpoints)), false) {
+		return o.verifyEndpoints(ctx, endpoints)
+	}
+	return nil
+}
+
+// inspectionFailed reports ClusterLogForwarders that can't be inspected, which is only fatal with --fix
+func (o *loggingCheckOptions) inspectionFailed(err error) error {
+	if o.fix {
+		return fmt.Errorf("failed to inspect the ClusterLogForwarders: %w", err)
+	}
+	fmt.Fprintf(o.ErrOut, "Unable to inspect the ClusterLogForwarders: %v\n", err)
+	return nil
+}
+
+// verifyEndpoints runs the egress verifier in pod mode against the output endpoints of the forwarders
+func (o *loggingCheckOptions) verifyEndpoints(ctx context.Context, endpoints []logEndpoint) error {
+	egressList, err := egressListYaml(endpoints)
+	if err != nil {
+		return err
 	}
-	fmt.Printf("Cluster logging not SREP supported\n")
+
+	verification := &network.EgressVerification{
+		ClusterId:      o.clusterID,
+		PodMode:        true,
+		Reason:         o.reason,
+		Namespace:      "openshift-network-diagnostics",
+		Probe:          "curl",
+		CpuArchName:    "x86",
+		EgressTimeout:  onv.DefaultTimeout,
+		SkipServiceLog: true,
+		EgressListYaml: egressList,
+	}
+	verification.Run(ctx)
 	return nil
 }
+
+var clusterLogForwarderGVKs = []schema.GroupVersionKind{
+	{Group: "observability.openshift.io", Version: "v1", Kind: "ClusterLogForwarder"},
+	{Group: "logging.openshift.io", Version: "v1", Kind: "ClusterLogForwarder"},
+}
+
+const (
+	collectorComponentLabel = "app.kubernetes.io/component"
+	collectorInstanceLabel  = "app.kubernetes.io/instance"
+	// collectorRestartThreshold is the number of container restarts after which a collector pod is considered unhealthy
+	collectorRestartThreshold = 5
+)
+
+// loggingIssue is a misconfiguration of a ClusterLogForwarder, with an optional guided remediation
+type loggingIssue struct {
+	forwarder   string
+	description string
+	remediation *loggingRemediation
+}
+
+// loggingRemediation fixes a loggingIssue once confirmed by the user
+type loggingRemediation struct {
+	description string
+	apply       func(ctx context.Context, c client.Client) error
+}
+
+// logEndpoint is an output endpoint in osd-network-verifier's egress list format
+type logEndpoint struct {
+	Host  string `json:"host"`
+	Ports []int  `json:"ports"`
+}
+
+// listClusterLogForwarders lists the ClusterLogForwarders of both the observability and the legacy logging API,
+// ignoring APIs which are not installed on the cluster
+func listClusterLogForwarders(ctx context.Context, c client.Client) ([]unstructured.Unstructured, error) {
+	var forwarders []unstructured.Unstructured
+	for _, gvk := range clusterLogForwarderGVKs {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list); err != nil {
+			if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", gvk.GroupVersion().WithResource("clusterlogforwarders").GroupResource(), err)
+		}
+		forwarders = append(forwarders, list.Items...)
+	}
+	return forwarders, nil
+}
+
+// listCollectorPods lists the collector pods deployed for a ClusterLogForwarder
+func listCollectorPods(ctx context.Context, c client.Client, forwarder *unstructured.Unstructured) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(forwarder.GetNamespace()), client.MatchingLabels{
+		collectorComponentLabel: "collector",
+		collectorInstanceLabel:  forwarder.GetName(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list the collector pods of %s: %w", forwarderName(forwarder), err)
+	}
+	return pods.Items, nil
+}
+
+func forwarderName(forwarder *unstructured.Unstructured) string {
+	return forwarder.GetNamespace() + "/" + forwarder.GetName()
+}
+
+// inspectClusterLogForwarder returns the issues found in the ClusterLogForwarder and its collector pods
+func inspectClusterLogForwarder(forwarder *unstructured.Unstructured, pods []corev1.Pod) []loggingIssue {
+	name := forwarderName(forwarder)
+	var issues []loggingIssue
+
+	conditions, _, _ := unstructured.NestedSlice(forwarder.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] != "False" {
+			continue
+		}
+		issues = append(issues, loggingIssue{
+			forwarder:   name,
+			description: fmt.Sprintf("condition %v is False: %v %v", condition["type"], condition["reason"], condition["message"]),
+		})
+	}
+
+	if unhealthy := unhealthyCollectorPods(pods); len(unhealthy) > 0 {
+		issues = append(issues, loggingIssue{
+			forwarder:   name,
+			description: fmt.Sprintf("collector pods are unhealthy: %s", strings.Join(unhealthy, ", ")),
+			remediation: restartCollectorPods(forwarder.GetNamespace(), unhealthy),
+		})
+	}
+
+	outputs, _, _ := unstructured.NestedSlice(forwarder.Object, "spec", "outputs")
+	outputNames := map[string]bool{}
+	needsTrim := false
+	for _, o := range outputs {
+		output, ok := o.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		outputName, _ := output["name"].(string)
+		outputNames[outputName] = true
+
+		rawURL, path := outputURL(output)
+		if path == nil {
+			continue
+		}
+		if trimmed := strings.TrimSpace(rawURL); trimmed != rawURL {
+			needsTrim = true
+			issues = append(issues, loggingIssue{
+				forwarder:   name,
+				description: fmt.Sprintf("output %s has leading or trailing whitespace in its URL %q", outputName, rawURL),
+			})
+			rawURL = trimmed
+		}
+		if u, err := url.Parse(rawURL); err != nil || u.Scheme == "" || u.Host == "" {
+			issues = append(issues, loggingIssue{
+				forwarder:   name,
+				description: fmt.Sprintf("output %s has an invalid URL %q, expected <scheme>://<host>[:<port>]", outputName, rawURL),
+			})
+		}
+	}
+	if needsTrim {
+		issues[len(issues)-1].remediation = patchForwarder(forwarder, "trim the whitespace from the output URLs", trimOutputURLs)
+	}
+
+	pipelines, _, _ := unstructured.NestedSlice(forwarder.Object, "spec", "pipelines")
+	dangling := false
+	for _, p := range pipelines {
+		pipeline, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		refs, _, _ := unstructured.NestedStringSlice(pipeline, "outputRefs")
+		for _, ref := range refs {
+			// "default" is the implicit log store output of the legacy logging API
+			if outputNames[ref] || ref == "default" {
+				continue
+			}
+			dangling = true
+			issues = append(issues, loggingIssue{
+				forwarder:   name,
+				description: fmt.Sprintf("pipeline %v references the undefined output %s", pipeline["name"], ref),
+			})
+		}
+	}
+	if dangling {
+		issues[len(issues)-1].remediation = patchForwarder(forwarder, "remove the undefined outputs from the pipelines", removeUndefinedOutputRefs)
+	}
+
+	return issues
+}
+
+// unhealthyCollectorPods returns the names of the collector pods which aren't ready or restart repeatedly
+func unhealthyCollectorPods(pods []corev1.Pod) []string {
+	var unhealthy []string
+	for _, pod := range pods {
+		healthy := pod.Status.Phase == corev1.PodRunning
+		for _, status := range pod.Status.ContainerStatuses {
+			if !status.Ready || status.RestartCount >= collectorRestartThreshold {
+				healthy = false
+			}
+		}
+		if !healthy {
+			unhealthy = append(unhealthy, pod.Name)
+		}
+	}
+	return unhealthy
+}
+
+// outputURL returns the URL of an output and its path within the output. Legacy outputs have a top-level
+// url field, observability outputs nest it under their type, e.g. http.url.
+func outputURL(output map[string]interface{}) (string, []string) {
+	if rawURL, ok := output["url"].(string); ok {
+		return rawURL, []string{"url"}
+	}
+	outputType, _ := output["type"].(string)
+	if rawURL, found, _ := unstructured.NestedString(output, outputType, "url"); found {
+		return rawURL, []string{outputType, "url"}
+	}
+	return "", nil
+}
+
+// forwarderEndpoints returns the host and port of the outputs which can be checked by the egress verifier
+func forwarderEndpoints(forwarder *unstructured.Unstructured) []logEndpoint {
+	outputs, _, _ := unstructured.NestedSlice(forwarder.Object, "spec", "outputs")
+	var endpoints []logEndpoint
+	for _, o := range outputs {
+		output, ok := o.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rawURL, _ := outputURL(output)
+		u, err := url.Parse(strings.TrimSpace(rawURL))
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+
+		port := 0
+		switch u.Scheme {
+		case "https", "tls":
+			port = 443
+		case "http":
+			port = 80
+		case "tcp":
+		default:
+			// UDP and other protocols aren't supported by the egress verifier
+			continue
+		}
+		if u.Port() != "" {
+			port, err = strconv.Atoi(u.Port())
+			if err != nil {
+				continue
+			}
+		}
+		if port == 0 {
+			continue
+		}
+		endpoints = append(endpoints, logEndpoint{Host: u.Hostname(), Ports: []int{port}})
+	}
+	return endpoints
+}
+
+// egressListYaml renders the endpoints as an osd-network-verifier egress list
+func egressListYaml(endpoints []logEndpoint) (string, error) {
+	out, err := yaml.Marshal(map[string][]logEndpoint{"endpoints": endpoints})
+	if err != nil {
+		return "", fmt.Errorf("failed to render the egress list: %w", err)
+	}
+	return string(out), nil
+}
+
+// restartCollectorPods deletes the given collector pods, which are recreated by their daemonset
+func restartCollectorPods(namespace string, names []string) *loggingRemediation {
+	return &loggingRemediation{
+		description: fmt.Sprintf("restart the collector pods %s", strings.Join(names, ", ")),
+		apply: func(ctx context.Context, c client.Client) error {
+			for _, name := range names {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+				if err := c.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+					return fmt.Errorf("failed to delete pod %s/%s: %w", namespace, name, err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// patchForwarder patches the ClusterLogForwarder with the changes made by mutate
+func patchForwarder(forwarder *unstructured.Unstructured, description string, mutate func(*unstructured.Unstructured) error) *loggingRemediation {
+	return &loggingRemediation{
+		description: fmt.Sprintf("patch %s to %s", forwarderName(forwarder), description),
+		apply: func(ctx context.Context, c client.Client) error {
+			patched := forwarder.DeepCopy()
+			if err := mutate(patched); err != nil {
+				return err
+			}
+			if err := c.Patch(ctx, patched, client.MergeFrom(forwarder)); err != nil {
+				return fmt.Errorf("failed to patch %s: %w", forwarderName(forwarder), err)
+			}
+			return nil
+		},
+	}
+}
+
+// trimOutputURLs trims the whitespace from the URLs of the outputs
+func trimOutputURLs(forwarder *unstructured.Unstructured) error {
+	outputs, _, err := unstructured.NestedSlice(forwarder.Object, "spec", "outputs")
+	if err != nil {
+		return err
+	}
+	for _, o := range outputs {
+		output, ok := o.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if rawURL, path := outputURL(output); path != nil {
+			if err := unstructured.SetNestedField(output, strings.TrimSpace(rawURL), path...); err != nil {
+				return err
+			}
+		}
+	}
+	return unstructured.SetNestedSlice(forwarder.Object, outputs, "spec", "outputs")
+}
+
+// removeUndefinedOutputRefs removes the references to undefined outputs from the pipelines, refusing to leave
+// a pipeline without any output
+func removeUndefinedOutputRefs(forwarder *unstructured.Unstructured) error {
+	outputs, _, err := unstructured.NestedSlice(forwarder.Object, "spec", "outputs")
+	if err != nil {
+		return err
+	}
+	defined := map[string]bool{"default": true}
+	for _, o := range outputs {
+		if output, ok := o.(map[string]interface{}); ok {
+			if name, ok := output["name"].(string); ok {
+				defined[name] = true
+			}
+		}
+	}
+
+	pipelines, _, err := unstructured.NestedSlice(forwarder.Object, "spec", "pipelines")
+	if err != nil {
+		return err
+	}
+	for _, p := range pipelines {
+		pipeline, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		refs, _, _ := unstructured.NestedStringSlice(pipeline, "outputRefs")
+		var kept []interface{}
+		for _, ref := range refs {
+			if defined[ref] {
+				kept = append(kept, ref)
+			}
+		}
+		if len(kept) == 0 {
+			return fmt.Errorf("pipeline %v only references undefined outputs, its outputs need to be fixed manually", pipeline["name"])
+		}
+		pipeline["outputRefs"] = kept
+	}
+	return unstructured.SetNestedSlice(forwarder.Object, pipelines, "spec", "pipelines")
+}
+
+// printLoggingIssues prints the issues found per ClusterLogForwarder
+func printLoggingIssues(w io.Writer, forwarders []unstructured.Unstructured, issues []loggingIssue, verbose bool) {
+	for i := range forwarders {
+		name := forwarderName(&forwarders[i])
+		var found []loggingIssue
+		for _, issue := range issues {
+			if issue.forwarder == name {
+				found = append(found, issue)
+			}
+		}
+
+		if len(found) == 0 {
+			if verbose {
+				fmt.Fprintf(w, "ClusterLogForwarder %s (%s): no issues found\n", name, forwarders[i].GetAPIVersion())
+			}
+			continue
+		}
+		fmt.Fprintf(w, "ClusterLogForwarder %s (%s):\n", name, forwarders[i].GetAPIVersion())
+		for _, issue := range found {
+			fmt.Fprintf(w, "  - %s\n", issue.description)
+			if issue.remediation != nil {
+				fmt.Fprintf(w, "    remediation: %s\n", issue.remediation.description)
+			}
+		}
+	}
+	if len(issues) == 0 {
+		fmt.Fprintln(w, "No log forwarding issues found")
+	}
+}
+
+// applyLoggingRemediations applies the remediations of the issues confirmed by the user
+func applyLoggingRemediations(ctx context.Context, c client.Client, w io.Writer, issues []loggingIssue) error {
+	var errs []error
+	for _, issue := range issues {
+		if issue.remediation == nil {
+			continue
+		}
+		if !prompt.Confirm(fmt.Sprintf("%s: %s?", issue.forwarder, issue.remediation.description), false) {
+			continue
+		}
+		if err := issue.remediation.apply(ctx, c); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(w, "Done: %s\n", issue.remediation.description)
+	}
+	return errors.Join(errs...)
+}
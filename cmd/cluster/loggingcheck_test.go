@@ -0,0 +1,162 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestForwarder() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "logging.openshift.io/v1",
+		"kind":       "ClusterLogForwarder",
+		"metadata":   map[string]interface{}{"name": "instance", "namespace": "openshift-logging"},
+		"spec": map[string]interface{}{
+			"outputs": []interface{}{
+				map[string]interface{}{"name": "splunk", "type": "splunk", "url": " https://splunk.example.com:8088 "},
+				map[string]interface{}{"name": "syslog", "type": "syslog", "url": "udp://syslog.example.com:514"},
+				map[string]interface{}{"name": "broken", "type": "http", "url": "logs.example.com"},
+			},
+			"pipelines": []interface{}{
+				map[string]interface{}{"name": "audit", "inputRefs": []interface{}{"audit"}, "outputRefs": []interface{}{"splunk", "cloudwatch"}},
+				map[string]interface{}{"name": "app", "inputRefs": []interface{}{"application"}, "outputRefs": []interface{}{"default"}},
+			},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "Invalid", "message": "invalid outputs"},
+			},
+		},
+	}}
+}
+
+func newTestCollectorPod(name string, ready bool, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-logging",
+			Labels:    map[string]string{collectorComponentLabel: "collector", collectorInstanceLabel: "instance"},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "collector", Ready: ready, RestartCount: restarts}},
+		},
+	}
+}
+
+func TestInspectClusterLogForwarder(t *testing.T) {
+	pods := []corev1.Pod{
+		*newTestCollectorPod("collector-healthy", true, 0),
+		*newTestCollectorPod("collector-crashing", true, 12),
+		*newTestCollectorPod("collector-notready", false, 0),
+	}
+
+	issues := inspectClusterLogForwarder(newTestForwarder(), pods)
+
+	var descriptions, remediations []string
+	for _, issue := range issues {
+		assert.Equal(t, "openshift-logging/instance", issue.forwarder)
+		descriptions = append(descriptions, issue.description)
+		if issue.remediation != nil {
+			remediations = append(remediations, issue.remediation.description)
+		}
+	}
+	assert.Equal(t, []string{
+		"condition Ready is False: Invalid invalid outputs",
+		"collector pods are unhealthy: collector-crashing, collector-notready",
+		`output splunk has leading or trailing whitespace in its URL " https://splunk.example.com:8088 "`,
+		`output broken has an invalid URL "logs.example.com", expected <scheme>://<host>[:<port>]`,
+		"pipeline audit references the undefined output cloudwatch",
+	}, descriptions)
+	assert.Equal(t, []string{
+		"restart the collector pods collector-crashing, collector-notready",
+		"patch openshift-logging/instance to trim the whitespace from the output URLs",
+		"patch openshift-logging/instance to remove the undefined outputs from the pipelines",
+	}, remediations)
+}
+
+func TestInspectObservabilityClusterLogForwarder(t *testing.T) {
+	forwarder := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "observability.openshift.io/v1",
+		"kind":       "ClusterLogForwarder",
+		"metadata":   map[string]interface{}{"name": "collector", "namespace": "logging"},
+		"spec": map[string]interface{}{
+			"outputs": []interface{}{
+				map[string]interface{}{"name": "http", "type": "http", "http": map[string]interface{}{"url": "http://collector.example.com"}},
+				map[string]interface{}{"name": "cw", "type": "cloudwatch", "cloudwatch": map[string]interface{}{"groupName": "logs"}},
+			},
+			"pipelines": []interface{}{
+				map[string]interface{}{"name": "all", "outputRefs": []interface{}{"http", "cw"}},
+			},
+		},
+	}}
+
+	assert.Empty(t, inspectClusterLogForwarder(forwarder, nil))
+	assert.Equal(t, []logEndpoint{{Host: "collector.example.com", Ports: []int{80}}}, forwarderEndpoints(forwarder))
+}
+
+func TestEgressListYaml(t *testing.T) {
+	endpoints := forwarderEndpoints(newTestForwarder())
+	assert.Equal(t, []logEndpoint{{Host: "splunk.example.com", Ports: []int{8088}}}, endpoints)
+
+	egressList, err := egressListYaml(endpoints)
+	require.NoError(t, err)
+	assert.Equal(t, "endpoints:\n- host: splunk.example.com\n  ports:\n  - 8088\n", egressList)
+}
+
+func TestApplyLoggingRemediations(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader(""), io.Discard)
+	defer restore()
+	prompt.SetAssumeYes(true)
+	defer prompt.SetAssumeYes(false)
+
+	forwarder := newTestForwarder()
+	crashing := newTestCollectorPod("collector-crashing", false, 12)
+	fakeClient := fake.NewClientBuilder().WithObjects(forwarder.DeepCopy(), crashing, newTestCollectorPod("collector-healthy", true, 0)).Build()
+
+	ctx := context.Background()
+	forwarders, err := listClusterLogForwarders(ctx, fakeClient)
+	require.NoError(t, err)
+	require.Len(t, forwarders, 1)
+	pods, err := listCollectorPods(ctx, fakeClient, &forwarders[0])
+	require.NoError(t, err)
+	require.Len(t, pods, 2)
+
+	out := &bytes.Buffer{}
+	issues := inspectClusterLogForwarder(&forwarders[0], pods)
+	require.NoError(t, applyLoggingRemediations(ctx, fakeClient, out, issues))
+	assert.Equal(t, 3, strings.Count(out.String(), "Done: "))
+
+	err = fakeClient.Get(ctx, client.ObjectKeyFromObject(crashing), &corev1.Pod{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: "openshift-logging", Name: "collector-healthy"}, &corev1.Pod{}))
+
+	patched := &unstructured.Unstructured{}
+	patched.SetGroupVersionKind(forwarder.GroupVersionKind())
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(forwarder), patched))
+	url, _, _ := unstructured.NestedString(patched.Object["spec"].(map[string]interface{})["outputs"].([]interface{})[0].(map[string]interface{}), "url")
+	assert.Equal(t, "https://splunk.example.com:8088", url)
+	refs, _, _ := unstructured.NestedStringSlice(patched.Object["spec"].(map[string]interface{})["pipelines"].([]interface{})[0].(map[string]interface{}), "outputRefs")
+	assert.Equal(t, []string{"splunk"}, refs)
+}
+
+func TestRemoveUndefinedOutputRefsKeepsPipelinesWithOutputs(t *testing.T) {
+	forwarder := newTestForwarder()
+	require.NoError(t, unstructured.SetNestedSlice(forwarder.Object, []interface{}{
+		map[string]interface{}{"name": "audit", "outputRefs": []interface{}{"cloudwatch"}},
+	}, "spec", "pipelines"))
+
+	assert.EqualError(t, removeUndefinedOutputRefs(forwarder), "pipeline audit only references undefined outputs, its outputs need to be fixed manually")
+}
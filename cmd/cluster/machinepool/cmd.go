@@ -0,0 +1,26 @@
+package machinepool
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdMachinePool implements the machinepool command to manage the machine pools of ROSA Classic and the node pools of HCP clusters
+// osdctl cluster machinepool list --cluster-id <cluster-id>
+func NewCmdMachinePool() *cobra.Command {
+	machinePoolCmd := &cobra.Command{
+		Use:               "machinepool",
+		Aliases:           []string{"machinepools", "mp"},
+		Short:             "Manage the machine pools of ROSA Classic and the node pools of HCP clusters",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	machinePoolCmd.AddCommand(newCmdList())
+	machinePoolCmd.AddCommand(newCmdScale())
+	machinePoolCmd.AddCommand(newCmdEditTaints())
+
+	return machinePoolCmd
+}
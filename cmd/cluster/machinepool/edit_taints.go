@@ -0,0 +1,161 @@
+package machinepool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type editTaintsOptions struct {
+	clusterID string
+	poolID    string
+	add       []string
+	remove    []string
+
+	out    io.Writer
+	client poolClient
+}
+
+// newCmdEditTaints implements machinepool edit-taints
+func newCmdEditTaints() *cobra.Command {
+	ops := &editTaintsOptions{out: os.Stdout}
+	editTaintsCmd := &cobra.Command{
+		Use:   "edit-taints",
+		Short: "Add or remove taints of a machine pool (node pool of HCP clusters)",
+		Long: `Adds taints to, or removes taints from, a machine pool of a ROSA Classic cluster or a node pool of an HCP cluster.
+
+Taints are added with the key=value:Effect syntax of "kubectl taint", the value being optional and the effect one of
+NoSchedule, PreferNoSchedule or NoExecute. Adding a taint with the key and effect of an existing one replaces its value.
+Taints are removed by key, or by key:Effect to only remove the taint with that effect.
+
+At least one pool of the cluster must remain without taints so the workloads can be scheduled.`,
+		Example: `  # Dedicate a machine pool to infra workloads
+  osdctl cluster machinepool edit-taints --cluster-id ${CLUSTER_ID} --machinepool infra --add node-role.kubernetes.io/infra=reserved:NoSchedule
+
+  # Remove a taint from a machine pool
+  osdctl cluster machinepool edit-taints --cluster-id ${CLUSTER_ID} --machinepool infra --remove node-role.kubernetes.io/infra`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(utils.ValidateClusterKey("cluster-id", ops.clusterID))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	editTaintsCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID of the machine pool")
+	editTaintsCmd.Flags().StringVarP(&ops.poolID, "machinepool", "m", "", "ID of the machine pool (node pool of HCP clusters) to edit")
+	editTaintsCmd.Flags().StringArrayVar(&ops.add, "add", nil, "Taint to add, as key=value:Effect (can be repeated)")
+	editTaintsCmd.Flags().StringArrayVar(&ops.remove, "remove", nil, "Key, or key:Effect, of the taint to remove (can be repeated)")
+
+	_ = editTaintsCmd.MarkFlagRequired("cluster-id")
+	_ = editTaintsCmd.MarkFlagRequired("machinepool")
+	editTaintsCmd.MarkFlagsOneRequired("add", "remove")
+
+	return editTaintsCmd
+}
+
+func (o *editTaintsOptions) run() error {
+	if o.client == nil {
+		conn, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		poolClient, cluster, err := newOCMPoolClient(conn, o.clusterID)
+		if err != nil {
+			return err
+		}
+		// The pools are looked up by the internal ID, -C also accepts the external ID and name of the cluster
+		o.client, o.clusterID = poolClient, cluster.ID()
+	}
+
+	pools, err := o.client.listPools(o.clusterID)
+	if err != nil {
+		return err
+	}
+	p, err := findPool(pools, o.poolID)
+	if err != nil {
+		return err
+	}
+	taints, err := editTaints(p.Taints, o.add, o.remove)
+	if err != nil {
+		return err
+	}
+	if slices.Equal(taints, p.Taints) {
+		fmt.Fprintf(o.out, "The taints of pool %s are unchanged, nothing to do\n", p.ID)
+		return nil
+	}
+	if len(taints) > 0 && !hasUntaintedPool(pools, p.ID) {
+		return fmt.Errorf("pool %s is the only pool of the cluster without taints, tainting it would leave no pool to schedule the workloads on", p.ID)
+	}
+
+	fmt.Fprintf(o.out, "The taints of pool %s of cluster %s will be changed\n  from: %s\n  to:   %s\n", p.ID, o.clusterID, formatTaints(p.Taints), formatTaints(taints))
+	if !prompt.ConfirmPrompt() {
+		return errors.New("aborting machine pool taints edit")
+	}
+	if err := o.client.updatePool(o.clusterID, poolUpdate{ID: p.ID, Taints: taints, SetTaints: true}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "The taints of pool %s are updated\n", p.ID)
+	return nil
+}
+
+// editTaints returns the taints once the taints to remove are removed and the ones to add are added or replaced
+func editTaints(current []taint, add []string, remove []string) ([]taint, error) {
+	taints := slices.Clone(current)
+	for _, r := range remove {
+		key, effect, withEffect := strings.Cut(r, ":")
+		if withEffect && !slices.Contains(taintEffects, effect) {
+			return nil, fmt.Errorf("invalid taint effect %q, expected one of %s", effect, strings.Join(taintEffects, ", "))
+		}
+		before := len(taints)
+		taints = slices.DeleteFunc(taints, func(t taint) bool {
+			return t.Key == key && (!withEffect || t.Effect == effect)
+		})
+		if len(taints) == before {
+			return nil, fmt.Errorf("no taint %s to remove", r)
+		}
+	}
+
+	for _, a := range add {
+		t, err := parseTaint(a)
+		if err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(taints, func(existing taint) bool { return existing.Key == t.Key && existing.Effect == t.Effect })
+		if i >= 0 {
+			taints[i] = t
+			continue
+		}
+		taints = append(taints, t)
+	}
+	return taints, nil
+}
+
+func hasUntaintedPool(pools []pool, except string) bool {
+	for _, p := range pools {
+		if p.ID != except && len(p.Taints) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func formatTaints(taints []taint) string {
+	if len(taints) == 0 {
+		return "<none>"
+	}
+	var formatted []string
+	for _, t := range taints {
+		formatted = append(formatted, t.String())
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,53 @@
+package machinepool
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditTaints(t *testing.T) {
+	current := []taint{{Key: "dedicated", Value: "infra", Effect: "NoSchedule"}, {Key: "dedicated", Value: "infra", Effect: "NoExecute"}}
+
+	taints, err := editTaints(current, []string{"dedicated=gpu:NoSchedule", "gpu:PreferNoSchedule"}, []string{"dedicated:NoExecute"})
+	require.NoError(t, err)
+	assert.Equal(t, []taint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}, {Key: "gpu", Effect: "PreferNoSchedule"}}, taints)
+	assert.Equal(t, "infra", current[0].Value, "the current taints must not be modified")
+
+	taints, err = editTaints(current, nil, []string{"dedicated"})
+	require.NoError(t, err)
+	assert.Empty(t, taints)
+
+	_, err = editTaints(current, nil, []string{"missing"})
+	assert.ErrorContains(t, err, "no taint missing to remove")
+}
+
+func TestEditTaintsRun(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader(""), io.Discard)
+	defer restore()
+	prompt.SetAssumeYes(true)
+	defer prompt.SetAssumeYes(false)
+
+	t.Run("removes the taints of a pool", func(t *testing.T) {
+		client := newFakePoolClient()
+		opts := &editTaintsOptions{clusterID: "cluster-id", poolID: "infra", remove: []string{"dedicated"}, out: io.Discard, client: client}
+
+		require.NoError(t, opts.run())
+
+		require.Len(t, client.updates, 1)
+		assert.True(t, client.updates[0].SetTaints)
+		assert.Empty(t, client.updates[0].Taints)
+	})
+
+	t.Run("refuses to taint the last untainted pool", func(t *testing.T) {
+		client := newFakePoolClient()
+		opts := &editTaintsOptions{clusterID: "cluster-id", poolID: "worker", add: []string{"dedicated=app:NoSchedule"}, out: io.Discard, client: client}
+
+		assert.ErrorContains(t, opts.run(), "only pool of the cluster without taints")
+		assert.Empty(t, client.updates)
+	})
+}
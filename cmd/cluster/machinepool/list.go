@@ -0,0 +1,97 @@
+package machinepool
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type listOptions struct {
+	clusterID string
+
+	out    io.Writer
+	client poolClient
+}
+
+// newCmdList implements machinepool list
+func newCmdList() *cobra.Command {
+	ops := &listOptions{out: os.Stdout}
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the machine pools (node pools of HCP clusters) of a cluster",
+		Example: `  # List the machine pools of a cluster
+  osdctl cluster machinepool list --cluster-id ${CLUSTER_ID}`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(utils.ValidateClusterKey("cluster-id", ops.clusterID))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	listCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID whose machine pools should be listed")
+	_ = listCmd.MarkFlagRequired("cluster-id")
+
+	return listCmd
+}
+
+func (o *listOptions) run() error {
+	if o.client == nil {
+		conn, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		poolClient, cluster, err := newOCMPoolClient(conn, o.clusterID)
+		if err != nil {
+			return err
+		}
+		// The pools are looked up by the internal ID, -C also accepts the external ID and name of the cluster
+		o.client, o.clusterID = poolClient, cluster.ID()
+	}
+
+	pools, err := o.client.listPools(o.clusterID)
+	if err != nil {
+		return err
+	}
+	printPools(o.out, pools)
+	return nil
+}
+
+func printPools(w io.Writer, pools []pool) {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"ID", "INSTANCE TYPE", "REPLICAS", "CURRENT", "AUTOSCALING", "ZONES", "LABELS", "TAINTS"})
+	for _, mp := range pools {
+		replicas, autoscaling := strconv.Itoa(mp.Replicas), "No"
+		if mp.Autoscaling {
+			replicas, autoscaling = fmt.Sprintf("%d-%d", mp.MinReplicas, mp.MaxReplicas), "Yes"
+		}
+		current := "-"
+		if mp.CurrentReplicas >= 0 {
+			current = strconv.Itoa(mp.CurrentReplicas)
+		}
+		var taints []string
+		for _, t := range mp.Taints {
+			taints = append(taints, t.String())
+		}
+		p.AddRow([]string{mp.ID, mp.InstanceType, replicas, current, autoscaling, strings.Join(mp.Zones, ","), formatLabels(mp.Labels), strings.Join(taints, ",")})
+	}
+	_ = p.Flush()
+}
+
+func formatLabels(labels map[string]string) string {
+	var pairs []string
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
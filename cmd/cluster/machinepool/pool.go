@@ -0,0 +1,281 @@
+package machinepool
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// taintEffects are the effects a taint of a pool can have
+var taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// pool is a machine pool of a ROSA Classic cluster or a node pool of an HCP cluster
+type pool struct {
+	ID           string
+	InstanceType string
+	Zones        []string
+	Replicas     int
+	// CurrentReplicas is only reported for the node pools of HCP clusters, -1 otherwise
+	CurrentReplicas int
+	Autoscaling     bool
+	MinReplicas     int
+	MaxReplicas     int
+	Labels          map[string]string
+	Taints          []taint
+}
+
+type taint struct {
+	Key    string
+	Value  string
+	Effect string
+}
+
+// String formats the taint as key=value:Effect, the syntax it is parsed from
+func (t taint) String() string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+// scaling is the size of a pool, either a fixed number of replicas or an autoscaling range
+type scaling struct {
+	Replicas    int
+	Autoscaling bool
+	MinReplicas int
+	MaxReplicas int
+}
+
+func (s scaling) String() string {
+	if s.Autoscaling {
+		return fmt.Sprintf("autoscaling %d-%d replicas", s.MinReplicas, s.MaxReplicas)
+	}
+	return fmt.Sprintf("%d replicas", s.Replicas)
+}
+
+func (p pool) scaling() scaling {
+	return scaling{Replicas: p.Replicas, Autoscaling: p.Autoscaling, MinReplicas: p.MinReplicas, MaxReplicas: p.MaxReplicas}
+}
+
+// poolUpdate holds the changes to a pool, only the set fields are sent to OCM
+type poolUpdate struct {
+	ID      string
+	Scaling *scaling
+	// Taints replace all the taints of the pool when set, an empty slice removes them
+	Taints []taint
+	// SetTaints distinguishes removing all the taints from not changing them
+	SetTaints bool
+}
+
+// poolClient is the subset of the OCM API used to manage the pools of a cluster
+type poolClient interface {
+	listPools(clusterID string) ([]pool, error)
+	updatePool(clusterID string, update poolUpdate) error
+}
+
+// ocmPoolClient manages the machine pools of ROSA Classic clusters or the node pools of HCP clusters
+type ocmPoolClient struct {
+	conn *sdk.Connection
+	hcp  bool
+}
+
+// newOCMPoolClient returns the pool client of the cluster, which manages its node pools when it is an HCP cluster
+func newOCMPoolClient(conn *sdk.Connection, clusterID string) (*ocmPoolClient, *cmv1.Cluster, error) {
+	cluster, err := utils.GetClusterAnyStatus(conn, clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ocmPoolClient{conn: conn, hcp: cluster.Hypershift().Enabled()}, cluster, nil
+}
+
+func (c *ocmPoolClient) listPools(clusterID string) ([]pool, error) {
+	clusterClient := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID)
+	var pools []pool
+	if c.hcp {
+		resp, err := clusterClient.NodePools().List().Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the node pools of cluster %s: %w", clusterID, err)
+		}
+		for _, np := range resp.Items().Slice() {
+			pools = append(pools, fromNodePool(np))
+		}
+	} else {
+		resp, err := clusterClient.MachinePools().List().Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the machine pools of cluster %s: %w", clusterID, err)
+		}
+		for _, mp := range resp.Items().Slice() {
+			pools = append(pools, fromMachinePool(mp))
+		}
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].ID < pools[j].ID })
+	return pools, nil
+}
+
+func (c *ocmPoolClient) updatePool(clusterID string, update poolUpdate) error {
+	clusterClient := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID)
+	if c.hcp {
+		body, err := nodePoolUpdate(update).Build()
+		if err != nil {
+			return err
+		}
+		if _, err := clusterClient.NodePools().NodePool(update.ID).Update().Body(body).Send(); err != nil {
+			return fmt.Errorf("failed to update node pool %s: %w", update.ID, err)
+		}
+		return nil
+	}
+
+	body, err := machinePoolUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+	if _, err := clusterClient.MachinePools().MachinePool(update.ID).Update().Body(body).Send(); err != nil {
+		return fmt.Errorf("failed to update machine pool %s: %w", update.ID, err)
+	}
+	return nil
+}
+
+func fromMachinePool(mp *cmv1.MachinePool) pool {
+	p := pool{
+		ID:              mp.ID(),
+		InstanceType:    mp.InstanceType(),
+		Zones:           mp.AvailabilityZones(),
+		Replicas:        mp.Replicas(),
+		CurrentReplicas: -1,
+		Labels:          mp.Labels(),
+		Taints:          fromTaints(mp.Taints()),
+	}
+	if autoscaling, ok := mp.GetAutoscaling(); ok {
+		p.Autoscaling, p.MinReplicas, p.MaxReplicas = true, autoscaling.MinReplicas(), autoscaling.MaxReplicas()
+	}
+	return p
+}
+
+func fromNodePool(np *cmv1.NodePool) pool {
+	p := pool{
+		ID:              np.ID(),
+		InstanceType:    np.AWSNodePool().InstanceType(),
+		Replicas:        np.Replicas(),
+		CurrentReplicas: np.Status().CurrentReplicas(),
+		Labels:          np.Labels(),
+		Taints:          fromTaints(np.Taints()),
+	}
+	if zone := np.AvailabilityZone(); zone != "" {
+		p.Zones = []string{zone}
+	}
+	if autoscaling, ok := np.GetAutoscaling(); ok {
+		p.Autoscaling, p.MinReplicas, p.MaxReplicas = true, autoscaling.MinReplica(), autoscaling.MaxReplica()
+	}
+	return p
+}
+
+func fromTaints(taints []*cmv1.Taint) []taint {
+	var result []taint
+	for _, t := range taints {
+		result = append(result, taint{Key: t.Key(), Value: t.Value(), Effect: t.Effect()})
+	}
+	return result
+}
+
+func taintBuilders(taints []taint) []*cmv1.TaintBuilder {
+	builders := []*cmv1.TaintBuilder{}
+	for _, t := range taints {
+		builders = append(builders, cmv1.NewTaint().Key(t.Key).Value(t.Value).Effect(t.Effect))
+	}
+	return builders
+}
+
+func machinePoolUpdate(update poolUpdate) *cmv1.MachinePoolBuilder {
+	builder := cmv1.NewMachinePool().ID(update.ID)
+	if s := update.Scaling; s != nil {
+		if s.Autoscaling {
+			builder.Autoscaling(cmv1.NewMachinePoolAutoscaling().MinReplicas(s.MinReplicas).MaxReplicas(s.MaxReplicas))
+		} else {
+			builder.Replicas(s.Replicas)
+		}
+	}
+	if update.SetTaints {
+		builder.Taints(taintBuilders(update.Taints)...)
+	}
+	return builder
+}
+
+func nodePoolUpdate(update poolUpdate) *cmv1.NodePoolBuilder {
+	builder := cmv1.NewNodePool().ID(update.ID)
+	if s := update.Scaling; s != nil {
+		if s.Autoscaling {
+			builder.Autoscaling(cmv1.NewNodePoolAutoscaling().MinReplica(s.MinReplicas).MaxReplica(s.MaxReplicas))
+		} else {
+			builder.Replicas(s.Replicas)
+		}
+	}
+	if update.SetTaints {
+		builder.Taints(taintBuilders(update.Taints)...)
+	}
+	return builder
+}
+
+// findPool returns the pool with the ID among the pools of the cluster
+func findPool(pools []pool, id string) (pool, error) {
+	var ids []string
+	for _, p := range pools {
+		if p.ID == id {
+			return p, nil
+		}
+		ids = append(ids, p.ID)
+	}
+	return pool{}, fmt.Errorf("pool %s not found, the pools of the cluster are: %s", id, strings.Join(ids, ", "))
+}
+
+// validateScaling checks the new size of the pool the same way OCM does, so the change is refused before prompting
+func validateScaling(p pool, s scaling) error {
+	if s.Autoscaling {
+		if s.MinReplicas < 0 {
+			return fmt.Errorf("--min-replicas must be a non-negative number, got %d", s.MinReplicas)
+		}
+		if s.MaxReplicas < 1 {
+			return fmt.Errorf("--max-replicas must be at least 1, got %d", s.MaxReplicas)
+		}
+		if s.MinReplicas > s.MaxReplicas {
+			return fmt.Errorf("--min-replicas (%d) can't be greater than --max-replicas (%d)", s.MinReplicas, s.MaxReplicas)
+		}
+	} else if s.Replicas < 0 {
+		return fmt.Errorf("--replicas must be a non-negative number, got %d", s.Replicas)
+	}
+
+	// The replicas of a multi-AZ machine pool are spread evenly across its zones
+	if zones := len(p.Zones); zones > 1 {
+		for _, n := range []int{s.Replicas, s.MinReplicas, s.MaxReplicas} {
+			if n%zones != 0 {
+				return fmt.Errorf("pool %s spans %d availability zones, its replicas must be a multiple of %d", p.ID, zones, zones)
+			}
+		}
+	}
+	return nil
+}
+
+// parseTaint parses a taint in the key=value:Effect syntax of kubectl taint, the value being optional
+func parseTaint(s string) (taint, error) {
+	keyValue, effect, found := strings.Cut(s, ":")
+	if !found {
+		return taint{}, fmt.Errorf("invalid taint %q, expected key=value:Effect", s)
+	}
+	key, value, _ := strings.Cut(keyValue, "=")
+
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return taint{}, fmt.Errorf("invalid taint key %q: %s", key, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return taint{}, fmt.Errorf("invalid taint value %q: %s", value, strings.Join(errs, "; "))
+	}
+	if !slices.Contains(taintEffects, effect) {
+		return taint{}, fmt.Errorf("invalid taint effect %q, expected one of %s", effect, strings.Join(taintEffects, ", "))
+	}
+	return taint{Key: key, Value: value, Effect: effect}, nil
+}
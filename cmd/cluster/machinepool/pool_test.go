@@ -0,0 +1,86 @@
+package machinepool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaint(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    taint
+		wantErr string
+	}{
+		{in: "dedicated=infra:NoSchedule", want: taint{Key: "dedicated", Value: "infra", Effect: "NoSchedule"}},
+		{in: "node-role.kubernetes.io/infra:NoExecute", want: taint{Key: "node-role.kubernetes.io/infra", Effect: "NoExecute"}},
+		{in: "dedicated=infra", wantErr: "expected key=value:Effect"},
+		{in: "dedicated=infra:Sometimes", wantErr: "invalid taint effect"},
+		{in: "bad key=infra:NoSchedule", wantErr: "invalid taint key"},
+		{in: "dedicated=not valid:NoSchedule", wantErr: "invalid taint value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseTaint(tt.in)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.in, got.String())
+		})
+	}
+}
+
+func TestValidateScaling(t *testing.T) {
+	singleAZ := pool{ID: "worker", Zones: []string{"us-east-1a"}}
+	multiAZ := pool{ID: "worker", Zones: []string{"us-east-1a", "us-east-1b", "us-east-1c"}}
+
+	tests := []struct {
+		name    string
+		pool    pool
+		scaling scaling
+		wantErr string
+	}{
+		{name: "fixed replicas", pool: singleAZ, scaling: scaling{Replicas: 2}},
+		{name: "negative replicas", pool: singleAZ, scaling: scaling{Replicas: -1}, wantErr: "--replicas must be a non-negative number"},
+		{name: "autoscaling range", pool: singleAZ, scaling: scaling{Autoscaling: true, MinReplicas: 1, MaxReplicas: 4}},
+		{name: "min greater than max", pool: singleAZ, scaling: scaling{Autoscaling: true, MinReplicas: 5, MaxReplicas: 4}, wantErr: "can't be greater than --max-replicas"},
+		{name: "zero max", pool: singleAZ, scaling: scaling{Autoscaling: true}, wantErr: "--max-replicas must be at least 1"},
+		{name: "multi-AZ multiple of zones", pool: multiAZ, scaling: scaling{Replicas: 6}},
+		{name: "multi-AZ not a multiple of zones", pool: multiAZ, scaling: scaling{Replicas: 4}, wantErr: "must be a multiple of 3"},
+		{name: "multi-AZ autoscaling not a multiple of zones", pool: multiAZ, scaling: scaling{Autoscaling: true, MinReplicas: 3, MaxReplicas: 10}, wantErr: "must be a multiple of 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScaling(tt.pool, tt.scaling)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPoolUpdateBody(t *testing.T) {
+	update := poolUpdate{ID: "worker", Scaling: &scaling{Autoscaling: true, MinReplicas: 2, MaxReplicas: 6}, SetTaints: true}
+
+	mp, err := machinePoolUpdate(update).Build()
+	require.NoError(t, err)
+	assert.Equal(t, 2, mp.Autoscaling().MinReplicas())
+	assert.Equal(t, 6, mp.Autoscaling().MaxReplicas())
+	_, replicasSet := mp.GetReplicas()
+	assert.False(t, replicasSet)
+	taints, taintsSet := mp.GetTaints()
+	assert.True(t, taintsSet, "an empty taint list must be sent to remove all the taints")
+	assert.Empty(t, taints)
+
+	np, err := nodePoolUpdate(poolUpdate{ID: "workers", Scaling: &scaling{Replicas: 3}}).Build()
+	require.NoError(t, err)
+	assert.Equal(t, 3, np.Replicas())
+	_, taintsSet = np.GetTaints()
+	assert.False(t, taintsSet)
+}
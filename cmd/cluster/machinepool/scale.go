@@ -0,0 +1,119 @@
+package machinepool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type scaleOptions struct {
+	clusterID   string
+	poolID      string
+	replicas    int
+	minReplicas int
+	maxReplicas int
+
+	// target is the new size of the pool, resolved from the flags which are set
+	target scaling
+
+	out    io.Writer
+	client poolClient
+}
+
+// newCmdScale implements machinepool scale
+func newCmdScale() *cobra.Command {
+	ops := &scaleOptions{out: os.Stdout}
+	scaleCmd := &cobra.Command{
+		Use:   "scale",
+		Short: "Scale a machine pool (node pool of HCP clusters) or change its autoscaling range",
+		Long: `Scales a machine pool of a ROSA Classic cluster, or a node pool of an HCP cluster, to a fixed number of replicas
+with --replicas, or enables autoscaling between --min-replicas and --max-replicas.
+
+The replicas of multi-AZ machine pools must be a multiple of their number of availability zones.`,
+		Example: `  # Scale the worker machine pool to 6 replicas
+  osdctl cluster machinepool scale --cluster-id ${CLUSTER_ID} --machinepool worker --replicas 6
+
+  # Autoscale a machine pool between 3 and 9 replicas
+  osdctl cluster machinepool scale --cluster-id ${CLUSTER_ID} --machinepool worker --min-replicas 3 --max-replicas 9`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete(cmd))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	scaleCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID of the machine pool")
+	scaleCmd.Flags().StringVarP(&ops.poolID, "machinepool", "m", "", "ID of the machine pool (node pool of HCP clusters) to scale")
+	scaleCmd.Flags().IntVar(&ops.replicas, "replicas", 0, "Fixed number of replicas of the machine pool, disabling autoscaling")
+	scaleCmd.Flags().IntVar(&ops.minReplicas, "min-replicas", 0, "Minimum number of replicas of the autoscaled machine pool")
+	scaleCmd.Flags().IntVar(&ops.maxReplicas, "max-replicas", 0, "Maximum number of replicas of the autoscaled machine pool")
+
+	_ = scaleCmd.MarkFlagRequired("cluster-id")
+	_ = scaleCmd.MarkFlagRequired("machinepool")
+	scaleCmd.MarkFlagsMutuallyExclusive("replicas", "min-replicas")
+	scaleCmd.MarkFlagsMutuallyExclusive("replicas", "max-replicas")
+	scaleCmd.MarkFlagsRequiredTogether("min-replicas", "max-replicas")
+	scaleCmd.MarkFlagsOneRequired("replicas", "min-replicas")
+
+	return scaleCmd
+}
+
+func (o *scaleOptions) complete(cmd *cobra.Command) error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	o.target = scaling{Replicas: o.replicas}
+	if cmd.Flags().Changed("min-replicas") {
+		o.target = scaling{Autoscaling: true, MinReplicas: o.minReplicas, MaxReplicas: o.maxReplicas}
+	}
+	return nil
+}
+
+func (o *scaleOptions) run() error {
+	if o.client == nil {
+		conn, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		poolClient, cluster, err := newOCMPoolClient(conn, o.clusterID)
+		if err != nil {
+			return err
+		}
+		// The pools are looked up by the internal ID, -C also accepts the external ID and name of the cluster
+		o.client, o.clusterID = poolClient, cluster.ID()
+	}
+
+	pools, err := o.client.listPools(o.clusterID)
+	if err != nil {
+		return err
+	}
+	p, err := findPool(pools, o.poolID)
+	if err != nil {
+		return err
+	}
+	if err := validateScaling(p, o.target); err != nil {
+		return err
+	}
+	if p.scaling() == o.target {
+		fmt.Fprintf(o.out, "Pool %s is already set to %s, nothing to do\n", p.ID, o.target)
+		return nil
+	}
+
+	fmt.Fprintf(o.out, "Pool %s of cluster %s will be changed from %s to %s\n", p.ID, o.clusterID, p.scaling(), o.target)
+	if !prompt.ConfirmPrompt() {
+		return errors.New("aborting machine pool scaling")
+	}
+	if err := o.client.updatePool(o.clusterID, poolUpdate{ID: p.ID, Scaling: &o.target}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "Pool %s is now set to %s\n", p.ID, o.target)
+	return nil
+}
@@ -0,0 +1,66 @@
+package machinepool
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePoolClient struct {
+	pools   []pool
+	updates []poolUpdate
+}
+
+func (c *fakePoolClient) listPools(string) ([]pool, error) {
+	return c.pools, nil
+}
+
+func (c *fakePoolClient) updatePool(_ string, update poolUpdate) error {
+	c.updates = append(c.updates, update)
+	return nil
+}
+
+func newFakePoolClient() *fakePoolClient {
+	return &fakePoolClient{pools: []pool{
+		{ID: "infra", Replicas: 2, Taints: []taint{{Key: "dedicated", Value: "infra", Effect: "NoSchedule"}}},
+		{ID: "worker", Replicas: 3, Zones: []string{"us-east-1a", "us-east-1b", "us-east-1c"}},
+	}}
+}
+
+func TestScaleRun(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader(""), io.Discard)
+	defer restore()
+	prompt.SetAssumeYes(true)
+	defer prompt.SetAssumeYes(false)
+
+	t.Run("enables autoscaling", func(t *testing.T) {
+		client := newFakePoolClient()
+		out := &bytes.Buffer{}
+		opts := &scaleOptions{clusterID: "cluster-id", poolID: "worker", target: scaling{Autoscaling: true, MinReplicas: 3, MaxReplicas: 9}, out: out, client: client}
+
+		require.NoError(t, opts.run())
+
+		require.Len(t, client.updates, 1)
+		assert.Equal(t, &scaling{Autoscaling: true, MinReplicas: 3, MaxReplicas: 9}, client.updates[0].Scaling)
+		assert.Contains(t, out.String(), "from 3 replicas to autoscaling 3-9 replicas")
+	})
+
+	t.Run("refuses invalid replicas before updating", func(t *testing.T) {
+		client := newFakePoolClient()
+		opts := &scaleOptions{clusterID: "cluster-id", poolID: "worker", target: scaling{Replicas: 4}, out: io.Discard, client: client}
+
+		assert.ErrorContains(t, opts.run(), "must be a multiple of 3")
+		assert.Empty(t, client.updates)
+	})
+
+	t.Run("unknown pool", func(t *testing.T) {
+		opts := &scaleOptions{clusterID: "cluster-id", poolID: "gpu", target: scaling{Replicas: 1}, out: io.Discard, client: newFakePoolClient()}
+
+		assert.ErrorContains(t, opts.run(), "the pools of the cluster are: infra, worker")
+	})
+}
@@ -0,0 +1,196 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	machineRoleLabel         = "machine.openshift.io/cluster-api-machine-role"
+	machineInstanceTypeLabel = "machine.openshift.io/instance-type"
+	machineZoneLabel         = "machine.openshift.io/zone"
+)
+
+var validMachineRoles = []string{"master", "infra", "worker"}
+
+type machinesListOptions struct {
+	clusterID string
+	role      string
+
+	out    io.Writer
+	client client.Client
+}
+
+func newCmdMachines() *cobra.Command {
+	machinesCmd := &cobra.Command{
+		Use:               "machines",
+		Short:             "Inspect the machines of a cluster",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+
+	machinesCmd.AddCommand(newCmdMachinesList())
+
+	return machinesCmd
+}
+
+func newCmdMachinesList() *cobra.Command {
+	opts := &machinesListOptions{out: os.Stdout}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the machines of a cluster with their backing node readiness",
+		Long: `List the machines of a cluster with their phase, instance type, availability zone, age
+and the readiness of the node backing each machine.
+
+Requires previous login to the api server via "ocm backplane login".`,
+		Example: `  # List all machines of a cluster
+  osdctl cluster machines list --cluster-id ${CLUSTER_ID}
+
+  # List only the control plane machines, e.g. after a control plane resize
+  osdctl cluster machines list --cluster-id ${CLUSTER_ID} --role master`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(context.Background())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "The internal ID of the cluster to list machines for")
+	cmd.Flags().StringVar(&opts.role, "role", "", "Only list machines with the given role (master, infra, worker)")
+	_ = cmd.MarkFlagRequired("cluster-id")
+
+	return cmd
+}
+
+func (o *machinesListOptions) validate() error {
+	if err := utils.IsValidClusterKey(o.clusterID); err != nil {
+		return err
+	}
+	if o.role == "" {
+		return nil
+	}
+	for _, r := range validMachineRoles {
+		if o.role == r {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid role %q, must be one of %v", o.role, validMachineRoles)
+}
+
+func (o *machinesListOptions) complete() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.Hypershift().Enabled() {
+		return fmt.Errorf("this command is not supported for HCP clusters, the control plane is not backed by machines in the cluster")
+	}
+
+	scheme := runtime.NewScheme()
+	if err := machinev1beta1.Install(scheme); err != nil {
+		return err
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return err
+	}
+
+	o.client, err = k8s.New(cluster.ID(), client.Options{Scheme: scheme})
+	return err
+}
+
+func (o *machinesListOptions) run(ctx context.Context) error {
+	listOpts := []client.ListOption{client.InNamespace(cpmsNamespace)}
+	if o.role != "" {
+		listOpts = append(listOpts, client.MatchingLabels{machineRoleLabel: o.role})
+	}
+
+	machines := &machinev1beta1.MachineList{}
+	if err := o.client.List(ctx, machines, listOpts...); err != nil {
+		return fmt.Errorf("failed to list machines: %w", err)
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := o.client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	nodeReady := map[string]string{}
+	for _, node := range nodes.Items {
+		nodeReady[node.Name] = "Unknown"
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady {
+				nodeReady[node.Name] = string(cond.Status)
+			}
+		}
+	}
+
+	sort.Slice(machines.Items, func(i, j int) bool {
+		ri, rj := machines.Items[i].Labels[machineRoleLabel], machines.Items[j].Labels[machineRoleLabel]
+		if ri != rj {
+			return ri < rj
+		}
+		return machines.Items[i].Name < machines.Items[j].Name
+	})
+
+	p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	p.AddRow([]string{"NAME", "ROLE", "PHASE", "TYPE", "ZONE", "AGE", "NODE", "NODE READY"})
+	for _, m := range machines.Items {
+		phase := "-"
+		if m.Status.Phase != nil {
+			phase = *m.Status.Phase
+		}
+		node, ready := "-", "-"
+		if m.Status.NodeRef != nil {
+			node = m.Status.NodeRef.Name
+			if r, ok := nodeReady[node]; ok {
+				ready = r
+			} else {
+				ready = "NotFound"
+			}
+		}
+		p.AddRow([]string{
+			m.Name,
+			valueOrDash(m.Labels[machineRoleLabel]),
+			phase,
+			valueOrDash(m.Labels[machineInstanceTypeLabel]),
+			valueOrDash(m.Labels[machineZoneLabel]),
+			duration.HumanDuration(time.Since(m.CreationTimestamp.Time)),
+			node,
+			ready,
+		})
+	}
+
+	return p.Flush()
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestMachine(name, role, node string) *machinev1beta1.Machine {
+	phase := "Running"
+	m := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cpmsNamespace,
+			Labels: map[string]string{
+				machineRoleLabel:         role,
+				machineInstanceTypeLabel: "m5.2xlarge",
+				machineZoneLabel:         "us-east-1a",
+			},
+		},
+		Status: machinev1beta1.MachineStatus{Phase: &phase},
+	}
+	if node != "" {
+		m.Status.NodeRef = &corev1.ObjectReference{Name: node}
+	}
+	return m
+}
+
+func TestMachinesListRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, machinev1beta1.Install(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	readyNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-master-0"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+		}},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newTestMachine("master-0", "master", "node-master-0"),
+		newTestMachine("infra-0", "infra", "node-infra-0"),
+		newTestMachine("worker-0", "worker", ""),
+		readyNode,
+	).Build()
+
+	tests := []struct {
+		name        string
+		role        string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "all machines",
+			contains: []string{"master-0", "infra-0", "worker-0", "node-master-0", "True", "NotFound"},
+		},
+		{
+			name:        "only masters",
+			role:        "master",
+			contains:    []string{"master-0", "m5.2xlarge", "us-east-1a", "Running"},
+			notContains: []string{"infra-0", "worker-0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			o := &machinesListOptions{role: tt.role, out: out, client: fakeClient}
+			require.NoError(t, o.run(context.Background()))
+			for _, s := range tt.contains {
+				assert.Contains(t, out.String(), s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, out.String(), s)
+			}
+		})
+	}
+}
+
+func TestMachinesListValidate(t *testing.T) {
+	assert.NoError(t, (&machinesListOptions{clusterID: "test-cluster-123", role: "infra"}).validate())
+	assert.NoError(t, (&machinesListOptions{clusterID: "test-cluster-123"}).validate())
+	assert.Error(t, (&machinesListOptions{clusterID: "test-cluster-123", role: "workers"}).validate())
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdMetrics implements the metrics command to query the monitoring stack of a cluster
+// osdctl cluster metrics query --cluster-id <cluster-id> --reason <reason> <PromQL expression>
+func NewCmdMetrics() *cobra.Command {
+	metricsCmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Query the in-cluster monitoring stack of a cluster",
+		Long: `Query the in-cluster monitoring stack of a cluster.
+
+Queries are run through backplane against the cluster's own Prometheus, which makes
+metrics available even when they are not forwarded to RHOBS or the cluster lost its
+connectivity to it.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	metricsCmd.AddCommand(newCmdQuery())
+
+	return metricsCmd
+}
@@ -0,0 +1,371 @@
+package metrics
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	monitoringNamespace = "openshift-monitoring"
+	prometheusContainer = "prometheus"
+	// prometheusURL is the address Prometheus listens on inside its pod, behind kube-rbac-proxy
+	prometheusURL = "http://localhost:9090"
+)
+
+// prometheusPods are tried in order, so that queries keep working while one replica is unavailable
+var prometheusPods = []string{"prometheus-k8s-0", "prometheus-k8s-1"}
+
+// shortcuts are commonly used queries which can be run by name instead of passing a PromQL expression
+var shortcuts = map[string]string{
+	"api-latency":     `histogram_quantile(0.99, sum by (verb, le) (rate(apiserver_request_duration_seconds_bucket{job="apiserver", verb!~"WATCH|CONNECT"}[5m])))`,
+	"etcd-fsync":      `histogram_quantile(0.99, sum by (instance, le) (rate(etcd_disk_wal_fsync_duration_seconds_bucket{job="etcd"}[5m])))`,
+	"node-saturation": `node_load1 / on (instance) count by (instance) (node_cpu_seconds_total{mode="idle"})`,
+}
+
+// PromtoolExecutor runs a promtool command in a Prometheus pod and returns its output
+type PromtoolExecutor func(ctx context.Context, command []string) (string, error)
+
+type queryOptions struct {
+	clusterID string
+	reason    string
+	shortcut  string
+	since     time.Duration
+	step      time.Duration
+	output    string
+
+	expression string
+	now        func() time.Time
+	exec       PromtoolExecutor
+	out        io.Writer
+}
+
+// sample is a series of a promtool query result; instant queries set Value, range queries set Values
+type sample struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value,omitempty"`
+	Values [][]interface{}   `json:"values,omitempty"`
+}
+
+// point is a single value of a series
+type point struct {
+	Metric    map[string]string `json:"metric"`
+	Timestamp time.Time         `json:"timestamp"`
+	Value     string            `json:"value"`
+}
+
+func newCmdQuery() *cobra.Command {
+	opts := &queryOptions{now: time.Now}
+
+	queryCmd := &cobra.Command{
+		Use:   "query --cluster-id <cluster-id> --reason <reason> [PromQL expression]",
+		Short: "Run a PromQL query against the cluster's Prometheus",
+		Long: `Run a PromQL query against the cluster's Prometheus.
+
+The query is run with promtool inside the Prometheus pods of the openshift-monitoring
+namespace, which requires backplane-cluster-admin elevation. Instead of an expression,
+one of the following shortcuts can be passed with --shortcut:
+
+` + shortcutsHelp() + `
+
+By default an instant query is run. --since runs a range query over the given duration
+instead, with one point every --step.`,
+		Example: `  # Run an instant query
+  osdctl cluster metrics query --cluster-id ${CLUSTER_ID} --reason "OHSS-1234" 'up{job="apiserver"}'
+
+  # Show the 99th percentile of the etcd fsync latency over the last hour as CSV
+  osdctl cluster metrics query --cluster-id ${CLUSTER_ID} --reason "OHSS-1234" --shortcut etcd-fsync --since 1h -o csv`,
+		Args:              cobra.MaximumNArgs(1),
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.expression = args[0]
+			}
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			exec, err := newPromtoolExecutor(opts.clusterID, opts.reason)
+			if err != nil {
+				return err
+			}
+			opts.exec = exec
+			opts.out = cmd.OutOrStdout()
+			return opts.run(cmd.Context())
+		},
+	}
+
+	queryCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID (internal or external)")
+	queryCmd.Flags().StringVar(&opts.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	queryCmd.Flags().StringVar(&opts.shortcut, "shortcut", "", "Run a predefined query instead of a PromQL expression: "+strings.Join(shortcutNames(), ", "))
+	queryCmd.Flags().DurationVar(&opts.since, "since", 0, "Run a range query from this long ago until now instead of an instant query")
+	queryCmd.Flags().DurationVar(&opts.step, "step", time.Minute, "Resolution of range queries")
+	queryCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table, json or csv")
+	_ = queryCmd.MarkFlagRequired("cluster-id")
+	_ = queryCmd.MarkFlagRequired("reason")
+
+	return queryCmd
+}
+
+func shortcutNames() []string {
+	return slices.Sorted(maps.Keys(shortcuts))
+}
+
+func shortcutsHelp() string {
+	var lines []string
+	for _, name := range shortcutNames() {
+		lines = append(lines, fmt.Sprintf("  %s: %s", name, shortcuts[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (o *queryOptions) validate() error {
+	switch {
+	case o.shortcut != "" && o.expression != "":
+		return fmt.Errorf("a PromQL expression and --shortcut are mutually exclusive")
+	case o.shortcut != "":
+		expression, ok := shortcuts[o.shortcut]
+		if !ok {
+			return fmt.Errorf("unknown shortcut %q, expected one of: %s", o.shortcut, strings.Join(shortcutNames(), ", "))
+		}
+		o.expression = expression
+	case o.expression == "":
+		return fmt.Errorf("a PromQL expression or --shortcut must be provided")
+	}
+
+	if o.since < 0 {
+		return fmt.Errorf("--since must be greater than 0")
+	}
+	if o.step <= 0 {
+		return fmt.Errorf("--step must be greater than 0")
+	}
+	switch o.output {
+	case "table", "json", "csv":
+	default:
+		return fmt.Errorf("invalid output format %q, expected table, json or csv", o.output)
+	}
+	return nil
+}
+
+func (o *queryOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	result, err := o.exec(ctx, o.promtoolCommand())
+	if err != nil {
+		return err
+	}
+
+	points, err := parsePromtoolResult(result)
+	if err != nil {
+		return err
+	}
+	return printPoints(o.out, points, o.output)
+}
+
+// promtoolCommand returns the promtool command running the query
+func (o *queryOptions) promtoolCommand() []string {
+	if o.since == 0 {
+		return []string{"promtool", "query", "instant", "--format=json", prometheusURL, o.expression}
+	}
+
+	end := o.now().UTC()
+	return []string{
+		"promtool", "query", "range", "--format=json",
+		"--start=" + end.Add(-o.since).Format(time.RFC3339),
+		"--end=" + end.Format(time.RFC3339),
+		"--step=" + o.step.String(),
+		prometheusURL, o.expression,
+	}
+}
+
+// newPromtoolExecutor returns a PromtoolExecutor running commands in the Prometheus pods as backplane-cluster-admin
+func newPromtoolExecutor(clusterID, reason string) (PromtoolExecutor, error) {
+	config, err := k8s.NewRestConfigAsBackplaneClusterAdmin(clusterID, reason, "Querying cluster metrics")
+	if err != nil {
+		return nil, err
+	}
+	return NewPromtoolExecutorForConfig(config)
+}
+
+// NewPromtoolExecutorForConfig returns a PromtoolExecutor running commands in the Prometheus pods with the
+// given config, which must be allowed to exec into pods of the openshift-monitoring namespace
+func NewPromtoolExecutorForConfig(config *rest.Config) (PromtoolExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, command []string) (string, error) {
+		var errs []string
+		for _, pod := range prometheusPods {
+			output, err := k8s.ExecInPodOutput(ctx, config, clientset, monitoringNamespace, pod, prometheusContainer, command)
+			if err == nil {
+				return output, nil
+			}
+			errs = append(errs, fmt.Sprintf("%s: %v", pod, err))
+		}
+		return "", fmt.Errorf("failed to run the query: %s", strings.Join(errs, "; "))
+	}, nil
+}
+
+// Series is a series of an instant query result
+type Series struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// InstantQuery runs an instant PromQL query with exec and returns the value of each series
+func InstantQuery(ctx context.Context, exec PromtoolExecutor, expression string) ([]Series, error) {
+	result, err := exec(ctx, []string{"promtool", "query", "instant", "--format=json", prometheusURL, expression})
+	if err != nil {
+		return nil, err
+	}
+	points, err := parsePromtoolResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]Series, 0, len(points))
+	for _, p := range points {
+		value, err := strconv.ParseFloat(p.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected value %q in the query result", p.Value)
+		}
+		series = append(series, Series{Labels: p.Metric, Value: value})
+	}
+	return series, nil
+}
+
+// parsePromtoolResult parses the JSON output of promtool into points, sorted by series and time.
+// Scalar results are returned as a single point without labels.
+func parsePromtoolResult(result string) ([]point, error) {
+	result = strings.TrimSpace(result)
+	if result == "" || result == "null" {
+		return nil, nil
+	}
+
+	var samples []sample
+	if err := json.Unmarshal([]byte(result), &samples); err != nil {
+		var scalar []interface{}
+		if scalarErr := json.Unmarshal([]byte(result), &scalar); scalarErr != nil {
+			return nil, fmt.Errorf("failed to parse the query result: %w", err)
+		}
+		samples = []sample{{Value: scalar}}
+	}
+
+	var points []point
+	for _, s := range samples {
+		values := s.Values
+		if s.Value != nil {
+			values = append(values, s.Value)
+		}
+		for _, value := range values {
+			p, err := newPoint(s.Metric, value)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
+
+func newPoint(metric map[string]string, value []interface{}) (point, error) {
+	if len(value) != 2 {
+		return point{}, fmt.Errorf("unexpected value %v in the query result", value)
+	}
+	timestamp, ok := value[0].(float64)
+	if !ok {
+		return point{}, fmt.Errorf("unexpected timestamp %v in the query result", value[0])
+	}
+	v, ok := value[1].(string)
+	if !ok {
+		return point{}, fmt.Errorf("unexpected value %v in the query result", value[1])
+	}
+	if metric == nil {
+		metric = map[string]string{}
+	}
+	return point{
+		Metric:    metric,
+		Timestamp: time.UnixMilli(int64(timestamp * 1000)).UTC(),
+		Value:     v,
+	}, nil
+}
+
+// labelNames returns the sorted union of the label names of the points
+func labelNames(points []point) []string {
+	names := map[string]bool{}
+	for _, p := range points {
+		for name := range p.Metric {
+			names[name] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(names))
+}
+
+// formatMetric formats labels the way PromQL does, e.g. {instance="a", job="b"}
+func formatMetric(metric map[string]string) string {
+	labels := make([]string, 0, len(metric))
+	for _, key := range slices.Sorted(maps.Keys(metric)) {
+		labels = append(labels, key+"="+strconv.Quote(metric[key]))
+	}
+	return "{" + strings.Join(labels, ", ") + "}"
+}
+
+func printPoints(w io.Writer, points []point, output string) error {
+	switch output {
+	case "json":
+		if points == nil {
+			points = []point{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(points)
+
+	case "csv":
+		names := labelNames(points)
+		writer := csv.NewWriter(w)
+		if err := writer.Write(append(slices.Clone(names), "timestamp", "value")); err != nil {
+			return err
+		}
+		for _, p := range points {
+			record := make([]string, 0, len(names)+2)
+			for _, name := range names {
+				record = append(record, p.Metric[name])
+			}
+			if err := writer.Write(append(record, p.Timestamp.Format(time.RFC3339), p.Value)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	default:
+		if len(points) == 0 {
+			_, err := fmt.Fprintln(w, "No results")
+			return err
+		}
+		table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+		table.AddRow([]string{"METRIC", "TIMESTAMP", "VALUE"})
+		for _, p := range points {
+			table.AddRow([]string{formatMetric(p.Metric), p.Timestamp.Format(time.RFC3339), p.Value})
+		}
+		return table.Flush()
+	}
+}
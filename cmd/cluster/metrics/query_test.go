@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	instantResult = `[{"metric":{"job":"apiserver","instance":"10.0.0.1:6443"},"value":[1700000000,"1"]},{"metric":{"job":"apiserver","instance":"10.0.0.2:6443"},"value":[1700000000,"0"]}]`
+	rangeResult   = `[{"metric":{"instance":"master-0"},"values":[[1700000000,"0.002"],[1700000060,"0.004"]]}]`
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       queryOptions
+		expression string
+		wantErr    string
+	}{
+		{
+			name:       "expression",
+			opts:       queryOptions{expression: "up", step: time.Minute, output: "table"},
+			expression: "up",
+		},
+		{
+			name:       "shortcut",
+			opts:       queryOptions{shortcut: "etcd-fsync", step: time.Minute, output: "csv"},
+			expression: shortcuts["etcd-fsync"],
+		},
+		{
+			name:    "expression and shortcut",
+			opts:    queryOptions{expression: "up", shortcut: "etcd-fsync", step: time.Minute, output: "table"},
+			wantErr: "a PromQL expression and --shortcut are mutually exclusive",
+		},
+		{
+			name:    "unknown shortcut",
+			opts:    queryOptions{shortcut: "disk", step: time.Minute, output: "table"},
+			wantErr: `unknown shortcut "disk", expected one of: api-latency, etcd-fsync, node-saturation`,
+		},
+		{
+			name:    "no query",
+			opts:    queryOptions{step: time.Minute, output: "table"},
+			wantErr: "a PromQL expression or --shortcut must be provided",
+		},
+		{
+			name:    "invalid output",
+			opts:    queryOptions{expression: "up", step: time.Minute, output: "yaml"},
+			wantErr: `invalid output format "yaml", expected table, json or csv`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expression, tt.opts.expression)
+		})
+	}
+}
+
+func TestPromtoolCommand(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	opts := &queryOptions{expression: "up", step: 30 * time.Second, now: func() time.Time { return now }}
+
+	assert.Equal(t, []string{"promtool", "query", "instant", "--format=json", prometheusURL, "up"}, opts.promtoolCommand())
+
+	opts.since = time.Hour
+	assert.Equal(t, []string{
+		"promtool", "query", "range", "--format=json",
+		"--start=2024-05-01T11:00:00Z", "--end=2024-05-01T12:00:00Z", "--step=30s",
+		prometheusURL, "up",
+	}, opts.promtoolCommand())
+}
+
+func TestParsePromtoolResult(t *testing.T) {
+	points, err := parsePromtoolResult(rangeResult)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.Equal(t, map[string]string{"instance": "master-0"}, points[1].Metric)
+	assert.Equal(t, time.Unix(1700000060, 0).UTC(), points[1].Timestamp)
+	assert.Equal(t, "0.004", points[1].Value)
+
+	points, err = parsePromtoolResult(`[1700000000.5,"42"]`)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	assert.Empty(t, points[0].Metric)
+	assert.Equal(t, "42", points[0].Value)
+
+	points, err = parsePromtoolResult("null\n")
+	require.NoError(t, err)
+	assert.Empty(t, points)
+
+	_, err = parsePromtoolResult("Error: bad_data: invalid parameter")
+	assert.ErrorContains(t, err, "failed to parse the query result")
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{
+			output: "csv",
+			expected: "instance,job,timestamp,value\n" +
+				"10.0.0.1:6443,apiserver,2023-11-14T22:13:20Z,1\n" +
+				"10.0.0.2:6443,apiserver,2023-11-14T22:13:20Z,0\n",
+		},
+		{
+			output: "table",
+			expected: "METRIC                                        TIMESTAMP              VALUE\n" +
+				"{instance=\"10.0.0.1:6443\", job=\"apiserver\"}   2023-11-14T22:13:20Z   1\n" +
+				"{instance=\"10.0.0.2:6443\", job=\"apiserver\"}   2023-11-14T22:13:20Z   0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			var command []string
+			out := &bytes.Buffer{}
+			opts := &queryOptions{
+				expression: `up{job="apiserver"}`,
+				output:     tt.output,
+				now:        time.Now,
+				out:        out,
+				exec: func(_ context.Context, c []string) (string, error) {
+					command = c
+					return instantResult, nil
+				},
+			}
+
+			require.NoError(t, opts.run(context.Background()))
+			assert.Equal(t, `up{job="apiserver"}`, command[len(command)-1])
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
+
+func TestPrintPointsJSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, printPoints(out, nil, "json"))
+	assert.Equal(t, "[]\n", out.String())
+}
+
+func TestInstantQuery(t *testing.T) {
+	exec := func(_ context.Context, c []string) (string, error) {
+		return instantResult, nil
+	}
+
+	series, err := InstantQuery(context.Background(), exec, `up{job="apiserver"}`)
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+	assert.Equal(t, "10.0.0.1:6443", series[0].Labels["instance"])
+	assert.Equal(t, float64(1), series[0].Value)
+	assert.Equal(t, float64(0), series[1].Value)
+}
@@ -0,0 +1,23 @@
+package node
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdNode implements the node command to perform maintenance operations on the nodes of a cluster
+// osdctl cluster node drain --cluster-id <cluster-id> --node <node> --reason <reason>
+func NewCmdNode() *cobra.Command {
+	nodeCmd := &cobra.Command{
+		Use:               "node",
+		Short:             "Perform maintenance operations on the nodes of a cluster",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	nodeCmd.AddCommand(newCmdDrain())
+
+	return nodeCmd
+}
@@ -0,0 +1,375 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// podNodeNameField is the field selector of the pods scheduled on a node
+	podNodeNameField = "spec.nodeName"
+	// mirrorPodAnnotation is set on the API representation of static pods, which drain leaves alone
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+)
+
+// Drain cordons and drains the node with "oc adm drain", elevated as backplane-cluster-admin on the cluster of
+// the current backplane login. DaemonSet pods are ignored and emptyDir data is deleted. Force additionally deletes
+// the pods which aren't managed by a controller, and a non-zero timeout gives up on the evictions after it.
+// The justification of the reason is set to the drain.
+func Drain(nodeName string, reason elevate.Reason, force bool, timeout time.Duration) error {
+	action := "drain"
+	args := "adm drain --ignore-daemonsets --delete-emptydir-data"
+	if force {
+		action = "force drain"
+		args += " --force"
+	}
+	if timeout > 0 {
+		args += " --timeout=" + timeout.String()
+	}
+
+	reason.Justification = fmt.Sprintf("%s node %s", action, nodeName)
+	return elevate.Run(reason, args, nodeName)
+}
+
+// drainOptions defines the struct for running the node drain command
+type drainOptions struct {
+	clusterID string
+	nodeName  string
+	reason    string
+	force     bool
+	timeout   time.Duration
+
+	out           io.Writer
+	client        client.Client
+	ensureSession func(ctx context.Context, clusterID string) error
+	drain         func(nodeName string, reason elevate.Reason, force bool, timeout time.Duration) error
+}
+
+// drainPlan is what draining a node would do to the pods scheduled on it
+type drainPlan struct {
+	// evicted are the pods evicted and recreated elsewhere by their controller
+	evicted []corev1.Pod
+	// unmanaged are the pods without a controller, which are deleted for good and require --force
+	unmanaged []corev1.Pod
+	// emptyDir are the evicted or unmanaged pods losing the data of their emptyDir volumes
+	emptyDir []corev1.Pod
+	// ignored are the DaemonSet, static and completed pods, which drain doesn't evict
+	ignored  int
+	pdbs     []pdbImpact
+	blocking int
+}
+
+// pdbImpact is how a PodDisruptionBudget covering the pods of the node constrains the drain
+type pdbImpact struct {
+	namespace          string
+	name               string
+	podsOnNode         int
+	disruptionsAllowed int32
+}
+
+func (p pdbImpact) isBlocking() bool {
+	return int32(p.podsOnNode) > p.disruptionsAllowed
+}
+
+func newCmdDrain() *cobra.Command {
+	opts := &drainOptions{}
+
+	drainCmd := &cobra.Command{
+		Use:   "drain",
+		Short: "Safely drain a node of a cluster",
+		Long: `Safely drain a node of a cluster.
+
+  The pods of the node and the PodDisruptionBudgets covering them are checked first: the pods which would be
+  evicted, deleted or lose emptyDir data are listed along with the PodDisruptionBudgets which would block the
+  evictions. After confirmation, the node is drained with "oc adm drain" elevated as backplane-cluster-admin,
+  and the placement of the pods of the controllers which were evicted is shown.
+
+  Pods which aren't managed by a controller are deleted for good, and are only drained with --force.
+
+  Requires previous login to the api server via "ocm backplane login".`,
+		Example: `  # Drain a node
+  osdctl cluster node drain --cluster-id ${CLUSTER_ID} --node ip-10-0-1-2.ec2.internal --reason "OHSS-1234"
+
+  # Drain a node running pods without a controller, giving up on the evictions after 10 minutes
+  osdctl cluster node drain --cluster-id ${CLUSTER_ID} --node ip-10-0-1-2.ec2.internal --reason "OHSS-1234" --force --timeout 10m`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.out = cmd.OutOrStdout()
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	drainCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "The internal ID of the cluster of the node")
+	drainCmd.Flags().StringVar(&opts.nodeName, "node", "", "The name of the node to drain")
+	drainCmd.Flags().StringVar(&opts.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	drainCmd.Flags().BoolVar(&opts.force, "force", false, "Also delete the pods which aren't managed by a controller, after confirmation")
+	drainCmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "How long to wait for the evictions before giving up, zero means infinite")
+	_ = drainCmd.MarkFlagRequired("cluster-id")
+	_ = drainCmd.MarkFlagRequired("node")
+	_ = drainCmd.MarkFlagRequired("reason")
+
+	return drainCmd
+}
+
+func (o *drainOptions) complete() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	if err := utils.ValidateReason(elevate.ReasonFlag, o.reason); err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	o.clusterID = cluster.ID()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	if err := policyv1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	o.client, err = k8s.New(o.clusterID, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	o.ensureSession = k8s.EnsureBackplaneSession
+	o.drain = Drain
+	return nil
+}
+
+func (o *drainOptions) run(ctx context.Context) error {
+	// The drain runs against the cluster of the current backplane login, which must be the one checked
+	if err := o.ensureSession(ctx, o.clusterID); err != nil {
+		return err
+	}
+
+	node := &corev1.Node{}
+	if err := o.client.Get(ctx, client.ObjectKey{Name: o.nodeName}, node); err != nil {
+		return fmt.Errorf("failed to get node %s: %w", o.nodeName, err)
+	}
+
+	plan, err := planDrain(ctx, o.client, o.nodeName)
+	if err != nil {
+		return err
+	}
+	o.printPlan(plan)
+
+	if len(plan.unmanaged) > 0 {
+		if !o.force {
+			return fmt.Errorf("%d pod(s) of node %s aren't managed by a controller and would be deleted for good, use --force to drain it anyway", len(plan.unmanaged), o.nodeName)
+		}
+		fmt.Fprintf(o.out, "\nForce draining deletes the %d pod(s) without a controller for good.\n", len(plan.unmanaged))
+		if !prompt.ConfirmPrompt() {
+			return errors.New("drain cancelled")
+		}
+	}
+	if plan.blocking > 0 {
+		fmt.Fprintf(o.out, "\n%d PodDisruptionBudget(s) don't allow evicting all their pods of the node, the drain waits for them", plan.blocking)
+		if o.timeout > 0 {
+			fmt.Fprintf(o.out, " for at most %s", o.timeout)
+		}
+		fmt.Fprintln(o.out, ".")
+	}
+	fmt.Fprintf(o.out, "\nNode %s will be cordoned and drained.\n", o.nodeName)
+	if !prompt.ConfirmPrompt() {
+		return errors.New("drain cancelled")
+	}
+
+	if err := o.drain(o.nodeName, elevate.Reason{Ticket: o.reason, Command: "cluster node drain"}, o.force, o.timeout); err != nil {
+		return fmt.Errorf("failed to drain node %s: %w", o.nodeName, err)
+	}
+	printer.PrintlnGreen("Node", o.nodeName, "drained")
+
+	placement, err := podPlacement(ctx, o.client, plan.evicted)
+	if err != nil {
+		return fmt.Errorf("node drained, but failed to get the placement of the evicted pods: %w", err)
+	}
+	o.printPlacement(placement)
+	return nil
+}
+
+// planDrain lists the pods of the node by what draining it does to them and the PodDisruptionBudgets covering them
+func planDrain(ctx context.Context, c client.Client, nodeName string) (*drainPlan, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.MatchingFields{podNodeNameField: nodeName}); err != nil {
+		return nil, fmt.Errorf("failed to list the pods of node %s: %w", nodeName, err)
+	}
+
+	plan := &drainPlan{}
+	namespaces := map[string]bool{}
+	for _, pod := range pods.Items {
+		controller := metav1.GetControllerOf(&pod)
+		switch {
+		case pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed,
+			pod.Annotations[mirrorPodAnnotation] != "",
+			controller != nil && controller.Kind == "DaemonSet":
+			plan.ignored++
+			continue
+		case controller == nil:
+			plan.unmanaged = append(plan.unmanaged, pod)
+		default:
+			plan.evicted = append(plan.evicted, pod)
+		}
+		if hasEmptyDir(pod) {
+			plan.emptyDir = append(plan.emptyDir, pod)
+		}
+		namespaces[pod.Namespace] = true
+	}
+
+	drained := append(append([]corev1.Pod{}, plan.evicted...), plan.unmanaged...)
+	for namespace := range namespaces {
+		pdbs := &policyv1.PodDisruptionBudgetList{}
+		if err := c.List(ctx, pdbs, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list the PodDisruptionBudgets of namespace %s: %w", namespace, err)
+		}
+		for _, pdb := range pdbs.Items {
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() {
+				continue
+			}
+			impact := pdbImpact{namespace: pdb.Namespace, name: pdb.Name, disruptionsAllowed: pdb.Status.DisruptionsAllowed}
+			for _, pod := range drained {
+				if pod.Namespace == pdb.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+					impact.podsOnNode++
+				}
+			}
+			if impact.podsOnNode == 0 {
+				continue
+			}
+			if impact.isBlocking() {
+				plan.blocking++
+			}
+			plan.pdbs = append(plan.pdbs, impact)
+		}
+	}
+	sort.Slice(plan.pdbs, func(i, j int) bool {
+		if plan.pdbs[i].namespace != plan.pdbs[j].namespace {
+			return plan.pdbs[i].namespace < plan.pdbs[j].namespace
+		}
+		return plan.pdbs[i].name < plan.pdbs[j].name
+	})
+
+	return plan, nil
+}
+
+func hasEmptyDir(pod corev1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *drainOptions) printPlan(plan *drainPlan) {
+	fmt.Fprintf(o.out, "Node %s: %d pod(s) evicted, %d pod(s) without a controller, %d pod(s) losing emptyDir data, %d pod(s) ignored (DaemonSet, static or completed)\n",
+		o.nodeName, len(plan.evicted), len(plan.unmanaged), len(plan.emptyDir), plan.ignored)
+
+	if len(plan.unmanaged) > 0 {
+		fmt.Fprintln(o.out, "\nPods without a controller:")
+		p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+		p.AddRow([]string{"NAMESPACE", "POD"})
+		for _, pod := range plan.unmanaged {
+			p.AddRow([]string{pod.Namespace, pod.Name})
+		}
+		_ = p.Flush()
+	}
+
+	if len(plan.pdbs) > 0 {
+		fmt.Fprintln(o.out, "\nPodDisruptionBudgets:")
+		p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+		p.AddRow([]string{"NAMESPACE", "NAME", "PODS ON NODE", "DISRUPTIONS ALLOWED", "IMPACT"})
+		for _, pdb := range plan.pdbs {
+			impact := "OK"
+			if pdb.isBlocking() {
+				impact = "BLOCKING"
+			}
+			p.AddRow([]string{pdb.namespace, pdb.name, fmt.Sprint(pdb.podsOnNode), fmt.Sprint(pdb.disruptionsAllowed), impact})
+		}
+		_ = p.Flush()
+	}
+}
+
+// podPlacement returns the pods of the controllers of the evicted pods, which shows where they were rescheduled
+func podPlacement(ctx context.Context, c client.Client, evicted []corev1.Pod) ([]corev1.Pod, error) {
+	controllers := map[string]map[string]bool{}
+	for _, pod := range evicted {
+		if controller := metav1.GetControllerOf(&pod); controller != nil {
+			if controllers[pod.Namespace] == nil {
+				controllers[pod.Namespace] = map[string]bool{}
+			}
+			controllers[pod.Namespace][string(controller.UID)] = true
+		}
+	}
+
+	var placement []corev1.Pod
+	for namespace, uids := range controllers {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		for _, pod := range pods.Items {
+			if controller := metav1.GetControllerOf(&pod); controller != nil && uids[string(controller.UID)] {
+				placement = append(placement, pod)
+			}
+		}
+	}
+	sort.Slice(placement, func(i, j int) bool {
+		if placement[i].Namespace != placement[j].Namespace {
+			return placement[i].Namespace < placement[j].Namespace
+		}
+		return placement[i].Name < placement[j].Name
+	})
+	return placement, nil
+}
+
+func (o *drainOptions) printPlacement(placement []corev1.Pod) {
+	if len(placement) == 0 {
+		return
+	}
+	fmt.Fprintln(o.out, "\nPlacement of the pods of the evicted controllers:")
+	p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	p.AddRow([]string{"NAMESPACE", "POD", "CONTROLLER", "NODE", "PHASE"})
+	for _, pod := range placement {
+		controller := metav1.GetControllerOf(&pod)
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			nodeName = "<unscheduled>"
+		}
+		p.AddRow([]string{pod.Namespace, pod.Name, strings.ToLower(controller.Kind) + "/" + controller.Name, nodeName, string(pod.Status.Phase)})
+	}
+	_ = p.Flush()
+}
@@ -0,0 +1,179 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPod(namespace, name, nodeName, controllerKind, controllerUID string, labels map[string]string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if controllerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{
+			Kind:       controllerKind,
+			Name:       strings.ToLower(controllerKind) + "-" + controllerUID,
+			UID:        types.UID(controllerUID),
+			Controller: ptr.To(true),
+		}}
+	}
+	return pod
+}
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithIndex(&corev1.Pod{}, podNodeNameField, func(o client.Object) []string {
+			return []string{o.(*corev1.Pod).Spec.NodeName}
+		}).
+		Build()
+}
+
+func drainFixtures() []client.Object {
+	emptyDirPod := newPod("app", "cache-1", "node-a", "ReplicaSet", "rs-cache", map[string]string{"app": "cache"})
+	emptyDirPod.Spec.Volumes = []corev1.Volume{{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	completed := newPod("app", "job-1", "node-a", "Job", "job", nil)
+	completed.Status.Phase = corev1.PodSucceeded
+
+	return []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		newPod("app", "web-1", "node-a", "ReplicaSet", "rs-web", map[string]string{"app": "web"}),
+		newPod("app", "web-2", "node-b", "ReplicaSet", "rs-web", map[string]string{"app": "web"}),
+		emptyDirPod,
+		completed,
+		newPod("openshift-dns", "dns-1", "node-a", "DaemonSet", "ds-dns", nil),
+		newPod("debug", "standalone", "node-a", "", "", nil),
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "web"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable: ptr.To(intstr.FromInt32(2)),
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+		},
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "cache"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+				Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cache"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+		},
+	}
+}
+
+func TestPlanDrain(t *testing.T) {
+	plan, err := planDrain(context.Background(), newFakeClient(t, drainFixtures()...), "node-a")
+	require.NoError(t, err)
+
+	podNames := func(pods []corev1.Pod) []string {
+		var names []string
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"web-1", "cache-1"}, podNames(plan.evicted))
+	assert.Equal(t, []string{"standalone"}, podNames(plan.unmanaged))
+	assert.Equal(t, []string{"cache-1"}, podNames(plan.emptyDir))
+	assert.Equal(t, 2, plan.ignored)
+
+	require.Len(t, plan.pdbs, 2)
+	assert.Equal(t, "cache", plan.pdbs[0].name)
+	assert.False(t, plan.pdbs[0].isBlocking())
+	assert.Equal(t, "web", plan.pdbs[1].name)
+	assert.True(t, plan.pdbs[1].isBlocking())
+	assert.Equal(t, 1, plan.blocking)
+}
+
+func TestDrainRun(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader(""), io.Discard)
+	defer restore()
+	prompt.SetAssumeYes(true)
+	defer prompt.SetAssumeYes(false)
+
+	newOptions := func(t *testing.T, force bool, drained *bool) (*drainOptions, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		return &drainOptions{
+			clusterID:     "cluster-id",
+			nodeName:      "node-a",
+			reason:        "OHSS-1234",
+			force:         force,
+			timeout:       time.Minute,
+			out:           out,
+			client:        newFakeClient(t, drainFixtures()...),
+			ensureSession: func(context.Context, string) error { return nil },
+			drain: func(nodeName string, reason elevate.Reason, force bool, timeout time.Duration) error {
+				assert.Equal(t, "node-a", nodeName)
+				assert.Equal(t, elevate.Reason{Ticket: "OHSS-1234", Command: "cluster node drain"}, reason)
+				assert.True(t, force)
+				assert.Equal(t, time.Minute, timeout)
+				*drained = true
+				return nil
+			},
+		}, out
+	}
+
+	t.Run("refuses to delete pods without a controller unless forced", func(t *testing.T) {
+		drained := false
+		opts, out := newOptions(t, false, &drained)
+
+		err := opts.run(context.Background())
+
+		assert.ErrorContains(t, err, "use --force")
+		assert.False(t, drained)
+		assert.Contains(t, out.String(), "standalone")
+		assert.Contains(t, out.String(), "BLOCKING")
+	})
+
+	t.Run("force drains and shows the placement of the evicted pods", func(t *testing.T) {
+		drained := false
+		opts, out := newOptions(t, true, &drained)
+
+		require.NoError(t, opts.run(context.Background()))
+
+		assert.True(t, drained)
+		assert.Contains(t, out.String(), "1 PodDisruptionBudget(s) don't allow evicting all their pods of the node, the drain waits for them for at most 1m0s")
+		assert.Contains(t, out.String(), "Placement of the pods of the evicted controllers")
+		assert.Regexp(t, `web-2\s+replicaset/replicaset-rs-web\s+node-b`, out.String())
+	})
+
+	t.Run("refuses to drain without a backplane session for the cluster", func(t *testing.T) {
+		drained := false
+		opts, _ := newOptions(t, true, &drained)
+		opts.ensureSession = func(_ context.Context, clusterID string) error {
+			return &k8s.SessionError{ClusterID: clusterID, Err: k8s.ErrSessionExpired}
+		}
+
+		err := opts.run(context.Background())
+
+		assert.ErrorIs(t, err, k8s.ErrSessionExpired)
+		assert.ErrorContains(t, err, "ocm backplane login cluster-id")
+		assert.False(t, drained)
+	})
+}
@@ -0,0 +1,58 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/elevate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// readyPollInterval is how often a node is checked while waiting for it to be Ready
+var readyPollInterval = 15 * time.Second
+
+// Cordon marks the node unschedulable with "oc adm cordon", elevated as backplane-cluster-admin on the cluster of the
+// current backplane login. The justification of the reason is set to the cordon.
+func Cordon(nodeName string, reason elevate.Reason) error {
+	reason.Justification = "cordon node " + nodeName
+	return elevate.Run(reason, "adm cordon", nodeName)
+}
+
+// Uncordon marks the node schedulable again with "oc adm uncordon", elevated as backplane-cluster-admin on the
+// cluster of the current backplane login. The justification of the reason is set to the uncordon.
+func Uncordon(nodeName string, reason elevate.Reason) error {
+	reason.Justification = "uncordon node " + nodeName
+	return elevate.Run(reason, "adm uncordon", nodeName)
+}
+
+// IsReady returns whether the Ready condition of the node is true
+func IsReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// WaitForReady polls the node until its Ready condition is true and done returns true, or the timeout elapses.
+// A nil done only waits for the node to be Ready.
+func WaitForReady(ctx context.Context, c client.Client, nodeName string, timeout time.Duration, done func(node *corev1.Node) bool) error {
+	log.Printf("Waiting up to %s for node %s to be ready", timeout, nodeName)
+	err := wait.PollUntilContextTimeout(ctx, readyPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		node := &corev1.Node{}
+		if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+			log.Printf("error retrieving node %s: %v", nodeName, err)
+			return false, nil
+		}
+		return IsReady(node) && (done == nil || done(node)), nil
+	})
+	if err != nil {
+		return fmt.Errorf("node %s did not become ready: %v", nodeName, err)
+	}
+	return nil
+}
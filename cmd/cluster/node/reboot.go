@@ -0,0 +1,346 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/provider/gcp"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultRebootReadyTimeout = 20 * time.Minute
+
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+)
+
+// nodeRoles are the roles of the nodes by priority, a node is of the first of its roles
+var nodeRoles = []string{"master", "control-plane", "infra", "worker"}
+
+// rebootNodeOptions defines the struct for running the reboot-node command
+type rebootNodeOptions struct {
+	clusterID       string
+	nodeName        string
+	reason          string
+	unsafeSkipDrain bool
+	drainTimeout    time.Duration
+	readyTimeout    time.Duration
+
+	out     io.Writer
+	factory clients.Factory
+	cluster *cmv1.Cluster
+	client  client.Client
+
+	ensureSession func(ctx context.Context, clusterID string) error
+	cordon        func(nodeName string, reason elevate.Reason) error
+	uncordon      func(nodeName string, reason elevate.Reason) error
+	drain         func(nodeName string, reason elevate.Reason, force bool, timeout time.Duration) error
+	// reboot reboots the instance of the node with the provider ID through the API of its cloud provider
+	reboot func(ctx context.Context, providerID string) error
+	// waitForReboot waits for the node to be Ready again after the reboot, once its boot ID changed
+	waitForReboot func(ctx context.Context, nodeName string, bootID string) error
+}
+
+// NewCmdRebootNode implements the reboot-node command to reboot a node of a cluster without downtime
+func NewCmdRebootNode(factory clients.Factory) *cobra.Command {
+	opts := &rebootNodeOptions{factory: factory}
+
+	rebootCmd := &cobra.Command{
+		Use:   "reboot-node",
+		Short: "Reboot a node of a cluster through its cloud provider, without downtime",
+		Long: `Reboot a node of a cluster through its cloud provider, without downtime.
+
+  The node is cordoned and drained like "osdctl cluster node drain", its instance is rebooted through the API of
+  the cloud provider, and the node is uncordoned once it is Ready again after the reboot. The reboot is refused when
+  another node of the same role isn't Ready, unless confirmed.
+
+  When a step fails, it can be retried, skipped or the reboot cancelled. A node which was already cordoned is left
+  cordoned after the reboot.
+
+  AWS instances are rebooted with the credentials of the cluster from backplane, GCP instances are reset with the
+  application default credentials, e.g. those of "gcloud auth application-default login".
+
+  With --unsafe-skip-drain, the node isn't drained and its pods are stopped abruptly by the reboot, e.g. when the
+  drain can't complete on an unhealthy node.
+
+  Requires previous login to the api server via "ocm backplane login".`,
+		Example: `  # Reboot a node
+  osdctl cluster reboot-node --cluster-id ${CLUSTER_ID} --node ip-10-0-1-2.ec2.internal --reason "OHSS-1234"
+
+  # Reboot an unhealthy node whose pods can't be evicted, without draining it
+  osdctl cluster reboot-node --cluster-id ${CLUSTER_ID} --node ip-10-0-1-2.ec2.internal --reason "OHSS-1234" --unsafe-skip-drain`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			defer opts.factory.Close()
+			opts.out = cmd.OutOrStdout()
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	rebootCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "The internal ID of the cluster of the node")
+	rebootCmd.Flags().StringVar(&opts.nodeName, "node", "", "The name of the node to reboot")
+	rebootCmd.Flags().StringVar(&opts.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	rebootCmd.Flags().BoolVar(&opts.unsafeSkipDrain, "unsafe-skip-drain", false, "Reboot the node without draining it, its pods are stopped abruptly")
+	rebootCmd.Flags().DurationVar(&opts.drainTimeout, "drain-timeout", 0, "How long to wait for the evictions of the drain before giving up, zero means infinite")
+	rebootCmd.Flags().DurationVar(&opts.readyTimeout, "ready-timeout", defaultRebootReadyTimeout, "How long to wait for the node to be Ready again after the reboot")
+	_ = rebootCmd.MarkFlagRequired("cluster-id")
+	_ = rebootCmd.MarkFlagRequired("node")
+	_ = rebootCmd.MarkFlagRequired("reason")
+
+	return danger.Annotate(rebootCmd, danger.High)
+}
+
+func (o *rebootNodeOptions) complete() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	if err := utils.ValidateReason(elevate.ReasonFlag, o.reason); err != nil {
+		return err
+	}
+
+	cluster, err := o.factory.Cluster(o.clusterID)
+	if err != nil {
+		return err
+	}
+	o.cluster = cluster
+	o.clusterID = cluster.ID()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	o.client, err = o.factory.KubeClient(o.clusterID, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	o.ensureSession = k8s.EnsureBackplaneSession
+	o.cordon = Cordon
+	o.uncordon = Uncordon
+	o.drain = Drain
+	o.reboot = o.rebootInstance
+	o.waitForReboot = func(ctx context.Context, nodeName string, bootID string) error {
+		return WaitForReady(ctx, o.client, nodeName, o.readyTimeout, func(node *corev1.Node) bool {
+			return node.Status.NodeInfo.BootID != bootID
+		})
+	}
+	return nil
+}
+
+func (o *rebootNodeOptions) run(ctx context.Context) error {
+	// The node is cordoned and drained through the current backplane login, which must be the one of the cluster
+	if err := o.ensureSession(ctx, o.clusterID); err != nil {
+		return err
+	}
+
+	target := &corev1.Node{}
+	if err := o.client.Get(ctx, client.ObjectKey{Name: o.nodeName}, target); err != nil {
+		return fmt.Errorf("failed to get node %s: %w", o.nodeName, err)
+	}
+	if target.Spec.ProviderID == "" {
+		return fmt.Errorf("node %s has no provider ID, its instance is unknown", o.nodeName)
+	}
+	role := nodeRole(target)
+	fmt.Fprintf(o.out, "Node %s (%s) runs on instance %s\n", o.nodeName, role, target.Spec.ProviderID)
+
+	if err := o.checkPeers(ctx, role); err != nil {
+		return err
+	}
+
+	wasCordoned := target.Spec.Unschedulable
+	if wasCordoned {
+		fmt.Fprintf(o.out, "Node %s is already cordoned, it is left cordoned after the reboot.\n", o.nodeName)
+	}
+	if o.unsafeSkipDrain {
+		fmt.Fprintf(o.out, "WARNING: --unsafe-skip-drain is set, the pods of node %s are stopped abruptly by the reboot.\n", o.nodeName)
+	}
+	if ok, err := danger.Confirm(danger.High, o.cluster); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("reboot cancelled")
+	}
+
+	reason := elevate.Reason{Ticket: o.reason, Command: "cluster reboot-node"}
+	cordoned := wasCordoned
+	steps := []struct {
+		name string
+		skip bool
+		fn   func() error
+	}{
+		{name: "cordoning the node", skip: wasCordoned, fn: func() error {
+			if err := o.cordon(o.nodeName, reason); err != nil {
+				return err
+			}
+			cordoned = true
+			return nil
+		}},
+		{name: "draining the node", skip: o.unsafeSkipDrain, fn: func() error {
+			return o.drain(o.nodeName, reason, false, o.drainTimeout)
+		}},
+		{name: "rebooting the instance", fn: func() error {
+			return o.reboot(ctx, target.Spec.ProviderID)
+		}},
+		{name: "waiting for the node to be ready", fn: func() error {
+			return o.waitForReboot(ctx, o.nodeName, target.Status.NodeInfo.BootID)
+		}},
+		{name: "uncordoning the node", skip: wasCordoned, fn: func() error {
+			if err := o.uncordon(o.nodeName, reason); err != nil {
+				return err
+			}
+			cordoned = false
+			return nil
+		}},
+	}
+	for _, step := range steps {
+		if step.skip {
+			continue
+		}
+		if err := o.runStep(step.name, step.fn); err != nil {
+			if cordoned {
+				return fmt.Errorf("%w, node %s is still cordoned", err, o.nodeName)
+			}
+			return err
+		}
+	}
+
+	printer.PrintlnGreen("Node", o.nodeName, "rebooted")
+	return nil
+}
+
+// runStep runs a step of the reboot, asking whether to retry it, skip it or cancel the reboot when it fails
+func (o *rebootNodeOptions) runStep(name string, fn func() error) error {
+	for {
+		printer.PrintlnGreen(strings.ToUpper(name[:1]) + name[1:])
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		fmt.Fprintf(o.out, "Failed %s: %v\n", name, err)
+
+		response, promptErr := prompt.Select(fmt.Sprintf("Do you want to retry %[1]s, skip %[1]s or cancel the reboot?", name), []string{"retry", "skip", "cancel"}, "cancel")
+		if promptErr != nil {
+			return promptErr
+		}
+		switch response {
+		case "retry":
+			continue
+		case "skip":
+			fmt.Fprintf(o.out, "Skipping %s\n", name)
+			return nil
+		default:
+			return fmt.Errorf("reboot cancelled after failing %s: %w", name, err)
+		}
+	}
+}
+
+// nodeRole returns the role of the node, by the priority of nodeRoles
+func nodeRole(node *corev1.Node) string {
+	for _, role := range nodeRoles {
+		if _, ok := node.Labels[nodeRoleLabelPrefix+role]; ok {
+			return role
+		}
+	}
+	return "worker"
+}
+
+// checkPeers asks for confirmation when other nodes of the role aren't Ready, rebooting the node could then
+// cause downtime, e.g. a loss of the etcd quorum for control plane nodes
+func (o *rebootNodeOptions) checkPeers(ctx context.Context, role string) error {
+	nodes := &corev1.NodeList{}
+	if err := o.client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list the nodes: %w", err)
+	}
+	var notReady []string
+	for _, node := range nodes.Items {
+		if node.Name != o.nodeName && nodeRole(&node) == role && !IsReady(&node) {
+			notReady = append(notReady, node.Name)
+		}
+	}
+	if len(notReady) == 0 {
+		return nil
+	}
+	sort.Strings(notReady)
+	fmt.Fprintf(o.out, "WARNING: %d other %s node(s) aren't Ready, rebooting node %s could cause downtime: %s\n", len(notReady), role, o.nodeName, strings.Join(notReady, ", "))
+	if !prompt.Confirm("Reboot the node anyway?", false) {
+		return errors.New("reboot cancelled")
+	}
+	return nil
+}
+
+// rebootInstance reboots the instance of the node through the API of its cloud provider
+func (o *rebootNodeOptions) rebootInstance(ctx context.Context, providerID string) error {
+	provider, segments, err := parseProviderID(providerID)
+	if err != nil {
+		return err
+	}
+
+	switch provider {
+	case "aws":
+		// aws:///<zone>/<instance ID>
+		connection, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer connection.Close()
+		cfg, err := osdCloud.CreateAWSV2Config(connection, o.cluster)
+		if err != nil {
+			return fmt.Errorf("failed to get the AWS credentials of the cluster from backplane: %w", err)
+		}
+		_, err = ec2.NewFromConfig(cfg).RebootInstances(ctx, &ec2.RebootInstancesInput{InstanceIds: []string{segments[len(segments)-1]}})
+		return err
+	case "gce":
+		// gce://<project>/<zone>/<instance name>
+		if len(segments) != 3 {
+			return fmt.Errorf("unexpected GCP provider ID %q, expected gce://<project>/<zone>/<instance>", providerID)
+		}
+		gcpClient, err := gcp.NewGcpClient(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create the GCP client: %w", err)
+		}
+		defer gcpClient.Close()
+		return gcpClient.ResetInstance(ctx, segments[0], segments[1], segments[2])
+	default:
+		return fmt.Errorf("cloud provider %q of node %s is not supported, only AWS and GCP instances can be rebooted", provider, o.nodeName)
+	}
+}
+
+// parseProviderID returns the cloud provider and the path segments of the provider ID of a node, e.g.
+// aws:///us-east-1a/i-0123456789abcdef0 or gce://my-project/us-east1-b/my-instance
+func parseProviderID(providerID string) (string, []string, error) {
+	provider, path, ok := strings.Cut(providerID, "://")
+	if !ok || provider == "" {
+		return "", nil, fmt.Errorf("invalid provider ID %q", providerID)
+	}
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return "", nil, fmt.Errorf("invalid provider ID %q", providerID)
+	}
+	return provider, segments, nil
+}
@@ -0,0 +1,187 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newNode(name, role string, ready bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{nodeRoleLabelPrefix + role: ""}},
+		Spec:       corev1.NodeSpec{ProviderID: "aws:///us-east-1a/i-" + name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			NodeInfo:   corev1.NodeSystemInfo{BootID: "boot-1"},
+		},
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	assert.True(t, IsReady(newNode("node-a", "worker", true)))
+	assert.False(t, IsReady(newNode("node-a", "worker", false)))
+	assert.False(t, IsReady(&corev1.Node{}))
+}
+
+func TestWaitForReady(t *testing.T) {
+	defer func(interval time.Duration) { readyPollInterval = interval }(readyPollInterval)
+	readyPollInterval = time.Millisecond
+
+	c := newFakeClient(t, newNode("node-a", "worker", true))
+	assert.NoError(t, WaitForReady(context.Background(), c, "node-a", time.Second, nil))
+
+	rebooted := func(node *corev1.Node) bool { return node.Status.NodeInfo.BootID != "boot-1" }
+	assert.ErrorContains(t, WaitForReady(context.Background(), c, "node-a", 50*time.Millisecond, rebooted), "node node-a did not become ready")
+}
+
+func TestParseProviderID(t *testing.T) {
+	provider, segments, err := parseProviderID("aws:///us-east-1a/i-0123456789abcdef0")
+	require.NoError(t, err)
+	assert.Equal(t, "aws", provider)
+	assert.Equal(t, []string{"us-east-1a", "i-0123456789abcdef0"}, segments)
+
+	provider, segments, err = parseProviderID("gce://my-project/us-east1-b/my-instance")
+	require.NoError(t, err)
+	assert.Equal(t, "gce", provider)
+	assert.Equal(t, []string{"my-project", "us-east1-b", "my-instance"}, segments)
+
+	for _, invalid := range []string{"", "i-0123", "aws://", "://zone/instance"} {
+		_, _, err = parseProviderID(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRebootNodeRun(t *testing.T) {
+	cluster, err := cmv1.NewCluster().ID("cluster-id").Name("my-cluster").Build()
+	require.NoError(t, err)
+
+	// newOptions returns options recording the steps of the reboot, failing the steps of fail once
+	newOptions := func(t *testing.T, target *corev1.Node, fail map[string]bool, objects ...client.Object) (*rebootNodeOptions, *[]string) {
+		var steps []string
+		record := func(step string) error {
+			steps = append(steps, step)
+			if fail[step] {
+				delete(fail, step)
+				return errors.New(step + " failed")
+			}
+			return nil
+		}
+		reason := elevate.Reason{Ticket: "OHSS-1234", Command: "cluster reboot-node"}
+		return &rebootNodeOptions{
+			clusterID:     "cluster-id",
+			nodeName:      target.Name,
+			reason:        "OHSS-1234",
+			out:           &bytes.Buffer{},
+			cluster:       cluster,
+			client:        newFakeClient(t, append(objects, target)...),
+			ensureSession: func(context.Context, string) error { return nil },
+			cordon: func(nodeName string, r elevate.Reason) error {
+				assert.Equal(t, reason, r)
+				return record("cordon " + nodeName)
+			},
+			uncordon: func(nodeName string, r elevate.Reason) error {
+				assert.Equal(t, reason, r)
+				return record("uncordon " + nodeName)
+			},
+			drain: func(nodeName string, r elevate.Reason, force bool, _ time.Duration) error {
+				assert.Equal(t, reason, r)
+				assert.False(t, force)
+				return record("drain " + nodeName)
+			},
+			reboot: func(_ context.Context, providerID string) error {
+				return record("reboot " + providerID)
+			},
+			waitForReboot: func(_ context.Context, nodeName string, bootID string) error {
+				assert.Equal(t, "boot-1", bootID)
+				return record("wait " + nodeName)
+			},
+		}, &steps
+	}
+
+	t.Run("cordons, drains, reboots and uncordons the node", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("my-cluster\n"), io.Discard)()
+
+		opts, steps := newOptions(t, newNode("node-a", "worker", true), nil, newNode("node-b", "worker", true))
+		require.NoError(t, opts.run(context.Background()))
+		assert.Equal(t, []string{"cordon node-a", "drain node-a", "reboot aws:///us-east-1a/i-node-a", "wait node-a", "uncordon node-a"}, *steps)
+	})
+
+	t.Run("unsafe skip drain", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("my-cluster\n"), io.Discard)()
+
+		opts, steps := newOptions(t, newNode("node-a", "worker", true), nil)
+		opts.unsafeSkipDrain = true
+		require.NoError(t, opts.run(context.Background()))
+		assert.Equal(t, []string{"cordon node-a", "reboot aws:///us-east-1a/i-node-a", "wait node-a", "uncordon node-a"}, *steps)
+		assert.Contains(t, opts.out.(*bytes.Buffer).String(), "WARNING: --unsafe-skip-drain is set")
+	})
+
+	t.Run("an already cordoned node is left cordoned", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("my-cluster\n"), io.Discard)()
+
+		target := newNode("node-a", "worker", true)
+		target.Spec.Unschedulable = true
+		opts, steps := newOptions(t, target, nil)
+		require.NoError(t, opts.run(context.Background()))
+		assert.Equal(t, []string{"drain node-a", "reboot aws:///us-east-1a/i-node-a", "wait node-a"}, *steps)
+	})
+
+	t.Run("failed steps are retried or skipped", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("my-cluster\nretry\nskip\n"), io.Discard)()
+
+		opts, steps := newOptions(t, newNode("node-a", "worker", true), map[string]bool{"drain node-a": true, "wait node-a": true})
+		require.NoError(t, opts.run(context.Background()))
+		assert.Equal(t, []string{"cordon node-a", "drain node-a", "drain node-a", "reboot aws:///us-east-1a/i-node-a", "wait node-a", "uncordon node-a"}, *steps)
+	})
+
+	t.Run("cancelling a failed step reports the node is still cordoned", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("my-cluster\ncancel\n"), io.Discard)()
+
+		opts, steps := newOptions(t, newNode("node-a", "worker", true), map[string]bool{"reboot aws:///us-east-1a/i-node-a": true})
+		err := opts.run(context.Background())
+		assert.EqualError(t, err, "reboot cancelled after failing rebooting the instance: reboot aws:///us-east-1a/i-node-a failed, node node-a is still cordoned")
+		assert.Equal(t, []string{"cordon node-a", "drain node-a", "reboot aws:///us-east-1a/i-node-a"}, *steps)
+	})
+
+	t.Run("other nodes of the role not ready", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("n\n"), io.Discard)()
+
+		opts, steps := newOptions(t, newNode("master-0", "master", true), nil,
+			newNode("master-1", "master", false), newNode("worker-0", "worker", false))
+		assert.EqualError(t, opts.run(context.Background()), "reboot cancelled")
+		assert.Empty(t, *steps)
+		assert.Contains(t, opts.out.(*bytes.Buffer).String(), "1 other master node(s) aren't Ready, rebooting node master-0 could cause downtime: master-1")
+	})
+
+	t.Run("wrong cluster name", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("other-cluster\n"), io.Discard)()
+
+		opts, steps := newOptions(t, newNode("node-a", "worker", true), nil)
+		assert.EqualError(t, opts.run(context.Background()), "reboot cancelled")
+		assert.Empty(t, *steps)
+	})
+
+	t.Run("node without provider ID", func(t *testing.T) {
+		target := newNode("node-a", "worker", true)
+		target.Spec.ProviderID = ""
+		opts, _ := newOptions(t, target, nil)
+		assert.EqualError(t, opts.run(context.Background()), "node node-a has no provider ID, its instance is unknown")
+	})
+}
@@ -0,0 +1,23 @@
+package oidc
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdOidc implements the oidc command to inspect the OIDC configuration of STS and HCP clusters
+// osdctl cluster oidc verify --cluster-id <cluster-id>
+func NewCmdOidc() *cobra.Command {
+	oidcCmd := &cobra.Command{
+		Use:               "oidc",
+		Short:             "Inspect the OIDC configuration of STS and HCP clusters",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	oidcCmd.AddCommand(newCmdVerify())
+
+	return oidcCmd
+}
@@ -0,0 +1,463 @@
+package oidc
+
+import (
+	"crypto/sha1" // #nosec G505 -- IAM identifies the certificates of OIDC providers by their SHA-1 thumbprint
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	internalutils "github.com/openshift/osdctl/internal/utils"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	statusPass = "PASS"
+	statusWarn = "WARN"
+	statusFail = "FAIL"
+	statusSkip = "SKIP"
+
+	discoveryPath = ".well-known/openid-configuration"
+
+	webIdentityAction = "sts:AssumeRoleWithWebIdentity"
+)
+
+// audiences are the client IDs the OIDC provider of OpenShift clusters is created with
+var audiences = []string{"openshift", "sts.amazonaws.com"}
+
+// verifyOptions defines the struct for running the oidc verify command
+type verifyOptions struct {
+	clusterID  string
+	awsProfile string
+	output     string
+
+	out            io.Writer
+	cluster        *cmv1.Cluster
+	awsClient      awsprovider.Client
+	httpClient     *http.Client
+	isOnline       func(url.URL) error
+	thumbprintFunc func(host string) (string, error)
+}
+
+// check is the result of verifying one piece of the OIDC configuration
+type check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Details string `json:"details"`
+}
+
+// discoveryDocument is the subset of the OIDC discovery document which is verified
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JwksURI string `json:"jwks_uri"`
+}
+
+// trustPolicy is the subset of an IAM role trust policy which is verified
+type trustPolicy struct {
+	Statement []trustStatement `json:"Statement"`
+}
+
+type trustStatement struct {
+	Effect    string                              `json:"Effect"`
+	Action    stringOrSlice                       `json:"Action"`
+	Principal map[string]stringOrSlice            `json:"Principal"`
+	Condition map[string]map[string]stringOrSlice `json:"Condition"`
+}
+
+// stringOrSlice is a policy element which is either a single string or a list of strings
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = []string{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// newCmdVerify implements oidc verify
+func newCmdVerify() *cobra.Command {
+	ops := &verifyOptions{
+		out:            os.Stdout,
+		httpClient:     &http.Client{Timeout: 10 * time.Second},
+		isOnline:       internalutils.IsOnline,
+		thumbprintFunc: topCertificateThumbprint,
+	}
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the OIDC configuration of an STS or HCP cluster",
+		Long: `Verifies every piece of the OIDC configuration the operators of an STS or HCP cluster rely on to
+assume their roles, and reports which one is broken:
+
+  - the issuer URL is reachable and serves a discovery document for the same issuer
+  - the JWKS of the issuer holds signing keys
+  - the discovery document is present in the issuer S3 bucket, when served straight from S3
+  - the IAM OIDC provider of the issuer exists, with the expected audiences and certificate thumbprint
+  - the trust policy of every operator role trusts the IAM OIDC provider for the operator service account
+
+The command exits with an error when any check fails.`,
+		Example: `  # Verify the OIDC configuration of a cluster
+  osdctl cluster oidc verify --cluster-id ${CLUSTER_ID}
+
+  # Verify the OIDC configuration with a specific AWS profile and print the results as JSON
+  osdctl cluster oidc verify --cluster-id ${CLUSTER_ID} -p rhcontrol -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	verifyCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID whose OIDC configuration should be verified")
+	verifyCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
+	verifyCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Output format: table or json")
+
+	_ = verifyCmd.MarkFlagRequired("cluster-id")
+
+	return verifyCmd
+}
+
+func (o *verifyOptions) complete() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	return utils.ValidateClusterKey("cluster-id", o.clusterID)
+}
+
+func (o *verifyOptions) run() error {
+	if o.cluster == nil {
+		ocmClient, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		o.cluster, err = utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+		ocmClient.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	issuerURL := utils.OIDCIssuerURL(o.cluster)
+	if issuerURL == "" {
+		return fmt.Errorf("cluster %s has no OIDC issuer, it is not an STS or HCP cluster", o.cluster.ID())
+	}
+	issuer, err := url.Parse(issuerURL)
+	if err != nil {
+		return fmt.Errorf("invalid OIDC issuer URL %q: %w", issuerURL, err)
+	}
+
+	if o.awsClient == nil {
+		o.awsClient, err = osdCloud.GenerateAWSClientForCluster(o.awsProfile, o.cluster.ID())
+		if err != nil {
+			return err
+		}
+	}
+
+	discovery, jwksURI := o.checkDiscovery(issuer)
+	checks := []check{discovery, o.checkJWKS(jwksURI), o.checkS3Bucket(issuer)}
+	provider, providerARN := o.checkProvider(issuer)
+	checks = append(checks, provider)
+	checks = append(checks, o.checkOperatorRoles(issuer, providerARN)...)
+
+	if err := o.printChecks(checks); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == statusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d OIDC checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// issuerHostPath returns the issuer the way IAM references it, without its scheme
+func issuerHostPath(issuer *url.URL) string {
+	return strings.TrimSuffix(issuer.Host+issuer.Path, "/")
+}
+
+// checkDiscovery verifies the discovery document of the issuer and returns the URI of its JWKS
+func (o *verifyOptions) checkDiscovery(issuer *url.URL) (check, string) {
+	c := check{Name: "Issuer discovery document"}
+	discoveryURL := issuer.JoinPath(discoveryPath)
+
+	if err := o.isOnline(*discoveryURL); err != nil {
+		c.Status, c.Details = statusFail, fmt.Sprintf("%s is not reachable: %v", discoveryURL, err)
+		return c, ""
+	}
+
+	body, header, err := o.get(discoveryURL.String())
+	if err != nil {
+		c.Status, c.Details = statusFail, err.Error()
+		return c, ""
+	}
+	var document discoveryDocument
+	if err := json.Unmarshal(body, &document); err != nil {
+		c.Status, c.Details = statusFail, fmt.Sprintf("%s is not a valid discovery document: %v", discoveryURL, err)
+		return c, ""
+	}
+	if strings.TrimSuffix(document.Issuer, "/") != strings.TrimSuffix(issuer.String(), "/") {
+		c.Status, c.Details = statusFail, fmt.Sprintf("the discovery document is for issuer %q instead of %q", document.Issuer, issuer)
+		return c, document.JwksURI
+	}
+
+	c.Status, c.Details = statusPass, fmt.Sprintf("served from %s", discoveryURL)
+	if header.Get("X-Amz-Cf-Id") != "" {
+		c.Details += " through CloudFront"
+	}
+	return c, document.JwksURI
+}
+
+// checkJWKS verifies the JWKS of the issuer holds keys
+func (o *verifyOptions) checkJWKS(jwksURI string) check {
+	c := check{Name: "Issuer JWKS"}
+	if jwksURI == "" {
+		c.Status, c.Details = statusFail, "the discovery document doesn't reference a JWKS"
+		return c
+	}
+
+	body, _, err := o.get(jwksURI)
+	if err != nil {
+		c.Status, c.Details = statusFail, err.Error()
+		return c
+	}
+	var jwks struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		c.Status, c.Details = statusFail, fmt.Sprintf("%s is not a valid JWKS: %v", jwksURI, err)
+		return c
+	}
+	if len(jwks.Keys) == 0 {
+		c.Status, c.Details = statusFail, fmt.Sprintf("%s holds no keys, service account tokens can't be verified", jwksURI)
+		return c
+	}
+
+	c.Status, c.Details = statusPass, fmt.Sprintf("%d key(s) served from %s", len(jwks.Keys), jwksURI)
+	return c
+}
+
+// checkS3Bucket verifies the discovery document is stored in the issuer bucket, when served straight from S3
+func (o *verifyOptions) checkS3Bucket(issuer *url.URL) check {
+	c := check{Name: "Issuer S3 bucket"}
+	bucket := utils.OIDCS3Bucket(issuer.String())
+	if bucket == "" {
+		c.Status, c.Details = statusSkip, "the issuer isn't served straight from S3 (managed OIDC configuration or CloudFront distribution)"
+		return c
+	}
+
+	prefix := strings.TrimPrefix(issuer.Path, "/")
+	if strings.HasPrefix(issuer.Hostname(), "s3.") || strings.HasPrefix(issuer.Hostname(), "s3-") {
+		prefix = strings.TrimPrefix(strings.TrimPrefix(prefix, bucket), "/")
+	}
+	key := path.Join(prefix, discoveryPath)
+
+	object, err := o.awsClient.GetObject(&s3.GetObjectInput{Bucket: awsSdk.String(bucket), Key: awsSdk.String(key)})
+	if err != nil {
+		c.Status, c.Details = statusFail, fmt.Sprintf("failed to get s3://%s/%s: %v", bucket, key, err)
+		return c
+	}
+	_ = object.Body.Close()
+
+	c.Status, c.Details = statusPass, fmt.Sprintf("s3://%s/%s exists", bucket, key)
+	return c
+}
+
+// checkProvider verifies the IAM OIDC provider of the issuer and returns its ARN
+func (o *verifyOptions) checkProvider(issuer *url.URL) (check, string) {
+	c := check{Name: "IAM OIDC provider"}
+	hostPath := issuerHostPath(issuer)
+
+	providers, err := o.awsClient.ListOpenIDConnectProviders(&iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		c.Status, c.Details = statusFail, fmt.Sprintf("failed to list the IAM OIDC providers: %v", err)
+		return c, ""
+	}
+	providerARN := ""
+	for _, provider := range providers.OpenIDConnectProviderList {
+		if strings.HasSuffix(awsSdk.ToString(provider.Arn), ":oidc-provider/"+hostPath) {
+			providerARN = awsSdk.ToString(provider.Arn)
+			break
+		}
+	}
+	if providerARN == "" {
+		c.Status, c.Details = statusFail, fmt.Sprintf("no IAM OIDC provider exists for %s, operators can't assume their roles", hostPath)
+		return c, ""
+	}
+
+	provider, err := o.awsClient.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: awsSdk.String(providerARN)})
+	if err != nil {
+		c.Status, c.Details = statusFail, fmt.Sprintf("failed to get %s: %v", providerARN, err)
+		return c, providerARN
+	}
+	if !slices.ContainsFunc(provider.ClientIDList, func(id string) bool { return slices.Contains(audiences, id) }) {
+		c.Status, c.Details = statusFail, fmt.Sprintf("%s has none of the %s audiences", providerARN, strings.Join(audiences, ", "))
+		return c, providerARN
+	}
+
+	thumbprint, err := o.thumbprintFunc(issuer.Hostname())
+	switch {
+	case err != nil:
+		c.Status, c.Details = statusWarn, fmt.Sprintf("%s exists, but the certificate of %s can't be retrieved: %v", providerARN, issuer.Hostname(), err)
+	case !slices.ContainsFunc(provider.ThumbprintList, func(t string) bool { return strings.EqualFold(t, thumbprint) }):
+		// IAM ignores the thumbprints of issuers with a certificate from a CA it trusts, e.g. S3 and CloudFront
+		c.Status, c.Details = statusWarn, fmt.Sprintf("%s doesn't list the thumbprint %s of the issuer certificate chain", providerARN, thumbprint)
+	default:
+		c.Status, c.Details = statusPass, providerARN
+	}
+	return c, providerARN
+}
+
+// checkOperatorRoles verifies the trust policy of every operator role trusts the IAM OIDC provider for its service account
+func (o *verifyOptions) checkOperatorRoles(issuer *url.URL, providerARN string) []check {
+	hostPath := issuerHostPath(issuer)
+
+	var checks []check
+	for _, role := range o.cluster.AWS().STS().OperatorIAMRoles() {
+		c := check{Name: fmt.Sprintf("Operator role %s/%s", role.Namespace(), role.Name())}
+		roleName := role.RoleARN()[strings.LastIndex(role.RoleARN(), "/")+1:]
+
+		output, err := o.awsClient.GetRole(&iam.GetRoleInput{RoleName: awsSdk.String(roleName)})
+		if err != nil {
+			c.Status, c.Details = statusFail, fmt.Sprintf("failed to get %s: %v", role.RoleARN(), err)
+			checks = append(checks, c)
+			continue
+		}
+
+		subject := fmt.Sprintf("system:serviceaccount:%s:%s", role.Namespace(), role.ServiceAccount())
+		if err := verifyTrustPolicy(awsSdk.ToString(output.Role.AssumeRolePolicyDocument), providerARN, hostPath, subject); err != nil {
+			c.Status, c.Details = statusFail, fmt.Sprintf("%s: %v", role.RoleARN(), err)
+		} else {
+			c.Status, c.Details = statusPass, role.RoleARN()
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// verifyTrustPolicy returns an error explaining why the URL encoded trust policy doesn't let the subject
+// assume the role with a web identity token of the issuer
+func verifyTrustPolicy(document, providerARN, issuerHostPath, subject string) error {
+	decoded, err := url.QueryUnescape(document)
+	if err != nil {
+		return fmt.Errorf("failed to decode the trust policy: %w", err)
+	}
+	var policy trustPolicy
+	if err := json.Unmarshal([]byte(decoded), &policy); err != nil {
+		return fmt.Errorf("failed to parse the trust policy: %w", err)
+	}
+
+	trustsProvider := false
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" || !slices.Contains(statement.Action, webIdentityAction) {
+			continue
+		}
+		if !slices.ContainsFunc(statement.Principal["Federated"], func(federated string) bool {
+			if providerARN != "" {
+				return federated == providerARN
+			}
+			return strings.HasSuffix(federated, ":oidc-provider/"+issuerHostPath)
+		}) {
+			continue
+		}
+		trustsProvider = true
+
+		for operator, conditions := range statement.Condition {
+			for _, pattern := range conditions[issuerHostPath+":sub"] {
+				if pattern == subject {
+					return nil
+				}
+				if strings.HasPrefix(operator, "StringLike") {
+					if matched, _ := path.Match(pattern, subject); matched {
+						return nil
+					}
+				}
+			}
+		}
+	}
+
+	if !trustsProvider {
+		return fmt.Errorf("the trust policy doesn't allow %s for the OIDC provider of %s", webIdentityAction, issuerHostPath)
+	}
+	return fmt.Errorf("the trust policy doesn't allow the subject %s", subject)
+}
+
+// get returns the body and headers of a successful GET request to rawURL
+func (o *verifyOptions) get(rawURL string) ([]byte, http.Header, error) {
+	resp, err := o.httpClient.Get(rawURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to get %s: %s", rawURL, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	return body, resp.Header, nil
+}
+
+// topCertificateThumbprint returns the SHA-1 thumbprint of the top certificate of the chain served by host,
+// which is the one IAM expects in the thumbprints of an OIDC provider
+func topCertificateThumbprint(host string) (string, error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, "443"), &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	certificates := conn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return "", errors.New("no certificate served")
+	}
+	sum := sha1.Sum(certificates[len(certificates)-1].Raw) // #nosec G401 -- see the import
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (o *verifyOptions) printChecks(checks []check) error {
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(checks)
+	}
+
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, c := range checks {
+		table.AddRow([]string{c.Name, c.Status, c.Details})
+	}
+	return table.Flush()
+}
@@ -0,0 +1,150 @@
+package oidc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func trustPolicyDocument(providerARN, hostPath, operator, subject string) string {
+	return url.QueryEscape(fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Federated":%q},`+
+		`"Action":"sts:AssumeRoleWithWebIdentity","Condition":{%q:{%q:[%q]}}}]}`, providerARN, operator, hostPath+":sub", subject))
+}
+
+func TestVerifyTrustPolicy(t *testing.T) {
+	const (
+		providerARN = "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"
+		hostPath    = "oidc.example.com/abc"
+		subject     = "system:serviceaccount:openshift-ingress-operator:ingress-operator"
+	)
+
+	tests := []struct {
+		name     string
+		document string
+		errMsg   string
+	}{
+		{"exact subject", trustPolicyDocument(providerARN, hostPath, "StringEquals", subject), ""},
+		{"wildcard subject", trustPolicyDocument(providerARN, hostPath, "StringLike", "system:serviceaccount:openshift-ingress-operator:*"), ""},
+		{"other subject", trustPolicyDocument(providerARN, hostPath, "StringEquals", "system:serviceaccount:other:sa"), "doesn't allow the subject"},
+		{"other provider", trustPolicyDocument("arn:aws:iam::123456789012:oidc-provider/old.example.com/abc", hostPath, "StringEquals", subject), "for the OIDC provider of oidc.example.com/abc"},
+		{"invalid document", url.QueryEscape("{"), "failed to parse the trust policy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyTrustPolicy(tt.document, providerARN, hostPath, subject)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestVerifyRun(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/abc/.well-known/openid-configuration":
+			_, _ = fmt.Fprintf(w, `{"issuer":"%s/abc","jwks_uri":"%s/abc/keys.json"}`, server.URL, server.URL)
+		case "/abc/keys.json":
+			_, _ = io.WriteString(w, `{"keys":[{"kid":"1"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	issuer, err := url.Parse(server.URL + "/abc")
+	require.NoError(t, err)
+	hostPath := issuerHostPath(issuer)
+	providerARN := "arn:aws:iam::123456789012:oidc-provider/" + hostPath
+
+	cluster, err := cmv1.NewCluster().ID("cluster-id").AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+		OidcConfig(cmv1.NewOidcConfig().IssuerUrl(issuer.String())).
+		OperatorIAMRoles(
+			cmv1.NewOperatorIAMRole().Namespace("openshift-ingress-operator").Name("cloud-credentials").ServiceAccount("ingress-operator").
+				RoleARN("arn:aws:iam::123456789012:role/prefix-openshift-ingress-operator-cloud-credentials"),
+			cmv1.NewOperatorIAMRole().Namespace("openshift-image-registry").Name("installer-cloud-credentials").ServiceAccount("cluster-image-registry-operator").
+				RoleARN("arn:aws:iam::123456789012:role/prefix-openshift-image-registry-installer-cloud-credentials"),
+		))).Build()
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	awsClient := mock.NewMockClient(ctrl)
+	awsClient.EXPECT().ListOpenIDConnectProviders(gomock.Any()).Return(&iam.ListOpenIDConnectProvidersOutput{
+		OpenIDConnectProviderList: []iamtypes.OpenIDConnectProviderListEntry{
+			{Arn: awsSdk.String("arn:aws:iam::123456789012:oidc-provider/other.example.com")},
+			{Arn: awsSdk.String(providerARN)},
+		},
+	}, nil)
+	awsClient.EXPECT().GetOpenIDConnectProvider(gomock.Any()).Return(&iam.GetOpenIDConnectProviderOutput{
+		ClientIDList:   []string{"openshift", "sts.amazonaws.com"},
+		ThumbprintList: []string{"ABCDEF"},
+	}, nil)
+	awsClient.EXPECT().GetRole(&iam.GetRoleInput{RoleName: awsSdk.String("prefix-openshift-ingress-operator-cloud-credentials")}).Return(&iam.GetRoleOutput{
+		Role: &iamtypes.Role{AssumeRolePolicyDocument: awsSdk.String(trustPolicyDocument(providerARN, hostPath, "StringEquals",
+			"system:serviceaccount:openshift-ingress-operator:ingress-operator"))},
+	}, nil)
+	awsClient.EXPECT().GetRole(&iam.GetRoleInput{RoleName: awsSdk.String("prefix-openshift-image-registry-installer-cloud-credentials")}).
+		Return(nil, errors.New("NoSuchEntity"))
+
+	out := &bytes.Buffer{}
+	o := &verifyOptions{
+		output:         "json",
+		out:            out,
+		cluster:        cluster,
+		awsClient:      awsClient,
+		httpClient:     server.Client(),
+		isOnline:       func(url.URL) error { return nil },
+		thumbprintFunc: func(string) (string, error) { return "abcdef", nil },
+	}
+
+	err = o.run()
+	assert.EqualError(t, err, "1 of 6 OIDC checks failed")
+
+	var checks []check
+	require.NoError(t, json.Unmarshal(out.Bytes(), &checks))
+	require.Len(t, checks, 6)
+	statuses := map[string]string{}
+	for _, c := range checks {
+		statuses[c.Name] = c.Status
+	}
+	assert.Equal(t, map[string]string{
+		"Issuer discovery document": statusPass,
+		"Issuer JWKS":               statusPass,
+		"Issuer S3 bucket":          statusSkip,
+		"IAM OIDC provider":         statusPass,
+		"Operator role openshift-ingress-operator/cloud-credentials":         statusPass,
+		"Operator role openshift-image-registry/installer-cloud-credentials": statusFail,
+	}, statuses)
+	assert.True(t, strings.Contains(checks[5].Details, "NoSuchEntity"))
+}
+
+func TestCheckDiscoveryUnreachable(t *testing.T) {
+	issuer, _ := url.Parse("https://oidc.example.com/abc")
+	o := &verifyOptions{isOnline: func(url.URL) error { return errors.New("timeout") }}
+
+	c, jwksURI := o.checkDiscovery(issuer)
+	assert.Equal(t, statusFail, c.Status)
+	assert.Equal(t, "https://oidc.example.com/abc/.well-known/openid-configuration is not reachable: timeout", c.Details)
+	assert.Empty(t, jwksURI)
+	assert.Equal(t, statusFail, o.checkJWKS(jwksURI).Status)
+}
@@ -40,6 +40,7 @@ func newCmdOwner(streams genericclioptions.IOStreams, globalOpts *globalflags.Gl
 		},
 	}
 	ownerCmd.Flags().StringVarP(&ops.userName, "user-id", "u", ops.userName, "user to check the cluster owner on")
+	ownerCmd.AddCommand(newCmdOwnerNotify())
 
 	return ownerCmd
 }
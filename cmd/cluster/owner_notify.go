@@ -0,0 +1,332 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	managedNotificationsBaseURL = "https://raw.githubusercontent.com/openshift/managed-notifications/master/"
+	outreachFileName            = "outreach.jsonl"
+
+	roleClusterOwner = "Cluster owner"
+	roleOrgAdmin     = "Organization admin"
+)
+
+// ownerContact is an account which is notified of the service logs sent to a cluster
+type ownerContact struct {
+	Role     string `json:"role"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// ownerContacts are the owner and organization contacts of a cluster
+type ownerContacts struct {
+	ClusterID        string         `json:"clusterId"`
+	OrganizationID   string         `json:"organizationId"`
+	OrganizationName string         `json:"organizationName"`
+	Contacts         []ownerContact `json:"contacts"`
+}
+
+// outreachRecord is the local audit record of a customer outreach
+type outreachRecord struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user,omitempty"`
+	ClusterID  string    `json:"clusterId"`
+	Template   string    `json:"template"`
+	Summary    string    `json:"summary"`
+	Parameters []string  `json:"parameters,omitempty"`
+	Contacts   []string  `json:"contacts,omitempty"`
+	JiraIssue  string    `json:"jiraIssue,omitempty"`
+}
+
+type ownerNotifyOptions struct {
+	clusterID string
+	template  string
+	params    []string
+	jiraIssue string
+
+	out       io.Writer
+	auditPath string
+	now       func() time.Time
+
+	resolveContacts func(clusterID string) (*ownerContacts, error)
+	post            func(opts *servicelog.PostCmdOptions) ([]string, error)
+	comment         func(issueKey string, body string) error
+}
+
+func newCmdOwnerNotify() *cobra.Command {
+	opts := &ownerNotifyOptions{
+		out:             os.Stdout,
+		now:             time.Now,
+		resolveContacts: resolveOwnerContacts,
+		post:            postServiceLog,
+		comment:         commentOnJiraIssue,
+	}
+	notifyCmd := &cobra.Command{
+		Use:   "notify --cluster-id <cluster-identifier> --template <template>",
+		Short: "Reach out to the owner of a cluster with a templated service log",
+		Long: `Reach out to the owner of a cluster with a templated service log.
+
+  The owner and organization admin contacts of the cluster are shown, the parameters of the
+  managed-notifications template which are not set with --param are asked for interactively,
+  and the service log is posted to the cluster.
+
+  Every outreach is recorded locally in ` + outreachFileName + ` in the osdctl data directory and,
+  with --jira, as a comment on the given Jira issue.`,
+		Example: `  # Notify the owner of a cluster with a managed-notifications template, prompting for its parameters
+  osdctl cluster owner notify --cluster-id ${CLUSTER_ID} --template osd/incident_resolved.json
+
+  # Set the parameters upfront and record the outreach on the OHSS ticket
+  osdctl cluster owner notify --cluster-id ${CLUSTER_ID} --template osd/incident_resolved.json -p ALERT_NAME=KubeAPIDown --jira OHSS-1234`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run()
+		},
+	}
+
+	notifyCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID, external ID or name of the cluster to notify the owner of")
+	notifyCmd.Flags().StringVarP(&opts.template, "template", "t", "", "Service log template: a file, a URL or a path in the managed-notifications repository, e.g. osd/incident_resolved.json")
+	notifyCmd.Flags().StringArrayVarP(&opts.params, "param", "p", nil, "Specify a key-value pair (eg. -p FOO=BAR) to set a parameter of the template, the missing ones are asked for")
+	notifyCmd.Flags().StringVar(&opts.jiraIssue, "jira", "", "Jira issue, e.g. OHSS-1234, to record the outreach on with a comment")
+	_ = notifyCmd.MarkFlagRequired("cluster-id")
+	_ = notifyCmd.MarkFlagRequired("template")
+
+	return notifyCmd
+}
+
+func (o *ownerNotifyOptions) complete() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	for _, param := range o.params {
+		if name, value, ok := strings.Cut(param, "="); !ok || name == "" || value == "" {
+			return fmt.Errorf("invalid parameter %q, use -p FOO=BAR", param)
+		}
+	}
+	o.template = resolveNotificationTemplate(o.template)
+
+	if o.auditPath == "" {
+		dataDir, err := osdctlConfig.DataDir()
+		if err != nil {
+			return fmt.Errorf("failed to find the osdctl data directory: %w", err)
+		}
+		o.auditPath = filepath.Join(dataDir, outreachFileName)
+	}
+	return nil
+}
+
+// resolveNotificationTemplate returns the location of the template, prefixing the paths which are neither a URL nor
+// a local file with the managed-notifications repository
+func resolveNotificationTemplate(template string) string {
+	if u, err := url.Parse(template); err == nil && u.Scheme != "" && u.Host != "" {
+		return template
+	}
+	if _, err := os.Stat(template); err == nil {
+		return template
+	}
+	return managedNotificationsBaseURL + strings.TrimPrefix(template, "/")
+}
+
+func (o *ownerNotifyOptions) run() error {
+	contacts, err := o.resolveContacts(o.clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the contacts of cluster %s: %w", o.clusterID, err)
+	}
+	if err := printOwnerContacts(o.out, contacts); err != nil {
+		return err
+	}
+
+	params, err := o.fillParameters()
+	if err != nil {
+		return err
+	}
+
+	postOpts := &servicelog.PostCmdOptions{
+		Template:       o.template,
+		ClusterId:      contacts.ClusterID,
+		TemplateParams: params,
+	}
+	sent, err := o.post(postOpts)
+	if err != nil {
+		return fmt.Errorf("failed to post the service log: %w", err)
+	}
+	if len(sent) == 0 {
+		return fmt.Errorf("no service log was sent to cluster %s, the outreach was not recorded", contacts.ClusterID)
+	}
+
+	record := outreachRecord{
+		Time:       o.now().UTC(),
+		ClusterID:  contacts.ClusterID,
+		Template:   o.template,
+		Summary:    postOpts.Message.Summary,
+		Parameters: params,
+		JiraIssue:  o.jiraIssue,
+	}
+	if current, err := user.Current(); err == nil {
+		record.User = current.Username
+	}
+	for _, contact := range contacts.Contacts {
+		record.Contacts = append(record.Contacts, contact.Email)
+	}
+	if err := appendOutreachRecord(o.auditPath, record); err != nil {
+		return fmt.Errorf("the service log was sent but the outreach could not be recorded in %s: %w", o.auditPath, err)
+	}
+	fmt.Fprintf(o.out, "Recorded the outreach in %s\n", o.auditPath)
+
+	if o.jiraIssue != "" {
+		if err := o.comment(o.jiraIssue, outreachComment(record)); err != nil {
+			return fmt.Errorf("the service log was sent but the outreach could not be recorded on %s: %w", o.jiraIssue, err)
+		}
+		fmt.Fprintf(o.out, "Recorded the outreach on %s\n", o.jiraIssue)
+	}
+	return nil
+}
+
+// fillParameters asks for the values of the template parameters which are not set with --param
+func (o *ownerNotifyOptions) fillParameters() ([]string, error) {
+	missing, err := servicelog.MissingTemplateParameters(o.template, o.params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the template %s: %w", o.template, err)
+	}
+
+	params := append([]string{}, o.params...)
+	for _, name := range missing {
+		value, err := prompt.Input(fmt.Sprintf("Value of %s", name), "")
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			return nil, fmt.Errorf("the template parameter %s is not set, use -p %s=\"FOOBAR\"", name, name)
+		}
+		params = append(params, name+"="+value)
+	}
+	return params, nil
+}
+
+func printOwnerContacts(w io.Writer, contacts *ownerContacts) error {
+	fmt.Fprintf(w, "Cluster %s is owned by organization %s (%s)\n", contacts.ClusterID, contacts.OrganizationName, contacts.OrganizationID)
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"ROLE", "USERNAME", "EMAIL"})
+	for _, contact := range contacts.Contacts {
+		table.AddRow([]string{contact.Role, contact.Username, contact.Email})
+	}
+	return table.Flush()
+}
+
+func outreachComment(record outreachRecord) string {
+	comment := fmt.Sprintf("Customer outreach: service log %q sent to cluster %s on %s",
+		record.Summary, record.ClusterID, record.Time.Format(time.RFC3339))
+	if record.User != "" {
+		comment += " by " + record.User
+	}
+	comment += "\nTemplate: " + record.Template
+	if len(record.Contacts) > 0 {
+		comment += "\nNotified contacts: " + strings.Join(record.Contacts, ", ")
+	}
+	return comment
+}
+
+// appendOutreachRecord appends the record to the audit file at path, creating it if needed
+func appendOutreachRecord(path string, record outreachRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 -- the path is osdctl's own audit file
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// resolveOwnerContacts returns the creator of the cluster subscription and the admins of its organization
+func resolveOwnerContacts(clusterID string) (*ownerContacts, error) {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	subscription, err := utils.GetSubscription(connection, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	accounts := connection.AccountsMgmt().V1().Accounts()
+
+	contacts := &ownerContacts{
+		ClusterID:      subscription.ExternalClusterID(),
+		OrganizationID: subscription.OrganizationID(),
+	}
+	org, err := connection.AccountsMgmt().V1().Organizations().Organization(subscription.OrganizationID()).Get().Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization %s: %w", subscription.OrganizationID(), err)
+	}
+	contacts.OrganizationName = org.Body().Name()
+
+	creator, err := accounts.Account(subscription.Creator().ID()).Get().Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the cluster owner %s: %w", subscription.Creator().ID(), err)
+	}
+	contacts.Contacts = append(contacts.Contacts, ownerContact{Role: roleClusterOwner, Username: creator.Body().Username(), Email: creator.Body().Email()})
+
+	search := fmt.Sprintf("organization_id = '%s' and role_id = 'OrganizationAdmin'", subscription.OrganizationID())
+	bindings, err := connection.AccountsMgmt().V1().RoleBindings().List().Parameter("search", search).Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the organization admins: %w", err)
+	}
+	for _, binding := range bindings.Items().Slice() {
+		accountID := binding.Account().ID()
+		if accountID == "" || accountID == creator.Body().ID() {
+			continue
+		}
+		admin, err := accounts.Account(accountID).Get().Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the organization admin %s: %w", accountID, err)
+		}
+		contacts.Contacts = append(contacts.Contacts, ownerContact{Role: roleOrgAdmin, Username: admin.Body().Username(), Email: admin.Body().Email()})
+	}
+	return contacts, nil
+}
+
+// postServiceLog posts the service log and returns the clusters it was sent to
+func postServiceLog(opts *servicelog.PostCmdOptions) ([]string, error) {
+	if err := opts.Run(); err != nil {
+		return nil, err
+	}
+	return opts.SuccessfulClusters(), nil
+}
+
+func commentOnJiraIssue(issueKey string, body string) error {
+	client, err := utils.NewJiraClient("")
+	if err != nil {
+		return err
+	}
+	_, _, err = client.Issue().AddComment(issueKey, &jira.Comment{Body: body})
+	return err
+}
@@ -0,0 +1,115 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveNotificationTemplate(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "template.json")
+	require.NoError(t, os.WriteFile(local, []byte(`{}`), 0600))
+
+	assert.Equal(t, local, resolveNotificationTemplate(local))
+	assert.Equal(t, "https://example.com/template.json", resolveNotificationTemplate("https://example.com/template.json"))
+	assert.Equal(t, managedNotificationsBaseURL+"osd/incident_resolved.json", resolveNotificationTemplate("osd/incident_resolved.json"))
+	assert.Equal(t, managedNotificationsBaseURL+"osd/incident_resolved.json", resolveNotificationTemplate("/osd/incident_resolved.json"))
+}
+
+func newTestOwnerNotifyOptions(t *testing.T, sent []string) (*ownerNotifyOptions, *bytes.Buffer, *[]string) {
+	template := filepath.Join(t.TempDir(), "template.json")
+	require.NoError(t, os.WriteFile(template, []byte(`{"severity": "Info", "summary": "${ALERT_NAME} resolved", "description": "${REASON}"}`), 0600))
+
+	var comments []string
+	out := &bytes.Buffer{}
+	return &ownerNotifyOptions{
+		clusterID: "abc",
+		template:  template,
+		params:    []string{"ALERT_NAME=KubeAPIDown"},
+		out:       out,
+		auditPath: filepath.Join(t.TempDir(), outreachFileName),
+		now:       func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) },
+		resolveContacts: func(string) (*ownerContacts, error) {
+			return &ownerContacts{
+				ClusterID:        "ext-abc",
+				OrganizationID:   "org-1",
+				OrganizationName: "ACME",
+				Contacts: []ownerContact{
+					{Role: roleClusterOwner, Username: "owner", Email: "owner@example.com"},
+					{Role: roleOrgAdmin, Username: "admin", Email: "admin@example.com"},
+				},
+			}, nil
+		},
+		post: func(opts *servicelog.PostCmdOptions) ([]string, error) {
+			assert.Equal(t, "ext-abc", opts.ClusterId)
+			assert.Equal(t, []string{"ALERT_NAME=KubeAPIDown", "REASON=maintenance"}, opts.TemplateParams)
+			opts.Message.Summary = "KubeAPIDown resolved"
+			return sent, nil
+		},
+		comment: func(issueKey string, body string) error {
+			comments = append(comments, issueKey+": "+body)
+			return nil
+		},
+	}, out, &comments
+}
+
+func TestOwnerNotifyRun(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader("maintenance\n"), &bytes.Buffer{})
+	defer restore()
+
+	opts, out, comments := newTestOwnerNotifyOptions(t, []string{"ext-abc"})
+	opts.jiraIssue = "OHSS-1234"
+	require.NoError(t, opts.run())
+
+	assert.Contains(t, out.String(), "Cluster ext-abc is owned by organization ACME (org-1)")
+	assert.Contains(t, out.String(), "admin@example.com")
+	assert.Contains(t, out.String(), "Recorded the outreach on OHSS-1234")
+
+	f, err := os.Open(opts.auditPath)
+	require.NoError(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	require.True(t, scanner.Scan())
+	var record outreachRecord
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+	assert.Equal(t, "ext-abc", record.ClusterID)
+	assert.Equal(t, "KubeAPIDown resolved", record.Summary)
+	assert.Equal(t, []string{"owner@example.com", "admin@example.com"}, record.Contacts)
+	assert.Equal(t, "OHSS-1234", record.JiraIssue)
+
+	require.Len(t, *comments, 1)
+	assert.Contains(t, (*comments)[0], `OHSS-1234: Customer outreach: service log "KubeAPIDown resolved" sent to cluster ext-abc on 2024-05-01T12:00:00Z`)
+	assert.Contains(t, (*comments)[0], "Notified contacts: owner@example.com, admin@example.com")
+}
+
+func TestOwnerNotifyRunNotSent(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader("maintenance\n"), &bytes.Buffer{})
+	defer restore()
+
+	opts, _, comments := newTestOwnerNotifyOptions(t, nil)
+	opts.jiraIssue = "OHSS-1234"
+	assert.EqualError(t, opts.run(), "no service log was sent to cluster ext-abc, the outreach was not recorded")
+
+	_, err := os.Stat(opts.auditPath)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Empty(t, *comments)
+}
+
+func TestOwnerNotifyRunMissingParameter(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader("\n"), &bytes.Buffer{})
+	defer restore()
+
+	opts, _, _ := newTestOwnerNotifyOptions(t, []string{"ext-abc"})
+	assert.EqualError(t, opts.run(), `the template parameter REASON is not set, use -p REASON="FOOBAR"`)
+}
@@ -6,11 +6,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// NewCmdReports implements the reports command to list, get, and create cluster reports
+// NewCmdReports implements the reports command to list, get, create and export cluster reports
 // osdctl cluster reports list --cluster-id <cluster-id>
 // osdctl cluster reports get --cluster-id <cluster-id> --report-id <report-id>
 // osdctl cluster reports create --cluster-id <cluster-id> --summary <summary> --data <data>
 // osdctl cluster reports create --cluster-id <cluster-id> --summary <summary> --file <file-path>
+// osdctl cluster reports export --cluster-id <cluster-id> --since <duration>
 func NewCmdReports() *cobra.Command {
 	reportsCmd := &cobra.Command{
 		Use:   "reports",
@@ -28,6 +29,7 @@ specific cluster and include a summary and base64-encoded data.`,
 	reportsCmd.AddCommand(newCmdList())
 	reportsCmd.AddCommand(newCmdGet())
 	reportsCmd.AddCommand(newCmdCreate())
+	reportsCmd.AddCommand(newCmdExport())
 
 	return reportsCmd
 }
@@ -15,22 +15,25 @@ func TestNewCmdReports(t *testing.T) {
 
 	// Check that subcommands are registered
 	subcommands := cmd.Commands()
-	assert.Len(t, subcommands, 3, "Reports command should have 3 subcommands")
+	assert.Len(t, subcommands, 4, "Reports command should have 4 subcommands")
 
 	// Check for specific subcommands
-	var hasListCmd, hasGetCmd, hasCreateCmd bool
+	var hasListCmd, hasGetCmd, hasCreateCmd, hasExportCmd bool
 	for _, subcmd := range subcommands {
 		switch subcmd.Use {
 		case "list":
 			hasListCmd = true
-		case "get":
+		case "get [report-id]":
 			hasGetCmd = true
 		case "create":
 			hasCreateCmd = true
+		case "export":
+			hasExportCmd = true
 		}
 	}
 
 	assert.True(t, hasListCmd, "Reports command should have 'list' subcommand")
 	assert.True(t, hasGetCmd, "Reports command should have 'get' subcommand")
 	assert.True(t, hasCreateCmd, "Reports command should have 'create' subcommand")
+	assert.True(t, hasExportCmd, "Reports command should have 'export' subcommand")
 }
@@ -0,0 +1,276 @@
+package reports
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
+	"github.com/openshift/osdctl/pkg/backplane"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	exportFormatMarkdown = "markdown"
+	exportFormatJSON     = "json"
+	exportFormatBoth     = "both"
+
+	// exportIndexFile lists the exported reports of a cluster
+	exportIndexFile = "index.md"
+)
+
+var exportFormats = []string{exportFormatMarkdown, exportFormatJSON, exportFormatBoth}
+
+type exportOptions struct {
+	clusterID                string
+	since                    time.Duration
+	until                    string
+	dir                      string
+	format                   string
+	maxReports               int
+	includeManagementCluster bool
+
+	out io.Writer
+	now func() time.Time
+	// listReports and getReport call backplane-api for a cluster, replaced in tests
+	listReports func(ctx context.Context, clusterID string, last int) (*backplaneapi.ListReports, error)
+	getReport   func(ctx context.Context, clusterID, reportID string) (*backplaneapi.Report, error)
+}
+
+// exportTarget is a cluster whose reports are exported to a directory
+type exportTarget struct {
+	clusterID string
+	name      string
+	dir       string
+}
+
+func newCmdExport() *cobra.Command {
+	opts := &exportOptions{
+		out:         os.Stdout,
+		now:         time.Now,
+		listReports: listClusterReports,
+		getReport:   getClusterReport,
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the reports of a cluster within a time range to local files",
+		Long: `Export all the reports of a cluster created within a time range to local files.
+
+Each report is written as markdown, the decoded report data, and/or as JSON, the
+report as returned by backplane-api, next to an index.md listing the exported
+reports, so that they can be attached to incident retrospectives.
+
+For HCP clusters, --include-management-cluster also exports the reports of the
+management cluster of the cluster to a sub directory named after it.`,
+		Example: `  # Export the reports of the last week of a cluster to ./reports-${CLUSTER_ID}
+  osdctl cluster reports export --cluster-id ${CLUSTER_ID}
+
+  # Export the markdown of the reports of a day to a directory
+  osdctl cluster reports export --cluster-id ${CLUSTER_ID} --since 24h --until 2026-01-02T12:00:00Z --format markdown --dir ./retro
+
+  # Export the reports of an HCP cluster and of its management cluster
+  osdctl cluster reports export --cluster-id ${CLUSTER_ID} --include-management-cluster`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			targets, err := opts.targets()
+			if err != nil {
+				return err
+			}
+			return opts.run(context.Background(), targets)
+		},
+	}
+
+	exportCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID (internal or external)")
+	exportCmd.Flags().DurationVar(&opts.since, "since", 7*24*time.Hour, "Export the reports created within this duration before --until")
+	exportCmd.Flags().StringVar(&opts.until, "until", "", "Export the reports created before this RFC 3339 time, defaults to now")
+	exportCmd.Flags().StringVar(&opts.dir, "dir", "", "Directory to export the reports to, defaults to ./reports-<cluster-id>")
+	exportCmd.Flags().StringVar(&opts.format, "format", exportFormatBoth, "Format of the exported reports: markdown, json or both")
+	exportCmd.Flags().IntVar(&opts.maxReports, "max-reports", 100, "Maximum number of most recent reports of a cluster to look up")
+	exportCmd.Flags().BoolVar(&opts.includeManagementCluster, "include-management-cluster", false, "Also export the reports of the management cluster of an HCP cluster")
+	_ = exportCmd.MarkFlagRequired("cluster-id")
+
+	return exportCmd
+}
+
+func (o *exportOptions) validate() error {
+	if o.since <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+	if o.until != "" {
+		if _, err := time.Parse(time.RFC3339, o.until); err != nil {
+			return fmt.Errorf("invalid --until %q, expected an RFC 3339 time such as 2026-01-02T12:00:00Z", o.until)
+		}
+	}
+	if !slices.Contains(exportFormats, o.format) {
+		return fmt.Errorf("invalid format %q, expected one of: %s", o.format, strings.Join(exportFormats, ", "))
+	}
+	if o.maxReports < 1 {
+		return fmt.Errorf("--max-reports must be at least 1")
+	}
+	return nil
+}
+
+// timeRange returns the start and the end of the time range of the exported reports
+func (o *exportOptions) timeRange() (time.Time, time.Time) {
+	end := o.now().UTC()
+	if o.until != "" {
+		end, _ = time.Parse(time.RFC3339, o.until)
+	}
+	return end.Add(-o.since), end
+}
+
+// targets resolves the cluster, and its management cluster with --include-management-cluster
+func (o *exportOptions) targets() ([]exportTarget, error) {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if o.dir == "" {
+		o.dir = "reports-" + cluster.ID()
+	}
+	targets := []exportTarget{{clusterID: cluster.ID(), name: cluster.Name(), dir: o.dir}}
+
+	if o.includeManagementCluster {
+		if !cluster.Hypershift().Enabled() {
+			return nil, fmt.Errorf("--include-management-cluster is only supported for HCP clusters")
+		}
+		mc, err := utils.GetManagementCluster(cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the management cluster of %s: %w", cluster.ID(), err)
+		}
+		targets = append(targets, exportTarget{clusterID: mc.ID(), name: mc.Name(), dir: filepath.Join(o.dir, mc.Name())})
+	}
+	return targets, nil
+}
+
+func (o *exportOptions) run(ctx context.Context, targets []exportTarget) error {
+	start, end := o.timeRange()
+	for _, target := range targets {
+		exported, err := o.export(ctx, target, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to export the reports of cluster %s: %w", target.clusterID, err)
+		}
+		_, _ = fmt.Fprintf(o.out, "Exported %d reports of cluster %s created between %s and %s to %s\n",
+			exported, target.name, start.Format(time.RFC3339), end.Format(time.RFC3339), target.dir)
+	}
+	return nil
+}
+
+// export writes the reports of a cluster created within the time range, and their index, to the directory of the
+// target, and returns the number of exported reports
+func (o *exportOptions) export(ctx context.Context, target exportTarget, start, end time.Time) (int, error) {
+	list, err := o.listReports(ctx, target.clusterID, o.maxReports)
+	if err != nil {
+		return 0, err
+	}
+
+	var reports []*backplaneapi.Report
+	for _, listed := range list.Reports {
+		if listed.ReportId == nil || listed.CreatedAt == nil || listed.CreatedAt.Before(start) || listed.CreatedAt.After(end) {
+			continue
+		}
+		report, err := o.getReport(ctx, target.clusterID, *listed.ReportId)
+		if err != nil {
+			return 0, err
+		}
+		reports = append(reports, report)
+	}
+	slices.SortFunc(reports, func(a, b *backplaneapi.Report) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	if err := os.MkdirAll(target.dir, 0750); err != nil {
+		return 0, err
+	}
+
+	var index strings.Builder
+	fmt.Fprintf(&index, "# Reports of cluster %s\n\n", target.name)
+	fmt.Fprintf(&index, "- Cluster ID: %s\n", target.clusterID)
+	fmt.Fprintf(&index, "- Created between %s and %s\n\n", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	if len(reports) == 0 {
+		fmt.Fprintf(&index, "No reports were created in this time range.\n")
+	} else {
+		fmt.Fprintf(&index, "| Created | Report ID | Summary | File |\n")
+		fmt.Fprintf(&index, "|---|---|---|---|\n")
+	}
+
+	for _, report := range reports {
+		files, err := o.writeReport(target.dir, report)
+		if err != nil {
+			return 0, fmt.Errorf("failed to export report %s: %w", report.ReportId, err)
+		}
+		links := make([]string, 0, len(files))
+		for _, file := range files {
+			links = append(links, fmt.Sprintf("[%s](%s)", filepath.Ext(file)[1:], file))
+		}
+		fmt.Fprintf(&index, "| %s | %s | %s | %s |\n", report.CreatedAt.UTC().Format(time.RFC3339), report.ReportId,
+			strings.ReplaceAll(report.Summary, "|", `\|`), strings.Join(links, " "))
+	}
+
+	if err := os.WriteFile(filepath.Join(target.dir, exportIndexFile), []byte(index.String()), 0600); err != nil {
+		return 0, err
+	}
+	return len(reports), nil
+}
+
+// writeReport writes a report in the export format, and returns the names of the written files
+func (o *exportOptions) writeReport(dir string, report *backplaneapi.Report) ([]string, error) {
+	// Prefix the files with the creation time so that they sort chronologically
+	name := report.CreatedAt.UTC().Format("20060102T150405Z") + "-" + filepath.Base(report.ReportId)
+
+	var files []string
+	if o.format == exportFormatMarkdown || o.format == exportFormatBoth {
+		data, err := base64.StdEncoding.DecodeString(report.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode report data: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".md"), data, 0600); err != nil {
+			return nil, err
+		}
+		files = append(files, name+".md")
+	}
+	if o.format == exportFormatJSON || o.format == exportFormatBoth {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal report: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), data, 0600); err != nil {
+			return nil, err
+		}
+		files = append(files, name+".json")
+	}
+	return files, nil
+}
+
+func listClusterReports(ctx context.Context, clusterID string, last int) (*backplaneapi.ListReports, error) {
+	backplaneClient, err := backplane.NewClient(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backplane client: %w", err)
+	}
+	return backplaneClient.ListReports(ctx, last)
+}
+
+func getClusterReport(ctx context.Context, clusterID, reportID string) (*backplaneapi.Report, error) {
+	backplaneClient, err := backplane.NewClient(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backplane client: %w", err)
+	}
+	return backplaneClient.GetReport(ctx, reportID)
+}
@@ -0,0 +1,110 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    exportOptions
+		wantErr string
+	}{
+		{name: "valid", opts: exportOptions{since: time.Hour, format: "both", maxReports: 10}},
+		{name: "valid until", opts: exportOptions{since: time.Hour, until: "2026-01-02T12:00:00Z", format: "json", maxReports: 10}},
+		{name: "negative since", opts: exportOptions{since: -time.Hour, format: "both", maxReports: 10}, wantErr: "--since must be positive"},
+		{name: "invalid until", opts: exportOptions{since: time.Hour, until: "yesterday", format: "both", maxReports: 10}, wantErr: "invalid --until"},
+		{name: "invalid format", opts: exportOptions{since: time.Hour, format: "pdf", maxReports: 10}, wantErr: "invalid format"},
+		{name: "invalid max reports", opts: exportOptions{since: time.Hour, format: "both"}, wantErr: "--max-reports"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExportOptions_Run(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	reports := map[string]*backplaneapi.Report{
+		"recent": {ReportId: "recent", Summary: "Network | egress", CreatedAt: now.Add(-time.Hour), Data: base64.StdEncoding.EncodeToString([]byte("# Egress blocked\n"))},
+		"older":  {ReportId: "older", Summary: "Etcd health", CreatedAt: now.Add(-20 * time.Hour), Data: base64.StdEncoding.EncodeToString([]byte("# Etcd\n"))},
+		"stale":  {ReportId: "stale", Summary: "Too old", CreatedAt: now.Add(-48 * time.Hour)},
+	}
+	list := &backplaneapi.ListReports{ClusterId: "cluster-id"}
+	for _, id := range []string{"recent", "older", "stale"} {
+		list.Reports = append(list.Reports, struct {
+			CreatedAt *time.Time `json:"created_at,omitempty"`
+			ReportId  *string    `json:"report_id,omitempty"`
+			Summary   *string    `json:"summary,omitempty"`
+		}{CreatedAt: &reports[id].CreatedAt, ReportId: &reports[id].ReportId, Summary: &reports[id].Summary})
+	}
+
+	var fetched []string
+	out := &bytes.Buffer{}
+	dir := t.TempDir()
+	opts := &exportOptions{
+		since:      24 * time.Hour,
+		format:     exportFormatBoth,
+		maxReports: 50,
+		out:        out,
+		now:        func() time.Time { return now },
+		listReports: func(ctx context.Context, clusterID string, last int) (*backplaneapi.ListReports, error) {
+			assert.Equal(t, 50, last)
+			if clusterID == "mc-id" {
+				return &backplaneapi.ListReports{ClusterId: clusterID}, nil
+			}
+			return list, nil
+		},
+		getReport: func(ctx context.Context, clusterID, reportID string) (*backplaneapi.Report, error) {
+			fetched = append(fetched, reportID)
+			return reports[reportID], nil
+		},
+	}
+
+	err := opts.run(context.Background(), []exportTarget{
+		{clusterID: "cluster-id", name: "my-cluster", dir: dir},
+		{clusterID: "mc-id", name: "hs-mc-1", dir: filepath.Join(dir, "hs-mc-1")},
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"recent", "older"}, fetched)
+	assert.Contains(t, out.String(), "Exported 2 reports of cluster my-cluster created between 2026-01-01T12:00:00Z and 2026-01-02T12:00:00Z")
+	assert.Contains(t, out.String(), "Exported 0 reports of cluster hs-mc-1")
+
+	markdown, err := os.ReadFile(filepath.Join(dir, "20260102T110000Z-recent.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Egress blocked\n", string(markdown))
+
+	data, err := os.ReadFile(filepath.Join(dir, "20260101T160000Z-older.json"))
+	require.NoError(t, err)
+	exported := &backplaneapi.Report{}
+	require.NoError(t, json.Unmarshal(data, exported))
+	assert.Equal(t, "Etcd health", exported.Summary)
+
+	index, err := os.ReadFile(filepath.Join(dir, exportIndexFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "| 2026-01-01T16:00:00Z | older | Etcd health | [md](20260101T160000Z-older.md) [json](20260101T160000Z-older.json) |\n"+
+		"| 2026-01-02T11:00:00Z | recent | Network \\| egress | [md](20260102T110000Z-recent.md) [json](20260102T110000Z-recent.json) |")
+	assert.NotContains(t, string(index), "stale")
+
+	mcIndex, err := os.ReadFile(filepath.Join(dir, "hs-mc-1", exportIndexFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(mcIndex), "No reports were created in this time range.")
+}
@@ -5,38 +5,66 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
 	"github.com/openshift/osdctl/pkg/backplane"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
+const (
+	getOutputText = "text"
+	getOutputJSON = "json"
+	getOutputRaw  = "raw"
+)
+
 type getOptions struct {
 	clusterID string
 	reportID  string
 	output    string
+
+	out io.Writer
 }
 
 func newCmdGet() *cobra.Command {
-	opts := &getOptions{}
+	opts := &getOptions{out: os.Stdout}
 
 	getCmd := &cobra.Command{
-		Use:   "get",
+		Use:   "get [report-id]",
 		Short: "Get a specific cluster report from backplane-api",
 		Long: `Retrieve and display a specific report by its ID.
 
 This command fetches a report by its report ID and displays the decoded
-report data. Use 'list' to find available report IDs.`,
+report data, rendering its markdown (headings, lists, code blocks) for the
+terminal. Use 'list' to find available report IDs.
+
+Use '--output raw' for the decoded report data as is, or '--output json' for
+the report as returned by backplane-api.`,
 		Example: `  # Get a specific report
-  osdctl cluster reports get --cluster-id ${CLUSTER_ID} --report-id ${REPORT_ID}
+  osdctl cluster reports get ${REPORT_ID} --cluster-id ${CLUSTER_ID}
 
   # Get a report with JSON output
-  osdctl cluster reports get --cluster-id ${CLUSTER_ID} --report-id ${REPORT_ID} --output json`,
-		Args:              cobra.NoArgs,
+  osdctl cluster reports get --cluster-id ${CLUSTER_ID} --report-id ${REPORT_ID} --output json
+
+  # Save the markdown of a report
+  osdctl cluster reports get ${REPORT_ID} --cluster-id ${CLUSTER_ID} --output raw > report.md`,
+		Args:              cobra.MaximumNArgs(1),
 		DisableAutoGenTag: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				if opts.reportID != "" && opts.reportID != args[0] {
+					return fmt.Errorf("the report ID %s conflicts with --report-id %s", args[0], opts.reportID)
+				}
+				opts.reportID = args[0]
+			}
+			if err := opts.validate(); err != nil {
+				return err
+			}
+
 			ocmClient, err := utils.CreateConnection()
 			if err != nil {
 				return err
@@ -48,14 +76,25 @@ report data. Use 'list' to find available report IDs.`,
 	}
 
 	getCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster ID (internal or external)")
-	getCmd.Flags().StringVarP(&opts.reportID, "report-id", "r", "", "Report ID to retrieve")
-	getCmd.Flags().StringVarP(&opts.output, "output", "o", "text", "Output format: text or json")
+	getCmd.Flags().StringVarP(&opts.reportID, "report-id", "r", "", "Report ID to retrieve, alternatively given as argument")
+	getCmd.Flags().StringVarP(&opts.output, "output", "o", getOutputText, "Output format: text (rendered markdown), raw (decoded report data) or json")
 	_ = getCmd.MarkFlagRequired("cluster-id")
-	_ = getCmd.MarkFlagRequired("report-id")
 
 	return getCmd
 }
 
+func (o *getOptions) validate() error {
+	if o.reportID == "" {
+		return fmt.Errorf("a report ID is required, as argument or with --report-id")
+	}
+	switch o.output {
+	case getOutputText, getOutputJSON, getOutputRaw:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, expected %s, %s or %s", o.output, getOutputText, getOutputRaw, getOutputJSON)
+	}
+}
+
 func (o *getOptions) run(ocmClient *sdk.Connection) error {
 	// Convert external cluster ID to internal if needed
 	internalClusterID, err := utils.GetInternalClusterID(ocmClient, o.clusterID)
@@ -75,13 +114,17 @@ func (o *getOptions) run(ocmClient *sdk.Connection) error {
 		return fmt.Errorf("failed to get report: %w", err)
 	}
 
-	if o.output == "json" {
+	return o.printReport(report)
+}
+
+func (o *getOptions) printReport(report *backplaneapi.Report) error {
+	if o.output == getOutputJSON {
 		bytes, err := json.Marshal(report)
 		if err != nil {
 			return fmt.Errorf("failed to marshal report: %w", err)
 		}
-		fmt.Println(string(bytes))
-		return nil
+		_, err = fmt.Fprintln(o.out, string(bytes))
+		return err
 	}
 
 	decodedData, err := base64.StdEncoding.DecodeString(report.Data)
@@ -89,8 +132,11 @@ func (o *getOptions) run(ocmClient *sdk.Connection) error {
 		return fmt.Errorf("failed to decode report data: %w", err)
 	}
 
-	fmt.Printf("📒Report Details for Report %s created at %s\n\n", report.ReportId, report.CreatedAt.Format(time.RFC3339))
-	fmt.Println(string(decodedData))
+	if o.output == getOutputRaw {
+		_, err = o.out.Write(decodedData)
+		return err
+	}
 
-	return nil
+	_, _ = fmt.Fprintf(o.out, "📒Report Details for Report %s created at %s\n\n", report.ReportId, report.CreatedAt.Format(time.RFC3339))
+	return renderMarkdown(o.out, string(decodedData))
 }
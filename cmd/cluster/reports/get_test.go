@@ -1,16 +1,22 @@
 package reports
 
 import (
+	"bytes"
+	"encoding/base64"
 	"testing"
+	"time"
 
+	"github.com/fatih/color"
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewCmdGet(t *testing.T) {
 	cmd := newCmdGet()
 
 	assert.NotNil(t, cmd)
-	assert.Equal(t, "get", cmd.Use)
+	assert.Equal(t, "get [report-id]", cmd.Use)
 	assert.Equal(t, "Get a specific cluster report from backplane-api", cmd.Short)
 
 	// Check required flags
@@ -81,3 +87,34 @@ func TestGetOptions_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOptions_validate(t *testing.T) {
+	assert.NoError(t, (&getOptions{reportID: "report-456", output: "raw"}).validate())
+	assert.Error(t, (&getOptions{output: "text"}).validate(), "a report ID is required")
+	assert.Error(t, (&getOptions{reportID: "report-456", output: "yaml"}).validate())
+}
+
+func TestGetOptions_printReport(t *testing.T) {
+	color.NoColor = true
+	markdown := "# Summary\n\n- **etcd** is `degraded`\n\n```\noc get pods\n```\n"
+	report := &backplaneapi.Report{
+		ReportId:  "report-456",
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data:      base64.StdEncoding.EncodeToString([]byte(markdown)),
+	}
+
+	var out bytes.Buffer
+	o := &getOptions{output: getOutputText, out: &out}
+	require.NoError(t, o.printReport(report))
+	assert.Equal(t, "📒Report Details for Report report-456 created at 2026-01-02T03:04:05Z\n\nSummary\n\n  • etcd is degraded\n\n    oc get pods\n", out.String())
+
+	out.Reset()
+	o.output = getOutputRaw
+	require.NoError(t, o.printReport(report))
+	assert.Equal(t, markdown, out.String())
+
+	out.Reset()
+	o.output = getOutputJSON
+	require.NoError(t, o.printReport(report))
+	assert.Contains(t, out.String(), `"report_id":"report-456"`)
+}
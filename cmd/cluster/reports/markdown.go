@@ -0,0 +1,87 @@
+package reports
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var (
+	mdHeadingRE    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdListItemRE   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdRuleRE       = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	mdBoldRE       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdInlineCodeRE = regexp.MustCompile("`([^`]+)`")
+	mdLinkRE       = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+
+	mdH1Color    = color.New(color.FgMagenta, color.Bold, color.Underline)
+	mdH2Color    = color.New(color.FgBlue, color.Bold)
+	mdHxColor    = color.New(color.Bold)
+	mdCodeColor  = color.New(color.FgCyan)
+	mdBoldColor  = color.New(color.Bold)
+	mdQuoteColor = color.New(color.Faint)
+)
+
+// renderMarkdown writes the markdown of a report for a terminal: headings are highlighted, code blocks are indented
+// and list items, quotes and rules are drawn with box characters. Colors follow the color settings of the terminal.
+func renderMarkdown(w io.Writer, markdown string) error {
+	scanner := bufio.NewScanner(strings.NewReader(markdown))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	inCodeBlock := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		var rendered string
+		switch {
+		case inCodeBlock:
+			rendered = "    " + mdCodeColor.Sprint(line)
+		case mdHeadingRE.MatchString(trimmed):
+			m := mdHeadingRE.FindStringSubmatch(trimmed)
+			switch len(m[1]) {
+			case 1:
+				rendered = mdH1Color.Sprint(renderInline(m[2]))
+			case 2:
+				rendered = mdH2Color.Sprint(renderInline(m[2]))
+			default:
+				rendered = mdHxColor.Sprint(renderInline(m[2]))
+			}
+		case mdRuleRE.MatchString(trimmed):
+			rendered = strings.Repeat("─", 40)
+		case mdListItemRE.MatchString(line):
+			m := mdListItemRE.FindStringSubmatch(line)
+			rendered = m[1] + "  • " + renderInline(m[2])
+		case strings.HasPrefix(trimmed, ">"):
+			rendered = mdQuoteColor.Sprint("│ " + renderInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))))
+		default:
+			rendered = renderInline(line)
+		}
+
+		if _, err := fmt.Fprintln(w, rendered); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// renderInline renders the bold text, inline code and links of a line
+func renderInline(line string) string {
+	line = mdInlineCodeRE.ReplaceAllStringFunc(line, func(s string) string {
+		return mdCodeColor.Sprint(mdInlineCodeRE.FindStringSubmatch(s)[1])
+	})
+	line = mdBoldRE.ReplaceAllStringFunc(line, func(s string) string {
+		m := mdBoldRE.FindStringSubmatch(s)
+		return mdBoldColor.Sprint(m[1] + m[2])
+	})
+	return mdLinkRE.ReplaceAllString(line, "$1 ($2)")
+}
@@ -0,0 +1,35 @@
+package reports
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	color.NoColor = true
+
+	markdown := `## Findings
+> Investigated by CAD
+---
+* See [the SOP](https://example.com/sop) and __notes__
+  - nested ` + "`item`" + `
+~~~yaml
+- not a list item
+~~~
+# in text`
+
+	var out bytes.Buffer
+	require.NoError(t, renderMarkdown(&out, markdown))
+	assert.Equal(t, `Findings
+│ Investigated by CAD
+────────────────────────────────────────
+  • See the SOP (https://example.com/sop) and notes
+    • nested item
+    - not a list item
+in text
+`, out.String())
+}
@@ -0,0 +1,424 @@
+package resize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/cluster/metrics"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	hoursPerMonth = 730
+
+	instanceTypeLabel = "node.kubernetes.io/instance-type"
+
+	adviseKeep     = "keep"
+	adviseUpsize   = "upsize"
+	adviseDownsize = "downsize"
+)
+
+// machinePoolLabels are the node labels holding the name of the machine pool of a node, in order of precedence
+var machinePoolLabels = []string{"hypershift.openshift.io/nodePool", "hive.openshift.io/machine-pool"}
+
+// nonWorkerRoleLabels are the node role labels of the nodes which aren't part of a worker machine pool
+var nonWorkerRoleLabels = []string{"node-role.kubernetes.io/master", "node-role.kubernetes.io/control-plane", "node-role.kubernetes.io/infra"}
+
+// awsSizes and gcpSizes are the sizes instance types are resized between, smallest first
+var (
+	awsSizes = []string{"xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge", "24xlarge"}
+	gcpSizes = []string{"4", "8", "16", "32", "48", "64", "80"}
+)
+
+// hourlyPricePer4VCPU is the approximate on-demand price in USD of 4 vCPUs of an instance family in
+// us-east-1 and us-central1. Prices scale linearly with the size within these families.
+var hourlyPricePer4VCPU = map[string]float64{
+	"m5":          0.192,
+	"m5a":         0.172,
+	"m6a":         0.1728,
+	"m6i":         0.192,
+	"m7i":         0.2016,
+	"c5":          0.17,
+	"c6i":         0.17,
+	"r5":          0.252,
+	"r6i":         0.252,
+	"e2-standard": 0.134,
+	"n2-standard": 0.1942,
+	"n2-highmem":  0.262,
+}
+
+var (
+	awsInstanceType = regexp.MustCompile(`^([a-z0-9-]+)\.([0-9]*xlarge)$`)
+	gcpInstanceType = regexp.MustCompile(`^([a-z0-9]+-[a-z]+)-([0-9]+)$`)
+)
+
+type adviseOptions struct {
+	clusterID string
+	reason    string
+	since     time.Duration
+	low       float64
+	high      float64
+	output    string
+
+	client client.Client
+	exec   metrics.PromtoolExecutor
+	out    io.Writer
+}
+
+// poolAdvice is the rightsizing advice of a worker machine pool
+type poolAdvice struct {
+	MachinePool        string   `json:"machinePool"`
+	InstanceType       string   `json:"instanceType"`
+	Nodes              int      `json:"nodes"`
+	CPUUtilization     *float64 `json:"cpuUtilization,omitempty"`
+	MemoryUtilization  *float64 `json:"memoryUtilization,omitempty"`
+	Recommendation     string   `json:"recommendation"`
+	SuggestedType      string   `json:"suggestedInstanceType,omitempty"`
+	MonthlyCostDelta   *float64 `json:"monthlyCostDeltaUSD,omitempty"`
+	RecommendationNote string   `json:"note,omitempty"`
+}
+
+func newCmdResizeAdvise() *cobra.Command {
+	opts := &adviseOptions{}
+
+	adviseCmd := &cobra.Command{
+		Use:   "advise",
+		Short: "Suggest instance types for the worker machine pools of a cluster based on their utilization",
+		Long: `Suggest instance types for the worker machine pools of a cluster based on their utilization
+
+  The 95th percentile of the CPU and memory utilization of every worker node over --since is read from the
+  cluster's Prometheus through backplane, for both classic and HCP clusters. A machine pool is advised to
+  move one size up when its busiest node is above --high, and one size down when all of its nodes are below
+  --low and would stay below --high once resized.
+
+  The monthly cost delta is estimated from on-demand list prices of us-east-1 (AWS) and us-central1 (GCP) and
+  is only meant to size the change, not to quote it.`,
+		Example: `  # Advise on the worker machine pools of a cluster based on the last week
+  osdctl cluster resize advise --cluster-id ${CLUSTER_ID} --reason "${REASON}"
+
+  # Use the last 30 days and stricter thresholds, as JSON
+  osdctl cluster resize advise --cluster-id ${CLUSTER_ID} --reason "${REASON}" --since 720h --low 0.2 --high 0.7 -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			config, err := elevate.NewRestConfig(opts.clusterID, elevate.Reason{
+				Ticket:        opts.reason,
+				Justification: "advise on the worker instance types of cluster " + opts.clusterID,
+				Command:       "cluster resize advise",
+			})
+			if err != nil {
+				return err
+			}
+			if opts.client, err = client.New(config, client.Options{}); err != nil {
+				return err
+			}
+			if opts.exec, err = metrics.NewPromtoolExecutorForConfig(config); err != nil {
+				return err
+			}
+			opts.out = cmd.OutOrStdout()
+			return opts.run(cmd.Context())
+		},
+	}
+
+	adviseCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	adviseCmd.Flags().StringVar(&opts.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	adviseCmd.Flags().DurationVar(&opts.since, "since", 7*24*time.Hour, "Period over which the utilization is evaluated")
+	adviseCmd.Flags().Float64Var(&opts.low, "low", 0.3, "Utilization ratio under which a machine pool is considered oversized")
+	adviseCmd.Flags().Float64Var(&opts.high, "high", 0.8, "Utilization ratio above which a machine pool is considered undersized")
+	adviseCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+
+	_ = adviseCmd.MarkFlagRequired("cluster-id")
+	_ = adviseCmd.MarkFlagRequired("reason")
+
+	return adviseCmd
+}
+
+func (o *adviseOptions) validate() error {
+	if o.since < time.Hour {
+		return fmt.Errorf("--since must be at least 1h")
+	}
+	if o.low <= 0 || o.high >= 1 || o.low >= o.high {
+		return fmt.Errorf("--low and --high must be ratios between 0 and 1, with --low lower than --high")
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	return nil
+}
+
+func (o *adviseOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := o.client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	window := fmt.Sprintf("%dm", int(o.since.Minutes()))
+	cpu, err := o.utilizationByNode(ctx, fmt.Sprintf(`quantile_over_time(0.95, (1 - avg by (instance) (rate(node_cpu_seconds_total{mode="idle"}[5m])))[%s:5m])`, window))
+	if err != nil {
+		return fmt.Errorf("failed to query the CPU utilization: %w", err)
+	}
+	memory, err := o.utilizationByNode(ctx, fmt.Sprintf(`quantile_over_time(0.95, (1 - node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes)[%s:5m])`, window))
+	if err != nil {
+		return fmt.Errorf("failed to query the memory utilization: %w", err)
+	}
+
+	advices := o.advise(groupWorkerNodes(nodes.Items), cpu, memory)
+	return printAdvices(o.out, advices, o.output)
+}
+
+// utilizationByNode runs the query and returns its value per node
+func (o *adviseOptions) utilizationByNode(ctx context.Context, expression string) (map[string]float64, error) {
+	series, err := metrics.InstantQuery(ctx, o.exec, expression)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]float64{}
+	for _, s := range series {
+		if instance := s.Labels["instance"]; instance != "" && !math.IsNaN(s.Value) {
+			values[instance] = s.Value
+		}
+	}
+	return values, nil
+}
+
+// workerPool is a set of worker nodes of the same machine pool and instance type
+type workerPool struct {
+	name         string
+	instanceType string
+	nodes        []string
+}
+
+// groupWorkerNodes groups the worker nodes by machine pool and instance type, sorted by name.
+// Nodes without a machine pool label are grouped by instance type.
+func groupWorkerNodes(nodes []corev1.Node) []*workerPool {
+	pools := map[string]*workerPool{}
+	for _, node := range nodes {
+		if slices.ContainsFunc(nonWorkerRoleLabels, func(label string) bool { _, ok := node.Labels[label]; return ok }) {
+			continue
+		}
+		instanceType := node.Labels[instanceTypeLabel]
+		name := ""
+		for _, label := range machinePoolLabels {
+			if name = node.Labels[label]; name != "" {
+				break
+			}
+		}
+
+		key := name + "/" + instanceType
+		if _, ok := pools[key]; !ok {
+			pools[key] = &workerPool{name: name, instanceType: instanceType}
+		}
+		pools[key].nodes = append(pools[key].nodes, node.Name)
+	}
+
+	var sorted []*workerPool
+	for _, key := range slices.Sorted(maps.Keys(pools)) {
+		sorted = append(sorted, pools[key])
+	}
+	return sorted
+}
+
+func (o *adviseOptions) advise(pools []*workerPool, cpu, memory map[string]float64) []poolAdvice {
+	advices := make([]poolAdvice, 0, len(pools))
+	for _, pool := range pools {
+		advice := poolAdvice{
+			MachinePool:       pool.name,
+			InstanceType:      pool.instanceType,
+			Nodes:             len(pool.nodes),
+			Recommendation:    adviseKeep,
+			CPUUtilization:    maxUtilization(pool.nodes, cpu),
+			MemoryUtilization: maxUtilization(pool.nodes, memory),
+		}
+		if advice.CPUUtilization == nil || advice.MemoryUtilization == nil {
+			advice.RecommendationNote = "no utilization metrics for the nodes"
+			advices = append(advices, advice)
+			continue
+		}
+
+		busiest := math.Max(*advice.CPUUtilization, *advice.MemoryUtilization)
+		step := 0
+		switch {
+		case busiest > o.high:
+			step = 1
+		case busiest < o.low:
+			step = -1
+		}
+		if step == 0 {
+			advices = append(advices, advice)
+			continue
+		}
+
+		suggested, ok := resizedInstanceType(pool.instanceType, step)
+		if !ok {
+			advice.RecommendationNote = "no other size known for the instance type"
+			advices = append(advices, advice)
+			continue
+		}
+		if step < 0 {
+			if projected := busiest * vcpuRatio(pool.instanceType, suggested); projected >= o.high {
+				advice.RecommendationNote = fmt.Sprintf("a smaller size would reach %.0f%% utilization", projected*100)
+				advices = append(advices, advice)
+				continue
+			}
+			advice.Recommendation = adviseDownsize
+		} else {
+			advice.Recommendation = adviseUpsize
+		}
+		advice.SuggestedType = suggested
+
+		current, currentOk := hourlyPrice(pool.instanceType)
+		target, targetOk := hourlyPrice(suggested)
+		if currentOk && targetOk {
+			delta := math.Round((target-current)*hoursPerMonth*float64(advice.Nodes)*100) / 100
+			advice.MonthlyCostDelta = &delta
+		}
+		advices = append(advices, advice)
+	}
+	return advices
+}
+
+// maxUtilization returns the highest utilization of the nodes, nil when none of them has any
+func maxUtilization(nodes []string, utilization map[string]float64) *float64 {
+	var highest *float64
+	for _, node := range nodes {
+		if value, ok := utilization[node]; ok && (highest == nil || value > *highest) {
+			highest = &value
+		}
+	}
+	return highest
+}
+
+// splitInstanceType returns the family, the size and the size ladder of an AWS or GCP instance type
+func splitInstanceType(instanceType string) (family, size string, sizes []string, ok bool) {
+	if m := awsInstanceType.FindStringSubmatch(instanceType); m != nil {
+		return m[1], m[2], awsSizes, true
+	}
+	if m := gcpInstanceType.FindStringSubmatch(instanceType); m != nil {
+		return m[1], m[2], gcpSizes, true
+	}
+	return "", "", nil, false
+}
+
+func joinInstanceType(family, size string) string {
+	if strings.HasSuffix(size, "xlarge") {
+		return family + "." + size
+	}
+	return family + "-" + size
+}
+
+// resizedInstanceType returns the instance type of the same family step sizes away
+func resizedInstanceType(instanceType string, step int) (string, bool) {
+	family, size, sizes, ok := splitInstanceType(instanceType)
+	if !ok {
+		return "", false
+	}
+	i := slices.Index(sizes, size)
+	if i < 0 || i+step < 0 || i+step >= len(sizes) {
+		return "", false
+	}
+	return joinInstanceType(family, sizes[i+step]), true
+}
+
+// vcpus returns the number of vCPUs of an instance type, 0 when unknown
+func vcpus(instanceType string) float64 {
+	_, size, _, ok := splitInstanceType(instanceType)
+	if !ok {
+		return 0
+	}
+	if multiplier, found := strings.CutSuffix(size, "xlarge"); found {
+		if multiplier == "" {
+			return 4
+		}
+		n, _ := strconv.Atoi(multiplier)
+		return float64(4 * n)
+	}
+	n, _ := strconv.Atoi(size)
+	return float64(n)
+}
+
+// vcpuRatio returns how much the utilization grows when resizing from one instance type to another
+func vcpuRatio(from, to string) float64 {
+	if vcpus(to) == 0 {
+		return math.Inf(1)
+	}
+	return vcpus(from) / vcpus(to)
+}
+
+// hourlyPrice returns the estimated on-demand hourly price of an instance type
+func hourlyPrice(instanceType string) (float64, bool) {
+	family, _, _, ok := splitInstanceType(instanceType)
+	if !ok {
+		return 0, false
+	}
+	price, ok := hourlyPricePer4VCPU[family]
+	if !ok {
+		return 0, false
+	}
+	return price * vcpus(instanceType) / 4, true
+}
+
+func printAdvices(w io.Writer, advices []poolAdvice, output string) error {
+	if output == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(advices)
+	}
+
+	if len(advices) == 0 {
+		_, err := fmt.Fprintln(w, "No worker nodes found")
+		return err
+	}
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"MACHINE POOL", "INSTANCE TYPE", "NODES", "CPU P95", "MEMORY P95", "RECOMMENDATION", "SUGGESTED TYPE", "MONTHLY DELTA"})
+	for _, advice := range advices {
+		recommendation := advice.Recommendation
+		if advice.RecommendationNote != "" {
+			recommendation += " (" + advice.RecommendationNote + ")"
+		}
+		delta := ""
+		if advice.MonthlyCostDelta != nil {
+			delta = fmt.Sprintf("%+.2f USD", *advice.MonthlyCostDelta)
+		}
+		table.AddRow([]string{
+			advice.MachinePool,
+			advice.InstanceType,
+			strconv.Itoa(advice.Nodes),
+			formatUtilization(advice.CPUUtilization),
+			formatUtilization(advice.MemoryUtilization),
+			recommendation,
+			advice.SuggestedType,
+			delta,
+		})
+	}
+	return table.Flush()
+}
+
+func formatUtilization(utilization *float64) string {
+	if utilization == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.0f%%", *utilization*100)
+}
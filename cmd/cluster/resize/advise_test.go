@@ -0,0 +1,127 @@
+package resize
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestResizedInstanceType(t *testing.T) {
+	tests := []struct {
+		instanceType string
+		step         int
+		expected     string
+		ok           bool
+	}{
+		{"m5.xlarge", 1, "m5.2xlarge", true},
+		{"m5.2xlarge", -1, "m5.xlarge", true},
+		{"m5.xlarge", -1, "", false},
+		{"r6i.24xlarge", 1, "", false},
+		{"n2-standard-8", -1, "n2-standard-4", true},
+		{"n2-highmem-16", 1, "n2-highmem-32", true},
+		{"custom-8-32768", 1, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instanceType, func(t *testing.T) {
+			got, ok := resizedInstanceType(tt.instanceType, tt.step)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestHourlyPrice(t *testing.T) {
+	price, ok := hourlyPrice("m5.4xlarge")
+	require.True(t, ok)
+	assert.InDelta(t, 0.768, price, 0.0001)
+
+	price, ok = hourlyPrice("n2-standard-8")
+	require.True(t, ok)
+	assert.InDelta(t, 0.3884, price, 0.0001)
+
+	_, ok = hourlyPrice("x2gd.xlarge")
+	assert.False(t, ok)
+}
+
+func TestAdviseRun(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		newNode("master-0", map[string]string{"node-role.kubernetes.io/master": "", instanceTypeLabel: "m5.2xlarge"}),
+		newNode("infra-0", map[string]string{"node-role.kubernetes.io/infra": "", instanceTypeLabel: "r5.xlarge"}),
+		newNode("idle-0", map[string]string{"hive.openshift.io/machine-pool": "idle", instanceTypeLabel: "m5.4xlarge"}),
+		newNode("idle-1", map[string]string{"hive.openshift.io/machine-pool": "idle", instanceTypeLabel: "m5.4xlarge"}),
+		newNode("busy-0", map[string]string{"hypershift.openshift.io/nodePool": "busy", instanceTypeLabel: "m6i.xlarge"}),
+		newNode("fine-0", map[string]string{"hive.openshift.io/machine-pool": "fine", instanceTypeLabel: "m5.xlarge"}),
+		newNode("unlabeled-0", map[string]string{instanceTypeLabel: "c5.2xlarge"}),
+	).Build()
+
+	var queries []string
+	exec := func(_ context.Context, command []string) (string, error) {
+		expression := command[len(command)-1]
+		queries = append(queries, expression)
+		if strings.Contains(expression, "node_cpu_seconds_total") {
+			return `[{"metric":{"instance":"idle-0"},"value":[1700000000,"0.10"]},{"metric":{"instance":"idle-1"},"value":[1700000000,"0.20"]},` +
+				`{"metric":{"instance":"busy-0"},"value":[1700000000,"0.92"]},{"metric":{"instance":"fine-0"},"value":[1700000000,"0.50"]},` +
+				`{"metric":{"instance":"master-0"},"value":[1700000000,"0.99"]}]`, nil
+		}
+		return `[{"metric":{"instance":"idle-0"},"value":[1700000000,"0.15"]},{"metric":{"instance":"idle-1"},"value":[1700000000,"0.25"]},` +
+			`{"metric":{"instance":"busy-0"},"value":[1700000000,"0.40"]},{"metric":{"instance":"fine-0"},"value":[1700000000,"0.30"]}]`, nil
+	}
+
+	out := &bytes.Buffer{}
+	opts := &adviseOptions{since: 7 * 24 * time.Hour, low: 0.3, high: 0.8, output: "json", client: c, exec: exec, out: out}
+	require.NoError(t, opts.validate())
+	require.NoError(t, opts.run(context.Background()))
+	require.Len(t, queries, 2)
+	assert.Contains(t, queries[0], "[10080m:5m]")
+
+	advices := opts.advise(groupWorkerNodes([]corev1.Node{
+		*newNode("idle-0", map[string]string{"hive.openshift.io/machine-pool": "idle", instanceTypeLabel: "m5.4xlarge"}),
+		*newNode("idle-1", map[string]string{"hive.openshift.io/machine-pool": "idle", instanceTypeLabel: "m5.4xlarge"}),
+	}), map[string]float64{"idle-0": 0.1, "idle-1": 0.2}, map[string]float64{"idle-0": 0.15, "idle-1": 0.25})
+	require.Len(t, advices, 1)
+	assert.Equal(t, adviseDownsize, advices[0].Recommendation)
+	assert.Equal(t, "m5.2xlarge", advices[0].SuggestedType)
+	require.NotNil(t, advices[0].MonthlyCostDelta)
+	assert.InDelta(t, -560.64, *advices[0].MonthlyCostDelta, 0.001)
+
+	assert.Contains(t, out.String(), `"machinePool": "busy"`)
+	assert.Contains(t, out.String(), `"suggestedInstanceType": "m6i.2xlarge"`)
+	assert.Contains(t, out.String(), `"recommendation": "downsize"`)
+	assert.NotContains(t, out.String(), "master-0")
+	assert.NotContains(t, out.String(), "r5.xlarge")
+
+	out.Reset()
+	opts.output = "table"
+	require.NoError(t, opts.run(context.Background()))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[0], "MACHINE POOL")
+	assert.Contains(t, lines[1], "c5.2xlarge")
+	assert.Contains(t, lines[1], "keep (no utilization metrics for the nodes)")
+	assert.Contains(t, lines[2], "upsize")
+	assert.Contains(t, lines[2], "+140.16 USD")
+	assert.Contains(t, lines[3], "keep")
+	assert.Contains(t, lines[4], "downsize")
+}
+
+func TestAdviseDownsizeWouldOverload(t *testing.T) {
+	opts := &adviseOptions{low: 0.45, high: 0.8}
+	advices := opts.advise([]*workerPool{{name: "pool", instanceType: "m5.2xlarge", nodes: []string{"n"}}},
+		map[string]float64{"n": 0.42}, map[string]float64{"n": 0.2})
+	require.Len(t, advices, 1)
+	assert.Equal(t, adviseKeep, advices[0].Recommendation)
+	assert.Equal(t, "a smaller size would reach 84% utilization", advices[0].RecommendationNote)
+}
@@ -0,0 +1,276 @@
+package resize
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	resizeKindControlPlane        = "control-plane"
+	resizeKindInfra               = "infra"
+	resizeKindRequestServingNodes = "request-serving-nodes"
+)
+
+var resizeKinds = []string{resizeKindControlPlane, resizeKindInfra, resizeKindRequestServingNodes}
+
+// resizeFile is a file declaring resizes for "osdctl cluster resize apply", meant to be peer reviewed before it is
+// applied
+type resizeFile struct {
+	Resizes []resizeSpec `yaml:"resizes"`
+}
+
+// resizeSpec is a resize declared in a resize file. Its fields are named after the flags of the equivalent
+// "osdctl cluster resize" subcommand.
+type resizeSpec struct {
+	ClusterID string `yaml:"cluster-id"`
+	// Kind is the subcommand performing the resize: control-plane, infra or request-serving-nodes
+	Kind string `yaml:"type"`
+	// InstanceType is the machine type of control plane and infra nodes, or the size of request serving nodes
+	InstanceType  string   `yaml:"instance-type"`
+	Reason        string   `yaml:"reason"`
+	Jira          string   `yaml:"jira"`
+	Justification string   `yaml:"justification,omitempty"`
+	Strategy      string   `yaml:"strategy,omitempty"`
+	Set           []string `yaml:"set,omitempty"`
+	// AllowClassChange allows control plane resizes to an instance type of another class
+	AllowClassChange bool `yaml:"allow-class-change,omitempty"`
+}
+
+// applyOptions defines the struct for running the apply command
+type applyOptions struct {
+	file   string
+	dryRun bool
+
+	out io.Writer
+	// resize performs a resize of the file
+	resize func(ctx context.Context, r *resizeSpec) error
+}
+
+func newCmdResizeApply(factory clients.Factory) *cobra.Command {
+	ops := &applyOptions{
+		out: os.Stdout,
+		resize: func(ctx context.Context, r *resizeSpec) error {
+			return runResizeSpec(ctx, factory, r)
+		},
+	}
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Perform the resizes declared in a file",
+		Long: `Perform the resizes declared in a YAML file, so that planned resizes can be peer reviewed before they are
+performed and applied the same way again.
+
+  Each resize names the cluster, the type of nodes to resize (control-plane, infra or request-serving-nodes),
+  the target instance type, or size for request serving nodes, the elevation reason and the JIRA ticket
+  tracking it. The other fields are named after the flags of the equivalent "osdctl cluster resize" subcommand:
+
+    resizes:
+      - cluster-id: 1a2b3c4d5e6f7g8h9i0j1k2l3m4n5o6p
+        type: control-plane
+        instance-type: m5.4xlarge
+        reason: OHSS-1234
+        jira: OHSS-1234
+        strategy: surge
+      - cluster-id: my-cluster
+        type: infra
+        instance-type: r5.2xlarge
+        reason: OHSS-1235
+        jira: OHSS-1235
+        justification: The infra nodes are running out of memory
+      - cluster-id: my-hcp-cluster
+        type: request-serving-nodes
+        instance-type: m54xl
+        reason: OHSS-1236
+        jira: OHSS-1236
+
+  The whole file is validated before any resize is performed. The resizes are then performed in order, each
+  with the confirmations of its subcommand, and the first failure stops the remaining ones.`,
+		Example: `  # Validate a resize file and show the resizes it declares
+  osdctl cluster resize apply -f resize.yaml --dry-run
+
+  # Perform the resizes declared in a file
+  osdctl cluster resize apply -f resize.yaml`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			defer factory.Close()
+			return ops.run(context.Background())
+		},
+	}
+	applyCmd.Flags().StringVarP(&ops.file, "filename", "f", "", "The YAML file declaring the resizes")
+	applyCmd.Flags().BoolVar(&ops.dryRun, "dry-run", false, "Only validate the file and show the resizes it declares")
+	_ = applyCmd.MarkFlagRequired("filename")
+
+	return danger.Annotate(applyCmd, danger.High)
+}
+
+// loadResizeFile reads and validates a resize file
+func loadResizeFile(path string) ([]resizeSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &resizeFile{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(file.Resizes) == 0 {
+		return nil, fmt.Errorf("%s declares no resizes", path)
+	}
+
+	seen := map[string]bool{}
+	for i := range file.Resizes {
+		r := &file.Resizes[i]
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("resize %d of %s: %w", i+1, path, err)
+		}
+		key := r.Kind + "/" + r.ClusterID
+		if seen[key] {
+			return nil, fmt.Errorf("resize %d of %s: the %s nodes of cluster %s are already resized by a previous resize", i+1, path, r.Kind, r.ClusterID)
+		}
+		seen[key] = true
+	}
+	return file.Resizes, nil
+}
+
+// validate checks a resize as its subcommand would before any API call is made
+func (r *resizeSpec) validate() error {
+	if err := utils.ValidateClusterKey("cluster-id", r.ClusterID); err != nil {
+		return err
+	}
+	if !slices.Contains(resizeKinds, r.Kind) {
+		return fmt.Errorf("invalid type %q, expected one of: %v", r.Kind, resizeKinds)
+	}
+	if err := utils.ValidateReason(elevate.ReasonFlag, r.Reason); err != nil {
+		return err
+	}
+	if err := utils.ValidateTicket("jira", r.Jira); err != nil {
+		return err
+	}
+
+	switch r.Kind {
+	case resizeKindControlPlane:
+		if r.InstanceType == "" {
+			return errors.New("--instance-type is required for control-plane resizes")
+		}
+		if err := validateInstanceSize(r.InstanceType, "controlplane"); err != nil {
+			return err
+		}
+		if r.Strategy != "" && !slices.Contains(resizeStrategies, r.Strategy) {
+			return fmt.Errorf("invalid strategy %q, expected one of: %v", r.Strategy, resizeStrategies)
+		}
+		if _, err := parseProviderSpecOverrides(r.Set); err != nil {
+			return err
+		}
+	case resizeKindInfra:
+		if r.InstanceType != "" {
+			if err := validateInstanceSize(r.InstanceType, "infra"); err != nil {
+				return err
+			}
+		}
+		if err := utils.ValidateJustification("justification", r.Justification); err != nil {
+			return err
+		}
+	}
+
+	if r.Kind != resizeKindControlPlane && (r.Strategy != "" || len(r.Set) > 0 || r.AllowClassChange) {
+		return fmt.Errorf("strategy, set and allow-class-change are only supported for control-plane resizes")
+	}
+	if r.Kind != resizeKindInfra && r.Justification != "" {
+		return fmt.Errorf("justification is only supported for infra resizes")
+	}
+	return nil
+}
+
+func (o *applyOptions) run(ctx context.Context) error {
+	resizes, err := loadResizeFile(o.file)
+	if err != nil {
+		return err
+	}
+
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"#", "CLUSTER", "TYPE", "INSTANCE TYPE", "REASON", "JIRA"})
+	for i, r := range resizes {
+		table.AddRow([]string{fmt.Sprint(i + 1), r.ClusterID, r.Kind, valueOrDash(r.InstanceType), r.Reason, r.Jira})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if o.dryRun {
+		_, _ = fmt.Fprintf(o.out, "\n%s is valid, %d resizes would be performed.\n", o.file, len(resizes))
+		return nil
+	}
+
+	for i := range resizes {
+		r := &resizes[i]
+		_, _ = fmt.Fprintf(o.out, "\nPerforming resize %d/%d: %s nodes of cluster %s to %s (%s)\n", i+1, len(resizes), r.Kind, r.ClusterID, valueOrDash(r.InstanceType), r.Jira)
+		if err := o.resize(ctx, r); err != nil {
+			var remaining []string
+			for _, next := range resizes[i+1:] {
+				remaining = append(remaining, next.ClusterID)
+			}
+			if len(remaining) > 0 {
+				_, _ = fmt.Fprintf(o.out, "Resize %d failed, the resizes of the following clusters were not performed: %v\n", i+1, remaining)
+			}
+			return fmt.Errorf("resize %d of the %s nodes of cluster %s failed: %w", i+1, r.Kind, r.ClusterID, err)
+		}
+	}
+	_, _ = fmt.Fprintf(o.out, "\nAll %d resizes of %s were performed.\n", len(resizes), o.file)
+	return nil
+}
+
+// runResizeSpec validates and performs a resize as its "osdctl cluster resize" subcommand would
+func runResizeSpec(ctx context.Context, factory clients.Factory, r *resizeSpec) error {
+	switch r.Kind {
+	case resizeKindControlPlane:
+		strategy := r.Strategy
+		if strategy == "" {
+			strategy = resizeStrategySurge
+		}
+		o := &controlPlane{
+			factory:          factory,
+			clusterID:        r.ClusterID,
+			newMachineType:   r.InstanceType,
+			reason:           r.Reason,
+			providerSpecSets: r.Set,
+			strategy:         strategy,
+			allowClassChange: r.AllowClassChange,
+		}
+		if err := o.New(); err != nil {
+			return err
+		}
+		return o.run(ctx)
+	case resizeKindInfra:
+		o := &Infra{
+			clusterId:     r.ClusterID,
+			instanceType:  r.InstanceType,
+			reason:        r.Reason,
+			justification: r.Justification,
+			ohss:          r.Jira,
+		}
+		return o.RunInfra(ctx)
+	case resizeKindRequestServingNodes:
+		o := &requestServingNodesOpts{
+			clusterID: r.ClusterID,
+			size:      r.InstanceType,
+			reason:    r.Reason,
+		}
+		return o.run(ctx)
+	}
+	return fmt.Errorf("unsupported resize type %q", r.Kind)
+}
@@ -0,0 +1,157 @@
+package resize
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	"github.com/openshift/osdctl/pkg/clients"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const validResizeFile = `resizes:
+  - cluster-id: cluster-a
+    type: control-plane
+    instance-type: m5.4xlarge
+    reason: OHSS-1234
+    jira: OHSS-1234
+    strategy: in-place
+  - cluster-id: cluster-b
+    type: infra
+    instance-type: r5.2xlarge
+    reason: OHSS-1235
+    jira: OHSS-1235
+    justification: The infra nodes are running out of memory
+  - cluster-id: cluster-c
+    type: request-serving-nodes
+    reason: OHSS-1236
+    jira: OHSS-1236
+`
+
+func writeResizeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "resize.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadResizeFile(t *testing.T) {
+	resizes, err := loadResizeFile(writeResizeFile(t, validResizeFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []resizeSpec{
+		{ClusterID: "cluster-a", Kind: "control-plane", InstanceType: "m5.4xlarge", Reason: "OHSS-1234", Jira: "OHSS-1234", Strategy: "in-place"},
+		{ClusterID: "cluster-b", Kind: "infra", InstanceType: "r5.2xlarge", Reason: "OHSS-1235", Jira: "OHSS-1235", Justification: "The infra nodes are running out of memory"},
+		{ClusterID: "cluster-c", Kind: "request-serving-nodes", Reason: "OHSS-1236", Jira: "OHSS-1236"},
+	}
+	if !reflect.DeepEqual(resizes, want) {
+		t.Errorf("unexpected resizes %+v", resizes)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{name: "empty", content: "", err: "declares no resizes"},
+		{name: "unknown field", content: "resizes:\n  - cluster-id: cluster-a\n    machine-type: m5.4xlarge\n", err: "field machine-type not found"},
+		{name: "missing cluster", content: "resizes:\n  - type: infra\n", err: "resize 1 of"},
+		{name: "invalid type", content: "resizes:\n  - cluster-id: cluster-a\n    type: workers\n    reason: OHSS-1\n    jira: OHSS-1\n", err: `invalid type "workers"`},
+		{name: "missing jira", content: "resizes:\n  - cluster-id: cluster-a\n    type: control-plane\n    instance-type: m5.4xlarge\n    reason: OHSS-1\n", err: "--jira is required"},
+		{name: "unsupported instance type", content: "resizes:\n  - cluster-id: cluster-a\n    type: control-plane\n    instance-type: t3.micro\n    reason: OHSS-1\n    jira: OHSS-1\n", err: "instance type t3.micro not supported"},
+		{name: "infra without justification", content: "resizes:\n  - cluster-id: cluster-a\n    type: infra\n    reason: OHSS-1\n    jira: OHSS-1\n", err: "--justification is required"},
+		{name: "strategy of infra resize", content: "resizes:\n  - cluster-id: cluster-a\n    type: infra\n    reason: OHSS-1\n    jira: OHSS-1\n    justification: memory\n    strategy: surge\n", err: "only supported for control-plane"},
+		{name: "class change of infra resize", content: "resizes:\n  - cluster-id: cluster-a\n    type: infra\n    reason: OHSS-1\n    jira: OHSS-1\n    justification: memory\n    allow-class-change: true\n", err: "only supported for control-plane"},
+		{name: "duplicate", content: validResizeFile + "  - cluster-id: cluster-a\n    type: control-plane\n    instance-type: m5.8xlarge\n    reason: OHSS-1\n    jira: OHSS-1\n", err: "already resized by a previous resize"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadResizeFile(writeResizeFile(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	path := writeResizeFile(t, validResizeFile)
+
+	var resized []string
+	var out bytes.Buffer
+	o := &applyOptions{
+		file:   path,
+		dryRun: true,
+		out:    &out,
+		resize: func(_ context.Context, r *resizeSpec) error {
+			resized = append(resized, r.ClusterID)
+			if r.ClusterID == "cluster-b" {
+				return errors.New("machine pool not found")
+			}
+			return nil
+		},
+	}
+
+	if err := o.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(resized) != 0 {
+		t.Errorf("expected no resize on dry run, got %v", resized)
+	}
+	if !strings.Contains(out.String(), "3 resizes would be performed") {
+		t.Errorf("unexpected output %s", out.String())
+	}
+
+	out.Reset()
+	o.dryRun = false
+	err := o.run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "resize 2 of the infra nodes of cluster cluster-b failed: machine pool not found") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(resized, []string{"cluster-a", "cluster-b"}) {
+		t.Errorf("expected the resizes to stop at the first failure, got %v", resized)
+	}
+	if !strings.Contains(out.String(), "were not performed: [cluster-c]") {
+		t.Errorf("unexpected output %s", out.String())
+	}
+}
+
+// newResizeTestFactory returns a fake factory of an AWS classic cluster whose control plane machine set is missing
+func newResizeTestFactory(t *testing.T) *clients.FakeFactory {
+	scheme := runtime.NewScheme()
+	if err := machinev1.Install(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cluster, err := cmv1.NewCluster().ID("1a2b3c").Name("cluster-a").CloudProvider(cmv1.NewCloudProvider().ID("aws")).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &clients.FakeFactory{Clusters: []*cmv1.Cluster{cluster}, Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+}
+
+func TestRunResizeSpecControlPlane(t *testing.T) {
+	factory := newResizeTestFactory(t)
+	err := runResizeSpec(context.Background(), factory, &resizeSpec{
+		ClusterID:    "cluster-a",
+		Kind:         resizeKindControlPlane,
+		InstanceType: "m5.4xlarge",
+		Reason:       "OHSS-1234",
+		Jira:         "OHSS-1234",
+	})
+	if err == nil || !strings.Contains(err.Error(), "error retrieving control plane machine set") {
+		t.Errorf("expected the resize to fail retrieving the control plane machine set, got %v", err)
+	}
+	if !reflect.DeepEqual(factory.ElevatedClusterIDs, []string{"1a2b3c"}) {
+		t.Errorf("expected the elevated client of the cluster to be created by the factory, got %v", factory.ElevatedClusterIDs)
+	}
+}
@@ -1,6 +1,7 @@
 package resize
 
 import (
+	"github.com/openshift/osdctl/pkg/clients"
 	"github.com/spf13/cobra"
 )
 
@@ -58,10 +59,16 @@ func NewCmdResize() *cobra.Command {
 		Args:  cobra.NoArgs,
 	}
 
+	factory := clients.NewFactory()
 	resize.AddCommand(
 		newCmdResizeInfra(),
-		newCmdResizeControlPlane(),
+		newCmdResizeControlPlane(factory),
 		newCmdResizeRequestServingNodes(),
+		newCmdResizeAdvise(),
+		newCmdResizeApply(factory),
+		newCmdResizeApplyScheduled(factory),
+		newCmdResizeHistory(),
+		newCmdResizeStatus(factory),
 	)
 
 	return resize
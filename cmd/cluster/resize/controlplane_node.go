@@ -1,25 +1,40 @@
 package resize
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
-	bpelevate "github.com/openshift/backplane-cli/pkg/elevate"
+	"github.com/openshift/osdctl/cmd/cluster/node"
 	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/elevate"
 	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/osdCloud"
 	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -27,6 +42,30 @@ const (
 	resizeControlPlaneServiceLogTemplate = "https://raw.githubusercontent.com/openshift/managed-notifications/master/osd/controlplane_resized.json"
 	cpmsNamespace                        = "openshift-machine-api"
 	cpmsName                             = "cluster"
+	masterMachineRoleLabel               = "machine.openshift.io/cluster-api-machine-role"
+
+	// resizeStrategySurge resizes the control plane through the control plane machine set, which replaces
+	// each master with a new machine of the requested type
+	resizeStrategySurge = "surge"
+	// resizeStrategyInPlace resizes the control plane node by node (cordon, drain, patch machine, uncordon)
+	// for clusters whose control plane machine set is not active
+	resizeStrategyInPlace = "in-place"
+)
+
+var (
+	resizeStrategies = []string{resizeStrategySurge, resizeStrategyInPlace}
+
+	// gcpMachineType matches GCP predefined and custom machine types, e.g. n2-standard-8 or custom-8-32768-ext,
+	// the first group being the machine series
+	gcpMachineType = regexp.MustCompile(`^([a-z][a-z0-9]*)-[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	// nodeReadyTimeout is how long to wait for a resized control plane node to become Ready again
+	nodeReadyTimeout = 20 * time.Minute
+
+	// instanceStateTimeout is how long to wait for a control plane instance to be stopped or running
+	instanceStateTimeout = 10 * time.Minute
+	// instanceStatePollInterval is the interval between two checks of the state of a control plane instance
+	instanceStatePollInterval = 15 * time.Second
 )
 
 // controlPlane defines the struct for running resizeControlPlaneNode command
@@ -43,11 +82,44 @@ type controlPlane struct {
 
 	// reason to provide for elevation (eg: OHSS/PG ticket)
 	reason string
+
+	// user is the OCM username recorded in the resize history
+	user string
+
+	// factory creates the OCM and K8s clients of the cluster
+	factory clients.Factory
+
+	// providerSpecSets are raw --set path=value overrides applied to the providerSpec
+	providerSpecSets []string
+	overrides        []providerSpecOverride
+
+	// strategy is either surge (control plane machine sets) or in-place (node by node)
+	strategy string
+
+	// ensureSession checks the backplane session the in-place strategy drains and patches through
+	ensureSession func(ctx context.Context, clusterID string) error
+
+	// awsClient stops, resizes and starts the instances of the in-place strategy, created from awsProfile when nil
+	awsClient  awsprovider.Client
+	awsProfile string
+
+	// schedule is the start of the maintenance window to perform the resize in, the resize is performed
+	// immediately when empty
+	schedule    string
+	window      time.Duration
+	windowStart time.Time
+	windowEnd   time.Time
+
+	// dryRun shows the changes of the resize without performing it
+	dryRun bool
+
+	// allowClassChange allows resizing to an instance type of another class, e.g. m5 to m6i or n2 to n2d
+	allowClassChange bool
 }
 
 // This command requires to previously be logged in via `ocm login`
-func newCmdResizeControlPlane() *cobra.Command {
-	ops := &controlPlane{}
+func newCmdResizeControlPlane(factory clients.Factory) *cobra.Command {
+	ops := &controlPlane{factory: factory}
 	resizeControlPlaneNodeCmd := &cobra.Command{
 		Use:   "control-plane",
 		Short: "Resize an OSD/ROSA cluster's control plane nodes",
@@ -55,12 +127,45 @@ func newCmdResizeControlPlane() *cobra.Command {
 
   Requires previous login to the api server via "ocm backplane login".
   The user will be prompted to send a service log after initiating the resize. The resize process runs asynchronously,
-  and this command exits immediately after sending the service log. Any issues with the resize will be reported via PagerDuty.`,
+  and this command exits immediately after sending the service log. Any issues with the resize will be reported via PagerDuty.
+
+  Clusters without an active control plane machine set can be resized with "--strategy in-place", which resizes
+  one control plane node at a time: the node is cordoned and drained, its machine is patched to the new type, the
+  instance is resized and restarted, and the node is uncordoned once it is Ready again. This strategy is refused
+  when the control plane machine set is active.
+
+  The control plane is resized within its instance class, e.g. from m5.4xlarge to m5.8xlarge on AWS or from
+  n2-standard-8 to n2-standard-16 on GCP. "--allow-class-change" allows resizing to another class, e.g. from m5 to m6i.
+
+  With "--schedule", the resize is validated now but performed later, during a maintenance window starting at
+  the given time: the resize is stored locally and performed by running "osdctl cluster resize apply-scheduled"
+  during the window.
+
+  With "--dry-run", the changes to the control plane machine set providerSpec, or the machines to resize in place,
+  are shown without resizing anything.`,
 		Example: `  # Resize all control plane instances to m5.4xlarge using control plane machine sets
-  osdctl cluster resize control-plane --cluster-id "${CLUSTER_ID}" --machine-type m5.4xlarge --reason "${REASON}"`,
+  osdctl cluster resize control-plane --cluster-id "${CLUSTER_ID}" --machine-type m5.4xlarge --reason "${REASON}"
+
+  # Resize and additionally increase the root volume size and IOPS of the control plane instances
+  osdctl cluster resize control-plane --cluster-id "${CLUSTER_ID}" --machine-type m5.4xlarge --reason "${REASON}" \
+    --set /blockDevices/0/ebs/volumeSize=350 --set /blockDevices/0/ebs/iops=6000
+
+  # Resize the control plane node by node on a cluster whose control plane machine set is inactive
+  osdctl cluster resize control-plane --cluster-id "${CLUSTER_ID}" --machine-type m5.4xlarge --reason "${REASON}" --strategy in-place
+
+  # Validate the resize now and perform it during a 2 hour maintenance window with "osdctl cluster resize apply-scheduled"
+  osdctl cluster resize control-plane --cluster-id "${CLUSTER_ID}" --machine-type m5.4xlarge --reason "${REASON}" \
+    --schedule 2026-01-02T22:00:00Z --window 2h
+
+  # Resize the control plane of a GCP cluster to another machine series
+  osdctl cluster resize control-plane --cluster-id "${CLUSTER_ID}" --machine-type n2-standard-16 --reason "${REASON}" --allow-class-change
+
+  # Show the changes of a resize without performing it
+  osdctl cluster resize control-plane --cluster-id "${CLUSTER_ID}" --machine-type m5.4xlarge --reason "${REASON}" --dry-run`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			defer ops.factory.Close()
 			if err := ops.New(); err != nil {
 				return err
 			}
@@ -68,41 +173,67 @@ func newCmdResizeControlPlane() *cobra.Command {
 		},
 	}
 	resizeControlPlaneNodeCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "The internal ID of the cluster to perform actions on")
-	resizeControlPlaneNodeCmd.Flags().StringVar(&ops.newMachineType, "machine-type", "", "The target AWS machine type to resize to (e.g. m5.2xlarge)")
+	resizeControlPlaneNodeCmd.Flags().StringVar(&ops.newMachineType, "machine-type", "", "The target AWS or GCP machine type to resize to (e.g. m5.2xlarge or n2-standard-16)")
 	resizeControlPlaneNodeCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	resizeControlPlaneNodeCmd.Flags().StringArrayVar(&ops.providerSpecSets, "set", nil, "Override a providerSpec field using a JSON pointer path, e.g. /blockDevices/0/ebs/volumeSize=350. Can be repeated")
+	resizeControlPlaneNodeCmd.Flags().StringVar(&ops.strategy, "strategy", resizeStrategySurge, "The resize strategy, one of: surge (control plane machine sets), in-place (node by node, only for clusters without an active control plane machine set)")
+	resizeControlPlaneNodeCmd.Flags().StringVar(&ops.schedule, "schedule", "", "Validate the resize now and schedule it for a maintenance window starting at this RFC 3339 time, see \"osdctl cluster resize apply-scheduled\"")
+	resizeControlPlaneNodeCmd.Flags().DurationVar(&ops.window, "window", defaultScheduleWindow, "The duration of the maintenance window of a scheduled resize")
+	resizeControlPlaneNodeCmd.Flags().BoolVar(&ops.dryRun, "dry-run", false, "Show the changes of the resize without performing it")
+	resizeControlPlaneNodeCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "The AWS profile used to resize the instances of the in-place strategy")
+	resizeControlPlaneNodeCmd.Flags().BoolVar(&ops.allowClassChange, "allow-class-change", false, "Allow resizing to an instance type of another class (e.g. m5 to m6i, or n2 to n2d), only sizes of the current class are allowed by default")
+	resizeControlPlaneNodeCmd.MarkFlagsMutuallyExclusive("dry-run", "schedule")
 	_ = resizeControlPlaneNodeCmd.MarkFlagRequired("cluster-id")
 	_ = resizeControlPlaneNodeCmd.MarkFlagRequired("machine-type")
 	_ = resizeControlPlaneNodeCmd.MarkFlagRequired("reason")
 
-	return resizeControlPlaneNodeCmd
+	return danger.Annotate(resizeControlPlaneNodeCmd, danger.High)
 }
 
 func (o *controlPlane) New() error {
-	if err := validateInstanceSize(o.newMachineType, "controlplane"); err != nil {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
 		return err
 	}
 
-	if o.cluster != nil && o.cluster.Hypershift().Enabled() {
-		return errors.New("this command should not be used for HCP clusters")
+	if err := utils.ValidateReason(elevate.ReasonFlag, o.reason); err != nil {
+		return err
 	}
 
-	err := utils.IsValidClusterKey(o.clusterID)
-	if err != nil {
+	if err := validateInstanceSize(o.newMachineType, "controlplane"); err != nil {
 		return err
 	}
 
-	connection, err := utils.CreateConnection()
+	if err := o.validateStrategy(); err != nil {
+		return err
+	}
+
+	overrides, err := parseProviderSpecOverrides(o.providerSpecSets)
 	if err != nil {
 		return err
 	}
-	defer connection.Close()
+	o.overrides = overrides
+
+	if o.schedule != "" {
+		if o.windowStart, o.windowEnd, err = parseScheduleWindow(o.schedule, o.window, time.Now()); err != nil {
+			return err
+		}
+	}
 
-	cluster, err := utils.GetCluster(connection, o.clusterID)
+	cluster, err := o.factory.Cluster(o.clusterID)
 	if err != nil {
 		return err
 	}
+	if cluster.Hypershift().Enabled() {
+		return errors.New("this command should not be used for HCP clusters")
+	}
+	if err := validateInstanceProvider(o.newMachineType, cluster.CloudProvider().ID()); err != nil {
+		return err
+	}
 
 	o.cluster = cluster
+	if o.user, err = o.factory.Username(); err != nil {
+		log.Printf("Warning: %v, the resize is recorded without its author", err)
+	}
 
 	// Ensure we store the internal OCM cluster id
 	o.clusterID = cluster.ID()
@@ -112,29 +243,50 @@ func (o *controlPlane) New() error {
 	if err := machinev1.Install(scheme); err != nil {
 		return err
 	}
+	// Register machinev1beta1 and corev1 for the Machines and Nodes of the in-place strategy
+	if err := machinev1beta1.Install(scheme); err != nil {
+		return err
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return err
+	}
 
-	c, err := k8s.New(o.clusterID, client.Options{Scheme: scheme})
+	c, err := o.factory.KubeClient(o.clusterID, client.Options{Scheme: scheme})
 	if err != nil {
 		return err
 	}
 
-	cAdmin, err := k8s.NewAsBackplaneClusterAdmin(o.cluster.ID(), client.Options{Scheme: scheme}, []string{
-		o.reason,
-		fmt.Sprintf("Need elevation for %s cluster in order to resize it to instance type %s", o.clusterID, o.newMachineType),
-	}...)
+	cAdmin, err := o.factory.ElevatedKubeClient(o.clusterID, client.Options{Scheme: scheme}, o.elevationReason(
+		fmt.Sprintf("resize the control plane of cluster %s to instance type %s", o.clusterID, o.newMachineType)))
 	if err != nil {
 		return err
 	}
 
 	o.client = c
 	o.clientAdmin = cAdmin
+	o.ensureSession = k8s.EnsureBackplaneSession
 	return nil
 }
 
 func (o *controlPlane) embiggenMachineType() {}
 
-// extractInstanceClass extracts the instance class from an instance type string.
-// For example: "m5.4xlarge" -> "m5", "m6i.8xlarge" -> "m6i"
+// validateStrategy ensures a known resize strategy was requested and that its options are compatible
+func (o *controlPlane) validateStrategy() error {
+	if o.strategy == "" {
+		o.strategy = resizeStrategySurge
+	}
+	if !slices.Contains(resizeStrategies, o.strategy) {
+		return fmt.Errorf("invalid strategy %q, must be one of: %s", o.strategy, strings.Join(resizeStrategies, ", "))
+	}
+	if o.strategy == resizeStrategyInPlace && len(o.providerSpecSets) > 0 {
+		return fmt.Errorf("--set is only supported with the %s strategy", resizeStrategySurge)
+	}
+	return nil
+}
+
+// extractInstanceClass extracts the instance class from an AWS instance type or a GCP machine type string.
+// For example: "m5.4xlarge" -> "m5", "m6i.8xlarge" -> "m6i", "n2-standard-8" -> "n2", "n2d-custom-8-32768" -> "n2d".
+// GCP custom machine types without a series are N1 machine types: "custom-8-32768" -> "n1"
 func extractInstanceClass(instanceType string) (string, error) {
 	if strings.Contains(instanceType, ".") {
 		parts := strings.Split(instanceType, ".")
@@ -143,9 +295,50 @@ func extractInstanceClass(instanceType string) (string, error) {
 		}
 	}
 
+	if m := gcpMachineType.FindStringSubmatch(instanceType); m != nil {
+		if m[1] == "custom" {
+			return "n1", nil
+		}
+		return m[1], nil
+	}
+
 	return "", fmt.Errorf("instance type %s is not a valid instance type", instanceType)
 }
 
+// validateInstanceClass ensures a resize from currentInstanceType to newInstanceType stays within the same instance
+// class, unless the class change is explicitly allowed with --allow-class-change
+func validateInstanceClass(currentInstanceType, newInstanceType string, allowClassChange bool) error {
+	currentClass, err := extractInstanceClass(currentInstanceType)
+	if err != nil {
+		return fmt.Errorf("error extracting current instance class: %v", err)
+	}
+	newClass, err := extractInstanceClass(newInstanceType)
+	if err != nil {
+		return fmt.Errorf("error extracting new instance class: %v", err)
+	}
+	if currentClass == newClass {
+		return nil
+	}
+	if !allowClassChange {
+		return fmt.Errorf("cannot change instance class from %s to %s (current: %s, requested: %s). You can only resize within the same instance class, unless --allow-class-change is set", currentClass, newClass, currentInstanceType, newInstanceType)
+	}
+	log.Printf("Warning: changing instance class from %s to %s (current: %s, requested: %s)", currentClass, newClass, currentInstanceType, newInstanceType)
+	return nil
+}
+
+// validateInstanceProvider ensures the instance type belongs to the cloud provider of the cluster, AWS instance types
+// being the only ones with a dot, e.g. that a GCP machine type isn't requested for an AWS cluster
+func validateInstanceProvider(instanceType, cloudProvider string) error {
+	provider := "gcp"
+	if strings.Contains(instanceType, ".") {
+		provider = "aws"
+	}
+	if provider != cloudProvider {
+		return fmt.Errorf("instance type %s is a %s instance type, it is not supported on %s clusters", instanceType, strings.ToUpper(provider), cloudProvider)
+	}
+	return nil
+}
+
 type optionsDialogResponse int64
 
 const (
@@ -157,27 +350,18 @@ const (
 )
 
 func retrySkipCancelDialog(procedure string) (optionsDialogResponse, error) {
-	fmt.Printf("Do you want to retry %[1]s, skip %[1]s or cancel this command? (retry/skip/cancel):\n", procedure)
-
-	reader := bufio.NewReader(os.Stdin)
-
-	responseBytes, _, err := reader.ReadLine()
+	response, err := prompt.Select(fmt.Sprintf("Do you want to retry %[1]s, skip %[1]s or cancel this command?", procedure), []string{"retry", "skip", "cancel"}, "cancel")
 	if err != nil {
-		return Undefined, fmt.Errorf("reader.ReadLine() resulted in an error: %s", err)
+		return Undefined, err
 	}
 
-	response := strings.ToUpper(string(responseBytes))
-
 	switch response {
-	case "RETRY":
+	case "retry":
 		return Retry, nil
-	case "SKIP":
+	case "skip":
 		return Skip, nil
-	case "CANCEL":
-		return Cancel, nil
 	default:
-		fmt.Println("Invalid response, expected 'retry', 'skip' or 'cancel' (case-insensitive).")
-		return retrySkipCancelDialog(procedure)
+		return Cancel, nil
 	}
 }
 
@@ -206,52 +390,40 @@ func withRetrySkipCancelOption(fn func() error, procedure string) (err error) {
 }
 
 func retrySkipForceCancelDialog(procedure string) (optionsDialogResponse, error) {
-	fmt.Printf("Do you want to retry %s, skip %s, force %s or cancel this command? (retry/skip/force/cancel):\n", procedure, procedure, procedure)
-
-	reader := bufio.NewReader(os.Stdin)
-
-	responseBytes, _, err := reader.ReadLine()
+	response, err := prompt.Select(fmt.Sprintf("Do you want to retry %[1]s, skip %[1]s, force %[1]s or cancel this command?", procedure), []string{"retry", "skip", "force", "cancel"}, "cancel")
 	if err != nil {
-		return Undefined, fmt.Errorf("reader.ReadLine() resulted in an error: %s", err)
+		return Undefined, err
 	}
 
-	response := strings.ToUpper(string(responseBytes))
-
 	switch response {
-	case "RETRY":
+	case "retry":
 		return Retry, nil
-	case "SKIP":
+	case "skip":
 		return Skip, nil
-	case "FORCE":
+	case "force":
 		return Force, nil
-	case "CANCEL":
-		return Cancel, nil
 	default:
-		fmt.Println("Invalid response, expected 'retry', 'skip', 'force' or 'cancel' (case-insensitive).")
-		return retrySkipForceCancelDialog(procedure)
+		return Cancel, nil
 	}
 }
 
-func (o *controlPlane) forceDrainNode(nodeID string, reason string) error {
+// elevationReason returns the reason of the elevations of the resize
+func (o *controlPlane) elevationReason(justification string) elevate.Reason {
+	return elevate.Reason{Ticket: o.reason, Justification: justification, Command: "cluster resize control-plane"}
+}
+
+func (o *controlPlane) forceDrainNode(nodeID string) error {
 	printer.PrintlnGreen("Force draining node... This might take a minute or two...")
-	err := bpelevate.RunElevate([]string{
-		fmt.Sprintf("%s - Elevate required to force drain node for resizecontroleplanenode", reason),
-		"adm drain --ignore-daemonsets --delete-emptydir-data --force", nodeID,
-	})
-	if err != nil {
+	if err := node.Drain(nodeID, o.elevationReason(""), true, 0); err != nil {
 		return fmt.Errorf("failed to force drain:\n%s", err)
 	}
 	return nil
 }
 
-func (o *controlPlane) drainNode(nodeID string, reason string) error {
+func (o *controlPlane) drainNode(nodeID string) error {
 	printer.PrintlnGreen("Draining node", nodeID)
 
-	err := bpelevate.RunElevate([]string{
-		fmt.Sprintf("%s - Elevate required to drain node for resizecontroleplanenode", reason),
-		"adm drain --ignore-daemonsets --delete-emptydir-data", nodeID,
-	})
-	if err != nil {
+	if err := node.Drain(nodeID, o.elevationReason(""), false, 0); err != nil {
 		fmt.Println("Failed to drain node:")
 		fmt.Println(err)
 
@@ -262,11 +434,11 @@ func (o *controlPlane) drainNode(nodeID string, reason string) error {
 
 		switch dialogResponse {
 		case Retry:
-			return o.drainNode(nodeID, reason)
+			return o.drainNode(nodeID)
 		case Skip:
 			fmt.Println("Skipping node drain")
 		case Force:
-			err = withRetrySkipCancelOption(func() error { return o.forceDrainNode(nodeID, reason) }, "force draining")
+			err = withRetrySkipCancelOption(func() error { return o.forceDrainNode(nodeID) }, "force draining")
 			if err != nil {
 				return err
 			}
@@ -277,12 +449,11 @@ func (o *controlPlane) drainNode(nodeID string, reason string) error {
 	return nil
 }
 
-func (o *controlPlane) patchMachineType(machine string, machineType string, reason string) error {
+func (o *controlPlane) patchMachineType(machine string, machineType string) error {
 	printer.PrintlnGreen("Patching machine type of machine", machine, "to", machineType)
-	err := bpelevate.RunElevate([]string{
-		fmt.Sprintf("%s - Elevate required to patch machine type of machine %s to %s", reason, machine, machineType),
-		`-n openshift-machine-api patch machine`, machine, `--patch "{\"spec\":{\"providerSpec\":{\"value\":{\"instanceType\":\"` + machineType + `\"}}}}" --type merge`,
-	})
+	err := elevate.Run(o.elevationReason(fmt.Sprintf("patch machine type of machine %s to %s", machine, machineType)),
+		`-n openshift-machine-api patch machine`, machine, `--patch "{\"spec\":{\"providerSpec\":{\"value\":{\"instanceType\":\"`+machineType+`\"}}}}" --type merge`,
+	)
 	if err != nil {
 		return fmt.Errorf("could not patch machine type:\n%s", err)
 	}
@@ -294,11 +465,22 @@ func (o *controlPlane) patchMachineType(machine string, machineType string, reas
 func (o *controlPlane) run(ctx context.Context) error {
 	cpms := &machinev1.ControlPlaneMachineSet{}
 	if err := o.client.Get(ctx, client.ObjectKey{Namespace: cpmsNamespace, Name: cpmsName}, cpms); err != nil {
+		if o.strategy == resizeStrategyInPlace && apierrors.IsNotFound(err) {
+			return o.runInPlace(ctx)
+		}
 		return fmt.Errorf("error retrieving control plane machine set: %v", err)
 	}
 
+	if o.strategy == resizeStrategyInPlace {
+		if cpms.Spec.State == machinev1.ControlPlaneMachineSetStateActive {
+			return fmt.Errorf("control plane machine set is %s, use the %s strategy instead of %s", cpms.Spec.State, resizeStrategySurge, resizeStrategyInPlace)
+		}
+		return o.runInPlace(ctx)
+	}
+
 	if cpms.Spec.State != machinev1.ControlPlaneMachineSetStateActive {
-		return fmt.Errorf("control plane machine set is unexpectedly in %s state, must be %s - check for service logs, support exceptions, ask for a second opinion", cpms.Spec.State, machinev1.ControlPlaneMachineSetStateActive)
+		return fmt.Errorf("control plane machine set is unexpectedly in %s state, must be %s - check for service logs, support exceptions, ask for a second opinion. "+
+			"Clusters without an active control plane machine set can be resized node by node with --strategy %s", cpms.Spec.State, machinev1.ControlPlaneMachineSetStateActive, resizeStrategyInPlace)
 	}
 
 	patch := client.MergeFrom(cpms.DeepCopy())
@@ -317,16 +499,8 @@ func (o *controlPlane) run(ctx context.Context) error {
 		currentInstanceType = awsSpec.InstanceType
 
 		// Validate that instance class is not being changed
-		currentClass, err := extractInstanceClass(currentInstanceType)
-		if err != nil {
-			return fmt.Errorf("error extracting current instance class: %v", err)
-		}
-		newClass, err := extractInstanceClass(o.newMachineType)
-		if err != nil {
-			return fmt.Errorf("error extracting new instance class: %v", err)
-		}
-		if currentClass != newClass {
-			return fmt.Errorf("cannot change instance class from %s to %s (current: %s, requested: %s). You can only resize within the same instance class", currentClass, newClass, currentInstanceType, o.newMachineType)
+		if err := validateInstanceClass(currentInstanceType, o.newMachineType, o.allowClassChange); err != nil {
+			return err
 		}
 
 		awsSpec.InstanceType = o.newMachineType
@@ -340,6 +514,12 @@ func (o *controlPlane) run(ctx context.Context) error {
 		if err := json.Unmarshal(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Raw, gcpSpec); err != nil {
 			return fmt.Errorf("error unmarshalling providerSpec: %v", err)
 		}
+		currentInstanceType = gcpSpec.MachineType
+
+		// Validate that machine series is not being changed
+		if err := validateInstanceClass(currentInstanceType, o.newMachineType, o.allowClassChange); err != nil {
+			return err
+		}
 
 		gcpSpec.MachineType = o.newMachineType
 		rawBytes, err = json.Marshal(gcpSpec)
@@ -350,13 +530,53 @@ func (o *controlPlane) run(ctx context.Context) error {
 		return fmt.Errorf("cloud provider not supported: %s, only AWS and GCP are supported", o.cluster.CloudProvider().ID())
 	}
 
+	currentRaw := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Raw
+	rawBytes, err = applyProviderSpecOverrides(rawBytes, o.overrides)
+	if err != nil {
+		return err
+	}
+
+	currentSpec, err := formatProviderSpec(currentRaw)
+	if err != nil {
+		return fmt.Errorf("error formatting current providerSpec: %v", err)
+	}
+	newSpec, err := formatProviderSpec(rawBytes)
+	if err != nil {
+		return fmt.Errorf("error formatting new providerSpec: %v", err)
+	}
+	fmt.Println("The following changes will be applied to the control plane machine set providerSpec:")
+	if _, err := printer.PrintDiff(os.Stdout, "current providerSpec", "new providerSpec", currentSpec, newSpec, printer.DefaultDiffContext); err != nil {
+		return err
+	}
+
+	if o.dryRun {
+		log.Println("Dry run, the control plane machine set was not patched.")
+		return nil
+	}
+
+	if o.schedule != "" {
+		return o.scheduleResize()
+	}
+
 	log.Printf("Initiating control plane node resize for cluster %s/%s to %s using control plane machine sets. This process runs asynchronously.", o.cluster.Name(), o.cluster.ID(), o.newMachineType)
-	if !utils.ConfirmPrompt() {
+	if ok, err := danger.Confirm(danger.High, o.cluster); err != nil {
+		return err
+	} else if !ok {
 		return errors.New("aborting control plane resize")
 	}
 
-	// Patch the ControlPlaneMachineSet
+	// Patch the ControlPlaneMachineSet, recording the resize for "osdctl cluster resize history"
 	cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: rawBytes}
+	if err := recordResize(cpms, resizeRecord{
+		Time:  time.Now().UTC(),
+		Nodes: resizeNodesControlPlane,
+		From:  currentInstanceType,
+		To:    o.newMachineType,
+		By:    o.user,
+		Jira:  o.reason,
+	}); err != nil {
+		return fmt.Errorf("failed recording the resize on the control plane machine set: %v", err)
+	}
 	if err := o.clientAdmin.Patch(ctx, cpms, patch); err != nil {
 		return fmt.Errorf("failed patching control plane machine set: %v", err)
 	}
@@ -366,49 +586,229 @@ func (o *controlPlane) run(ctx context.Context) error {
 	return promptGenerateResizeSL(o.clusterID, o.newMachineType)
 }
 
+// runInPlace performs the legacy node by node control plane resize for clusters without an active control plane machine set.
+// Each master is cordoned and drained, its machine is patched to the new instance type, the instance is stopped, resized
+// and started and, once the node is Ready again, the node is uncordoned before moving on to the next one.
+func (o *controlPlane) runInPlace(ctx context.Context) error {
+	if o.cluster.CloudProvider().ID() != "aws" {
+		return fmt.Errorf("cloud provider not supported: %s, only AWS is supported by the %s strategy", o.cluster.CloudProvider().ID(), resizeStrategyInPlace)
+	}
+
+	machines, err := getMasterMachines(ctx, o.client)
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range machines {
+		if machine.Status.NodeRef == nil {
+			return fmt.Errorf("machine %s has no node, ensure all control plane nodes are healthy before resizing", machine.Name)
+		}
+		currentInstanceType, err := getMachineInstanceType(machine)
+		if err != nil {
+			return err
+		}
+		if err := validateInstanceClass(currentInstanceType, o.newMachineType, o.allowClassChange); err != nil {
+			return fmt.Errorf("machine %s: %w", machine.Name, err)
+		}
+		fmt.Printf("  %s (node %s): %s -> %s\n", machine.Name, machine.Status.NodeRef.Name, currentInstanceType, o.newMachineType)
+	}
+
+	if o.dryRun {
+		log.Println("Dry run, no control plane node was resized.")
+		return nil
+	}
+
+	// The nodes are drained and the machines patched through the current backplane login, check it before starting
+	if err := o.ensureSession(ctx, o.clusterID); err != nil {
+		return err
+	}
+
+	if o.schedule != "" {
+		return o.scheduleResize()
+	}
+
+	log.Printf("Initiating in-place control plane node resize for cluster %s/%s to %s. Control plane nodes will be resized one at a time.", o.cluster.Name(), o.cluster.ID(), o.newMachineType)
+	if ok, err := danger.Confirm(danger.High, o.cluster); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("aborting control plane resize")
+	}
+
+	if o.awsClient == nil {
+		if o.awsClient, err = osdCloud.GenerateAWSClientForCluster(o.awsProfile, o.clusterID); err != nil {
+			return err
+		}
+	}
+
+	for _, machine := range machines {
+		if err := o.resizeMachineInPlace(ctx, machine); err != nil {
+			return err
+		}
+	}
+
+	log.Println("All control plane nodes have been resized.")
+	return promptGenerateResizeSL(o.clusterID, o.newMachineType)
+}
+
+// resizeMachineInPlace cordons and drains the node of a single master machine, patches the machine type, resizes its
+// instance and uncordons the node once the resized instance is Ready again
+func (o *controlPlane) resizeMachineInPlace(ctx context.Context, machine machinev1beta1.Machine) error {
+	nodeName := machine.Status.NodeRef.Name
+	machineNode := &corev1.Node{}
+	if err := o.client.Get(ctx, client.ObjectKey{Name: nodeName}, machineNode); err != nil {
+		return fmt.Errorf("error retrieving node %s of machine %s: %v", nodeName, machine.Name, err)
+	}
+	instanceID := convertProviderIDtoInstanceID(machineNode.Spec.ProviderID)
+
+	printer.PrintlnGreen("Resizing machine", machine.Name, "- node", nodeName, "- instance", instanceID)
+
+	// adm drain cordons the node before evicting its pods
+	if err := o.drainNode(nodeName); err != nil {
+		return err
+	}
+
+	if err := withRetrySkipCancelOption(func() error { return o.patchMachineType(machine.Name, o.newMachineType) }, "patching machine type"); err != nil {
+		return err
+	}
+
+	if err := withRetrySkipCancelOption(func() error { return o.resizeInstance(ctx, instanceID) }, "resizing instance"); err != nil {
+		return err
+	}
+
+	if err := withRetrySkipCancelOption(func() error { return node.WaitForReady(ctx, o.client, nodeName, nodeReadyTimeout, nil) }, "waiting for the node to be ready"); err != nil {
+		return err
+	}
+
+	return withRetrySkipCancelOption(func() error { return o.uncordonNode(nodeName) }, "uncordoning node")
+}
+
+// resizeInstance stops an instance, changes its instance type to the new machine type and starts it again
+func (o *controlPlane) resizeInstance(ctx context.Context, instanceID string) error {
+	printer.PrintlnGreen("Stopping instance", instanceID)
+	if _, err := o.awsClient.StopInstances(&ec2.StopInstancesInput{InstanceIds: []string{instanceID}}); err != nil {
+		return fmt.Errorf("failed to stop instance %s: %v", instanceID, err)
+	}
+	if err := o.waitForInstanceState(ctx, instanceID, ec2types.InstanceStateNameStopped); err != nil {
+		return err
+	}
+
+	printer.PrintlnGreen("Changing the instance type of instance", instanceID, "to", o.newMachineType)
+	if _, err := o.awsClient.ModifyInstanceAttribute(&ec2.ModifyInstanceAttributeInput{
+		InstanceId:   awssdk.String(instanceID),
+		InstanceType: &ec2types.AttributeValue{Value: awssdk.String(o.newMachineType)},
+	}); err != nil {
+		return fmt.Errorf("failed to change the instance type of instance %s: %v", instanceID, err)
+	}
+
+	printer.PrintlnGreen("Starting instance", instanceID)
+	if _, err := o.awsClient.StartInstances(&ec2.StartInstancesInput{InstanceIds: []string{instanceID}}); err != nil {
+		return fmt.Errorf("failed to start instance %s: %v", instanceID, err)
+	}
+	return o.waitForInstanceState(ctx, instanceID, ec2types.InstanceStateNameRunning)
+}
+
+// waitForInstanceState waits until an instance reaches the given state
+func (o *controlPlane) waitForInstanceState(ctx context.Context, instanceID string, state ec2types.InstanceStateName) error {
+	log.Printf("Waiting up to %s for instance %s to be %s", instanceStateTimeout, instanceID, state)
+	err := wait.PollUntilContextTimeout(ctx, instanceStatePollInterval, instanceStateTimeout, true, func(ctx context.Context) (bool, error) {
+		out, err := o.awsClient.DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}})
+		if err != nil {
+			log.Printf("error describing instance %s: %v", instanceID, err)
+			return false, nil
+		}
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.State != nil && instance.State.Name == state {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("instance %s is not %s: %v", instanceID, state, err)
+	}
+	return nil
+}
+
+func (o *controlPlane) uncordonNode(nodeID string) error {
+	printer.PrintlnGreen("Uncordoning node", nodeID)
+	if err := node.Uncordon(nodeID, o.elevationReason("")); err != nil {
+		return fmt.Errorf("failed to uncordon node:\n%s", err)
+	}
+	return nil
+}
+
+// getMasterMachines returns the control plane machines of the cluster sorted by name
+func getMasterMachines(ctx context.Context, c client.Client) ([]machinev1beta1.Machine, error) {
+	machines := &machinev1beta1.MachineList{}
+	if err := c.List(ctx, machines, client.InNamespace(cpmsNamespace), client.MatchingLabels{masterMachineRoleLabel: "master"}); err != nil {
+		return nil, fmt.Errorf("error listing control plane machines: %v", err)
+	}
+	if len(machines.Items) == 0 {
+		return nil, errors.New("no control plane machines found")
+	}
+
+	sort.Slice(machines.Items, func(i, j int) bool {
+		return machines.Items[i].Name < machines.Items[j].Name
+	})
+	return machines.Items, nil
+}
+
+// getMachineInstanceType returns the AWS instance type from a machine's providerSpec
+func getMachineInstanceType(machine machinev1beta1.Machine) (string, error) {
+	if machine.Spec.ProviderSpec.Value == nil {
+		return "", fmt.Errorf("machine %s has no providerSpec", machine.Name)
+	}
+	awsSpec := &machinev1beta1.AWSMachineProviderConfig{}
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, awsSpec); err != nil {
+		return "", fmt.Errorf("error unmarshalling providerSpec of machine %s: %v", machine.Name, err)
+	}
+	return awsSpec.InstanceType, nil
+}
+
 func promptGenerateResizeSL(clusterID string, newMachineType string) error {
 	fmt.Println("The resize operation is in progress and will complete asynchronously. A service log will now be sent to document this action. Any issues with the resize will be reported via PagerDuty.")
 	fmt.Println("Would you like to proceed with sending the service log?")
-	if !utils.ConfirmPrompt() {
+	if !prompt.ConfirmPrompt() {
 		fmt.Println("Service log not sent. The resize is still in progress, and this command will now exit. Monitor PagerDuty for any issues.")
+		fmt.Printf("Run \"osdctl cluster resize status --cluster-id %s --watch\" to follow the resize and send the service log once it completes.\n", clusterID)
 		return nil
 	}
 
-	var jiraID string
-	fmt.Print("Please enter the JIRA ID that corresponds to this resize: ")
-	_, err := fmt.Scanln(&jiraID)
+	jiraID, err := prompt.Input("Please enter the JIRA ID that corresponds to this resize", "")
 	if err != nil {
 		log.Printf("Error reading JIRA ID: %v, proceeding with empty value", err)
 	}
 
-	var justification string
-	fmt.Print("Please enter a justification for the resize: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	if scanner.Scan() {
-		justification = scanner.Text()
-	} else if err := scanner.Err(); err != nil {
+	justification, err := prompt.Input("Please enter a justification for the resize", "")
+	if err != nil {
 		errText := "failed to read justification text, send service log manually"
 		_, _ = fmt.Fprintf(os.Stderr, "%s: %v\n", errText, err)
 		return errors.New(errText)
 	}
 
-	postCmd := servicelog.PostCmdOptions{
-		Template: resizeControlPlaneServiceLogTemplate,
-		TemplateParams: []string{
-			fmt.Sprintf("INSTANCE_TYPE=%s", newMachineType),
-			fmt.Sprintf("JIRA_ID=%s", jiraID),
-			fmt.Sprintf("JUSTIFICATION=%s", justification),
-		},
-		ClusterId: clusterID,
-	}
-
+	postCmd := controlPlaneResizedServiceLog(clusterID, newMachineType, jiraID, justification)
 	if err := postCmd.Run(); err != nil {
 		return fmt.Errorf("failed to send service log: %v", err)
 	}
 
 	fmt.Println("Service log sent successfully. Use the following command to track progress of the resize:")
 	fmt.Println()
-	fmt.Println(`watch -d 'oc get machines -n openshift-machine-api -l machine.openshift.io/cluster-api-machine-role=master && oc get nodes -l node-role.kubernetes.io/master'`)
+	fmt.Printf("osdctl cluster resize status --cluster-id %s --watch\n", clusterID)
 
 	return nil
 }
+
+// controlPlaneResizedServiceLog returns the service log notifying the customer of the resize of the control plane
+func controlPlaneResizedServiceLog(clusterID, newMachineType, jiraID, justification string) servicelog.PostCmdOptions {
+	return servicelog.PostCmdOptions{
+		Template: resizeControlPlaneServiceLogTemplate,
+		TemplateParams: []string{
+			fmt.Sprintf("INSTANCE_TYPE=%s", newMachineType),
+			fmt.Sprintf("JIRA_ID=%s", jiraID),
+			fmt.Sprintf("JUSTIFICATION=%s", justification),
+		},
+		ClusterId: clusterID,
+	}
+}
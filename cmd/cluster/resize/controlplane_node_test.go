@@ -1,7 +1,22 @@
 package resize
 
 import (
+	"context"
+	"errors"
 	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestExtractInstanceClass_AWS(t *testing.T) {
@@ -92,3 +107,344 @@ func TestInstanceClassValidation_AWS(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractInstanceClass_GCP(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceType string
+		expected     string
+	}{
+		{
+			name:         "GCP n2 standard machine",
+			instanceType: "n2-standard-8",
+			expected:     "n2",
+		},
+		{
+			name:         "GCP n2d highmem machine",
+			instanceType: "n2d-highmem-16",
+			expected:     "n2d",
+		},
+		{
+			name:         "GCP n2 custom machine",
+			instanceType: "n2-custom-8-32768",
+			expected:     "n2",
+		},
+		{
+			name:         "GCP custom machine",
+			instanceType: "custom-16-65536",
+			expected:     "n1",
+		},
+		{
+			name:         "GCP custom extended memory machine",
+			instanceType: "custom-8-65536-ext",
+			expected:     "n1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := extractInstanceClass(tt.instanceType)
+			if err != nil {
+				t.Error(err)
+			}
+			if result != tt.expected {
+				t.Errorf("extractInstanceClass(%s) = %s, expected %s", tt.instanceType, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractInstanceClass_Invalid(t *testing.T) {
+	for _, instanceType := range []string{"", "m5", "N2-standard-8", "n2-", "-standard-8"} {
+		if class, err := extractInstanceClass(instanceType); err == nil {
+			t.Errorf("extractInstanceClass(%s) = %s, expected an error", instanceType, class)
+		}
+	}
+}
+
+func TestInstanceClassValidation_GCP(t *testing.T) {
+	tests := []struct {
+		name             string
+		currentInstance  string
+		newInstance      string
+		allowClassChange bool
+		shouldFail       bool
+	}{
+		{
+			name:            "Same class GCP n2",
+			currentInstance: "n2-standard-8",
+			newInstance:     "n2-standard-16",
+			shouldFail:      false,
+		},
+		{
+			name:            "Different class GCP n2 to n2d",
+			currentInstance: "n2-standard-8",
+			newInstance:     "n2d-standard-16",
+			shouldFail:      true,
+		},
+		{
+			name:            "Different class GCP custom to n2",
+			currentInstance: "custom-8-32768",
+			newInstance:     "n2-standard-16",
+			shouldFail:      true,
+		},
+		{
+			name:            "Same class GCP custom",
+			currentInstance: "custom-8-32768",
+			newInstance:     "custom-16-65536",
+			shouldFail:      false,
+		},
+		{
+			name:             "Different class GCP allowed",
+			currentInstance:  "custom-8-32768",
+			newInstance:      "n2-standard-16",
+			allowClassChange: true,
+			shouldFail:       false,
+		},
+		{
+			name:             "Different class AWS allowed",
+			currentInstance:  "m5.4xlarge",
+			newInstance:      "m6i.8xlarge",
+			allowClassChange: true,
+			shouldFail:       false,
+		},
+		{
+			name:            "Invalid current instance",
+			currentInstance: "unknown",
+			newInstance:     "n2-standard-16",
+			shouldFail:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateInstanceClass(tt.currentInstance, tt.newInstance, tt.allowClassChange)
+			if failed := err != nil; failed != tt.shouldFail {
+				t.Errorf("Instance class validation for %s -> %s: expected shouldFail=%v, got %v", tt.currentInstance, tt.newInstance, tt.shouldFail, err)
+			}
+		})
+	}
+}
+
+func TestValidateInstanceProvider(t *testing.T) {
+	tests := []struct {
+		instanceType  string
+		cloudProvider string
+		expectErr     bool
+	}{
+		{instanceType: "m5.4xlarge", cloudProvider: "aws"},
+		{instanceType: "n2-standard-8", cloudProvider: "gcp"},
+		{instanceType: "custom-16-65536", cloudProvider: "gcp"},
+		{instanceType: "n2-standard-8", cloudProvider: "aws", expectErr: true},
+		{instanceType: "m6i.8xlarge", cloudProvider: "gcp", expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.instanceType+"/"+test.cloudProvider, func(t *testing.T) {
+			err := validateInstanceProvider(test.instanceType, test.cloudProvider)
+			if (err != nil) != test.expectErr {
+				t.Errorf("validateInstanceProvider(%s, %s) = %v, expected error: %v", test.instanceType, test.cloudProvider, err, test.expectErr)
+			}
+		})
+	}
+}
+
+func TestValidateStrategy(t *testing.T) {
+	tests := []struct {
+		name       string
+		ops        *controlPlane
+		expected   string
+		shouldFail bool
+	}{
+		{
+			name:     "Defaults to surge",
+			ops:      &controlPlane{},
+			expected: resizeStrategySurge,
+		},
+		{
+			name:     "In-place",
+			ops:      &controlPlane{strategy: resizeStrategyInPlace},
+			expected: resizeStrategyInPlace,
+		},
+		{
+			name:     "Surge with providerSpec overrides",
+			ops:      &controlPlane{strategy: resizeStrategySurge, providerSpecSets: []string{"/blockDevices/0/ebs/volumeSize=350"}},
+			expected: resizeStrategySurge,
+		},
+		{
+			name:       "In-place with providerSpec overrides",
+			ops:        &controlPlane{strategy: resizeStrategyInPlace, providerSpecSets: []string{"/blockDevices/0/ebs/volumeSize=350"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Unknown strategy",
+			ops:        &controlPlane{strategy: "rolling"},
+			shouldFail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ops.validateStrategy()
+			if tt.shouldFail {
+				if err == nil {
+					t.Errorf("expected an error for strategy %q", tt.ops.strategy)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.ops.strategy != tt.expected {
+				t.Errorf("strategy = %s, expected %s", tt.ops.strategy, tt.expected)
+			}
+		})
+	}
+}
+
+func newTestMasterMachine(name, role, instanceType string) *machinev1beta1.Machine {
+	return &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cpmsNamespace,
+			Labels:    map[string]string{masterMachineRoleLabel: role},
+		},
+		Spec: machinev1beta1.MachineSpec{
+			ProviderSpec: machinev1beta1.ProviderSpec{
+				Value: &runtime.RawExtension{Raw: []byte(`{"instanceType":"` + instanceType + `"}`)},
+			},
+		},
+	}
+}
+
+func TestGetMasterMachines(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := machinev1beta1.Install(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newTestMasterMachine("master-2", "master", "m5.2xlarge"),
+		newTestMasterMachine("infra-0", "infra", "r5.xlarge"),
+		newTestMasterMachine("master-0", "master", "m5.2xlarge"),
+		newTestMasterMachine("master-1", "master", "m5.2xlarge"),
+	).Build()
+
+	machines, err := getMasterMachines(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, m := range machines {
+		names = append(names, m.Name)
+	}
+	if len(names) != 3 || names[0] != "master-0" || names[1] != "master-1" || names[2] != "master-2" {
+		t.Errorf("getMasterMachines() = %v, expected [master-0 master-1 master-2]", names)
+	}
+
+	instanceType, err := getMachineInstanceType(machines[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instanceType != "m5.2xlarge" {
+		t.Errorf("getMachineInstanceType() = %s, expected m5.2xlarge", instanceType)
+	}
+
+	empty := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if _, err := getMasterMachines(context.Background(), empty); err == nil {
+		t.Error("expected an error when there are no control plane machines")
+	}
+}
+
+func TestRunInPlaceRefusedWithActiveCPMS(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := machinev1.Install(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&machinev1.ControlPlaneMachineSet{
+		ObjectMeta: metav1.ObjectMeta{Name: cpmsName, Namespace: cpmsNamespace},
+		Spec:       machinev1.ControlPlaneMachineSetSpec{State: machinev1.ControlPlaneMachineSetStateActive},
+	}).Build()
+
+	ops := &controlPlane{client: c, strategy: resizeStrategyInPlace, newMachineType: "m5.4xlarge"}
+	if err := ops.run(context.Background()); err == nil {
+		t.Error("expected the in-place strategy to be refused when the control plane machine set is active")
+	}
+}
+
+func describeInstanceInState(state ec2types.InstanceStateName) *ec2.DescribeInstancesOutput {
+	return &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{{
+		Instances: []ec2types.Instance{{State: &ec2types.InstanceState{Name: state}}},
+	}}}
+}
+
+func TestResizeInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	awsClient := mock.NewMockClient(ctrl)
+	instanceIDs := []string{"i-0123456789abcdef0"}
+
+	gomock.InOrder(
+		awsClient.EXPECT().StopInstances(&ec2.StopInstancesInput{InstanceIds: instanceIDs}).Return(&ec2.StopInstancesOutput{}, nil),
+		awsClient.EXPECT().DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: instanceIDs}).Return(describeInstanceInState(ec2types.InstanceStateNameStopped), nil),
+		awsClient.EXPECT().ModifyInstanceAttribute(&ec2.ModifyInstanceAttributeInput{
+			InstanceId:   awssdk.String(instanceIDs[0]),
+			InstanceType: &ec2types.AttributeValue{Value: awssdk.String("m5.4xlarge")},
+		}).Return(&ec2.ModifyInstanceAttributeOutput{}, nil),
+		awsClient.EXPECT().StartInstances(&ec2.StartInstancesInput{InstanceIds: instanceIDs}).Return(&ec2.StartInstancesOutput{}, nil),
+		awsClient.EXPECT().DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: instanceIDs}).Return(describeInstanceInState(ec2types.InstanceStateNameRunning), nil),
+	)
+
+	ops := &controlPlane{awsClient: awsClient, newMachineType: "m5.4xlarge"}
+	if err := ops.resizeInstance(context.Background(), instanceIDs[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	awsClient.EXPECT().StopInstances(gomock.Any()).Return(nil, errors.New("UnauthorizedOperation"))
+	if err := ops.resizeInstance(context.Background(), instanceIDs[0]); err == nil {
+		t.Error("expected the error of stopping the instance")
+	}
+}
+
+func TestNewWithFactory(t *testing.T) {
+	classic, _ := cmv1.NewCluster().ID("1a2b3c").ExternalID("ext-1a2b3c").Name("my-cluster").CloudProvider(cmv1.NewCloudProvider().ID("aws")).Build()
+	hcp, _ := cmv1.NewCluster().ID("4d5e6f").Name("my-hcp").Hypershift(cmv1.NewHypershift().Enabled(true)).Build()
+	c := fake.NewClientBuilder().Build()
+	factory := &clients.FakeFactory{Clusters: []*cmv1.Cluster{classic, hcp}, User: "jdoe", Client: c}
+
+	ops := &controlPlane{factory: factory, clusterID: "my-cluster", newMachineType: "m5.4xlarge", reason: "OHSS-12345"}
+	if err := ops.New(); err != nil {
+		t.Fatal(err)
+	}
+	if ops.clusterID != "1a2b3c" {
+		t.Errorf("expected the internal cluster ID to be stored, got %s", ops.clusterID)
+	}
+	if ops.user != "jdoe" {
+		t.Errorf("expected the OCM username to be recorded, got %s", ops.user)
+	}
+	if ops.client != c || ops.clientAdmin != c {
+		t.Error("expected the clients of the factory to be used")
+	}
+	if len(factory.ElevatedClusterIDs) != 1 || factory.ElevatedClusterIDs[0] != "1a2b3c" {
+		t.Errorf("expected an elevated client of the cluster, got %v", factory.ElevatedClusterIDs)
+	}
+	if factory.Reasons[0].Ticket != "OHSS-12345" || factory.Reasons[0].Command != "cluster resize control-plane" {
+		t.Errorf("unexpected elevation reason %+v", factory.Reasons[0])
+	}
+
+	ops = &controlPlane{factory: factory, clusterID: "my-cluster", newMachineType: "n2-standard-8", reason: "OHSS-12345"}
+	if err := ops.New(); err == nil {
+		t.Error("expected a GCP machine type to be refused for an AWS cluster")
+	}
+
+	ops = &controlPlane{factory: factory, clusterID: "my-hcp", newMachineType: "m5.4xlarge", reason: "OHSS-12345"}
+	if err := ops.New(); err == nil {
+		t.Error("expected HCP clusters to be refused")
+	}
+
+	ops = &controlPlane{factory: &clients.FakeFactory{Err: errors.New("not logged in")}, clusterID: "my-cluster", newMachineType: "m5.4xlarge", reason: "OHSS-12345"}
+	if err := ops.New(); err == nil || err.Error() != "not logged in" {
+		t.Errorf("expected the error of the factory, got %v", err)
+	}
+}
@@ -0,0 +1,356 @@
+package resize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	infraPkg "github.com/openshift/osdctl/pkg/infra"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// resizeHistoryAnnotation holds the JSON list of the resizes of the control plane machine set or infra machine pool
+	resizeHistoryAnnotation = "osdctl.openshift.io/resize-history"
+	// maxResizeHistory is the number of resizes kept in the annotation, oldest first
+	maxResizeHistory = 20
+
+	resizeNodesControlPlane = "control-plane"
+	resizeNodesInfra        = "infra"
+
+	resizeSourceAnnotation = "annotation"
+	resizeSourceServiceLog = "servicelog"
+
+	// serviceLogMatchWindow is how far apart the annotation and service log of the same resize can be
+	serviceLogMatchWindow = 6 * time.Hour
+)
+
+var (
+	resizeServiceLogInstanceType = regexp.MustCompile(`\b([a-z][a-z0-9-]*\.[0-9]*x?large|custom-[0-9]+-[0-9]+(-ext)?|n2-(standard|highmem)-[0-9]+)\b`)
+	resizeServiceLogJira         = regexp.MustCompile(`\b(OHSS|PG|SREP|OSD)-[0-9]+\b`)
+)
+
+// resizeRecord is a past resize of the control plane or infra nodes of a cluster
+type resizeRecord struct {
+	Time   time.Time `json:"time"`
+	Nodes  string    `json:"nodes"`
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to"`
+	By     string    `json:"by,omitempty"`
+	Jira   string    `json:"jira,omitempty"`
+	Source string    `json:"source,omitempty"`
+}
+
+// recordResize appends the resize to the history annotation of the object, dropping the oldest resizes beyond
+// maxResizeHistory
+func recordResize(obj metav1.Object, record resizeRecord) error {
+	history, err := resizeHistoryFromAnnotation(obj)
+	if err != nil {
+		// A corrupted history must not block the resize, start a new one
+		log.Warnf("Discarding the unreadable resize history of %s: %v", obj.GetName(), err)
+		history = nil
+	}
+	history = append(history, record)
+	if len(history) > maxResizeHistory {
+		history = history[len(history)-maxResizeHistory:]
+	}
+	// The source is implied by where the history is stored
+	for i := range history {
+		history[i].Source = ""
+	}
+
+	raw, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[resizeHistoryAnnotation] = string(raw)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// resizeHistoryFromAnnotation returns the resizes recorded on the object
+func resizeHistoryFromAnnotation(obj metav1.Object) ([]resizeRecord, error) {
+	raw, ok := obj.GetAnnotations()[resizeHistoryAnnotation]
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	var history []resizeRecord
+	if err := json.Unmarshal([]byte(raw), &history); err != nil {
+		return nil, fmt.Errorf("failed to parse the %s annotation: %w", resizeHistoryAnnotation, err)
+	}
+	for i := range history {
+		history[i].Source = resizeSourceAnnotation
+	}
+	return history, nil
+}
+
+// ocmUsername returns the username of the OCM account osdctl is logged in with, recorded as the author of resizes
+func ocmUsername(connection *sdk.Connection) string {
+	response, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		log.Warnf("Failed to get the current OCM account, the resize is recorded without its author: %v", err)
+		return ""
+	}
+	return response.Body().Username()
+}
+
+type resizeHistoryOptions struct {
+	clusterID string
+	output    string
+
+	out io.Writer
+
+	// controlPlaneHistory, infraHistory and serviceLogs are the sources of the history, replaced in tests
+	controlPlaneHistory func(ctx context.Context) ([]resizeRecord, error)
+	infraHistory        func(ctx context.Context) ([]resizeRecord, error)
+	serviceLogs         func() ([]*slv1.LogEntry, error)
+}
+
+func newCmdResizeHistory() *cobra.Command {
+	opts := &resizeHistoryOptions{out: os.Stdout}
+
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the past control plane and infra node resizes of a cluster",
+		Long: `Show the past control plane and infra node resizes of a cluster
+
+  The resizes performed with osdctl are recorded in the ` + resizeHistoryAnnotation + ` annotation of the
+  control plane machine set and of the infra machine pool on hive, with the previous and new instance types,
+  who performed them and the ticket referenced. The resize service logs sent to the cluster complete the
+  history, e.g. for the resizes performed before they were recorded.
+
+  The annotations are read through backplane; when they can't be read, only the service logs are shown.`,
+		Example: `  # Show when the control plane and infra nodes of a cluster were resized
+  osdctl cluster resize history --cluster-id ${CLUSTER_ID}
+
+  # As JSON
+  osdctl cluster resize history --cluster-id ${CLUSTER_ID} -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			return opts.run(cmd.Context())
+		},
+	}
+
+	historyCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "The internal ID of the cluster to show the resize history of")
+	historyCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	_ = historyCmd.MarkFlagRequired("cluster-id")
+
+	return historyCmd
+}
+
+func (o *resizeHistoryOptions) complete() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, must be table or json", o.output)
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	cluster, err := utils.GetClusterAnyStatus(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	o.clusterID = cluster.ID()
+
+	o.controlPlaneHistory = func(ctx context.Context) ([]resizeRecord, error) {
+		scheme := runtime.NewScheme()
+		if err := machinev1.Install(scheme); err != nil {
+			return nil, err
+		}
+		c, err := k8s.New(o.clusterID, client.Options{Scheme: scheme})
+		if err != nil {
+			return nil, err
+		}
+		cpms := &machinev1.ControlPlaneMachineSet{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: cpmsNamespace, Name: cpmsName}, cpms); err != nil {
+			return nil, err
+		}
+		return resizeHistoryFromAnnotation(cpms)
+	}
+	o.infraHistory = func(ctx context.Context) ([]resizeRecord, error) {
+		scheme := runtime.NewScheme()
+		if err := hivev1.AddToScheme(scheme); err != nil {
+			return nil, err
+		}
+		if err := corev1.AddToScheme(scheme); err != nil {
+			return nil, err
+		}
+		hive, err := utils.GetHiveCluster(o.clusterID)
+		if err != nil {
+			return nil, err
+		}
+		hc, err := k8s.New(hive.ID(), client.Options{Scheme: scheme})
+		if err != nil {
+			return nil, err
+		}
+		mp, err := infraPkg.GetInfraMachinePool(ctx, hc, o.clusterID)
+		if err != nil {
+			return nil, err
+		}
+		return resizeHistoryFromAnnotation(mp)
+	}
+	o.serviceLogs = func() ([]*slv1.LogEntry, error) {
+		return servicelog.FetchServiceLogs(o.clusterID, true, false)
+	}
+	return nil
+}
+
+func (o *resizeHistoryOptions) run(ctx context.Context) error {
+	var history []resizeRecord
+	for _, source := range []struct {
+		nodes string
+		read  func(ctx context.Context) ([]resizeRecord, error)
+	}{
+		{nodes: resizeNodesControlPlane, read: o.controlPlaneHistory},
+		{nodes: resizeNodesInfra, read: o.infraHistory},
+	} {
+		records, err := source.read(ctx)
+		if err != nil {
+			log.Warnf("Failed to read the recorded %s resizes, only their service logs are shown: %v", source.nodes, err)
+			continue
+		}
+		history = append(history, records...)
+	}
+
+	entries, err := o.serviceLogs()
+	if err != nil {
+		return fmt.Errorf("failed to fetch the service logs of cluster %s: %w", o.clusterID, err)
+	}
+	history = mergeResizeServiceLogs(history, entries)
+
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(history)
+	}
+	return printResizeHistory(o.out, history)
+}
+
+// resizeFromServiceLog returns the resize a service log notified the customer of, if any
+func resizeFromServiceLog(entry *slv1.LogEntry) (resizeRecord, bool) {
+	summary := strings.ToLower(entry.Summary())
+	if !strings.Contains(summary, "resiz") {
+		return resizeRecord{}, false
+	}
+
+	record := resizeRecord{
+		Time:   entry.CreatedAt(),
+		By:     entry.CreatedBy(),
+		Source: resizeSourceServiceLog,
+	}
+	switch {
+	case strings.Contains(summary, "control plane") || strings.Contains(summary, "control-plane") || strings.Contains(summary, "master"):
+		record.Nodes = resizeNodesControlPlane
+	case strings.Contains(summary, "infra"):
+		record.Nodes = resizeNodesInfra
+	default:
+		return resizeRecord{}, false
+	}
+
+	text := entry.Summary() + " " + entry.Description()
+	if instanceTypes := resizeServiceLogInstanceType.FindAllString(text, -1); len(instanceTypes) > 0 {
+		// The new instance type is the last one mentioned, e.g. "from m5.2xlarge to m5.4xlarge"
+		record.To = instanceTypes[len(instanceTypes)-1]
+		if len(instanceTypes) > 1 {
+			record.From = instanceTypes[len(instanceTypes)-2]
+		}
+	}
+	record.Jira = resizeServiceLogJira.FindString(text)
+	return record, true
+}
+
+// mergeResizeServiceLogs adds the resizes of the service logs to the recorded ones, completing the recorded
+// resizes the service logs were sent for, and returns them oldest first
+func mergeResizeServiceLogs(history []resizeRecord, entries []*slv1.LogEntry) []resizeRecord {
+	for _, entry := range entries {
+		notified, ok := resizeFromServiceLog(entry)
+		if !ok {
+			continue
+		}
+
+		matched := false
+		for i := range history {
+			recorded := &history[i]
+			if recorded.Source != resizeSourceAnnotation || recorded.Nodes != notified.Nodes || recorded.To != notified.To {
+				continue
+			}
+			if gap := notified.Time.Sub(recorded.Time); gap < 0 || gap > serviceLogMatchWindow {
+				continue
+			}
+			recorded.Source = resizeSourceAnnotation + "+" + resizeSourceServiceLog
+			if recorded.Jira == "" {
+				recorded.Jira = notified.Jira
+			}
+			matched = true
+			break
+		}
+		if !matched {
+			history = append(history, notified)
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Time.Before(history[j].Time) })
+	return history
+}
+
+func printResizeHistory(w io.Writer, history []resizeRecord) error {
+	if len(history) == 0 {
+		_, err := fmt.Fprintln(w, "No resize found")
+		return err
+	}
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"TIME", "NODES", "FROM", "TO", "BY", "JIRA", "SOURCE"})
+	for _, record := range history {
+		table.AddRow([]string{
+			record.Time.UTC().Format(time.RFC3339),
+			record.Nodes,
+			valueOrDash(record.From),
+			valueOrDash(record.To),
+			valueOrDash(record.By),
+			valueOrDash(record.Jira),
+			record.Source,
+		})
+	}
+	return table.Flush()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+package resize
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func serviceLogEntry(t *testing.T, summary, description, by string, at time.Time) *slv1.LogEntry {
+	entry, err := slv1.NewLogEntry().Summary(summary).Description(description).CreatedBy(by).CreatedAt(at).Build()
+	require.NoError(t, err)
+	return entry
+}
+
+func TestRecordResize(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cpms := &machinev1.ControlPlaneMachineSet{ObjectMeta: metav1.ObjectMeta{Name: cpmsName}}
+
+	for i := 0; i < maxResizeHistory+2; i++ {
+		require.NoError(t, recordResize(cpms, resizeRecord{
+			Time:  start.Add(time.Duration(i) * time.Hour),
+			Nodes: resizeNodesControlPlane,
+			To:    fmt.Sprintf("type-%d", i),
+		}))
+	}
+
+	history, err := resizeHistoryFromAnnotation(cpms)
+	require.NoError(t, err)
+	require.Len(t, history, maxResizeHistory)
+	assert.Equal(t, "type-2", history[0].To)
+	assert.Equal(t, fmt.Sprintf("type-%d", maxResizeHistory+1), history[maxResizeHistory-1].To)
+	assert.Equal(t, resizeSourceAnnotation, history[0].Source)
+	assert.NotContains(t, cpms.Annotations[resizeHistoryAnnotation], `"source"`)
+
+	// An unreadable history is replaced rather than blocking the resize
+	cpms.Annotations[resizeHistoryAnnotation] = "not json"
+	_, err = resizeHistoryFromAnnotation(cpms)
+	assert.Error(t, err)
+	require.NoError(t, recordResize(cpms, resizeRecord{Time: start, Nodes: resizeNodesControlPlane, To: "m5.4xlarge"}))
+	history, err = resizeHistoryFromAnnotation(cpms)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+}
+
+func TestResizeFromServiceLog(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		summary  string
+		desc     string
+		expected *resizeRecord
+	}{
+		{
+			name:     "control plane",
+			summary:  "Control plane nodes resized",
+			desc:     "Your cluster's control plane nodes were resized to m5.4xlarge (OHSS-1234) due to increased load.",
+			expected: &resizeRecord{Nodes: resizeNodesControlPlane, To: "m5.4xlarge", Jira: "OHSS-1234"},
+		},
+		{
+			name:     "infra with previous type",
+			summary:  "Infra nodes resized",
+			desc:     "The infra nodes were resized from r5.xlarge to r5.2xlarge.",
+			expected: &resizeRecord{Nodes: resizeNodesInfra, From: "r5.xlarge", To: "r5.2xlarge"},
+		},
+		{
+			name:    "unrelated",
+			summary: "Cluster upgrade scheduled",
+			desc:    "m5.4xlarge",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, ok := resizeFromServiceLog(serviceLogEntry(t, tt.summary, tt.desc, "service-account-sre", at))
+			if tt.expected == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			tt.expected.Time = at
+			tt.expected.By = "service-account-sre"
+			tt.expected.Source = resizeSourceServiceLog
+			assert.Equal(t, *tt.expected, record)
+		})
+	}
+}
+
+func TestResizeHistoryRun(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	opts := &resizeHistoryOptions{
+		clusterID: "abc",
+		output:    "table",
+		controlPlaneHistory: func(context.Context) ([]resizeRecord, error) {
+			return []resizeRecord{{Time: start.Add(24 * time.Hour), Nodes: resizeNodesControlPlane, From: "m5.2xlarge", To: "m5.4xlarge", By: "jdoe", Source: resizeSourceAnnotation}}, nil
+		},
+		infraHistory: func(context.Context) ([]resizeRecord, error) {
+			return nil, errors.New("no backplane session")
+		},
+		serviceLogs: func() ([]*slv1.LogEntry, error) {
+			return []*slv1.LogEntry{
+				serviceLogEntry(t, "Control plane nodes resized", "Resized to m5.4xlarge, see OHSS-42", "jdoe", start.Add(25*time.Hour)),
+				serviceLogEntry(t, "Infra nodes resized", "Resized to r5.2xlarge", "alice", start),
+				serviceLogEntry(t, "Cluster upgrade scheduled", "", "alice", start),
+			}, nil
+		},
+	}
+
+	out := &bytes.Buffer{}
+	opts.out = out
+	require.NoError(t, opts.run(context.Background()))
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^2024-05-01T12:00:00Z\s+infra\s+-\s+r5.2xlarge\s+alice\s+-\s+servicelog$`, string(lines[1]))
+	assert.Regexp(t, `^2024-05-02T12:00:00Z\s+control-plane\s+m5.2xlarge\s+m5.4xlarge\s+jdoe\s+OHSS-42\s+annotation\+servicelog$`, string(lines[2]))
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"slices"
 	"strings"
+	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -17,6 +18,8 @@ import (
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/elevate"
 	infraPkg "github.com/openshift/osdctl/pkg/infra"
 	"github.com/openshift/osdctl/pkg/k8s"
 	"github.com/openshift/osdctl/pkg/osdCloud"
@@ -54,6 +57,9 @@ type Infra struct {
 
 	// hiveOcmUrl is the OCM environment URL for Hive operations
 	hiveOcmUrl string
+
+	// user is the OCM username recorded in the resize history
+	user string
 }
 
 func newCmdResizeInfra() *cobra.Command {
@@ -93,7 +99,7 @@ func newCmdResizeInfra() *cobra.Command {
 	_ = infraResizeCmd.MarkFlagRequired("reason")
 	_ = infraResizeCmd.MarkFlagRequired("ohss")
 
-	return infraResizeCmd
+	return danger.Annotate(infraResizeCmd, danger.High)
 }
 
 func (r *Infra) New() error {
@@ -147,6 +153,7 @@ func (r *Infra) New() error {
 		}
 		r.cluster = cluster
 		r.clusterId = cluster.ID()
+		r.user = ocmUsername(targetOCM)
 
 		hive, err = utils.GetHiveClusterWithConn(cluster.ID(), targetOCM, hiveOCM)
 		if err != nil {
@@ -163,10 +170,7 @@ func (r *Infra) New() error {
 			return fmt.Errorf("failed to create hive k8s client (OCM URL:'%s'): %w", r.hiveOcmUrl, err)
 		}
 
-		hac, err = k8s.NewAsBackplaneClusterAdminWithConn(hive.ID(), client.Options{Scheme: scheme}, hiveOCM, []string{
-			r.reason,
-			fmt.Sprintf("Need elevation for %s cluster in order to resize it to instance type %s", r.clusterId, r.instanceType),
-		}...)
+		hac, err = elevate.NewClientWithConn(hive.ID(), client.Options{Scheme: scheme}, hiveOCM, r.elevationReason())
 		if err != nil {
 			return fmt.Errorf("failed to create hive admin k8s client (OCM URL:'%s'): %w", r.hiveOcmUrl, err)
 		}
@@ -184,6 +188,7 @@ func (r *Infra) New() error {
 		}
 		r.cluster = cluster
 		r.clusterId = cluster.ID()
+		r.user = ocmUsername(ocmClient)
 
 		hive, err = utils.GetHiveCluster(cluster.ID())
 		if err != nil {
@@ -200,10 +205,7 @@ func (r *Infra) New() error {
 			return err
 		}
 
-		hac, err = k8s.NewAsBackplaneClusterAdmin(hive.ID(), client.Options{Scheme: scheme}, []string{
-			r.reason,
-			fmt.Sprintf("Need elevation for %s cluster in order to resize it to instance type %s", r.clusterId, r.instanceType),
-		}...)
+		hac, err = elevate.NewClient(hive.ID(), client.Options{Scheme: scheme}, r.elevationReason())
 		if err != nil {
 			return err
 		}
@@ -217,7 +219,34 @@ func (r *Infra) New() error {
 	return nil
 }
 
+// validate checks the flags before any API call is made
+// elevationReason returns the reason of the elevations on the hive shard of the cluster
+func (r *Infra) elevationReason() elevate.Reason {
+	return elevate.Reason{
+		Ticket:        r.reason,
+		Justification: fmt.Sprintf("resize the infra nodes of cluster %s to instance type %s", r.clusterId, r.instanceType),
+		Command:       "cluster resize infra",
+	}
+}
+
+func (r *Infra) validate() error {
+	if err := utils.ValidateClusterKey("cluster-id", r.clusterId); err != nil {
+		return err
+	}
+	if err := utils.ValidateReason(elevate.ReasonFlag, r.reason); err != nil {
+		return err
+	}
+	if err := utils.ValidateJustification("justification", r.justification); err != nil {
+		return err
+	}
+	return utils.ValidateTicket("ohss", r.ohss)
+}
+
 func (r *Infra) RunInfra(ctx context.Context) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+
 	if err := r.New(); err != nil {
 		return fmt.Errorf("failed to initialize command: %v", err)
 	}
@@ -241,10 +270,22 @@ func (r *Infra) RunInfra(ctx context.Context) error {
 		return fmt.Errorf("failed to parse instance type from machinepool: %v", err)
 	}
 
+	// The history is carried over by the replacement machine pool, for "osdctl cluster resize history"
+	if err := recordResize(newMp, resizeRecord{
+		Time:  time.Now().UTC(),
+		Nodes: resizeNodesInfra,
+		From:  originalInstanceType,
+		To:    instanceType,
+		By:    r.user,
+		Jira:  r.ohss,
+	}); err != nil {
+		return fmt.Errorf("failed recording the resize on the machine pool: %v", err)
+	}
+
 	log.Printf("planning to resize to instance type from %s to %s", originalInstanceType, instanceType)
-	if !utils.ConfirmPrompt() {
+	if ok, err := danger.Confirm(danger.High, r.cluster); err != nil || !ok {
 		log.Printf("exiting")
-		return nil
+		return err
 	}
 
 	clients := infraPkg.DanceClients{
@@ -173,6 +173,31 @@ func TestValidateInstanceSize(t *testing.T) {
 			nodeType:     "controlplane",
 			expectErr:    true,
 		},
+		{
+			instanceSize: "n2-standard-8",
+			nodeType:     "controlplane",
+			expectErr:    false,
+		},
+		{
+			instanceSize: "custom-16-65536",
+			nodeType:     "controlplane",
+			expectErr:    false,
+		},
+		{
+			instanceSize: "n2-highmem-8",
+			nodeType:     "controlplane",
+			expectErr:    true,
+		},
+		{
+			instanceSize: "n2-highmem-8",
+			nodeType:     "infra",
+			expectErr:    false,
+		},
+		{
+			instanceSize: "n2-standard-8",
+			nodeType:     "infra",
+			expectErr:    true,
+		},
 	}
 
 	for _, test := range tests {
@@ -279,3 +304,36 @@ func TestHiveOcmUrlValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestInfraValidate(t *testing.T) {
+	valid := Infra{clusterId: "test-cluster", reason: "OHSS-1234", justification: "The infra nodes are running out of memory", ohss: "OHSS-1234"}
+
+	tests := []struct {
+		name    string
+		modify  func(r *Infra)
+		wantErr string
+	}{
+		{name: "valid", modify: func(r *Infra) {}},
+		{name: "malformed cluster id", modify: func(r *Infra) { r.clusterId = "test cluster" }, wantErr: "isn't valid"},
+		{name: "malformed reason", modify: func(r *Infra) { r.reason = "OHSS1234" }, wantErr: "--reason references the malformed ticket"},
+		{name: "empty justification", modify: func(r *Infra) { r.justification = "" }, wantErr: "--justification is required"},
+		{name: "ohss is not a ticket", modify: func(r *Infra) { r.ohss = "SREP-1" }, wantErr: "--ohss must be an OHSS or ITN ticket"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid
+			tt.modify(&r)
+			err := r.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
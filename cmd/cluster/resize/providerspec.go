@@ -0,0 +1,130 @@
+package resize
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// protectedProviderSpecPaths cannot be overridden with --set as they are managed by --machine-type
+var protectedProviderSpecPaths = []string{"/instanceType", "/machineType"}
+
+// providerSpecOverride is a single JSON-pointer style override of a providerSpec field
+type providerSpecOverride struct {
+	path  string
+	value interface{}
+}
+
+// parseProviderSpecOverrides parses --set flags of the form /json/pointer=value.
+// Values that are valid JSON (numbers, booleans, objects...) are decoded, anything else is kept as a string.
+func parseProviderSpecOverrides(sets []string) ([]providerSpecOverride, error) {
+	overrides := make([]providerSpecOverride, 0, len(sets))
+	for _, s := range sets {
+		path, rawValue, found := strings.Cut(s, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid --set %q: expected path=value", s)
+		}
+		if !strings.HasPrefix(path, "/") || path == "/" {
+			return nil, fmt.Errorf("invalid --set %q: path must be a JSON pointer such as /blockDevices/0/ebs/volumeSize", s)
+		}
+		for _, protected := range protectedProviderSpecPaths {
+			if path == protected {
+				return nil, fmt.Errorf("invalid --set %q: use --machine-type to change the instance type", s)
+			}
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(rawValue), &value); err != nil {
+			value = rawValue
+		}
+		overrides = append(overrides, providerSpecOverride{path: path, value: value})
+	}
+	return overrides, nil
+}
+
+// applyProviderSpecOverrides applies the overrides to the raw providerSpec and returns the updated raw providerSpec.
+// Missing intermediate objects are created, array indices must already exist unless "-" is used to append.
+func applyProviderSpecOverrides(raw []byte, overrides []providerSpecOverride) ([]byte, error) {
+	if len(overrides) == 0 {
+		return raw, nil
+	}
+
+	var spec interface{}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, fmt.Errorf("error unmarshalling providerSpec: %v", err)
+	}
+
+	for _, o := range overrides {
+		updated, err := setJSONPointer(spec, splitJSONPointer(o.path), o.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set %s: %v", o.path, err)
+		}
+		spec = updated
+	}
+
+	return json.Marshal(spec)
+}
+
+func splitJSONPointer(path string) []string {
+	tokens := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+func setJSONPointer(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	token := tokens[0]
+
+	switch n := node.(type) {
+	case nil:
+		child, err := setJSONPointer(nil, tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{token: child}, nil
+	case map[string]interface{}:
+		child, err := setJSONPointer(n[token], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = child
+		return n, nil
+	case []interface{}:
+		if token == "-" {
+			if len(tokens) > 1 {
+				return nil, fmt.Errorf("'-' can only be used as the last path element")
+			}
+			return append(n, value), nil
+		}
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 || idx >= len(n) {
+			return nil, fmt.Errorf("invalid array index %q (length %d)", token, len(n))
+		}
+		child, err := setJSONPointer(n[idx], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = child
+		return n, nil
+	default:
+		return nil, fmt.Errorf("cannot descend into %q: parent is a %T", token, node)
+	}
+}
+
+// formatProviderSpec indents a raw providerSpec with sorted keys, so that two providerSpecs can be diffed line by line
+func formatProviderSpec(raw []byte) (string, error) {
+	var spec interface{}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return "", err
+	}
+	formatted, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
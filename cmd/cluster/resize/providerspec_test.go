@@ -0,0 +1,126 @@
+package resize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProviderSpecOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		sets      []string
+		expected  []providerSpecOverride
+		expectErr bool
+	}{
+		{
+			name:     "number value",
+			sets:     []string{"/blockDevices/0/ebs/volumeSize=350"},
+			expected: []providerSpecOverride{{path: "/blockDevices/0/ebs/volumeSize", value: float64(350)}},
+		},
+		{
+			name:     "string value",
+			sets:     []string{"/blockDevices/0/ebs/volumeType=io1"},
+			expected: []providerSpecOverride{{path: "/blockDevices/0/ebs/volumeType", value: "io1"}},
+		},
+		{
+			name:     "boolean value",
+			sets:     []string{"/blockDevices/0/ebs/encrypted=true"},
+			expected: []providerSpecOverride{{path: "/blockDevices/0/ebs/encrypted", value: true}},
+		},
+		{
+			name:      "missing value",
+			sets:      []string{"/blockDevices/0/ebs/volumeSize"},
+			expectErr: true,
+		},
+		{
+			name:      "not a JSON pointer",
+			sets:      []string{"blockDevices.0.ebs.volumeSize=350"},
+			expectErr: true,
+		},
+		{
+			name:      "instance type is protected",
+			sets:      []string{"/instanceType=m5.8xlarge"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides, err := parseProviderSpecOverrides(tt.sets)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, overrides)
+		})
+	}
+}
+
+func TestApplyProviderSpecOverrides(t *testing.T) {
+	raw := []byte(`{"instanceType":"m5.4xlarge","blockDevices":[{"ebs":{"volumeSize":120,"volumeType":"gp3"}}]}`)
+
+	tests := []struct {
+		name      string
+		overrides []providerSpecOverride
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:      "replace existing value",
+			overrides: []providerSpecOverride{{path: "/blockDevices/0/ebs/volumeSize", value: float64(350)}},
+			expected:  `{"blockDevices":[{"ebs":{"volumeSize":350,"volumeType":"gp3"}}],"instanceType":"m5.4xlarge"}`,
+		},
+		{
+			name:      "add missing value",
+			overrides: []providerSpecOverride{{path: "/blockDevices/0/ebs/iops", value: float64(6000)}},
+			expected:  `{"blockDevices":[{"ebs":{"iops":6000,"volumeSize":120,"volumeType":"gp3"}}],"instanceType":"m5.4xlarge"}`,
+		},
+		{
+			name:      "create intermediate objects",
+			overrides: []providerSpecOverride{{path: "/metadataServiceOptions/authentication", value: "Required"}},
+			expected:  `{"blockDevices":[{"ebs":{"volumeSize":120,"volumeType":"gp3"}}],"instanceType":"m5.4xlarge","metadataServiceOptions":{"authentication":"Required"}}`,
+		},
+		{
+			name:      "out of range index",
+			overrides: []providerSpecOverride{{path: "/blockDevices/3/ebs/volumeSize", value: float64(350)}},
+			expectErr: true,
+		},
+		{
+			name:      "descend into scalar",
+			overrides: []providerSpecOverride{{path: "/instanceType/foo", value: "bar"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := applyProviderSpecOverrides(raw, tt.overrides)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestFormatProviderSpec(t *testing.T) {
+	formatted, err := formatProviderSpec([]byte(`{"instanceType":"m5.2xlarge","blockDevices":[{"ebs":{"volumeSize":120}}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "blockDevices": [
+    {
+      "ebs": {
+        "volumeSize": 120
+      }
+    }
+  ],
+  "instanceType": "m5.2xlarge"
+}`, formatted)
+
+	_, err = formatProviderSpec([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -9,6 +9,8 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
 	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/elevate"
 	"github.com/openshift/osdctl/pkg/k8s"
 	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/utils"
@@ -78,12 +80,14 @@ func newCmdResizeRequestServingNodes() *cobra.Command {
 	_ = cmd.MarkFlagRequired("reason")
 	cmd.MarkFlagsMutuallyExclusive("size", "remove-override")
 
-	return cmd
+	return danger.Annotate(cmd, danger.High)
 }
 
 func (r *requestServingNodesOpts) run(ctx context.Context) error {
-	// Validate cluster key
-	if err := utils.IsValidClusterKey(r.clusterID); err != nil {
+	if err := utils.ValidateClusterKey("cluster-id", r.clusterID); err != nil {
+		return err
+	}
+	if err := utils.ValidateReason(elevate.ReasonFlag, r.reason); err != nil {
 		return err
 	}
 
@@ -134,7 +138,11 @@ func (r *requestServingNodesOpts) run(ctx context.Context) error {
 	r.mgmtClient = mgmtClient
 
 	// Create admin client with elevation for management cluster
-	mgmtClientAdmin, err := k8s.NewAsBackplaneClusterAdmin(mgmtClusterID, client.Options{Scheme: scheme}, r.reason)
+	mgmtClientAdmin, err := elevate.NewClient(mgmtClusterID, client.Options{Scheme: scheme}, elevate.Reason{
+		Ticket:        r.reason,
+		Justification: fmt.Sprintf("resize the request serving nodes of hosted cluster %s", r.clusterID),
+		Command:       "cluster resize request-serving-nodes",
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create admin management cluster client: %v", err)
 	}
@@ -225,7 +233,9 @@ func (r *requestServingNodesOpts) run(ctx context.Context) error {
 
 	// Prompt user to confirm
 	fmt.Printf("\nThis will resize cluster %s from %s to %s\n", cluster.Name(), currentSize, targetSize)
-	if !utils.ConfirmPrompt() {
+	if ok, err := danger.Confirm(danger.High, cluster); err != nil {
+		return err
+	} else if !ok {
 		return errors.New("resize cancelled by user")
 	}
 
@@ -442,7 +452,9 @@ func (r *requestServingNodesOpts) handleRemoveOverride(ctx context.Context, host
 	} else {
 		fmt.Println("The cluster will revert to automatic sizing based on the worker node pool size.")
 	}
-	if !utils.ConfirmPrompt() {
+	if ok, err := danger.Confirm(danger.High, r.cluster); err != nil {
+		return err
+	} else if !ok {
 		return errors.New("operation cancelled by user")
 	}
 
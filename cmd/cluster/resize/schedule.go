@@ -0,0 +1,295 @@
+package resize
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// resizeScheduleDir is the directory of the data dir the scheduled control plane resizes are stored in
+	resizeScheduleDir = "resize-schedules"
+
+	defaultScheduleWindow = 2 * time.Hour
+)
+
+// scheduledResize is a control plane resize which was validated when scheduled, and is run by
+// "osdctl cluster resize apply-scheduled" during its maintenance window
+type scheduledResize struct {
+	ClusterID        string    `json:"clusterId"`
+	ClusterName      string    `json:"clusterName"`
+	MachineType      string    `json:"machineType"`
+	Strategy         string    `json:"strategy"`
+	ProviderSpecSets []string  `json:"providerSpecSets,omitempty"`
+	AllowClassChange bool      `json:"allowClassChange,omitempty"`
+	Reason           string    `json:"reason"`
+	WindowStart      time.Time `json:"windowStart"`
+	WindowEnd        time.Time `json:"windowEnd"`
+	Scheduled        time.Time `json:"scheduled"`
+}
+
+// status returns whether the maintenance window of the resize is pending, open or missed at the given time
+func (s *scheduledResize) status(now time.Time) string {
+	switch {
+	case now.Before(s.WindowStart):
+		return "pending"
+	case now.After(s.WindowEnd):
+		return "missed"
+	default:
+		return "due"
+	}
+}
+
+// parseScheduleWindow parses the start of a maintenance window, which must be in the future
+func parseScheduleWindow(schedule string, window time.Duration, now time.Time) (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, schedule)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --schedule %q, expected an RFC 3339 time such as 2026-01-02T22:00:00Z: %w", schedule, err)
+	}
+	if !start.After(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--schedule %s is not in the future", start.Format(time.RFC3339))
+	}
+	if window <= 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --window %s, must be positive", window)
+	}
+	return start, start.Add(window), nil
+}
+
+func resizeScheduleDirPath() (string, error) {
+	dataDir, err := osdctlConfig.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, resizeScheduleDir), nil
+}
+
+func (s *scheduledResize) save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, s.ClusterID+".json"), data, 0600)
+}
+
+func removeScheduledResize(dir, clusterID string) error {
+	err := os.Remove(filepath.Join(dir, clusterID+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// loadScheduledResizes reads the scheduled resizes, sorted by the start of their window
+func loadScheduledResizes(dir string) ([]*scheduledResize, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var schedules []*scheduledResize
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		s := &scheduledResize{}
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("failed to parse the scheduled resize %s: %w", path, err)
+		}
+		schedules = append(schedules, s)
+	}
+	sort.SliceStable(schedules, func(i, j int) bool { return schedules[i].WindowStart.Before(schedules[j].WindowStart) })
+	return schedules, nil
+}
+
+// scheduleResize stores the validated resize instead of performing it
+func (o *controlPlane) scheduleResize() error {
+	dir, err := resizeScheduleDirPath()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Scheduling control plane node resize for cluster %s/%s to %s using the %s strategy between %s and %s.",
+		o.cluster.Name(), o.cluster.ID(), o.newMachineType, o.strategy, o.windowStart.Format(time.RFC3339), o.windowEnd.Format(time.RFC3339))
+	if !prompt.ConfirmPrompt() {
+		return errors.New("aborting control plane resize scheduling")
+	}
+
+	s := &scheduledResize{
+		ClusterID:        o.clusterID,
+		ClusterName:      o.cluster.Name(),
+		MachineType:      o.newMachineType,
+		Strategy:         o.strategy,
+		ProviderSpecSets: o.providerSpecSets,
+		AllowClassChange: o.allowClassChange,
+		Reason:           o.reason,
+		WindowStart:      o.windowStart,
+		WindowEnd:        o.windowEnd,
+		Scheduled:        time.Now().UTC(),
+	}
+	if err := s.save(dir); err != nil {
+		return fmt.Errorf("failed to store the scheduled resize: %w", err)
+	}
+
+	fmt.Printf("Resize scheduled. Run the following command during the maintenance window to perform it:\n\n")
+	fmt.Printf("  osdctl cluster resize apply-scheduled --cluster-id %s\n", o.clusterID)
+	return nil
+}
+
+// applyScheduledOptions defines the struct for running the apply-scheduled command
+type applyScheduledOptions struct {
+	clusterID string
+	list      bool
+
+	out io.Writer
+	dir string
+	now func() time.Time
+	// resize performs a scheduled resize
+	resize func(ctx context.Context, s *scheduledResize) error
+}
+
+func newCmdResizeApplyScheduled(factory clients.Factory) *cobra.Command {
+	ops := &applyScheduledOptions{
+		out: os.Stdout,
+		now: time.Now,
+		resize: func(ctx context.Context, s *scheduledResize) error {
+			return runScheduledResize(ctx, factory, s)
+		},
+	}
+	applyScheduledCmd := &cobra.Command{
+		Use:   "apply-scheduled",
+		Short: "Perform the control plane resizes whose maintenance window is open",
+		Long: `Perform the control plane resizes scheduled with "osdctl cluster resize control-plane --schedule" whose
+maintenance window is open.
+
+  The resizes were validated when scheduled, and are validated again before being performed. A resize is
+  removed once performed. Resizes whose window has passed are reported and kept, and can be rescheduled by
+  running "osdctl cluster resize control-plane --schedule" again.`,
+		Example: `  # List the scheduled resizes
+  osdctl cluster resize apply-scheduled --list
+
+  # Perform the scheduled resize of a cluster during its maintenance window
+  osdctl cluster resize apply-scheduled --cluster-id "${CLUSTER_ID}"
+
+  # Perform all the scheduled resizes whose maintenance window is open
+  osdctl cluster resize apply-scheduled`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := resizeScheduleDirPath()
+			if err != nil {
+				return err
+			}
+			ops.dir = dir
+			defer factory.Close()
+			return ops.run(context.Background())
+		},
+	}
+	applyScheduledCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Only perform the scheduled resize of this cluster")
+	applyScheduledCmd.Flags().BoolVar(&ops.list, "list", false, "List the scheduled resizes without performing them")
+
+	return applyScheduledCmd
+}
+
+func (o *applyScheduledOptions) run(ctx context.Context) error {
+	schedules, err := loadScheduledResizes(o.dir)
+	if err != nil {
+		return err
+	}
+	if o.clusterID != "" {
+		if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+			return err
+		}
+		var matching []*scheduledResize
+		for _, s := range schedules {
+			if s.ClusterID == o.clusterID || s.ClusterName == o.clusterID {
+				matching = append(matching, s)
+			}
+		}
+		if len(matching) == 0 {
+			return fmt.Errorf("no resize is scheduled for cluster %s", o.clusterID)
+		}
+		schedules = matching
+	}
+	if len(schedules) == 0 {
+		_, _ = fmt.Fprintln(o.out, "No resize is scheduled.")
+		return nil
+	}
+
+	now := o.now()
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"CLUSTER ID", "NAME", "MACHINE TYPE", "STRATEGY", "WINDOW START", "WINDOW END", "STATUS"})
+	for _, s := range schedules {
+		table.AddRow([]string{s.ClusterID, s.ClusterName, s.MachineType, s.Strategy, s.WindowStart.Format(time.RFC3339), s.WindowEnd.Format(time.RFC3339), s.status(now)})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if o.list {
+		return nil
+	}
+
+	var failed []string
+	for _, s := range schedules {
+		if s.status(now) != "due" {
+			continue
+		}
+		_, _ = fmt.Fprintf(o.out, "\nPerforming the scheduled resize of cluster %s/%s to %s\n", s.ClusterName, s.ClusterID, s.MachineType)
+		if err := o.resize(ctx, s); err != nil {
+			_, _ = fmt.Fprintf(o.out, "Scheduled resize of cluster %s failed: %v\n", s.ClusterID, err)
+			failed = append(failed, s.ClusterID)
+			continue
+		}
+		if err := removeScheduledResize(o.dir, s.ClusterID); err != nil {
+			return fmt.Errorf("resize of cluster %s performed, but failed to remove it from the schedule: %w", s.ClusterID, err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("scheduled resizes failed for clusters: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// runScheduledResize validates and performs a scheduled resize as "osdctl cluster resize control-plane" would
+func runScheduledResize(ctx context.Context, factory clients.Factory, s *scheduledResize) error {
+	o := &controlPlane{
+		factory:          factory,
+		clusterID:        s.ClusterID,
+		newMachineType:   s.MachineType,
+		reason:           s.Reason,
+		providerSpecSets: s.ProviderSpecSets,
+		strategy:         s.Strategy,
+		allowClassChange: s.AllowClassChange,
+	}
+	if err := o.New(); err != nil {
+		return err
+	}
+	return o.run(ctx)
+}
@@ -0,0 +1,117 @@
+package resize
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScheduleWindow(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	start, end, err := parseScheduleWindow("2026-01-02T22:00:00Z", 2*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !start.Equal(time.Date(2026, 1, 2, 22, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected window %s - %s", start, end)
+	}
+
+	for _, tt := range []struct {
+		schedule string
+		window   time.Duration
+	}{
+		{schedule: "tomorrow", window: time.Hour},
+		{schedule: "2026-01-02T10:00:00Z", window: time.Hour},
+		{schedule: "2026-01-02T22:00:00Z", window: 0},
+	} {
+		if _, _, err := parseScheduleWindow(tt.schedule, tt.window, now); err == nil {
+			t.Errorf("expected an error for schedule %s and window %s", tt.schedule, tt.window)
+		}
+	}
+}
+
+func TestApplyScheduled(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 2, 23, 0, 0, 0, time.UTC)
+
+	schedules := []*scheduledResize{
+		{ClusterID: "due-ok", MachineType: "m5.4xlarge", WindowStart: now.Add(-time.Hour), WindowEnd: now.Add(time.Hour)},
+		{ClusterID: "due-failing", MachineType: "m5.4xlarge", WindowStart: now.Add(-time.Hour), WindowEnd: now.Add(time.Hour)},
+		{ClusterID: "pending", MachineType: "m5.4xlarge", WindowStart: now.Add(time.Hour), WindowEnd: now.Add(2 * time.Hour)},
+		{ClusterID: "missed", MachineType: "m5.4xlarge", WindowStart: now.Add(-3 * time.Hour), WindowEnd: now.Add(-time.Hour)},
+	}
+	for _, s := range schedules {
+		if err := s.save(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var resized []string
+	var out bytes.Buffer
+	o := &applyScheduledOptions{
+		out: &out,
+		dir: dir,
+		now: func() time.Time { return now },
+		resize: func(_ context.Context, s *scheduledResize) error {
+			resized = append(resized, s.ClusterID)
+			if s.ClusterID == "due-failing" {
+				return errors.New("control plane machine set is inactive")
+			}
+			return nil
+		},
+	}
+
+	o.list = true
+	if err := o.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(resized) != 0 {
+		t.Errorf("expected --list not to perform any resize, got %v", resized)
+	}
+
+	o.list = false
+	err := o.run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "due-failing") {
+		t.Errorf("expected the failed resize to be reported, got %v", err)
+	}
+	if strings.Join(resized, ",") != "due-failing,due-ok" {
+		t.Errorf("expected only the due resizes to be performed, got %v", resized)
+	}
+
+	remaining, err := loadScheduledResizes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range remaining {
+		ids = append(ids, s.ClusterID)
+	}
+	if strings.Join(ids, ",") != "missed,due-failing,pending" {
+		t.Errorf("expected the performed resize to be removed from the schedule, got %v", ids)
+	}
+
+	o.clusterID = "unknown"
+	if err := o.run(context.Background()); err == nil {
+		t.Error("expected an error for a cluster without a scheduled resize")
+	}
+}
+
+func TestRunScheduledResize(t *testing.T) {
+	factory := newResizeTestFactory(t)
+	err := runScheduledResize(context.Background(), factory, &scheduledResize{
+		ClusterID:   "1a2b3c",
+		MachineType: "m5.4xlarge",
+		Strategy:    resizeStrategySurge,
+		Reason:      "OHSS-1234",
+	})
+	if err == nil || !strings.Contains(err.Error(), "error retrieving control plane machine set") {
+		t.Errorf("expected the resize to fail retrieving the control plane machine set, got %v", err)
+	}
+	if len(factory.ElevatedClusterIDs) != 1 || factory.ElevatedClusterIDs[0] != "1a2b3c" {
+		t.Errorf("expected the elevated client of the cluster to be created by the factory, got %v", factory.ElevatedClusterIDs)
+	}
+}
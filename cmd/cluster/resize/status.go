@@ -0,0 +1,366 @@
+package resize
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultStatusInterval = 30 * time.Second
+	machinePhaseRunning   = "Running"
+)
+
+// resizeStatusOptions defines the struct for running the resize status command
+type resizeStatusOptions struct {
+	clusterID     string
+	watch         bool
+	interval      time.Duration
+	autoSL        bool
+	jiraID        string
+	justification string
+
+	out     io.Writer
+	factory clients.Factory
+	client  client.Client
+	now     func() time.Time
+	// wait blocks between two polls of the rollout with --watch
+	wait func(ctx context.Context) error
+	// sendServiceLog sends the completion service log of the resize
+	sendServiceLog func(sl resizeCompletion) error
+}
+
+// controlPlaneRollout is the progress of the control plane machine set replacing the control plane machines
+type controlPlaneRollout struct {
+	// instanceType is the instance type of the control plane machine set the machines are rolled out to
+	instanceType string
+	// observed is whether the control plane machine set controller has seen the latest change of its spec
+	observed bool
+	replicas int32
+	updated  int32
+	ready    int32
+	machines []rolloutMachine
+	// resize is the last resize recorded on the control plane machine set, nil when none was
+	resize *resizeRecord
+}
+
+// rolloutMachine is a control plane machine of a rollout
+type rolloutMachine struct {
+	name         string
+	phase        string
+	instanceType string
+	node         string
+}
+
+// resizeCompletion is a completed control plane resize, notified to the customer by a service log
+type resizeCompletion struct {
+	clusterID     string
+	instanceType  string
+	jiraID        string
+	justification string
+	started       time.Time
+	finished      time.Time
+}
+
+func newCmdResizeStatus(factory clients.Factory) *cobra.Command {
+	ops := &resizeStatusOptions{factory: factory, now: time.Now}
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the progress of a control plane resize",
+		Long: `Show the progress of a control plane resize
+
+  The control plane machine set replaces the control plane machines one at a time after a resize. The status
+  shows the instance type they are rolled out to and the phase and instance type of every control plane machine.
+
+  With "--watch", the status is refreshed until all the control plane machines are rolled out. The completion
+  service log is then offered, or sent right away with "--auto-sl", including when the resize started and
+  finished: the start is the time the resize was recorded on the control plane machine set, or when the watch
+  started for the resizes which weren't recorded, and the finish is when the watch observed the rollout complete.
+
+  Requires previous login to the api server via "ocm backplane login".`,
+		Example: `  # Show the progress of the control plane resize of a cluster
+  osdctl cluster resize status --cluster-id "${CLUSTER_ID}"
+
+  # Watch the resize and offer to send the completion service log once all the control plane machines are rolled out
+  osdctl cluster resize status --cluster-id "${CLUSTER_ID}" --watch
+
+  # Watch the resize and send the completion service log automatically
+  osdctl cluster resize status --cluster-id "${CLUSTER_ID}" --watch --auto-sl --justification "${JUSTIFICATION}"`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			defer ops.factory.Close()
+			ops.out = cmd.OutOrStdout()
+			if err := ops.complete(); err != nil {
+				return err
+			}
+			return ops.run(cmd.Context())
+		},
+	}
+	statusCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "The internal ID of the cluster to show the resize status of")
+	statusCmd.Flags().BoolVarP(&ops.watch, "watch", "w", false, "Refresh the status until all the control plane machines are rolled out, then offer the completion service log")
+	statusCmd.Flags().DurationVar(&ops.interval, "interval", defaultStatusInterval, "How often the status is refreshed with --watch")
+	statusCmd.Flags().BoolVar(&ops.autoSL, "auto-sl", false, "Send the completion service log without prompting once the rollout completes, requires --watch and --justification")
+	statusCmd.Flags().StringVar(&ops.jiraID, "jira", "", "The JIRA ID of the completion service log, defaults to the ticket recorded with the resize")
+	statusCmd.Flags().StringVar(&ops.justification, "justification", "", "The justification of the completion service log")
+	_ = statusCmd.MarkFlagRequired("cluster-id")
+
+	return statusCmd
+}
+
+func (o *resizeStatusOptions) validate() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	if o.interval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %s", o.interval)
+	}
+	if o.autoSL && !o.watch {
+		return errors.New("--auto-sl requires --watch")
+	}
+	if o.autoSL && o.justification == "" {
+		return errors.New("--auto-sl requires --justification, the service log can't be sent without one")
+	}
+	return nil
+}
+
+func (o *resizeStatusOptions) complete() error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	cluster, err := o.factory.Cluster(o.clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.Hypershift().Enabled() {
+		return errors.New("this command should not be used for HCP clusters")
+	}
+	o.clusterID = cluster.ID()
+
+	scheme := runtime.NewScheme()
+	if err := machinev1.Install(scheme); err != nil {
+		return err
+	}
+	if err := machinev1beta1.Install(scheme); err != nil {
+		return err
+	}
+	if o.client, err = o.factory.KubeClient(o.clusterID, client.Options{Scheme: scheme}); err != nil {
+		return err
+	}
+
+	o.wait = func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(o.interval):
+			return nil
+		}
+	}
+	o.sendServiceLog = func(sl resizeCompletion) error {
+		postCmd := sl.serviceLog()
+		return postCmd.Run()
+	}
+	return nil
+}
+
+func (o *resizeStatusOptions) run(ctx context.Context) error {
+	rollout, err := getControlPlaneRollout(ctx, o.client)
+	if err != nil {
+		return err
+	}
+	if err := printRollout(o.out, rollout); err != nil {
+		return err
+	}
+	if !o.watch {
+		return nil
+	}
+	if rollout.complete() {
+		fmt.Fprintln(o.out, "\nThe control plane machines are already rolled out, the completion service log is only offered when the watch observes the rollout complete.")
+		return nil
+	}
+
+	watchStarted := o.now()
+	for !rollout.complete() {
+		if err := o.wait(ctx); err != nil {
+			return err
+		}
+		current, err := getControlPlaneRollout(ctx, o.client)
+		if err != nil {
+			// The rollout takes a while, a failed poll is retried rather than ending the watch
+			log.Printf("Failed to get the control plane rollout, retrying in %s: %v", o.interval, err)
+			continue
+		}
+		rollout = current
+		fmt.Fprintf(o.out, "\n%s\n", o.now().UTC().Format(time.RFC3339))
+		if err := printRollout(o.out, rollout); err != nil {
+			return err
+		}
+	}
+
+	completion := resizeCompletion{
+		clusterID:     o.clusterID,
+		instanceType:  rollout.instanceType,
+		jiraID:        o.jiraID,
+		justification: o.justification,
+		started:       watchStarted,
+		finished:      o.now(),
+	}
+	if r := rollout.resize; r != nil && r.To == rollout.instanceType && r.Time.Before(watchStarted) {
+		completion.started = r.Time
+		if completion.jiraID == "" {
+			completion.jiraID = r.Jira
+		}
+	}
+	return o.offerServiceLog(completion)
+}
+
+// offerServiceLog sends the completion service log with --auto-sl, and asks whether to send it otherwise
+func (o *resizeStatusOptions) offerServiceLog(sl resizeCompletion) error {
+	fmt.Fprintf(o.out, "\nAll control plane machines were rolled out to %s, the resize started at %s and finished at %s.\n",
+		sl.instanceType, sl.started.UTC().Format(time.RFC3339), sl.finished.UTC().Format(time.RFC3339))
+
+	if !o.autoSL {
+		if !prompt.Confirm("Would you like to send the completion service log?", false) {
+			fmt.Fprintln(o.out, "Service log not sent.")
+			return nil
+		}
+		var err error
+		if sl.jiraID, err = prompt.Input("Please enter the JIRA ID that corresponds to this resize", sl.jiraID); err != nil {
+			return fmt.Errorf("failed to read the JIRA ID, send the service log manually: %w", err)
+		}
+		if sl.justification, err = prompt.Input("Please enter a justification for the resize", sl.justification); err != nil {
+			return fmt.Errorf("failed to read the justification, send the service log manually: %w", err)
+		}
+		if sl.justification == "" {
+			return errors.New("a justification is required, send the service log manually")
+		}
+	}
+
+	if err := o.sendServiceLog(sl); err != nil {
+		return fmt.Errorf("failed to send service log: %v", err)
+	}
+	fmt.Fprintln(o.out, "Service log sent successfully.")
+	return nil
+}
+
+// serviceLog returns the completion service log of the resize, whose justification states when it started and finished
+func (sl resizeCompletion) serviceLog() servicelog.PostCmdOptions {
+	justification := fmt.Sprintf("%s The resize started at %s and completed at %s.", sl.justification,
+		sl.started.UTC().Format(time.RFC3339), sl.finished.UTC().Format(time.RFC3339))
+	return controlPlaneResizedServiceLog(sl.clusterID, sl.instanceType, sl.jiraID, justification)
+}
+
+// getControlPlaneRollout returns the progress of the control plane machine set rolling out its machines
+func getControlPlaneRollout(ctx context.Context, c client.Client) (*controlPlaneRollout, error) {
+	cpms := &machinev1.ControlPlaneMachineSet{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: cpmsNamespace, Name: cpmsName}, cpms); err != nil {
+		return nil, fmt.Errorf("error retrieving control plane machine set: %v", err)
+	}
+	if cpms.Spec.Template.OpenShiftMachineV1Beta1Machine == nil || cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value == nil {
+		return nil, errors.New("control plane machine set has no providerSpec")
+	}
+	instanceType, err := providerSpecInstanceType(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the instance type of the control plane machine set: %v", err)
+	}
+
+	rollout := &controlPlaneRollout{
+		instanceType: instanceType,
+		observed:     cpms.Status.ObservedGeneration >= cpms.Generation,
+		updated:      cpms.Status.UpdatedReplicas,
+		ready:        cpms.Status.ReadyReplicas,
+	}
+	if cpms.Spec.Replicas != nil {
+		rollout.replicas = *cpms.Spec.Replicas
+	}
+	if history, err := resizeHistoryFromAnnotation(cpms); err != nil {
+		log.Printf("Warning: %v, the start of the resize is when the watch started", err)
+	} else if len(history) > 0 {
+		rollout.resize = &history[len(history)-1]
+	}
+
+	machines, err := getMasterMachines(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for _, machine := range machines {
+		m := rolloutMachine{name: machine.Name, phase: "-", node: "-"}
+		if machine.Status.Phase != nil {
+			m.phase = *machine.Status.Phase
+		}
+		if machine.Status.NodeRef != nil {
+			m.node = machine.Status.NodeRef.Name
+		}
+		if machine.Spec.ProviderSpec.Value != nil {
+			if m.instanceType, err = providerSpecInstanceType(machine.Spec.ProviderSpec.Value.Raw); err != nil {
+				return nil, fmt.Errorf("error reading the instance type of machine %s: %v", machine.Name, err)
+			}
+		}
+		rollout.machines = append(rollout.machines, m)
+	}
+	return rollout, nil
+}
+
+// providerSpecInstanceType returns the instance type of an AWS or the machine type of a GCP providerSpec
+func providerSpecInstanceType(raw []byte) (string, error) {
+	spec := struct {
+		InstanceType string `json:"instanceType"`
+		MachineType  string `json:"machineType"`
+	}{}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return "", err
+	}
+	if spec.InstanceType != "" {
+		return spec.InstanceType, nil
+	}
+	return spec.MachineType, nil
+}
+
+// complete returns whether all the control plane machines are running with the instance type of the control
+// plane machine set, and are reported updated and ready by the control plane machine set
+func (r *controlPlaneRollout) complete() bool {
+	if !r.observed || r.updated != r.replicas || r.ready != r.replicas || len(r.machines) != int(r.replicas) {
+		return false
+	}
+	for _, m := range r.machines {
+		if m.phase != machinePhaseRunning || m.instanceType != r.instanceType {
+			return false
+		}
+	}
+	return true
+}
+
+func printRollout(w io.Writer, r *controlPlaneRollout) error {
+	state := "in progress"
+	if r.complete() {
+		state = "complete"
+	}
+	fmt.Fprintf(w, "Control plane rollout to %s %s: %d/%d updated, %d/%d ready\n", r.instanceType, state, r.updated, r.replicas, r.ready, r.replicas)
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"MACHINE", "PHASE", "INSTANCE TYPE", "NODE", "UPDATED"})
+	for _, m := range r.machines {
+		updated := "no"
+		if m.instanceType == r.instanceType {
+			updated = "yes"
+		}
+		table.AddRow([]string{m.name, m.phase, valueOrDash(m.instanceType), m.node, updated})
+	}
+	return table.Flush()
+}
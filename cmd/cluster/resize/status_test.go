@@ -0,0 +1,247 @@
+package resize
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestCPMS(t *testing.T, instanceType string, updated int32, history ...resizeRecord) *machinev1.ControlPlaneMachineSet {
+	cpms := &machinev1.ControlPlaneMachineSet{
+		ObjectMeta: metav1.ObjectMeta{Name: cpmsName, Namespace: cpmsNamespace, Generation: 2},
+		Spec: machinev1.ControlPlaneMachineSetSpec{
+			Replicas: ptr.To[int32](3),
+			State:    machinev1.ControlPlaneMachineSetStateActive,
+			Template: machinev1.ControlPlaneMachineSetTemplate{
+				OpenShiftMachineV1Beta1Machine: &machinev1.OpenShiftMachineV1Beta1MachineTemplate{
+					Spec: machinev1beta1.MachineSpec{ProviderSpec: machinev1beta1.ProviderSpec{
+						Value: &runtime.RawExtension{Raw: []byte(`{"instanceType":"` + instanceType + `"}`)},
+					}},
+				},
+			},
+		},
+		Status: machinev1.ControlPlaneMachineSetStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: updated, ReadyReplicas: 3},
+	}
+	for _, record := range history {
+		require.NoError(t, recordResize(cpms, record))
+	}
+	return cpms
+}
+
+func newTestRolloutMachine(name, instanceType, phase string) *machinev1beta1.Machine {
+	machine := newTestMasterMachine(name, "master", instanceType)
+	machine.Status.Phase = ptr.To(phase)
+	return machine
+}
+
+func newTestRolloutClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, machinev1.Install(scheme))
+	require.NoError(t, machinev1beta1.Install(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&machinev1.ControlPlaneMachineSet{}).Build()
+}
+
+func TestControlPlaneRolloutComplete(t *testing.T) {
+	ctx := context.Background()
+
+	c := newTestRolloutClient(t,
+		newTestCPMS(t, "m5.4xlarge", 3),
+		newTestRolloutMachine("master-0", "m5.4xlarge", "Running"),
+		newTestRolloutMachine("master-1", "m5.4xlarge", "Running"),
+		newTestRolloutMachine("master-2", "m5.4xlarge", "Running"),
+	)
+	rollout, err := getControlPlaneRollout(ctx, c)
+	require.NoError(t, err)
+	assert.Equal(t, "m5.4xlarge", rollout.instanceType)
+	assert.Nil(t, rollout.resize)
+	assert.True(t, rollout.complete())
+
+	tests := []struct {
+		name   string
+		modify func(r *controlPlaneRollout)
+	}{
+		{name: "machine of the previous type", modify: func(r *controlPlaneRollout) { r.machines[1].instanceType = "m5.2xlarge" }},
+		{name: "machine provisioning", modify: func(r *controlPlaneRollout) { r.machines[2].phase = "Provisioned" }},
+		{name: "surge machine", modify: func(r *controlPlaneRollout) { r.machines = append(r.machines, r.machines[0]) }},
+		{name: "not updated", modify: func(r *controlPlaneRollout) { r.updated = 2 }},
+		{name: "not observed", modify: func(r *controlPlaneRollout) { r.observed = false }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := *rollout
+			r.machines = append([]rolloutMachine{}, rollout.machines...)
+			tt.modify(&r)
+			assert.False(t, r.complete())
+		})
+	}
+}
+
+func TestProviderSpecInstanceType(t *testing.T) {
+	instanceType, err := providerSpecInstanceType([]byte(`{"instanceType":"m5.4xlarge"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "m5.4xlarge", instanceType)
+
+	instanceType, err = providerSpecInstanceType([]byte(`{"machineType":"n2-standard-16"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "n2-standard-16", instanceType)
+
+	_, err = providerSpecInstanceType([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestResizeStatusValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     resizeStatusOptions
+		expected string
+	}{
+		{name: "valid", opts: resizeStatusOptions{clusterID: "1a2b3c", interval: time.Second, watch: true, autoSL: true, justification: "Increased load"}},
+		{name: "auto-sl without watch", opts: resizeStatusOptions{clusterID: "1a2b3c", interval: time.Second, autoSL: true, justification: "Increased load"}, expected: "--auto-sl requires --watch"},
+		{name: "auto-sl without justification", opts: resizeStatusOptions{clusterID: "1a2b3c", interval: time.Second, watch: true, autoSL: true}, expected: "--auto-sl requires --justification"},
+		{name: "no interval", opts: resizeStatusOptions{clusterID: "1a2b3c", watch: true}, expected: "--interval must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate()
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestResizeStatusWatch(t *testing.T) {
+	resizedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	watchStarted := resizedAt.Add(5 * time.Minute)
+	rolledOut := resizedAt.Add(45 * time.Minute)
+
+	newOptions := func(t *testing.T, autoSL bool, sent *[]resizeCompletion) (*resizeStatusOptions, *bytes.Buffer) {
+		c := newTestRolloutClient(t,
+			newTestCPMS(t, "m5.4xlarge", 1, resizeRecord{Time: resizedAt, Nodes: resizeNodesControlPlane, From: "m5.2xlarge", To: "m5.4xlarge", Jira: "OHSS-12345"}),
+			newTestRolloutMachine("master-0", "m5.4xlarge", "Running"),
+			newTestRolloutMachine("master-1", "m5.2xlarge", "Running"),
+			newTestRolloutMachine("master-2", "m5.2xlarge", "Running"),
+		)
+		now := watchStarted
+		polls := 0
+		out := &bytes.Buffer{}
+		return &resizeStatusOptions{
+			clusterID:     "1a2b3c",
+			watch:         true,
+			interval:      time.Minute,
+			autoSL:        autoSL,
+			justification: "Increased load",
+			out:           out,
+			client:        c,
+			now:           func() time.Time { return now },
+			// The rollout completes on the second poll
+			wait: func(ctx context.Context) error {
+				polls++
+				now = watchStarted.Add(time.Duration(polls) * 20 * time.Minute)
+				if polls == 2 {
+					now = rolledOut
+					for _, name := range []string{"master-1", "master-2"} {
+						require.NoError(t, c.Delete(ctx, &machinev1beta1.Machine{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cpmsNamespace}}))
+						require.NoError(t, c.Create(ctx, newTestRolloutMachine(name, "m5.4xlarge", "Running")))
+					}
+					cpms := &machinev1.ControlPlaneMachineSet{}
+					require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: cpmsNamespace, Name: cpmsName}, cpms))
+					cpms.Status.UpdatedReplicas = 3
+					require.NoError(t, c.Status().Update(ctx, cpms))
+				}
+				return nil
+			},
+			sendServiceLog: func(sl resizeCompletion) error {
+				*sent = append(*sent, sl)
+				return nil
+			},
+		}, out
+	}
+
+	t.Run("auto-sl", func(t *testing.T) {
+		var sent []resizeCompletion
+		opts, out := newOptions(t, true, &sent)
+		require.NoError(t, opts.run(context.Background()))
+
+		assert.Contains(t, out.String(), "Control plane rollout to m5.4xlarge in progress: 1/3 updated, 3/3 ready")
+		assert.Contains(t, out.String(), "Control plane rollout to m5.4xlarge complete: 3/3 updated, 3/3 ready")
+		assert.Equal(t, 2, strings.Count(out.String(), "in progress"), "the status is printed on every poll")
+		require.Len(t, sent, 1)
+		assert.Equal(t, resizeCompletion{
+			clusterID:     "1a2b3c",
+			instanceType:  "m5.4xlarge",
+			jiraID:        "OHSS-12345",
+			justification: "Increased load",
+			started:       resizedAt,
+			finished:      rolledOut,
+		}, sent[0])
+
+		postCmd := sent[0].serviceLog()
+		assert.Equal(t, resizeControlPlaneServiceLogTemplate, postCmd.Template)
+		assert.Contains(t, postCmd.TemplateParams, "JUSTIFICATION=Increased load The resize started at 2026-03-02T10:00:00Z and completed at 2026-03-02T10:45:00Z.")
+		assert.Contains(t, postCmd.TemplateParams, "JIRA_ID=OHSS-12345")
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		restore := prompt.SetIO(strings.NewReader("n\n"), &bytes.Buffer{})
+		defer restore()
+
+		var sent []resizeCompletion
+		opts, out := newOptions(t, false, &sent)
+		require.NoError(t, opts.run(context.Background()))
+		assert.Empty(t, sent)
+		assert.Contains(t, out.String(), "the resize started at 2026-03-02T10:00:00Z and finished at 2026-03-02T10:45:00Z")
+		assert.Contains(t, out.String(), "Service log not sent.")
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		restore := prompt.SetIO(strings.NewReader("y\nOHSS-67890\n\n"), &bytes.Buffer{})
+		defer restore()
+
+		var sent []resizeCompletion
+		opts, _ := newOptions(t, false, &sent)
+		require.NoError(t, opts.run(context.Background()))
+		require.Len(t, sent, 1)
+		assert.Equal(t, "OHSS-67890", sent[0].jiraID)
+		assert.Equal(t, "Increased load", sent[0].justification)
+	})
+}
+
+func TestResizeStatusAlreadyComplete(t *testing.T) {
+	c := newTestRolloutClient(t,
+		newTestCPMS(t, "m5.4xlarge", 3),
+		newTestRolloutMachine("master-0", "m5.4xlarge", "Running"),
+		newTestRolloutMachine("master-1", "m5.4xlarge", "Running"),
+		newTestRolloutMachine("master-2", "m5.4xlarge", "Running"),
+	)
+	out := &bytes.Buffer{}
+	opts := &resizeStatusOptions{
+		clusterID: "1a2b3c",
+		watch:     true,
+		out:       out,
+		client:    c,
+		now:       time.Now,
+		sendServiceLog: func(resizeCompletion) error {
+			t.Error("no service log is sent for a rollout which wasn't watched")
+			return nil
+		},
+	}
+	require.NoError(t, opts.run(context.Background()))
+	assert.Contains(t, out.String(), "already rolled out")
+}
@@ -0,0 +1,285 @@
+package resize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	sizeStatusOK         = "ok"
+	sizeStatusUndersized = "undersized"
+	sizeStatusUnknown    = "unknown"
+)
+
+// controlPlaneSize is a row of the documented control plane sizing matrix. A cluster needs the control plane vCPUs
+// of the first row fitting both its number of worker nodes and their total vCPUs.
+type controlPlaneSize struct {
+	maxWorkers   int
+	maxWorkerCPU float64
+	vcpus        float64
+}
+
+// controlPlaneSizing is the control plane sizing matrix of OSD and ROSA classic. The worker vCPU limits assume
+// workers of 16 vCPUs, so that clusters of few large workers are sized like clusters of many small ones.
+var controlPlaneSizing = []controlPlaneSize{
+	{maxWorkers: 24, maxWorkerCPU: 24 * 16, vcpus: 8},
+	{maxWorkers: 48, maxWorkerCPU: 48 * 16, vcpus: 16},
+	{maxWorkers: 99, maxWorkerCPU: 99 * 16, vcpus: 32},
+	{maxWorkers: 180, maxWorkerCPU: 180 * 16, vcpus: 48},
+	{maxWorkers: 249, maxWorkerCPU: 249 * 16, vcpus: 64},
+	{maxWorkers: math.MaxInt, maxWorkerCPU: math.Inf(1), vcpus: 96},
+}
+
+// gcpCustomInstanceType matches GCP custom machine types, e.g. custom-16-65536
+var gcpCustomInstanceType = regexp.MustCompile(`^custom-([0-9]+)-[0-9]+(-ext)?$`)
+
+type validateSizeOptions struct {
+	clusterID string
+	output    string
+
+	client client.Client
+	out    io.Writer
+}
+
+// sizeValidation is the result of the comparison of the control plane size with the documented sizing matrix
+type sizeValidation struct {
+	ControlPlaneType string  `json:"controlPlaneInstanceType"`
+	ControlPlaneVCPU float64 `json:"controlPlaneVCPU"`
+	Workers          int     `json:"workers"`
+	WorkerVCPU       float64 `json:"workerVCPU"`
+	RequiredVCPU     float64 `json:"requiredControlPlaneVCPU"`
+	Status           string  `json:"status"`
+	RecommendedType  string  `json:"recommendedInstanceType,omitempty"`
+	Note             string  `json:"note,omitempty"`
+}
+
+// NewCmdValidateSize implements "osdctl cluster validate-size"
+func NewCmdValidateSize() *cobra.Command {
+	opts := &validateSizeOptions{out: os.Stdout}
+
+	validateSizeCmd := &cobra.Command{
+		Use:   "validate-size",
+		Short: "Check that the control plane instance type fits the number and size of the worker nodes",
+		Long: `Check that the control plane instance type fits the number and size of the worker nodes
+
+  The control plane instance type is compared against the worker count and the total worker vCPUs using the
+  documented control plane sizing matrix of OSD and ROSA classic:
+
+    Workers     Control plane vCPUs   AWS           GCP
+    1-24        8                     m5.2xlarge    custom-8-32768
+    25-48       16                    m5.4xlarge    custom-16-65536
+    49-99       32                    m5.8xlarge    custom-32-131072
+    100-180     48                    m5.12xlarge
+    181-249     64                    m5.16xlarge
+    250+        96                    m5.24xlarge
+
+  When the control plane is undersized, the smallest supported instance type of the same family fitting the
+  cluster is recommended as the target of 'osdctl cluster resize control-plane'.
+
+  Requires previous login to the api server via "ocm backplane login". HCP clusters aren't supported, as their
+  control plane is managed by the service.`,
+		Example: `  # Check the control plane size of a cluster
+  osdctl cluster validate-size --cluster-id ${CLUSTER_ID}
+
+  # Check the control plane size of a cluster as JSON
+  osdctl cluster validate-size --cluster-id ${CLUSTER_ID} -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	validateSizeCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	validateSizeCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	_ = validateSizeCmd.MarkFlagRequired("cluster-id")
+
+	return validateSizeCmd
+}
+
+func (o *validateSizeOptions) complete() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.Hypershift().Enabled() {
+		return fmt.Errorf("this command is not supported for HCP clusters, the control plane is managed by the service")
+	}
+	o.clusterID = cluster.ID()
+
+	o.client, err = k8s.New(cluster.ID(), client.Options{})
+	return err
+}
+
+func (o *validateSizeOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := o.client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	validation, err := validateControlPlaneSize(nodes.Items)
+	if err != nil {
+		return err
+	}
+	return o.print(validation)
+}
+
+// validateControlPlaneSize compares the instance type of the control plane nodes with the size required by the
+// worker nodes
+func validateControlPlaneSize(nodes []corev1.Node) (*sizeValidation, error) {
+	validation := &sizeValidation{}
+	for _, node := range nodes {
+		_, isMaster := node.Labels["node-role.kubernetes.io/master"]
+		_, isControlPlane := node.Labels["node-role.kubernetes.io/control-plane"]
+		if isMaster || isControlPlane {
+			instanceType := node.Labels[instanceTypeLabel]
+			if validation.ControlPlaneType != "" && instanceType != validation.ControlPlaneType {
+				validation.Note = fmt.Sprintf("control plane nodes have different instance types, %s is used", validation.ControlPlaneType)
+				continue
+			}
+			validation.ControlPlaneType = instanceType
+			continue
+		}
+		if slices.ContainsFunc(nonWorkerRoleLabels, func(label string) bool { _, ok := node.Labels[label]; return ok }) {
+			continue
+		}
+		validation.Workers++
+		validation.WorkerVCPU += float64(node.Status.Capacity.Cpu().MilliValue()) / 1000
+	}
+	if validation.ControlPlaneType == "" {
+		return nil, fmt.Errorf("no control plane node with the %s label found", instanceTypeLabel)
+	}
+
+	validation.RequiredVCPU = requiredControlPlaneVCPUs(validation.Workers, validation.WorkerVCPU)
+	validation.ControlPlaneVCPU = controlPlaneVCPUs(validation.ControlPlaneType)
+	switch {
+	case validation.ControlPlaneVCPU == 0:
+		validation.Status = sizeStatusUnknown
+		validation.Note = "unknown number of vCPUs for the instance type"
+	case validation.ControlPlaneVCPU >= validation.RequiredVCPU:
+		validation.Status = sizeStatusOK
+	default:
+		validation.Status = sizeStatusUndersized
+		validation.RecommendedType = recommendedControlPlaneType(validation.ControlPlaneType, validation.RequiredVCPU)
+		if validation.RecommendedType == "" {
+			validation.Note = "no supported instance type of the same family fits the cluster"
+		}
+	}
+	return validation, nil
+}
+
+// requiredControlPlaneVCPUs returns the control plane vCPUs of the sizing matrix for the worker nodes
+func requiredControlPlaneVCPUs(workers int, workerVCPU float64) float64 {
+	for _, size := range controlPlaneSizing {
+		if workers <= size.maxWorkers && workerVCPU <= size.maxWorkerCPU {
+			return size.vcpus
+		}
+	}
+	return controlPlaneSizing[len(controlPlaneSizing)-1].vcpus
+}
+
+// controlPlaneVCPUs returns the number of vCPUs of a control plane instance type, including GCP custom types,
+// 0 when unknown
+func controlPlaneVCPUs(instanceType string) float64 {
+	if m := gcpCustomInstanceType.FindStringSubmatch(instanceType); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return float64(n)
+	}
+	return vcpus(instanceType)
+}
+
+// instanceTypeFamily returns the family of an instance type, "custom" for GCP custom types
+func instanceTypeFamily(instanceType string) string {
+	if gcpCustomInstanceType.MatchString(instanceType) {
+		return "custom"
+	}
+	family, _, _, _ := splitInstanceType(instanceType)
+	return family
+}
+
+// recommendedControlPlaneType returns the smallest supported control plane instance type of the same family with
+// at least the required vCPUs, or an empty string when there is none
+func recommendedControlPlaneType(instanceType string, required float64) string {
+	family := instanceTypeFamily(instanceType)
+	if family == "" {
+		return ""
+	}
+	recommended := ""
+	for _, candidate := range supportedInstanceTypes["controlplane"] {
+		if instanceTypeFamily(candidate) != family || strings.HasSuffix(candidate, "-ext") {
+			continue
+		}
+		candidateVCPUs := controlPlaneVCPUs(candidate)
+		if candidateVCPUs < required {
+			continue
+		}
+		if recommended == "" || candidateVCPUs < controlPlaneVCPUs(recommended) {
+			recommended = candidate
+		}
+	}
+	return recommended
+}
+
+func (o *validateSizeOptions) print(validation *sizeValidation) error {
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(validation)
+	}
+
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"CONTROL PLANE TYPE", "CONTROL PLANE VCPU", "WORKERS", "WORKER VCPU", "REQUIRED VCPU", "STATUS", "RECOMMENDED TYPE"})
+	table.AddRow([]string{
+		validation.ControlPlaneType,
+		strconv.FormatFloat(validation.ControlPlaneVCPU, 'f', -1, 64),
+		strconv.Itoa(validation.Workers),
+		strconv.FormatFloat(validation.WorkerVCPU, 'f', -1, 64),
+		strconv.FormatFloat(validation.RequiredVCPU, 'f', -1, 64),
+		validation.Status,
+		valueOrDash(validation.RecommendedType),
+	})
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if validation.Note != "" {
+		fmt.Fprintln(o.out, "\nNote:", validation.Note)
+	}
+	if validation.RecommendedType != "" {
+		fmt.Fprintf(o.out, "\nThe control plane is undersized, resize it with:\n  osdctl cluster resize control-plane --cluster-id %s --machine-type %s --reason \"${REASON}\"\n",
+			o.clusterID, validation.RecommendedType)
+	}
+	return nil
+}
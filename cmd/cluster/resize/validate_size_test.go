@@ -0,0 +1,111 @@
+package resize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func nodesForSizing(controlPlaneType string, workers int, workerCPU string) []client.Object {
+	var nodes []client.Object
+	for i := 0; i < 3; i++ {
+		nodes = append(nodes, newNode(fmt.Sprintf("master-%d", i), map[string]string{"node-role.kubernetes.io/master": "", instanceTypeLabel: controlPlaneType}))
+	}
+	nodes = append(nodes, newNode("infra-0", map[string]string{"node-role.kubernetes.io/infra": "", instanceTypeLabel: "r5.xlarge"}))
+	for i := 0; i < workers; i++ {
+		node := newNode(fmt.Sprintf("worker-%d", i), map[string]string{instanceTypeLabel: "m5.xlarge"})
+		node.Status.Capacity = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(workerCPU)}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func TestRequiredControlPlaneVCPUs(t *testing.T) {
+	assert.Equal(t, float64(8), requiredControlPlaneVCPUs(3, 12))
+	assert.Equal(t, float64(16), requiredControlPlaneVCPUs(25, 100))
+	assert.Equal(t, float64(32), requiredControlPlaneVCPUs(10, 1000), "a few large workers need the size of many small ones")
+	assert.Equal(t, float64(96), requiredControlPlaneVCPUs(500, 2000))
+}
+
+func TestRecommendedControlPlaneType(t *testing.T) {
+	assert.Equal(t, "m5.4xlarge", recommendedControlPlaneType("m5.2xlarge", 16))
+	assert.Equal(t, "m6i.12xlarge", recommendedControlPlaneType("m6i.2xlarge", 48))
+	assert.Equal(t, "custom-32-131072", recommendedControlPlaneType("custom-8-32768", 32))
+	assert.Equal(t, "n2-standard-16", recommendedControlPlaneType("n2-standard-8", 16))
+	assert.Empty(t, recommendedControlPlaneType("custom-8-32768", 64))
+	assert.Empty(t, recommendedControlPlaneType("c5.2xlarge", 16))
+}
+
+func TestValidateSizeRun(t *testing.T) {
+	tests := []struct {
+		name             string
+		controlPlaneType string
+		workers          int
+		workerCPU        string
+		expected         sizeValidation
+	}{
+		{
+			name:             "correctly sized",
+			controlPlaneType: "m5.2xlarge",
+			workers:          4,
+			workerCPU:        "4",
+			expected:         sizeValidation{ControlPlaneType: "m5.2xlarge", ControlPlaneVCPU: 8, Workers: 4, WorkerVCPU: 16, RequiredVCPU: 8, Status: sizeStatusOK},
+		},
+		{
+			name:             "undersized",
+			controlPlaneType: "m5.2xlarge",
+			workers:          30,
+			workerCPU:        "3500m",
+			expected:         sizeValidation{ControlPlaneType: "m5.2xlarge", ControlPlaneVCPU: 8, Workers: 30, WorkerVCPU: 105, RequiredVCPU: 16, Status: sizeStatusUndersized, RecommendedType: "m5.4xlarge"},
+		},
+		{
+			name:             "unknown instance type",
+			controlPlaneType: "x2gd.metal",
+			workers:          1,
+			workerCPU:        "4",
+			expected:         sizeValidation{ControlPlaneType: "x2gd.metal", Workers: 1, WorkerVCPU: 4, RequiredVCPU: 8, Status: sizeStatusUnknown, Note: "unknown number of vCPUs for the instance type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			opts := &validateSizeOptions{
+				clusterID: "abc",
+				output:    "json",
+				client:    fake.NewClientBuilder().WithObjects(nodesForSizing(tt.controlPlaneType, tt.workers, tt.workerCPU)...).Build(),
+				out:       out,
+			}
+			require.NoError(t, opts.run(context.Background()))
+
+			var validation sizeValidation
+			require.NoError(t, json.Unmarshal(out.Bytes(), &validation))
+			assert.Equal(t, tt.expected, validation)
+		})
+	}
+}
+
+func TestValidateSizeTable(t *testing.T) {
+	out := &bytes.Buffer{}
+	opts := &validateSizeOptions{
+		clusterID: "abc",
+		output:    "table",
+		client:    fake.NewClientBuilder().WithObjects(nodesForSizing("m5.2xlarge", 30, "4")...).Build(),
+		out:       out,
+	}
+	require.NoError(t, opts.run(context.Background()))
+	assert.Contains(t, out.String(), "undersized")
+	assert.Contains(t, out.String(), `osdctl cluster resize control-plane --cluster-id abc --machine-type m5.4xlarge --reason "${REASON}"`)
+
+	opts.client = fake.NewClientBuilder().WithObjects(newNode("worker-0", nil)).Build()
+	assert.EqualError(t, opts.run(context.Background()), "no control plane node with the node.kubernetes.io/instance-type label found")
+}
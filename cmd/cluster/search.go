@@ -0,0 +1,241 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const searchPageSize = 100
+
+var searchProducts = []string{"hcp", "osd", "rosa"}
+
+type searchOptions struct {
+	query   string
+	version string
+	region  string
+	product string
+	state   string
+	limit   int
+	output  string
+
+	tableFlags *printer.TableFlags
+	out        io.Writer
+	// search lists the clusters matching the OCM search query, replaced in tests
+	search func(query string, limit int) ([]*cmv1.Cluster, error)
+}
+
+// searchResult is a cluster matching the search
+type searchResult struct {
+	ID         string    `json:"id"`
+	ExternalID string    `json:"external_id"`
+	Name       string    `json:"name"`
+	Product    string    `json:"product"`
+	HCP        bool      `json:"hcp"`
+	Version    string    `json:"version"`
+	Cloud      string    `json:"cloud_provider"`
+	Region     string    `json:"region"`
+	State      string    `json:"state"`
+	Created    time.Time `json:"created"`
+}
+
+func newCmdSearch() *cobra.Command {
+	opts := &searchOptions{
+		out:        os.Stdout,
+		tableFlags: printer.NewTableFlags(),
+		search:     searchClusters,
+	}
+
+	searchCmd := &cobra.Command{
+		Use:   "search [OCM search query]",
+		Short: "Search clusters in OCM",
+		Long: `Search clusters in OCM using the OCM search syntax, shortcut flags or both
+
+  The shortcut flags are combined with the search query with 'and'. The search query uses the syntax of the
+  'search' parameter of the OCM clusters API, e.g. "cloud_provider.id = 'gcp' and name like 'prod-%'".`,
+		Example: `  # Search the HCP clusters in error in a region
+  osdctl cluster search --product hcp --state error --region us-east-1
+
+  # Search the ROSA clusters of a minor version, as JSON
+  osdctl cluster search --product rosa --version 4.14 -o json
+
+  # Search with the OCM search syntax
+  osdctl cluster search "name like 'prod-%' and cloud_provider.id = 'gcp'"`,
+		Args:              cobra.MaximumNArgs(1),
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.query = args[0]
+			}
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			return opts.run()
+		},
+	}
+
+	searchCmd.Flags().StringVar(&opts.version, "version", "", "Only clusters of this OpenShift version or version prefix, e.g. 4.14 or 4.14.12")
+	searchCmd.Flags().StringVar(&opts.region, "region", "", "Only clusters in this cloud region, e.g. us-east-1")
+	searchCmd.Flags().StringVar(&opts.product, "product", "", "Only clusters of this product: hcp, osd or rosa (classic)")
+	searchCmd.Flags().StringVar(&opts.state, "state", "", "Only clusters in this state, e.g. ready, error, installing, uninstalling")
+	searchCmd.Flags().IntVar(&opts.limit, "limit", searchPageSize, "Maximum number of clusters to return")
+	searchCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	opts.tableFlags.AddFlags(searchCmd)
+
+	_ = searchCmd.RegisterFlagCompletionFunc("product", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return searchProducts, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return searchCmd
+}
+
+func (o *searchOptions) validate() error {
+	if o.product != "" && !slices.Contains(searchProducts, o.product) {
+		return fmt.Errorf("invalid product %q, must be one of: %v", o.product, searchProducts)
+	}
+	if o.limit < 1 {
+		return fmt.Errorf("--limit must be at least 1")
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	if o.buildQuery() == "" {
+		return fmt.Errorf("a search query or at least one of --version, --region, --product or --state is required")
+	}
+	return nil
+}
+
+// buildQuery combines the search query and the shortcut flags into a single OCM search query
+func (o *searchOptions) buildQuery() string {
+	var filters []string
+	if query := strings.TrimSpace(o.query); query != "" {
+		filters = append(filters, query)
+	}
+	if o.version != "" {
+		// Match 4.14 as a minor version prefix rather than 4.140
+		version := strings.TrimSuffix(o.version, ".")
+		filters = append(filters, fmt.Sprintf("openshift_version = '%[1]s' or openshift_version like '%[1]s.%%'", quoteSearchValue(version)))
+	}
+	if o.region != "" {
+		filters = append(filters, fmt.Sprintf("region.id = '%s'", quoteSearchValue(o.region)))
+	}
+	switch o.product {
+	case "hcp":
+		filters = append(filters, "hypershift.enabled = 'true'")
+	case "osd":
+		filters = append(filters, "product.id = 'osd'")
+	case "rosa":
+		filters = append(filters, "product.id = 'rosa' and hypershift.enabled = 'false'")
+	}
+	if o.state != "" {
+		filters = append(filters, fmt.Sprintf("state = '%s'", quoteSearchValue(o.state)))
+	}
+
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	for i, filter := range filters {
+		filters[i] = "(" + filter + ")"
+	}
+	return strings.Join(filters, " and ")
+}
+
+// quoteSearchValue escapes the single quotes of a value of an OCM search query
+func quoteSearchValue(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+func (o *searchOptions) run() error {
+	clusters, err := o.search(o.buildQuery(), o.limit)
+	if err != nil {
+		return err
+	}
+
+	results := make([]searchResult, 0, len(clusters))
+	for _, cluster := range clusters {
+		results = append(results, searchResult{
+			ID:         cluster.ID(),
+			ExternalID: cluster.ExternalID(),
+			Name:       cluster.Name(),
+			Product:    cluster.Product().ID(),
+			HCP:        cluster.Hypershift().Enabled(),
+			Version:    cluster.OpenshiftVersion(),
+			Cloud:      cluster.CloudProvider().ID(),
+			Region:     cluster.Region().ID(),
+			State:      string(cluster.State()),
+			Created:    cluster.CreationTimestamp(),
+		})
+	}
+
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(o.out, "No clusters found")
+		return err
+	}
+
+	table := o.tableFlags.Apply(printer.NewTablePrinter(o.out, 20, 1, 3, ' '))
+	table.AddRow([]string{"ID", "NAME", "PRODUCT", "VERSION", "CLOUD", "REGION", "STATE", "CREATED"})
+	for _, result := range results {
+		product := result.Product
+		if result.HCP {
+			product += " (hcp)"
+		}
+		table.AddRow([]string{
+			result.ID,
+			result.Name,
+			product,
+			result.Version,
+			result.Cloud,
+			result.Region,
+			result.State,
+			result.Created.Format(time.RFC3339),
+		})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if len(results) == o.limit {
+		fmt.Fprintf(o.out, "\nShowing the first %d clusters, use --limit to show more\n", o.limit)
+	}
+	return nil
+}
+
+// searchClusters returns up to limit clusters matching the OCM search query, most recently created first
+func searchClusters(query string, limit int) ([]*cmv1.Cluster, error) {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	var clusters []*cmv1.Cluster
+	request := connection.ClustersMgmt().V1().Clusters().List().Search(query).Order("creation_timestamp desc").Size(min(limit, searchPageSize))
+	for page := 1; len(clusters) < limit; page++ {
+		response, err := request.Page(page).Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to search clusters: %w", err)
+		}
+		clusters = append(clusters, response.Items().Slice()...)
+		if response.Size() < min(limit, searchPageSize) {
+			break
+		}
+	}
+	if len(clusters) > limit {
+		clusters = clusters[:limit]
+	}
+	return clusters, nil
+}
@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchBuildQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     searchOptions
+		expected string
+	}{
+		{
+			name:     "query only",
+			opts:     searchOptions{query: " name like 'prod-%' "},
+			expected: "name like 'prod-%'",
+		},
+		{
+			name:     "single shortcut",
+			opts:     searchOptions{state: "error"},
+			expected: "state = 'error'",
+		},
+		{
+			name:     "query and shortcuts",
+			opts:     searchOptions{query: "cloud_provider.id = 'aws'", version: "4.14.", region: "us-east-1", product: "hcp"},
+			expected: "(cloud_provider.id = 'aws') and (openshift_version = '4.14' or openshift_version like '4.14.%') and (region.id = 'us-east-1') and (hypershift.enabled = 'true')",
+		},
+		{
+			name:     "rosa classic",
+			opts:     searchOptions{product: "rosa"},
+			expected: "product.id = 'rosa' and hypershift.enabled = 'false'",
+		},
+		{
+			name:     "quoted value",
+			opts:     searchOptions{region: "us-east-1' or '1'='1"},
+			expected: "region.id = 'us-east-1'' or ''1''=''1'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.opts.buildQuery())
+		})
+	}
+}
+
+func TestSearchValidate(t *testing.T) {
+	assert.EqualError(t, (&searchOptions{limit: 10, output: "table"}).validate(),
+		"a search query or at least one of --version, --region, --product or --state is required")
+	assert.EqualError(t, (&searchOptions{product: "aro", limit: 10, output: "table"}).validate(),
+		`invalid product "aro", must be one of: [hcp osd rosa]`)
+	assert.EqualError(t, (&searchOptions{state: "error", limit: 0, output: "table"}).validate(), "--limit must be at least 1")
+	assert.EqualError(t, (&searchOptions{state: "error", limit: 10, output: "yaml"}).validate(), `invalid output format "yaml", expected table or json`)
+	assert.NoError(t, (&searchOptions{state: "error", limit: 10, output: "json"}).validate())
+}
+
+func TestSearchRun(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cluster, err := cmv1.NewCluster().ID("abc").Name("prod-1").Product(cmv1.NewProduct().ID("rosa")).
+		Hypershift(cmv1.NewHypershift().Enabled(true)).OpenshiftVersion("4.14.12").
+		CloudProvider(cmv1.NewCloudProvider().ID("aws")).Region(cmv1.NewCloudRegion().ID("us-east-1")).
+		State(cmv1.ClusterStateError).CreationTimestamp(created).Build()
+	require.NoError(t, err)
+
+	var query string
+	out := &bytes.Buffer{}
+	opts := &searchOptions{
+		state:      "error",
+		limit:      1,
+		output:     "table",
+		tableFlags: printer.NewTableFlags(),
+		out:        out,
+		search: func(q string, limit int) ([]*cmv1.Cluster, error) {
+			query = q
+			return []*cmv1.Cluster{cluster}, nil
+		},
+	}
+	require.NoError(t, opts.run())
+	assert.Equal(t, "state = 'error'", query)
+	assert.Regexp(t, `abc\s+prod-1\s+rosa \(hcp\)\s+4.14.12\s+aws\s+us-east-1\s+error\s+2024-05-01T12:00:00Z`, out.String())
+	assert.Contains(t, out.String(), "Showing the first 1 clusters")
+
+	out.Reset()
+	opts.output = "json"
+	require.NoError(t, opts.run())
+	assert.Contains(t, out.String(), `"hcp": true`)
+	assert.Contains(t, out.String(), `"state": "error"`)
+}
@@ -0,0 +1,353 @@
+package ssh
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExpectedKeysConfigKey is the list of the SSH public keys expected on the nodes of the clusters, in the
+// authorized_keys format
+const ExpectedKeysConfigKey = "ssh_expected_keys"
+
+var machineConfigListGVK = schema.GroupVersionKind{Group: "machineconfiguration.openshift.io", Version: "v1", Kind: "MachineConfigList"}
+
+type listKeysEC2Client interface {
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeKeyPairs(ctx context.Context, params *ec2.DescribeKeyPairsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeKeyPairsOutput, error)
+}
+
+// authorizedKey is an SSH public key authorized on the nodes of the cluster
+type authorizedKey struct {
+	source      string
+	keyType     string
+	comment     string
+	fingerprint string
+	// expected is "yes", "UNEXPECTED", or "-" when there is nothing to compare the key with
+	expected string
+}
+
+type listKeysOpts struct {
+	clusterID    string
+	expectedFile string
+
+	out     io.Writer
+	kubeCli client.Client
+	// ec2Client is nil when the cluster doesn't run on AWS
+	ec2Client listKeysEC2Client
+	infraID   string
+	// expected maps the fingerprints of the expected keys to their comment
+	expected map[string]string
+}
+
+func NewCmdListKeys() *cobra.Command {
+	opts := &listKeysOpts{out: os.Stdout}
+	cmd := &cobra.Command{
+		Use:   "list-keys [--cluster-id $CLUSTER_ID]",
+		Short: "List the SSH public keys authorized on the nodes of a cluster",
+		Long: `List the SSH public keys authorized on the nodes of a cluster, and flag the ones which aren't expected.
+
+  The keys are read from the Ignition config of the MachineConfigs of the cluster and, on AWS, from the EC2 key
+  pairs the instances of the cluster were launched with. Each key is compared with the expected SRE keys, given
+  with --expected-keys as an authorized_keys file or with the ssh_expected_keys list of the config file, and
+  marked UNEXPECTED when it isn't one of them.`,
+		Example: `  # List the SSH keys of the nodes of the current cluster
+  osdctl cluster ssh list-keys
+
+  # Audit the SSH keys of a cluster against a set of expected keys
+  osdctl cluster ssh list-keys --cluster-id ${CLUSTER_ID} --expected-keys ~/.ssh/sre_authorized_keys`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster identifier (internal ID, UUID, name, etc). If not specified, the current cluster will be used.")
+	cmd.Flags().StringVar(&opts.expectedFile, "expected-keys", "", "authorized_keys file of the expected SRE keys. Defaults to the ssh_expected_keys list of the config file")
+
+	return cmd
+}
+
+func (o *listKeysOpts) complete() error {
+	var err error
+	if o.expected, err = loadExpectedKeys(o.expectedFile); err != nil {
+		return err
+	}
+
+	if o.clusterID == "" {
+		if o.clusterID, err = k8s.GetCurrentCluster(); err != nil {
+			return fmt.Errorf("failed to retrieve ID for current cluster")
+		}
+	}
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to establish connection to OCM: %w", err)
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve cluster from OCM: %w", err)
+	}
+	o.clusterID = cluster.ID()
+
+	if o.kubeCli, err = k8s.New(cluster.ID(), client.Options{}); err != nil {
+		return fmt.Errorf("failed to create the client of cluster %s: %w", cluster.ID(), err)
+	}
+
+	if cluster.CloudProvider().ID() == "aws" {
+		awsConfig, err := osdCloud.CreateAWSV2Config(ocmClient, cluster)
+		if err != nil {
+			return fmt.Errorf("failed to get the AWS credentials of cluster %s from backplane: %w", cluster.ID(), err)
+		}
+		o.ec2Client = ec2.NewFromConfig(awsConfig)
+		o.infraID = cluster.InfraID()
+	}
+	return nil
+}
+
+func (o *listKeysOpts) run(ctx context.Context) error {
+	keys, err := o.machineConfigKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	if o.ec2Client != nil {
+		cloudKeys, err := o.keyPairKeys(ctx)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, cloudKeys...)
+	} else {
+		_, _ = fmt.Fprintln(o.out, "The cluster doesn't run on AWS, only the keys of the MachineConfigs are listed.")
+	}
+
+	if len(keys) == 0 {
+		_, _ = fmt.Fprintf(o.out, "No SSH key is authorized on the nodes of cluster %s.\n", o.clusterID)
+		return nil
+	}
+
+	var unexpected int
+	for _, key := range keys {
+		key.expected = o.checkExpected(key.fingerprint)
+		if key.expected == "UNEXPECTED" {
+			unexpected++
+		}
+	}
+	printAuthorizedKeys(o.out, keys)
+
+	switch {
+	case len(o.expected) == 0:
+		_, _ = fmt.Fprintf(o.out, "\nNo expected keys configured, use --expected-keys or the %s list of the config file to flag unexpected keys.\n", ExpectedKeysConfigKey)
+	case unexpected > 0:
+		return fmt.Errorf("%d of the %d SSH key(s) authorized on the nodes of cluster %s are not expected", unexpected, len(keys), o.clusterID)
+	}
+	return nil
+}
+
+func (o *listKeysOpts) checkExpected(fingerprint string) string {
+	switch {
+	case len(o.expected) == 0 || fingerprint == "":
+		return "-"
+	case o.expected[fingerprint] != "":
+		return "yes"
+	}
+	return "UNEXPECTED"
+}
+
+// machineConfigKeys returns the keys authorized by the Ignition config of the MachineConfigs
+func (o *listKeysOpts) machineConfigKeys(ctx context.Context) ([]*authorizedKey, error) {
+	machineConfigs := &unstructured.UnstructuredList{}
+	machineConfigs.SetGroupVersionKind(machineConfigListGVK)
+	if err := o.kubeCli.List(ctx, machineConfigs); err != nil {
+		return nil, fmt.Errorf("failed to list the MachineConfigs: %w", err)
+	}
+
+	var keys []*authorizedKey
+	for _, mc := range machineConfigs.Items {
+		config, found, err := unstructured.NestedFieldNoCopy(mc.Object, "spec", "config")
+		if err != nil || !found {
+			continue
+		}
+		users, err := ignitionUsers(config)
+		if err != nil {
+			_, _ = fmt.Fprintf(o.out, "Failed to read the Ignition config of MachineConfig %s: %v\n", mc.GetName(), err)
+			continue
+		}
+		for _, user := range users {
+			for _, line := range user.SSHAuthorizedKeys {
+				keys = append(keys, parseAuthorizedKey(fmt.Sprintf("MachineConfig/%s (%s)", mc.GetName(), user.Name), line))
+			}
+		}
+	}
+	return keys, nil
+}
+
+type ignitionUser struct {
+	Name              string   `json:"name"`
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys"`
+}
+
+// ignitionUsers returns the users of the passwd section of an Ignition config
+func ignitionUsers(config interface{}) ([]ignitionUser, error) {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	ignition := struct {
+		Passwd struct {
+			Users []ignitionUser `json:"users"`
+		} `json:"passwd"`
+	}{}
+	if err := json.Unmarshal(raw, &ignition); err != nil {
+		return nil, err
+	}
+	return ignition.Passwd.Users, nil
+}
+
+// keyPairKeys returns the EC2 key pairs the instances of the cluster were launched with
+func (o *listKeysOpts) keyPairKeys(ctx context.Context) ([]*authorizedKey, error) {
+	instancesByKey := map[string][]string{}
+	paginator := ec2.NewDescribeInstancesPaginator(o.ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2Types.Filter{
+			{Name: awsSdk.String("tag-key"), Values: []string{"kubernetes.io/cluster/" + o.infraID}},
+			{Name: awsSdk.String("instance-state-name"), Values: []string{"pending", "running", "stopping", "stopped"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the instances of the cluster: %w", err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if name := awsSdk.ToString(instance.KeyName); name != "" {
+					instancesByKey[name] = append(instancesByKey[name], awsSdk.ToString(instance.InstanceId))
+				}
+			}
+		}
+	}
+	if len(instancesByKey) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(instancesByKey))
+	for name := range instancesByKey {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Key names would fail the whole call with InvalidKeyPair.NotFound when a key pair was deleted, the filter skips it
+	output, err := o.ec2Client.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{
+		Filters:          []ec2Types.Filter{{Name: awsSdk.String("key-name"), Values: names}},
+		IncludePublicKey: awsSdk.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the key pairs of the instances: %w", err)
+	}
+	publicKeys := map[string]string{}
+	for _, pair := range output.KeyPairs {
+		publicKeys[awsSdk.ToString(pair.KeyName)] = awsSdk.ToString(pair.PublicKey)
+	}
+
+	keys := make([]*authorizedKey, 0, len(names))
+	for _, name := range names {
+		source := fmt.Sprintf("EC2 key pair %s (%d instance(s))", name, len(instancesByKey[name]))
+		if publicKeys[name] == "" {
+			keys = append(keys, &authorizedKey{source: source, comment: "key pair deleted, public key unknown"})
+			continue
+		}
+		keys = append(keys, parseAuthorizedKey(source, publicKeys[name]))
+	}
+	return keys, nil
+}
+
+// parseAuthorizedKey parses a line in the authorized_keys format, keeping the line as comment when it isn't a key
+func parseAuthorizedKey(source string, line string) *authorizedKey {
+	publicKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil {
+		return &authorizedKey{source: source, comment: fmt.Sprintf("invalid key: %v", err)}
+	}
+	return &authorizedKey{source: source, keyType: publicKey.Type(), comment: comment, fingerprint: ssh.FingerprintSHA256(publicKey)}
+}
+
+// loadExpectedKeys returns the fingerprints of the expected keys, from the authorized_keys file or the config file
+func loadExpectedKeys(path string) (map[string]string, error) {
+	var lines []string
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the expected keys: %w", err)
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read the expected keys: %w", err)
+		}
+	} else {
+		// Without a config file there are no expected keys
+		lines, _ = osdctlConfig.GetConfigStringSlice(ExpectedKeysConfigKey)
+	}
+
+	expected := map[string]string{}
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key := parseAuthorizedKey("", line)
+		if key.fingerprint == "" {
+			return nil, fmt.Errorf("expected key %d is not a valid SSH public key: %s", i+1, key.comment)
+		}
+		expected[key.fingerprint] = key.comment
+		if key.comment == "" {
+			expected[key.fingerprint] = key.fingerprint
+		}
+	}
+	return expected, nil
+}
+
+func printAuthorizedKeys(w io.Writer, keys []*authorizedKey) {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"SOURCE", "TYPE", "COMMENT", "FINGERPRINT", "EXPECTED"})
+	for _, key := range keys {
+		p.AddRow([]string{key.source, valueOrDash(key.keyType), valueOrDash(key.comment), valueOrDash(key.fingerprint), key.expected})
+	}
+	if err := p.Flush(); err != nil {
+		_, _ = fmt.Fprintf(w, "failed to print the SSH keys: %v\n", err)
+	}
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,149 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeListKeysEC2 struct {
+	instances []ec2Types.Instance
+	keyPairs  []ec2Types.KeyPairInfo
+}
+
+func (f *fakeListKeysEC2) DescribeInstances(_ context.Context, _ *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	return &ec2.DescribeInstancesOutput{Reservations: []ec2Types.Reservation{{Instances: f.instances}}}, nil
+}
+
+// DescribeKeyPairs fails like EC2 when a key name doesn't exist, and only returns the key pairs matching the filters
+func (f *fakeListKeysEC2) DescribeKeyPairs(_ context.Context, input *ec2.DescribeKeyPairsInput, _ ...func(*ec2.Options)) (*ec2.DescribeKeyPairsOutput, error) {
+	names := map[string]bool{}
+	for _, pair := range f.keyPairs {
+		names[awsSdk.ToString(pair.KeyName)] = true
+	}
+	for _, name := range input.KeyNames {
+		if !names[name] {
+			return nil, &smithy.GenericAPIError{Code: "InvalidKeyPair.NotFound", Message: fmt.Sprintf("The key pair '%s' does not exist", name)}
+		}
+	}
+
+	output := &ec2.DescribeKeyPairsOutput{}
+	for _, pair := range f.keyPairs {
+		matches := true
+		for _, filter := range input.Filters {
+			if awsSdk.ToString(filter.Name) == "key-name" && !slices.Contains(filter.Values, awsSdk.ToString(pair.KeyName)) {
+				matches = false
+			}
+		}
+		if matches {
+			output.KeyPairs = append(output.KeyPairs, pair)
+		}
+	}
+	return output, nil
+}
+
+// newAuthorizedKey returns a new public key in the authorized_keys format and its fingerprint
+func newAuthorizedKey(t *testing.T, comment string) (string, string) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshKey, err := ssh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshKey))) + " " + comment, ssh.FingerprintSHA256(sshKey)
+}
+
+func machineConfig(name string, keys ...string) *unstructured.Unstructured {
+	sshKeys := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		sshKeys = append(sshKeys, key)
+	}
+	mc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"config": map[string]interface{}{
+				"ignition": map[string]interface{}{"version": "3.2.0"},
+				"passwd": map[string]interface{}{
+					"users": []interface{}{map[string]interface{}{"name": "core", "sshAuthorizedKeys": sshKeys}},
+				},
+			},
+		},
+	}}
+	mc.SetAPIVersion("machineconfiguration.openshift.io/v1")
+	mc.SetKind("MachineConfig")
+	mc.SetName(name)
+	return mc
+}
+
+func TestListKeysRun(t *testing.T) {
+	sreKey, sreFingerprint := newAuthorizedKey(t, "sre@example.com")
+	strayKey, strayFingerprint := newAuthorizedKey(t, "someone@laptop")
+
+	expectedFile := filepath.Join(t.TempDir(), "authorized_keys")
+	require.NoError(t, os.WriteFile(expectedFile, []byte("# SRE keys\n"+sreKey+"\n"), 0600))
+	expected, err := loadExpectedKeys(expectedFile)
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	o := &listKeysOpts{
+		clusterID: "cluster-id",
+		out:       out,
+		kubeCli:   fake.NewClientBuilder().WithObjects(machineConfig("99-master-ssh", sreKey), machineConfig("99-worker-ssh", sreKey, strayKey)).Build(),
+		ec2Client: &fakeListKeysEC2{
+			instances: []ec2Types.Instance{
+				{InstanceId: awsSdk.String("i-1"), KeyName: awsSdk.String("cluster-key")},
+				{InstanceId: awsSdk.String("i-2"), KeyName: awsSdk.String("deleted-key")},
+			},
+			keyPairs: []ec2Types.KeyPairInfo{{KeyName: awsSdk.String("cluster-key"), PublicKey: awsSdk.String(sreKey)}},
+		},
+		expected: expected,
+	}
+
+	err = o.run(context.Background())
+	require.EqualError(t, err, "1 of the 5 SSH key(s) authorized on the nodes of cluster cluster-id are not expected")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 6)
+	assert.Contains(t, lines[1], "MachineConfig/99-master-ssh (core)")
+	assert.Contains(t, lines[1], sreFingerprint)
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(lines[1]), "yes"))
+	assert.Contains(t, lines[3], strayFingerprint)
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(lines[3]), "UNEXPECTED"))
+	assert.Contains(t, lines[4], "EC2 key pair cluster-key (1 instance(s))")
+	assert.Contains(t, lines[5], "key pair deleted, public key unknown")
+}
+
+func TestListKeysRunWithoutExpectedKeys(t *testing.T) {
+	key, _ := newAuthorizedKey(t, "sre@example.com")
+	out := &bytes.Buffer{}
+	o := &listKeysOpts{
+		clusterID: "cluster-id",
+		out:       out,
+		kubeCli:   fake.NewClientBuilder().WithObjects(machineConfig("99-worker-ssh", key)).Build(),
+	}
+
+	require.NoError(t, o.run(context.Background()))
+	assert.Contains(t, out.String(), "The cluster doesn't run on AWS")
+	assert.Contains(t, out.String(), "No expected keys configured")
+}
+
+func TestLoadExpectedKeysInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authorized_keys")
+	require.NoError(t, os.WriteFile(path, []byte("not-a-key\n"), 0600))
+	_, err := loadExpectedKeys(path)
+	assert.ErrorContains(t, err, "expected key 1 is not a valid SSH public key")
+}
@@ -0,0 +1,149 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/openshift/osdctl/pkg/prompt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// nodeRoleLabelPrefix prefixes the role labels of the nodes, e.g. node-role.kubernetes.io/master
+const nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+
+// nodeChoice is a node of the cluster offered by the interactive picker
+type nodeChoice struct {
+	Name   string
+	Roles  string
+	Status string
+	Age    string
+	// Target is what the session is opened to: the EC2 instance of the node, or its name when the provider ID
+	// doesn't hold it
+	Target string
+}
+
+// pickNode lists the nodes of the cluster and asks the user to pick the one to open the session to
+func (o *sessionOpts) pickNode(ctx context.Context) error {
+	if !prompt.IsInteractive() {
+		return errors.New("--interactive-picker requires an interactive terminal, use --node instead")
+	}
+	nodes, err := o.listNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the nodes of the cluster: %w", err)
+	}
+	if len(nodes) == 0 {
+		return errors.New("the cluster has no nodes")
+	}
+
+	choices := nodeChoices(nodes, time.Now())
+	index, err := o.selectNode(choices)
+	if err != nil {
+		return err
+	}
+	o.node = choices[index].Target
+	return nil
+}
+
+// nodeChoices describes the nodes with their role, readiness and age, sorted by role and name
+func nodeChoices(nodes []corev1.Node, now time.Time) []nodeChoice {
+	choices := make([]nodeChoice, 0, len(nodes))
+	for _, node := range nodes {
+		choices = append(choices, nodeChoice{
+			Name:   node.Name,
+			Roles:  nodeRoles(node),
+			Status: nodeStatus(node),
+			Age:    duration.HumanDuration(now.Sub(node.CreationTimestamp.Time)),
+			Target: nodeTarget(node),
+		})
+	}
+	sort.SliceStable(choices, func(i, j int) bool {
+		if choices[i].Roles != choices[j].Roles {
+			return choices[i].Roles < choices[j].Roles
+		}
+		return choices[i].Name < choices[j].Name
+	})
+	return choices
+}
+
+func nodeRoles(node corev1.Node) string {
+	var roles []string
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, nodeRoleLabelPrefix); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return "<none>"
+	}
+	sort.Strings(roles)
+	return strings.Join(roles, ",")
+}
+
+func nodeStatus(node corev1.Node) string {
+	status := "NotReady"
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			status = "Ready"
+		}
+	}
+	if node.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
+	}
+	return status
+}
+
+// nodeTarget returns the EC2 instance ID of the provider ID of the node (aws:///us-east-1a/i-0123456789abcdef0),
+// or the node name
+func nodeTarget(node corev1.Node) string {
+	if providerID, ok := strings.CutPrefix(node.Spec.ProviderID, "aws://"); ok {
+		if instanceID := path.Base(providerID); strings.HasPrefix(instanceID, "i-") {
+			return instanceID
+		}
+	}
+	return node.Name
+}
+
+// fuzzyMatch reports whether the characters of the input appear in order, ignoring case, in the text
+func fuzzyMatch(input, text string) bool {
+	text = strings.ToLower(text)
+	for _, r := range strings.ToLower(strings.ReplaceAll(input, " ", "")) {
+		index := strings.IndexRune(text, r)
+		if index < 0 {
+			return false
+		}
+		text = text[index+len(string(r)):]
+	}
+	return true
+}
+
+// promptNode asks the user to pick a node with a fuzzy searchable list
+func promptNode(choices []nodeChoice) (int, error) {
+	picker := promptui.Select{
+		Label: "Select the node to open a session to (type to search)",
+		Items: choices,
+		Size:  15,
+		Templates: &promptui.SelectTemplates{
+			Label:    "{{ . }}",
+			Active:   "> {{ .Name | cyan }}  {{ .Roles }}  {{ .Status }}  {{ .Age }}",
+			Inactive: "  {{ .Name }}  {{ .Roles }}  {{ .Status }}  {{ .Age }}",
+			Selected: "Node {{ .Name | cyan }}",
+		},
+		Searcher: func(input string, index int) bool {
+			choice := choices[index]
+			return fuzzyMatch(input, choice.Name+" "+choice.Roles+" "+choice.Status)
+		},
+		StartInSearchMode: true,
+	}
+	index, _, err := picker.Run()
+	if err != nil {
+		return 0, fmt.Errorf("no node selected: %w", err)
+	}
+	return index, nil
+}
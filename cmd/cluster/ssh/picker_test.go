@@ -0,0 +1,93 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ssmTypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func pickerNode(name, role, providerID string, ready bool, created time.Time) corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            map[string]string{nodeRoleLabelPrefix + role: ""},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec:   corev1.NodeSpec{ProviderID: providerID},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}},
+	}
+}
+
+func TestNodeChoices(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	cordoned := pickerNode("ip-10-0-2-1.ec2.internal", "worker", "aws:///us-east-1b/i-0b", true, now.Add(-3*time.Hour))
+	cordoned.Spec.Unschedulable = true
+
+	choices := nodeChoices([]corev1.Node{
+		cordoned,
+		pickerNode("ip-10-0-1-1.ec2.internal", "master", "aws:///us-east-1a/i-0a", true, now.Add(-48*time.Hour)),
+		pickerNode("worker-gcp-1", "worker", "gce://project/us-east1-b/worker-gcp-1", false, now.Add(-90*time.Second)),
+	}, now)
+
+	assert.Equal(t, []nodeChoice{
+		{Name: "ip-10-0-1-1.ec2.internal", Roles: "master", Status: "Ready", Age: "2d", Target: "i-0a"},
+		{Name: "ip-10-0-2-1.ec2.internal", Roles: "worker", Status: "Ready,SchedulingDisabled", Age: "3h", Target: "i-0b"},
+		{Name: "worker-gcp-1", Roles: "worker", Status: "NotReady", Age: "90s", Target: "worker-gcp-1"},
+	}, choices)
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	assert.True(t, fuzzyMatch("", "ip-10-0-1-1.ec2.internal master Ready"))
+	assert.True(t, fuzzyMatch("ip11mas", "ip-10-0-1-1.ec2.internal master Ready"))
+	assert.True(t, fuzzyMatch("WORKER notready", "worker-gcp-1 worker NotReady"))
+	assert.False(t, fuzzyMatch("infra", "ip-10-0-1-1.ec2.internal master Ready"))
+}
+
+func TestSessionRunInteractivePicker(t *testing.T) {
+	restore := prompt.SetIO(strings.NewReader(""), &bytes.Buffer{})
+	defer restore()
+
+	var offered []nodeChoice
+	var started string
+	o := &sessionOpts{
+		interactivePicker: true,
+		out:               &bytes.Buffer{},
+		ec2Client:         &fakeSessionEC2{instances: []ec2Types.Instance{sessionInstance("i-0b", ec2Types.InstanceStateNameRunning)}},
+		ssmClient:         &fakeSessionSSM{info: []ssmTypes.InstanceInformation{{InstanceId: awsSdk.String("i-0b"), PingStatus: ssmTypes.PingStatusOnline}}},
+		listNodes: func(context.Context) ([]corev1.Node, error) {
+			return []corev1.Node{
+				pickerNode("ip-10-0-2-1.ec2.internal", "worker", "aws:///us-east-1b/i-0b", true, time.Now()),
+				pickerNode("ip-10-0-1-1.ec2.internal", "master", "aws:///us-east-1a/i-0a", true, time.Now()),
+			}, nil
+		},
+		selectNode: func(choices []nodeChoice) (int, error) {
+			offered = choices
+			return 1, nil
+		},
+		startSession: func(instanceID string) error {
+			started = instanceID
+			return nil
+		},
+	}
+
+	require.NoError(t, o.run(context.Background()))
+	require.Len(t, offered, 2)
+	assert.Equal(t, "ip-10-0-1-1.ec2.internal", offered[0].Name)
+	assert.Equal(t, "i-0b", o.node)
+	assert.Equal(t, "i-0b", started)
+}
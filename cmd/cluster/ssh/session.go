@@ -0,0 +1,253 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmTypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// sessionManagerPlugin is the binary the aws cli runs to open SSM sessions
+const sessionManagerPlugin = "session-manager-plugin"
+
+type sessionEC2Client interface {
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+}
+
+type sessionSSMClient interface {
+	DescribeInstanceInformation(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error)
+}
+
+type sessionOpts struct {
+	clusterID         string
+	node              string
+	checkOnly         bool
+	interactivePicker bool
+
+	out       io.Writer
+	region    string
+	awsConfig awsSdk.Config
+	ec2Client sessionEC2Client
+	ssmClient sessionSSMClient
+	// startSession opens the interactive session, replaced in tests
+	startSession func(instanceID string) error
+	// listNodes and selectNode list the nodes of the cluster and ask the user to pick one, replaced in tests
+	listNodes  func(ctx context.Context) ([]corev1.Node, error)
+	selectNode func(choices []nodeChoice) (int, error)
+}
+
+func NewCmdSession() *cobra.Command {
+	opts := &sessionOpts{out: os.Stdout, selectNode: promptNode}
+	cmd := &cobra.Command{
+		Use:   "session (--node $NODE | --interactive-picker) [--cluster-id $CLUSTER_ID]",
+		Short: "Open a shell on a cluster node through AWS SSM Session Manager",
+		Long: `Open a shell on a cluster node through AWS SSM Session Manager, for when direct SSH to the nodes is blocked.
+
+  The node is resolved to its EC2 instance in the cluster's account, using the AWS credentials backplane provides
+  for the cluster, and the SSM agent of the instance is checked to be online before the session is opened with
+  "aws ssm start-session". This requires the aws cli and the session-manager-plugin to be installed locally.
+
+  The SSM agent isn't part of RHCOS, so sessions are only possible on nodes where it has been installed and whose
+  instance profile allows it to register with SSM. Use --check-only to only report whether a node is reachable.
+
+  Without --node, --interactive-picker lists the nodes of the cluster with their role, readiness and age in a
+  searchable list, and opens the session to the selected one. Listing the nodes requires a backplane login to
+  the cluster.`,
+		Example: `  # Open a session on a node of a cluster
+  osdctl cluster ssh session --cluster-id ${CLUSTER_ID} --node ip-10-0-1-23.ec2.internal
+
+  # Pick the node of the current cluster to open a session to from a searchable list
+  osdctl cluster ssh session --interactive-picker
+
+  # Check whether the SSM agent of an instance of the current cluster is reachable
+  osdctl cluster ssh session --node i-0123456789abcdef0 --check-only`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.complete(cmd.Context()); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster identifier (internal ID, UUID, name, etc) of the node. If not specified, the current cluster will be used.")
+	cmd.Flags().StringVar(&opts.node, "node", "", "Name of the node (its private DNS name) or ID of its EC2 instance")
+	cmd.Flags().BoolVar(&opts.checkOnly, "check-only", false, "Only check that the SSM agent of the node is reachable, without opening a session")
+	cmd.Flags().BoolVar(&opts.interactivePicker, "interactive-picker", false, "Pick the node from a searchable list of the nodes of the cluster instead of passing --node")
+
+	cmd.MarkFlagsMutuallyExclusive("node", "interactive-picker")
+	cmd.MarkFlagsOneRequired("node", "interactive-picker")
+
+	return cmd
+}
+
+func (o *sessionOpts) complete(ctx context.Context) error {
+	var err error
+	if o.clusterID == "" {
+		if o.clusterID, err = k8s.GetCurrentCluster(); err != nil {
+			return fmt.Errorf("failed to retrieve ID for current cluster")
+		}
+	}
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("failed to establish connection to OCM: %w", err)
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve cluster from OCM: %w", err)
+	}
+	if cluster.CloudProvider().ID() != "aws" {
+		return fmt.Errorf("SSM sessions are only supported on AWS clusters, cluster %s runs on %s", cluster.ID(), cluster.CloudProvider().ID())
+	}
+
+	o.awsConfig, err = osdCloud.CreateAWSV2Config(ocmClient, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get the AWS credentials of cluster %s from backplane: %w", cluster.ID(), err)
+	}
+	o.region = cluster.Region().ID()
+	o.ec2Client = ec2.NewFromConfig(o.awsConfig)
+	o.ssmClient = ssm.NewFromConfig(o.awsConfig)
+	o.startSession = func(instanceID string) error {
+		return o.startCLISession(ctx, instanceID)
+	}
+	o.listNodes = func(ctx context.Context) ([]corev1.Node, error) {
+		_, _, clientset, err := common.GetKubeConfigAndClient(cluster.ID())
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return nodes.Items, nil
+	}
+	return nil
+}
+
+func (o *sessionOpts) run(ctx context.Context) error {
+	if o.interactivePicker {
+		if err := o.pickNode(ctx); err != nil {
+			return err
+		}
+	}
+
+	instance, err := o.findInstance(ctx)
+	if err != nil {
+		return err
+	}
+	instanceID := awsSdk.ToString(instance.InstanceId)
+	_, _ = fmt.Fprintf(o.out, "Node %s is instance %s (%s)\n", o.node, instanceID, awsSdk.ToString(instance.PrivateDnsName))
+
+	if err := o.checkAgent(ctx, instanceID); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(o.out, "The SSM agent of instance %s is online\n", instanceID)
+
+	if o.checkOnly {
+		return nil
+	}
+	return o.startSession(instanceID)
+}
+
+// findInstance resolves the node to its running EC2 instance, by instance ID or private DNS name
+func (o *sessionOpts) findInstance(ctx context.Context) (*ec2Types.Instance, error) {
+	input := &ec2.DescribeInstancesInput{}
+	if strings.HasPrefix(o.node, "i-") {
+		input.InstanceIds = []string{o.node}
+	} else {
+		input.Filters = []ec2Types.Filter{{Name: awsSdk.String("private-dns-name"), Values: []string{o.node}}}
+	}
+
+	output, err := o.ec2Client.DescribeInstances(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the instance of node %s: %w", o.node, err)
+	}
+	var instances []ec2Types.Instance
+	for _, reservation := range output.Reservations {
+		instances = append(instances, reservation.Instances...)
+	}
+	switch len(instances) {
+	case 0:
+		return nil, fmt.Errorf("no instance found for node %s in the account of the cluster", o.node)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%d instances found for node %s, use the ID of the instance instead", len(instances), o.node)
+	}
+
+	instance := instances[0]
+	if instance.State != nil && instance.State.Name != ec2Types.InstanceStateNameRunning {
+		return nil, fmt.Errorf("instance %s of node %s is %s", awsSdk.ToString(instance.InstanceId), o.node, instance.State.Name)
+	}
+	return &instance, nil
+}
+
+// checkAgent verifies that the SSM agent of the instance is registered and online
+func (o *sessionOpts) checkAgent(ctx context.Context, instanceID string) error {
+	output, err := o.ssmClient.DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{
+		Filters: []ssmTypes.InstanceInformationStringFilter{{Key: awsSdk.String("InstanceIds"), Values: []string{instanceID}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get the SSM agent status of instance %s: %w", instanceID, err)
+	}
+	if len(output.InstanceInformationList) == 0 {
+		return fmt.Errorf("the SSM agent of instance %s isn't registered with SSM: the agent may not be installed, "+
+			"its instance profile may lack the AmazonSSMManagedInstanceCore policy, or it may not reach the SSM endpoints", instanceID)
+	}
+
+	info := output.InstanceInformationList[0]
+	if info.PingStatus != ssmTypes.PingStatusOnline {
+		lastPing := "never"
+		if info.LastPingDateTime != nil {
+			lastPing = info.LastPingDateTime.UTC().Format("2006-01-02 15:04:05")
+		}
+		return fmt.Errorf("the SSM agent of instance %s is %s, last seen %s", instanceID, info.PingStatus, lastPing)
+	}
+	return nil
+}
+
+// startCLISession opens the session with the aws cli, passing it the credentials of the cluster's account
+func (o *sessionOpts) startCLISession(ctx context.Context, instanceID string) error {
+	for _, binary := range []string{"aws", sessionManagerPlugin} {
+		if _, err := exec.LookPath(binary); err != nil {
+			return fmt.Errorf("%s is required to open SSM sessions: %w", binary, err)
+		}
+	}
+	if o.awsConfig.Credentials == nil {
+		return errors.New("no AWS credentials to open the session with")
+	}
+	credentials, err := o.awsConfig.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the AWS credentials of the cluster: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "aws", "ssm", "start-session", "--target", instanceID, "--region", o.region) //#nosec G204 -- the instance ID comes from the EC2 API
+	cmd.Env = append(os.Environ(),
+		"AWS_ACCESS_KEY_ID="+credentials.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY="+credentials.SecretAccessKey,
+		"AWS_SESSION_TOKEN="+credentials.SessionToken,
+	)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,126 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmTypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSessionEC2 struct {
+	instances []ec2Types.Instance
+	input     *ec2.DescribeInstancesInput
+}
+
+func (f *fakeSessionEC2) DescribeInstances(_ context.Context, input *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.input = input
+	return &ec2.DescribeInstancesOutput{Reservations: []ec2Types.Reservation{{Instances: f.instances}}}, nil
+}
+
+type fakeSessionSSM struct {
+	info []ssmTypes.InstanceInformation
+}
+
+func (f *fakeSessionSSM) DescribeInstanceInformation(_ context.Context, _ *ssm.DescribeInstanceInformationInput, _ ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+	return &ssm.DescribeInstanceInformationOutput{InstanceInformationList: f.info}, nil
+}
+
+func sessionInstance(id string, state ec2Types.InstanceStateName) ec2Types.Instance {
+	return ec2Types.Instance{
+		InstanceId:     awsSdk.String(id),
+		PrivateDnsName: awsSdk.String("ip-10-0-1-23.ec2.internal"),
+		State:          &ec2Types.InstanceState{Name: state},
+	}
+}
+
+func TestSessionRun(t *testing.T) {
+	lastPing := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		node      string
+		checkOnly bool
+		instances []ec2Types.Instance
+		info      []ssmTypes.InstanceInformation
+		expectErr string
+		started   bool
+	}{
+		{
+			name:      "session opened by node name",
+			node:      "ip-10-0-1-23.ec2.internal",
+			instances: []ec2Types.Instance{sessionInstance("i-1", ec2Types.InstanceStateNameRunning)},
+			info:      []ssmTypes.InstanceInformation{{InstanceId: awsSdk.String("i-1"), PingStatus: ssmTypes.PingStatusOnline}},
+			started:   true,
+		},
+		{
+			name:      "check only",
+			node:      "i-1",
+			checkOnly: true,
+			instances: []ec2Types.Instance{sessionInstance("i-1", ec2Types.InstanceStateNameRunning)},
+			info:      []ssmTypes.InstanceInformation{{InstanceId: awsSdk.String("i-1"), PingStatus: ssmTypes.PingStatusOnline}},
+		},
+		{
+			name:      "node not found",
+			node:      "ip-10-0-9-9.ec2.internal",
+			expectErr: "no instance found for node ip-10-0-9-9.ec2.internal",
+		},
+		{
+			name:      "instance stopped",
+			node:      "i-1",
+			instances: []ec2Types.Instance{sessionInstance("i-1", ec2Types.InstanceStateNameStopped)},
+			expectErr: "instance i-1 of node i-1 is stopped",
+		},
+		{
+			name:      "agent not registered",
+			node:      "i-1",
+			instances: []ec2Types.Instance{sessionInstance("i-1", ec2Types.InstanceStateNameRunning)},
+			expectErr: "isn't registered with SSM",
+		},
+		{
+			name:      "agent lost",
+			node:      "i-1",
+			instances: []ec2Types.Instance{sessionInstance("i-1", ec2Types.InstanceStateNameRunning)},
+			info:      []ssmTypes.InstanceInformation{{InstanceId: awsSdk.String("i-1"), PingStatus: ssmTypes.PingStatusConnectionLost, LastPingDateTime: &lastPing}},
+			expectErr: "the SSM agent of instance i-1 is ConnectionLost, last seen 2024-05-01 10:00:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ec2Client := &fakeSessionEC2{instances: tt.instances}
+			started := false
+			opts := &sessionOpts{
+				node:      tt.node,
+				checkOnly: tt.checkOnly,
+				out:       &bytes.Buffer{},
+				ec2Client: ec2Client,
+				ssmClient: &fakeSessionSSM{info: tt.info},
+				startSession: func(instanceID string) error {
+					assert.Equal(t, "i-1", instanceID)
+					started = true
+					return nil
+				},
+			}
+
+			err := opts.run(context.Background())
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.started, started)
+			if tt.node[:2] == "i-" {
+				assert.Equal(t, []string{tt.node}, ec2Client.input.InstanceIds)
+			} else {
+				assert.Equal(t, "private-dns-name", *ec2Client.input.Filters[0].Name)
+			}
+		})
+	}
+}
@@ -9,5 +9,7 @@ func NewCmdSSH() *cobra.Command {
 	}
 
 	cmd.AddCommand(NewCmdKey())
+	cmd.AddCommand(NewCmdSession())
+	cmd.AddCommand(NewCmdListKeys())
 	return cmd
 }
@@ -0,0 +1,90 @@
+package support
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
+)
+
+const (
+	FromCADFlag = "from-cad"
+
+	// cadReportSearchDepth is the number of latest reports of the cluster searched for the CAD investigation
+	cadReportSearchDepth = 20
+)
+
+// cadReportClient is the subset of the backplane client used to retrieve the CAD investigation reports
+type cadReportClient interface {
+	ListReports(ctx context.Context, last int) (*backplaneapi.ListReports, error)
+	GetReport(ctx context.Context, reportID string) (*backplaneapi.Report, error)
+}
+
+// cadFindings is the report written by a CAD investigation
+type cadFindings struct {
+	pipelineRun string
+	reportID    string
+	summary     string
+	details     string
+}
+
+// findCADReport searches the latest reports of the cluster for the one written by the CAD PipelineRun.
+// CAD references the PipelineRun in the report, so the summaries are checked first and the report
+// contents only when no summary matches.
+func findCADReport(ctx context.Context, client cadReportClient, pipelineRun string) (*cadFindings, error) {
+	reports, err := client.ListReports(ctx, cadReportSearchDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	for _, report := range reports.Reports {
+		if report.ReportId == nil {
+			continue
+		}
+		if report.Summary != nil && strings.Contains(*report.Summary, pipelineRun) {
+			candidates = append([]string{*report.ReportId}, candidates...)
+			continue
+		}
+		candidates = append(candidates, *report.ReportId)
+	}
+
+	for _, reportID := range candidates {
+		report, err := client.GetReport(ctx, reportID)
+		if err != nil {
+			return nil, err
+		}
+		data, err := base64.StdEncoding.DecodeString(report.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode report %s: %w", reportID, err)
+		}
+		if !strings.Contains(report.Summary, pipelineRun) && !strings.Contains(string(data), pipelineRun) {
+			continue
+		}
+		return &cadFindings{
+			pipelineRun: pipelineRun,
+			reportID:    report.ReportId,
+			summary:     report.Summary,
+			details:     string(data),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no report referencing the CAD investigation %s found in the latest %d reports of the cluster, check 'osdctl cluster reports list'", pipelineRun, cadReportSearchDepth)
+}
+
+// applyCADFindings prefills the problem with the summary of the investigation when it isn't set,
+// and references the investigation in the evidence sent in the internal service log.
+func (p *Post) applyCADFindings(findings *cadFindings) {
+	if p.Problem == "" {
+		p.Problem = strings.TrimSpace(findings.summary)
+	}
+
+	reference := fmt.Sprintf("CAD investigation %s (backplane report %s)", findings.pipelineRun, findings.reportID)
+	if p.Evidence == "" {
+		p.Evidence = reference
+	} else {
+		p.Evidence = fmt.Sprintf("%s: %s", reference, p.Evidence)
+	}
+}
@@ -0,0 +1,84 @@
+package support
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCADReportClient struct {
+	reports map[string]backplaneapi.Report
+	order   []string
+}
+
+func (f *fakeCADReportClient) ListReports(_ context.Context, _ int) (*backplaneapi.ListReports, error) {
+	list := &backplaneapi.ListReports{}
+	for _, id := range f.order {
+		report := f.reports[id]
+		list.Reports = append(list.Reports, struct {
+			CreatedAt *time.Time `json:"created_at,omitempty"`
+			ReportId  *string    `json:"report_id,omitempty"`
+			Summary   *string    `json:"summary,omitempty"`
+		}{ReportId: &report.ReportId, Summary: &report.Summary})
+	}
+	return list, nil
+}
+
+func (f *fakeCADReportClient) GetReport(_ context.Context, reportID string) (*backplaneapi.Report, error) {
+	report, ok := f.reports[reportID]
+	if !ok {
+		return nil, fmt.Errorf("report %s not found", reportID)
+	}
+	return &report, nil
+}
+
+func newFakeCADReportClient(reports ...backplaneapi.Report) *fakeCADReportClient {
+	f := &fakeCADReportClient{reports: map[string]backplaneapi.Report{}}
+	for _, report := range reports {
+		report.Data = base64.StdEncoding.EncodeToString([]byte(report.Data))
+		f.reports[report.ReportId] = report
+		f.order = append(f.order, report.ReportId)
+	}
+	return f
+}
+
+func TestFindCADReport(t *testing.T) {
+	client := newFakeCADReportClient(
+		backplaneapi.Report{ReportId: "r1", Summary: "Manual report", Data: "unrelated"},
+		backplaneapi.Report{ReportId: "r2", Summary: "Installer role is missing permissions", Data: "PipelineRun: cad-manual-abcde\nec2:RunInstances is denied"},
+		backplaneapi.Report{ReportId: "r3", Summary: "cad-manual-xyz: egress blocked", Data: "quay.io is blocked"},
+	)
+
+	findings, err := findCADReport(context.Background(), client, "cad-manual-abcde")
+	require.NoError(t, err)
+	assert.Equal(t, "r2", findings.reportID)
+	assert.Equal(t, "Installer role is missing permissions", findings.summary)
+	assert.Contains(t, findings.details, "ec2:RunInstances is denied")
+
+	findings, err = findCADReport(context.Background(), client, "cad-manual-xyz")
+	require.NoError(t, err)
+	assert.Equal(t, "r3", findings.reportID)
+
+	_, err = findCADReport(context.Background(), client, "cad-manual-missing")
+	assert.ErrorContains(t, err, "no report referencing the CAD investigation cad-manual-missing")
+}
+
+func TestApplyCADFindings(t *testing.T) {
+	findings := &cadFindings{pipelineRun: "cad-manual-abcde", reportID: "r2", summary: "The installer role is missing permissions. "}
+
+	p := &Post{}
+	p.applyCADFindings(findings)
+	assert.Equal(t, "The installer role is missing permissions.", p.Problem)
+	assert.Equal(t, "CAD investigation cad-manual-abcde (backplane report r2)", p.Evidence)
+
+	p = &Post{Problem: "The cluster is broken.", Evidence: "OHSS-1234"}
+	p.applyCADFindings(findings)
+	assert.Equal(t, "The cluster is broken.", p.Problem)
+	assert.Equal(t, "CAD investigation cad-manual-abcde (backplane report r2): OHSS-1234", p.Evidence)
+}
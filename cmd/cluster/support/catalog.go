@@ -0,0 +1,205 @@
+package support
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/openshift/osdctl/internal/utils"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+)
+
+const (
+	SearchFlag  = "search"
+	CatalogFlag = "catalog"
+)
+
+// builtinCatalog is the catalog of common limited support reasons shipped with osdctl
+//
+//go:embed catalog.json
+var builtinCatalog []byte
+
+// templateCatalog is a catalog of limited support reason templates, so that the wording of common reasons stays
+// consistent
+type templateCatalog struct {
+	Templates []catalogTemplate `json:"templates"`
+}
+
+// catalogTemplate is a limited support reason template of the catalog
+type catalogTemplate struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	// Parameters describe the ${PARAMETERS} of the details, shown when asking for their value
+	Parameters map[string]string `json:"parameters,omitempty"`
+	TemplateFile
+}
+
+// parseCatalog parses and validates a catalog
+func parseCatalog(data []byte) (*templateCatalog, error) {
+	var catalog templateCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("cannot parse the template catalog: %w", err)
+	}
+	names := map[string]bool{}
+	for _, t := range catalog.Templates {
+		if t.Name == "" || t.Summary == "" || t.Details == "" {
+			return nil, fmt.Errorf("invalid template catalog: the name, summary and details of the templates are required")
+		}
+		if names[t.Name] {
+			return nil, fmt.Errorf("invalid template catalog: template %s is defined more than once", t.Name)
+		}
+		names[t.Name] = true
+	}
+	return &catalog, nil
+}
+
+// find returns the template with the name, or nil
+func (c *templateCatalog) find(name string) *catalogTemplate {
+	for i := range c.Templates {
+		if c.Templates[i].Name == name {
+			return &c.Templates[i]
+		}
+	}
+	return nil
+}
+
+// search returns the templates matching all the words of the query, case-insensitively, in their name, description,
+// tags, summary or details
+func (c *templateCatalog) search(query string) []catalogTemplate {
+	words := strings.Fields(strings.ToLower(query))
+	var matches []catalogTemplate
+	for _, t := range c.Templates {
+		text := strings.ToLower(strings.Join(append([]string{t.Name, t.Description, t.Summary, t.Details}, t.Tags...), " "))
+		if !slices.ContainsFunc(words, func(word string) bool { return !strings.Contains(text, word) }) {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
+// loadCatalog returns the catalog at the location, a file or a URL, or the built-in catalog when the location is empty
+func (p *Post) loadCatalog() (*templateCatalog, error) {
+	if p.Catalog == "" {
+		return parseCatalog(builtinCatalog)
+	}
+	data, err := p.accessFile(p.Catalog)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the template catalog: %w", err)
+	}
+	return parseCatalog(data)
+}
+
+// selectTemplate returns the template matching the search. When several templates match, they are listed and the user
+// picks one of them.
+func selectTemplate(w io.Writer, catalog *templateCatalog, query string) (*catalogTemplate, error) {
+	matches := catalog.search(query)
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("no limited support template matches %q", query)
+	case len(matches) == 1:
+		fmt.Fprintf(w, "Using limited support template %s: %s\n", matches[0].Name, matches[0].Description)
+		return &matches[0], nil
+	}
+
+	if err := printCatalogTemplates(w, matches); err != nil {
+		return nil, err
+	}
+	if !prompt.IsInteractive() {
+		return nil, fmt.Errorf("%d limited support templates match %q, refine the search or use --template <name>", len(matches), query)
+	}
+	names := make([]string, 0, len(matches))
+	for _, t := range matches {
+		names = append(names, t.Name)
+	}
+	name, err := prompt.Select("Limited support template", names, "")
+	if err != nil {
+		return nil, err
+	}
+	return catalog.find(name), nil
+}
+
+func printCatalogTemplates(w io.Writer, templates []catalogTemplate) error {
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"NAME", "PARAMETERS", "DESCRIPTION"})
+	for _, t := range templates {
+		params := make([]string, 0, len(t.Parameters))
+		for name := range t.Parameters {
+			params = append(params, name)
+		}
+		sort.Strings(params)
+		table.AddRow([]string{t.Name, strings.Join(params, ","), t.Description})
+	}
+	return table.Flush()
+}
+
+// resolveTemplate picks the template of the limited support reason in the catalog, with --search or when --template
+// is the name of a catalog template rather than a file or a URL, then asks for the parameters which are not set
+func (p *Post) resolveTemplate(w io.Writer) error {
+	if p.Template == "" && p.Search == "" {
+		return nil
+	}
+
+	if p.Search != "" || (!utils.IsValidUrl(p.Template) && !utils.FileExists(p.Template) && !utils.FolderExists(p.Template)) {
+		catalog, err := p.loadCatalog()
+		if err != nil {
+			return err
+		}
+		var selected *catalogTemplate
+		if p.Search != "" {
+			if selected, err = selectTemplate(w, catalog, p.Search); err != nil {
+				return err
+			}
+		} else if selected = catalog.find(p.Template); selected == nil {
+			return fmt.Errorf("%q is neither a template file, a URL nor a template of the catalog, use --search to find one", p.Template)
+		}
+		p.Template = selected.Name
+		p.selected = selected
+		p.templateFile = &selected.TemplateFile
+	}
+
+	return p.fillParameters()
+}
+
+// fillParameters asks for the values of the template parameters which are not set with --param, in interactive
+// sessions only: the missing parameters are otherwise reported when building the limited support reason
+func (p *Post) fillParameters() error {
+	if !prompt.IsInteractive() {
+		return nil
+	}
+	t, err := p.readTemplate()
+	if err != nil {
+		return fmt.Errorf("error parsing template: %w", err)
+	}
+
+	set := map[string]bool{}
+	for _, param := range p.TemplateParams {
+		name, _, _ := strings.Cut(param, "=")
+		set[name] = true
+	}
+	for _, placeholder := range p.findLeftovers(t.Details) {
+		name := strings.TrimSuffix(strings.TrimPrefix(placeholder, "${"), "}")
+		if set[name] {
+			continue
+		}
+		question := fmt.Sprintf("Value of %s", name)
+		if p.selected != nil && p.selected.Parameters[name] != "" {
+			question = fmt.Sprintf("Value of %s (%s)", name, p.selected.Parameters[name])
+		}
+		value, err := prompt.Input(question, "")
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			return fmt.Errorf("the template parameter %s is not set, use -p %s=\"FOOBAR\"", name, name)
+		}
+		p.TemplateParams = append(p.TemplateParams, name+"="+value)
+		set[name] = true
+	}
+	return nil
+}
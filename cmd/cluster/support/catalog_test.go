@@ -0,0 +1,153 @@
+package support
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinCatalog(t *testing.T) {
+	catalog, err := parseCatalog(builtinCatalog)
+	require.NoError(t, err)
+	require.NotEmpty(t, catalog.Templates)
+
+	for _, tmpl := range catalog.Templates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			assert.Contains(t, []string{LimitedSupportSummaryCloud, LimitedSupportSummaryCluster}, tmpl.Summary)
+			assert.Equal(t, cmv1.DetectionTypeManual, tmpl.DetectionType)
+			assert.NotEmpty(t, tmpl.Description)
+			// The details end without punctuation like --resolution, the email template adds it
+			assert.NoError(t, validateResolutionString(tmpl.Details))
+			assert.NoError(t, (&Post{Resolution: tmpl.Details}).setup())
+
+			// Every parameter of the details is described
+			for _, placeholder := range (&Post{}).findLeftovers(tmpl.Details) {
+				name := strings.TrimSuffix(strings.TrimPrefix(placeholder, "${"), "}")
+				assert.Contains(t, tmpl.Parameters, name)
+			}
+		})
+	}
+}
+
+func TestParseCatalogErrors(t *testing.T) {
+	_, err := parseCatalog([]byte(`{"templates": [{"name": "a", "summary": "s"}]}`))
+	assert.EqualError(t, err, "invalid template catalog: the name, summary and details of the templates are required")
+
+	_, err = parseCatalog([]byte(`{"templates": [{"name": "a", "summary": "s", "details": "d"}, {"name": "a", "summary": "s", "details": "d"}]}`))
+	assert.EqualError(t, err, "invalid template catalog: template a is defined more than once")
+
+	_, err = parseCatalog([]byte(`[`))
+	assert.ErrorContains(t, err, "cannot parse the template catalog")
+}
+
+func testCatalog() *templateCatalog {
+	return &templateCatalog{Templates: []catalogTemplate{
+		{Name: "egress-blocked", Description: "Egress is blocked", Tags: []string{"network"}, Parameters: map[string]string{"ENDPOINTS": "the blocked endpoints"},
+			TemplateFile: TemplateFile{Summary: LimitedSupportSummaryCloud, Details: "Your cluster is unable to reach ${ENDPOINTS}. Allow egress"}},
+		{Name: "default-ingress-modified", Description: "The default ingress controller was modified", Tags: []string{"network", "ingress"},
+			TemplateFile: TemplateFile{Summary: LimitedSupportSummaryCluster, Details: "The default ingress controller was modified. Restore it"}},
+	}}
+}
+
+func TestCatalogSearch(t *testing.T) {
+	catalog := testCatalog()
+	names := func(templates []catalogTemplate) []string {
+		var names []string
+		for _, tmpl := range templates {
+			names = append(names, tmpl.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"egress-blocked", "default-ingress-modified"}, names(catalog.search("network")))
+	assert.Equal(t, []string{"default-ingress-modified"}, names(catalog.search("Network INGRESS")))
+	assert.Equal(t, []string{"egress-blocked"}, names(catalog.search("reach")))
+	assert.Empty(t, catalog.search("network kms"))
+}
+
+func TestSelectTemplate(t *testing.T) {
+	catalog := testCatalog()
+
+	prompt.SetNonInteractive(true)
+	defer prompt.SetNonInteractive(false)
+
+	out := &bytes.Buffer{}
+	selected, err := selectTemplate(out, catalog, "ingress")
+	require.NoError(t, err)
+	assert.Equal(t, "default-ingress-modified", selected.Name)
+	assert.Contains(t, out.String(), "Using limited support template default-ingress-modified")
+
+	_, err = selectTemplate(&bytes.Buffer{}, catalog, "kms")
+	assert.EqualError(t, err, `no limited support template matches "kms"`)
+
+	out.Reset()
+	_, err = selectTemplate(out, catalog, "network")
+	assert.EqualError(t, err, `2 limited support templates match "network", refine the search or use --template <name>`)
+	assert.Contains(t, out.String(), "egress-blocked")
+	assert.Contains(t, out.String(), "ENDPOINTS")
+
+	prompt.SetNonInteractive(false)
+	defer prompt.SetIO(strings.NewReader("egress-blocked\n"), &bytes.Buffer{})()
+	selected, err = selectTemplate(&bytes.Buffer{}, catalog, "network")
+	require.NoError(t, err)
+	assert.Equal(t, "egress-blocked", selected.Name)
+}
+
+func TestResolveTemplate(t *testing.T) {
+	catalogFile := filepath.Join(t.TempDir(), "catalog.json")
+	require.NoError(t, os.WriteFile(catalogFile, []byte(`{"templates": [{"name": "egress-blocked", "description": "Egress is blocked",
+		"parameters": {"ENDPOINTS": "the blocked endpoints"}, "summary": "s", "details": "Unable to reach ${ENDPOINTS}. Allow egress"}]}`), 0600))
+
+	t.Run("catalog template by name, prompting for its parameters", func(t *testing.T) {
+		promptOut := &bytes.Buffer{}
+		defer prompt.SetIO(strings.NewReader("quay.io:443\n"), promptOut)()
+
+		p := &Post{Template: "egress-blocked", Catalog: catalogFile}
+		require.NoError(t, p.resolveTemplate(&bytes.Buffer{}))
+		assert.Equal(t, []string{"ENDPOINTS=quay.io:443"}, p.TemplateParams)
+		assert.Contains(t, promptOut.String(), "Value of ENDPOINTS (the blocked endpoints)")
+
+		limitedSupport, err := p.buildLimitedSupportTemplate()
+		require.NoError(t, err)
+		assert.Equal(t, "Unable to reach quay.io:443. Allow egress", limitedSupport.Details())
+		assert.Equal(t, "s", limitedSupport.Summary())
+	})
+
+	t.Run("parameters set with --param are not asked for", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader(""), &bytes.Buffer{})()
+
+		p := &Post{Search: "egress", Catalog: catalogFile, TemplateParams: []string{"ENDPOINTS=quay.io:443"}}
+		require.NoError(t, p.resolveTemplate(&bytes.Buffer{}))
+		assert.Equal(t, "egress-blocked", p.Template)
+		assert.Equal(t, []string{"ENDPOINTS=quay.io:443"}, p.TemplateParams)
+	})
+
+	t.Run("empty parameter", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("\n"), &bytes.Buffer{})()
+
+		p := &Post{Template: "egress-blocked", Catalog: catalogFile}
+		assert.EqualError(t, p.resolveTemplate(&bytes.Buffer{}), `the template parameter ENDPOINTS is not set, use -p ENDPOINTS="FOOBAR"`)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		p := &Post{Template: "kms-key-unavailable", Catalog: catalogFile}
+		assert.EqualError(t, p.resolveTemplate(&bytes.Buffer{}), `"kms-key-unavailable" is neither a template file, a URL nor a template of the catalog, use --search to find one`)
+	})
+
+	t.Run("template file", func(t *testing.T) {
+		prompt.SetNonInteractive(true)
+		defer prompt.SetNonInteractive(false)
+
+		p := &Post{Template: catalogFile}
+		require.NoError(t, p.resolveTemplate(&bytes.Buffer{}))
+		assert.Nil(t, p.selected)
+		assert.Equal(t, catalogFile, p.Template)
+	})
+}
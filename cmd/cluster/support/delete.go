@@ -11,6 +11,7 @@ import (
 	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/osdctl/internal/support"
 	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/prompt"
 	"github.com/openshift/osdctl/pkg/utils"
 	ctlutil "github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -85,7 +86,7 @@ func (o *deleteOptions) run() error {
 
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection
-	err := ctlutil.IsValidClusterKey(o.clusterID)
+	err := ctlutil.ValidateClusterKey("cluster-id", o.clusterID)
 	if err != nil {
 		return err
 	}
@@ -108,7 +109,7 @@ func (o *deleteOptions) run() error {
 	}
 
 	// confirmSend prompt to confirm
-	if !utils.ConfirmPrompt() {
+	if !prompt.ConfirmPrompt() {
 		return nil
 	}
 
@@ -2,6 +2,7 @@ package support
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,6 +19,9 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
 	"github.com/openshift/osdctl/internal/utils"
+	"github.com/openshift/osdctl/pkg/backplane"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/prompt"
 	ctlutil "github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -44,8 +48,15 @@ type Post struct {
 	Problem          string
 	Resolution       string
 	Evidence         string
+	FromCAD          string
+	Search           string
+	Catalog          string
 	cluster          *cmv1.Cluster
 	ClusterID        string
+
+	// selected is the catalog template picked with --search or --template, and templateFile the parsed template
+	selected     *catalogTemplate
+	templateFile *TemplateFile
 }
 
 type TemplateFile struct {
@@ -72,7 +83,19 @@ The caller will be prompted to continue before sending the limited support reaso
   osdctl cluster support post --cluster-id ${CLUSTER_ID} --misconfiguration=cluster \
     --problem="The cluster has a second failing ingress controller" \
     --resolution="Remove the additional ingress controller" \
-    --evidence="See ${REASON}"`,
+    --evidence="See ${REASON}"
+
+  # Post a limited support reason based on the findings of a CAD investigation, the problem
+  # defaults to the summary of the investigation report
+  osdctl cluster support post --cluster-id ${CLUSTER_ID} --from-cad ${PIPELINERUN} --misconfiguration=cloud \
+    --resolution="Restore the permissions of the installer role"
+
+  # Search the catalog of common limited support reasons, pick one of the matching templates and fill
+  # its parameters interactively
+  osdctl cluster support post --cluster-id ${CLUSTER_ID} --search egress
+
+  # Post a limited support reason of the catalog by its name
+  osdctl cluster support post --cluster-id ${CLUSTER_ID} --template egress-blocked -p ENDPOINTS=quay.io:443`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -82,16 +105,21 @@ The caller will be prompted to continue before sending the limited support reaso
 
 	// Define required flags
 	postCmd.Flags().StringVarP(&p.ClusterID, "cluster-id", "C", "", "Internal Cluster ID (required)")
-	postCmd.Flags().StringVarP(&p.Template, "template", "t", "", "Message template file or URL")
+	postCmd.Flags().StringVarP(&p.Template, "template", "t", "", "Message template file or URL, or the name of a template of the catalog")
 	postCmd.Flags().StringArrayVarP(&p.TemplateParams, "param", "p", p.TemplateParams, "Specify a key-value pair (eg. -p FOO=BAR) to set/override a parameter value in the template.")
 	postCmd.Flags().Var(&p.Misconfiguration, MisconfigurationFlag, "The type of misconfiguration responsible for the cluster being placed into limited support. Valid values are `cloud` or `cluster`.")
 	postCmd.Flags().StringVar(&p.Problem, ProblemFlag, "", "Complete sentence(s) describing the problem responsible for the cluster being placed into limited support. Will form the limited support message with the contents of --resolution appended")
 	postCmd.Flags().StringVar(&p.Resolution, ResolutionFlag, "", "Complete sentence(s) describing the steps for the customer to take to resolve the issue and move out of limited support. Will form the limited support message with the contents of --problem prepended")
 	postCmd.Flags().StringVar(&p.Evidence, EvidenceFlag, "", "(optional) The reasoning that led to the decision to place the cluster in limited support. Can also be a link to a Jira case. Used for internal service log only.")
+	postCmd.Flags().StringVar(&p.FromCAD, FromCADFlag, "", "(optional) Name of a completed CAD investigation PipelineRun. Its report prefills --problem and is referenced in the internal service log.")
+
+	postCmd.Flags().StringVar(&p.Search, SearchFlag, "", "Search the template catalog for a limited support reason, the matching templates are listed to pick one of them")
+	postCmd.Flags().StringVar(&p.Catalog, CatalogFlag, "", "Template catalog file or URL to use instead of the catalog shipped with osdctl")
 
 	_ = postCmd.MarkFlagRequired("cluster-id")
+	postCmd.MarkFlagsMutuallyExclusive("template", SearchFlag)
 
-	return postCmd
+	return danger.Annotate(postCmd, danger.Medium)
 }
 
 func (p *Post) setup() error {
@@ -110,14 +138,22 @@ func validateResolutionString(res string) error {
 }
 
 func (p *Post) check() error {
-	if p.Template != "" {
-		if p.Problem != "" || p.Resolution != "" || p.Misconfiguration != "" || p.Evidence != "" {
-			return fmt.Errorf("\nIf --template flag is used, --problem, --resolution, --misconfiguration and --evidence flags cannot be used")
+	if p.Template != "" || p.Search != "" {
+		templateFlag := "--template"
+		if p.Search != "" {
+			templateFlag = "--" + SearchFlag
+		}
+		if p.Problem != "" || p.Resolution != "" || p.Misconfiguration != "" || p.Evidence != "" || p.FromCAD != "" {
+			return fmt.Errorf("\nIf %s flag is used, --problem, --resolution, --misconfiguration, --evidence and --from-cad flags cannot be used", templateFlag)
 		}
 	} else {
-		if p.Problem == "" || p.Resolution == "" || p.Misconfiguration == "" {
+		// The problem is prefilled from the CAD report when it isn't given
+		if (p.Problem == "" && p.FromCAD == "") || p.Resolution == "" || p.Misconfiguration == "" {
 			return fmt.Errorf("\nIn the absence of --template flag, --problem, --resolution and --misconfiguration flags are mandatory")
 		}
+		if err := p.checkLengths(); err != nil {
+			return err
+		}
 		if err := validateResolutionString(p.Resolution); err != nil {
 			return err
 		}
@@ -128,6 +164,15 @@ func (p *Post) check() error {
 	return nil
 }
 
+func (p *Post) checkLengths() error {
+	for flag, value := range map[string]string{ProblemFlag: p.Problem, ResolutionFlag: p.Resolution, EvidenceFlag: p.Evidence} {
+		if err := ctlutil.ValidateMaxLength(flag, value, ctlutil.MaxJustificationLength); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Post) Run(clusterID string) error {
 	userParameterNames = []string{}
 	userParameterValues = []string{}
@@ -138,7 +183,11 @@ func (p *Post) Run(clusterID string) error {
 
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection
-	if err := ctlutil.IsValidClusterKey(clusterID); err != nil {
+	if err := ctlutil.ValidateClusterKey("cluster-id", clusterID); err != nil {
+		return err
+	}
+
+	if err := p.resolveTemplate(os.Stdout); err != nil {
 		return err
 	}
 
@@ -158,6 +207,12 @@ func (p *Post) Run(clusterID string) error {
 		return fmt.Errorf("can't retrieve cluster: %w", err)
 	}
 
+	if p.FromCAD != "" {
+		if err := p.loadCADFindings(); err != nil {
+			return err
+		}
+	}
+
 	subscriptionResponse, err := connection.
 		AccountsMgmt().
 		V1().
@@ -185,7 +240,7 @@ func (p *Post) Run(clusterID string) error {
 		fmt.Println(`WARNING: This cluster is owned by a critical customer. Make sure that an SL has been sent and proactive case opened with the customer. Only continue if there has been no customer response for 24 hours.
 
 See: https://source.redhat.com/groups/public/sre/wiki/defining_limited_support_process_for_osdrosa_for_critical_customers`)
-		if !ctlutil.ConfirmPrompt() {
+		if !prompt.ConfirmPrompt() {
 			return nil
 		}
 	}
@@ -208,8 +263,8 @@ See: https://source.redhat.com/groups/public/sre/wiki/defining_limited_support_p
 		return fmt.Errorf("failed to print limited support reason template: %w", err)
 	}
 
-	if !ctlutil.ConfirmPrompt() {
-		return nil
+	if ok, err := danger.Confirm(danger.Medium, p.cluster); err != nil || !ok {
+		return err
 	}
 
 	postLimitedSupportResponse, err := sendLimitedSupportPostRequest(connection, p.cluster.ID(), limitedSupport)
@@ -243,6 +298,27 @@ See: https://source.redhat.com/groups/public/sre/wiki/defining_limited_support_p
 	return nil
 }
 
+// loadCADFindings retrieves the report of the CAD investigation from backplane, shows it and prefills the
+// limited support reason from it
+func (p *Post) loadCADFindings() error {
+	backplaneClient, err := backplane.NewClient(p.cluster.ID())
+	if err != nil {
+		return fmt.Errorf("failed to create backplane client: %w", err)
+	}
+	findings, err := findCADReport(context.Background(), backplaneClient, p.FromCAD)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Findings of CAD investigation %s (report %s):\n\n%s\n\n", findings.pipelineRun, findings.reportID, findings.details)
+	p.applyCADFindings(findings)
+
+	if p.Problem == "" {
+		return fmt.Errorf("the report of CAD investigation %s has no summary, use --problem", p.FromCAD)
+	}
+	return p.checkLengths()
+}
+
 func (p *Post) buildLimitedSupport() (*cmv1.LimitedSupportReason, error) {
 	limitedSupportBuilder := cmv1.NewLimitedSupportReason().
 		Details(fmt.Sprintf("%s %s", p.Problem, p.Resolution)).
@@ -300,18 +376,23 @@ func (p *Post) parseUserParameters() {
 	}
 }
 
+// readTemplate returns a copy of the template, which is read once
 func (p *Post) readTemplate() (*TemplateFile, error) {
-	templateObj, err := p.accessFile(p.Template)
-	if err != nil { //check the presence of this URL or file and also if this can be accessed
-		return nil, err
-	}
+	if p.templateFile == nil {
+		templateObj, err := p.accessFile(p.Template)
+		if err != nil { //check the presence of this URL or file and also if this can be accessed
+			return nil, err
+		}
 
-	var template TemplateFile
-	err = json.Unmarshal(templateObj, &template)
-	if err != nil {
-		return nil, err
+		var template TemplateFile
+		err = json.Unmarshal(templateObj, &template)
+		if err != nil {
+			return nil, err
+		}
+		p.templateFile = &template
 	}
 
+	template := *p.templateFile
 	return &template, nil
 }
 
@@ -215,6 +215,33 @@ func TestPostCheck(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "From_CAD_without_problem",
+			post: Post{
+				FromCAD:          "cad-manual-abcde",
+				Resolution:       "Reinstall the app",
+				Misconfiguration: "cloud",
+			},
+			expectError: false,
+		},
+		{
+			name: "Template_with_from_CAD_should_error",
+			post: Post{
+				Template: "some-template",
+				FromCAD:  "cad-manual-abcde",
+			},
+			expectError:    true,
+			errorSubstring: "--template flag is used",
+		},
+		{
+			name: "Search_with_other_fields_set_should_error",
+			post: Post{
+				Search:     "egress",
+				Resolution: "fix it",
+			},
+			expectError:    true,
+			errorSubstring: "--search flag is used",
+		},
 	}
 
 	for _, tt := range tests {
@@ -33,6 +33,7 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/utils"
 )
 
@@ -755,6 +756,20 @@ func (o *transferOwnerOptions) run() error {
 		fmt.Printf("with organization change from \t'%v' to '%v'\n", oldOrganizationId, newOwnerOrganizationId)
 	}
 
+	// Show the ownership changes of the subscription, the role binding may be missing after a previously failed run
+	clusterOwnerAccountID := ""
+	if roleBinding, err := getRoleBinding(ocm, subscriptionID); err == nil {
+		clusterOwnerAccountID = roleBinding.Account().ID()
+	}
+	fmt.Printf("The following changes will be applied to subscription %s:\n", subscriptionID)
+	_, err = printer.PrintDiff(os.Stdout, "current subscription", "transferred subscription",
+		formatSubscriptionOwnership(subscription.OrganizationID(), subscription.Creator().ID(), clusterOwnerAccountID),
+		formatSubscriptionOwnership(newOwnerOrganizationId, newOwnerAccountID, newOwnerAccountID),
+		printer.DefaultDiffContext)
+	if err != nil {
+		return err
+	}
+
 	if o.dryrun {
 		fmt.Print("This is a dry run, nothing changed.\n")
 		return nil
@@ -866,6 +881,15 @@ func getRoleBinding(ocm *sdk.Connection, subscriptionID string) (*amv1.RoleBindi
 	return response.Items().Get(0), nil
 }
 
+// formatSubscriptionOwnership renders the ownership fields of a subscription updated by the transfer
+func formatSubscriptionOwnership(organizationID, creatorAccountID, clusterOwnerAccountID string) string {
+	if clusterOwnerAccountID == "" {
+		clusterOwnerAccountID = "<none>"
+	}
+	return fmt.Sprintf("organization_id: %s\ncreator.id: %s\nClusterOwner role binding account_id: %s\n",
+		organizationID, creatorAccountID, clusterOwnerAccountID)
+}
+
 // deletes old rolebinding by subscription id
 func deleteOldRoleBinding(ocm *sdk.Connection, subscriptionID string) error {
 	oldRoleBinding, err := getRoleBinding(ocm, subscriptionID)
@@ -586,3 +586,10 @@ func TestUpdateManifestWorkIndexShiftSafety(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int32(2), updateCalls.Load())
 }
+
+func TestFormatSubscriptionOwnership(t *testing.T) {
+	assert.Equal(t, "organization_id: org-1\ncreator.id: account-1\nClusterOwner role binding account_id: account-1\n",
+		formatSubscriptionOwnership("org-1", "account-1", "account-1"))
+	assert.Equal(t, "organization_id: org-1\ncreator.id: account-1\nClusterOwner role binding account_id: <none>\n",
+		formatSubscriptionOwnership("org-1", "account-1", ""))
+}
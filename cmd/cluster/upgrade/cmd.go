@@ -0,0 +1,19 @@
+package upgrade
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdUpgrade implements the cluster upgrade command group
+func NewCmdUpgrade() *cobra.Command {
+	upgradeCmd := &cobra.Command{
+		Use:               "upgrade",
+		Short:             "Cluster upgrade related utilities",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+
+	upgradeCmd.AddCommand(newCmdHealthGate())
+
+	return upgradeCmd
+}
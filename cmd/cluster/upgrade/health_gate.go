@@ -0,0 +1,467 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/osdctl/cmd/cluster/metrics"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	checkPass = "PASS"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+
+	resultGo   = "GO"
+	resultNoGo = "NO-GO"
+
+	machineAPINamespace = "openshift-machine-api"
+	cpmsName            = "cluster"
+	workerRoleLabel     = "node-role.kubernetes.io/worker"
+
+	criticalAlertsQuery = `ALERTS{alertstate="firing",severity="critical"}`
+)
+
+// healthGateOptions defines the struct for running the upgrade health-gate command
+type healthGateOptions struct {
+	clusterID string
+	reason    string
+	output    string
+
+	hcp     bool
+	kubeCli client.Client
+	exec    metrics.PromtoolExecutor
+	out     io.Writer
+}
+
+// gateCheck is the result of one check of the pre-upgrade checklist
+type gateCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Details string `json:"details"`
+}
+
+// gateReport is the go/no-go result of the pre-upgrade checklist
+type gateReport struct {
+	ClusterID string      `json:"clusterID"`
+	Result    string      `json:"result"`
+	Reasons   []string    `json:"reasons"`
+	Checks    []gateCheck `json:"checks"`
+}
+
+func newCmdHealthGate() *cobra.Command {
+	opts := &healthGateOptions{}
+	healthGateCmd := &cobra.Command{
+		Use:   "health-gate --cluster-id <cluster-id> --reason <reason>",
+		Short: "Run the pre-upgrade checklist of a cluster and report a go/no-go result",
+		Long: `Run the pre-upgrade checklist of a cluster and report a go/no-go result
+
+  Nothing is changed on the cluster. The checks are:
+
+  - cluster operators: every cluster operator is available, not degraded and upgradeable.
+  - critical alerts: no critical alert is firing, read from the cluster's Prometheus through backplane.
+  - pod disruption budgets: no budget allows zero disruptions while its pods are healthy, which would block the
+    node drains of the upgrade.
+  - spare capacity: the resource requests of the busiest worker node fit in the free capacity of the other
+    worker nodes, so its pods can be rescheduled while it is drained.
+  - resize: no control plane or infra resize is ongoing, i.e. the control plane machine set and the infra
+    machine sets are rolled out and every machine is running. Skipped for hosted control planes.
+
+  The result is NO-GO when any check fails, with the failed checks as reasons. Warnings don't block the upgrade.`,
+		Example: `  # Check whether a cluster can be upgraded
+  osdctl cluster upgrade health-gate --cluster-id ${CLUSTER_ID} --reason "${REASON}"
+
+  # Get the result as JSON
+  osdctl cluster upgrade health-gate --cluster-id ${CLUSTER_ID} --reason "${REASON}" -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			opts.out = cmd.OutOrStdout()
+			return opts.run(cmd.Context())
+		},
+	}
+
+	healthGateCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	healthGateCmd.Flags().StringVar(&opts.reason, "reason", "", "The reason for this command, which requires elevation to read the alerts, to be run (usually an OHSS or PD ticket)")
+	healthGateCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+
+	_ = healthGateCmd.MarkFlagRequired("cluster-id")
+	_ = healthGateCmd.MarkFlagRequired("reason")
+
+	return healthGateCmd
+}
+
+func (o *healthGateOptions) validate() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	return nil
+}
+
+func (o *healthGateOptions) complete() error {
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	cluster, err := utils.GetCluster(conn, o.clusterID)
+	if err != nil {
+		return err
+	}
+	o.clusterID = cluster.ID()
+	o.hcp = cluster.Hypershift().Enabled()
+
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{corev1.AddToScheme, policyv1.AddToScheme, configv1.Install, machinev1.Install, machinev1beta1.Install} {
+		if err := addToScheme(scheme); err != nil {
+			return err
+		}
+	}
+	if o.kubeCli, err = k8s.New(o.clusterID, client.Options{Scheme: scheme}); err != nil {
+		return err
+	}
+
+	config, err := elevate.NewRestConfig(o.clusterID, elevate.Reason{
+		Ticket:        o.reason,
+		Justification: "read the firing alerts of cluster " + o.clusterID + " before an upgrade",
+		Command:       "cluster upgrade health-gate",
+	})
+	if err != nil {
+		return err
+	}
+	o.exec, err = metrics.NewPromtoolExecutorForConfig(config)
+	return err
+}
+
+func (o *healthGateOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	checks := []gateCheck{
+		o.checkClusterOperators(ctx),
+		o.checkCriticalAlerts(ctx),
+		o.checkPodDisruptionBudgets(ctx),
+		o.checkSpareCapacity(ctx),
+		o.checkResize(ctx),
+	}
+	report := gateReport{ClusterID: o.clusterID, Result: resultGo, Reasons: []string{}, Checks: checks}
+	for _, c := range checks {
+		if c.Status == checkFail {
+			report.Result = resultNoGo
+			report.Reasons = append(report.Reasons, fmt.Sprintf("%s: %s", c.Name, c.Details))
+		}
+	}
+
+	if err := printGateReport(o.out, report, o.output); err != nil {
+		return err
+	}
+	if report.Result == resultNoGo {
+		return fmt.Errorf("cluster %s is not ready to be upgraded", o.clusterID)
+	}
+	return nil
+}
+
+func (o *healthGateOptions) checkClusterOperators(ctx context.Context) gateCheck {
+	c := gateCheck{Name: "Cluster operators"}
+	operators := &configv1.ClusterOperatorList{}
+	if err := o.kubeCli.List(ctx, operators); err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to list the cluster operators: %v", err)
+		return c
+	}
+
+	var problems []string
+	for _, co := range operators.Items {
+		for _, condition := range co.Status.Conditions {
+			switch {
+			case condition.Type == configv1.OperatorAvailable && condition.Status != configv1.ConditionTrue:
+				problems = append(problems, co.Name+" is not available")
+			case condition.Type == configv1.OperatorDegraded && condition.Status == configv1.ConditionTrue:
+				problems = append(problems, co.Name+" is degraded")
+			case condition.Type == configv1.OperatorUpgradeable && condition.Status == configv1.ConditionFalse:
+				problems = append(problems, fmt.Sprintf("%s is not upgradeable (%s)", co.Name, condition.Reason))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		c.Status, c.Details = checkFail, strings.Join(problems, ", ")
+		return c
+	}
+	c.Status, c.Details = checkPass, fmt.Sprintf("%d cluster operators healthy", len(operators.Items))
+	return c
+}
+
+func (o *healthGateOptions) checkCriticalAlerts(ctx context.Context) gateCheck {
+	c := gateCheck{Name: "Critical alerts"}
+	alerts, err := metrics.InstantQuery(ctx, o.exec, criticalAlertsQuery)
+	if err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to query the firing alerts: %v", err)
+		return c
+	}
+
+	var firing []string
+	for _, a := range alerts {
+		name := a.Labels["alertname"]
+		if namespace := a.Labels["namespace"]; namespace != "" {
+			name += " (" + namespace + ")"
+		}
+		if !slices.Contains(firing, name) {
+			firing = append(firing, name)
+		}
+	}
+	if len(firing) > 0 {
+		sort.Strings(firing)
+		c.Status, c.Details = checkFail, "firing: "+strings.Join(firing, ", ")
+		return c
+	}
+	c.Status, c.Details = checkPass, "no critical alert firing"
+	return c
+}
+
+// checkPodDisruptionBudgets fails for the budgets which allow no disruption although their pods are healthy: they
+// will never allow the eviction of their pods, blocking the drain of their nodes
+func (o *healthGateOptions) checkPodDisruptionBudgets(ctx context.Context) gateCheck {
+	c := gateCheck{Name: "Pod disruption budgets"}
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := o.kubeCli.List(ctx, pdbs); err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to list the pod disruption budgets: %v", err)
+		return c
+	}
+
+	var deadlocked, unhealthy []string
+	for _, pdb := range pdbs.Items {
+		if pdb.Status.ExpectedPods == 0 || pdb.Status.DisruptionsAllowed > 0 {
+			continue
+		}
+		name := pdb.Namespace + "/" + pdb.Name
+		if pdb.Status.CurrentHealthy >= pdb.Status.DesiredHealthy {
+			deadlocked = append(deadlocked, name)
+		} else {
+			unhealthy = append(unhealthy, name)
+		}
+	}
+	switch {
+	case len(deadlocked) > 0:
+		c.Status, c.Details = checkFail, "allowing no disruption with all pods healthy: "+strings.Join(deadlocked, ", ")
+	case len(unhealthy) > 0:
+		c.Status, c.Details = checkWarn, "allowing no disruption until their pods are healthy: "+strings.Join(unhealthy, ", ")
+	default:
+		c.Status, c.Details = checkPass, fmt.Sprintf("%d pod disruption budgets allow disruptions", len(pdbs.Items))
+	}
+	return c
+}
+
+// checkSpareCapacity checks that the pods of the busiest worker node can be rescheduled on the other worker nodes
+// while it is drained
+func (o *healthGateOptions) checkSpareCapacity(ctx context.Context) gateCheck {
+	c := gateCheck{Name: "Spare capacity"}
+	nodes := &corev1.NodeList{}
+	if err := o.kubeCli.List(ctx, nodes, client.HasLabels{workerRoleLabel}); err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to list the worker nodes: %v", err)
+		return c
+	}
+	pods := &corev1.PodList{}
+	if err := o.kubeCli.List(ctx, pods); err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to list the pods: %v", err)
+		return c
+	}
+
+	requested := map[string]corev1.ResourceList{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		addResources(requested, pod.Spec.NodeName, podRequests(pod))
+	}
+
+	var workers []corev1.Node
+	for _, node := range nodes.Items {
+		// Infra and master nodes may carry the worker role too, their pods can't move to the workers
+		if _, infra := node.Labels["node-role.kubernetes.io/infra"]; infra {
+			continue
+		}
+		if _, master := node.Labels["node-role.kubernetes.io/master"]; master {
+			continue
+		}
+		workers = append(workers, node)
+	}
+	if len(workers) < 2 {
+		c.Status, c.Details = checkWarn, fmt.Sprintf("%d worker node(s), the pods of a drained node can't be rescheduled", len(workers))
+		return c
+	}
+
+	for _, drained := range workers {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			needed := requested[drained.Name][name]
+			if needed.IsZero() {
+				continue
+			}
+			free := resource.Quantity{}
+			for _, node := range workers {
+				if node.Name == drained.Name || node.Spec.Unschedulable {
+					continue
+				}
+				available := node.Status.Allocatable[name]
+				available.Sub(requested[node.Name][name])
+				if available.Sign() > 0 {
+					free.Add(available)
+				}
+			}
+			if free.Cmp(needed) < 0 {
+				c.Status = checkFail
+				c.Details = fmt.Sprintf("the %s requests of node %s (%s) don't fit in the free %s of the other workers (%s)", name, drained.Name, needed.String(), name, free.String())
+				return c
+			}
+		}
+	}
+	c.Status, c.Details = checkPass, fmt.Sprintf("the pods of any of the %d worker nodes fit on the others", len(workers))
+	return c
+}
+
+func (o *healthGateOptions) checkResize(ctx context.Context) gateCheck {
+	c := gateCheck{Name: "Resize"}
+	if o.hcp {
+		c.Status, c.Details = checkPass, "skipped, the control plane of a hosted cluster isn't resized on the cluster"
+		return c
+	}
+
+	var ongoing []string
+	cpms := &machinev1.ControlPlaneMachineSet{}
+	err := o.kubeCli.Get(ctx, client.ObjectKey{Namespace: machineAPINamespace, Name: cpmsName}, cpms)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to get the control plane machine set: %v", err)
+		return c
+	case cpms.Spec.Replicas != nil && (cpms.Status.UpdatedReplicas != *cpms.Spec.Replicas || cpms.Status.ReadyReplicas != *cpms.Spec.Replicas):
+		ongoing = append(ongoing, fmt.Sprintf("control plane machine set rolling out (%d/%d updated, %d/%d ready)",
+			cpms.Status.UpdatedReplicas, *cpms.Spec.Replicas, cpms.Status.ReadyReplicas, *cpms.Spec.Replicas))
+	}
+
+	machineSets := &machinev1beta1.MachineSetList{}
+	if err := o.kubeCli.List(ctx, machineSets, client.InNamespace(machineAPINamespace)); err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to list the machine sets: %v", err)
+		return c
+	}
+	for _, ms := range machineSets.Items {
+		if !strings.Contains(ms.Name, "-infra-") || ms.Spec.Replicas == nil {
+			continue
+		}
+		if ms.Status.ReadyReplicas != *ms.Spec.Replicas {
+			ongoing = append(ongoing, fmt.Sprintf("infra machine set %s has %d/%d ready machines", ms.Name, ms.Status.ReadyReplicas, *ms.Spec.Replicas))
+		}
+	}
+
+	machines := &machinev1beta1.MachineList{}
+	if err := o.kubeCli.List(ctx, machines, client.InNamespace(machineAPINamespace)); err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to list the machines: %v", err)
+		return c
+	}
+	for _, machine := range machines.Items {
+		role := machine.Labels["machine.openshift.io/cluster-api-machine-role"]
+		if role != "master" && role != "infra" {
+			continue
+		}
+		if phase := ptrString(machine.Status.Phase); phase != "Running" {
+			ongoing = append(ongoing, fmt.Sprintf("%s machine %s is %s", role, machine.Name, phase))
+		}
+	}
+
+	if len(ongoing) > 0 {
+		c.Status, c.Details = checkFail, strings.Join(ongoing, ", ")
+		return c
+	}
+	c.Status, c.Details = checkPass, "no control plane or infra resize ongoing"
+	return c
+}
+
+func podRequests(pod corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	// Init containers run before the containers, the pod requests the highest of both
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current := requests[name]; quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	return requests
+}
+
+func addResources(totals map[string]corev1.ResourceList, key string, resources corev1.ResourceList) {
+	if totals[key] == nil {
+		totals[key] = corev1.ResourceList{}
+	}
+	for name, quantity := range resources {
+		total := totals[key][name]
+		total.Add(quantity)
+		totals[key][name] = total
+	}
+}
+
+func ptrString(s *string) string {
+	if s == nil {
+		return "unknown"
+	}
+	return *s
+}
+
+func printGateReport(w io.Writer, report gateReport, output string) error {
+	if output == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, c := range report.Checks {
+		table.AddRow([]string{c.Name, c.Status, c.Details})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "\nResult: %s\n", report.Result)
+	for _, reason := range report.Reasons {
+		_, _ = fmt.Fprintf(w, "  - %s\n", reason)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/osdctl/cmd/cluster/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func fakeAlertsExecutor(alerts string) metrics.PromtoolExecutor {
+	return func(_ context.Context, command []string) (string, error) {
+		if expression := command[len(command)-1]; expression != criticalAlertsQuery {
+			return "", fmt.Errorf("unexpected query %s", expression)
+		}
+		return alerts, nil
+	}
+}
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+	require.NoError(t, configv1.Install(scheme))
+	require.NoError(t, machinev1.Install(scheme))
+	require.NoError(t, machinev1beta1.Install(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func clusterOperator(name string, conditions ...configv1.ClusterOperatorStatusCondition) *configv1.ClusterOperator {
+	return &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     configv1.ClusterOperatorStatus{Conditions: conditions},
+	}
+}
+
+func workerNode(name, cpu string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{workerRoleLabel: ""}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse("16Gi"),
+		}},
+	}
+}
+
+func podOn(name, node, cpu string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{
+			Name:      "app",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func pdb(name string, expected, healthy, desired, allowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			ExpectedPods:       expected,
+			CurrentHealthy:     healthy,
+			DesiredHealthy:     desired,
+			DisruptionsAllowed: allowed,
+		},
+	}
+}
+
+func runningMachine(name, role string) *machinev1beta1.Machine {
+	phase := "Running"
+	return &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: machineAPINamespace, Labels: map[string]string{"machine.openshift.io/cluster-api-machine-role": role}},
+		Status:     machinev1beta1.MachineStatus{Phase: &phase},
+	}
+}
+
+func healthyObjects() []client.Object {
+	replicas := int32(3)
+	return []client.Object{
+		clusterOperator("dns", configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue}),
+		clusterOperator("ingress", configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue}),
+		workerNode("worker-0", "4"),
+		workerNode("worker-1", "4"),
+		podOn("app-0", "worker-0", "2"),
+		podOn("app-1", "worker-1", "1"),
+		pdb("app", 2, 2, 1, 1),
+		&machinev1.ControlPlaneMachineSet{
+			ObjectMeta: metav1.ObjectMeta{Name: cpmsName, Namespace: machineAPINamespace},
+			Spec:       machinev1.ControlPlaneMachineSetSpec{Replicas: &replicas},
+			Status:     machinev1.ControlPlaneMachineSetStatus{Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 3},
+		},
+		runningMachine("master-0", "master"),
+	}
+}
+
+func TestHealthGateRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		objects      func() []client.Object
+		alerts       string
+		hcp          bool
+		expectResult string
+		expectStatus map[string]string
+		expectReason string
+	}{
+		{
+			name:         "all checks pass",
+			objects:      healthyObjects,
+			alerts:       "[]",
+			expectResult: resultGo,
+			expectStatus: map[string]string{"Cluster operators": checkPass, "Critical alerts": checkPass, "Pod disruption budgets": checkPass, "Spare capacity": checkPass, "Resize": checkPass},
+		},
+		{
+			name: "degraded operator",
+			objects: func() []client.Object {
+				return append(healthyObjects(), clusterOperator("monitoring", configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue}))
+			},
+			alerts:       "[]",
+			expectResult: resultNoGo,
+			expectStatus: map[string]string{"Cluster operators": checkFail},
+			expectReason: "Cluster operators: monitoring is degraded",
+		},
+		{
+			name:         "critical alert firing",
+			objects:      healthyObjects,
+			alerts:       `[{"metric":{"alertname":"KubeAPIDown","namespace":"openshift-kube-apiserver"},"value":[1700000000,"1"]}]`,
+			expectResult: resultNoGo,
+			expectStatus: map[string]string{"Critical alerts": checkFail},
+			expectReason: "Critical alerts: firing: KubeAPIDown (openshift-kube-apiserver)",
+		},
+		{
+			name: "deadlocked pod disruption budget",
+			objects: func() []client.Object {
+				return append(healthyObjects(), pdb("singleton", 1, 1, 1, 0))
+			},
+			alerts:       "[]",
+			expectResult: resultNoGo,
+			expectStatus: map[string]string{"Pod disruption budgets": checkFail},
+			expectReason: "default/singleton",
+		},
+		{
+			name: "unhealthy pod disruption budget only warns",
+			objects: func() []client.Object {
+				return append(healthyObjects(), pdb("starting", 2, 1, 2, 0))
+			},
+			alerts:       "[]",
+			expectResult: resultGo,
+			expectStatus: map[string]string{"Pod disruption budgets": checkWarn},
+		},
+		{
+			name: "no spare capacity",
+			objects: func() []client.Object {
+				return append(healthyObjects(), podOn("app-2", "worker-1", "2"))
+			},
+			alerts:       "[]",
+			expectResult: resultNoGo,
+			expectStatus: map[string]string{"Spare capacity": checkFail},
+			expectReason: "the cpu requests of node worker-0 (2) don't fit in the free cpu of the other workers (1)",
+		},
+		{
+			name: "ongoing control plane resize",
+			objects: func() []client.Object {
+				objects := healthyObjects()
+				provisioning := "Provisioning"
+				machine := runningMachine("master-3", "master")
+				machine.Status.Phase = &provisioning
+				return append(objects, machine)
+			},
+			alerts:       "[]",
+			expectResult: resultNoGo,
+			expectStatus: map[string]string{"Resize": checkFail},
+			expectReason: "master machine master-3 is Provisioning",
+		},
+		{
+			name: "resize skipped for hosted control planes",
+			objects: func() []client.Object {
+				provisioning := "Provisioning"
+				machine := runningMachine("master-3", "master")
+				machine.Status.Phase = &provisioning
+				return append(healthyObjects(), machine)
+			},
+			alerts:       "[]",
+			hcp:          true,
+			expectResult: resultGo,
+			expectStatus: map[string]string{"Resize": checkPass},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			opts := &healthGateOptions{
+				clusterID: "abc",
+				output:    "json",
+				hcp:       tt.hcp,
+				kubeCli:   newFakeClient(t, tt.objects()...),
+				exec:      fakeAlertsExecutor(tt.alerts),
+				out:       out,
+			}
+			err := opts.run(context.Background())
+			if tt.expectResult == resultNoGo {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			var report gateReport
+			require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+			assert.Equal(t, tt.expectResult, report.Result)
+			statuses := map[string]string{}
+			for _, c := range report.Checks {
+				statuses[c.Name] = c.Status
+			}
+			for name, status := range tt.expectStatus {
+				assert.Equal(t, status, statuses[name], name)
+			}
+			if tt.expectReason != "" {
+				require.Len(t, report.Reasons, 1)
+				assert.Contains(t, report.Reasons[0], tt.expectReason)
+			}
+		})
+	}
+}
+
+func TestPrintGateReportTable(t *testing.T) {
+	out := &bytes.Buffer{}
+	report := gateReport{
+		Result:  resultNoGo,
+		Reasons: []string{"Critical alerts: firing: KubeAPIDown"},
+		Checks: []gateCheck{
+			{Name: "Cluster operators", Status: checkPass, Details: "2 cluster operators healthy"},
+			{Name: "Critical alerts", Status: checkFail, Details: "firing: KubeAPIDown"},
+		},
+	}
+	require.NoError(t, printGateReport(out, report, "table"))
+	assert.Contains(t, out.String(), "CHECK")
+	assert.Contains(t, out.String(), "Result: NO-GO")
+	assert.Contains(t, out.String(), "  - Critical alerts: firing: KubeAPIDown")
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const clusterIDFlag = "cluster-id"
+
+// resolveClusterIDFlag replaces a cluster name given to --cluster-id by the internal ID of the cluster, so every
+// command accepts names. The user picks the cluster when several have the name, and a name which can't be resolved
+// is left for the command to report.
+func resolveClusterIDFlag(cmd *cobra.Command, resolve func(name string) (*cmv1.Cluster, error)) error {
+	flag := cmd.Flags().Lookup(clusterIDFlag)
+	if flag == nil || !flag.Changed || flag.Value.Type() != "string" {
+		return nil
+	}
+	name := flag.Value.String()
+	if name == "" || utils.GetClusterKeyKind(name) != utils.ClusterKeyName {
+		return nil
+	}
+
+	cluster, err := resolve(name)
+	if err != nil {
+		var notFound *utils.ClusterNotFoundError
+		if errors.As(err, &notFound) && notFound.Matches > 1 {
+			return err
+		}
+		return nil
+	}
+	return cmd.Flags().Set(clusterIDFlag, cluster.ID())
+}
+
+// resolveClusterName looks up the cluster with the name in the OCM environment of the current login
+func resolveClusterName(name string) (*cmv1.Cluster, error) {
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return utils.GetCluster(conn, name)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clusterIDCommand(t *testing.T, args ...string) (*cobra.Command, *string) {
+	t.Helper()
+	var clusterID string
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringVarP(&clusterID, clusterIDFlag, "C", "", "")
+	require.NoError(t, cmd.ParseFlags(args))
+	return cmd, &clusterID
+}
+
+func TestResolveClusterIDFlag(t *testing.T) {
+	resolved, err := cmv1.NewCluster().ID("2abcdefghijklmnopqrstuvwxyz01234").Build()
+	require.NoError(t, err)
+	var looked []string
+	resolve := func(name string) (*cmv1.Cluster, error) {
+		looked = append(looked, name)
+		return resolved, nil
+	}
+
+	cmd, clusterID := clusterIDCommand(t, "-C", "my-cluster")
+	require.NoError(t, resolveClusterIDFlag(cmd, resolve))
+	assert.Equal(t, "2abcdefghijklmnopqrstuvwxyz01234", *clusterID)
+
+	for _, args := range [][]string{
+		{"--cluster-id", "1a2b3c4d5e6f7g8h9i0j1k2l3m4n5o6p"},
+		{"--cluster-id", "3f2e1d0c-1234-4abc-8def-0123456789ab"},
+		{},
+	} {
+		cmd, _ := clusterIDCommand(t, args...)
+		require.NoError(t, resolveClusterIDFlag(cmd, resolve))
+	}
+	assert.Equal(t, []string{"my-cluster"}, looked)
+
+	require.NoError(t, resolveClusterIDFlag(&cobra.Command{Use: "no-flag"}, resolve))
+}
+
+func TestResolveClusterIDFlagErrors(t *testing.T) {
+	cmd, clusterID := clusterIDCommand(t, "-C", "unknown")
+	require.NoError(t, resolveClusterIDFlag(cmd, func(string) (*cmv1.Cluster, error) {
+		return nil, &utils.ClusterNotFoundError{Key: "unknown"}
+	}))
+	assert.Equal(t, "unknown", *clusterID, "names which can't be resolved are left to the command")
+
+	cmd, _ = clusterIDCommand(t, "-C", "shared-name")
+	ambiguous := &utils.ClusterNotFoundError{Key: "shared-name", Matches: 2}
+	assert.Equal(t, ambiguous, resolveClusterIDFlag(cmd, func(string) (*cmv1.Cluster, error) {
+		return nil, ambiguous
+	}))
+
+	cmd, _ = clusterIDCommand(t, "-C", "offline")
+	require.NoError(t, resolveClusterIDFlag(cmd, func(string) (*cmv1.Cluster, error) {
+		return nil, errors.New("not logged in")
+	}))
+}
+
+func TestClusterIDShorthandIsResolved(t *testing.T) {
+	root := newTestRoot()
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+		cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			if f.Shorthand == "C" && f.Name != clusterIDFlag {
+				t.Errorf("command %q registers -C as --%s, which cluster names aren't resolved for", cmd.CommandPath(), f.Name)
+			}
+		})
+	}
+	walk(root)
+}
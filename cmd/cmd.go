@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	routev1 "github.com/openshift/api/route/v1"
@@ -18,15 +19,19 @@ import (
 	"k8s.io/kubectl/pkg/util/slice"
 
 	"github.com/openshift/osdctl/cmd/aao"
+	"github.com/openshift/osdctl/cmd/accessrequest"
 	"github.com/openshift/osdctl/cmd/account"
 	"github.com/openshift/osdctl/cmd/alerts"
+	"github.com/openshift/osdctl/cmd/cloudlog"
 	"github.com/openshift/osdctl/cmd/cloudtrail"
 	"github.com/openshift/osdctl/cmd/cluster"
 	"github.com/openshift/osdctl/cmd/cost"
 	"github.com/openshift/osdctl/cmd/dynatrace"
 	"github.com/openshift/osdctl/cmd/env"
 	"github.com/openshift/osdctl/cmd/evidence"
+	"github.com/openshift/osdctl/cmd/fleet"
 	"github.com/openshift/osdctl/cmd/hcp"
+	"github.com/openshift/osdctl/cmd/history"
 	"github.com/openshift/osdctl/cmd/hive"
 	"github.com/openshift/osdctl/cmd/iampermissions"
 	"github.com/openshift/osdctl/cmd/jira"
@@ -36,11 +41,19 @@ import (
 	"github.com/openshift/osdctl/cmd/org"
 	"github.com/openshift/osdctl/cmd/promote"
 	"github.com/openshift/osdctl/cmd/rhobs"
+	"github.com/openshift/osdctl/cmd/selftest"
+	"github.com/openshift/osdctl/cmd/serve"
 	"github.com/openshift/osdctl/cmd/servicelog"
 	"github.com/openshift/osdctl/cmd/setup"
+	"github.com/openshift/osdctl/cmd/sts"
 	"github.com/openshift/osdctl/cmd/swarm"
+	"github.com/openshift/osdctl/cmd/ui"
 	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/elevate"
+	commandhistory "github.com/openshift/osdctl/pkg/history"
 	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/prompt"
 	"github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/openshift/osdctl/pkg/utils"
 )
@@ -64,6 +77,24 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 		DisableAutoGenTag: true,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			cmd.SilenceUsage = true
+			// The outcome is recorded by main once the command returned or exited through cmdutil.CheckErr
+			commandhistory.Start(cmd)
+
+			if err := osdctlConfig.ApplyFlagDefaults(cmd); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			prompt.SetAssumeYes(globalOpts.AssumeYes)
+			prompt.SetNonInteractive(globalOpts.NonInteractive)
+			if globalOpts.Trace {
+				utils.EnableOCMTrace(os.Stderr)
+			}
+			if err := resolveClusterIDFlag(cmd, resolveClusterName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			verifyReasonTickets(cmd, globalOpts.VerifyTickets)
 
 			if cmd.Flags().Lookup(aws.NoProxyFlag) != nil {
 				noAwsProxy, err := cmd.Flags().GetBool(aws.NoProxyFlag)
@@ -87,6 +118,9 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	}
 
 	globalOpts.AddSkipVersionCheckFlag(rootCmd)
+	globalOpts.AddPromptFlags(rootCmd)
+	globalOpts.AddTraceFlag(rootCmd)
+	globalOpts.AddVerifyTicketsFlag(rootCmd)
 	addToRootCmdWithOtherGlobalOpts := func(cmd *cobra.Command) {
 		globalOpts.AddOutputFlag(cmd)
 		globalOpts.AddNoAwsProxyFlag(cmd)
@@ -100,16 +134,20 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	// add sub commands
 	addToRootCmdWithOtherGlobalOpts(aao.NewCmdAao(kubeClient))
 	addToRootCmdWithOtherGlobalOpts(account.NewCmdAccount(streams, kubeClient, globalOpts))
+	rootCmd.AddCommand(accessrequest.NewCmdAccessRequest())
 	addToRootCmdWithOtherGlobalOpts(alerts.NewCmdAlerts())
+	addToRootCmdWithOtherGlobalOpts(cloudlog.NewCloudlogCmd())
 	addToRootCmdWithOtherGlobalOpts(cloudtrail.NewCloudtrailCmd())
 	addToRootCmdWithOtherGlobalOpts(cluster.NewCmdCluster(streams, kubeClient, globalOpts))
 	addToRootCmdWithOtherGlobalOpts(env.NewCmdEnv())
 	addToRootCmdWithOtherGlobalOpts(evidence.NewCmdEvidence())
+	rootCmd.AddCommand(fleet.NewCmdFleet())
 	addToRootCmdWithOtherGlobalOpts(hive.NewCmdHive(streams, kubeClient))
 	addToRootCmdWithOtherGlobalOpts(jira.Cmd)
 	addToRootCmdWithOtherGlobalOpts(jumphost.NewCmdJumphost())
 	addToRootCmdWithOtherGlobalOpts(mc.NewCmdMC())
 	addToRootCmdWithOtherGlobalOpts(hcp.NewCmdHCP())
+	rootCmd.AddCommand(history.NewCmdHistory())
 	addToRootCmdWithOtherGlobalOpts(network.NewCmdNetwork(streams, kubeClient))
 	addToRootCmdWithOtherGlobalOpts(org.NewCmdOrg())
 	rootCmd.AddCommand(promote.NewCmdPromote())
@@ -119,6 +157,10 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	addToRootCmdWithOtherGlobalOpts(iampermissions.NewCmdIamPermissions())
 	rootCmd.AddCommand(dynatrace.NewCmdDynatrace())
 	rootCmd.AddCommand(rhobs.NewCmdRhobs())
+	rootCmd.AddCommand(sts.NewCmdSts())
+	rootCmd.AddCommand(serve.NewCmdServe())
+	rootCmd.AddCommand(selftest.NewCmdSelftest())
+	rootCmd.AddCommand(ui.NewCmdUI())
 
 	// Add cost command to use AWS Cost Manager
 	addToRootCmdWithOtherGlobalOpts(cost.NewCmdCost(streams, globalOpts))
@@ -153,12 +195,31 @@ func shouldSkipVersionCheckForCmd(cmd *cobra.Command) bool {
 	return false
 }
 
+// verifyReasonTickets warns when the tickets referenced by the --reason of the command don't exist, with
+// --verify-tickets or when enabled in the config file
+func verifyReasonTickets(cmd *cobra.Command, verifyTickets bool) {
+	reason := cmd.Flags().Lookup(elevate.ReasonFlag)
+	if reason == nil || !reason.Changed {
+		return
+	}
+	if verifyTickets || utils.TicketVerificationEnabled() {
+		utils.VerifyTicketReferences(os.Stderr, elevate.ReasonFlag, reason.Value.String())
+	}
+}
+
 // Returns allowlist of commands that can skip version check
 func getSkipVersionCommands() []string {
-	return []string{"upgrade", "version", "mcp"}
+	return []string{"upgrade", "version", "mcp", "history", "serve"}
 }
 
 func versionCheck() {
+	if utils.DailyVersionCheckEnabled() {
+		if utils.VersionCheckDue(time.Now()) {
+			warnIfOutdated(os.Stderr)
+		}
+		return
+	}
+
 	latestVersion, err := utils.GetLatestVersion()
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, "WARN: Unable to verify that osdctl is running under the latest released version. Error trying to reach GitHub:")
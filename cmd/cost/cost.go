@@ -2,6 +2,7 @@ package cost
 
 import (
 	"log"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
@@ -28,6 +29,9 @@ platforms may be added in the future)`,
 	costCmd.PersistentFlags().StringVarP(&opsCost.profile, "aws-profile", "p", "", "specify AWS profile")
 	costCmd.PersistentFlags().StringVarP(&opsCost.configFile, "aws-config", "c", "", "specify AWS config file path")
 	costCmd.PersistentFlags().StringVarP(&opsCost.region, "aws-region", "g", common.DefaultRegion, "specify AWS region")
+	costCmd.PersistentFlags().IntVar(&opsCost.concurrency, "concurrency", defaultCostConcurrency, "number of accounts whose cost is fetched from Cost Explorer in parallel")
+	costCmd.PersistentFlags().BoolVar(&opsCost.cache, "cache", false, "reuse the costs of the accounts fetched by previous commands, and store the fetched ones")
+	costCmd.PersistentFlags().DurationVar(&opsCost.cacheTTL, "cache-ttl", defaultCostCacheTTL, "how long the cached costs are reused with --cache")
 
 	//Add commands
 	costCmd.AddCommand(newCmdGet(streams, globalOpts))
@@ -50,6 +54,11 @@ type costOptions struct {
 	profile         string
 	region          string
 
+	// Cost Explorer fetching
+	concurrency int
+	cache       bool
+	cacheTTL    time.Duration
+
 	genericclioptions.IOStreams
 }
 
@@ -0,0 +1,218 @@
+package cost
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/shopspring/decimal"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/term"
+)
+
+const (
+	defaultCostConcurrency = 5
+	defaultCostCacheTTL    = 24 * time.Hour
+
+	// costCacheFile is the file of the data dir the costs are cached in with --cache
+	costCacheFile = "cost/costs.json"
+)
+
+// costFetcher fetches the costs of accounts from Cost Explorer concurrently. The costs are kept in memory for the
+// command, so that nested OUs don't fetch the costs of their accounts again, and in the cache file with --cache.
+type costFetcher struct {
+	concurrency int
+	// cacheFile is where the costs are stored between commands, empty when caching is disabled
+	cacheFile string
+	cacheTTL  time.Duration
+	progress  *costProgress
+
+	mu      sync.Mutex
+	entries map[string]costCacheEntry
+}
+
+// costCacheEntry is the cost of an account for a time period
+type costCacheEntry struct {
+	Cost      decimal.Decimal `json:"cost"`
+	Unit      string          `json:"unit"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+}
+
+// newCostFetcher returns the fetcher for the flags of the cost command, loading the cache file with --cache
+func (opsCost *costOptions) newCostFetcher() (*costFetcher, error) {
+	if opsCost.concurrency < 1 {
+		return nil, fmt.Errorf("--concurrency must be at least 1, got %d", opsCost.concurrency)
+	}
+	f := &costFetcher{
+		concurrency: opsCost.concurrency,
+		cacheTTL:    opsCost.cacheTTL,
+		progress:    newCostProgress(opsCost.ErrOut),
+		entries:     map[string]costCacheEntry{},
+	}
+	if !opsCost.cache {
+		return f, nil
+	}
+
+	dataDir, err := osdctlConfig.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	f.cacheFile = filepath.Join(dataDir, costCacheFile)
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// load reads the entries of the cache file which haven't expired
+func (f *costFetcher) load() error {
+	data, err := os.ReadFile(f.cacheFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the cost cache: %w", err)
+	}
+	var entries map[string]costCacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		// A corrupted cache is only a missed optimization, the costs are fetched again
+		fmt.Fprintf(os.Stderr, "Ignoring the cost cache %s: %v\n", f.cacheFile, err)
+		return nil
+	}
+	for key, entry := range entries {
+		if time.Since(entry.FetchedAt) < f.cacheTTL {
+			f.entries[key] = entry
+		}
+	}
+	return nil
+}
+
+// save writes the entries to the cache file with --cache, and does nothing otherwise
+func (f *costFetcher) save() error {
+	if f == nil || f.cacheFile == "" {
+		return nil
+	}
+	f.mu.Lock()
+	data, err := json.MarshalIndent(f.entries, "", "  ")
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.cacheFile), 0700); err != nil {
+		return fmt.Errorf("failed to create the cost cache directory: %w", err)
+	}
+	if err := os.WriteFile(f.cacheFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write the cost cache: %w", err)
+	}
+	return nil
+}
+
+func costCacheKey(accountID, start, end, granularity string) string {
+	return strings.Join([]string{accountID, start, end, granularity}, "/")
+}
+
+// accountCosts returns the costs of the accounts during the time period of the options, in the order of the accounts.
+// All the accounts are fetched even when some fail: the costs of the others are returned with the first error.
+func (f *costFetcher) accountCosts(o *getOptions, accounts []*string, awsClient awsprovider.Client) ([]AccountCost, error) {
+	start, end, granularity := o.timePeriod()
+	results := make([]*AccountCost, len(accounts))
+
+	var missing []int
+	f.mu.Lock()
+	for i, account := range accounts {
+		if entry, ok := f.entries[costCacheKey(*account, start, end, granularity)]; ok {
+			results[i] = &AccountCost{AccountID: *account, Cost: entry.Cost, Unit: entry.Unit}
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	f.mu.Unlock()
+
+	f.progress.start(len(missing))
+	defer f.progress.finish()
+
+	var g errgroup.Group
+	g.SetLimit(f.concurrency)
+	for _, i := range missing {
+		account := accounts[i]
+		g.Go(func() error {
+			defer f.progress.increment()
+			accCost := AccountCost{AccountID: *account, Cost: decimal.Zero}
+			if err := o.getAccountCost(account, &accCost.Unit, awsClient, &accCost.Cost); err != nil {
+				return fmt.Errorf("failed to get the cost of account %s: %w", *account, err)
+			}
+			results[i] = &accCost
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.entries[costCacheKey(*account, start, end, granularity)] = costCacheEntry{Cost: accCost.Cost, Unit: accCost.Unit, FetchedAt: time.Now()}
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	costs := make([]AccountCost, 0, len(accounts))
+	for _, result := range results {
+		if result != nil {
+			costs = append(costs, *result)
+		}
+	}
+	return costs, err
+}
+
+// costProgress renders the number of accounts whose cost was fetched on a terminal, and nothing otherwise
+type costProgress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	enabled bool
+	total   int
+	done    int
+}
+
+func newCostProgress(w io.Writer) *costProgress {
+	f, ok := w.(*os.File)
+	return &costProgress{w: w, enabled: ok && term.IsTerminal(int(f.Fd()))} // #nosec G115 -- file descriptors fit into an int
+}
+
+func (p *costProgress) start(total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total, p.done = total, 0
+	p.render()
+}
+
+func (p *costProgress) increment() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.render()
+}
+
+// finish clears the progress line, so that it doesn't mix with the output
+func (p *costProgress) finish() {
+	if p == nil || !p.enabled || p.total == 0 {
+		return
+	}
+	_, _ = fmt.Fprint(p.w, "\r\033[K")
+}
+
+func (p *costProgress) render() {
+	if !p.enabled || p.total == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(p.w, "\rFetching costs: %d/%d accounts", p.done, p.total)
+}
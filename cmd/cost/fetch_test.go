@@ -0,0 +1,129 @@
+package cost
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	costexplorer "github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	types2 "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func costOutput(amount string) *costexplorer.GetCostAndUsageOutput {
+	return &costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []types2.ResultByTime{{
+			Total: map[string]types2.MetricValue{
+				"NetUnblendedCost": {Amount: aws.String(amount), Unit: aws.String("USD")},
+			},
+		}},
+	}
+}
+
+func TestAccountCostsConcurrency(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAWS := mock.NewMockClient(mockCtrl)
+
+	var inFlight, maxInFlight atomic.Int32
+	mockAWS.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(
+		func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				highest := maxInFlight.Load()
+				if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			if input.Filter.Dimensions.Values[0] == "3" {
+				return nil, errors.New("throttled")
+			}
+			return costOutput("10.00"), nil
+		}).Times(6)
+
+	accounts := []*string{aws.String("1"), aws.String("2"), aws.String("3"), aws.String("4"), aws.String("5"), aws.String("6")}
+	f := &costFetcher{concurrency: 2, entries: map[string]costCacheEntry{}}
+	costs, err := f.accountCosts(&getOptions{start: "2025-01-01", end: "2025-01-31"}, accounts, mockAWS)
+
+	assert.EqualError(t, err, "failed to get the cost of account 3: throttled")
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	var ids []string
+	for _, c := range costs {
+		ids = append(ids, c.AccountID)
+		assert.True(t, decimal.NewFromInt(10).Equal(c.Cost))
+		assert.Equal(t, "USD", c.Unit)
+	}
+	assert.Equal(t, []string{"1", "2", "4", "5", "6"}, ids)
+}
+
+func TestAccountCostsCache(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "cost", "costs.json")
+	opts := &getOptions{start: "2025-01-01", end: "2025-01-31"}
+	accounts := []*string{aws.String("111111111111"), aws.String("222222222222")}
+
+	mockCtrl := gomock.NewController(t)
+	mockAWS := mock.NewMockClient(mockCtrl)
+	mockAWS.EXPECT().GetCostAndUsage(gomock.Any()).Return(costOutput("100.00"), nil).Times(2)
+
+	// The costs are fetched once per command
+	f := &costFetcher{concurrency: 5, cacheFile: cacheFile, cacheTTL: time.Hour, entries: map[string]costCacheEntry{}}
+	for i := 0; i < 2; i++ {
+		costs, err := f.accountCosts(opts, accounts, mockAWS)
+		require.NoError(t, err)
+		assert.Len(t, costs, 2)
+	}
+	require.NoError(t, f.save())
+
+	// and reused by the next commands until they expire
+	cached := &costFetcher{concurrency: 5, cacheFile: cacheFile, cacheTTL: time.Hour, entries: map[string]costCacheEntry{}}
+	require.NoError(t, cached.load())
+	costs, err := cached.accountCosts(opts, accounts, mockAWS)
+	require.NoError(t, err)
+	require.Len(t, costs, 2)
+	assert.Equal(t, "111111111111", costs[0].AccountID)
+	assert.True(t, decimal.NewFromInt(100).Equal(costs[0].Cost))
+
+	expired := &costFetcher{cacheFile: cacheFile, cacheTTL: -time.Second, entries: map[string]costCacheEntry{}}
+	require.NoError(t, expired.load())
+	assert.Empty(t, expired.entries)
+
+	// Another time period isn't cached
+	other := &getOptions{start: "2025-02-01", end: "2025-02-28"}
+	mockAWS.EXPECT().GetCostAndUsage(gomock.Any()).Return(costOutput("5.00"), nil).Times(2)
+	_, err = cached.accountCosts(other, accounts, mockAWS)
+	require.NoError(t, err)
+}
+
+func TestCostCacheLoad(t *testing.T) {
+	f := &costFetcher{cacheFile: filepath.Join(t.TempDir(), "missing.json"), entries: map[string]costCacheEntry{}}
+	assert.NoError(t, f.load())
+
+	require.NoError(t, os.WriteFile(f.cacheFile, []byte("{"), 0600))
+	assert.NoError(t, f.load(), "a corrupted cache is ignored")
+	assert.Empty(t, f.entries)
+}
+
+func TestNewCostFetcher(t *testing.T) {
+	_, err := (&costOptions{concurrency: 0}).newCostFetcher()
+	assert.EqualError(t, err, "--concurrency must be at least 1, got 0")
+
+	f, err := (&costOptions{concurrency: 3}).newCostFetcher()
+	require.NoError(t, err)
+	assert.Empty(t, f.cacheFile)
+	assert.NoError(t, f.save(), "nothing is saved without --cache")
+
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	f, err = (&costOptions{concurrency: 3, cache: true}).newCostFetcher()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataHome, "osdctl", "cost", "costs.json"), f.cacheFile)
+}
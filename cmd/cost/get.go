@@ -77,6 +77,7 @@ type getOptions struct {
 	csv       bool
 	sum       bool
 	output    string
+	fetcher   *costFetcher
 
 	genericclioptions.IOStreams
 	GlobalOptions *globalflags.GlobalOptions
@@ -107,6 +108,9 @@ func (o *getOptions) run() error {
 	if err != nil {
 		return err
 	}
+	if o.fetcher, err = opsCost.newCostFetcher(); err != nil {
+		return err
+	}
 
 	//Get information regarding Organizational Unit
 	OU := getOU(awsClient, o.ou)
@@ -124,6 +128,10 @@ func (o *getOptions) run() error {
 		}
 	}
 
+	if err := o.fetcher.save(); err != nil {
+		log.Println("Error saving the cost cache:", err)
+	}
+
 	print, err := o.getCostOutput(cost, unit, o, OU)
 	if err != nil {
 		log.Println("Error calling printCostGet(): ", err.Error())
@@ -235,10 +243,8 @@ func getOUsRecursive(OU *organizationTypes.OrganizationalUnit, awsClient awsprov
 	return OUs, nil
 }
 
-// Get cost of given account
-func (o *getOptions) getAccountCost(accountID *string, unit *string, awsClient awsprovider.Client, cost *decimal.Decimal) error {
-
-	var start, end, granularity string
+// Get the time period and granularity of the costs based on the time or the date range flags
+func (o *getOptions) timePeriod() (start string, end string, granularity string) {
 	if o.time != "" {
 		start, end = getTimePeriod(&o.time)
 		granularity = "MONTHLY"
@@ -250,6 +256,22 @@ func (o *getOptions) getAccountCost(accountID *string, unit *string, awsClient a
 		granularity = "DAILY"
 	}
 
+	return start, end, granularity
+}
+
+// Get the fetcher of the costs, without cache when the options have none
+func (o *getOptions) costFetcher() *costFetcher {
+	if o.fetcher == nil {
+		o.fetcher = &costFetcher{concurrency: defaultCostConcurrency, entries: map[string]costCacheEntry{}}
+	}
+	return o.fetcher
+}
+
+// Get cost of given account
+func (o *getOptions) getAccountCost(accountID *string, unit *string, awsClient awsprovider.Client, cost *decimal.Decimal) error {
+
+	start, end, granularity := o.timePeriod()
+
 	metrics := []string{
 		"NetUnblendedCost",
 	}
@@ -296,11 +318,20 @@ func (o *getOptions) getOUCost(cost *decimal.Decimal, unit *string, OU *organiza
 		return err
 	}
 
+	return o.addAccountsCost(cost, unit, accounts, awsClient)
+}
+
+// Add the costs of the given accounts, fetched concurrently, to cost
+func (o *getOptions) addAccountsCost(cost *decimal.Decimal, unit *string, accounts []*string, awsClient awsprovider.Client) error {
+	costs, err := o.costFetcher().accountCosts(o, accounts, awsClient)
+	if err != nil {
+		return err
+	}
+
 	//Increment costs of accounts
-	for _, account := range accounts {
-		if err := o.getAccountCost(account, unit, awsClient, cost); err != nil {
-			return err
-		}
+	for _, accountCost := range costs {
+		*cost = cost.Add(accountCost.Cost)
+		*unit = accountCost.Unit
 	}
 
 	return nil
@@ -308,25 +339,39 @@ func (o *getOptions) getOUCost(cost *decimal.Decimal, unit *string, OU *organiza
 
 // Get cost of given OU by aggregating costs of all (including immediate) accounts under OU
 func (o *getOptions) getOUCostRecursive(cost *decimal.Decimal, unit *string, OU *organizationTypes.OrganizationalUnit, awsClient awsprovider.Client) error {
+	//Walk the OU tree first, so that the costs of all its accounts are fetched concurrently
+	accounts, err := getAccountsUnderOU(OU, awsClient)
+	if err != nil {
+		return err
+	}
+
+	return o.addAccountsCost(cost, unit, accounts, awsClient)
+}
+
+// Get the account IDs of all (not only immediate) accounts under OU, failing when an OU can't be listed
+func getAccountsUnderOU(OU *organizationTypes.OrganizationalUnit, awsClient awsprovider.Client) ([]*string, error) {
 	//Populate OUs
 	OUs, err := getOUs(OU, awsClient)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	//Loop through all child OUs, get their costs, and store it to cost of current OU
+	var accounts []*string
 	for _, childOU := range OUs {
-		if err := o.getOUCostRecursive(cost, unit, childOU, awsClient); err != nil {
-			return err
+		childAccounts, err := getAccountsUnderOU(childOU, awsClient)
+		if err != nil {
+			return nil, err
 		}
+		accounts = append(accounts, childAccounts...)
 	}
 
-	//Return cost of child OUs + cost of immediate accounts under current OU
-	if err := o.getOUCost(cost, unit, OU, awsClient); err != nil {
-		return err
+	//Add the immediate accounts under current OU
+	ouAccounts, err := getAccounts(OU, awsClient)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return append(accounts, ouAccounts...), nil
 }
 
 // Get time period based on time flag
@@ -80,6 +80,8 @@ type listOptions struct {
 	csv    bool
 	sum    bool
 	output string
+	// fetcher fetches the costs of the accounts, shared by all the OUs so that each account is fetched once
+	fetcher *costFetcher
 
 	genericclioptions.IOStreams
 	GlobalOptions *globalflags.GlobalOptions
@@ -119,6 +121,13 @@ func newListOptions(streams genericclioptions.IOStreams, globalOpts *globalflags
 func (o *listOptions) runList() error {
 	awsClient, err := opsCost.initAWSClients()
 	cmdutil.CheckErr(err)
+	o.fetcher, err = opsCost.newCostFetcher()
+	cmdutil.CheckErr(err)
+	defer func() {
+		if err := o.fetcher.save(); err != nil {
+			log.Println("Error saving the cost cache:", err)
+		}
+	}()
 
 	printHeader(o)
 
@@ -175,10 +184,11 @@ func listCostsUnderOU(OU *types.OrganizationalUnit, awsClient awsprovider.Client
 	var isChildNode bool
 
 	o := &getOptions{
-		time:  ops.time,
-		start: ops.start,
-		end:   ops.end,
-		ou:    *OU.Id,
+		time:    ops.time,
+		start:   ops.start,
+		end:     ops.end,
+		ou:      *OU.Id,
+		fetcher: ops.fetcher,
 	}
 	if err := o.getOUCostRecursive(&cost, &unit, OU, awsClient); err != nil {
 		return err
@@ -221,23 +231,17 @@ func (o *OUCost) getCost(awsClient awsprovider.Client) error {
 	}
 
 	ops := &getOptions{
-		time:  o.options.time,
-		start: o.options.start,
-		end:   o.options.end,
-		ou:    *o.OU.Id,
+		time:    o.options.time,
+		start:   o.options.start,
+		end:     o.options.end,
+		ou:      *o.OU.Id,
+		fetcher: o.options.fetcher,
 	}
 
-	for _, account := range accounts {
-		accCost := AccountCost{
-			AccountID: *account,
-			Unit:      "",
-			Cost:      decimal.Zero,
-		}
-		err = ops.getAccountCost(account, &accCost.Unit, awsClient, &accCost.Cost)
-		if err != nil {
-			return err
-		}
-		o.Costs = append(o.Costs, accCost)
+	costs, err := ops.costFetcher().accountCosts(ops, accounts, awsClient)
+	o.Costs = append(o.Costs, costs...)
+	if err != nil {
+		return err
 	}
 
 	sort.Slice(o.Costs, func(i, j int) bool {
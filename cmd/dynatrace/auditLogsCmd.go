@@ -0,0 +1,208 @@
+package dynatrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	dtclient "github.com/openshift/osdctl/pkg/dynatrace"
+	"github.com/spf13/cobra"
+)
+
+// maxAuditLogsPageSize is the maximum number of records returned by a query
+const maxAuditLogsPageSize = dtclient.MaxResultRecords
+
+type auditLogsOptions struct {
+	hcpEventsOptions
+	pageSize int
+	limit    int
+
+	// query runs the DQL query and returns its records, replaced in tests
+	query func(ctx context.Context, query string) ([]json.RawMessage, error)
+}
+
+// auditLogRecord is the part of a {timestamp, audit} record used to page through the events
+type auditLogRecord struct {
+	Timestamp string `json:"timestamp"`
+	Audit     struct {
+		AuditID string `json:"auditID"`
+	} `json:"audit"`
+}
+
+func newCmdAuditLogs() *cobra.Command {
+	opts := &auditLogsOptions{}
+
+	auditLogsCmd := &cobra.Command{
+		Use:   "audit-logs --cluster-id <cluster-identifier>",
+		Short: "Export the kube-apiserver audit log of a HCP from Dynatrace as JSON lines",
+		Long: `Export the kube-apiserver audit log of a HCP from Dynatrace as JSON lines.
+
+  The audit log lines of the HCP namespace on the management cluster are parsed and filtered
+  by user, verb and resource, like hcp-events. The events are fetched page by page in
+  chronological order, so exports aren't capped by the maximum size of a Dynatrace query
+  result, and written as one {timestamp, audit} JSON record per line, ready for jq or audit tooling.
+
+  The DQL of every page is printed on stderr.`,
+		Example: `
+  # Export the audit log of the last hour of a HCP
+  osdctl dt audit-logs --cluster-id ${CLUSTER_ID} > audit.jsonl
+
+  # Find who deleted secrets during the last day
+  osdctl dt audit-logs --cluster-id ${CLUSTER_ID} --since 24 --verb delete --resource secrets | jq -r '.audit.user.username'
+
+  # Export the requests of a user within a time range to a file
+  osdctl dt audit-logs --cluster-id ${CLUSTER_ID} --user system:admin --from "2025-06-15 04:00" --to "2025-06-15 06:00" --output-file audit.jsonl`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context(), cmd.ErrOrStderr())
+		},
+	}
+
+	auditLogsCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Name or Internal ID of the HCP cluster (required)")
+	auditLogsCmd.Flags().IntVar(&opts.since, "since", 1, "Number of hours (integer) since which to search")
+	auditLogsCmd.Flags().TimeVar(&opts.from, "from", time.Time{}, []string{time.RFC3339, "2006-01-02 15:04"}, "Datetime from which to filter events, in the format \"YYYY-MM-DD HH:MM\"")
+	auditLogsCmd.Flags().TimeVar(&opts.to, "to", time.Time{}, []string{time.RFC3339, "2006-01-02 15:04"}, "Datetime until which to filter events, in the format \"YYYY-MM-DD HH:MM\"")
+	auditLogsCmd.Flags().StringSliceVar(&opts.users, "user", []string{}, "User name(s) performing the requests (comma-separated)")
+	auditLogsCmd.Flags().StringSliceVar(&opts.verbs, "verb", []string{}, "Verb(s) of the requests, e.g. get, create, update, patch, delete (comma-separated)")
+	auditLogsCmd.Flags().StringSliceVar(&opts.resources, "resource", []string{}, "Resource(s) targeted by the requests, e.g. pods, secrets (comma-separated)")
+	auditLogsCmd.Flags().IntVar(&opts.pageSize, "page-size", 5000, fmt.Sprintf("Number of events fetched per query, at most %d", maxAuditLogsPageSize))
+	auditLogsCmd.Flags().IntVar(&opts.limit, "limit", 0, "Maximum number of events to export, 0 exports all the events")
+	auditLogsCmd.Flags().StringVar(&opts.outputFile, "output-file", "", "File to write the JSON lines to, defaults to stdout")
+	auditLogsCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only builds the query of the first page without fetching any events from the tenant")
+	auditLogsCmd.MarkFlagsRequiredTogether("from", "to")
+	auditLogsCmd.MarkFlagsMutuallyExclusive("since", "from")
+	auditLogsCmd.MarkFlagsMutuallyExclusive("since", "to")
+	_ = auditLogsCmd.MarkFlagRequired("cluster-id")
+
+	return auditLogsCmd
+}
+
+func (o *auditLogsOptions) validate() error {
+	// Pages are fetched in chronological order
+	o.sortOrder = "asc"
+	if err := o.hcpEventsOptions.validate(); err != nil {
+		return err
+	}
+	if o.pageSize <= 0 || o.pageSize > maxAuditLogsPageSize {
+		return fmt.Errorf("--page-size must be between 1 and %d", maxAuditLogsPageSize)
+	}
+	if o.limit < 0 {
+		return fmt.Errorf("--limit can't be negative")
+	}
+	return nil
+}
+
+func (o *auditLogsOptions) run(ctx context.Context, logOut io.Writer) error {
+	hcpCluster, err := FetchClusterDetails(o.clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to acquire cluster details %v", err)
+	}
+	if hcpCluster.hcpNamespace == "" {
+		return fmt.Errorf("cluster %s is not a HCP, audit logs are only available for hosted control planes", o.clusterID)
+	}
+
+	if o.dryRun {
+		o.tail = o.pageSize
+		query, err := o.buildQuery(hcpCluster.managementClusterName, hcpCluster.hcpNamespace)
+		if err != nil {
+			return fmt.Errorf("failed to build query for Dynatrace %v", err)
+		}
+		fmt.Fprintln(logOut, query.Build())
+		return nil
+	}
+
+	client, err := newStorageClient(hcpCluster.DynatraceURL)
+	if err != nil {
+		return err
+	}
+	o.query = func(ctx context.Context, query string) ([]json.RawMessage, error) {
+		return dtclient.QueryRecords(ctx, client, query)
+	}
+
+	w := o.out
+	if o.outputFile != "" {
+		f, err := os.OpenFile(o.outputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	written, err := o.export(ctx, w, logOut, hcpCluster.managementClusterName, hcpCluster.hcpNamespace)
+	if o.outputFile != "" {
+		fmt.Fprintf(logOut, "Wrote %d events to %s\n", written, o.outputFile)
+	}
+	return err
+}
+
+// export writes the events page by page as JSON lines and returns how many were written. Each page starts at the
+// timestamp of the last event of the previous one, and the events of that timestamp already written are skipped.
+func (o *auditLogsOptions) export(ctx context.Context, w io.Writer, logOut io.Writer, managementClusterName string, hcpNamespace string) (int, error) {
+	var written int
+	// The audit IDs of the events written with the timestamp the next page starts at
+	lastIDs := map[string]bool{}
+	for {
+		o.tail = o.pageSize
+		query, err := o.buildQuery(managementClusterName, hcpNamespace)
+		if err != nil {
+			return written, fmt.Errorf("failed to build query for Dynatrace %v", err)
+		}
+		fmt.Fprintln(logOut, query.Build())
+
+		records, err := o.query(ctx, query.finalQuery)
+		if err != nil {
+			return written, err
+		}
+
+		var pageWritten int
+		for _, raw := range records {
+			var record auditLogRecord
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return written, fmt.Errorf("failed to parse the audit event %s: %w", raw, err)
+			}
+			timestamp, err := time.Parse(time.RFC3339Nano, record.Timestamp)
+			if err != nil {
+				return written, fmt.Errorf("failed to parse the timestamp of the audit event %s: %w", record.Audit.AuditID, err)
+			}
+			if timestamp.Equal(o.after) && lastIDs[record.Audit.AuditID] {
+				continue
+			}
+
+			line := &bytes.Buffer{}
+			if err := json.Compact(line, raw); err != nil {
+				return written, err
+			}
+			if _, err := fmt.Fprintln(w, line.String()); err != nil {
+				return written, err
+			}
+			written++
+			pageWritten++
+
+			if !timestamp.Equal(o.after) {
+				o.after = timestamp
+				lastIDs = map[string]bool{}
+			}
+			lastIDs[record.Audit.AuditID] = true
+
+			if o.limit > 0 && written >= o.limit {
+				return written, nil
+			}
+		}
+
+		if len(records) < o.pageSize {
+			return written, nil
+		}
+		if pageWritten == 0 {
+			return written, fmt.Errorf("more than %d events were logged at %s, increase --page-size to export them", o.pageSize, o.after.Format(time.RFC3339Nano))
+		}
+	}
+}
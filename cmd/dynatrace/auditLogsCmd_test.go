@@ -0,0 +1,119 @@
+package dynatrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func auditRecord(timestamp string, auditID string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{
+  "timestamp": %q,
+  "audit": {"auditID": %q, "verb": "delete"}
+}`, timestamp, auditID))
+}
+
+// pagedQuery returns the pages in sequence and records the queries
+func pagedQuery(pages [][]json.RawMessage, queries *[]string) func(context.Context, string) ([]json.RawMessage, error) {
+	return func(_ context.Context, query string) ([]json.RawMessage, error) {
+		*queries = append(*queries, query)
+		if len(*queries) > len(pages) {
+			return nil, nil
+		}
+		return pages[len(*queries)-1], nil
+	}
+}
+
+func TestAuditLogsExport(t *testing.T) {
+	pages := [][]json.RawMessage{
+		{auditRecord("2025-06-15T04:00:00.000000001Z", "a"), auditRecord("2025-06-15T04:00:00.000000002Z", "b"), auditRecord("2025-06-15T04:00:00.000000002Z", "c")},
+		// The next page starts at the timestamp of the last event, the events already written are skipped
+		{auditRecord("2025-06-15T04:00:00.000000002Z", "b"), auditRecord("2025-06-15T04:00:00.000000002Z", "c"), auditRecord("2025-06-15T04:00:00.000000002Z", "d")},
+		{auditRecord("2025-06-15T04:00:00.000000002Z", "d"), auditRecord("2025-06-15T04:00:01Z", "e")},
+	}
+	var queries []string
+	o := &auditLogsOptions{hcpEventsOptions: hcpEventsOptions{since: 1, sortOrder: "asc"}, pageSize: 3}
+	o.query = pagedQuery(pages, &queries)
+
+	out := &bytes.Buffer{}
+	written, err := o.export(context.Background(), out, io.Discard, "hs-mc-1", "ocm-production-abc-hcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if written != 5 {
+		t.Errorf("expected 5 events written, got %d", written)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var ids []string
+	for _, line := range lines {
+		var record auditLogRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %q is not a JSON record: %v", line, err)
+		}
+		ids = append(ids, record.Audit.AuditID)
+	}
+	if strings.Join(ids, ",") != "a,b,c,d,e" {
+		t.Errorf("expected the events a,b,c,d,e once each, got %v", ids)
+	}
+
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 queries, got %d", len(queries))
+	}
+	if strings.Contains(queries[0], "toTimestamp") {
+		t.Errorf("expected the first page to start at the beginning of the time range, got:\n%s", queries[0])
+	}
+	if !strings.Contains(queries[1], `and timestamp >= toTimestamp("2025-06-15T04:00:00.000000002Z")`) || !strings.HasSuffix(queries[1], "| limit 3") {
+		t.Errorf("expected the second page to start at the last event of the first one, got:\n%s", queries[1])
+	}
+}
+
+func TestAuditLogsExportLimit(t *testing.T) {
+	var queries []string
+	o := &auditLogsOptions{hcpEventsOptions: hcpEventsOptions{since: 1, sortOrder: "asc"}, pageSize: 2, limit: 3}
+	o.query = pagedQuery([][]json.RawMessage{
+		{auditRecord("2025-06-15T04:00:01Z", "a"), auditRecord("2025-06-15T04:00:02Z", "b")},
+		{auditRecord("2025-06-15T04:00:02Z", "b"), auditRecord("2025-06-15T04:00:03Z", "c")},
+	}, &queries)
+
+	written, err := o.export(context.Background(), io.Discard, io.Discard, "hs-mc-1", "ocm-production-abc-hcp")
+	if err != nil || written != 3 {
+		t.Errorf("expected 3 events written, got %d (error: %v)", written, err)
+	}
+}
+
+func TestAuditLogsExportStuckPage(t *testing.T) {
+	var queries []string
+	o := &auditLogsOptions{hcpEventsOptions: hcpEventsOptions{since: 1, sortOrder: "asc"}, pageSize: 2}
+	page := []json.RawMessage{auditRecord("2025-06-15T04:00:01Z", "a"), auditRecord("2025-06-15T04:00:01Z", "b")}
+	o.query = pagedQuery([][]json.RawMessage{page, page}, &queries)
+
+	_, err := o.export(context.Background(), io.Discard, io.Discard, "hs-mc-1", "ocm-production-abc-hcp")
+	if err == nil || !strings.Contains(err.Error(), "increase --page-size") {
+		t.Errorf("expected an error for a page of events of a single timestamp, got %v", err)
+	}
+}
+
+func TestAuditLogsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        auditLogsOptions
+		expectError bool
+	}{
+		{"valid", auditLogsOptions{hcpEventsOptions: hcpEventsOptions{since: 1}, pageSize: 100}, false},
+		{"page size too large", auditLogsOptions{hcpEventsOptions: hcpEventsOptions{since: 1}, pageSize: maxAuditLogsPageSize + 1}, true},
+		{"negative limit", auditLogsOptions{hcpEventsOptions: hcpEventsOptions{since: 1}, pageSize: 100, limit: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.validate(); (err != nil) != tt.expectError {
+				t.Errorf("expected error: %v, got: %v", tt.expectError, err)
+			}
+		})
+	}
+}
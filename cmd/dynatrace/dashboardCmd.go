@@ -6,8 +6,8 @@ import (
 	"runtime"
 
 	ocmutils "github.com/openshift/ocm-container/pkg/utils"
+	dtclient "github.com/openshift/osdctl/pkg/dynatrace"
 	"github.com/spf13/cobra"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
 var (
@@ -45,25 +45,24 @@ func newCmdDashboard() *cobra.Command {
 		Example: `  # Open the Dynatrace dashboard for a cluster
   osdctl dynatrace dashboard --cluster-id ${CLUSTER_ID}`,
 		DisableAutoGenTag: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// We need the Dynatrace URL
 			hcpCluster, err := FetchClusterDetails(clusterId)
 			if err != nil {
-				cmdutil.CheckErr(err)
+				return err
 			}
 
 			// Get credentials
-			accessToken, err := getDocumentAccessToken()
+			accessToken, err := dtclient.GetDocumentAccessToken()
 			if err != nil {
-				fmt.Printf("Could not get access token %s\n", err)
-				return
+				return fmt.Errorf("could not get access token: %w", err)
 			}
 
 			// Search for the dashboard
-			id, err := getDocumentIDByNameAndType(hcpCluster.DynatraceURL, accessToken, dashboardName, DTDashboardType)
+			client := newDTClient(hcpCluster.DynatraceURL, dtclient.StaticToken(accessToken))
+			id, err := client.GetDocumentIDByNameAndType(cmd.Context(), dashboardName, dtclient.DashboardType)
 			if err != nil {
-				fmt.Printf("Could not find dashboard named '%s': %s\n", dashboardName, err)
-				return
+				return fmt.Errorf("could not find dashboard named '%s': %w", dashboardName, err)
 			}
 
 			// Tell the user
@@ -80,6 +79,7 @@ func newCmdDashboard() *cobra.Command {
 			} else {
 				fmt.Println("\nRunning in container mode - open the URL above in your host browser.")
 			}
+			return nil
 		},
 	}
 
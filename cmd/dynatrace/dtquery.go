@@ -167,6 +167,59 @@ func (q *DTQuery) Deployments(workloads []string) *DTQuery {
 	return q
 }
 
+// ParseAuditEvents parses the content of Kubernetes audit log lines into the audit record,
+// dropping the lines which aren't audit events. It must be added after the log filters.
+func (q *DTQuery) ParseAuditEvents() *DTQuery {
+	q.fragments = append(q.fragments, "\n| parse content, \"JSON:audit\"\n| filter isNotNull(audit[auditID])")
+
+	return q
+}
+
+func (q *DTQuery) AuditVerbs(verbs []string) *DTQuery {
+	q.fragments = append(q.fragments, auditFieldFilter("audit[verb]", verbs))
+
+	return q
+}
+
+func (q *DTQuery) AuditUsers(users []string) *DTQuery {
+	q.fragments = append(q.fragments, auditFieldFilter("audit[user][username]", users))
+
+	return q
+}
+
+func (q *DTQuery) AuditResources(resources []string) *DTQuery {
+	q.fragments = append(q.fragments, auditFieldFilter("audit[objectRef][resource]", resources))
+
+	return q
+}
+
+// AuditAfter only keeps the events logged at or after the time, with a nanosecond precision
+func (q *DTQuery) AuditAfter(after time.Time) *DTQuery {
+	q.fragments = append(q.fragments, fmt.Sprintf(" and timestamp >= toTimestamp(\"%s\")", after.UTC().Format(time.RFC3339Nano)))
+
+	return q
+}
+
+// AuditFields only keeps the timestamp and the parsed audit record of each event
+func (q *DTQuery) AuditFields() *DTQuery {
+	q.fragments = append(q.fragments, "\n| fields timestamp, audit")
+
+	return q
+}
+
+func auditFieldFilter(field string, values []string) string {
+	filter := " and ("
+	for i, value := range values {
+		filter += fmt.Sprintf("%s == \"%s\"", field, value)
+		if i < len(values)-1 {
+			filter += " or "
+		}
+	}
+	filter += ")"
+
+	return filter
+}
+
 func (q *DTQuery) Limit(limit int) *DTQuery {
 	q.fragments = append(q.fragments, "\n| limit "+fmt.Sprint(limit))
 
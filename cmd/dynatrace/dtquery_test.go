@@ -219,3 +219,18 @@ func TestDTQuery_InitLogsWithTimeRange(t *testing.T) {
 		})
 	}
 }
+
+func TestDTQuery_AuditFilters(t *testing.T) {
+	q := new(DTQuery).InitLogs(1).ParseAuditEvents().AuditVerbs([]string{"delete", "patch"}).AuditUsers([]string{"system:admin"}).AuditResources([]string{"secrets"})
+	expected := []string{
+		"\n| parse content, \"JSON:audit\"\n| filter isNotNull(audit[auditID])",
+		` and (audit[verb] == "delete" or audit[verb] == "patch")`,
+		` and (audit[user][username] == "system:admin")`,
+		` and (audit[objectRef][resource] == "secrets")`,
+	}
+	for i, e := range expected {
+		if q.fragments[i+1] != e {
+			t.Errorf("expected: %s\ngot: %s", e, q.fragments[i+1])
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package dynatrace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	dtclient "github.com/openshift/osdctl/pkg/dynatrace"
+	"github.com/spf13/cobra"
+)
+
+// auditLogsContainer is the container of the HCP API server pods shipping the audit log
+const auditLogsContainer = "audit-logs"
+
+type hcpEventsOptions struct {
+	clusterID  string
+	since      int
+	from       time.Time
+	to         time.Time
+	verbs      []string
+	users      []string
+	resources  []string
+	sortOrder  string
+	tail       int
+	outputFile string
+	dryRun     bool
+	// after only keeps the events from this time on, to fetch the events page by page
+	after time.Time
+
+	out io.Writer
+}
+
+func newCmdHCPEvents() *cobra.Command {
+	opts := &hcpEventsOptions{}
+
+	hcpEventsCmd := &cobra.Command{
+		Use:   "hcp-events --cluster-id <cluster-identifier>",
+		Short: "Fetch the Kubernetes audit events of a HCP from Dynatrace",
+		Long: `Fetch the Kubernetes audit events of the API servers of a HCP from Dynatrace.
+
+  The audit log lines of the HCP namespace on the management cluster are parsed and filtered
+  by verb, user and resource, and exported as a JSON array of {timestamp, audit} records.
+
+  The DQL used is printed on stderr.`,
+		Example: `
+  # Get the audit events of the last hour of a HCP
+  osdctl dt hcp-events --cluster-id ${CLUSTER_ID}
+
+  # Get who deleted secrets or configmaps during the last 12 hours
+  osdctl dt hcp-events --cluster-id ${CLUSTER_ID} --since 12 --verb delete --resource secrets,configmaps
+
+  # Export the requests of a user within a time range to a file
+  osdctl dt hcp-events --cluster-id ${CLUSTER_ID} --user system:admin --from "2025-06-15 04:00" --to "2025-06-15 06:00" --output-file events.json
+
+  # Only print the DQL query
+  osdctl dt hcp-events --cluster-id ${CLUSTER_ID} --verb patch --dry-run`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.out = cmd.OutOrStdout()
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context(), cmd.ErrOrStderr())
+		},
+	}
+
+	hcpEventsCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Name or Internal ID of the HCP cluster (required)")
+	hcpEventsCmd.Flags().IntVar(&opts.since, "since", 1, "Number of hours (integer) since which to search")
+	hcpEventsCmd.Flags().TimeVar(&opts.from, "from", time.Time{}, []string{time.RFC3339, "2006-01-02 15:04"}, "Datetime from which to filter events, in the format \"YYYY-MM-DD HH:MM\"")
+	hcpEventsCmd.Flags().TimeVar(&opts.to, "to", time.Time{}, []string{time.RFC3339, "2006-01-02 15:04"}, "Datetime until which to filter events, in the format \"YYYY-MM-DD HH:MM\"")
+	hcpEventsCmd.Flags().StringSliceVar(&opts.verbs, "verb", []string{}, "Verb(s) of the requests, e.g. get, create, update, patch, delete (comma-separated)")
+	hcpEventsCmd.Flags().StringSliceVar(&opts.users, "user", []string{}, "User name(s) performing the requests (comma-separated)")
+	hcpEventsCmd.Flags().StringSliceVar(&opts.resources, "resource", []string{}, "Resource(s) targeted by the requests, e.g. pods, secrets (comma-separated)")
+	hcpEventsCmd.Flags().StringVar(&opts.sortOrder, "sort", "asc", "Sort the results by timestamp in either ascending or descending order. Accepted values are 'asc' and 'desc'.")
+	hcpEventsCmd.Flags().IntVar(&opts.tail, "tail", 1000, "Last 'n' events to fetch")
+	hcpEventsCmd.Flags().StringVar(&opts.outputFile, "output-file", "", "File to write the JSON events to, defaults to stdout")
+	hcpEventsCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only builds the query without fetching any events from the tenant")
+	hcpEventsCmd.MarkFlagsRequiredTogether("from", "to")
+	hcpEventsCmd.MarkFlagsMutuallyExclusive("since", "from")
+	hcpEventsCmd.MarkFlagsMutuallyExclusive("since", "to")
+	_ = hcpEventsCmd.MarkFlagRequired("cluster-id")
+
+	return hcpEventsCmd
+}
+
+func (o *hcpEventsOptions) validate() error {
+	if o.since <= 0 {
+		return fmt.Errorf("invalid time duration")
+	}
+	if !o.from.IsZero() && !o.to.IsZero() && o.to.Before(o.from) {
+		return fmt.Errorf("--to cannot be set to a datetime before --from")
+	}
+	if o.sortOrder != "asc" && o.sortOrder != "desc" {
+		return fmt.Errorf("invalid sort order, expecting 'asc' or 'desc'")
+	}
+	return nil
+}
+
+func (o *hcpEventsOptions) run(ctx context.Context, logOut io.Writer) error {
+	hcpCluster, err := FetchClusterDetails(o.clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to acquire cluster details %v", err)
+	}
+	if hcpCluster.hcpNamespace == "" {
+		return fmt.Errorf("cluster %s is not a HCP, audit events are only available for hosted control planes", o.clusterID)
+	}
+
+	query, err := o.buildQuery(hcpCluster.managementClusterName, hcpCluster.hcpNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to build query for Dynatrace %v", err)
+	}
+	fmt.Fprintln(logOut, query.Build())
+	if o.dryRun {
+		return nil
+	}
+
+	client, err := newStorageClient(hcpCluster.DynatraceURL)
+	if err != nil {
+		return err
+	}
+	records, err := dtclient.QueryRecords(ctx, client, query.finalQuery)
+	if err != nil {
+		return fmt.Errorf("failed to get events %v", err)
+	}
+
+	w := o.out
+	if o.outputFile != "" {
+		f, err := os.OpenFile(o.outputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeAuditEvents(w, records); err != nil {
+		return err
+	}
+	if o.outputFile != "" {
+		fmt.Fprintf(logOut, "Wrote %d events to %s\n", len(records), o.outputFile)
+	}
+	return nil
+}
+
+func (o *hcpEventsOptions) buildQuery(managementClusterName string, hcpNamespace string) (DTQuery, error) {
+	q := DTQuery{}
+	if !o.from.IsZero() && !o.to.IsZero() {
+		q.InitLogsWithTimeRange(o.from, o.to)
+	} else {
+		q.InitLogs(o.since)
+	}
+	q.Cluster(managementClusterName).
+		Namespaces([]string{hcpNamespace}).
+		Containers([]string{auditLogsContainer}).
+		ParseAuditEvents()
+
+	if len(o.verbs) > 0 {
+		q.AuditVerbs(o.verbs)
+	}
+	if len(o.users) > 0 {
+		q.AuditUsers(o.users)
+	}
+	if len(o.resources) > 0 {
+		q.AuditResources(o.resources)
+	}
+	if !o.after.IsZero() {
+		q.AuditAfter(o.after)
+	}
+	q.AuditFields()
+
+	if _, err := q.Sort(o.sortOrder); err != nil {
+		return q, err
+	}
+	if o.tail > 0 {
+		q.Limit(o.tail)
+	}
+
+	return q, nil
+}
+
+// writeAuditEvents writes the records as an indented JSON array
+func writeAuditEvents(w io.Writer, records []json.RawMessage) error {
+	if records == nil {
+		records = []json.RawMessage{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
@@ -0,0 +1,103 @@
+package dynatrace
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHCPEventsBuildQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     hcpEventsOptions
+		expected string
+	}{
+		{
+			name: "No filters",
+			opts: hcpEventsOptions{since: 2, sortOrder: "asc"},
+			expected: `fetch logs, from:now()-2h 
+| filter matchesValue(event.type, "LOG") and matchesPhrase(dt.kubernetes.cluster.name, "hs-mc-1") and (matchesValue(k8s.namespace.name, "ocm-production-abc-hcp")) and (matchesValue(k8s.container.name, "audit-logs"))
+| parse content, "JSON:audit"
+| filter isNotNull(audit[auditID])
+| fields timestamp, audit
+| sort timestamp asc`,
+		},
+		{
+			name: "Time range with filters",
+			opts: hcpEventsOptions{
+				from:      time.Date(2025, 6, 15, 4, 0, 0, 0, time.UTC),
+				to:        time.Date(2025, 6, 15, 6, 0, 0, 0, time.UTC),
+				verbs:     []string{"delete"},
+				users:     []string{"system:admin"},
+				resources: []string{"secrets", "configmaps"},
+				sortOrder: "desc",
+				tail:      10,
+			},
+			expected: `fetch logs, from:"2025-06-15T04:00:00Z", to:"2025-06-15T06:00:00Z" 
+| filter matchesValue(event.type, "LOG") and matchesPhrase(dt.kubernetes.cluster.name, "hs-mc-1") and (matchesValue(k8s.namespace.name, "ocm-production-abc-hcp")) and (matchesValue(k8s.container.name, "audit-logs"))
+| parse content, "JSON:audit"
+| filter isNotNull(audit[auditID]) and (audit[verb] == "delete") and (audit[user][username] == "system:admin") and (audit[objectRef][resource] == "secrets" or audit[objectRef][resource] == "configmaps")
+| fields timestamp, audit
+| sort timestamp desc
+| limit 10`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := tt.opts.buildQuery("hs-mc-1", "ocm-production-abc-hcp")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := q.Build(); got != tt.expected {
+				t.Errorf("expected: %s\ngot: %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHCPEventsValidate(t *testing.T) {
+	from := time.Date(2025, 6, 15, 4, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		opts        hcpEventsOptions
+		expectError bool
+	}{
+		{"valid", hcpEventsOptions{since: 1, sortOrder: "asc"}, false},
+		{"invalid since", hcpEventsOptions{since: 0, sortOrder: "asc"}, true},
+		{"invalid sort", hcpEventsOptions{since: 1, sortOrder: "up"}, true},
+		{"to before from", hcpEventsOptions{since: 1, sortOrder: "asc", from: from, to: from.Add(-time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.validate(); (err != nil) != tt.expectError {
+				t.Errorf("expected error: %v, got: %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestWriteAuditEvents(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := writeAuditEvents(out, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "[]\n" {
+		t.Errorf("expected an empty JSON array, got: %s", out.String())
+	}
+
+	out.Reset()
+	records := []json.RawMessage{json.RawMessage(`{"timestamp":"2025-06-15T04:00:00Z","audit":{"verb":"delete"}}`)}
+	if err := writeAuditEvents(out, records); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0]["audit"].(map[string]interface{})["verb"] != "delete" {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
@@ -3,12 +3,17 @@ package dynatrace
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/openshift/osdctl/cmd/common"
+	dtclient "github.com/openshift/osdctl/pkg/dynatrace"
+	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -16,7 +21,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
 type GatherLogsOpts struct {
@@ -25,6 +29,21 @@ type GatherLogsOpts struct {
 	SortOrder string
 	DestDir   string
 	ClusterID string
+	// QueryOnly prints or writes the DQL queries of the gathering instead of executing them
+	QueryOnly bool
+	// MaxRecords limits the records fetched by each query, 0 for no limit
+	MaxRecords int
+	// MaxBytesPerPod limits the size of the logs written for each pod, 0 for no limit
+	MaxBytesPerPod int64
+
+	// truncated are the queries whose results were cut by the limits
+	truncated []truncatedQuery
+}
+
+// truncatedQuery is a dump file whose query results were cut by the limits of the gathering
+type truncatedQuery struct {
+	file   string
+	reason string
 }
 
 func NewCmdHCPMustGather() *cobra.Command {
@@ -38,17 +57,33 @@ func NewCmdHCPMustGather() *cobra.Command {
 
   This command fetches the logs from the HCP namespace, the hypershift namespace and cert-manager related namespaces.
   Logs will be dumped to a directory with prefix hcp-must-gather.
+
+  Interrupting the command (Ctrl+C) stops the in-flight queries and keeps the logs gathered so far,
+  which are marked as partial in the dump directory.
+
+  The gathering can be bounded with --max-records, the maximum number of records fetched by each query, and
+  --max-bytes-per-pod, the maximum size of the logs written for each pod and for the restarted pods of a namespace.
+  A summary of the files written, their total size and the queries truncated by these limits is printed at the end.
+
+  With --query-only, the pod, event and restarted pod DQL queries are generated but not executed, so they can be
+  run or adjusted in the Dynatrace UI. They are printed along with the Dynatrace tenant URL, or written as .dql files
+  in the dump directory when --dest-dir is set.
 		`,
 		Example: `
   # Gather logs for a HCP cluster with cluster id hcp-cluster-id-123
-  osdctl dt gather-logs --cluster-id hcp-cluster-id-123`,
+  osdctl dt gather-logs --cluster-id hcp-cluster-id-123
+
+  # Gather at most 10000 records per query and 50MiB of logs per pod
+  osdctl dt gather-logs --cluster-id hcp-cluster-id-123 --max-records 10000 --max-bytes-per-pod 52428800
+
+  # Print the DQL queries of the gathering without executing them
+  osdctl dt gather-logs --cluster-id hcp-cluster-id-123 --query-only`,
 		DisableAutoGenTag: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := utils.NotifyInterrupt(cmd.Context(), cmd.ErrOrStderr())
+			defer stop()
 
-			err := g.GatherLogs(g.ClusterID, "")
-			if err != nil {
-				cmdutil.CheckErr(err)
-			}
+			return g.GatherLogs(ctx, g.ClusterID, "")
 		},
 	}
 
@@ -57,21 +92,43 @@ func NewCmdHCPMustGather() *cobra.Command {
 	hcpMgCmd.Flags().StringVar(&g.SortOrder, "sort", "asc", "Sort the results by timestamp in either ascending or descending order. Accepted values are 'asc' and 'desc'")
 	hcpMgCmd.Flags().StringVar(&g.DestDir, "dest-dir", "", "Destination directory for the logs dump, defaults to the local directory.")
 	hcpMgCmd.Flags().StringVarP(&g.ClusterID, "cluster-id", "C", "", "Internal ID of the HCP cluster to gather logs from (required)")
+	hcpMgCmd.Flags().BoolVar(&g.QueryOnly, "query-only", false, "Print the DQL queries with the Dynatrace tenant URL instead of executing them (written as .dql files with --dest-dir)")
 
+	hcpMgCmd.Flags().IntVar(&g.MaxRecords, "max-records", 0, "Maximum number of records fetched by each log and event query, 0 for no limit")
+	hcpMgCmd.Flags().Int64Var(&g.MaxBytesPerPod, "max-bytes-per-pod", defaultMaxBytesPerPod, "Maximum size in bytes of the logs written for each pod, 0 for no limit")
 	_ = hcpMgCmd.MarkFlagRequired("cluster-id")
 
 	return hcpMgCmd
 }
 
-func (g *GatherLogsOpts) GatherLogs(clusterID string, elevationReasons ...string) (error error) {
-	tokenProvider, err := getStorageTokenProvider()
-	if err != nil {
-		return fmt.Errorf("failed to setup Dynatrace access token provider (is the vault CLI installed and configured?): %v", err)
+const (
+	// partialGatherFileName is written to the dump directory when the gathering is interrupted
+	partialGatherFileName = "PARTIAL"
+
+	// defaultMaxBytesPerPod keeps the logs of a chatty pod from filling the disk
+	defaultMaxBytesPerPod = 100 * 1024 * 1024
+)
+
+func (g *GatherLogsOpts) GatherLogs(ctx context.Context, clusterID string, elevationReasons ...string) (error error) {
+	if g.MaxRecords < 0 {
+		return fmt.Errorf("--max-records must not be negative")
+	}
+	if g.MaxBytesPerPod < 0 {
+		return fmt.Errorf("--max-bytes-per-pod must not be negative")
 	}
 
-	// Eagerly fetch the first token to fail fast on auth issues
-	if _, err := tokenProvider.Token(); err != nil {
-		return fmt.Errorf("failed to acquire access token: %v", err)
+	var tokenProvider utils.AccessTokenProvider
+	if !g.QueryOnly {
+		provider, err := dtclient.NewStorageTokenProvider()
+		if err != nil {
+			return fmt.Errorf("failed to setup Dynatrace access token provider (is the vault CLI installed and configured?): %v", err)
+		}
+		tokenProvider = provider
+
+		// Eagerly fetch the first token to fail fast on auth issues
+		if _, err := tokenProvider.Token(); err != nil {
+			return fmt.Errorf("failed to acquire access token: %w", err)
+		}
 	}
 
 	hcpCluster, err := FetchClusterDetails(clusterID)
@@ -88,16 +145,27 @@ func (g *GatherLogsOpts) GatherLogs(clusterID string, elevationReasons ...string
 
 	gatherNamespaces := []string{hcpCluster.hcpNamespace, hcpCluster.klusterletNS, hcpCluster.hostedNS, "hypershift", "cert-manager", "redhat-cert-manager-operator", "open-cluster-management-agent", "open-cluster-management-agent-addon"}
 
+	if g.QueryOnly {
+		return g.emitQueries(ctx, clientset, hcpCluster, gatherNamespaces)
+	}
+
 	gatherDir, err := setupGatherDir(g.DestDir, hcpCluster.hcpNamespace)
 	if err != nil {
 		return err
 	}
+	client := newDTClient(hcpCluster.DynatraceURL, tokenProvider)
 
 	for _, gatherNS := range gatherNamespaces {
+		if ctx.Err() != nil {
+			break
+		}
 		fmt.Printf("Gathering for %s\n", gatherNS)
 
-		pods, err := getPodsForNamespace(clientset, gatherNS)
+		pods, err := getPodsForNamespace(ctx, clientset, gatherNS)
 		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
 			return err
 		}
 
@@ -106,37 +174,296 @@ func (g *GatherLogsOpts) GatherLogs(clusterID string, elevationReasons ...string
 			return err
 		}
 
-		err = g.dumpPodLogs(pods, nsDir, gatherNS, hcpCluster.managementClusterName, hcpCluster.DynatraceURL, tokenProvider, g.Since, g.Tail, g.SortOrder)
+		err = g.dumpPodLogs(ctx, pods, nsDir, gatherNS, hcpCluster.managementClusterName, client, g.Since, g.Tail, g.SortOrder)
 		if err != nil {
 			return err
 		}
 
-		deployments, err := getDeploymentsForNamespace(clientset, gatherNS)
+		deployments, err := getDeploymentsForNamespace(ctx, clientset, gatherNS)
 		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
 			return err
 		}
 
-		err = g.dumpEvents(deployments, nsDir, gatherNS, hcpCluster.managementClusterName, hcpCluster.DynatraceURL, tokenProvider, g.Since, g.Tail, g.SortOrder)
+		err = g.dumpEvents(ctx, deployments, nsDir, gatherNS, hcpCluster.managementClusterName, client, g.Since, g.Tail, g.SortOrder)
 		if err != nil {
 			return err
 		}
 
-		err = g.dumpRestartedPodLogs(pods, nsDir, gatherNS, hcpCluster.managementClusterName, hcpCluster.DynatraceURL, tokenProvider)
+		err = g.dumpRestartedPodLogs(ctx, pods, nsDir, gatherNS, hcpCluster.managementClusterName, client)
 		if err != nil {
 			return err
 		}
 
 	}
 
+	if err := printGatherSummary(os.Stdout, gatherDir, g.truncated); err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return markPartialGather(gatherDir, ctx.Err())
+	}
+
 	return nil
 }
 
-func (g *GatherLogsOpts) dumpEvents(deploys *appsv1.DeploymentList, parentDir string, targetNS string, managementClusterName string, DTURL string, tokenProvider utils.AccessTokenProvider, since int, tail int, sortOrder string) error {
+// recordLimit is the limit of the gathering queries, the lowest of --tail and --max-records
+func (g *GatherLogsOpts) recordLimit() int {
+	if g.MaxRecords > 0 && (g.Tail <= 0 || g.Tail > g.MaxRecords) {
+		return g.MaxRecords
+	}
+	return g.Tail
+}
+
+// fetchAndWriteGatherLogs appends the logs of a query to a dump file, up to --max-bytes-per-pod when limitBytes is
+// set, and records the truncation of the query
+func (g *GatherLogsOpts) fetchAndWriteGatherLogs(ctx context.Context, client dtclient.Client, query string, filePath string, limitBytes bool) error {
+	records, err := dtclient.QueryLogs(ctx, client, query)
+	if err != nil {
+		return err
+	}
+
+	exceeded := false
+	err = writeToFileOrStdout(filePath, func(w io.Writer) error {
+		var written int64
+		for _, result := range records {
+			line := result.Content + "\n"
+			if limitBytes && g.MaxBytesPerPod > 0 && written+int64(len(line)) > g.MaxBytesPerPod {
+				exceeded = true
+				return nil
+			}
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+			written += int64(len(line))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	g.recordTruncation(filePath, len(records), exceeded)
+	return nil
+}
+
+// fetchAndWriteGatherEvents appends the events of a query to a dump file and records the truncation of the query
+func (g *GatherLogsOpts) fetchAndWriteGatherEvents(ctx context.Context, client dtclient.Client, query string, filePath string) error {
+	records, err := dtclient.QueryRecords(ctx, client, query)
+	if err != nil {
+		return err
+	}
+
+	err = writeToFileOrStdout(filePath, func(w io.Writer) error {
+		for _, result := range records {
+			if _, err := fmt.Fprintf(w, "%s\n", result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	g.recordTruncation(filePath, len(records), false)
+	return nil
+}
+
+// recordTruncation records a dump file whose query reached --max-records or whose logs exceeded --max-bytes-per-pod
+func (g *GatherLogsOpts) recordTruncation(filePath string, records int, bytesExceeded bool) {
+	var reasons []string
+	if g.MaxRecords > 0 && g.recordLimit() == g.MaxRecords && records >= g.MaxRecords {
+		reasons = append(reasons, fmt.Sprintf("reached --max-records=%d", g.MaxRecords))
+	}
+	if bytesExceeded {
+		reasons = append(reasons, fmt.Sprintf("reached --max-bytes-per-pod=%d", g.MaxBytesPerPod))
+	}
+	if len(reasons) > 0 {
+		g.truncated = append(g.truncated, truncatedQuery{file: filePath, reason: strings.Join(reasons, ", ")})
+	}
+}
+
+// printGatherSummary prints the number and the total size of the files of the dump directory, and the queries
+// truncated by the limits of the gathering
+func printGatherSummary(w io.Writer, gatherDir string, truncated []truncatedQuery) error {
+	files := 0
+	var size int64
+	err := filepath.WalkDir(gatherDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to summarize %s: %w", gatherDir, err)
+	}
+
+	fmt.Fprintf(w, "\nGathering summary of %s\n", gatherDir)
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"Files written", strconv.Itoa(files)})
+	table.AddRow([]string{"Total size", formatBytes(size)})
+	table.AddRow([]string{"Truncated queries", strconv.Itoa(len(truncated))})
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if len(truncated) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	table = printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"FILE", "TRUNCATED"})
+	for _, query := range truncated {
+		file, err := filepath.Rel(gatherDir, query.file)
+		if err != nil {
+			file = query.file
+		}
+		table.AddRow([]string{file, query.reason})
+	}
+	return table.Flush()
+}
+
+// formatBytes formats a size in bytes with a binary unit, e.g. 2.5GiB
+func formatBytes(size int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(size)
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f%s", value, units[i])
+}
+
+// markPartialGather flags the dump directory of an interrupted gathering, so the logs aren't mistaken for a complete dump
+func markPartialGather(gatherDir string, cause error) error {
+	fmt.Println(utils.PartialResultsBanner)
+	fmt.Printf("Gathering interrupted, the logs gathered so far are in %s\n", gatherDir)
+
+	note := fmt.Sprintf("The gathering was interrupted (%v), the logs of this directory are incomplete.\n", cause)
+	if err := os.WriteFile(filepath.Join(gatherDir, partialGatherFileName), []byte(note), 0600); err != nil {
+		return fmt.Errorf("failed to mark %s as partial: %w", gatherDir, err)
+	}
+	return fmt.Errorf("gathering interrupted: %w", cause)
+}
+
+// gatherQuery is a DQL query of the gathering and the dump file its results are written to
+type gatherQuery struct {
+	namespace string
+	// file is the path of the dump file relative to the namespace directory
+	file  string
+	query string
+}
+
+// emitQueries generates the queries of the gathering without executing them. They are printed with the tenant URL or,
+// when a destination directory is set, written as .dql files mirroring the layout of the logs dump.
+func (g *GatherLogsOpts) emitQueries(ctx context.Context, clientset *kubernetes.Clientset, hcpCluster HCPCluster, gatherNamespaces []string) error {
+	var queries []gatherQuery
+	for _, gatherNS := range gatherNamespaces {
+		pods, err := getPodsForNamespace(ctx, clientset, gatherNS)
+		if err != nil {
+			return err
+		}
+		deployments, err := getDeploymentsForNamespace(ctx, clientset, gatherNS)
+		if err != nil {
+			return err
+		}
+		nsQueries, err := g.buildGatherQueries(gatherNS, hcpCluster.managementClusterName, pods, deployments)
+		if err != nil {
+			return err
+		}
+		queries = append(queries, nsQueries...)
+	}
+
+	if g.DestDir == "" {
+		printGatherQueries(os.Stdout, hcpCluster.DynatraceURL, queries)
+		return nil
+	}
+
+	gatherDir, err := setupGatherDir(g.DestDir, hcpCluster.hcpNamespace)
+	if err != nil {
+		return err
+	}
+	if err := writeGatherQueries(gatherDir, hcpCluster.DynatraceURL, queries); err != nil {
+		return err
+	}
+	fmt.Printf("%d queries written to %s, run them in %s\n", len(queries), gatherDir, hcpCluster.DynatraceURL)
+	return nil
+}
+
+// buildGatherQueries returns the pod, deployment event and restarted pod queries the gathering runs for a namespace
+func (g *GatherLogsOpts) buildGatherQueries(namespace string, managementClusterName string, pods *corev1.PodList, deploys *appsv1.DeploymentList) ([]gatherQuery, error) {
+	var queries []gatherQuery
+	var podNames []string
+	for _, p := range pods.Items {
+		podNames = append(podNames, p.Name)
+		q, err := getPodQuery(p.Name, namespace, g.Since, g.recordLimit(), g.SortOrder, managementClusterName)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, gatherQuery{namespace: namespace, file: filepath.Join("pods", p.Name, "pod.dql"), query: q.Build()})
+	}
+
+	for _, d := range deploys.Items {
+		q, err := getEventQuery(d.Name, namespace, g.Since, g.recordLimit(), g.SortOrder, managementClusterName)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, gatherQuery{namespace: namespace, file: filepath.Join("events", d.Name, "events.dql"), query: q.Build()})
+	}
+
+	q, err := getRestartedPodQuery(podNames, namespace, g.Since, g.recordLimit(), g.SortOrder, managementClusterName)
+	if err != nil {
+		return nil, err
+	}
+	queries = append(queries, gatherQuery{namespace: namespace, file: filepath.Join("restarted-pods", "pods.dql"), query: q.Build()})
+
+	return queries, nil
+}
+
+func printGatherQueries(w io.Writer, dtURL string, queries []gatherQuery) {
+	fmt.Fprintf(w, "Dynatrace tenant: %s\n", dtURL)
+	for _, q := range queries {
+		fmt.Fprintf(w, "\n// %s/%s\n%s\n", q.namespace, q.file, q.query)
+	}
+}
+
+// dtURLFileName holds the Dynatrace tenant URL of the queries written with --query-only
+const dtURLFileName = "dynatrace-url.txt"
+
+func writeGatherQueries(gatherDir string, dtURL string, queries []gatherQuery) error {
+	if err := os.WriteFile(filepath.Join(gatherDir, dtURLFileName), []byte(dtURL+"\n"), 0600); err != nil {
+		return err
+	}
+	for _, q := range queries {
+		path := filepath.Join(gatherDir, q.namespace, q.file)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(q.query+"\n"), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *GatherLogsOpts) dumpEvents(ctx context.Context, deploys *appsv1.DeploymentList, parentDir string, targetNS string, managementClusterName string, client dtclient.Client, since int, tail int, sortOrder string) error {
 	totalDeployments := len(deploys.Items)
 	for k, d := range deploys.Items {
+		if ctx.Err() != nil {
+			return nil
+		}
 		fmt.Printf("[%d/%d] Deployment events for %s\n", k+1, totalDeployments, d.Name)
 
-		eventQuery, err := getEventQuery(d.Name, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
+		eventQuery, err := getEventQuery(d.Name, targetNS, g.Since, g.recordLimit(), g.SortOrder, managementClusterName)
 		if err != nil {
 			return err
 		}
@@ -169,18 +496,7 @@ func (g *GatherLogsOpts) dumpEvents(deploys *appsv1.DeploymentList, parentDir st
 
 		eventsFilePath := filepath.Join(eventsDirPath, eventsFileName)
 
-		accessToken, err := tokenProvider.Token()
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %v", err)
-		}
-
-		eventsRequestToken, err := getDTQueryExecution(DTURL, accessToken, eventQuery.finalQuery)
-		if err != nil {
-			log.Printf("failed to get request token: %v", err)
-			continue
-		}
-
-		err = fetchAndWriteEvents(DTURL, accessToken, eventsRequestToken, eventsFilePath)
+		err = g.fetchAndWriteGatherEvents(ctx, client, eventQuery.finalQuery, eventsFilePath)
 		if err != nil {
 			log.Printf("failed to get logs, continuing: %v. Query: %v", err, eventQuery.finalQuery)
 			continue
@@ -190,12 +506,15 @@ func (g *GatherLogsOpts) dumpEvents(deploys *appsv1.DeploymentList, parentDir st
 	return nil
 }
 
-func (g *GatherLogsOpts) dumpPodLogs(pods *corev1.PodList, parentDir string, targetNS string, managementClusterName string, DTURL string, tokenProvider utils.AccessTokenProvider, since int, tail int, sortOrder string) error {
+func (g *GatherLogsOpts) dumpPodLogs(ctx context.Context, pods *corev1.PodList, parentDir string, targetNS string, managementClusterName string, client dtclient.Client, since int, tail int, sortOrder string) error {
 	totalPods := len(pods.Items)
 	for k, p := range pods.Items {
+		if ctx.Err() != nil {
+			return nil
+		}
 		fmt.Printf("[%d/%d] Pod logs for %s\n", k+1, totalPods, p.Name)
 
-		podLogsQuery, err := getPodQuery(p.Name, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
+		podLogsQuery, err := getPodQuery(p.Name, targetNS, g.Since, g.recordLimit(), g.SortOrder, managementClusterName)
 		if err != nil {
 			return err
 		}
@@ -228,18 +547,7 @@ func (g *GatherLogsOpts) dumpPodLogs(pods *corev1.PodList, parentDir string, tar
 
 		podLogsFilePath := filepath.Join(podDirPath, podLogFileName)
 
-		accessToken, err := tokenProvider.Token()
-		if err != nil {
-			return fmt.Errorf("failed to get access token: %v", err)
-		}
-
-		podLogsRequestToken, err := getDTQueryExecution(DTURL, accessToken, podLogsQuery.finalQuery)
-		if err != nil {
-			log.Printf("failed to get request token: %v", err)
-			continue
-		}
-
-		err = fetchAndWriteLogs(DTURL, accessToken, podLogsRequestToken, podLogsFilePath)
+		err = g.fetchAndWriteGatherLogs(ctx, client, podLogsQuery.finalQuery, podLogsFilePath, true)
 		if err != nil {
 			log.Printf("failed to get logs, continuing: %v. Query: %v", err, podLogsQuery.finalQuery)
 			continue
@@ -249,14 +557,18 @@ func (g *GatherLogsOpts) dumpPodLogs(pods *corev1.PodList, parentDir string, tar
 	return nil
 }
 
-func (g *GatherLogsOpts) dumpRestartedPodLogs(pods *corev1.PodList, parentDir string, targetNS string, managementClusterName string, DTURL string, tokenProvider utils.AccessTokenProvider) error {
+func (g *GatherLogsOpts) dumpRestartedPodLogs(ctx context.Context, pods *corev1.PodList, parentDir string, targetNS string, managementClusterName string, client dtclient.Client) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+
 	var podList []string
 	for _, p := range pods.Items {
 		podList = append(podList, p.Name)
 	}
 	fmt.Printf("Collecting Restarted Pod logs for %s\n", targetNS)
 
-	restartedPodLogsQuery, err := getRestartedPodQuery(podList, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
+	restartedPodLogsQuery, err := getRestartedPodQuery(podList, targetNS, g.Since, g.recordLimit(), g.SortOrder, managementClusterName)
 	if err != nil {
 		return err
 	}
@@ -270,17 +582,7 @@ func (g *GatherLogsOpts) dumpRestartedPodLogs(pods *corev1.PodList, parentDir st
 
 	restartedPodLogsFilePath := filepath.Join(podDirPath, restartedPodLogFileName)
 
-	accessToken, err := tokenProvider.Token()
-	if err != nil {
-		return fmt.Errorf("failed to get access token: %v", err)
-	}
-
-	podLogsRequestToken, err := getDTQueryExecution(DTURL, accessToken, restartedPodLogsQuery.finalQuery)
-	if err != nil {
-		log.Printf("failed to get request token: %v", err)
-		return nil
-	}
-	err = fetchAndWriteLogs(DTURL, accessToken, podLogsRequestToken, restartedPodLogsFilePath)
+	err = g.fetchAndWriteGatherLogs(ctx, client, restartedPodLogsQuery.finalQuery, restartedPodLogsFilePath, true)
 	if err != nil {
 		log.Printf("failed to get restarted pod logs: %v. Query: %v", err, restartedPodLogsQuery.finalQuery)
 	}
@@ -402,9 +704,9 @@ func getEventQuery(deploy string, namespace string, since int, tail int, sortOrd
 	return q, nil
 }
 
-func getPodsForNamespace(clientset *kubernetes.Clientset, namespace string) (pl *corev1.PodList, error error) {
+func getPodsForNamespace(ctx context.Context, clientset *kubernetes.Clientset, namespace string) (pl *corev1.PodList, error error) {
 	// Getting pod objects for non-running state pod
-	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), v1.ListOptions{})
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace '%s'", namespace)
 	}
@@ -412,9 +714,9 @@ func getPodsForNamespace(clientset *kubernetes.Clientset, namespace string) (pl
 	return pods, nil
 }
 
-func getDeploymentsForNamespace(clientset *kubernetes.Clientset, namespace string) (pl *appsv1.DeploymentList, error error) {
+func getDeploymentsForNamespace(ctx context.Context, clientset *kubernetes.Clientset, namespace string) (pl *appsv1.DeploymentList, error error) {
 	// Getting pod objects for non-running state pod
-	deploys, err := clientset.AppsV1().Deployments(namespace).List(context.TODO(), v1.ListOptions{})
+	deploys, err := clientset.AppsV1().Deployments(namespace).List(ctx, v1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace '%s'", namespace)
 	}
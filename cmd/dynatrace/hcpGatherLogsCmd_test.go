@@ -1,11 +1,18 @@
 package dynatrace
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestSetupGatherDir(t *testing.T) {
@@ -277,3 +284,168 @@ func TestGetEventQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkPartialGather(t *testing.T) {
+	gatherDir := t.TempDir()
+
+	err := markPartialGather(gatherDir, context.Canceled)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the interruption to be returned, got: %v", err)
+	}
+
+	note, err := os.ReadFile(filepath.Join(gatherDir, partialGatherFileName))
+	if err != nil {
+		t.Fatalf("expected the gather directory to be marked as partial: %v", err)
+	}
+	if !strings.Contains(string(note), "incomplete") {
+		t.Errorf("unexpected partial note: %s", note)
+	}
+}
+
+func TestGatherQueries(t *testing.T) {
+	g := &GatherLogsOpts{Since: 10, SortOrder: "asc"}
+	pods := &corev1.PodList{Items: []corev1.Pod{{ObjectMeta: v1.ObjectMeta{Name: "kube-apiserver-1"}}}}
+	deploys := &appsv1.DeploymentList{Items: []appsv1.Deployment{{ObjectMeta: v1.ObjectMeta{Name: "kube-apiserver"}}}}
+
+	queries, err := g.buildGatherQueries("ocm-hcp-ns", "mc-1", pods, deploys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("expected a pod, an event and a restarted pod query, got %d", len(queries))
+	}
+	if !strings.Contains(queries[0].query, "kube-apiserver-1") || !strings.Contains(queries[1].query, "kube-apiserver") || !strings.Contains(queries[2].query, "and not (") {
+		t.Errorf("unexpected queries: %+v", queries)
+	}
+
+	var out bytes.Buffer
+	printGatherQueries(&out, "https://tenant.example.com", queries)
+	if !strings.Contains(out.String(), "Dynatrace tenant: https://tenant.example.com") || !strings.Contains(out.String(), "// ocm-hcp-ns/pods/kube-apiserver-1/pod.dql") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	gatherDir := t.TempDir()
+	if err := writeGatherQueries(gatherDir, "https://tenant.example.com", queries); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{dtURLFileName, "ocm-hcp-ns/pods/kube-apiserver-1/pod.dql", "ocm-hcp-ns/events/kube-apiserver/events.dql", "ocm-hcp-ns/restarted-pods/pods.dql"} {
+		if _, err := os.Stat(filepath.Join(gatherDir, file)); err != nil {
+			t.Errorf("expected %s to be written: %v", file, err)
+		}
+	}
+}
+
+// fakeDTClient answers every query with the same log lines and records the queries
+type fakeDTClient struct {
+	lines   []string
+	queries []string
+}
+
+func (f *fakeDTClient) ExecuteQuery(_ context.Context, query string) (string, error) {
+	f.queries = append(f.queries, query)
+	return "req-1", nil
+}
+
+func (f *fakeDTClient) PollResults(_ context.Context, _ string) (string, error) {
+	var records []string
+	for _, line := range f.lines {
+		records = append(records, fmt.Sprintf(`{"content":%q}`, line))
+	}
+	return `{"state":"SUCCEEDED","result":{"records":[` + strings.Join(records, ",") + `]}}`, nil
+}
+
+func (f *fakeDTClient) GetDocumentIDByNameAndType(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func TestDumpPodLogs(t *testing.T) {
+	client := &fakeDTClient{lines: []string{"starting", "ready"}}
+	pods := &corev1.PodList{Items: []corev1.Pod{
+		{ObjectMeta: v1.ObjectMeta{Name: "kube-apiserver-0"}},
+		{ObjectMeta: v1.ObjectMeta{Name: "etcd-0"}},
+	}}
+	g := &GatherLogsOpts{Since: 1, SortOrder: "asc"}
+	dir := t.TempDir()
+
+	err := g.dumpPodLogs(context.Background(), pods, dir, "ocm-staging-abc", "mc-1", client, g.Since, g.Tail, g.SortOrder)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.queries) != 2 || !strings.Contains(client.queries[0], `"kube-apiserver-0"`) {
+		t.Errorf("expected a query per pod, got %v", client.queries)
+	}
+	for _, pod := range []string{"kube-apiserver-0", "etcd-0"} {
+		content, err := os.ReadFile(filepath.Join(dir, "pods", pod, "pod.log"))
+		if err != nil {
+			t.Fatalf("failed to read the logs of %s: %v", pod, err)
+		}
+		if string(content) != "starting\nready\n" {
+			t.Errorf("unexpected logs of %s: %q", pod, content)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "pods", pod, "pod.yaml")); err != nil {
+			t.Errorf("expected the manifest of %s: %v", pod, err)
+		}
+	}
+}
+
+func TestDumpPodLogsLimits(t *testing.T) {
+	client := &fakeDTClient{lines: []string{"starting", "ready", "serving"}}
+	pods := &corev1.PodList{Items: []corev1.Pod{{ObjectMeta: v1.ObjectMeta{Name: "kube-apiserver-0"}}}}
+	g := &GatherLogsOpts{Since: 1, SortOrder: "asc", Tail: 100, MaxRecords: 3, MaxBytesPerPod: 15}
+	dir := t.TempDir()
+
+	err := g.dumpPodLogs(context.Background(), pods, dir, "ocm-staging-abc", "mc-1", client, g.Since, g.Tail, g.SortOrder)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasSuffix(client.queries[0], "| limit 3") {
+		t.Errorf("expected --max-records to limit the query, got %s", client.queries[0])
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "pods", "kube-apiserver-0", "pod.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "starting\nready\n" {
+		t.Errorf("expected the logs to be cut at --max-bytes-per-pod, got %q", content)
+	}
+	if len(g.truncated) != 1 || g.truncated[0].reason != "reached --max-records=3, reached --max-bytes-per-pod=15" {
+		t.Errorf("unexpected truncated queries: %+v", g.truncated)
+	}
+
+	var out bytes.Buffer
+	if err := printGatherSummary(&out, dir, g.truncated); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"Files written", "2", "Total size", "Truncated queries", "pods/kube-apiserver-0/pod.log", "reached --max-records=3"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected the summary to contain %q, got %s", expected, out.String())
+		}
+	}
+}
+
+func TestRecordLimit(t *testing.T) {
+	for _, tt := range []struct {
+		tail, maxRecords, expected int
+	}{
+		{tail: 0, maxRecords: 0, expected: 0},
+		{tail: 50, maxRecords: 0, expected: 50},
+		{tail: 0, maxRecords: 1000, expected: 1000},
+		{tail: 50, maxRecords: 1000, expected: 50},
+		{tail: 5000, maxRecords: 1000, expected: 1000},
+	} {
+		g := &GatherLogsOpts{Tail: tt.tail, MaxRecords: tt.maxRecords}
+		if limit := g.recordLimit(); limit != tt.expected {
+			t.Errorf("tail %d and max records %d: expected limit %d, got %d", tt.tail, tt.maxRecords, tt.expected, limit)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for size, expected := range map[int64]string{512: "512.0B", 2048: "2.0KiB", 3 * 1024 * 1024 * 1024: "3.0GiB"} {
+		if formatted := formatBytes(size); formatted != expected {
+			t.Errorf("expected %d bytes to be formatted as %s, got %s", size, expected, formatted)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package dynatrace
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -8,7 +9,6 @@ import (
 
 	k8s "github.com/openshift/osdctl/pkg/k8s"
 	"github.com/spf13/cobra"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
 var (
@@ -71,12 +71,12 @@ func NewCmdLogs() *cobra.Command {
 		Example:           logsCmdExample,
 		Args:              cobra.MaximumNArgs(1),
 		DisableAutoGenTag: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			if clusterID == "" {
 				clusterID, err = k8s.GetCurrentCluster()
 				if err != nil {
-					cmdutil.CheckErr(err)
+					return err
 				}
 			}
 
@@ -84,10 +84,7 @@ func NewCmdLogs() *cobra.Command {
 				pod = args[0]
 			}
 
-			err = main(clusterID)
-			if err != nil {
-				cmdutil.CheckErr(err)
-			}
+			return main(cmd.Context(), clusterID)
 		},
 	}
 
@@ -138,7 +135,7 @@ func GetLinkToWebConsole(dtURL string, from string, to string, finalQuery string
 	return fmt.Sprintf("%sui/apps/dynatrace.logs/#%s", dtURL, url.PathEscape(string(mStr))), nil
 }
 
-func main(clusterID string) error {
+func main(ctx context.Context, clusterID string) error {
 	var hcpCluster HCPCluster
 	if since <= 0 {
 		return fmt.Errorf("invalid time duration")
@@ -186,16 +183,11 @@ func main(clusterID string) error {
 		return nil
 	}
 
-	accessToken, err := getStorageAccessToken()
-	if err != nil {
-		return fmt.Errorf("failed to acquire access token %v", err)
-	}
-
-	requestToken, err := getDTQueryExecution(hcpCluster.DynatraceURL, accessToken, query.finalQuery)
+	client, err := newStorageClient(hcpCluster.DynatraceURL)
 	if err != nil {
-		return fmt.Errorf("failed to get  vault token %v", err)
+		return err
 	}
-	err = fetchAndWriteLogs(hcpCluster.DynatraceURL, accessToken, requestToken, "")
+	err = fetchAndWriteLogs(ctx, client, query.finalQuery, "")
 	if err != nil {
 		return fmt.Errorf("failed to get logs %v", err)
 	}
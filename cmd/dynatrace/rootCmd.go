@@ -23,6 +23,8 @@ func NewCmdDynatrace() *cobra.Command {
 	dtCmd.AddCommand(newCmdURL())
 	dtCmd.AddCommand(newCmdDashboard())
 	dtCmd.AddCommand(NewCmdHCPMustGather())
+	dtCmd.AddCommand(newCmdHCPEvents())
+	dtCmd.AddCommand(newCmdAuditLogs())
 
 	return dtCmd
 }
@@ -1,33 +1,128 @@
 package dynatrace
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 
+	osdctlio "github.com/openshift/osdctl/internal/io"
+	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/spf13/cobra"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+// urlOptions defines the struct for running the url command
+type urlOptions struct {
+	clusterIDs   []string
+	clustersFile string
+	since        int
+
+	out io.Writer
+	// fetchCluster returns the Dynatrace details of a cluster, replaced in tests
+	fetchCluster func(clusterKey string) (HCPCluster, error)
+}
+
 func newCmdURL() *cobra.Command {
-	var clusterID string
+	ops := &urlOptions{
+		out:          os.Stdout,
+		fetchCluster: FetchClusterDetails,
+	}
 
 	urlCmd := &cobra.Command{
 		Use:   "url --cluster-id <cluster-identifier>",
 		Short: "Get the Dynatrace Tenant URL for a given MC or HCP cluster",
+		Long: `Get the Dynatrace Tenant URL for a given MC or HCP cluster.
+
+  When several clusters are given, e.g. all the HCPs of a management cluster, a table of the tenant URL of each
+  cluster and a link to its logs in the Dynatrace web console is printed instead.`,
 		Example: `  # Get the Dynatrace URL for a cluster
-  osdctl dynatrace url --cluster-id ${CLUSTER_ID}`,
-		DisableAutoGenTag: true,
-		Run: func(cmd *cobra.Command, args []string) {
+  osdctl dynatrace url --cluster-id ${CLUSTER_ID}
 
-			hcpCluster, err := FetchClusterDetails(clusterID)
-			if err != nil {
-				cmdutil.CheckErr(err)
+  # Get the Dynatrace URLs and links to the logs of the last 2 hours of several clusters
+  osdctl dynatrace url --cluster-id ${CLUSTER_ID_1},${CLUSTER_ID_2} --since 2
+
+  # Get the Dynatrace URLs of the clusters of a file (format: {"clusters":["$CLUSTERID1", "$CLUSTERID2"]})
+  osdctl dynatrace url --clusters-file clusters.json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ops.complete(); err != nil {
+				return err
 			}
-			fmt.Println("Dynatrace Environment URL - ", hcpCluster.DynatraceURL)
+			return ops.run()
 		},
 	}
 
-	urlCmd.Flags().StringVarP(&clusterID, "cluster-id", "C", "", "ID of the cluster")
-	_ = urlCmd.MarkFlagRequired("cluster-id")
+	urlCmd.Flags().StringSliceVarP(&ops.clusterIDs, "cluster-id", "C", nil, "ID of the cluster, can be repeated or comma-separated")
+	urlCmd.Flags().StringVarP(&ops.clustersFile, "clusters-file", "c", "", "JSON file containing cluster IDs (format: {\"clusters\":[\"$CLUSTERID1\", \"$CLUSTERID2\"]})")
+	urlCmd.Flags().IntVar(&ops.since, "since", 1, "Number of hours of logs the links of several clusters open")
+	urlCmd.MarkFlagsMutuallyExclusive("cluster-id", "clusters-file")
+	urlCmd.MarkFlagsOneRequired("cluster-id", "clusters-file")
 
 	return urlCmd
 }
+
+func (o *urlOptions) complete() error {
+	if o.since <= 0 {
+		return fmt.Errorf("invalid --since %d, must be a positive number of hours", o.since)
+	}
+	if o.clustersFile != "" {
+		clusterIDs, err := osdctlio.ParseAndValidateClustersFile(o.clustersFile)
+		if err != nil {
+			return err
+		}
+		o.clusterIDs = clusterIDs
+	}
+	if len(o.clusterIDs) == 0 {
+		return errors.New("no cluster identifier has been found, please specify either --cluster-id or --clusters-file")
+	}
+	return nil
+}
+
+func (o *urlOptions) run() error {
+	if len(o.clusterIDs) == 1 {
+		hcpCluster, err := o.fetchCluster(o.clusterIDs[0])
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(o.out, "Dynatrace Environment URL - ", hcpCluster.DynatraceURL)
+		return nil
+	}
+
+	failed := 0
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"CLUSTER", "MANAGEMENT CLUSTER", "TENANT URL", "LOGS"})
+	for _, clusterID := range o.clusterIDs {
+		hcpCluster, err := o.fetchCluster(clusterID)
+		if err != nil {
+			failed++
+			table.AddRow([]string{clusterID, "-", "-", fmt.Sprintf("error: %v", err)})
+			continue
+		}
+		link, err := o.logsLink(hcpCluster)
+		if err != nil {
+			failed++
+			link = fmt.Sprintf("error: %v", err)
+		}
+		table.AddRow([]string{clusterID, hcpCluster.managementClusterName, hcpCluster.DynatraceURL, link})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to get the Dynatrace URLs of %d of %d clusters", failed, len(o.clusterIDs))
+	}
+	return nil
+}
+
+// logsLink returns a link to the web console showing the logs of the cluster: those of its HCP namespace for an HCP,
+// or those of the whole cluster for a management cluster
+func (o *urlOptions) logsLink(hcpCluster HCPCluster) (string, error) {
+	q := DTQuery{}
+	q.InitLogs(o.since).Cluster(hcpCluster.managementClusterName)
+	if hcpCluster.hcpNamespace != "" {
+		q.Namespaces([]string{hcpCluster.hcpNamespace})
+	}
+	return GetLinkToWebConsole(hcpCluster.DynatraceURL, fmt.Sprintf("now()-%dh", o.since), "now()", q.Build())
+}
@@ -0,0 +1,89 @@
+package dynatrace
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestURLBatch(t *testing.T) {
+	clusters := map[string]HCPCluster{
+		"hcp-1": {managementClusterName: "mc-1", hcpNamespace: "ocm-production-hcp-1-name", DynatraceURL: "https://tenant.example.com/"},
+		"mc-1":  {managementClusterName: "mc-1", DynatraceURL: "https://tenant.example.com/"},
+	}
+	var out bytes.Buffer
+	o := &urlOptions{
+		clusterIDs: []string{"hcp-1", "mc-1", "classic-1"},
+		since:      2,
+		out:        &out,
+		fetchCluster: func(clusterKey string) (HCPCluster, error) {
+			if c, ok := clusters[clusterKey]; ok {
+				return c, nil
+			}
+			return HCPCluster{}, ErrUnsupportedCluster
+		},
+	}
+
+	err := o.run()
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Errorf("expected the unsupported cluster to be reported, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and a row per cluster, got:\n%s", out.String())
+	}
+	if !strings.Contains(lines[1], "https://tenant.example.com/ui/apps/dynatrace.logs/#") || !strings.Contains(lines[1], "ocm-production-hcp-1-name") {
+		t.Errorf("expected a link to the logs of the HCP namespace, got %s", lines[1])
+	}
+	if !strings.Contains(lines[1], "now%28%29-2h") && !strings.Contains(lines[1], "now()-2h") {
+		t.Errorf("expected the link to cover the last 2 hours, got %s", lines[1])
+	}
+	if !strings.Contains(lines[3], "error: "+ErrUnsupportedCluster.Error()) {
+		t.Errorf("expected the error of the unsupported cluster, got %s", lines[3])
+	}
+}
+
+func TestURLSingleCluster(t *testing.T) {
+	var out bytes.Buffer
+	o := &urlOptions{
+		clusterIDs: []string{"mc-1"},
+		out:        &out,
+		fetchCluster: func(string) (HCPCluster, error) {
+			return HCPCluster{DynatraceURL: "https://tenant.example.com/"}, nil
+		},
+	}
+	if err := o.run(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "Dynatrace Environment URL -  https://tenant.example.com/\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	o.fetchCluster = func(string) (HCPCluster, error) { return HCPCluster{}, errors.New("not found") }
+	if err := o.run(); err == nil {
+		t.Error("expected the error of a single cluster to be returned")
+	}
+}
+
+func TestURLComplete(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "clusters.json")
+	if err := os.WriteFile(file, []byte(`{"clusters":["hcp-1","hcp-2"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	o := &urlOptions{clustersFile: file, since: 1}
+	if err := o.complete(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(o.clusterIDs, ",") != "hcp-1,hcp-2" {
+		t.Errorf("unexpected clusters %v", o.clusterIDs)
+	}
+
+	if err := (&urlOptions{clusterIDs: []string{"hcp-1"}}).complete(); err == nil {
+		t.Error("expected an error for a non positive --since")
+	}
+}
@@ -0,0 +1,327 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/spf13/cobra"
+)
+
+// environment is an existing environment directory
+type environment struct {
+	Name string
+	Path string
+	// LastUsed is the last modification of the environment directory or of the files in it
+	LastUsed time.Time
+	// LoggedIn is true when a kubeconfig was created in the environment
+	LoggedIn bool
+	// Active is true while background processes of the environment run, e.g. the login script of an open shell
+	Active bool
+}
+
+type cleanupOptions struct {
+	olderThan time.Duration
+	unused    bool
+	dryRun    bool
+
+	baseDir string
+	out     io.Writer
+	now     func() time.Time
+	// logout revokes the backplane session of the environment, replaced in tests
+	logout func(env environment) error
+}
+
+func newCmdCleanup() *cobra.Command {
+	opts := &cleanupOptions{
+		out:    os.Stdout,
+		now:    time.Now,
+		logout: backplaneLogout,
+	}
+	cleanupCmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Delete stale environments and revoke their backplane sessions",
+		Long: `Delete stale environments and revoke their backplane sessions
+
+  The environments in $HOME/ocenv are selected with --older-than and --unused, or picked from a list
+  when neither is set. Environments with running background processes, e.g. the login script of an
+  open shell, are never deleted. The backplane session of each environment is revoked before its
+  directory is deleted.`,
+		Example: `  # Pick the environments to delete from a list
+  osdctl env cleanup
+
+  # Delete the environments not used for a month
+  osdctl env cleanup --older-than 720h
+
+  # Show the environments that were never logged in to, without deleting them
+  osdctl env cleanup --unused --dry-run`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.olderThan < 0 {
+				return errors.New("--older-than must not be negative")
+			}
+			opts.baseDir = filepath.Join(os.Getenv("HOME"), "ocenv")
+			return opts.run()
+		},
+	}
+
+	cleanupCmd.Flags().DurationVar(&opts.olderThan, "older-than", 0, "Select the environments not used for this long, e.g. 168h")
+	cleanupCmd.Flags().BoolVar(&opts.unused, "unused", false, "Select the environments that were never logged in to")
+	cleanupCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the selected environments without deleting them")
+
+	return cleanupCmd
+}
+
+func (o *cleanupOptions) run() error {
+	envs, err := listEnvironments(o.baseDir)
+	if err != nil {
+		return err
+	}
+	if len(envs) == 0 {
+		fmt.Fprintln(o.out, "No environment found in", o.baseDir)
+		return nil
+	}
+
+	var selected []environment
+	if o.olderThan == 0 && !o.unused {
+		if selected, err = o.pick(envs); err != nil {
+			return err
+		}
+	} else {
+		selected = o.filter(envs)
+		if len(selected) > 0 {
+			fmt.Fprintln(o.out, "The following environments are selected:")
+			if err := o.printEnvironments(selected); err != nil {
+				return err
+			}
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Fprintln(o.out, "No environment to delete")
+		return nil
+	}
+
+	if o.dryRun {
+		fmt.Fprintf(o.out, "Dry run, %d environment(s) would be deleted\n", len(selected))
+		return nil
+	}
+	if !prompt.Confirm(fmt.Sprintf("Delete %d environment(s)?", len(selected)), false) {
+		return nil
+	}
+
+	var failed []string
+	for _, env := range selected {
+		if env.LoggedIn {
+			if err := o.logout(env); err != nil {
+				fmt.Fprintf(o.out, "Failed to revoke the backplane session of %s, it expires on its own: %v\n", env.Name, err)
+			}
+		}
+		if err := os.RemoveAll(env.Path); err != nil {
+			failed = append(failed, env.Name)
+			fmt.Fprintf(o.out, "Failed to delete %s: %v\n", env.Name, err)
+			continue
+		}
+		fmt.Fprintf(o.out, "Deleted %s\n", env.Name)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete %d environment(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// filter returns the inactive environments matching all of --older-than and --unused
+func (o *cleanupOptions) filter(envs []environment) []environment {
+	var selected []environment
+	for _, env := range envs {
+		if env.Active {
+			continue
+		}
+		if o.olderThan > 0 && o.now().Sub(env.LastUsed) < o.olderThan {
+			continue
+		}
+		if o.unused && env.LoggedIn {
+			continue
+		}
+		selected = append(selected, env)
+	}
+	return selected
+}
+
+// pick lists the inactive environments and asks which ones to delete
+func (o *cleanupOptions) pick(envs []environment) ([]environment, error) {
+	var candidates []environment
+	for _, env := range envs {
+		if env.Active {
+			fmt.Fprintf(o.out, "Skipping %s, it is in use\n", env.Name)
+			continue
+		}
+		candidates = append(candidates, env)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	if err := o.printEnvironments(candidates); err != nil {
+		return nil, err
+	}
+
+	answer, err := prompt.Input("Environments to delete (e.g. 1,3-5 or all, empty for none)", "")
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := parseSelection(answer, len(candidates))
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]environment, 0, len(indexes))
+	for _, i := range indexes {
+		selected = append(selected, candidates[i])
+	}
+	return selected, nil
+}
+
+func (o *cleanupOptions) printEnvironments(envs []environment) error {
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"#", "NAME", "LAST USED", "LOGGED IN"})
+	for i, env := range envs {
+		age := o.now().Sub(env.LastUsed).Truncate(time.Hour)
+		table.AddRow([]string{
+			strconv.Itoa(i + 1),
+			env.Name,
+			fmt.Sprintf("%s (%s ago)", env.LastUsed.Format("2006-01-02"), age),
+			strconv.FormatBool(env.LoggedIn),
+		})
+	}
+	return table.Flush()
+}
+
+// parseSelection parses a comma-separated list of 1-based indexes and ranges, or "all", into 0-based indexes
+func parseSelection(answer string, count int) ([]int, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil, nil
+	}
+	if strings.EqualFold(answer, "all") {
+		indexes := make([]int, count)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	seen := map[int]bool{}
+	var indexes []int
+	for _, part := range strings.Split(answer, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+				return nil, fmt.Errorf("invalid selection %q", part)
+			}
+		}
+		if first < 1 || last > count || first > last {
+			return nil, fmt.Errorf("selection %q is out of range 1-%d", part, count)
+		}
+		for i := first - 1; i < last; i++ {
+			if !seen[i] {
+				seen[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	return indexes, nil
+}
+
+// listEnvironments returns the environments in baseDir sorted by name
+func listEnvironments(baseDir string) ([]environment, error) {
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var envs []environment
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(baseDir, entry.Name())
+		env := environment{Name: entry.Name(), Path: path}
+
+		files, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := entry.Info(); err == nil {
+			env.LastUsed = info.ModTime()
+		}
+		for _, file := range files {
+			info, err := file.Info()
+			if err != nil {
+				continue
+			}
+			if info.ModTime().After(env.LastUsed) {
+				env.LastUsed = info.ModTime()
+			}
+			switch file.Name() {
+			case "kubeconfig.json":
+				env.LoggedIn = info.Size() > 0
+			case ".killpids":
+				env.Active = hasRunningProcess(filepath.Join(path, file.Name()))
+			}
+		}
+		envs = append(envs, env)
+	}
+	sort.Slice(envs, func(i, j int) bool { return envs[i].Name < envs[j].Name })
+	return envs, nil
+}
+
+// hasRunningProcess returns true if any of the PIDs listed in the file is still running
+func hasRunningProcess(pidFile string) bool {
+	content, err := os.ReadFile(pidFile) //#nosec G304 -- the path is a file of the environment
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Fields(string(content)) {
+		pid, err := strconv.Atoi(line)
+		if err != nil || pid <= 0 {
+			continue
+		}
+		// Signal 0 only checks that the process exists
+		if err := syscall.Kill(pid, 0); err == nil || errors.Is(err, syscall.EPERM) {
+			return true
+		}
+	}
+	return false
+}
+
+// backplaneLogout revokes the backplane session of the environment with its own ocm and kube configs
+func backplaneLogout(env environment) error {
+	cmd := exec.Command("ocm", "backplane", "logout")
+	cmd.Env = append(os.Environ(),
+		"KUBECONFIG="+filepath.Join(env.Path, "kubeconfig.json"),
+		"OCM_CONFIG="+filepath.Join(env.Path, "ocm.json"),
+	)
+	cmd.Dir = env.Path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package env
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestEnvironment creates an environment last used at the given time, logged in when kubeconfig isn't empty
+func newTestEnvironment(t *testing.T, baseDir, name, kubeconfig string, lastUsed time.Time) string {
+	path := filepath.Join(baseDir, name)
+	require.NoError(t, os.MkdirAll(path, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(path, ".ocenv"), []byte("CLUSTERID="+name), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "kubeconfig.json"), []byte(kubeconfig), 0600))
+	for _, file := range []string{".ocenv", "kubeconfig.json", ""} {
+		require.NoError(t, os.Chtimes(filepath.Join(path, file), lastUsed, lastUsed))
+	}
+	return path
+}
+
+func TestListEnvironments(t *testing.T) {
+	baseDir := t.TempDir()
+	lastUsed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newTestEnvironment(t, baseDir, "b-logged-in", "{}", lastUsed)
+	newTestEnvironment(t, baseDir, "a-never-logged-in", "", lastUsed)
+	active := newTestEnvironment(t, baseDir, "c-active", "{}", lastUsed)
+	require.NoError(t, os.WriteFile(filepath.Join(active, ".killpids"), []byte(strconv.Itoa(os.Getpid())+"\n"), 0600))
+	stale := newTestEnvironment(t, baseDir, "d-stale-pids", "{}", lastUsed)
+	require.NoError(t, os.WriteFile(filepath.Join(stale, ".killpids"), []byte("999999999\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "not-an-env"), nil, 0600))
+
+	envs, err := listEnvironments(baseDir)
+	require.NoError(t, err)
+	require.Len(t, envs, 4)
+	assert.Equal(t, "a-never-logged-in", envs[0].Name)
+	assert.False(t, envs[0].LoggedIn)
+	assert.True(t, envs[0].LastUsed.Equal(lastUsed))
+	assert.True(t, envs[1].LoggedIn)
+	assert.True(t, envs[2].Active)
+	assert.False(t, envs[3].Active)
+
+	envs, err = listEnvironments(filepath.Join(baseDir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, envs)
+}
+
+func TestCleanupFilter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	envs := []environment{
+		{Name: "old-unused", LastUsed: now.Add(-60 * 24 * time.Hour)},
+		{Name: "old-logged-in", LastUsed: now.Add(-60 * 24 * time.Hour), LoggedIn: true},
+		{Name: "recent-unused", LastUsed: now.Add(-time.Hour)},
+		{Name: "old-active", LastUsed: now.Add(-60 * 24 * time.Hour), Active: true},
+	}
+	names := func(envs []environment) []string {
+		var names []string
+		for _, env := range envs {
+			names = append(names, env.Name)
+		}
+		return names
+	}
+
+	o := &cleanupOptions{now: func() time.Time { return now }, olderThan: 30 * 24 * time.Hour}
+	assert.Equal(t, []string{"old-unused", "old-logged-in"}, names(o.filter(envs)))
+
+	o.unused = true
+	assert.Equal(t, []string{"old-unused"}, names(o.filter(envs)))
+
+	o.olderThan = 0
+	assert.Equal(t, []string{"old-unused", "recent-unused"}, names(o.filter(envs)))
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		answer    string
+		expected  []int
+		expectErr string
+	}{
+		{answer: "", expected: nil},
+		{answer: "all", expected: []int{0, 1, 2, 3}},
+		{answer: "1, 3-4, 3", expected: []int{0, 2, 3}},
+		{answer: "5", expectErr: `selection "5" is out of range 1-4`},
+		{answer: "3-2", expectErr: `selection "3-2" is out of range 1-4`},
+		{answer: "a", expectErr: `invalid selection "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			indexes, err := parseSelection(tt.answer, 4)
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, indexes)
+		})
+	}
+}
+
+func TestCleanupRun(t *testing.T) {
+	baseDir := t.TempDir()
+	now := time.Now()
+	oldLoggedIn := newTestEnvironment(t, baseDir, "old-logged-in", "{}", now.Add(-60*24*time.Hour))
+	oldUnused := newTestEnvironment(t, baseDir, "old-unused", "", now.Add(-60*24*time.Hour))
+	recent := newTestEnvironment(t, baseDir, "recent", "{}", now)
+
+	var loggedOut []string
+	o := &cleanupOptions{
+		olderThan: 30 * 24 * time.Hour,
+		baseDir:   baseDir,
+		out:       &bytes.Buffer{},
+		now:       time.Now,
+		logout: func(env environment) error {
+			loggedOut = append(loggedOut, env.Name)
+			return nil
+		},
+	}
+
+	// A dry run deletes nothing
+	o.dryRun = true
+	require.NoError(t, o.run())
+	assert.DirExists(t, oldLoggedIn)
+
+	o.dryRun = false
+	prompt.SetAssumeYes(true)
+	defer prompt.SetAssumeYes(false)
+	require.NoError(t, o.run())
+
+	assert.NoDirExists(t, oldLoggedIn)
+	assert.NoDirExists(t, oldUnused)
+	assert.DirExists(t, recent)
+	assert.Equal(t, []string{"old-logged-in"}, loggedOut)
+}
+
+func TestCleanupPick(t *testing.T) {
+	baseDir := t.TempDir()
+	now := time.Now()
+	first := newTestEnvironment(t, baseDir, "first", "{}", now)
+	second := newTestEnvironment(t, baseDir, "second", "{}", now)
+
+	restore := prompt.SetIO(bytes.NewBufferString("2\ny\n"), &bytes.Buffer{})
+	defer restore()
+
+	o := &cleanupOptions{
+		baseDir: baseDir,
+		out:     &bytes.Buffer{},
+		now:     time.Now,
+		logout:  func(environment) error { return nil },
+	}
+	require.NoError(t, o.run())
+	assert.DirExists(t, first)
+	assert.NoDirExists(t, second)
+}
@@ -68,6 +68,10 @@ When initialized, osdctl env will copy the ocm config you're currently using.
 
 To log in to a cluster within the environment using backplane, osdctl creates the ocb command.
 The ocb command is created in the bin directory in the environment folder and added to the PATH when inside the environment.
+
+*Cleaning up*
+
+Stale environments can be deleted with "osdctl env cleanup", which also revokes their backplane sessions.
 `
 
 func NewCmdEnv() *cobra.Command {
@@ -101,6 +105,8 @@ func NewCmdEnv() *cobra.Command {
 			return validEnvs, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
+	envCmd.AddCommand(newCmdCleanup())
+
 	envCmd.Flags().BoolVarP(&options.DeleteEnv, "delete", "d", false, "Delete environment")
 	envCmd.Flags().BoolVarP(&options.TempEnv, "temp", "t", false, "Delete environment on exit")
 	envCmd.Flags().BoolVarP(&options.ResetEnv, "reset", "r", false, "Reset environment")
@@ -0,0 +1,16 @@
+package fleet
+
+import "github.com/spf13/cobra"
+
+// NewCmdFleet implements the fleet command to run read-only queries across many clusters
+func NewCmdFleet() *cobra.Command {
+	fleet := &cobra.Command{
+		Use:   "fleet",
+		Short: "Run read-only queries across a fleet of clusters",
+		Args:  cobra.NoArgs,
+	}
+
+	fleet.AddCommand(newCmdExec())
+
+	return fleet
+}
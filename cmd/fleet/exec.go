@@ -0,0 +1,464 @@
+package fleet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// clusterIDPlaceholder is replaced with the cluster ID in osdctl subcommand arguments
+const clusterIDPlaceholder = "{}"
+
+// readOnlyCommands lists the osdctl subcommands which are safe to run across the fleet
+var readOnlyCommands = [][]string{
+	{"cluster", "context"},
+	{"cluster", "dns", "verify"},
+	{"cluster", "health"},
+	{"cluster", "hypershift-info"},
+	{"cluster", "logging-check"},
+	{"cluster", "machines", "list"},
+	{"cluster", "orgId"},
+	{"cluster", "sre-operators", "describe"},
+	{"cluster", "sre-operators", "list"},
+	{"cluster", "support", "status"},
+	{"cluster", "verify-dns"},
+	{"hcp", "status"},
+	{"hive", "clusterdeployment", "shard"},
+	{"hive", "clusterdeployment", "status"},
+	{"hive", "clusterdeployment", "syncsets"},
+	{"servicelog", "list"},
+}
+
+// mutatingFlags are the flags of read-only commands which make them change the cluster, refused by fleet exec
+var mutatingFlags = map[string][]string{
+	"cluster logging-check": {"--fix"},
+}
+
+// forbiddenOcFlags are "oc get" flags which either never return or would target another cluster
+var forbiddenOcFlags = []string{"-w", "--watch", "--watch-only", "--kubeconfig", "--context", "--cluster", "--server", "-s", "--as", "--as-group"}
+
+type clusterTarget struct {
+	ID   string
+	Name string
+}
+
+// commandRunner runs the given command against a single cluster and returns its stdout and stderr
+type commandRunner func(ctx context.Context, cluster clusterTarget, args []string) ([]byte, []byte, error)
+
+type execOptions struct {
+	search       string
+	clustersFile string
+	concurrency  int
+	timeout      time.Duration
+	args         []string
+
+	out    io.Writer
+	runner commandRunner
+}
+
+type execResult struct {
+	ClusterID       string  `json:"cluster_id"`
+	ClusterName     string  `json:"cluster_name,omitempty"`
+	Success         bool    `json:"success"`
+	ExitCode        int     `json:"exit_code"`
+	Output          any     `json:"output,omitempty"`
+	Stderr          string  `json:"stderr,omitempty"`
+	Error           string  `json:"error,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+type execSummary struct {
+	Command     []string     `json:"command"`
+	Total       int          `json:"total"`
+	Succeeded   int          `json:"succeeded"`
+	Failed      int          `json:"failed"`
+	Skipped     int          `json:"skipped,omitempty"`
+	Interrupted bool         `json:"interrupted,omitempty"`
+	Results     []execResult `json:"results"`
+}
+
+// errSkippedInterrupted is the error of the clusters the command didn't run on because it was interrupted
+var errSkippedInterrupted = errors.New("skipped: interrupted before running on the cluster")
+
+func newCmdExec() *cobra.Command {
+	opts := &execOptions{runner: runClusterCommand}
+
+	execCmd := &cobra.Command{
+		Use:   "exec [flags] -- <command>",
+		Short: "Run a read-only osdctl subcommand or `oc get` across many clusters",
+		Long: `Run a read-only osdctl subcommand or "oc get" across many clusters
+
+  The clusters are selected with an OCM search query (--search) or a file of cluster
+  IDs (--clusters-file), which is either one cluster ID per line or a JSON document in
+  the format {"clusters":["$CLUSTERID1", "$CLUSTERID2"]}.
+
+  The command to run is given after "--" and is either:
+    - "oc get ...", which runs against every cluster through backplane
+    - a read-only osdctl subcommand, where "` + clusterIDPlaceholder + `" is replaced with the cluster ID. The
+      flags making a subcommand change the cluster, e.g. "cluster logging-check --fix", are refused
+
+  The commands run with bounded concurrency and the results of all clusters are
+  aggregated into a single JSON document. Output which is valid JSON is embedded as is.
+
+  Interrupting the command (Ctrl+C) stops the running commands and skips the remaining
+  clusters, the results gathered so far are still printed and flagged as interrupted.`,
+		Example: `
+  # Get the cluster version of all clusters in an organization
+  osdctl fleet exec --search "organization.id='1a2b3c'" -- oc get clusterversion version -o json
+
+  # Print the context of every cluster listed in a file, 5 clusters at a time
+  osdctl fleet exec --clusters-file clusters.txt --concurrency 5 -- osdctl cluster context -C {} -o json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.args = args
+			opts.out = cmd.OutOrStdout()
+			ctx, stop := utils.NotifyInterrupt(cmd.Context(), cmd.ErrOrStderr())
+			defer stop()
+			return opts.run(ctx)
+		},
+	}
+
+	execCmd.Flags().StringVar(&opts.search, "search", "", "OCM search query selecting the clusters to run against")
+	execCmd.Flags().StringVar(&opts.clustersFile, "clusters-file", "", "file containing the IDs of the clusters to run against")
+	execCmd.Flags().IntVar(&opts.concurrency, "concurrency", 10, "maximum number of clusters to run against at the same time")
+	execCmd.Flags().DurationVar(&opts.timeout, "timeout", 5*time.Minute, "timeout of the command on a single cluster")
+	execCmd.MarkFlagsMutuallyExclusive("search", "clusters-file")
+	execCmd.MarkFlagsOneRequired("search", "clusters-file")
+
+	return execCmd
+}
+
+func (o *execOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if o.concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", o.concurrency)
+	}
+
+	args, err := validateCommand(o.args)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := o.resolveClusters()
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		return errors.New("no clusters matched")
+	}
+
+	log.Printf("running %q on %d cluster(s) with a concurrency of %d", strings.Join(args, " "), len(clusters), o.concurrency)
+	summary := execAcrossClusters(ctx, clusters, args, o.concurrency, o.timeout, o.runner)
+	if summary.Interrupted {
+		log.Print(utils.PartialResultsBanner)
+	}
+
+	encoder := json.NewEncoder(o.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(summary); err != nil {
+		return err
+	}
+
+	if summary.Interrupted {
+		return fmt.Errorf("interrupted, skipped %d of %d cluster(s)", summary.Skipped, summary.Total)
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("command failed on %d of %d cluster(s)", summary.Failed, summary.Total)
+	}
+	return nil
+}
+
+// resolveClusters returns the clusters selected by either the search query or the clusters file
+func (o *execOptions) resolveClusters() ([]clusterTarget, error) {
+	if o.clustersFile != "" {
+		content, err := os.ReadFile(o.clustersFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read clusters file: %w", err)
+		}
+		return parseClustersFile(content)
+	}
+
+	if o.search == "" {
+		return nil, errors.New("either --search or --clusters-file is required")
+	}
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer ocmClient.Close()
+
+	clusters, err := utils.ApplyFilters(ocmClient, []string{o.search})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search clusters: %w", err)
+	}
+
+	targets := make([]clusterTarget, 0, len(clusters))
+	for _, cluster := range clusters {
+		targets = append(targets, clusterTarget{ID: cluster.ID(), Name: cluster.Name()})
+	}
+	return targets, nil
+}
+
+// parseClustersFile parses either a JSON document in the format {"clusters":[...]} or one cluster ID per line.
+// Empty lines and lines starting with '#' are ignored.
+func parseClustersFile(content []byte) ([]clusterTarget, error) {
+	var targets []clusterTarget
+	seen := map[string]bool{}
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			targets = append(targets, clusterTarget{ID: id})
+		}
+	}
+
+	trimmed := bytes.TrimSpace(content)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var file struct {
+			Clusters []string `json:"clusters"`
+		}
+		if err := json.Unmarshal(trimmed, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse clusters file: %w", err)
+		}
+		for _, id := range file.Clusters {
+			add(strings.TrimSpace(id))
+		}
+		return targets, nil
+	}
+
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		add(line)
+	}
+	return targets, nil
+}
+
+// validateCommand makes sure the command is read-only and returns it without a leading "osdctl"
+func validateCommand(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.New("a command to run is required after \"--\"")
+	}
+
+	if args[0] == "oc" {
+		if len(args) < 2 || args[1] != "get" {
+			return nil, errors.New("only \"oc get\" is supported")
+		}
+		for _, arg := range args[2:] {
+			for _, flag := range forbiddenOcFlags {
+				if arg == flag || strings.HasPrefix(arg, flag+"=") {
+					return nil, fmt.Errorf("the %s flag is not supported by fleet exec", flag)
+				}
+			}
+		}
+		return args, nil
+	}
+
+	if args[0] == "osdctl" {
+		args = args[1:]
+	}
+
+	for _, command := range readOnlyCommands {
+		if len(args) >= len(command) && slices.Equal(args[:len(command)], command) {
+			for _, flag := range mutatingFlags[strings.Join(command, " ")] {
+				for _, arg := range args {
+					if arg == flag || strings.HasPrefix(arg, flag+"=") {
+						return nil, fmt.Errorf("the %s flag of %q changes the clusters, it is not supported by fleet exec", flag, strings.Join(command, " "))
+					}
+				}
+			}
+			for _, arg := range args {
+				if strings.Contains(arg, clusterIDPlaceholder) {
+					return args, nil
+				}
+			}
+			return nil, fmt.Errorf("the command must contain %q, which is replaced with the cluster ID", clusterIDPlaceholder)
+		}
+	}
+
+	supported := make([]string, 0, len(readOnlyCommands))
+	for _, command := range readOnlyCommands {
+		supported = append(supported, strings.Join(command, " "))
+	}
+	return nil, fmt.Errorf("%q is not a supported read-only command, supported commands are \"oc get\" and: %s", strings.Join(args, " "), strings.Join(supported, ", "))
+}
+
+// execAcrossClusters runs the command on all clusters with at most concurrency commands running at the same time.
+// The results are returned in the order of the clusters.
+func execAcrossClusters(ctx context.Context, clusters []clusterTarget, args []string, concurrency int, timeout time.Duration, runner commandRunner) execSummary {
+	results := make([]execResult, len(clusters))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i, cluster := range clusters {
+		wg.Add(1)
+		go func(i int, cluster clusterTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				results[i] = execResult{ClusterID: cluster.ID, ClusterName: cluster.Name, ExitCode: -1, Error: errSkippedInterrupted.Error()}
+				return
+			}
+			results[i] = execOnCluster(ctx, cluster, args, timeout, runner)
+			if !results[i].Success {
+				log.Printf("%s: failed: %s", cluster.ID, results[i].Error)
+			}
+		}(i, cluster)
+	}
+	wg.Wait()
+
+	summary := execSummary{Command: args, Total: len(results), Interrupted: utils.IsInterrupted(ctx), Results: results}
+	for _, result := range results {
+		switch {
+		case result.Success:
+			summary.Succeeded++
+		case result.Error == errSkippedInterrupted.Error():
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+func execOnCluster(ctx context.Context, cluster clusterTarget, args []string, timeout time.Duration, runner commandRunner) execResult {
+	result := execResult{ClusterID: cluster.ID, ClusterName: cluster.Name}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	stdout, stderr, err := runner(ctx, cluster, args)
+	result.DurationSeconds = time.Since(start).Round(time.Millisecond).Seconds()
+	result.Stderr = strings.TrimSpace(string(stderr))
+
+	trimmed := bytes.TrimSpace(stdout)
+	if len(trimmed) > 0 {
+		if json.Valid(trimmed) {
+			result.Output = json.RawMessage(trimmed)
+		} else {
+			result.Output = string(trimmed)
+		}
+	}
+
+	if err != nil {
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		switch ctx.Err() {
+		case context.DeadlineExceeded:
+			err = fmt.Errorf("timed out after %s: %w", timeout, err)
+		case context.Canceled:
+			err = fmt.Errorf("interrupted: %w", err)
+		}
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Success = true
+	return result
+}
+
+// runClusterCommand runs "oc get" through backplane or an osdctl subcommand against the cluster
+func runClusterCommand(ctx context.Context, cluster clusterTarget, args []string) ([]byte, []byte, error) {
+	var cmd *exec.Cmd
+	if args[0] == "oc" {
+		restConfig, err := k8s.NewRestConfig(cluster.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to login to cluster: %w", err)
+		}
+		kubeconfig, err := writeKubeconfig(restConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer os.Remove(kubeconfig)
+
+		cmd = exec.CommandContext(ctx, "oc", append([]string{"--kubeconfig", kubeconfig}, args[1:]...)...) // #nosec G204 -- validated to be "oc get"
+	} else {
+		osdctl, err := os.Executable()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find the osdctl executable: %w", err)
+		}
+
+		cmdArgs := make([]string, 0, len(args)+2)
+		for _, arg := range args {
+			cmdArgs = append(cmdArgs, strings.ReplaceAll(arg, clusterIDPlaceholder, cluster.ID))
+		}
+		cmdArgs = append(cmdArgs, "--skip-version-check", "--"+prompt.NonInteractiveFlag)
+
+		cmd = exec.CommandContext(ctx, osdctl, cmdArgs...) // #nosec G204 -- validated to be a read-only osdctl subcommand
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// writeKubeconfig writes a temporary kubeconfig for the rest config, so it can be used by oc
+func writeKubeconfig(restConfig *rest.Config) (string, error) {
+	cluster := &clientcmdapi.Cluster{
+		Server:                   restConfig.Host,
+		CertificateAuthorityData: restConfig.CAData,
+	}
+	if restConfig.Proxy != nil {
+		req, _ := http.NewRequest(http.MethodGet, restConfig.Host, nil)
+		if proxyUrl, err := restConfig.Proxy(req); err == nil && proxyUrl != nil {
+			cluster.ProxyURL = proxyUrl.String()
+		}
+	}
+
+	config := clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{"cluster": cluster},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"user": {
+			ClientCertificateData: restConfig.CertData,
+			ClientKeyData:         restConfig.KeyData,
+			Token:                 restConfig.BearerToken,
+		}},
+		Contexts:       map[string]*clientcmdapi.Context{"context": {Cluster: "cluster", AuthInfo: "user"}},
+		CurrentContext: "context",
+	}
+
+	file, err := os.CreateTemp("", "osdctl-fleet-kubeconfig")
+	if err != nil {
+		return "", fmt.Errorf("failed to create kubeconfig: %w", err)
+	}
+	_ = file.Close()
+
+	if err := clientcmd.WriteToFile(config, file.Name()); err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return file.Name(), nil
+}
@@ -0,0 +1,230 @@
+package fleet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClustersFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []clusterTarget
+		wantErr  bool
+	}{
+		{
+			name:     "one_id_per_line",
+			content:  "# clusters to audit\nabc\n\n  def  \nabc\n",
+			expected: []clusterTarget{{ID: "abc"}, {ID: "def"}},
+		},
+		{
+			name:     "json",
+			content:  `{"clusters": ["abc", "def"]}`,
+			expected: []clusterTarget{{ID: "abc"}, {ID: "def"}},
+		},
+		{
+			name:    "invalid_json",
+			content: `{"clusters": [`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			targets, err := parseClustersFile([]byte(test.content))
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, targets)
+		})
+	}
+}
+
+func TestValidateCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:     "oc_get",
+			args:     []string{"oc", "get", "nodes", "-o", "json"},
+			expected: []string{"oc", "get", "nodes", "-o", "json"},
+		},
+		{
+			name:   "oc_delete",
+			args:   []string{"oc", "delete", "pod", "foo"},
+			errMsg: "only \"oc get\" is supported",
+		},
+		{
+			name:   "oc_get_watch",
+			args:   []string{"oc", "get", "pods", "--watch"},
+			errMsg: "the --watch flag is not supported by fleet exec",
+		},
+		{
+			name:   "oc_get_other_kubeconfig",
+			args:   []string{"oc", "get", "pods", "--kubeconfig=/tmp/other"},
+			errMsg: "the --kubeconfig flag is not supported by fleet exec",
+		},
+		{
+			name:     "osdctl_read_only_command",
+			args:     []string{"osdctl", "cluster", "context", "-C", "{}"},
+			expected: []string{"cluster", "context", "-C", "{}"},
+		},
+		{
+			name:   "osdctl_command_without_placeholder",
+			args:   []string{"servicelog", "list", "-C", "abc"},
+			errMsg: "the command must contain \"{}\", which is replaced with the cluster ID",
+		},
+		{
+			name:     "osdctl_logging_check",
+			args:     []string{"osdctl", "cluster", "logging-check", "-C", "{}"},
+			expected: []string{"cluster", "logging-check", "-C", "{}"},
+		},
+		{
+			name:   "osdctl_logging_check_fix",
+			args:   []string{"cluster", "logging-check", "-C", "{}", "--fix", "--reason", "OHSS-1234", "--assume-yes"},
+			errMsg: "the --fix flag of \"cluster logging-check\" changes the clusters, it is not supported by fleet exec",
+		},
+		{
+			name:   "osdctl_mutating_command",
+			args:   []string{"servicelog", "post", "-C", "{}"},
+			errMsg: "\"servicelog post -C {}\" is not a supported read-only command",
+		},
+		{
+			name:   "no_command",
+			errMsg: "a command to run is required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args, err := validateCommand(test.args)
+			if test.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, args)
+		})
+	}
+}
+
+func TestExecAcrossClusters(t *testing.T) {
+	clusters := []clusterTarget{{ID: "a"}, {ID: "b", Name: "bee"}, {ID: "c"}, {ID: "d"}}
+
+	var running, maxRunning int32
+	runner := func(ctx context.Context, cluster clusterTarget, args []string) ([]byte, []byte, error) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			previous := atomic.LoadInt32(&maxRunning)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		switch cluster.ID {
+		case "b":
+			return []byte(`{"version": "4.16.3"}`), nil, nil
+		case "c":
+			return nil, []byte("forbidden\n"), errors.New("exit status 1")
+		case "d":
+			<-ctx.Done()
+			return nil, nil, ctx.Err()
+		}
+		return []byte("plain output\n"), nil, nil
+	}
+
+	summary := execAcrossClusters(context.Background(), clusters, []string{"oc", "get", "clusterversion"}, 2, 50*time.Millisecond, runner)
+
+	assert.LessOrEqual(t, maxRunning, int32(2))
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 2, summary.Succeeded)
+	assert.Equal(t, 2, summary.Failed)
+
+	require.Len(t, summary.Results, 4)
+	assert.Equal(t, "a", summary.Results[0].ClusterID)
+	assert.Equal(t, "plain output", summary.Results[0].Output)
+	assert.Equal(t, "bee", summary.Results[1].ClusterName)
+	assert.Equal(t, json.RawMessage(`{"version": "4.16.3"}`), summary.Results[1].Output)
+	assert.False(t, summary.Results[2].Success)
+	assert.Equal(t, -1, summary.Results[2].ExitCode)
+	assert.Equal(t, "forbidden", summary.Results[2].Stderr)
+	assert.Contains(t, summary.Results[3].Error, "timed out after 50ms")
+}
+
+func TestExecAcrossClustersInterrupted(t *testing.T) {
+	clusters := []clusterTarget{{ID: "a"}, {ID: "b"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner := func(ctx context.Context, cluster clusterTarget, args []string) ([]byte, []byte, error) {
+		t.Errorf("unexpected run on cluster %s after the interruption", cluster.ID)
+		return nil, nil, nil
+	}
+
+	summary := execAcrossClusters(ctx, clusters, []string{"oc", "get", "nodes"}, 1, time.Minute, runner)
+
+	assert.True(t, summary.Interrupted)
+	assert.Equal(t, 2, summary.Skipped)
+	assert.Equal(t, 0, summary.Failed)
+	assert.Equal(t, errSkippedInterrupted.Error(), summary.Results[0].Error)
+}
+
+func TestExecOnClusterInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := func(ctx context.Context, cluster clusterTarget, args []string) ([]byte, []byte, error) {
+		cancel()
+		return []byte("partial"), nil, ctx.Err()
+	}
+
+	result := execOnCluster(ctx, clusterTarget{ID: "a"}, []string{"oc", "get", "nodes"}, time.Minute, runner)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, "partial", result.Output)
+	assert.Contains(t, result.Error, "interrupted")
+}
+
+func TestExecRunWithClustersFile(t *testing.T) {
+	clustersFile := filepath.Join(t.TempDir(), "clusters.txt")
+	require.NoError(t, os.WriteFile(clustersFile, []byte("abc\ndef\n"), 0600))
+
+	var commands [][]string
+	out := &bytes.Buffer{}
+	opts := &execOptions{
+		clustersFile: clustersFile,
+		concurrency:  1,
+		args:         []string{"osdctl", "cluster", "context", "-C", "{}"},
+		out:          out,
+		runner: func(ctx context.Context, cluster clusterTarget, args []string) ([]byte, []byte, error) {
+			commands = append(commands, append([]string{cluster.ID}, args...))
+			return []byte(`{}`), nil, nil
+		},
+	}
+
+	require.NoError(t, opts.run(context.Background()))
+	assert.ElementsMatch(t, [][]string{
+		{"abc", "cluster", "context", "-C", "{}"},
+		{"def", "cluster", "context", "-C", "{}"},
+	}, commands)
+
+	var summary execSummary
+	require.NoError(t, json.Unmarshal(out.Bytes(), &summary))
+	assert.Equal(t, 2, summary.Succeeded)
+}
@@ -11,15 +11,13 @@ import (
 	"strings"
 
 	ocmsdk "github.com/openshift-online/ocm-sdk-go"
-	"github.com/openshift/osdctl/cmd/cluster"
+	"github.com/openshift/osdctl/pkg/k8s"
 	logrus "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/remotecommand"
-	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -97,42 +95,11 @@ func (k *kubeClient) Exec(ctx context.Context, namespace, pod, container string,
 		return "", errors.New("Exec is not supported on this client: no REST config or Clientset provided")
 	}
 
-	req := k.clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(pod).
-		Namespace(namespace).
-		SubResource("exec")
-
-	option := &corev1.PodExecOptions{
-		Container: container,
-		Command:   cmd,
-		Stdin:     false,
-		Stdout:    true,
-		Stderr:    true,
-		TTY:       false,
-	}
-
-	req.VersionedParams(option, scheme.ParameterCodec)
-
-	executor, err := remotecommand.NewSPDYExecutor(k.restCfg, "POST", req.URL())
+	output, err := k8s.ExecInPodOutput(ctx, k.restCfg, k.clientset, namespace, pod, container, cmd)
 	if err != nil {
-		return "", fmt.Errorf("creating SPDY executor for pod exec: %w", err)
+		return "", fmt.Errorf("executing command in pod %q: %w", pod, err)
 	}
-
-	stdoutCapture := &cluster.LogCapture{}
-	stderrCapture := &cluster.LogCapture{}
-
-	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdin:  nil,
-		Stdout: stdoutCapture,
-		Stderr: stderrCapture,
-		Tty:    false,
-	})
-	if err != nil {
-		return "", fmt.Errorf("executing command in pod %q: %w\nstderr: %s", pod, err, stderrCapture.GetStdOut())
-	}
-
-	return stdoutCapture.GetStdOut(), nil
+	return output, nil
 }
 
 // Printer writes user-facing output (command results, hints) to an output stream.
@@ -175,7 +175,7 @@ func (mg *mustGather) Run() error {
 
 				// 1. Gather logs from DT
 				gatherOptions := &dynatrace.GatherLogsOpts{Since: 72, SortOrder: "asc", DestDir: destDir}
-				if err := gatherOptions.GatherLogs(mg.clusterId); err != nil {
+				if err := gatherOptions.GatherLogs(context.Background(), mg.clusterId); err != nil {
 					fmt.Printf("failed to gather HCP dynatrace logs: %v\n", err)
 				}
 
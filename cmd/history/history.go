@@ -0,0 +1,115 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/history"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+type historyOptions struct {
+	clusterID string
+	since     time.Duration
+	output    string
+
+	path    string
+	enabled bool
+	now     func() time.Time
+	out     io.Writer
+}
+
+// NewCmdHistory implements the history command to show the locally recorded osdctl invocations
+func NewCmdHistory() *cobra.Command {
+	opts := &historyOptions{now: time.Now}
+
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the osdctl commands run from this machine",
+		Long: `Show the osdctl commands run from this machine
+
+  Recording the command history is opt-in and enabled by setting "` + history.EnabledConfigKey + `: true"
+  in the osdctl config file. Every invocation is then recorded locally with its cluster ID, flags and
+  result, the values of flags which may hold secrets (tokens, passwords, keys, ...) are redacted.
+  Nothing is sent anywhere.
+
+  Commands which exit the process themselves before their outcome can be recorded (e.g. through
+  log.Fatal) are shown with the "` + history.ResultIncomplete + `" result.
+
+  The history is meant to be shared on incident handovers, to show exactly what was run against
+  a cluster.`,
+		Example: `
+  # Show the commands run against a cluster during the last day
+  osdctl history --cluster-id ${CLUSTER_ID} --since 24h
+
+  # Export the full history as JSON
+  osdctl history -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := history.Path()
+			if err != nil {
+				return err
+			}
+			opts.path = path
+			opts.enabled = history.Enabled()
+			opts.out = cmd.OutOrStdout()
+			return opts.run()
+		},
+	}
+
+	historyCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Only show the commands run against this cluster, as passed to osdctl")
+	historyCmd.Flags().DurationVar(&opts.since, "since", 0, "Only show the commands run within this duration")
+	historyCmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+
+	return historyCmd
+}
+
+func (o *historyOptions) run() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+
+	filter := history.Filter{ClusterID: o.clusterID}
+	if o.since > 0 {
+		filter.Since = o.now().Add(-o.since)
+	}
+	entries, err := history.Load(o.path, filter)
+	if err != nil {
+		return err
+	}
+
+	if o.output == "json" {
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		if !o.enabled {
+			_, err := fmt.Fprintf(o.out, "No commands recorded, recording is disabled. Set %q in the osdctl config file to enable it.\n", history.EnabledConfigKey+": true")
+			return err
+		}
+		_, err := fmt.Fprintln(o.out, "No commands recorded")
+		return err
+	}
+
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"TIME", "USER", "CLUSTER", "RESULT", "DURATION", "COMMAND"})
+	for _, entry := range entries {
+		table.AddRow([]string{
+			entry.Time.Local().Format(time.RFC3339),
+			entry.User,
+			entry.ClusterID,
+			entry.Result,
+			entry.Duration.String(),
+			entry.CommandLine(),
+		})
+	}
+	return table.Flush()
+}
@@ -0,0 +1,41 @@
+package history
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	now := time.Now()
+	require.NoError(t, history.Append(path, history.Entry{
+		Time: now.Add(-2 * time.Hour), User: "sre", Command: "osdctl cluster context", ClusterID: "abc123",
+		Flags: map[string]string{"cluster-id": "abc123"}, Result: history.ResultSuccess, Duration: 2 * time.Second,
+	}))
+	require.NoError(t, history.Append(path, history.Entry{
+		Time: now.Add(-time.Minute), User: "sre", Command: "osdctl cluster context", ClusterID: "def456", Result: history.ResultError,
+	}))
+
+	out := &bytes.Buffer{}
+	opts := &historyOptions{clusterID: "abc123", output: "table", path: path, enabled: true, now: time.Now, out: out}
+	require.NoError(t, opts.run())
+	assert.Contains(t, out.String(), "osdctl cluster context --cluster-id=abc123")
+	assert.NotContains(t, out.String(), "def456")
+
+	out.Reset()
+	opts = &historyOptions{since: time.Hour, output: "json", path: path, now: time.Now, out: out}
+	require.NoError(t, opts.run())
+	assert.Contains(t, out.String(), `"clusterId": "def456"`)
+	assert.NotContains(t, out.String(), "abc123")
+
+	out.Reset()
+	opts = &historyOptions{output: "table", path: filepath.Join(t.TempDir(), "missing"), now: time.Now, out: out}
+	require.NoError(t, opts.run())
+	assert.Equal(t, "No commands recorded, recording is disabled. Set \"history_enabled: true\" in the osdctl config file to enable it.\n", out.String())
+}
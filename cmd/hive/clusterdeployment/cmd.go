@@ -19,6 +19,11 @@ func NewCmdClusterDeployment(streams genericclioptions.IOStreams, client client.
 
 	cdCmd.AddCommand(newCmdList(streams, client))
 	cdCmd.AddCommand(newCmdListResources(streams, client))
+	cdCmd.AddCommand(newCmdShard(streams))
+	cdCmd.AddCommand(newCmdStatus(streams))
+	cdCmd.AddCommand(newCmdSyncSets(streams))
+	cdCmd.AddCommand(newCmdPauseSyncSets(streams))
+	cdCmd.AddCommand(newCmdUnpauseSyncSets(streams))
 	return cdCmd
 }
 
@@ -0,0 +1,195 @@
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveinternalv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// syncSetPauseAnnotation makes Hive stop applying SyncSets and SelectorSyncSets to a cluster
+	syncSetPauseAnnotation = "hive.openshift.io/syncset-pause"
+	clusterIDLabel         = "api.openshift.com/id"
+
+	syncSetKind         = "SyncSet"
+	selectorSyncSetKind = "SelectorSyncSet"
+)
+
+// hiveTarget is a cluster together with the Hive shard managing it
+type hiveTarget struct {
+	cluster *cmv1.Cluster
+	hive    *cmv1.Cluster
+	shard   string
+}
+
+// syncSetStatus is the status of a single SyncSet or SelectorSyncSet applied to a cluster
+type syncSetStatus struct {
+	Kind               string
+	Name               string
+	Result             string
+	LastTransitionTime string
+	FailureMessage     string
+}
+
+// newHiveScheme returns a scheme with the Hive types needed by the inspection commands
+func newHiveScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := hivev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := hiveinternalv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+// resolveHiveTarget looks up a cluster and the Hive shard managing it. If hiveOcmUrl is set, the Hive cluster
+// is resolved in that OCM environment while the target cluster still comes from the current one.
+// The returned connection is the one to use to reach the Hive cluster and must be closed by the caller.
+func resolveHiveTarget(clusterID string, hiveOcmUrl string) (*hiveTarget, *sdk.Connection, error) {
+	targetOCM, err := utils.CreateConnection()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hiveOCM := targetOCM
+	if hiveOcmUrl != "" {
+		resolvedHiveOcmURL, err := utils.ValidateAndResolveOcmUrl(hiveOcmUrl)
+		if err != nil {
+			targetOCM.Close()
+			return nil, nil, fmt.Errorf("invalid --hive-ocm-url: %w", err)
+		}
+		hiveOCM, err = utils.CreateConnectionWithUrl(resolvedHiveOcmURL)
+		if err != nil {
+			targetOCM.Close()
+			return nil, nil, fmt.Errorf("failed to create hive OCM connection with URL '%s': %w", resolvedHiveOcmURL, err)
+		}
+		defer targetOCM.Close()
+	}
+
+	cluster, err := utils.GetClusterAnyStatus(targetOCM, clusterID)
+	if err != nil {
+		hiveOCM.Close()
+		return nil, nil, fmt.Errorf("failed to get OCM cluster info for %s: %w", clusterID, err)
+	}
+
+	shard, err := utils.GetHiveShardWithConn(cluster.ID(), targetOCM)
+	if err != nil {
+		hiveOCM.Close()
+		return nil, nil, err
+	}
+
+	hive, err := utils.GetHiveClusterWithConn(cluster.ID(), targetOCM, hiveOCM)
+	if err != nil {
+		hiveOCM.Close()
+		return nil, nil, fmt.Errorf("failed to get hive cluster for %s: %w", cluster.ID(), err)
+	}
+
+	return &hiveTarget{cluster: cluster, hive: hive, shard: shard}, hiveOCM, nil
+}
+
+// newHiveClient returns the target cluster and a client to its Hive shard.
+// The client impersonates backplane-cluster-admin when an elevation reason is given.
+func newHiveClient(clusterID string, hiveOcmUrl string, reason *elevate.Reason) (*hiveTarget, client.Client, error) {
+	target, hiveOCM, err := resolveHiveTarget(clusterID, hiveOcmUrl)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer hiveOCM.Close()
+
+	scheme, err := newHiveScheme()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var hc client.Client
+	if reason != nil {
+		hc, err = elevate.NewClientWithConn(target.hive.ID(), client.Options{Scheme: scheme}, hiveOCM, *reason)
+	} else {
+		hc, err = k8s.NewWithConn(target.hive.ID(), client.Options{Scheme: scheme}, hiveOCM)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create hive k8s client for %s: %w", target.hive.Name(), err)
+	}
+
+	return target, hc, nil
+}
+
+// getClusterDeployment returns the ClusterDeployment of a cluster from the namespace labelled with its ID
+func getClusterDeployment(ctx context.Context, hc client.Client, clusterID string) (*hivev1.ClusterDeployment, error) {
+	namespaces := &corev1.NamespaceList{}
+	if err := hc.List(ctx, namespaces, client.MatchingLabels{clusterIDLabel: clusterID}); err != nil {
+		return nil, err
+	}
+	if len(namespaces.Items) != 1 {
+		return nil, fmt.Errorf("expected 1 namespace, found %d namespaces with label: %s=%s", len(namespaces.Items), clusterIDLabel, clusterID)
+	}
+
+	cds := &hivev1.ClusterDeploymentList{}
+	if err := hc.List(ctx, cds, client.InNamespace(namespaces.Items[0].Name)); err != nil {
+		return nil, err
+	}
+	if len(cds.Items) != 1 {
+		return nil, fmt.Errorf("expected 1 clusterdeployment, found %d clusterdeployments in namespace: %s", len(cds.Items), namespaces.Items[0].Name)
+	}
+
+	return &cds.Items[0], nil
+}
+
+// getClusterSync returns the ClusterSync of a ClusterDeployment, which shares its namespace and name
+func getClusterSync(ctx context.Context, hc client.Client, cd *hivev1.ClusterDeployment) (*hiveinternalv1alpha1.ClusterSync, error) {
+	cs := &hiveinternalv1alpha1.ClusterSync{}
+	if err := hc.Get(ctx, client.ObjectKey{Namespace: cd.Namespace, Name: cd.Name}, cs); err != nil {
+		return nil, fmt.Errorf("failed to get clustersync %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	return cs, nil
+}
+
+// isSyncSetPaused returns true if Hive is not applying SyncSets to the ClusterDeployment
+func isSyncSetPaused(cd *hivev1.ClusterDeployment) bool {
+	return cd.Annotations[syncSetPauseAnnotation] == "true"
+}
+
+// syncSetStatuses flattens the SyncSet and SelectorSyncSet statuses of a ClusterSync, sorted by kind and name.
+// Only failing ones are returned if failingOnly is set.
+func syncSetStatuses(cs *hiveinternalv1alpha1.ClusterSync, failingOnly bool) []syncSetStatus {
+	var statuses []syncSetStatus
+	add := func(kind string, syncs []hiveinternalv1alpha1.SyncStatus) {
+		for _, s := range syncs {
+			if failingOnly && s.Result != hiveinternalv1alpha1.FailureSyncSetResult {
+				continue
+			}
+			statuses = append(statuses, syncSetStatus{
+				Kind:               kind,
+				Name:               s.Name,
+				Result:             string(s.Result),
+				LastTransitionTime: s.LastTransitionTime.UTC().Format("2006-01-02 15:04:05"),
+				FailureMessage:     s.FailureMessage,
+			})
+		}
+	}
+	add(syncSetKind, cs.Status.SyncSets)
+	add(selectorSyncSetKind, cs.Status.SelectorSyncSets)
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Kind != statuses[j].Kind {
+			return statuses[i].Kind > statuses[j].Kind
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
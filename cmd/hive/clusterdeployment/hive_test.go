@@ -0,0 +1,174 @@
+package clusterdeployment
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveinternalv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testClusterID = "1a2b3c4d5e6f7g8h9i0j"
+	testNamespace = "uhc-production-1a2b3c4d5e6f7g8h9i0j"
+	testCDName    = "test-cluster"
+)
+
+func newTestHiveClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme, err := newHiveScheme()
+	require.NoError(t, err)
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   testNamespace,
+		Labels: map[string]string{clusterIDLabel: testClusterID},
+	}}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, ns)...).Build()
+}
+
+func newTestClusterDeployment(annotations map[string]string) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: testCDName, Namespace: testNamespace, Annotations: annotations},
+		Spec:       hivev1.ClusterDeploymentSpec{Installed: true},
+		Status: hivev1.ClusterDeploymentStatus{
+			PowerState: hivev1.ClusterPowerStateRunning,
+			Conditions: []hivev1.ClusterDeploymentCondition{
+				{Type: hivev1.UnreachableCondition, Status: corev1.ConditionTrue, Reason: "ErrorConnectingToCluster"},
+				{Type: hivev1.ProvisionFailedCondition, Status: corev1.ConditionFalse, Reason: "ProvisionSucceeded"},
+			},
+		},
+	}
+}
+
+func newTestClusterSync() *hiveinternalv1alpha1.ClusterSync {
+	ts := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	return &hiveinternalv1alpha1.ClusterSync{
+		ObjectMeta: metav1.ObjectMeta{Name: testCDName, Namespace: testNamespace},
+		Status: hiveinternalv1alpha1.ClusterSyncStatus{
+			SyncSets: []hiveinternalv1alpha1.SyncStatus{
+				{Name: "ss-b", Result: hiveinternalv1alpha1.SuccessSyncSetResult, LastTransitionTime: ts},
+				{Name: "ss-a", Result: hiveinternalv1alpha1.FailureSyncSetResult, FailureMessage: "webhook denied", LastTransitionTime: ts},
+			},
+			SelectorSyncSets: []hiveinternalv1alpha1.SyncStatus{
+				{Name: "sss-a", Result: hiveinternalv1alpha1.FailureSyncSetResult, FailureMessage: "no matches for kind", LastTransitionTime: ts},
+				{Name: "sss-b", Result: hiveinternalv1alpha1.SuccessSyncSetResult, LastTransitionTime: ts},
+			},
+			Conditions: []hiveinternalv1alpha1.ClusterSyncCondition{
+				{Type: hiveinternalv1alpha1.ClusterSyncFailed, Status: corev1.ConditionTrue, Reason: "Failure", Message: "SyncSet ss-a is failing"},
+			},
+		},
+	}
+}
+
+func TestGetClusterDeployment(t *testing.T) {
+	hc := newTestHiveClient(t, newTestClusterDeployment(nil))
+
+	cd, err := getClusterDeployment(context.Background(), hc, testClusterID)
+	require.NoError(t, err)
+	assert.Equal(t, testCDName, cd.Name)
+
+	_, err = getClusterDeployment(context.Background(), hc, "unknown")
+	assert.Error(t, err)
+
+	_, err = getClusterDeployment(context.Background(), newTestHiveClient(t), testClusterID)
+	assert.Error(t, err)
+}
+
+func TestSyncSetStatuses(t *testing.T) {
+	cs := newTestClusterSync()
+
+	all := syncSetStatuses(cs, false)
+	require.Len(t, all, 4)
+	assert.Equal(t, []string{"ss-a", "ss-b", "sss-a", "sss-b"}, []string{all[0].Name, all[1].Name, all[2].Name, all[3].Name})
+	assert.Equal(t, syncSetKind, all[0].Kind)
+	assert.Equal(t, selectorSyncSetKind, all[2].Kind)
+
+	failing := syncSetStatuses(cs, true)
+	require.Len(t, failing, 2)
+	assert.Equal(t, "ss-a", failing[0].Name)
+	assert.Equal(t, "webhook denied", failing[0].FailureMessage)
+	assert.Equal(t, "2024-01-02 03:04:05", failing[0].LastTransitionTime)
+	assert.Equal(t, "sss-a", failing[1].Name)
+}
+
+func TestPrintStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		all         bool
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "true conditions and failing syncsets",
+			objs:        []client.Object{newTestClusterDeployment(nil), newTestClusterSync()},
+			contains:    []string{"Running", "Unreachable", "ErrorConnectingToCluster", "SyncSet ss-a is failing", "2 applied, 2 failing", "webhook denied"},
+			notContains: []string{"ProvisionFailed", "ss-b"},
+		},
+		{
+			name:     "all conditions",
+			objs:     []client.Object{newTestClusterDeployment(nil), newTestClusterSync()},
+			all:      true,
+			contains: []string{"Unreachable", "ProvisionFailed"},
+		},
+		{
+			name:     "missing clustersync",
+			objs:     []client.Object{newTestClusterDeployment(map[string]string{syncSetPauseAnnotation: "true"})},
+			contains: []string{"No ClusterSync found", "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			require.NoError(t, printStatus(context.Background(), out, newTestHiveClient(t, tt.objs...), testClusterID, tt.all))
+			for _, s := range tt.contains {
+				assert.Contains(t, out.String(), s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, out.String(), s)
+			}
+		})
+	}
+}
+
+func TestSetSyncSetPause(t *testing.T) {
+	hc := newTestHiveClient(t, newTestClusterDeployment(nil))
+	ctx := context.Background()
+
+	cd, err := getClusterDeployment(ctx, hc, testClusterID)
+	require.NoError(t, err)
+	assert.False(t, isSyncSetPaused(cd))
+
+	require.NoError(t, setSyncSetPause(ctx, hc, cd, true))
+	cd, err = getClusterDeployment(ctx, hc, testClusterID)
+	require.NoError(t, err)
+	assert.True(t, isSyncSetPaused(cd))
+
+	require.NoError(t, setSyncSetPause(ctx, hc, cd, false))
+	cd, err = getClusterDeployment(ctx, hc, testClusterID)
+	require.NoError(t, err)
+	assert.False(t, isSyncSetPaused(cd))
+	assert.NotContains(t, cd.Annotations, syncSetPauseAnnotation)
+}
+
+func TestPauseSyncSetsValidatesReason(t *testing.T) {
+	for _, pause := range []bool{true, false} {
+		cmd := newCmdSetSyncSetPause(genericclioptions.IOStreams{}, pause)
+		assert.Equal(t, danger.Medium, danger.LevelOf(cmd))
+
+		o := &pauseOptions{clusterID: testClusterID, reason: "OHSS1234", pause: pause}
+		err := o.run(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "malformed ticket")
+	}
+}
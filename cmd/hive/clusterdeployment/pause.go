@@ -0,0 +1,136 @@
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pauseOptions defines the struct for running the pause-syncsets and unpause-syncsets commands
+type pauseOptions struct {
+	clusterID  string
+	hiveOcmUrl string
+	reason     string
+	pause      bool
+
+	genericclioptions.IOStreams
+}
+
+// newCmdPauseSyncSets implements the pause-syncsets command
+func newCmdPauseSyncSets(streams genericclioptions.IOStreams) *cobra.Command {
+	return newCmdSetSyncSetPause(streams, true)
+}
+
+// newCmdUnpauseSyncSets implements the unpause-syncsets command
+func newCmdUnpauseSyncSets(streams genericclioptions.IOStreams) *cobra.Command {
+	return newCmdSetSyncSetPause(streams, false)
+}
+
+func newCmdSetSyncSetPause(streams genericclioptions.IOStreams, pause bool) *cobra.Command {
+	ops := &pauseOptions{IOStreams: streams, pause: pause}
+	cmd := &cobra.Command{
+		Use:   "pause-syncsets",
+		Short: "Pause SyncSet and SelectorSyncSet reconciliation of a cluster",
+		Long: `Pause SyncSet and SelectorSyncSet reconciliation of a cluster
+
+  Annotates the cluster's ClusterDeployment with ` + syncSetPauseAnnotation + `=true so that Hive stops applying
+  SyncSets and SelectorSyncSets to the cluster. This requires elevation on the Hive shard. Make sure to unpause
+  the cluster once done, e.g. with "osdctl hive clusterdeployment unpause-syncsets".`,
+		Example: `  # Pause syncsets of a cluster
+  osdctl hive clusterdeployment pause-syncsets --cluster-id ${CLUSTER_ID} --reason "${OHSS}"`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run(context.Background())
+		},
+	}
+	if !pause {
+		cmd.Use = "unpause-syncsets"
+		cmd.Short = "Resume SyncSet and SelectorSyncSet reconciliation of a cluster"
+		cmd.Long = `Resume SyncSet and SelectorSyncSet reconciliation of a cluster
+
+  Removes the ` + syncSetPauseAnnotation + ` annotation from the cluster's ClusterDeployment so that Hive applies
+  SyncSets and SelectorSyncSets to the cluster again. This requires elevation on the Hive shard.`
+		cmd.Example = `  # Resume syncsets of a cluster
+  osdctl hive clusterdeployment unpause-syncsets --cluster-id ${CLUSTER_ID} --reason "${OHSS}"`
+	}
+
+	cmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	cmd.Flags().StringVar(&ops.hiveOcmUrl, "hive-ocm-url", "", "(optional) OCM environment URL for Hive operations. Aliases: 'production', 'staging', 'integration'")
+	cmd.Flags().StringVar(&ops.reason, elevate.ReasonFlag, "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	_ = cmd.MarkFlagRequired("cluster-id")
+	_ = cmd.MarkFlagRequired(elevate.ReasonFlag)
+
+	return danger.Annotate(cmd, danger.Medium)
+}
+
+func (o *pauseOptions) action() string {
+	if o.pause {
+		return "pause"
+	}
+	return "unpause"
+}
+
+func (o *pauseOptions) run(ctx context.Context) error {
+	if err := utils.ValidateReason(elevate.ReasonFlag, o.reason); err != nil {
+		return err
+	}
+
+	reason := &elevate.Reason{
+		Ticket:        o.reason,
+		Justification: fmt.Sprintf("%s syncsets of cluster %s", o.action(), o.clusterID),
+		Command:       "hive clusterdeployment " + o.action() + "-syncsets",
+	}
+	target, hc, err := newHiveClient(o.clusterID, o.hiveOcmUrl, reason)
+	if err != nil {
+		return err
+	}
+
+	cd, err := getClusterDeployment(ctx, hc, target.cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	if isSyncSetPaused(cd) == o.pause {
+		fmt.Fprintf(o.Out, "SyncSets of %s/%s are already %sd, nothing to do\n", cd.Namespace, cd.Name, o.action())
+		return nil
+	}
+
+	fmt.Fprintf(o.Out, "About to %s syncsets of cluster %s (%s) via ClusterDeployment %s/%s on %s\n", o.action(), target.cluster.Name(), target.cluster.ID(), cd.Namespace, cd.Name, target.hive.Name())
+	if ok, err := danger.Confirm(danger.Medium, target.cluster); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("aborting the %s of the syncsets", o.action())
+	}
+
+	if err := setSyncSetPause(ctx, hc, cd, o.pause); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "SyncSets of %s/%s %sd\n", cd.Namespace, cd.Name, o.action())
+	return nil
+}
+
+// setSyncSetPause adds or removes the syncset pause annotation of a ClusterDeployment
+func setSyncSetPause(ctx context.Context, hc client.Client, cd *hivev1.ClusterDeployment, pause bool) error {
+	patch := client.MergeFrom(cd.DeepCopy())
+	if pause {
+		if cd.Annotations == nil {
+			cd.Annotations = map[string]string{}
+		}
+		cd.Annotations[syncSetPauseAnnotation] = "true"
+	} else {
+		delete(cd.Annotations, syncSetPauseAnnotation)
+	}
+
+	if err := hc.Patch(ctx, cd, patch); err != nil {
+		return fmt.Errorf("failed to patch clusterdeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	return nil
+}
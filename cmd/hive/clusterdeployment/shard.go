@@ -0,0 +1,61 @@
+package clusterdeployment
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// shardOptions defines the struct for running the shard command
+type shardOptions struct {
+	clusterID  string
+	hiveOcmUrl string
+
+	genericclioptions.IOStreams
+}
+
+// newCmdShard implements the shard command to locate the Hive shard managing a cluster
+func newCmdShard(streams genericclioptions.IOStreams) *cobra.Command {
+	ops := &shardOptions{IOStreams: streams}
+	shardCmd := &cobra.Command{
+		Use:   "shard",
+		Short: "Locate the Hive shard managing a cluster",
+		Example: `  # Show the Hive shard of a cluster
+  osdctl hive clusterdeployment shard --cluster-id ${CLUSTER_ID}
+
+  # While connected to staging OCM, locate the shard in the production Hive environment
+  osdctl hive clusterdeployment shard --cluster-id ${CLUSTER_ID} --hive-ocm-url production`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run()
+		},
+	}
+	shardCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	shardCmd.Flags().StringVar(&ops.hiveOcmUrl, "hive-ocm-url", "", "(optional) OCM environment URL for Hive operations. Aliases: 'production', 'staging', 'integration'")
+	_ = shardCmd.MarkFlagRequired("cluster-id")
+
+	return shardCmd
+}
+
+func (o *shardOptions) run() error {
+	target, hiveOCM, err := resolveHiveTarget(o.clusterID, o.hiveOcmUrl)
+	if err != nil {
+		return err
+	}
+	hiveOCM.Close()
+
+	return printShard(o.Out, target)
+}
+
+func printShard(w io.Writer, target *hiveTarget) error {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"Cluster ID:", target.cluster.ID()})
+	p.AddRow([]string{"Cluster Name:", target.cluster.Name()})
+	p.AddRow([]string{"Hive Shard:", target.shard})
+	p.AddRow([]string{"Hive Cluster:", fmt.Sprintf("%s (%s)", target.hive.Name(), target.hive.ID())})
+	return p.Flush()
+}
@@ -0,0 +1,148 @@
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveinternalv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// statusOptions defines the struct for running the status command
+type statusOptions struct {
+	clusterID  string
+	hiveOcmUrl string
+	all        bool
+
+	genericclioptions.IOStreams
+}
+
+// newCmdStatus implements the status command to show the ClusterDeployment and ClusterSync status of a cluster
+func newCmdStatus(streams genericclioptions.IOStreams) *cobra.Command {
+	ops := &statusOptions{IOStreams: streams}
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the ClusterDeployment and ClusterSync status of a cluster",
+		Long: `Show the ClusterDeployment and ClusterSync status of a cluster
+
+  Prints the Hive shard, the ClusterDeployment state (installed, power state, syncset pause) and its conditions,
+  followed by the ClusterSync status and the SyncSets and SelectorSyncSets failing to apply. By default only
+  ClusterDeployment conditions which are True are printed, use --all to print all of them.`,
+		Example: `  # Show the Hive status of a cluster
+  osdctl hive clusterdeployment status --cluster-id ${CLUSTER_ID}
+
+  # Include all ClusterDeployment conditions
+  osdctl hive clusterdeployment status --cluster-id ${CLUSTER_ID} --all`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run(context.Background())
+		},
+	}
+	statusCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	statusCmd.Flags().StringVar(&ops.hiveOcmUrl, "hive-ocm-url", "", "(optional) OCM environment URL for Hive operations. Aliases: 'production', 'staging', 'integration'")
+	statusCmd.Flags().BoolVar(&ops.all, "all", false, "Print all ClusterDeployment conditions instead of only the ones which are True")
+	_ = statusCmd.MarkFlagRequired("cluster-id")
+
+	return statusCmd
+}
+
+func (o *statusOptions) run(ctx context.Context) error {
+	target, hc, err := newHiveClient(o.clusterID, o.hiveOcmUrl, nil)
+	if err != nil {
+		return err
+	}
+	if err := printShard(o.Out, target); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.Out)
+
+	return printStatus(ctx, o.Out, hc, target.cluster.ID(), o.all)
+}
+
+func printStatus(ctx context.Context, w io.Writer, hc client.Client, clusterID string, all bool) error {
+	cd, err := getClusterDeployment(ctx, hc, clusterID)
+	if err != nil {
+		return err
+	}
+	printClusterDeployment(w, cd, all)
+	fmt.Fprintln(w)
+
+	cs, err := getClusterSync(ctx, hc, cd)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(w, "No ClusterSync found for %s/%s\n", cd.Namespace, cd.Name)
+			return nil
+		}
+		return err
+	}
+	return printClusterSync(w, cs)
+}
+
+func printClusterDeployment(w io.Writer, cd *hivev1.ClusterDeployment, all bool) {
+	fmt.Fprintf(w, "ClusterDeployment %s/%s\n", cd.Namespace, cd.Name)
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"Installed:", strconv.FormatBool(cd.Spec.Installed)})
+	p.AddRow([]string{"Power State:", string(cd.Status.PowerState)})
+	p.AddRow([]string{"SyncSets Paused:", strconv.FormatBool(isSyncSetPaused(cd))})
+	_ = p.Flush()
+
+	conditions := make([]hivev1.ClusterDeploymentCondition, 0, len(cd.Status.Conditions))
+	for _, c := range cd.Status.Conditions {
+		if all || c.Status == corev1.ConditionTrue {
+			conditions = append(conditions, c)
+		}
+	}
+	sort.SliceStable(conditions, func(i, j int) bool {
+		return conditions[i].Type < conditions[j].Type
+	})
+	if len(conditions) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	p = printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"TYPE", "STATUS", "REASON", "LAST TRANSITION", "MESSAGE"})
+	for _, c := range conditions {
+		p.AddRow([]string{string(c.Type), string(c.Status), c.Reason, c.LastTransitionTime.UTC().Format("2006-01-02 15:04:05"), c.Message})
+	}
+	_ = p.Flush()
+}
+
+func printClusterSync(w io.Writer, cs *hiveinternalv1alpha1.ClusterSync) error {
+	fmt.Fprintf(w, "ClusterSync %s/%s\n", cs.Namespace, cs.Name)
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	for _, c := range cs.Status.Conditions {
+		if c.Type == hiveinternalv1alpha1.ClusterSyncFailed {
+			p.AddRow([]string{"Failed:", string(c.Status)})
+			p.AddRow([]string{"Reason:", c.Reason})
+			if c.Status == corev1.ConditionTrue {
+				p.AddRow([]string{"Message:", c.Message})
+			}
+		}
+	}
+	if cs.Status.FirstSuccessTime != nil {
+		p.AddRow([]string{"First Success:", cs.Status.FirstSuccessTime.UTC().Format("2006-01-02 15:04:05")})
+	}
+	all := syncSetStatuses(cs, false)
+	failing := syncSetStatuses(cs, true)
+	p.AddRow([]string{"SyncSets:", fmt.Sprintf("%d applied, %d failing", len(all)-len(failing), len(failing))})
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	if len(failing) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	return printSyncSetStatuses(w, failing)
+}
@@ -0,0 +1,81 @@
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// syncSetsOptions defines the struct for running the syncsets command
+type syncSetsOptions struct {
+	clusterID  string
+	hiveOcmUrl string
+	failing    bool
+
+	genericclioptions.IOStreams
+}
+
+// newCmdSyncSets implements the syncsets command to list the SyncSets applied to a cluster
+func newCmdSyncSets(streams genericclioptions.IOStreams) *cobra.Command {
+	ops := &syncSetsOptions{IOStreams: streams}
+	syncSetsCmd := &cobra.Command{
+		Use:   "syncsets",
+		Short: "List the SyncSets and SelectorSyncSets applied to a cluster and their result",
+		Example: `  # List the SyncSets and SelectorSyncSets of a cluster
+  osdctl hive clusterdeployment syncsets --cluster-id ${CLUSTER_ID}
+
+  # Only list the ones failing to apply
+  osdctl hive clusterdeployment syncsets --cluster-id ${CLUSTER_ID} --failing`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run(context.Background())
+		},
+	}
+	syncSetsCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	syncSetsCmd.Flags().StringVar(&ops.hiveOcmUrl, "hive-ocm-url", "", "(optional) OCM environment URL for Hive operations. Aliases: 'production', 'staging', 'integration'")
+	syncSetsCmd.Flags().BoolVar(&ops.failing, "failing", false, "Only list SyncSets and SelectorSyncSets failing to apply")
+	_ = syncSetsCmd.MarkFlagRequired("cluster-id")
+
+	return syncSetsCmd
+}
+
+func (o *syncSetsOptions) run(ctx context.Context) error {
+	target, hc, err := newHiveClient(o.clusterID, o.hiveOcmUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	cd, err := getClusterDeployment(ctx, hc, target.cluster.ID())
+	if err != nil {
+		return err
+	}
+	cs, err := getClusterSync(ctx, hc, cd)
+	if err != nil {
+		return err
+	}
+
+	statuses := syncSetStatuses(cs, o.failing)
+	if len(statuses) == 0 {
+		if o.failing {
+			fmt.Fprintf(o.Out, "No failing SyncSets for %s/%s\n", cd.Namespace, cd.Name)
+		} else {
+			fmt.Fprintf(o.Out, "No SyncSets applied to %s/%s\n", cd.Namespace, cd.Name)
+		}
+		return nil
+	}
+	return printSyncSetStatuses(o.Out, statuses)
+}
+
+func printSyncSetStatuses(w io.Writer, statuses []syncSetStatus) error {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"KIND", "NAME", "RESULT", "LAST TRANSITION", "FAILURE MESSAGE"})
+	for _, s := range statuses {
+		p.AddRow([]string{s.Kind, s.Name, s.Result, s.LastTransitionTime, s.FailureMessage})
+	}
+	return p.Flush()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -18,6 +19,13 @@ const (
 	awsResourceName     = "red-hat-sre-jumphost"
 	publicSubnetTagKey  = "kubernetes.io/role/elb"
 	privateSubnetTagKey = "kubernetes.io/role/internal-elb"
+
+	// expiresAtTagKey tags jumphost resources with the RFC3339 time after which "osdctl jumphost gc" may clean them up
+	expiresAtTagKey = "red-hat-sre-jumphost-expires-at"
+	// noExpiry is the value of the expiry tag of jumphosts created with --ttl 0
+	noExpiry = "never"
+	// defaultTTL is how long a jumphost lives before it shuts itself down and is considered expired
+	defaultTTL = 8 * time.Hour
 )
 
 func NewCmdJumphost() *cobra.Command {
@@ -29,6 +37,8 @@ func NewCmdJumphost() *cobra.Command {
 	jumphost.AddCommand(
 		newCmdCreateJumphost(),
 		newCmdDeleteJumphost(),
+		newCmdGcJumphost(),
+		newCmdConnectJumphost(),
 	)
 
 	return jumphost
@@ -42,6 +52,9 @@ type jumphostConfig struct {
 
 	keyFilepath string
 	ec2PublicIp string
+
+	// ttl is how long a created jumphost lives, 0 disables its expiry and automatic shutdown
+	ttl time.Duration
 }
 
 type jumphostAWSClient interface {
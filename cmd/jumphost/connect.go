@@ -0,0 +1,153 @@
+package jumphost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/spf13/cobra"
+)
+
+const jumphostUser = "ec2-user"
+
+func newCmdConnectJumphost() *cobra.Command {
+	var (
+		subnetId  string
+		keyFile   string
+		record    bool
+		recordDir string
+	)
+
+	connect := &cobra.Command{
+		Use:          "connect",
+		SilenceUsage: true,
+		Short:        "SSH to a jumphost created by `osdctl jumphost create`, optionally recording the session",
+		Long: `SSH to a jumphost created by "osdctl jumphost create", optionally recording the session
+
+  This command looks up the running jumphost in the provided subnet's VPC and opens an
+  SSH session to it. With --record, a transcript of the session is written locally
+  with "script" for audit purposes.`,
+		Example: `
+  # Connect to a jumphost
+  osdctl jumphost connect --subnet-id public-subnet-id --key-file /tmp/jumphost_123.pem
+
+  # Connect to a jumphost and record the session transcript
+  osdctl jumphost connect --subnet-id public-subnet-id --key-file /tmp/jumphost_123.pem --record`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			j, err := initJumphostConfig(context.TODO(), "", subnetId)
+			if err != nil {
+				return err
+			}
+
+			return j.runConnect(context.TODO(), keyFile, record, recordDir)
+		},
+	}
+
+	connect.Flags().StringVar(&subnetId, "subnet-id", "", "subnet id the jumphost was created in")
+	connect.Flags().StringVar(&keyFile, "key-file", "", "path to the jumphost private key printed by \"osdctl jumphost create\"")
+	connect.Flags().BoolVar(&record, "record", false, "record a transcript of the SSH session")
+	connect.Flags().StringVar(&recordDir, "record-dir", "", "directory to store session transcripts in (default $XDG_DATA_HOME/osdctl/jumphost-sessions, or ~/.local/share/osdctl/jumphost-sessions)")
+
+	_ = connect.MarkFlagRequired("subnet-id")
+	_ = connect.MarkFlagRequired("key-file")
+
+	return connect
+}
+
+// defaultRecordDir returns the default directory session transcripts are stored in
+func defaultRecordDir() (string, error) {
+	dataDir, err := osdctlConfig.DataDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the session recording directory, set --record-dir: %w", err)
+	}
+	return filepath.Join(dataDir, "jumphost-sessions"), nil
+}
+
+func (j *jumphostConfig) runConnect(ctx context.Context, keyFile string, record bool, recordDir string) error {
+	instance, err := j.findRunningJumphost(ctx)
+	if err != nil {
+		return err
+	}
+	j.ec2PublicIp = aws.ToString(instance.PublicIpAddress)
+	if j.ec2PublicIp == "" {
+		return fmt.Errorf("jumphost %s has no public ip", aws.ToString(instance.InstanceId))
+	}
+
+	sshArgs := []string{"-i", keyFile, fmt.Sprintf("%s@%s", jumphostUser, j.ec2PublicIp)}
+	name, args := "ssh", sshArgs
+	if record {
+		if recordDir == "" {
+			if recordDir, err = defaultRecordDir(); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(recordDir, 0700); err != nil {
+			return fmt.Errorf("failed to create session recording directory: %w", err)
+		}
+		transcript := filepath.Join(recordDir, fmt.Sprintf("%s-%s.log", aws.ToString(instance.InstanceId), time.Now().UTC().Format("20060102T150405Z")))
+		name, args = recordedCommand(runtime.GOOS, transcript, append([]string{"ssh"}, sshArgs...))
+		log.Printf("recording session transcript to %s", transcript)
+	}
+
+	cmd := exec.Command(name, args...) // #nosec G204 -- arguments are built from the jumphost lookup and user provided flags
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// findRunningJumphost returns the running jumphost EC2 instance in the provided subnet's VPC
+func (j *jumphostConfig) findRunningJumphost(ctx context.Context) (*types.Instance, error) {
+	vpcId, err := j.findVpcId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := j.awsClient.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		Filters: append(generateTagFilters(j.tags), []types.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []string{vpcId},
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{string(types.InstanceStateNameRunning)},
+			},
+		}...),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Reservations) == 0 || len(resp.Reservations[0].Instances) == 0 {
+		return nil, errors.New("no running jumphost found, create one with \"osdctl jumphost create\"")
+	}
+
+	return &resp.Reservations[0].Instances[0], nil
+}
+
+// recordedCommand wraps a command with "script" so that a transcript of the session is written to a file.
+// The BSD script shipped with macOS takes the command as arguments, util-linux script takes it as a single string.
+func recordedCommand(goos string, transcript string, command []string) (string, []string) {
+	if goos == "darwin" {
+		return "script", append([]string{"-q", transcript}, command...)
+	}
+
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return "script", []string{"-q", "-c", strings.Join(quoted, " "), transcript}
+}
@@ -2,10 +2,12 @@ package jumphost
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net"
 	"net/http"
 	"os"
@@ -22,6 +24,7 @@ func newCmdCreateJumphost() *cobra.Command {
 	var (
 		clusterId string
 		subnetId  string
+		ttl       time.Duration
 	)
 
 	create := &cobra.Command{
@@ -38,6 +41,10 @@ func newCmdCreateJumphost() *cobra.Command {
 
   When the cluster's API server is accessible, prefer "oc debug node".
 
+  Created resources are tagged with an expiry time based on --ttl and the jumphost
+  shuts itself down, which terminates it, once the TTL has elapsed. Leftover
+  resources of expired jumphosts can be cleaned up with "osdctl jumphost gc".
+
   Requires these permissions:
   {
     "Version": "2012-10-17",
@@ -66,19 +73,28 @@ func newCmdCreateJumphost() *cobra.Command {
 		Example: `
   # Create and delete a jumphost
   osdctl jumphost create --subnet-id public-subnet-id
-  osdctl jumphost delete --subnet-id public-subnet-id`,
+  osdctl jumphost delete --subnet-id public-subnet-id
+
+  # Create a jumphost which terminates itself after 2 hours
+  osdctl jumphost create --subnet-id public-subnet-id --ttl 2h`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if ttl < 0 {
+				return errors.New("--ttl must not be negative")
+			}
+
 			j, err := initJumphostConfig(context.TODO(), clusterId, subnetId)
 			if err != nil {
 				return err
 			}
+			j.ttl = ttl
 
 			return j.runCreate(context.TODO())
 		},
 	}
 
 	create.Flags().StringVar(&subnetId, "subnet-id", "", "public subnet id to create a jumphost in")
+	create.Flags().DurationVar(&ttl, "ttl", defaultTTL, "time after which the jumphost shuts down and terminates itself, 0 disables it")
 
 	_ = create.MarkFlagRequired("subnet-id")
 
@@ -114,7 +130,7 @@ func (j *jumphostConfig) createKeyPair(ctx context.Context) error {
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeKeyPair,
-				Tags:         j.tags,
+				Tags:         j.creationTags(time.Now()),
 			},
 		},
 	})
@@ -160,7 +176,7 @@ func (j *jumphostConfig) createSecurityGroup(ctx context.Context) (string, error
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeSecurityGroup,
-				Tags:         j.tags,
+				Tags:         j.creationTags(time.Now()),
 			},
 		},
 		VpcId: aws.String(vpcId),
@@ -222,12 +238,12 @@ func (j *jumphostConfig) createEc2Jumphost(ctx context.Context, securityGroupId
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeInstance,
-				Tags:         j.tags,
+				Tags:         j.creationTags(time.Now()),
 			},
 		},
-		// TODO: It would be nice to add user data which shut down the EC2 instance after 8 hours.
-		// This would automatically terminate the EC2 instance in case we forget to clean up.
-		UserData: nil,
+		// Shutting down the instance terminates it thanks to InstanceInitiatedShutdownBehavior,
+		// so the jumphost is cleaned up even if we forget to delete it.
+		UserData: j.shutdownUserData(),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create jumphost EC2 instace: %w", err)
@@ -255,6 +271,31 @@ func (j *jumphostConfig) createEc2Jumphost(ctx context.Context, securityGroupId
 	return nil
 }
 
+// creationTags returns the tags to create jumphost resources with, which include an expiry tag, set to noExpiry
+// without a TTL so that gc doesn't fall back to the default TTL.
+// The expiry tag is not part of j.tags as those are also used as filters to find existing jumphost resources.
+func (j *jumphostConfig) creationTags(now time.Time) []types.Tag {
+	expiresAt := noExpiry
+	if j.ttl > 0 {
+		expiresAt = now.Add(j.ttl).UTC().Format(time.RFC3339)
+	}
+	return append(append([]types.Tag{}, j.tags...), types.Tag{
+		Key:   aws.String(expiresAtTagKey),
+		Value: aws.String(expiresAt),
+	})
+}
+
+// shutdownUserData returns base64 encoded user data scheduling a shutdown of the instance once its TTL has elapsed
+func (j *jumphostConfig) shutdownUserData() *string {
+	if j.ttl <= 0 {
+		return nil
+	}
+
+	minutes := int(math.Ceil(j.ttl.Minutes()))
+	script := fmt.Sprintf("#!/bin/bash\nshutdown -h +%d\n", minutes)
+	return aws.String(base64.StdEncoding.EncodeToString([]byte(script)))
+}
+
 // assembleNextSteps returns a string with helpful next steps for connecting to the created jumphost
 func (j *jumphostConfig) assembleNextSteps() string {
 	if j.ec2PublicIp == "" {
@@ -307,7 +348,7 @@ func (j *jumphostConfig) allowJumphostSshFromIp(ctx context.Context, groupId str
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeSecurityGroupRule,
-				Tags:         j.tags,
+				Tags:         j.creationTags(time.Now()),
 			},
 		},
 		ToPort: aws.Int32(22),
@@ -124,6 +124,12 @@ func (j *jumphostConfig) deleteSecurityGroup(ctx context.Context) error {
 		return err
 	}
 
+	return j.deleteSecurityGroupInVpc(ctx, vpcId)
+}
+
+// deleteSecurityGroupInVpc searches for security groups by the expected tag filter within a VPC and
+// deletes the first matching security group
+func (j *jumphostConfig) deleteSecurityGroupInVpc(ctx context.Context, vpcId string) error {
 	resp, err := j.awsClient.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
 		Filters: append(generateTagFilters(j.tags), []types.Filter{
 			{
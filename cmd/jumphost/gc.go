@@ -0,0 +1,262 @@
+package jumphost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newCmdGcJumphost() *cobra.Command {
+	var dryRun bool
+
+	gc := &cobra.Command{
+		Use:          "gc",
+		SilenceUsage: true,
+		Short:        "Clean up expired jumphosts created by `osdctl jumphost create`",
+		Long: `Clean up expired jumphosts created by "osdctl jumphost create"
+
+  This command searches the AWS account and region of the current credentials for
+  jumphosts whose expiry tag (` + expiresAtTagKey + `) is in the past
+  and terminates them. Jumphosts created without an expiry tag are considered expired
+  once they have been running for longer than the default TTL (` + defaultTTL.String() + `),
+  jumphosts created with "--ttl 0" never expire. Security groups left behind in VPCs
+  without any remaining jumphost are deleted, as well as the key pair once no jumphost
+  remains in the account, including the ones of jumphosts which terminated themselves
+  once their TTL elapsed. The security groups of jumphosts still shutting down are
+  kept until a later run, and resources failing to be deleted don't prevent the
+  others from being cleaned up.
+
+  Requires the same permissions as "osdctl jumphost delete".`,
+		Example: `
+  # List expired jumphosts without deleting anything
+  osdctl jumphost gc --dry-run
+
+  # Clean up expired jumphosts
+  osdctl jumphost gc`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			j, err := initJumphostConfig(context.TODO(), "", "")
+			if err != nil {
+				return err
+			}
+
+			return j.runGc(context.TODO(), time.Now(), dryRun)
+		},
+	}
+
+	gc.Flags().BoolVar(&dryRun, "dry-run", false, "only list expired jumphosts without deleting them")
+
+	return gc
+}
+
+// runGc terminates expired jumphosts and cleans up the security groups and key pairs which are no longer used,
+// including the ones left behind by jumphosts which terminated themselves once their TTL elapsed
+func (j *jumphostConfig) runGc(ctx context.Context, now time.Time, dryRun bool) error {
+	instances, err := j.listJumphostInstances(ctx)
+	if err != nil {
+		return err
+	}
+
+	var expired, remaining, shuttingDown []types.Instance
+	for _, instance := range instances {
+		if instance.State != nil && instance.State.Name == types.InstanceStateNameShuttingDown {
+			shuttingDown = append(shuttingDown, instance)
+		} else if isJumphostExpired(instance, now) {
+			expired = append(expired, instance)
+		} else {
+			remaining = append(remaining, instance)
+		}
+	}
+
+	// Security groups can only be deleted once no instance uses them anymore, which includes the jumphosts
+	// still shutting down, e.g. the ones terminating themselves
+	usedVpcIds := map[string]bool{}
+	for _, instance := range append(remaining, shuttingDown...) {
+		if instance.VpcId != nil {
+			usedVpcIds[*instance.VpcId] = true
+		}
+	}
+	securityGroups, err := j.listJumphostSecurityGroups(ctx)
+	if err != nil {
+		return err
+	}
+	var unusedSecurityGroups []types.SecurityGroup
+	for _, securityGroup := range securityGroups {
+		if !usedVpcIds[aws.ToString(securityGroup.VpcId)] {
+			unusedSecurityGroups = append(unusedSecurityGroups, securityGroup)
+		}
+	}
+
+	// The key pairs are shared by all jumphosts of the account
+	var unusedKeyPairs []types.KeyPairInfo
+	if len(remaining) == 0 {
+		unusedKeyPairs, err = j.listJumphostKeyPairs(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(expired) == 0 && len(unusedSecurityGroups) == 0 && len(unusedKeyPairs) == 0 {
+		log.Printf("no expired jumphosts found, %d jumphost(s) still active", len(remaining))
+		return nil
+	}
+
+	var instanceIds []string
+	for _, instance := range expired {
+		log.Printf("found expired jumphost: %s (vpc: %s, expires at: %s)", aws.ToString(instance.InstanceId), aws.ToString(instance.VpcId), jumphostExpiry(instance))
+		instanceIds = append(instanceIds, aws.ToString(instance.InstanceId))
+	}
+	for _, securityGroup := range unusedSecurityGroups {
+		log.Printf("found unused security group: %s (vpc: %s)", aws.ToString(securityGroup.GroupId), aws.ToString(securityGroup.VpcId))
+	}
+	for _, keyPair := range unusedKeyPairs {
+		log.Printf("found unused key pair: %s (%s)", aws.ToString(keyPair.KeyName), aws.ToString(keyPair.KeyPairId))
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if !prompt.Confirm(fmt.Sprintf("Terminate %d expired jumphost(s) and delete %d security group(s) and %d key pair(s)?", len(instanceIds), len(unusedSecurityGroups), len(unusedKeyPairs)), false) {
+		return errors.New("aborted")
+	}
+
+	if len(instanceIds) > 0 {
+		if _, err := j.awsClient.TerminateInstances(ctx, &ec2.TerminateInstancesInput{InstanceIds: instanceIds}); err != nil {
+			return fmt.Errorf("failed to terminate jumphosts: %w", err)
+		}
+
+		log.Println("waiting for the EC2 instances to be in a terminated state")
+		waiter := ec2.NewInstanceTerminatedWaiter(j.awsClient)
+		if err := waiter.Wait(ctx, &ec2.DescribeInstancesInput{InstanceIds: instanceIds}, 5*time.Minute); err != nil {
+			return err
+		}
+	}
+
+	// A resource failing to be deleted doesn't prevent cleaning up the others, the next run retries it
+	var errs []error
+	for _, securityGroup := range unusedSecurityGroups {
+		log.Printf("deleting security group: %s (%s)", aws.ToString(securityGroup.GroupName), aws.ToString(securityGroup.GroupId))
+		if _, err := j.awsClient.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{GroupId: securityGroup.GroupId}); err != nil {
+			log.Printf("failed to delete security group %s: %v", aws.ToString(securityGroup.GroupId), err)
+			errs = append(errs, fmt.Errorf("failed to delete security group %s: %w", aws.ToString(securityGroup.GroupId), err))
+		}
+	}
+
+	for _, keyPair := range unusedKeyPairs {
+		log.Printf("deleting key pair: %s (%s)", aws.ToString(keyPair.KeyName), aws.ToString(keyPair.KeyPairId))
+		if _, err := j.awsClient.DeleteKeyPair(ctx, &ec2.DeleteKeyPairInput{KeyPairId: keyPair.KeyPairId}); err != nil {
+			log.Printf("failed to delete key pair %s: %v", aws.ToString(keyPair.KeyPairId), err)
+			errs = append(errs, fmt.Errorf("failed to delete key pair %s: %w", aws.ToString(keyPair.KeyPairId), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// listJumphostSecurityGroups returns the jumphost security groups of all VPCs
+func (j *jumphostConfig) listJumphostSecurityGroups(ctx context.Context) ([]types.SecurityGroup, error) {
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: append(generateTagFilters(j.tags), types.Filter{
+			Name:   aws.String("group-name"),
+			Values: []string{awsResourceName},
+		}),
+	}
+
+	var securityGroups []types.SecurityGroup
+	for {
+		resp, err := j.awsClient.DescribeSecurityGroups(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe security groups: %w", err)
+		}
+		securityGroups = append(securityGroups, resp.SecurityGroups...)
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+
+	sort.Slice(securityGroups, func(a, b int) bool {
+		return aws.ToString(securityGroups[a].GroupId) < aws.ToString(securityGroups[b].GroupId)
+	})
+	return securityGroups, nil
+}
+
+// listJumphostKeyPairs returns the jumphost key pairs of the account
+func (j *jumphostConfig) listJumphostKeyPairs(ctx context.Context) ([]types.KeyPairInfo, error) {
+	resp, err := j.awsClient.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{
+		Filters: generateTagFilters(j.tags),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe key pair: %w", err)
+	}
+	return resp.KeyPairs, nil
+}
+
+// listJumphostInstances returns all jumphost EC2 instances which aren't terminated yet, including the shutting down ones
+func (j *jumphostConfig) listJumphostInstances(ctx context.Context) ([]types.Instance, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: append(generateTagFilters(j.tags), types.Filter{
+			Name: aws.String("instance-state-name"),
+			Values: []string{
+				string(types.InstanceStateNamePending),
+				string(types.InstanceStateNameRunning),
+				string(types.InstanceStateNameShuttingDown),
+				string(types.InstanceStateNameStopping),
+				string(types.InstanceStateNameStopped),
+			},
+		}),
+	}
+
+	var instances []types.Instance
+	for {
+		resp, err := j.awsClient.DescribeInstances(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+		for _, reservation := range resp.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+
+	return instances, nil
+}
+
+// jumphostExpiry returns the expiry time of a jumphost, falling back to its launch time plus the default TTL
+// for jumphosts created without an expiry tag. Jumphosts created with --ttl 0 never expire, their expiry is zero.
+func jumphostExpiry(instance types.Instance) time.Time {
+	for _, tag := range instance.Tags {
+		if aws.ToString(tag.Key) == expiresAtTagKey {
+			if aws.ToString(tag.Value) == noExpiry {
+				return time.Time{}
+			}
+			if expiresAt, err := time.Parse(time.RFC3339, aws.ToString(tag.Value)); err == nil {
+				return expiresAt
+			}
+		}
+	}
+
+	if instance.LaunchTime != nil {
+		return instance.LaunchTime.Add(defaultTTL)
+	}
+	return time.Time{}
+}
+
+// isJumphostExpired returns true if the jumphost's expiry time is in the past
+func isJumphostExpired(instance types.Instance, now time.Time) bool {
+	expiresAt := jumphostExpiry(instance)
+	return !expiresAt.IsZero() && now.After(expiresAt)
+}
@@ -0,0 +1,217 @@
+package jumphost
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIsJumphostExpired(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		instance types.Instance
+		expected bool
+	}{
+		{
+			name: "expiry_tag_in_the_past",
+			instance: types.Instance{Tags: []types.Tag{
+				{Key: aws.String(expiresAtTagKey), Value: aws.String("2024-05-01T11:00:00Z")},
+			}},
+			expected: true,
+		},
+		{
+			name: "expiry_tag_in_the_future",
+			instance: types.Instance{
+				LaunchTime: aws.Time(now.Add(-48 * time.Hour)),
+				Tags: []types.Tag{
+					{Key: aws.String(expiresAtTagKey), Value: aws.String("2024-05-01T13:00:00Z")},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "created_without_ttl",
+			instance: types.Instance{
+				LaunchTime: aws.Time(now.Add(-48 * time.Hour)),
+				Tags:       []types.Tag{{Key: aws.String(expiresAtTagKey), Value: aws.String(noExpiry)}},
+			},
+			expected: false,
+		},
+		{
+			name:     "untagged_older_than_default_ttl",
+			instance: types.Instance{LaunchTime: aws.Time(now.Add(-defaultTTL - time.Minute))},
+			expected: true,
+		},
+		{
+			name:     "untagged_younger_than_default_ttl",
+			instance: types.Instance{LaunchTime: aws.Time(now.Add(-time.Hour))},
+			expected: false,
+		},
+		{
+			name:     "no_expiry_information",
+			instance: types.Instance{},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isJumphostExpired(test.instance, now))
+		})
+	}
+}
+
+func TestCreationTagsAndUserData(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	j := &jumphostConfig{tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String(awsResourceName)}}}
+
+	tags := j.creationTags(now)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, expiresAtTagKey, aws.ToString(tags[1].Key))
+	assert.Equal(t, noExpiry, aws.ToString(tags[1].Value))
+	assert.Nil(t, j.shutdownUserData())
+
+	j.ttl = 90 * time.Minute
+	tags = j.creationTags(now)
+	assert.Len(t, tags, 2)
+	assert.Len(t, j.tags, 1, "the expiry tag must not be added to the filter tags")
+	assert.Equal(t, expiresAtTagKey, aws.ToString(tags[1].Key))
+	assert.Equal(t, "2024-05-01T13:30:00Z", aws.ToString(tags[1].Value))
+
+	userData, err := base64.StdEncoding.DecodeString(aws.ToString(j.shutdownUserData()))
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/bash\nshutdown -h +90\n", string(userData))
+}
+
+func TestRecordedCommand(t *testing.T) {
+	command := []string{"ssh", "-i", "/tmp/it's.pem", "ec2-user@1.2.3.4"}
+
+	name, args := recordedCommand("darwin", "/tmp/session.log", command)
+	assert.Equal(t, "script", name)
+	assert.Equal(t, []string{"-q", "/tmp/session.log", "ssh", "-i", "/tmp/it's.pem", "ec2-user@1.2.3.4"}, args)
+
+	name, args = recordedCommand("linux", "/tmp/session.log", command)
+	assert.Equal(t, "script", name)
+	assert.Equal(t, []string{"-q", "-c", `'ssh' '-i' '/tmp/it'\''s.pem' 'ec2-user@1.2.3.4'`, "/tmp/session.log"}, args)
+}
+
+func TestRunGc(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	restore := prompt.SetIO(strings.NewReader(""), io.Discard)
+	defer restore()
+	prompt.SetAssumeYes(true)
+	defer prompt.SetAssumeYes(false)
+
+	expired := types.Instance{
+		InstanceId: aws.String("i-expired"),
+		VpcId:      aws.String("vpc-1"),
+		Tags:       []types.Tag{{Key: aws.String(expiresAtTagKey), Value: aws.String("2024-05-01T11:00:00Z")}},
+	}
+	active := types.Instance{
+		InstanceId: aws.String("i-active"),
+		VpcId:      aws.String("vpc-2"),
+		Tags:       []types.Tag{{Key: aws.String(expiresAtTagKey), Value: aws.String("2024-05-01T13:00:00Z")}},
+	}
+
+	isListCall := mock.MatchedBy(func(input *ec2.DescribeInstancesInput) bool { return len(input.InstanceIds) == 0 })
+	isWaiterCall := mock.MatchedBy(func(input *ec2.DescribeInstancesInput) bool { return len(input.InstanceIds) > 0 })
+	securityGroups := &ec2.DescribeSecurityGroupsOutput{SecurityGroups: []types.SecurityGroup{
+		{GroupId: aws.String("sg-1"), GroupName: aws.String(awsResourceName), VpcId: aws.String("vpc-1")},
+		{GroupId: aws.String("sg-2"), GroupName: aws.String(awsResourceName), VpcId: aws.String("vpc-2")},
+		{GroupId: aws.String("sg-3"), GroupName: aws.String(awsResourceName), VpcId: aws.String("vpc-3")},
+	}}
+
+	t.Run("dry_run", func(t *testing.T) {
+		mockAws := new(mockAWSClient)
+		mockAws.On("DescribeInstances", ctx, isListCall).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{Instances: []types.Instance{expired, active}}},
+		}, nil).Once()
+		mockAws.On("DescribeSecurityGroups", ctx, mock.Anything).Return(securityGroups, nil).Once()
+
+		j := &jumphostConfig{awsClient: mockAws}
+		assert.NoError(t, j.runGc(ctx, now, true))
+		mockAws.AssertExpectations(t)
+		mockAws.AssertNotCalled(t, "TerminateInstances", mock.Anything, mock.Anything)
+		mockAws.AssertNotCalled(t, "DeleteSecurityGroup", mock.Anything, mock.Anything)
+	})
+
+	t.Run("terminates_expired_and_keeps_key_pair_in_use", func(t *testing.T) {
+		mockAws := new(mockAWSClient)
+		mockAws.On("DescribeInstances", ctx, isListCall).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{Instances: []types.Instance{expired, active}}},
+		}, nil).Once()
+		mockAws.On("DescribeSecurityGroups", ctx, mock.Anything).Return(securityGroups, nil).Once()
+		mockAws.On("TerminateInstances", ctx, &ec2.TerminateInstancesInput{InstanceIds: []string{"i-expired"}}).Return(&ec2.TerminateInstancesOutput{}, nil).Once()
+		mockAws.On("DescribeInstances", mock.Anything, isWaiterCall).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{Instances: []types.Instance{{
+				InstanceId: aws.String("i-expired"),
+				State:      &types.InstanceState{Name: types.InstanceStateNameTerminated},
+			}}}},
+		}, nil).Once()
+		mockAws.On("DeleteSecurityGroup", ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-1")}).Return(&ec2.DeleteSecurityGroupOutput{}, nil).Once()
+		mockAws.On("DeleteSecurityGroup", ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-3")}).Return(&ec2.DeleteSecurityGroupOutput{}, nil).Once()
+
+		j := &jumphostConfig{awsClient: mockAws}
+		assert.NoError(t, j.runGc(ctx, now, false))
+		mockAws.AssertExpectations(t)
+		mockAws.AssertNotCalled(t, "DescribeKeyPairs", mock.Anything, mock.Anything)
+		mockAws.AssertNotCalled(t, "DeleteKeyPair", mock.Anything, mock.Anything)
+	})
+
+	t.Run("cleans_up_after_self_terminated_jumphosts", func(t *testing.T) {
+		mockAws := new(mockAWSClient)
+		mockAws.On("DescribeInstances", ctx, isListCall).Return(&ec2.DescribeInstancesOutput{}, nil).Once()
+		mockAws.On("DescribeSecurityGroups", ctx, mock.Anything).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: securityGroups.SecurityGroups[:1]}, nil).Once()
+		mockAws.On("DescribeKeyPairs", ctx, mock.Anything).Return(&ec2.DescribeKeyPairsOutput{
+			KeyPairs: []types.KeyPairInfo{{KeyName: aws.String(awsResourceName), KeyPairId: aws.String("key-1")}},
+		}, nil).Once()
+		mockAws.On("DeleteSecurityGroup", ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-1")}).Return(&ec2.DeleteSecurityGroupOutput{}, nil).Once()
+		mockAws.On("DeleteKeyPair", ctx, &ec2.DeleteKeyPairInput{KeyPairId: aws.String("key-1")}).Return(&ec2.DeleteKeyPairOutput{}, nil).Once()
+
+		j := &jumphostConfig{awsClient: mockAws}
+		assert.NoError(t, j.runGc(ctx, now, false))
+		mockAws.AssertExpectations(t)
+		mockAws.AssertNotCalled(t, "TerminateInstances", mock.Anything, mock.Anything)
+	})
+
+	t.Run("keeps_security_groups_of_shutting_down_jumphosts_and_continues_on_errors", func(t *testing.T) {
+		shuttingDown := types.Instance{
+			InstanceId: aws.String("i-shutting-down"),
+			VpcId:      aws.String("vpc-3"),
+			State:      &types.InstanceState{Name: types.InstanceStateNameShuttingDown},
+			Tags:       []types.Tag{{Key: aws.String(expiresAtTagKey), Value: aws.String("2024-05-01T11:00:00Z")}},
+		}
+		mockAws := new(mockAWSClient)
+		mockAws.On("DescribeInstances", ctx, isListCall).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{Instances: []types.Instance{shuttingDown}}},
+		}, nil).Once()
+		mockAws.On("DescribeSecurityGroups", ctx, mock.Anything).Return(securityGroups, nil).Once()
+		mockAws.On("DescribeKeyPairs", ctx, mock.Anything).Return(&ec2.DescribeKeyPairsOutput{
+			KeyPairs: []types.KeyPairInfo{{KeyName: aws.String(awsResourceName), KeyPairId: aws.String("key-1")}},
+		}, nil).Once()
+		mockAws.On("DeleteSecurityGroup", ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-1")}).Return((*ec2.DeleteSecurityGroupOutput)(nil), errors.New("DependencyViolation")).Once()
+		mockAws.On("DeleteSecurityGroup", ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-2")}).Return(&ec2.DeleteSecurityGroupOutput{}, nil).Once()
+		mockAws.On("DeleteKeyPair", ctx, &ec2.DeleteKeyPairInput{KeyPairId: aws.String("key-1")}).Return(&ec2.DeleteKeyPairOutput{}, nil).Once()
+
+		j := &jumphostConfig{awsClient: mockAws}
+		err := j.runGc(ctx, now, false)
+		assert.ErrorContains(t, err, "failed to delete security group sg-1")
+		mockAws.AssertExpectations(t)
+		mockAws.AssertNotCalled(t, "TerminateInstances", mock.Anything, mock.Anything)
+		mockAws.AssertNotCalled(t, "DeleteSecurityGroup", ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-3")})
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	ocmsdk "github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -18,6 +19,7 @@ import (
 
 type list struct {
 	outputFormat string
+	tableFlags   *printer.TableFlags
 }
 
 type managementClusterOutput struct {
@@ -32,13 +34,16 @@ type managementClusterOutput struct {
 }
 
 func newCmdList() *cobra.Command {
-	l := &list{}
+	l := &list{tableFlags: printer.NewTableFlags()}
 	listCmd := &cobra.Command{
-		Use:     "list",
-		Short:   "List ROSA HCP Management Clusters",
-		Long:    "List ROSA HCP Management Clusters.",
-		Example: "osdctl mc list",
-		Args:    cobra.NoArgs,
+		Use:   "list",
+		Short: "List ROSA HCP Management Clusters",
+		Long:  "List ROSA HCP Management Clusters.",
+		Example: `  osdctl mc list
+
+  # Only show the name, region and status of management clusters, sorted by region
+  osdctl mc list --columns name,region,status --sort-by region`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			l.outputFormat = cmd.Flag("output").Value.String()
 			return l.Run()
@@ -52,6 +57,7 @@ func newCmdList() *cobra.Command {
 		"table",
 		"Output format. Supported output formats include: table, text, json, yaml",
 	)
+	l.tableFlags.AddFlags(listCmd)
 	return listCmd
 }
 
@@ -160,13 +166,10 @@ func (l *list) Run() error {
 			}
 		}
 	case "table":
-		w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
-		_, err := fmt.Fprintln(w, "NAME\tID\tSECTOR\tREGION\tACCOUNT_ID\tSTATUS\tHIVE\tPROVISION_SHARD_ID")
-		if err != nil {
-			return fmt.Errorf("failed to format table output: %v", err)
-		}
+		p := l.tableFlags.Apply(printer.NewTablePrinter(os.Stdout, 1, 1, 2, ' '))
+		p.AddRow([]string{"NAME", "ID", "SECTOR", "REGION", "ACCOUNT_ID", "STATUS", "HIVE", "PROVISION_SHARD_ID"})
 		for _, item := range output {
-			_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			p.AddRow([]string{
 				item.Name,
 				item.ID,
 				item.Sector,
@@ -175,13 +178,9 @@ func (l *list) Run() error {
 				item.Status,
 				item.Hive,
 				item.ProvisionShardID,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to format table output: %v", err)
-			}
+			})
 		}
-		err = w.Flush()
-		if err != nil {
+		if err := p.Flush(); err != nil {
 			return fmt.Errorf("failed to format table output: %v", err)
 		}
 	default:
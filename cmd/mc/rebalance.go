@@ -0,0 +1,536 @@
+package mc
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	fleetmgmtv1 "github.com/openshift-online/ocm-sdk-go/osdfleetmgmt/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	labelClusterID         = "api.openshift.com/id"
+	labelHostedClusterSize = "hypershift.openshift.io/hosted-cluster-size"
+
+	managementClusterStatusReady = "ready"
+)
+
+// nonWorkerRoleLabels are the node role labels of the nodes which don't run hosted control planes
+var nonWorkerRoleLabels = []string{"node-role.kubernetes.io/master", "node-role.kubernetes.io/control-plane", "node-role.kubernetes.io/infra"}
+
+type rebalancePlanOptions struct {
+	clusterID         string
+	targetUtilization float64
+	maxMigrations     int
+	output            string
+	file              string
+
+	out io.Writer
+	now func() time.Time
+	// source and siblings are the management cluster to rebalance and the ready management clusters of its sector
+	// and region
+	source   *managementCluster
+	siblings []*managementCluster
+	// usage returns the capacity and the hosted control planes of a management cluster, replaced in tests
+	usage func(ctx context.Context, mc *managementCluster) (*mcUsage, error)
+}
+
+// managementCluster is a management cluster as registered in the fleet manager
+type managementCluster struct {
+	Name   string `json:"name"`
+	ID     string `json:"id"`
+	Sector string `json:"sector"`
+	Region string `json:"region"`
+}
+
+// mcUsage is the capacity of the worker nodes of a management cluster, and the resources requested by its pods
+type mcUsage struct {
+	managementCluster
+	AllocatableCPU    int64       `json:"allocatableCPUMillicores"`
+	AllocatableMemory int64       `json:"allocatableMemoryBytes"`
+	RequestedCPU      int64       `json:"requestedCPUMillicores"`
+	RequestedMemory   int64       `json:"requestedMemoryBytes"`
+	HCPs              []*hcpUsage `json:"hostedControlPlanes"`
+}
+
+// hcpUsage is the resources requested by the pods of a hosted control plane
+type hcpUsage struct {
+	ClusterID string `json:"clusterID"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Size      string `json:"size"`
+	CPU       int64  `json:"requestedCPUMillicores"`
+	Memory    int64  `json:"requestedMemoryBytes"`
+}
+
+// hcpMigration is a hosted control plane proposed to move to another management cluster
+type hcpMigration struct {
+	hcpUsage
+	Target string `json:"target"`
+}
+
+// rebalancePlan is the migration plan of the hosted control planes of a management cluster
+type rebalancePlan struct {
+	GeneratedAt       time.Time          `json:"generatedAt"`
+	TargetUtilization float64            `json:"targetUtilization"`
+	Source            *mcUsage           `json:"source"`
+	Siblings          []*mcUsage         `json:"siblings"`
+	Migrations        []hcpMigration     `json:"migrations"`
+	Projected         map[string]float64 `json:"projectedUtilization"`
+	Note              string             `json:"note,omitempty"`
+}
+
+func newCmdRebalance() *cobra.Command {
+	rebalanceCmd := &cobra.Command{
+		Use:               "rebalance",
+		Short:             "Plan the rebalancing of hosted control planes between management clusters",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+
+	rebalanceCmd.AddCommand(newCmdRebalancePlan())
+
+	return rebalanceCmd
+}
+
+func newCmdRebalancePlan() *cobra.Command {
+	opts := &rebalancePlanOptions{out: os.Stdout, now: time.Now}
+	opts.usage = opts.managementClusterUsage
+
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Propose hosted control planes to migrate off a management cluster nearing capacity",
+		Long: `Propose hosted control planes to migrate off a management cluster nearing capacity
+
+  The hosted control planes of the management cluster are listed with the CPU and memory requested by their pods,
+  and compared with the allocatable resources of its worker nodes. While the utilization of the management cluster,
+  the highest of its CPU and memory utilization, is above the target, hosted control planes are proposed to migrate
+  to the ready management clusters of the same sector and region that stay under the target once they host them.
+
+  The plan is a Markdown document, or JSON with -o json. This command is read-only: nothing is migrated, and
+  the plan is meant to be reviewed and executed through the regular migration process.
+
+  Requires access to the management clusters with "ocm backplane login".`,
+		Example: `  # Plan the rebalancing of a management cluster down to 70% utilization
+  osdctl mc rebalance plan --cluster-id hs-mc-abcdefg
+
+  # Plan at most 5 migrations down to 60% utilization, and save the plan
+  osdctl mc rebalance plan --cluster-id hs-mc-abcdefg --target-utilization 60 --max-migrations 5 --file plan.md`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	planCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Name or cluster ID of the management cluster to rebalance")
+	planCmd.Flags().Float64Var(&opts.targetUtilization, "target-utilization", 70, "Utilization percentage the management clusters should stay under")
+	planCmd.Flags().IntVar(&opts.maxMigrations, "max-migrations", 10, "Maximum number of hosted control planes to propose to migrate")
+	planCmd.Flags().StringVarP(&opts.output, "output", "o", "markdown", "Output format: markdown or json")
+	planCmd.Flags().StringVar(&opts.file, "file", "", "Write the plan to this file instead of the standard output")
+	_ = planCmd.MarkFlagRequired("cluster-id")
+
+	return planCmd
+}
+
+func (o *rebalancePlanOptions) validate() error {
+	if o.targetUtilization <= 0 || o.targetUtilization > 100 {
+		return fmt.Errorf("--target-utilization must be between 0 and 100")
+	}
+	if o.maxMigrations < 1 {
+		return fmt.Errorf("--max-migrations must be at least 1")
+	}
+	if o.output != "markdown" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected markdown or json", o.output)
+	}
+	return nil
+}
+
+// complete resolves the management cluster and its siblings in the fleet manager
+func (o *rebalancePlanOptions) complete() error {
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	managementClusters, err := listManagementClusters(conn)
+	if err != nil {
+		return err
+	}
+	o.source, o.siblings, err = findSiblings(managementClusters, o.clusterID)
+	return err
+}
+
+// listManagementClusters returns all the management clusters of the fleet manager
+func listManagementClusters(conn *sdk.Connection) ([]*fleetmgmtv1.ManagementCluster, error) {
+	request := conn.OSDFleetMgmt().V1().ManagementClusters().List()
+	managementClusters, err := utils.NewOCMPaginator(func(page, size int) ([]*fleetmgmtv1.ManagementCluster, error) {
+		response, err := request.Page(page).Size(size).Send()
+		if err != nil {
+			return nil, err
+		}
+		return response.Items().Slice(), nil
+	}).All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list management clusters: %w", err)
+	}
+	return managementClusters, nil
+}
+
+// findSiblings returns the management cluster matching the name or cluster ID, and the ready management clusters
+// of the same sector and region
+func findSiblings(managementClusters []*fleetmgmtv1.ManagementCluster, key string) (*managementCluster, []*managementCluster, error) {
+	var source *managementCluster
+	for _, mc := range managementClusters {
+		if mc.Name() == key || mc.ClusterManagementReference().ClusterId() == key {
+			source = toManagementCluster(mc)
+			break
+		}
+	}
+	if source == nil {
+		return nil, nil, fmt.Errorf("management cluster %s not found in the fleet manager", key)
+	}
+
+	var siblings []*managementCluster
+	for _, mc := range managementClusters {
+		if mc.Name() == source.Name || mc.Sector() != source.Sector || mc.Region() != source.Region || mc.Status() != managementClusterStatusReady {
+			continue
+		}
+		siblings = append(siblings, toManagementCluster(mc))
+	}
+	if len(siblings) == 0 {
+		return nil, nil, fmt.Errorf("no ready management cluster found in sector %s and region %s to migrate to", source.Sector, source.Region)
+	}
+	return source, siblings, nil
+}
+
+func toManagementCluster(mc *fleetmgmtv1.ManagementCluster) *managementCluster {
+	return &managementCluster{
+		Name:   mc.Name(),
+		ID:     mc.ClusterManagementReference().ClusterId(),
+		Sector: mc.Sector(),
+		Region: mc.Region(),
+	}
+}
+
+func (o *rebalancePlanOptions) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	source, err := o.usage(ctx, o.source)
+	if err != nil {
+		return fmt.Errorf("failed to get the usage of management cluster %s: %w", o.source.Name, err)
+	}
+	var siblings []*mcUsage
+	for _, sibling := range o.siblings {
+		usage, err := o.usage(ctx, sibling)
+		if err != nil {
+			log.Printf("Warning: skipping management cluster %s: %v", sibling.Name, err)
+			continue
+		}
+		siblings = append(siblings, usage)
+	}
+	if len(siblings) == 0 {
+		return fmt.Errorf("the usage of none of the sibling management clusters of %s could be read", o.source.Name)
+	}
+
+	plan := planRebalance(source, siblings, o.targetUtilization, o.maxMigrations)
+	plan.GeneratedAt = o.now().UTC()
+
+	out := o.out
+	if o.file != "" {
+		f, err := os.Create(o.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if o.output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(plan)
+	} else {
+		err = writeRebalancePlan(out, plan)
+	}
+	if err != nil {
+		return err
+	}
+	if o.file != "" {
+		fmt.Fprintf(o.out, "Plan of %d migrations written to %s\n", len(plan.Migrations), o.file)
+	}
+	return nil
+}
+
+// managementClusterUsage logs into a management cluster and reads its usage
+func (o *rebalancePlanOptions) managementClusterUsage(ctx context.Context, mc *managementCluster) (*mcUsage, error) {
+	scheme := runtime.NewScheme()
+	if err := hypershiftv1beta1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add hypershift scheme: %w", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add core v1 scheme: %w", err)
+	}
+
+	kubeClient, err := k8s.New(mc.ID, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	return collectUsage(ctx, kubeClient, mc)
+}
+
+// collectUsage sums the allocatable resources of the worker nodes of a management cluster, the resources requested
+// by its scheduled pods, and the resources requested by the pods of each of its hosted control planes
+func collectUsage(ctx context.Context, kubeClient client.Client, mc *managementCluster) (*mcUsage, error) {
+	usage := &mcUsage{managementCluster: *mc}
+
+	nodes := &corev1.NodeList{}
+	if err := kubeClient.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	workers := map[string]bool{}
+	for _, node := range nodes.Items {
+		if slices.ContainsFunc(nonWorkerRoleLabels, func(label string) bool { _, ok := node.Labels[label]; return ok }) {
+			continue
+		}
+		workers[node.Name] = true
+		usage.AllocatableCPU += node.Status.Allocatable.Cpu().MilliValue()
+		usage.AllocatableMemory += node.Status.Allocatable.Memory().Value()
+	}
+	if usage.AllocatableCPU == 0 || usage.AllocatableMemory == 0 {
+		return nil, fmt.Errorf("no allocatable resources found on the worker nodes")
+	}
+
+	hostedClusters := &hypershiftv1beta1.HostedClusterList{}
+	if err := kubeClient.List(ctx, hostedClusters); err != nil {
+		return nil, fmt.Errorf("failed to list hosted clusters: %w", err)
+	}
+	hcpsByNamespace := map[string]*hcpUsage{}
+	for _, hc := range hostedClusters.Items {
+		hcp := &hcpUsage{
+			ClusterID: hc.Labels[labelClusterID],
+			Name:      hc.Name,
+			// The control plane of a hosted cluster runs in the namespace named after the hosted cluster
+			Namespace: hc.Namespace + "-" + hc.Name,
+			Size:      hc.Labels[labelHostedClusterSize],
+		}
+		hcpsByNamespace[hcp.Namespace] = hcp
+		usage.HCPs = append(usage.HCPs, hcp)
+	}
+
+	pods := &corev1.PodList{}
+	if err := kubeClient.List(ctx, pods); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if !workers[pod.Spec.NodeName] || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		var cpu, memory int64
+		for _, container := range pod.Spec.Containers {
+			cpu += container.Resources.Requests.Cpu().MilliValue()
+			memory += container.Resources.Requests.Memory().Value()
+		}
+		usage.RequestedCPU += cpu
+		usage.RequestedMemory += memory
+		if hcp, ok := hcpsByNamespace[pod.Namespace]; ok {
+			hcp.CPU += cpu
+			hcp.Memory += memory
+		}
+	}
+	return usage, nil
+}
+
+// utilization returns the highest of the CPU and memory utilization percentages of a management cluster
+func utilization(usage *mcUsage, cpu, memory int64) float64 {
+	return max(float64(cpu)/float64(usage.AllocatableCPU), float64(memory)/float64(usage.AllocatableMemory)) * 100
+}
+
+// planRebalance proposes hosted control planes to migrate off the source management cluster until its utilization
+// is under the target, each to the sibling with the lowest utilization once it hosts it, without any sibling going
+// above the target. The smallest hosted control plane bringing the source under the target is preferred, otherwise
+// the largest one fitting a sibling is moved, to keep the number of migrations low.
+func planRebalance(source *mcUsage, siblings []*mcUsage, target float64, maxMigrations int) *rebalancePlan {
+	plan := &rebalancePlan{
+		TargetUtilization: target,
+		Source:            source,
+		Siblings:          siblings,
+		Projected:         map[string]float64{},
+	}
+
+	type requested struct{ cpu, memory int64 }
+	projected := map[string]*requested{source.Name: {source.RequestedCPU, source.RequestedMemory}}
+	for _, sibling := range siblings {
+		projected[sibling.Name] = &requested{sibling.RequestedCPU, sibling.RequestedMemory}
+	}
+	sourceUtilization := func() float64 {
+		return utilization(source, projected[source.Name].cpu, projected[source.Name].memory)
+	}
+	// bestTarget returns the sibling with the lowest utilization once it hosts the hosted control plane, nil when
+	// it would take all of them above the target
+	bestTarget := func(hcp *hcpUsage) *mcUsage {
+		var best *mcUsage
+		bestUtilization := 0.0
+		for _, sibling := range siblings {
+			u := utilization(sibling, projected[sibling.Name].cpu+hcp.CPU, projected[sibling.Name].memory+hcp.Memory)
+			if u <= target && (best == nil || u < bestUtilization) {
+				best, bestUtilization = sibling, u
+			}
+		}
+		return best
+	}
+
+	// Candidates are sorted largest first, by their share of the source management cluster
+	candidates := slices.Clone(source.HCPs)
+	share := func(hcp *hcpUsage) float64 { return utilization(source, hcp.CPU, hcp.Memory) }
+	slices.SortStableFunc(candidates, func(a, b *hcpUsage) int {
+		if c := cmp.Compare(share(b), share(a)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for sourceUtilization() > target && len(plan.Migrations) < maxMigrations {
+		chosen, chosenTarget := -1, (*mcUsage)(nil)
+		for i, hcp := range candidates {
+			if hcp.CPU == 0 && hcp.Memory == 0 {
+				continue
+			}
+			sibling := bestTarget(hcp)
+			if sibling == nil {
+				continue
+			}
+			if chosen == -1 {
+				chosen, chosenTarget = i, sibling
+			}
+			remaining := utilization(source, projected[source.Name].cpu-hcp.CPU, projected[source.Name].memory-hcp.Memory)
+			if remaining <= target {
+				// Candidates are sorted largest first, the last one bringing the source under the target is the smallest
+				chosen, chosenTarget = i, sibling
+			}
+		}
+		if chosen == -1 {
+			break
+		}
+
+		hcp := candidates[chosen]
+		candidates = slices.Delete(candidates, chosen, chosen+1)
+		projected[source.Name].cpu -= hcp.CPU
+		projected[source.Name].memory -= hcp.Memory
+		projected[chosenTarget.Name].cpu += hcp.CPU
+		projected[chosenTarget.Name].memory += hcp.Memory
+		plan.Migrations = append(plan.Migrations, hcpMigration{hcpUsage: *hcp, Target: chosenTarget.Name})
+	}
+
+	for name, r := range projected {
+		usage := source
+		for _, sibling := range siblings {
+			if sibling.Name == name {
+				usage = sibling
+			}
+		}
+		plan.Projected[name] = utilization(usage, r.cpu, r.memory)
+	}
+
+	switch {
+	case utilization(source, source.RequestedCPU, source.RequestedMemory) <= target:
+		plan.Note = fmt.Sprintf("%s is already under the target utilization of %.0f%%, no migration is needed.", source.Name, target)
+	case sourceUtilization() > target && len(plan.Migrations) == maxMigrations:
+		plan.Note = fmt.Sprintf("%s stays above the target utilization after %d migrations, raise --max-migrations to plan more.", source.Name, maxMigrations)
+	case sourceUtilization() > target:
+		plan.Note = fmt.Sprintf("%s stays above the target utilization: the sibling management clusters can't host more of its hosted control planes without going above the target, consider adding capacity to the sector.", source.Name)
+	}
+	return plan
+}
+
+// writeRebalancePlan writes the plan as a Markdown document
+func writeRebalancePlan(w io.Writer, plan *rebalancePlan) error {
+	var b strings.Builder
+	source := plan.Source
+
+	fmt.Fprintf(&b, "# HCP rebalance plan for %s\n\n", source.Name)
+	fmt.Fprintf(&b, "- Generated: %s\n", plan.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Sector: %s, region: %s\n", source.Sector, source.Region)
+	fmt.Fprintf(&b, "- Target utilization: %.0f%%\n", plan.TargetUtilization)
+	fmt.Fprintf(&b, "- This plan is read-only, no hosted control plane was migrated.\n\n")
+
+	fmt.Fprintf(&b, "## Management clusters\n\n")
+	fmt.Fprintf(&b, "| Management cluster | HCPs | CPU requested | Memory requested | Utilization | Projected |\n")
+	fmt.Fprintf(&b, "|---|---|---|---|---|---|\n")
+	for _, usage := range append([]*mcUsage{source}, plan.Siblings...) {
+		fmt.Fprintf(&b, "| %s | %d | %s / %s | %s / %s | %.1f%% | %.1f%% |\n",
+			usage.Name, len(usage.HCPs),
+			formatCPU(usage.RequestedCPU), formatCPU(usage.AllocatableCPU),
+			formatMemory(usage.RequestedMemory), formatMemory(usage.AllocatableMemory),
+			utilization(usage, usage.RequestedCPU, usage.RequestedMemory), plan.Projected[usage.Name])
+	}
+
+	fmt.Fprintf(&b, "\n## Hosted control planes on %s\n\n", source.Name)
+	fmt.Fprintf(&b, "| Cluster ID | Name | Size | CPU requested | Memory requested | Share |\n")
+	fmt.Fprintf(&b, "|---|---|---|---|---|---|\n")
+	hcps := slices.Clone(source.HCPs)
+	slices.SortStableFunc(hcps, func(a, b *hcpUsage) int {
+		return cmp.Compare(utilization(source, b.CPU, b.Memory), utilization(source, a.CPU, a.Memory))
+	})
+	for _, hcp := range hcps {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %.1f%% |\n",
+			hcp.ClusterID, hcp.Name, valueOrNA(hcp.Size), formatCPU(hcp.CPU), formatMemory(hcp.Memory), utilization(source, hcp.CPU, hcp.Memory))
+	}
+
+	fmt.Fprintf(&b, "\n## Proposed migrations\n\n")
+	if len(plan.Migrations) == 0 {
+		fmt.Fprintf(&b, "None.\n")
+	} else {
+		fmt.Fprintf(&b, "| # | Cluster ID | Name | Size | CPU requested | Memory requested | Target |\n")
+		fmt.Fprintf(&b, "|---|---|---|---|---|---|---|\n")
+		for i, migration := range plan.Migrations {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s | %s |\n",
+				i+1, migration.ClusterID, migration.Name, valueOrNA(migration.Size), formatCPU(migration.CPU), formatMemory(migration.Memory), migration.Target)
+		}
+	}
+	if plan.Note != "" {
+		fmt.Fprintf(&b, "\n> %s\n", plan.Note)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatCPU(millicores int64) string {
+	return fmt.Sprintf("%.1f", float64(millicores)/1000)
+}
+
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%.1fGiB", float64(bytes)/(1<<30))
+}
+
+func valueOrNA(value string) string {
+	if value == "" {
+		return "N/A"
+	}
+	return value
+}
@@ -0,0 +1,241 @@
+package mc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	fleetmgmtv1 "github.com/openshift-online/ocm-sdk-go/osdfleetmgmt/v1"
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const gib = int64(1 << 30)
+
+func fleetManagementCluster(t *testing.T, name, id, sector, region, status string) *fleetmgmtv1.ManagementCluster {
+	mc, err := fleetmgmtv1.NewManagementCluster().Name(name).Sector(sector).Region(region).Status(status).
+		ClusterManagementReference(fleetmgmtv1.NewClusterManagementReference().ClusterId(id)).Build()
+	require.NoError(t, err)
+	return mc
+}
+
+func TestFindSiblings(t *testing.T) {
+	managementClusters := []*fleetmgmtv1.ManagementCluster{
+		fleetManagementCluster(t, "hs-mc-1", "id-1", "main", "us-east-1", "ready"),
+		fleetManagementCluster(t, "hs-mc-2", "id-2", "main", "us-east-1", "ready"),
+		fleetManagementCluster(t, "hs-mc-3", "id-3", "main", "us-east-1", "maintenance"),
+		fleetManagementCluster(t, "hs-mc-4", "id-4", "canary", "us-east-1", "ready"),
+		fleetManagementCluster(t, "hs-mc-5", "id-5", "main", "us-west-2", "ready"),
+	}
+
+	source, siblings, err := findSiblings(managementClusters, "id-1")
+	require.NoError(t, err)
+	assert.Equal(t, "hs-mc-1", source.Name)
+	require.Len(t, siblings, 1)
+	assert.Equal(t, "hs-mc-2", siblings[0].Name)
+
+	_, _, err = findSiblings(managementClusters, "hs-mc-4")
+	assert.ErrorContains(t, err, "no ready management cluster found in sector canary")
+
+	_, _, err = findSiblings(managementClusters, "hs-mc-9")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func pod(namespace, name, node, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func node(name, cpu, memory string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}},
+	}
+}
+
+func TestCollectUsage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, hypershiftv1beta1.AddToScheme(scheme))
+
+	completed := pod("ocm-production-abc-hcp1", "completed", "worker-1", "4", "4Gi")
+	completed.Status.Phase = corev1.PodSucceeded
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		node("master-0", "8", "32Gi", map[string]string{"node-role.kubernetes.io/master": ""}),
+		node("worker-1", "16", "64Gi", nil),
+		node("worker-2", "16", "64Gi", nil),
+		&hypershiftv1beta1.HostedCluster{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ocm-production-abc",
+			Name:      "hcp1",
+			Labels:    map[string]string{labelClusterID: "abc", labelHostedClusterSize: "medium"},
+		}},
+		pod("ocm-production-abc-hcp1", "kube-apiserver", "worker-1", "2", "8Gi"),
+		pod("ocm-production-abc-hcp1", "etcd", "worker-2", "500m", "2Gi"),
+		pod("openshift-monitoring", "prometheus", "worker-2", "1", "4Gi"),
+		pod("openshift-etcd", "etcd", "master-0", "1", "4Gi"),
+		completed,
+	).Build()
+
+	usage, err := collectUsage(context.Background(), kubeClient, &managementCluster{Name: "hs-mc-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(32000), usage.AllocatableCPU)
+	assert.Equal(t, 128*gib, usage.AllocatableMemory)
+	assert.Equal(t, int64(3500), usage.RequestedCPU)
+	assert.Equal(t, 14*gib, usage.RequestedMemory)
+	require.Len(t, usage.HCPs, 1)
+	assert.Equal(t, hcpUsage{ClusterID: "abc", Name: "hcp1", Namespace: "ocm-production-abc-hcp1", Size: "medium", CPU: 2500, Memory: 10 * gib}, *usage.HCPs[0])
+}
+
+// usage returns a management cluster of 100 cores and 400GiB with the hosted control planes, each of cpu cores and
+// cpu*4GiB, and other requests of the same proportion
+func usage(name string, other int64, hcps map[string]int64) *mcUsage {
+	u := &mcUsage{
+		managementCluster: managementCluster{Name: name, Sector: "main", Region: "us-east-1"},
+		AllocatableCPU:    100000,
+		AllocatableMemory: 400 * gib,
+		RequestedCPU:      other * 1000,
+		RequestedMemory:   other * 4 * gib,
+	}
+	for hcpName, cpu := range hcps {
+		u.HCPs = append(u.HCPs, &hcpUsage{ClusterID: "id-" + hcpName, Name: hcpName, CPU: cpu * 1000, Memory: cpu * 4 * gib})
+		u.RequestedCPU += cpu * 1000
+		u.RequestedMemory += cpu * 4 * gib
+	}
+	return u
+}
+
+func migratedNames(plan *rebalancePlan) []string {
+	var names []string
+	for _, migration := range plan.Migrations {
+		names = append(names, migration.Name+"->"+migration.Target)
+	}
+	return names
+}
+
+func TestPlanRebalance(t *testing.T) {
+	tests := []struct {
+		name          string
+		source        *mcUsage
+		siblings      []*mcUsage
+		maxMigrations int
+		want          []string
+		note          string
+	}{
+		{
+			name:          "under target",
+			source:        usage("hs-mc-1", 10, map[string]int64{"a": 20}),
+			siblings:      []*mcUsage{usage("hs-mc-2", 10, nil)},
+			maxMigrations: 10,
+			note:          "already under the target",
+		},
+		{
+			name:          "smallest hcp bringing the source under the target",
+			source:        usage("hs-mc-1", 22, map[string]int64{"a": 30, "b": 20, "c": 5}),
+			siblings:      []*mcUsage{usage("hs-mc-2", 40, nil), usage("hs-mc-3", 20, nil)},
+			maxMigrations: 10,
+			want:          []string{"b->hs-mc-3"},
+		},
+		{
+			name:          "largest hcps first when none is enough",
+			source:        usage("hs-mc-1", 30, map[string]int64{"a": 25, "b": 25, "c": 20}),
+			siblings:      []*mcUsage{usage("hs-mc-2", 20, nil), usage("hs-mc-3", 25, nil)},
+			maxMigrations: 10,
+			want:          []string{"a->hs-mc-2", "c->hs-mc-3"},
+		},
+		{
+			name:          "siblings full",
+			source:        usage("hs-mc-1", 10, map[string]int64{"a": 40, "b": 40}),
+			siblings:      []*mcUsage{usage("hs-mc-2", 60, nil)},
+			maxMigrations: 10,
+			note:          "consider adding capacity",
+		},
+		{
+			name:          "max migrations reached",
+			source:        usage("hs-mc-1", 30, map[string]int64{"a": 25, "b": 25, "c": 20}),
+			siblings:      []*mcUsage{usage("hs-mc-2", 20, nil), usage("hs-mc-3", 25, nil)},
+			maxMigrations: 1,
+			want:          []string{"a->hs-mc-2"},
+			note:          "raise --max-migrations",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planRebalance(tt.source, tt.siblings, 70, tt.maxMigrations)
+			assert.Equal(t, tt.want, migratedNames(plan))
+			if tt.note == "" {
+				assert.Empty(t, plan.Note)
+			} else {
+				assert.Contains(t, plan.Note, tt.note)
+			}
+			for name, projected := range plan.Projected {
+				if name != tt.source.Name {
+					assert.LessOrEqual(t, projected, 70.0, name)
+				}
+			}
+		})
+	}
+}
+
+func TestRebalancePlanRun(t *testing.T) {
+	usages := map[string]*mcUsage{
+		"hs-mc-1": usage("hs-mc-1", 10, map[string]int64{"a": 30, "b": 20, "c": 15}),
+		"hs-mc-2": usage("hs-mc-2", 40, nil),
+	}
+	newOpts := func(output string, out *bytes.Buffer) *rebalancePlanOptions {
+		return &rebalancePlanOptions{
+			targetUtilization: 70,
+			maxMigrations:     10,
+			output:            output,
+			out:               out,
+			now:               func() time.Time { return time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC) },
+			source:            &managementCluster{Name: "hs-mc-1"},
+			siblings:          []*managementCluster{{Name: "hs-mc-2"}, {Name: "hs-mc-3"}},
+			usage: func(ctx context.Context, mc *managementCluster) (*mcUsage, error) {
+				if u, ok := usages[mc.Name]; ok {
+					return u, nil
+				}
+				return nil, assert.AnError
+			},
+		}
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, newOpts("markdown", out).run(context.Background()))
+	assert.Contains(t, out.String(), "# HCP rebalance plan for hs-mc-1")
+	assert.Contains(t, out.String(), "| hs-mc-1 | 3 | 75.0 / 100.0 | 300.0GiB / 400.0GiB | 75.0% | 60.0% |")
+	assert.Contains(t, out.String(), "| 1 | id-c | c | N/A | 15.0 | 60.0GiB | hs-mc-2 |")
+	assert.NotContains(t, out.String(), "hs-mc-3")
+
+	out = &bytes.Buffer{}
+	require.NoError(t, newOpts("json", out).run(context.Background()))
+	plan := &rebalancePlan{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), plan))
+	require.Len(t, plan.Migrations, 1)
+	assert.Equal(t, "hs-mc-2", plan.Migrations[0].Target)
+	assert.Equal(t, "id-c", plan.Migrations[0].ClusterID)
+	assert.Equal(t, 60.0, plan.Projected["hs-mc-1"])
+}
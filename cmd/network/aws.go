@@ -5,12 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/openshift/osd-network-verifier/pkg/data/cloud"
 	"github.com/openshift/osd-network-verifier/pkg/verifier"
-	"github.com/openshift/osdctl/pkg/osdCloud"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/openshift/osdctl/pkg/utils"
 	"strings"
 )
@@ -24,6 +23,10 @@ type egressVerificationAWSClient interface {
 // setupForAws configures an EgressVerification's awsClient and cluster depending on whether the ClusterId or profile
 // flags are supplied. It also returns an aws.Config if needed.
 func (e *EgressVerification) setupForAws(ctx context.Context) (*aws.Config, error) {
+	awsConfig := awsprovider.NewClusterConfigFactory()
+	// --region overrides the region of the cluster in OCM, or the one of the local AWS configuration
+	awsConfig.Region = e.Region
+
 	// If ClusterId is supplied, leverage ocm and ocm-backplane to get an AWS client.
 	// We previously hydrated the EgressVerification struct with a `cluster` in this scenario.
 	if e.ClusterId != "" && e.cluster != nil {
@@ -40,7 +43,7 @@ func (e *EgressVerification) setupForAws(ctx context.Context) (*aws.Config, erro
 		defer ocmClient.Close()
 
 		e.log.Info(ctx, "getting AWS credentials from backplane-api")
-		cfg, err := osdCloud.CreateAWSV2Config(ocmClient, e.cluster)
+		cfg, err := awsConfig.Config(ocmClient, e.cluster)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get credentials automatically from backplane-api: %v."+
 				" You can still try this command by exporting AWS credentials as environment variables and specifying"+
@@ -58,16 +61,14 @@ func (e *EgressVerification) setupForAws(ctx context.Context) (*aws.Config, erro
 
 	e.log.Info(ctx, "[WARNING] no cluster-id specified, there is reduced validation around the security group, subnet, and proxy, causing inaccurate results")
 	e.log.Info(ctx, "using whatever default AWS credentials are locally available")
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("network verification failed to find valid creds locally: %s", err)
-	}
-
-	// Additionally, if an AWS region must be provided somehow if there's no ClusterId
+	// Additionally, an AWS region must be provided somehow if there's no ClusterId
 	// This could have been done via the default AWS credentials or can be supplied manually via --region
 	if e.Region != "" {
 		e.log.Info(ctx, "overriding region with %s", e.Region)
-		cfg.Region = e.Region
+	}
+	cfg, err := awsConfig.LocalConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("network verification failed to find valid creds locally: %s", err)
 	}
 
 	e.awsClient = ec2.NewFromConfig(cfg)
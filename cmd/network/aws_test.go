@@ -114,6 +114,24 @@ func Test_egressVerification_setupForAws(t *testing.T) {
 	}
 }
 
+func Test_egressVerification_setupForAwsRegion(t *testing.T) {
+	e := &EgressVerification{
+		SubnetIds:       []string{"subnet-a"},
+		SecurityGroupId: "sg-a",
+		platformName:    "aws",
+		Region:          "eu-west-1",
+		log:             newTestLogger(t),
+	}
+
+	cfg, err := e.setupForAws(context.Background())
+	if err != nil {
+		t.Fatalf("expected no err, got %s", err)
+	}
+	if cfg.Region != "eu-west-1" {
+		t.Errorf("expected the region to be overridden with eu-west-1, got %s", cfg.Region)
+	}
+}
+
 func Test_egressVerification_GenerateAWSValidateEgressInput(t *testing.T) {
 	tests := []struct {
 		name      string
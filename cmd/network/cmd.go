@@ -19,6 +19,8 @@ func NewCmdNetwork(streams genericclioptions.IOStreams, client *k8s.LazyClient)
 
 	netCmd.AddCommand(newCmdPacketCapture(streams, client))
 	netCmd.AddCommand(NewCmdValidateEgress())
+	netCmd.AddCommand(newCmdVerifyPrivateLink())
+	netCmd.AddCommand(newCmdFlowLogs())
 	return netCmd
 }
 
@@ -0,0 +1,131 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/openshift/osd-network-verifier/pkg/output"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+)
+
+const (
+	// egressBaselineDir is the directory of the data dir the egress baselines of the clusters are stored in
+	egressBaselineDir = "egress-baselines"
+	// podModeBaselineKey identifies the results of pod mode verifications, which don't run in a given subnet
+	podModeBaselineKey = "pod-mode"
+)
+
+// egressBaseline is the result of a previous egress verification of a cluster, which later verifications are compared
+// with to detect the endpoints blocked since, e.g. by a change of the customer firewall
+type egressBaseline struct {
+	ClusterID string    `json:"clusterId"`
+	Platform  string    `json:"platform"`
+	Updated   time.Time `json:"updated"`
+	// Blocked are the egress URLs which were blocked, per subnet ID (or podModeBaselineKey)
+	Blocked map[string][]string `json:"blocked"`
+}
+
+// egressBaselinePath returns the path of the baseline of the cluster
+func egressBaselinePath(clusterID string) (string, error) {
+	dataDir, err := osdctlConfig.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, egressBaselineDir, clusterID+".json"), nil
+}
+
+// loadEgressBaseline reads a baseline, returning nil when none has been saved yet
+func loadEgressBaseline(path string) (*egressBaseline, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	baseline := &egressBaseline{}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse the egress baseline %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+func (b *egressBaseline) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// merge records the results of a verification over the baseline. The subnets which weren't verified this time
+// keep their previous results.
+func (b *egressBaseline) merge(results map[string][]string) {
+	if b.Blocked == nil {
+		b.Blocked = map[string][]string{}
+	}
+	for key, blocked := range results {
+		b.Blocked[key] = blocked
+	}
+}
+
+// blockedEgressURLs returns the sorted egress URLs the verification found blocked
+func blockedEgressURLs(out *output.Output) []string {
+	blocked := []string{}
+	for _, failure := range out.GetEgressURLFailures() {
+		blocked = append(blocked, failure.EgressURL())
+	}
+	slices.Sort(blocked)
+	return slices.Compact(blocked)
+}
+
+// compareEgress returns the URLs blocked now which weren't in the baseline, and the ones which are no longer blocked
+func compareEgress(baseline []string, current []string) (newlyBlocked []string, unblocked []string) {
+	for _, url := range current {
+		if !slices.Contains(baseline, url) {
+			newlyBlocked = append(newlyBlocked, url)
+		}
+	}
+	for _, url := range baseline {
+		if !slices.Contains(current, url) {
+			unblocked = append(unblocked, url)
+		}
+	}
+	return newlyBlocked, unblocked
+}
+
+// printEgressDrift prints the difference of the verification of a subnet with its baseline, highlighting the
+// newly blocked endpoints
+func printEgressDrift(w io.Writer, baseline *egressBaseline, key string, current []string) {
+	previous, verified := baseline.Blocked[key]
+	if !verified {
+		fmt.Fprintf(w, "Subnet %s isn't in the egress baseline of %s, nothing to compare with\n", key, baseline.Updated.Format(time.RFC3339))
+		return
+	}
+
+	newlyBlocked, unblocked := compareEgress(previous, current)
+	if len(newlyBlocked) == 0 && len(unblocked) == 0 {
+		fmt.Fprintf(w, "No egress drift for %s since the baseline of %s\n", key, baseline.Updated.Format(time.RFC3339))
+		return
+	}
+
+	red := color.New(color.FgRed, color.Bold)
+	fmt.Fprintf(w, "Egress drift for %s since the baseline of %s:\n", key, baseline.Updated.Format(time.RFC3339))
+	for _, url := range newlyBlocked {
+		red.Fprintf(w, "  NEWLY BLOCKED  %s\n", url)
+	}
+	for _, url := range unblocked {
+		fmt.Fprintf(w, "  UNBLOCKED      %s\n", url)
+	}
+}
@@ -0,0 +1,70 @@
+package network
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareEgress(t *testing.T) {
+	newlyBlocked, unblocked := compareEgress(
+		[]string{"api.openshift.com:443", "quay.io:443"},
+		[]string{"quay.io:443", "registry.redhat.io:443"},
+	)
+
+	assert.Equal(t, []string{"registry.redhat.io:443"}, newlyBlocked)
+	assert.Equal(t, []string{"api.openshift.com:443"}, unblocked)
+}
+
+func TestEgressBaselineSaveAndLoad(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	path, err := egressBaselinePath("cluster-id")
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(path, filepath.Join("osdctl", egressBaselineDir, "cluster-id.json")), path)
+
+	missing, err := loadEgressBaseline(path)
+	require.NoError(t, err)
+	assert.Nil(t, missing, "no baseline must be returned before one is saved")
+
+	baseline := &egressBaseline{ClusterID: "cluster-id", Platform: "aws-classic", Updated: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	baseline.merge(map[string][]string{"subnet-a": {"quay.io:443"}, "subnet-b": {}})
+	require.NoError(t, baseline.save(path))
+
+	loaded, err := loadEgressBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, baseline, loaded)
+
+	// Only the subnets verified again are replaced
+	loaded.merge(map[string][]string{"subnet-a": {}})
+	assert.Equal(t, map[string][]string{"subnet-a": {}, "subnet-b": {}}, loaded.Blocked)
+}
+
+func TestPrintEgressDrift(t *testing.T) {
+	baseline := &egressBaseline{Updated: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Blocked: map[string][]string{"subnet-a": {"quay.io:443"}}}
+
+	var out bytes.Buffer
+	printEgressDrift(&out, baseline, "subnet-a", []string{"registry.redhat.io:443"})
+	assert.Contains(t, out.String(), "Egress drift for subnet-a since the baseline of 2026-01-02T03:04:05Z")
+	assert.Regexp(t, `NEWLY BLOCKED\s+registry.redhat.io:443`, out.String())
+	assert.Regexp(t, `UNBLOCKED\s+quay.io:443`, out.String())
+
+	out.Reset()
+	printEgressDrift(&out, baseline, "subnet-a", []string{"quay.io:443"})
+	assert.Contains(t, out.String(), "No egress drift for subnet-a")
+
+	out.Reset()
+	printEgressDrift(&out, baseline, "subnet-b", nil)
+	assert.Contains(t, out.String(), "Subnet subnet-b isn't in the egress baseline")
+}
+
+func TestBaselineRequiresClusterID(t *testing.T) {
+	e := &EgressVerification{CompareBaseline: true, SubnetIds: []string{"subnet-a"}}
+
+	assert.ErrorContains(t, e.validateInput(), "require --cluster-id")
+}
@@ -0,0 +1,631 @@
+package network
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// flowLogsBucketTag tags the flow logs enabled by osdctl with the bucket they are delivered to
+	flowLogsBucketTag = "osdctl-flowlogs-bucket"
+	// flowLogsExpiresTag tags the flow logs enabled by osdctl with the time after which they are cleaned up
+	flowLogsExpiresTag   = "osdctl-flowlogs-expires-at"
+	flowLogsBucketPrefix = "osdctl-flowlogs-"
+
+	defaultFlowLogsDuration = 30 * time.Minute
+	maxFlowLogsDuration     = 2 * time.Hour
+	// flowLogsRetentionDays is the number of days after which the lifecycle of the bucket expires the delivered
+	// files, bounding the storage of a flow log nobody fetches or cleans up
+	flowLogsRetentionDays = 1
+)
+
+// flowLogsEC2Client is the subset of the EC2 API used to manage the flow logs of the cluster VPC
+type flowLogsEC2Client interface {
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	CreateFlowLogs(ctx context.Context, params *ec2.CreateFlowLogsInput, optFns ...func(*ec2.Options)) (*ec2.CreateFlowLogsOutput, error)
+	DescribeFlowLogs(ctx context.Context, params *ec2.DescribeFlowLogsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeFlowLogsOutput, error)
+	DeleteFlowLogs(ctx context.Context, params *ec2.DeleteFlowLogsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteFlowLogsOutput, error)
+}
+
+// flowLogsS3Client is the subset of the S3 API used to manage the temporary bucket the flow logs are delivered to
+type flowLogsS3Client interface {
+	CreateBucket(ctx context.Context, params *s3.CreateBucketInput, optFns ...func(*s3.Options)) (*s3.CreateBucketOutput, error)
+	PutBucketLifecycleConfiguration(ctx context.Context, params *s3.PutBucketLifecycleConfigurationInput, optFns ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	DeleteBucket(ctx context.Context, params *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
+}
+
+// flowLogsOptions holds the options shared by the flowlogs subcommands
+type flowLogsOptions struct {
+	clusterID string
+	awsConfig *awsprovider.ClusterConfigFactory
+
+	out       io.Writer
+	errOut    io.Writer
+	cluster   *cmv1.Cluster
+	region    string
+	ec2Client flowLogsEC2Client
+	s3Client  flowLogsS3Client
+	now       func() time.Time
+}
+
+type flowLogsEnableOptions struct {
+	flowLogsOptions
+	duration    time.Duration
+	trafficType string
+}
+
+type flowLogsFetchOptions struct {
+	flowLogsOptions
+	top     int
+	output  string
+	cleanup bool
+}
+
+// flowRecord is a record of the default flow log format:
+// version account-id interface-id srcaddr dstaddr srcport dstport protocol packets bytes start end action log-status
+type flowRecord struct {
+	SrcAddr  string
+	DstAddr  string
+	DstPort  string
+	Protocol string
+	Packets  int64
+	Bytes    int64
+	Action   string
+}
+
+// flowSummary aggregates the records of the flows between two addresses to a port
+type flowSummary struct {
+	SrcAddr  string `json:"srcAddr"`
+	DstAddr  string `json:"dstAddr"`
+	DstPort  string `json:"dstPort"`
+	Protocol string `json:"protocol"`
+	Records  int    `json:"records"`
+	Packets  int64  `json:"packets"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// flowLogsReport is the rendering of the records of a flow log
+type flowLogsReport struct {
+	FlowLogID   string        `json:"flowLogID"`
+	Bucket      string        `json:"bucket"`
+	Records     int           `json:"records"`
+	TopTalkers  []flowSummary `json:"topTalkers"`
+	RejectedTop []flowSummary `json:"rejectedFlows"`
+}
+
+func newCmdFlowLogs() *cobra.Command {
+	flowLogsCmd := &cobra.Command{
+		Use:   "flowlogs",
+		Short: "Temporarily enable the VPC flow logs of a cluster and render their top talkers and rejected flows",
+		Long: `Temporarily enable the VPC flow logs of an AWS cluster and render their top talkers and rejected flows.
+
+  enable turns on the flow logs of the cluster VPC, delivered to a temporary S3 bucket in the cluster account, for a
+  limited duration. fetch downloads the delivered records and renders the top talkers and the rejected flows, and
+  deletes the flow logs and their bucket once the duration is over.
+
+  AWS can't stop a flow log by itself: an expired flow log keeps delivering records until fetch, or the next enable
+  on the cluster, deletes it. The files of the bucket expire after 1 day whatever happens.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+
+	flowLogsCmd.AddCommand(newCmdFlowLogsEnable())
+	flowLogsCmd.AddCommand(newCmdFlowLogsFetch())
+	return flowLogsCmd
+}
+
+func newCmdFlowLogsEnable() *cobra.Command {
+	ops := &flowLogsEnableOptions{flowLogsOptions: flowLogsOptions{awsConfig: awsprovider.NewClusterConfigFactory(), out: os.Stdout, errOut: os.Stderr, now: time.Now}}
+	enableCmd := &cobra.Command{
+		Use:   "enable --cluster-id <cluster-id>",
+		Short: "Enable the flow logs of the cluster VPC to a temporary S3 bucket",
+		Long: `Enable the flow logs of the cluster VPC to a temporary S3 bucket for a limited duration.
+
+  The flow logs are aggregated every minute, and AWS delivers them to the bucket about every 5 minutes. They are
+  tagged with their expiry, after which the next fetch or enable deletes them and their bucket. The flow logs keep
+  delivering records until then, run fetch once the duration is over. The lifecycle of the bucket expires its files
+  after 1 day, so that a forgotten flow log doesn't keep its records.`,
+		Example: `  # Log the traffic of the cluster VPC for 30 minutes
+  osdctl network flowlogs enable --cluster-id ${CLUSTER_ID}
+
+  # Only log the rejected traffic for an hour
+  osdctl network flowlogs enable --cluster-id ${CLUSTER_ID} --duration 1h --traffic-type REJECT`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ops.complete(); err != nil {
+				return err
+			}
+			return ops.run(cmd.Context())
+		},
+	}
+
+	enableCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID whose VPC flow logs should be enabled")
+	enableCmd.Flags().DurationVar(&ops.duration, "duration", defaultFlowLogsDuration, fmt.Sprintf("How long the flow logs are kept enabled, at most %s", maxFlowLogsDuration))
+	enableCmd.Flags().StringVar(&ops.trafficType, "traffic-type", string(ec2types.TrafficTypeAll), "Traffic to log: ALL, ACCEPT or REJECT")
+	ops.awsConfig.AddRegionFlag(enableCmd.Flags())
+
+	_ = enableCmd.MarkFlagRequired("cluster-id")
+
+	return enableCmd
+}
+
+func newCmdFlowLogsFetch() *cobra.Command {
+	ops := &flowLogsFetchOptions{flowLogsOptions: flowLogsOptions{awsConfig: awsprovider.NewClusterConfigFactory(), out: os.Stdout, errOut: os.Stderr, now: time.Now}}
+	fetchCmd := &cobra.Command{
+		Use:   "fetch --cluster-id <cluster-id>",
+		Short: "Render the top talkers and rejected flows of the flow logs enabled by osdctl",
+		Long: `Download the records of the flow logs enabled by osdctl on the cluster VPC and render the top talkers, by bytes,
+and the rejected flows, by number of records.
+
+  The flow logs and their bucket are deleted when their duration is over, or right away with --cleanup.`,
+		Example: `  # Render the flow logs of the cluster VPC
+  osdctl network flowlogs fetch --cluster-id ${CLUSTER_ID}
+
+  # Render the 20 top flows as JSON and clean up the flow logs
+  osdctl network flowlogs fetch --cluster-id ${CLUSTER_ID} --top 20 -o json --cleanup`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ops.complete(); err != nil {
+				return err
+			}
+			return ops.run(cmd.Context())
+		},
+	}
+
+	fetchCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID whose VPC flow logs should be fetched")
+	fetchCmd.Flags().IntVar(&ops.top, "top", 10, "Number of top talkers and rejected flows to render")
+	fetchCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Output format: table or json")
+	fetchCmd.Flags().BoolVar(&ops.cleanup, "cleanup", false, "Delete the flow logs and their bucket after fetching, even if their duration isn't over")
+	ops.awsConfig.AddRegionFlag(fetchCmd.Flags())
+
+	_ = fetchCmd.MarkFlagRequired("cluster-id")
+
+	return fetchCmd
+}
+
+func (o *flowLogsEnableOptions) complete() error {
+	if o.duration <= 0 || o.duration > maxFlowLogsDuration {
+		return fmt.Errorf("--duration must be between 1s and %s", maxFlowLogsDuration)
+	}
+	switch ec2types.TrafficType(o.trafficType) {
+	case ec2types.TrafficTypeAll, ec2types.TrafficTypeAccept, ec2types.TrafficTypeReject:
+	default:
+		return fmt.Errorf("invalid traffic type %q, expected ALL, ACCEPT or REJECT", o.trafficType)
+	}
+	return utils.ValidateClusterKey("cluster-id", o.clusterID)
+}
+
+func (o *flowLogsFetchOptions) complete() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	if o.top <= 0 {
+		return errors.New("--top must be positive")
+	}
+	return utils.ValidateClusterKey("cluster-id", o.clusterID)
+}
+
+// init resolves the cluster and creates the AWS clients of its account, unless set by tests
+func (o *flowLogsOptions) init() error {
+	if o.cluster != nil {
+		return nil
+	}
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	o.cluster, err = utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if o.cluster.CloudProvider().ID() != "aws" {
+		return fmt.Errorf("cluster %s is not an AWS cluster, flow logs are only supported on AWS", o.cluster.ID())
+	}
+
+	cfg, err := o.awsConfig.Config(ocmClient, o.cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get AWS credentials of cluster %s: %w", o.cluster.ID(), err)
+	}
+	o.region = cfg.Region
+	o.ec2Client = ec2.NewFromConfig(cfg)
+	o.s3Client = s3.NewFromConfig(cfg)
+	return nil
+}
+
+// clusterVPC returns the VPC of the cluster: the VPC of its subnets for clusters installed in existing subnets,
+// the VPC tagged with its infra ID otherwise
+func (o *flowLogsOptions) clusterVPC(ctx context.Context) (string, error) {
+	if subnetIDs := o.cluster.AWS().SubnetIDs(); len(subnetIDs) > 0 {
+		resp, err := o.ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{SubnetIds: subnetIDs[:1]})
+		if err != nil {
+			return "", fmt.Errorf("failed to describe subnet %s: %w", subnetIDs[0], err)
+		}
+		if len(resp.Subnets) == 0 || resp.Subnets[0].VpcId == nil {
+			return "", fmt.Errorf("subnet %s of the cluster not found", subnetIDs[0])
+		}
+		return *resp.Subnets[0].VpcId, nil
+	}
+
+	resp, err := o.ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("tag-key"), Values: []string{"kubernetes.io/cluster/" + o.cluster.InfraID()}}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe the VPC of the cluster: %w", err)
+	}
+	if len(resp.Vpcs) == 0 {
+		return "", fmt.Errorf("no VPC tagged with the infra ID %s of the cluster", o.cluster.InfraID())
+	}
+	return aws.ToString(resp.Vpcs[0].VpcId), nil
+}
+
+// osdctlFlowLogs returns the flow logs enabled by osdctl on the VPC
+func (o *flowLogsOptions) osdctlFlowLogs(ctx context.Context, vpcID string) ([]ec2types.FlowLog, error) {
+	resp, err := o.ec2Client.DescribeFlowLogs(ctx, &ec2.DescribeFlowLogsInput{
+		Filter: []ec2types.Filter{
+			{Name: aws.String("resource-id"), Values: []string{vpcID}},
+			{Name: aws.String("tag-key"), Values: []string{flowLogsBucketTag}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the flow logs of %s: %w", vpcID, err)
+	}
+	return resp.FlowLogs, nil
+}
+
+func (o *flowLogsEnableOptions) run(ctx context.Context) error {
+	if err := o.init(); err != nil {
+		return err
+	}
+	vpcID, err := o.clusterVPC(ctx)
+	if err != nil {
+		return err
+	}
+	existing, err := o.osdctlFlowLogs(ctx, vpcID)
+	if err != nil {
+		return err
+	}
+	for _, flowLog := range existing {
+		if !o.expired(flowLog) {
+			return fmt.Errorf("flow log %s is already enabled on %s by osdctl, fetch it with 'osdctl network flowlogs fetch --cluster-id %s'", aws.ToString(flowLog.FlowLogId), vpcID, o.clusterID)
+		}
+	}
+	// The expired flow logs weren't fetched, they are still delivering records
+	for _, flowLog := range existing {
+		if err := o.deleteFlowLog(ctx, flowLog); err != nil {
+			return err
+		}
+	}
+
+	now := o.now()
+	region := o.region
+	bucket := fmt.Sprintf("%s%s-%d", flowLogsBucketPrefix, strings.ToLower(o.cluster.ID()), now.Unix())
+	createBucket := &s3.CreateBucketInput{Bucket: aws.String(bucket)}
+	// us-east-1 is the default location and can't be set as constraint
+	if region != "us-east-1" {
+		createBucket.CreateBucketConfiguration = &s3types.CreateBucketConfiguration{LocationConstraint: s3types.BucketLocationConstraint(region)}
+	}
+	if _, err := o.s3Client.CreateBucket(ctx, createBucket); err != nil {
+		return fmt.Errorf("failed to create the bucket %s: %w", bucket, err)
+	}
+	if _, err := o.s3Client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{Rules: []s3types.LifecycleRule{{
+			ID:         aws.String("osdctl-flowlogs-expiration"),
+			Status:     s3types.ExpirationStatusEnabled,
+			Filter:     &s3types.LifecycleRuleFilter{Prefix: aws.String("")},
+			Expiration: &s3types.LifecycleExpiration{Days: aws.Int32(flowLogsRetentionDays)},
+		}}},
+	}); err != nil {
+		if _, deleteErr := o.s3Client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String(bucket)}); deleteErr != nil {
+			_, _ = fmt.Fprintf(o.out, "failed to delete the bucket %s: %v\n", bucket, deleteErr)
+		}
+		return fmt.Errorf("failed to set the lifecycle of the bucket %s: %w", bucket, err)
+	}
+
+	expiresAt := now.Add(o.duration).UTC()
+	resp, err := o.ec2Client.CreateFlowLogs(ctx, &ec2.CreateFlowLogsInput{
+		ResourceIds:            []string{vpcID},
+		ResourceType:           ec2types.FlowLogsResourceTypeVpc,
+		TrafficType:            ec2types.TrafficType(o.trafficType),
+		LogDestinationType:     ec2types.LogDestinationTypeS3,
+		LogDestination:         aws.String(fmt.Sprintf("arn:%s:s3:::%s", awsprovider.GetPartitionForRegion(region), bucket)),
+		MaxAggregationInterval: aws.Int32(60),
+		TagSpecifications: []ec2types.TagSpecification{{
+			ResourceType: ec2types.ResourceTypeVpcFlowLog,
+			Tags: []ec2types.Tag{
+				{Key: aws.String(flowLogsBucketTag), Value: aws.String(bucket)},
+				{Key: aws.String(flowLogsExpiresTag), Value: aws.String(expiresAt.Format(time.RFC3339))},
+			},
+		}},
+	})
+	if err == nil && len(resp.Unsuccessful) > 0 {
+		err = errors.New(aws.ToString(resp.Unsuccessful[0].Error.Message))
+	}
+	if err != nil {
+		if _, deleteErr := o.s3Client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String(bucket)}); deleteErr != nil {
+			_, _ = fmt.Fprintf(o.out, "failed to delete the bucket %s: %v\n", bucket, deleteErr)
+		}
+		return fmt.Errorf("failed to enable the flow logs of %s: %w", vpcID, err)
+	}
+
+	_, _ = fmt.Fprintf(o.out, "Enabled flow log %s on %s, delivered to s3://%s until %s\n", strings.Join(resp.FlowLogIds, ","), vpcID, bucket, expiresAt.Format(time.RFC3339))
+	_, _ = fmt.Fprintf(o.out, "The first records are delivered after about 10 minutes, fetch them with 'osdctl network flowlogs fetch --cluster-id %s'\n", o.clusterID)
+	return nil
+}
+
+func (o *flowLogsFetchOptions) run(ctx context.Context) error {
+	if err := o.init(); err != nil {
+		return err
+	}
+	vpcID, err := o.clusterVPC(ctx)
+	if err != nil {
+		return err
+	}
+	flowLogs, err := o.osdctlFlowLogs(ctx, vpcID)
+	if err != nil {
+		return err
+	}
+	if len(flowLogs) == 0 {
+		return fmt.Errorf("no flow log enabled by osdctl on %s, enable one with 'osdctl network flowlogs enable --cluster-id %s'", vpcID, o.clusterID)
+	}
+
+	var reports []flowLogsReport
+	for _, flowLog := range flowLogs {
+		flowLogID := aws.ToString(flowLog.FlowLogId)
+		bucket := flowLogTag(flowLog, flowLogsBucketTag)
+		records, err := o.fetchRecords(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		topTalkers, rejected := summarizeFlows(records, o.top)
+		reports = append(reports, flowLogsReport{FlowLogID: flowLogID, Bucket: bucket, Records: len(records), TopTalkers: topTalkers, RejectedTop: rejected})
+	}
+	if err := o.printReports(reports); err != nil {
+		return err
+	}
+
+	for _, flowLog := range flowLogs {
+		if !o.cleanup && !o.expired(flowLog) {
+			_, _ = fmt.Fprintf(o.errOut, "Flow log %s stays enabled until %s, fetch again later or pass --cleanup to delete it now\n", aws.ToString(flowLog.FlowLogId), flowLogTag(flowLog, flowLogsExpiresTag))
+			continue
+		}
+		if err := o.deleteFlowLog(ctx, flowLog); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchRecords downloads and parses the flow log files of the bucket
+func (o *flowLogsFetchOptions) fetchRecords(ctx context.Context, bucket string) ([]flowRecord, error) {
+	var records []flowRecord
+	paginator := s3.NewListObjectsV2Paginator(o.s3Client, &s3.ListObjectsV2Input{Bucket: aws.String(bucket)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the flow log files of %s: %w", bucket, err)
+		}
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			if !strings.HasSuffix(key, ".log.gz") {
+				continue
+			}
+			resp, err := o.s3Client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: object.Key})
+			if err != nil {
+				return nil, fmt.Errorf("failed to download %s: %w", key, err)
+			}
+			fileRecords, err := parseFlowLogFile(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", key, err)
+			}
+			records = append(records, fileRecords...)
+		}
+	}
+	return records, nil
+}
+
+// expired returns whether the duration of the flow log is over, a flow log without a valid expiry is considered expired
+func (o *flowLogsOptions) expired(flowLog ec2types.FlowLog) bool {
+	expiresAt, err := time.Parse(time.RFC3339, flowLogTag(flowLog, flowLogsExpiresTag))
+	return err != nil || !o.now().Before(expiresAt)
+}
+
+// deleteFlowLog deletes the flow log, then the files of its bucket and the bucket
+func (o *flowLogsOptions) deleteFlowLog(ctx context.Context, flowLog ec2types.FlowLog) error {
+	flowLogID := aws.ToString(flowLog.FlowLogId)
+	if _, err := o.ec2Client.DeleteFlowLogs(ctx, &ec2.DeleteFlowLogsInput{FlowLogIds: []string{flowLogID}}); err != nil {
+		return fmt.Errorf("failed to delete the flow log %s: %w", flowLogID, err)
+	}
+
+	bucket := flowLogTag(flowLog, flowLogsBucketTag)
+	if !strings.HasPrefix(bucket, flowLogsBucketPrefix) {
+		return fmt.Errorf("refusing to delete the bucket %q of flow log %s, it wasn't created by osdctl", bucket, flowLogID)
+	}
+	paginator := s3.NewListObjectsV2Paginator(o.s3Client, &s3.ListObjectsV2Input{Bucket: aws.String(bucket)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list the flow log files of %s: %w", bucket, err)
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+		objects := make([]s3types.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, s3types.ObjectIdentifier{Key: object.Key})
+		}
+		if _, err := o.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{Bucket: aws.String(bucket), Delete: &s3types.Delete{Objects: objects}}); err != nil {
+			return fmt.Errorf("failed to delete the flow log files of %s: %w", bucket, err)
+		}
+	}
+	if _, err := o.s3Client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		return fmt.Errorf("failed to delete the bucket %s: %w", bucket, err)
+	}
+	_, _ = fmt.Fprintf(o.errOut, "Deleted flow log %s and bucket %s\n", flowLogID, bucket)
+	return nil
+}
+
+func (o *flowLogsFetchOptions) printReports(reports []flowLogsReport) error {
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	}
+
+	for _, report := range reports {
+		_, _ = fmt.Fprintf(o.out, "Flow log %s (s3://%s): %d records\n", report.FlowLogID, report.Bucket, report.Records)
+		for _, section := range []struct {
+			title string
+			flows []flowSummary
+		}{{"Top talkers", report.TopTalkers}, {"Rejected flows", report.RejectedTop}} {
+			_, _ = fmt.Fprintf(o.out, "\n%s:\n", section.title)
+			if len(section.flows) == 0 {
+				_, _ = fmt.Fprintln(o.out, "  none")
+				continue
+			}
+			table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+			table.AddRow([]string{"SOURCE", "DESTINATION", "PORT", "PROTOCOL", "RECORDS", "PACKETS", "BYTES"})
+			for _, flow := range section.flows {
+				table.AddRow([]string{flow.SrcAddr, flow.DstAddr, flow.DstPort, flow.Protocol, strconv.Itoa(flow.Records), strconv.FormatInt(flow.Packets, 10), strconv.FormatInt(flow.Bytes, 10)})
+			}
+			if err := table.Flush(); err != nil {
+				return err
+			}
+		}
+		_, _ = fmt.Fprintln(o.out)
+	}
+	return nil
+}
+
+// parseFlowLogFile parses a gzipped flow log file of the default format, skipping its header and the records
+// without data
+func parseFlowLogFile(r io.Reader) ([]flowRecord, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var records []flowRecord
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 14 || fields[0] == "version" || fields[13] != "OK" {
+			continue
+		}
+		packets, err := strconv.ParseInt(fields[8], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid packets %q: %w", fields[8], err)
+		}
+		bytes, err := strconv.ParseInt(fields[9], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytes %q: %w", fields[9], err)
+		}
+		records = append(records, flowRecord{
+			SrcAddr:  fields[3],
+			DstAddr:  fields[4],
+			DstPort:  fields[6],
+			Protocol: protocolName(fields[7]),
+			Packets:  packets,
+			Bytes:    bytes,
+			Action:   fields[12],
+		})
+	}
+	return records, scanner.Err()
+}
+
+// summarizeFlows aggregates the records by source, destination, port and protocol, and returns the top flows by
+// bytes and the top rejected flows by number of records
+func summarizeFlows(records []flowRecord, top int) ([]flowSummary, []flowSummary) {
+	all := map[string]*flowSummary{}
+	rejected := map[string]*flowSummary{}
+	for _, record := range records {
+		key := strings.Join([]string{record.SrcAddr, record.DstAddr, record.DstPort, record.Protocol}, " ")
+		addFlow(all, key, record)
+		if record.Action == "REJECT" {
+			addFlow(rejected, key, record)
+		}
+	}
+
+	topTalkers := sortedFlows(all, func(a, b flowSummary) bool { return a.Bytes > b.Bytes })
+	topRejected := sortedFlows(rejected, func(a, b flowSummary) bool { return a.Records > b.Records })
+	return topTalkers[:min(top, len(topTalkers))], topRejected[:min(top, len(topRejected))]
+}
+
+func addFlow(flows map[string]*flowSummary, key string, record flowRecord) {
+	flow, ok := flows[key]
+	if !ok {
+		flow = &flowSummary{SrcAddr: record.SrcAddr, DstAddr: record.DstAddr, DstPort: record.DstPort, Protocol: record.Protocol}
+		flows[key] = flow
+	}
+	flow.Records++
+	flow.Packets += record.Packets
+	flow.Bytes += record.Bytes
+}
+
+func sortedFlows(flows map[string]*flowSummary, less func(a, b flowSummary) bool) []flowSummary {
+	sorted := make([]flowSummary, 0, len(flows))
+	for _, flow := range flows {
+		sorted = append(sorted, *flow)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if less(sorted[i], sorted[j]) != less(sorted[j], sorted[i]) {
+			return less(sorted[i], sorted[j])
+		}
+		// Keep the output stable for flows of the same size
+		return sorted[i].SrcAddr+sorted[i].DstAddr+sorted[i].DstPort < sorted[j].SrcAddr+sorted[j].DstAddr+sorted[j].DstPort
+	})
+	return sorted
+}
+
+func protocolName(number string) string {
+	switch number {
+	case "1":
+		return "ICMP"
+	case "6":
+		return "TCP"
+	case "17":
+		return "UDP"
+	default:
+		return number
+	}
+}
+
+func flowLogTag(flowLog ec2types.FlowLog, key string) string {
+	for _, tag := range flowLog.Tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
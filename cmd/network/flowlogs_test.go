@@ -0,0 +1,241 @@
+package network
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFlowLogsEC2 struct {
+	flowLogs []ec2types.FlowLog
+	created  *ec2.CreateFlowLogsInput
+	deleted  []string
+}
+
+func (f *fakeFlowLogsEC2) DescribeSubnets(_ context.Context, _ *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{{VpcId: aws.String("vpc-byo")}}}, nil
+}
+
+func (f *fakeFlowLogsEC2) DescribeVpcs(_ context.Context, _ *ec2.DescribeVpcsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	return &ec2.DescribeVpcsOutput{Vpcs: []ec2types.Vpc{{VpcId: aws.String("vpc-1")}}}, nil
+}
+
+func (f *fakeFlowLogsEC2) CreateFlowLogs(_ context.Context, params *ec2.CreateFlowLogsInput, _ ...func(*ec2.Options)) (*ec2.CreateFlowLogsOutput, error) {
+	f.created = params
+	return &ec2.CreateFlowLogsOutput{FlowLogIds: []string{"fl-1"}}, nil
+}
+
+func (f *fakeFlowLogsEC2) DescribeFlowLogs(_ context.Context, _ *ec2.DescribeFlowLogsInput, _ ...func(*ec2.Options)) (*ec2.DescribeFlowLogsOutput, error) {
+	return &ec2.DescribeFlowLogsOutput{FlowLogs: f.flowLogs}, nil
+}
+
+func (f *fakeFlowLogsEC2) DeleteFlowLogs(_ context.Context, params *ec2.DeleteFlowLogsInput, _ ...func(*ec2.Options)) (*ec2.DeleteFlowLogsOutput, error) {
+	f.deleted = append(f.deleted, params.FlowLogIds...)
+	return &ec2.DeleteFlowLogsOutput{}, nil
+}
+
+type fakeFlowLogsS3 struct {
+	objects        map[string][]byte
+	createdBucket  *s3.CreateBucketInput
+	lifecycle      *s3.PutBucketLifecycleConfigurationInput
+	deletedObjects int
+	deletedBucket  string
+}
+
+func (f *fakeFlowLogsS3) CreateBucket(_ context.Context, params *s3.CreateBucketInput, _ ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+	f.createdBucket = params
+	return &s3.CreateBucketOutput{}, nil
+}
+
+func (f *fakeFlowLogsS3) PutBucketLifecycleConfiguration(_ context.Context, params *s3.PutBucketLifecycleConfigurationInput, _ ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	f.lifecycle = params
+	return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+}
+
+func (f *fakeFlowLogsS3) ListObjectsV2(_ context.Context, _ *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	out := &s3.ListObjectsV2Output{}
+	for key := range f.objects {
+		out.Contents = append(out.Contents, s3types.Object{Key: aws.String(key)})
+	}
+	return out, nil
+}
+
+func (f *fakeFlowLogsS3) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(f.objects[aws.ToString(params.Key)]))}, nil
+}
+
+func (f *fakeFlowLogsS3) DeleteObjects(_ context.Context, params *s3.DeleteObjectsInput, _ ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	f.deletedObjects += len(params.Delete.Objects)
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func (f *fakeFlowLogsS3) DeleteBucket(_ context.Context, params *s3.DeleteBucketInput, _ ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+	f.deletedBucket = aws.ToString(params.Bucket)
+	return &s3.DeleteBucketOutput{}, nil
+}
+
+func gzipFlowLog(t *testing.T, lines ...string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	_, err := gz.Write([]byte("version account-id interface-id srcaddr dstaddr srcport dstport protocol packets bytes start end action log-status\n" + strings.Join(lines, "\n") + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func newFlowLogsCluster(t *testing.T) *cmv1.Cluster {
+	cluster, err := cmv1.NewCluster().ID("ABC123").InfraID("abc-x1y2").
+		Region(cmv1.NewCloudRegion().ID("eu-west-1")).Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func osdctlFlowLog(expiresAt time.Time) ec2types.FlowLog {
+	return ec2types.FlowLog{FlowLogId: aws.String("fl-1"), Tags: []ec2types.Tag{
+		{Key: aws.String(flowLogsBucketTag), Value: aws.String("osdctl-flowlogs-abc123-1700000000")},
+		{Key: aws.String(flowLogsExpiresTag), Value: aws.String(expiresAt.Format(time.RFC3339))},
+	}}
+}
+
+func TestFlowLogsEnable(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ec2Client := &fakeFlowLogsEC2{}
+	s3Client := &fakeFlowLogsS3{}
+	out := &bytes.Buffer{}
+	opts := &flowLogsEnableOptions{
+		flowLogsOptions: flowLogsOptions{clusterID: "ABC123", out: out, cluster: newFlowLogsCluster(t), region: "eu-west-1", ec2Client: ec2Client, s3Client: s3Client, now: func() time.Time { return now }},
+		duration:        time.Hour,
+		trafficType:     "REJECT",
+	}
+	require.NoError(t, opts.run(context.Background()))
+
+	bucket := "osdctl-flowlogs-abc123-" + "1748779200"
+	assert.Equal(t, bucket, aws.ToString(s3Client.createdBucket.Bucket))
+	assert.Equal(t, s3types.BucketLocationConstraint("eu-west-1"), s3Client.createdBucket.CreateBucketConfiguration.LocationConstraint)
+	require.NotNil(t, s3Client.lifecycle)
+	assert.Equal(t, bucket, aws.ToString(s3Client.lifecycle.Bucket))
+	assert.Equal(t, int32(flowLogsRetentionDays), aws.ToInt32(s3Client.lifecycle.LifecycleConfiguration.Rules[0].Expiration.Days))
+
+	require.NotNil(t, ec2Client.created)
+	assert.Equal(t, []string{"vpc-1"}, ec2Client.created.ResourceIds)
+	assert.Equal(t, ec2types.TrafficTypeReject, ec2Client.created.TrafficType)
+	assert.Equal(t, "arn:aws:s3:::"+bucket, aws.ToString(ec2Client.created.LogDestination))
+	assert.Equal(t, "2025-06-01T13:00:00Z", flowLogTag(ec2types.FlowLog{Tags: ec2Client.created.TagSpecifications[0].Tags}, flowLogsExpiresTag))
+	assert.Contains(t, out.String(), "Enabled flow log fl-1 on vpc-1")
+}
+
+func TestFlowLogsEnableAlreadyEnabled(t *testing.T) {
+	s3Client := &fakeFlowLogsS3{}
+	opts := &flowLogsEnableOptions{
+		flowLogsOptions: flowLogsOptions{clusterID: "ABC123", out: &bytes.Buffer{}, cluster: newFlowLogsCluster(t), region: "eu-west-1", ec2Client: &fakeFlowLogsEC2{flowLogs: []ec2types.FlowLog{osdctlFlowLog(time.Now().Add(time.Hour))}}, s3Client: s3Client, now: time.Now},
+		duration:        time.Hour,
+		trafficType:     "ALL",
+	}
+	err := opts.run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already enabled")
+	assert.Nil(t, s3Client.createdBucket)
+}
+
+func TestFlowLogsEnableCleansUpExpired(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ec2Client := &fakeFlowLogsEC2{flowLogs: []ec2types.FlowLog{osdctlFlowLog(now.Add(-time.Hour))}}
+	s3Client := &fakeFlowLogsS3{objects: map[string][]byte{"AWSLogs/a.log.gz": nil}}
+	opts := &flowLogsEnableOptions{
+		flowLogsOptions: flowLogsOptions{clusterID: "ABC123", out: &bytes.Buffer{}, errOut: &bytes.Buffer{}, cluster: newFlowLogsCluster(t), region: "eu-west-1", ec2Client: ec2Client, s3Client: s3Client, now: func() time.Time { return now }},
+		duration:        time.Hour,
+		trafficType:     "ALL",
+	}
+	require.NoError(t, opts.run(context.Background()))
+
+	assert.Equal(t, []string{"fl-1"}, ec2Client.deleted)
+	assert.Equal(t, "osdctl-flowlogs-abc123-1700000000", s3Client.deletedBucket)
+	assert.Equal(t, 1, s3Client.deletedObjects)
+	require.NotNil(t, ec2Client.created)
+}
+
+func TestFlowLogsFetch(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	objects := map[string][]byte{
+		"AWSLogs/123/vpcflowlogs/eu-west-1/2025/06/01/a.log.gz": gzipFlowLog(t,
+			"2 123 eni-1 10.0.1.5 10.0.2.7 44321 443 6 10 5000 1 2 ACCEPT OK",
+			"2 123 eni-1 10.0.1.5 10.0.2.7 44322 443 6 20 9000 1 2 ACCEPT OK",
+			"2 123 eni-1 10.0.1.6 52.1.2.3 44323 53 17 1 80 1 2 ACCEPT OK",
+			"2 123 eni-2 1.2.3.4 10.0.1.5 5555 22 6 1 40 1 2 REJECT OK",
+			"2 123 eni-2 1.2.3.4 10.0.1.5 5556 22 6 1 40 1 2 REJECT OK",
+			"2 123 eni-3 - - - - - - - 1 2 - NODATA",
+		),
+		"AWSLogs/123/vpcflowlogs/eu-west-1/2025/06/01/": nil,
+	}
+
+	tests := []struct {
+		name          string
+		expiresAt     time.Time
+		cleanup       bool
+		expectDeleted bool
+	}{
+		{name: "not expired keeps the flow log", expiresAt: now.Add(time.Minute)},
+		{name: "expired is cleaned up", expiresAt: now.Add(-time.Minute), expectDeleted: true},
+		{name: "cleanup before expiry", expiresAt: now.Add(time.Minute), cleanup: true, expectDeleted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ec2Client := &fakeFlowLogsEC2{flowLogs: []ec2types.FlowLog{osdctlFlowLog(tt.expiresAt)}}
+			s3Client := &fakeFlowLogsS3{objects: objects}
+			out := &bytes.Buffer{}
+			opts := &flowLogsFetchOptions{
+				flowLogsOptions: flowLogsOptions{clusterID: "ABC123", out: out, errOut: &bytes.Buffer{}, cluster: newFlowLogsCluster(t), region: "eu-west-1", ec2Client: ec2Client, s3Client: s3Client, now: func() time.Time { return now }},
+				top:             10,
+				output:          "json",
+				cleanup:         tt.cleanup,
+			}
+			require.NoError(t, opts.run(context.Background()))
+
+			var reports []flowLogsReport
+			require.NoError(t, json.Unmarshal(out.Bytes(), &reports))
+			require.Len(t, reports, 1)
+			assert.Equal(t, 5, reports[0].Records)
+			require.Len(t, reports[0].TopTalkers, 3)
+			assert.Equal(t, flowSummary{SrcAddr: "10.0.1.5", DstAddr: "10.0.2.7", DstPort: "443", Protocol: "TCP", Records: 2, Packets: 30, Bytes: 14000}, reports[0].TopTalkers[0])
+			require.Len(t, reports[0].RejectedTop, 1)
+			assert.Equal(t, flowSummary{SrcAddr: "1.2.3.4", DstAddr: "10.0.1.5", DstPort: "22", Protocol: "TCP", Records: 2, Packets: 2, Bytes: 80}, reports[0].RejectedTop[0])
+
+			if tt.expectDeleted {
+				assert.Equal(t, []string{"fl-1"}, ec2Client.deleted)
+				assert.Equal(t, 2, s3Client.deletedObjects)
+				assert.Equal(t, "osdctl-flowlogs-abc123-1700000000", s3Client.deletedBucket)
+			} else {
+				assert.Empty(t, ec2Client.deleted)
+				assert.Empty(t, s3Client.deletedBucket)
+			}
+		})
+	}
+}
+
+func TestSummarizeFlowsTop(t *testing.T) {
+	records := []flowRecord{
+		{SrcAddr: "a", DstAddr: "b", DstPort: "1", Protocol: "TCP", Bytes: 10, Action: "ACCEPT"},
+		{SrcAddr: "a", DstAddr: "c", DstPort: "1", Protocol: "TCP", Bytes: 30, Action: "ACCEPT"},
+		{SrcAddr: "a", DstAddr: "d", DstPort: "1", Protocol: "TCP", Bytes: 20, Action: "REJECT"},
+	}
+	topTalkers, rejected := summarizeFlows(records, 2)
+	require.Len(t, topTalkers, 2)
+	assert.Equal(t, "c", topTalkers[0].DstAddr)
+	assert.Equal(t, "d", topTalkers[1].DstAddr)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, "d", rejected[0].DstAddr)
+}
@@ -0,0 +1,417 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	checkPass = "PASS"
+	checkFail = "FAIL"
+
+	// classicAPIPort is the port of the API of ROSA Classic clusters, served by the NLB of the endpoint service
+	classicAPIPort = 6443
+	// hcpAPIPort is the port of the API of HCP clusters, reached through the VPC endpoint of the cluster VPC
+	hcpAPIPort = 443
+)
+
+// privateLinkEC2Client is the subset of the EC2 API used to inspect the PrivateLink resources of a cluster
+type privateLinkEC2Client interface {
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error)
+	DescribeVpcEndpointServiceConfigurations(ctx context.Context, params *ec2.DescribeVpcEndpointServiceConfigurationsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error)
+	DescribeVpcEndpointConnections(ctx context.Context, params *ec2.DescribeVpcEndpointConnectionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointConnectionsOutput, error)
+	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+}
+
+// privateLinkRoute53Client is the subset of the Route53 API used to inspect the private DNS of a cluster
+type privateLinkRoute53Client interface {
+	ListHostedZonesByVPC(ctx context.Context, params *route53.ListHostedZonesByVPCInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesByVPCOutput, error)
+	ListResourceRecordSets(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error)
+}
+
+// privateLinkOptions defines the struct for running the verify-privatelink command
+type privateLinkOptions struct {
+	clusterID string
+	output    string
+	awsConfig *awsprovider.ClusterConfigFactory
+
+	out           io.Writer
+	cluster       *cmv1.Cluster
+	region        string
+	ec2Client     privateLinkEC2Client
+	route53Client privateLinkRoute53Client
+}
+
+// privateLinkCheck is the result of verifying one PrivateLink resource of the cluster
+type privateLinkCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Details string `json:"details"`
+}
+
+func newCmdVerifyPrivateLink() *cobra.Command {
+	ops := &privateLinkOptions{awsConfig: awsprovider.NewClusterConfigFactory(), out: os.Stdout}
+	verifyPrivateLinkCmd := &cobra.Command{
+		Use:   "verify-privatelink",
+		Short: "Verify the PrivateLink configuration an AWS PrivateLink cluster API is reached through",
+		Long: `Verifies the AWS resources the API of a PrivateLink cluster is reached through, from the AWS account of the
+cluster, and reports the misconfigurations which make it unreachable:
+
+  ROSA Classic clusters:
+  - the VPC endpoint service of the API load balancer is available
+  - the VPC endpoint connections to the service are accepted
+  - the control plane security groups allow the API port from the machine CIDR
+
+  HCP clusters:
+  - the VPC endpoint of the API in the cluster VPC is accepted and available
+  - the security groups of the VPC endpoint allow the API port from the machine CIDR
+
+  All clusters:
+  - a private hosted zone associated with the cluster VPC resolves the API hostname
+
+The command exits with an error when any check fails.`,
+		Example: `  # Verify the PrivateLink configuration of a cluster
+  osdctl network verify-privatelink --cluster-id ${CLUSTER_ID}
+
+  # Print the results as JSON
+  osdctl network verify-privatelink --cluster-id ${CLUSTER_ID} -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ops.complete(); err != nil {
+				return err
+			}
+			return ops.run(cmd.Context())
+		},
+	}
+
+	verifyPrivateLinkCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID whose PrivateLink configuration should be verified")
+	verifyPrivateLinkCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Output format: table or json")
+	ops.awsConfig.AddRegionFlag(verifyPrivateLinkCmd.Flags())
+
+	_ = verifyPrivateLinkCmd.MarkFlagRequired("cluster-id")
+
+	return verifyPrivateLinkCmd
+}
+
+func (o *privateLinkOptions) complete() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	return utils.ValidateClusterKey("cluster-id", o.clusterID)
+}
+
+func (o *privateLinkOptions) run(ctx context.Context) error {
+	if o.cluster == nil {
+		ocmClient, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer ocmClient.Close()
+
+		o.cluster, err = utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+		if err != nil {
+			return err
+		}
+		if !o.cluster.AWS().PrivateLink() {
+			return fmt.Errorf("cluster %s is not an AWS PrivateLink cluster", o.cluster.ID())
+		}
+
+		cfg, err := o.awsConfig.Config(ocmClient, o.cluster)
+		if err != nil {
+			return fmt.Errorf("failed to get AWS credentials of cluster %s: %w", o.cluster.ID(), err)
+		}
+		o.region = cfg.Region
+		o.ec2Client = ec2.NewFromConfig(cfg)
+		o.route53Client = route53.NewFromConfig(cfg)
+	}
+
+	vpcID, err := o.clusterVPC(ctx)
+	if err != nil {
+		return err
+	}
+
+	var checks []privateLinkCheck
+	if o.cluster.Hypershift().Enabled() {
+		endpoint, groupIDs := o.checkHCPEndpoint(ctx, vpcID)
+		checks = append(checks, endpoint)
+		if len(groupIDs) > 0 {
+			checks = append(checks, o.checkSecurityGroups(ctx, "VPC endpoint security groups", &ec2.DescribeSecurityGroupsInput{GroupIds: groupIDs}, hcpAPIPort))
+		}
+	} else {
+		service, serviceID := o.checkEndpointService(ctx)
+		checks = append(checks, service)
+		if serviceID != "" {
+			checks = append(checks, o.checkEndpointConnections(ctx, serviceID))
+		}
+		checks = append(checks, o.checkSecurityGroups(ctx, "Control plane security groups", &ec2.DescribeSecurityGroupsInput{
+			Filters: []ec2types.Filter{
+				{Name: aws.String("vpc-id"), Values: []string{vpcID}},
+				{Name: aws.String("tag:Name"), Values: []string{o.cluster.InfraID() + "-master-sg", o.cluster.InfraID() + "-controlplane"}},
+			},
+		}, classicAPIPort))
+	}
+	checks = append(checks, o.checkAPIDNS(ctx, vpcID))
+
+	if err := o.printChecks(checks); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == checkFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d PrivateLink checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// clusterVPC returns the VPC of the subnets of the cluster
+func (o *privateLinkOptions) clusterVPC(ctx context.Context) (string, error) {
+	subnetIDs := o.cluster.AWS().SubnetIDs()
+	if len(subnetIDs) == 0 {
+		return "", fmt.Errorf("cluster %s has no subnets in OCM, PrivateLink clusters are installed in existing subnets", o.cluster.ID())
+	}
+	resp, err := o.ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{SubnetIds: subnetIDs[:1]})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe subnet %s: %w", subnetIDs[0], err)
+	}
+	if len(resp.Subnets) == 0 || resp.Subnets[0].VpcId == nil {
+		return "", fmt.Errorf("subnet %s of the cluster not found", subnetIDs[0])
+	}
+	return *resp.Subnets[0].VpcId, nil
+}
+
+// checkEndpointService verifies the VPC endpoint service of the internal API load balancer of a ROSA Classic cluster
+// and returns its ID
+func (o *privateLinkOptions) checkEndpointService(ctx context.Context) (privateLinkCheck, string) {
+	c := privateLinkCheck{Name: "VPC endpoint service"}
+	// The installer names the internal API load balancer <infra ID>-int
+	lbName := "/net/" + o.cluster.InfraID() + "-int/"
+
+	resp, err := o.ec2Client.DescribeVpcEndpointServiceConfigurations(ctx, &ec2.DescribeVpcEndpointServiceConfigurationsInput{})
+	if err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to describe the VPC endpoint services: %v", err)
+		return c, ""
+	}
+	for _, service := range resp.ServiceConfigurations {
+		if !containsSubstring(service.NetworkLoadBalancerArns, lbName) {
+			continue
+		}
+		serviceID := aws.ToString(service.ServiceId)
+		if service.ServiceState != ec2types.ServiceStateAvailable {
+			c.Status, c.Details = checkFail, fmt.Sprintf("%s is %s", serviceID, service.ServiceState)
+			return c, serviceID
+		}
+		c.Status, c.Details = checkPass, fmt.Sprintf("%s is available (acceptance required: %t)", serviceID, aws.ToBool(service.AcceptanceRequired))
+		return c, serviceID
+	}
+
+	c.Status, c.Details = checkFail, fmt.Sprintf("no VPC endpoint service for the API load balancer %s-int, the API can't be reached through PrivateLink", o.cluster.InfraID())
+	return c, ""
+}
+
+// checkEndpointConnections verifies the connections to the endpoint service of a ROSA Classic cluster are accepted
+func (o *privateLinkOptions) checkEndpointConnections(ctx context.Context, serviceID string) privateLinkCheck {
+	c := privateLinkCheck{Name: "VPC endpoint connections"}
+	resp, err := o.ec2Client.DescribeVpcEndpointConnections(ctx, &ec2.DescribeVpcEndpointConnectionsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("service-id"), Values: []string{serviceID}}},
+	})
+	if err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to describe the connections of %s: %v", serviceID, err)
+		return c
+	}
+	if len(resp.VpcEndpointConnections) == 0 {
+		c.Status, c.Details = checkFail, fmt.Sprintf("no VPC endpoint is connected to %s", serviceID)
+		return c
+	}
+
+	var states, broken []string
+	for _, conn := range resp.VpcEndpointConnections {
+		state := fmt.Sprintf("%s (owner %s): %s", aws.ToString(conn.VpcEndpointId), aws.ToString(conn.VpcEndpointOwner), conn.VpcEndpointState)
+		states = append(states, state)
+		if conn.VpcEndpointState != ec2types.StateAvailable {
+			broken = append(broken, state)
+		}
+	}
+	if len(broken) > 0 {
+		c.Status, c.Details = checkFail, "connections not available: "+strings.Join(broken, ", ")
+		return c
+	}
+	c.Status, c.Details = checkPass, strings.Join(states, ", ")
+	return c
+}
+
+// checkHCPEndpoint verifies the VPC endpoint of the API of an HCP cluster and returns its security groups
+func (o *privateLinkOptions) checkHCPEndpoint(ctx context.Context, vpcID string) (privateLinkCheck, []string) {
+	c := privateLinkCheck{Name: "VPC endpoint"}
+	resp, err := o.ec2Client.DescribeVpcEndpoints(ctx, &ec2.DescribeVpcEndpointsInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("vpc-id"), Values: []string{vpcID}},
+			{Name: aws.String("tag-key"), Values: []string{"kubernetes.io/cluster/" + o.cluster.InfraID()}},
+		},
+	})
+	if err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to describe the VPC endpoints of %s: %v", vpcID, err)
+		return c, nil
+	}
+	if len(resp.VpcEndpoints) == 0 {
+		c.Status, c.Details = checkFail, fmt.Sprintf("no VPC endpoint of cluster %s in %s, the API can't be reached through PrivateLink", o.cluster.InfraID(), vpcID)
+		return c, nil
+	}
+
+	endpoint := resp.VpcEndpoints[0]
+	var groupIDs []string
+	for _, group := range endpoint.Groups {
+		groupIDs = append(groupIDs, aws.ToString(group.GroupId))
+	}
+	switch endpoint.State {
+	case ec2types.StateAvailable:
+		c.Status, c.Details = checkPass, fmt.Sprintf("%s to %s is available", aws.ToString(endpoint.VpcEndpointId), aws.ToString(endpoint.ServiceName))
+	case ec2types.StatePendingAcceptance:
+		c.Status, c.Details = checkFail, fmt.Sprintf("%s is pending acceptance by the endpoint service %s of the management cluster", aws.ToString(endpoint.VpcEndpointId), aws.ToString(endpoint.ServiceName))
+	default:
+		c.Status, c.Details = checkFail, fmt.Sprintf("%s is %s", aws.ToString(endpoint.VpcEndpointId), endpoint.State)
+	}
+	return c, groupIDs
+}
+
+// checkSecurityGroups verifies at least one of the security groups allows the API port from the machine CIDR
+func (o *privateLinkOptions) checkSecurityGroups(ctx context.Context, name string, input *ec2.DescribeSecurityGroupsInput, port int32) privateLinkCheck {
+	c := privateLinkCheck{Name: name}
+	machineCIDR, err := netip.ParsePrefix(o.cluster.Network().MachineCIDR())
+	if err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("invalid machine CIDR %q: %v", o.cluster.Network().MachineCIDR(), err)
+		return c
+	}
+
+	resp, err := o.ec2Client.DescribeSecurityGroups(ctx, input)
+	if err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to describe the security groups: %v", err)
+		return c
+	}
+	if len(resp.SecurityGroups) == 0 {
+		c.Status, c.Details = checkFail, "no security group found"
+		return c
+	}
+
+	var ids []string
+	for _, sg := range resp.SecurityGroups {
+		ids = append(ids, aws.ToString(sg.GroupId))
+		if allowsIngress(sg, port, machineCIDR) {
+			c.Status, c.Details = checkPass, fmt.Sprintf("%s allows tcp/%d from %s", aws.ToString(sg.GroupId), port, machineCIDR)
+			return c
+		}
+	}
+	c.Status, c.Details = checkFail, fmt.Sprintf("none of %s allows tcp/%d from the machine CIDR %s", strings.Join(ids, ", "), port, machineCIDR)
+	return c
+}
+
+// allowsIngress returns whether a rule of the security group allows the TCP port from the whole CIDR
+func allowsIngress(sg ec2types.SecurityGroup, port int32, cidr netip.Prefix) bool {
+	for _, perm := range sg.IpPermissions {
+		protocol := aws.ToString(perm.IpProtocol)
+		if protocol != "-1" && protocol != "tcp" {
+			continue
+		}
+		if protocol == "tcp" && (aws.ToInt32(perm.FromPort) > port || aws.ToInt32(perm.ToPort) < port) {
+			continue
+		}
+		for _, r := range perm.IpRanges {
+			allowed, err := netip.ParsePrefix(aws.ToString(r.CidrIp))
+			if err == nil && allowed.Bits() <= cidr.Bits() && allowed.Contains(cidr.Addr()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkAPIDNS verifies a private hosted zone associated with the VPC holds a record for the API hostname
+func (o *privateLinkOptions) checkAPIDNS(ctx context.Context, vpcID string) privateLinkCheck {
+	c := privateLinkCheck{Name: "API private DNS"}
+	apiURL, err := url.Parse(o.cluster.API().URL())
+	if err != nil || apiURL.Hostname() == "" {
+		c.Status, c.Details = checkFail, fmt.Sprintf("invalid API URL %q", o.cluster.API().URL())
+		return c
+	}
+	apiHost := apiURL.Hostname() + "."
+
+	resp, err := o.route53Client.ListHostedZonesByVPC(ctx, &route53.ListHostedZonesByVPCInput{
+		VPCId:     aws.String(vpcID),
+		VPCRegion: route53types.VPCRegion(o.region),
+	})
+	if err != nil {
+		c.Status, c.Details = checkFail, fmt.Sprintf("failed to list the hosted zones of %s: %v", vpcID, err)
+		return c
+	}
+
+	for _, zone := range resp.HostedZoneSummaries {
+		if !strings.HasSuffix(apiHost, "."+aws.ToString(zone.Name)) {
+			continue
+		}
+		records, err := o.route53Client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+			HostedZoneId:    zone.HostedZoneId,
+			StartRecordName: aws.String(apiHost),
+			MaxItems:        aws.Int32(1),
+		})
+		if err != nil {
+			c.Status, c.Details = checkFail, fmt.Sprintf("failed to list the records of %s: %v", aws.ToString(zone.Name), err)
+			return c
+		}
+		if len(records.ResourceRecordSets) > 0 && aws.ToString(records.ResourceRecordSets[0].Name) == apiHost {
+			c.Status, c.Details = checkPass, fmt.Sprintf("%s resolves %s (%s record)", aws.ToString(zone.Name), apiHost, records.ResourceRecordSets[0].Type)
+			return c
+		}
+		c.Status, c.Details = checkFail, fmt.Sprintf("private hosted zone %s has no record for %s", aws.ToString(zone.Name), apiHost)
+		return c
+	}
+
+	c.Status, c.Details = checkFail, fmt.Sprintf("no private hosted zone for %s is associated with %s", apiHost, vpcID)
+	return c
+}
+
+func (o *privateLinkOptions) printChecks(checks []privateLinkCheck) error {
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(checks)
+	}
+
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, c := range checks {
+		table.AddRow([]string{c.Name, c.Status, c.Details})
+	}
+	return table.Flush()
+}
+
+func containsSubstring(values []string, substr string) bool {
+	for _, v := range values {
+		if strings.Contains(v, substr) {
+			return true
+		}
+	}
+	return false
+}
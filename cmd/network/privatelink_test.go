@@ -0,0 +1,146 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePrivateLinkEC2 struct {
+	services      []ec2types.ServiceConfiguration
+	connections   []ec2types.VpcEndpointConnection
+	endpoints     []ec2types.VpcEndpoint
+	securityGroup ec2types.SecurityGroup
+}
+
+func (f *fakePrivateLinkEC2) DescribeSubnets(_ context.Context, _ *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{{VpcId: aws.String("vpc-1")}}}, nil
+}
+
+func (f *fakePrivateLinkEC2) DescribeVpcEndpoints(_ context.Context, _ *ec2.DescribeVpcEndpointsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+	return &ec2.DescribeVpcEndpointsOutput{VpcEndpoints: f.endpoints}, nil
+}
+
+func (f *fakePrivateLinkEC2) DescribeVpcEndpointServiceConfigurations(_ context.Context, _ *ec2.DescribeVpcEndpointServiceConfigurationsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error) {
+	return &ec2.DescribeVpcEndpointServiceConfigurationsOutput{ServiceConfigurations: f.services}, nil
+}
+
+func (f *fakePrivateLinkEC2) DescribeVpcEndpointConnections(_ context.Context, _ *ec2.DescribeVpcEndpointConnectionsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointConnectionsOutput, error) {
+	return &ec2.DescribeVpcEndpointConnectionsOutput{VpcEndpointConnections: f.connections}, nil
+}
+
+func (f *fakePrivateLinkEC2) DescribeSecurityGroups(_ context.Context, _ *ec2.DescribeSecurityGroupsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: []ec2types.SecurityGroup{f.securityGroup}}, nil
+}
+
+type fakePrivateLinkRoute53 struct {
+	zone   string
+	record string
+}
+
+func (f *fakePrivateLinkRoute53) ListHostedZonesByVPC(_ context.Context, _ *route53.ListHostedZonesByVPCInput, _ ...func(*route53.Options)) (*route53.ListHostedZonesByVPCOutput, error) {
+	return &route53.ListHostedZonesByVPCOutput{HostedZoneSummaries: []route53types.HostedZoneSummary{{HostedZoneId: aws.String("Z1"), Name: aws.String(f.zone)}}}, nil
+}
+
+func (f *fakePrivateLinkRoute53) ListResourceRecordSets(_ context.Context, _ *route53.ListResourceRecordSetsInput, _ ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+	return &route53.ListResourceRecordSetsOutput{ResourceRecordSets: []route53types.ResourceRecordSet{{Name: aws.String(f.record), Type: route53types.RRTypeA}}}, nil
+}
+
+func newPrivateLinkCluster(t *testing.T, hcp bool) *cmv1.Cluster {
+	cluster, err := cmv1.NewCluster().
+		ID("abc123").
+		InfraID("mycluster-x1y2z").
+		Region(cmv1.NewCloudRegion().ID("us-east-1")).
+		AWS(cmv1.NewAWS().PrivateLink(true).SubnetIDs("subnet-1")).
+		Network(cmv1.NewNetwork().MachineCIDR("10.0.0.0/16")).
+		API(cmv1.NewClusterAPI().URL("https://api.mycluster.abcd.p1.openshiftapps.com:6443")).
+		Hypershift(cmv1.NewHypershift().Enabled(hcp)).
+		Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func apiSecurityGroup(port int32, cidr string) ec2types.SecurityGroup {
+	return ec2types.SecurityGroup{
+		GroupId: aws.String("sg-1"),
+		IpPermissions: []ec2types.IpPermission{{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int32(port),
+			ToPort:     aws.Int32(port),
+			IpRanges:   []ec2types.IpRange{{CidrIp: aws.String(cidr)}},
+		}},
+	}
+}
+
+func TestVerifyPrivateLinkClassic(t *testing.T) {
+	ec2Client := &fakePrivateLinkEC2{
+		services: []ec2types.ServiceConfiguration{{
+			ServiceId:               aws.String("vpce-svc-1"),
+			ServiceState:            ec2types.ServiceStateAvailable,
+			AcceptanceRequired:      aws.Bool(true),
+			NetworkLoadBalancerArns: []string{"arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/net/mycluster-x1y2z-int/abc"},
+		}},
+		connections: []ec2types.VpcEndpointConnection{
+			{VpcEndpointId: aws.String("vpce-1"), VpcEndpointOwner: aws.String("456"), VpcEndpointState: ec2types.StateAvailable},
+		},
+		securityGroup: apiSecurityGroup(classicAPIPort, "10.0.0.0/16"),
+	}
+	route53Client := &fakePrivateLinkRoute53{zone: "mycluster.abcd.p1.openshiftapps.com.", record: "api.mycluster.abcd.p1.openshiftapps.com."}
+
+	var out bytes.Buffer
+	o := &privateLinkOptions{output: "table", out: &out, region: "us-east-1", cluster: newPrivateLinkCluster(t, false), ec2Client: ec2Client, route53Client: route53Client}
+	require.NoError(t, o.run(context.Background()))
+	assert.NotContains(t, out.String(), checkFail)
+
+	ec2Client.connections[0].VpcEndpointState = ec2types.StatePendingAcceptance
+	ec2Client.securityGroup = apiSecurityGroup(classicAPIPort, "10.0.1.0/24")
+	route53Client.record = "api-int.mycluster.abcd.p1.openshiftapps.com."
+	out.Reset()
+	err := o.run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "3 of 4")
+	assert.Contains(t, out.String(), "vpce-1 (owner 456): PendingAcceptance")
+	assert.Contains(t, out.String(), "has no record for api.mycluster")
+}
+
+func TestVerifyPrivateLinkHCP(t *testing.T) {
+	ec2Client := &fakePrivateLinkEC2{
+		endpoints: []ec2types.VpcEndpoint{{
+			VpcEndpointId: aws.String("vpce-1"),
+			ServiceName:   aws.String("com.amazonaws.vpce.us-east-1.vpce-svc-1"),
+			State:         ec2types.StatePendingAcceptance,
+			Groups:        []ec2types.SecurityGroupIdentifier{{GroupId: aws.String("sg-1")}},
+		}},
+		securityGroup: apiSecurityGroup(hcpAPIPort, "0.0.0.0/0"),
+	}
+	route53Client := &fakePrivateLinkRoute53{zone: "openshiftapps.com.", record: "api.mycluster.abcd.p1.openshiftapps.com."}
+
+	var out bytes.Buffer
+	o := &privateLinkOptions{output: "json", out: &out, region: "us-east-1", cluster: newPrivateLinkCluster(t, true), ec2Client: ec2Client, route53Client: route53Client}
+	err := o.run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 3")
+	assert.True(t, strings.Contains(out.String(), `"status": "FAIL"`))
+	assert.Contains(t, out.String(), "pending acceptance")
+}
+
+func TestAllowsIngress(t *testing.T) {
+	machineCIDR := netip.MustParsePrefix("10.0.0.0/16")
+	all := ec2types.SecurityGroup{IpPermissions: []ec2types.IpPermission{{IpProtocol: aws.String("-1"), IpRanges: []ec2types.IpRange{{CidrIp: aws.String("10.0.0.0/8")}}}}}
+
+	assert.True(t, allowsIngress(all, hcpAPIPort, machineCIDR))
+	assert.True(t, allowsIngress(apiSecurityGroup(hcpAPIPort, "10.0.0.0/16"), hcpAPIPort, machineCIDR))
+	assert.False(t, allowsIngress(apiSecurityGroup(classicAPIPort, "10.0.0.0/16"), hcpAPIPort, machineCIDR))
+	assert.False(t, allowsIngress(apiSecurityGroup(hcpAPIPort, "10.0.0.0/24"), hcpAPIPort, machineCIDR))
+}
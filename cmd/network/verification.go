@@ -109,6 +109,19 @@ type EgressVerification struct {
 	hiveOcmUrl string
 	// Reason is the justification for elevation (required for pod mode write operations)
 	Reason string
+	// EgressListYaml optionally replaces the platform's list of egress endpoints to verify, using
+	// osd-network-verifier's egress list format. Used by other commands to verify their own endpoints.
+	EgressListYaml string
+	// CompareBaseline compares the blocked egresses with the baseline of the cluster, highlighting the newly blocked ones
+	CompareBaseline bool
+	// SaveBaseline saves the blocked egresses as the baseline of the cluster
+	SaveBaseline bool
+	// AllClustersInMC verifies all the hosted clusters of this management cluster instead of a single cluster
+	AllClustersInMC string
+	// Concurrency is the maximum number of hosted clusters verified at the same time with AllClustersInMC
+	Concurrency int
+	// ProbeFromLocal probes the egress endpoints from the local machine instead of launching the verifier in the VPC
+	ProbeFromLocal bool
 }
 
 func NewCmdValidateEgress() *cobra.Command {
@@ -139,6 +152,23 @@ func NewCmdValidateEgress() *cobra.Command {
      3. User-provided kubeconfig (when --kubeconfig is specified)
      4. Default kubeconfig (from ~/.kube/config)
 
+  Interrupting the command (Ctrl+C) lets the verification of the current subnet finish and clean up its probe
+  instance or pods, and skips the remaining subnets. Interrupting it a second time exits immediately, which may
+  leave temporary resources behind.
+
+  The blocked egresses of a cluster can be saved as its baseline with --save-baseline, as JSON in the osdctl data
+  directory (~/.local/share/osdctl/egress-baselines). --compare-baseline compares a later verification with it and
+  highlights the endpoints blocked since, e.g. by a change of the customer firewall which will break upgrades.
+
+  All the hosted clusters of a management cluster can be verified at once with --all-clusters-in-mc, in pod mode,
+  --concurrency clusters at a time. A progress bar is shown while they are verified, followed by a matrix of the
+  clusters and the endpoints blocked on any of them. No service logs are sent for these verifications.
+
+  With --probe-from-local, the endpoints are probed from the machine running osdctl (e.g. a jumphost) instead of the
+  VPC of the cluster, without any cloud resource or cluster access. This is only an approximation, ignoring the
+  firewall and proxy of the cluster, to quickly tell a global outage of an endpoint from a block specific to the VPC.
+  No service logs are sent for these probes.
+
   Docs: https://docs.openshift.com/rosa/rosa_install_access_delete_clusters/rosa_getting_started_iam/rosa-aws-prereqs.html#osd-aws-privatelink-firewall-prerequisites_prerequisites`,
 		Example: `
   # Run against a cluster registered in OCM
@@ -169,6 +199,16 @@ func NewCmdValidateEgress() *cobra.Command {
   # Run in pod mode with custom namespace and kubeconfig (no elevation needed with explicit kubeconfig)
   osdctl network verify-egress --pod-mode --region us-east-1 --namespace my-namespace --kubeconfig ~/.kube/config
 
+  # Save the blocked egresses of a cluster as its baseline, and later show the ones blocked since
+  osdctl network verify-egress --cluster-id my-rosa-cluster --save-baseline
+  osdctl network verify-egress --cluster-id my-rosa-cluster --compare-baseline --save-baseline
+
+  # Verify all the hosted clusters of a management cluster, 5 at a time
+  osdctl network verify-egress --all-clusters-in-mc my-mc --reason "OHSS-12345" --concurrency 5
+
+  # Probe the endpoints of a cluster from this machine, to tell a global outage from a VPC-specific block
+  osdctl network verify-egress --cluster-id my-rosa-cluster --probe-from-local
+
   # Run network verification without sending service logs on failure
   osdctl network verify-egress --cluster-id my-rosa-cluster --skip-service-log
 
@@ -186,7 +226,9 @@ func NewCmdValidateEgress() *cobra.Command {
 			if e.Version {
 				printVersion()
 			}
-			e.Run(context.Background())
+			ctx, stop := utils.NotifyInterrupt(cmd.Context(), cmd.ErrOrStderr())
+			defer stop()
+			e.Run(ctx)
 		},
 	}
 
@@ -195,7 +237,7 @@ func NewCmdValidateEgress() *cobra.Command {
 	validateEgressCmd.Flags().StringVar(&e.SecurityGroupId, "security-group", "", "(optional) security group ID override for osd-network-verifier, required if not specifying --cluster-id")
 	validateEgressCmd.Flags().StringVar(&e.CaCert, "cacert", "", "(optional) path to a file containing the additional CA trust bundle. Typically set so that the verifier can use a configured cluster-wide proxy.")
 	validateEgressCmd.Flags().BoolVar(&e.NoTls, "no-tls", false, "(optional) if provided, ignore all ssl certificate validations on client-side.")
-	validateEgressCmd.Flags().StringVar(&e.Region, "region", "", "(optional) AWS region, required for --pod-mode if not passing a --cluster-id")
+	validateEgressCmd.Flags().StringVar(&e.Region, "region", "", "(optional) AWS region overriding the region of the cluster, required for --pod-mode if not passing a --cluster-id")
 	validateEgressCmd.Flags().BoolVar(&e.Debug, "debug", false, "(optional) if provided, enable additional debug-level logging")
 	validateEgressCmd.Flags().BoolVarP(&e.AllSubnets, "all-subnets", "A", false, "(optional) an option for AWS Privatelink clusters to run osd-network-verifier against all subnets listed by ocm.")
 	validateEgressCmd.Flags().StringVar(&e.platformName, "platform", "", "(optional) override for cloud platform/product. E.g., 'aws-classic' (OSD/ROSA Classic), 'aws-hcp' (ROSA HCP), 'aws-hcp-zeroegress', 'aws-govcloud-classic' (AWS GovCloud), or 'gcp-classic'")
@@ -210,6 +252,11 @@ func NewCmdValidateEgress() *cobra.Command {
 	validateEgressCmd.Flags().StringVar(&e.Namespace, "namespace", "openshift-network-diagnostics", "(optional) Kubernetes namespace to run verification pods in")
 	validateEgressCmd.Flags().BoolVar(&e.SkipServiceLog, "skip-service-log", false, "(optional) disable automatic service log sending when verification fails")
 	validateEgressCmd.Flags().StringVar(&e.hiveOcmUrl, "hive-ocm-url", "", "(optional) OCM environment URL for hive operations. Aliases: 'production', 'staging', 'integration'. If not specified, uses the same OCM environment as the target cluster.")
+	validateEgressCmd.Flags().BoolVar(&e.CompareBaseline, "compare-baseline", false, "(optional) compare the blocked egresses with the saved baseline of the cluster and highlight the newly blocked ones")
+	validateEgressCmd.Flags().BoolVar(&e.SaveBaseline, "save-baseline", false, "(optional) save the blocked egresses as the baseline of the cluster, after comparing with --compare-baseline")
+	validateEgressCmd.Flags().StringVar(&e.AllClustersInMC, "all-clusters-in-mc", "", "(optional) verify all the hosted clusters of this management cluster in pod mode, instead of a single cluster")
+	validateEgressCmd.Flags().IntVar(&e.Concurrency, "concurrency", 10, "(optional) maximum number of hosted clusters verified at the same time with --all-clusters-in-mc")
+	validateEgressCmd.Flags().BoolVar(&e.ProbeFromLocal, "probe-from-local", false, "(optional) probe the endpoints from this machine instead of the VPC of the cluster, an approximation to tell global outages from VPC-specific blocks")
 	validateEgressCmd.Flags().StringVar(&e.Reason, "reason", "", "(required for pod mode with --cluster-id) The reason for elevation to perform write operations (usually an OHSS or PD ticket)")
 
 	return validateEgressCmd
@@ -241,6 +288,11 @@ func (e *EgressVerification) Run(ctx context.Context) {
 		log.Fatalf("%s is not a valid CPU architecture", e.CpuArchName)
 	}
 
+	if e.AllClustersInMC != "" {
+		e.runFleet(ctx)
+		return
+	}
+
 	// If no ClusterId is provided, fetch from OCM
 	err = e.fetchCluster(ctx)
 	if err != nil {
@@ -252,6 +304,11 @@ func (e *EgressVerification) Run(ctx context.Context) {
 		log.Fatalf("error getting platform: %s", err)
 	}
 
+	if e.ProbeFromLocal {
+		e.runLocalProbe(ctx, platform)
+		return
+	}
+
 	if !e.PodMode && platform == cloud.AWSHCP {
 		e.log.Info(ctx, "Cluster is HCP - forcing pod mode.")
 		e.PodMode = true
@@ -278,14 +335,33 @@ func (e *EgressVerification) Run(ctx context.Context) {
 		log.Fatal(err)
 	}
 
+	baseline, baselinePath, err := e.loadBaseline()
+	if err != nil {
+		log.Fatal(err)
+	}
+	results := map[string][]string{}
+
 	var failures int
 	for i := range inputs {
+		if ctx.Err() != nil {
+			// The verifier of the previous subnet already cleaned up its temporary resources
+			fmt.Println(utils.PartialResultsBanner)
+			fmt.Printf("Network verification interrupted, verified %d of %d subnet(s)\n", i, len(inputs))
+			os.Exit(1)
+		}
 		if !e.PodMode {
 			e.log.Info(ctx, "running network verifier for subnet  %+v, security group %+v", inputs[i].SubnetID, inputs[i].AWS.SecurityGroupIDs)
 		}
 
 		out := onv.ValidateEgress(verifier, *inputs[i])
 		out.Summary(e.Debug)
+		if e.CompareBaseline || e.SaveBaseline {
+			key := e.baselineKey(inputs[i])
+			results[key] = blockedEgressURLs(out)
+			if baseline != nil && e.CompareBaseline {
+				printEgressDrift(os.Stdout, baseline, key, results[key])
+			}
+		}
 		// Prompt putting the cluster into LS if egresses crucial for monitoring (PagerDuty/DMS) are blocked.
 		// Prompt sending a service log instead for other blocked egresses.
 		if !out.IsSuccessful() && len(out.GetEgressURLFailures()) > 0 {
@@ -310,9 +386,56 @@ func (e *EgressVerification) Run(ctx context.Context) {
 			}
 		}
 		if failures > 0 {
+			e.saveBaseline(baseline, baselinePath, platform, results)
 			os.Exit(1)
 		}
 	}
+	e.saveBaseline(baseline, baselinePath, platform, results)
+}
+
+// loadBaseline returns the saved baseline of the cluster and its path when the baseline flags are set
+func (e *EgressVerification) loadBaseline() (*egressBaseline, string, error) {
+	if !e.CompareBaseline && !e.SaveBaseline {
+		return nil, "", nil
+	}
+	path, err := egressBaselinePath(e.cluster.ID())
+	if err != nil {
+		return nil, "", err
+	}
+	baseline, err := loadEgressBaseline(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if baseline == nil && e.CompareBaseline {
+		fmt.Printf("No egress baseline saved for cluster %s yet, save one with --save-baseline\n", e.cluster.ID())
+	}
+	return baseline, path, nil
+}
+
+// saveBaseline saves the results of the verification over the baseline of the cluster when --save-baseline is set
+func (e *EgressVerification) saveBaseline(baseline *egressBaseline, path string, platform cloud.Platform, results map[string][]string) {
+	if !e.SaveBaseline {
+		return
+	}
+	if baseline == nil {
+		baseline = &egressBaseline{ClusterID: e.cluster.ID()}
+	}
+	baseline.Platform = platform.String()
+	baseline.Updated = time.Now().UTC()
+	baseline.merge(results)
+	if err := baseline.save(path); err != nil {
+		fmt.Printf("Failed to save the egress baseline of cluster %s: %v\n", e.cluster.ID(), err)
+		return
+	}
+	fmt.Printf("Egress baseline of cluster %s saved to %s\n", e.cluster.ID(), path)
+}
+
+// baselineKey identifies the results of the verification of an input in the baseline
+func (e *EgressVerification) baselineKey(input *onv.ValidateEgressInput) string {
+	if e.PodMode || input.SubnetID == "" {
+		return podModeBaselineKey
+	}
+	return input.SubnetID
 }
 
 func generateServiceLog(out *output.Output, clusterId string) servicelog.PostCmdOptions {
@@ -575,14 +698,17 @@ func filtersToString(filters []types.Filter) string {
 // Tags from the cluster are passed to the network-verifier instance
 func (e *EgressVerification) defaultValidateEgressInput(ctx context.Context, platform cloud.Platform) (*onv.ValidateEgressInput, error) {
 	input := &onv.ValidateEgressInput{
-		Ctx:             ctx,
+		// The verifier cleans up the instances, pods and security groups it creates with this context,
+		// so it must not be cancelled on interruption for the temporary resources to be removed.
+		Ctx:             context.WithoutCancel(ctx),
 		CPUArchitecture: e.cpuArch,
 		PlatformType:    platform,
 		Proxy: proxy.ProxyConfig{
 			NoTls: e.NoTls,
 		},
-		Timeout: e.EgressTimeout,
-		Tags:    networkVerifierDefaultTags,
+		Timeout:        e.EgressTimeout,
+		Tags:           networkVerifierDefaultTags,
+		EgressListYaml: e.EgressListYaml,
 	}
 
 	switch strings.ToLower(e.Probe) {
@@ -687,6 +813,22 @@ func (e *EgressVerification) validateInput() error {
 		}
 	}
 
+	if e.AllClustersInMC != "" {
+		if err := e.validateFleetInput(); err != nil {
+			return err
+		}
+	}
+
+	if e.ProbeFromLocal {
+		if err := e.validateLocalProbeInput(); err != nil {
+			return err
+		}
+	}
+
+	if (e.CompareBaseline || e.SaveBaseline) && e.ClusterId == "" {
+		return fmt.Errorf("--compare-baseline and --save-baseline require --cluster-id, the baseline is saved per cluster")
+	}
+
 	// Validate and resolve --hive-ocm-url if provided
 	if e.hiveOcmUrl != "" {
 		resolvedUrl, err := utils.ValidateAndResolveOcmUrl(e.hiveOcmUrl)
@@ -813,14 +955,13 @@ func (e *EgressVerification) setupPodModeVerification(ctx context.Context, platf
 	if platform == cloud.AWSClassic || platform == cloud.AWSHCP || platform == cloud.AWSHCPZeroEgress || platform == cloud.AWSGovCloudClassic {
 		var region string
 
-		// Try to detect region from OCM cluster info first
-		if e.cluster != nil && e.cluster.Region() != nil && e.cluster.Region().ID() != "" {
-			region = e.cluster.Region().ID()
-			e.log.Info(ctx, "Detected AWS region from OCM: %s", region)
-		} else if e.Region != "" {
-			// Use manually specified region
+		// A manually specified region overrides the region of the cluster in OCM
+		if e.Region != "" {
 			region = e.Region
 			e.log.Info(ctx, "Using manually specified AWS region: %s", region)
+		} else if e.cluster != nil && e.cluster.Region() != nil && e.cluster.Region().ID() != "" {
+			region = e.cluster.Region().ID()
+			e.log.Info(ctx, "Detected AWS region from OCM: %s", region)
 		} else {
 			// No region available - require user to specify it
 			return nil, nil, fmt.Errorf("pod mode for AWS platforms requires region information. Please specify --region or provide --cluster-id for automatic detection")
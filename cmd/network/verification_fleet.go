@@ -0,0 +1,382 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+	onv "github.com/openshift/osd-network-verifier/pkg/verifier"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// clusterIDLabel is the label of the namespaces of the hosted clusters on their management cluster
+	clusterIDLabel = "api.openshift.com/id"
+
+	fleetResultPassed  = "PASSED"
+	fleetResultBlocked = "BLOCKED"
+	fleetResultError   = "ERROR"
+	fleetResultSkipped = "SKIPPED"
+)
+
+// errFleetSkipped is the error of the clusters which weren't verified because the command was interrupted
+var errFleetSkipped = errors.New("skipped: interrupted before verifying the cluster")
+
+// fleetEgressResult is the result of the egress verification of one hosted cluster of the management cluster
+type fleetEgressResult struct {
+	ClusterID   string
+	ClusterName string
+	// Blocked are the egress URLs the cluster can't reach
+	Blocked []string
+	Err     error
+}
+
+// egressFleet verifies the egress of all the hosted clusters of a management cluster, concurrently
+type egressFleet struct {
+	mcID        string
+	concurrency int
+
+	out      io.Writer
+	progress *progressBar
+	// listClusters returns the IDs of the hosted clusters of the management cluster, replaced in tests
+	listClusters func(ctx context.Context) ([]string, error)
+	// verify runs the egress verification of a hosted cluster, replaced in tests
+	verify func(ctx context.Context, clusterID string) fleetEgressResult
+}
+
+// runFleet verifies all the hosted clusters of the management cluster of --all-clusters-in-mc
+func (e *EgressVerification) runFleet(ctx context.Context) {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		log.Fatalf("error creating OCM connection: %s", err)
+	}
+	defer ocmClient.Close()
+
+	mc, err := utils.GetClusterAnyStatus(ocmClient, e.AllClustersInMC)
+	if err != nil {
+		log.Fatalf("failed to get management cluster %s from OCM: %s", e.AllClustersInMC, err)
+	}
+	isMC, err := utils.IsManagementCluster(mc.ID())
+	if err != nil {
+		log.Fatalf("failed to verify management cluster %s: %s", mc.ID(), err)
+	}
+	if !isMC {
+		log.Fatalf("cluster %s is not a management cluster", mc.ID())
+	}
+
+	fleet := &egressFleet{
+		mcID:        mc.ID(),
+		concurrency: e.Concurrency,
+		out:         os.Stdout,
+		progress:    newProgressBar(os.Stderr),
+		listClusters: func(ctx context.Context) ([]string, error) {
+			return listHostedClusterIDs(ctx, mc.ID())
+		},
+		verify: func(ctx context.Context, clusterID string) fleetEgressResult {
+			return e.verifyHostedCluster(ctx, ocmClient, clusterID)
+		},
+	}
+	results, err := fleet.run(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, result := range results {
+		if result.Err != nil || len(result.Blocked) > 0 {
+			if !e.SkipServiceLog {
+				fmt.Println("Service logs aren't sent for fleet verifications, verify the failing clusters with --cluster-id to send them.")
+			}
+			os.Exit(1)
+		}
+	}
+}
+
+// run verifies the hosted clusters with at most concurrency verifications running at the same time, and prints
+// the matrix of the results. The results are returned in the order of the clusters.
+func (f *egressFleet) run(ctx context.Context) ([]fleetEgressResult, error) {
+	if f.concurrency < 1 {
+		return nil, fmt.Errorf("--concurrency must be at least 1, got %d", f.concurrency)
+	}
+
+	clusterIDs, err := f.listClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the hosted clusters of management cluster %s: %w", f.mcID, err)
+	}
+	if len(clusterIDs) == 0 {
+		return nil, fmt.Errorf("no hosted clusters found on management cluster %s", f.mcID)
+	}
+
+	results := make([]fleetEgressResult, len(clusterIDs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, f.concurrency)
+	f.progress.start(len(clusterIDs))
+
+	for i, clusterID := range clusterIDs {
+		wg.Add(1)
+		go func(i int, clusterID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				results[i] = fleetEgressResult{ClusterID: clusterID, Err: errFleetSkipped}
+			} else {
+				results[i] = f.verify(ctx, clusterID)
+			}
+			f.progress.increment()
+		}(i, clusterID)
+	}
+	wg.Wait()
+	f.progress.finish()
+
+	if utils.IsInterrupted(ctx) {
+		_, _ = fmt.Fprintln(f.out, utils.PartialResultsBanner)
+	}
+	printFleetMatrix(f.out, results)
+	return results, nil
+}
+
+// verifyHostedCluster runs the pod mode egress verification of a hosted cluster, without exiting or prompting
+// on failures
+func (e *EgressVerification) verifyHostedCluster(ctx context.Context, ocmClient *sdk.Connection, clusterID string) fleetEgressResult {
+	result := fleetEgressResult{ClusterID: clusterID}
+
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, clusterID)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get the cluster from OCM: %w", err)
+		return result
+	}
+	result.ClusterName = cluster.Name()
+
+	// The verifications run concurrently, only the debug logs are printed not to garble the progress bar
+	logger, err := logging.NewGoLoggerBuilder().Debug(e.Debug).Info(e.Debug).Build()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	verification := &EgressVerification{
+		cluster:       cluster,
+		cpuArch:       e.cpuArch,
+		log:           logger,
+		ClusterId:     cluster.ID(),
+		platformName:  e.platformName,
+		Debug:         e.Debug,
+		NoTls:         e.NoTls,
+		EgressTimeout: e.EgressTimeout,
+		Probe:         "curl",
+		PodMode:       true,
+		Namespace:     e.Namespace,
+		Reason:        e.Reason,
+	}
+
+	platform, err := verification.getPlatform()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	verifier, inputs, err := verification.setupPodModeVerification(ctx, platform)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	out := onv.ValidateEgress(verifier, *inputs[0])
+	result.Blocked = blockedEgressURLs(out)
+	if !out.IsSuccessful() && len(result.Blocked) == 0 {
+		_, exceptions, errs := out.Parse()
+		result.Err = errors.Join(append(exceptions, errs...)...)
+		if result.Err == nil {
+			result.Err = errors.New("the verification failed")
+		}
+	}
+	return result
+}
+
+// listHostedClusterIDs returns the IDs of the hosted clusters of the management cluster, from the labels of
+// their namespaces
+func listHostedClusterIDs(ctx context.Context, mcID string) ([]string, error) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	mcClient, err := k8s.New(mcID, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	nsList := &corev1.NamespaceList{}
+	if err := mcClient.List(ctx, nsList, client.HasLabels{clusterIDLabel}); err != nil {
+		return nil, err
+	}
+	return hostedClusterIDs(nsList.Items), nil
+}
+
+// hostedClusterIDs returns the sorted cluster IDs of the namespaces, each hosted cluster having several
+func hostedClusterIDs(namespaces []corev1.Namespace) []string {
+	var ids []string
+	for _, ns := range namespaces {
+		if id := ns.Labels[clusterIDLabel]; id != "" {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// printFleetMatrix prints a row per cluster, with a column per endpoint blocked on at least one of them
+func printFleetMatrix(w io.Writer, results []fleetEgressResult) {
+	var endpoints []string
+	for _, result := range results {
+		endpoints = append(endpoints, result.Blocked...)
+	}
+	slices.Sort(endpoints)
+	endpoints = slices.Compact(endpoints)
+
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow(append([]string{"CLUSTER ID", "NAME", "RESULT"}, endpoints...))
+
+	var passed, blocked, failed, skipped int
+	var errs []string
+	for _, result := range results {
+		status := fleetResultPassed
+		switch {
+		case errors.Is(result.Err, errFleetSkipped):
+			status = fleetResultSkipped
+			skipped++
+		case result.Err != nil:
+			status = fleetResultError
+			failed++
+			errs = append(errs, fmt.Sprintf("%s: %v", result.ClusterID, result.Err))
+		case len(result.Blocked) > 0:
+			status = fleetResultBlocked
+			blocked++
+		default:
+			passed++
+		}
+
+		row := []string{result.ClusterID, result.ClusterName, status}
+		for _, endpoint := range endpoints {
+			switch {
+			case result.Err != nil:
+				row = append(row, "-")
+			case slices.Contains(result.Blocked, endpoint):
+				row = append(row, "blocked")
+			default:
+				row = append(row, "ok")
+			}
+		}
+		p.AddRow(row)
+	}
+	if err := p.Flush(); err != nil {
+		_, _ = fmt.Fprintf(w, "failed to print the results: %v\n", err)
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%d cluster(s): %d passed, %d with blocked egresses, %d failed to verify", len(results), passed, blocked, failed)
+	if skipped > 0 {
+		_, _ = fmt.Fprintf(w, ", %d skipped", skipped)
+	}
+	_, _ = fmt.Fprintln(w)
+	if len(errs) > 0 {
+		_, _ = fmt.Fprintf(w, "\nErrors:\n  %s\n", strings.Join(errs, "\n  "))
+	}
+}
+
+// progressBar renders the progress of the fleet verification on a terminal, and nothing otherwise
+type progressBar struct {
+	mu      sync.Mutex
+	w       io.Writer
+	enabled bool
+	total   int
+	done    int
+}
+
+const progressBarWidth = 40
+
+func newProgressBar(f *os.File) *progressBar {
+	enabled := term.IsTerminal(int(f.Fd())) // #nosec G115 -- file descriptors fit into an int
+	return &progressBar{w: f, enabled: enabled}
+}
+
+func (p *progressBar) start(total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+	p.render()
+}
+
+func (p *progressBar) increment() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.render()
+}
+
+func (p *progressBar) finish() {
+	if p == nil || !p.enabled {
+		return
+	}
+	_, _ = fmt.Fprintln(p.w)
+}
+
+func (p *progressBar) render() {
+	if !p.enabled || p.total == 0 {
+		return
+	}
+	filled := p.done * progressBarWidth / p.total
+	_, _ = fmt.Fprintf(p.w, "\r[%s%s] %d/%d clusters verified", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), p.done, p.total)
+}
+
+// validateFleetInput checks the flags of --all-clusters-in-mc, which verifies every hosted cluster in pod mode
+// with backplane elevation
+func (e *EgressVerification) validateFleetInput() error {
+	if e.Reason == "" {
+		return fmt.Errorf("--all-clusters-in-mc requires --reason flag for elevation (write operations need backplane-cluster-admin). Example: --reason 'PD-12345' or --reason 'OHSS-67890'")
+	}
+	if e.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", e.Concurrency)
+	}
+
+	conflicts := []string{}
+	for flag, set := range map[string]bool{
+		"--cluster-id":       e.ClusterId != "",
+		"--subnet-id":        len(e.SubnetIds) > 0,
+		"--security-group":   e.SecurityGroupId != "",
+		"--cacert":           e.CaCert != "",
+		"--all-subnets":      e.AllSubnets,
+		"--gcp-project-id":   e.GcpProjectID != "",
+		"--vpc":              e.VpcName != "",
+		"--kubeconfig":       e.KubeConfig != "",
+		"--region":           e.Region != "",
+		"--compare-baseline": e.CompareBaseline,
+		"--save-baseline":    e.SaveBaseline,
+	} {
+		if set {
+			conflicts = append(conflicts, flag)
+		}
+	}
+	if len(conflicts) > 0 {
+		slices.Sort(conflicts)
+		return fmt.Errorf("the following flags are incompatible with --all-clusters-in-mc: %s", strings.Join(conflicts, ","))
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEgressFleetRun(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	out := &bytes.Buffer{}
+	fleet := &egressFleet{
+		mcID:        "mc-id",
+		concurrency: 2,
+		out:         out,
+		listClusters: func(ctx context.Context) ([]string, error) {
+			return []string{"cluster-a", "cluster-b", "cluster-c"}, nil
+		},
+		verify: func(ctx context.Context, clusterID string) fleetEgressResult {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			switch clusterID {
+			case "cluster-a":
+				return fleetEgressResult{ClusterID: clusterID, ClusterName: "a"}
+			case "cluster-b":
+				return fleetEgressResult{ClusterID: clusterID, ClusterName: "b", Blocked: []string{"quay.io:443"}}
+			default:
+				return fleetEgressResult{ClusterID: clusterID, Err: errors.New("pods failed to start")}
+			}
+		},
+	}
+
+	results, err := fleet.run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "cluster-a", results[0].ClusterID)
+	assert.Equal(t, "cluster-b", results[1].ClusterID)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+
+	output := out.String()
+	assert.Contains(t, output, "quay.io:443")
+	assert.Regexp(t, `cluster-a\s+a\s+PASSED\s+ok`, output)
+	assert.Regexp(t, `cluster-b\s+b\s+BLOCKED\s+blocked`, output)
+	assert.Regexp(t, `cluster-c\s+ERROR\s+-`, output)
+	assert.Contains(t, output, "3 cluster(s): 1 passed, 1 with blocked egresses, 1 failed to verify")
+	assert.Contains(t, output, "cluster-c: pods failed to start")
+}
+
+func TestEgressFleetRunInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out := &bytes.Buffer{}
+	fleet := &egressFleet{
+		concurrency: 1,
+		out:         out,
+		listClusters: func(ctx context.Context) ([]string, error) {
+			return []string{"cluster-a"}, nil
+		},
+		verify: func(ctx context.Context, clusterID string) fleetEgressResult {
+			t.Fatal("no cluster should be verified once interrupted")
+			return fleetEgressResult{}
+		},
+	}
+
+	results, err := fleet.run(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, errFleetSkipped)
+	assert.Contains(t, out.String(), "1 skipped")
+}
+
+func TestEgressFleetRunNoClusters(t *testing.T) {
+	fleet := &egressFleet{
+		mcID:        "mc-id",
+		concurrency: 1,
+		listClusters: func(ctx context.Context) ([]string, error) {
+			return nil, nil
+		},
+	}
+
+	_, err := fleet.run(context.Background())
+	assert.EqualError(t, err, "no hosted clusters found on management cluster mc-id")
+}
+
+func TestHostedClusterIDs(t *testing.T) {
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ocm-production-b", Labels: map[string]string{clusterIDLabel: "b"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ocm-production-a", Labels: map[string]string{clusterIDLabel: "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ocm-production-a-name", Labels: map[string]string{clusterIDLabel: "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "hypershift"}},
+	}
+
+	assert.Equal(t, []string{"a", "b"}, hostedClusterIDs(namespaces))
+}
+
+func TestValidateFleetInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		e         *EgressVerification
+		expectErr string
+	}{
+		{name: "valid", e: &EgressVerification{AllClustersInMC: "mc", Reason: "OHSS-1", Concurrency: 5}},
+		{name: "missing reason", e: &EgressVerification{AllClustersInMC: "mc", Concurrency: 5}, expectErr: "requires --reason"},
+		{name: "invalid concurrency", e: &EgressVerification{AllClustersInMC: "mc", Reason: "OHSS-1"}, expectErr: "--concurrency must be at least 1"},
+		{
+			name:      "conflicting flags",
+			e:         &EgressVerification{AllClustersInMC: "mc", Reason: "OHSS-1", Concurrency: 5, ClusterId: "c", SubnetIds: []string{"subnet-a"}},
+			expectErr: "incompatible with --all-clusters-in-mc: --cluster-id,--subnet-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.e.validateFleetInput()
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
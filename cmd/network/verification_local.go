@@ -0,0 +1,232 @@
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osd-network-verifier/pkg/data/cloud"
+	"github.com/openshift/osd-network-verifier/pkg/data/egress_lists"
+	"github.com/openshift/osdctl/pkg/printer"
+)
+
+const (
+	// localProbeConcurrency is the number of endpoints probed at the same time with --probe-from-local
+	localProbeConcurrency = 10
+
+	localProbeBanner = `APPROXIMATION: the endpoints are probed from this machine, not from the VPC of the cluster.
+The firewall, proxy and DNS of the cluster aren't involved: an endpoint blocked here is likely down globally or
+blocked by the local network, while an endpoint reachable here but blocked for the cluster points to a block
+specific to its VPC. Run the verification without --probe-from-local for the actual results of the cluster.`
+)
+
+// localEgressEndpoint is an endpoint of the egress list, e.g. https://quay.io:443
+type localEgressEndpoint struct {
+	URL string
+	// TLSDisabled is set for the endpoints whose certificate isn't verified by the verifier either
+	TLSDisabled bool
+}
+
+// localProbeResult is the result of probing an endpoint from the local machine
+type localProbeResult struct {
+	localEgressEndpoint
+	Err error
+}
+
+// localProber probes the egress endpoints from the machine running osdctl: a TCP connection to every endpoint, and a
+// TLS handshake for the HTTPS ones
+type localProber struct {
+	timeout     time.Duration
+	concurrency int
+	// rootCAs verify the certificates of the HTTPS endpoints, the system ones when nil
+	rootCAs *x509.CertPool
+	// insecure skips the verification of all the certificates, like --no-tls
+	insecure bool
+}
+
+// validateLocalProbeInput checks the flags of --probe-from-local, which doesn't run the verifier in the cluster's VPC
+func (e *EgressVerification) validateLocalProbeInput() error {
+	if e.ClusterId == "" && e.platformName == "" {
+		return fmt.Errorf("--probe-from-local requires either --cluster-id or --platform to determine the endpoints to probe")
+	}
+
+	conflicts := []string{}
+	for flag, set := range map[string]bool{
+		"--subnet-id":          len(e.SubnetIds) > 0,
+		"--security-group":     e.SecurityGroupId != "",
+		"--all-subnets":        e.AllSubnets,
+		"--pod-mode":           e.PodMode,
+		"--kubeconfig":         e.KubeConfig != "",
+		"--all-clusters-in-mc": e.AllClustersInMC != "",
+		"--compare-baseline":   e.CompareBaseline,
+		"--save-baseline":      e.SaveBaseline,
+	} {
+		if set {
+			conflicts = append(conflicts, flag)
+		}
+	}
+	if len(conflicts) > 0 {
+		slices.Sort(conflicts)
+		return fmt.Errorf("the following flags are incompatible with --probe-from-local: %s", strings.Join(conflicts, ","))
+	}
+	return nil
+}
+
+// runLocalProbe probes the egress endpoints of the platform from the local machine, exiting non-zero when some of
+// them can't be reached. No service log is sent, the results only approximate the ones of the cluster.
+func (e *EgressVerification) runLocalProbe(ctx context.Context, platform cloud.Platform) {
+	region := e.Region
+	if region == "" && e.cluster != nil {
+		region = e.cluster.Region().ID()
+	}
+	endpoints, err := localEgressEndpoints(ctx, e, platform, region)
+	if err != nil {
+		log.Fatalf("failed to get the egress endpoints of platform %s: %s", platform, err)
+	}
+
+	prober := &localProber{timeout: e.EgressTimeout, concurrency: localProbeConcurrency, insecure: e.NoTls}
+	if e.CaCert != "" {
+		if prober.rootCAs, err = loadRootCAs(e.CaCert); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	fmt.Println(localProbeBanner)
+	fmt.Println()
+	e.log.Info(ctx, "Probing %d endpoint(s) of platform %s from this machine.", len(endpoints), platform)
+	results := prober.probe(ctx, endpoints)
+	if blocked := printLocalProbeResults(os.Stdout, results); blocked > 0 {
+		os.Exit(1)
+	}
+}
+
+// localEgressEndpoints returns the endpoints of the egress list of the platform, or of EgressListYaml when set, the
+// same the verifier probes
+func localEgressEndpoints(ctx context.Context, e *EgressVerification, platform cloud.Platform, region string) ([]localEgressEndpoint, error) {
+	generator := egress_lists.NewGenerator(platform, map[string]string{"AWS_REGION": region}, e.log)
+	urls, tlsDisabledURLs, err := generator.GenerateEgressLists(ctx, e.EgressListYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []localEgressEndpoint
+	for _, u := range strings.Fields(urls) {
+		endpoints = append(endpoints, localEgressEndpoint{URL: u})
+	}
+	for _, u := range strings.Fields(tlsDisabledURLs) {
+		endpoints = append(endpoints, localEgressEndpoint{URL: u, TLSDisabled: true})
+	}
+	return endpoints, nil
+}
+
+func loadRootCAs(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", path)
+	}
+	return pool, nil
+}
+
+// probe probes the endpoints concurrently, the results are in the order of the endpoints
+func (p *localProber) probe(ctx context.Context, endpoints []localEgressEndpoint) []localProbeResult {
+	results := make([]localProbeResult, len(endpoints))
+	sem := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = localProbeResult{localEgressEndpoint: endpoint, Err: p.probeEndpoint(ctx, endpoint)}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// probeEndpoint connects to the endpoint, and completes a TLS handshake for HTTPS endpoints
+func (p *localProber) probeEndpoint(ctx context.Context, endpoint localEgressEndpoint) error {
+	u, err := url.Parse(endpoint.URL)
+	if err != nil {
+		return err
+	}
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if u.Scheme != "https" {
+		return nil
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         u.Hostname(),
+		RootCAs:            p.rootCAs,
+		InsecureSkipVerify: p.insecure || endpoint.TLSDisabled, // #nosec G402 -- like --no-tls and tlsDisabled endpoints of the verifier
+		MinVersion:         tls.VersionTLS12,
+	})
+	return tlsConn.HandshakeContext(ctx)
+}
+
+// printLocalProbeResults prints the results and returns the number of endpoints which couldn't be reached
+func printLocalProbeResults(w io.Writer, results []localProbeResult) int {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"ENDPOINT", "RESULT"})
+	blocked := 0
+	for _, r := range results {
+		result := "reachable"
+		if r.Err != nil {
+			blocked++
+			result = "blocked: " + describeProbeError(r.Err)
+		}
+		p.AddRow([]string{r.URL, result})
+	}
+	_ = p.Flush()
+
+	fmt.Fprintf(w, "\n%d of %d endpoint(s) reachable from this machine\n", len(results)-blocked, len(results))
+	if blocked > 0 {
+		fmt.Fprintln(w, "Endpoints blocked from this machine too are likely unavailable globally, or blocked by the local network")
+	}
+	return blocked
+}
+
+// describeProbeError shortens the errors of the probes to their cause
+func describeProbeError(err error) string {
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &dnsErr):
+		return "DNS resolution failed"
+	case errors.Is(err, context.DeadlineExceeded) || os.IsTimeout(err):
+		return "timeout"
+	case errors.As(err, &certErr):
+		return "certificate verification failed"
+	}
+	return err.Error()
+}
@@ -0,0 +1,98 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-online/ocm-sdk-go/logging"
+	"github.com/openshift/osd-network-verifier/pkg/data/cloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLocalProbeInput(t *testing.T) {
+	assert.EqualError(t, (&EgressVerification{ProbeFromLocal: true}).validateLocalProbeInput(),
+		"--probe-from-local requires either --cluster-id or --platform to determine the endpoints to probe")
+	assert.NoError(t, (&EgressVerification{ProbeFromLocal: true, platformName: "aws-classic", Region: "us-east-1"}).validateLocalProbeInput())
+	assert.EqualError(t, (&EgressVerification{ProbeFromLocal: true, ClusterId: "abc", PodMode: true, SubnetIds: []string{"subnet-1"}}).validateLocalProbeInput(),
+		"the following flags are incompatible with --probe-from-local: --pod-mode,--subnet-id")
+}
+
+func TestLocalEgressEndpoints(t *testing.T) {
+	logger, err := logging.NewGoLoggerBuilder().Build()
+	require.NoError(t, err)
+	e := &EgressVerification{log: logger, EgressListYaml: `endpoints:
+  - host: quay.io
+    ports:
+      - 443
+  - host: sts.${AWS_REGION}.amazonaws.com
+    ports:
+      - 80
+      - 443
+  - host: insecure.example.com
+    tlsDisabled: true
+    ports:
+      - 443
+`}
+
+	endpoints, err := localEgressEndpoints(context.Background(), e, cloud.AWSClassic, "us-east-1")
+	require.NoError(t, err)
+	assert.Equal(t, []localEgressEndpoint{
+		{URL: "https://quay.io:443"},
+		{URL: "http://sts.us-east-1.amazonaws.com:80"},
+		{URL: "https://sts.us-east-1.amazonaws.com:443"},
+		{URL: "https://insecure.example.com:443", TLSDisabled: true},
+	}, endpoints)
+}
+
+func TestLocalProberProbe(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	// A listener which is closed right away, so that connections to it are refused
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddr := closed.Addr().String()
+	require.NoError(t, closed.Close())
+
+	endpoints := []localEgressEndpoint{
+		{URL: "https://" + host},
+		{URL: "https://" + host, TLSDisabled: true},
+		{URL: "telnet://" + host},
+		{URL: "https://" + closedAddr},
+	}
+
+	prober := &localProber{timeout: 5 * time.Second, concurrency: 2}
+	results := prober.probe(context.Background(), endpoints)
+	require.Len(t, results, 4)
+	assert.Equal(t, "certificate verification failed", describeProbeError(results[0].Err))
+	assert.NoError(t, results[1].Err)
+	assert.NoError(t, results[2].Err)
+	assert.Error(t, results[3].Err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	prober.rootCAs = pool
+	results = prober.probe(context.Background(), endpoints[:1])
+	assert.NoError(t, results[0].Err)
+
+	out := &bytes.Buffer{}
+	blocked := printLocalProbeResults(out, []localProbeResult{
+		{localEgressEndpoint: endpoints[0]},
+		{localEgressEndpoint: endpoints[3], Err: context.DeadlineExceeded},
+		{localEgressEndpoint: localEgressEndpoint{URL: "https://nowhere.invalid:443"}, Err: &net.DNSError{Err: "no such host", Name: "nowhere.invalid"}},
+	})
+	assert.Equal(t, 2, blocked)
+	assert.Regexp(t, `https://127.0.0.1:\d+\s+reachable`, out.String())
+	assert.Contains(t, out.String(), "blocked: timeout")
+	assert.Contains(t, out.String(), "blocked: DNS resolution failed")
+	assert.Contains(t, out.String(), "1 of 3 endpoint(s) reachable from this machine")
+}
@@ -30,6 +30,7 @@ func NewCmdOrg() *cobra.Command {
 	orgCmd.AddCommand(customersCmd)
 	orgCmd.AddCommand(awsAccountsCmd)
 	orgCmd.AddCommand(contextCmd)
+	orgCmd.AddCommand(costReportCmd)
 
 	return orgCmd
 }
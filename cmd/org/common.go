@@ -97,28 +97,9 @@ func PrintJson(data interface{}) {
 	dump.Pretty(os.Stdout, marshalledStruct)
 }
 
+// SearchAllSubscriptionsByOrg returns all the subscriptions of an organization, optionally filtered by status and
+// managed clusters
 func SearchAllSubscriptionsByOrg(orgID string, status string, managedOnly bool) ([]*accountsv1.Subscription, error) {
-	var clusterSubscriptions []*accountsv1.Subscription
-	requestPageSize := 100
-	morePages := true
-	for page := 1; morePages; page++ {
-		clustersData, err := getSubscriptions(orgID, status, managedOnly, page, requestPageSize)
-		if err != nil {
-			return nil, fmt.Errorf("encountered an error fetching subscriptions for page %v: %w", page, err)
-		}
-
-		clustersDataItems := clustersData.Items().Slice()
-		clusterSubscriptions = append(clusterSubscriptions, clustersDataItems...)
-
-		if clustersData.Size() < requestPageSize {
-			morePages = false
-		}
-	}
-
-	return clusterSubscriptions, nil
-}
-
-func getSubscriptions(orgID string, status string, managedOnly bool, page int, size int) (*accountsv1.SubscriptionsListResponse, error) {
 	// Create OCM client to talk
 	ocmClient, err := utils.CreateConnection()
 	if err != nil {
@@ -130,13 +111,18 @@ func getSubscriptions(orgID string, status string, managedOnly bool, page int, s
 		}
 	}()
 
-	// Now get the matching orgs
-	response, err := createGetSubscriptionsRequest(ocmClient, orgID, status, managedOnly, page, size).Send()
+	clusterSubscriptions, err := utils.NewOCMPaginator(func(page, size int) ([]*accountsv1.Subscription, error) {
+		response, err := createGetSubscriptionsRequest(ocmClient, orgID, status, managedOnly, page, size).Send()
+		if err != nil {
+			return nil, err
+		}
+		return response.Items().Slice(), nil
+	}).All()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get clusters: %w", err)
+		return nil, fmt.Errorf("encountered an error fetching subscriptions: %w", err)
 	}
 
-	return response, nil
+	return clusterSubscriptions, nil
 }
 
 func createGetSubscriptionsRequest(ocmClient *sdk.Connection, orgID string, status string, managedOnly bool, page int, size int) *accountsv1.SubscriptionsListRequest {
@@ -0,0 +1,303 @@
+package org
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	accountsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+const (
+	costReportMonthFormat = "2006-01"
+	costReportMetric      = "NetUnblendedCost"
+	statusDeprovisioned   = "Deprovisioned"
+)
+
+var costReportCmd = newCmdCostReport()
+
+type costReportOptions struct {
+	orgID      string
+	month      string
+	output     string
+	awsProfile string
+
+	out                 io.Writer
+	now                 func() time.Time
+	searchSubscriptions func(orgID string, status string, managedOnly bool) ([]*accountsv1.Subscription, error)
+	newAWSClient        func(profile string) (awsprovider.Client, error)
+}
+
+// costReport is the monthly summary of the clusters of an organization
+type costReport struct {
+	OrganizationID string              `json:"organizationId"`
+	Month          string              `json:"month"`
+	ClusterCount   int                 `json:"clusterCount"`
+	Versions       map[string]int      `json:"versions"`
+	TotalCostUSD   decimal.Decimal     `json:"totalCostUSD"`
+	Clusters       []costReportCluster `json:"clusters"`
+}
+
+// costReportCluster is a cluster of the cost report. The cost is the one of its AWS account, which
+// is shared by all the clusters of the account.
+type costReportCluster struct {
+	ClusterID      string           `json:"clusterId"`
+	DisplayName    string           `json:"displayName"`
+	Status         string           `json:"status"`
+	Version        string           `json:"version"`
+	CloudProvider  string           `json:"cloudProvider"`
+	AWSAccountID   string           `json:"awsAccountId,omitempty"`
+	AccountCostUSD *decimal.Decimal `json:"accountCostUSD,omitempty"`
+	SharedAccount  bool             `json:"sharedAccount,omitempty"`
+}
+
+func newCmdCostReport() *cobra.Command {
+	opts := &costReportOptions{
+		out:                 os.Stdout,
+		now:                 time.Now,
+		searchSubscriptions: SearchAllSubscriptionsByOrg,
+		newAWSClient:        initAWSClient,
+	}
+
+	costReportCmd := &cobra.Command{
+		Use:   "cost-report org-id",
+		Short: "Get a monthly report of the clusters and AWS cost of an organization",
+		Long: `Get a monthly report of the clusters of an organization for capacity and billing reviews.
+
+The report holds the clusters which existed during the month, the number of clusters per OpenShift
+version and the AWS cost of each cluster's account during the month, as reported by Cost Explorer.
+The AWS profile must have access to Cost Explorer in the payer account of the clusters' accounts.
+
+Clusters sharing an AWS account are flagged, as the account cost can't be split between them. The
+total cost counts every account once.`,
+		Example: `  # Get the report of the previous month as CSV
+  osdctl org cost-report 123456789AbcDEfGHiJklMnopQR -p my-payer-profile
+
+  # Get the report of May 2025 as JSON
+  osdctl org cost-report 123456789AbcDEfGHiJklMnopQR -p my-payer-profile --month 2025-05 -o json`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.orgID = args[0]
+			return opts.run()
+		},
+	}
+
+	costReportCmd.Flags().StringVar(&opts.month, "month", "", "Month of the report, in the YYYY-MM format (defaults to the previous month)")
+	costReportCmd.Flags().StringVarP(&opts.output, "output", "o", "csv", "Output format: csv or json")
+	costReportCmd.Flags().StringVarP(&opts.awsProfile, "aws-profile", "p", "", "specify AWS profile")
+
+	return costReportCmd
+}
+
+// monthRange returns the first day of the report month and of the following month
+func (o *costReportOptions) monthRange() (time.Time, time.Time, error) {
+	if o.month == "" {
+		now := o.now().UTC()
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+		return start, start.AddDate(0, 1, 0), nil
+	}
+	start, err := time.Parse(costReportMonthFormat, o.month)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --month %q, expected the YYYY-MM format", o.month)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+func (o *costReportOptions) run() error {
+	if o.output != "csv" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected csv or json", o.output)
+	}
+	start, end, err := o.monthRange()
+	if err != nil {
+		return err
+	}
+
+	subscriptions, err := o.searchSubscriptions(o.orgID, "", true)
+	if err != nil {
+		return err
+	}
+
+	report := newCostReport(o.orgID, start, end, subscriptions)
+	accounts := report.accountIDs()
+	if len(accounts) > 0 {
+		awsClient, err := o.newAWSClient(o.awsProfile)
+		if err != nil {
+			return fmt.Errorf("could not create AWS client: %w", err)
+		}
+		costs, err := getAccountCosts(awsClient, accounts, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to get the cost of the AWS accounts: %w", err)
+		}
+		report.setCosts(costs)
+	}
+
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return report.writeCSV(o.out)
+}
+
+// newCostReport builds the report of the subscriptions of the clusters which existed during the month
+func newCostReport(orgID string, start, end time.Time, subscriptions []*accountsv1.Subscription) *costReport {
+	report := &costReport{
+		OrganizationID: orgID,
+		Month:          start.Format(costReportMonthFormat),
+		Versions:       map[string]int{},
+		Clusters:       []costReportCluster{},
+	}
+
+	accountClusters := map[string]int{}
+	for _, subscription := range subscriptions {
+		if subscription.ClusterID() == "" || !subscription.CreatedAt().Before(end) {
+			continue
+		}
+		if subscription.Status() == statusDeprovisioned && subscription.UpdatedAt().Before(start) {
+			continue
+		}
+
+		cluster := costReportCluster{
+			ClusterID:     subscription.ClusterID(),
+			DisplayName:   subscription.DisplayName(),
+			Status:        subscription.Status(),
+			CloudProvider: subscription.CloudProviderID(),
+		}
+		if metrics := subscription.Metrics(); len(metrics) > 0 {
+			cluster.Version = metrics[0].OpenshiftVersion()
+		}
+		if cluster.CloudProvider == "aws" {
+			cluster.AWSAccountID = subscription.CloudAccountID()
+		}
+		if cluster.AWSAccountID != "" {
+			accountClusters[cluster.AWSAccountID]++
+		}
+
+		report.Versions[cluster.Version]++
+		report.Clusters = append(report.Clusters, cluster)
+	}
+
+	for i := range report.Clusters {
+		report.Clusters[i].SharedAccount = accountClusters[report.Clusters[i].AWSAccountID] > 1
+	}
+	slices.SortFunc(report.Clusters, func(a, b costReportCluster) int {
+		if a.DisplayName != b.DisplayName {
+			if a.DisplayName < b.DisplayName {
+				return -1
+			}
+			return 1
+		}
+		if a.ClusterID < b.ClusterID {
+			return -1
+		}
+		return 1
+	})
+	report.ClusterCount = len(report.Clusters)
+	return report
+}
+
+// accountIDs returns the sorted AWS accounts of the clusters of the report
+func (r *costReport) accountIDs() []string {
+	accounts := map[string]bool{}
+	for _, cluster := range r.Clusters {
+		if cluster.AWSAccountID != "" {
+			accounts[cluster.AWSAccountID] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(accounts))
+}
+
+// setCosts sets the cost of the accounts on their clusters and sums the cost of every account once
+func (r *costReport) setCosts(costs map[string]decimal.Decimal) {
+	r.TotalCostUSD = decimal.Zero
+	for _, cost := range costs {
+		r.TotalCostUSD = r.TotalCostUSD.Add(cost)
+	}
+	for i, cluster := range r.Clusters {
+		if cost, ok := costs[cluster.AWSAccountID]; ok {
+			r.Clusters[i].AccountCostUSD = &cost
+		}
+	}
+}
+
+// getAccountCosts returns the cost of each account during the month, accounts without cost are omitted
+func getAccountCosts(awsClient awsprovider.Client, accounts []string, start, end time.Time) (map[string]decimal.Decimal, error) {
+	startDate, endDate := start.Format(time.DateOnly), end.Format(time.DateOnly)
+	costs := map[string]decimal.Decimal{}
+
+	var nextPageToken *string
+	for {
+		result, err := awsClient.GetCostAndUsage(&costexplorer.GetCostAndUsageInput{
+			Filter: &costExplorerTypes.Expression{
+				Dimensions: &costExplorerTypes.DimensionValues{
+					Key:    costExplorerTypes.DimensionLinkedAccount,
+					Values: accounts,
+				},
+			},
+			TimePeriod:    &costExplorerTypes.DateInterval{Start: &startDate, End: &endDate},
+			Granularity:   costExplorerTypes.GranularityMonthly,
+			Metrics:       []string{costReportMetric},
+			GroupBy:       []costExplorerTypes.GroupDefinition{{Type: costExplorerTypes.GroupDefinitionTypeDimension, Key: aws.String(string(costExplorerTypes.DimensionLinkedAccount))}},
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, period := range result.ResultsByTime {
+			for _, group := range period.Groups {
+				metric, ok := group.Metrics[costReportMetric]
+				if len(group.Keys) == 0 || !ok || metric.Amount == nil {
+					continue
+				}
+				amount, err := decimal.NewFromString(*metric.Amount)
+				if err != nil {
+					return nil, err
+				}
+				costs[group.Keys[0]] = costs[group.Keys[0]].Add(amount)
+			}
+		}
+
+		if result.NextPageToken == nil {
+			return costs, nil
+		}
+		nextPageToken = result.NextPageToken
+	}
+}
+
+func (r *costReport) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"month", "cluster_id", "display_name", "status", "version", "cloud_provider", "aws_account_id", "account_cost_usd", "shared_account"}); err != nil {
+		return err
+	}
+	for _, cluster := range r.Clusters {
+		cost := ""
+		if cluster.AccountCostUSD != nil {
+			cost = cluster.AccountCostUSD.StringFixed(2)
+		}
+		if err := writer.Write([]string{
+			r.Month, cluster.ClusterID, cluster.DisplayName, cluster.Status, cluster.Version,
+			cluster.CloudProvider, cluster.AWSAccountID, cost, strconv.FormatBool(cluster.SharedAccount),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := writer.Write([]string{r.Month, "TOTAL", strconv.Itoa(r.ClusterCount) + " clusters", "", "", "", "", r.TotalCostUSD.StringFixed(2), ""}); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,180 @@
+package org
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	accountsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func buildCostReportSubscription(t *testing.T, clusterID, name, status, provider, account, version string, createdAt, updatedAt time.Time) *accountsv1.Subscription {
+	subscription, err := accountsv1.NewSubscription().
+		ClusterID(clusterID).
+		DisplayName(name).
+		Status(status).
+		CloudProviderID(provider).
+		CloudAccountID(account).
+		CreatedAt(createdAt).
+		UpdatedAt(updatedAt).
+		Metrics(accountsv1.NewSubscriptionMetrics().OpenshiftVersion(version)).
+		Build()
+	require.NoError(t, err)
+	return subscription
+}
+
+func costGroup(account, amount string) costExplorerTypes.Group {
+	return costExplorerTypes.Group{
+		Keys:    []string{account},
+		Metrics: map[string]costExplorerTypes.MetricValue{costReportMetric: {Amount: aws.String(amount), Unit: aws.String("USD")}},
+	}
+}
+
+func TestCostReportMonthRange(t *testing.T) {
+	now := func() time.Time { return time.Date(2025, time.January, 15, 10, 0, 0, 0, time.UTC) }
+
+	start, end, err := (&costReportOptions{now: now}).monthRange()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = (&costReportOptions{now: now, month: "2024-02"}).monthRange()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = (&costReportOptions{now: now, month: "02-2024"}).monthRange()
+	assert.ErrorContains(t, err, "invalid --month")
+}
+
+func TestNewCostReport(t *testing.T) {
+	start := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	subscriptions := []*accountsv1.Subscription{
+		buildCostReportSubscription(t, "c1", "alpha", StatusActive, "aws", "111111111111", "4.16.1", start.AddDate(0, -2, 0), start),
+		buildCostReportSubscription(t, "c2", "beta", StatusActive, "aws", "111111111111", "4.16.1", start.AddDate(0, 0, 10), start.AddDate(0, 0, 10)),
+		buildCostReportSubscription(t, "c3", "gamma", statusDeprovisioned, "aws", "222222222222", "4.15.3", start.AddDate(0, -3, 0), start.AddDate(0, 0, 5)),
+		buildCostReportSubscription(t, "c4", "delta", "Active", "gcp", "my-project", "4.17.0", start.AddDate(0, -1, 0), start),
+		// Deprovisioned before the month
+		buildCostReportSubscription(t, "c5", "old", statusDeprovisioned, "aws", "333333333333", "4.14.0", start.AddDate(-1, 0, 0), start.AddDate(0, -1, 0)),
+		// Created after the month
+		buildCostReportSubscription(t, "c6", "new", StatusActive, "aws", "444444444444", "4.18.0", end.AddDate(0, 0, 1), end.AddDate(0, 0, 1)),
+	}
+
+	report := newCostReport("org-id", start, end, subscriptions)
+
+	assert.Equal(t, "2025-05", report.Month)
+	assert.Equal(t, 4, report.ClusterCount)
+	assert.Equal(t, map[string]int{"4.16.1": 2, "4.15.3": 1, "4.17.0": 1}, report.Versions)
+	assert.Equal(t, []string{"111111111111", "222222222222"}, report.accountIDs())
+
+	names := []string{}
+	for _, cluster := range report.Clusters {
+		names = append(names, cluster.DisplayName)
+	}
+	assert.Equal(t, []string{"alpha", "beta", "delta", "gamma"}, names)
+	assert.True(t, report.Clusters[0].SharedAccount)
+	assert.True(t, report.Clusters[1].SharedAccount)
+	assert.Empty(t, report.Clusters[2].AWSAccountID, "GCP projects shouldn't be reported as AWS accounts")
+	assert.False(t, report.Clusters[3].SharedAccount)
+}
+
+func TestGetAccountCosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAWS := mock.NewMockClient(ctrl)
+	start := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	gomock.InOrder(
+		mockAWS.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+			assert.Equal(t, "2025-05-01", *input.TimePeriod.Start)
+			assert.Equal(t, "2025-06-01", *input.TimePeriod.End)
+			assert.Equal(t, []string{"111111111111", "222222222222"}, input.Filter.Dimensions.Values)
+			assert.Nil(t, input.NextPageToken)
+			return &costexplorer.GetCostAndUsageOutput{
+				ResultsByTime: []costExplorerTypes.ResultByTime{{Groups: []costExplorerTypes.Group{costGroup("111111111111", "100.50")}}},
+				NextPageToken: aws.String("next"),
+			}, nil
+		}),
+		mockAWS.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+			assert.Equal(t, "next", *input.NextPageToken)
+			return &costexplorer.GetCostAndUsageOutput{
+				ResultsByTime: []costExplorerTypes.ResultByTime{{Groups: []costExplorerTypes.Group{costGroup("222222222222", "20.25")}}},
+			}, nil
+		}),
+	)
+
+	costs, err := getAccountCosts(mockAWS, []string{"111111111111", "222222222222"}, start, start.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "100.50", costs["111111111111"].StringFixed(2))
+	assert.Equal(t, "20.25", costs["222222222222"].StringFixed(2))
+}
+
+func TestCostReportRun(t *testing.T) {
+	start := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	subscriptions := []*accountsv1.Subscription{
+		buildCostReportSubscription(t, "c1", "alpha", StatusActive, "aws", "111111111111", "4.16.1", start.AddDate(0, -2, 0), start),
+		buildCostReportSubscription(t, "c2", "beta", StatusActive, "aws", "111111111111", "4.16.1", start.AddDate(0, -2, 0), start),
+		buildCostReportSubscription(t, "c3", "gamma", StatusActive, "aws", "222222222222", "4.15.3", start.AddDate(0, -3, 0), start),
+	}
+
+	newOptions := func(t *testing.T, output string, out *bytes.Buffer) *costReportOptions {
+		ctrl := gomock.NewController(t)
+		mockAWS := mock.NewMockClient(ctrl)
+		mockAWS.EXPECT().GetCostAndUsage(gomock.Any()).Return(&costexplorer.GetCostAndUsageOutput{
+			ResultsByTime: []costExplorerTypes.ResultByTime{{Groups: []costExplorerTypes.Group{
+				costGroup("111111111111", "100"),
+				costGroup("222222222222", "50.5"),
+			}}},
+		}, nil)
+		return &costReportOptions{
+			orgID:  "org-id",
+			month:  "2025-05",
+			output: output,
+			out:    out,
+			searchSubscriptions: func(orgID string, status string, managedOnly bool) ([]*accountsv1.Subscription, error) {
+				assert.Equal(t, "org-id", orgID)
+				return subscriptions, nil
+			},
+			newAWSClient: func(profile string) (awsprovider.Client, error) { return mockAWS, nil },
+		}
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, newOptions(t, "csv", out).run())
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 5)
+		assert.Equal(t, "month,cluster_id,display_name,status,version,cloud_provider,aws_account_id,account_cost_usd,shared_account", lines[0])
+		assert.Equal(t, "2025-05,c1,alpha,Active,4.16.1,aws,111111111111,100.00,true", lines[1])
+		assert.Equal(t, "2025-05,c3,gamma,Active,4.15.3,aws,222222222222,50.50,false", lines[3])
+		assert.Equal(t, "2025-05,TOTAL,3 clusters,,,,,150.50,", lines[4])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, newOptions(t, "json", out).run())
+
+		report := costReport{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		assert.Equal(t, 3, report.ClusterCount)
+		assert.Equal(t, "150.5", report.TotalCostUSD.String())
+		assert.Equal(t, map[string]int{"4.16.1": 2, "4.15.3": 1}, report.Versions)
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		err := (&costReportOptions{output: "yaml"}).run()
+		assert.ErrorContains(t, err, "invalid output format")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -11,9 +12,22 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
+var (
+	testRootOnce sync.Once
+	testRoot     *cobra.Command
+)
+
+// newTestRoot returns the root command shared by the tests walking the command tree, which can only be built once
+// as some commands are package level variables
+func newTestRoot() *cobra.Command {
+	testRootOnce.Do(func() {
+		testRoot = NewCmdRoot(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	})
+	return testRoot
+}
+
 func TestRequiredFlagsDocumentedInExamples(t *testing.T) {
-	streams := genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
-	root := NewCmdRoot(streams)
+	root := newTestRoot()
 
 	var walk func(cmd *cobra.Command)
 	walk = func(cmd *cobra.Command) {
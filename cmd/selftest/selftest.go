@@ -0,0 +1,228 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/clients"
+	dtclient "github.com/openshift/osdctl/pkg/dynatrace"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ClusterIDConfigKey is the config key of the cluster the checks run against when --cluster-id isn't set
+	ClusterIDConfigKey = "selftest_cluster_id"
+
+	defaultCheckTimeout = time.Minute
+
+	resultPass = "PASS"
+	resultFail = "FAIL"
+	resultSkip = "SKIP"
+)
+
+// skipError is returned by the checks which can't run, e.g. because a check they rely on failed
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+// selftestCheck is a read-only check of the setup of osdctl
+type selftestCheck struct {
+	name string
+	// run returns the details of the check on success
+	run func(ctx context.Context) (string, error)
+}
+
+type selftestOptions struct {
+	clusterID string
+	timeout   time.Duration
+
+	out     io.Writer
+	factory clients.Factory
+	// awsDescribe and dynatraceToken are replaced in tests
+	awsDescribe    func(ctx context.Context, cluster *cmv1.Cluster) (string, error)
+	dynatraceToken func() (string, error)
+
+	cluster *cmv1.Cluster
+	client  client.Client
+}
+
+// NewCmdSelftest implements the selftest command, checking osdctl end to end against a stage cluster
+func NewCmdSelftest() *cobra.Command {
+	return newCmdSelftest(clients.NewFactory())
+}
+
+func newCmdSelftest(factory clients.Factory) *cobra.Command {
+	o := &selftestOptions{
+		factory:        factory,
+		awsDescribe:    describeAWS,
+		dynatraceToken: dtclient.GetStorageAccessToken,
+	}
+	selftestCmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Check a build or setup of osdctl end to end against a stage cluster",
+		Long: `Check a build or setup of osdctl end to end against a stage cluster, e.g. after a release or on a new laptop.
+
+A battery of read-only checks is run against the cluster: fetching it from OCM, logging in to it through backplane,
+listing its namespaces, acquiring a Dynatrace token and describing the AWS regions with the credentials of the
+cluster. The checks relying on a failed one are skipped.
+
+The cluster is given with --cluster-id, or with the selftest_cluster_id key of the config file. Designate a stage
+cluster and log in to the stage OCM environment.`,
+		Example: `  # Run the checks against the stage cluster of the config file
+  osdctl selftest
+
+  # Run the checks against another stage cluster
+  osdctl selftest --cluster-id ${CLUSTER_ID}`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			defer o.factory.Close()
+			o.out = cmd.OutOrStdout()
+			if o.clusterID == "" {
+				o.clusterID = viper.GetString(ClusterIDConfigKey)
+			}
+			if o.clusterID == "" {
+				return fmt.Errorf("no cluster to run the checks against, use --cluster-id or set %s in the config file", ClusterIDConfigKey)
+			}
+			return o.run(cmd.Context())
+		},
+	}
+	selftestCmd.Flags().StringVarP(&o.clusterID, "cluster-id", "C", "", "The stage cluster to run the checks against, "+ClusterIDConfigKey+" of the config file by default")
+	selftestCmd.Flags().DurationVar(&o.timeout, "timeout", defaultCheckTimeout, "How long each check can take")
+	return selftestCmd
+}
+
+func (o *selftestOptions) checks() []selftestCheck {
+	return []selftestCheck{
+		{name: "OCM cluster fetch", run: o.checkOCM},
+		{name: "Backplane login", run: o.checkBackplane},
+		{name: "Kubernetes list", run: o.checkKubernetes},
+		{name: "Dynatrace token", run: o.checkDynatrace},
+		{name: "AWS describe", run: o.checkAWS},
+	}
+}
+
+func (o *selftestOptions) run(ctx context.Context) error {
+	checks := o.checks()
+	p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	p.AddRow([]string{"CHECK", "RESULT", "DURATION", "DETAILS"})
+	failed := 0
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, o.timeout)
+		start := time.Now()
+		details, err := check.run(checkCtx)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		cancel()
+
+		result := resultPass
+		var skip *skipError
+		switch {
+		case errors.As(err, &skip):
+			result, details = resultSkip, skip.reason
+		case err != nil:
+			failed++
+			result, details = resultFail, err.Error()
+		}
+		p.AddRow([]string{check.name, result, elapsed.String(), details})
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	fmt.Fprintln(o.out, "\nosdctl is set up correctly")
+	return nil
+}
+
+func (o *selftestOptions) checkOCM(context.Context) (string, error) {
+	user, err := o.factory.Username()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the OCM account: %w", err)
+	}
+	cluster, err := o.factory.Cluster(o.clusterID)
+	if err != nil {
+		return "", err
+	}
+	o.cluster = cluster
+	return fmt.Sprintf("cluster %s (%s) as %s", cluster.Name(), cluster.ID(), user), nil
+}
+
+func (o *selftestOptions) checkBackplane(context.Context) (string, error) {
+	if o.cluster == nil {
+		return "", &skipError{reason: "the cluster wasn't fetched from OCM"}
+	}
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return "", err
+	}
+	c, err := o.factory.KubeClient(o.cluster.ID(), client.Options{Scheme: scheme})
+	if err != nil {
+		return "", err
+	}
+	o.client = c
+	return "logged in to " + o.cluster.API().URL(), nil
+}
+
+func (o *selftestOptions) checkKubernetes(ctx context.Context) (string, error) {
+	if o.client == nil {
+		return "", &skipError{reason: "no backplane login to the cluster"}
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := o.client.List(ctx, namespaces, client.Limit(10)); err != nil {
+		return "", fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	return fmt.Sprintf("listed %d namespaces", len(namespaces.Items)), nil
+}
+
+func (o *selftestOptions) checkDynatrace(context.Context) (string, error) {
+	if _, err := o.dynatraceToken(); err != nil {
+		return "", fmt.Errorf("failed to acquire a Dynatrace token: %w", err)
+	}
+	return "token acquired", nil
+}
+
+func (o *selftestOptions) checkAWS(ctx context.Context) (string, error) {
+	if o.cluster == nil {
+		return "", &skipError{reason: "the cluster wasn't fetched from OCM"}
+	}
+	if o.cluster.CloudProvider().ID() != "aws" {
+		return "", &skipError{reason: "the cluster isn't on AWS"}
+	}
+	return o.awsDescribe(ctx, o.cluster)
+}
+
+// describeAWS describes the regions with the AWS credentials of the cluster from backplane
+func describeAWS(ctx context.Context, cluster *cmv1.Cluster) (string, error) {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return "", err
+	}
+	defer connection.Close()
+	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the AWS credentials of the cluster: %w", err)
+	}
+	regions, err := ec2.NewFromConfig(cfg).DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("described %d regions", len(regions.Regions)), nil
+}
@@ -0,0 +1,94 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/clients"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newOptions(t *testing.T, provider string) (*selftestOptions, *clients.FakeFactory, *bytes.Buffer) {
+	cluster, err := cmv1.NewCluster().ID("cluster-id").Name("stage-cluster").
+		CloudProvider(cmv1.NewCloudProvider().ID(provider)).
+		API(cmv1.NewClusterAPI().URL("https://api.stage-cluster.example.com:6443")).Build()
+	require.NoError(t, err)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	factory := &clients.FakeFactory{
+		Clusters: []*cmv1.Cluster{cluster},
+		User:     "sre",
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-monitoring"}},
+		).Build(),
+	}
+	out := &bytes.Buffer{}
+	return &selftestOptions{
+		clusterID: "stage-cluster",
+		timeout:   time.Second,
+		out:       out,
+		factory:   factory,
+		awsDescribe: func(_ context.Context, cluster *cmv1.Cluster) (string, error) {
+			assert.Equal(t, "cluster-id", cluster.ID())
+			return "described 17 regions", nil
+		},
+		dynatraceToken: func() (string, error) { return "token", nil },
+	}, factory, out
+}
+
+func TestSelftestRun(t *testing.T) {
+	t.Run("all checks pass", func(t *testing.T) {
+		o, factory, out := newOptions(t, "aws")
+		require.NoError(t, o.run(context.Background()))
+
+		assert.Equal(t, []string{"cluster-id"}, factory.KubeClusterIDs)
+		assert.Regexp(t, `OCM cluster fetch\s+PASS\s+\S+\s+cluster stage-cluster \(cluster-id\) as sre`, out.String())
+		assert.Regexp(t, `Backplane login\s+PASS\s+\S+\s+logged in to https://api.stage-cluster.example.com:6443`, out.String())
+		assert.Regexp(t, `Kubernetes list\s+PASS\s+\S+\s+listed 2 namespaces`, out.String())
+		assert.Regexp(t, `Dynatrace token\s+PASS`, out.String())
+		assert.Regexp(t, `AWS describe\s+PASS\s+\S+\s+described 17 regions`, out.String())
+		assert.Contains(t, out.String(), "osdctl is set up correctly")
+	})
+
+	t.Run("checks relying on a failed one are skipped", func(t *testing.T) {
+		o, _, out := newOptions(t, "aws")
+		o.clusterID = "unknown"
+		o.dynatraceToken = func() (string, error) { return "", errors.New("invalid client secret") }
+
+		assert.EqualError(t, o.run(context.Background()), "2 of 5 checks failed")
+		assert.Regexp(t, `OCM cluster fetch\s+FAIL`, out.String())
+		assert.Regexp(t, `Backplane login\s+SKIP\s+\S+\s+the cluster wasn't fetched from OCM`, out.String())
+		assert.Regexp(t, `Kubernetes list\s+SKIP\s+\S+\s+no backplane login to the cluster`, out.String())
+		assert.Regexp(t, `Dynatrace token\s+FAIL\s+\S+\s+failed to acquire a Dynatrace token: invalid client secret`, out.String())
+		assert.Regexp(t, `AWS describe\s+SKIP`, out.String())
+	})
+
+	t.Run("AWS is skipped for GCP clusters", func(t *testing.T) {
+		o, _, out := newOptions(t, "gcp")
+		o.awsDescribe = func(context.Context, *cmv1.Cluster) (string, error) {
+			t.Fatal("AWS shouldn't be described")
+			return "", nil
+		}
+		require.NoError(t, o.run(context.Background()))
+		assert.Regexp(t, `AWS describe\s+SKIP\s+\S+\s+the cluster isn't on AWS`, out.String())
+	})
+}
+
+func TestSelftestClusterRequired(t *testing.T) {
+	cmd := newCmdSelftest(&clients.FakeFactory{})
+	cmd.SetArgs([]string{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	assert.EqualError(t, cmd.Execute(), "no cluster to run the checks against, use --cluster-id or set selftest_cluster_id in the config file")
+}
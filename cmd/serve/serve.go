@@ -0,0 +1,281 @@
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/cluster"
+	"github.com/openshift/osdctl/cmd/cluster/cad"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// TokenEnvVar is the environment variable holding the bearer token clients authenticate with
+	TokenEnvVar = "OSDCTL_SERVE_TOKEN"
+
+	defaultAddress = "127.0.0.1:8765"
+	// maxRequestBytes limits the size of the request bodies
+	maxRequestBytes = 1 << 20
+)
+
+// serveOptions defines the struct for running the serve command
+type serveOptions struct {
+	address string
+	token   string
+
+	// The operations exposed by the server, replaced in tests
+	clusterContext func(clusterID string, days int, full bool) (json.RawMessage, []error)
+	postServiceLog func(req servicelog.PostRequest) (*servicelog.PostResult, error)
+	runCAD         func(req cad.RunRequest) (*cad.RunResult, error)
+}
+
+// errorResponse is the body of the responses of failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+	Hint  string `json:"hint,omitempty"`
+}
+
+// contextResponse is the body of the cluster context responses
+type contextResponse struct {
+	Context json.RawMessage `json:"context"`
+	// Errors are the data points of the context which couldn't be collected
+	Errors []string `json:"errors,omitempty"`
+}
+
+// NewCmdServe implements the serve command, a local HTTP API exposing a few osdctl operations
+func NewCmdServe() *cobra.Command {
+	ops := &serveOptions{
+		clusterContext: cluster.GetClusterContext,
+		postServiceLog: func(req servicelog.PostRequest) (*servicelog.PostResult, error) {
+			conn, err := utils.CreateConnection()
+			if err != nil {
+				return nil, err
+			}
+			defer conn.Close()
+			return servicelog.Post(conn, req)
+		},
+		runCAD: cad.Run,
+	}
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a local HTTP API for chatops bots and internal tools",
+		Long: `Serve a local HTTP API for chatops bots and internal tools
+
+  The API exposes a few osdctl operations as JSON, so that tools can reuse them without running osdctl and
+  parsing its output. The operations run with the OCM login and the config file of the user running the server,
+  exactly like the CLI. Clients authenticate with the bearer token of the ` + TokenEnvVar + ` environment
+  variable, or the one generated and printed at startup when it isn't set.
+
+  Endpoints:
+    GET  /healthz
+    GET  /v1/clusters/{cluster-id}/context?days=30&full=false
+         The context of "osdctl cluster context -o json", with the errors of the data which couldn't be collected
+    POST /v1/servicelogs
+         {"cluster_id": "...", "template": "<url>", "params": {"NAME": "value"}, "dry_run": true}
+         {"cluster_id": "...", "internal_only": true, "params": {"MESSAGE": "..."}}
+         Critical service logs can only be posted with "osdctl servicelog post"
+    POST /v1/cad/runs
+         {"cluster_id": "...", "investigation": "chgm", "environment": "production", "reason": "OHSS-1234"}
+
+  Failed requests return {"error": "...", "hint": "..."}.`,
+		Example: `  # Serve the API on the default address (127.0.0.1:8765)
+  OSDCTL_SERVE_TOKEN=$(openssl rand -hex 32) osdctl serve
+
+  # Query the context of a cluster
+  curl -H "Authorization: Bearer ${OSDCTL_SERVE_TOKEN}" http://127.0.0.1:8765/v1/clusters/${CLUSTER_ID}/context`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := utils.NotifyInterrupt(cmd.Context(), cmd.ErrOrStderr())
+			defer stop()
+			return ops.run(ctx, cmd.ErrOrStderr())
+		},
+	}
+
+	serveCmd.Flags().StringVar(&ops.address, "address", defaultAddress, "Address to listen on. Listening on other addresses than the loopback exposes the API to the network")
+
+	return serveCmd
+}
+
+func (o *serveOptions) run(ctx context.Context, logOut io.Writer) error {
+	o.token = strings.TrimSpace(os.Getenv(TokenEnvVar))
+	if o.token == "" {
+		var err error
+		if o.token, err = generateToken(); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(logOut, "%s isn't set, clients must authenticate with the bearer token %s\n", TokenEnvVar, o.token)
+	}
+
+	host, _, err := net.SplitHostPort(o.address)
+	if err != nil {
+		return fmt.Errorf("invalid --address %q: %w", o.address, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		_, _ = fmt.Fprintf(logOut, "WARN: listening on %s exposes the API to the network\n", o.address)
+	}
+
+	listener, err := net.Listen("tcp", o.address)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:           o.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(logOut, "Serving the osdctl API on http://%s\n", listener.Addr())
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handler returns the routes of the API, behind the bearer token authentication
+func (o *serveOptions) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /v1/clusters/{id}/context", o.handleContext)
+	mux.HandleFunc("POST /v1/servicelogs", o.handleServiceLog)
+	mux.HandleFunc("POST /v1/cad/runs", o.handleCADRun)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" && !o.authorized(r) {
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid bearer token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (o *serveOptions) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(o.token)) == 1
+}
+
+func (o *serveOptions) handleContext(w http.ResponseWriter, r *http.Request) {
+	days := 30
+	if value := r.URL.Query().Get("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days < 1 {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid days %q, must be a positive number", value)})
+			return
+		}
+	}
+	full, _ := strconv.ParseBool(r.URL.Query().Get("full"))
+
+	clusterID := r.PathValue("id")
+	if err := utils.ValidateClusterKey("cluster-id", clusterID); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	clusterContext, dataErrors := o.clusterContext(clusterID, days, full)
+	if clusterContext == nil {
+		err := errors.Join(dataErrors...)
+		if err == nil {
+			err = fmt.Errorf("failed to get the context of cluster %s", clusterID)
+		}
+		writeError(w, err)
+		return
+	}
+	response := contextResponse{Context: clusterContext}
+	for _, err := range dataErrors {
+		response.Errors = append(response.Errors, err.Error())
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (o *serveOptions) handleServiceLog(w http.ResponseWriter, r *http.Request) {
+	var req servicelog.PostRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	result, err := o.postServiceLog(req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	status := http.StatusCreated
+	if !result.Sent {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, result)
+}
+
+func (o *serveOptions) handleCADRun(w http.ResponseWriter, r *http.Request) {
+	var req cad.RunRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	result, err := o.runCAD(req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, result)
+}
+
+// decodeRequest decodes the JSON body of the request into v, and writes the error response if it isn't valid
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return false
+	}
+	return true
+}
+
+// writeError writes the error with the remediation hint of its type, if any
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var notFound *utils.ClusterNotFoundError
+	var notLoggedIn *utils.NotLoggedInError
+	var authErr *utils.AuthError
+	switch {
+	case errors.As(err, &notFound):
+		status = http.StatusNotFound
+	case errors.As(err, &notLoggedIn), errors.As(err, &authErr):
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error(), Hint: utils.RemediationHint(err)})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate a token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,120 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/cluster/cad"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret"
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	o := &serveOptions{
+		token: testToken,
+		clusterContext: func(clusterID string, days int, full bool) (json.RawMessage, []error) {
+			if clusterID == "missing" {
+				return nil, []error{&utils.ClusterNotFoundError{Key: clusterID}}
+			}
+			assert.Equal(t, 7, days)
+			assert.True(t, full)
+			return json.RawMessage(`{"ClusterID":"` + clusterID + `"}`), []error{errors.New("skipping PagerDuty context collection")}
+		},
+		postServiceLog: func(req servicelog.PostRequest) (*servicelog.PostResult, error) {
+			if req.Template == "" && !req.InternalOnly {
+				return nil, errors.New("one of template or internal_only is required")
+			}
+			return &servicelog.PostResult{ClusterID: req.ClusterID, Payload: json.RawMessage(`{}`), Sent: !req.DryRun}, nil
+		},
+		runCAD: func(req cad.RunRequest) (*cad.RunResult, error) {
+			return &cad.RunResult{PipelineRun: "cad-manual-abcde"}, nil
+		},
+	}
+	server := httptest.NewServer(o.handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doRequest(t *testing.T, method, url, token, body string) (int, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+	var decoded map[string]any
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&decoded))
+	return res.StatusCode, decoded
+}
+
+func TestServeAuthentication(t *testing.T) {
+	server := newTestServer(t)
+
+	status, _ := doRequest(t, http.MethodGet, server.URL+"/healthz", "", "")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body := doRequest(t, http.MethodGet, server.URL+"/v1/clusters/abc/context", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "missing or invalid bearer token", body["error"])
+
+	status, _ = doRequest(t, http.MethodGet, server.URL+"/v1/clusters/abc/context", "wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestServeContext(t *testing.T) {
+	server := newTestServer(t)
+
+	status, body := doRequest(t, http.MethodGet, server.URL+"/v1/clusters/abc/context?days=7&full=true", testToken, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{"ClusterID": "abc"}, body["context"])
+	assert.Equal(t, []any{"skipping PagerDuty context collection"}, body["errors"])
+
+	status, body = doRequest(t, http.MethodGet, server.URL+"/v1/clusters/missing/context?days=7&full=true", testToken, "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body["hint"], "OCM environment")
+
+	status, _ = doRequest(t, http.MethodGet, server.URL+"/v1/clusters/abc/context?days=-1", testToken, "")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestServeServiceLog(t *testing.T) {
+	server := newTestServer(t)
+
+	status, body := doRequest(t, http.MethodPost, server.URL+"/v1/servicelogs", testToken, `{"cluster_id":"abc","internal_only":true,"params":{"MESSAGE":"hello"}}`)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, true, body["sent"])
+
+	status, body = doRequest(t, http.MethodPost, server.URL+"/v1/servicelogs", testToken, `{"cluster_id":"abc","template":"https://example.com/t.json","dry_run":true}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, false, body["sent"])
+
+	status, body = doRequest(t, http.MethodPost, server.URL+"/v1/servicelogs", testToken, `{"cluster_id":"abc"}`)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, "one of template or internal_only is required", body["error"])
+
+	status, body = doRequest(t, http.MethodPost, server.URL+"/v1/servicelogs", testToken, `{"cluster":"abc"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body["error"], "unknown field")
+}
+
+func TestServeCADRun(t *testing.T) {
+	server := newTestServer(t)
+
+	status, body := doRequest(t, http.MethodPost, server.URL+"/v1/cad/runs", testToken, `{"cluster_id":"abc","investigation":"chgm","environment":"production","reason":"OHSS-1"}`)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "cad-manual-abcde", body["pipeline_run"])
+}
@@ -0,0 +1,137 @@
+package servicelog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift/osdctl/internal/utils"
+	"github.com/openshift/osdctl/pkg/link_validator"
+	ocmutils "github.com/openshift/osdctl/pkg/utils"
+)
+
+// PostRequest is a service log to post to a single cluster, for callers outside of the CLI such as "osdctl serve".
+// Unlike "osdctl servicelog post", the template can only be a URL, e.g. one of the managed-notifications repository,
+// and Critical service logs are refused as they have to be confirmed interactively.
+type PostRequest struct {
+	ClusterID string `json:"cluster_id"`
+	// Template is the URL of the message template, or empty for an internal service log
+	Template string `json:"template,omitempty"`
+	// Params set the ${NAME} parameters of the template, MESSAGE for an internal service log
+	Params        map[string]string `json:"params,omitempty"`
+	InternalOnly  bool              `json:"internal_only,omitempty"`
+	DryRun        bool              `json:"dry_run,omitempty"`
+	SkipLinkCheck bool              `json:"skip_link_check,omitempty"`
+}
+
+// PostResult is the service log posted, or which would be posted for a dry run
+type PostResult struct {
+	ClusterID string          `json:"cluster_id"`
+	Payload   json.RawMessage `json:"payload"`
+	Sent      bool            `json:"sent"`
+}
+
+// Post renders and posts the service log of the request
+func Post(ocmClient *sdk.Connection, req PostRequest) (*PostResult, error) {
+	o, err := req.options(utils.CurlThis)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.SkipLinkCheck {
+		if _, err := link_validator.NewLinkValidator().ValidateLinks(o.Message.Summary + " " + o.Message.Description); err != nil {
+			return nil, fmt.Errorf("the service log has a dead link: %w", err)
+		}
+	}
+
+	cluster, err := ocmutils.GetCluster(ocmClient, req.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := o.payloadForCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+	result := &PostResult{ClusterID: cluster.ID(), Payload: payload}
+	if req.DryRun {
+		return result, nil
+	}
+
+	request, err := o.createPostRequest(ocmClient, cluster)
+	if err != nil {
+		return nil, err
+	}
+	response, err := ocmutils.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	o.check(response, o.Message)
+	if reason, failed := o.failedClusters[o.Message.ClusterUUID]; failed {
+		return nil, fmt.Errorf("failed to post the service log: %s", reason)
+	}
+	result.Sent = true
+	return result, nil
+}
+
+// options loads the template of the request and sets its parameters, fetching template URLs with fetch
+func (r PostRequest) options(fetch func(url string) ([]byte, error)) (*PostCmdOptions, error) {
+	if r.ClusterID == "" {
+		return nil, errors.New("cluster_id is required")
+	}
+	if err := ocmutils.ValidateClusterKey("cluster_id", r.ClusterID); err != nil {
+		return nil, err
+	}
+
+	o := &PostCmdOptions{
+		ClusterId:          r.ClusterID,
+		Template:           r.Template,
+		InternalOnly:       r.InternalOnly,
+		successfulClusters: map[string]string{},
+		failedClusters:     map[string]string{},
+	}
+
+	template := internalMessageTemplate
+	switch {
+	case r.InternalOnly && r.Template != "":
+		return nil, errors.New("template and internal_only are mutually exclusive")
+	case !r.InternalOnly && r.Template == "":
+		return nil, errors.New("one of template or internal_only is required")
+	case !r.InternalOnly:
+		if !utils.IsValidUrl(r.Template) {
+			return nil, fmt.Errorf("template %q must be a URL", r.Template)
+		}
+		var err error
+		if template, err = fetch(r.Template); err != nil {
+			return nil, fmt.Errorf("cannot fetch the template %s: %w", r.Template, err)
+		}
+	}
+	if err := o.parseTemplate(template); err != nil {
+		return nil, fmt.Errorf("cannot parse the template: %w", err)
+	}
+
+	names := make([]string, 0, len(r.Params))
+	for name := range r.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		placeholder := fmt.Sprintf("${%s}", name)
+		if r.Params[name] == "" {
+			return nil, fmt.Errorf("the value of parameter %s is empty", name)
+		}
+		if !o.Message.SearchFlag(placeholder) {
+			return nil, fmt.Errorf("the template doesn't use parameter %s", name)
+		}
+		o.Message.ReplaceWithFlag(placeholder, r.Params[name])
+	}
+	if missing := o.missingParameters([]string{"${CLUSTER_UUID}"}); len(missing) > 0 {
+		return nil, fmt.Errorf("the template uses parameter(s) which are not set: %v", missing)
+	}
+
+	if o.isCritical() {
+		return nil, fmt.Errorf("service logs with severity %s must be posted with \"osdctl servicelog post\"", criticalSeverity)
+	}
+	return o, nil
+}
@@ -0,0 +1,54 @@
+package servicelog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostRequestOptions(t *testing.T) {
+	template := []byte(`{"severity": "Warning", "service_name": "SREManualAction", "summary": "Action required",
+		"description": "Your cluster ${CLUSTER_UUID} has ${ISSUE}", "internal_only": false}`)
+	fetch := func(url string) ([]byte, error) {
+		if url == "https://example.com/critical.json" {
+			return []byte(`{"severity": "Critical", "summary": "Outage", "description": "down"}`), nil
+		}
+		if url != "https://example.com/template.json" {
+			return nil, errors.New("not found")
+		}
+		return template, nil
+	}
+
+	o, err := PostRequest{ClusterID: "abc", Template: "https://example.com/template.json", Params: map[string]string{"ISSUE": "a problem"}}.options(fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "Your cluster ${CLUSTER_UUID} has a problem", o.Message.Description)
+
+	o, err = PostRequest{ClusterID: "abc", InternalOnly: true, Params: map[string]string{"MESSAGE": "looked into it"}}.options(fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "looked into it", o.Message.Description)
+	assert.True(t, o.InternalOnly)
+
+	tests := []struct {
+		name     string
+		req      PostRequest
+		expected string
+	}{
+		{"no cluster", PostRequest{InternalOnly: true}, "cluster_id is required"},
+		{"no template", PostRequest{ClusterID: "abc"}, "one of template or internal_only is required"},
+		{"both", PostRequest{ClusterID: "abc", InternalOnly: true, Template: "https://example.com/template.json"}, "mutually exclusive"},
+		{"local file", PostRequest{ClusterID: "abc", Template: "/etc/passwd"}, "must be a URL"},
+		{"unreachable template", PostRequest{ClusterID: "abc", Template: "https://example.com/missing.json"}, "cannot fetch the template"},
+		{"missing parameter", PostRequest{ClusterID: "abc", Template: "https://example.com/template.json"}, "not set: [ISSUE]"},
+		{"unused parameter", PostRequest{ClusterID: "abc", InternalOnly: true, Params: map[string]string{"MESSAGE": "m", "OTHER": "o"}}, "doesn't use parameter OTHER"},
+		{"critical", PostRequest{ClusterID: "abc", Template: "https://example.com/critical.json"}, "must be posted with \"osdctl servicelog post\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.req.options(fetch)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
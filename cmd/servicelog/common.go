@@ -3,6 +3,7 @@ package servicelog
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
@@ -68,13 +69,13 @@ func validateBadResponse(body []byte) (badReply *servicelog.BadReply, err error)
 func GetServiceLogsSince(clusterID string, timeSince time.Time, allMessages bool, internalOnly bool) ([]*v1.LogEntry, error) {
 	earliestTime := timeSince
 
-	slResponse, err := FetchServiceLogs(clusterID, allMessages, internalOnly)
+	serviceLogs, err := FetchServiceLogs(clusterID, allMessages, internalOnly)
 	if err != nil {
 		return nil, err
 	}
 
 	var errorServiceLogs []*v1.LogEntry
-	for _, serviceLog := range slResponse.Items().Slice() {
+	for _, serviceLog := range serviceLogs {
 		if serviceLog.CreatedAt().After(earliestTime) {
 			errorServiceLogs = append(errorServiceLogs, serviceLog)
 		}
@@ -83,7 +84,8 @@ func GetServiceLogsSince(clusterID string, timeSince time.Time, allMessages bool
 	return errorServiceLogs, nil
 }
 
-func FetchServiceLogs(clusterID string, allMessages bool, internalOnly bool) (*v1.ClustersClusterLogsListResponse, error) {
+// FetchServiceLogs returns all the service logs of a cluster, most recent first
+func FetchServiceLogs(clusterID string, allMessages bool, internalOnly bool) ([]*v1.LogEntry, error) {
 	// Create OCM client to talk to cluster API
 	ocmClient, err := utils.CreateConnection()
 	if err != nil {
@@ -103,34 +105,46 @@ func FetchServiceLogs(clusterID string, allMessages bool, internalOnly bool) (*v
 	cluster := clusters[0]
 
 	// Now get the SLs for the cluster
-	clusterLogsListResponse, err := sendClusterLogsListRequest(ocmClient, cluster, allMessages, internalOnly)
+	serviceLogs, err := ListClusterLogs(ocmClient, cluster, allMessages, internalOnly, time.Time{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch service logs for cluster %v: %w", clusterID, err)
 	}
-	return clusterLogsListResponse, nil
+	return serviceLogs, nil
 }
 
-func sendClusterLogsListRequest(ocmClient *sdk.Connection, cluster *cmv1.Cluster, allMessages bool, internalMessages bool) (*v1.ClustersClusterLogsListResponse, error) {
+// ListClusterLogs returns the service logs of the cluster, most recent first. Only the ones sent by SREs are returned
+// unless allMessages is set, only the internal ones with internalMessages, and only the ones sent since a non zero since.
+func ListClusterLogs(ocmClient *sdk.Connection, cluster *cmv1.Cluster, allMessages bool, internalMessages bool, since time.Time) ([]*v1.LogEntry, error) {
 	request := ocmClient.ServiceLogs().V1().Clusters().ClusterLogs().List().
 		ClusterID(cluster.ID()).
 		ClusterUUID(cluster.ExternalID()).
-		Parameter("orderBy", "timestamp desc")
+		Parameter("orderBy", "timestamp desc").
+		Search(clusterLogsSearch(allMessages, internalMessages, since))
 
-	var searchQuery string
+	serviceLogs, err := utils.NewOCMPaginator(func(page, size int) ([]*v1.LogEntry, error) {
+		response, err := request.Page(page).Size(size).Send()
+		if err != nil {
+			return nil, err
+		}
+		return response.Items().Slice(), nil
+	}).All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch service logs: %w", err)
+	}
+	return serviceLogs, nil
+}
+
+// clusterLogsSearch returns the search query of the service logs listed by ListClusterLogs
+func clusterLogsSearch(allMessages bool, internalMessages bool, since time.Time) string {
+	var terms []string
 	if !allMessages {
-		searchQuery = "service_name='SREManualAction'"
+		terms = append(terms, "service_name='SREManualAction'")
 	}
 	if internalMessages {
-		if searchQuery != "" {
-			searchQuery += " and "
-		}
-		searchQuery += "internal_only='true'"
+		terms = append(terms, "internal_only='true'")
 	}
-	request.Search(searchQuery)
-
-	response, err := request.Send()
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch service logs: %w", err)
+	if !since.IsZero() {
+		terms = append(terms, fmt.Sprintf("timestamp >= '%s'", since.UTC().Format(time.RFC3339)))
 	}
-	return response, nil
+	return strings.Join(terms, " and ")
 }
@@ -2,6 +2,7 @@ package servicelog
 
 import (
 	"testing"
+	"time"
 
 	"github.com/openshift/osdctl/internal/servicelog"
 	"github.com/stretchr/testify/assert"
@@ -224,3 +225,13 @@ func TestValidateBadResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterLogsSearch(t *testing.T) {
+	since := time.Date(2026, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	assert.Equal(t, "service_name='SREManualAction'", clusterLogsSearch(false, false, time.Time{}))
+	assert.Equal(t, "", clusterLogsSearch(true, false, time.Time{}))
+	assert.Equal(t, "service_name='SREManualAction' and internal_only='true'", clusterLogsSearch(false, true, time.Time{}))
+	assert.Equal(t, "timestamp >= '2026-06-01T10:00:00Z'", clusterLogsSearch(true, false, since))
+	assert.Equal(t, "service_name='SREManualAction' and internal_only='true' and timestamp >= '2026-06-01T10:00:00Z'", clusterLogsSearch(false, true, since))
+}
@@ -0,0 +1,226 @@
+package servicelog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// retractConfirmation is the word to type to confirm retracting a service log
+	retractConfirmation = "retract"
+	// manualActionServiceName is the service name of the service logs posted by SREs
+	manualActionServiceName = "SREManualAction"
+)
+
+// serviceLogClient is the part of the service logs API used to retract service logs
+type serviceLogClient interface {
+	Get(id string) (*slv1.LogEntry, error)
+	Delete(id string) error
+	Add(entry *slv1.LogEntry) (*slv1.LogEntry, error)
+}
+
+type deleteCmdOptions struct {
+	clusterID  string
+	logID      string
+	reason     string
+	correction string
+	dryRun     bool
+
+	out     io.Writer
+	cluster *cmv1.Cluster
+	client  serviceLogClient
+}
+
+func newDeleteCmd() *cobra.Command {
+	opts := &deleteCmdOptions{out: os.Stdout}
+	cmd := &cobra.Command{
+		Use:   "delete --cluster-id <cluster-identifier> --id <service-log-id> --reason <reason>",
+		Short: "Retract a service log posted in error",
+		Long: `Retract a service log posted in error
+
+  Internal service logs are deleted. Service logs the customer can see can't be deleted: --correction posts a
+  new service log to the customer, correcting the original one. Either way an internal service log recording
+  the retraction, its reason and the original service log is posted to the cluster.
+
+  The service log being retracted is shown first, and the retraction has to be confirmed by typing "retract",
+  which --assume-yes doesn't answer.`,
+		Example: `  # Delete an internal service log posted to the wrong cluster
+  osdctl servicelog delete --cluster-id ${CLUSTER_ID} --id ${SERVICE_LOG_ID} --reason "Posted to the wrong cluster"
+
+  # Correct a service log sent to the customer
+  osdctl servicelog delete --cluster-id ${CLUSTER_ID} --id ${SERVICE_LOG_ID} --reason "Wrong template" \
+    --correction "The previous notification about the expiring pull secret was sent in error, no action is required."`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := utils.CreateConnection()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			if opts.cluster, err = utils.GetCluster(conn, opts.clusterID); err != nil {
+				return err
+			}
+			opts.client = &ocmServiceLogClient{conn: conn}
+			return opts.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Internal Cluster identifier (required)")
+	cmd.Flags().StringVar(&opts.logID, "id", "", "ID of the service log to retract, see \"osdctl servicelog list\"")
+	cmd.Flags().StringVar(&opts.reason, "reason", "", "Why the service log is retracted, recorded in the internal service log of the retraction")
+	cmd.Flags().StringVar(&opts.correction, "correction", "", "Post this correction to the customer instead of deleting the service log. Required for the service logs the customer can see")
+	cmd.Flags().BoolVarP(&opts.dryRun, "dry-run", "d", false, "Show the service log and what would be done without doing it")
+	_ = cmd.MarkFlagRequired("cluster-id")
+	_ = cmd.MarkFlagRequired("id")
+	_ = cmd.MarkFlagRequired("reason")
+
+	return cmd
+}
+
+func (o *deleteCmdOptions) run() error {
+	if strings.TrimSpace(o.reason) == "" {
+		return errors.New("--reason can't be empty")
+	}
+
+	entry, err := o.client.Get(o.logID)
+	if err != nil {
+		return fmt.Errorf("failed to get service log %s: %w", o.logID, err)
+	}
+	if entry.ClusterID() != o.cluster.ID() && entry.ClusterUUID() != o.cluster.ExternalID() {
+		return fmt.Errorf("service log %s wasn't posted to cluster %s", o.logID, o.cluster.ID())
+	}
+	if !entry.InternalOnly() && o.correction == "" {
+		return fmt.Errorf("service log %s is visible to the customer and can't be deleted, post a correction with --correction", o.logID)
+	}
+
+	action := "deleted"
+	if o.correction != "" {
+		action = "corrected"
+	}
+	printRetractedEntry(o.out, entry, action)
+	if o.dryRun {
+		_, _ = fmt.Fprintln(o.out, "This is a dry run, nothing was done.")
+		return nil
+	}
+
+	if !prompt.IsInteractive() {
+		return errors.New("retracting a service log must be confirmed by typing \"retract\", which isn't possible in non-interactive mode")
+	}
+	answer, err := prompt.Input(fmt.Sprintf("Type %q to confirm", retractConfirmation), "")
+	if err != nil {
+		return err
+	}
+	if answer != retractConfirmation {
+		return errors.New("aborted: the retraction wasn't confirmed")
+	}
+
+	if o.correction != "" {
+		correction, err := o.client.Add(o.correctionEntry(entry))
+		if err != nil {
+			return fmt.Errorf("failed to post the correction: %w", err)
+		}
+		_, _ = fmt.Fprintf(o.out, "Posted correction %s\n", correction.ID())
+	} else {
+		if err := o.client.Delete(entry.ID()); err != nil {
+			return fmt.Errorf("failed to delete service log %s, OCM may not allow it, post a correction with --correction instead: %w", entry.ID(), err)
+		}
+		_, _ = fmt.Fprintf(o.out, "Deleted service log %s\n", entry.ID())
+	}
+
+	audit, err := o.client.Add(o.auditEntry(entry, action))
+	if err != nil {
+		return fmt.Errorf("service log %s was %s but the internal service log recording it couldn't be posted: %w", entry.ID(), action, err)
+	}
+	_, _ = fmt.Fprintf(o.out, "Recorded the retraction in internal service log %s\n", audit.ID())
+	return nil
+}
+
+// correctionEntry is the service log correcting the original one, visible to the same audience
+func (o *deleteCmdOptions) correctionEntry(original *slv1.LogEntry) *slv1.LogEntry {
+	description := fmt.Sprintf("%s\n\nThis corrects the notification %q of %s.",
+		o.correction, original.Summary(), original.Timestamp().UTC().Format(time.RFC1123))
+	entry, _ := o.baseEntry().
+		Severity(slv1.SeverityInfo).
+		Summary("Correction: " + original.Summary()).
+		Description(description).
+		InternalOnly(original.InternalOnly()).
+		Build()
+	return entry
+}
+
+// auditEntry is the internal service log recording the retraction of the original one
+func (o *deleteCmdOptions) auditEntry(original *slv1.LogEntry, action string) *slv1.LogEntry {
+	description := fmt.Sprintf("Service log %s %q posted on %s by %s was %s: %s\n\nOriginal description: %s",
+		original.ID(), original.Summary(), original.Timestamp().UTC().Format(time.RFC3339), original.Username(), action, o.reason, original.Description())
+	entry, _ := o.baseEntry().
+		Severity(slv1.SeverityInfo).
+		Summary("INTERNAL ONLY, DO NOT SHARE WITH CUSTOMER").
+		Description(description).
+		InternalOnly(true).
+		Build()
+	return entry
+}
+
+func (o *deleteCmdOptions) baseEntry() *slv1.LogEntryBuilder {
+	builder := slv1.NewLogEntry().
+		ClusterID(o.cluster.ID()).
+		ClusterUUID(o.cluster.ExternalID()).
+		ServiceName(manualActionServiceName)
+	if subscription := o.cluster.Subscription(); subscription != nil {
+		builder.SubscriptionID(subscription.ID())
+	}
+	return builder
+}
+
+func printRetractedEntry(w io.Writer, entry *slv1.LogEntry, action string) {
+	visibility := "visible to the customer"
+	if entry.InternalOnly() {
+		visibility = "internal only"
+	}
+	_, _ = fmt.Fprintf(w, "The following service log will be %s:\n\n", action)
+	_, _ = fmt.Fprintf(w, "  ID:          %s\n", entry.ID())
+	_, _ = fmt.Fprintf(w, "  Posted:      %s by %s\n", entry.Timestamp().UTC().Format(time.RFC3339), entry.Username())
+	_, _ = fmt.Fprintf(w, "  Visibility:  %s\n", visibility)
+	_, _ = fmt.Fprintf(w, "  Severity:    %s\n", entry.Severity())
+	_, _ = fmt.Fprintf(w, "  Summary:     %s\n", entry.Summary())
+	_, _ = fmt.Fprintf(w, "  Description: %s\n\n", entry.Description())
+}
+
+// ocmServiceLogClient implements serviceLogClient with the OCM service logs API
+type ocmServiceLogClient struct {
+	conn *sdk.Connection
+}
+
+func (c *ocmServiceLogClient) Get(id string) (*slv1.LogEntry, error) {
+	response, err := c.conn.ServiceLogs().V1().ClusterLogs().LogEntry(id).Get().Send()
+	if err != nil {
+		return nil, err
+	}
+	return response.Body(), nil
+}
+
+func (c *ocmServiceLogClient) Delete(id string) error {
+	_, err := c.conn.ServiceLogs().V1().ClusterLogs().LogEntry(id).Delete().Send()
+	return err
+}
+
+func (c *ocmServiceLogClient) Add(entry *slv1.LogEntry) (*slv1.LogEntry, error) {
+	response, err := c.conn.ServiceLogs().V1().ClusterLogs().Add().Body(entry).Send()
+	if err != nil {
+		return nil, err
+	}
+	return response.Body(), nil
+}
@@ -0,0 +1,109 @@
+package servicelog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeServiceLogClient struct {
+	entry     *slv1.LogEntry
+	deleteErr error
+	deleted   []string
+	added     []*slv1.LogEntry
+}
+
+func (f *fakeServiceLogClient) Get(id string) (*slv1.LogEntry, error) {
+	if f.entry == nil || f.entry.ID() != id {
+		return nil, errors.New("not found")
+	}
+	return f.entry, nil
+}
+
+func (f *fakeServiceLogClient) Delete(id string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeServiceLogClient) Add(entry *slv1.LogEntry) (*slv1.LogEntry, error) {
+	f.added = append(f.added, entry)
+	return slv1.NewLogEntry().ID("new-log").Build()
+}
+
+func newRetractedEntry(t *testing.T, clusterID string, internal bool) *slv1.LogEntry {
+	t.Helper()
+	entry, err := slv1.NewLogEntry().ID("log-1").ClusterID(clusterID).
+		Summary("Pull secret expiring").Description("Your pull secret expires soon").
+		Severity(slv1.SeverityWarning).InternalOnly(internal).Username("jdoe").
+		Timestamp(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)).Build()
+	require.NoError(t, err)
+	return entry
+}
+
+func TestDeleteRun(t *testing.T) {
+	cluster, err := cmv1.NewCluster().ID("abc123").ExternalID("uuid-1").Build()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		entry      *slv1.LogEntry
+		correction string
+		input      string
+		dryRun     bool
+		deleteErr  error
+		expectErr  string
+		deleted    bool
+		added      int
+	}{
+		{name: "internal entry deleted", entry: newRetractedEntry(t, "abc123", true), input: "retract\n", deleted: true, added: 1},
+		{name: "customer entry corrected", entry: newRetractedEntry(t, "abc123", false), correction: "Please ignore it.", input: "retract\n", added: 2},
+		{name: "customer entry can't be deleted", entry: newRetractedEntry(t, "abc123", false), expectErr: "visible to the customer and can't be deleted"},
+		{name: "other cluster", entry: newRetractedEntry(t, "def456", true), expectErr: "wasn't posted to cluster abc123"},
+		{name: "not confirmed", entry: newRetractedEntry(t, "abc123", true), input: "y\n", expectErr: "wasn't confirmed"},
+		{name: "dry run", entry: newRetractedEntry(t, "abc123", true), dryRun: true},
+		{name: "delete refused", entry: newRetractedEntry(t, "abc123", true), input: "retract\n", deleteErr: errors.New("forbidden"), expectErr: "post a correction with --correction instead"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer prompt.SetIO(strings.NewReader(tt.input), &bytes.Buffer{})()
+			client := &fakeServiceLogClient{entry: tt.entry, deleteErr: tt.deleteErr}
+			out := &bytes.Buffer{}
+			opts := &deleteCmdOptions{logID: "log-1", reason: "Wrong cluster", correction: tt.correction, dryRun: tt.dryRun, out: out, cluster: cluster, client: client}
+
+			err := opts.run()
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.deleted, len(client.deleted) == 1)
+			require.Len(t, client.added, tt.added)
+			if tt.added > 0 {
+				audit := client.added[len(client.added)-1]
+				assert.True(t, audit.InternalOnly())
+				assert.Contains(t, audit.Description(), `Service log log-1 "Pull secret expiring" posted on 2024-05-01T10:00:00Z by jdoe was`)
+				assert.Contains(t, audit.Description(), "Wrong cluster")
+			}
+			if tt.correction != "" && tt.added > 0 {
+				correction := client.added[0]
+				assert.False(t, correction.InternalOnly())
+				assert.Equal(t, "Correction: Pull secret expiring", correction.Summary())
+				assert.Equal(t, "abc123", correction.ClusterID())
+				assert.Equal(t, "uuid-1", correction.ClusterUUID())
+				assert.Contains(t, correction.Description(), "Please ignore it.")
+			}
+		})
+	}
+}
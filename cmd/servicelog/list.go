@@ -48,27 +48,28 @@ func newListCmd() *cobra.Command {
 }
 
 func listServiceLogs(clusterID string, opts *listCmdOptions) error {
-	response, err := FetchServiceLogs(clusterID, opts.allMessages, opts.internal)
+	serviceLogs, err := FetchServiceLogs(clusterID, opts.allMessages, opts.internal)
 	if err != nil {
 		return fmt.Errorf("failed to fetch service logs: %w", err)
 	}
 
-	if err = printServiceLogResponse(response); err != nil {
+	if err = printServiceLogResponse(serviceLogs); err != nil {
 		return fmt.Errorf("failed to print service logs: %w", err)
 	}
 
 	return nil
 }
 
-func printServiceLogResponse(response *slv1.ClustersClusterLogsListResponse) error {
-	entryViews := logEntryToView(response.Items().Slice())
+func printServiceLogResponse(serviceLogs []*slv1.LogEntry) error {
+	entryViews := logEntryToView(serviceLogs)
 	slices.Reverse(entryViews)
+	// All the pages are fetched, so the whole list is shown as a single page
 	view := LogEntryResponseView{
 		Items: entryViews,
 		Kind:  "ClusterLogList",
-		Page:  response.Page(),
-		Size:  response.Size(),
-		Total: response.Total(),
+		Page:  1,
+		Size:  len(entryViews),
+		Total: len(entryViews),
 	}
 
 	viewBytes, err := json.Marshal(view)
@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	stdio "io"
 	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,6 +27,7 @@ import (
 	"github.com/openshift/osdctl/internal/utils"
 	"github.com/openshift/osdctl/pkg/link_validator"
 	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/prompt"
 	ocmutils "github.com/openshift/osdctl/pkg/utils"
 
 	log "github.com/sirupsen/logrus"
@@ -52,7 +55,21 @@ type PostCmdOptions struct {
 	failedClusters     map[string]string
 }
 
-const documentationBaseURL = "https://docs.openshift.com"
+const (
+	documentationBaseURL = "https://docs.openshift.com"
+	criticalSeverity     = "Critical"
+)
+
+// internalMessageTemplate is the fixed template of internal service logs
+var internalMessageTemplate = []byte(`
+		{
+			"severity": "Info",
+			"service_name": "SREManualAction",
+			"summary": "INTERNAL ONLY, DO NOT SHARE WITH CUSTOMER",
+			"description": "${MESSAGE}",
+			"internal_only": true
+		}
+		`)
 
 func newPostCmd() *cobra.Command {
 	var opts = PostCmdOptions{}
@@ -61,6 +78,9 @@ func newPostCmd() *cobra.Command {
 		Short: "Post a service log to a cluster or list of clusters",
 		Long: `Post a service log to a cluster or list of clusters
 
+  Service logs with severity Critical have to be confirmed explicitly by typing the
+  severity, even when prompts are skipped with --yes.
+
   Docs: https://docs.openshift.com/rosa/logging/sd-accessing-the-service-logs.html`,
 		Example: `
   # Post a service log to a single cluster via a local file
@@ -69,6 +89,9 @@ func newPostCmd() *cobra.Command {
   # Post a service log to a single cluster via a remote URL, providing a parameter
   osdctl servicelog post --cluster-id ${CLUSTER_ID} -t https://raw.githubusercontent.com/openshift/managed-notifications/master/osd/incident_resolved.json -p ALERT_NAME="alert"
 
+  # Render the exact payload which would be sent, without sending it
+  osdctl servicelog post --cluster-id ${CLUSTER_ID} -t ~/path/to/file.json --dry-run
+
   # Post an internal-only service log message
   osdctl servicelog post --cluster-id ${CLUSTER_ID} -i -p "MESSAGE=This is an internal message"
 
@@ -90,7 +113,7 @@ func newPostCmd() *cobra.Command {
 	postCmd.Flags().StringVarP(&opts.Template, "template", "t", "", "Message template file or URL")
 	postCmd.Flags().StringArrayVarP(&opts.TemplateParams, "param", "p", opts.TemplateParams, "Specify a key-value pair (eg. -p FOO=BAR) to set/override a parameter value in the template.")
 	postCmd.Flags().StringArrayVarP(&opts.Overrides, "override", "r", opts.Overrides, "Specify a key-value pair (eg. -r FOO=BAR) to replace a JSON key in the document, only supports string fields, specifying -r without -t or -i will use a default template with severity `Info` and internal_only=True unless these are also overridden.")
-	postCmd.Flags().BoolVarP(&opts.isDryRun, "dry-run", "d", false, "Dry-run - print the service log about to be sent but don't send it.")
+	postCmd.Flags().BoolVarP(&opts.isDryRun, "dry-run", "d", false, "Dry-run - print the exact service log payload about to be sent to each cluster but don't send it.")
 	postCmd.Flags().StringArrayVarP(&opts.filterParams, "query", "q", []string{}, "Specify a search query (eg. -q \"name like foo\") for a bulk-post to matching clusters.")
 	postCmd.Flags().BoolVarP(&opts.skipPrompts, "yes", "y", false, "Skips all prompts.")
 	postCmd.Flags().StringArrayVarP(&opts.filterFiles, "query-file", "f", []string{}, "File containing search queries to apply. All lines in the file will be concatenated into a single query. If this flag is called multiple times, every file's search query will be combined with logical AND.")
@@ -98,6 +121,8 @@ func newPostCmd() *cobra.Command {
 	postCmd.Flags().BoolVarP(&opts.InternalOnly, "internal", "i", false, "Internal only service log. Use MESSAGE for template parameter (eg. -p MESSAGE='My super secret message').")
 	postCmd.Flags().BoolVar(&opts.SkipLinkCheck, "skip-link-check", false, "Skip validating if links in Service Log are valid")
 
+	_ = postCmd.RegisterFlagCompletionFunc("param", opts.completeTemplateParams)
+
 	return postCmd
 }
 
@@ -165,7 +190,9 @@ func (o *PostCmdOptions) Run() error {
 
 	// Check if there are any remaining placeholders in the template that are not replaced by a parameter,
 	// excluding '${CLUSTER_UUID}' which will be replaced for each cluster later
-	o.checkLeftovers([]string{"${CLUSTER_UUID}"})
+	if err := o.checkLeftovers([]string{"${CLUSTER_UUID}"}); err != nil {
+		return err
+	}
 
 	// Create an OCM client to talk to the cluster API
 	// the user has to be logged in (e.g. 'ocm login')
@@ -229,7 +256,7 @@ func (o *PostCmdOptions) Run() error {
 	// duplicate messages in quick succession
 	if len(clusters) == 1 {
 		if term.IsTerminal(int(os.Stdout.Fd())) && CheckServiceLogsLastHour(clusters[0].ID()) {
-			if !ocmutils.ConfirmPrompt() {
+			if !prompt.ConfirmPrompt() {
 				return nil
 			}
 		}
@@ -254,17 +281,22 @@ func (o *PostCmdOptions) Run() error {
 		}
 	}
 
-	// If this is a dry-run, don't proceed further.
+	// If this is a dry-run, render the exact payloads and don't proceed further.
 	if o.isDryRun {
-		return nil
+		return o.printDryRun(os.Stdout, clusters)
 	}
 
 	if !o.skipPrompts {
-		if !ocmutils.ConfirmPrompt() {
+		if !prompt.ConfirmPrompt() {
 			return nil
 		}
 	}
 
+	// Critical service logs always need an explicit confirmation, even when prompts are skipped
+	if !o.confirmCriticalSeverity(len(clusters)) {
+		return errors.New("aborted: Critical service logs must be confirmed explicitly")
+	}
+
 	// Handler if the program terminates abruptly
 	go func() {
 		sigchan := make(chan os.Signal, 1)
@@ -294,7 +326,7 @@ func (o *PostCmdOptions) Run() error {
 
 			if docClusterType != clusterType {
 				log.Warn("The documentation mentioned in the servicelog is for '", docClusterType, "' while the product is '", clusterType, "'.")
-				if !ocmutils.ConfirmPrompt() {
+				if !prompt.ConfirmPrompt() {
 					log.Info("Skipping cluster ID: ", cluster.ID(), ", Name: ", cluster.Name())
 					continue
 				}
@@ -468,17 +500,7 @@ func (o *PostCmdOptions) parseTemplate(jsonFile []byte) error {
 // readTemplate loads the template into the Message variable
 func (o *PostCmdOptions) readTemplate() {
 	if o.InternalOnly {
-		// fixed template for internal service logs
-		messageTemplate := []byte(`
-		{
-			"severity": "Info",
-			"service_name": "SREManualAction",
-			"summary": "INTERNAL ONLY, DO NOT SHARE WITH CUSTOMER",
-			"description": "${MESSAGE}",
-			"internal_only": true
-		}
-		`)
-		if err := o.parseTemplate(messageTemplate); err != nil {
+		if err := o.parseTemplate(internalMessageTemplate); err != nil {
 			log.Fatalf("Cannot not parse the JSON internal message template.\nError: %q\n", err)
 		}
 		return
@@ -539,25 +561,98 @@ func (o *PostCmdOptions) FindLeftovers(s string) (matches []string) {
 	return matches
 }
 
-func (o *PostCmdOptions) checkLeftovers(excludes []string) {
+// missingParameters returns the unique names of the template parameters that were not replaced by a '-p' flag,
+// ignoring the placeholders in excludes
+func (o *PostCmdOptions) missingParameters(excludes []string) []string {
 	unusedParameters, _ := o.Message.FindLeftovers()
 	unusedParameters = append(unusedParameters, o.FindLeftovers(o.filtersFromFile)...)
 
-	var numberOfMissingParameters int
+	regex := strings.NewReplacer("${", "", "}", "")
+	var missing []string
 	for _, v := range unusedParameters {
 		// Ignore parameters in the exclude list, ie ${CLUSTER_UUID}, which will be replaced later for each cluster a servicelog is sent to
-
-		if !slices.Contains(excludes, v) {
-			numberOfMissingParameters++
-			regex := strings.NewReplacer("${", "", "}", "")
-			log.Errorf("The one of the template files is using '%s' parameter, but '--param' flag is not set for this one. Use '-p %v=\"FOOBAR\"' to fix this.", v, regex.Replace(v))
+		if slices.Contains(excludes, v) {
+			continue
+		}
+		name := regex.Replace(v)
+		if !slices.Contains(missing, name) {
+			missing = append(missing, name)
 		}
 	}
-	if numberOfMissingParameters == 1 {
-		log.Fatal("Please define this missing parameter properly.")
-	} else if numberOfMissingParameters > 1 {
-		log.Fatalf("Please define all %v missing parameters properly.", numberOfMissingParameters)
+	return missing
+}
+
+// checkLeftovers returns an error listing every template parameter that is not set
+func (o *PostCmdOptions) checkLeftovers(excludes []string) error {
+	missing := o.missingParameters(excludes)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	hints := make([]string, 0, len(missing))
+	for _, name := range missing {
+		hints = append(hints, fmt.Sprintf("-p %v=\"FOOBAR\"", name))
+	}
+	return fmt.Errorf("the template is using %d parameter(s) which are not set via '--param': %s. Use '%s' to fix this",
+		len(missing), strings.Join(missing, ", "), strings.Join(hints, " "))
+}
+
+// completeTemplateParams provides shell completion for '-p' by listing the parameters of the template
+// given via '-t' which have not been set yet
+func (o *PostCmdOptions) completeTemplateParams(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if o.Template == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	file, err := o.accessFile(o.Template)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completion := PostCmdOptions{filtersFromFile: o.filtersFromFile}
+	if err := completion.parseTemplate(file); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var alreadySet []string
+	for _, p := range o.TemplateParams {
+		alreadySet = append(alreadySet, fmt.Sprintf("${%s}", strings.SplitN(p, "=", 2)[0]))
+	}
+
+	var suggestions []string
+	for _, name := range completion.missingParameters(append(alreadySet, "${CLUSTER_UUID}")) {
+		suggestions = append(suggestions, name+"=")
+	}
+	return suggestions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// MissingTemplateParameters returns the names of the parameters of the template file or URL which are not set by
+// the given '-p' style parameters, ignoring ${CLUSTER_UUID} which is set for each cluster a service log is sent to
+func MissingTemplateParameters(template string, params []string) ([]string, error) {
+	o := PostCmdOptions{}
+	file, err := o.accessFile(template)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.parseTemplate(file); err != nil {
+		return nil, fmt.Errorf("cannot parse the JSON template: %w", err)
+	}
+
+	excludes := []string{"${CLUSTER_UUID}"}
+	for _, p := range params {
+		excludes = append(excludes, fmt.Sprintf("${%s}", strings.SplitN(p, "=", 2)[0]))
 	}
+	return o.missingParameters(excludes), nil
+}
+
+// SuccessfulClusters returns the external IDs of the clusters the service log was sent to by Run
+func (o *PostCmdOptions) SuccessfulClusters() []string {
+	clusters := make([]string, 0, len(o.successfulClusters))
+	for id := range o.successfulClusters {
+		clusters = append(clusters, id)
+	}
+	sort.Strings(clusters)
+	return clusters
 }
 
 func (o *PostCmdOptions) replaceFlags(flagName string, flagValue string) {
@@ -609,6 +704,17 @@ func (o *PostCmdOptions) createPostRequest(ocmClient *sdk.Connection, cluster *v
 		return nil, fmt.Errorf("cannot parse API path '%s': %v", targetAPIPath, err)
 	}
 
+	messageBytes, err := o.payloadForCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Bytes(messageBytes)
+	return request, nil
+}
+
+// payloadForCluster returns the exact body of the service log API request for the cluster
+func (o *PostCmdOptions) payloadForCluster(cluster *v1.Cluster) ([]byte, error) {
 	o.Message.ClusterUUID = cluster.ExternalID()
 	o.Message.ClusterID = cluster.ID()
 	o.Message.InternalOnly = o.InternalOnly
@@ -620,9 +726,58 @@ func (o *PostCmdOptions) createPostRequest(ocmClient *sdk.Connection, cluster *v
 	if err != nil {
 		return nil, fmt.Errorf("cannot marshal template to json: %v", err)
 	}
+	return messageBytes, nil
+}
 
-	request.Bytes(messageBytes)
-	return request, nil
+// isCritical returns true if the service log has the Critical severity
+func (o *PostCmdOptions) isCritical() bool {
+	return strings.EqualFold(strings.TrimSpace(o.Message.Severity), criticalSeverity)
+}
+
+// confirmCriticalSeverity asks the user to type the severity to confirm sending a Critical service log.
+// Service logs with any other severity are always confirmed.
+func (o *PostCmdOptions) confirmCriticalSeverity(clusterCount int) bool {
+	if !o.isCritical() {
+		return true
+	}
+
+	log.Warnf("This service log has severity %s and will be sent to %d cluster(s).", criticalSeverity, clusterCount)
+	answer, err := prompt.Input(fmt.Sprintf("Type %q to send it", criticalSeverity), "")
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(answer), criticalSeverity)
+}
+
+// printDryRun renders the fully substituted service log and the API payload for every cluster
+func (o *PostCmdOptions) printDryRun(w stdio.Writer, clusters []*v1.Cluster) error {
+	for _, cluster := range clusters {
+		payload, err := o.payloadForCluster(cluster)
+		if err != nil {
+			return err
+		}
+
+		table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+		table.AddRow([]string{"Cluster:", fmt.Sprintf("%s (%s)", cluster.Name(), cluster.ID())})
+		table.AddRow([]string{"External ID:", cluster.ExternalID()})
+		table.AddRow([]string{"Severity:", o.Message.Severity})
+		table.AddRow([]string{"Internal only:", strconv.FormatBool(o.Message.InternalOnly)})
+		table.AddRow([]string{"Summary:", o.Message.Summary})
+		if err := table.Flush(); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(w, "Description:\n%s\n\nPayload:\n", o.Message.Description)
+		if err := dump.Pretty(w, payload); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	if o.isCritical() {
+		log.Warnf("This is a dry-run, sending this %s service log requires an explicit confirmation.", criticalSeverity)
+	}
+	return nil
 }
 
 // listMessagedClusters prints all the clusters a service log was tried to be posted.
@@ -1,17 +1,24 @@
 package servicelog
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/osdctl/internal/servicelog"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSetup(t *testing.T) {
@@ -423,6 +430,7 @@ func TestCheckLeftovers(t *testing.T) {
 		name         string
 		inputOptions PostCmdOptions
 		excludes     []string
+		expectedErr  string
 	}{
 		{
 			name: "no_leftovers",
@@ -452,15 +460,58 @@ func TestCheckLeftovers(t *testing.T) {
 			},
 			excludes: []string{"${PLACEHOLDER}"},
 		},
+		{
+			name: "missing_parameters_are_listed",
+			inputOptions: PostCmdOptions{
+				Message:         servicelog.Message{Summary: "${FOO} and ${BAR}", Description: "${FOO} again"},
+				filtersFromFile: "name is ${BAZ}",
+			},
+			excludes:    []string{"${CLUSTER_UUID}"},
+			expectedErr: "the template is using 3 parameter(s) which are not set via '--param': FOO, BAR, BAZ",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.inputOptions.checkLeftovers(tt.excludes)
-			// no assert is required as checkleftover doesn't return anything, We just have to ensure no Fatal logs are logged.
+			err := tt.inputOptions.checkLeftovers(tt.excludes)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
 		})
 	}
 }
+
+func TestCompleteTemplateParams(t *testing.T) {
+	tmpFile, err := os.CreateTemp(t.TempDir(), "template-*.json")
+	assert.NoError(t, err)
+	_, err = tmpFile.WriteString(`{"severity": "Info", "summary": "${ALERT_NAME} on ${CLUSTER_UUID}", "description": "${REASON}"}`)
+	assert.NoError(t, err)
+	assert.NoError(t, tmpFile.Close())
+
+	opts := PostCmdOptions{Template: tmpFile.Name(), TemplateParams: []string{"REASON=foo"}}
+	suggestions, directive := opts.completeTemplateParams(nil, nil, "")
+	assert.Equal(t, []string{"ALERT_NAME="}, suggestions)
+	assert.Equal(t, cobra.ShellCompDirectiveNoSpace|cobra.ShellCompDirectiveNoFileComp, directive)
+
+	opts = PostCmdOptions{}
+	suggestions, _ = opts.completeTemplateParams(nil, nil, "")
+	assert.Empty(t, suggestions)
+}
+
+func TestMissingTemplateParameters(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "template.json")
+	require.NoError(t, os.WriteFile(template, []byte(`{"summary": "${ALERT_NAME} on ${CLUSTER_UUID}", "description": "${REASON} ${REASON}"}`), 0600))
+
+	missing, err := MissingTemplateParameters(template, []string{"REASON=foo"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ALERT_NAME"}, missing)
+
+	_, err = MissingTemplateParameters(filepath.Join(t.TempDir(), "missing.json"), nil)
+	assert.Error(t, err)
+}
+
 func TestReadTemplate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -633,3 +684,55 @@ func TestPrintPostOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintDryRun(t *testing.T) {
+	cluster, err := v1.NewCluster().ID("abc123").Name("my-cluster").ExternalID("1a2b-3c4d").
+		Subscription(v1.NewSubscription().ID("sub-1")).Build()
+	require.NoError(t, err)
+
+	o := &PostCmdOptions{Message: servicelog.Message{
+		Severity:    "Critical",
+		ServiceName: "SREManualAction",
+		Summary:     "Action required",
+		Description: "Your cluster requires attention",
+	}}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, o.printDryRun(out, []*v1.Cluster{cluster}))
+
+	assert.Regexp(t, `External ID:\s+1a2b-3c4d\n`, out.String())
+	assert.Regexp(t, `Severity:\s+Critical\n`, out.String())
+	assert.Regexp(t, `Internal only:\s+false\n`, out.String())
+	assert.Contains(t, out.String(), "Description:\nYour cluster requires attention\n")
+
+	payload := out.String()[strings.Index(out.String(), "Payload:\n")+len("Payload:\n"):]
+	var message servicelog.Message
+	require.NoError(t, json.Unmarshal([]byte(payload), &message))
+	assert.Equal(t, "1a2b-3c4d", message.ClusterUUID)
+	assert.Equal(t, "abc123", message.ClusterID)
+	assert.Equal(t, "sub-1", message.SubscriptionID)
+}
+
+func TestConfirmCriticalSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity string
+		input    string
+		expected bool
+	}{
+		{name: "non_critical_is_not_confirmed", severity: "Warning", expected: true},
+		{name: "critical_confirmed", severity: "Critical", input: "critical\n", expected: true},
+		{name: "critical_declined", severity: "Critical", input: "y\n", expected: false},
+		{name: "critical_no_input", severity: "critical", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := prompt.SetIO(strings.NewReader(tt.input), io.Discard)
+			defer restore()
+
+			o := &PostCmdOptions{Message: servicelog.Message{Severity: tt.severity}}
+			assert.Equal(t, tt.expected, o.confirmCriticalSeverity(1))
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package servicelog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultStatsWindow is the time window the service logs are aggregated over by default
+	defaultStatsWindow = 30 * 24 * time.Hour
+	// hostedClusterIDLabel is the label of the namespaces of the hosted clusters on their management cluster
+	hostedClusterIDLabel = "api.openshift.com/id"
+	// statsClusterBatchSize is the number of hosted clusters retrieved from OCM per request
+	statsClusterBatchSize = 50
+)
+
+// severityOrder is the order the severities are shown in, the most severe first
+var severityOrder = []slv1.Severity{
+	slv1.SeverityFatal,
+	slv1.SeverityError,
+	slv1.SeverityWarning,
+	slv1.SeverityInfo,
+	slv1.SeverityDebug,
+}
+
+type statsCmdOptions struct {
+	clusterID   string
+	mcID        string
+	since       time.Duration
+	allMessages bool
+	internal    bool
+	top         int
+	output      string
+
+	out    io.Writer
+	errOut io.Writer
+	now    func() time.Time
+	// listClusters returns the clusters whose service logs are aggregated, replaced in tests
+	listClusters func(ctx context.Context) ([]*cmv1.Cluster, error)
+	// listLogs returns the service logs of a cluster sent since the given time, replaced in tests
+	listLogs func(cluster *cmv1.Cluster, since time.Time) ([]*slv1.LogEntry, error)
+}
+
+// templateStats aggregates the service logs sharing a summary and a severity. Service logs don't record the template
+// they were sent from, the summary of a template is what identifies it.
+type templateStats struct {
+	Summary     string    `json:"summary"`
+	Severity    string    `json:"severity"`
+	ServiceName string    `json:"serviceName"`
+	Count       int       `json:"count"`
+	Clusters    int       `json:"clusters"`
+	LastSent    time.Time `json:"lastSent"`
+
+	clusterIDs map[string]struct{}
+}
+
+// clusterStats aggregates the service logs of a cluster
+type clusterStats struct {
+	ClusterID  string         `json:"clusterId"`
+	Name       string         `json:"name"`
+	Count      int            `json:"count"`
+	Templates  int            `json:"templates"`
+	BySeverity map[string]int `json:"bySeverity"`
+}
+
+// serviceLogStats is the aggregation of the service logs of the clusters over the time window
+type serviceLogStats struct {
+	Since      time.Time       `json:"since"`
+	Total      int             `json:"total"`
+	BySeverity map[string]int  `json:"bySeverity"`
+	Templates  []templateStats `json:"templates"`
+	Clusters   []clusterStats  `json:"clusters"`
+	// Failed are the clusters whose service logs couldn't be retrieved
+	Failed []string `json:"failed,omitempty"`
+}
+
+func newStatsCmd() *cobra.Command {
+	opts := &statsCmdOptions{out: os.Stdout, errOut: os.Stderr, now: time.Now}
+	cmd := &cobra.Command{
+		Use:   "stats (--cluster-id <cluster-identifier> | --mc <management-cluster-identifier>)",
+		Short: "Aggregate the service logs of a cluster or of the hosted clusters of a management cluster",
+		Long: `Aggregate the service logs of a cluster, or of all the hosted clusters of a management cluster, sent over a time
+window by template and severity, to identify the noisy templates and the clusters receiving the most service logs.
+
+  Service logs don't record the template they were sent from, they are grouped by summary instead, which identifies
+  the template. Like "osdctl servicelog list", only the service logs sent by SREs are counted unless --all-messages
+  is set.
+
+  The hosted clusters of the management cluster are listed from its namespaces, which requires access to it.`,
+		Example: `  # Aggregate the service logs sent by SREs to a cluster over the last 30 days
+  osdctl servicelog stats --cluster-id ${CLUSTER_ID}
+
+  # Find the noisiest templates and the 10 hosted clusters with the most service logs of a management cluster last week
+  osdctl servicelog stats --mc ${MC_ID} --all-messages --since 168h --top 10
+
+  # Output the aggregation as JSON
+  osdctl servicelog stats --cluster-id ${CLUSTER_ID} -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.validate(); err != nil {
+				return err
+			}
+
+			conn, err := utils.CreateConnection()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			opts.listClusters = func(ctx context.Context) ([]*cmv1.Cluster, error) {
+				if opts.mcID != "" {
+					return listHostedClusters(ctx, conn, opts.mcID)
+				}
+				cluster, err := utils.GetCluster(conn, opts.clusterID)
+				if err != nil {
+					return nil, err
+				}
+				return []*cmv1.Cluster{cluster}, nil
+			}
+			opts.listLogs = func(cluster *cmv1.Cluster, since time.Time) ([]*slv1.LogEntry, error) {
+				return ListClusterLogs(conn, cluster, opts.allMessages, opts.internal, since)
+			}
+			return opts.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Cluster identifier whose service logs are aggregated")
+	cmd.Flags().StringVar(&opts.mcID, "mc", "", "Management cluster identifier whose hosted clusters' service logs are aggregated")
+	cmd.Flags().DurationVar(&opts.since, "since", defaultStatsWindow, "Time window of the service logs to aggregate")
+	cmd.Flags().BoolVarP(&opts.allMessages, "all-messages", "A", false, "Aggregate all the service logs, not only the ones sent by SREs")
+	cmd.Flags().BoolVarP(&opts.internal, "internal", "i", false, "Only aggregate the internal service logs")
+	cmd.Flags().IntVar(&opts.top, "top", 0, "Only show this many templates and clusters, 0 shows all of them")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table or json")
+	cmd.MarkFlagsOneRequired("cluster-id", "mc")
+	cmd.MarkFlagsMutuallyExclusive("cluster-id", "mc")
+
+	return cmd
+}
+
+func (o *statsCmdOptions) validate() error {
+	if o.since <= 0 {
+		return errors.New("--since must be positive")
+	}
+	if o.top < 0 {
+		return errors.New("--top can't be negative")
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	return nil
+}
+
+func (o *statsCmdOptions) run(ctx context.Context) error {
+	clusters, err := o.listClusters(ctx)
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		return errors.New("no clusters found")
+	}
+
+	stats := newServiceLogStats(o.now().Add(-o.since))
+	for _, cluster := range clusters {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		entries, err := o.listLogs(cluster, stats.Since)
+		if err != nil {
+			// A single cluster can't be skipped, it's all there is to aggregate
+			if len(clusters) == 1 {
+				return fmt.Errorf("failed to fetch the service logs of cluster %s: %w", cluster.ID(), err)
+			}
+			_, _ = fmt.Fprintf(o.errOut, "Failed to fetch the service logs of cluster %s, skipping it: %v\n", cluster.ID(), err)
+			stats.Failed = append(stats.Failed, cluster.ID())
+			continue
+		}
+		stats.add(cluster, entries)
+	}
+	stats.sort()
+
+	if o.output == "json" {
+		enc := json.NewEncoder(o.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	stats.print(o.out, o.top)
+	return nil
+}
+
+func newServiceLogStats(since time.Time) *serviceLogStats {
+	return &serviceLogStats{
+		Since:      since,
+		BySeverity: map[string]int{},
+		Templates:  []templateStats{},
+		Clusters:   []clusterStats{},
+	}
+}
+
+// add aggregates the service logs of the cluster sent within the time window
+func (s *serviceLogStats) add(cluster *cmv1.Cluster, entries []*slv1.LogEntry) {
+	cs := clusterStats{ClusterID: cluster.ID(), Name: cluster.Name(), BySeverity: map[string]int{}}
+	summaries := map[string]struct{}{}
+
+	for _, entry := range entries {
+		if entry.CreatedAt().Before(s.Since) {
+			continue
+		}
+		severity := string(entry.Severity())
+		s.Total++
+		s.BySeverity[severity]++
+		cs.Count++
+		cs.BySeverity[severity]++
+		summaries[entry.Summary()] = struct{}{}
+
+		t := s.template(entry.Summary(), severity, entry.ServiceName())
+		t.Count++
+		t.clusterIDs[cluster.ID()] = struct{}{}
+		t.Clusters = len(t.clusterIDs)
+		if entry.CreatedAt().After(t.LastSent) {
+			t.LastSent = entry.CreatedAt()
+		}
+	}
+
+	cs.Templates = len(summaries)
+	s.Clusters = append(s.Clusters, cs)
+}
+
+// template returns the stats of the template, adding them when missing
+func (s *serviceLogStats) template(summary, severity, serviceName string) *templateStats {
+	for i := range s.Templates {
+		if s.Templates[i].Summary == summary && s.Templates[i].Severity == severity {
+			return &s.Templates[i]
+		}
+	}
+	s.Templates = append(s.Templates, templateStats{
+		Summary:     summary,
+		Severity:    severity,
+		ServiceName: serviceName,
+		clusterIDs:  map[string]struct{}{},
+	})
+	return &s.Templates[len(s.Templates)-1]
+}
+
+// sort orders the templates and the clusters by number of service logs, the most first
+func (s *serviceLogStats) sort() {
+	sort.SliceStable(s.Templates, func(i, j int) bool {
+		if s.Templates[i].Count != s.Templates[j].Count {
+			return s.Templates[i].Count > s.Templates[j].Count
+		}
+		return s.Templates[i].Summary < s.Templates[j].Summary
+	})
+	sort.SliceStable(s.Clusters, func(i, j int) bool {
+		if s.Clusters[i].Count != s.Clusters[j].Count {
+			return s.Clusters[i].Count > s.Clusters[j].Count
+		}
+		return s.Clusters[i].ClusterID < s.Clusters[j].ClusterID
+	})
+}
+
+// print prints the totals and the tables of the templates and, for several clusters, of the clusters. Only the top
+// templates and clusters are printed unless top is 0.
+func (s *serviceLogStats) print(w io.Writer, top int) {
+	_, _ = fmt.Fprintf(w, "%d service log(s) across %d cluster(s) since %s", s.Total, len(s.Clusters), s.Since.UTC().Format(time.RFC3339))
+	if s.Total > 0 {
+		_, _ = fmt.Fprintf(w, " (%s)", formatSeverities(s.BySeverity))
+	}
+	_, _ = fmt.Fprintln(w)
+	if len(s.Failed) > 0 {
+		_, _ = fmt.Fprintf(w, "The service logs of %d cluster(s) couldn't be fetched: %s\n", len(s.Failed), strings.Join(s.Failed, ", "))
+	}
+	if s.Total == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w)
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"SUMMARY", "SEVERITY", "SERVICE", "COUNT", "CLUSTERS", "LAST SENT"})
+	for _, t := range limit(s.Templates, top) {
+		p.AddRow([]string{t.Summary, t.Severity, t.ServiceName, strconv.Itoa(t.Count), strconv.Itoa(t.Clusters), t.LastSent.UTC().Format(time.RFC3339)})
+	}
+	_ = p.Flush()
+
+	if len(s.Clusters) < 2 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	p = printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"CLUSTER ID", "NAME", "COUNT", "TEMPLATES", "SEVERITIES"})
+	for _, c := range limit(s.Clusters, top) {
+		if c.Count == 0 {
+			break
+		}
+		p.AddRow([]string{c.ClusterID, c.Name, strconv.Itoa(c.Count), strconv.Itoa(c.Templates), formatSeverities(c.BySeverity)})
+	}
+	_ = p.Flush()
+}
+
+// limit returns the first top items, or all of them when top is 0
+func limit[T any](items []T, top int) []T {
+	if top > 0 && top < len(items) {
+		return items[:top]
+	}
+	return items
+}
+
+// formatSeverities formats the counts of the severities, the most severe first
+func formatSeverities(counts map[string]int) string {
+	severities := make([]string, 0, len(counts))
+	for severity := range counts {
+		severities = append(severities, severity)
+	}
+	slices.SortFunc(severities, func(a, b string) int {
+		ia, ib := slices.Index(severityOrder, slv1.Severity(a)), slices.Index(severityOrder, slv1.Severity(b))
+		if ia == ib {
+			return strings.Compare(a, b)
+		}
+		// Unknown severities go last
+		if ia == -1 {
+			return 1
+		}
+		if ib == -1 {
+			return -1
+		}
+		return ia - ib
+	})
+
+	parts := make([]string, 0, len(severities))
+	for _, severity := range severities {
+		parts = append(parts, fmt.Sprintf("%s: %d", severity, counts[severity]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// listHostedClusters returns the hosted clusters of the management cluster, listed from the namespaces on it
+func listHostedClusters(ctx context.Context, conn *sdk.Connection, mcID string) ([]*cmv1.Cluster, error) {
+	mc, err := utils.GetClusterAnyStatus(conn, mcID)
+	if err != nil {
+		return nil, err
+	}
+	isMC, err := utils.IsManagementCluster(mc.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify management cluster %s: %w", mc.ID(), err)
+	}
+	if !isMC {
+		return nil, fmt.Errorf("cluster %s is not a management cluster", mc.ID())
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	mcClient, err := k8s.New(mc.ID(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	nsList := &corev1.NamespaceList{}
+	if err := mcClient.List(ctx, nsList, client.HasLabels{hostedClusterIDLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list the hosted clusters of management cluster %s: %w", mc.ID(), err)
+	}
+
+	var clusters []*cmv1.Cluster
+	for batch := range slices.Chunk(hostedClusterIDs(nsList.Items), statsClusterBatchSize) {
+		batchClusters, err := utils.ApplyFilters(conn, []string{fmt.Sprintf("id in ('%s')", strings.Join(batch, "','"))})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the hosted clusters of management cluster %s from OCM: %w", mc.ID(), err)
+		}
+		clusters = append(clusters, batchClusters...)
+	}
+	return clusters, nil
+}
+
+// hostedClusterIDs returns the sorted cluster IDs of the namespaces, each hosted cluster having several
+func hostedClusterIDs(namespaces []corev1.Namespace) []string {
+	var ids []string
+	for _, ns := range namespaces {
+		if id := ns.Labels[hostedClusterIDLabel]; id != "" {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
@@ -0,0 +1,161 @@
+package servicelog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newStatsTestCluster(t *testing.T, id, name string) *cmv1.Cluster {
+	cluster, err := cmv1.NewCluster().ID(id).Name(name).Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func newStatsTestEntry(t *testing.T, summary string, severity slv1.Severity, createdAt time.Time) *slv1.LogEntry {
+	entry, err := slv1.NewLogEntry().Summary(summary).Severity(severity).ServiceName(manualActionServiceName).CreatedAt(createdAt).Build()
+	require.NoError(t, err)
+	return entry
+}
+
+func newStatsTestOptions(t *testing.T, output string, top int) (*statsCmdOptions, *bytes.Buffer, *bytes.Buffer) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	clusters := []*cmv1.Cluster{
+		newStatsTestCluster(t, "aaa111", "quiet"),
+		newStatsTestCluster(t, "bbb222", "noisy"),
+		newStatsTestCluster(t, "ccc333", "broken"),
+	}
+	logs := map[string][]*slv1.LogEntry{
+		"aaa111": {
+			newStatsTestEntry(t, "Pull secret expiring", slv1.SeverityWarning, now.Add(-48*time.Hour)),
+			// Outside of the time window
+			newStatsTestEntry(t, "Pull secret expiring", slv1.SeverityWarning, now.Add(-40*24*time.Hour)),
+		},
+		"bbb222": {
+			newStatsTestEntry(t, "Pull secret expiring", slv1.SeverityWarning, now.Add(-time.Hour)),
+			newStatsTestEntry(t, "Pull secret expiring", slv1.SeverityWarning, now.Add(-24*time.Hour)),
+			newStatsTestEntry(t, "Blocked egress", slv1.SeverityError, now.Add(-2*time.Hour)),
+			newStatsTestEntry(t, "Cluster upgraded", slv1.SeverityInfo, now.Add(-3*time.Hour)),
+		},
+	}
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	return &statsCmdOptions{
+		since:  defaultStatsWindow,
+		top:    top,
+		output: output,
+		out:    out,
+		errOut: errOut,
+		now:    func() time.Time { return now },
+		listClusters: func(context.Context) ([]*cmv1.Cluster, error) {
+			return clusters, nil
+		},
+		listLogs: func(cluster *cmv1.Cluster, since time.Time) ([]*slv1.LogEntry, error) {
+			assert.Equal(t, now.Add(-defaultStatsWindow), since)
+			if cluster.ID() == "ccc333" {
+				return nil, errors.New("forbidden")
+			}
+			return logs[cluster.ID()], nil
+		},
+	}, out, errOut
+}
+
+func TestStatsRunJSON(t *testing.T) {
+	opts, out, errOut := newStatsTestOptions(t, "json", 0)
+	require.NoError(t, opts.run(context.Background()))
+	assert.Contains(t, errOut.String(), "Failed to fetch the service logs of cluster ccc333, skipping it: forbidden")
+
+	var stats serviceLogStats
+	require.NoError(t, json.Unmarshal(out.Bytes(), &stats))
+	assert.Equal(t, 5, stats.Total)
+	assert.Equal(t, map[string]int{"Warning": 3, "Error": 1, "Info": 1}, stats.BySeverity)
+	assert.Equal(t, []string{"ccc333"}, stats.Failed)
+
+	require.Len(t, stats.Templates, 3)
+	assert.Equal(t, "Pull secret expiring", stats.Templates[0].Summary)
+	assert.Equal(t, 3, stats.Templates[0].Count)
+	assert.Equal(t, 2, stats.Templates[0].Clusters)
+	assert.Equal(t, time.Date(2026, 6, 30, 11, 0, 0, 0, time.UTC), stats.Templates[0].LastSent)
+	assert.Equal(t, "Blocked egress", stats.Templates[1].Summary)
+
+	assert.Equal(t, []clusterStats{
+		{ClusterID: "bbb222", Name: "noisy", Count: 4, Templates: 3, BySeverity: map[string]int{"Warning": 2, "Error": 1, "Info": 1}},
+		{ClusterID: "aaa111", Name: "quiet", Count: 1, Templates: 1, BySeverity: map[string]int{"Warning": 1}},
+	}, stats.Clusters)
+}
+
+func TestStatsRunTable(t *testing.T) {
+	opts, out, _ := newStatsTestOptions(t, "table", 1)
+	require.NoError(t, opts.run(context.Background()))
+
+	output := out.String()
+	assert.Contains(t, output, "5 service log(s) across 2 cluster(s) since 2026-05-31T12:00:00Z (Error: 1, Warning: 3, Info: 1)")
+	assert.Contains(t, output, "The service logs of 1 cluster(s) couldn't be fetched: ccc333")
+	assert.Contains(t, output, "Pull secret expiring")
+	assert.NotContains(t, output, "Blocked egress", "only the top template is shown")
+	assert.Contains(t, output, "bbb222")
+	assert.NotContains(t, output, "aaa111", "only the top cluster is shown")
+}
+
+func TestStatsRunSingleClusterError(t *testing.T) {
+	opts, _, _ := newStatsTestOptions(t, "table", 0)
+	opts.listClusters = func(context.Context) ([]*cmv1.Cluster, error) {
+		return []*cmv1.Cluster{newStatsTestCluster(t, "ccc333", "broken")}, nil
+	}
+	assert.EqualError(t, opts.run(context.Background()), "failed to fetch the service logs of cluster ccc333: forbidden")
+}
+
+func TestStatsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     statsCmdOptions
+		expected string
+	}{
+		{name: "valid", opts: statsCmdOptions{since: time.Hour, output: "table"}},
+		{name: "no window", opts: statsCmdOptions{output: "table"}, expected: "--since must be positive"},
+		{name: "negative top", opts: statsCmdOptions{since: time.Hour, top: -1, output: "table"}, expected: "--top can't be negative"},
+		{name: "invalid output", opts: statsCmdOptions{since: time.Hour, output: "yaml"}, expected: "invalid output format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate()
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestFormatSeverities(t *testing.T) {
+	assert.Equal(t, "Fatal: 1, Warning: 2, Debug: 4, Custom: 3", formatSeverities(map[string]int{"Debug": 4, "Custom": 3, "Warning": 2, "Fatal": 1}))
+	assert.Equal(t, "", formatSeverities(map[string]int{}))
+}
+
+func TestHostedClusterIDs(t *testing.T) {
+	namespace := func(name, id string) corev1.Namespace {
+		ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if id != "" {
+			ns.Labels = map[string]string{hostedClusterIDLabel: id}
+		}
+		return ns
+	}
+	assert.Equal(t, []string{"aaa111", "bbb222"}, hostedClusterIDs([]corev1.Namespace{
+		namespace("ocm-production-bbb222", "bbb222"),
+		namespace("ocm-production-bbb222-my-cluster", "bbb222"),
+		namespace("ocm-production-aaa111", "aaa111"),
+		namespace("hypershift", ""),
+	}))
+}
@@ -0,0 +1,121 @@
+package setup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/history"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+type doctorOptions struct {
+	commands []string
+	since    time.Duration
+
+	out io.Writer
+	// readConfig and recentCommands are replaced in tests
+	readConfig     func() (map[string]any, error)
+	recentCommands func(since time.Time) ([]string, bool, error)
+}
+
+func newCmdDoctor() *cobra.Command {
+	o := &doctorOptions{
+		out:            os.Stdout,
+		readConfig:     osdctlConfig.ReadConfigSettings,
+		recentCommands: historyCommands,
+	}
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the config file for unknown, deprecated, invalid and missing keys",
+		Long: `Check ~/.config/osdctl against the schema of its keys.
+
+Unknown keys (usually typos), deprecated keys and values which don't have the expected format are reported,
+as well as the keys missing for the commands you run. These commands are read from the command history
+(history_enabled: true in the config file) of the --since period, or given with --command.`,
+		Example: `  # Check the config file, and the keys required by the commands of the last 30 days history
+  osdctl setup doctor
+
+  # Check the keys required by specific commands
+  osdctl setup doctor --command "jira quick-task" --command "dynatrace gather-logs"`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run()
+		},
+	}
+	doctorCmd.Flags().StringArrayVar(&o.commands, "command", nil, "Command path, e.g. \"cluster cad run\", whose required keys are checked instead of the ones of the history (repeatable)")
+	doctorCmd.Flags().DurationVar(&o.since, "since", 30*24*time.Hour, "How far back the command history is read")
+	return doctorCmd
+}
+
+func (o *doctorOptions) run() error {
+	schema, err := osdctlConfig.LoadSchema()
+	if err != nil {
+		return err
+	}
+	config, err := o.readConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read the config file: %w", err)
+	}
+
+	issues := schema.Validate(config)
+
+	commands := o.commands
+	if len(commands) == 0 {
+		var enabled bool
+		commands, enabled, err = o.recentCommands(time.Now().Add(-o.since))
+		if err != nil {
+			return fmt.Errorf("failed to read the command history: %w", err)
+		}
+		if !enabled {
+			_, _ = fmt.Fprintln(o.out, "The command history is disabled, set history_enabled: true in the config file or pass --command to check the keys required by the commands you run.")
+		}
+	}
+	issues = append(issues, schema.Missing(config, commands)...)
+
+	if len(issues) == 0 {
+		_, _ = fmt.Fprintf(o.out, "No issues found in ~/.config/%s\n", osdctlConfig.ConfigFileName)
+		return nil
+	}
+
+	p := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	p.AddRow([]string{"KEY", "ISSUE", "DETAILS"})
+	for _, issue := range issues {
+		p.AddRow([]string{issue.Key, string(issue.Kind), issue.Message})
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+	return fmt.Errorf("found %d issues in ~/.config/%s", len(issues), osdctlConfig.ConfigFileName)
+}
+
+// historyCommands returns the distinct commands of the history since the time, and whether the history is enabled
+func historyCommands(since time.Time) ([]string, bool, error) {
+	if !history.Enabled() {
+		return nil, false, nil
+	}
+	path, err := history.Path()
+	if err != nil {
+		return nil, true, err
+	}
+	entries, err := history.Load(path, history.Filter{Since: since})
+	if err != nil {
+		return nil, true, err
+	}
+
+	seen := map[string]bool{}
+	var commands []string
+	for _, entry := range entries {
+		if !seen[entry.Command] {
+			seen[entry.Command] = true
+			commands = append(commands, entry.Command)
+		}
+	}
+	sort.Strings(commands)
+	return commands, true, nil
+}
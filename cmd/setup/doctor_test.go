@@ -0,0 +1,62 @@
+package setup
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("setup doctor", func() {
+	var (
+		out *bytes.Buffer
+		o   *doctorOptions
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		o = &doctorOptions{
+			since: 24 * time.Hour,
+			out:   out,
+			readConfig: func() (map[string]any, error) {
+				return map[string]any{
+					"aws_proxy":  "http://squid.example.com:3128",
+					"jira_token": "token",
+					"jira_tem":   "SD",
+				}, nil
+			},
+			recentCommands: func(time.Time) ([]string, bool, error) {
+				return []string{"osdctl jira quick-task", "osdctl version"}, true, nil
+			},
+		}
+	})
+
+	It("reports the unknown keys and the keys missing for the commands of the history", func() {
+		err := o.run()
+		Expect(err).To(MatchError("found 4 issues in ~/.config/osdctl"))
+		Expect(out.String()).To(MatchRegexp(`jira_tem +unknown +not a known osdctl config key`))
+		Expect(out.String()).To(MatchRegexp(`jira_board_id +missing +required by osdctl jira quick-task`))
+		Expect(out.String()).To(MatchRegexp(`jira_team_label +missing`))
+		Expect(out.String()).NotTo(ContainSubstring("aws_proxy"))
+	})
+
+	It("checks the given commands instead of the history", func() {
+		o.commands = []string{"cluster ssh"}
+		o.recentCommands = func(time.Time) ([]string, bool, error) {
+			Fail("the history shouldn't be read")
+			return nil, false, nil
+		}
+		err := o.run()
+		Expect(err).To(MatchError("found 2 issues in ~/.config/osdctl"))
+		Expect(out.String()).To(MatchRegexp(`prod_jumprole_account_id +missing +required by cluster ssh`))
+	})
+
+	It("notes the disabled history", func() {
+		o.readConfig = func() (map[string]any, error) { return map[string]any{}, nil }
+		o.recentCommands = func(time.Time) ([]string, bool, error) { return nil, false, nil }
+		Expect(o.run()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("The command history is disabled"))
+		Expect(out.String()).To(ContainSubstring("No issues found in ~/.config/osdctl"))
+	})
+})
@@ -167,6 +167,7 @@ func NewCmdSetup() *cobra.Command {
 			return nil
 		},
 	}
+	setupCmd.AddCommand(newCmdDoctor())
 	return setupCmd
 }
 
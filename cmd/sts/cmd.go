@@ -0,0 +1,19 @@
+package sts
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdSts implements the sts command, utilities for the AWS assume-role chains of SREs and the STS roles of clusters
+func NewCmdSts() *cobra.Command {
+	stsCmd := &cobra.Command{
+		Use:               "sts",
+		Short:             "Debug the AWS assume-role chains and the STS roles of clusters",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+
+	stsCmd.AddCommand(newCmdTrace(), newCmdRefreshRolePolicies())
+
+	return stsCmd
+}
@@ -0,0 +1,357 @@
+package sts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/danger"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	policyUpToDate = "UP TO DATE"
+	policyDrifted  = "DRIFTED"
+	policySkipped  = "SKIPPED"
+	policyUpdated  = "UPDATED"
+
+	// maxPolicyVersions is the number of versions IAM keeps for a managed policy
+	maxPolicyVersions = 5
+)
+
+// refreshRolePoliciesOptions defines the struct for running the sts refresh-role-policies command
+type refreshRolePoliciesOptions struct {
+	clusterID string
+	profile   string
+	apply     bool
+
+	out     io.Writer
+	cluster *cmv1.Cluster
+	// expectedPolicies are the operator role policy documents served by OCM, by policy ID
+	expectedPolicies map[string]string
+	awsClient        aws.Client
+	// confirm asks for the confirmation of the policy updates, replaced in tests
+	confirm func(cluster *cmv1.Cluster) (bool, error)
+}
+
+// rolePolicy is the policy attached to an operator role, compared to its expected document
+type rolePolicy struct {
+	Role      string
+	PolicyARN string
+	Status    string
+	Details   string
+	current   string
+	expected  string
+}
+
+func newCmdRefreshRolePolicies() *cobra.Command {
+	ops := &refreshRolePoliciesOptions{
+		out:     os.Stdout,
+		confirm: func(cluster *cmv1.Cluster) (bool, error) { return danger.Confirm(danger.High, cluster) },
+	}
+	refreshCmd := &cobra.Command{
+		Use:   "refresh-role-policies --cluster-id <cluster-id>",
+		Short: "Compare the operator role policies of an STS cluster to the expected ones and update the drifted ones",
+		Long: `Compare the operator role policies of an STS cluster to the expected ones and update the drifted ones
+
+  The default version of the customer managed policy attached to each operator role of the cluster is compared to
+  the operator role policy OCM serves for it at the minor OpenShift version of the cluster, the policy rosa creates
+  the operator roles with, and the differences are shown as a diff. AWS managed policies, used by the operator
+  roles of HCP clusters, can't drift and are skipped.
+
+  With --apply, the expected documents are set as the new default version of the drifted policies after
+  confirmation. IAM keeps up to 5 versions of a policy: the oldest non-default version is deleted first when a
+  policy already has 5 versions.`,
+		Example: `  # Show the drift of the operator role policies of a cluster
+  osdctl sts refresh-role-policies --cluster-id ${CLUSTER_ID}
+
+  # Update the drifted operator role policies of a cluster
+  osdctl sts refresh-role-policies --cluster-id ${CLUSTER_ID} --profile rhcontrol --apply`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	refreshCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	refreshCmd.Flags().StringVarP(&ops.profile, "profile", "p", "", "AWS profile used to access the cluster's account")
+	refreshCmd.Flags().BoolVar(&ops.apply, "apply", false, "Update the drifted policies to their expected documents after confirmation")
+	_ = refreshCmd.MarkFlagRequired("cluster-id")
+
+	return danger.Annotate(refreshCmd, danger.High)
+}
+
+func (o *refreshRolePoliciesOptions) complete() error {
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	o.cluster, err = utils.GetClusterAnyStatus(conn, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if o.cluster.AWS().STS().RoleARN() == "" {
+		return fmt.Errorf("cluster %s is not an STS cluster", o.cluster.ID())
+	}
+
+	search, err := operatorPoliciesSearch(o.cluster)
+	if err != nil {
+		return err
+	}
+	policies, err := utils.NewOCMPaginator(func(page, size int) ([]*cmv1.AWSSTSPolicy, error) {
+		response, err := conn.ClustersMgmt().V1().AWSInquiries().STSPolicies().List().
+			Search(search).Page(page).Size(size).Send()
+		if err != nil {
+			return nil, err
+		}
+		return response.Items().Slice(), nil
+	}).All()
+	if err != nil {
+		return fmt.Errorf("failed to get the expected operator role policies: %w", err)
+	}
+	o.expectedPolicies = map[string]string{}
+	for _, policy := range policies {
+		o.expectedPolicies[policy.ID()] = policy.Details()
+	}
+
+	o.awsClient, err = osdCloud.GenerateAWSClientForCluster(o.profile, o.cluster.ID())
+	return err
+}
+
+// operatorPoliciesSearch returns the search of the operator role policies of the minor OpenShift version of the
+// cluster, the permissions of the operators changing between versions
+func operatorPoliciesSearch(cluster *cmv1.Cluster) (string, error) {
+	version, err := semver.NewVersion(cluster.OpenshiftVersion())
+	if err != nil {
+		return "", fmt.Errorf("invalid OpenShift version %q of cluster %s: %w", cluster.OpenshiftVersion(), cluster.ID(), err)
+	}
+	return fmt.Sprintf("policy_type = 'OperatorRole' and openshift_version = '%d.%d'", version.Major(), version.Minor()), nil
+}
+
+func (o *refreshRolePoliciesOptions) run() error {
+	var policies []*rolePolicy
+	for _, role := range o.cluster.AWS().STS().OperatorIAMRoles() {
+		rolePolicies, err := o.compare(role)
+		if err != nil {
+			return fmt.Errorf("failed to compare the policies of role %s: %w", role.RoleARN(), err)
+		}
+		policies = append(policies, rolePolicies...)
+	}
+
+	drifted := 0
+	for _, policy := range policies {
+		if policy.Status != policyDrifted {
+			continue
+		}
+		drifted++
+		if _, err := printer.PrintDiff(o.out, policy.PolicyARN+" (current)", policy.PolicyARN+" (expected)",
+			policy.current, policy.expected, printer.DefaultDiffContext); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(o.out)
+	}
+
+	if drifted > 0 && o.apply {
+		if ok, err := o.confirm(o.cluster); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("aborting the update of the operator role policies")
+		}
+		for _, policy := range policies {
+			if policy.Status != policyDrifted {
+				continue
+			}
+			if err := o.update(policy); err != nil {
+				return fmt.Errorf("failed to update policy %s: %w", policy.PolicyARN, err)
+			}
+			policy.Status = policyUpdated
+		}
+	}
+
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"ROLE", "POLICY", "STATUS", "DETAILS"})
+	for _, policy := range policies {
+		table.AddRow([]string{policy.Role, policy.PolicyARN, policy.Status, policy.Details})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if drifted > 0 && !o.apply {
+		_, _ = fmt.Fprintf(o.out, "\n%d operator role policies of cluster %s drifted, rerun with --apply to update them\n", drifted, o.cluster.ID())
+	}
+	return nil
+}
+
+// compare compares the customer managed policies attached to an operator role to the expected policy of the role
+func (o *refreshRolePoliciesOptions) compare(role *cmv1.OperatorIAMRole) ([]*rolePolicy, error) {
+	roleARN, err := arn.Parse(role.RoleARN())
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s/%s", role.Namespace(), role.Name())
+
+	var attached []string
+	input := &iam.ListAttachedRolePoliciesInput{RoleName: awsSdk.String(path.Base(roleARN.Resource))}
+	for {
+		output, err := o.awsClient.ListAttachedRolePolicies(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range output.AttachedPolicies {
+			attached = append(attached, awsSdk.ToString(policy.PolicyArn))
+		}
+		if !output.IsTruncated {
+			break
+		}
+		input.Marker = output.Marker
+	}
+	if len(attached) == 0 {
+		return []*rolePolicy{{Role: name, PolicyARN: "-", Status: policySkipped, Details: "no policy attached to the role"}}, nil
+	}
+
+	policyID := operatorPolicyID(role)
+	expectedDocument, found := o.expectedPolicies[policyID]
+
+	var policies []*rolePolicy
+	for _, policyARN := range attached {
+		policy := &rolePolicy{Role: name, PolicyARN: policyARN}
+		policies = append(policies, policy)
+
+		parsed, err := arn.Parse(policyARN)
+		if err != nil {
+			return nil, err
+		}
+		if parsed.AccountID == "aws" {
+			policy.Status, policy.Details = policySkipped, "AWS managed policy"
+			continue
+		}
+		if !found {
+			policy.Status, policy.Details = policySkipped, fmt.Sprintf("no expected policy %s", policyID)
+			continue
+		}
+
+		current, err := o.currentDocument(policyARN)
+		if err != nil {
+			return nil, err
+		}
+		if policy.current, err = formatPolicyDocument(current); err != nil {
+			return nil, fmt.Errorf("invalid document of policy %s: %w", policyARN, err)
+		}
+		expected := strings.ReplaceAll(expectedDocument, "%{partition}", roleARN.Partition)
+		if policy.expected, err = formatPolicyDocument(expected); err != nil {
+			return nil, fmt.Errorf("invalid expected policy %s: %w", policyID, err)
+		}
+
+		if policy.current == policy.expected {
+			policy.Status = policyUpToDate
+		} else {
+			policy.Status, policy.Details = policyDrifted, fmt.Sprintf("differs from %s", policyID)
+		}
+	}
+	return policies, nil
+}
+
+// currentDocument returns the document of the default version of a policy
+func (o *refreshRolePoliciesOptions) currentDocument(policyARN string) (string, error) {
+	policy, err := o.awsClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: awsSdk.String(policyARN)})
+	if err != nil {
+		return "", err
+	}
+	version, err := o.awsClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+		PolicyArn: awsSdk.String(policyARN),
+		VersionId: policy.Policy.DefaultVersionId,
+	})
+	if err != nil {
+		return "", err
+	}
+	// IAM returns the policy documents URL encoded
+	return url.QueryUnescape(awsSdk.ToString(version.PolicyVersion.Document))
+}
+
+// update sets the expected document as the default version of a drifted policy, deleting its oldest non-default
+// version when the policy already has the maximum number of versions
+func (o *refreshRolePoliciesOptions) update(policy *rolePolicy) error {
+	versions, err := o.awsClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{PolicyArn: awsSdk.String(policy.PolicyARN)})
+	if err != nil {
+		return err
+	}
+	if len(versions.Versions) >= maxPolicyVersions {
+		var oldest *iamtypes.PolicyVersion
+		for i, version := range versions.Versions {
+			if version.IsDefaultVersion {
+				continue
+			}
+			if oldest == nil || version.CreateDate.Before(awsSdk.ToTime(oldest.CreateDate)) {
+				oldest = &versions.Versions[i]
+			}
+		}
+		if oldest != nil {
+			if _, err := o.awsClient.DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+				PolicyArn: awsSdk.String(policy.PolicyARN),
+				VersionId: oldest.VersionId,
+			}); err != nil {
+				return fmt.Errorf("failed to delete the oldest version %s: %w", awsSdk.ToString(oldest.VersionId), err)
+			}
+		}
+	}
+
+	// Compact the document, IAM limits the size of the policies
+	var document interface{}
+	if err := json.Unmarshal([]byte(policy.expected), &document); err != nil {
+		return err
+	}
+	compact, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	_, err = o.awsClient.CreatePolicyVersion(&iam.CreatePolicyVersionInput{
+		PolicyArn:      awsSdk.String(policy.PolicyARN),
+		PolicyDocument: awsSdk.String(string(compact)),
+		SetAsDefault:   true,
+	})
+	return err
+}
+
+// operatorPolicyID is the ID of the policy OCM serves for an operator role, e.g.
+// openshift_ingress_operator_cloud_credentials_policy
+func operatorPolicyID(role *cmv1.OperatorIAMRole) string {
+	return strings.ReplaceAll(fmt.Sprintf("%s_%s_policy", role.Namespace(), role.Name()), "-", "_")
+}
+
+// formatPolicyDocument indents a policy document with sorted keys, so that two documents can be diffed line by line
+func formatPolicyDocument(document string) (string, error) {
+	var policy interface{}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return "", err
+	}
+	formatted, err := json.MarshalIndent(policy, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
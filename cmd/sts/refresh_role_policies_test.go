@@ -0,0 +1,150 @@
+package sts
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	ingressRoleARN   = "arn:aws:iam::333333333333:role/mycluster-openshift-ingress-operator-cloud-credentials"
+	ingressPolicyARN = "arn:aws:iam::333333333333:policy/mycluster-openshift-ingress-operator-cloud-credentials"
+	ingressPolicy    = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["route53:ChangeResourceRecordSets","tag:GetResources"],"Resource":"arn:%{partition}:route53:::hostedzone/*"}]}`
+	driftedPolicy    = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["route53:ChangeResourceRecordSets"],"Resource":"arn:aws:route53:::hostedzone/*"}]}`
+)
+
+func newRefreshOptions(t *testing.T, awsClient *mock.MockClient, out *bytes.Buffer) *refreshRolePoliciesOptions {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID("abc123").Name("mycluster").AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+		RoleARN("arn:aws:iam::333333333333:role/ManagedOpenShift-Installer-Role").
+		OperatorIAMRoles(
+			cmv1.NewOperatorIAMRole().Namespace("openshift-ingress-operator").Name("cloud-credentials").RoleARN(ingressRoleARN),
+			cmv1.NewOperatorIAMRole().Namespace("openshift-image-registry").Name("installer-cloud-credentials").
+				RoleARN("arn:aws:iam::333333333333:role/mycluster-openshift-image-registry-installer-cloud-credentials"),
+		))).
+		Build()
+	require.NoError(t, err)
+	return &refreshRolePoliciesOptions{
+		out:              out,
+		cluster:          cluster,
+		expectedPolicies: map[string]string{"openshift_ingress_operator_cloud_credentials_policy": ingressPolicy},
+		awsClient:        awsClient,
+		confirm:          func(*cmv1.Cluster) (bool, error) { return true, nil },
+	}
+}
+
+func expectAttachedPolicies(awsClient *mock.MockClient, document string) {
+	awsClient.EXPECT().ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: awsSdk.String("mycluster-openshift-ingress-operator-cloud-credentials"),
+	}).Return(&iam.ListAttachedRolePoliciesOutput{
+		AttachedPolicies: []iamtypes.AttachedPolicy{{PolicyArn: awsSdk.String(ingressPolicyARN)}},
+	}, nil)
+	awsClient.EXPECT().ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: awsSdk.String("mycluster-openshift-image-registry-installer-cloud-credentials"),
+	}).Return(&iam.ListAttachedRolePoliciesOutput{
+		AttachedPolicies: []iamtypes.AttachedPolicy{{PolicyArn: awsSdk.String("arn:aws:iam::aws:policy/service-role/ROSAImageRegistryOperatorPolicy")}},
+	}, nil)
+	awsClient.EXPECT().GetPolicy(gomock.Any()).Return(&iam.GetPolicyOutput{
+		Policy: &iamtypes.Policy{DefaultVersionId: awsSdk.String("v2")},
+	}, nil)
+	awsClient.EXPECT().GetPolicyVersion(&iam.GetPolicyVersionInput{
+		PolicyArn: awsSdk.String(ingressPolicyARN),
+		VersionId: awsSdk.String("v2"),
+	}).Return(&iam.GetPolicyVersionOutput{
+		PolicyVersion: &iamtypes.PolicyVersion{Document: awsSdk.String(url.QueryEscape(document))},
+	}, nil)
+}
+
+func TestRefreshRolePoliciesUpToDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	awsClient := mock.NewMockClient(ctrl)
+	out := &bytes.Buffer{}
+	o := newRefreshOptions(t, awsClient, out)
+	o.apply = true
+
+	// Same document with a different key order and the partition substituted
+	expectAttachedPolicies(awsClient, `{"Statement":[{"Resource":"arn:aws:route53:::hostedzone/*","Effect":"Allow","Action":["route53:ChangeResourceRecordSets","tag:GetResources"]}],"Version":"2012-10-17"}`)
+
+	require.NoError(t, o.run())
+	assert.Regexp(t, `cloud-credentials +UP TO DATE`, out.String())
+	assert.Regexp(t, `ROSAImageRegistryOperatorPolicy +SKIPPED +AWS managed policy`, out.String())
+	assert.NotContains(t, out.String(), "---")
+}
+
+func TestRefreshRolePoliciesDrifted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	awsClient := mock.NewMockClient(ctrl)
+	out := &bytes.Buffer{}
+	o := newRefreshOptions(t, awsClient, out)
+
+	expectAttachedPolicies(awsClient, driftedPolicy)
+
+	require.NoError(t, o.run())
+	assert.Contains(t, out.String(), `+        "tag:GetResources"`)
+	assert.Contains(t, out.String(), "DRIFTED")
+	assert.Contains(t, out.String(), "1 operator role policies of cluster abc123 drifted, rerun with --apply to update them")
+}
+
+func TestRefreshRolePoliciesApply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	awsClient := mock.NewMockClient(ctrl)
+	out := &bytes.Buffer{}
+	o := newRefreshOptions(t, awsClient, out)
+	o.apply = true
+
+	expectAttachedPolicies(awsClient, driftedPolicy)
+
+	now := time.Now()
+	var versions []iamtypes.PolicyVersion
+	for i := 1; i <= maxPolicyVersions; i++ {
+		versions = append(versions, iamtypes.PolicyVersion{
+			VersionId:        awsSdk.String(fmt.Sprintf("v%d", i)),
+			IsDefaultVersion: i == 2,
+			CreateDate:       awsSdk.Time(now.Add(time.Duration(i) * time.Hour)),
+		})
+	}
+	awsClient.EXPECT().ListPolicyVersions(gomock.Any()).Return(&iam.ListPolicyVersionsOutput{Versions: versions}, nil)
+	awsClient.EXPECT().DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+		PolicyArn: awsSdk.String(ingressPolicyARN),
+		VersionId: awsSdk.String("v1"),
+	}).Return(&iam.DeletePolicyVersionOutput{}, nil)
+	awsClient.EXPECT().CreatePolicyVersion(&iam.CreatePolicyVersionInput{
+		PolicyArn:      awsSdk.String(ingressPolicyARN),
+		PolicyDocument: awsSdk.String(`{"Statement":[{"Action":["route53:ChangeResourceRecordSets","tag:GetResources"],"Effect":"Allow","Resource":"arn:aws:route53:::hostedzone/*"}],"Version":"2012-10-17"}`),
+		SetAsDefault:   true,
+	}).Return(&iam.CreatePolicyVersionOutput{}, nil)
+
+	require.NoError(t, o.run())
+	assert.Contains(t, out.String(), "UPDATED")
+	assert.NotContains(t, out.String(), "rerun with --apply")
+}
+
+func TestOperatorPoliciesSearch(t *testing.T) {
+	cluster, err := cmv1.NewCluster().ID("abc123").OpenshiftVersion("4.15.3").Build()
+	require.NoError(t, err)
+	search, err := operatorPoliciesSearch(cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "policy_type = 'OperatorRole' and openshift_version = '4.15'", search)
+
+	cluster, err = cmv1.NewCluster().ID("abc123").Build()
+	require.NoError(t, err)
+	_, err = operatorPoliciesSearch(cluster)
+	assert.ErrorContains(t, err, "invalid OpenShift version")
+}
+
+func TestOperatorPolicyID(t *testing.T) {
+	role, err := cmv1.NewOperatorIAMRole().Namespace("openshift-cluster-csi-drivers").Name("ebs-cloud-credentials").Build()
+	require.NoError(t, err)
+	assert.Equal(t, "openshift_cluster_csi_drivers_ebs_cloud_credentials_policy", operatorPolicyID(role))
+}
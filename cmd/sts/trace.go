@@ -0,0 +1,353 @@
+package sts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	hopOK      = "OK"
+	hopFailed  = "FAILED"
+	hopSkipped = "SKIPPED"
+)
+
+// traceOptions defines the struct for running the sts trace command
+type traceOptions struct {
+	clusterID string
+	profile   string
+	output    string
+	verbose   bool
+
+	out     io.Writer
+	cluster *cmv1.Cluster
+	// supportRoleARN is the role of the cluster's account the chain of a CCS cluster ends with
+	supportRoleARN string
+	// jumpAccountID is the account of the jump role of the OCM environment, empty when it isn't configured
+	jumpAccountID string
+	jumpRoleKey   string
+	// accountID is the AWS account of a non-CCS cluster
+	accountID string
+	// baseClient uses the credentials of the AWS profile, the start of the chain
+	baseClient aws.Client
+	// newClient builds the client of a hop from the credentials of the previous one, replaced in tests
+	newClient func(creds *stsTypes.Credentials, region string) (aws.Client, error)
+}
+
+// traceHop is one role assumption of the chain
+type traceHop struct {
+	Step    int    `json:"step"`
+	Role    string `json:"role"`
+	Status  string `json:"status"`
+	Details string `json:"details,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+	// Error is the decoded AWS error, only set with --verbose
+	Error *stsErrorDetails `json:"error,omitempty"`
+}
+
+// stsErrorDetails is what an STS error response carries, printed with --verbose
+type stsErrorDetails struct {
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message,omitempty"`
+	HTTPStatus int    `json:"httpStatus,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+	Raw        string `json:"raw"`
+}
+
+func newCmdTrace() *cobra.Command {
+	ops := &traceOptions{
+		out:       os.Stdout,
+		newClient: newClientFromCredentials,
+	}
+
+	traceCmd := &cobra.Command{
+		Use:   "trace --cluster-id <cluster-id>",
+		Short: "Walk the assume-role chain to the AWS account of a cluster and report the hop that fails",
+		Long: `Walk the assume-role chain to the AWS account of a cluster and report the hop that fails
+
+  The chain is assumed one role at a time, the same way osdctl builds its AWS clients:
+
+  - CCS clusters: the AWS profile assumes RH-SRE-CCS-Access, then the RH-Technical-Support-Access jump role of the
+    OCM environment (configured by the prod_jumprole_account_id or stage_jumprole_account_id key), then the
+    support role of the cluster's account.
+  - non-CCS clusters: the AWS profile assumes OrganizationAccountAccessRole of the cluster's account.
+
+  The STS error of the failing hop is decoded into its likely cause: expired or invalid credentials, a trust
+  policy that doesn't allow the previous hop or requires an external ID, or a service control policy denial.
+  The hops after the failing one are skipped.`,
+		Example: `  # Trace the assume-role chain of a cluster
+  osdctl sts trace --cluster-id ${CLUSTER_ID}
+
+  # Trace with a given AWS profile and print the raw STS errors and their request IDs
+  osdctl sts trace --cluster-id ${CLUSTER_ID} --profile rhcontrol --verbose`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	traceCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	traceCmd.Flags().StringVarP(&ops.profile, "profile", "p", "", "AWS profile the chain starts from, defaults to the AWS_PROFILE environment variable or the default profile")
+	traceCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Output format: table or json")
+	traceCmd.Flags().BoolVarP(&ops.verbose, "verbose", "v", false, "Print the raw STS errors with their code, HTTP status and request ID")
+	_ = traceCmd.MarkFlagRequired("cluster-id")
+
+	return traceCmd
+}
+
+func (o *traceOptions) complete() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format %q, expected table or json", o.output)
+	}
+	if err := utils.ValidateClusterKey("cluster-id", o.clusterID); err != nil {
+		return err
+	}
+
+	conn, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	o.cluster, err = utils.GetClusterAnyStatus(conn, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if o.cluster.CloudProvider().ID() != "aws" {
+		return fmt.Errorf("cluster %s is not an AWS cluster", o.cluster.ID())
+	}
+
+	if o.cluster.CCS().Enabled() {
+		o.supportRoleARN, err = utils.GetSupportRoleArnForCluster(conn, o.cluster.ID())
+		if err != nil {
+			return fmt.Errorf("failed to get the support role of cluster %s: %w", o.cluster.ID(), err)
+		}
+		o.jumpRoleKey = osdCloud.ProdJumproleConfigKey
+		if env := utils.GetCurrentOCMEnv(conn); env == "stage" || env == "integration" {
+			o.jumpRoleKey = osdCloud.StageJumproleConfigKey
+		}
+		o.jumpAccountID = viper.GetString(o.jumpRoleKey)
+	} else {
+		o.accountID, err = utils.GetAWSAccountIdForCluster(conn, o.cluster.ID())
+		if err != nil {
+			return fmt.Errorf("failed to get the AWS account of cluster %s: %w", o.cluster.ID(), err)
+		}
+	}
+
+	o.baseClient, err = aws.NewAwsClient(o.profile, o.cluster.Region().ID(), "")
+	return err
+}
+
+func (o *traceOptions) run() error {
+	hops := o.trace()
+	if err := o.printHops(hops); err != nil {
+		return err
+	}
+	for _, hop := range hops {
+		if hop.Status == hopFailed {
+			return fmt.Errorf("the assume-role chain of cluster %s fails at step %d (%s)", o.cluster.ID(), hop.Step, hop.Role)
+		}
+	}
+	return nil
+}
+
+// trace assumes the roles of the chain in order, and stops at the first failing hop
+func (o *traceOptions) trace() []traceHop {
+	region := o.cluster.Region().ID()
+	var hops []traceHop
+
+	identity, err := o.baseClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	caller := traceHop{Step: 1, Role: "caller identity"}
+	if err != nil {
+		o.fail(&caller, err)
+		return append(hops, caller)
+	}
+	callerARN, err := arn.Parse(awsSdk.ToString(identity.Arn))
+	if err != nil {
+		o.fail(&caller, err)
+		return append(hops, caller)
+	}
+	caller.Status, caller.Details = hopOK, callerARN.String()
+	hops = append(hops, caller)
+
+	sessionName := "RH-SRE-" + callerARN.Resource[strings.LastIndex(callerARN.Resource, "/")+1:]
+
+	// The steps mirror the AssumeRole requests of osdCloud.GenerateSupportRoleCredentials, no external ID is sent
+	type step struct {
+		role string
+		// skip is why the hop can't be tried, e.g. a missing configuration
+		skip string
+	}
+	var steps []step
+	if o.cluster.CCS().Enabled() {
+		jump := step{role: aws.GenerateRoleARNForPartition(callerARN.Partition, o.jumpAccountID, osdCloud.RhTechnicalSupportAccess)}
+		if o.jumpAccountID == "" {
+			jump = step{role: osdCloud.RhTechnicalSupportAccess, skip: fmt.Sprintf("key %s is not set in the osdctl config file", o.jumpRoleKey)}
+		}
+		support := step{role: o.supportRoleARN}
+		if supportARN, err := arn.Parse(o.supportRoleARN); err == nil {
+			supportARN.Partition = callerARN.Partition
+			support.role = supportARN.String()
+		}
+		steps = append(steps,
+			step{role: aws.GenerateRoleARNForPartition(callerARN.Partition, callerARN.AccountID, osdCloud.RhSreCcsAccessRolename)},
+			jump,
+			support,
+		)
+	} else {
+		steps = append(steps, step{role: aws.GenerateRoleARNForPartition(callerARN.Partition, o.accountID, osdCloud.OrganizationAccountAccessRole)})
+	}
+
+	client := o.baseClient
+	failed := false
+	for i, s := range steps {
+		hop := traceHop{Step: i + 2, Role: s.role}
+		switch {
+		case failed:
+			hop.Status, hop.Details = hopSkipped, "a previous hop failed"
+		case s.skip != "":
+			failed = true
+			hop.Status, hop.Details = hopFailed, s.skip
+		default:
+			output, err := client.AssumeRole(&sts.AssumeRoleInput{RoleArn: awsSdk.String(s.role), RoleSessionName: awsSdk.String(sessionName)})
+			if err == nil && output.Credentials != nil {
+				client, err = o.newClient(output.Credentials, region)
+			}
+			if err != nil {
+				failed = true
+				o.fail(&hop, err)
+				break
+			}
+			hop.Status = hopOK
+			hop.Details = fmt.Sprintf("assumed as %s", awsSdk.ToString(output.AssumedRoleUser.Arn))
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// fail records the decoded error of a hop
+func (o *traceOptions) fail(hop *traceHop, err error) {
+	hop.Status = hopFailed
+	hop.Details, hop.Hint = decodeSTSError(err)
+	if o.verbose {
+		hop.Error = errorDetails(err)
+	}
+}
+
+// decodeSTSError returns the likely cause of an STS error and how to remediate it
+func decodeSTSError(err error) (string, string) {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err.Error(), ""
+	}
+
+	message := apiErr.ErrorMessage()
+	switch apiErr.ErrorCode() {
+	case "ExpiredToken", "ExpiredTokenException", "TokenRefreshRequired":
+		return "the session token of the credentials has expired",
+			"refresh the credentials of the AWS profile and retry"
+	case "InvalidClientTokenId", "UnrecognizedClientException", "SignatureDoesNotMatch", "IncompleteSignature":
+		return "the access key of the credentials is invalid or has been deactivated",
+			"check the AWS profile used, and rotate its access key if it was deactivated"
+	case "RegionDisabledException":
+		return "STS is not activated in the region of the cluster",
+			"the STS endpoint of the region must be activated in the account settings of IAM"
+	case "AccessDenied", "AccessDeniedException":
+		switch {
+		case strings.Contains(message, "service control policy"):
+			return "denied by a service control policy (SCP) of the account's organization",
+				"an SCP explicitly denies sts:AssumeRole, the organization's administrator must exempt the role"
+		case strings.Contains(message, "identity-based policy"):
+			return "denied by a policy of the previous hop's identity",
+				"a policy attached to the previous role or user explicitly denies sts:AssumeRole on this role"
+		case strings.Contains(message, "permissions boundary"):
+			return "denied by the permissions boundary of the previous hop's identity",
+				"the permissions boundary of the previous role or user doesn't allow sts:AssumeRole on this role"
+		case strings.Contains(message, "session policy"):
+			return "denied by the session policy of the previous hop", ""
+		}
+		return "not authorized to assume the role",
+			"the role doesn't exist, or its trust policy doesn't trust the previous hop or requires an external ID (sts:ExternalId condition), which the SRE chain doesn't send"
+	}
+
+	if strings.Contains(strings.ToLower(message), "expired") {
+		return fmt.Sprintf("%s: %s", apiErr.ErrorCode(), message), "the credentials or the trust of the role have expired"
+	}
+	return fmt.Sprintf("%s: %s", apiErr.ErrorCode(), message), utils.RemediationHint(err)
+}
+
+// errorDetails returns the code, HTTP status and request ID of an AWS error
+func errorDetails(err error) *stsErrorDetails {
+	details := &stsErrorDetails{Raw: err.Error()}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		details.Code, details.Message = apiErr.ErrorCode(), apiErr.ErrorMessage()
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		details.HTTPStatus = respErr.HTTPStatusCode()
+		details.RequestID = respErr.ServiceRequestID()
+	}
+	return details
+}
+
+func (o *traceOptions) printHops(hops []traceHop) error {
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(hops)
+	}
+
+	table := printer.NewTablePrinter(o.out, 20, 1, 3, ' ')
+	table.AddRow([]string{"STEP", "ROLE", "STATUS", "DETAILS"})
+	for _, hop := range hops {
+		table.AddRow([]string{fmt.Sprint(hop.Step), hop.Role, hop.Status, hop.Details})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	for _, hop := range hops {
+		if hop.Status != hopFailed {
+			continue
+		}
+		if hop.Hint != "" {
+			_, _ = fmt.Fprintf(o.out, "\nStep %d failed: %s\n", hop.Step, hop.Hint)
+		}
+		if hop.Error != nil {
+			_, _ = fmt.Fprintf(o.out, "\nError code:  %s\nHTTP status: %d\nRequest ID:  %s\nRaw error:   %s\n",
+				hop.Error.Code, hop.Error.HTTPStatus, hop.Error.RequestID, hop.Error.Raw)
+		}
+	}
+	return nil
+}
+
+func newClientFromCredentials(creds *stsTypes.Credentials, region string) (aws.Client, error) {
+	return aws.NewAwsClientWithInput(&aws.ClientInput{
+		AccessKeyID:     awsSdk.ToString(creds.AccessKeyId),
+		SecretAccessKey: awsSdk.ToString(creds.SecretAccessKey),
+		SessionToken:    awsSdk.ToString(creds.SessionToken),
+		Region:          region,
+	})
+}
@@ -0,0 +1,197 @@
+package sts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	callerARN  = "arn:aws:iam::111111111111:user/jdoe"
+	supportARN = "arn:aws:iam::333333333333:role/ManagedOpenShift-Support-Role"
+)
+
+func newTraceCluster(t *testing.T, ccs bool, externalID string) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID("abc123").
+		Region(cmv1.NewCloudRegion().ID("us-east-1")).
+		CCS(cmv1.NewCCS().Enabled(ccs)).
+		AWS(cmv1.NewAWS().STS(cmv1.NewSTS().ExternalID(externalID))).
+		Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func assumed(role string) *sts.AssumeRoleOutput {
+	return &sts.AssumeRoleOutput{
+		AssumedRoleUser: &stsTypes.AssumedRoleUser{Arn: awsSdk.String(role + "/RH-SRE-jdoe")},
+		Credentials:     &stsTypes.Credentials{AccessKeyId: awsSdk.String("key")},
+	}
+}
+
+func TestTraceCCS(t *testing.T) {
+	tests := []struct {
+		name          string
+		jumpAccountID string
+		externalID    string
+		supportErr    error
+		expected      []string
+		expectErr     string
+		expectHint    string
+	}{
+		{
+			name:          "chain works",
+			jumpAccountID: "222222222222",
+			expected:      []string{hopOK, hopOK, hopOK, hopOK},
+		},
+		{
+			name:          "external ID of the cluster is not sent",
+			jumpAccountID: "222222222222",
+			externalID:    "ext-id",
+			expected:      []string{hopOK, hopOK, hopOK, hopOK},
+		},
+		{
+			name:          "SCP denial",
+			jumpAccountID: "222222222222",
+			supportErr: &smithy.GenericAPIError{Code: "AccessDenied", Message: "User: arn:aws:sts::222222222222:assumed-role/RH-Technical-Support-Access/RH-SRE-jdoe " +
+				"is not authorized to perform: sts:AssumeRole on resource: " + supportARN + " with an explicit deny in a service control policy"},
+			expected:   []string{hopOK, hopOK, hopOK, hopFailed},
+			expectErr:  "the assume-role chain of cluster abc123 fails at step 4 (" + supportARN + ")",
+			expectHint: "organization's administrator must exempt the role",
+		},
+		{
+			name:          "external ID required",
+			jumpAccountID: "222222222222",
+			supportErr:    &smithy.GenericAPIError{Code: "AccessDenied", Message: "is not authorized to perform: sts:AssumeRole on resource: " + supportARN},
+			expected:      []string{hopOK, hopOK, hopOK, hopFailed},
+			expectErr:     "fails at step 4",
+			expectHint:    "requires an external ID",
+		},
+		{
+			name:      "jump role not configured",
+			expected:  []string{hopOK, hopOK, hopFailed, hopSkipped},
+			expectErr: "fails at step 3 (RH-Technical-Support-Access)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			base, ccsAccess, jump := mock.NewMockClient(ctrl), mock.NewMockClient(ctrl), mock.NewMockClient(ctrl)
+
+			base.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Arn: awsSdk.String(callerARN)}, nil)
+			base.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+				assert.Equal(t, "arn:aws:iam::111111111111:role/RH-SRE-CCS-Access", *input.RoleArn)
+				assert.Equal(t, "RH-SRE-jdoe", *input.RoleSessionName)
+				return assumed(*input.RoleArn), nil
+			})
+			if tt.jumpAccountID != "" {
+				ccsAccess.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+					assert.Equal(t, "arn:aws:iam::222222222222:role/RH-Technical-Support-Access", *input.RoleArn)
+					return assumed(*input.RoleArn), nil
+				})
+				jump.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+					assert.Equal(t, supportARN, *input.RoleArn)
+					assert.Nil(t, input.ExternalId)
+					if tt.supportErr != nil {
+						return nil, tt.supportErr
+					}
+					return assumed(*input.RoleArn), nil
+				})
+			}
+
+			clients := []aws.Client{ccsAccess, jump, mock.NewMockClient(ctrl)}
+			out := &bytes.Buffer{}
+			opts := &traceOptions{
+				output:         "json",
+				out:            out,
+				cluster:        newTraceCluster(t, true, tt.externalID),
+				supportRoleARN: supportARN,
+				jumpAccountID:  tt.jumpAccountID,
+				jumpRoleKey:    "prod_jumprole_account_id",
+				baseClient:     base,
+				newClient: func(_ *stsTypes.Credentials, region string) (aws.Client, error) {
+					assert.Equal(t, "us-east-1", region)
+					client := clients[0]
+					clients = clients[1:]
+					return client, nil
+				},
+			}
+
+			err := opts.run()
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var hops []traceHop
+			require.NoError(t, json.Unmarshal(out.Bytes(), &hops))
+			var statuses []string
+			for _, hop := range hops {
+				statuses = append(statuses, hop.Status)
+			}
+			assert.Equal(t, tt.expected, statuses)
+			if tt.expectHint != "" {
+				assert.Contains(t, hops[len(hops)-1].Hint, tt.expectHint)
+			}
+		})
+	}
+}
+
+func TestTraceNonCCS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	base := mock.NewMockClient(ctrl)
+	base.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Arn: awsSdk.String(callerARN)}, nil)
+	base.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+		assert.Equal(t, "arn:aws:iam::444444444444:role/OrganizationAccountAccessRole", *input.RoleArn)
+		return nil, &smithy.GenericAPIError{Code: "ExpiredToken", Message: "The security token included in the request is expired"}
+	})
+
+	out := &bytes.Buffer{}
+	opts := &traceOptions{
+		output:     "table",
+		verbose:    true,
+		out:        out,
+		cluster:    newTraceCluster(t, false, ""),
+		accountID:  "444444444444",
+		baseClient: base,
+	}
+	require.ErrorContains(t, opts.run(), "fails at step 2")
+	assert.Contains(t, out.String(), "the session token of the credentials has expired")
+	assert.Contains(t, out.String(), "Step 2 failed: refresh the credentials of the AWS profile")
+	assert.Contains(t, out.String(), "Error code:  ExpiredToken")
+}
+
+func TestDecodeSTSError(t *testing.T) {
+	reason, hint := decodeSTSError(&smithy.GenericAPIError{Code: "AccessDenied", Message: "not authorized"})
+	assert.Equal(t, "not authorized to assume the role", reason)
+	assert.Contains(t, hint, "requires an external ID")
+
+	reason, _ = decodeSTSError(&smithy.GenericAPIError{Code: "AccessDenied", Message: "with an explicit deny in an identity-based policy"})
+	assert.Equal(t, "denied by a policy of the previous hop's identity", reason)
+
+	reason, hint = decodeSTSError(&smithy.GenericAPIError{Code: "InvalidClientTokenId", Message: "invalid"})
+	assert.Contains(t, reason, "invalid or has been deactivated")
+	assert.NotEmpty(t, hint)
+
+	reason, hint = decodeSTSError(&smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"})
+	assert.Equal(t, "Throttling: Rate exceeded", reason)
+	assert.Contains(t, hint, "wait a few minutes")
+
+	reason, hint = decodeSTSError(errors.New("dial tcp: i/o timeout"))
+	assert.Equal(t, "dial tcp: i/o timeout", reason)
+	assert.Empty(t, hint)
+}
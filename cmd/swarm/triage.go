@@ -0,0 +1,292 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/andygrunwald/go-jira"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
+	"github.com/openshift/osdctl/pkg/backplane"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// unknownRegion groups the incidents whose alerts don't reference a cluster known to OCM
+	unknownRegion = "unknown"
+
+	// limitedSupportSearch selects the clusters with at least one limited support reason
+	limitedSupportSearch = "status.limited_support_reason_count > 0"
+
+	// cadReportsPerCluster is the number of latest reports of a cluster searched for CAD findings
+	cadReportsPerCluster = 10
+)
+
+// cadFinding is a CAD investigation report of a cluster with an open incident
+type cadFinding struct {
+	ClusterID   string
+	ClusterName string
+	ReportID    string
+	Summary     string
+	CreatedAt   time.Time
+}
+
+// triageReport is the state of the fleet summarized for the swarm handoff
+type triageReport struct {
+	GeneratedAt time.Time
+	Since       time.Time
+
+	// LimitedSupport are the clusters in limited support by region
+	LimitedSupport map[string][]*cmv1.Cluster
+	// OpenIncidents is the number of open PagerDuty incidents, IncidentsByRegion counts them per region of
+	// their clusters
+	OpenIncidents     int
+	IncidentsByRegion map[string]int
+	CADFindings       []cadFinding
+	SecondaryIssues   []jira.Issue
+
+	// Errors are the sections which couldn't be collected, by section title
+	Errors map[string]error
+}
+
+type triageReportOptions struct {
+	since time.Duration
+
+	out io.Writer
+	now func() time.Time
+	// The data sources are replaced in tests
+	listLimitedSupportClusters func() ([]*cmv1.Cluster, error)
+	getClusters                func(identifiers []string) ([]*cmv1.Cluster, error)
+	listOpenIncidents          func() ([]pd.Incident, error)
+	incidentClusterIDs         func(incidentID string) ([]string, error)
+	listReports                func(clusterID string) (*backplaneapi.ListReports, error)
+	searchIssues               func(jql string) ([]jira.Issue, error)
+}
+
+func newCmdTriage() *cobra.Command {
+	triageCmd := &cobra.Command{
+		Use:               "triage",
+		Short:             "Summarize the state of the fleet for the swarm",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	triageCmd.AddCommand(newCmdTriageReport())
+	return triageCmd
+}
+
+func newCmdTriageReport() *cobra.Command {
+	o := &triageReportOptions{
+		out: os.Stdout,
+		now: time.Now,
+	}
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate the markdown summary of a shift for the handoff channel",
+		Long: `Generate a markdown summary of the shift to post in the handoff channel, with:
+  - the clusters currently in limited support, by region (OCM)
+  - the open PagerDuty incidents of the team_ids of the config file, by region of their clusters
+  - the CAD investigation reports written during the shift for the clusters of these incidents (backplane-api)
+  - the unassigned issues of the secondary swarm queue (Jira)
+
+A section which can't be collected is reported as such, the other sections are still generated.`,
+		Example: `  # Summarize the last 12 hours
+  osdctl swarm triage report
+
+  # Summarize the last day into a file
+  osdctl swarm triage report --since 24h > handoff.md`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ocmClient, err := utils.CreateConnection()
+			if err != nil {
+				return err
+			}
+			defer ocmClient.Close()
+
+			o.listLimitedSupportClusters = func() ([]*cmv1.Cluster, error) {
+				return utils.ApplyFilters(ocmClient, []string{limitedSupportSearch})
+			}
+			o.getClusters = func(identifiers []string) ([]*cmv1.Cluster, error) {
+				queries := make([]string, 0, len(identifiers))
+				for _, identifier := range identifiers {
+					queries = append(queries, utils.GenerateQuery(identifier))
+				}
+				return utils.ApplyFilters(ocmClient, []string{strings.Join(queries, " or ")})
+			}
+			o.initPagerDuty()
+			o.listReports = func(clusterID string) (*backplaneapi.ListReports, error) {
+				backplaneClient, err := backplane.NewClient(clusterID)
+				if err != nil {
+					return nil, err
+				}
+				return backplaneClient.ListReports(context.Background(), cadReportsPerCluster)
+			}
+			o.searchIssues = func(jql string) ([]jira.Issue, error) {
+				jiraClient, err := utils.NewJiraClient("")
+				if err != nil {
+					return nil, err
+				}
+				return jiraClient.SearchIssues(jql)
+			}
+
+			return o.run()
+		},
+	}
+	reportCmd.Flags().DurationVar(&o.since, "since", 12*time.Hour, "Length of the shift the CAD findings are reported for")
+	return reportCmd
+}
+
+// initPagerDuty sets the PagerDuty data sources, which fail with the error of the client when it can't be built
+func (o *triageReportOptions) initPagerDuty() {
+	pdClient, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		WithTeamIdList(viper.GetStringSlice(pagerduty.PagerDutyTeamIDsKey)).
+		Init()
+	if err != nil {
+		o.listOpenIncidents = func() ([]pd.Incident, error) { return nil, err }
+		o.incidentClusterIDs = func(string) ([]string, error) { return nil, err }
+		return
+	}
+	o.listOpenIncidents = pdClient.GetOpenIncidents
+	o.incidentClusterIDs = pdClient.GetClusterIDsForIncident
+}
+
+func (o *triageReportOptions) run() error {
+	if o.since <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+	report := o.collect()
+	return renderTriageReport(o.out, report)
+}
+
+// collect gathers the sections of the report, recording the error of a section instead of failing the report
+func (o *triageReportOptions) collect() *triageReport {
+	now := o.now()
+	report := &triageReport{
+		GeneratedAt:       now,
+		Since:             now.Add(-o.since),
+		LimitedSupport:    map[string][]*cmv1.Cluster{},
+		IncidentsByRegion: map[string]int{},
+		Errors:            map[string]error{},
+	}
+
+	clusters, err := o.listLimitedSupportClusters()
+	if err != nil {
+		report.Errors[limitedSupportTitle] = err
+	}
+	for _, cluster := range clusters {
+		region := cluster.Region().ID()
+		report.LimitedSupport[region] = append(report.LimitedSupport[region], cluster)
+	}
+
+	incidentClusters, err := o.collectIncidents(report)
+	if err != nil {
+		report.Errors[incidentsTitle] = err
+	}
+
+	report.CADFindings, err = o.collectCADFindings(incidentClusters, report.Since)
+	if err != nil {
+		report.Errors[cadFindingsTitle] = err
+	}
+
+	report.SecondaryIssues, err = o.searchIssues(buildJQL())
+	if err != nil {
+		report.Errors[secondaryTitle] = err
+	}
+	return report
+}
+
+// collectIncidents counts the open incidents per region of their clusters, and returns these clusters
+func (o *triageReportOptions) collectIncidents(report *triageReport) ([]*cmv1.Cluster, error) {
+	incidents, err := o.listOpenIncidents()
+	if err != nil {
+		return nil, err
+	}
+	report.OpenIncidents = len(incidents)
+
+	incidentIdentifiers := make(map[string][]string, len(incidents))
+	var identifiers []string
+	for _, incident := range incidents {
+		ids, err := o.incidentClusterIDs(incident.ID)
+		if err != nil {
+			return nil, err
+		}
+		incidentIdentifiers[incident.ID] = ids
+		identifiers = append(identifiers, ids...)
+	}
+
+	var clusters []*cmv1.Cluster
+	if len(identifiers) > 0 {
+		clusters, err = o.getClusters(identifiers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up the clusters of the incidents: %w", err)
+		}
+	}
+	byIdentifier := map[string]*cmv1.Cluster{}
+	for _, cluster := range clusters {
+		byIdentifier[cluster.ID()] = cluster
+		byIdentifier[cluster.ExternalID()] = cluster
+	}
+
+	for _, incident := range incidents {
+		// An incident counts once per region, even when several of its clusters are in the region
+		regions := map[string]bool{}
+		for _, identifier := range incidentIdentifiers[incident.ID] {
+			if cluster, ok := byIdentifier[identifier]; ok {
+				regions[cluster.Region().ID()] = true
+			}
+		}
+		if len(regions) == 0 {
+			regions[unknownRegion] = true
+		}
+		for region := range regions {
+			report.IncidentsByRegion[region]++
+		}
+	}
+	return clusters, nil
+}
+
+// collectCADFindings returns the reports written since the time for the clusters, newest first
+func (o *triageReportOptions) collectCADFindings(clusters []*cmv1.Cluster, since time.Time) ([]cadFinding, error) {
+	var findings []cadFinding
+	for _, cluster := range clusters {
+		reports, err := o.listReports(cluster.ID())
+		if err != nil {
+			return findings, fmt.Errorf("failed to list the reports of cluster %s: %w", cluster.ID(), err)
+		}
+		if reports == nil {
+			continue
+		}
+		for _, report := range reports.Reports {
+			if report.CreatedAt == nil || report.CreatedAt.Before(since) {
+				continue
+			}
+			finding := cadFinding{
+				ClusterID:   cluster.ID(),
+				ClusterName: cluster.Name(),
+				CreatedAt:   *report.CreatedAt,
+			}
+			if report.ReportId != nil {
+				finding.ReportID = *report.ReportId
+			}
+			if report.Summary != nil {
+				finding.Summary = *report.Summary
+			}
+			findings = append(findings, finding)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].CreatedAt.After(findings[j].CreatedAt)
+	})
+	return findings, nil
+}
@@ -0,0 +1,104 @@
+package swarm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+const (
+	limitedSupportTitle = "Limited support"
+	incidentsTitle      = "PagerDuty incidents"
+	cadFindingsTitle    = "CAD findings"
+	secondaryTitle      = "Secondary queue"
+
+	triageTimeFormat = "2006-01-02 15:04 MST"
+)
+
+// renderTriageReport writes the report as markdown for the handoff channel
+func renderTriageReport(w io.Writer, report *triageReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Swarm triage report %s\n\n", report.GeneratedAt.UTC().Format(triageTimeFormat))
+	fmt.Fprintf(&b, "_Shift since %s_\n", report.Since.UTC().Format(triageTimeFormat))
+
+	clusterCount := 0
+	for _, clusters := range report.LimitedSupport {
+		clusterCount += len(clusters)
+	}
+	writeSection(&b, fmt.Sprintf("%s (%d clusters)", limitedSupportTitle, clusterCount), report.Errors[limitedSupportTitle])
+	if clusterCount > 0 {
+		b.WriteString("| Region | Count | Clusters |\n|---|---|---|\n")
+		for _, region := range sortedRegions(report.LimitedSupport) {
+			clusters := report.LimitedSupport[region]
+			names := make([]string, 0, len(clusters))
+			for _, cluster := range clusters {
+				names = append(names, fmt.Sprintf("%s (`%s`)", oneLine(cluster.Name()), cluster.ID()))
+			}
+			sort.Strings(names)
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", region, len(clusters), strings.Join(names, ", "))
+		}
+	}
+
+	writeSection(&b, fmt.Sprintf("%s (%d open)", incidentsTitle, report.OpenIncidents), report.Errors[incidentsTitle])
+	if len(report.IncidentsByRegion) > 0 {
+		b.WriteString("| Region | Open incidents |\n|---|---|\n")
+		for _, region := range sortedRegions(report.IncidentsByRegion) {
+			fmt.Fprintf(&b, "| %s | %d |\n", region, report.IncidentsByRegion[region])
+		}
+	}
+
+	writeSection(&b, fmt.Sprintf("%s (%d)", cadFindingsTitle, len(report.CADFindings)), report.Errors[cadFindingsTitle])
+	for _, finding := range report.CADFindings {
+		summary := finding.Summary
+		if summary == "" {
+			summary = "no summary"
+		}
+		fmt.Fprintf(&b, "- **%s** (`%s`) %s: %s\n", finding.ClusterName, finding.ClusterID, finding.CreatedAt.UTC().Format(triageTimeFormat), oneLine(summary))
+		if finding.ReportID != "" {
+			fmt.Fprintf(&b, "  `osdctl cluster reports get --cluster-id %s --report-id %s`\n", finding.ClusterID, finding.ReportID)
+		}
+	}
+
+	writeSection(&b, fmt.Sprintf("%s (%d unassigned)", secondaryTitle, len(report.SecondaryIssues)), report.Errors[secondaryTitle])
+	for _, issue := range report.SecondaryIssues {
+		summary := ""
+		if issue.Fields != nil {
+			summary = issue.Fields.Summary
+		}
+		fmt.Fprintf(&b, "- [%s](%s/browse/%s) %s\n", issue.Key, utils.JiraBaseURL, issue.Key, oneLine(summary))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeSection starts a section, noting the error it couldn't be collected with
+func writeSection(b *strings.Builder, title string, err error) {
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	if err != nil {
+		fmt.Fprintf(b, "_Failed to collect: %s_\n\n", oneLine(err.Error()))
+	}
+}
+
+// sortedRegions returns the regions sorted by name, the unknown region last
+func sortedRegions[V any](byRegion map[string]V) []string {
+	regions := make([]string, 0, len(byRegion))
+	for region := range byRegion {
+		regions = append(regions, region)
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		if (regions[i] == unknownRegion) != (regions[j] == unknownRegion) {
+			return regions[j] == unknownRegion
+		}
+		return regions[i] < regions[j]
+	})
+	return regions
+}
+
+// oneLine keeps text on a single line, so it doesn't break the markdown lists and tables
+func oneLine(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "|", `\|`)
+}
@@ -0,0 +1,131 @@
+package swarm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/andygrunwald/go-jira"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	backplaneapi "github.com/openshift/backplane-api/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func triageCluster(t *testing.T, id, externalID, name, region string) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID(id).ExternalID(externalID).Name(name).Region(cmv1.NewCloudRegion().ID(region)).Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func newTriageOptions(t *testing.T, out *bytes.Buffer, now time.Time) *triageReportOptions {
+	t.Helper()
+	east := triageCluster(t, "east1", "ext-east1", "prod-east", "us-east-1")
+	west := triageCluster(t, "west1", "ext-west1", "prod-west", "us-west-2")
+	return &triageReportOptions{
+		since: 12 * time.Hour,
+		out:   out,
+		now:   func() time.Time { return now },
+		listLimitedSupportClusters: func() ([]*cmv1.Cluster, error) {
+			return []*cmv1.Cluster{
+				west,
+				triageCluster(t, "ls2", "ext-ls2", "broken | cluster", "us-east-1"),
+				triageCluster(t, "ls1", "ext-ls1", "alpha", "us-east-1"),
+			}, nil
+		},
+		listOpenIncidents: func() ([]pd.Incident, error) {
+			return []pd.Incident{
+				{APIObject: pd.APIObject{ID: "Q1"}},
+				{APIObject: pd.APIObject{ID: "Q2"}},
+				{APIObject: pd.APIObject{ID: "Q3"}},
+				{APIObject: pd.APIObject{ID: "Q4"}},
+			}, nil
+		},
+		incidentClusterIDs: func(incidentID string) ([]string, error) {
+			return map[string][]string{
+				"Q1": {"ext-east1"},
+				"Q2": {"east1", "west1"},
+				"Q3": {"deleted-cluster"},
+			}[incidentID], nil
+		},
+		getClusters: func(identifiers []string) ([]*cmv1.Cluster, error) {
+			assert.Equal(t, []string{"ext-east1", "east1", "west1", "deleted-cluster"}, identifiers)
+			return []*cmv1.Cluster{east, west}, nil
+		},
+		listReports: func(clusterID string) (*backplaneapi.ListReports, error) {
+			if clusterID != "east1" {
+				return &backplaneapi.ListReports{}, nil
+			}
+			reports := &backplaneapi.ListReports{}
+			err := json.Unmarshal([]byte(`{"reports":[
+				{"report_id":"r-old","summary":"Previous shift","created_at":"`+now.Add(-20*time.Hour).Format(time.RFC3339)+`"},
+				{"report_id":"r-1","summary":"Egress blocked\nby firewall","created_at":"`+now.Add(-time.Hour).Format(time.RFC3339)+`"}
+			]}`), reports)
+			return reports, err
+		},
+		searchIssues: func(string) ([]jira.Issue, error) {
+			return []jira.Issue{{Key: "OHSS-1", Fields: &jira.IssueFields{Summary: "Cluster upgrade stuck"}}}, nil
+		},
+	}
+}
+
+func TestTriageReport(t *testing.T) {
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	out := &bytes.Buffer{}
+	o := newTriageOptions(t, out, now)
+
+	require.NoError(t, o.run())
+	assert.Equal(t, `# Swarm triage report 2026-10-17 08:00 UTC
+
+_Shift since 2026-10-16 20:00 UTC_
+
+## Limited support (3 clusters)
+
+| Region | Count | Clusters |
+|---|---|---|
+| us-east-1 | 2 | alpha (`+"`ls1`"+`), broken \| cluster (`+"`ls2`"+`) |
+| us-west-2 | 1 | prod-west (`+"`west1`"+`) |
+
+## PagerDuty incidents (4 open)
+
+| Region | Open incidents |
+|---|---|
+| us-east-1 | 2 |
+| us-west-2 | 1 |
+| unknown | 2 |
+
+## CAD findings (1)
+
+- **prod-east** (`+"`east1`"+`) 2026-10-17 07:00 UTC: Egress blocked by firewall
+  `+"`osdctl cluster reports get --cluster-id east1 --report-id r-1`"+`
+
+## Secondary queue (1 unassigned)
+
+- [OHSS-1](https://redhat.atlassian.net/browse/OHSS-1) Cluster upgrade stuck
+`, out.String())
+}
+
+func TestTriageReportSectionErrors(t *testing.T) {
+	out := &bytes.Buffer{}
+	o := newTriageOptions(t, out, time.Now())
+	o.listOpenIncidents = func() ([]pd.Incident, error) {
+		return nil, errors.New("Could not build PagerDuty Client - No configured tokens")
+	}
+	o.searchIssues = func(string) ([]jira.Issue, error) { return nil, errors.New("jira unavailable") }
+
+	require.NoError(t, o.run())
+	assert.Contains(t, out.String(), "## Limited support (3 clusters)")
+	assert.Contains(t, out.String(), "## PagerDuty incidents (0 open)\n\n_Failed to collect: Could not build PagerDuty Client - No configured tokens_")
+	assert.Contains(t, out.String(), "## CAD findings (0)")
+	assert.Contains(t, out.String(), "## Secondary queue (0 unassigned)\n\n_Failed to collect: jira unavailable_")
+}
+
+func TestTriageReportInvalidSince(t *testing.T) {
+	o := newTriageOptions(t, &bytes.Buffer{}, time.Now())
+	o.since = 0
+	assert.EqualError(t, o.run(), "--since must be positive")
+}
@@ -0,0 +1,325 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+const (
+	reverseVideo = "\x1b[7m"
+	bold         = "\x1b[1m"
+	resetStyle   = "\x1b[0m"
+)
+
+type viewKind int
+
+const (
+	viewPicker viewKind = iota
+	viewCluster
+)
+
+type paneKind int
+
+const (
+	paneAlerts paneKind = iota
+	paneServiceLogs
+	paneLimitedSupport
+	paneCount
+)
+
+var paneTitles = [paneCount]string{"Alerts", "Service logs", "Limited support"}
+
+// paneContent is what a pane of the cluster view shows, or the error it couldn't be loaded with
+type paneContent struct {
+	lines []string
+	err   error
+}
+
+// action is the effect of a key the event loop carries out, e.g. loading data or running a command
+type action int
+
+const (
+	actionNone action = iota
+	actionQuit
+	actionSearch
+	actionOpenCluster
+	actionRefresh
+	actionSendServiceLog
+	actionRunCAD
+)
+
+// model is the state of the UI, updated by the keys pressed and drawn by render
+type model struct {
+	view viewKind
+
+	// query is the search typed in the picker, searched the one clusters were last searched with
+	query    string
+	searched string
+	clusters []*cmv1.Cluster
+	selected int
+
+	cluster *cmv1.Cluster
+	pane    paneKind
+	panes   [paneCount]paneContent
+	scroll  int
+
+	// status is a message shown at the bottom of the screen, e.g. an error
+	status string
+	// bodyHeight is the number of lines of the body at the last render, the scroll step of the page keys
+	bodyHeight int
+}
+
+// setClusters shows the clusters found by the search of the query
+func (m *model) setClusters(query string, clusters []*cmv1.Cluster, err error) {
+	m.searched = query
+	m.clusters = clusters
+	m.selected = 0
+	switch {
+	case err != nil:
+		m.status = fmt.Sprintf("Search failed: %v", err)
+	case len(clusters) == 0:
+		m.status = fmt.Sprintf("No cluster matches '%s'", query)
+	default:
+		m.status = fmt.Sprintf("%d clusters match '%s', Enter opens the selected one", len(clusters), query)
+	}
+}
+
+// openCluster switches to the cluster view, the panes are loaded by the event loop
+func (m *model) openCluster(cluster *cmv1.Cluster) {
+	m.view = viewCluster
+	m.cluster = cluster
+	m.pane = paneAlerts
+	m.panes = [paneCount]paneContent{}
+	m.scroll = 0
+	m.status = ""
+}
+
+// handleKey updates the model with the key and returns the action to carry out
+func (m *model) handleKey(k key) action {
+	if k.Kind == keyCtrlC {
+		return actionQuit
+	}
+	if m.view == viewPicker {
+		return m.handlePickerKey(k)
+	}
+	return m.handleClusterKey(k)
+}
+
+func (m *model) handlePickerKey(k key) action {
+	switch k.Kind {
+	case keyRune:
+		m.query += string(k.Rune)
+	case keyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+		}
+	case keyUp:
+		if m.selected > 0 {
+			m.selected--
+		}
+	case keyDown:
+		if m.selected < len(m.clusters)-1 {
+			m.selected++
+		}
+	case keyEnter:
+		query := strings.TrimSpace(m.query)
+		if query == "" {
+			return actionNone
+		}
+		if query != m.searched || len(m.clusters) == 0 {
+			return actionSearch
+		}
+		m.openCluster(m.clusters[m.selected])
+		return actionOpenCluster
+	case keyEscape:
+		if m.query == "" {
+			return actionQuit
+		}
+		m.query = ""
+	}
+	return actionNone
+}
+
+func (m *model) handleClusterKey(k key) action {
+	switch k.Kind {
+	case keyEscape, keyBackspace:
+		m.view = viewPicker
+		m.status = ""
+	case keyTab, keyRight:
+		m.selectPane((m.pane + 1) % paneCount)
+	case keyLeft:
+		m.selectPane((m.pane + paneCount - 1) % paneCount)
+	case keyUp:
+		m.scrollBy(-1)
+	case keyDown:
+		m.scrollBy(1)
+	case keyPageUp:
+		m.scrollBy(-max(m.bodyHeight, 1))
+	case keyPageDown:
+		m.scrollBy(max(m.bodyHeight, 1))
+	case keyRune:
+		switch k.Rune {
+		case 'q':
+			return actionQuit
+		case 'b':
+			m.view = viewPicker
+			m.status = ""
+		case '1', '2', '3':
+			m.selectPane(paneKind(k.Rune - '1'))
+		case 'l':
+			m.selectPane((m.pane + 1) % paneCount)
+		case 'h':
+			m.selectPane((m.pane + paneCount - 1) % paneCount)
+		case 'k':
+			m.scrollBy(-1)
+		case 'j':
+			m.scrollBy(1)
+		case 'r':
+			return actionRefresh
+		case 's':
+			return actionSendServiceLog
+		case 'c':
+			return actionRunCAD
+		}
+	}
+	return actionNone
+}
+
+func (m *model) selectPane(pane paneKind) {
+	m.pane = pane
+	m.scroll = 0
+}
+
+// scrollBy scrolls the pane, until its last line is at the bottom of the body
+func (m *model) scrollBy(lines int) {
+	m.scroll = max(0, min(m.scroll+lines, len(m.panes[m.pane].lines)-max(m.bodyHeight, 1)))
+}
+
+// render draws the model for a terminal of the size
+func (m *model) render(width, height int) []string {
+	var header, body []string
+	var hints string
+	if m.view == viewPicker {
+		header = []string{
+			styled(bold, fit(" osdctl ui - select a cluster", width)),
+			fit(fmt.Sprintf(" Search (name, ID or external ID, %% wildcards): %s_", m.query), width),
+			"",
+		}
+		body = m.renderClusters(width)
+		hints = " Enter search/open  ↑↓ select  Esc clear/quit  Ctrl-C quit"
+	} else {
+		header = []string{
+			styled(bold, fit(" osdctl ui - "+describeCluster(m.cluster), width)),
+			m.renderTabs(width),
+			"",
+		}
+		hints = " ←→/Tab/1-3 panes  ↑↓/PgUp/PgDn scroll  r refresh  s send service log  c run CAD  b back  q quit"
+	}
+
+	footer := []string{fit(" "+m.status, width), styled(reverseVideo, fit(hints, width))}
+	m.bodyHeight = max(height-len(header)-len(footer), 0)
+	if m.view == viewCluster {
+		body = m.renderPane(width)
+	}
+
+	lines := append(header, windowLines(body, m.bodyHeight, m.visibleOffset(len(body)))...)
+	for len(lines) < height-len(footer) {
+		lines = append(lines, "")
+	}
+	return append(lines, footer...)
+}
+
+// visibleOffset is the first line of the body shown, keeping the selected cluster or the scroll position visible
+func (m *model) visibleOffset(bodyLines int) int {
+	if m.bodyHeight == 0 {
+		return 0
+	}
+	if m.view == viewPicker {
+		return max(0, m.selected-m.bodyHeight+1)
+	}
+	return max(0, min(m.scroll, bodyLines-m.bodyHeight))
+}
+
+func (m *model) renderClusters(width int) []string {
+	lines := make([]string, 0, len(m.clusters))
+	for i, cluster := range m.clusters {
+		line := fit(fmt.Sprintf("   %-30s %-32s %-14s %-12s %s", cluster.Name(), cluster.ID(), cluster.Region().ID(), cluster.State(), cluster.Version().RawID()), width)
+		if i == m.selected {
+			line = styled(reverseVideo, fit(" >"+line[2:], width))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (m *model) renderTabs(width int) string {
+	var tabs strings.Builder
+	plain := 0
+	for pane := paneKind(0); pane < paneCount; pane++ {
+		label := fmt.Sprintf(" %d %s", pane+1, paneTitles[pane])
+		if content := m.panes[pane]; content.err != nil {
+			label += " (!)"
+		} else if content.lines != nil {
+			label += fmt.Sprintf(" (%d)", countItems(content.lines))
+		}
+		label += " "
+		plain += len([]rune(label)) + 1
+		if pane == m.pane {
+			label = styled(reverseVideo, label)
+		}
+		tabs.WriteString(" " + label)
+	}
+	return tabs.String() + strings.Repeat(" ", max(width-plain, 0))
+}
+
+func (m *model) renderPane(width int) []string {
+	content := m.panes[m.pane]
+	if content.err != nil {
+		return []string{fit(fmt.Sprintf(" Failed to load the %s: %v", strings.ToLower(paneTitles[m.pane]), content.err), width)}
+	}
+	if content.lines == nil {
+		return []string{fit(" Loading...", width)}
+	}
+	if len(content.lines) == 0 {
+		return []string{fit(fmt.Sprintf(" No %s", strings.ToLower(paneTitles[m.pane])), width)}
+	}
+	lines := make([]string, 0, len(content.lines))
+	for _, line := range content.lines {
+		lines = append(lines, fit(" "+line, width))
+	}
+	return lines
+}
+
+// windowLines returns the height lines of the body from the offset
+func windowLines(body []string, height, offset int) []string {
+	if offset >= len(body) {
+		return nil
+	}
+	return body[offset:min(offset+height, len(body))]
+}
+
+// countItems counts the lines of a pane which aren't the indented details of an item
+func countItems(lines []string) int {
+	count := 0
+	for _, line := range lines {
+		if !strings.HasPrefix(line, " ") {
+			count++
+		}
+	}
+	return count
+}
+
+func describeCluster(cluster *cmv1.Cluster) string {
+	description := fmt.Sprintf("%s (%s)  %s  %s %s  %s",
+		cluster.Name(), cluster.ID(), cluster.State(), cluster.CloudProvider().ID(), cluster.Region().ID(), cluster.Version().RawID())
+	if count := cluster.Status().LimitedSupportReasonCount(); count > 0 {
+		description += fmt.Sprintf("  LIMITED SUPPORT (%d)", count)
+	}
+	return description
+}
+
+func styled(style, line string) string {
+	return style + line + resetStyle
+}
@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uiCluster(t *testing.T, id, name string, limitedSupportReasons int) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID(id).Name(name).State(cmv1.ClusterStateReady).
+		CloudProvider(cmv1.NewCloudProvider().ID("aws")).
+		Region(cmv1.NewCloudRegion().ID("us-east-1")).
+		Version(cmv1.NewVersion().RawID("4.17.3")).
+		Status(cmv1.NewClusterStatus().LimitedSupportReasonCount(limitedSupportReasons)).
+		Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func runes(text string) []key {
+	keys := make([]key, 0, len(text))
+	for _, r := range text {
+		keys = append(keys, key{Kind: keyRune, Rune: r})
+	}
+	return keys
+}
+
+func TestPickerKeys(t *testing.T) {
+	m := &model{}
+	assert.Equal(t, actionNone, m.handleKey(key{Kind: keyEnter}), "an empty query isn't searched")
+
+	for _, k := range runes("prodx") {
+		assert.Equal(t, actionNone, m.handleKey(k))
+	}
+	m.handleKey(key{Kind: keyBackspace})
+	assert.Equal(t, "prod", m.query)
+	assert.Equal(t, actionSearch, m.handleKey(key{Kind: keyEnter}))
+
+	first, second := uiCluster(t, "id1", "prod-1", 0), uiCluster(t, "id2", "prod-2", 0)
+	m.setClusters("prod", []*cmv1.Cluster{first, second}, nil)
+	assert.Equal(t, "2 clusters match 'prod', Enter opens the selected one", m.status)
+
+	m.handleKey(key{Kind: keyDown})
+	m.handleKey(key{Kind: keyDown})
+	assert.Equal(t, 1, m.selected, "the selection stops at the last cluster")
+	assert.Equal(t, actionOpenCluster, m.handleKey(key{Kind: keyEnter}))
+	assert.Equal(t, viewCluster, m.view)
+	assert.Same(t, second, m.cluster)
+
+	m.handleKey(key{Kind: keyRune, Rune: 'b'})
+	assert.Equal(t, viewPicker, m.view)
+	m.handleKey(key{Kind: keyRune, Rune: '-'})
+	assert.Equal(t, actionSearch, m.handleKey(key{Kind: keyEnter}), "a changed query is searched again")
+
+	assert.Equal(t, actionNone, m.handleKey(key{Kind: keyEscape}), "escape clears the query first")
+	assert.Empty(t, m.query)
+	assert.Equal(t, actionQuit, m.handleKey(key{Kind: keyEscape}))
+}
+
+func TestPickerSearchResults(t *testing.T) {
+	m := &model{}
+	m.setClusters("nope", nil, nil)
+	assert.Equal(t, "No cluster matches 'nope'", m.status)
+	m.setClusters("prod", nil, errors.New("unauthorized"))
+	assert.Equal(t, "Search failed: unauthorized", m.status)
+}
+
+func TestClusterKeys(t *testing.T) {
+	m := &model{}
+	m.openCluster(uiCluster(t, "id1", "prod-1", 0))
+	m.panes[paneAlerts].lines = []string{"a", "b", "c"}
+	m.bodyHeight = 2
+
+	assert.Equal(t, actionNone, m.handleKey(key{Kind: keyRune, Rune: 'j'}))
+	m.handleKey(key{Kind: keyDown})
+	m.handleKey(key{Kind: keyDown})
+	assert.Equal(t, 1, m.scroll, "scrolling stops when the last line is at the bottom")
+	m.handleKey(key{Kind: keyPageUp})
+	assert.Equal(t, 0, m.scroll)
+
+	m.handleKey(key{Kind: keyRune, Rune: 'j'})
+	m.handleKey(key{Kind: keyTab})
+	assert.Equal(t, paneServiceLogs, m.pane)
+	assert.Equal(t, 0, m.scroll, "switching panes resets the scroll")
+	m.handleKey(key{Kind: keyLeft})
+	m.handleKey(key{Kind: keyLeft})
+	assert.Equal(t, paneLimitedSupport, m.pane, "the panes wrap around")
+	m.handleKey(key{Kind: keyRune, Rune: '2'})
+	assert.Equal(t, paneServiceLogs, m.pane)
+
+	assert.Equal(t, actionRefresh, m.handleKey(key{Kind: keyRune, Rune: 'r'}))
+	assert.Equal(t, actionSendServiceLog, m.handleKey(key{Kind: keyRune, Rune: 's'}))
+	assert.Equal(t, actionRunCAD, m.handleKey(key{Kind: keyRune, Rune: 'c'}))
+	assert.Equal(t, actionQuit, m.handleKey(key{Kind: keyRune, Rune: 'q'}))
+	assert.Equal(t, actionQuit, m.handleKey(key{Kind: keyCtrlC}))
+}
+
+func TestRenderPicker(t *testing.T) {
+	m := &model{query: "prod"}
+	m.setClusters("prod", []*cmv1.Cluster{uiCluster(t, "id1", "prod-1", 0), uiCluster(t, "id2", "prod-2", 0)}, nil)
+	m.handleKey(key{Kind: keyDown})
+
+	lines := m.render(100, 10)
+	require.Len(t, lines, 10)
+	assert.Contains(t, lines[1], "Search (name, ID or external ID, % wildcards): prod_")
+	assert.True(t, strings.HasPrefix(lines[3], "   prod-1"))
+	assert.Equal(t, reverseVideo, lines[4][:len(reverseVideo)], "the selected cluster is highlighted")
+	assert.Contains(t, lines[4], " > prod-2")
+	assert.Contains(t, lines[8], "2 clusters match 'prod'")
+	for _, line := range lines[2:8] {
+		if !strings.HasPrefix(line, reverseVideo) {
+			assert.LessOrEqual(t, len([]rune(line)), 100)
+		}
+	}
+}
+
+func TestRenderPickerKeepsSelectionVisible(t *testing.T) {
+	m := &model{}
+	var clusters []*cmv1.Cluster
+	for i := 0; i < 10; i++ {
+		clusters = append(clusters, uiCluster(t, "id", "cluster-"+string(rune('a'+i)), 0))
+	}
+	m.setClusters("cluster", clusters, nil)
+	for i := 0; i < 9; i++ {
+		m.handleKey(key{Kind: keyDown})
+	}
+
+	lines := m.render(80, 8)
+	// 3 header lines, 3 body lines and 2 footer lines
+	assert.Contains(t, lines[3], "cluster-h")
+	assert.Contains(t, lines[5], "> cluster-j")
+}
+
+func TestRenderCluster(t *testing.T) {
+	m := &model{}
+	m.openCluster(uiCluster(t, "id1", "prod-1", 2))
+
+	lines := m.render(120, 8)
+	assert.Contains(t, lines[0], "prod-1 (id1)  ready  aws us-east-1  4.17.3  LIMITED SUPPORT (2)")
+	assert.Contains(t, lines[3], "Loading...")
+
+	m.panes = [paneCount]paneContent{
+		{lines: []string{}},
+		{lines: []string{"2026-10-17 08:00 Info     Cluster upgraded", "    details", "2026-10-16 08:00 Info     Other", "    details"}},
+		{err: errors.New("forbidden")},
+	}
+	lines = m.render(120, 8)
+	assert.Contains(t, lines[1], " 1 Alerts (0) ")
+	assert.Contains(t, lines[1], " 2 Service logs (2) ")
+	assert.Contains(t, lines[1], " 3 Limited support (!) ")
+	assert.Contains(t, lines[3], "No alerts")
+
+	m.handleKey(key{Kind: keyRune, Rune: '3'})
+	assert.Contains(t, m.render(120, 8)[3], "Failed to load the limited support: forbidden")
+
+	m.handleKey(key{Kind: keyRune, Rune: '2'})
+	m.handleKey(key{Kind: keyPageDown})
+	lines = m.render(120, 8)
+	// The body has 3 lines, the scroll stops where the last line is at the bottom
+	assert.Equal(t, 1, m.scroll)
+	assert.Contains(t, lines[3], "    details")
+	assert.Contains(t, lines[5], "    details")
+	assert.Contains(t, lines[4], "Other")
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+// The items of a pane start at the first column, their details are indented so countItems can tell them apart
+
+func alertLines(incidents map[string][]pd.Incident) []string {
+	var all []pd.Incident
+	for _, serviceIncidents := range incidents {
+		all = append(all, serviceIncidents...)
+	}
+	if len(all) == 0 {
+		return []string{}
+	}
+	// The newest first, the PagerDuty timestamps are RFC3339 in UTC and sort as strings
+	sort.SliceStable(all, func(i, j int) bool { return all[i].CreatedAt > all[j].CreatedAt })
+
+	lines := make([]string, 0, 2*len(all))
+	for _, incident := range all {
+		lines = append(lines,
+			fmt.Sprintf("%-20s %-5s %-12s %s", incident.CreatedAt, incident.Urgency, incident.Status, singleLine(incident.Title)),
+			"    "+incident.HTMLURL,
+		)
+	}
+	return lines
+}
+
+func serviceLogLines(entries []*slv1.LogEntry) []string {
+	lines := make([]string, 0, 2*len(entries))
+	for _, entry := range entries {
+		lines = append(lines,
+			fmt.Sprintf("%-16s %-8s %s", entry.Timestamp().UTC().Format("2006-01-02 15:04"), entry.Severity(), singleLine(entry.Summary())),
+			"    "+singleLine(entry.Description()),
+		)
+	}
+	return lines
+}
+
+func limitedSupportLines(reasons []*cmv1.LimitedSupportReason) []string {
+	lines := make([]string, 0, 2*len(reasons))
+	for _, reason := range reasons {
+		lines = append(lines,
+			fmt.Sprintf("%-16s %-13s %s", reason.CreationTimestamp().UTC().Format("2006-01-02 15:04"), reason.DetectionType(), singleLine(reason.Summary())),
+			"    "+singleLine(reason.Details()),
+		)
+	}
+	return lines
+}
+
+// singleLine joins the lines of a text, a pane line must not move the cursor to another line
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
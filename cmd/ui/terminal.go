@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	clearScreen    = "\x1b[H\x1b[2J"
+)
+
+// keyKind is the kind of a key pressed
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyEnter
+	keyBackspace
+	keyEscape
+	keyTab
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPageUp
+	keyPageDown
+	keyCtrlC
+)
+
+// key is a key pressed, Rune is set for keyRune
+type key struct {
+	Kind keyKind
+	Rune rune
+}
+
+// screen is the terminal the UI is drawn on
+type screen interface {
+	// Size returns the width and height of the terminal
+	Size() (int, int)
+	// Draw replaces the content of the terminal with the lines
+	Draw(lines []string) error
+	// ReadKey waits for a key to be pressed
+	ReadKey() (key, error)
+	// Suspend gives the terminal back to the shell, e.g. to run a command, until resume is called
+	Suspend() (resume func() error, err error)
+	// Close restores the terminal
+	Close() error
+}
+
+// ttyScreen is a screen drawn on the terminal of stdin/stdout in raw mode, on the alternate screen buffer
+type ttyScreen struct {
+	fd     int
+	out    io.Writer
+	in     *bufio.Reader
+	state  *term.State
+	closed bool
+}
+
+func newTTYScreen() (*ttyScreen, error) {
+	fd := int(os.Stdin.Fd()) // #nosec G115 -- file descriptors fit into an int
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("osdctl ui requires an interactive terminal")
+	}
+	s := &ttyScreen{fd: fd, out: os.Stdout, in: bufio.NewReader(os.Stdin)}
+	if err := s.enter(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *ttyScreen) enter() error {
+	state, err := term.MakeRaw(s.fd)
+	if err != nil {
+		return fmt.Errorf("failed to set the terminal to raw mode: %w", err)
+	}
+	s.state = state
+	_, err = io.WriteString(s.out, enterAltScreen+hideCursor)
+	return err
+}
+
+func (s *ttyScreen) leave() error {
+	_, _ = io.WriteString(s.out, showCursor+exitAltScreen)
+	return term.Restore(s.fd, s.state)
+}
+
+func (s *ttyScreen) Size() (int, int) {
+	width, height, err := term.GetSize(s.fd)
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+func (s *ttyScreen) Draw(lines []string) error {
+	// Raw mode doesn't translate the line feeds
+	_, err := io.WriteString(s.out, clearScreen+strings.Join(lines, "\r\n"))
+	return err
+}
+
+func (s *ttyScreen) ReadKey() (key, error) {
+	return readKey(s.in)
+}
+
+func (s *ttyScreen) Suspend() (func() error, error) {
+	if err := s.leave(); err != nil {
+		return nil, err
+	}
+	return s.enter, nil
+}
+
+func (s *ttyScreen) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.leave()
+}
+
+// readKey decodes the next key from the input of a terminal in raw mode
+func readKey(in *bufio.Reader) (key, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return key{}, err
+	}
+	switch r {
+	case '\r', '\n':
+		return key{Kind: keyEnter}, nil
+	case 127, '\b':
+		return key{Kind: keyBackspace}, nil
+	case '\t':
+		return key{Kind: keyTab}, nil
+	case 3:
+		return key{Kind: keyCtrlC}, nil
+	case 0x1b:
+		return readEscapeSequence(in)
+	}
+	return key{Kind: keyRune, Rune: r}, nil
+}
+
+// readEscapeSequence decodes the CSI sequences of the arrow and page keys, a lone escape is the escape key
+func readEscapeSequence(in *bufio.Reader) (key, error) {
+	if in.Buffered() == 0 {
+		return key{Kind: keyEscape}, nil
+	}
+	next, _, err := in.ReadRune()
+	if err != nil || next != '[' {
+		return key{Kind: keyEscape}, nil
+	}
+	code, _, err := in.ReadRune()
+	if err != nil {
+		return key{Kind: keyEscape}, nil
+	}
+	switch code {
+	case 'A':
+		return key{Kind: keyUp}, nil
+	case 'B':
+		return key{Kind: keyDown}, nil
+	case 'C':
+		return key{Kind: keyRight}, nil
+	case 'D':
+		return key{Kind: keyLeft}, nil
+	case '5', '6':
+		// Page up and down end with a tilde
+		if tilde, _, err := in.ReadRune(); err == nil && tilde == '~' {
+			if code == '5' {
+				return key{Kind: keyPageUp}, nil
+			}
+			return key{Kind: keyPageDown}, nil
+		}
+	}
+	return key{Kind: keyEscape}, nil
+}
+
+// fit truncates or pads the line to the width, in runes
+func fit(line string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	count := utf8.RuneCountInString(line)
+	if count > width {
+		runes := []rune(line)
+		if width == 1 {
+			return "…"
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return line + strings.Repeat(" ", width-count)
+}
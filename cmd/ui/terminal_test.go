@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKey(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("aé\r\x7f\t\x03\x1b[A\x1b[B\x1b[C\x1b[D\x1b[5~\x1b[6~\x1b[Z"))
+	expected := []key{
+		{Kind: keyRune, Rune: 'a'},
+		{Kind: keyRune, Rune: 'é'},
+		{Kind: keyEnter},
+		{Kind: keyBackspace},
+		{Kind: keyTab},
+		{Kind: keyCtrlC},
+		{Kind: keyUp},
+		{Kind: keyDown},
+		{Kind: keyRight},
+		{Kind: keyLeft},
+		{Kind: keyPageUp},
+		{Kind: keyPageDown},
+		// Unsupported sequences are an escape
+		{Kind: keyEscape},
+	}
+	for _, want := range expected {
+		got, err := readKey(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := readKey(in)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReadKeyLoneEscape(t *testing.T) {
+	got, err := readKey(bufio.NewReader(strings.NewReader("\x1b")))
+	require.NoError(t, err)
+	assert.Equal(t, key{Kind: keyEscape}, got)
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		line     string
+		width    int
+		expected string
+	}{
+		{line: "abc", width: 5, expected: "abc  "},
+		{line: "abcdef", width: 4, expected: "abc…"},
+		{line: "héllo", width: 5, expected: "héllo"},
+		{line: "abc", width: 1, expected: "…"},
+		{line: "abc", width: 0, expected: ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, fit(test.line, test.width), "fit(%q, %d)", test.line, test.width)
+	}
+}
@@ -0,0 +1,319 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/cmd/cluster/cad"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// searchSize is the number of clusters listed by a search of the picker
+const searchSize = 50
+
+// cadEnvironments are the environments accepted by cluster cad run
+var cadEnvironments = []string{"production", "stage"}
+
+// errCancelled is returned by a quick action the user didn't give the inputs of
+var errCancelled = errors.New("cancelled")
+
+type uiOptions struct {
+	clusterID string
+	// environment is the OCM environment, the default environment of the CAD investigations
+	environment string
+
+	newScreen func() (screen, error)
+	// The data sources and the osdctl runner are replaced in tests
+	getCluster         func(key string) (*cmv1.Cluster, error)
+	searchClusters     func(query string) ([]*cmv1.Cluster, error)
+	listAlerts         func(cluster *cmv1.Cluster) (map[string][]pd.Incident, error)
+	listServiceLogs    func(cluster *cmv1.Cluster) ([]*slv1.LogEntry, error)
+	listLimitedSupport func(cluster *cmv1.Cluster) ([]*cmv1.LimitedSupportReason, error)
+	runOsdctl          func(args ...string) error
+}
+
+// NewCmdUI implements the ui command
+func NewCmdUI() *cobra.Command {
+	o := &uiOptions{
+		newScreen: func() (screen, error) { return newTTYScreen() },
+		runOsdctl: runOsdctl,
+	}
+	uiCmd := &cobra.Command{
+		Use:   "ui",
+		Short: "Triage clusters in an interactive terminal UI",
+		Long: `Triage clusters in an interactive terminal UI.
+
+Search a cluster by name, ID or external ID (% is a wildcard) and open it to browse its:
+  1. firing PagerDuty alerts
+  2. service logs
+  3. limited support reasons
+
+From a cluster, quick actions run the osdctl commands with the inputs they ask for:
+  s  send a service log (osdctl servicelog post)
+  c  run a CAD investigation (osdctl cluster cad run)
+
+The PagerDuty alerts need the PagerDuty tokens of the config file, see 'osdctl cluster context'.`,
+		Example: `  # Search a cluster to triage
+  osdctl ui
+
+  # Open a cluster directly
+  osdctl ui --cluster-id ${CLUSTER_ID}`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ocmClient, err := utils.CreateConnection()
+			if err != nil {
+				return err
+			}
+			defer ocmClient.Close()
+
+			o.environment = utils.GetCurrentOCMEnv(ocmClient)
+			o.getCluster = func(key string) (*cmv1.Cluster, error) {
+				return utils.GetCluster(ocmClient, key)
+			}
+			o.searchClusters = func(query string) ([]*cmv1.Cluster, error) {
+				response, err := ocmClient.ClustersMgmt().V1().Clusters().List().
+					Search(utils.GenerateQuery(query)).
+					Size(searchSize).
+					Send()
+				if err != nil {
+					return nil, utils.ClassifyError(err)
+				}
+				return response.Items().Slice(), nil
+			}
+			o.listAlerts = listAlerts
+			o.listServiceLogs = func(cluster *cmv1.Cluster) ([]*slv1.LogEntry, error) {
+				return servicelog.ListClusterLogs(ocmClient, cluster, false, false, time.Time{})
+			}
+			o.listLimitedSupport = func(cluster *cmv1.Cluster) ([]*cmv1.LimitedSupportReason, error) {
+				return utils.GetClusterLimitedSupportReasons(ocmClient, cluster.ID())
+			}
+
+			return o.run()
+		},
+	}
+	uiCmd.Flags().StringVarP(&o.clusterID, "cluster-id", "C", "", "Cluster to open, instead of searching one")
+	return uiCmd
+}
+
+// listAlerts returns the firing PagerDuty incidents of the cluster, the way 'osdctl cluster context' does
+func listAlerts(cluster *cmv1.Cluster) (map[string][]pd.Incident, error) {
+	pdClient, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		WithBaseDomain(cluster.DNS().BaseDomain()).
+		WithTeamIdList(viper.GetStringSlice(pagerduty.PagerDutyTeamIDsKey)).
+		Init()
+	if err != nil {
+		return nil, err
+	}
+	serviceIDs, err := pdClient.GetPDServiceIDs()
+	if err != nil {
+		return nil, err
+	}
+	return pdClient.GetFiringAlertsForCluster(serviceIDs)
+}
+
+// runOsdctl runs an osdctl command on the terminal
+func runOsdctl(args ...string) error {
+	osdctl, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the osdctl executable: %w", err)
+	}
+	fmt.Printf("$ osdctl %s\n", strings.Join(args, " "))
+	cmd := exec.Command(osdctl, args...) // #nosec G204 -- an osdctl subcommand of the quick actions
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (o *uiOptions) run() error {
+	m := &model{}
+	if o.clusterID != "" {
+		cluster, err := o.getCluster(o.clusterID)
+		if err != nil {
+			return err
+		}
+		m.openCluster(cluster)
+	}
+
+	s, err := o.newScreen()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if m.view == viewCluster {
+		if err := o.loadPanes(s, m); err != nil {
+			return err
+		}
+	}
+	return o.loop(s, m)
+}
+
+// loop draws the model and carries out the actions of the keys until the user quits
+func (o *uiOptions) loop(s screen, m *model) error {
+	for {
+		if err := draw(s, m); err != nil {
+			return err
+		}
+		k, err := s.ReadKey()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch m.handleKey(k) {
+		case actionQuit:
+			return nil
+		case actionSearch:
+			query := strings.TrimSpace(m.query)
+			m.status = fmt.Sprintf("Searching '%s'...", query)
+			if err := draw(s, m); err != nil {
+				return err
+			}
+			clusters, err := o.searchClusters(query)
+			m.setClusters(query, clusters, err)
+		case actionOpenCluster, actionRefresh:
+			if err := o.loadPanes(s, m); err != nil {
+				return err
+			}
+		case actionSendServiceLog:
+			if err := o.quickAction(s, m, "Service log", o.sendServiceLog); err != nil {
+				return err
+			}
+		case actionRunCAD:
+			if err := o.quickAction(s, m, "CAD investigation", o.runCAD); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func draw(s screen, m *model) error {
+	width, height := s.Size()
+	return s.Draw(m.render(width, height))
+}
+
+// loadPanes loads the panes of the cluster of the model concurrently, the error of a pane is shown in the pane
+func (o *uiOptions) loadPanes(s screen, m *model) error {
+	m.panes = [paneCount]paneContent{}
+	m.status = fmt.Sprintf("Loading the context of %s...", m.cluster.Name())
+	if err := draw(s, m); err != nil {
+		return err
+	}
+
+	var panes [paneCount]paneContent
+	var wg sync.WaitGroup
+	load := func(pane paneKind, lines func() ([]string, error)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			panes[pane].lines, panes[pane].err = lines()
+		}()
+	}
+	load(paneAlerts, func() ([]string, error) {
+		incidents, err := o.listAlerts(m.cluster)
+		return alertLines(incidents), err
+	})
+	load(paneServiceLogs, func() ([]string, error) {
+		entries, err := o.listServiceLogs(m.cluster)
+		return serviceLogLines(entries), err
+	})
+	load(paneLimitedSupport, func() ([]string, error) {
+		reasons, err := o.listLimitedSupport(m.cluster)
+		return limitedSupportLines(reasons), err
+	})
+	wg.Wait()
+
+	m.panes = panes
+	m.status = ""
+	return nil
+}
+
+// quickAction gives the terminal to the action, then reloads the panes to show its effect.
+// Only failing to restore the terminal is returned, the outcome of the action is shown in the status line.
+func (o *uiOptions) quickAction(s screen, m *model, name string, action func(cluster *cmv1.Cluster) error) error {
+	resume, err := s.Suspend()
+	if err != nil {
+		return err
+	}
+	actionErr := action(m.cluster)
+	if !errors.Is(actionErr, errCancelled) {
+		_, _ = prompt.Input("Press Enter to return to osdctl ui", "")
+	}
+	if err := resume(); err != nil {
+		return err
+	}
+
+	switch {
+	case errors.Is(actionErr, errCancelled):
+		m.status = name + " cancelled"
+	case actionErr != nil:
+		m.status = fmt.Sprintf("%s failed: %v", name, actionErr)
+	default:
+		if err := o.loadPanes(s, m); err != nil {
+			return err
+		}
+		m.status = name + " done"
+	}
+	return nil
+}
+
+// sendServiceLog asks for the template of the service log and posts it with osdctl servicelog post, which
+// asks for its parameters and a confirmation
+func (o *uiOptions) sendServiceLog(cluster *cmv1.Cluster) error {
+	fmt.Printf("Send a service log to %s (%s)\n", cluster.Name(), cluster.ID())
+	template, err := prompt.Input("Template file or URL (empty to cancel)", "")
+	if err != nil {
+		return err
+	}
+	if template = strings.TrimSpace(template); template == "" {
+		return errCancelled
+	}
+	return o.runOsdctl("servicelog", "post", "--cluster-id", cluster.ID(), "--template", template)
+}
+
+// runCAD asks for the investigation, environment and reason required by osdctl cluster cad run, and runs it
+func (o *uiOptions) runCAD(cluster *cmv1.Cluster) error {
+	fmt.Printf("Run a CAD investigation on %s (%s)\n", cluster.Name(), cluster.ID())
+	investigation, err := prompt.Select("Investigation", cad.ValidInvestigations, "")
+	if err != nil {
+		return err
+	}
+	environment := ""
+	if slices.Contains(cadEnvironments, o.environment) {
+		environment = o.environment
+	}
+	environment, err = prompt.Select("Environment", cadEnvironments, environment)
+	if err != nil {
+		return err
+	}
+	reason, err := prompt.Input("Reason, e.g. OHSS-XXXX (empty to cancel)", "")
+	if err != nil {
+		return err
+	}
+	if reason = strings.TrimSpace(reason); reason == "" {
+		return errCancelled
+	}
+	return o.runOsdctl("cluster", "cad", "run", "--cluster-id", cluster.ID(),
+		"--investigation", investigation, "--environment", environment, "--reason", reason)
+}
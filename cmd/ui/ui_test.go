@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScreen replays the keys and records the frames drawn
+type fakeScreen struct {
+	keys      []key
+	frames    [][]string
+	suspended int
+	closed    bool
+}
+
+func (s *fakeScreen) Size() (int, int) { return 120, 12 }
+
+func (s *fakeScreen) Draw(lines []string) error {
+	s.frames = append(s.frames, lines)
+	return nil
+}
+
+func (s *fakeScreen) ReadKey() (key, error) {
+	if len(s.keys) == 0 {
+		return key{}, io.EOF
+	}
+	k := s.keys[0]
+	s.keys = s.keys[1:]
+	return k, nil
+}
+
+func (s *fakeScreen) Suspend() (func() error, error) {
+	s.suspended++
+	return func() error { return nil }, nil
+}
+
+func (s *fakeScreen) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *fakeScreen) lastFrame() string {
+	return strings.Join(s.frames[len(s.frames)-1], "\n")
+}
+
+func newTestUIOptions(t *testing.T, s *fakeScreen) (*uiOptions, *[]string) {
+	t.Helper()
+	cluster := uiCluster(t, "id1", "prod-1", 1)
+	entry, err := slv1.NewLogEntry().Timestamp(time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)).
+		Severity(slv1.SeverityWarning).Summary("Action required:\nnetwork").Description("Fix the egress").Build()
+	require.NoError(t, err)
+	reason, err := cmv1.NewLimitedSupportReason().CreationTimestamp(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)).
+		DetectionType(cmv1.DetectionTypeManual).Summary("Egress blocked").Details("Allow the firewall").Build()
+	require.NoError(t, err)
+
+	var commands []string
+	return &uiOptions{
+		environment: "stage",
+		newScreen:   func() (screen, error) { return s, nil },
+		getCluster: func(key string) (*cmv1.Cluster, error) {
+			if key != "prod-1" {
+				return nil, errors.New("not found")
+			}
+			return cluster, nil
+		},
+		searchClusters: func(query string) ([]*cmv1.Cluster, error) {
+			assert.Equal(t, "prod", query)
+			return []*cmv1.Cluster{cluster}, nil
+		},
+		listAlerts: func(*cmv1.Cluster) (map[string][]pd.Incident, error) {
+			return map[string][]pd.Incident{"svc": {
+				{Title: "ClusterOperatorDown", Urgency: "high", Status: "triggered", CreatedAt: "2026-10-17T07:00:00Z", APIObject: pd.APIObject{HTMLURL: "https://pd/incidents/Q1"}},
+			}}, nil
+		},
+		listServiceLogs: func(*cmv1.Cluster) ([]*slv1.LogEntry, error) {
+			return []*slv1.LogEntry{entry}, nil
+		},
+		listLimitedSupport: func(*cmv1.Cluster) ([]*cmv1.LimitedSupportReason, error) {
+			return []*cmv1.LimitedSupportReason{reason}, nil
+		},
+		runOsdctl: func(args ...string) error {
+			commands = append(commands, strings.Join(args, " "))
+			return nil
+		},
+	}, &commands
+}
+
+func TestUISearchAndBrowse(t *testing.T) {
+	s := &fakeScreen{}
+	s.keys = append(runes("prod"), key{Kind: keyEnter}, key{Kind: keyEnter})
+	o, _ := newTestUIOptions(t, s)
+
+	require.NoError(t, o.run())
+	assert.True(t, s.closed)
+	frame := s.lastFrame()
+	assert.Contains(t, frame, "osdctl ui - prod-1 (id1)")
+	assert.Contains(t, frame, " 1 Alerts (1) ")
+	assert.Contains(t, frame, " 2 Service logs (1) ")
+	assert.Contains(t, frame, " 3 Limited support (1) ")
+	assert.Contains(t, frame, "2026-10-17T07:00:00Z high  triggered    ClusterOperatorDown")
+	assert.Contains(t, frame, "https://pd/incidents/Q1")
+
+	s.keys = []key{{Kind: keyRune, Rune: '2'}}
+	require.NoError(t, o.loop(s, &model{view: viewCluster, cluster: mustGetCluster(t, o), panes: loadedPanes(t, o)}))
+	assert.Contains(t, s.lastFrame(), "2026-10-17 08:00 Warning  Action required: network")
+	assert.Contains(t, s.lastFrame(), "    Fix the egress")
+
+	s.keys = []key{{Kind: keyRune, Rune: '3'}}
+	require.NoError(t, o.loop(s, &model{view: viewCluster, cluster: mustGetCluster(t, o), panes: loadedPanes(t, o)}))
+	assert.Contains(t, s.lastFrame(), "2026-10-16 08:00 manual        Egress blocked")
+}
+
+func mustGetCluster(t *testing.T, o *uiOptions) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := o.getCluster("prod-1")
+	require.NoError(t, err)
+	return cluster
+}
+
+func loadedPanes(t *testing.T, o *uiOptions) [paneCount]paneContent {
+	t.Helper()
+	m := &model{cluster: mustGetCluster(t, o)}
+	require.NoError(t, o.loadPanes(&fakeScreen{}, m))
+	return m.panes
+}
+
+func TestUIPaneErrors(t *testing.T) {
+	s := &fakeScreen{}
+	o, _ := newTestUIOptions(t, s)
+	o.clusterID = "prod-1"
+	o.listAlerts = func(*cmv1.Cluster) (map[string][]pd.Incident, error) {
+		return nil, errors.New("Could not build PagerDuty Client - No configured tokens")
+	}
+
+	require.NoError(t, o.run())
+	frame := s.lastFrame()
+	assert.Contains(t, frame, " 1 Alerts (!) ")
+	assert.Contains(t, frame, "Failed to load the alerts: Could not build PagerDuty Client - No configured tokens")
+	assert.Contains(t, frame, " 2 Service logs (1) ")
+}
+
+func TestUIUnknownCluster(t *testing.T) {
+	s := &fakeScreen{}
+	o, _ := newTestUIOptions(t, s)
+	o.clusterID = "nope"
+	assert.EqualError(t, o.run(), "not found")
+	assert.Empty(t, s.frames, "the screen isn't entered")
+}
+
+func TestUIQuickActions(t *testing.T) {
+	t.Run("send a service log", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("~/sl.json\n\n"), &bytes.Buffer{})()
+		s := &fakeScreen{keys: []key{{Kind: keyRune, Rune: 's'}}}
+		o, commands := newTestUIOptions(t, s)
+		o.clusterID = "prod-1"
+
+		require.NoError(t, o.run())
+		assert.Equal(t, []string{"servicelog post --cluster-id id1 --template ~/sl.json"}, *commands)
+		assert.Equal(t, 1, s.suspended)
+		assert.Contains(t, s.lastFrame(), "Service log done")
+	})
+
+	t.Run("run a CAD investigation", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("chgm\n\nOHSS-1234\n\n"), &bytes.Buffer{})()
+		s := &fakeScreen{keys: []key{{Kind: keyRune, Rune: 'c'}}}
+		o, commands := newTestUIOptions(t, s)
+		o.clusterID = "prod-1"
+
+		require.NoError(t, o.run())
+		assert.Equal(t, []string{"cluster cad run --cluster-id id1 --investigation chgm --environment stage --reason OHSS-1234"}, *commands)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("\n"), &bytes.Buffer{})()
+		s := &fakeScreen{keys: []key{{Kind: keyRune, Rune: 's'}}}
+		o, commands := newTestUIOptions(t, s)
+		o.clusterID = "prod-1"
+
+		require.NoError(t, o.run())
+		assert.Empty(t, *commands)
+		assert.Contains(t, s.lastFrame(), "Service log cancelled")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		defer prompt.SetIO(strings.NewReader("~/sl.json\n\n"), &bytes.Buffer{})()
+		s := &fakeScreen{keys: []key{{Kind: keyRune, Rune: 's'}}}
+		o, _ := newTestUIOptions(t, s)
+		o.clusterID = "prod-1"
+		o.runOsdctl = func(...string) error { return errors.New("exit status 1") }
+
+		require.NoError(t, o.run())
+		assert.Contains(t, s.lastFrame(), "Service log failed: exit status 1")
+	})
+}
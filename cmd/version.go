@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"runtime/debug"
 	"strings"
 
@@ -19,16 +20,51 @@ type versionResponse struct {
 	InstallMethod string `json:"install_method,omitempty"`
 }
 
+var (
+	versionCheckFlag   bool
+	versionChannelFlag string
+)
+
 // versionCmd is the subcommand "osdctl version" for cobra.
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Display the version",
-	Long:  "Display version of osdctl",
-	RunE:  version,
+	Long: `Display version of osdctl
+
+  With --check, the latest release of the channel is looked up, and the changelog of the releases since the
+  version of this build is printed when it is outdated. The stable channel only considers stable releases, the
+  prerelease channel also considers release candidates. The default channel is set by the version_channel key
+  of the config file.`,
+	Example: `  # Check whether osdctl is up to date and print the changes of the newer releases
+  osdctl version --check
+
+  # Also consider the release candidates
+  osdctl version --check --channel prerelease`,
+	RunE: version,
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionCheckFlag, "check", false, "Check whether this build is outdated and print the changelog of the newer releases")
+	versionCmd.Flags().StringVar(&versionChannelFlag, "channel", "", "Release channel to check against: stable or prerelease (default from the version_channel config key, or stable)")
 }
 
 // version returns the osdctl version marshalled in JSON
 func version(cmd *cobra.Command, args []string) error {
+	if versionCheckFlag {
+		channel := versionChannelFlag
+		if channel == "" {
+			channel = utils.ConfiguredChannel()
+		}
+		if err := utils.ValidateChannel(channel); err != nil {
+			return err
+		}
+		releases, err := utils.GetReleases()
+		if err != nil {
+			return fmt.Errorf("failed to look up the latest release: %w", err)
+		}
+		return printVersionCheck(cmd.OutOrStdout(), utils.Version, channel, releases)
+	}
+
 	gitCommit := "unknown"
 
 	if info, ok := debug.ReadBuildInfo(); ok {
@@ -53,3 +89,49 @@ func version(cmd *cobra.Command, args []string) error {
 	fmt.Println(string(ver))
 	return nil
 }
+
+// printVersionCheck prints whether current is the latest release of the channel, and the changelog of the newer releases
+func printVersionCheck(w io.Writer, current, channel string, releases []utils.Release) error {
+	newer, err := utils.ReleasesNewerThan(releases, current, channel)
+	if err != nil {
+		return err
+	}
+	if len(newer) == 0 {
+		_, err := fmt.Fprintf(w, "osdctl %s is up to date with the %s channel\n", current, channel)
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "osdctl %s is outdated, the latest %s release is %s\n", current, channel, strings.TrimPrefix(newer[0].TagName, "v"))
+	for _, release := range newer {
+		_, _ = fmt.Fprintf(w, "\n## %s (%s)\n\n", release.TagName, release.PublishedAt.Format("2006-01-02"))
+		body := strings.TrimSpace(strings.ReplaceAll(release.Body, "\r\n", "\n"))
+		if body == "" {
+			body = "No release notes, see " + release.HTMLURL
+		}
+		_, _ = fmt.Fprintln(w, body)
+	}
+
+	instruction, err := utils.UpgradeInstruction()
+	if err != nil || instruction == "" {
+		instruction = "osdctl upgrade"
+	}
+	_, err = fmt.Fprintf(w, "\nUpgrade with: %s\n", instruction)
+	return err
+}
+
+// warnIfOutdated prints a warning without prompting when a newer release of the configured channel exists,
+// for the automatic version check limited to once per day
+func warnIfOutdated(w io.Writer) {
+	channel := utils.ConfiguredChannel()
+	releases, err := utils.GetReleases()
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "WARN: Unable to verify that osdctl is running under the latest released version: %v\n", err)
+		return
+	}
+	newer, err := utils.ReleasesNewerThan(releases, utils.Version, channel)
+	if err != nil || len(newer) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "WARN: osdctl %s is outdated, the latest %s release is %s. Run \"osdctl version --check\" for the changelog.\n",
+		utils.Version, channel, strings.TrimPrefix(newer[0].TagName, "v"))
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintVersionCheck(t *testing.T) {
+	releases := []utils.Release{
+		{TagName: "v0.46.0", Body: "* Add version --check\r\n* Fix resize", PublishedAt: time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)},
+		{TagName: "v0.45.0", HTMLURL: "https://github.com/openshift/osdctl/releases/tag/v0.45.0", PublishedAt: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{TagName: "v0.44.0"},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printVersionCheck(out, "0.44.0", utils.ChannelStable, releases))
+	assert.Equal(t, `osdctl 0.44.0 is outdated, the latest stable release is 0.46.0
+
+## v0.46.0 (2025-07-15)
+
+* Add version --check
+* Fix resize
+
+## v0.45.0 (2025-07-01)
+
+No release notes, see https://github.com/openshift/osdctl/releases/tag/v0.45.0
+
+Upgrade with: osdctl upgrade
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, printVersionCheck(out, "0.46.0", utils.ChannelStable, releases))
+	assert.Equal(t, "osdctl 0.46.0 is up to date with the stable channel\n", out.String())
+
+	assert.Error(t, printVersionCheck(out, "", utils.ChannelStable, releases))
+}
@@ -2,6 +2,7 @@ package globalflags
 
 import (
 	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/openshift/osdctl/pkg/prompt"
 	"github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -12,6 +13,10 @@ type GlobalOptions struct {
 	SkipVersionCheck bool
 	Output           string
 	NoAwsProxy       bool
+	AssumeYes        bool
+	NonInteractive   bool
+	Trace            bool
+	VerifyTickets    bool
 	KubeFlags        genericclioptions.ConfigFlags
 }
 
@@ -32,6 +37,19 @@ func (opts *GlobalOptions) AddSkipVersionCheckFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&opts.SkipVersionCheck, "skip-version-check", "S", false, "skip checking to see if this is the most recent release")
 }
 
+func (opts *GlobalOptions) AddPromptFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&opts.AssumeYes, prompt.AssumeYesFlag, false, "Automatically answer yes to all confirmation prompts")
+	cmd.PersistentFlags().BoolVar(&opts.NonInteractive, prompt.NonInteractiveFlag, false, "Never prompt for input, use the default answer of every prompt instead. Implied when stdin is not a terminal")
+}
+
+func (opts *GlobalOptions) AddTraceFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&opts.Trace, "trace", false, "Print the request ID, HTTP status and timing of every OCM API call to stderr")
+}
+
+func (opts *GlobalOptions) AddVerifyTicketsFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&opts.VerifyTickets, "verify-tickets", false, "Warn when the OHSS issues and PagerDuty incidents referenced by --reason don't exist. Can be enabled by default with verify_tickets: true in the config file")
+}
+
 func (opts *GlobalOptions) AddOutputFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&opts.Output, "output", "o", "", "Valid formats are ['', 'json', 'yaml', 'env']")
 }
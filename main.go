@@ -1,14 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/openshift/osdctl/cmd"
+	"github.com/openshift/osdctl/pkg/history"
 	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
 func main() {
@@ -18,18 +23,41 @@ func main() {
 		fmt.Println(err)
 		return
 	}
+	osdctlConfig.WarnInvalidConfig(os.Stderr)
 
 	cobra.EnableTraverseRunHooks = true
 	command := cmd.NewCmdRoot(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
 
+	// Commands exiting through cmdutil.CheckErr don't return to main, record their outcome before exiting
+	cmdutil.BehaviorOnFatal(exitOnFatal)
+
 	resolved, err := command.ExecuteC()
+	history.Finish(err)
 	if err != nil {
 		if resolved != nil && resolved.SilenceErrors {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
+		if hint := utils.RemediationHint(err); hint != "" {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+		}
 		if resolved != nil && resolved.SilenceUsage {
 			fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", resolved.CommandPath())
 		}
 		os.Exit(1)
 	}
 }
+
+// exitOnFatal prints the message of a fatal error like cmdutil.CheckErr does by default, and records it in
+// the history before exiting
+func exitOnFatal(msg string, code int) {
+	err := fmt.Errorf("exited with code %d", code)
+	if len(msg) > 0 {
+		if !strings.HasSuffix(msg, "\n") {
+			msg += "\n"
+		}
+		fmt.Fprint(os.Stderr, msg)
+		err = errors.New(strings.TrimSpace(msg))
+	}
+	history.Finish(err)
+	os.Exit(code)
+}
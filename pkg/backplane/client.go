@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	backplaneapi "github.com/openshift/backplane-api/pkg/client"
@@ -23,6 +24,7 @@ type ManagedJobResult struct {
 type Client struct {
 	backplaneClient backplaneapi.ClientInterface
 	clusterID       string
+	url             string
 }
 
 // NewClient creates a new backplane client
@@ -40,6 +42,7 @@ func NewClient(clusterID string) (*Client, error) {
 	return &Client{
 		backplaneClient: bpclient,
 		clusterID:       clusterID,
+		url:             bp.URL,
 	}, nil
 }
 
@@ -140,6 +143,11 @@ func (c *Client) GetReport(ctx context.Context, reportID string) (*backplaneapi.
 	return output, nil
 }
 
+// ReportURL returns the backplane API URL of a report of the cluster
+func (c *Client) ReportURL(reportID string) string {
+	return fmt.Sprintf("%s/backplane/cluster/%s/reports/%s", strings.TrimSuffix(c.url, "/"), c.clusterID, reportID)
+}
+
 func (c *Client) CreateReport(ctx context.Context, summary string, data string) (*backplaneapi.Report, error) {
 	output := &backplaneapi.Report{}
 
@@ -412,3 +412,11 @@ func TestListReports(t *testing.T) {
 		})
 	}
 }
+
+func TestReportURL(t *testing.T) {
+	client := &Client{clusterID: "test-cluster", url: "https://api.backplane.example.com/"}
+	expected := "https://api.backplane.example.com/backplane/cluster/test-cluster/reports/report-1"
+	if got := client.ReportURL("report-1"); got != expected {
+		t.Errorf("ReportURL() = %v, expected %v", got, expected)
+	}
+}
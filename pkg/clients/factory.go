@@ -0,0 +1,109 @@
+package clients
+
+import (
+	"fmt"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Factory creates the OCM and Kubernetes clients of a command. Commands receive a Factory from their constructor
+// instead of creating connections themselves, so that tests can run them with a FakeFactory.
+type Factory interface {
+	// Cluster returns the cluster with the internal ID, external ID or name
+	Cluster(key string) (*cmv1.Cluster, error)
+	// Username returns the username of the OCM account osdctl is logged in with
+	Username() (string, error)
+	// KubeClient returns a client of the cluster through backplane
+	KubeClient(clusterID string, options client.Options) (client.Client, error)
+	// ElevatedKubeClient returns a client of the cluster through backplane, elevated as backplane-cluster-admin
+	ElevatedKubeClient(clusterID string, options client.Options, reason elevate.Reason) (client.Client, error)
+	// Close closes the OCM connection of the factory, a later call to the factory opens a new one
+	Close()
+}
+
+// ocmFactory creates the clients with an OCM connection, opened on first use
+type ocmFactory struct {
+	// ocmURL is the URL or alias (e.g. "production") of the OCM environment, the one of the current OCM login when empty
+	ocmURL string
+	conn   *sdk.Connection
+}
+
+// NewFactory returns a Factory of clients of the OCM environment osdctl is logged in to
+func NewFactory() Factory {
+	return &ocmFactory{}
+}
+
+// NewFactoryWithURL returns a Factory of clients of another OCM environment than the one of the current OCM login,
+// given by its URL or alias, e.g. "production"
+func NewFactoryWithURL(ocmURL string) Factory {
+	return &ocmFactory{ocmURL: ocmURL}
+}
+
+func (f *ocmFactory) connection() (*sdk.Connection, error) {
+	if f.conn != nil {
+		return f.conn, nil
+	}
+	var conn *sdk.Connection
+	var err error
+	if f.ocmURL == "" {
+		conn, err = utils.CreateConnection()
+	} else {
+		conn, err = utils.CreateConnectionWithUrl(f.ocmURL)
+	}
+	if err != nil {
+		if f.ocmURL != "" {
+			return nil, fmt.Errorf("failed to create %s OCM connection: %w", f.ocmURL, err)
+		}
+		return nil, err
+	}
+	f.conn = conn
+	return conn, nil
+}
+
+func (f *ocmFactory) Cluster(key string) (*cmv1.Cluster, error) {
+	conn, err := f.connection()
+	if err != nil {
+		return nil, err
+	}
+	return utils.GetCluster(conn, key)
+}
+
+func (f *ocmFactory) Username() (string, error) {
+	conn, err := f.connection()
+	if err != nil {
+		return "", err
+	}
+	response, err := conn.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the current OCM account: %w", err)
+	}
+	return response.Body().Username(), nil
+}
+
+func (f *ocmFactory) KubeClient(clusterID string, options client.Options) (client.Client, error) {
+	conn, err := f.connection()
+	if err != nil {
+		return nil, err
+	}
+	return k8s.NewWithConn(clusterID, options, conn)
+}
+
+func (f *ocmFactory) ElevatedKubeClient(clusterID string, options client.Options, reason elevate.Reason) (client.Client, error) {
+	conn, err := f.connection()
+	if err != nil {
+		return nil, err
+	}
+	return elevate.NewClientWithConn(clusterID, options, conn, reason)
+}
+
+func (f *ocmFactory) Close() {
+	if f.conn != nil {
+		f.conn.Close()
+		f.conn = nil
+	}
+}
@@ -0,0 +1,76 @@
+package clients
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FakeFactory is a Factory returning fixed clusters and clients, to test commands without OCM or backplane
+type FakeFactory struct {
+	// Clusters are the clusters returned by Cluster, looked up by internal ID, external ID or name
+	Clusters []*cmv1.Cluster
+	User     string
+
+	// Client and ElevatedClient are returned for every cluster, ElevatedClient defaults to Client
+	Client         client.Client
+	ElevatedClient client.Client
+	// Err is returned by every call when set
+	Err error
+
+	// KubeClusterIDs and ElevatedClusterIDs are the clusters clients were requested for, and Reasons the
+	// reasons of the elevations
+	KubeClusterIDs     []string
+	ElevatedClusterIDs []string
+	Reasons            []elevate.Reason
+	Closed             bool
+}
+
+var _ Factory = &FakeFactory{}
+
+func (f *FakeFactory) Cluster(key string) (*cmv1.Cluster, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	for _, cluster := range f.Clusters {
+		if cluster.ID() == key || cluster.ExternalID() == key || cluster.Name() == key {
+			return cluster, nil
+		}
+	}
+	return nil, &utils.ClusterNotFoundError{Key: key}
+}
+
+func (f *FakeFactory) Username() (string, error) {
+	if f.Err != nil {
+		return "", f.Err
+	}
+	return f.User, nil
+}
+
+func (f *FakeFactory) KubeClient(clusterID string, _ client.Options) (client.Client, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	f.KubeClusterIDs = append(f.KubeClusterIDs, clusterID)
+	return f.Client, nil
+}
+
+func (f *FakeFactory) ElevatedKubeClient(clusterID string, _ client.Options, reason elevate.Reason) (client.Client, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if err := reason.Validate(); err != nil {
+		return nil, err
+	}
+	f.ElevatedClusterIDs = append(f.ElevatedClusterIDs, clusterID)
+	f.Reasons = append(f.Reasons, reason)
+	if f.ElevatedClient != nil {
+		return f.ElevatedClient, nil
+	}
+	return f.Client, nil
+}
+
+func (f *FakeFactory) Close() {
+	f.Closed = true
+}
@@ -66,6 +66,10 @@ type RotateSecretInput struct {
 	// target AWS account (after all role chaining has been completed).
 	AwsClient awsprovider.Client
 
+	// Partition is the AWS partition of the target account (aws or aws-us-gov).
+	// If empty, the standard aws partition is assumed.
+	Partition string
+
 	// HiveKubeClient is the k8s client connected to the hive cluster.
 	HiveKubeClient client.Client
 
@@ -100,6 +104,10 @@ func RotateSecret(ctx context.Context, input *RotateSecretInput) error {
 	}
 
 	accountID := account.Spec.AwsAccountID
+	partition := input.Partition
+	if partition == "" {
+		partition = awsprovider.PartitionID
+	}
 
 	accountIDSuffixLabel, ok := account.Labels["iamUserId"]
 	if !ok {
@@ -112,7 +120,7 @@ func RotateSecret(ctx context.Context, input *RotateSecretInput) error {
 		adminUsername = osdManagedAdminIAM + "-" + accountIDSuffixLabel
 	}
 
-	adminUsername, err := resolveAdminUsername(input.Out, input.AwsClient, accountID, adminUsername, accountIDSuffixLabel)
+	adminUsername, err := resolveAdminUsername(input.Out, input.AwsClient, partition, accountID, adminUsername, accountIDSuffixLabel)
 	if err != nil {
 		return err
 	}
@@ -151,7 +159,7 @@ func RotateSecret(ctx context.Context, input *RotateSecretInput) error {
 		if errors.As(err, &nse) {
 			// Retry without the suffix
 			// Retry without the suffix, re-verifying permissions first.
-			if err := VerifyRotationPermissions(input.Out, input.AwsClient, accountID, osdManagedAdminIAM); err != nil {
+			if err := VerifyRotationPermissions(input.Out, input.AwsClient, partition, accountID, osdManagedAdminIAM); err != nil {
 				return err
 			}
 			adminUsername = osdManagedAdminIAM
@@ -236,7 +244,7 @@ func RotateSecret(ctx context.Context, input *RotateSecretInput) error {
 
 // VerifyRotationPermissions checks if the assumed role has the necessary IAM
 // permissions to perform secret rotation by simulating the required actions.
-func VerifyRotationPermissions(out io.Writer, awsClient awsprovider.Client, accountID string, username string) error {
+func VerifyRotationPermissions(out io.Writer, awsClient awsprovider.Client, partition string, accountID string, username string) error {
 	requiredActions := []string{
 		"iam:CreateAccessKey",
 		"iam:CreateUser",
@@ -250,7 +258,7 @@ func VerifyRotationPermissions(out io.Writer, awsClient awsprovider.Client, acco
 		"iam:TagUser",
 	}
 
-	userArn := fmt.Sprintf("arn:aws:iam::%s:user/%s", accountID, username)
+	userArn := awsprovider.GenerateUserARN(partition, accountID, username)
 
 	fmt.Fprintf(out, "Verifying IAM permissions for user %s...\n", username)
 
@@ -281,13 +289,13 @@ func VerifyRotationPermissions(out io.Writer, awsClient awsprovider.Client, acco
 // falling back to the unsuffixed osdManagedAdmin only when the suffixed user
 // has insufficient permissions. Transport or API errors are never retried
 // with a different principal.
-func resolveAdminUsername(out io.Writer, awsClient awsprovider.Client, accountID, username, suffix string) (string, error) {
-	err := VerifyRotationPermissions(out, awsClient, accountID, username)
+func resolveAdminUsername(out io.Writer, awsClient awsprovider.Client, partition, accountID, username, suffix string) (string, error) {
+	err := VerifyRotationPermissions(out, awsClient, partition, accountID, username)
 	if err != nil {
 		var permErr *InsufficientPermissionsError
 		if errors.As(err, &permErr) && username == osdManagedAdminIAM+"-"+suffix {
 			fmt.Fprintf(out, "Permission verification failed for %s, trying %s...\n", username, osdManagedAdminIAM)
-			if err := VerifyRotationPermissions(out, awsClient, accountID, osdManagedAdminIAM); err != nil {
+			if err := VerifyRotationPermissions(out, awsClient, partition, accountID, osdManagedAdminIAM); err != nil {
 				return "", err
 			}
 			return osdManagedAdminIAM, nil
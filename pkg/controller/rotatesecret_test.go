@@ -661,7 +661,7 @@ func TestVerifyRotationPermissions(t *testing.T) {
 				Times(1)
 
 			out := &bytes.Buffer{}
-			err := VerifyRotationPermissions(out, mockClient, tt.accountID, tt.username)
+			err := VerifyRotationPermissions(out, mockClient, "aws", tt.accountID, tt.username)
 
 			if tt.expectedErr {
 				assert.Error(t, err)
@@ -0,0 +1,127 @@
+// Package danger implements the confirmation policy of the operations which change or can break clusters.
+//
+// Commands declare the danger level of their operation with Annotate, and call Confirm with the same level
+// before performing it. Operations on the clusters of the block list of the config file are refused:
+//
+//	danger_blocked_clusters:
+//	  - hs-mc-*
+//	  - 1a2b3c4d5e6f7g8h9i0j
+//
+// Medium danger operations are confirmed with a yes/no prompt. High danger operations require typing the
+// name of the cluster, which --assume-yes doesn't answer.
+package danger
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/spf13/cobra"
+)
+
+// Level is the danger level of an operation
+type Level string
+
+const (
+	// Low operations are read-only or trivially reversible, they aren't confirmed
+	Low Level = "low"
+	// Medium operations change the cluster or its access in a reversible way
+	Medium Level = "medium"
+	// High operations can disrupt the cluster or lose data
+	High Level = "high"
+
+	// AnnotationKey is the cobra command annotation holding the danger level of the command
+	AnnotationKey = "osdctl.openshift.io/danger-level"
+	// BlockedClustersConfigKey is the list of the config file of the clusters dangerous operations are refused on.
+	// The entries are cluster IDs, external IDs or names, and can be glob patterns.
+	BlockedClustersConfigKey = "danger_blocked_clusters"
+)
+
+// blockedClusters returns the block list of the config file, replaced in tests
+var blockedClusters = func() []string {
+	clusters, err := osdctlConfig.GetConfigStringSlice(BlockedClustersConfigKey)
+	if err != nil {
+		return nil
+	}
+	return clusters
+}
+
+// Annotate marks the command with the danger level of its operation and describes the confirmation in its help
+func Annotate(cmd *cobra.Command, level Level) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[AnnotationKey] = string(level)
+
+	switch level {
+	case Medium:
+		cmd.Long = longOrShort(cmd) + "\n\n  Danger level: medium. The operation must be confirmed, and is refused on the clusters of the " +
+			BlockedClustersConfigKey + " config."
+	case High:
+		cmd.Long = longOrShort(cmd) + "\n\n  Danger level: high. The name of the cluster must be typed to confirm the operation, even with --" +
+			prompt.AssumeYesFlag + ", and the operation is refused on the clusters of the " + BlockedClustersConfigKey + " config."
+	}
+	return cmd
+}
+
+func longOrShort(cmd *cobra.Command) string {
+	if cmd.Long != "" {
+		return cmd.Long
+	}
+	return cmd.Short
+}
+
+// LevelOf returns the danger level the command was annotated with, Low if it wasn't
+func LevelOf(cmd *cobra.Command) Level {
+	if level, ok := cmd.Annotations[AnnotationKey]; ok {
+		return Level(level)
+	}
+	return Low
+}
+
+// Confirm applies the policy of the danger level to an operation on the cluster. It returns an error if the
+// operation is refused, and false if the user didn't confirm it.
+func Confirm(level Level, cluster *cmv1.Cluster) (bool, error) {
+	if level == Low {
+		return true, nil
+	}
+	if pattern, blocked := isBlocked(cluster); blocked {
+		return false, fmt.Errorf("%s danger operations are refused on cluster %s (%s): it matches %q of the %s config",
+			level, cluster.Name(), cluster.ID(), pattern, BlockedClustersConfigKey)
+	}
+
+	if level != High {
+		return prompt.ConfirmPrompt(), nil
+	}
+
+	if !prompt.IsInteractive() {
+		return false, errors.New("high danger operations must be confirmed by typing the name of the cluster, which isn't possible in non-interactive mode")
+	}
+	name, err := prompt.Input(fmt.Sprintf("This is a high danger operation, type the name of the cluster (%s) to continue", cluster.Name()), "")
+	if err != nil {
+		return false, err
+	}
+	if name != cluster.Name() {
+		fmt.Printf("%q doesn't match the name of the cluster\n", name)
+		return false, nil
+	}
+	return true, nil
+}
+
+// isBlocked returns the entry of the block list matching the ID, external ID or name of the cluster
+func isBlocked(cluster *cmv1.Cluster) (string, bool) {
+	for _, pattern := range blockedClusters() {
+		for _, identifier := range []string{cluster.ID(), cluster.ExternalID(), cluster.Name()} {
+			if identifier == "" {
+				continue
+			}
+			if matched, err := path.Match(pattern, identifier); pattern == identifier || (err == nil && matched) {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,84 @@
+package danger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDangerCluster(t *testing.T) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID("abc123").ExternalID("0000-1111").Name("hs-mc-abcde").Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func TestAnnotate(t *testing.T) {
+	cmd := Annotate(&cobra.Command{Use: "resize", Short: "Resize a cluster"}, High)
+	assert.Equal(t, High, LevelOf(cmd))
+	assert.Contains(t, cmd.Long, "Resize a cluster\n\n  Danger level: high.")
+
+	cmd = Annotate(&cobra.Command{Use: "post", Long: "Post"}, Medium)
+	assert.Equal(t, Medium, LevelOf(cmd))
+	assert.Contains(t, cmd.Long, "Danger level: medium.")
+
+	assert.Equal(t, Low, LevelOf(&cobra.Command{Use: "get"}))
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     Level
+		blocked   []string
+		input     string
+		assumeYes bool
+		expected  bool
+		expectErr string
+	}{
+		{name: "low isn't confirmed", level: Low, blocked: []string{"*"}, expected: true},
+		{name: "medium confirmed", level: Medium, input: "y\n", expected: true},
+		{name: "medium declined", level: Medium, input: "n\n"},
+		{name: "medium assume yes", level: Medium, assumeYes: true, expected: true},
+		{name: "high with the cluster name", level: High, input: "hs-mc-abcde\n", expected: true},
+		{name: "high with another name", level: High, input: "hs-mc-xyz\n"},
+		{name: "high ignores assume yes", level: High, input: "y\n", assumeYes: true},
+		{name: "blocked by pattern", level: Medium, blocked: []string{"hs-mc-*"}, input: "y\n", expectErr: `it matches "hs-mc-*"`},
+		{name: "blocked by external ID", level: High, blocked: []string{"other", "0000-1111"}, expectErr: "refused on cluster hs-mc-abcde (abc123)"},
+		{name: "not blocked", level: Medium, blocked: []string{"hs-mc-xyz", "def456"}, input: "y\n", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer prompt.SetIO(strings.NewReader(tt.input), &bytes.Buffer{})()
+			prompt.SetAssumeYes(tt.assumeYes)
+			defer prompt.SetAssumeYes(false)
+			previous := blockedClusters
+			blockedClusters = func() []string { return tt.blocked }
+			defer func() { blockedClusters = previous }()
+
+			ok, err := Confirm(tt.level, newDangerCluster(t))
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestConfirmHighNonInteractive(t *testing.T) {
+	defer prompt.SetIO(strings.NewReader("hs-mc-abcde\n"), &bytes.Buffer{})()
+	prompt.SetNonInteractive(true)
+	defer prompt.SetNonInteractive(false)
+
+	ok, err := Confirm(High, newDangerCluster(t))
+	require.ErrorContains(t, err, "isn't possible in non-interactive mode")
+	assert.False(t, ok)
+}
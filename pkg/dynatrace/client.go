@@ -0,0 +1,281 @@
+// Package dynatrace is a client of the Dynatrace Grail storage query and document APIs
+package dynatrace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+const (
+	authURL string = "https://sso.dynatrace.com/sso/oauth2/token"
+
+	// Logs
+	StorageVaultPathKey string = "dt_vault_path"
+	StorageScopes       string = "storage:logs:read storage:events:read storage:buckets:read"
+
+	// Dashboards
+	DocumentVaultPathKey string = "dt_document_vault_path"
+	DocumentScopes       string = "document:documents:read"
+	DashboardType        string = "dashboard"
+
+	// MaxResultRecords is the maximum number of records returned by a query.
+	// Dynatrace can't page through the results of a query, see
+	// https://community.dynatrace.com/t5/Product-ideas/Pagination-in-DQL-results/idi-p/248282#M45818
+	MaxResultRecords = 20000
+
+	stateRunning   = "RUNNING"
+	stateSucceeded = "SUCCEEDED"
+)
+
+// QueryExecutor starts the execution of DQL queries
+type QueryExecutor interface {
+	// ExecuteQuery starts the query and returns the request token to poll its results with
+	ExecuteQuery(ctx context.Context, query string) (string, error)
+}
+
+// ResultPoller polls the results of the queries started by a QueryExecutor
+type ResultPoller interface {
+	// PollResults waits for the query of the request token to complete and returns the raw poll response
+	PollResults(ctx context.Context, requestToken string) (string, error)
+}
+
+// DocumentSearcher searches the documents, e.g. dashboards, of a tenant
+type DocumentSearcher interface {
+	// GetDocumentIDByNameAndType returns the ID of the only document with the exact name and type
+	GetDocumentIDByNameAndType(ctx context.Context, name string, docType string) (string, error)
+}
+
+// Client is a client of a Dynatrace tenant
+type Client interface {
+	QueryExecutor
+	ResultPoller
+	DocumentSearcher
+}
+
+type QueryPayload struct {
+	Query            string `json:"query"`
+	MaxResultRecords int    `json:"maxResultRecords"`
+}
+
+type LogsPollResult struct {
+	State    string    `json:"state"`
+	Progress int       `json:"progress"`
+	Result   LogResult `json:"result"`
+}
+
+type LogResult struct {
+	Records []LogContent `json:"records"`
+}
+
+type LogContent struct {
+	Content string `json:"content"`
+}
+
+type EventsPollResult struct {
+	State    string      `json:"state"`
+	Progress int         `json:"progress"`
+	Result   EventResult `json:"result"`
+}
+
+type EventResult struct {
+	Records []json.RawMessage `json:"records"`
+}
+
+type executeResponse struct {
+	State        string `json:"state"`
+	TTLSeconds   int    `json:"ttlSeconds"`
+	RequestToken string `json:"requestToken"`
+}
+
+type DocumentResult struct {
+	Documents []Document `json:"documents"`
+}
+
+type Document struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// client calls the APIs of the tenant at baseURL with the tokens of its provider
+type client struct {
+	baseURL string
+	tokens  utils.AccessTokenProvider
+}
+
+// NewClient returns a client of the tenant at dtURL, e.g. https://abc123.apps.dynatrace.com/,
+// authenticated with the access tokens of the provider
+func NewClient(dtURL string, tokens utils.AccessTokenProvider) Client {
+	if !strings.HasSuffix(dtURL, "/") {
+		dtURL += "/"
+	}
+	return &client{baseURL: dtURL, tokens: tokens}
+}
+
+// StaticToken provides the same access token for every request
+type StaticToken string
+
+func (t StaticToken) Token() (string, error) {
+	return string(t), nil
+}
+
+// NewStorageTokenProvider returns a provider of the access tokens reading the logs and events, refreshed when they
+// expire
+func NewStorageTokenProvider() (utils.AccessTokenProvider, error) {
+	return utils.GetScopedTokenProvider(authURL, StorageVaultPathKey, StorageScopes)
+}
+
+// GetStorageAccessToken returns an access token reading the logs and events
+func GetStorageAccessToken() (string, error) {
+	return utils.GetScopedAccessToken(authURL, StorageVaultPathKey, StorageScopes)
+}
+
+// GetDocumentAccessToken returns an access token reading the documents
+func GetDocumentAccessToken() (string, error) {
+	return utils.GetScopedAccessToken(authURL, DocumentVaultPathKey, DocumentScopes)
+}
+
+func (c *client) requester(ctx context.Context, method string, path string, data string, successCode int) (*utils.Requester, error) {
+	accessToken, err := c.tokens.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	return &utils.Requester{
+		Method: method,
+		Url:    c.baseURL + path,
+		Data:   data,
+		Headers: map[string]string{
+			"Content-Type":  "application/json",
+			"Authorization": "Bearer " + accessToken,
+		},
+		SuccessCode: successCode,
+		Context:     ctx,
+	}, nil
+}
+
+func (c *client) ExecuteQuery(ctx context.Context, query string) (string, error) {
+	payload, err := json.Marshal(QueryPayload{Query: query, MaxResultRecords: MaxResultRecords})
+	if err != nil {
+		return "", err
+	}
+
+	requester, err := c.requester(ctx, http.MethodPost, "platform/storage/query/v1/query:execute", string(payload), http.StatusAccepted)
+	if err != nil {
+		return "", err
+	}
+	resp, err := requester.Send()
+	if err != nil {
+		return "", err
+	}
+
+	var execResp executeResponse
+	if err := json.Unmarshal([]byte(resp), &execResp); err != nil {
+		return "", err
+	}
+	if execResp.State != stateRunning && execResp.State != stateSucceeded {
+		return "", fmt.Errorf("query failed")
+	}
+	return execResp.RequestToken, nil
+}
+
+func (c *client) PollResults(ctx context.Context, requestToken string) (string, error) {
+	parameters := url.Values{"request-token": {requestToken}}.Encode()
+	for {
+		// The token is requested on every poll, as long queries outlive it
+		requester, err := c.requester(ctx, http.MethodGet, "platform/storage/query/v1/query:poll?"+parameters, "", http.StatusOK)
+		if err != nil {
+			return "", err
+		}
+		resp, err := requester.Send()
+		if err != nil {
+			return "", err
+		}
+
+		var pollRes struct {
+			State string `json:"state"`
+		}
+		if err := json.Unmarshal([]byte(resp), &pollRes); err != nil {
+			return "", err
+		}
+		switch pollRes.State {
+		case stateRunning:
+			continue
+		case stateSucceeded:
+			return resp, nil
+		default:
+			return "", fmt.Errorf("query failed")
+		}
+	}
+}
+
+func (c *client) GetDocumentIDByNameAndType(ctx context.Context, name string, docType string) (string, error) {
+	parameters := url.Values{
+		"filter": {"name == '" + name + "' and type == '" + docType + "'"},
+	}.Encode()
+
+	requester, err := c.requester(ctx, http.MethodGet, "platform/document/v1/documents?"+parameters, "", http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	result, err := requester.Send()
+	if err != nil {
+		return "", fmt.Errorf("could not search for %s: %w", docType, err)
+	}
+
+	var docResult DocumentResult
+	if err := json.Unmarshal([]byte(result), &docResult); err != nil {
+		return "", fmt.Errorf("response in incorrect format")
+	}
+
+	switch docCount := len(docResult.Documents); {
+	case docCount == 0:
+		return "", fmt.Errorf("%s not found", docType)
+	case docCount > 1:
+		return "", fmt.Errorf("%s name was ambiguous, %d %ss found", docType, docCount, docType)
+	}
+	return docResult.Documents[0].Id, nil
+}
+
+// QueryLogs runs the query and returns the content of the log records
+func QueryLogs(ctx context.Context, c Client, query string) ([]LogContent, error) {
+	resp, err := executeAndPoll(ctx, c, query)
+	if err != nil {
+		return nil, err
+	}
+	var pollRes LogsPollResult
+	if err := json.Unmarshal([]byte(resp), &pollRes); err != nil {
+		return nil, err
+	}
+	return pollRes.Result.Records, nil
+}
+
+// QueryRecords runs the query and returns its raw records, e.g. events
+func QueryRecords(ctx context.Context, c Client, query string) ([]json.RawMessage, error) {
+	resp, err := executeAndPoll(ctx, c, query)
+	if err != nil {
+		return nil, err
+	}
+	var pollRes EventsPollResult
+	if err := json.Unmarshal([]byte(resp), &pollRes); err != nil {
+		return nil, err
+	}
+	return pollRes.Result.Records, nil
+}
+
+func executeAndPoll(ctx context.Context, c Client, query string) (string, error) {
+	requestToken, err := c.ExecuteQuery(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute the query: %w", err)
+	}
+	resp, err := c.PollResults(ctx, requestToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the query results: %w", err)
+	}
+	return resp, nil
+}
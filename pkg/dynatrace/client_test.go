@@ -0,0 +1,102 @@
+package dynatrace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTenant serves the query and document APIs, answering the polls with the given states in turn
+func newTenant(t *testing.T, executeState string, pollStates []string, documents []Document) *httptest.Server {
+	var polls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/platform/storage/query/v1/query:execute", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var payload QueryPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "fetch logs", payload.Query)
+		assert.Equal(t, MaxResultRecords, payload.MaxResultRecords)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"state":"` + executeState + `","requestToken":"req-1"}`))
+	})
+	mux.HandleFunc("/platform/storage/query/v1/query:poll", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-1", r.URL.Query().Get("request-token"))
+		state := pollStates[min(polls, len(pollStates)-1)]
+		polls++
+		_, _ = w.Write([]byte(`{"state":"` + state + `","result":{"records":[{"content":"line 1"},{"content":"line 2"}]}}`))
+	})
+	mux.HandleFunc("/platform/document/v1/documents", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "name == 'HCP' and type == 'dashboard'", r.URL.Query().Get("filter"))
+		_ = json.NewEncoder(w).Encode(DocumentResult{Documents: documents})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestQueryLogs(t *testing.T) {
+	tests := []struct {
+		name         string
+		executeState string
+		pollStates   []string
+		expectErr    string
+	}{
+		{name: "succeeded right away", executeState: "SUCCEEDED", pollStates: []string{"SUCCEEDED"}},
+		{name: "polls while running", executeState: "RUNNING", pollStates: []string{"RUNNING", "RUNNING", "SUCCEEDED"}},
+		{name: "execution failed", executeState: "FAILED", expectErr: "failed to execute the query: query failed"},
+		{name: "poll failed", executeState: "RUNNING", pollStates: []string{"RUNNING", "FAILED"}, expectErr: "failed to get the query results: query failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTenant(t, tt.executeState, tt.pollStates, nil)
+			client := NewClient(server.URL, StaticToken("token"))
+
+			records, err := QueryLogs(context.Background(), client, "fetch logs")
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []LogContent{{Content: "line 1"}, {Content: "line 2"}}, records)
+		})
+	}
+}
+
+func TestQueryRecords(t *testing.T) {
+	server := newTenant(t, "SUCCEEDED", []string{"SUCCEEDED"}, nil)
+	records, err := QueryRecords(context.Background(), NewClient(server.URL+"/", StaticToken("token")), "fetch logs")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.JSONEq(t, `{"content":"line 1"}`, string(records[0]))
+}
+
+func TestGetDocumentIDByNameAndType(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents []Document
+		expectID  string
+		expectErr string
+	}{
+		{name: "found", documents: []Document{{Id: "doc-1", Name: "HCP", Type: DashboardType}}, expectID: "doc-1"},
+		{name: "not found", expectErr: "dashboard not found"},
+		{name: "ambiguous", documents: []Document{{Id: "doc-1"}, {Id: "doc-2"}}, expectErr: "dashboard name was ambiguous, 2 dashboards found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTenant(t, "", nil, tt.documents)
+			id, err := NewClient(server.URL, StaticToken("token")).GetDocumentIDByNameAndType(context.Background(), "HCP", DashboardType)
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectID, id)
+		})
+	}
+}
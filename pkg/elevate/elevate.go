@@ -0,0 +1,97 @@
+// Package elevate is the single entry point of the commands elevating as backplane-cluster-admin. Every elevation
+// takes a structured Reason, which is validated and formatted the same way whichever backplane API is used.
+package elevate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	bpelevate "github.com/openshift/backplane-cli/pkg/elevate"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/utils"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReasonFlag is the flag the commands elevating read the ticket of the Reason from
+const ReasonFlag = "reason"
+
+// runElevate runs the elevated "oc" command, replaced in tests
+var runElevate = bpelevate.RunElevate
+
+// Reason is the justification recorded by backplane for an elevation
+type Reason struct {
+	// Ticket is the --reason given by the SRE, usually an OHSS, ITN or PD ticket
+	Ticket string
+	// Justification is what the elevation is needed for, e.g. "patch machine type of machine x to m5.2xlarge"
+	Justification string
+	// Command is the osdctl command elevating, e.g. "cluster resize control-plane"
+	Command string
+}
+
+// Validate checks the ticket the same way as the --reason flag and that the justification and command are set
+func (r Reason) Validate() error {
+	if err := utils.ValidateReason(ReasonFlag, r.Ticket); err != nil {
+		return err
+	}
+	if strings.TrimSpace(r.Justification) == "" {
+		return errors.New("the justification of the elevation is required")
+	}
+	if strings.TrimSpace(r.Command) == "" {
+		return errors.New("the command of the elevation is required")
+	}
+	return nil
+}
+
+// String formats the reason as "<ticket> - <justification> (osdctl <command>)", the format of every elevation
+// reason sent to backplane
+func (r Reason) String() string {
+	return fmt.Sprintf("%s - %s (osdctl %s)", strings.TrimSpace(r.Ticket), r.Justification, r.Command)
+}
+
+// Run runs "oc" with the arguments elevated as backplane-cluster-admin on the cluster of the current backplane login
+func Run(reason Reason, args ...string) error {
+	if err := reason.Validate(); err != nil {
+		return err
+	}
+	return runElevate(append([]string{reason.String()}, args...))
+}
+
+// NewClient returns a client of the cluster elevated as backplane-cluster-admin
+func NewClient(clusterID string, options client.Options, reason Reason) (client.Client, error) {
+	if err := reason.Validate(); err != nil {
+		return nil, err
+	}
+	c, err := k8s.NewAsBackplaneClusterAdmin(clusterID, options, reason.String())
+	if err != nil {
+		return nil, &utils.ElevationDeniedError{Err: err}
+	}
+	return c, nil
+}
+
+// NewClientWithConn returns a client of the cluster elevated as backplane-cluster-admin, for clusters of
+// another OCM environment than the one of the current OCM login
+func NewClientWithConn(clusterID string, options client.Options, ocmConn *sdk.Connection, reason Reason) (client.Client, error) {
+	if err := reason.Validate(); err != nil {
+		return nil, err
+	}
+	c, err := k8s.NewAsBackplaneClusterAdminWithConn(clusterID, options, ocmConn, reason.String())
+	if err != nil {
+		return nil, &utils.ElevationDeniedError{Err: err}
+	}
+	return c, nil
+}
+
+// NewRestConfig returns the rest config of the cluster elevated as backplane-cluster-admin
+func NewRestConfig(clusterID string, reason Reason) (*rest.Config, error) {
+	if err := reason.Validate(); err != nil {
+		return nil, err
+	}
+	cfg, err := k8s.NewRestConfigAsBackplaneClusterAdmin(clusterID, reason.String())
+	if err != nil {
+		return nil, &utils.ElevationDeniedError{Err: err}
+	}
+	return cfg, nil
+}
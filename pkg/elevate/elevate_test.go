@@ -0,0 +1,57 @@
+package elevate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReasonString(t *testing.T) {
+	reason := Reason{Ticket: " OHSS-1234 ", Justification: "uncordon node ip-10-0-1-2", Command: "cluster resize control-plane"}
+
+	assert.Equal(t, "OHSS-1234 - uncordon node ip-10-0-1-2 (osdctl cluster resize control-plane)", reason.String())
+}
+
+func TestReasonValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  Reason
+		wantErr string
+	}{
+		{name: "valid", reason: Reason{Ticket: "OHSS-1234", Justification: "drain node", Command: "cluster node drain"}},
+		{name: "missing ticket", reason: Reason{Justification: "drain node", Command: "cluster node drain"}, wantErr: "--reason is required"},
+		{name: "malformed ticket", reason: Reason{Ticket: "OHSS1234", Justification: "drain node", Command: "cluster node drain"}, wantErr: "malformed ticket"},
+		{name: "missing justification", reason: Reason{Ticket: "OHSS-1234", Command: "cluster node drain"}, wantErr: "justification"},
+		{name: "missing command", reason: Reason{Ticket: "OHSS-1234", Justification: "drain node"}, wantErr: "command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.reason.Validate()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	orig := runElevate
+	defer func() { runElevate = orig }()
+
+	var argv []string
+	runElevate = func(args []string) error {
+		argv = args
+		return nil
+	}
+
+	reason := Reason{Ticket: "OHSS-1234", Justification: "uncordon node node-a", Command: "cluster resize control-plane"}
+	require.NoError(t, Run(reason, "adm uncordon", "node-a"))
+	assert.Equal(t, []string{reason.String(), "adm uncordon", "node-a"}, argv)
+
+	argv = nil
+	assert.Error(t, Run(Reason{Justification: "uncordon node node-a", Command: "cluster resize control-plane"}, "adm uncordon", "node-a"))
+	assert.Nil(t, argv, "nothing must be run with an invalid reason")
+}
@@ -25,10 +25,19 @@ type PDConfig struct {
 // cloudtrailCmd configuration struct for parsing configuration options
 type CloudTrailConfig struct {
 	CloudTrailList struct {
-		FilterPatternList []string `mapstructure:"filter_regex_patterns"`
+		FilterPatternList []string                    `mapstructure:"filter_regex_patterns"`
+		IdentityMappings  []CloudTrailIdentityMapping `mapstructure:"identity_mappings"`
 	} `mapstructure:"cloudtrail_cmd_lists"`
 }
 
+// CloudTrailIdentityMapping maps the ARNs of roles (or users) matching Pattern to a friendly identity name.
+// Kind is either "human" or "automation".
+type CloudTrailIdentityMapping struct {
+	Pattern string `mapstructure:"pattern"`
+	Name    string `mapstructure:"name"`
+	Kind    string `mapstructure:"kind"`
+}
+
 func LoadYaml(paramFilePath string) Config {
 	config := Config{
 		LoginScripts: map[string]string{},
@@ -67,3 +76,23 @@ func LoadCloudTrailConfig() ([]string, error) {
 
 	return configuration.CloudTrailList.FilterPatternList, err
 }
+
+// LoadCloudTrailIdentityMappings loads the cloudtrail identity mappings from ~/.config/osdctl
+func LoadCloudTrailIdentityMappings() ([]CloudTrailIdentityMapping, error) {
+	var configuration *CloudTrailConfig
+	err := osdctlConfig.EnsureConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	err = viper.Unmarshal(&configuration)
+	if err != nil {
+		log.Printf("[ERROR] Failed to unmarshal Cloudtrail config yaml %s %v", viper.ConfigFileUsed(), err)
+		return nil, err
+	}
+	if configuration == nil {
+		return nil, nil
+	}
+
+	return configuration.CloudTrailList.IdentityMappings, nil
+}
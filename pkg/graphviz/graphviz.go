@@ -2,6 +2,7 @@ package graphviz
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -16,33 +17,48 @@ func (n *Node) Render() string {
 }
 
 func RenderGraphViz(connections map[Node][]Node) {
+	fmt.Println(Render(connections))
+}
+
+// Render returns the connections as a graphviz (DOT) graph, grouping nodes by subgraph.
+// Subgraphs and nodes are sorted so that the same connections always render the same graph.
+func Render(connections map[Node][]Node) string {
+	nodes := make([]Node, 0, len(connections))
 	subgraphs := make(map[string]bool)
-	sb := strings.Builder{}
-	sb.WriteString("strict graph {\n")
-	sb.WriteString("node [shape=box]\n")
 	for node := range connections {
+		nodes = append(nodes, node)
 		subgraphs[node.Subgraph] = true
 	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Render() < nodes[j].Render()
+	})
+	subgraphNames := make([]string, 0, len(subgraphs))
 	for subgraph := range subgraphs {
-		sb.WriteString(fmt.Sprintf("subgraph cluster_%s {\n", subgraph))
-		for node, nodes := range connections {
+		if subgraph != "" {
+			subgraphNames = append(subgraphNames, subgraph)
+		}
+	}
+	sort.Strings(subgraphNames)
+
+	sb := strings.Builder{}
+	sb.WriteString("strict graph {\n")
+	sb.WriteString("node [shape=box]\n")
+	writeNodes := func(subgraph string) {
+		for _, node := range nodes {
 			if node.Subgraph == subgraph {
 				sb.WriteString(fmt.Sprintf("\"%s\"\n", node.Render()))
-				for _, v := range nodes {
+				for _, v := range connections[node] {
 					sb.WriteString(fmt.Sprintf("  \"%s\" -- \"%s\"\n", node.Render(), v.Render()))
 				}
 			}
 		}
-		sb.WriteString("}\n")
 	}
-	for node, nodes := range connections {
-		if node.Subgraph == "" {
-			sb.WriteString(fmt.Sprintf("\"%s\"\n", node.Render()))
-			for _, v := range nodes {
-				sb.WriteString(fmt.Sprintf("  \"%s\" -- \"%s\"\n", node.Render(), v.Render()))
-			}
-		}
+	for _, subgraph := range subgraphNames {
+		sb.WriteString(fmt.Sprintf("subgraph cluster_%s {\n", subgraph))
+		writeNodes(subgraph)
+		sb.WriteString("}\n")
 	}
+	writeNodes("")
 	sb.WriteString("}")
-	fmt.Println(sb.String())
+	return sb.String()
 }
@@ -0,0 +1,282 @@
+// Package history records osdctl invocations locally, so that the commands run against a cluster
+// during an incident can be shown on handover. Recording is opt-in and nothing leaves the machine.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// EnabledConfigKey is the osdctl config key enabling the recording of the command history
+	EnabledConfigKey = "history_enabled"
+
+	fileName = "history.jsonl"
+	redacted = "REDACTED"
+
+	ResultSuccess = "success"
+	ResultError   = "error"
+	// ResultIncomplete is the result of a command which exited before its outcome was recorded, e.g. through os.Exit
+	ResultIncomplete = "incomplete"
+)
+
+// sensitiveFlag matches the names of flags whose values are never recorded
+var sensitiveFlag = regexp.MustCompile(`(?i)(token|password|passwd|secret|credential|key|auth|cookie)`)
+
+// clusterFlags are the flag names identifying the cluster a command is run against, in order of precedence
+var clusterFlags = []string{"cluster-id", "cluster", "hosted-cluster-id", "hcp"}
+
+// skippedCommands are never recorded as they don't act on anything
+var skippedCommands = []string{"history", "help", "completion", "__complete", "__completeNoDesc", "version"}
+
+// Entry is a recorded osdctl invocation
+type Entry struct {
+	// ID identifies the invocation, the outcome of a command is appended with the ID of its started entry
+	ID        string            `json:"id,omitempty"`
+	Time      time.Time         `json:"time"`
+	User      string            `json:"user,omitempty"`
+	Command   string            `json:"command"`
+	ClusterID string            `json:"clusterId,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Flags     map[string]string `json:"flags,omitempty"`
+	Result    string            `json:"result"`
+	Error     string            `json:"error,omitempty"`
+	Duration  time.Duration     `json:"duration"`
+}
+
+// CommandLine reconstructs the command line of the entry, with the values of sensitive flags redacted
+func (e Entry) CommandLine() string {
+	parts := append([]string{e.Command}, e.Args...)
+	// The order flags were passed in isn't recorded, sort them to keep the output stable
+	for _, name := range slices.Sorted(maps.Keys(e.Flags)) {
+		parts = append(parts, fmt.Sprintf("--%s=%s", name, quoteIfNeeded(e.Flags[name])))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Path returns the path of the history file
+func Path() (string, error) {
+	dataDir, err := osdctlConfig.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, fileName), nil
+}
+
+// Enabled returns whether recording the history is enabled in the osdctl config
+func Enabled() bool {
+	values, err := osdctlConfig.GetConfigValues(EnabledConfigKey)
+	if err != nil {
+		return false
+	}
+	enabled, err := strconv.ParseBool(values[EnabledConfigKey])
+	return err == nil && enabled
+}
+
+// startedCommand is a command recorded by Start whose outcome isn't recorded yet
+type startedCommand struct {
+	cmd   *cobra.Command
+	id    string
+	start time.Time
+}
+
+// started is the command recorded by Start whose outcome Finish records
+var started *startedCommand
+
+// Start records cmd as incomplete if the history is enabled, so that commands exiting without returning to
+// Finish (e.g. through log.Fatal or os.Exit) are still recorded. Failures to record are reported on stderr
+// without affecting the command.
+func Start(cmd *cobra.Command) {
+	if cmd == nil || !Enabled() || isSkipped(cmd) {
+		return
+	}
+
+	start := time.Now()
+	id := fmt.Sprintf("%d-%d", start.UnixNano(), os.Getpid())
+	entry := NewEntry(cmd, nil, start, start)
+	entry.ID, entry.Result = id, ResultIncomplete
+	if err := appendToHistory(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to record the command history: %v\n", err)
+		return
+	}
+	started = &startedCommand{cmd: cmd, id: id, start: start}
+}
+
+// Finish records the outcome of the command recorded by Start, if any
+func Finish(cmdErr error) {
+	if started == nil {
+		return
+	}
+	entry := NewEntry(started.cmd, cmdErr, started.start, time.Now())
+	entry.ID = started.id
+	started = nil
+	if err := appendToHistory(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to record the command history: %v\n", err)
+	}
+}
+
+func appendToHistory(entry Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return Append(path, entry)
+}
+
+func isSkipped(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.HasParent() && slices.Contains(skippedCommands, c.Name()) {
+			return true
+		}
+	}
+	return !cmd.HasParent()
+}
+
+// NewEntry builds the history entry of an executed command, redacting the values of sensitive flags
+func NewEntry(cmd *cobra.Command, cmdErr error, start, end time.Time) Entry {
+	entry := Entry{
+		Time:     start.UTC(),
+		Command:  cmd.CommandPath(),
+		Args:     cmd.Flags().Args(),
+		Result:   ResultSuccess,
+		Duration: end.Sub(start).Round(time.Millisecond),
+	}
+	if current, err := user.Current(); err == nil {
+		entry.User = current.Username
+	}
+	if cmdErr != nil {
+		entry.Result = ResultError
+		entry.Error = cmdErr.Error()
+	}
+
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if entry.Flags == nil {
+			entry.Flags = map[string]string{}
+		}
+		value := flag.Value.String()
+		if sensitiveFlag.MatchString(flag.Name) {
+			value = redacted
+		}
+		entry.Flags[flag.Name] = value
+	})
+	for _, name := range clusterFlags {
+		if value, ok := entry.Flags[name]; ok && value != "" {
+			entry.ClusterID = value
+			break
+		}
+	}
+
+	return entry
+}
+
+// Append appends the entry to the history file at path, creating it if needed
+func Append(path string, entry Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 -- the path is osdctl's own history file
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Filter selects entries from the history
+type Filter struct {
+	// ClusterID matches entries run against the cluster, either through a flag or a positional argument
+	ClusterID string
+	// Since drops the entries older than it, when not zero
+	Since time.Time
+}
+
+func (f Filter) matches(entry Entry) bool {
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if f.ClusterID == "" || entry.ClusterID == f.ClusterID {
+		return true
+	}
+	for _, arg := range entry.Args {
+		if arg == f.ClusterID {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the entries of the history file at path matching the filter, oldest first. The outcome of a command
+// replaces its started entry. A missing history file is an empty history.
+func Load(path string, filter Filter) ([]Entry, error) {
+	f, err := os.Open(path) // #nosec G304 -- the path is osdctl's own history file
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	// positions are the indexes of the entries with an ID in entries
+	positions := map[string]int{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse line %d of %s: %w", line, path, err)
+		}
+		if i, ok := positions[entry.ID]; ok && entry.ID != "" {
+			entries[i] = entry
+			continue
+		}
+		if entry.ID != "" {
+			positions[entry.ID] = len(entries)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Filter once the outcomes replaced the started entries, the cluster name of a started entry is resolved to its ID
+	var matching []Entry
+	for _, entry := range entries {
+		if filter.matches(entry) {
+			matching = append(matching, entry)
+		}
+	}
+	return matching, nil
+}
+
+func quoteIfNeeded(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\"'") {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -0,0 +1,144 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	root := &cobra.Command{Use: "osdctl"}
+	cluster := &cobra.Command{Use: "cluster"}
+	context := &cobra.Command{Use: "context", RunE: func(*cobra.Command, []string) error { return nil }}
+	context.Flags().StringP("cluster-id", "C", "", "")
+	context.Flags().String("jira-token", "", "")
+	context.Flags().String("reason", "", "")
+	context.Flags().StringP("output", "o", "", "")
+	cluster.AddCommand(context)
+	root.AddCommand(cluster)
+
+	root.SetArgs(args)
+	resolved, err := root.ExecuteC()
+	require.NoError(t, err)
+	return resolved
+}
+
+func TestNewEntry(t *testing.T) {
+	cmd := newTestCommand(t, "cluster", "context", "-C", "abc123", "--jira-token", "s3cr3t", "--reason", "OHSS-1 investigation", "extra")
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	entry := NewEntry(cmd, errors.New("cluster not found"), start, start.Add(1500*time.Millisecond))
+
+	assert.Equal(t, "osdctl cluster context", entry.Command)
+	assert.Equal(t, "abc123", entry.ClusterID)
+	assert.Equal(t, []string{"extra"}, entry.Args)
+	assert.Equal(t, map[string]string{"cluster-id": "abc123", "jira-token": redacted, "reason": "OHSS-1 investigation"}, entry.Flags)
+	assert.Equal(t, ResultError, entry.Result)
+	assert.Equal(t, "cluster not found", entry.Error)
+	assert.Equal(t, 1500*time.Millisecond, entry.Duration)
+	assert.Equal(t, `osdctl cluster context extra --cluster-id=abc123 --jira-token=REDACTED --reason="OHSS-1 investigation"`, entry.CommandLine())
+}
+
+func TestIsSkipped(t *testing.T) {
+	root := &cobra.Command{Use: "osdctl"}
+	history := &cobra.Command{Use: "history"}
+	cluster := &cobra.Command{Use: "cluster"}
+	version := &cobra.Command{Use: "version"}
+	root.AddCommand(history, cluster)
+	cluster.AddCommand(version)
+
+	assert.True(t, isSkipped(root))
+	assert.True(t, isSkipped(history))
+	assert.True(t, isSkipped(version))
+	assert.False(t, isSkipped(cluster))
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "osdctl", fileName)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []Entry{
+		{Time: now.Add(-48 * time.Hour), Command: "osdctl cluster context", ClusterID: "abc123", Result: ResultSuccess},
+		{Time: now.Add(-time.Hour), Command: "osdctl servicelog post", Args: []string{"abc123"}, Result: ResultSuccess},
+		{Time: now.Add(-time.Minute), Command: "osdctl cluster context", ClusterID: "def456", Result: ResultError},
+	}
+	for _, entry := range entries {
+		require.NoError(t, Append(path, entry))
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := Load(path, Filter{})
+	require.NoError(t, err)
+	assert.Len(t, loaded, 3)
+
+	loaded, err = Load(path, Filter{ClusterID: "abc123"})
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, "osdctl servicelog post", loaded[1].Command)
+
+	loaded, err = Load(path, Filter{ClusterID: "abc123", Since: now.Add(-24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, []string{"abc123"}, loaded[0].Args)
+
+	loaded, err = Load(filepath.Join(t.TempDir(), "missing"), Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+}
+
+func TestStartAndFinish(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".config"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".config", "osdctl"), []byte(EnabledConfigKey+": true\n"), 0600))
+	require.True(t, Enabled())
+	path, err := Path()
+	require.NoError(t, err)
+
+	// A command exiting without returning to Finish stays incomplete
+	Start(newTestCommand(t, "cluster", "context", "-C", "abc123"))
+	started = nil
+	Start(newTestCommand(t, "cluster", "context", "-C", "def456"))
+	Finish(errors.New("cluster not found"))
+
+	entries, err := Load(path, Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, ResultIncomplete, entries[0].Result)
+	assert.Equal(t, "abc123", entries[0].ClusterID)
+	assert.Equal(t, ResultError, entries[1].Result)
+	assert.Equal(t, "cluster not found", entries[1].Error)
+	assert.Equal(t, "def456", entries[1].ClusterID)
+	assert.NotEmpty(t, entries[1].ID)
+}
+
+func TestLoadReplacesStartedEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), fileName)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, Append(path, Entry{ID: "1", Time: now, Command: "osdctl cluster context", ClusterID: "my-cluster", Result: ResultIncomplete}))
+	require.NoError(t, Append(path, Entry{ID: "2", Time: now, Command: "osdctl cluster logging-check", Result: ResultIncomplete}))
+	require.NoError(t, Append(path, Entry{ID: "1", Time: now, Command: "osdctl cluster context", ClusterID: "abc123", Result: ResultSuccess}))
+
+	entries, err := Load(path, Filter{ClusterID: "abc123"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ResultSuccess, entries[0].Result)
+
+	entries, err = Load(path, Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "osdctl cluster context", entries[0].Command)
+	assert.Equal(t, ResultIncomplete, entries[1].Result)
+}
@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// ExecInPod runs a command in a container of a pod and streams its standard output to stdout. The standard error of
+// a failed command is included in the returned error.
+func ExecInPod(ctx context.Context, restCfg *rest.Config, clientset kubernetes.Interface, namespace, pod, container string, command []string, stdout io.Writer) error {
+	req := clientset.CoreV1().RESTClient().Post().Resource("pods").Name(pod).Namespace(namespace).SubResource("exec")
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restCfg, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	stderr := &bytes.Buffer{}
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// ExecInPodOutput runs a command in a container of a pod and returns its standard output
+func ExecInPodOutput(ctx context.Context, restCfg *rest.Config, clientset kubernetes.Interface, namespace, pod, container string, command []string) (string, error) {
+	stdout := &bytes.Buffer{}
+	if err := ExecInPod(ctx, restCfg, clientset, namespace, pod, container, command, stdout); err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
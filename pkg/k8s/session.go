@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// BackplaneAutoLoginConfigKey is the osdctl config key enabling the automatic "ocm backplane login" when
+// the backplane session of a command is missing or expired
+const BackplaneAutoLoginConfigKey = "backplane_auto_login"
+
+var (
+	// ErrSessionExpired is returned when the API server rejects the credentials of the backplane session
+	ErrSessionExpired = errors.New("the backplane session has expired")
+	// ErrSessionOtherCluster is returned when the backplane session is for another cluster
+	ErrSessionOtherCluster = errors.New("the backplane session is for another cluster")
+)
+
+// The steps of the session check, replaced in tests
+var (
+	currentBackplaneCluster = GetCurrentCluster
+	probeSession            = probeCurrentSession
+	backplaneLogin          = runBackplaneLogin
+	autoLoginEnabled        = AutoLoginEnabled
+)
+
+// SessionError explains why the backplane session can't be used for a cluster and how to log in again
+type SessionError struct {
+	ClusterID string
+	Err       error
+}
+
+func (e *SessionError) Error() string {
+	return fmt.Sprintf("%v, log in again with \"ocm backplane login %s\" (or set '%s: true' in the osdctl config to log in automatically)", e.Err, e.ClusterID, BackplaneAutoLoginConfigKey)
+}
+
+func (e *SessionError) Unwrap() error {
+	return e.Err
+}
+
+// AutoLoginEnabled returns whether the automatic backplane login is enabled in the osdctl config
+func AutoLoginEnabled() bool {
+	values, err := osdctlConfig.GetConfigValues(BackplaneAutoLoginConfigKey)
+	if err != nil {
+		return false
+	}
+	enabled, err := strconv.ParseBool(values[BackplaneAutoLoginConfigKey])
+	return err == nil && enabled
+}
+
+// CheckBackplaneSession verifies that the current backplane session, which "oc" and the elevated commands run with,
+// is for the cluster and that the API server still accepts its credentials. The returned *SessionError holds the
+// login command to run when it isn't.
+func CheckBackplaneSession(ctx context.Context, clusterID string) error {
+	current, err := currentBackplaneCluster()
+	if err != nil {
+		return &SessionError{ClusterID: clusterID, Err: fmt.Errorf("no backplane session found: %w", err)}
+	}
+	if current != clusterID {
+		return &SessionError{ClusterID: clusterID, Err: fmt.Errorf("%w %s", ErrSessionOtherCluster, current)}
+	}
+	if err := probeSession(ctx); err != nil {
+		return &SessionError{ClusterID: clusterID, Err: err}
+	}
+	return nil
+}
+
+// EnsureBackplaneSession checks the backplane session for the cluster as a pre-flight of the commands relying on
+// it. When the session is missing, expired or for another cluster and the automatic login is enabled in the
+// osdctl config, "ocm backplane login" is run once and the session checked again.
+func EnsureBackplaneSession(ctx context.Context, clusterID string) error {
+	err := CheckBackplaneSession(ctx, clusterID)
+	var sessionErr *SessionError
+	if err == nil || !errors.As(err, &sessionErr) || !autoLoginEnabled() {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%v, logging in again to cluster %s\n", sessionErr.Err, clusterID)
+	if err := backplaneLogin(ctx, clusterID); err != nil {
+		return fmt.Errorf("automatic backplane login to cluster %s failed: %w", clusterID, err)
+	}
+	return CheckBackplaneSession(ctx, clusterID)
+}
+
+// probeCurrentSession sends a SelfSubjectAccessReview with the credentials of the current kubeconfig context,
+// which only fails with Unauthorized when the credentials are no longer valid.
+func probeCurrentSession(ctx context.Context) error {
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load the kubeconfig of the backplane session: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: "get", Resource: "nodes"},
+		},
+	}
+	_, err = clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if apierrors.IsUnauthorized(err) {
+		return ErrSessionExpired
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reach the API server of the backplane session: %w", err)
+	}
+	return nil
+}
+
+func runBackplaneLogin(ctx context.Context, clusterID string) error {
+	cmd := exec.CommandContext(ctx, "ocm", "backplane", "login", clusterID) // #nosec G204 -- the cluster ID is validated by OCM
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
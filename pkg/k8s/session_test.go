@@ -0,0 +1,106 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func stubSession(t *testing.T, current string, probeErrs []error, autoLogin bool) *[]string {
+	t.Helper()
+	origCurrent, origProbe, origLogin, origAutoLogin := currentBackplaneCluster, probeSession, backplaneLogin, autoLoginEnabled
+	t.Cleanup(func() {
+		currentBackplaneCluster, probeSession, backplaneLogin, autoLoginEnabled = origCurrent, origProbe, origLogin, origAutoLogin
+	})
+
+	logins := &[]string{}
+	currentBackplaneCluster = func() (string, error) { return current, nil }
+	probeSession = func(context.Context) error {
+		if len(probeErrs) == 0 {
+			return nil
+		}
+		err := probeErrs[0]
+		probeErrs = probeErrs[1:]
+		return err
+	}
+	backplaneLogin = func(_ context.Context, clusterID string) error {
+		*logins = append(*logins, clusterID)
+		current = clusterID
+		return nil
+	}
+	autoLoginEnabled = func() bool { return autoLogin }
+	return logins
+}
+
+func TestCheckBackplaneSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		probeErr error
+		wantErr  error
+	}{
+		{name: "valid session", current: "cluster-id"},
+		{name: "session for another cluster", current: "other-id", wantErr: ErrSessionOtherCluster},
+		{name: "expired session", current: "cluster-id", probeErr: ErrSessionExpired, wantErr: ErrSessionExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubSession(t, tt.current, []error{tt.probeErr}, false)
+
+			err := CheckBackplaneSession(context.Background(), "cluster-id")
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			var sessionErr *SessionError
+			if !errors.As(err, &sessionErr) || sessionErr.ClusterID != "cluster-id" {
+				t.Fatalf("expected a *SessionError for cluster-id, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEnsureBackplaneSession(t *testing.T) {
+	t.Run("logs in again when enabled", func(t *testing.T) {
+		logins := stubSession(t, "cluster-id", []error{ErrSessionExpired}, true)
+
+		if err := EnsureBackplaneSession(context.Background(), "cluster-id"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*logins) != 1 || (*logins)[0] != "cluster-id" {
+			t.Errorf("expected a single login to cluster-id, got %v", *logins)
+		}
+	})
+
+	t.Run("logs into the cluster when the session is for another one", func(t *testing.T) {
+		logins := stubSession(t, "other-id", nil, true)
+
+		if err := EnsureBackplaneSession(context.Background(), "cluster-id"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*logins) != 1 {
+			t.Errorf("expected a single login, got %v", *logins)
+		}
+	})
+
+	t.Run("returns the login command when disabled", func(t *testing.T) {
+		logins := stubSession(t, "cluster-id", []error{ErrSessionExpired}, false)
+
+		err := EnsureBackplaneSession(context.Background(), "cluster-id")
+		if !errors.Is(err, ErrSessionExpired) {
+			t.Fatalf("expected %v, got %v", ErrSessionExpired, err)
+		}
+		if want := `ocm backplane login cluster-id`; !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to contain %q, got %q", want, err.Error())
+		}
+		if len(*logins) != 0 {
+			t.Errorf("expected no login, got %v", *logins)
+		}
+	})
+}
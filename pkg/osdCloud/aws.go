@@ -11,8 +11,6 @@ import (
 	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
-	bpcloud "github.com/openshift/backplane-cli/cmd/ocm-backplane/cloud"
-	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
 	"github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/viper"
@@ -182,18 +180,7 @@ func GenerateRoleSessionName(client aws.Client) (string, error) {
 
 // CreateAWSV2Config creates an aws-sdk-go-v2 config via Backplane given an internal cluster id
 func CreateAWSV2Config(conn *sdk.Connection, cluster *cmv1.Cluster) (awsSdk.Config, error) {
-	bp, err := bpconfig.GetBackplaneConfiguration()
-	if err != nil {
-		return awsSdk.Config{}, fmt.Errorf("failed to load backplane-cli config: %v", err)
-	}
-
-	qc := &bpcloud.QueryConfig{
-		BackplaneConfiguration: bp,
-		OcmConnection:          conn,
-		Cluster:                cluster,
-	}
-
-	return qc.GetAWSV2Config()
+	return aws.BackplaneConfig(conn, cluster)
 }
 
 func GenerateCCSClusterAWSClient(ocmClient *sdk.Connection, awsClient aws.Client, clusterID string, clusterRegion string, partition string, sessionName string) (aws.Client, error) {
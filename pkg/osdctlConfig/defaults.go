@@ -0,0 +1,94 @@
+package osdctlConfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// DefaultsConfigKey is the section of the config file holding the default flag values of the commands, e.g.
+//
+//	defaults:
+//	  cluster cad run:
+//	    environment: stage
+//	  dt gather-logs:
+//	    since: 24
+const DefaultsConfigKey = "defaults"
+
+// mutuallyExclusiveAnnotation is the annotation cobra stores the groups of MarkFlagsMutuallyExclusive under, each
+// group being the space-separated names of its flags
+const mutuallyExclusiveAnnotation = "cobra_annotation_mutually_exclusive"
+
+// ApplyFlagDefaults sets the flags of the command which weren't given on the command line to their default
+// value from the defaults section of the config file
+func ApplyFlagDefaults(cmd *cobra.Command) error {
+	v, err := readConfig()
+	if err != nil {
+		// Without a readable config file there are no defaults to apply
+		return nil
+	}
+	return applyFlagDefaults(cmd, v.GetStringMap(DefaultsConfigKey))
+}
+
+// applyFlagDefaults applies the defaults of the entries whose command path, e.g. "dt gather-logs", resolves to cmd.
+// Command paths may use aliases and are relative to the root command.
+func applyFlagDefaults(cmd *cobra.Command, defaults map[string]any) error {
+	for path, values := range defaults {
+		target, remaining, err := cmd.Root().Find(strings.Fields(path))
+		if err != nil || len(remaining) > 0 || target != cmd {
+			continue
+		}
+
+		flags, ok := values.(map[string]any)
+		if !ok {
+			return fmt.Errorf("invalid %s for %q in the config file, expected a map of flag names to values", DefaultsConfigKey, path)
+		}
+		names := make([]string, 0, len(flags))
+		for name := range flags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			flag := cmd.Flags().Lookup(name)
+			if flag == nil {
+				return fmt.Errorf("the %s of %q in the config file set the unknown flag --%s", DefaultsConfigKey, path, name)
+			}
+			// A default can't be applied along a mutually exclusive flag given on the command line
+			if flag.Changed || exclusiveFlagChanged(cmd.Flags(), flag) {
+				continue
+			}
+			if err := cmd.Flags().Set(name, flagValue(flags[name])); err != nil {
+				return fmt.Errorf("invalid default value of --%s in the config file: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// exclusiveFlagChanged returns whether another flag of a mutually exclusive group of flag is set
+func exclusiveFlagChanged(flags *pflag.FlagSet, flag *pflag.Flag) bool {
+	for _, group := range flag.Annotations[mutuallyExclusiveAnnotation] {
+		for _, name := range strings.Fields(group) {
+			if other := flags.Lookup(name); name != flag.Name && other != nil && other.Changed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagValue formats a config value as given on the command line, lists as comma-separated values
+func flagValue(value any) string {
+	if list, ok := value.([]any); ok {
+		values := make([]string, 0, len(list))
+		for _, v := range list {
+			values = append(values, fmt.Sprint(v))
+		}
+		return strings.Join(values, ",")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,108 @@
+package osdctlConfig
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newDefaultsTestCommands() (*cobra.Command, *cobra.Command) {
+	root := &cobra.Command{Use: "osdctl"}
+	dt := &cobra.Command{Use: "dynatrace", Aliases: []string{"dt"}}
+	gather := &cobra.Command{Use: "gather-logs", Run: func(*cobra.Command, []string) {}}
+	gather.Flags().Int("since", 10, "")
+	gather.Flags().String("sort", "asc", "")
+	gather.Flags().StringSlice("namespace", nil, "")
+	gather.Flags().String("from", "", "")
+	gather.Flags().String("to", "", "")
+	gather.MarkFlagsMutuallyExclusive("since", "from")
+	gather.MarkFlagsMutuallyExclusive("since", "to")
+	dt.AddCommand(gather)
+	root.AddCommand(dt)
+	return root, gather
+}
+
+func readDefaults(t *testing.T, config string) map[string]any {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewBufferString(config)); err != nil {
+		t.Fatal(err)
+	}
+	return v.GetStringMap(DefaultsConfigKey)
+}
+
+func TestApplyFlagDefaults(t *testing.T) {
+	defaults := readDefaults(t, `
+defaults:
+  dt gather-logs:
+    since: 24
+    sort: desc
+    namespace:
+      - openshift-etcd
+      - openshift-kube-apiserver
+  cluster cad run:
+    environment: stage
+`)
+
+	_, gather := newDefaultsTestCommands()
+	if err := gather.Flags().Set("sort", "asc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyFlagDefaults(gather, defaults); err != nil {
+		t.Fatal(err)
+	}
+
+	if since, _ := gather.Flags().GetInt("since"); since != 24 {
+		t.Errorf("expected --since to default to 24, got %d", since)
+	}
+	if sort, _ := gather.Flags().GetString("sort"); sort != "asc" {
+		t.Errorf("expected the --sort given on the command line to be kept, got %s", sort)
+	}
+	if namespaces, _ := gather.Flags().GetStringSlice("namespace"); len(namespaces) != 2 || namespaces[1] != "openshift-kube-apiserver" {
+		t.Errorf("expected the --namespace list default, got %v", namespaces)
+	}
+}
+
+func TestApplyFlagDefaultsMutuallyExclusive(t *testing.T) {
+	defaults := readDefaults(t, `
+defaults:
+  dt gather-logs:
+    since: 2
+    sort: desc
+`)
+
+	_, gather := newDefaultsTestCommands()
+	if err := gather.ParseFlags([]string{"--to", "2026-01-02T00:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyFlagDefaults(gather, defaults); err != nil {
+		t.Fatal(err)
+	}
+
+	if gather.Flags().Lookup("since").Changed {
+		t.Error("expected the --since default to be skipped as --to was given")
+	}
+	if sort, _ := gather.Flags().GetString("sort"); sort != "desc" {
+		t.Errorf("expected --sort to default to desc, got %s", sort)
+	}
+	if err := gather.ValidateFlagGroups(); err != nil {
+		t.Errorf("expected the flag groups to be valid, got %v", err)
+	}
+}
+
+func TestApplyFlagDefaultsErrors(t *testing.T) {
+	for name, config := range map[string]string{
+		"unknown flag":  "defaults:\n  dynatrace gather-logs:\n    tail: 10\n",
+		"invalid value": "defaults:\n  dynatrace gather-logs:\n    since: yesterday\n",
+		"not a map":     "defaults:\n  dynatrace gather-logs: 24\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, gather := newDefaultsTestCommands()
+			if err := applyFlagDefaults(gather, readDefaults(t, config)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
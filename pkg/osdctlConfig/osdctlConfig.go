@@ -3,6 +3,7 @@ package osdctlConfig
 import (
 	"errors"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/viper"
 )
@@ -11,6 +12,19 @@ const (
 	ConfigFileName = "osdctl"
 )
 
+// DataDir returns the directory osdctl stores local data in, such as session transcripts.
+// ~/.config/osdctl is the config file, so data lives in $XDG_DATA_HOME/osdctl (~/.local/share/osdctl).
+func DataDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, ConfigFileName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", ConfigFileName), nil
+}
+
 func EnsureConfigFile() error {
 	configHomePath, err := os.UserHomeDir()
 	if err != nil {
@@ -42,6 +56,29 @@ func EnsureConfigFile() error {
 // avoiding the global viper which backplane-cli overwrites concurrently.
 // TODO: Remove this workaround once backplane-cli stops overwriting the global viper instance.
 func GetConfigValues(keys ...string) (map[string]string, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(keys))
+	for _, k := range keys {
+		values[k] = v.GetString(k)
+	}
+	return values, nil
+}
+
+// GetConfigStringSlice reads a list of the osdctl config file, like GetConfigValues
+func GetConfigStringSlice(key string) ([]string, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	return v.GetStringSlice(key), nil
+}
+
+// readConfig reads the osdctl config file into a dedicated viper instance
+func readConfig() (*viper.Viper, error) {
 	configHomePath, err := os.UserHomeDir()
 	if err != nil {
 		return nil, err
@@ -54,10 +91,5 @@ func GetConfigValues(keys ...string) (map[string]string, error) {
 	if err := v.ReadInConfig(); err != nil {
 		return nil, err
 	}
-
-	values := make(map[string]string, len(keys))
-	for _, k := range keys {
-		values[k] = v.GetString(k)
-	}
-	return values, nil
+	return v, nil
 }
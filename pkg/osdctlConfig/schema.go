@@ -0,0 +1,251 @@
+package osdctlConfig
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// requiredByKeyword is the schema keyword listing the command paths, relative to the root command, which need a key.
+// The subcommands of a command need the key too.
+const requiredByKeyword = "x-required-by"
+
+//go:embed schema.json
+var schemaJSON []byte
+
+// IssueKind is the kind of a problem found in the config file
+type IssueKind string
+
+const (
+	// IssueUnknown is a key which isn't in the schema, usually a typo
+	IssueUnknown IssueKind = "unknown"
+	// IssueDeprecated is a key osdctl still reads but is going away
+	IssueDeprecated IssueKind = "deprecated"
+	// IssueInvalid is a value which doesn't match the format of its key
+	IssueInvalid IssueKind = "invalid"
+	// IssueMissing is a key which isn't set but is required by a command
+	IssueMissing IssueKind = "missing"
+)
+
+// Issue is a problem with a key of the config file
+type Issue struct {
+	Key     string
+	Kind    IssueKind
+	Message string
+}
+
+// Schema describes the keys of the config file and the commands requiring them
+type Schema struct {
+	properties map[string]*jsonschema.Schema
+	resolved   map[string]*jsonschema.Resolved
+}
+
+// LoadSchema returns the schema of the config file embedded in osdctl
+func LoadSchema() (*Schema, error) {
+	return parseSchema(schemaJSON)
+}
+
+// parseSchema resolves every property of the schema on its own, so each key is validated and reported separately
+func parseSchema(data []byte) (*Schema, error) {
+	var root jsonschema.Schema
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse the config file schema: %w", err)
+	}
+
+	s := &Schema{
+		properties: root.Properties,
+		resolved:   make(map[string]*jsonschema.Resolved, len(root.Properties)),
+	}
+	for key, property := range root.Properties {
+		resolved, err := property.Resolve(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the schema of %s: %w", key, err)
+		}
+		s.resolved[key] = resolved
+	}
+	return s, nil
+}
+
+// Validate reports the unknown, deprecated and invalid keys of the config, sorted by key
+func (s *Schema) Validate(config map[string]any) []Issue {
+	var issues []Issue
+	for _, key := range sortedKeys(config) {
+		property, ok := s.properties[key]
+		if !ok {
+			issues = append(issues, Issue{Key: key, Kind: IssueUnknown, Message: "not a known osdctl config key"})
+			continue
+		}
+		if property.Deprecated {
+			message := "deprecated"
+			if property.Description != "" {
+				message += ": " + property.Description
+			}
+			issues = append(issues, Issue{Key: key, Kind: IssueDeprecated, Message: message})
+		}
+		if err := s.resolved[key].Validate(toJSONValue(config[key])); err != nil {
+			message := validationMessage(err)
+			if property.WriteOnly {
+				// The validation errors quote the value, which is a secret
+				message = "the value doesn't have the expected format"
+			}
+			issues = append(issues, Issue{Key: key, Kind: IssueInvalid, Message: message})
+		}
+	}
+	return issues
+}
+
+// RequiredBy returns the keys required by the command path, e.g. "jira quick-task" or "osdctl jira quick-task"
+func (s *Schema) RequiredBy(command string) []string {
+	command = strings.Join(strings.Fields(command), " ")
+	command = strings.TrimPrefix(strings.TrimPrefix(command, ConfigFileName), " ")
+
+	var keys []string
+	for key, property := range s.properties {
+		paths, _ := property.Extra[requiredByKeyword].([]any)
+		for _, path := range paths {
+			if path, ok := path.(string); ok && (command == path || strings.HasPrefix(command, path+" ")) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Missing reports the keys required by the commands which aren't set in the config, sorted by key
+func (s *Schema) Missing(config map[string]any, commands []string) []Issue {
+	requiredBy := map[string][]string{}
+	for _, command := range commands {
+		for _, key := range s.RequiredBy(command) {
+			if isSet(config[key]) {
+				continue
+			}
+			requiredBy[key] = appendUnique(requiredBy[key], command)
+		}
+	}
+
+	var issues []Issue
+	for _, key := range sortedKeys(requiredBy) {
+		sort.Strings(requiredBy[key])
+		issues = append(issues, Issue{
+			Key:     key,
+			Kind:    IssueMissing,
+			Message: "required by " + strings.Join(requiredBy[key], ", "),
+		})
+	}
+	return issues
+}
+
+// ReadConfigSettings returns the keys of the config file and their values
+func ReadConfigSettings() (map[string]any, error) {
+	v, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	return v.AllSettings(), nil
+}
+
+// WarnInvalidConfig prints a warning for the unknown, deprecated and invalid keys of the config file. The keys
+// required by specific commands aren't checked here, "osdctl setup doctor" reports them.
+func WarnInvalidConfig(w io.Writer) {
+	config, err := ReadConfigSettings()
+	if err != nil {
+		return
+	}
+	schema, err := LoadSchema()
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "Warning: %v\n", err)
+		return
+	}
+	issues := schema.Validate(config)
+	for _, issue := range issues {
+		_, _ = fmt.Fprintf(w, "Warning: %s key %q in ~/.config/%s: %s\n", issue.Kind, issue.Key, ConfigFileName, issue.Message)
+	}
+	if len(issues) > 0 {
+		_, _ = fmt.Fprintf(w, "Run 'osdctl setup doctor' to check the config file.\n")
+	}
+}
+
+// toJSONValue converts the values read from the yaml config file, e.g. map[any]any or int, to the ones of
+// encoding/json the validation expects
+func toJSONValue(value any) any {
+	data, err := json.Marshal(normalizeYAML(value))
+	if err != nil {
+		return value
+	}
+	var converted any
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return value
+	}
+	return converted
+}
+
+func normalizeYAML(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return converted
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[key] = normalizeYAML(item)
+		}
+		return converted
+	case []any:
+		converted := make([]any, len(v))
+		for i, item := range v {
+			converted[i] = normalizeYAML(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// validationMessage strips the location prefix of the validation errors, which is always the root of the value
+func validationMessage(err error) string {
+	message := err.Error()
+	message = strings.TrimPrefix(message, "validating root: ")
+	message = strings.TrimPrefix(message, "validating /: ")
+	return message
+}
+
+func isSet(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(v) != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	}
+	return true
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,89 @@
+package osdctlConfig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := LoadSchema()
+	require.NoError(t, err)
+
+	issues := schema.Validate(map[string]any{
+		"prod_jumprole_account_id": 123456789012,
+		"aws_proxy":                "http://squid.example.com:3128",
+		"jira_tokn":                "abc",
+		"pd_user_token":            "short",
+		"history_enabled":          true,
+		"version_channel":          "nightly",
+		"danger_blocked_clusters":  []any{"hs-mc-*"},
+		"cloudtrail_cmd_lists": map[string]any{
+			"identity_mappings": []any{map[any]any{"pattern": ".*-Installer-Role", "name": "installer", "kind": "robot"}},
+		},
+	})
+
+	require.Len(t, issues, 4)
+	assert.Equal(t, Issue{Key: "cloudtrail_cmd_lists", Kind: IssueInvalid, Message: issues[0].Message}, issues[0])
+	assert.Contains(t, issues[0].Message, "robot")
+	assert.Equal(t, Issue{Key: "jira_tokn", Kind: IssueUnknown, Message: "not a known osdctl config key"}, issues[1])
+	assert.Equal(t, Issue{Key: "pd_user_token", Kind: IssueInvalid, Message: "the value doesn't have the expected format"}, issues[2])
+	assert.Equal(t, "version_channel", issues[3].Key)
+	assert.Equal(t, IssueInvalid, issues[3].Kind)
+}
+
+func TestSchemaDeprecated(t *testing.T) {
+	schema, err := parseSchema([]byte(`{"properties": {
+		"old_key": {"type": "string", "deprecated": true, "description": "use new_key instead"},
+		"new_key": {"type": "string"}
+	}}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Issue{{Key: "old_key", Kind: IssueDeprecated, Message: "deprecated: use new_key instead"}},
+		schema.Validate(map[string]any{"old_key": "value", "new_key": "value"}))
+}
+
+func TestSchemaRequiredBy(t *testing.T) {
+	schema, err := LoadSchema()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"jira_board_id", "jira_team", "jira_team_label", "jira_token"}, schema.RequiredBy("osdctl jira quick-task"))
+	assert.Equal(t, []string{"jira_token"}, schema.RequiredBy("jira create-handover-announcement"))
+	assert.Equal(t, []string{"dt_vault_path", "vault_address"}, schema.RequiredBy("osdctl dynatrace gather-logs"))
+	assert.Empty(t, schema.RequiredBy("osdctl jiraX"))
+	assert.Empty(t, schema.RequiredBy("osdctl version"))
+}
+
+func TestSchemaMissing(t *testing.T) {
+	schema, err := LoadSchema()
+	require.NoError(t, err)
+
+	issues := schema.Missing(map[string]any{
+		"jira_token": "token",
+		"jira_team":  "  ",
+	}, []string{"osdctl jira quick-task", "osdctl jira quick-task", "osdctl cluster cad run", "osdctl version"})
+
+	assert.Equal(t, []Issue{
+		{Key: "jira_board_id", Kind: IssueMissing, Message: "required by osdctl jira quick-task"},
+		{Key: "jira_team", Kind: IssueMissing, Message: "required by osdctl jira quick-task"},
+		{Key: "jira_team_label", Kind: IssueMissing, Message: "required by osdctl jira quick-task"},
+		{Key: "pd_user_token", Kind: IssueMissing, Message: "required by osdctl cluster cad run"},
+	}, issues)
+}
+
+func TestWarnInvalidConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".config"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".config", ConfigFileName), []byte("aws_proxy: squid:3128\nfedramp: false\njira_team: SD\n"), 0600))
+
+	out := &bytes.Buffer{}
+	WarnInvalidConfig(out)
+	assert.Contains(t, out.String(), `Warning: invalid key "aws_proxy" in ~/.config/osdctl:`)
+	assert.Contains(t, out.String(), "Run 'osdctl setup doctor' to check the config file.")
+	assert.NotContains(t, out.String(), "jira_team")
+}
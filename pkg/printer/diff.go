@@ -0,0 +1,79 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DefaultDiffContext is the number of unchanged lines shown around the changes of a diff
+const DefaultDiffContext = 3
+
+var (
+	diffHeaderColor  = color.New(color.Bold)
+	diffHunkColor    = color.New(color.FgCyan)
+	diffAddedColor   = color.New(color.FgGreen)
+	diffRemovedColor = color.New(color.FgRed)
+)
+
+// UnifiedDiff returns the unified diff of two texts, with contextLines unchanged lines around the changes, or an
+// empty string when the texts are equal
+func UnifiedDiff(fromName, toName, from, to string, contextLines int) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  contextLines,
+	})
+}
+
+// PrintDiff writes the unified diff of two texts, colorized following the color settings of the terminal, and
+// returns whether the texts differ
+func PrintDiff(w io.Writer, fromName, toName, from, to string, contextLines int) (bool, error) {
+	diff, err := UnifiedDiff(fromName, toName, from, to, contextLines)
+	if err != nil {
+		return false, err
+	}
+	if diff == "" {
+		return false, nil
+	}
+	return true, printColorizedDiff(w, diff)
+}
+
+// printColorizedDiff colorizes the lines of a unified diff
+func printColorizedDiff(w io.Writer, diff string) error {
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if line == "" {
+			continue
+		}
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(text, "---"), strings.HasPrefix(text, "+++"):
+			text = diffHeaderColor.Sprint(text)
+		case strings.HasPrefix(text, "@@"):
+			text = diffHunkColor.Sprint(text)
+		case strings.HasPrefix(text, "+"):
+			text = diffAddedColor.Sprint(text)
+		case strings.HasPrefix(text, "-"):
+			text = diffRemovedColor.Sprint(text)
+		}
+		if _, err := fmt.Fprintln(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitLines splits a text into lines ending with a newline, ignoring the newline ending the text so that no change
+// is reported on the last line when only one of the texts ends with a newline
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return difflib.SplitLines(text)
+}
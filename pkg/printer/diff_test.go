@@ -0,0 +1,56 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+	. "github.com/onsi/gomega"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	from := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	to := "a\nb\nc\nd\nE\nf\ng\nh"
+
+	diff, err := UnifiedDiff("before", "after", from, to, 1)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(diff).To(Equal("--- before\n+++ after\n@@ -4,3 +4,3 @@\n d\n-e\n+E\n f\n"))
+
+	diff, err = UnifiedDiff("before", "after", from, from, DefaultDiffContext)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(diff).To(BeEmpty())
+
+	// Only the trailing newline differs
+	diff, err = UnifiedDiff("before", "after", "a\n", "a", DefaultDiffContext)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(diff).To(BeEmpty())
+}
+
+func TestPrintDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+
+	color.NoColor = true
+	out := &bytes.Buffer{}
+	changed, err := PrintDiff(out, "before", "after", "x: 1\ny: 2\n", "x: 1\ny: 3\n", DefaultDiffContext)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changed).To(BeTrue())
+	g.Expect(out.String()).To(Equal("--- before\n+++ after\n@@ -1,2 +1,2 @@\n x: 1\n-y: 2\n+y: 3\n"))
+
+	color.NoColor = false
+	out.Reset()
+	_, err = PrintDiff(out, "before", "after", "y: 2\n", "y: 3\n", DefaultDiffContext)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out.String()).To(ContainSubstring("\x1b[31m-y: 2\x1b[0m"))
+	g.Expect(out.String()).To(ContainSubstring("\x1b[32m+y: 3\x1b[0m"))
+
+	out.Reset()
+	changed, err = PrintDiff(out, "before", "after", "y: 2\n", "y: 2\n", DefaultDiffContext)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changed).To(BeFalse())
+	g.Expect(out.String()).To(BeEmpty())
+}
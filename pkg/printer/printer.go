@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
@@ -13,24 +15,125 @@ import (
 // printer use to output something on screen with table format.
 type printer struct {
 	w *tabwriter.Writer
+
+	// sortBy and columns are applied on Flush, the first row added is the header
+	sortBy  string
+	columns []string
+	rows    [][]string
 }
 
 // NewTablePrinter creates a printer instance, and uses to format output with table.
 func NewTablePrinter(o io.Writer, minWidth, tabWidth, padding int, padChar byte) *printer {
 	w := tabwriter.NewWriter(o, minWidth, tabWidth, padding, padChar, 0)
-	return &printer{w}
+	return &printer{w: w}
+}
+
+// SortBy sorts the rows by the given header column when flushing. Numeric values are compared as numbers.
+func (p *printer) SortBy(column string) {
+	p.sortBy = column
+}
+
+// SelectColumns only outputs the given header columns, in the given order, when flushing.
+func (p *printer) SelectColumns(columns []string) {
+	p.columns = columns
 }
 
 // AddRow adds a row of data.
 func (p *printer) AddRow(row []string) {
+	if p.buffered() {
+		p.rows = append(p.rows, row)
+		return
+	}
 	fmt.Fprintln(p.w, strings.Join(row, "\t"))
 }
 
 // Flush outputs all rows on screen.
 func (p *printer) Flush() error {
+	if p.buffered() && len(p.rows) > 0 {
+		rows, err := p.transform()
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			fmt.Fprintln(p.w, strings.Join(row, "\t"))
+		}
+		p.rows = nil
+	}
 	return p.w.Flush()
 }
 
+func (p *printer) buffered() bool {
+	return p.sortBy != "" || len(p.columns) > 0
+}
+
+// transform sorts the buffered rows and selects their columns. The first row is the header.
+func (p *printer) transform() ([][]string, error) {
+	header, body := p.rows[0], p.rows[1:]
+
+	if p.sortBy != "" {
+		idx, err := columnIndex(header, p.sortBy)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(body, func(i, j int) bool {
+			return lessCell(cell(body[i], idx), cell(body[j], idx))
+		})
+	}
+
+	if len(p.columns) == 0 {
+		return append([][]string{header}, body...), nil
+	}
+
+	indexes := make([]int, 0, len(p.columns))
+	for _, column := range p.columns {
+		idx, err := columnIndex(header, column)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, idx)
+	}
+
+	rows := make([][]string, 0, len(p.rows))
+	for _, row := range append([][]string{header}, body...) {
+		selected := make([]string, 0, len(indexes))
+		for _, idx := range indexes {
+			selected = append(selected, cell(row, idx))
+		}
+		rows = append(rows, selected)
+	}
+	return rows, nil
+}
+
+// normalizeColumn makes column names comparable regardless of case, spaces, dashes and underscores
+func normalizeColumn(column string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(column)))
+}
+
+func columnIndex(header []string, column string) (int, error) {
+	for i, h := range header {
+		if normalizeColumn(h) == normalizeColumn(column) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("unknown column %q, available columns: %s", column, strings.Join(header, ", "))
+}
+
+func cell(row []string, idx int) string {
+	if idx < len(row) {
+		return row[idx]
+	}
+	return ""
+}
+
+func lessCell(a, b string) bool {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return fa < fb
+	}
+	return a < b
+}
+
 // ClearScreen clears all output on screen.
 func (p *printer) ClearScreen() {
 	fmt.Fprint(os.Stdout, "\033[2J")
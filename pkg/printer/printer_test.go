@@ -51,3 +51,80 @@ foo1                foo2                foo3
 		})
 	}
 }
+
+func TestSortAndSelectColumns(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rows := [][]string{
+		{"NAME", "Node Count", "REGION"},
+		{"mc-b", "10", "us-east-1"},
+		{"mc-a", "9", "eu-west-1"},
+		{"mc-c", "100", "ap-south-1"},
+	}
+
+	testCases := []struct {
+		title   string
+		sortBy  string
+		columns []string
+		output  string
+		errMsg  string
+	}{
+		{
+			title:  "sort by string column",
+			sortBy: "name",
+			output: `NAME   Node Count   REGION
+mc-a   9            eu-west-1
+mc-b   10           us-east-1
+mc-c   100          ap-south-1
+`,
+		},
+		{
+			title:  "sort by numeric column",
+			sortBy: "node_count",
+			output: `NAME   Node Count   REGION
+mc-a   9            eu-west-1
+mc-b   10           us-east-1
+mc-c   100          ap-south-1
+`,
+		},
+		{
+			title:   "select and reorder columns",
+			sortBy:  "region",
+			columns: []string{"Region", "name"},
+			output: `REGION       NAME
+ap-south-1   mc-c
+eu-west-1    mc-a
+us-east-1    mc-b
+`,
+		},
+		{
+			title:  "unknown sort column",
+			sortBy: "status",
+			errMsg: `unknown column "status", available columns: NAME, Node Count, REGION`,
+		},
+		{
+			title:   "unknown column",
+			columns: []string{"name", "id"},
+			errMsg:  `unknown column "id"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			p := NewTablePrinter(buf, 1, 1, 3, ' ')
+			p.SortBy(tc.sortBy)
+			p.SelectColumns(tc.columns)
+			for _, row := range rows {
+				p.AddRow(row)
+			}
+			err := p.Flush()
+			if tc.errMsg != "" {
+				g.Expect(err).Should(MatchError(ContainSubstring(tc.errMsg)))
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(buf.String()).Should(Equal(tc.output))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+const (
+	// qrQuietZone is the number of light modules around a QR code, scanners need it to find the code
+	qrQuietZone = 2
+
+	// The code is drawn black on white whatever the colors of the terminal, scanners don't read inverted codes
+	qrColors     = "\x1b[30;107m"
+	qrResetColor = "\x1b[0m"
+)
+
+// PrintQRCode writes the text as a QR code drawn with half block characters, two modules per character,
+// e.g. to open a URL from a phone
+func PrintQRCode(w io.Writer, text string) error {
+	code, err := qr.Encode(text, qr.L)
+	if err != nil {
+		return fmt.Errorf("cannot encode a QR code: %w", err)
+	}
+
+	dark := func(x, y int) bool {
+		x, y = x-qrQuietZone, y-qrQuietZone
+		return x >= 0 && y >= 0 && x < code.Size && y < code.Size && code.Black(x, y)
+	}
+	size := code.Size + 2*qrQuietZone
+	var out strings.Builder
+	for y := 0; y < size; y += 2 {
+		out.WriteString(qrColors)
+		for x := 0; x < size; x++ {
+			switch top, bottom := dark(x, y), dark(x, y+1); {
+			case top && bottom:
+				out.WriteString("█")
+			case top:
+				out.WriteString("▀")
+			case bottom:
+				out.WriteString("▄")
+			default:
+				out.WriteString(" ")
+			}
+		}
+		out.WriteString(qrResetColor + "\n")
+	}
+	_, err = io.WriteString(w, out.String())
+	return err
+}
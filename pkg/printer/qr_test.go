@@ -0,0 +1,34 @@
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPrintQRCode(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &bytes.Buffer{}
+	g.Expect(PrintQRCode(out, "https://console.aws.amazon.com/")).To(Succeed())
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	// A version 2 code is 25 modules wide, 29 with the quiet zone, drawn on 15 lines
+	g.Expect(lines).To(HaveLen(15))
+	for _, line := range lines {
+		g.Expect(line).To(HavePrefix(qrColors))
+		g.Expect(line).To(HaveSuffix(qrResetColor))
+		g.Expect(utf8.RuneCountInString(strings.TrimSuffix(strings.TrimPrefix(line, qrColors), qrResetColor))).To(Equal(29))
+	}
+	// The quiet zone is light, the finder pattern of the top left corner starts below it
+	g.Expect(strings.TrimPrefix(lines[0], qrColors)).To(HavePrefix(strings.Repeat(" ", 29)))
+	g.Expect(strings.TrimPrefix(lines[1], qrColors)).To(HavePrefix("  █▀▀▀▀▀█"))
+}
+
+func TestPrintQRCodeTooLong(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(PrintQRCode(&bytes.Buffer{}, strings.Repeat("x", 4000))).NotTo(Succeed())
+}
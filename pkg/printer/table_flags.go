@@ -0,0 +1,27 @@
+package printer
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// TableFlags are the --sort-by and --columns flags of commands producing table output
+type TableFlags struct {
+	SortBy  string
+	Columns []string
+}
+
+func NewTableFlags() *TableFlags {
+	return &TableFlags{}
+}
+
+func (f *TableFlags) AddFlags(c *cobra.Command) {
+	c.Flags().StringVar(&f.SortBy, "sort-by", "", "Sort table output by the given column name (case-insensitive)")
+	c.Flags().StringSliceVar(&f.Columns, "columns", nil, "Comma-separated list of column names to include in table output, in the given order")
+}
+
+// Apply configures a table printer to sort and select columns according to the flags
+func (f *TableFlags) Apply(p *printer) *printer {
+	p.SortBy(f.SortBy)
+	p.SelectColumns(f.Columns)
+	return p
+}
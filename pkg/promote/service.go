@@ -2,9 +2,12 @@ package promote
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/openshift/osdctl/pkg/printer"
 	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
@@ -47,6 +50,24 @@ func (d *yamlDoc) Save() error {
 	return kyaml.WriteFile(d.rootNode, d.filePath)
 }
 
+// SaveWithDiff saves the document and writes the diff of the file content to w, so that the changes can be
+// reviewed before they are committed
+func (d *yamlDoc) SaveWithDiff(w io.Writer) error {
+	before, err := os.ReadFile(d.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' file: %v", d.filePath, err)
+	}
+	if err := d.Save(); err != nil {
+		return err
+	}
+	after, err := os.ReadFile(d.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' file: %v", d.filePath, err)
+	}
+	_, err = printer.PrintDiff(w, d.filePath, d.filePath, string(before), string(after), printer.DefaultDiffContext)
+	return err
+}
+
 type CodeComponent struct {
 	filePath string
 	node     *kyaml.RNode
@@ -407,7 +428,8 @@ func (p *resourceTemplatePromotion) promote(callbacks PromoteCallbacks, service
 			return err
 		}
 	}
-	err := service.Save()
+	fmt.Println("")
+	err := service.SaveWithDiff(os.Stdout)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package promote
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
 
@@ -475,3 +476,37 @@ var _ = Describe("Service struct", func() {
 
 	})
 })
+
+var _ = Describe("yamlDoc.SaveWithDiff", func() {
+	var data *TestData
+
+	BeforeEach(func() {
+		data = CreateDefaultTestData()
+	})
+
+	AfterEach(func() {
+		CleanupAllTestDataResources()
+	})
+
+	It("writes the diff of the saved file", func() {
+		application, err := readApplicationFromFile(filepath.Join(data.AppInterfacePath, "data/services/gen-app/app.yml"))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		component, err := application.GetComponentByName("default-component")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = component.AddBlockedVersion("abc123")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		out := &bytes.Buffer{}
+		err = application.SaveWithDiff(out)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring("+++ " + application.GetFilePath()))
+		Expect(out.String()).To(ContainSubstring("abc123"))
+
+		out.Reset()
+		err = application.SaveWithDiff(out)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.String()).To(BeEmpty())
+	})
+})
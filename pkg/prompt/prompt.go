@@ -0,0 +1,205 @@
+// Package prompt provides the interactive prompts used by osdctl commands.
+//
+// Prompts are answered automatically when --assume-yes is set, and fall back to their
+// default answer when osdctl runs non-interactively (--non-interactive or stdin is not a terminal)
+// so that commands never block waiting for input that cannot come.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+const (
+	AssumeYesFlag      = "assume-yes"
+	NonInteractiveFlag = "non-interactive"
+)
+
+// ErrNoDefault is returned when a prompt without a default answer is shown in non-interactive mode
+var ErrNoDefault = errors.New("cannot prompt for input in non-interactive mode and no default answer is available")
+
+var (
+	mu             sync.Mutex
+	assumeYes      bool
+	nonInteractive bool
+
+	in     io.Reader = os.Stdin
+	out    io.Writer = os.Stderr
+	reader *bufio.Reader
+
+	// isTerminal reports whether stdin is attached to a terminal
+	isTerminal = func() bool {
+		return term.IsTerminal(int(os.Stdin.Fd())) // #nosec G115 -- file descriptors fit into an int
+	}
+)
+
+// SetAssumeYes makes every confirmation prompt answer yes without asking
+func SetAssumeYes(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	assumeYes = enabled
+}
+
+// SetNonInteractive makes every prompt use its default answer without asking
+func SetNonInteractive(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	nonInteractive = enabled
+}
+
+// AssumeYes returns true if confirmations are answered with yes automatically
+func AssumeYes() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return assumeYes
+}
+
+// IsInteractive returns true if prompts can be shown to the user
+func IsInteractive() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return !nonInteractive && isTerminal()
+}
+
+// SetIO replaces the input and output used by the prompts and returns a function restoring the previous ones.
+// It also marks the input as a terminal, which is mostly useful for tests.
+func SetIO(newIn io.Reader, newOut io.Writer) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prevIn, prevOut, prevReader, prevIsTerminal := in, out, reader, isTerminal
+	in, out, reader, isTerminal = newIn, newOut, nil, func() bool { return true }
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		in, out, reader, isTerminal = prevIn, prevOut, prevReader, prevIsTerminal
+	}
+}
+
+// readLine reads a single trimmed line from the input. A shared reader is used so that
+// successive prompts don't lose input buffered by a previous one.
+func readLine() (string, error) {
+	mu.Lock()
+	if reader == nil {
+		reader = bufio.NewReader(in)
+	}
+	r := reader
+	mu.Unlock()
+
+	line, err := r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func printf(format string, a ...any) {
+	mu.Lock()
+	w := out
+	mu.Unlock()
+	_, _ = fmt.Fprintf(w, format, a...)
+}
+
+// Confirm asks a yes/no question and returns the answer.
+// An empty answer or a non-interactive session returns defaultYes, --assume-yes always returns true.
+func Confirm(question string, defaultYes bool) bool {
+	choices := "y/N"
+	if defaultYes {
+		choices = "Y/n"
+	}
+
+	if AssumeYes() {
+		printf("%s (%s): y (--%s)\n", question, choices, AssumeYesFlag)
+		return true
+	}
+	if !IsInteractive() {
+		answer := "n"
+		if defaultYes {
+			answer = "y"
+		}
+		printf("%s (%s): %s (non-interactive, using default)\n", question, choices, answer)
+		return defaultYes
+	}
+
+	for {
+		printf("%s (%s): ", question, choices)
+		response, err := readLine()
+		if err != nil {
+			printf("\n")
+			return false
+		}
+
+		switch strings.ToLower(response) {
+		case "":
+			return defaultYes
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		default:
+			printf("Invalid input. Expecting (y)es or (n)o\n")
+		}
+	}
+}
+
+// ConfirmPrompt asks the user whether to continue, defaulting to no
+func ConfirmPrompt() bool {
+	return Confirm("Continue?", false)
+}
+
+// Select asks the user to pick one of the options (case-insensitive) and returns it as given in options.
+// An empty answer or a non-interactive session returns defaultOption; ErrNoDefault is returned
+// in non-interactive mode when defaultOption is empty.
+func Select(question string, options []string, defaultOption string) (string, error) {
+	if !IsInteractive() {
+		if defaultOption == "" {
+			return "", ErrNoDefault
+		}
+		printf("%s (%s): %s (non-interactive, using default)\n", question, strings.Join(options, "/"), defaultOption)
+		return defaultOption, nil
+	}
+
+	for {
+		printf("%s (%s): ", question, strings.Join(options, "/"))
+		response, err := readLine()
+		if err != nil {
+			return "", err
+		}
+		if response == "" && defaultOption != "" {
+			return defaultOption, nil
+		}
+		for _, option := range options {
+			if strings.EqualFold(response, option) {
+				return option, nil
+			}
+		}
+		printf("Invalid response, expected one of '%s' (case-insensitive).\n", strings.Join(options, "', '"))
+	}
+}
+
+// Input asks the user for free text. An empty answer or a non-interactive session returns defaultValue.
+func Input(question string, defaultValue string) (string, error) {
+	if !IsInteractive() {
+		return defaultValue, nil
+	}
+
+	if defaultValue != "" {
+		printf("%s [default %s]: ", question, defaultValue)
+	} else {
+		printf("%s: ", question)
+	}
+	response, err := readLine()
+	if err != nil {
+		return "", err
+	}
+	if response == "" {
+		return defaultValue, nil
+	}
+	return response, nil
+}
@@ -0,0 +1,121 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		defaultYes     bool
+		assumeYes      bool
+		nonInteractive bool
+		expected       bool
+	}{
+		{name: "yes", input: "y\n", expected: true},
+		{name: "full yes in upper case", input: "YES\n", expected: true},
+		{name: "no", input: "n\n", defaultYes: true, expected: false},
+		{name: "empty answer uses default no", input: "\n", expected: false},
+		{name: "empty answer uses default yes", input: "\n", defaultYes: true, expected: true},
+		{name: "invalid answer asks again", input: "maybe\ny\n", expected: true},
+		{name: "closed input", input: "", defaultYes: true, expected: false},
+		{name: "assume yes does not read input", input: "n\n", assumeYes: true, expected: true},
+		{name: "non-interactive uses default", input: "y\n", nonInteractive: true, expected: false},
+		{name: "assume yes wins over non-interactive", assumeYes: true, nonInteractive: true, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			defer SetIO(strings.NewReader(tt.input), out)()
+			SetAssumeYes(tt.assumeYes)
+			SetNonInteractive(tt.nonInteractive)
+			defer SetAssumeYes(false)
+			defer SetNonInteractive(false)
+
+			assert.Equal(t, tt.expected, Confirm("Continue?", tt.defaultYes))
+			assert.Contains(t, out.String(), "Continue?")
+		})
+	}
+}
+
+func TestConfirmPromptSharesBufferedInput(t *testing.T) {
+	defer SetIO(strings.NewReader("y\nn\n"), &bytes.Buffer{})()
+
+	assert.True(t, ConfirmPrompt())
+	assert.False(t, ConfirmPrompt())
+}
+
+func TestSelect(t *testing.T) {
+	options := []string{"retry", "skip", "cancel"}
+
+	t.Run("case insensitive match", func(t *testing.T) {
+		defer SetIO(strings.NewReader("SKIP\n"), &bytes.Buffer{})()
+		answer, err := Select("What now?", options, "cancel")
+		assert.NoError(t, err)
+		assert.Equal(t, "skip", answer)
+	})
+
+	t.Run("invalid answer asks again", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		defer SetIO(strings.NewReader("abort\nretry\n"), out)()
+		answer, err := Select("What now?", options, "cancel")
+		assert.NoError(t, err)
+		assert.Equal(t, "retry", answer)
+		assert.Contains(t, out.String(), "Invalid response")
+	})
+
+	t.Run("non-interactive uses default", func(t *testing.T) {
+		defer SetIO(strings.NewReader("retry\n"), &bytes.Buffer{})()
+		SetNonInteractive(true)
+		defer SetNonInteractive(false)
+		answer, err := Select("What now?", options, "cancel")
+		assert.NoError(t, err)
+		assert.Equal(t, "cancel", answer)
+	})
+
+	t.Run("non-interactive without default", func(t *testing.T) {
+		defer SetIO(strings.NewReader("retry\n"), &bytes.Buffer{})()
+		SetNonInteractive(true)
+		defer SetNonInteractive(false)
+		_, err := Select("What now?", options, "")
+		assert.ErrorIs(t, err, ErrNoDefault)
+	})
+}
+
+func TestInput(t *testing.T) {
+	t.Run("reads the whole line", func(t *testing.T) {
+		defer SetIO(strings.NewReader("  resize requested by customer \n"), &bytes.Buffer{})()
+		answer, err := Input("Justification", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "resize requested by customer", answer)
+	})
+
+	t.Run("last line without newline", func(t *testing.T) {
+		defer SetIO(strings.NewReader("OHSS-1234"), &bytes.Buffer{})()
+		answer, err := Input("JIRA ID", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "OHSS-1234", answer)
+	})
+
+	t.Run("empty answer uses default", func(t *testing.T) {
+		defer SetIO(strings.NewReader("\n"), &bytes.Buffer{})()
+		answer, err := Input("JIRA ID", "OHSS-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "OHSS-1", answer)
+	})
+
+	t.Run("non-interactive uses default", func(t *testing.T) {
+		defer SetIO(strings.NewReader("OHSS-1234\n"), &bytes.Buffer{})()
+		SetNonInteractive(true)
+		defer SetNonInteractive(false)
+		answer, err := Input("JIRA ID", "OHSS-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "OHSS-1", answer)
+	})
+}
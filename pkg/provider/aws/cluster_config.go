@@ -0,0 +1,103 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	bpcloud "github.com/openshift/backplane-cli/cmd/ocm-backplane/cloud"
+	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/spf13/pflag"
+)
+
+// RegionFlag is the flag overriding the region of the cluster the AWS clients are created in
+const RegionFlag = "region"
+
+// ClusterConfigFactory creates the AWS configs of clusters, in the region of the cluster in OCM unless Region
+// overrides it. Commands add its --region flag rather than requiring the region of the cluster.
+type ClusterConfigFactory struct {
+	// Region overrides the region of the cluster when set
+	Region string
+
+	// credentials returns the config of the AWS account of the cluster, replaced in tests
+	credentials func(conn *sdk.Connection, cluster *cmv1.Cluster) (aws.Config, error)
+	// localConfig returns the config of the AWS credentials available locally, replaced in tests
+	localConfig func(ctx context.Context) (aws.Config, error)
+}
+
+// NewClusterConfigFactory returns a ClusterConfigFactory getting the AWS credentials of the clusters from backplane
+func NewClusterConfigFactory() *ClusterConfigFactory {
+	return &ClusterConfigFactory{
+		credentials: BackplaneConfig,
+		localConfig: func(ctx context.Context) (aws.Config, error) {
+			return config.LoadDefaultConfig(ctx)
+		},
+	}
+}
+
+// AddRegionFlag adds the --region flag overriding the region of the cluster to the flags
+func (f *ClusterConfigFactory) AddRegionFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&f.Region, RegionFlag, "", "The AWS region to use instead of the region of the cluster")
+}
+
+// ClusterRegion returns the region of the cluster, or the region overriding it
+func (f *ClusterConfigFactory) ClusterRegion(cluster *cmv1.Cluster) (string, error) {
+	if f.Region != "" {
+		return f.Region, nil
+	}
+	if region := cluster.Region().ID(); region != "" {
+		return region, nil
+	}
+	return "", fmt.Errorf("the region of cluster %s is unknown, specify it with --%s", cluster.ID(), RegionFlag)
+}
+
+// Config returns the AWS config of the account of the cluster in the region of ClusterRegion
+func (f *ClusterConfigFactory) Config(conn *sdk.Connection, cluster *cmv1.Cluster) (aws.Config, error) {
+	if !strings.EqualFold(cluster.CloudProvider().ID(), "aws") {
+		return aws.Config{}, fmt.Errorf("cluster %s is not an AWS cluster", cluster.ID())
+	}
+	region, err := f.ClusterRegion(cluster)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	cfg, err := f.credentials(conn, cluster)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	cfg.Region = region
+	return cfg, nil
+}
+
+// LocalConfig returns the config of the AWS credentials available locally for the commands run without a cluster,
+// in the region overriding the one of the local configuration when set
+func (f *ClusterConfigFactory) LocalConfig(ctx context.Context) (aws.Config, error) {
+	cfg, err := f.localConfig(ctx)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	if f.Region != "" {
+		cfg.Region = f.Region
+	}
+	return cfg, nil
+}
+
+// BackplaneConfig returns the config of the AWS credentials of the cluster from backplane, in the region of the cluster
+func BackplaneConfig(conn *sdk.Connection, cluster *cmv1.Cluster) (aws.Config, error) {
+	bp, err := bpconfig.GetBackplaneConfiguration()
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load backplane-cli config: %v", err)
+	}
+
+	qc := &bpcloud.QueryConfig{
+		BackplaneConfiguration: bp,
+		OcmConnection:          conn,
+		Cluster:                cluster,
+	}
+
+	return qc.GetAWSV2Config()
+}
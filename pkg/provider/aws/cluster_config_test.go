@@ -0,0 +1,85 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/gomega"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func newTestConfigCluster(t *testing.T, provider, region string) *cmv1.Cluster {
+	cluster, err := cmv1.NewCluster().ID("1a2b3c").
+		CloudProvider(cmv1.NewCloudProvider().ID(provider)).
+		Region(cmv1.NewCloudRegion().ID(region)).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cluster
+}
+
+func newTestClusterConfigFactory(region string) *ClusterConfigFactory {
+	return &ClusterConfigFactory{
+		Region: region,
+		credentials: func(*sdk.Connection, *cmv1.Cluster) (aws.Config, error) {
+			return aws.Config{Region: "us-east-1"}, nil
+		},
+		localConfig: func(context.Context) (aws.Config, error) {
+			return aws.Config{}, nil
+		},
+	}
+}
+
+func TestClusterConfigFactoryConfig(t *testing.T) {
+	testCases := []struct {
+		name           string
+		provider       string
+		clusterRegion  string
+		region         string
+		expectedRegion string
+		expectedErr    string
+	}{
+		{name: "cluster region", provider: "aws", clusterRegion: "eu-west-1", expectedRegion: "eu-west-1"},
+		{name: "region override", provider: "aws", clusterRegion: "eu-west-1", region: "us-west-2", expectedRegion: "us-west-2"},
+		{name: "unknown region", provider: "aws", expectedErr: "the region of cluster 1a2b3c is unknown, specify it with --region"},
+		{name: "gcp cluster", provider: "gcp", clusterRegion: "us-east1", expectedErr: "cluster 1a2b3c is not an AWS cluster"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			cfg, err := newTestClusterConfigFactory(tc.region).Config(nil, newTestConfigCluster(t, tc.provider, tc.clusterRegion))
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(tc.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cfg.Region).To(Equal(tc.expectedRegion))
+		})
+	}
+}
+
+func TestClusterConfigFactoryCredentialsError(t *testing.T) {
+	g := NewGomegaWithT(t)
+	f := newTestClusterConfigFactory("")
+	f.credentials = func(*sdk.Connection, *cmv1.Cluster) (aws.Config, error) {
+		return aws.Config{}, errors.New("backplane unavailable")
+	}
+	_, err := f.Config(nil, newTestConfigCluster(t, "aws", "eu-west-1"))
+	g.Expect(err).To(MatchError("backplane unavailable"))
+}
+
+func TestClusterConfigFactoryLocalConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cfg, err := newTestClusterConfigFactory("").LocalConfig(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Region).To(BeEmpty())
+
+	cfg, err = newTestClusterConfigFactory("ap-south-1").LocalConfig(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Region).To(Equal("ap-south-1"))
+}
@@ -56,5 +56,5 @@ func DeleteUserAccessKeys(awsClient Client, username *string) error {
 }
 
 func GenerateRoleARN(accountId, roleName string) string {
-	return fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, roleName)
+	return GenerateRoleARNForPartition(PartitionID, accountId, roleName)
 }
@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultRegion      = "us-east-1"     // Default region of the AWS Standard partition.
+	DefaultUsGovRegion = "us-gov-west-1" // Default region of the AWS GovCloud (US) partition.
+	DefaultChinaRegion = "cn-north-1"    // Default region of the AWS China partition.
+
+	usGovRegionPrefix = "us-gov-"
+	chinaRegionPrefix = "cn-"
+)
+
+// GetPartitionForRegion returns the AWS partition a region belongs to
+func GetPartitionForRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, usGovRegionPrefix):
+		return UsGovPartitionID
+	case strings.HasPrefix(region, chinaRegionPrefix):
+		return ChinaPartitionID
+	}
+	return PartitionID
+}
+
+// GetDefaultRegion returns the default region of a given partition.
+// This is also the region global services (IAM, STS, Organizations) log their CloudTrail events to.
+func GetDefaultRegion(partition string) string {
+	switch partition {
+	case UsGovPartitionID:
+		return DefaultUsGovRegion
+	case ChinaPartitionID:
+		return DefaultChinaRegion
+	}
+	return DefaultRegion
+}
+
+// GenerateRoleARNForPartition returns the ARN of an IAM role in the given partition
+func GenerateRoleARNForPartition(partition, accountId, roleName string) string {
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, accountId, roleName)
+}
+
+// GenerateUserARN returns the ARN of an IAM user in the given partition
+func GenerateUserARN(partition, accountId, userName string) string {
+	return fmt.Sprintf("arn:%s:iam::%s:user/%s", partition, accountId, userName)
+}
+
+// GenerateManagedPolicyARN returns the ARN of an AWS managed IAM policy in the given partition
+func GenerateManagedPolicyARN(partition, policyName string) string {
+	return fmt.Sprintf("arn:%s:iam::aws:policy/%s", partition, policyName)
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGetPartitionForRegion(t *testing.T) {
+	g := NewGomegaWithT(t)
+	testCases := []struct {
+		region   string
+		expected string
+	}{
+		{region: "us-east-1", expected: PartitionID},
+		{region: "eu-west-1", expected: PartitionID},
+		{region: "", expected: PartitionID},
+		{region: "us-gov-west-1", expected: UsGovPartitionID},
+		{region: "us-gov-east-1", expected: UsGovPartitionID},
+		{region: "cn-north-1", expected: ChinaPartitionID},
+		{region: "cn-northwest-1", expected: ChinaPartitionID},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.region, func(t *testing.T) {
+			g.Expect(GetPartitionForRegion(tc.region)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestGetDefaultRegion(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(GetDefaultRegion(PartitionID)).To(Equal("us-east-1"))
+	g.Expect(GetDefaultRegion(UsGovPartitionID)).To(Equal("us-gov-west-1"))
+	g.Expect(GetDefaultRegion(ChinaPartitionID)).To(Equal("cn-north-1"))
+	g.Expect(GetDefaultRegion("")).To(Equal("us-east-1"))
+}
+
+func TestGenerateARNs(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(GenerateRoleARN("123456789012", "OrganizationAccountAccessRole")).
+		To(Equal("arn:aws:iam::123456789012:role/OrganizationAccountAccessRole"))
+	g.Expect(GenerateRoleARNForPartition(UsGovPartitionID, "123456789012", "OrganizationAccountAccessRole")).
+		To(Equal("arn:aws-us-gov:iam::123456789012:role/OrganizationAccountAccessRole"))
+	g.Expect(GenerateUserARN(UsGovPartitionID, "123456789012", "osdManagedAdmin")).
+		To(Equal("arn:aws-us-gov:iam::123456789012:user/osdManagedAdmin"))
+	g.Expect(GenerateManagedPolicyARN(UsGovPartitionID, "AdministratorAccess")).
+		To(Equal("arn:aws-us-gov:iam::aws:policy/AdministratorAccess"))
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
@@ -19,6 +20,10 @@ const (
 
 	PartitionID      = "aws"        // AWS Standard partition.
 	UsGovPartitionID = "aws-us-gov" // AWS GovCloud (US) partition.
+	ChinaPartitionID = "aws-cn"     // AWS China partition.
+
+	MinConsoleSessionSeconds = 900   // Shortest console session, the minimum duration of assumed role credentials.
+	MaxConsoleSessionSeconds = 43200 // Longest console session, the maximum duration of assumed role credentials.
 )
 
 // Type for JSON response from Federation end point
@@ -55,6 +60,8 @@ func GetFederationEndpointUrl(partition string) (string, error) {
 	case UsGovPartitionID:
 		// us-gov-west-1 endpoint
 		return "https://signin.amazonaws-us-gov.com/federation", nil
+	case ChinaPartitionID:
+		return "https://signin.amazonaws.cn/federation", nil
 	default:
 		return "", fmt.Errorf("invalid partition %s", partition)
 	}
@@ -69,13 +76,57 @@ func GetConsoleUrl(partition string) (string, error) {
 	case UsGovPartitionID:
 		// us-gov-west-1 endpoint
 		return "https://console.amazonaws-us-gov.com/", nil
+	case ChinaPartitionID:
+		return "https://console.amazonaws.cn/", nil
 	default:
 		return "", fmt.Errorf("invalid partition %s", partition)
 	}
 }
 
+// ConsoleDestination returns the URL of a page of the console of the partition, in the region.
+// The page is a path relative to the console home, e.g. "ec2/home#Instances:", an empty page is the home page.
+func ConsoleDestination(partition, region, page string) (string, error) {
+	consoleUrl, err := GetConsoleUrl(partition)
+	if err != nil {
+		return "", err
+	}
+	destination, err := url.Parse(consoleUrl)
+	if err != nil {
+		return "", err
+	}
+	pageUrl, err := url.Parse(strings.TrimPrefix(page, "/"))
+	if err != nil {
+		return "", fmt.Errorf("cannot parse the console page '%s': %w", page, err)
+	}
+	if pageUrl.Scheme != "" || pageUrl.Host != "" {
+		return "", fmt.Errorf("the console page '%s' must be a path of the console, e.g. ec2/home", page)
+	}
+
+	// The standard partition has a console endpoint per region, the others select the region with the query only
+	if region != "" && partition == PartitionID {
+		destination.Host = fmt.Sprintf("%s.%s", region, destination.Host)
+	}
+	destination = destination.ResolveReference(pageUrl)
+	if query := destination.Query(); region != "" && query.Get("region") == "" {
+		query.Set("region", region)
+		destination.RawQuery = query.Encode()
+	}
+	return destination.String(), nil
+}
+
+// EC2InstanceConsolePage returns the console page of an EC2 instance, for ConsoleDestination
+func EC2InstanceConsolePage(region, instanceID string) string {
+	return fmt.Sprintf("ec2/home?region=%s#InstanceDetails:instanceId=%s", url.QueryEscape(region), url.QueryEscape(instanceID))
+}
+
 // RequestSignInToken makes an HTTP request to retrieve an AWS Sign-In Token via the AWS Federation endpoint
 func RequestSignInToken(awsClient Client, durationSeconds *int32, sessionName, roleArn *string) (string, error) {
+	return RequestSignInURL(awsClient, durationSeconds, sessionName, roleArn, "")
+}
+
+// RequestSignInURL is RequestSignInToken landing on the destination, a console URL of the partition (see ConsoleDestination).
+// An empty destination lands on the console home page.
+func RequestSignInURL(awsClient Client, durationSeconds *int32, sessionName, roleArn *string, destination string) (string, error) {
 	credentials, err := GetAssumeRoleCredentials(awsClient, durationSeconds, sessionName, roleArn)
 	if err != nil {
 		return "", err
@@ -100,7 +151,7 @@ func RequestSignInToken(awsClient Client, durationSeconds *int32, sessionName, r
 		return "", fmt.Errorf("sign-in token is empty")
 	}
 
-	signedFederationURL, err := formatSignInURL(partition, signInToken)
+	signedFederationURL, err := formatSignInURL(partition, signInToken, destination)
 	if err != nil {
 		return "", err
 	}
@@ -190,16 +241,19 @@ func requestSignedURL(baseUrl string, jsonCredentials []byte) (string, error) {
 	return resp.SigninToken, nil
 }
 
-// formatSignInURL builds and format the Sign-In URL
-func formatSignInURL(partition, signInToken string) (*url.URL, error) {
+// formatSignInURL builds and format the Sign-In URL, landing on the console home page when the destination is empty
+func formatSignInURL(partition, signInToken, destination string) (*url.URL, error) {
 	federationEndpointUrl, err := GetFederationEndpointUrl(partition)
 	if err != nil {
 		return nil, err
 	}
 
-	consoleUrl, err := GetConsoleUrl(partition)
-	if err != nil {
-		return nil, err
+	consoleUrl := destination
+	if consoleUrl == "" {
+		consoleUrl, err = GetConsoleUrl(partition)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	signInFederationURL, err := url.Parse(federationEndpointUrl)
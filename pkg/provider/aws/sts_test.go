@@ -152,6 +152,67 @@ func TestGetConsoleUrl(t *testing.T) {
 	}
 }
 
+func TestConsoleDestination(t *testing.T) {
+	g := NewGomegaWithT(t)
+	testCases := []struct {
+		title       string
+		partition   string
+		region      string
+		page        string
+		expected    string
+		errExpected bool
+	}{
+		{
+			title:     "home page of the standard partition",
+			partition: PartitionID,
+			expected:  "https://console.aws.amazon.com/",
+		},
+		{
+			title:     "regional endpoint of the standard partition",
+			partition: PartitionID,
+			region:    "eu-west-1",
+			page:      "/cloudwatch/home",
+			expected:  "https://eu-west-1.console.aws.amazon.com/cloudwatch/home?region=eu-west-1",
+		},
+		{
+			title:     "EC2 instance in GovCloud",
+			partition: UsGovPartitionID,
+			region:    "us-gov-east-1",
+			page:      EC2InstanceConsolePage("us-gov-east-1", "i-0123456789abcdef0"),
+			expected:  "https://console.amazonaws-us-gov.com/ec2/home?region=us-gov-east-1#InstanceDetails:instanceId=i-0123456789abcdef0",
+		},
+		{
+			title:     "China partition",
+			partition: ChinaPartitionID,
+			region:    "cn-northwest-1",
+			expected:  "https://console.amazonaws.cn/?region=cn-northwest-1",
+		},
+		{
+			title:       "page on another host",
+			partition:   PartitionID,
+			page:        "https://example.com/ec2/home",
+			errExpected: true,
+		},
+		{
+			title:       "invalid partition",
+			partition:   "hello",
+			errExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			destination, err := ConsoleDestination(tc.partition, tc.region, tc.page)
+			if tc.errExpected {
+				g.Expect(err).Should(HaveOccurred())
+			} else {
+				g.Expect(err).ShouldNot(HaveOccurred())
+				g.Expect(destination).Should(Equal(tc.expected))
+			}
+		})
+	}
+}
+
 func TestGetAssumeRoleCredentials(t *testing.T) {
 	g := NewGomegaWithT(t)
 	testCases := []struct {
@@ -230,6 +291,7 @@ func TestFormatSignInURL(t *testing.T) {
 		title       string
 		partition   string
 		signInToken string
+		destination string
 		base        string
 	}{
 		{
@@ -244,11 +306,18 @@ func TestFormatSignInURL(t *testing.T) {
 			signInToken: "bar",
 			base:        "https://signin.amazonaws-us-gov.com/federation?Action=login&Destination=https%3A%2F%2Fconsole.amazonaws-us-gov.com%2F&Issuer=Red+Hat+SRE&SigninToken=",
 		},
+		{
+			title:       "China partition with a destination",
+			partition:   ChinaPartitionID,
+			signInToken: "baz",
+			destination: "https://console.amazonaws.cn/ec2/home?region=cn-north-1",
+			base:        "https://signin.amazonaws.cn/federation?Action=login&Destination=https%3A%2F%2Fconsole.amazonaws.cn%2Fec2%2Fhome%3Fregion%3Dcn-north-1&Issuer=Red+Hat+SRE&SigninToken=",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.title, func(t *testing.T) {
-			u, err := formatSignInURL(tc.partition, tc.signInToken)
+			u, err := formatSignInURL(tc.partition, tc.signInToken, tc.destination)
 			g.Expect(err).ShouldNot(HaveOccurred())
 			g.Expect(u.String()).Should(Equal(tc.base + tc.signInToken))
 		})
@@ -0,0 +1,128 @@
+package gcp
+
+// Generate client mocks for testing
+//go:generate mockgen -source=client.go -package=mock -destination=mock/client.go
+
+import (
+	"context"
+	"errors"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/iterator"
+)
+
+// TODO: Add more methods when needed
+type Client interface {
+	// compute
+	ListFirewalls(ctx context.Context, project string) ([]*computepb.Firewall, error)
+	ListRouters(ctx context.Context, project, region string) ([]*computepb.Router, error)
+	GetRegion(ctx context.Context, project, region string) (*computepb.Region, error)
+	// ResetInstance hard resets the instance, like pressing the reset button of a machine, and waits for the operation
+	ResetInstance(ctx context.Context, project, zone, instance string) error
+
+	// resource manager
+	GetProjectIamPolicy(ctx context.Context, project string) (*cloudresourcemanager.Policy, error)
+
+	Close() error
+}
+
+// GcpClient implements Client with the application default credentials, e.g. those of "gcloud auth application-default login"
+type GcpClient struct {
+	firewalls       *compute.FirewallsClient
+	routers         *compute.RoutersClient
+	regions         *compute.RegionsClient
+	instances       *compute.InstancesClient
+	resourceManager *cloudresourcemanager.Service
+}
+
+// NewGcpClient creates a Client using the application default credentials
+func NewGcpClient(ctx context.Context) (Client, error) {
+	c := &GcpClient{}
+	var err error
+	if c.firewalls, err = compute.NewFirewallsRESTClient(ctx); err != nil {
+		return nil, err
+	}
+	if c.routers, err = compute.NewRoutersRESTClient(ctx); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	if c.regions, err = compute.NewRegionsRESTClient(ctx); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	if c.instances, err = compute.NewInstancesRESTClient(ctx); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	if c.resourceManager, err = cloudresourcemanager.NewService(ctx); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *GcpClient) ListFirewalls(ctx context.Context, project string) ([]*computepb.Firewall, error) {
+	var firewalls []*computepb.Firewall
+	it := c.firewalls.List(ctx, &computepb.ListFirewallsRequest{Project: project})
+	for {
+		firewall, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return firewalls, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		firewalls = append(firewalls, firewall)
+	}
+}
+
+func (c *GcpClient) ListRouters(ctx context.Context, project, region string) ([]*computepb.Router, error) {
+	var routers []*computepb.Router
+	it := c.routers.List(ctx, &computepb.ListRoutersRequest{Project: project, Region: region})
+	for {
+		router, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return routers, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		routers = append(routers, router)
+	}
+}
+
+func (c *GcpClient) GetRegion(ctx context.Context, project, region string) (*computepb.Region, error) {
+	return c.regions.Get(ctx, &computepb.GetRegionRequest{Project: project, Region: region})
+}
+
+func (c *GcpClient) ResetInstance(ctx context.Context, project, zone, instance string) error {
+	op, err := c.instances.Reset(ctx, &computepb.ResetInstanceRequest{Project: project, Zone: zone, Instance: instance})
+	if err != nil {
+		return err
+	}
+	return op.Wait(ctx)
+}
+
+func (c *GcpClient) GetProjectIamPolicy(ctx context.Context, project string) (*cloudresourcemanager.Policy, error) {
+	return c.resourceManager.Projects.GetIamPolicy(project, &cloudresourcemanager.GetIamPolicyRequest{}).Context(ctx).Do()
+}
+
+// Close releases the connections of the compute clients
+func (c *GcpClient) Close() error {
+	var errs []error
+	if c.firewalls != nil {
+		errs = append(errs, c.firewalls.Close())
+	}
+	if c.routers != nil {
+		errs = append(errs, c.routers.Close())
+	}
+	if c.regions != nil {
+		errs = append(errs, c.regions.Close())
+	}
+	if c.instances != nil {
+		errs = append(errs, c.instances.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: client.go
+//
+// Generated by this command:
+//
+//	mockgen -source=client.go -package=mock -destination=mock/client.go
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	gomock "go.uber.org/mock/gomock"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+	isgomock struct{}
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockClient) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockClient)(nil).Close))
+}
+
+// GetProjectIamPolicy mocks base method.
+func (m *MockClient) GetProjectIamPolicy(ctx context.Context, project string) (*cloudresourcemanager.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectIamPolicy", ctx, project)
+	ret0, _ := ret[0].(*cloudresourcemanager.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectIamPolicy indicates an expected call of GetProjectIamPolicy.
+func (mr *MockClientMockRecorder) GetProjectIamPolicy(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectIamPolicy", reflect.TypeOf((*MockClient)(nil).GetProjectIamPolicy), ctx, project)
+}
+
+// GetRegion mocks base method.
+func (m *MockClient) GetRegion(ctx context.Context, project, region string) (*computepb.Region, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegion", ctx, project, region)
+	ret0, _ := ret[0].(*computepb.Region)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegion indicates an expected call of GetRegion.
+func (mr *MockClientMockRecorder) GetRegion(ctx, project, region any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegion", reflect.TypeOf((*MockClient)(nil).GetRegion), ctx, project, region)
+}
+
+// ListFirewalls mocks base method.
+func (m *MockClient) ListFirewalls(ctx context.Context, project string) ([]*computepb.Firewall, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFirewalls", ctx, project)
+	ret0, _ := ret[0].([]*computepb.Firewall)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFirewalls indicates an expected call of ListFirewalls.
+func (mr *MockClientMockRecorder) ListFirewalls(ctx, project any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFirewalls", reflect.TypeOf((*MockClient)(nil).ListFirewalls), ctx, project)
+}
+
+// ListRouters mocks base method.
+func (m *MockClient) ListRouters(ctx context.Context, project, region string) ([]*computepb.Router, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRouters", ctx, project, region)
+	ret0, _ := ret[0].([]*computepb.Router)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRouters indicates an expected call of ListRouters.
+func (mr *MockClientMockRecorder) ListRouters(ctx, project, region any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRouters", reflect.TypeOf((*MockClient)(nil).ListRouters), ctx, project, region)
+}
+
+// ResetInstance mocks base method.
+func (m *MockClient) ResetInstance(ctx context.Context, project, zone, instance string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetInstance", ctx, project, zone, instance)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetInstance indicates an expected call of ResetInstance.
+func (mr *MockClientMockRecorder) ResetInstance(ctx, project, zone, instance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetInstance", reflect.TypeOf((*MockClient)(nil).ResetInstance), ctx, project, zone, instance)
+}
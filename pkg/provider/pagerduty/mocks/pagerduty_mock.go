@@ -41,6 +41,51 @@ func (m *MockpdClientInterface) EXPECT() *MockpdClientInterfaceMockRecorder {
 	return m.recorder
 }
 
+// CreateIncidentNoteWithContext mocks base method.
+func (m *MockpdClientInterface) CreateIncidentNoteWithContext(arg0 context.Context, arg1 string, arg2 pagerduty.IncidentNote) (*pagerduty.IncidentNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncidentNoteWithContext", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pagerduty.IncidentNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIncidentNoteWithContext indicates an expected call of CreateIncidentNoteWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) CreateIncidentNoteWithContext(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentNoteWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).CreateIncidentNoteWithContext), arg0, arg1, arg2)
+}
+
+// GetCurrentUserWithContext mocks base method.
+func (m *MockpdClientInterface) GetCurrentUserWithContext(arg0 context.Context, arg1 pagerduty.GetCurrentUserOptions) (*pagerduty.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentUserWithContext", arg0, arg1)
+	ret0, _ := ret[0].(*pagerduty.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentUserWithContext indicates an expected call of GetCurrentUserWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) GetCurrentUserWithContext(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUserWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).GetCurrentUserWithContext), arg0, arg1)
+}
+
+// ListIncidentAlertsWithContext mocks base method.
+func (m *MockpdClientInterface) ListIncidentAlertsWithContext(arg0 context.Context, arg1 string, arg2 pagerduty.ListIncidentAlertsOptions) (*pagerduty.ListAlertsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentAlertsWithContext", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pagerduty.ListAlertsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentAlertsWithContext indicates an expected call of ListIncidentAlertsWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) ListIncidentAlertsWithContext(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentAlertsWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListIncidentAlertsWithContext), arg0, arg1, arg2)
+}
+
 // ListIncidentsWithContext mocks base method.
 func (m *MockpdClientInterface) ListIncidentsWithContext(arg0 context.Context, arg1 pagerduty.ListIncidentsOptions) (*pagerduty.ListIncidentsResponse, error) {
 	m.ctrl.T.Helper()
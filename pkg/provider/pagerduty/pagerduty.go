@@ -6,6 +6,7 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -28,6 +29,9 @@ type IncidentOccurrenceTracker struct {
 type pdClientInterface interface {
 	ListIncidentsWithContext(context.Context, pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error)
 	ListServicesWithContext(context.Context, pd.ListServiceOptions) (*pd.ListServiceResponse, error)
+	ListIncidentAlertsWithContext(context.Context, string, pd.ListIncidentAlertsOptions) (*pd.ListAlertsResponse, error)
+	GetCurrentUserWithContext(context.Context, pd.GetCurrentUserOptions) (*pd.User, error)
+	CreateIncidentNoteWithContext(context.Context, string, pd.IncidentNote) (*pd.IncidentNote, error)
 }
 
 type client struct {
@@ -213,3 +217,89 @@ func (c *client) GetHistoricalAlertsForCluster(pdServiceIDs []string) (map[strin
 	return incidentMap, nil
 
 }
+
+// GetOpenIncidents returns the triggered and acknowledged incidents of the teams of the client, or of every team
+// when none is set
+func (c *client) GetOpenIncidents() ([]pd.Incident, error) {
+	var incidents []pd.Incident
+	var limit uint = 100
+	for offset := uint(0); ; offset += limit {
+		listIncidentsResponse, err := c.pdclient.ListIncidentsWithContext(
+			context.TODO(),
+			pd.ListIncidentsOptions{
+				TeamIDs:  c.teamIds,
+				Statuses: []string{"triggered", "acknowledged"},
+				Limit:    limit,
+				Offset:   offset,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the open incidents: %w", err)
+		}
+
+		incidents = append(incidents, listIncidentsResponse.Incidents...)
+
+		if !listIncidentsResponse.More {
+			break
+		}
+	}
+	return incidents, nil
+}
+
+// GetClusterIDsForIncident returns the IDs of the clusters referenced by the alerts of a PagerDuty incident,
+// in the order the alerts were listed and without duplicates
+func (c *client) GetClusterIDsForIncident(incidentID string) ([]string, error) {
+	var clusterIDs []string
+	var limit uint = 100
+	for offset := uint(0); ; offset += limit {
+		alertsResponse, err := c.pdclient.ListIncidentAlertsWithContext(
+			context.TODO(),
+			incidentID,
+			pd.ListIncidentAlertsOptions{Limit: limit, Offset: offset},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the alerts of incident %s: %w", incidentID, err)
+		}
+
+		for _, alert := range alertsResponse.Alerts {
+			clusterID := clusterIDFromAlertBody(alert.Body)
+			if clusterID != "" && !slices.Contains(clusterIDs, clusterID) {
+				clusterIDs = append(clusterIDs, clusterID)
+			}
+		}
+
+		if !alertsResponse.More {
+			break
+		}
+	}
+	return clusterIDs, nil
+}
+
+// clusterIDFromAlertBody reads the cluster ID from the custom details of an alert, which are set either
+// directly by the Events API or under the common event format details
+func clusterIDFromAlertBody(body map[string]interface{}) string {
+	details, _ := body["details"].(map[string]interface{})
+	if clusterID, ok := details["cluster_id"].(string); ok && clusterID != "" {
+		return clusterID
+	}
+	cefDetails, _ := body["cef_details"].(map[string]interface{})
+	details, _ = cefDetails["details"].(map[string]interface{})
+	clusterID, _ := details["cluster_id"].(string)
+	return clusterID
+}
+
+// AddIncidentNote adds a note to a PagerDuty incident on behalf of the user owning the token
+func (c *client) AddIncidentNote(incidentID, content string) error {
+	// Notes are attributed to the user passed in the From header, which must be a valid PagerDuty user
+	user, err := c.pdclient.GetCurrentUserWithContext(context.TODO(), pd.GetCurrentUserOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get the current PagerDuty user: %w", err)
+	}
+
+	note := pd.IncidentNote{Content: content}
+	note.User.Summary = user.Email
+	if _, err := c.pdclient.CreateIncidentNoteWithContext(context.TODO(), incidentID, note); err != nil {
+		return fmt.Errorf("failed to add a note to incident %s: %w", incidentID, err)
+	}
+	return nil
+}
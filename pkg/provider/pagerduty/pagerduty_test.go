@@ -191,5 +191,95 @@ var _ = Describe("Tests the Pagerduty Provider", func() {
 				})
 			})
 		})
+
+		Context("GetClusterIDsForIncident", func() {
+			alert := func(body map[string]interface{}) pd.IncidentAlert {
+				return pd.IncidentAlert{Body: body}
+			}
+
+			It("Returns an error from the pd client if there's an error with the request", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListIncidentAlertsWithContext(gomock.Any(), "Q1", gomock.Any()).Return(nil, fmt.Errorf("An error"))
+				pdProvider.pdclient = m
+
+				ids, err := pdProvider.GetClusterIDsForIncident("Q1")
+				Expect(ids).To(BeEmpty())
+				Expect(err.Error()).To(ContainSubstring("An error"))
+			})
+
+			It("Collects the unique cluster IDs across all pages of alerts", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListIncidentAlertsWithContext(gomock.Any(), "Q1", gomock.Any()).Return(&pd.ListAlertsResponse{
+					APIListObject: pd.APIListObject{More: true},
+					Alerts: []pd.IncidentAlert{
+						alert(map[string]interface{}{"details": map[string]interface{}{"cluster_id": "cluster-a"}}),
+						alert(map[string]interface{}{"details": map[string]interface{}{"summary": "no cluster"}}),
+					},
+				}, nil)
+				m.EXPECT().ListIncidentAlertsWithContext(gomock.Any(), "Q1", gomock.Any()).Return(&pd.ListAlertsResponse{
+					Alerts: []pd.IncidentAlert{
+						alert(map[string]interface{}{"cef_details": map[string]interface{}{"details": map[string]interface{}{"cluster_id": "cluster-b"}}}),
+						alert(map[string]interface{}{"details": map[string]interface{}{"cluster_id": "cluster-a"}}),
+					},
+				}, nil)
+				pdProvider.pdclient = m
+
+				ids, err := pdProvider.GetClusterIDsForIncident("Q1")
+				Expect(err).To(BeNil())
+				Expect(ids).To(Equal([]string{"cluster-a", "cluster-b"}))
+			})
+		})
+
+		Context("AddIncidentNote", func() {
+			It("Attributes the note to the current user", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(&pd.User{Email: "jdoe@example.com"}, nil)
+				m.EXPECT().CreateIncidentNoteWithContext(gomock.Any(), "Q1", gomock.Any()).DoAndReturn(
+					func(_ interface{}, _ string, note pd.IncidentNote) (*pd.IncidentNote, error) {
+						Expect(note.User.Summary).To(Equal("jdoe@example.com"))
+						Expect(note.Content).To(Equal("CAD report"))
+						return &note, nil
+					})
+				pdProvider.pdclient = m
+
+				Expect(pdProvider.AddIncidentNote("Q1", "CAD report")).To(Succeed())
+			})
+
+			It("Returns an error when the current user can't be resolved", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("An error"))
+				pdProvider.pdclient = m
+
+				err := pdProvider.AddIncidentNote("Q1", "CAD report")
+				Expect(err.Error()).To(ContainSubstring("An error"))
+			})
+		})
+		Context("GetOpenIncidents", func() {
+			It("Lists the open incidents of the teams across pages", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				first, second := generateIncident(), generateIncident()
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), pd.ListIncidentsOptions{
+					TeamIDs:  []string{"TEAM1"},
+					Statuses: []string{"triggered", "acknowledged"},
+					Limit:    100,
+				}).Return(&pd.ListIncidentsResponse{APIListObject: pd.APIListObject{More: true}, Incidents: []pd.Incident{first}}, nil)
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).Return(&pd.ListIncidentsResponse{Incidents: []pd.Incident{second}}, nil)
+				pdProvider.WithTeamIdList([]string{"TEAM1"})
+				pdProvider.pdclient = m
+
+				incidents, err := pdProvider.GetOpenIncidents()
+				Expect(err).To(BeNil())
+				Expect(incidents).To(Equal([]pd.Incident{first, second}))
+			})
+
+			It("Returns the errors of PagerDuty", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("An error"))
+				pdProvider.pdclient = m
+
+				_, err := pdProvider.GetOpenIncidents()
+				Expect(err.Error()).To(ContainSubstring("An error"))
+			})
+		})
 	})
 })
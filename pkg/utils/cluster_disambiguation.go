@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
+)
+
+// maxClusterCandidates is the number of clusters offered when a name matches several
+const maxClusterCandidates = 20
+
+// subscriptionCandidates returns the clusters of the subscriptions matching the search, and how many match
+func subscriptionCandidates(conn *sdk.Connection, subsSearch string) ([]*cmv1.Cluster, int, error) {
+	subsListResponse, err := conn.AccountsMgmt().V1().Subscriptions().List().
+		Search(subsSearch).
+		Size(maxClusterCandidates).
+		Send()
+	if err != nil {
+		return nil, 0, ClassifyError(err)
+	}
+	var ids []string
+	for _, sub := range subsListResponse.Items().Slice() {
+		if id, ok := sub.GetClusterID(); ok && id != "" {
+			ids = append(ids, fmt.Sprintf("'%s'", id))
+		}
+	}
+	if len(ids) == 0 {
+		return nil, subsListResponse.Total(), nil
+	}
+	clusters, _, err := clusterCandidates(conn, fmt.Sprintf("id in (%s)", strings.Join(ids, ", ")))
+	return clusters, subsListResponse.Total(), err
+}
+
+// clusterCandidates returns the clusters matching the search, and how many match
+func clusterCandidates(conn *sdk.Connection, clustersSearch string) ([]*cmv1.Cluster, int, error) {
+	clustersListResponse, err := conn.ClustersMgmt().V1().Clusters().List().
+		Search(clustersSearch).
+		Size(maxClusterCandidates).
+		Send()
+	if err != nil {
+		return nil, 0, ClassifyError(err)
+	}
+	return clustersListResponse.Items().Slice(), clustersListResponse.Total(), nil
+}
+
+// disambiguateCluster lists the clusters matching an ambiguous key and asks the user to pick one of them.
+// Without an interactive terminal, or when the candidates can't be listed, the key is reported as ambiguous.
+func disambiguateCluster(key string, matches int, list func() ([]*cmv1.Cluster, int, error)) (*cmv1.Cluster, error) {
+	candidates, total, err := list()
+	if err != nil || len(candidates) == 0 {
+		return nil, &ClusterNotFoundError{Key: key, Matches: matches}
+	}
+	return pickCluster(key, candidates, max(matches, total))
+}
+
+// pickCluster asks the user which of the candidates the key refers to, or fails listing them when the session isn't
+// interactive
+func pickCluster(key string, candidates []*cmv1.Cluster, matches int) (*cmv1.Cluster, error) {
+	if !prompt.IsInteractive() {
+		return nil, &ClusterNotFoundError{Key: key, Matches: matches, Candidates: candidates}
+	}
+
+	var question strings.Builder
+	fmt.Fprintf(&question, "There are %d clusters with identifier or name '%s':\n", matches, key)
+	options := make([]string, 0, len(candidates))
+	for i, candidate := range candidates {
+		option := strconv.Itoa(i + 1)
+		options = append(options, option)
+		fmt.Fprintf(&question, "  %s) %s\n", option, describeClusterCandidate(candidate))
+	}
+	if matches > len(candidates) {
+		fmt.Fprintf(&question, "  (only the first %d are listed, use the ID of the cluster if it isn't one of them)\n", len(candidates))
+	}
+	question.WriteString("Select the cluster")
+
+	answer, err := prompt.Select(question.String(), options, "")
+	if err != nil {
+		return nil, err
+	}
+	index, err := strconv.Atoi(answer)
+	if err != nil {
+		return nil, err
+	}
+	return candidates[index-1], nil
+}
+
+// describeClusterCandidate describes a cluster with what tells apart clusters sharing a name
+func describeClusterCandidate(cluster *cmv1.Cluster) string {
+	return fmt.Sprintf("%s (ID %s, external ID %s, %s, %s, created %s)",
+		cluster.Name(), cluster.ID(), cluster.ExternalID(), cluster.Region().ID(), cluster.State(),
+		cluster.CreationTimestamp().Format("2006-01-02"))
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func candidateCluster(t *testing.T, id, region string) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID(id).ExternalID("ext-" + id).Name("my-cluster").
+		Region(cmv1.NewCloudRegion().ID(region)).State(cmv1.ClusterStateReady).
+		CreationTimestamp(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)).Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func TestPickClusterInteractive(t *testing.T) {
+	out := &bytes.Buffer{}
+	restore := prompt.SetIO(strings.NewReader("3\n2\n"), out)
+	defer restore()
+
+	candidates := []*cmv1.Cluster{candidateCluster(t, "a1", "us-east-1"), candidateCluster(t, "b2", "eu-west-1")}
+	cluster, err := pickCluster("my-cluster", candidates, 25)
+	require.NoError(t, err)
+	assert.Equal(t, "b2", cluster.ID())
+	assert.Contains(t, out.String(), "There are 25 clusters with identifier or name 'my-cluster':\n")
+	assert.Contains(t, out.String(), "  2) my-cluster (ID b2, external ID ext-b2, eu-west-1, ready, created 2026-03-01)\n")
+	assert.Contains(t, out.String(), "only the first 2 are listed")
+	assert.Contains(t, out.String(), "Invalid response")
+}
+
+func TestPickClusterNonInteractive(t *testing.T) {
+	prompt.SetNonInteractive(true)
+	defer prompt.SetNonInteractive(false)
+
+	candidates := []*cmv1.Cluster{candidateCluster(t, "a1", "us-east-1"), candidateCluster(t, "b2", "eu-west-1")}
+	_, err := pickCluster("my-cluster", candidates, 2)
+
+	var notFound *ClusterNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, `there are 2 clusters with identifier or name 'my-cluster', expected 1
+  - my-cluster (ID a1, external ID ext-a1, us-east-1, ready, created 2026-03-01)
+  - my-cluster (ID b2, external ID ext-b2, eu-west-1, ready, created 2026-03-01)`, err.Error())
+}
+
+func TestDisambiguateClusterWithoutCandidates(t *testing.T) {
+	_, err := disambiguateCluster("my-cluster", 3, func() ([]*cmv1.Cluster, int, error) {
+		return nil, 0, errors.New("forbidden")
+	})
+	assert.Equal(t, &ClusterNotFoundError{Key: "my-cluster", Matches: 3}, err)
+}
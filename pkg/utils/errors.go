@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/smithy-go"
+	ocmConfig "github.com/openshift-online/ocm-common/pkg/ocm/config"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// HintedError is implemented by the errors the root command prints a remediation hint for
+type HintedError interface {
+	error
+	Hint() string
+}
+
+// NotLoggedInError is returned when there is no usable OCM login
+type NotLoggedInError struct {
+	// Reason is why the OCM configuration can't be used, e.g. "refresh token is expired"
+	Reason string
+}
+
+func (e *NotLoggedInError) Error() string {
+	if e.Reason == "" {
+		return "not logged in to OCM"
+	}
+	return fmt.Sprintf("not logged in to OCM: %s", e.Reason)
+}
+
+func (e *NotLoggedInError) Hint() string {
+	return `run "ocm login --use-auth-code --url <environment>" and retry`
+}
+
+// AuthError is returned when OCM or the cluster rejects the credentials of the current login
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication failed: %v", e.Err)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+func (e *AuthError) Hint() string {
+	return `the login has expired or lacks permissions, run "ocm login --use-auth-code --url <environment>" and "ocm backplane login <cluster>" again`
+}
+
+// ClusterNotFoundError is returned when a cluster identifier doesn't match exactly one cluster
+type ClusterNotFoundError struct {
+	// Key is the cluster ID, external ID or name looked up
+	Key string
+	// Matches is how many clusters matched the key, more than one when the key is an ambiguous name
+	Matches int
+	// Candidates are the clusters an ambiguous key matches, when they could be listed
+	Candidates []*cmv1.Cluster
+}
+
+func (e *ClusterNotFoundError) Error() string {
+	if e.Matches > 1 {
+		message := fmt.Sprintf("there are %d clusters with identifier or name '%s', expected 1", e.Matches, e.Key)
+		for _, candidate := range e.Candidates {
+			message += "\n  - " + describeClusterCandidate(candidate)
+		}
+		return message
+	}
+	return fmt.Sprintf("there are no subscriptions or clusters with identifier or name '%s'", e.Key)
+}
+
+func (e *ClusterNotFoundError) Hint() string {
+	if e.Matches > 1 {
+		return "use the internal or external ID of the cluster instead of its name"
+	}
+	return `check the identifier, and that the OCM environment of the current login (OCM_URL, "ocm whoami") is the one of the cluster`
+}
+
+// ElevationDeniedError is returned when backplane refuses to elevate as backplane-cluster-admin
+type ElevationDeniedError struct {
+	Err error
+}
+
+func (e *ElevationDeniedError) Error() string {
+	return fmt.Sprintf("elevation denied: %v", e.Err)
+}
+
+func (e *ElevationDeniedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ElevationDeniedError) Hint() string {
+	return `check the --reason references a valid ticket, and that "ocm backplane login <cluster>" was run for this cluster`
+}
+
+// CloudThrottledError is returned when the API of a cloud provider throttles the requests
+type CloudThrottledError struct {
+	Provider string
+	Err      error
+}
+
+func (e *CloudThrottledError) Error() string {
+	return fmt.Sprintf("%s API rate limit exceeded: %v", e.Provider, e.Err)
+}
+
+func (e *CloudThrottledError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CloudThrottledError) Hint() string {
+	return "wait a few minutes and retry, or narrow the request (e.g. a shorter time range or fewer resources)"
+}
+
+// MissingScopesError is returned when the OAuth client of a vault path isn't granted all the scopes a command needs
+type MissingScopesError struct {
+	// VaultConfigKey is the config key of the vault path of the client credentials
+	VaultConfigKey string
+	Missing        []string
+}
+
+func (e *MissingScopesError) Error() string {
+	return fmt.Sprintf("the credentials of %s lack the required scope(s): %s", e.VaultConfigKey, strings.Join(e.Missing, ", "))
+}
+
+func (e *MissingScopesError) Hint() string {
+	return fmt.Sprintf("check that %s in the config file is the vault path of the right OAuth client, or ask for the client to be granted the missing scope(s)", e.VaultConfigKey)
+}
+
+// awsThrottlingCodes are the error codes the AWS APIs throttle requests with
+var awsThrottlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"RequestLimitExceeded":                   true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"SlowDown":                               true,
+}
+
+// ClassifyError wraps the OCM, Kubernetes and AWS errors the taxonomy covers into their typed error, so that
+// the root command can print a remediation hint. Other errors are returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var hinted HintedError
+	if errors.As(err, &hinted) {
+		return err
+	}
+
+	var ocmErr *ocmerrors.Error
+	if errors.As(err, &ocmErr) {
+		switch ocmErr.Status() {
+		case http.StatusUnauthorized:
+			return &AuthError{Err: err}
+		case http.StatusTooManyRequests:
+			return &CloudThrottledError{Provider: "OCM", Err: err}
+		}
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && awsThrottlingCodes[apiErr.ErrorCode()] {
+		return &CloudThrottledError{Provider: "AWS", Err: err}
+	}
+
+	if apierrors.IsUnauthorized(err) {
+		return &AuthError{Err: err}
+	}
+	return err
+}
+
+// RemediationHint returns the hint of the first typed error in the chain of err, or "" if there is none
+func RemediationHint(err error) string {
+	var hinted HintedError
+	if errors.As(ClassifyError(err), &hinted) {
+		return hinted.Hint()
+	}
+	return ""
+}
+
+// checkOCMLogin returns a NotLoggedInError when the OCM configuration can't authenticate requests
+func checkOCMLogin(config *ocmConfig.Config) error {
+	if config == nil {
+		return &NotLoggedInError{}
+	}
+	armed, reason, err := config.Armed()
+	if err != nil {
+		return &NotLoggedInError{Reason: err.Error()}
+	}
+	if !armed {
+		return &NotLoggedInError{Reason: reason}
+	}
+	return nil
+}
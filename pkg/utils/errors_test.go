@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	ocmConfig "github.com/openshift-online/ocm-common/pkg/ocm/config"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	unauthorized, err := ocmerrors.NewError().Status(http.StatusUnauthorized).Reason("invalid token").Build()
+	require.NoError(t, err)
+	tooManyRequests, err := ocmerrors.NewError().Status(http.StatusTooManyRequests).Build()
+	require.NoError(t, err)
+	notFound, err := ocmerrors.NewError().Status(http.StatusNotFound).Build()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected any
+	}{
+		{name: "OCM unauthorized", err: fmt.Errorf("can't list clusters: %w", unauthorized), expected: &AuthError{}},
+		{name: "OCM rate limited", err: tooManyRequests, expected: &CloudThrottledError{}},
+		{name: "Kubernetes unauthorized", err: apierrors.NewUnauthorized("token expired"), expected: &AuthError{}},
+		{name: "AWS throttled", err: fmt.Errorf("describe instances: %w", &smithy.GenericAPIError{Code: "RequestLimitExceeded"}), expected: &CloudThrottledError{}},
+		{name: "Already typed", err: fmt.Errorf("lookup: %w", &ClusterNotFoundError{Key: "abc"}), expected: &ClusterNotFoundError{}},
+		{name: "OCM not found", err: notFound},
+		{name: "AWS other error", err: &smithy.GenericAPIError{Code: "InvalidSubnetID.NotFound"}},
+		{name: "Untyped", err: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classified := ClassifyError(tt.err)
+			if tt.expected == nil {
+				assert.Equal(t, tt.err, classified)
+				assert.Empty(t, RemediationHint(tt.err))
+				return
+			}
+			var hinted HintedError
+			require.ErrorAs(t, classified, &hinted)
+			assert.IsType(t, tt.expected, hinted)
+			assert.Equal(t, hinted.Hint(), RemediationHint(tt.err))
+			assert.ErrorIs(t, classified, tt.err, "the original error must stay in the chain")
+		})
+	}
+
+	assert.NoError(t, ClassifyError(nil))
+}
+
+func TestClusterNotFoundError(t *testing.T) {
+	ambiguous := &ClusterNotFoundError{Key: "my-cluster", Matches: 2}
+	assert.Equal(t, "there are 2 clusters with identifier or name 'my-cluster', expected 1", ambiguous.Error())
+	assert.Contains(t, ambiguous.Hint(), "ID of the cluster")
+
+	missing := &ClusterNotFoundError{Key: "my-cluster"}
+	assert.Equal(t, "there are no subscriptions or clusters with identifier or name 'my-cluster'", missing.Error())
+	assert.Contains(t, missing.Hint(), "OCM environment")
+}
+
+func TestCheckOCMLogin(t *testing.T) {
+	var notLoggedIn *NotLoggedInError
+
+	require.ErrorAs(t, checkOCMLogin(nil), &notLoggedIn)
+	assert.Contains(t, RemediationHint(notLoggedIn), "ocm login")
+
+	require.ErrorAs(t, checkOCMLogin(&ocmConfig.Config{URL: productionURL, TokenURL: "https://sso.example.com/token"}), &notLoggedIn)
+	assert.Equal(t, "not logged in to OCM: credentials aren't set", notLoggedIn.Error())
+
+	assert.NoError(t, checkOCMLogin(&ocmConfig.Config{
+		URL:          productionURL,
+		TokenURL:     "https://sso.example.com/token",
+		ClientID:     "id",
+		ClientSecret: "secret",
+	}))
+}
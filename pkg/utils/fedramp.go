@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"os"
+
+	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/spf13/viper"
+)
+
+// FedRAMPConfigKey can be set to true in the osdctl config to always target the FedRAMP environment
+const FedRAMPConfigKey = "fedramp"
+
+// IsFedRAMPURL returns true if the given OCM URL or alias points to a FedRAMP OCM environment
+func IsFedRAMPURL(ocmUrl string) bool {
+	switch urlAliases[ocmUrl] {
+	case productionGovURL, integrationGovURL, stagingGovURL:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFedRAMP returns true when osdctl targets the FedRAMP environment, either because it is
+// enabled in the osdctl config or because OCM_URL or the OCM config point to a FedRAMP OCM
+func IsFedRAMP() bool {
+	if viper.GetBool(FedRAMPConfigKey) {
+		return true
+	}
+	if urlEnv := os.Getenv("OCM_URL"); urlEnv != "" {
+		return IsFedRAMPURL(urlEnv)
+	}
+	cfg, err := loadOCMConfig()
+	if err != nil || cfg == nil {
+		return false
+	}
+	return IsFedRAMPURL(cfg.URL)
+}
+
+// GetAWSPartition returns the AWS partition of the environment osdctl targets
+func GetAWSPartition() string {
+	if IsFedRAMP() {
+		return aws.UsGovPartitionID
+	}
+	return aws.PartitionID
+}
+
+// GetDefaultAWSRegion returns the AWS region commands fall back to when none is given
+func GetDefaultAWSRegion() string {
+	return aws.GetDefaultRegion(GetAWSPartition())
+}
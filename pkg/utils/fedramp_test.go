@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocmConfig "github.com/openshift-online/ocm-common/pkg/ocm/config"
+	"github.com/spf13/viper"
+)
+
+func TestIsFedRAMPURL(t *testing.T) {
+	tests := []struct {
+		ocmUrl string
+		want   bool
+	}{
+		{ocmUrl: "production", want: false},
+		{ocmUrl: "https://api.stage.openshift.com", want: false},
+		{ocmUrl: "prodgov", want: true},
+		{ocmUrl: "integrationgov", want: true},
+		{ocmUrl: "https://api-admin.stage.openshiftusgov.com", want: true},
+		{ocmUrl: "unknown", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ocmUrl, func(t *testing.T) {
+			if got := IsFedRAMPURL(tt.ocmUrl); got != tt.want {
+				t.Errorf("IsFedRAMPURL(%q) = %v, want %v", tt.ocmUrl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsFedRAMP(t *testing.T) {
+	writeOCMConfig := func(t *testing.T, url string) {
+		configFile := filepath.Join(t.TempDir(), "ocm.json")
+		data, err := json.Marshal(ocmConfig.Config{URL: url})
+		if err != nil {
+			t.Fatalf("failed to marshal config: %v", err)
+		}
+		if err := os.WriteFile(configFile, data, 0600); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+		t.Setenv("OCM_CONFIG", configFile)
+	}
+
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T)
+		want       bool
+		wantRegion string
+	}{
+		{
+			name: "commercial OCM config",
+			setup: func(t *testing.T) {
+				writeOCMConfig(t, "https://api.openshift.com")
+			},
+			want:       false,
+			wantRegion: "us-east-1",
+		},
+		{
+			name: "FedRAMP OCM config",
+			setup: func(t *testing.T) {
+				writeOCMConfig(t, "https://api-admin.openshiftusgov.com")
+			},
+			want:       true,
+			wantRegion: "us-gov-west-1",
+		},
+		{
+			name: "OCM_URL takes precedence over the OCM config",
+			setup: func(t *testing.T) {
+				writeOCMConfig(t, "https://api.openshift.com")
+				t.Setenv("OCM_URL", "stagegov")
+			},
+			want:       true,
+			wantRegion: "us-gov-west-1",
+		},
+		{
+			name: "forced through the osdctl config",
+			setup: func(t *testing.T) {
+				writeOCMConfig(t, "https://api.openshift.com")
+				viper.Set(FedRAMPConfigKey, true)
+				t.Cleanup(func() { viper.Set(FedRAMPConfigKey, false) })
+			},
+			want:       true,
+			wantRegion: "us-gov-west-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OCM_URL", "")
+			tt.setup(t)
+			if got := IsFedRAMP(); got != tt.want {
+				t.Errorf("IsFedRAMP() = %v, want %v", got, tt.want)
+			}
+			if got := GetDefaultAWSRegion(); got != tt.wantRegion {
+				t.Errorf("GetDefaultAWSRegion() = %v, want %v", got, tt.wantRegion)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// PartialResultsBanner is printed by interrupted commands before flushing what they gathered,
+// so the output isn't mistaken for complete results.
+const PartialResultsBanner = "=== INTERRUPTED: PARTIAL RESULTS ==="
+
+// NotifyInterrupt returns a copy of the parent context which is cancelled on the first SIGINT or SIGTERM,
+// letting long-running commands stop their in-flight calls, flush their partial output and clean up.
+// The default signal handling is restored once interrupted, so a second Ctrl+C terminates the process
+// immediately. The returned cancel function must be called to release the signal handler.
+func NotifyInterrupt(parent context.Context, w io.Writer) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case <-signals:
+			fmt.Fprintln(w, "Interrupted, stopping and cleaning up. Interrupt again to exit immediately.")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// IsInterrupted returns whether the context of the command was cancelled
+func IsInterrupted(ctx context.Context) bool {
+	return ctx != nil && ctx.Err() == context.Canceled
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyInterrupt(t *testing.T) {
+	out := &bytes.Buffer{}
+	ctx, stop := NotifyInterrupt(context.Background(), out)
+	defer stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("failed to send SIGINT: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the context to be cancelled on SIGINT")
+	}
+	if !IsInterrupted(ctx) {
+		t.Error("expected the context to be reported as interrupted")
+	}
+}
+
+func TestNotifyInterruptStop(t *testing.T) {
+	ctx, stop := NotifyInterrupt(context.Background(), &bytes.Buffer{})
+	if IsInterrupted(ctx) {
+		t.Fatal("expected the context not to be interrupted before any signal")
+	}
+	stop()
+	if ctx.Err() == nil {
+		t.Error("expected the context to be cancelled once stopped")
+	}
+}
@@ -67,7 +67,7 @@ func GetClusterAnyStatus(conn *sdk.Connection, clusterId string) (*cmv1.Cluster,
 	clustersSearch := fmt.Sprintf(ClusterServiceClusterSearch, clusterId, clusterId, clusterId)
 	clustersListResponse, err := conn.ClustersMgmt().V1().Clusters().List().Search(clustersSearch).Size(1).Send()
 	if err != nil {
-		return nil, fmt.Errorf("can't retrieve clusters for clusterId '%s': %w", clusterId, err)
+		return nil, fmt.Errorf("can't retrieve clusters for clusterId '%s': %w", clusterId, ClassifyError(err))
 	}
 
 	// If there is exactly one cluster matching then return it:
@@ -76,7 +76,14 @@ func GetClusterAnyStatus(conn *sdk.Connection, clusterId string) (*cmv1.Cluster,
 		return clustersListResponse.Items().Slice()[0], nil
 	}
 
-	return nil, fmt.Errorf("there are %d clusters with identifier or name '%s', expected 1", clustersTotal, clusterId)
+	// If there are multiple matching clusters then the user picks one of them:
+	if clustersTotal > 1 {
+		return disambiguateCluster(clusterId, clustersTotal, func() ([]*cmv1.Cluster, int, error) {
+			return clusterCandidates(conn, clustersSearch)
+		})
+	}
+
+	return nil, &ClusterNotFoundError{Key: clusterId, Matches: clustersTotal}
 }
 
 func GetClusters(ocmClient *sdk.Connection, clusterIds []string) []*cmv1.Cluster {
@@ -262,7 +269,7 @@ func CreateConnection() (*sdk.Connection, error) {
 		// in the case where it may be an alias
 		gatewayURL, ok := urlAliases[urlEnv]
 		if !ok {
-			return nil, fmt.Errorf("invalid OCM_URL found: %s\nValid URL aliases are: 'production', 'staging', 'integration'", urlEnv)
+			return nil, fmt.Errorf("invalid OCM_URL found: %s\nValid URL aliases are: 'production', 'staging', 'integration', 'productiongov', 'staginggov', 'integrationgov'", urlEnv)
 		}
 
 		ocmApiOverride = gatewayURL
@@ -272,9 +279,16 @@ func CreateConnection() (*sdk.Connection, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to load OCM config. %w", err)
 	}
+	if err := checkOCMLogin(config); err != nil {
+		return nil, err
+	}
 
 	agentString := fmt.Sprintf("osdctl-%s", Version)
 
+	if out := ocmTraceWriter(); out != nil {
+		return buildTracedConnection(config, agentString, ocmApiOverride, out)
+	}
+
 	connBuilder := ocmConnBuilder.NewConnection().Config(config).AsAgent(agentString)
 
 	if ocmApiOverride != "" {
@@ -293,7 +307,7 @@ func ValidateAndResolveOcmUrl(ocmUrl string) (string, error) {
 	// Validate URL in the case where it may be an alias
 	resolvedUrl, ok := urlAliases[ocmUrl]
 	if !ok {
-		return "", fmt.Errorf("invalid OCM_URL found: %s\nValid URL aliases are: 'production', 'staging', 'integration'", ocmUrl)
+		return "", fmt.Errorf("invalid OCM_URL found: %s\nValid URL aliases are: 'production', 'staging', 'integration', 'productiongov', 'staginggov', 'integrationgov'", ocmUrl)
 	}
 	return resolvedUrl, nil
 }
@@ -309,9 +323,16 @@ func CreateConnectionWithUrl(OcmUrl string) (*sdk.Connection, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to load OCM config. %w", err)
 	}
+	if err := checkOCMLogin(config); err != nil {
+		return nil, err
+	}
 
 	agentString := fmt.Sprintf("osdctl-%s", Version)
 
+	if out := ocmTraceWriter(); out != nil {
+		return buildTracedConnection(config, agentString, ocmApiUrl, out)
+	}
+
 	connBuilder := ocmConnBuilder.NewConnection().Config(config).AsAgent(agentString)
 
 	if connBuilder == nil {
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+const (
+	// DefaultOCMPageSize is the page size of OCM list requests, the largest page size OCM accepts for most endpoints
+	DefaultOCMPageSize = 100
+	minOCMPageSize     = 10
+
+	defaultOCMMaxRetries = 5
+	defaultOCMBackoff    = 2 * time.Second
+	maxOCMBackoff        = time.Minute
+)
+
+// OCMPageFetcher fetches a page of an OCM list endpoint, with pages numbered from 1
+type OCMPageFetcher[T any] func(page, size int) ([]T, error)
+
+// OCMPaginator lists all the items of an OCM list endpoint page by page.
+//
+// The OCM SDK already retries throttled requests a couple of times within a few seconds. On top of that, the
+// paginator backs off exponentially when a page is still throttled (429) or unavailable (503), and halves the page
+// size when a page times out, so that large listings complete instead of failing on a single page.
+type OCMPaginator[T any] struct {
+	fetch      OCMPageFetcher[T]
+	pageSize   int
+	limit      int
+	maxRetries int
+	backoff    time.Duration
+	sleep      func(time.Duration)
+}
+
+// NewOCMPaginator returns a paginator fetching pages of DefaultOCMPageSize items with fetch
+func NewOCMPaginator[T any](fetch OCMPageFetcher[T]) *OCMPaginator[T] {
+	return &OCMPaginator[T]{
+		fetch:      fetch,
+		pageSize:   DefaultOCMPageSize,
+		maxRetries: defaultOCMMaxRetries,
+		backoff:    defaultOCMBackoff,
+		sleep:      time.Sleep,
+	}
+}
+
+// WithPageSize sets the number of items requested per page
+func (p *OCMPaginator[T]) WithPageSize(size int) *OCMPaginator[T] {
+	if size > 0 {
+		p.pageSize = size
+	}
+	return p
+}
+
+// WithLimit stops the listing once limit items were fetched, 0 lists all the items
+func (p *OCMPaginator[T]) WithLimit(limit int) *OCMPaginator[T] {
+	p.limit = limit
+	return p
+}
+
+// WithRetries sets how many times a throttled page is retried, and the delay before the first retry which doubles
+// on every retry
+func (p *OCMPaginator[T]) WithRetries(maxRetries int, backoff time.Duration) *OCMPaginator[T] {
+	p.maxRetries = maxRetries
+	p.backoff = backoff
+	return p
+}
+
+// All fetches the pages until one isn't full or the limit is reached, and returns their items
+func (p *OCMPaginator[T]) All() ([]T, error) {
+	var items []T
+	size := p.pageSize
+	if p.limit > 0 && p.limit < size {
+		size = p.limit
+	}
+
+	for {
+		// Pages are numbered from the items fetched so far, which stays correct when the page size is halved
+		page := len(items)/size + 1
+		pageItems, err := p.fetchWithRetries(page, size)
+		if err != nil {
+			if isOCMTimeout(err) && size/2 >= minOCMPageSize && len(items)%(size/2) == 0 {
+				size /= 2
+				fmt.Fprintf(os.Stderr, "OCM page %d timed out, retrying with pages of %d items\n", page, size)
+				continue
+			}
+			return items, fmt.Errorf("failed to fetch page %d: %w", page, err)
+		}
+
+		items = append(items, pageItems...)
+		if p.limit > 0 && len(items) >= p.limit {
+			return items[:p.limit], nil
+		}
+		if len(pageItems) < size {
+			return items, nil
+		}
+	}
+}
+
+// fetchWithRetries fetches a page, backing off while OCM throttles the requests
+func (p *OCMPaginator[T]) fetchWithRetries(page, size int) ([]T, error) {
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		items, err := p.fetch(page, size)
+		if err == nil || !isOCMThrottled(err) || attempt >= p.maxRetries {
+			return items, err
+		}
+		fmt.Fprintf(os.Stderr, "OCM is throttling the requests, retrying page %d in %s\n", page, backoff)
+		p.sleep(backoff)
+		backoff = min(backoff*2, maxOCMBackoff)
+	}
+}
+
+// ocmErrorStatus returns the HTTP status of an OCM API error, 0 for other errors
+func ocmErrorStatus(err error) int {
+	var ocmErr *ocmerrors.Error
+	if errors.As(err, &ocmErr) {
+		return ocmErr.Status()
+	}
+	return 0
+}
+
+func isOCMThrottled(err error) bool {
+	status := ocmErrorStatus(err)
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+func isOCMTimeout(err error) bool {
+	status := ocmErrorStatus(err)
+	return status == http.StatusGatewayTimeout || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ocmError(t *testing.T, status int) error {
+	err, buildErr := ocmerrors.NewError().Status(status).Reason(http.StatusText(status)).Build()
+	require.NoError(t, buildErr)
+	return err
+}
+
+// pagedItems serves the items 0..total-1 page by page
+func pagedItems(total int) func(page, size int) []int {
+	return func(page, size int) []int {
+		var items []int
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			items = append(items, i)
+		}
+		return items
+	}
+}
+
+func TestOCMPaginatorAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		pageSize int
+		limit    int
+		want     int
+		requests int
+	}{
+		{name: "single partial page", total: 7, pageSize: 10, want: 7, requests: 1},
+		{name: "several pages", total: 25, pageSize: 10, want: 25, requests: 3},
+		{name: "last page full", total: 20, pageSize: 10, want: 20, requests: 3},
+		{name: "no items", total: 0, pageSize: 10, want: 0, requests: 1},
+		{name: "limit within first page", total: 25, pageSize: 10, limit: 4, want: 4, requests: 1},
+		{name: "limit across pages", total: 25, pageSize: 10, limit: 15, want: 15, requests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serve := pagedItems(tt.total)
+			requests := 0
+			items, err := NewOCMPaginator(func(page, size int) ([]int, error) {
+				requests++
+				return serve(page, size), nil
+			}).WithPageSize(tt.pageSize).WithLimit(tt.limit).All()
+
+			require.NoError(t, err)
+			assert.Len(t, items, tt.want)
+			for i, item := range items {
+				assert.Equal(t, i, item)
+			}
+			assert.Equal(t, tt.requests, requests)
+		})
+	}
+}
+
+func TestOCMPaginatorThrottled(t *testing.T) {
+	serve := pagedItems(15)
+	throttled := 2
+	var sleeps []time.Duration
+	paginator := NewOCMPaginator(func(page, size int) ([]int, error) {
+		if page == 2 && throttled > 0 {
+			throttled--
+			return nil, ocmError(t, http.StatusTooManyRequests)
+		}
+		return serve(page, size), nil
+	}).WithPageSize(10)
+	paginator.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	items, err := paginator.All()
+	require.NoError(t, err)
+	assert.Len(t, items, 15)
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second}, sleeps)
+}
+
+func TestOCMPaginatorRetriesExhausted(t *testing.T) {
+	requests := 0
+	paginator := NewOCMPaginator(func(page, size int) ([]int, error) {
+		requests++
+		return nil, ocmError(t, http.StatusServiceUnavailable)
+	}).WithRetries(2, time.Second)
+	paginator.sleep = func(time.Duration) {}
+
+	_, err := paginator.All()
+	assert.ErrorContains(t, err, "failed to fetch page 1")
+	assert.Equal(t, 3, requests)
+}
+
+func TestOCMPaginatorNotRetried(t *testing.T) {
+	requests := 0
+	_, err := NewOCMPaginator(func(page, size int) ([]int, error) {
+		requests++
+		return nil, ocmError(t, http.StatusForbidden)
+	}).All()
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+
+	requests = 0
+	_, err = NewOCMPaginator(func(page, size int) ([]int, error) {
+		requests++
+		return nil, errors.New("connection refused")
+	}).All()
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestOCMPaginatorTimeoutHalvesPageSize(t *testing.T) {
+	serve := pagedItems(250)
+	var sizes []int
+	items, err := NewOCMPaginator(func(page, size int) ([]int, error) {
+		sizes = append(sizes, size)
+		// Pages of more than 50 items time out after the first one
+		if page > 1 && size > 50 {
+			return nil, ocmError(t, http.StatusGatewayTimeout)
+		}
+		return serve(page, size), nil
+	}).All()
+
+	require.NoError(t, err)
+	assert.Len(t, items, 250)
+	for i, item := range items {
+		assert.Equal(t, i, item)
+	}
+	assert.Equal(t, []int{100, 100, 50, 50, 50, 50}, sizes)
+}
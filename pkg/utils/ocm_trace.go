@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	ocmConfig "github.com/openshift-online/ocm-common/pkg/ocm/config"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+)
+
+const (
+	// RequestIDHeader is set on every OCM request when tracing, so the request can be found server side
+	RequestIDHeader = "X-Request-Id"
+	// OperationIDHeader is returned by OCM with the identifier of the operation handling a request
+	OperationIDHeader = "X-Operation-Id"
+)
+
+var (
+	ocmTraceLock sync.Mutex
+	ocmTraceOut  io.Writer
+)
+
+// EnableOCMTrace prints the request ID, HTTP status and timing of every OCM API call made through
+// connections created afterwards to out. A nil writer disables tracing.
+func EnableOCMTrace(out io.Writer) {
+	ocmTraceLock.Lock()
+	defer ocmTraceLock.Unlock()
+	ocmTraceOut = out
+}
+
+func ocmTraceWriter() io.Writer {
+	ocmTraceLock.Lock()
+	defer ocmTraceLock.Unlock()
+	return ocmTraceOut
+}
+
+// tracingTransport tags every request with a request ID and reports it along with the
+// operation ID, status and duration of the call
+type tracingTransport struct {
+	next http.RoundTripper
+	out  io.Writer
+	lock sync.Mutex
+}
+
+func newTracingTransport(out io.Writer) sdk.TransportWrapper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &tracingTransport{next: next, out: out}
+	}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestID := req.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = uuid.NewString()
+		// RoundTrippers must not modify the original request
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if err != nil {
+		fmt.Fprintf(t.out, "[ocm] %s %s -> error (%s) request-id=%s: %v\n", req.Method, req.URL.Redacted(), elapsed, requestID, err)
+		return nil, fmt.Errorf("%w (request-id=%s)", err, requestID)
+	}
+	line := fmt.Sprintf("[ocm] %s %s -> %d (%s) request-id=%s", req.Method, req.URL.Redacted(), resp.StatusCode, elapsed, requestID)
+	if operationID := resp.Header.Get(OperationIDHeader); operationID != "" {
+		line += " operation-id=" + operationID
+	}
+	fmt.Fprintln(t.out, line)
+	return resp, nil
+}
+
+// buildTracedConnection builds the OCM connection from the config like the ocm-common connection builder
+// does, which doesn't allow wrapping the transport, and traces its requests to out
+func buildTracedConnection(config *ocmConfig.Config, agent, apiUrlOverride string, out io.Writer) (*sdk.Connection, error) {
+	if config == nil {
+		return nil, fmt.Errorf("Not logged in, run the 'login' command")
+	}
+	armed, reason, err := config.Armed()
+	if err != nil {
+		return nil, err
+	}
+	if !armed {
+		return nil, fmt.Errorf("Not logged in, %s, run the 'login' command", reason)
+	}
+
+	builder := sdk.NewConnectionBuilder()
+	if config.TokenURL != "" {
+		builder.TokenURL(config.TokenURL)
+	}
+	if config.ClientID != "" || config.ClientSecret != "" {
+		builder.Client(config.ClientID, config.ClientSecret)
+	}
+	if config.Scopes != nil {
+		builder.Scopes(config.Scopes...)
+	}
+	if config.User != "" || config.Password != "" {
+		builder.User(config.User, config.Password)
+	}
+	if config.URL != "" {
+		builder.URL(config.URL)
+	}
+	tokens := make([]string, 0, 2)
+	if config.AccessToken != "" {
+		tokens = append(tokens, config.AccessToken)
+	}
+	if config.RefreshToken != "" {
+		tokens = append(tokens, config.RefreshToken)
+	}
+	if len(tokens) > 0 {
+		builder.Tokens(tokens...)
+	}
+	builder.Insecure(config.Insecure)
+	builder.Agent(agent)
+	if apiUrlOverride != "" {
+		builder.URL(apiUrlOverride)
+	}
+	builder.TransportWrapper(newTracingTransport(out))
+
+	return builder.Build()
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	ocmConfig "github.com/openshift-online/ocm-common/pkg/ocm/config"
+)
+
+func TestTracingTransport(t *testing.T) {
+	var receivedRequestID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedRequestID = r.Header.Get(RequestIDHeader)
+		w.Header().Set(OperationIDHeader, "op-123")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	client := &http.Client{Transport: newTracingTransport(out)(http.DefaultTransport)}
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/clusters_mgmt/v1/clusters/abc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if receivedRequestID == "" {
+		t.Fatal("expected the request to carry a request ID")
+	}
+	if req.Header.Get(RequestIDHeader) != "" {
+		t.Error("expected the original request to be left untouched")
+	}
+	expected := regexp.MustCompile(`^\[ocm\] GET ` + regexp.QuoteMeta(server.URL) + `/api/clusters_mgmt/v1/clusters/abc -> 404 \(\d+ms\) request-id=` +
+		regexp.QuoteMeta(receivedRequestID) + ` operation-id=op-123\n$`)
+	if !expected.MatchString(out.String()) {
+		t.Errorf("unexpected trace output %q", out.String())
+	}
+}
+
+type failingRoundTripper struct{}
+
+func (failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTracingTransportError(t *testing.T) {
+	out := &bytes.Buffer{}
+	transport := newTracingTransport(out)(failingRoundTripper{})
+	req, err := http.NewRequest(http.MethodGet, "https://api.openshift.com/api/clusters_mgmt/v1/clusters", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(RequestIDHeader, "req-1")
+
+	_, err = transport.RoundTrip(req)
+	if err == nil || err.Error() != "connection refused (request-id=req-1)" {
+		t.Errorf("expected the request ID in the error, got %v", err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("-> error")) {
+		t.Errorf("expected the failure to be traced, got %q", out.String())
+	}
+}
+
+func TestBuildTracedConnectionNotLoggedIn(t *testing.T) {
+	if _, err := buildTracedConnection(nil, "osdctl-test", "", &bytes.Buffer{}); err == nil {
+		t.Error("expected an error without an OCM config")
+	}
+	if _, err := buildTracedConnection(&ocmConfig.Config{}, "osdctl-test", "", &bytes.Buffer{}); err == nil {
+		t.Error("expected an error without credentials")
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// OIDCIssuerURL returns the OIDC issuer URL of an STS cluster, or an empty string if it has none
+func OIDCIssuerURL(cluster *cmv1.Cluster) string {
+	sts := cluster.AWS().STS()
+	if issuerURL := sts.OidcConfig().IssuerUrl(); issuerURL != "" {
+		return issuerURL
+	}
+	return sts.OIDCEndpointURL()
+}
+
+// OIDCS3Bucket returns the S3 bucket serving an unmanaged OIDC issuer, or an empty string
+// if the issuer isn't served straight from S3 (e.g. managed OIDC configurations behind CloudFront)
+func OIDCS3Bucket(issuerURL string) string {
+	u, err := url.Parse(issuerURL)
+	if err != nil || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return ""
+	}
+	host := u.Hostname()
+	// Virtual-hosted style: <bucket>.s3.<region>.amazonaws.com
+	if bucket, _, found := strings.Cut(host, ".s3."); found && bucket != "" {
+		return bucket
+	}
+	// Path style: s3.<region>.amazonaws.com/<bucket>
+	if strings.HasPrefix(host, "s3.") || strings.HasPrefix(host, "s3-") {
+		bucket, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+		return bucket
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestOIDCS3Bucket(t *testing.T) {
+	tests := map[string]string{
+		"https://my-oidc-bucket.s3.us-east-1.amazonaws.com":           "my-oidc-bucket",
+		"https://s3.us-east-1.amazonaws.com/my-oidc-bucket/issuer":    "my-oidc-bucket",
+		"https://oidc.os1.devshift.org/2abcdefghijklmnopqrstuvwxyz12": "",
+		"not a url\x7f": "",
+	}
+	for issuer, expected := range tests {
+		if got := OIDCS3Bucket(issuer); got != expected {
+			t.Errorf("OIDCS3Bucket(%q) = %q, expected %q", issuer, got, expected)
+		}
+	}
+}
+
+func TestOIDCIssuerURL(t *testing.T) {
+	withConfig, _ := cmv1.NewCluster().AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+		OIDCEndpointURL("https://legacy.example.com").
+		OidcConfig(cmv1.NewOidcConfig().IssuerUrl("https://oidc.example.com/abc")))).Build()
+	legacy, _ := cmv1.NewCluster().AWS(cmv1.NewAWS().STS(cmv1.NewSTS().OIDCEndpointURL("https://legacy.example.com"))).Build()
+	nonSTS, _ := cmv1.NewCluster().Build()
+
+	if got := OIDCIssuerURL(withConfig); got != "https://oidc.example.com/abc" {
+		t.Errorf("expected the OIDC config issuer, got %q", got)
+	}
+	if got := OIDCIssuerURL(legacy); got != "https://legacy.example.com" {
+		t.Errorf("expected the OIDC endpoint URL, got %q", got)
+	}
+	if got := OIDCIssuerURL(nonSTS); got != "" {
+		t.Errorf("expected no issuer, got %q", got)
+	}
+}
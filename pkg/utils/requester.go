@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -19,6 +20,8 @@ type Requester struct {
 	Data        string
 	Headers     map[string]string
 	SuccessCode int
+	// Context cancels the request when done, defaults to context.Background()
+	Context context.Context
 }
 
 func (rh *Requester) Send() (string, error) {
@@ -26,12 +29,17 @@ func (rh *Requester) Send() (string, error) {
 		Timeout: time.Second * 600,
 	}
 
+	ctx := rh.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	var req *http.Request
 	var err error
 	if rh.Data != "" {
-		req, err = http.NewRequest(rh.Method, rh.Url, bytes.NewBuffer([]byte(rh.Data)))
+		req, err = http.NewRequestWithContext(ctx, rh.Method, rh.Url, bytes.NewBuffer([]byte(rh.Data)))
 	} else {
-		req, err = http.NewRequest(rh.Method, rh.Url, nil)
+		req, err = http.NewRequestWithContext(ctx, rh.Method, rh.Url, nil)
 	}
 
 	if err != nil {
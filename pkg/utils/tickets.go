@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+)
+
+const (
+	// VerifyTicketsConfigKey enables the verification of the tickets referenced by every --reason, like --verify-tickets
+	VerifyTicketsConfigKey = "verify_tickets"
+
+	ticketLookupTimeout = 10 * time.Second
+)
+
+var (
+	ohssReferenceRE       = regexp.MustCompile(`(?i)\bOHSS-[0-9]+\b`)
+	pdIncidentReferenceRE = regexp.MustCompile(`(?i)\bpagerduty\.com/incidents/([A-Z0-9]+)\b`)
+)
+
+// lookupJiraIssue reports whether the Jira issue exists, replaced in tests
+var lookupJiraIssue = func(ctx context.Context, key string) (bool, error) {
+	client, err := NewJiraClient("")
+	if err != nil {
+		return false, err
+	}
+	_, resp, err := client.Issue().GetWithContext(ctx, key, nil)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// lookupPDIncident reports whether the PagerDuty incident exists, replaced in tests
+var lookupPDIncident = func(ctx context.Context, id string) (bool, error) {
+	tokens, err := osdctlConfig.GetConfigValues(pagerduty.PagerDutyUserTokenConfigKey, pagerduty.PagerDutyOauthTokenConfigKey)
+	if err != nil {
+		return false, err
+	}
+	var client *pd.Client
+	switch {
+	case tokens[pagerduty.PagerDutyUserTokenConfigKey] != "":
+		client = pd.NewClient(tokens[pagerduty.PagerDutyUserTokenConfigKey])
+	case tokens[pagerduty.PagerDutyOauthTokenConfigKey] != "":
+		client = pd.NewOAuthClient(tokens[pagerduty.PagerDutyOauthTokenConfigKey])
+	default:
+		return false, errors.New("no PagerDuty token is configured")
+	}
+	_, err = client.GetIncidentWithContext(ctx, id)
+	var apiErr pd.APIError
+	if errors.As(err, &apiErr) && apiErr.NotFound() {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// TicketReferences returns the OHSS issues and PagerDuty incidents referenced by a reason, e.g. "OHSS-1234" and
+// "https://redhat.pagerduty.com/incidents/Q0ABCDEF12345"
+func TicketReferences(reason string) (ohssIssues []string, pdIncidents []string) {
+	seen := map[string]bool{}
+	for _, issue := range ohssReferenceRE.FindAllString(reason, -1) {
+		if issue = strings.ToUpper(issue); !seen[issue] {
+			seen[issue] = true
+			ohssIssues = append(ohssIssues, issue)
+		}
+	}
+	for _, match := range pdIncidentReferenceRE.FindAllStringSubmatch(reason, -1) {
+		if incident := strings.ToUpper(match[1]); !seen[incident] {
+			seen[incident] = true
+			pdIncidents = append(pdIncidents, incident)
+		}
+	}
+	return ohssIssues, pdIncidents
+}
+
+// VerifyTicketReferences checks that the OHSS issues and PagerDuty incidents referenced by the reason exist, and
+// warns about the ones which don't so a typo is caught before the action is audited with a bad reference.
+// It only warns: the reason stays valid when a ticket doesn't exist or can't be checked.
+func VerifyTicketReferences(w io.Writer, flag, reason string) {
+	ohssIssues, pdIncidents := TicketReferences(reason)
+	verify := func(kind, ref string, lookup func(context.Context, string) (bool, error)) {
+		ctx, cancel := context.WithTimeout(context.Background(), ticketLookupTimeout)
+		defer cancel()
+		exists, err := lookup(ctx, ref)
+		switch {
+		case err != nil:
+			_, _ = fmt.Fprintf(w, "Warning: couldn't verify the %s %s referenced by --%s: %v\n", kind, ref, flag, err)
+		case !exists:
+			_, _ = fmt.Fprintf(w, "Warning: the %s %s referenced by --%s doesn't exist, check it for typos\n", kind, ref, flag)
+		}
+	}
+	for _, issue := range ohssIssues {
+		verify("Jira issue", issue, lookupJiraIssue)
+	}
+	for _, incident := range pdIncidents {
+		verify("PagerDuty incident", incident, lookupPDIncident)
+	}
+}
+
+// TicketVerificationEnabled returns whether the tickets referenced by reasons are verified by default, from the
+// verify_tickets key of the config file
+func TicketVerificationEnabled() bool {
+	values, err := osdctlConfig.GetConfigValues(VerifyTicketsConfigKey)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(values[VerifyTicketsConfigKey], "true")
+}
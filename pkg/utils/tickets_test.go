@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTicketReferences(t *testing.T) {
+	ohssIssues, pdIncidents := TicketReferences("ohss-123, OHSS-123 and OHSS-456 - https://redhat.pagerduty.com/incidents/q0abcdef12345 ITN-2024-1")
+	if expected := []string{"OHSS-123", "OHSS-456"}; !reflect.DeepEqual(ohssIssues, expected) {
+		t.Errorf("expected OHSS issues %v, got %v", expected, ohssIssues)
+	}
+	if expected := []string{"Q0ABCDEF12345"}; !reflect.DeepEqual(pdIncidents, expected) {
+		t.Errorf("expected PagerDuty incidents %v, got %v", expected, pdIncidents)
+	}
+}
+
+func TestVerifyTicketReferences(t *testing.T) {
+	origJira, origPD := lookupJiraIssue, lookupPDIncident
+	defer func() { lookupJiraIssue, lookupPDIncident = origJira, origPD }()
+
+	lookupJiraIssue = func(_ context.Context, key string) (bool, error) {
+		switch key {
+		case "OHSS-1":
+			return true, nil
+		case "OHSS-2":
+			return false, nil
+		}
+		return false, errors.New("jira is down")
+	}
+	lookupPDIncident = func(_ context.Context, id string) (bool, error) {
+		return id == "Q0EXISTS", nil
+	}
+
+	out := &bytes.Buffer{}
+	VerifyTicketReferences(out, "reason", "OHSS-1 OHSS-2 OHSS-3 https://redhat.pagerduty.com/incidents/Q0EXISTS https://redhat.pagerduty.com/incidents/Q0TYPO")
+	for _, expected := range []string{
+		"Warning: the Jira issue OHSS-2 referenced by --reason doesn't exist",
+		"Warning: couldn't verify the Jira issue OHSS-3 referenced by --reason: jira is down",
+		"Warning: the PagerDuty incident Q0TYPO referenced by --reason doesn't exist",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in the output, got:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "OHSS-1 ") || strings.Contains(out.String(), "Q0EXISTS") {
+		t.Errorf("expected no warning for the existing tickets, got:\n%s", out.String())
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -50,13 +52,56 @@ func (p *cachedTokenProvider) Token() (string, error) {
 }
 
 // GetScopedAccessToken gets an access token using the vault path in the configuration key specified
-// It will request any scopes listed in the scopes string
+// It will request any scopes listed in the scopes string, and fails with a MissingScopesError if the
+// client isn't granted all of them
 func GetScopedAccessToken(authUrl, vaultConfigKey string, scopes string) (string, error) {
 	clientId, clientSecret, err := GetCredsFromVault(vaultConfigKey)
 	if err != nil {
 		return "", err
 	}
 
+	token, _, err := requestVerifiedToken(authUrl, vaultConfigKey, clientId, clientSecret, scopes)
+	if err != nil {
+		return "", err
+	}
+
+	log.Infoln("Successfully authenticated")
+
+	return token, nil
+}
+
+// GetScopedTokenProvider returns an AccessTokenProvider that fetches tokens
+// using the vault path in the specified configuration key.
+func GetScopedTokenProvider(authUrl, vaultConfigKey string, scopes string) (AccessTokenProvider, error) {
+	clientId, clientSecret, err := GetCredsFromVault(vaultConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchFunc := func() (string, int, error) {
+		token, expiresIn, err := requestVerifiedToken(authUrl, vaultConfigKey, clientId, clientSecret, scopes)
+		if err != nil {
+			return "", 0, err
+		}
+
+		log.Infoln("Successfully authenticated")
+
+		return token, expiresIn, nil
+	}
+
+	return newCachedTokenProvider(fetchFunc), nil
+}
+
+// tokenResponse is the response of the OAuth token endpoint
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	// Scope are the space separated scopes granted to the token, when the endpoint returns them
+	Scope string `json:"scope"`
+}
+
+// requestToken requests an access token with the client credentials grant
+var requestToken = func(authUrl, clientId, clientSecret, scopes string) (*tokenResponse, error) {
 	reqData := url.Values{
 		"grant_type":    {"client_credentials"},
 		"scope":         {scopes},
@@ -76,76 +121,57 @@ func GetScopedAccessToken(authUrl, vaultConfigKey string, scopes string) (string
 
 	resp, err := requester.Send()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var respObj map[string]interface{}
-	err = json.Unmarshal([]byte(resp), &respObj)
-	if err != nil {
-		return "", err
+	response := &tokenResponse{}
+	if err := json.Unmarshal([]byte(resp), response); err != nil {
+		return nil, err
 	}
-
-	token, ok := respObj["access_token"].(string)
-	if !ok {
-		return "", fmt.Errorf("access token not present in response")
+	if response.AccessToken == "" {
+		return nil, fmt.Errorf("access token not present in response")
 	}
-
-	log.Infoln("Successfully authenticated")
-
-	return token, nil
+	return response, nil
 }
 
-// GetScopedTokenProvider returns an AccessTokenProvider that fetches tokens
-// using the vault path in the specified configuration key.
-func GetScopedTokenProvider(authUrl, vaultConfigKey string, scopes string) (AccessTokenProvider, error) {
-	clientId, clientSecret, err := GetCredsFromVault(vaultConfigKey)
+// requestVerifiedToken requests an access token and verifies it was granted all the scopes. When the token
+// endpoint rejects the request, each scope is requested alone to report the ones the client isn't granted.
+// The lifetime of the token defaults to 5 minutes if the endpoint doesn't return it.
+func requestVerifiedToken(authUrl, vaultConfigKey, clientId, clientSecret, scopes string) (string, int, error) {
+	response, err := requestToken(authUrl, clientId, clientSecret, scopes)
 	if err != nil {
-		return nil, err
-	}
-
-	fetchFunc := func() (string, int, error) {
-		reqData := url.Values{
-			"grant_type":    {"client_credentials"},
-			"scope":         {scopes},
-			"client_id":     {clientId},
-			"client_secret": {clientSecret},
-		}.Encode()
-
-		requester := Requester{
-			Method: http.MethodPost,
-			Url:    authUrl,
-			Data:   string(reqData),
-			Headers: map[string]string{
-				"Content-Type": "application/x-www-form-urlencoded",
-			},
-			SuccessCode: http.StatusOK,
-		}
-
-		resp, err := requester.Send()
-		if err != nil {
-			return "", 0, err
+		var missing []string
+		if requested := strings.Fields(scopes); len(requested) > 1 {
+			for _, scope := range requested {
+				if _, scopeErr := requestToken(authUrl, clientId, clientSecret, scope); scopeErr != nil {
+					missing = append(missing, scope)
+				}
+			}
 		}
-
-		var respObj map[string]interface{}
-		err = json.Unmarshal([]byte(resp), &respObj)
-		if err != nil {
-			return "", 0, err
+		// When no scope can be requested at all, the credentials themselves are rejected
+		if len(missing) > 0 && len(missing) < len(strings.Fields(scopes)) {
+			return "", 0, &MissingScopesError{VaultConfigKey: vaultConfigKey, Missing: missing}
 		}
+		return "", 0, err
+	}
 
-		token, ok := respObj["access_token"].(string)
-		if !ok {
-			return "", 0, fmt.Errorf("access token not present in response")
+	// Endpoints may grant a subset of the requested scopes, which is only visible in the scope of the response
+	if response.Scope != "" {
+		granted := strings.Fields(response.Scope)
+		var missing []string
+		for _, scope := range strings.Fields(scopes) {
+			if !slices.Contains(granted, scope) {
+				missing = append(missing, scope)
+			}
 		}
-
-		expiresIn := 300 // default to 5 minutes if not present
-		if v, ok := respObj["expires_in"].(float64); ok {
-			expiresIn = int(v)
+		if len(missing) > 0 {
+			return "", 0, &MissingScopesError{VaultConfigKey: vaultConfigKey, Missing: missing}
 		}
-
-		log.Infoln("Successfully authenticated")
-
-		return token, expiresIn, nil
 	}
 
-	return newCachedTokenProvider(fetchFunc), nil
+	expiresIn := response.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = 300
+	}
+	return response.AccessToken, expiresIn, nil
 }
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestVerifiedToken(t *testing.T) {
+	const scopes = "storage:logs:read storage:events:read storage:buckets:read"
+
+	tests := []struct {
+		name string
+		// allowed are the scopes the fake token endpoint grants, nil rejecting the credentials
+		allowed []string
+		// grantSubset makes the endpoint grant the allowed scopes instead of rejecting the request
+		grantSubset   bool
+		expectMissing []string
+		expectErr     bool
+	}{
+		{name: "all scopes granted", allowed: []string{"storage:logs:read", "storage:events:read", "storage:buckets:read"}},
+		{name: "request rejected", allowed: []string{"storage:logs:read", "storage:buckets:read"}, expectMissing: []string{"storage:events:read"}},
+		{name: "subset granted", allowed: []string{"storage:logs:read"}, grantSubset: true, expectMissing: []string{"storage:events:read", "storage:buckets:read"}},
+		{name: "credentials rejected", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := requestToken
+			defer func() { requestToken = previous }()
+			requestToken = func(authUrl, clientId, clientSecret, requested string) (*tokenResponse, error) {
+				if tt.grantSubset {
+					return &tokenResponse{AccessToken: "token", Scope: strings.Join(tt.allowed, " ")}, nil
+				}
+				for _, scope := range strings.Fields(requested) {
+					if !slices.Contains(tt.allowed, scope) {
+						return nil, errors.New("invalid_scope")
+					}
+				}
+				return &tokenResponse{AccessToken: "token"}, nil
+			}
+
+			token, expiresIn, err := requestVerifiedToken("https://sso.example.com", "dt_vault_path", "id", "secret", scopes)
+			var missingErr *MissingScopesError
+			switch {
+			case tt.expectMissing != nil:
+				require.ErrorAs(t, err, &missingErr)
+				assert.Equal(t, tt.expectMissing, missingErr.Missing)
+				assert.Contains(t, RemediationHint(err), "dt_vault_path")
+			case tt.expectErr:
+				require.Error(t, err)
+				assert.False(t, errors.As(err, &missingErr))
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "token", token)
+				assert.Equal(t, 300, expiresIn)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
-	"os"
 	"regexp"
 	"runtime/debug"
 	"strings"
@@ -13,6 +12,7 @@ import (
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/prompt"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -80,7 +80,7 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 		Size(1).
 		Send()
 	if err != nil {
-		err = fmt.Errorf("Can't retrieve subscription for key '%s': %v", key, err)
+		err = fmt.Errorf("Can't retrieve subscription for key '%s': %w", key, ClassifyError(err))
 		return
 	}
 
@@ -94,8 +94,8 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 				Send()
 			if err != nil {
 				err = fmt.Errorf(
-					"Can't retrieve cluster for key '%s': %v",
-					key, err,
+					"Can't retrieve cluster for key '%s': %w",
+					key, ClassifyError(err),
 				)
 				return
 			}
@@ -104,14 +104,12 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 		}
 	}
 
-	// If there are multiple subscriptions that match the cluster then we should report it as
-	// an error:
+	// If there are multiple subscriptions that match the cluster then the user picks one of them,
+	// or it is reported as an error:
 	if subsTotal > 1 {
-		err = fmt.Errorf(
-			"There are %d subscriptions with cluster identifier or name '%s'",
-			subsTotal, key,
-		)
-		return
+		return disambiguateCluster(key, subsTotal, func() ([]*cmv1.Cluster, int, error) {
+			return subscriptionCandidates(connection, subsSearch)
+		})
 	}
 
 	// If we are here then no subscription matches the passed key. It may still be possible that
@@ -127,7 +125,7 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 		Size(1).
 		Send()
 	if err != nil {
-		err = fmt.Errorf("Can't retrieve clusters for key '%s': %v", key, err)
+		err = fmt.Errorf("Can't retrieve clusters for key '%s': %w", key, ClassifyError(err))
 		return
 	}
 
@@ -138,19 +136,17 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 		return
 	}
 
-	// If there are multiple matching clusters then we should report it as an error:
+	// If there are multiple matching clusters then the user picks one of them, or it is reported as an error:
 	if clustersTotal > 1 {
-		err = fmt.Errorf(
-			"There are %d clusters with identifier or name '%s'",
-			clustersTotal, key,
-		)
-		return
+		return disambiguateCluster(key, clustersTotal, func() ([]*cmv1.Cluster, int, error) {
+			return clusterCandidates(connection, clustersSearch)
+		})
 	}
 
 	// If we get here we might still be able to get some information from the deleted_clusters information:
 	deletedClustersResponse, err := deletedClustersResource.List().Search(clustersSearch).Size(1).Send()
 	if err != nil {
-		err = fmt.Errorf("can't retrieve deleted clusters for key '%s': '%v'", key, err)
+		err = fmt.Errorf("can't retrieve deleted clusters for key '%s': %w", key, ClassifyError(err))
 		return
 	}
 
@@ -163,18 +159,12 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 
 	// If there are multiple matching clusters then we should report it as an error:
 	if clustersTotal > 1 {
-		err = fmt.Errorf(
-			"there are %d deleted clusters with identifier or name '%s'",
-			clustersTotal, key,
-		)
+		err = &ClusterNotFoundError{Key: key, Matches: clustersTotal}
 		return
 	}
 
 	// If we are here then there are no subscriptions or clusters matching the passed key:
-	err = fmt.Errorf(
-		"There are no subscriptions or clusters with identifier or name '%s'",
-		key,
-	)
+	err = &ClusterNotFoundError{Key: key}
 	return
 }
 
@@ -203,7 +193,7 @@ func GetSubscription(connection *sdk.Connection, key string) (subscription *amv1
 		key, key, key, key)
 	subsListResponse, err := subsResource.List().Parameter("search", subsSearch).Send()
 	if err != nil {
-		err = fmt.Errorf("can't retrieve subscription for key '%s': %v", key, err)
+		err = fmt.Errorf("can't retrieve subscription for key '%s': %w", key, ClassifyError(err))
 		return
 	}
 
@@ -216,17 +206,11 @@ func GetSubscription(connection *sdk.Connection, key string) (subscription *amv1
 	// If there are multiple subscriptions that match the key then we should report it as
 	// an error:
 	if subsTotal > 1 {
-		err = fmt.Errorf(
-			"there are %d subscriptions with cluster identifier or name '%s'",
-			subsTotal, key,
-		)
+		err = &ClusterNotFoundError{Key: key, Matches: subsTotal}
 		return
 	}
 	// If we are here then there are no subscriptions matching the passed key:
-	err = fmt.Errorf(
-		"there are no subscriptions with identifier or name '%s'",
-		key,
-	)
+	err = &ClusterNotFoundError{Key: key}
 	return
 }
 
@@ -302,21 +286,11 @@ func GetRegistryCredentials(connection *sdk.Connection, accountId string) ([]*am
 	return registryCredentials.Items().Slice(), nil
 }
 
+// ConfirmPrompt asks the user whether to continue, honoring --assume-yes and non-interactive sessions.
+//
+// Deprecated: use prompt.ConfirmPrompt instead.
 func ConfirmPrompt() bool {
-	_, _ = fmt.Fprintf(os.Stderr, "Continue? (y/N): ")
-
-	var response = "n"
-	_, _ = fmt.Scanln(&response) // Erroneous input will be handled by the default case below
-
-	switch strings.ToLower(response) {
-	case "y", "yes":
-		return true
-	case "n", "no":
-		return false
-	default:
-		_, _ = fmt.Fprintln(os.Stderr, "Invalid input. Expecting (y)es or (N)o")
-		return ConfirmPrompt()
-	}
+	return prompt.ConfirmPrompt()
 }
 
 // StreamPrintln appends a newline then prints the given msg using the provided IOStreams
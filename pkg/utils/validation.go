@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Validation of the flag values shared by many commands, so the same mistakes are reported
+// the same way, before any API call is made.
+
+const (
+	// MaxReasonLength is the maximum length of an elevation reason, which is recorded in the backplane audit logs
+	MaxReasonLength = 200
+	// MaxJustificationLength is the maximum length of a justification sent to customers in a service log
+	MaxJustificationLength = 1000
+)
+
+// ClusterKeyKind is the kind of identifier used to reference a cluster
+type ClusterKeyKind string
+
+const (
+	ClusterKeyInternalID ClusterKeyKind = "internal ID"
+	ClusterKeyExternalID ClusterKeyKind = "external ID"
+	ClusterKeyName       ClusterKeyKind = "name"
+)
+
+var (
+	internalClusterIDRE = regexp.MustCompile(`^[a-z0-9]{32}$`)
+	externalClusterIDRE = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	ticketRE            = regexp.MustCompile(`^(OHSS-[0-9]+|ITN-[0-9]{4}-[0-9]+)$`)
+)
+
+// IsInternalClusterID returns whether key is formatted as an OCM internal cluster ID
+func IsInternalClusterID(key string) bool {
+	return internalClusterIDRE.MatchString(key)
+}
+
+// IsExternalClusterID returns whether key is formatted as an external cluster ID (a UUID)
+func IsExternalClusterID(key string) bool {
+	return externalClusterIDRE.MatchString(key)
+}
+
+// GetClusterKeyKind returns the kind of identifier of a cluster key. Keys which are neither formatted
+// as an internal nor an external ID are assumed to be cluster names.
+func GetClusterKeyKind(key string) ClusterKeyKind {
+	switch {
+	case IsInternalClusterID(key):
+		return ClusterKeyInternalID
+	case IsExternalClusterID(key):
+		return ClusterKeyExternalID
+	default:
+		return ClusterKeyName
+	}
+}
+
+// ValidateClusterKey validates the cluster name, internal or external ID passed to a flag
+func ValidateClusterKey(flag, key string) error {
+	if key == "" {
+		return fmt.Errorf("--%s is required", flag)
+	}
+	return IsValidClusterKey(key)
+}
+
+// ValidateInternalClusterID validates that the value of a flag is an internal cluster ID
+func ValidateInternalClusterID(flag, key string) error {
+	if key == "" {
+		return fmt.Errorf("--%s is required", flag)
+	}
+	if kind := GetClusterKeyKind(key); kind != ClusterKeyInternalID {
+		return fmt.Errorf("--%s must be an internal cluster ID, %q looks like a cluster %s", flag, key, kind)
+	}
+	return nil
+}
+
+// ValidateReason validates an elevation reason: it must be set, fit on a single line of at most MaxReasonLength
+// characters, and the OHSS and ITN tickets it references must be well formed.
+func ValidateReason(flag, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("--%s is required", flag)
+	}
+	if err := ValidateMaxLength(flag, reason, MaxReasonLength); err != nil {
+		return err
+	}
+	if strings.ContainsAny(reason, "\r\n") {
+		return fmt.Errorf("--%s must be a single line", flag)
+	}
+	for _, word := range strings.FieldsFunc(reason, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) {
+		upper := strings.ToUpper(word)
+		if (strings.HasPrefix(upper, "OHSS") || strings.HasPrefix(upper, "ITN")) && !ticketRE.MatchString(upper) {
+			return fmt.Errorf("--%s references the malformed ticket %q, expected OHSS-<number> or ITN-<year>-<number>", flag, word)
+		}
+	}
+	return nil
+}
+
+// ValidateTicket validates that the value of a flag is an OHSS or ITN ticket, e.g. OHSS-1234 or ITN-2024-12345
+func ValidateTicket(flag, ticket string) error {
+	if ticket == "" {
+		return fmt.Errorf("--%s is required", flag)
+	}
+	if !ticketRE.MatchString(strings.ToUpper(strings.TrimPrefix(ticket, "#"))) {
+		return fmt.Errorf("--%s must be an OHSS or ITN ticket, e.g. OHSS-1234 or ITN-2024-12345, got %q", flag, ticket)
+	}
+	return nil
+}
+
+// ValidateJustification validates a justification shared with the customer, which must be set and at most
+// MaxJustificationLength characters
+func ValidateJustification(flag, justification string) error {
+	if strings.TrimSpace(justification) == "" {
+		return fmt.Errorf("--%s is required", flag)
+	}
+	return ValidateMaxLength(flag, justification, MaxJustificationLength)
+}
+
+// ValidateMaxLength validates that the value of a flag is at most maxLength characters
+func ValidateMaxLength(flag, value string, maxLength int) error {
+	if length := len([]rune(value)); length > maxLength {
+		return fmt.Errorf("--%s must be at most %d characters, got %d", flag, maxLength, length)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetClusterKeyKind(t *testing.T) {
+	tests := map[string]ClusterKeyKind{
+		"2abcdefghijklmnopqrstuvwxyz01234":     ClusterKeyInternalID,
+		"3b2c8e6a-4f1d-4d2e-9a8b-7c6d5e4f3a2b": ClusterKeyExternalID,
+		"my-cluster":                           ClusterKeyName,
+		"2ABCDEFGHIJKLMNOPQRSTUVWXYZ01234":     ClusterKeyName,
+	}
+	for key, expected := range tests {
+		if kind := GetClusterKeyKind(key); kind != expected {
+			t.Errorf("GetClusterKeyKind(%q) = %q, expected %q", key, kind, expected)
+		}
+	}
+}
+
+func TestValidateInternalClusterID(t *testing.T) {
+	if err := ValidateInternalClusterID("cluster-id", "2abcdefghijklmnopqrstuvwxyz01234"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := ValidateInternalClusterID("cluster-id", "3b2c8e6a-4f1d-4d2e-9a8b-7c6d5e4f3a2b")
+	if err == nil || err.Error() != `--cluster-id must be an internal cluster ID, "3b2c8e6a-4f1d-4d2e-9a8b-7c6d5e4f3a2b" looks like a cluster external ID` {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateInternalClusterID("cluster-id", ""); err == nil || err.Error() != "--cluster-id is required" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateClusterKey(t *testing.T) {
+	if err := ValidateClusterKey("cluster-id", "my-cluster"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateClusterKey("cluster-id", "my cluster'"); err == nil {
+		t.Error("expected an error for an unsafe cluster key")
+	}
+	if err := ValidateClusterKey("cluster-id", ""); err == nil || err.Error() != "--cluster-id is required" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateReason(t *testing.T) {
+	tests := []struct {
+		reason  string
+		wantErr string
+	}{
+		{reason: "OHSS-1234"},
+		{reason: "#ITN-2024-12345"},
+		{reason: "Investigating https://redhat.atlassian.net/browse/OHSS-1234 for the customer"},
+		{reason: "PD incident Q1A2B3C4D5E6F7"},
+		{reason: "  ", wantErr: "--reason is required"},
+		{reason: "OHSS1234", wantErr: `--reason references the malformed ticket "OHSS1234", expected OHSS-<number> or ITN-<year>-<number>`},
+		{reason: "ITN-12345", wantErr: `--reason references the malformed ticket "ITN-12345", expected OHSS-<number> or ITN-<year>-<number>`},
+		{reason: "OHSS-1234\nrm -rf", wantErr: "--reason must be a single line"},
+		{reason: strings.Repeat("a", MaxReasonLength+1), wantErr: "--reason must be at most 200 characters, got 201"},
+	}
+	for _, tt := range tests {
+		err := ValidateReason("reason", tt.reason)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("ValidateReason(%q) unexpected error: %v", tt.reason, err)
+		}
+		if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("ValidateReason(%q) = %v, expected %q", tt.reason, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateTicket(t *testing.T) {
+	for _, ticket := range []string{"OHSS-1", "ohss-1234", "#ITN-2024-12345"} {
+		if err := ValidateTicket("ohss", ticket); err != nil {
+			t.Errorf("ValidateTicket(%q) unexpected error: %v", ticket, err)
+		}
+	}
+	for _, ticket := range []string{"", "OHSS", "SREP-123", "https://redhat.atlassian.net/browse/OHSS-1"} {
+		if err := ValidateTicket("ohss", ticket); err == nil {
+			t.Errorf("ValidateTicket(%q) expected an error", ticket)
+		}
+	}
+}
+
+func TestValidateJustification(t *testing.T) {
+	if err := ValidateJustification("justification", "The infra nodes are running out of memory"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateJustification("justification", ""); err == nil || err.Error() != "--justification is required" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateJustification("justification", strings.Repeat("é", MaxJustificationLength)); err != nil {
+		t.Errorf("expected the length to be counted in characters, got %v", err)
+	}
+	if err := ValidateJustification("justification", strings.Repeat("a", MaxJustificationLength+1)); err == nil {
+		t.Error("expected an error for a too long justification")
+	}
+}
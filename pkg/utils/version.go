@@ -5,12 +5,33 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
 )
 
 const (
 	VersionAPIEndpoint     = "https://api.github.com/repos/openshift/osdctl/releases/latest"
+	ReleasesAPIEndpoint    = "https://api.github.com/repos/openshift/osdctl/releases?per_page=100"
 	VersionAddressTemplate = "https://github.com/openshift/osdctl/releases/download/v%s/osdctl_%s_%s_%s.tar.gz" // version, version, GOOS, GOARCH
+
+	// ChannelStable only considers the stable releases, the ones "osdctl upgrade" installs
+	ChannelStable = "stable"
+	// ChannelPrerelease also considers the release candidates, e.g. v0.45.0-rc.1
+	ChannelPrerelease = "prerelease"
+
+	// VersionChannelConfigKey is the osdctl config key setting the release channel versions are checked against
+	VersionChannelConfigKey = "version_channel"
+	// VersionCheckDailyConfigKey is the osdctl config key limiting the automatic version check to once per day,
+	// warning without prompting when osdctl is outdated
+	VersionCheckDailyConfigKey = "version_check_daily"
+
+	versionCheckStampFileName = "version-check"
 )
 
 var (
@@ -89,3 +110,127 @@ func GetLatestVersion() (latest string, err error) {
 
 	return githubResp.TagName, nil
 }
+
+// Release is a GitHub release of osdctl
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+}
+
+// GetReleases returns the latest releases of osdctl, release candidates included
+func GetReleases() ([]Release, error) {
+	return getReleases(ReleasesAPIEndpoint)
+}
+
+func getReleases(endpoint string) ([]Release, error) {
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+
+	res, err := client.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list the osdctl releases: GitHub returned %s", res.Status)
+	}
+
+	var releases []Release
+	if err := json.NewDecoder(res.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode the osdctl releases: %w", err)
+	}
+	return releases, nil
+}
+
+// ValidateChannel returns an error if channel isn't a known release channel
+func ValidateChannel(channel string) error {
+	if channel != ChannelStable && channel != ChannelPrerelease {
+		return fmt.Errorf("invalid release channel %q, expected %s or %s", channel, ChannelStable, ChannelPrerelease)
+	}
+	return nil
+}
+
+// ConfiguredChannel returns the release channel of the osdctl config, stable by default
+func ConfiguredChannel() string {
+	values, err := osdctlConfig.GetConfigValues(VersionChannelConfigKey)
+	if err != nil || ValidateChannel(values[VersionChannelConfigKey]) != nil {
+		return ChannelStable
+	}
+	return values[VersionChannelConfigKey]
+}
+
+// ReleasesNewerThan returns the releases of the channel newer than current, newest first. The first one is the
+// latest release of the channel. current must be a semantic version, e.g. "0.45.0".
+func ReleasesNewerThan(releases []Release, current, channel string) ([]Release, error) {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return nil, fmt.Errorf("the version %q of this build isn't a release version: %w", current, err)
+	}
+
+	type versionedRelease struct {
+		Release
+		version *semver.Version
+	}
+	var newer []versionedRelease
+	for _, release := range releases {
+		if release.Prerelease && channel != ChannelPrerelease {
+			continue
+		}
+		version, err := semver.NewVersion(release.TagName)
+		if err != nil {
+			continue
+		}
+		if version.Prerelease() != "" && channel != ChannelPrerelease {
+			continue
+		}
+		if version.GreaterThan(currentVersion) {
+			newer = append(newer, versionedRelease{Release: release, version: version})
+		}
+	}
+
+	sort.Slice(newer, func(i, j int) bool {
+		return newer[i].version.GreaterThan(newer[j].version)
+	})
+	result := make([]Release, 0, len(newer))
+	for _, release := range newer {
+		result = append(result, release.Release)
+	}
+	return result, nil
+}
+
+// DailyVersionCheckEnabled returns whether the automatic version check is limited to once per day in the osdctl config
+func DailyVersionCheckEnabled() bool {
+	values, err := osdctlConfig.GetConfigValues(VersionCheckDailyConfigKey)
+	if err != nil {
+		return false
+	}
+	enabled, err := strconv.ParseBool(values[VersionCheckDailyConfigKey])
+	return err == nil && enabled
+}
+
+// VersionCheckDue returns whether the last automatic version check is more than a day old, and records the check
+// when it is due. Failures to read or record the last check make the check due, so that it isn't silently skipped.
+func VersionCheckDue(now time.Time) bool {
+	dataDir, err := osdctlConfig.DataDir()
+	if err != nil {
+		return true
+	}
+	stamp := filepath.Join(dataDir, versionCheckStampFileName)
+
+	if info, err := os.Stat(stamp); err == nil && now.Sub(info.ModTime()) < 24*time.Hour {
+		return false
+	}
+
+	if err := os.MkdirAll(dataDir, 0750); err == nil {
+		if err := os.WriteFile(stamp, nil, 0600); err == nil {
+			_ = os.Chtimes(stamp, now, now)
+		}
+	}
+	return true
+}
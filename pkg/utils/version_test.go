@@ -1,6 +1,12 @@
 package utils
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestIsManagedInstall(t *testing.T) {
 	tests := []struct {
@@ -52,3 +58,79 @@ func TestUpgradeInstruction(t *testing.T) {
 		})
 	}
 }
+
+var testReleases = []Release{
+	{TagName: "v0.44.0"},
+	{TagName: "v0.46.0-rc.1", Prerelease: true},
+	{TagName: "v0.45.1"},
+	{TagName: "not-a-version"},
+	{TagName: "v0.45.0"},
+}
+
+func TestReleasesNewerThan(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		channel string
+		want    []string
+		wantErr bool
+	}{
+		{"stable outdated", "0.44.0", ChannelStable, []string{"v0.45.1", "v0.45.0"}, false},
+		{"stable up to date", "0.45.1", ChannelStable, nil, false},
+		{"prerelease", "0.45.1", ChannelPrerelease, []string{"v0.46.0-rc.1"}, false},
+		{"development build", "", ChannelStable, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReleasesNewerThan(testReleases, tt.current, tt.channel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReleasesNewerThan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var tags []string
+			for _, release := range got {
+				tags = append(tags, release.TagName)
+			}
+			if strings.Join(tags, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ReleasesNewerThan() = %v, want %v", tags, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"tag_name":"v0.45.0","body":"* Fix things","prerelease":false,"published_at":"2025-07-15T09:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	releases, err := getReleases(server.URL)
+	if err != nil {
+		t.Fatalf("getReleases() error = %v", err)
+	}
+	if len(releases) != 1 || releases[0].TagName != "v0.45.0" || releases[0].Body != "* Fix things" {
+		t.Errorf("getReleases() = %+v", releases)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer failing.Close()
+	if _, err := getReleases(failing.URL); err == nil {
+		t.Error("getReleases() expected an error for a rate limited response")
+	}
+}
+
+func TestVersionCheckDue(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	now := time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC)
+
+	if !VersionCheckDue(now) {
+		t.Error("the first check should be due")
+	}
+	if VersionCheckDue(now.Add(time.Hour)) {
+		t.Error("a check an hour later shouldn't be due")
+	}
+	if !VersionCheckDue(now.Add(25 * time.Hour)) {
+		t.Error("a check a day later should be due")
+	}
+}